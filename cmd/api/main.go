@@ -1,15 +1,24 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/api"
-
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage/sqlite"
+	"github.com/adampetrovic/nrl-scheduler/migrations"
 )
 
+// defaultShutdownTimeout is how long a shutdown waits for requests and
+// optimization jobs to stop
+const defaultShutdownTimeout = 30 * time.Second
+
 func main() {
 	// Database connection
 	dbPath := os.Getenv("DATABASE_URL")
@@ -17,30 +26,124 @@ func main() {
 		dbPath = "nrl-scheduler.db"
 	}
 
-	db, err := sql.Open("sqlite3", dbPath)
+	// SQLITE_BUSY_TIMEOUT_MS and SQLITE_MAX_OPEN_CONNS tune the connection pool
+	opts := sqlite.DefaultOptions()
+	if value := os.Getenv("SQLITE_BUSY_TIMEOUT_MS"); value != "" {
+		ms, err := strconv.Atoi(value)
+		if err != nil || ms < 0 {
+			log.Fatal("Invalid SQLITE_BUSY_TIMEOUT_MS:", value)
+		}
+		opts.BusyTimeout = time.Duration(ms) * time.Millisecond
+	}
+	if value := os.Getenv("SQLITE_MAX_OPEN_CONNS"); value != "" {
+		conns, err := strconv.Atoi(value)
+		if err != nil || conns < 1 {
+			log.Fatal("Invalid SQLITE_MAX_OPEN_CONNS:", value)
+		}
+		opts.MaxOpenConns = conns
+		opts.MaxIdleConns = conns
+	}
+
+	db, err := sqlite.NewWithOptions(dbPath, opts)
 	if err != nil {
 		log.Fatal("Failed to open database:", err)
 	}
-	defer db.Close()
 
 	// Test database connection
-	if err := db.Ping(); err != nil {
+	if err := db.Conn().Ping(); err != nil {
 		log.Fatal("Failed to ping database:", err)
 	}
 
-	// TODO: Run migrations - placeholder for now
-	log.Println("Migrations skipped - placeholder implementation")
+	// Apply the embedded migrations. MIGRATION_VERSION pins the schema to a
+	// specific version instead, rolling back newer migrations if needed.
+	if target := os.Getenv("MIGRATION_VERSION"); target != "" {
+		version, err := strconv.ParseUint(target, 10, 32)
+		if err != nil {
+			log.Fatal("Invalid MIGRATION_VERSION:", err)
+		}
+		if err := db.MigrateFSTo(migrations.FS, uint(version)); err != nil {
+			log.Fatal("Failed to run migrations:", err)
+		}
+	} else if err := db.MigrateFS(migrations.FS); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	version, dirty, err := db.SchemaVersion()
+	if err != nil {
+		log.Fatal("Failed to read schema version:", err)
+	}
+	if dirty {
+		log.Fatalf("Database schema is dirty at version %d, fix the failed migration before starting", version)
+	}
+	log.Printf("Database schema at version %d", version)
 
 	// Create and start server
-	server := api.NewServer(db)
+	server := api.NewServer(db.Conn())
+
+	// WS_PROGRESS_INTERVAL_MS sets how often job subscribers receive progress
+	if value := os.Getenv("WS_PROGRESS_INTERVAL_MS"); value != "" {
+		ms, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatal("Invalid WS_PROGRESS_INTERVAL_MS:", err)
+		}
+		server.GetWebSocketHub().SetProgressInterval(time.Duration(ms) * time.Millisecond)
+	}
+
+	// API_KEYS turns on authentication, e.g. "admin:s3cret,viewer:readonly"
+	if value := os.Getenv("API_KEYS"); value != "" {
+		keys, err := middleware.ParseAPIKeys(value)
+		if err != nil {
+			log.Fatal("Invalid API_KEYS:", err)
+		}
+		server.SetAPIKeys(keys)
+		log.Printf("Authentication enabled with %d API keys", len(keys))
+	} else {
+		log.Printf("API_KEYS is not set, authentication is disabled")
+	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
+	// SHUTDOWN_TIMEOUT_SECONDS bounds how long a shutdown waits for requests
+	// and optimization jobs to stop
+	shutdownTimeout := defaultShutdownTimeout
+	if value := os.Getenv("SHUTDOWN_TIMEOUT_SECONDS"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			log.Fatal("Invalid SHUTDOWN_TIMEOUT_SECONDS:", value)
+		}
+		shutdownTimeout = time.Duration(seconds) * time.Second
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	log.Printf("Starting NRL Scheduler API server on port %s", port)
-	if err := server.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Run(":" + port)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Fatal("Failed to start server:", err)
+		}
+	case <-ctx.Done():
+		stop()
+		log.Printf("Shutting down, waiting up to %s for requests and optimization jobs to stop", shutdownTimeout)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error during shutdown: %v", err)
+	}
+
+	if err := db.Close(); err != nil {
+		log.Printf("Error closing database: %v", err)
 	}
+	log.Printf("Server stopped")
 }
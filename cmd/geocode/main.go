@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage/sqlite"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report coordinates without saving them")
+	flag.Parse()
+
+	// Database connection
+	dbPath := os.Getenv("DATABASE_URL")
+	if dbPath == "" {
+		dbPath = "nrl-scheduler.db"
+	}
+
+	db, err := sqlite.New(dbPath)
+	if err != nil {
+		log.Fatal("Failed to open database:", err)
+	}
+	defer db.Close()
+
+	repos := sqlite.NewRepositories(db.Conn())
+	report, err := geo.BackfillCoordinates(context.Background(), repos, geo.NewOfflineGeocoder(), *dryRun)
+	if err != nil {
+		log.Fatal("Failed to geocode coordinates:", err)
+	}
+
+	for _, result := range report.Results {
+		switch result.Status {
+		case geo.BackfillUpdated:
+			fmt.Printf("%-6s %4d %-40s %10.4f %10.4f (%s)\n", result.Kind, result.ID, result.Name, result.Latitude, result.Longitude, result.Source)
+		case geo.BackfillNotFound:
+			fmt.Printf("%-6s %4d %-40s not found\n", result.Kind, result.ID, result.Name)
+		default:
+			fmt.Printf("%-6s %4d %-40s failed: %s\n", result.Kind, result.ID, result.Name, result.Error)
+		}
+	}
+
+	action := "Updated"
+	if report.DryRun {
+		action = "Would update"
+	}
+	fmt.Printf("%s %d, not found %d, failed %d\n", action, report.Updated, report.NotFound, report.Failed)
+
+	if report.NotFound > 0 || report.Failed > 0 {
+		os.Exit(1)
+	}
+}
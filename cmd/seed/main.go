@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/seed"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage/sqlite"
+)
+
+func main() {
+	// Database connection
+	dbPath := os.Getenv("DATABASE_URL")
+	if dbPath == "" {
+		dbPath = "nrl-scheduler.db"
+	}
+
+	db, err := sqlite.New(dbPath)
+	if err != nil {
+		log.Fatal("Failed to open database:", err)
+	}
+	defer db.Close()
+
+	repos := sqlite.NewRepositories(db.Conn())
+	report, err := seed.LoadNRL(context.Background(), repos)
+	if err != nil {
+		log.Fatal("Failed to seed NRL teams and venues:", err)
+	}
+
+	for _, result := range report.Results {
+		fmt.Printf("%-6s %4d %-40s %s\n", result.Kind, result.ID, result.Name, result.Status)
+	}
+	fmt.Printf("Created %d venues and %d teams, %d already existed\n", report.VenuesCreated, report.TeamsCreated, report.Existing)
+}
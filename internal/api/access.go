@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+)
+
+// publicRoutes need no API key
+var publicRoutes = map[string]bool{
+	"GET /health":       true,
+	"GET /share/:token": true,
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+
+	"GET /api/v1/public/draws/:id/fixtures": true,
+}
+
+// adminPrefixes are the paths whose changes need the admin role: league
+// data and server configuration
+var adminPrefixes = []string{
+	"/api/v1/calendars",
+	"/api/v1/competitions",
+	"/api/v1/constraint-templates",
+	"/api/v1/teams",
+	"/api/v1/timeslots",
+	"/api/v1/venues",
+	"/api/v1/admin",
+	"/api/v1/ladders",
+	"/api/v1/optimize/config",
+	"/api/v1/optimize/retention",
+	"/api/v1/webhooks",
+	"/test/",
+}
+
+// readOnlyRoutes change nothing despite being POSTs, so viewers may use them
+var readOnlyRoutes = map[string]bool{
+	"POST /api/v1/draws/compare":                  true,
+	"POST /api/v1/constraints/validate":           true,
+	"POST /api/v1/constraints/simulate":           true,
+	"POST /api/v1/draws/:id/validate-constraints": true,
+	// Exports and reports posted with locale overrides
+	"POST /api/v1/draws/:id/export": true,
+	"POST /api/v1/draws/:id/report": true,
+}
+
+// routeRole returns the role a route requires. Reads need a viewer, changes
+// to draws and optimizations a scheduler, and changes to teams, venues and
+// configuration an admin. WebSocket connections need a viewer.
+func routeRole(method, path string) middleware.Role {
+	route := method + " " + path
+	if publicRoutes[route] {
+		return middleware.RolePublic
+	}
+	if method == http.MethodGet || method == http.MethodHead || readOnlyRoutes[route] {
+		return middleware.RoleViewer
+	}
+
+	// Ladder repeat matchups feed a draw's constraints rather than league data
+	if path == "/api/v1/ladders/:season/repeat-matchups" {
+		return middleware.RoleScheduler
+	}
+	// Creating a draw from a template is scheduling, not template upkeep
+	if path == "/api/v1/constraint-templates/:id/draws" {
+		return middleware.RoleScheduler
+	}
+	for _, prefix := range adminPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return middleware.RoleAdmin
+		}
+	}
+	return middleware.RoleScheduler
+}
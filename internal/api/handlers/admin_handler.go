@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/seed"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// AdminHandler handles maintenance tasks over the stored data
+type AdminHandler struct {
+	repos    storage.Repositories
+	geocoder geo.Geocoder
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(repos storage.Repositories, geocoder geo.Geocoder) *AdminHandler {
+	return &AdminHandler{
+		repos:    repos,
+		geocoder: geocoder,
+	}
+}
+
+// GeocodeMissingCoordinates fills in latitude/longitude for teams and venues that have none
+// POST /api/v1/admin/geocode?dry_run=true
+func (h *AdminHandler) GeocodeMissingCoordinates(c *gin.Context) {
+	dryRun := false
+	if dryRunStr := c.Query("dry_run"); dryRunStr != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			middleware.BadRequest(c, "dry_run must be a boolean")
+			return
+		}
+	}
+
+	report, err := geo.BackfillCoordinates(context.Background(), h.repos, h.geocoder, dryRun)
+	if err != nil {
+		log.Printf("Error geocoding coordinates: %v", err)
+		middleware.InternalError(c, "Failed to geocode coordinates")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// SeedNRL loads the NRL clubs and their home venues, skipping any that exist
+// POST /api/v1/admin/seed/nrl
+func (h *AdminHandler) SeedNRL(c *gin.Context) {
+	report, err := seed.LoadNRL(context.Background(), h.repos)
+	if err != nil {
+		log.Printf("Error seeding NRL teams: %v", err)
+		middleware.InternalError(c, "Failed to seed NRL teams and venues")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
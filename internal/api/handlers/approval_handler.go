@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/api/websocket"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/approval"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// ApprovalHandler handles stakeholder sign-off and publication of draws
+type ApprovalHandler struct {
+	approvalService *approval.Service
+	wsHub           *websocket.Hub
+}
+
+// NewApprovalHandler creates a new approval handler
+func NewApprovalHandler(approvalService *approval.Service, wsHub *websocket.Hub) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService: approvalService,
+		wsHub:           wsHub,
+	}
+}
+
+// GetApprovals returns the approval state of a draw
+// GET /api/v1/draws/:id/approvals
+func (h *ApprovalHandler) GetApprovals(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	summary, err := h.approvalService.GetSummary(context.Background(), id)
+	if err != nil {
+		h.handleApprovalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// RequestApprovals asks stakeholders to sign off the draw's current version
+// POST /api/v1/draws/:id/approvals
+func (h *ApprovalHandler) RequestApprovals(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var req types.RequestApprovalsRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	roles := make([]models.ApprovalRole, len(req.Roles))
+	for i, role := range req.Roles {
+		roles[i] = models.ApprovalRole(role)
+	}
+
+	summary, err := h.approvalService.RequestApprovals(context.Background(), id, roles, req.RequestedBy)
+	if err != nil {
+		h.handleApprovalError(c, err)
+		return
+	}
+
+	h.broadcastApprovals(websocket.ApprovalRequested, summary)
+
+	c.JSON(http.StatusCreated, summary)
+}
+
+// RecordApproval records a stakeholder's approval or rejection
+// POST /api/v1/draws/:id/approvals/:role
+func (h *ApprovalHandler) RecordApproval(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var req types.RecordApprovalRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	role := models.ApprovalRole(c.Param("role"))
+	_, err = h.approvalService.RecordDecision(context.Background(), id, role, req.Approver, req.Decision == "approve", req.Comment)
+	if err != nil {
+		h.handleApprovalError(c, err)
+		return
+	}
+
+	summary, err := h.approvalService.GetSummary(context.Background(), id)
+	if err != nil {
+		h.handleApprovalError(c, err)
+		return
+	}
+
+	h.broadcastApprovals(websocket.ApprovalRecorded, summary)
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// PublishDraw publishes a draw once every required stakeholder has approved it
+// POST /api/v1/draws/:id/publish
+func (h *ApprovalHandler) PublishDraw(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	draw, err := h.approvalService.Publish(context.Background(), id)
+	if err != nil {
+		h.handleApprovalError(c, err)
+		return
+	}
+
+	if h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.DrawPublished, websocket.DrawEventData{
+			Draw:      draw,
+			Timestamp: time.Now(),
+		})
+	}
+
+	c.JSON(http.StatusOK, types.DrawToResponse(draw))
+}
+
+// broadcastApprovals notifies clients that a draw's approval state changed
+func (h *ApprovalHandler) broadcastApprovals(messageType string, summary *approval.Summary) {
+	if h.wsHub == nil {
+		return
+	}
+	h.wsHub.BroadcastMessage(messageType, websocket.ApprovalEventData{
+		DrawID:         summary.DrawID,
+		Approvals:      summary.Approvals,
+		ReadyToPublish: summary.ReadyToPublish,
+		Timestamp:      time.Now(),
+	})
+}
+
+// handleApprovalError maps approval workflow errors onto HTTP responses
+func (h *ApprovalHandler) handleApprovalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, approval.ErrInvalidRole):
+		middleware.BadRequest(c, err.Error())
+	case errors.Is(err, approval.ErrDrawNotReady),
+		errors.Is(err, approval.ErrNoPendingApproval),
+		errors.Is(err, approval.ErrStaleApproval),
+		errors.Is(err, approval.ErrApprovalsIncomplete):
+		middleware.Conflict(c, err.Error())
+	case errors.Is(err, storage.ErrNotFound):
+		middleware.NotFound(c, err.Error())
+	default:
+		log.Printf("Error processing draw approval: %v", err)
+		middleware.InternalError(c, "Failed to process draw approval")
+	}
+}
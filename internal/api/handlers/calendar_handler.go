@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// SeasonCalendarHandler manages the season calendars that date each round to
+// the weekends it is played on
+type SeasonCalendarHandler struct {
+	calendarRepo    storage.SeasonCalendarRepository
+	competitionRepo storage.CompetitionRepository
+}
+
+// NewSeasonCalendarHandler creates a new season calendar handler
+func NewSeasonCalendarHandler(calendarRepo storage.SeasonCalendarRepository, competitionRepo storage.CompetitionRepository) *SeasonCalendarHandler {
+	return &SeasonCalendarHandler{
+		calendarRepo:    calendarRepo,
+		competitionRepo: competitionRepo,
+	}
+}
+
+// GetCalendars lists every season calendar, latest season first
+// GET /api/v1/calendars
+func (h *SeasonCalendarHandler) GetCalendars(c *gin.Context) {
+	calendars, err := h.calendarRepo.List(context.Background())
+	if err != nil {
+		log.Printf("Error listing season calendars: %v", err)
+		middleware.InternalError(c, "Failed to retrieve season calendars")
+		return
+	}
+
+	responses := make([]types.SeasonCalendarResponse, len(calendars))
+	for i, calendar := range calendars {
+		responses[i] = types.SeasonCalendarToResponse(calendar)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetCalendar returns one season calendar
+// GET /api/v1/calendars/:id
+func (h *SeasonCalendarHandler) GetCalendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid calendar ID")
+		return
+	}
+
+	calendar, err := h.calendarRepo.Get(context.Background(), id)
+	if err != nil {
+		h.handleCalendarError(c, err, "Failed to retrieve season calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SeasonCalendarToResponse(calendar))
+}
+
+// CreateCalendar adds a season calendar. Without a competition it applies to
+// every draw of the season whose competition has no calendar of its own.
+// POST /api/v1/calendars
+func (h *SeasonCalendarHandler) CreateCalendar(c *gin.Context) {
+	var req types.CreateSeasonCalendarRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	rounds, err := parseCalendarRounds(req.Rounds)
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	calendar := &models.SeasonCalendar{
+		Name:          req.Name,
+		SeasonYear:    req.SeasonYear,
+		CompetitionID: req.CompetitionID,
+		Rounds:        rounds,
+	}
+	if err := calendar.Validate(); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+	if !competitionExists(c, h.competitionRepo, calendar.CompetitionID) {
+		return
+	}
+
+	if err := h.calendarRepo.Create(context.Background(), calendar); err != nil {
+		h.handleCalendarError(c, err, "Failed to create season calendar")
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.SeasonCalendarToResponse(calendar))
+}
+
+// UpdateCalendar changes the fields provided. Rounds, when given, replace the
+// calendar's rounds entirely.
+// PUT /api/v1/calendars/:id
+func (h *SeasonCalendarHandler) UpdateCalendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid calendar ID")
+		return
+	}
+
+	var req types.UpdateSeasonCalendarRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	calendar, err := h.calendarRepo.Get(context.Background(), id)
+	if err != nil {
+		h.handleCalendarError(c, err, "Failed to retrieve season calendar")
+		return
+	}
+
+	if req.Name != nil {
+		calendar.Name = *req.Name
+	}
+	if req.SeasonYear != nil {
+		calendar.SeasonYear = *req.SeasonYear
+	}
+	if req.CompetitionID != nil {
+		if !competitionExists(c, h.competitionRepo, req.CompetitionID) {
+			return
+		}
+		calendar.CompetitionID = req.CompetitionID
+	}
+	if len(req.Rounds) > 0 {
+		if calendar.Rounds, err = parseCalendarRounds(req.Rounds); err != nil {
+			middleware.BadRequest(c, err.Error())
+			return
+		}
+	}
+	if err := calendar.Validate(); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.calendarRepo.Update(context.Background(), calendar); err != nil {
+		h.handleCalendarError(c, err, "Failed to update season calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SeasonCalendarToResponse(calendar))
+}
+
+// DeleteCalendar removes a season calendar. Matches already dated from it
+// keep their kickoffs.
+// DELETE /api/v1/calendars/:id
+func (h *SeasonCalendarHandler) DeleteCalendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid calendar ID")
+		return
+	}
+
+	if err := h.calendarRepo.Delete(context.Background(), id); err != nil {
+		h.handleCalendarError(c, err, "Failed to delete season calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SuccessResponse{
+		Success: true,
+		Message: "Season calendar deleted successfully",
+	})
+}
+
+// handleCalendarError maps season calendar storage errors to responses
+func (h *SeasonCalendarHandler) handleCalendarError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		middleware.NotFound(c, "Season calendar not found")
+	case errors.Is(err, storage.ErrConflict):
+		middleware.Conflict(c, "The competition already has a calendar for that season")
+	default:
+		log.Printf("%s: %v", message, err)
+		middleware.InternalError(c, message)
+	}
+}
+
+// parseCalendarRounds converts requested rounds to calendar rounds, which are
+// regular unless given a kind
+func parseCalendarRounds(requests []types.CalendarRoundRequest) ([]models.CalendarRound, error) {
+	rounds := make([]models.CalendarRound, len(requests))
+	for i, req := range requests {
+		start, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("round %d: invalid start_date %q, expected YYYY-MM-DD", req.Round, req.StartDate)
+		}
+		end, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("round %d: invalid end_date %q, expected YYYY-MM-DD", req.Round, req.EndDate)
+		}
+
+		kind := models.RoundKindRegular
+		if req.Kind != "" {
+			kind = models.RoundKind(strings.ToLower(req.Kind))
+		}
+
+		rounds[i] = models.CalendarRound{
+			Round:     req.Round,
+			StartDate: start,
+			EndDate:   end,
+			Kind:      kind,
+			Label:     req.Label,
+		}
+	}
+	return rounds, nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/compare"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// CompareHandler serves side-by-side reports of several draws
+type CompareHandler struct {
+	compareService *compare.Service
+}
+
+// NewCompareHandler creates a new compare handler
+func NewCompareHandler(compareService *compare.Service) *CompareHandler {
+	return &CompareHandler{
+		compareService: compareService,
+	}
+}
+
+// CompareDraws scores two or more draws and reports them side by side
+// POST /api/v1/draws/compare
+func (h *CompareHandler) CompareDraws(c *gin.Context) {
+	var req types.CompareDrawsRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	comparison, err := h.compareService.Compare(context.Background(), req.DrawIDs, req.Constraints)
+	if err != nil {
+		switch {
+		case errors.Is(err, compare.ErrTooFewDraws), errors.Is(err, compare.ErrTooManyDraws), errors.Is(err, compare.ErrDuplicateDraw),
+			errors.Is(err, compare.ErrInvalidConfig):
+			middleware.BadRequest(c, err.Error())
+		case errors.Is(err, storage.ErrNotFound):
+			middleware.NotFound(c, err.Error())
+		default:
+			log.Printf("Error comparing draws: %v", err)
+			middleware.InternalError(c, "Failed to compare draws")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
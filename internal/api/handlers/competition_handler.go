@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// CompetitionHandler manages the competitions teams and draws belong to
+type CompetitionHandler struct {
+	competitionRepo storage.CompetitionRepository
+}
+
+// NewCompetitionHandler creates a new competition handler
+func NewCompetitionHandler(competitionRepo storage.CompetitionRepository) *CompetitionHandler {
+	return &CompetitionHandler{
+		competitionRepo: competitionRepo,
+	}
+}
+
+// GetCompetitions lists every competition
+// GET /api/v1/competitions
+func (h *CompetitionHandler) GetCompetitions(c *gin.Context) {
+	competitions, err := h.competitionRepo.List(context.Background())
+	if err != nil {
+		log.Printf("Error listing competitions: %v", err)
+		middleware.InternalError(c, "Failed to retrieve competitions")
+		return
+	}
+
+	responses := make([]types.CompetitionResponse, len(competitions))
+	for i, competition := range competitions {
+		responses[i] = types.CompetitionToResponse(competition)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetCompetition returns one competition
+// GET /api/v1/competitions/:id
+func (h *CompetitionHandler) GetCompetition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid competition ID")
+		return
+	}
+
+	competition, err := h.competitionRepo.Get(context.Background(), id)
+	if err != nil {
+		h.handleCompetitionError(c, err, "Failed to retrieve competition")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.CompetitionToResponse(competition))
+}
+
+// CreateCompetition adds a competition, optionally with the constraints its
+// draws are generated with by default
+// POST /api/v1/competitions
+func (h *CompetitionHandler) CreateCompetition(c *gin.Context) {
+	var req types.CreateCompetitionRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	competition := &models.Competition{
+		Name: req.Name,
+		Code: req.Code,
+	}
+	if !setCompetitionConstraints(c, competition, req.ConstraintConfig) {
+		return
+	}
+	if err := competition.Validate(); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.competitionRepo.Create(context.Background(), competition); err != nil {
+		h.handleCompetitionError(c, err, "Failed to create competition")
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.CompetitionToResponse(competition))
+}
+
+// UpdateCompetition changes the fields provided
+// PUT /api/v1/competitions/:id
+func (h *CompetitionHandler) UpdateCompetition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid competition ID")
+		return
+	}
+
+	var req types.UpdateCompetitionRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	competition, err := h.competitionRepo.Get(context.Background(), id)
+	if err != nil {
+		h.handleCompetitionError(c, err, "Failed to retrieve competition")
+		return
+	}
+
+	if req.Name != nil {
+		competition.Name = *req.Name
+	}
+	if req.Code != nil {
+		competition.Code = *req.Code
+	}
+	if !setCompetitionConstraints(c, competition, req.ConstraintConfig) {
+		return
+	}
+	if err := competition.Validate(); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.competitionRepo.Update(context.Background(), competition); err != nil {
+		h.handleCompetitionError(c, err, "Failed to update competition")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.CompetitionToResponse(competition))
+}
+
+// DeleteCompetition removes a competition. Its teams and draws are kept
+// without one.
+// DELETE /api/v1/competitions/:id
+func (h *CompetitionHandler) DeleteCompetition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid competition ID")
+		return
+	}
+
+	if err := h.competitionRepo.Delete(context.Background(), id); err != nil {
+		h.handleCompetitionError(c, err, "Failed to delete competition")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SuccessResponse{
+		Success: true,
+		Message: "Competition deleted successfully",
+	})
+}
+
+// handleCompetitionError maps competition storage errors to responses
+func (h *CompetitionHandler) handleCompetitionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		middleware.NotFound(c, "Competition not found")
+	case errors.Is(err, storage.ErrConflict):
+		middleware.Conflict(c, "A competition with that name or code already exists")
+	default:
+		log.Printf("%s: %v", message, err)
+		middleware.InternalError(c, message)
+	}
+}
+
+// setCompetitionConstraints validates and stores a competition's default
+// constraint configuration, responding with 400 when it is invalid
+func setCompetitionConstraints(c *gin.Context, competition *models.Competition, config *constraints.ConstraintConfig) bool {
+	if config == nil {
+		return true
+	}
+	configJSON, ok := encodeConstraintConfig(c, config)
+	if !ok {
+		return false
+	}
+	competition.ConstraintConfig = configJSON
+	return true
+}
+
+// encodeConstraintConfig validates a constraint configuration kept for later
+// draws and encodes it with its rivalry weights frozen, responding with 400
+// when it is invalid
+func encodeConstraintConfig(c *gin.Context, config *constraints.ConstraintConfig) (json.RawMessage, bool) {
+	// There is no draw yet, so only the params' own consistency is checked
+	if _, err := constraints.ValidateConstraintConfig(*config, constraints.ConflictContext{}); err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return nil, false
+	}
+	if err := constraints.FreezeRivalryWeights(config); err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return nil, false
+	}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration")
+		return nil, false
+	}
+	return configJSON, true
+}
+
+// competitionExists responds with 400 and returns false when a request names
+// a competition that doesn't exist. A nil ID names none.
+func competitionExists(c *gin.Context, competitionRepo storage.CompetitionRepository, id *int) bool {
+	if id == nil {
+		return true
+	}
+	if _, err := competitionRepo.Get(context.Background(), *id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.BadRequest(c, "Competition not found")
+			return false
+		}
+		middleware.InternalError(c, "Failed to retrieve competition")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/simulate"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// ConstraintHandler handles constraint catalogue and analysis requests
+type ConstraintHandler struct {
+	drawRepo  storage.DrawRepository
+	teamRepo  storage.TeamRepository
+	venueRepo storage.VenueRepository
+}
+
+// NewConstraintHandler creates a new constraint handler
+func NewConstraintHandler(drawRepo storage.DrawRepository, teamRepo storage.TeamRepository, venueRepo storage.VenueRepository) *ConstraintHandler {
+	return &ConstraintHandler{
+		drawRepo:  drawRepo,
+		teamRepo:  teamRepo,
+		venueRepo: venueRepo,
+	}
+}
+
+// GetConstraintTypes returns every constraint type with its parameters and defaults
+// GET /api/v1/constraints/types
+func (h *ConstraintHandler) GetConstraintTypes(c *gin.Context) {
+	typeInfo := constraints.GetConstraintTypeInfo()
+	defaults := constraints.GetDefaultConstraintParams()
+
+	response := types.ConstraintTypesResponse{
+		Types: make([]types.ConstraintTypeResponse, 0, len(typeInfo)),
+	}
+
+	for name, info := range typeInfo {
+		item := types.ConstraintTypeResponse{
+			Name:          name,
+			Type:          info.Type,
+			Description:   info.Description,
+			Parameters:    info.Parameters,
+			DefaultParams: map[string]interface{}{},
+		}
+
+		if typeDefaults, ok := defaults[name]; ok {
+			item.DefaultParams = typeDefaults.Params
+			item.DefaultWeight = typeDefaults.Weight
+			item.InDefaultConfig = true
+		}
+
+		response.Types = append(response.Types, item)
+	}
+
+	// Sort by name for a stable response
+	for i := 0; i < len(response.Types)-1; i++ {
+		for j := i + 1; j < len(response.Types); j++ {
+			if response.Types[i].Name > response.Types[j].Name {
+				response.Types[i], response.Types[j] = response.Types[j], response.Types[i]
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetConstraintSchema returns JSON Schemas for constraint configuration and each type's params
+// GET /api/v1/constraints/schema
+func (h *ConstraintHandler) GetConstraintSchema(c *gin.Context) {
+	c.JSON(http.StatusOK, types.ConstraintSchemaResponse{
+		Config: constraints.ConstraintConfigSchema(),
+		Types:  constraints.GetConstraintSchemas(),
+	})
+}
+
+// ValidateConstraintConfig checks a constraint configuration and reports conflicting constraints
+// and params that don't make sense for the draw, split into errors and warnings
+// POST /api/v1/constraints/validate
+func (h *ConstraintHandler) ValidateConstraintConfig(c *gin.Context) {
+	var req types.ValidateConstraintConfigRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	meta := constraints.ConflictContext{Teams: req.Teams, Rounds: req.Rounds}
+	response := types.ValidateConstraintConfigResponse{
+		Conflicts: constraints.DetectConfigConflicts(req.Constraints, meta),
+		Errors:    []constraints.ConfigConflict{},
+		Warnings:  []constraints.ConfigConflict{},
+	}
+
+	issues, err := constraints.ValidateConstraintConfig(req.Constraints, meta)
+	if err != nil {
+		response.Error = err.Error()
+	}
+	for _, issue := range append(issues, response.Conflicts...) {
+		if issue.Severity == constraints.ConflictError {
+			response.Errors = append(response.Errors, issue)
+		} else {
+			response.Warnings = append(response.Warnings, issue)
+		}
+	}
+	response.Valid = response.Error == "" && !constraints.HasBlockingConflicts(response.Conflicts)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SimulateConstraints scores a constraint configuration on random draws so
+// users can see which scores are attainable
+// POST /api/v1/constraints/simulate
+func (h *ConstraintHandler) SimulateConstraints(c *gin.Context) {
+	var req types.SimulateConstraintsRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := simulate.Simulate(req.Constraints, simulate.Options{
+		Teams:   req.Teams,
+		Rounds:  req.Rounds,
+		Samples: req.Samples,
+		Seed:    req.Seed,
+	})
+	if err != nil {
+		if errors.Is(err, simulate.ErrInvalidConfig) || errors.Is(err, simulate.ErrInvalidLeague) {
+			middleware.BadRequest(c, err.Error())
+			return
+		}
+		log.Printf("Error simulating constraints: %v", err)
+		middleware.InternalError(c, "Failed to simulate constraints")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetConstraintTimeline returns round-by-round violation counts and soft penalty contributions
+// GET /api/v1/draws/:id/constraints/timeline
+func (h *ConstraintHandler) GetConstraintTimeline(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	draw, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		log.Printf("Error retrieving draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+	league, err := constraints.LoadLeagueData(context.Background(), h.teamRepo, h.venueRepo)
+	if err != nil {
+		log.Printf("Error loading teams and venues: %v", err)
+		middleware.InternalError(c, "Failed to load teams and venues")
+		return
+	}
+	engine.SetLeagueData(league)
+	if err := engine.LoadOtherDraws(context.Background(), h.drawRepo); err != nil {
+		log.Printf("Error loading partner draws: %v", err)
+		middleware.InternalError(c, "Failed to load partner draws")
+		return
+	}
+
+	timeline := engine.AnalyzeTimeline(draw)
+
+	// Streamed timelines carry one round per line; draw-level violations are
+	// only available in the full response
+	if middleware.WantsNDJSON(c) {
+		middleware.StreamNDJSONSlice(c, timeline.Rounds)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ConstraintTimelineResponse{
+		DrawID:              draw.ID,
+		Rounds:              timeline.Rounds,
+		DrawLevelViolations: timeline.DrawLevelViolations,
+		TotalSoftPenalty:    timeline.TotalSoftPenalty,
+	})
+}
+
+// GetBroadcasterQuotas returns each team's appearances against the draw's broadcaster quotas
+// GET /api/v1/draws/:id/constraints/broadcaster-quotas
+func (h *ConstraintHandler) GetBroadcasterQuotas(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	draw, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		log.Printf("Error retrieving draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, types.BroadcasterQuotaReportResponse{
+		DrawID: draw.ID,
+		Quotas: engine.AnalyzeBroadcasterQuotas(draw),
+	})
+}
+
+// GetRegionSpread returns each region's home matches per round against the draw's region spreads
+// GET /api/v1/draws/:id/constraints/region-spread
+func (h *ConstraintHandler) GetRegionSpread(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	draw, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		log.Printf("Error retrieving draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, types.RegionSpreadReportResponse{
+		DrawID:  draw.ID,
+		Spreads: engine.AnalyzeRegionSpread(draw),
+	})
+}
+
+// GetDerbies returns how the draw's derbies between nearby teams fall across its rounds
+// GET /api/v1/draws/:id/constraints/derbies
+func (h *ConstraintHandler) GetDerbies(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	draw, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		log.Printf("Error retrieving draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+	league, err := constraints.LoadLeagueData(context.Background(), h.teamRepo, h.venueRepo)
+	if err != nil {
+		log.Printf("Error loading teams and venues: %v", err)
+		middleware.InternalError(c, "Failed to load teams and venues")
+		return
+	}
+	engine.SetLeagueData(league)
+
+	c.JSON(http.StatusOK, types.DerbyReportResponse{
+		DrawID:  draw.ID,
+		Derbies: engine.AnalyzeDerbies(draw),
+	})
+}
+
+// GetTravelFatigue returns each team's itinerary, with every trip classed as
+// a drive, short flight or long flight, and its travel fatigue score
+// GET /api/v1/draws/:id/constraints/travel-fatigue
+func (h *ConstraintHandler) GetTravelFatigue(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	draw, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		log.Printf("Error retrieving draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+	league, err := constraints.LoadLeagueData(context.Background(), h.teamRepo, h.venueRepo)
+	if err != nil {
+		log.Printf("Error loading teams and venues: %v", err)
+		middleware.InternalError(c, "Failed to load teams and venues")
+		return
+	}
+	fatigue := engine.TravelFatigue()
+	fatigue.SetLeagueData(league)
+
+	c.JSON(http.StatusOK, types.TravelFatigueReportResponse{
+		DrawID:                 draw.ID,
+		MaxConsecutiveLongHaul: fatigue.GetMaxConsecutiveLongHaul(),
+		Teams:                  fatigue.Itineraries(draw),
+	})
+}
+
+// GetTravelCost returns each team's estimated travel cost for the season and
+// the league totals, priced with the draw's travel cost configuration or the
+// default rates
+// GET /api/v1/draws/:id/constraints/travel-cost
+func (h *ConstraintHandler) GetTravelCost(c *gin.Context) {
+	draw, cost, ok := h.loadTravelCost(c)
+	if !ok {
+		return
+	}
+
+	report := cost.Report(draw)
+	c.JSON(http.StatusOK, types.TravelCostReportResponse{
+		DrawID:                 draw.ID,
+		Model:                  report.Model,
+		Budget:                 report.Budget,
+		TotalTravelCost:        report.TotalTravelCost,
+		TotalAccommodationCost: report.TotalAccommodationCost,
+		TotalCost:              report.TotalCost,
+		AverageTeamCost:        report.AverageTeamCost,
+		Teams:                  report.Teams,
+	})
+}
+
+// GetTeamTravelCost returns one team's estimated travel cost for the season
+// GET /api/v1/draws/:id/constraints/travel-cost/teams/:teamId
+func (h *ConstraintHandler) GetTeamTravelCost(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("teamId"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid team ID")
+		return
+	}
+
+	draw, cost, ok := h.loadTravelCost(c)
+	if !ok {
+		return
+	}
+
+	teamCost, found := cost.TeamCost(draw, teamID)
+	if !found {
+		middleware.NotFound(c, "Team not in draw")
+		return
+	}
+	c.JSON(http.StatusOK, types.TeamTravelCostResponse{
+		DrawID: draw.ID,
+		Model:  cost.GetModel(),
+		Team:   teamCost,
+	})
+}
+
+// loadTravelCost loads the draw and its travel cost constraint with league
+// data, writing the error response when it can't
+func (h *ConstraintHandler) loadTravelCost(c *gin.Context) (*models.Draw, *constraints.TravelCostConstraint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return nil, nil, false
+	}
+
+	draw, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return nil, nil, false
+		}
+		log.Printf("Error retrieving draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return nil, nil, false
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return nil, nil, false
+	}
+	league, err := constraints.LoadLeagueData(context.Background(), h.teamRepo, h.venueRepo)
+	if err != nil {
+		log.Printf("Error loading teams and venues: %v", err)
+		middleware.InternalError(c, "Failed to load teams and venues")
+		return nil, nil, false
+	}
+	cost := engine.TravelCost()
+	cost.SetLeagueData(league)
+	return draw, cost, true
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/api/websocket"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// ConstraintTemplateHandler manages the library of named constraint
+// configurations draws are created from
+type ConstraintTemplateHandler struct {
+	templateRepo    storage.ConstraintTemplateRepository
+	drawRepo        storage.DrawRepository
+	competitionRepo storage.CompetitionRepository
+	wsHub           *websocket.Hub
+}
+
+// NewConstraintTemplateHandler creates a new constraint template handler
+func NewConstraintTemplateHandler(templateRepo storage.ConstraintTemplateRepository, drawRepo storage.DrawRepository, competitionRepo storage.CompetitionRepository, wsHub *websocket.Hub) *ConstraintTemplateHandler {
+	return &ConstraintTemplateHandler{
+		templateRepo:    templateRepo,
+		drawRepo:        drawRepo,
+		competitionRepo: competitionRepo,
+		wsHub:           wsHub,
+	}
+}
+
+// GetTemplates lists every constraint template at its latest version
+// GET /api/v1/constraint-templates
+func (h *ConstraintTemplateHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.templateRepo.List(context.Background())
+	if err != nil {
+		log.Printf("Error listing constraint templates: %v", err)
+		middleware.InternalError(c, "Failed to retrieve constraint templates")
+		return
+	}
+
+	responses := make([]types.ConstraintTemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = types.ConstraintTemplateToResponse(template)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetTemplate returns one constraint template at its latest version
+// GET /api/v1/constraint-templates/:id
+func (h *ConstraintTemplateHandler) GetTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid template ID")
+		return
+	}
+
+	template, err := h.templateRepo.Get(context.Background(), id)
+	if err != nil {
+		h.handleTemplateError(c, err, "Failed to retrieve constraint template")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ConstraintTemplateToResponse(template))
+}
+
+// CreateTemplate adds a constraint template as its version 1
+// POST /api/v1/constraint-templates
+func (h *ConstraintTemplateHandler) CreateTemplate(c *gin.Context) {
+	var req types.CreateConstraintTemplateRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	config, ok := encodeConstraintConfig(c, req.ConstraintConfig)
+	if !ok {
+		return
+	}
+
+	template := &models.ConstraintTemplate{
+		Name:        req.Name,
+		Description: req.Description,
+		Config:      config,
+	}
+	if err := template.Validate(); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.templateRepo.Create(context.Background(), template); err != nil {
+		h.handleTemplateError(c, err, "Failed to create constraint template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ConstraintTemplateToResponse(template))
+}
+
+// UpdateTemplate changes the fields provided. A changed configuration becomes
+// the template's next version; earlier versions are kept.
+// PUT /api/v1/constraint-templates/:id
+func (h *ConstraintTemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid template ID")
+		return
+	}
+
+	var req types.UpdateConstraintTemplateRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	template, err := h.templateRepo.Get(context.Background(), id)
+	if err != nil {
+		h.handleTemplateError(c, err, "Failed to retrieve constraint template")
+		return
+	}
+
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	if req.ConstraintConfig != nil {
+		config, ok := encodeConstraintConfig(c, req.ConstraintConfig)
+		if !ok {
+			return
+		}
+		template.Config = config
+	}
+	if err := template.Validate(); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.templateRepo.Update(context.Background(), template); err != nil {
+		h.handleTemplateError(c, err, "Failed to update constraint template")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ConstraintTemplateToResponse(template))
+}
+
+// DeleteTemplate removes a constraint template and its versions. Draws
+// created from it keep their configuration.
+// DELETE /api/v1/constraint-templates/:id
+func (h *ConstraintTemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid template ID")
+		return
+	}
+
+	if err := h.templateRepo.Delete(context.Background(), id); err != nil {
+		h.handleTemplateError(c, err, "Failed to delete constraint template")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SuccessResponse{
+		Success: true,
+		Message: "Constraint template deleted successfully",
+	})
+}
+
+// GetTemplateVersions lists every version of a template, latest first
+// GET /api/v1/constraint-templates/:id/versions
+func (h *ConstraintTemplateHandler) GetTemplateVersions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid template ID")
+		return
+	}
+
+	if _, err := h.templateRepo.Get(context.Background(), id); err != nil {
+		h.handleTemplateError(c, err, "Failed to retrieve constraint template")
+		return
+	}
+
+	versions, err := h.templateRepo.ListVersions(context.Background(), id)
+	if err != nil {
+		h.handleTemplateError(c, err, "Failed to retrieve constraint template versions")
+		return
+	}
+
+	responses := make([]types.ConstraintTemplateVersionResponse, len(versions))
+	for i, version := range versions {
+		responses[i] = types.ConstraintTemplateVersionToResponse(version)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetTemplateVersion returns one version of a template
+// GET /api/v1/constraint-templates/:id/versions/:version
+func (h *ConstraintTemplateHandler) GetTemplateVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid template ID")
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid template version")
+		return
+	}
+
+	templateVersion, err := h.templateRepo.GetVersion(context.Background(), id, version)
+	if err != nil {
+		h.handleVersionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.ConstraintTemplateVersionToResponse(templateVersion))
+}
+
+// CreateDrawFromTemplate creates a draft draw with the template's
+// configuration, at its latest version unless the request names one. The
+// draw keeps its own copy, so later template versions don't change it.
+// POST /api/v1/constraint-templates/:id/draws
+func (h *ConstraintTemplateHandler) CreateDrawFromTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid template ID")
+		return
+	}
+
+	var req types.CreateDrawFromTemplateRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var config json.RawMessage
+	if req.Version != nil {
+		templateVersion, err := h.templateRepo.GetVersion(context.Background(), id, *req.Version)
+		if err != nil {
+			h.handleVersionError(c, err)
+			return
+		}
+		config = templateVersion.Config
+	} else {
+		template, err := h.templateRepo.Get(context.Background(), id)
+		if err != nil {
+			h.handleTemplateError(c, err, "Failed to retrieve constraint template")
+			return
+		}
+		config = template.Config
+	}
+
+	if !competitionExists(c, h.competitionRepo, req.CompetitionID) {
+		return
+	}
+
+	drawModel := &models.Draw{
+		Name:             req.Name,
+		SeasonYear:       req.SeasonYear,
+		Rounds:           req.Rounds,
+		Status:           models.DrawStatusDraft,
+		ConstraintConfig: config,
+		CompetitionID:    req.CompetitionID,
+	}
+
+	if err := h.drawRepo.Create(context.Background(), drawModel); err != nil {
+		middleware.InternalError(c, "Failed to create draw")
+		return
+	}
+
+	// Broadcast draw creation event
+	if h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.DrawCreated, websocket.DrawEventData{
+			Draw:      drawModel,
+			Timestamp: time.Now(),
+		})
+	}
+
+	c.JSON(http.StatusCreated, types.DrawToResponse(drawModel))
+}
+
+// handleVersionError maps errors retrieving a template version to responses
+func (h *ConstraintTemplateHandler) handleVersionError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrNotFound) {
+		middleware.NotFound(c, "Constraint template version not found")
+		return
+	}
+	h.handleTemplateError(c, err, "Failed to retrieve constraint template version")
+}
+
+// handleTemplateError maps constraint template storage errors to responses
+func (h *ConstraintTemplateHandler) handleTemplateError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		middleware.NotFound(c, "Constraint template not found")
+	case errors.Is(err, storage.ErrConflict):
+		middleware.Conflict(c, "A constraint template with that name already exists")
+	default:
+		log.Printf("%s: %v", message, err)
+		middleware.InternalError(c, message)
+	}
+}
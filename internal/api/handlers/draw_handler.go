@@ -3,6 +3,8 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -13,24 +15,35 @@ import (
 	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
 	"github.com/adampetrovic/nrl-scheduler/internal/api/websocket"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/draw"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/slots"
 	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 	"github.com/adampetrovic/nrl-scheduler/pkg/types"
 )
 
 type DrawHandler struct {
-	drawRepo  storage.DrawRepository
-	teamRepo  storage.TeamRepository
-	matchRepo storage.MatchRepository
-	wsHub     *websocket.Hub
+	drawRepo        storage.DrawRepository
+	teamRepo        storage.TeamRepository
+	venueRepo       storage.VenueRepository
+	matchRepo       storage.MatchRepository
+	competitionRepo storage.CompetitionRepository
+	calendarRepo    storage.SeasonCalendarRepository
+	// repos begins transactions saving a draw with its matches
+	repos storage.Repositories
+	wsHub *websocket.Hub
 }
 
-func NewDrawHandler(drawRepo storage.DrawRepository, teamRepo storage.TeamRepository, matchRepo storage.MatchRepository, wsHub *websocket.Hub) *DrawHandler {
+func NewDrawHandler(repos storage.Repositories, wsHub *websocket.Hub) *DrawHandler {
 	return &DrawHandler{
-		drawRepo:  drawRepo,
-		teamRepo:  teamRepo,
-		matchRepo: matchRepo,
-		wsHub:     wsHub,
+		drawRepo:        repos.Draws(),
+		teamRepo:        repos.Teams(),
+		venueRepo:       repos.Venues(),
+		matchRepo:       repos.Matches(),
+		competitionRepo: repos.Competitions(),
+		calendarRepo:    repos.SeasonCalendars(),
+		repos:           repos,
+		wsHub:           wsHub,
 	}
 }
 
@@ -41,15 +54,12 @@ func (h *DrawHandler) GetDraws(c *gin.Context) {
 		return
 	}
 
-	// Set defaults
-	if params.Page == 0 {
-		params.Page = 1
+	filter := storage.DrawFilter{
+		ListOptions: listOptions(&params),
+		SeasonYear:  params.SeasonYear,
+		Status:      params.Status,
 	}
-	if params.PerPage == 0 {
-		params.PerPage = 20
-	}
-
-	draws, err := h.drawRepo.List(context.Background())
+	draws, total, err := h.drawRepo.ListFiltered(context.Background(), filter)
 	if err != nil {
 		log.Printf("Error retrieving draws: %v", err)
 		middleware.InternalError(c, "Failed to retrieve draws")
@@ -59,34 +69,10 @@ func (h *DrawHandler) GetDraws(c *gin.Context) {
 	// Convert to response format
 	drawResponses := make([]types.DrawResponse, len(draws))
 	for i, draw := range draws {
-		log.Printf("Converting draw %d: %+v", draw.ID, draw)
 		drawResponses[i] = types.DrawToResponse(draw)
 	}
 
-	// Simple pagination
-	total := len(drawResponses)
-	start := (params.Page - 1) * params.PerPage
-	end := start + params.PerPage
-	
-	if start >= total {
-		drawResponses = []types.DrawResponse{}
-	} else if end > total {
-		drawResponses = drawResponses[start:]
-	} else {
-		drawResponses = drawResponses[start:end]
-	}
-
-	totalPages := (total + params.PerPage - 1) / params.PerPage
-
-	response := types.PaginatedResponse{
-		Data:       drawResponses,
-		Total:      total,
-		Page:       params.Page,
-		PerPage:    params.PerPage,
-		TotalPages: totalPages,
-	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, paginatedResponse(drawResponses, total, params))
 }
 
 func (h *DrawHandler) GetDraw(c *gin.Context) {
@@ -99,7 +85,7 @@ func (h *DrawHandler) GetDraw(c *gin.Context) {
 
 	drawModel, err := h.drawRepo.Get(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Draw not found")
 			return
 		}
@@ -121,6 +107,11 @@ func (h *DrawHandler) CreateDraw(c *gin.Context) {
 	// Convert constraint config to JSON if provided
 	var constraintConfigJSON json.RawMessage
 	if req.ConstraintConfig != nil {
+		if err := constraints.FreezeRivalryWeights(req.ConstraintConfig); err != nil {
+			middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+			return
+		}
+		
 		var err error
 		constraintConfigJSON, err = json.Marshal(req.ConstraintConfig)
 		if err != nil {
@@ -129,12 +120,17 @@ func (h *DrawHandler) CreateDraw(c *gin.Context) {
 		}
 	}
 
+	if !competitionExists(c, h.competitionRepo, req.CompetitionID) {
+		return
+	}
+
 	drawModel := &models.Draw{
 		Name:             req.Name,
 		SeasonYear:       req.SeasonYear,
 		Rounds:           req.Rounds,
 		Status:           models.DrawStatusDraft,
 		ConstraintConfig: constraintConfigJSON,
+		CompetitionID:    req.CompetitionID,
 	}
 
 	if err := h.drawRepo.Create(context.Background(), drawModel); err != nil {
@@ -154,6 +150,116 @@ func (h *DrawHandler) CreateDraw(c *gin.Context) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// CloneDraw copies a draw as a scenario to experiment with, leaving the
+// original untouched. Its matches are copied too when with_matches is set.
+// POST /api/v1/draws/:id/clone
+func (h *DrawHandler) CloneDraw(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	withMatches := false
+	if value := c.Query("with_matches"); value != "" {
+		withMatches, err = strconv.ParseBool(value)
+		if err != nil {
+			middleware.BadRequest(c, "with_matches must be true or false")
+			return
+		}
+	}
+
+	var req types.CloneDrawRequest
+	if c.Request.ContentLength != 0 {
+		if err := middleware.BindAndValidate(c, &req); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	source, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	clone := &models.Draw{
+		Name:             source.Name + " (copy)",
+		SeasonYear:       source.SeasonYear,
+		Rounds:           source.Rounds,
+		Status:           models.DrawStatusDraft,
+		ConstraintConfig: source.ConstraintConfig,
+		CompetitionID:    source.CompetitionID,
+	}
+	if req.Name != nil {
+		clone.Name = *req.Name
+	}
+	if req.ConstraintConfig != nil {
+		if err := constraints.FreezeRivalryWeights(req.ConstraintConfig); err != nil {
+			middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+			return
+		}
+		
+		clone.ConstraintConfig, err = json.Marshal(req.ConstraintConfig)
+		if err != nil {
+			middleware.BadRequest(c, "Invalid constraint configuration")
+			return
+		}
+	}
+
+	// Copies of generated draws, including ones being optimized, take the
+	// matches as they stand and can be optimized straight away
+	var matches, byes []*models.Match
+	if withMatches && len(source.Matches) > 0 {
+		clone.Status = models.DrawStatusCompleted
+		if source.Status == models.DrawStatusDraft {
+			clone.Status = models.DrawStatusDraft
+		}
+		for _, match := range source.Matches {
+			copied := *match
+			copied.ID = 0
+			copied.HomeTeam, copied.AwayTeam, copied.Venue = nil, nil, nil
+			matches = append(matches, &copied)
+		}
+		for _, bye := range source.Byes {
+			copied := *bye
+			copied.ID = 0
+			byes = append(byes, &copied)
+		}
+	}
+
+	if err := h.drawRepo.Create(context.Background(), clone); err != nil {
+		log.Printf("Error cloning draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to clone draw")
+		return
+	}
+	for _, match := range append(matches, byes...) {
+		match.DrawID = clone.ID
+	}
+	if err := h.matchRepo.CreateBatch(context.Background(), append(matches, byes...)); err != nil {
+		log.Printf("Error copying matches from draw %d: %v", id, err)
+		if err := h.drawRepo.Delete(context.Background(), clone.ID); err != nil {
+			log.Printf("Error removing incomplete clone %d: %v", clone.ID, err)
+		}
+		middleware.InternalError(c, "Failed to copy matches")
+		return
+	}
+	clone.Matches, clone.Byes = matches, byes
+
+	if h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.DrawCreated, websocket.DrawEventData{
+			Draw:      clone,
+			Timestamp: time.Now(),
+		})
+	}
+
+	c.JSON(http.StatusCreated, types.DrawToResponse(clone))
+}
+
 func (h *DrawHandler) UpdateDraw(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -170,7 +276,7 @@ func (h *DrawHandler) UpdateDraw(c *gin.Context) {
 
 	drawModel, err := h.drawRepo.Get(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Draw not found")
 			return
 		}
@@ -188,7 +294,21 @@ func (h *DrawHandler) UpdateDraw(c *gin.Context) {
 	if req.Rounds != nil {
 		drawModel.Rounds = *req.Rounds
 	}
+	if req.CompetitionID != nil {
+		if !competitionExists(c, h.competitionRepo, req.CompetitionID) {
+			return
+		}
+		drawModel.CompetitionID = req.CompetitionID
+	}
+	if req.Version != nil {
+		drawModel.Version = *req.Version
+	}
 	if req.ConstraintConfig != nil {
+		if err := constraints.FreezeRivalryWeights(req.ConstraintConfig); err != nil {
+			middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+			return
+		}
+		
 		var err error
 		drawModel.ConstraintConfig, err = json.Marshal(req.ConstraintConfig)
 		if err != nil {
@@ -198,6 +318,10 @@ func (h *DrawHandler) UpdateDraw(c *gin.Context) {
 	}
 
 	if err := h.drawRepo.Update(context.Background(), drawModel); err != nil {
+		if errors.Is(err, storage.ErrVersionConflict) {
+			drawVersionConflict(c, h.drawRepo, id, drawModel.Version)
+			return
+		}
 		middleware.InternalError(c, "Failed to update draw")
 		return
 	}
@@ -223,7 +347,7 @@ func (h *DrawHandler) DeleteDraw(c *gin.Context) {
 	}
 
 	if err := h.drawRepo.Delete(context.Background(), id); err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Draw not found")
 			return
 		}
@@ -253,9 +377,54 @@ func (h *DrawHandler) GetDrawMatches(c *gin.Context) {
 		return
 	}
 
+	if _, err := h.drawRepo.Get(context.Background(), id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	matches, err := h.matchRepo.ListByDrawWithRelations(context.Background(), id)
+	if err != nil {
+		log.Printf("Error retrieving matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve matches")
+		return
+	}
+
+	matchResponses := make([]types.MatchResponse, len(matches))
+	for i, match := range matches {
+		matchResponses[i] = types.MatchToResponse(match, match.HomeTeam, match.AwayTeam, match.Venue)
+	}
+
+	// A full season is several hundred matches; let large consumers stream it
+	if middleware.WantsNDJSON(c) {
+		middleware.StreamNDJSONSlice(c, matchResponses)
+		return
+	}
+
+	c.JSON(http.StatusOK, matchResponses)
+}
+
+// GetRound returns one round's matches with the round's bye teams, prime-time
+// matches, venues and region spread
+// GET /api/v1/draws/:id/rounds/:round
+func (h *DrawHandler) GetRound(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid round")
+		return
+	}
+
 	drawModel, err := h.drawRepo.Get(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Draw not found")
 			return
 		}
@@ -263,25 +432,225 @@ func (h *DrawHandler) GetDrawMatches(c *gin.Context) {
 		return
 	}
 
-	// For now, return the matches from the draw model
-	// In a full implementation, you might fetch from a match repository
-	matchResponses := make([]types.MatchResponse, len(drawModel.Matches))
-	for i, match := range drawModel.Matches {
-		var homeTeam, awayTeam *models.Team
-		var venue *models.Venue
-		
-		if match.HomeTeamID != nil {
-			homeTeam, _ = h.teamRepo.Get(context.Background(), *match.HomeTeamID)
+	drawModel.Matches, err = h.matchRepo.ListByDrawWithRelations(context.Background(), id)
+	if err != nil {
+		log.Printf("Error retrieving matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve matches")
+		return
+	}
+
+	fixtures, err := draw.Round(drawModel, round)
+	if err != nil {
+		middleware.NotFound(c, "Round not found")
+		return
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(drawModel.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, types.RoundToResponse(id, fixtures, engine.AnalyzeRegionSpread(drawModel)))
+}
+
+// GetTeamSchedule returns one team's season in round order, with its
+// opponents, venues, rest days and away streaks
+// GET /api/v1/draws/:id/teams/:teamId/schedule
+func (h *DrawHandler) GetTeamSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+	teamID, err := strconv.Atoi(c.Param("teamId"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid team ID")
+		return
+	}
+
+	drawModel, err := h.drawRepo.Get(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
 		}
-		if match.AwayTeamID != nil {
-			awayTeam, _ = h.teamRepo.Get(context.Background(), *match.AwayTeamID)
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	drawModel.Matches, err = h.matchRepo.ListByDrawWithRelations(context.Background(), id)
+	if err != nil {
+		log.Printf("Error retrieving matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve matches")
+		return
+	}
+
+	schedule, err := draw.TeamSchedule(drawModel, teamID)
+	if err != nil {
+		if errors.Is(err, draw.ErrTeamNotInDraw) {
+			middleware.NotFound(c, err.Error())
+			return
 		}
-		// Placeholder venue - would fetch from venue repo if VenueID exists
-		
-		matchResponses[i] = types.MatchToResponse(match, homeTeam, awayTeam, venue)
+		middleware.InternalError(c, "Failed to build team schedule")
+		return
 	}
 
-	c.JSON(http.StatusOK, matchResponses)
+	c.JSON(http.StatusOK, types.TeamScheduleToResponse(id, teamID, schedule))
+}
+
+// AddDrawTeam adds a team to a generated draw, such as an expansion club,
+// changing only the matches needed to fit it in
+// POST /api/v1/draws/:id/teams
+func (h *DrawHandler) AddDrawTeam(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var req types.AddDrawTeamRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	team, err := h.teamRepo.Get(context.Background(), req.TeamID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Team not found")
+			return
+		}
+		middleware.InternalError(c, "Failed to retrieve team")
+		return
+	}
+
+	drawModel, err := h.drawRepo.Get(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+	if !team.PlaysIn(drawModel.CompetitionID) {
+		middleware.BadRequest(c, "Team doesn't play in the draw's competition")
+		return
+	}
+
+	h.changeDrawTeam(c, id, req.TeamID, draw.AddTeam)
+}
+
+// RemoveDrawTeam removes a team from a generated draw, handing its matches to
+// teams with byes where it can
+// DELETE /api/v1/draws/:id/teams/:teamId
+func (h *DrawHandler) RemoveDrawTeam(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+	teamID, err := strconv.Atoi(c.Param("teamId"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid team ID")
+		return
+	}
+
+	h.changeDrawTeam(c, id, teamID, draw.RemoveTeam)
+}
+
+// changeDrawTeam applies a team change to an unpublished draw's matches and
+// saves the matches it touched
+func (h *DrawHandler) changeDrawTeam(c *gin.Context, id, teamID int, apply func(*models.Draw, int, []*models.Team) (*draw.TeamChange, error)) {
+	drawModel, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	if drawModel.Status == models.DrawStatusOptimizing {
+		middleware.Conflict(c, "Draw is being optimized")
+		return
+	}
+	if drawModel.PublishedAt != nil {
+		middleware.Conflict(c, "Published draws can't change teams")
+		return
+	}
+	if len(drawModel.Matches) == 0 {
+		middleware.BadRequest(c, "Draw has not been generated yet")
+		return
+	}
+
+	teams, err := h.teamRepo.List(context.Background(), storage.ListOptions{})
+	if err != nil {
+		log.Printf("Error listing teams for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve teams")
+		return
+	}
+
+	change, err := apply(drawModel, teamID, teams)
+	if err != nil {
+		switch {
+		case errors.Is(err, draw.ErrTeamNotInDraw):
+			middleware.NotFound(c, err.Error())
+		case errors.Is(err, draw.ErrTeamInDraw), errors.Is(err, draw.ErrTeamHasLockedMatches):
+			middleware.Conflict(c, err.Error())
+		default:
+			log.Printf("Error changing teams in draw %d: %v", id, err)
+			middleware.InternalError(c, "Failed to change draw teams")
+		}
+		return
+	}
+
+	if err := h.matchRepo.CreateBatch(context.Background(), change.Created); err != nil {
+		log.Printf("Error saving matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to save new matches")
+		return
+	}
+	if err := h.matchRepo.UpdateBatch(context.Background(), change.Updated); err != nil {
+		log.Printf("Error updating matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to update matches")
+		return
+	}
+	for _, match := range change.Deleted {
+		if err := h.matchRepo.Delete(context.Background(), match.ID); err != nil {
+			log.Printf("Error deleting match %d from draw %d: %v", match.ID, id, err)
+			middleware.InternalError(c, "Failed to delete matches")
+			return
+		}
+	}
+	if err := h.matchRepo.SyncByes(context.Background(), id); err != nil {
+		log.Printf("Error updating byes for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to update byes")
+		return
+	}
+
+	if h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.DrawUpdated, websocket.DrawEventData{
+			Draw:      drawModel,
+			Timestamp: time.Now(),
+		})
+	}
+
+	matchResponses := func(matches []*models.Match) []types.MatchResponse {
+		responses := make([]types.MatchResponse, len(matches))
+		for i, match := range matches {
+			responses[i] = types.MatchToResponse(match, nil, nil, nil)
+		}
+		return responses
+	}
+	c.JSON(http.StatusOK, types.DrawTeamChangeResponse{
+		DrawID:  id,
+		TeamID:  teamID,
+		Created: matchResponses(change.Created),
+		Updated: matchResponses(change.Updated),
+		Deleted: matchResponses(change.Deleted),
+	})
 }
 
 func (h *DrawHandler) GenerateDraw(c *gin.Context) {
@@ -298,9 +667,19 @@ func (h *DrawHandler) GenerateDraw(c *gin.Context) {
 		return
 	}
 
+	var seasonStart *time.Time
+	if req.Options != nil && req.Options.SeasonStart != "" {
+		start, err := time.Parse("2006-01-02", req.Options.SeasonStart)
+		if err != nil {
+			middleware.BadRequest(c, fmt.Sprintf("Invalid season_start %q, expected YYYY-MM-DD", req.Options.SeasonStart))
+			return
+		}
+		seasonStart = &start
+	}
+
 	drawModel, err := h.drawRepo.Get(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Draw not found")
 			return
 		}
@@ -308,27 +687,244 @@ func (h *DrawHandler) GenerateDraw(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement actual draw generation
-	// For now, just change status to optimizing
-	drawModel.Status = models.DrawStatusOptimizing
+	if drawModel.Status == models.DrawStatusOptimizing {
+		middleware.Conflict(c, "Draw is being optimized")
+		return
+	}
+	if drawModel.PublishedAt != nil {
+		middleware.Conflict(c, "Published draws can't be regenerated")
+		return
+	}
+
+	// Regenerating replaces every match, so it would discard pinned fixtures
+	existing, err := h.matchRepo.ListByDraw(context.Background(), id)
+	if err != nil {
+		log.Printf("Error listing matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve matches")
+		return
+	}
+	for _, match := range existing {
+		if match.Locked {
+			middleware.Conflict(c, "Draw has locked matches; unlock them before regenerating")
+			return
+		}
+	}
+
+	// Only the draw's competition's teams are drawn
+	teamOpts := storage.ListOptions{}
+	if drawModel.CompetitionID != nil {
+		teamOpts.CompetitionID = *drawModel.CompetitionID
+	}
+	teams, err := h.teamRepo.List(context.Background(), teamOpts)
+	if err != nil {
+		log.Printf("Error listing teams for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve teams")
+		return
+	}
+
+	// Reject configurations that can't be satisfied before attempting generation
+	var warnings []constraints.ConfigConflict
+	if req.Constraints != nil {
+		meta := constraints.ConflictContext{Teams: len(teams), Rounds: drawModel.Rounds}
+		issues, err := constraints.ValidateConstraintConfig(*req.Constraints, meta)
+		if err != nil {
+			middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+			return
+		}
+		
+		warnings = append(issues, constraints.DetectConfigConflicts(*req.Constraints, meta)...)
+		if constraints.HasBlockingConflicts(warnings) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     "Constraint configuration has conflicts",
+				"code":      "CONSTRAINT_CONFLICT",
+				"conflicts": warnings,
+			})
+			return
+		}
+	}
+
+	// Use the request's constraints, then the draw's stored configuration,
+	// then its competition's, then the NRL defaults
+	var competition *models.Competition
+	if drawModel.CompetitionID != nil {
+		competition, err = h.competitionRepo.Get(context.Background(), *drawModel.CompetitionID)
+		if err != nil {
+			log.Printf("Error retrieving competition for draw %d: %v", id, err)
+			middleware.InternalError(c, "Failed to retrieve competition")
+			return
+		}
+	}
+	constraintConfig := constraints.GetDefaultNRLConstraintConfig()
+	switch {
+	case req.Constraints != nil:
+		constraintConfig = *req.Constraints
+	case len(drawModel.ConstraintConfig) > 0 && string(drawModel.ConstraintConfig) != "null":
+		constraintConfig, err = constraints.LoadConstraintConfigFromJSON(drawModel.ConstraintConfig)
+		if err != nil {
+			middleware.BadRequest(c, "Invalid stored constraint configuration: "+err.Error())
+			return
+		}
+	case competition != nil && len(competition.ConstraintConfig) > 0:
+		constraintConfig, err = constraints.LoadConstraintConfigFromJSON(competition.ConstraintConfig)
+		if err != nil {
+			middleware.BadRequest(c, "Invalid competition constraint configuration: "+err.Error())
+			return
+		}
+		// Kept on the draw so validation and optimization use it too
+		drawModel.ConstraintConfig = competition.ConstraintConfig
+	}
+
+	venues, err := h.venueRepo.List(context.Background(), storage.ListOptions{})
+	if err != nil {
+		log.Printf("Error listing venues for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve venues")
+		return
+	}
+
+	startedAt := time.Now()
+
+	generator, err := draw.NewConstraintAwareGenerator(teams, drawModel.Rounds, constraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Unable to generate draw: "+err.Error())
+		return
+	}
+	generator.GetConstraintEngine().SetLeagueData(constraints.NewLeagueData(teams, venues))
+	if err := generator.GetConstraintEngine().LoadOtherDraws(context.Background(), h.drawRepo); err != nil {
+		log.Printf("Error loading partner draws for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to load partner draws")
+		return
+	}
+	if req.Options != nil && req.Options.FixtureTemplate != nil {
+		if err := generator.SetTemplate(*req.Options.FixtureTemplate); err != nil {
+			middleware.BadRequest(c, "Invalid fixture template: "+err.Error())
+			return
+		}
+	}
+	if req.Options != nil && len(req.Options.PinnedFixtures) > 0 {
+		if err := generator.SetPinnedFixtures(req.Options.PinnedFixtures); err != nil {
+			middleware.BadRequest(c, "Invalid pinned fixtures: "+err.Error())
+			return
+		}
+	}
 	
-	if err := h.drawRepo.Update(context.Background(), drawModel); err != nil {
+	// Record the seed so the same fixture can be generated again
+	seed := startedAt.UnixNano()
+	if req.Options != nil && req.Options.Seed != nil {
+		seed = *req.Options.Seed
+	}
+	generator.SetSeed(seed)
+
+	generated, _, err := generator.GenerateWithConstraints()
+	if errors.Is(err, draw.ErrPinnedFixturesUnsatisfiable) || errors.Is(err, draw.ErrPinSearchLimit) {
+		middleware.BadRequest(c, "Unable to generate draw: "+err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error generating draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to generate draw")
+		return
+	}
+
+	// Date the fixture into the standard timeslots so date-based constraints
+	// have kickoffs to judge, stacking magic rounds over a single weekend
+	if seasonStart != nil {
+		roundTemplates := slots.StackMagicRounds(nil, generator.GetConstraintEngine().MagicRounds())
+		inventory := slots.BuildInventory(*seasonStart, drawModel.Rounds, slots.DefaultNRLTemplate(), roundTemplates)
+		scheduled, err := slots.NewAssigner(generator.GetConstraintEngine(), nil).Assign(generated, inventory)
+		if err != nil {
+			if errors.Is(err, slots.ErrNotEnoughSlots) {
+				middleware.BadRequest(c, "Unable to schedule timeslots: "+err.Error())
+				return
+			}
+			log.Printf("Error scheduling timeslots for draw %d: %v", id, err)
+			middleware.InternalError(c, "Failed to schedule timeslots")
+			return
+		}
+		generated = scheduled.Draw
+	}
+
+	if req.Constraints != nil {
+		configJSON, err := json.Marshal(req.Constraints)
+		if err != nil {
+			middleware.InternalError(c, "Failed to save constraint configuration")
+			return
+		}
+		drawModel.ConstraintConfig = configJSON
+	}
+	drawModel.Status = models.DrawStatusCompleted
+
+	// Regenerating replaces the draw's existing fixture and completes the
+	// draw in one transaction, so a failed save leaves the old fixture in
+	// place. Byes are rebuilt from the new fixture. The draw is saved first,
+	// as replacing its fixture moves it on to the next version.
+	ctx := context.Background()
+	tx, err := h.repos.BeginTx(ctx)
+	if err != nil {
+		middleware.InternalError(c, "Failed to save generated draw")
+		return
+	}
+	defer tx.Rollback()
+
+	if err := tx.Draws().Update(ctx, drawModel); err != nil {
+		if errors.Is(err, storage.ErrVersionConflict) {
+			drawVersionConflict(c, tx.Draws(), id, drawModel.Version)
+			return
+		}
 		middleware.InternalError(c, "Failed to update draw status")
 		return
 	}
+	if err := tx.Matches().ReplaceForDraw(ctx, id, generated.Matches); err != nil {
+		log.Printf("Error saving matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to save generated matches")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error saving generated draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to save generated draw")
+		return
+	}
+	// Replacing the fixture moved the draw on another version
+	drawModel.Version++
+	drawModel.Matches = generated.Matches
+
+	// Analyze after saving so violations reference the stored match IDs
+	analysis := generator.AnalyzeDraw(drawModel)
+	violations := make([]types.ConstraintViolation, len(analysis))
+	hardViolations := 0
+	for i, violation := range analysis {
+		violations[i] = types.ConstraintViolationToResponse(violation)
+		if violation.Severity == constraints.SeverityHard {
+			hardViolations++
+		}
+	}
+
+	generationTime := time.Since(startedAt)
+
+	if h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.DrawGenerated, websocket.DrawEventData{
+			Draw:      drawModel,
+			Timestamp: time.Now(),
+		})
+	}
 
 	response := types.GenerateDrawResponse{
-		Success:        true,
-		MatchCount:     0,
-		Violations:     []types.ConstraintViolation{},
-		Message:        "Draw generation started (placeholder implementation)",
+		Success:        hardViolations == 0,
+		MatchCount:     len(generated.Matches),
+		Violations:     violations,
+		Warnings:       warnings,
+		Message:        fmt.Sprintf("Generated %d matches over %d rounds with %d hard and %d soft violations", len(generated.Matches), drawModel.Rounds, hardViolations, len(analysis)-hardViolations),
 		GeneratedAt:    time.Now(),
-		GenerationTime: time.Millisecond,
+		GenerationTime: generationTime,
+		Seed:           seed,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// ValidateConstraints checks a generated draw against its constraints, or the
+// request's constraints when given, and explains every violation
+// GET /api/v1/draws/:id/validate-constraints
+// POST /api/v1/draws/:id/validate-constraints
 func (h *DrawHandler) ValidateConstraints(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -337,15 +933,18 @@ func (h *DrawHandler) ValidateConstraints(c *gin.Context) {
 		return
 	}
 
+	// GET requests validate against the draw's stored configuration
 	var req types.ValidateConstraintsRequest
-	if err := middleware.BindAndValidate(c, &req); err != nil {
-		c.Error(err)
-		return
+	if c.Request.Method == http.MethodPost && c.Request.ContentLength != 0 {
+		if err := middleware.BindAndValidate(c, &req); err != nil {
+			c.Error(err)
+			return
+		}
 	}
 
-	drawModel, err := h.drawRepo.Get(context.Background(), id)
+	drawModel, err := h.drawRepo.GetWithMatches(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Draw not found")
 			return
 		}
@@ -358,12 +957,34 @@ func (h *DrawHandler) ValidateConstraints(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement actual constraint validation
-	// For now, return a simple placeholder response
+	var engine *constraints.ConstraintEngine
+	if req.Constraints != nil {
+		engine, err = constraints.NewConstraintFactory().CreateConstraintEngine(*req.Constraints)
+	} else {
+		engine, err = constraints.NewConstraintEngineFromJSON(drawModel.ConstraintConfig)
+	}
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+	if !h.loadEngineData(c, engine, drawModel) {
+		return
+	}
+
+	analysis := engine.AnalyzeDraw(drawModel)
+	violations := make([]types.ConstraintViolation, len(analysis))
+	hardViolations := 0
+	for i, violation := range analysis {
+		violations[i] = types.ConstraintViolationToResponse(violation)
+		if violation.Severity == constraints.SeverityHard {
+			hardViolations++
+		}
+	}
+
 	response := types.ValidateConstraintsResponse{
-		IsValid:    true,
-		Violations: []types.ConstraintViolation{},
-		Score:      1.0,
+		IsValid:    hardViolations == 0,
+		Violations: violations,
+		Score:      engine.ScoreDraw(drawModel),
 	}
 
 	// Broadcast constraint validation event
@@ -371,11 +992,78 @@ func (h *DrawHandler) ValidateConstraints(c *gin.Context) {
 		h.wsHub.BroadcastMessage(websocket.ConstraintsValidated, websocket.ConstraintsValidatedData{
 			DrawID:      id,
 			IsValid:     response.IsValid,
-			Violations:  []constraints.ConstraintViolation{}, // Convert from types.ConstraintViolation if needed
+			Violations:  analysis,
 			Score:       response.Score,
 			ValidatedAt: time.Now(),
 		})
 	}
 
 	c.JSON(http.StatusOK, response)
+}
+
+// GetDrawStatistics runs every analysis the draw's constraint configuration
+// offers, such as home/away balance, rest, prime time and travel, and
+// returns them together
+// GET /api/v1/draws/:id/statistics
+func (h *DrawHandler) GetDrawStatistics(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	drawModel, err := h.drawRepo.GetWithMatches(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		log.Printf("Error retrieving draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	if drawModel.Status == models.DrawStatusDraft {
+		middleware.BadRequest(c, "Draw has not been generated yet")
+		return
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(drawModel.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+	if !h.loadEngineData(c, engine, drawModel) {
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DrawStatisticsResponse{
+		DrawID:     drawModel.ID,
+		Score:      engine.ScoreDraw(drawModel),
+		Statistics: engine.Statistics(drawModel),
+	})
+}
+
+// loadEngineData gives the engine the league data, partner draws and season
+// calendar its constraints check the draw against, writing the error
+// response when it can't
+func (h *DrawHandler) loadEngineData(c *gin.Context, engine *constraints.ConstraintEngine, drawModel *models.Draw) bool {
+	league, err := constraints.LoadLeagueData(context.Background(), h.teamRepo, h.venueRepo)
+	if err != nil {
+		log.Printf("Error loading teams and venues: %v", err)
+		middleware.InternalError(c, "Failed to load teams and venues")
+		return false
+	}
+	engine.SetLeagueData(league)
+	if err := engine.LoadOtherDraws(context.Background(), h.drawRepo); err != nil {
+		log.Printf("Error loading partner draws for draw %d: %v", drawModel.ID, err)
+		middleware.InternalError(c, "Failed to load partner draws")
+		return false
+	}
+	if err := engine.LoadCalendar(context.Background(), h.calendarRepo, drawModel); err != nil {
+		log.Printf("Error loading the season calendar for draw %d: %v", drawModel.ID, err)
+		middleware.InternalError(c, "Failed to load season calendar")
+		return false
+	}
+	return true
 }
\ No newline at end of file
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// DrawLocker reports the optimization job holding a draw's lock, if any
+type DrawLocker interface {
+	DrawLock(drawID int) (jobID string, locked bool)
+}
+
+// RequireDrawUnlocked rejects requests changing a draw while an optimization
+// job holds its lock. drawID finds the draw a request changes; requests it
+// can't place are passed on for the handler to reject.
+func RequireDrawUnlocked(locks DrawLocker, drawID func(c *gin.Context) (int, bool)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if id, ok := drawID(c); ok {
+			if jobID, locked := locks.DrawLock(id); locked {
+				drawLocked(c, id, jobID)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// DrawParam finds the draw named by the :id path parameter
+func DrawParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	return id, err == nil
+}
+
+// MatchDraw finds the draw of the match named by the :id path parameter
+func MatchDraw(matchRepo storage.MatchRepository) func(c *gin.Context) (int, bool) {
+	return func(c *gin.Context) (int, bool) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return 0, false
+		}
+		match, err := matchRepo.Get(context.Background(), id)
+		if err != nil {
+			return 0, false
+		}
+		return match.DrawID, true
+	}
+}
+
+// drawLocked responds that an optimization job holds a draw's lock
+func drawLocked(c *gin.Context, drawID int, jobID string) {
+	c.AbortWithStatusJSON(http.StatusConflict, types.ErrorResponse{
+		Error: "Draw is locked by a running optimization",
+		Code:  "DRAW_LOCKED",
+		Details: map[string]string{
+			"draw_id": strconv.Itoa(drawID),
+			"job_id":  jobID,
+		},
+	})
+}
+
+// drawVersionConflict responds that a draw was updated since the version a
+// change was made against
+func drawVersionConflict(c *gin.Context, drawRepo storage.DrawRepository, drawID, version int) {
+	details := map[string]string{
+		"draw_id": strconv.Itoa(drawID),
+		"version": strconv.Itoa(version),
+	}
+	if current, err := drawRepo.Get(context.Background(), drawID); err == nil {
+		details["current_version"] = strconv.Itoa(current.Version)
+	}
+	c.AbortWithStatusJSON(http.StatusConflict, types.ErrorResponse{
+		Error:   "Draw was changed by another update",
+		Code:    "VERSION_CONFLICT",
+		Details: details,
+	})
+}
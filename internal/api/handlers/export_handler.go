@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/export"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// ExportHandler renders draws as files for spreadsheets and calendars
+type ExportHandler struct {
+	drawRepo  storage.DrawRepository
+	teamRepo  storage.TeamRepository
+	venueRepo storage.VenueRepository
+	matchRepo storage.MatchRepository
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(drawRepo storage.DrawRepository, teamRepo storage.TeamRepository, venueRepo storage.VenueRepository, matchRepo storage.MatchRepository) *ExportHandler {
+	return &ExportHandler{
+		drawRepo:  drawRepo,
+		teamRepo:  teamRepo,
+		venueRepo: venueRepo,
+		matchRepo: matchRepo,
+	}
+}
+
+// ExportDraw renders the draw's fixture list as CSV or an iCalendar feed,
+// optionally limited to one team's or one venue's matches
+// GET /api/v1/draws/:id/export?format=csv|ics
+// POST /api/v1/draws/:id/export?format=csv|ics with locale overrides
+func (h *ExportHandler) ExportDraw(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var params types.ExportQueryParams
+	if err := middleware.BindQueryAndValidate(c, &params); err != nil {
+		middleware.BadRequest(c, "Invalid query parameters: format must be csv or ics")
+		return
+	}
+
+	locale, ok := exportLocale(c, params.Locale)
+	if !ok {
+		return
+	}
+
+	timezone := params.Timezone
+	if timezone == "" {
+		timezone = export.DefaultTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		middleware.BadRequest(c, fmt.Sprintf("Unknown time zone: %s", timezone))
+		return
+	}
+
+	ctx := context.Background()
+	draw, err := h.drawRepo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	// Scope names double as the calendar name and the file name
+	var scope []string
+	filter := export.Filter{TeamID: params.TeamID, VenueID: params.VenueID}
+	if params.TeamID > 0 {
+		team, err := h.teamRepo.Get(ctx, params.TeamID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				middleware.NotFound(c, "Team not found")
+				return
+			}
+			middleware.InternalError(c, "Failed to retrieve team")
+			return
+		}
+		scope = append(scope, team.Name)
+	}
+	if params.VenueID > 0 {
+		venue, err := h.venueRepo.Get(ctx, params.VenueID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				middleware.NotFound(c, "Venue not found")
+				return
+			}
+			middleware.InternalError(c, "Failed to retrieve venue")
+			return
+		}
+		scope = append(scope, venue.Name)
+	}
+
+	matches, err := h.matchRepo.ListByDrawWithRelations(ctx, id)
+	if err != nil {
+		log.Printf("Error retrieving matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve matches")
+		return
+	}
+	draw.Matches = matches
+
+	title := fmt.Sprintf("%s %d", draw.Name, draw.SeasonYear)
+	if len(scope) > 0 {
+		title = strings.Join(scope, ", ") + " - " + title
+	}
+
+	var buf bytes.Buffer
+	contentType := export.CSVContentType
+	switch params.Format {
+	case "csv":
+		err = export.WriteCSV(&buf, draw, locale, filter)
+	case "ics":
+		contentType = export.ICSContentType
+		err = export.WriteICS(&buf, draw, export.Calendar{
+			Name:     title,
+			Locale:   locale,
+			Filter:   filter,
+			Location: location,
+		})
+	}
+	if err != nil {
+		log.Printf("Error exporting draw %d as %s: %v", id, params.Format, err)
+		middleware.InternalError(c, "Failed to export draw")
+		return
+	}
+
+	// Override the API-wide JSON content type
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, exportFileName(title), params.Format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// ReportDraw renders a season report for clubs to review: each team's fixture
+// list, travel, home/away split, byes and prime-time games, and the draw's
+// constraint violations
+// GET /api/v1/draws/:id/report?format=html
+// POST /api/v1/draws/:id/report?format=html with locale overrides
+func (h *ExportHandler) ReportDraw(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var params types.ReportQueryParams
+	if err := middleware.BindQueryAndValidate(c, &params); err != nil {
+		middleware.BadRequest(c, "Invalid query parameters: format must be html")
+		return
+	}
+
+	locale, ok := exportLocale(c, params.Locale)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	draw, err := h.drawRepo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		middleware.InternalError(c, "Failed to retrieve draw")
+		return
+	}
+
+	matches, err := h.matchRepo.ListByDrawWithRelations(ctx, id)
+	if err != nil {
+		log.Printf("Error retrieving matches for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve matches")
+		return
+	}
+	draw.Matches = matches
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		middleware.BadRequest(c, "Invalid constraint configuration: "+err.Error())
+		return
+	}
+	league, err := constraints.LoadLeagueData(ctx, h.teamRepo, h.venueRepo)
+	if err != nil {
+		log.Printf("Error loading teams and venues: %v", err)
+		middleware.InternalError(c, "Failed to load teams and venues")
+		return
+	}
+	engine.SetLeagueData(league)
+	if err := engine.LoadOtherDraws(ctx, h.drawRepo); err != nil {
+		log.Printf("Error loading partner draws for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to load partner draws")
+		return
+	}
+
+	var buf bytes.Buffer
+	report := export.BuildReport(draw, engine, league, locale)
+	if err := export.WriteHTML(&buf, report); err != nil {
+		log.Printf("Error rendering report for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to render report")
+		return
+	}
+
+	// Override the API-wide JSON content type; reports open in the browser
+	c.Header("Content-Type", export.HTMLContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s-report.html"`, exportFileName(report.Title)))
+	c.Data(http.StatusOK, export.HTMLContentType, buf.Bytes())
+}
+
+// exportLocale resolves the export's locale preset. A posted body overrides
+// its formats and renames teams and venues for the export.
+func exportLocale(c *gin.Context, code string) (export.Locale, bool) {
+	locale := export.Locale{Code: code}
+	if c.Request.Method == http.MethodPost {
+		var req types.ExportLocaleRequest
+		if err := middleware.BindAndValidate(c, &req); err != nil {
+			c.Error(err)
+			return export.Locale{}, false
+		}
+
+		locale.DateFormat = req.DateFormat
+		locale.TimeFormat = req.TimeFormat
+		for _, override := range req.TeamNames {
+			if override.ToRound > 0 && override.ToRound < override.FromRound {
+				middleware.BadRequest(c, fmt.Sprintf("Team %d's name override ends before it starts", override.TeamID))
+				return export.Locale{}, false
+			}
+			locale.TeamNames = append(locale.TeamNames, export.TeamNameOverride{
+				TeamID:    override.TeamID,
+				Name:      override.Name,
+				FromRound: override.FromRound,
+				ToRound:   override.ToRound,
+			})
+		}
+		for _, override := range req.VenueNames {
+			locale.VenueNames = append(locale.VenueNames, export.VenueNameOverride{
+				VenueID:       override.VenueID,
+				Name:          override.Name,
+				EffectiveFrom: override.EffectiveFrom,
+				EffectiveTo:   override.EffectiveTo,
+			})
+		}
+	}
+
+	resolved, err := locale.Resolve()
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return export.Locale{}, false
+	}
+	return resolved, true
+}
+
+// exportFileName turns an export title into a safe file name
+func exportFileName(title string) string {
+	var name strings.Builder
+	lastDash := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			name.WriteRune(r)
+			lastDash = false
+		} else if !lastDash && name.Len() > 0 {
+			name.WriteRune('-')
+			lastDash = true
+		}
+	}
+	return strings.TrimSuffix(name.String(), "-")
+}
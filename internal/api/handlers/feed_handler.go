@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/feed"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// FeedCacheControl lets browsers and CDNs keep the fixture feed for an hour,
+// and serve it stale for a day while they revalidate
+const FeedCacheControl = "public, max-age=3600, stale-while-revalidate=86400"
+
+// FeedHandler serves the public fixture feed of published draws
+type FeedHandler struct {
+	feedService *feed.Service
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(feedService *feed.Service) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+	}
+}
+
+// GetPublishedFixtures returns a published draw's fixture without needing an
+// API key. The ETag is the feed's content hash, suffixed by the Gzip
+// middleware when the response is compressed, so clients polling with
+// If-None-Match or If-Modified-Since get a 304 until the fixture changes.
+// Unpublished draws are reported as not found.
+// GET /api/v1/public/draws/:id/fixtures
+func (h *FeedHandler) GetPublishedFixtures(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	fixture, err := h.feedService.PublishedFixture(context.Background(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, feed.ErrDrawNotPublished),
+			errors.Is(err, storage.ErrNotFound):
+			middleware.NotFound(c, "Draw not found")
+		default:
+			log.Printf("Error building fixture feed for draw %d: %v", id, err)
+			middleware.InternalError(c, "Failed to retrieve fixtures")
+		}
+		return
+	}
+
+	body, err := json.Marshal(fixture)
+	if err != nil {
+		log.Printf("Error encoding fixture feed for draw %d: %v", id, err)
+		middleware.InternalError(c, "Failed to retrieve fixtures")
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+	lastModified := fixture.LastModified.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	c.Header("Cache-Control", FeedCacheControl)
+	c.Header("Access-Control-Expose-Headers", "ETag, Last-Modified")
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// notModified reports whether the client's cached copy is current. An
+// If-None-Match header takes precedence over If-Modified-Since. The Gzip
+// middleware's suffix is ignored, since both encodings hold the same fixture.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = middleware.StripGzipETag(strings.TrimPrefix(strings.TrimSpace(candidate), "W/"))
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !lastModified.After(since)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/importer"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// ImportHandler handles bulk imports of teams and venues
+type ImportHandler struct {
+	repos storage.Repositories
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(repos storage.Repositories) *ImportHandler {
+	return &ImportHandler{
+		repos: repos,
+	}
+}
+
+// ImportVenues creates venues from a CSV document or a JSON array
+// POST /api/v1/venues/import
+func (h *ImportHandler) ImportVenues(c *gin.Context) {
+	format, ok := importFormat(c)
+	if !ok {
+		return
+	}
+
+	rows, parseErrors, err := importer.ParseVenues(c.Request.Body, format)
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	report, err := importer.ImportVenues(context.Background(), h.repos, rows, parseErrors)
+	respondImport(c, report, err, "venues")
+}
+
+// ImportTeams creates teams from a CSV document or a JSON array
+// POST /api/v1/teams/import
+func (h *ImportHandler) ImportTeams(c *gin.Context) {
+	format, ok := importFormat(c)
+	if !ok {
+		return
+	}
+
+	rows, parseErrors, err := importer.ParseTeams(c.Request.Body, format)
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	report, err := importer.ImportTeams(context.Background(), h.repos, rows, parseErrors)
+	respondImport(c, report, err, "teams")
+}
+
+// importFormat picks the import format from the request's Content-Type,
+// responding with an error when it's neither CSV nor JSON
+func importFormat(c *gin.Context) (string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return importer.FormatCSV, true
+	case "", "application/json":
+		return importer.FormatJSON, true
+	default:
+		middleware.BadRequest(c, "Content-Type must be text/csv or application/json")
+		return "", false
+	}
+}
+
+// respondImport writes the import report, or the invalid rows when the
+// import was rejected
+func respondImport(c *gin.Context, report *importer.Report, err error, kind string) {
+	switch {
+	case errors.Is(err, importer.ErrInvalidRows):
+		c.JSON(http.StatusUnprocessableEntity, report)
+	case errors.Is(err, importer.ErrNoRows):
+		middleware.BadRequest(c, err.Error())
+	case err != nil:
+		log.Printf("Error importing %s: %v", kind, err)
+		middleware.InternalError(c, "Failed to import "+kind)
+	default:
+		c.JSON(http.StatusCreated, report)
+	}
+}
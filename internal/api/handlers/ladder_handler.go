@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/ladder"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// LadderHandler imports season ladders and derives repeat matchups from them
+type LadderHandler struct {
+	ladderService *ladder.Service
+}
+
+// NewLadderHandler creates a new ladder handler
+func NewLadderHandler(ladderService *ladder.Service) *LadderHandler {
+	return &LadderHandler{
+		ladderService: ladderService,
+	}
+}
+
+// ImportLadder replaces a season's final ladder
+// PUT /api/v1/ladders/:season
+func (h *LadderHandler) ImportLadder(c *gin.Context) {
+	season, err := strconv.Atoi(c.Param("season"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid season")
+		return
+	}
+
+	var req types.ImportLadderRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	entries := make([]*models.LadderEntry, len(req.Entries))
+	for i, entry := range req.Entries {
+		entries[i] = &models.LadderEntry{
+			TeamID:   entry.TeamID,
+			Position: entry.Position,
+			Points:   entry.Points,
+		}
+	}
+
+	imported, err := h.ladderService.ImportLadder(context.Background(), season, entries)
+	if err != nil {
+		h.handleLadderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.LadderResponse{Season: season, Entries: imported})
+}
+
+// GetLadder returns a season's final ladder
+// GET /api/v1/ladders/:season
+func (h *LadderHandler) GetLadder(c *gin.Context) {
+	season, err := strconv.Atoi(c.Param("season"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid season")
+		return
+	}
+
+	entries, err := h.ladderService.GetLadder(context.Background(), season)
+	if err != nil {
+		h.handleLadderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.LadderResponse{Season: season, Entries: entries})
+}
+
+// DeriveRepeatMatchups applies repeat rules to a season's ladder to decide
+// who plays whom twice the following season
+// POST /api/v1/ladders/:season/repeat-matchups
+func (h *LadderHandler) DeriveRepeatMatchups(c *gin.Context) {
+	season, err := strconv.Atoi(c.Param("season"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid season")
+		return
+	}
+
+	var req types.RepeatMatchupsRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	matrix, err := h.ladderService.DeriveRepeats(context.Background(), season, req.Rules, req.RepeatOpponents)
+	if err != nil {
+		h.handleLadderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.RepeatMatchupsResponse{
+		OpponentMatrix:  matrix,
+		FixtureTemplate: matrix.FixtureTemplate(),
+	})
+}
+
+// handleLadderError maps ladder errors onto HTTP responses
+func (h *LadderHandler) handleLadderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ladder.ErrInvalidLadder),
+		errors.Is(err, ladder.ErrInvalidRule),
+		errors.Is(err, ladder.ErrUnsatisfiable):
+		middleware.BadRequest(c, err.Error())
+	case errors.Is(err, ladder.ErrLadderNotFound):
+		middleware.NotFound(c, err.Error())
+	default:
+		log.Printf("Error processing season ladder: %v", err)
+		middleware.InternalError(c, "Failed to process season ladder")
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// defaultPerPage is the page size of list endpoints when the query doesn't set one
+const defaultPerPage = 20
+
+// listOptions converts list query parameters into repository list options,
+// applying the default page and page size
+func listOptions(params *types.ListQueryParams) storage.ListOptions {
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.PerPage == 0 {
+		params.PerPage = defaultPerPage
+	}
+
+	return storage.ListOptions{
+		Page:          params.Page,
+		PerPage:       params.PerPage,
+		Search:        params.Search,
+		SortBy:        params.SortBy,
+		SortDir:       params.SortDir,
+		CompetitionID: params.CompetitionID,
+	}
+}
+
+// paginatedResponse wraps one page of results with the paging totals
+func paginatedResponse(data interface{}, total int, params types.ListQueryParams) types.PaginatedResponse {
+	return types.PaginatedResponse{
+		Data:       data,
+		Total:      total,
+		Page:       params.Page,
+		PerPage:    params.PerPage,
+		TotalPages: (total + params.PerPage - 1) / params.PerPage,
+	}
+}
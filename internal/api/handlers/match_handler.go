@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/api/websocket"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/reschedule"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// MatchHandler handles changes to individual matches
+type MatchHandler struct {
+	rescheduleService *reschedule.Service
+	wsHub             *websocket.Hub
+}
+
+// NewMatchHandler creates a new match handler
+func NewMatchHandler(rescheduleService *reschedule.Service, wsHub *websocket.Hub) *MatchHandler {
+	return &MatchHandler{
+		rescheduleService: rescheduleService,
+		wsHub:             wsHub,
+	}
+}
+
+// GetVenueSubstitutes ranks replacement venues for a match whose venue is unavailable
+// GET /api/v1/matches/:id/venue-substitutes
+func (h *MatchHandler) GetVenueSubstitutes(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid match ID")
+		return
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			middleware.BadRequest(c, "Limit must be a non-negative integer")
+			return
+		}
+	}
+
+	match, candidates, err := h.rescheduleService.FindVenueSubstitutes(context.Background(), id, limit)
+	if err != nil {
+		h.handleRescheduleError(c, err)
+		return
+	}
+
+	response := types.VenueSubstitutesResponse{
+		MatchID:        match.ID,
+		CurrentVenueID: match.VenueID,
+		Candidates:     make([]types.VenueSubstituteResponse, len(candidates)),
+	}
+	for i, candidate := range candidates {
+		response.Candidates[i] = types.VenueSubstituteResponse{
+			Venue:            types.VenueToResponse(candidate.Venue),
+			Available:        candidate.Available,
+			Conflicts:        candidate.Conflicts,
+			CapacityFit:      candidate.CapacityFit,
+			HomeDistanceKm:   candidate.HomeDistanceKm,
+			AwayDistanceKm:   candidate.AwayDistanceKm,
+			DistanceScore:    candidate.DistanceScore,
+			ConstraintImpact: candidate.ConstraintImpact,
+			Score:            candidate.Score,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ApplyVenueSubstitution moves a match to the chosen replacement venue
+// POST /api/v1/matches/:id/venue-substitutes
+func (h *MatchHandler) ApplyVenueSubstitution(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid match ID")
+		return
+	}
+
+	var req types.ApplyVenueSubstitutionRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	match, err := h.rescheduleService.ApplyVenueSubstitution(context.Background(), id, req.VenueID)
+	if err != nil {
+		h.handleRescheduleError(c, err)
+		return
+	}
+
+	// Broadcast match updated event
+	if h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.MatchUpdated, websocket.MatchEventData{
+			Match:     match,
+			DrawID:    match.DrawID,
+			Timestamp: time.Now(),
+		})
+	}
+
+	c.JSON(http.StatusOK, types.MatchToResponse(match, nil, nil, nil))
+}
+
+// PostponeMatch frees a washed-out match's slot and ranks the rounds and
+// dates it could be replayed in
+// POST /api/v1/matches/:id/postpone
+func (h *MatchHandler) PostponeMatch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid match ID")
+		return
+	}
+
+	var req types.PostponeMatchRequest
+	if c.Request.ContentLength != 0 {
+		if err := middleware.BindAndValidate(c, &req); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	match, options, err := h.rescheduleService.PostponeMatch(context.Background(), id, req.Limit)
+	if err != nil {
+		h.handleRescheduleError(c, err)
+		return
+	}
+
+	// Broadcast match updated event
+	if h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.MatchUpdated, websocket.MatchEventData{
+			Match:     match,
+			DrawID:    match.DrawID,
+			Timestamp: time.Now(),
+		})
+	}
+
+	response := types.PostponeMatchResponse{
+		Match:   types.MatchToResponse(match, nil, nil, nil),
+		Options: make([]types.PostponementOptionResponse, len(options)),
+	}
+	for i, option := range options {
+		response.Options[i] = types.PostponementOptionResponse{
+			Round:            option.Round,
+			MatchDate:        option.MatchDate,
+			Available:        option.Available,
+			Conflicts:        option.Conflicts,
+			DelayDays:        option.DelayDays,
+			ConstraintImpact: option.ConstraintImpact,
+			Score:            option.ScoreAfter,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// LockMatch pins a match in place, or releases it, so announced fixtures
+// survive regeneration and optimization
+// PATCH /api/v1/matches/:id/lock
+func (h *MatchHandler) LockMatch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid match ID")
+		return
+	}
+
+	var req types.LockMatchRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	match, err := h.rescheduleService.SetMatchLocked(context.Background(), id, *req.Locked)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Match not found")
+			return
+		}
+		log.Printf("Error locking match %d: %v", id, err)
+		middleware.InternalError(c, "Failed to update match lock")
+		return
+	}
+
+	// Broadcast match updated event
+	if h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.MatchUpdated, websocket.MatchEventData{
+			Match:     match,
+			DrawID:    match.DrawID,
+			Timestamp: time.Now(),
+		})
+	}
+
+	c.JSON(http.StatusOK, types.MatchToResponse(match, match.HomeTeam, match.AwayTeam, match.Venue))
+}
+
+// UpdateMatch edits a match by hand, reporting the constraint violations and
+// score change the edit causes. With dry_run=true nothing is saved. Edits that
+// break hard constraints are refused with 409 unless forced.
+// PATCH /api/v1/matches/:id?dry_run=true
+func (h *MatchHandler) UpdateMatch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid match ID")
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := c.Query("dry_run"); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			middleware.BadRequest(c, "dry_run must be a boolean")
+			return
+		}
+	}
+
+	var req types.UpdateMatchRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	change, err := parseMatchChange(req)
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	impact, err := h.rescheduleService.ChangeMatch(context.Background(), id, change, dryRun, req.Force)
+	if err != nil && !errors.Is(err, reschedule.ErrBreaksConstraints) {
+		switch {
+		case errors.Is(err, reschedule.ErrEmptyChange), errors.Is(err, reschedule.ErrInvalidChange):
+			middleware.BadRequest(c, err.Error())
+		default:
+			h.handleRescheduleError(c, err)
+		}
+		return
+	}
+
+	response := types.MatchChangeResponse{
+		Match:              types.MatchToResponse(impact.Match, impact.Match.HomeTeam, impact.Match.AwayTeam, impact.Match.Venue),
+		DryRun:             dryRun,
+		Applied:            impact.Applied,
+		Conflicts:          impact.Conflicts,
+		NewViolations:      make([]types.ConstraintViolation, len(impact.NewViolations)),
+		ResolvedViolations: make([]types.ConstraintViolation, len(impact.ResolvedViolations)),
+		ScoreBefore:        impact.ScoreBefore,
+		ScoreAfter:         impact.ScoreAfter,
+		ScoreChange:        impact.ScoreChange,
+	}
+	for i, violation := range impact.NewViolations {
+		response.NewViolations[i] = types.ConstraintViolationToResponse(violation)
+	}
+	for i, violation := range impact.ResolvedViolations {
+		response.ResolvedViolations[i] = types.ConstraintViolationToResponse(violation)
+	}
+
+	// The edit was refused; say why alongside what it would have done
+	if err != nil {
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	// Broadcast match updated event
+	if impact.Applied && h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.MatchUpdated, websocket.MatchEventData{
+			Match:     impact.Match,
+			DrawID:    impact.Match.DrawID,
+			Timestamp: time.Now(),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseMatchChange converts a requested match edit for the reschedule service
+func parseMatchChange(req types.UpdateMatchRequest) (reschedule.MatchChange, error) {
+	change := reschedule.MatchChange{
+		Round:       req.Round,
+		HomeTeamID:  req.HomeTeamID,
+		AwayTeamID:  req.AwayTeamID,
+		VenueID:     req.VenueID,
+		IsPrimeTime: req.PrimeTime,
+	}
+	if req.Date != nil {
+		date, err := time.Parse("2006-01-02", *req.Date)
+		if err != nil {
+			return change, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", *req.Date)
+		}
+		change.MatchDate = &date
+	}
+	if req.Time != nil {
+		kickoff, err := time.Parse("15:04", *req.Time)
+		if err != nil {
+			return change, fmt.Errorf("invalid time %q, expected HH:MM", *req.Time)
+		}
+		change.MatchTime = &kickoff
+	}
+	return change, nil
+}
+
+// handleRescheduleError maps reschedule service errors onto HTTP responses
+func (h *MatchHandler) handleRescheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, reschedule.ErrVenueUnavailable), errors.Is(err, reschedule.ErrMatchLocked):
+		middleware.Conflict(c, err.Error())
+	case errors.Is(err, reschedule.ErrByeMatch), errors.Is(err, reschedule.ErrSameVenue), errors.Is(err, reschedule.ErrNotScheduled):
+		middleware.BadRequest(c, err.Error())
+	case errors.Is(err, storage.ErrNotFound):
+		middleware.NotFound(c, err.Error())
+	default:
+		log.Printf("Error rescheduling match: %v", err)
+		middleware.InternalError(c, "Failed to reschedule match")
+	}
+}
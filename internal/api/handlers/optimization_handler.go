@@ -1,13 +1,20 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
 	"github.com/adampetrovic/nrl-scheduler/internal/api/websocket"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/optimizer"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 	"github.com/adampetrovic/nrl-scheduler/pkg/types"
 )
 
@@ -51,11 +58,154 @@ func (h *OptimizationHandler) StartOptimization(c *gin.Context) {
 		return
 	}
 
+	if request.StabilityWeight != nil && (*request.StabilityWeight < 0 || *request.StabilityWeight > 1) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid stability weight",
+			Details: map[string]string{
+				"stability_weight": "must be between 0 and 1",
+			},
+		})
+		return
+	}
+
+	if request.TimeBudgetSeconds < 0 || request.TimeBudgetSeconds > optimizer.MaxTimeBudgetSeconds {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid time budget",
+			Details: map[string]string{
+				"time_budget_seconds": fmt.Sprintf("must be between 1 and %d", optimizer.MaxTimeBudgetSeconds),
+			},
+		})
+		return
+	}
+
+	if request.HistoryInterval < 0 {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid history interval",
+			Details: map[string]string{
+				"history_interval": "must be a positive number of iterations",
+			},
+		})
+		return
+	}
+
+	if request.CheckpointInterval < 0 {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid checkpoint interval",
+			Details: map[string]string{
+				"checkpoint_interval": "must be a positive number of iterations",
+			},
+		})
+		return
+	}
+
+	if request.Algorithm == optimizer.AlgorithmTabuSearch && request.MultiStart != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid optimization config",
+			Details: map[string]string{
+				"multi_start": "is only supported by simulated annealing",
+			},
+		})
+		return
+	}
+
+	if request.Algorithm == optimizer.AlgorithmTabuSearch && len(request.Objectives) > 0 {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid optimization config",
+			Details: map[string]string{
+				"objectives": "are only supported by simulated annealing",
+			},
+		})
+		return
+	}
+
+	if request.ArchiveSize < 0 || request.ArchiveSize > optimizer.MaxArchiveSize {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid archive size",
+			Details: map[string]string{
+				"archive_size": fmt.Sprintf("must be between 1 and %d", optimizer.MaxArchiveSize),
+			},
+		})
+		return
+	}
+
+	if request.EarlyStopping != nil {
+		stopping := request.EarlyStopping
+		if stopping.NoImprovementIterations < 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Error: "Invalid early stopping",
+				Details: map[string]string{
+					"no_improvement_iterations": "must be a positive number of iterations",
+				},
+			})
+			return
+		}
+		if stopping.TargetScore != nil && (*stopping.TargetScore < 0 || *stopping.TargetScore > 1) {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Error: "Invalid early stopping",
+				Details: map[string]string{
+					"target_score": "must be between 0 and 1",
+				},
+			})
+			return
+		}
+	}
+
+	if request.Rounds != nil && (request.Rounds.FromRound < 1 || request.Rounds.ToRound < request.Rounds.FromRound) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid round window",
+			Details: map[string]string{
+				"rounds": "from_round must be at least 1 and no later than to_round",
+			},
+		})
+		return
+	}
+
 	// Convert request to optimization config
 	config := optimizer.OptimizationConfig{
+		Algorithm:     request.Algorithm,
 		Temperature:   request.Temperature,
 		CoolingRate:   request.CoolingRate,
 		MaxIterations: request.MaxIterations,
+		StabilityWeight: request.StabilityWeight,
+		TimeBudgetSeconds: request.TimeBudgetSeconds,
+		Seed:          request.Seed,
+		CheckpointInterval: request.CheckpointInterval,
+		HistoryInterval: request.HistoryInterval,
+		OperatorWeights: request.OperatorWeights,
+		WarmStart:     request.WarmStart,
+		ArchiveSize:   request.ArchiveSize,
+	}
+
+	for _, objective := range request.Objectives {
+		config.Objectives = append(config.Objectives, constraints.Objective(objective))
+	}
+
+	if request.EarlyStopping != nil {
+		config.EarlyStopping = optimizer.EarlyStopping{
+			NoImprovementIterations: request.EarlyStopping.NoImprovementIterations,
+			TargetScore:             request.EarlyStopping.TargetScore,
+		}
+	}
+
+	if request.Tabu != nil {
+		config.Tabu = optimizer.TabuConfig{
+			Tenure:           request.Tabu.Tenure,
+			NeighborhoodSize: request.Tabu.NeighborhoodSize,
+		}
+	}
+
+	if request.Rounds != nil {
+		config.Window = optimizer.RoundWindow{
+			From: request.Rounds.FromRound,
+			To:   request.Rounds.ToRound,
+		}
+	}
+
+	if request.MultiStart != nil {
+		config.MultiStart = optimizer.MultiStartConfig{
+			Starts:  request.MultiStart.Starts,
+			Workers: request.MultiStart.Workers,
+		}
 	}
 
 	if request.CoolingSchedule != nil {
@@ -72,6 +222,45 @@ func (h *OptimizationHandler) StartOptimization(c *gin.Context) {
 	}
 
 	jobID, err := h.optimizerService.OptimizeDraw(drawID, config)
+	if errors.Is(err, optimizer.ErrDrawLocked) {
+		holder, _ := h.optimizerService.DrawLock(drawID)
+		drawLocked(c, drawID, holder)
+		return
+	}
+	if errors.Is(err, storage.ErrVersionConflict) {
+		optimizationVersionConflict(c, "Failed to start optimization", map[string]string{
+			"draw_id": strconv.Itoa(drawID),
+			"error":   err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, optimizer.ErrInvalidOperatorWeights) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid operator weights",
+			Details: map[string]string{
+				"operator_weights": err.Error(),
+			},
+		})
+		return
+	}
+	if errors.Is(err, optimizer.ErrInvalidRoundWindow) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid round window",
+			Details: map[string]string{
+				"rounds": err.Error(),
+			},
+		})
+		return
+	}
+	if errors.Is(err, constraints.ErrInvalidObjectives) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid objectives",
+			Details: map[string]string{
+				"objectives": err.Error(),
+			},
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 			Error: "Failed to start optimization",
@@ -103,7 +292,8 @@ func (h *OptimizationHandler) StartOptimization(c *gin.Context) {
 func (h *OptimizationHandler) GetOptimizationStatus(c *gin.Context) {
 	jobID := c.Param("jobId")
 
-	job, err := h.optimizerService.GetOptimizationJob(jobID)
+	// A snapshot, as the job's status and progress change while it runs
+	job, err := h.optimizerService.GetOptimizationJobSnapshot(jobID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, types.ErrorResponse{
 			Error: "Optimization job not found",
@@ -130,13 +320,128 @@ func (h *OptimizationHandler) GetOptimizationStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetOptimizationHistory returns the sampled score, temperature and
+// acceptance-rate trajectory of a job for charting convergence
+// GET /api/v1/optimize/jobs/:jobId/history
+func (h *OptimizationHandler) GetOptimizationHistory(c *gin.Context) {
+	jobID := c.Param("jobId")
+
+	history, err := h.optimizerService.GetOptimizationHistory(jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.ErrorResponse{
+			Error: "Optimization job not found",
+			Details: map[string]string{
+				"job_id": jobID,
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// StreamOptimization subscribes a websocket to one job's progress, completion
+// and failure events. The job's current state is sent first, so clients
+// joining late still see how it finished. interval_ms sets the minimum time
+// between progress messages.
+// GET /ws/optimize/:jobId
+func (h *OptimizationHandler) StreamOptimization(c *gin.Context) {
+	jobID := c.Param("jobId")
+
+	job, err := h.optimizerService.GetOptimizationJobSnapshot(jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.ErrorResponse{
+			Error: "Optimization job not found",
+			Details: map[string]string{
+				"job_id": jobID,
+			},
+		})
+		return
+	}
+
+	var interval time.Duration
+	if value := c.Query("interval_ms"); value != "" {
+		ms, err := strconv.Atoi(value)
+		if err != nil || ms < int(websocket.MinProgressInterval/time.Millisecond) {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Error: "Invalid progress interval",
+				Details: map[string]string{
+					"interval_ms": fmt.Sprintf("must be an integer of at least %d", websocket.MinProgressInterval/time.Millisecond),
+				},
+			})
+			return
+		}
+		interval = time.Duration(ms) * time.Millisecond
+	}
+
+	if h.wsHub == nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{Error: "WebSocket hub not available"})
+		return
+	}
+
+	h.wsHub.ServeJobWS(c.Writer, c.Request, jobID, interval, jobStateMessage(job))
+}
+
+// jobStateMessage describes a job's current state as the event a subscriber
+// would have received last
+func jobStateMessage(job optimizer.OptimizationJob) websocket.Message {
+	finishedAt := time.Now()
+	if job.CompletedAt != nil {
+		finishedAt = *job.CompletedAt
+	}
+
+	switch job.Status {
+	case optimizer.JobStatusCompleted:
+		data := websocket.OptimizationCompletedData{
+			JobID:       job.ID,
+			DrawID:      job.DrawID,
+			CompletedAt: finishedAt,
+			Duration:    finishedAt.Sub(job.StartedAt),
+		}
+		if job.Result != nil {
+			data.FinalScore = job.Result.FinalScore
+			data.Iterations = job.Result.Iterations
+			data.Improvements = job.Result.Improvements
+			data.TerminationReason = job.Result.TerminationReason
+		}
+		return websocket.Message{Type: websocket.OptimizationCompleted, Data: data}
+	case optimizer.JobStatusFailed:
+		return websocket.Message{Type: websocket.OptimizationFailed, Data: websocket.OptimizationFailedData{
+			JobID:    job.ID,
+			DrawID:   job.DrawID,
+			Error:    job.Error,
+			FailedAt: finishedAt,
+		}}
+	case optimizer.JobStatusCancelled:
+		return websocket.Message{Type: websocket.OptimizationCancelled, Data: websocket.OptimizationCancelledData{
+			JobID:       job.ID,
+			DrawID:      job.DrawID,
+			CancelledAt: finishedAt,
+		}}
+	default:
+		return websocket.Message{Type: websocket.OptimizationProgress, Data: websocket.OptimizationProgressData{
+			JobID:          job.ID,
+			DrawID:         job.DrawID,
+			Iteration:      job.Progress.Iteration,
+			CurrentScore:   job.Progress.CurrentScore,
+			BestScore:      job.Progress.BestScore,
+			Temperature:    job.Progress.Temperature,
+			AcceptanceRate: job.Progress.AcceptanceRate,
+			Progress:       job.Progress.FractionComplete * 100.0,
+			WorstTeams:     job.Progress.WorstTeams,
+			ConstraintScores: job.Progress.ConstraintScores,
+			UpdatedAt:      time.Now(),
+		}}
+	}
+}
+
 // CancelOptimization cancels a running optimization job
 // POST /api/v1/optimize/:jobId/cancel
 func (h *OptimizationHandler) CancelOptimization(c *gin.Context) {
 	jobID := c.Param("jobId")
 
 	// Get job info before cancellation
-	job, _ := h.optimizerService.GetOptimizationJob(jobID)
+	job, jobErr := h.optimizerService.GetOptimizationJobSnapshot(jobID)
 
 	err := h.optimizerService.CancelOptimization(jobID)
 	if err != nil {
@@ -151,12 +456,14 @@ func (h *OptimizationHandler) CancelOptimization(c *gin.Context) {
 	}
 
 	// Broadcast optimization cancelled event
-	if h.wsHub != nil && job != nil {
-		h.wsHub.BroadcastMessage(websocket.OptimizationCancelled, websocket.OptimizationCancelledData{
+	if h.wsHub != nil && jobErr == nil {
+		data := websocket.OptimizationCancelledData{
 			JobID:       jobID,
 			DrawID:      job.DrawID,
 			CancelledAt: time.Now(),
-		})
+		}
+		h.wsHub.BroadcastMessage(websocket.OptimizationCancelled, data)
+		h.wsHub.SendToJob(jobID, websocket.OptimizationCancelled, data)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -185,12 +492,41 @@ func (h *OptimizationHandler) GetOptimizationResult(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetOptimizationDiff previews what applying a completed job's result would
+// change: moved matches, venue changes, home/away flips, and constraint and
+// per-team metrics before and after
+// GET /api/v1/optimize/jobs/:jobId/diff
+func (h *OptimizationHandler) GetOptimizationDiff(c *gin.Context) {
+	jobID := c.Param("jobId")
+
+	diff, err := h.optimizerService.DiffOptimizationResult(jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.ErrorResponse{
+			Error: "Optimization result not available",
+			Details: map[string]string{
+				"job_id": jobID,
+				"error":  err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
 // ApplyOptimizationResult applies the optimized draw to storage
 // POST /api/v1/optimize/:jobId/apply
 func (h *OptimizationHandler) ApplyOptimizationResult(c *gin.Context) {
 	jobID := c.Param("jobId")
 
 	err := h.optimizerService.ApplyOptimizationResult(jobID)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		optimizationVersionConflict(c, "Failed to apply optimization result", map[string]string{
+			"job_id": jobID,
+			"error":  err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 			Error: "Failed to apply optimization result",
@@ -256,6 +592,11 @@ func (h *OptimizationHandler) ValidateDrawConstraints(c *gin.Context) {
 		})
 	}
 
+	if middleware.WantsNDJSON(c) {
+		middleware.StreamNDJSONSlice(c, violations)
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -322,6 +663,11 @@ func (h *OptimizationHandler) ListOptimizationJobs(c *gin.Context) {
 		return
 	}
 
+	if middleware.WantsNDJSON(c) {
+		middleware.StreamNDJSONSlice(c, jobs)
+		return
+	}
+
 	c.JSON(http.StatusOK, types.OptimizationJobsResponse{
 		Jobs: jobs,
 	})
@@ -355,6 +701,16 @@ func (h *OptimizationHandler) SetOptimizationConfig(c *gin.Context) {
 		return
 	}
 
+	if err := config.OperatorWeights.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid operator weights",
+			Details: map[string]string{
+				"operator_weights": err.Error(),
+			},
+		})
+		return
+	}
+
 	h.optimizerService.SetOptimizationConfig(config)
 	c.JSON(http.StatusOK, gin.H{
 		"status": "updated",
@@ -362,14 +718,305 @@ func (h *OptimizationHandler) SetOptimizationConfig(c *gin.Context) {
 	})
 }
 
+// GetRetentionPolicy returns the optimization job retention policy
+// GET /api/v1/optimize/retention
+func (h *OptimizationHandler) GetRetentionPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, types.RetentionPolicyToResponse(h.optimizerService.GetRetentionPolicy()))
+}
+
+// SetRetentionPolicy replaces the optimization job retention policy
+// PUT /api/v1/optimize/retention
+func (h *OptimizationHandler) SetRetentionPolicy(c *gin.Context) {
+	var request types.RetentionPolicyRequest
+	if err := middleware.BindAndValidate(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	policy := types.RetentionPolicyFromRequest(request)
+	if err := h.optimizerService.SetRetentionPolicy(policy); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid retention policy",
+			Details: map[string]string{
+				"error": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.RetentionPolicyToResponse(policy))
+}
+
+// ApplyRetentionPolicy archives finished jobs and discards expired payloads now
+// rather than waiting for the next scheduled pass
+// POST /api/v1/optimize/retention/apply
+func (h *OptimizationHandler) ApplyRetentionPolicy(c *gin.Context) {
+	report, err := h.optimizerService.ApplyRetention(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Error: "Failed to apply retention policy",
+			Details: map[string]string{
+				"error": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListArchivedJobs returns archived optimization jobs, optionally filtered by draw ID
+// GET /api/v1/optimize/archives
+func (h *OptimizationHandler) ListArchivedJobs(c *gin.Context) {
+	drawIDStr := c.Query("draw_id")
+	var drawID int
+	var err error
+
+	if drawIDStr != "" {
+		drawID, err = strconv.Atoi(drawIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Error: "Invalid draw ID filter",
+				Details: map[string]string{
+					"draw_id": "must be a valid integer",
+				},
+			})
+			return
+		}
+	}
+
+	archives, err := h.optimizerService.ListArchivedJobs(context.Background(), drawID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Error: "Failed to list archived jobs",
+			Details: map[string]string{
+				"error": err.Error(),
+			},
+		})
+		return
+	}
+	if archives == nil {
+		archives = []*models.JobArchive{}
+	}
+
+	if middleware.WantsNDJSON(c) {
+		middleware.StreamNDJSONSlice(c, archives)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.JobArchivesResponse{
+		Archives: archives,
+	})
+}
+
+// ListSolutions returns the solution library's best-known draws, best first
+// GET /api/v1/optimize/solutions
+func (h *OptimizationHandler) ListSolutions(c *gin.Context) {
+	solutions, err := h.optimizerService.ListSolutions(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Error: "Failed to list solutions",
+			Details: map[string]string{
+				"error": err.Error(),
+			},
+		})
+		return
+	}
+	if solutions == nil {
+		solutions = []*models.Solution{}
+	}
+
+	c.JSON(http.StatusOK, types.SolutionsResponse{
+		Solutions: solutions,
+	})
+}
+
+// DeleteSolution removes a solution from the library
+// DELETE /api/v1/optimize/solutions/:id
+func (h *OptimizationHandler) DeleteSolution(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid solution ID",
+			Details: map[string]string{
+				"id": "must be a valid integer",
+			},
+		})
+		return
+	}
+
+	if err := h.optimizerService.DeleteSolution(context.Background(), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, types.ErrorResponse{
+			Error: "Failed to delete solution",
+			Details: map[string]string{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SuccessResponse{
+		Success: true,
+		Message: "Solution deleted successfully",
+	})
+}
+
+// RestoreOptimizationJob loads an archived job back into memory so its result
+// can be fetched and applied again
+// POST /api/v1/optimize/jobs/:jobId/restore
+func (h *OptimizationHandler) RestoreOptimizationJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+
+	job, err := h.optimizerService.RestoreJob(context.Background(), jobID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, optimizer.ErrArchivePayloadDiscarded):
+			status = http.StatusGone
+		case errors.Is(err, storage.ErrNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, types.ErrorResponse{
+			Error: "Failed to restore optimization job",
+			Details: map[string]string{
+				"job_id": jobID,
+				"error":  err.Error(),
+			},
+		})
+		return
+	}
+
+	response := types.OptimizationStatusResponse{
+		JobID:       job.ID,
+		DrawID:      job.DrawID,
+		Status:      string(job.Status),
+		Progress:    job.Progress,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+	}
+
+	if job.Error != "" {
+		response.Error = &job.Error
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ResumeOptimization restarts a failed or cancelled job from its last checkpoint
+// POST /api/v1/optimize/jobs/:jobId/resume
+func (h *OptimizationHandler) ResumeOptimization(c *gin.Context) {
+	jobID := c.Param("jobId")
+
+	err := h.optimizerService.ResumeOptimization(jobID)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		optimizationVersionConflict(c, "Failed to resume optimization", map[string]string{
+			"job_id": jobID,
+			"error":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, optimizer.ErrNoCheckpoint), errors.Is(err, optimizer.ErrJobNotResumable),
+			errors.Is(err, optimizer.ErrDrawLocked):
+			status = http.StatusConflict
+		case errors.Is(err, storage.ErrNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, types.ErrorResponse{
+			Error: "Failed to resume optimization",
+			Details: map[string]string{
+				"job_id": jobID,
+				"error":  err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.StartOptimizationResponse{
+		JobID:  jobID,
+		Status: "resumed",
+	})
+}
+
+// optimizationVersionConflict responds that the draw a job starts from or
+// applies to was changed by another update
+func optimizationVersionConflict(c *gin.Context, message string, details map[string]string) {
+	c.JSON(http.StatusConflict, types.ErrorResponse{
+		Error:   message,
+		Code:    "VERSION_CONFLICT",
+		Details: details,
+	})
+}
+
+// TuneWeights runs short optimizations of a draw across a sample of
+// soft-constraint weights and reports the Pareto front of per-constraint scores
+// POST /api/v1/draws/:id/tune-weights
+func (h *OptimizationHandler) TuneWeights(c *gin.Context) {
+	drawID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Invalid draw ID",
+			Details: map[string]string{
+				"draw_id": "must be a valid integer",
+			},
+		})
+		return
+	}
+
+	var request types.TuneWeightsRequest
+	if err := middleware.BindAndValidate(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.optimizerService.TuneWeights(context.Background(), drawID, optimizer.WeightTuningConfig{
+		Strategy:   request.Strategy,
+		Samples:    request.Samples,
+		Levels:     request.Levels,
+		Iterations: request.Iterations,
+		Seed:       request.Seed,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, optimizer.ErrInvalidTuningConfig),
+			errors.Is(err, optimizer.ErrNoSoftConstraints),
+			errors.Is(err, optimizer.ErrDrawNotGenerated):
+			status = http.StatusBadRequest
+		case errors.Is(err, storage.ErrNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, types.ErrorResponse{
+			Error: "Failed to tune constraint weights",
+			Details: map[string]string{
+				"error": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // RegisterRoutes registers optimization routes with the Gin router
 func (h *OptimizationHandler) RegisterRoutes(router *gin.RouterGroup) {
 	// Optimization job management - separate draw and job routes
 	router.POST("/optimize/draws/:drawId/start", h.StartOptimization)
 	router.GET("/optimize/jobs/:jobId/status", h.GetOptimizationStatus)
+	router.GET("/optimize/jobs/:jobId/history", h.GetOptimizationHistory)
 	router.POST("/optimize/jobs/:jobId/cancel", h.CancelOptimization)
 	router.GET("/optimize/jobs/:jobId/result", h.GetOptimizationResult)
+	router.GET("/optimize/jobs/:jobId/diff", h.GetOptimizationDiff)
 	router.POST("/optimize/jobs/:jobId/apply", h.ApplyOptimizationResult)
+	router.POST("/optimize/jobs/:jobId/restore", h.RestoreOptimizationJob)
+	router.POST("/optimize/jobs/:jobId/resume", h.ResumeOptimization)
 
 	// Draw validation and scoring - use optimize prefix to avoid conflicts
 	router.GET("/optimize/draws/:drawId/validate-constraints", h.ValidateDrawConstraints)
@@ -382,4 +1029,14 @@ func (h *OptimizationHandler) RegisterRoutes(router *gin.RouterGroup) {
 	// Configuration
 	router.GET("/optimize/config", h.GetOptimizationConfig)
 	router.PUT("/optimize/config", h.SetOptimizationConfig)
+
+	// Job retention and archives
+	router.GET("/optimize/retention", h.GetRetentionPolicy)
+	router.PUT("/optimize/retention", h.SetRetentionPolicy)
+	router.POST("/optimize/retention/apply", h.ApplyRetentionPolicy)
+	router.GET("/optimize/archives", h.ListArchivedJobs)
+
+	// Solution library
+	router.GET("/optimize/solutions", h.ListSolutions)
+	router.DELETE("/optimize/solutions/:id", h.DeleteSolution)
 }
\ No newline at end of file
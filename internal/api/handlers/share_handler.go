@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/share"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// ShareHandler mints share links and serves the read-only fixture behind them
+type ShareHandler struct {
+	shareService *share.Service
+}
+
+// NewShareHandler creates a new share handler
+func NewShareHandler(shareService *share.Service) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+// CreateShareLink mints an expiring link to the draw's current fixture
+// POST /api/v1/draws/:id/share-links
+func (h *ShareHandler) CreateShareLink(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var req types.CreateShareLinkRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	ttl := time.Duration(req.TTLHours) * time.Hour
+	link, err := h.shareService.CreateLink(context.Background(), id, ttl, req.Label, req.CreatedBy)
+	if err != nil {
+		h.handleShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.ShareLinkToResponse(link))
+}
+
+// GetShareLinks lists the share links minted for a draw
+// GET /api/v1/draws/:id/share-links
+func (h *ShareHandler) GetShareLinks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	links, err := h.shareService.ListLinks(context.Background(), id)
+	if err != nil {
+		h.handleShareError(c, err)
+		return
+	}
+
+	responses := make([]types.ShareLinkResponse, len(links))
+	for i, link := range links {
+		responses[i] = types.ShareLinkToResponse(link)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// RevokeShareLink stops a share link from working
+// DELETE /api/v1/draws/:id/share-links/:linkId
+func (h *ShareHandler) RevokeShareLink(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+	linkID, err := strconv.Atoi(c.Param("linkId"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid share link ID")
+		return
+	}
+
+	if err := h.shareService.RevokeLink(context.Background(), id, linkID); err != nil {
+		h.handleShareError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ViewSharedDraw renders the fixture behind a share token. Browsers get a
+// read-only HTML page; clients asking for JSON get the snapshot itself.
+// GET /share/:token
+func (h *ShareHandler) ViewSharedDraw(c *gin.Context) {
+	// The token is the credential, so keep it out of caches, referrers and search engines
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+
+	wantsJSON := c.Query("format") == "json" || strings.Contains(c.GetHeader("Accept"), "application/json")
+
+	shared, err := h.shareService.Resolve(context.Background(), c.Param("token"))
+	if err != nil {
+		if wantsJSON {
+			h.handleShareError(c, err)
+			return
+		}
+		status, message := shareErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("Error resolving share link: %v", err)
+		}
+		h.renderSharedPage(c, status, sharePageData{Error: message})
+		return
+	}
+
+	if wantsJSON {
+		c.JSON(http.StatusOK, shared)
+		return
+	}
+
+	h.renderSharedPage(c, http.StatusOK, sharePageData{Draw: shared, Rounds: shared.ByRound()})
+}
+
+// handleShareError maps share link errors onto JSON responses
+func (h *ShareHandler) handleShareError(c *gin.Context, err error) {
+	status, message := shareErrorStatus(err)
+	switch status {
+	case http.StatusBadRequest:
+		middleware.BadRequest(c, message)
+	case http.StatusConflict:
+		middleware.Conflict(c, message)
+	case http.StatusNotFound:
+		middleware.NotFound(c, message)
+	case http.StatusGone:
+		middleware.Gone(c, message)
+	default:
+		log.Printf("Error processing share link: %v", err)
+		middleware.InternalError(c, message)
+	}
+}
+
+// shareErrorStatus returns the HTTP status and client-safe message for a share error
+func shareErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, share.ErrInvalidTTL):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, share.ErrDrawNotReady):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, share.ErrLinkExpired), errors.Is(err, share.ErrLinkRevoked):
+		return http.StatusGone, err.Error()
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound, err.Error()
+	default:
+		return http.StatusInternalServerError, "Failed to process share link"
+	}
+}
+
+type sharePageData struct {
+	Draw   *share.SharedDraw
+	Rounds []share.SnapshotRound
+	Error  string
+}
+
+func (h *ShareHandler) renderSharedPage(c *gin.Context, status int, data sharePageData) {
+	var buf bytes.Buffer
+	if err := sharedDrawTemplate.Execute(&buf, data); err != nil {
+		log.Printf("Error rendering shared draw: %v", err)
+		middleware.InternalError(c, "Failed to render shared draw")
+		return
+	}
+	// Override the API-wide JSON content type
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+var sharedDrawTemplate = template.Must(template.New("shared_draw").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>{{if .Draw}}{{.Draw.Name}} {{.Draw.SeasonYear}}{{else}}Shared draw{{end}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 56rem; padding: 0 1rem; color: #1a1a1a; }
+.meta { color: #555; }
+.notice { background: #fff4d6; border: 1px solid #e6c25c; padding: 0.5rem 0.75rem; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5rem; }
+th, td { text-align: left; padding: 0.35rem 0.5rem; border-bottom: 1px solid #ddd; }
+.prime { font-weight: 600; }
+</style>
+</head>
+<body>
+{{if .Error}}
+<h1>Shared draw unavailable</h1>
+<p>{{.Error}}</p>
+{{else}}
+<h1>{{.Draw.Name}} &ndash; {{.Draw.SeasonYear}} season</h1>
+{{if .Draw.Label}}<p>{{.Draw.Label}}</p>{{end}}
+<p class="meta">Version {{.Draw.Version}}, shared {{.Draw.SnapshotAt.Format "2 Jan 2006 15:04"}}. This read-only link expires {{.Draw.ExpiresAt.Format "2 Jan 2006 15:04"}}.</p>
+{{if .Draw.Outdated}}<p class="notice">The draw has changed since this link was shared. You are viewing the version that was circulated.</p>{{end}}
+{{range .Rounds}}
+<h2>Round {{.Round}}</h2>
+<table>
+<thead><tr><th>Date</th><th>Kickoff</th><th>Match</th><th>Venue</th></tr></thead>
+<tbody>
+{{range .Matches}}<tr{{if .IsPrimeTime}} class="prime"{{end}}>
+<td>{{.Date}}</td><td>{{.Time}}</td>
+<td>{{if .IsBye}}Bye{{else if not .AwayTeam}}{{.HomeTeam}} (bye){{else}}{{.HomeTeam}} v {{.AwayTeam}}{{end}}</td>
+<td>{{.Venue}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
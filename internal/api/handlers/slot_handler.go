@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/api/websocket"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/slots"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// SlotHandler handles kickoff slot assignment for approved matchup structures
+type SlotHandler struct {
+	slotService *slots.Service
+	wsHub       *websocket.Hub
+}
+
+// NewSlotHandler creates a new slot handler
+func NewSlotHandler(slotService *slots.Service, wsHub *websocket.Hub) *SlotHandler {
+	return &SlotHandler{
+		slotService: slotService,
+		wsHub:       wsHub,
+	}
+}
+
+// AssignSlots gives every match a kickoff slot from its round's inventory
+// without changing matchups or rounds
+// POST /api/v1/draws/:id/assign-slots
+func (h *SlotHandler) AssignSlots(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var req types.AssignSlotsRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	inventory, err := parseSlotInventory(req.Rounds)
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	quotas, err := parseBroadcasterQuotas(req.BroadcasterQuotas)
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.slotService.AssignSlots(context.Background(), id, inventory, quotas, req.DryRun)
+	h.respondWithAssignments(c, id, req.DryRun, result, err)
+}
+
+// ScheduleTimeslots dates every round from weekly timeslot templates and
+// assigns each match a kickoff, marking prime time automatically
+// POST /api/v1/draws/:id/schedule-timeslots
+func (h *SlotHandler) ScheduleTimeslots(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var req types.ScheduleTimeslotsRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Without a season start, rounds are dated from the season calendar
+	var seasonStart time.Time
+	if req.SeasonStart != "" {
+		if seasonStart, err = time.Parse("2006-01-02", req.SeasonStart); err != nil {
+			middleware.BadRequest(c, fmt.Sprintf("invalid season_start %q, expected YYYY-MM-DD", req.SeasonStart))
+			return
+		}
+	}
+
+	var template []slots.TemplateSlot
+	if len(req.Template) > 0 {
+		if template, err = parseTimeslotTemplate(req.Template); err != nil {
+			middleware.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	roundTemplates := make(map[int][]slots.TemplateSlot)
+	for _, round := range req.RoundTemplates {
+		if _, exists := roundTemplates[round.Round]; exists {
+			middleware.BadRequest(c, fmt.Sprintf("round %d is listed more than once", round.Round))
+			return
+		}
+		roundTemplate, err := parseTimeslotTemplate(round.Slots)
+		if err != nil {
+			middleware.BadRequest(c, fmt.Sprintf("round %d: %v", round.Round, err))
+			return
+		}
+		roundTemplates[round.Round] = roundTemplate
+	}
+
+	quotas, err := parseBroadcasterQuotas(req.BroadcasterQuotas)
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.slotService.ScheduleTimeslots(context.Background(), id, seasonStart, template, roundTemplates, quotas, req.DryRun)
+	h.respondWithAssignments(c, id, req.DryRun, result, err)
+}
+
+// respondWithAssignments writes the outcome of a slot assignment
+func (h *SlotHandler) respondWithAssignments(c *gin.Context, id int, dryRun bool, result *slots.Result, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, slots.ErrNotEnoughSlots), errors.Is(err, slots.ErrNoSeasonStart):
+			middleware.BadRequest(c, err.Error())
+		case errors.Is(err, slots.ErrDrawNotReady):
+			middleware.Conflict(c, err.Error())
+		case errors.Is(err, storage.ErrNotFound):
+			middleware.NotFound(c, "Draw not found")
+		default:
+			log.Printf("Error assigning slots for draw %d: %v", id, err)
+			middleware.InternalError(c, "Failed to assign kickoff slots")
+		}
+		return
+	}
+
+	response := types.AssignSlotsResponse{
+		DrawID:          id,
+		DryRun:          dryRun,
+		ScoreBefore:     result.ScoreBefore,
+		ScoreAfter:      result.ScoreAfter,
+		HardViolations:  result.HardViolations,
+		Assignments:     make([]types.SlotAssignmentResponse, len(result.Assignments)),
+		QuotaShortfalls: result.Shortfalls,
+		Rounds:          result.Profiles,
+	}
+	for i, assignment := range result.Assignments {
+		response.Assignments[i] = types.SlotAssignmentResponse{
+			MatchID:     assignment.Match.ID,
+			Round:       assignment.Match.Round,
+			Date:        assignment.Slot.Date.Format("2006-01-02"),
+			IsPrimeTime: assignment.Slot.PrimeTime,
+			Broadcaster: assignment.Slot.Broadcaster,
+		}
+		if assignment.Slot.Time != nil {
+			response.Assignments[i].Time = assignment.Slot.Time.Format("15:04")
+		}
+	}
+
+	if !dryRun && h.wsHub != nil {
+		h.wsHub.BroadcastMessage(websocket.DrawUpdated, websocket.DrawEventData{
+			Draw:      result.Draw,
+			Timestamp: time.Now(),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseBroadcasterQuotas converts the requested broadcaster quotas for the assigner
+func parseBroadcasterQuotas(requested []types.BroadcasterQuotaRequest) ([]slots.BroadcasterQuota, error) {
+	quotas := make([]slots.BroadcasterQuota, len(requested))
+	for i, quota := range requested {
+		if quota.MaxPerTeam > 0 && quota.MaxPerTeam < quota.MinPerTeam {
+			return nil, fmt.Errorf("broadcaster quota %q has max_per_team below min_per_team", quota.Broadcaster)
+		}
+		quotas[i] = slots.BroadcasterQuota{
+			Broadcaster: quota.Broadcaster,
+			MinPerTeam:  quota.MinPerTeam,
+			MaxPerTeam:  quota.MaxPerTeam,
+		}
+	}
+	return quotas, nil
+}
+
+// parseTimeslotTemplate converts requested weekly timeslots into a template
+func parseTimeslotTemplate(requested []types.TimeslotTemplateRequest) ([]slots.TemplateSlot, error) {
+	template := make([]slots.TemplateSlot, len(requested))
+	for i, slot := range requested {
+		weekday, err := constraints.ParseWeekday(slot.Day)
+		if err != nil {
+			return nil, err
+		}
+		kickoff, err := time.Parse("15:04", slot.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q, expected HH:MM", slot.Time)
+		}
+		template[i] = slots.TemplateSlot{
+			Weekday:     weekday,
+			Kickoff:     kickoff,
+			Broadcaster: slot.Broadcaster,
+			PrimeTime:   slot.PrimeTime,
+		}
+	}
+	return template, nil
+}
+
+// parseSlotInventory converts the requested slots into the assigner's per-round inventory
+func parseSlotInventory(rounds []types.RoundSlotsRequest) (map[int][]slots.Slot, error) {
+	inventory := make(map[int][]slots.Slot)
+	for _, round := range rounds {
+		if _, exists := inventory[round.Round]; exists {
+			return nil, fmt.Errorf("round %d is listed more than once", round.Round)
+		}
+		for _, requested := range round.Slots {
+			date, err := time.Parse("2006-01-02", requested.Date)
+			if err != nil {
+				return nil, fmt.Errorf("round %d: invalid date %q, expected YYYY-MM-DD", round.Round, requested.Date)
+			}
+			slot := slots.Slot{
+				Date:          date,
+				Broadcaster:   requested.Broadcaster,
+				AutoPrimeTime: requested.PrimeTime == nil,
+			}
+			if requested.PrimeTime != nil {
+				slot.PrimeTime = *requested.PrimeTime
+			}
+			if requested.Time != "" {
+				kickoff, err := time.Parse("15:04", requested.Time)
+				if err != nil {
+					return nil, fmt.Errorf("round %d: invalid time %q, expected HH:MM", round.Round, requested.Time)
+				}
+				slot.Time = &kickoff
+			}
+			inventory[round.Round] = append(inventory[round.Round], slot)
+		}
+	}
+	return inventory, nil
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -14,12 +17,14 @@ import (
 )
 
 type TeamHandler struct {
-	teamRepo storage.TeamRepository
+	teamRepo        storage.TeamRepository
+	competitionRepo storage.CompetitionRepository
 }
 
-func NewTeamHandler(teamRepo storage.TeamRepository) *TeamHandler {
+func NewTeamHandler(teamRepo storage.TeamRepository, competitionRepo storage.CompetitionRepository) *TeamHandler {
 	return &TeamHandler{
-		teamRepo: teamRepo,
+		teamRepo:        teamRepo,
+		competitionRepo: competitionRepo,
 	}
 }
 
@@ -30,15 +35,13 @@ func (h *TeamHandler) GetTeams(c *gin.Context) {
 		return
 	}
 
-	// Set defaults
-	if params.Page == 0 {
-		params.Page = 1
-	}
-	if params.PerPage == 0 {
-		params.PerPage = 20
+	opts := listOptions(&params)
+	teams, err := h.teamRepo.List(context.Background(), opts)
+	if err != nil {
+		middleware.InternalError(c, "Failed to retrieve teams")
+		return
 	}
-
-	teams, err := h.teamRepo.List(context.Background())
+	total, err := h.teamRepo.Count(context.Background(), opts)
 	if err != nil {
 		middleware.InternalError(c, "Failed to retrieve teams")
 		return
@@ -50,30 +53,7 @@ func (h *TeamHandler) GetTeams(c *gin.Context) {
 		teamResponses[i] = types.TeamToResponse(team, nil)
 	}
 
-	// Simple pagination (in production, you'd do this in the database)
-	total := len(teamResponses)
-	start := (params.Page - 1) * params.PerPage
-	end := start + params.PerPage
-	
-	if start >= total {
-		teamResponses = []types.TeamResponse{}
-	} else if end > total {
-		teamResponses = teamResponses[start:]
-	} else {
-		teamResponses = teamResponses[start:end]
-	}
-
-	totalPages := (total + params.PerPage - 1) / params.PerPage
-
-	response := types.PaginatedResponse{
-		Data:       teamResponses,
-		Total:      total,
-		Page:       params.Page,
-		PerPage:    params.PerPage,
-		TotalPages: totalPages,
-	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, paginatedResponse(teamResponses, total, params))
 }
 
 func (h *TeamHandler) GetTeam(c *gin.Context) {
@@ -86,7 +66,7 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 
 	team, err := h.teamRepo.Get(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Team not found")
 			return
 		}
@@ -106,18 +86,33 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	}
 
 	team := &models.Team{
-		Name:      req.Name,
-		ShortName: req.ShortName,
-		City:      req.City,
-		VenueID:   req.VenueID,
-		Latitude:  req.Latitude,
-		Longitude: req.Longitude,
+		Name:          req.Name,
+		ShortName:     req.ShortName,
+		City:          req.City,
+		VenueID:       req.VenueID,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		HomeVenues:    types.HomeVenuesFromRequest(req.HomeVenues),
+		CompetitionID: req.CompetitionID,
+	}
+	if err := models.ValidateHomeVenues(team.HomeVenues); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+	if !competitionExists(c, h.competitionRepo, team.CompetitionID) {
+		return
 	}
 
 	if err := h.teamRepo.Create(context.Background(), team); err != nil {
 		middleware.InternalError(c, "Failed to create team")
 		return
 	}
+	if len(team.HomeVenues) > 0 {
+		if err := h.teamRepo.SetHomeVenues(context.Background(), team.ID, team.HomeVenues); err != nil {
+			middleware.InternalError(c, "Failed to set team home venues")
+			return
+		}
+	}
 
 	response := types.TeamToResponse(team, nil)
 	c.JSON(http.StatusCreated, response)
@@ -139,7 +134,7 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 
 	team, err := h.teamRepo.Get(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Team not found")
 			return
 		}
@@ -166,11 +161,30 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 	if req.Longitude != nil {
 		team.Longitude = *req.Longitude
 	}
+	if req.HomeVenues != nil {
+		team.HomeVenues = types.HomeVenuesFromRequest(req.HomeVenues)
+		if err := models.ValidateHomeVenues(team.HomeVenues); err != nil {
+			middleware.BadRequest(c, err.Error())
+			return
+		}
+	}
+	if req.CompetitionID != nil {
+		if !competitionExists(c, h.competitionRepo, req.CompetitionID) {
+			return
+		}
+		team.CompetitionID = req.CompetitionID
+	}
 
 	if err := h.teamRepo.Update(context.Background(), team); err != nil {
 		middleware.InternalError(c, "Failed to update team")
 		return
 	}
+	if req.HomeVenues != nil {
+		if err := h.teamRepo.SetHomeVenues(context.Background(), team.ID, team.HomeVenues); err != nil {
+			middleware.InternalError(c, "Failed to set team home venues")
+			return
+		}
+	}
 
 	response := types.TeamToResponse(team, nil)
 	c.JSON(http.StatusOK, response)
@@ -185,7 +199,7 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 	}
 
 	if err := h.teamRepo.Delete(context.Background(), id); err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Team not found")
 			return
 		}
@@ -197,4 +211,128 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 		Success: true,
 		Message: "Team deleted successfully",
 	})
-}
\ No newline at end of file
+}
+
+// GetTeamUnavailability lists the dates a team can't play
+// GET /api/v1/teams/:id/unavailability
+func (h *TeamHandler) GetTeamUnavailability(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid team ID")
+		return
+	}
+
+	if !h.teamExists(c, id) {
+		return
+	}
+
+	unavailability, err := h.teamRepo.ListUnavailability(context.Background(), id)
+	if err != nil {
+		middleware.InternalError(c, "Failed to retrieve team unavailability")
+		return
+	}
+
+	response := types.TeamUnavailabilityListResponse{
+		TeamID:         id,
+		Unavailability: make([]types.TeamUnavailabilityResponse, len(unavailability)),
+	}
+	for i, entry := range unavailability {
+		response.Unavailability[i] = types.TeamUnavailabilityToResponse(entry)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateTeamUnavailability records a date a team can't play. Every draw's
+// validation, generation and optimization keeps the team off that date.
+// POST /api/v1/teams/:id/unavailability
+func (h *TeamHandler) CreateTeamUnavailability(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid team ID")
+		return
+	}
+
+	var req types.CreateTeamUnavailabilityRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		middleware.BadRequest(c, fmt.Sprintf("Invalid date %q, expected YYYY-MM-DD", req.Date))
+		return
+	}
+
+	if !h.teamExists(c, id) {
+		return
+	}
+
+	existing, err := h.teamRepo.ListUnavailability(context.Background(), id)
+	if err != nil {
+		middleware.InternalError(c, "Failed to retrieve team unavailability")
+		return
+	}
+	for _, entry := range existing {
+		if entry.Date.Format("2006-01-02") == req.Date {
+			middleware.Conflict(c, fmt.Sprintf("Team is already unavailable on %s", req.Date))
+			return
+		}
+	}
+
+	unavailability := &models.TeamUnavailability{
+		TeamID: id,
+		Date:   date,
+		Reason: req.Reason,
+	}
+	if err := h.teamRepo.AddUnavailability(context.Background(), unavailability); err != nil {
+		middleware.InternalError(c, "Failed to add team unavailability")
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.TeamUnavailabilityToResponse(*unavailability))
+}
+
+// DeleteTeamUnavailability removes one of the dates a team can't play
+// DELETE /api/v1/teams/:id/unavailability/:unavailabilityId
+func (h *TeamHandler) DeleteTeamUnavailability(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid team ID")
+		return
+	}
+	unavailabilityID, err := strconv.Atoi(c.Param("unavailabilityId"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid unavailability ID")
+		return
+	}
+
+	if err := h.teamRepo.DeleteUnavailability(context.Background(), id, unavailabilityID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Team unavailability not found")
+			return
+		}
+		middleware.InternalError(c, "Failed to delete team unavailability")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SuccessResponse{
+		Success: true,
+		Message: "Team unavailability deleted successfully",
+	})
+}
+
+// teamExists reports whether the team exists, responding with an error when
+// it doesn't or can't be looked up
+func (h *TeamHandler) teamExists(c *gin.Context, id int) bool {
+	if _, err := h.teamRepo.Get(context.Background(), id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Team not found")
+			return false
+		}
+		middleware.InternalError(c, "Failed to retrieve team")
+		return false
+	}
+	return true
+}
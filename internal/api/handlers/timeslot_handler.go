@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/slots"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// TimeslotHandler manages the catalogue of weekly kickoff windows rounds are
+// scheduled in
+type TimeslotHandler struct {
+	timeslotRepo    storage.TimeslotRepository
+	competitionRepo storage.CompetitionRepository
+}
+
+// NewTimeslotHandler creates a new timeslot handler
+func NewTimeslotHandler(timeslotRepo storage.TimeslotRepository, competitionRepo storage.CompetitionRepository) *TimeslotHandler {
+	return &TimeslotHandler{
+		timeslotRepo:    timeslotRepo,
+		competitionRepo: competitionRepo,
+	}
+}
+
+// GetTimeslots lists the catalogue in week order from Sunday
+// GET /api/v1/timeslots
+func (h *TimeslotHandler) GetTimeslots(c *gin.Context) {
+	timeslots, err := h.timeslotRepo.List(context.Background())
+	if err != nil {
+		log.Printf("Error listing timeslots: %v", err)
+		middleware.InternalError(c, "Failed to retrieve timeslots")
+		return
+	}
+
+	responses := make([]types.TimeslotResponse, len(timeslots))
+	for i, timeslot := range timeslots {
+		responses[i] = types.TimeslotToResponse(timeslot)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetTimeslot returns one timeslot
+// GET /api/v1/timeslots/:id
+func (h *TimeslotHandler) GetTimeslot(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid timeslot ID")
+		return
+	}
+
+	timeslot, err := h.timeslotRepo.Get(context.Background(), id)
+	if err != nil {
+		h.handleTimeslotError(c, err, "Failed to retrieve timeslot")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.TimeslotToResponse(timeslot))
+}
+
+// CreateTimeslot adds a slot to the catalogue. Unless flagged otherwise it is
+// prime time on Thursday to Saturday nights from 7pm.
+// POST /api/v1/timeslots
+func (h *TimeslotHandler) CreateTimeslot(c *gin.Context) {
+	var req types.CreateTimeslotRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	timeslot := &models.Timeslot{
+		Kickoff:       req.Kickoff,
+		Channel:       req.Channel,
+		CompetitionID: req.CompetitionID,
+	}
+	if !setTimeslotDay(c, timeslot, req.Day) {
+		return
+	}
+	if err := timeslot.Validate(); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+	if req.PrimeTime != nil {
+		timeslot.IsPrimeTime = *req.PrimeTime
+	} else {
+		kickoff, _ := timeslot.KickoffTime()
+		timeslot.IsPrimeTime = slots.IsPrimeTimeKickoff(timeslot.DayOfWeek, kickoff)
+	}
+	if !competitionExists(c, h.competitionRepo, timeslot.CompetitionID) {
+		return
+	}
+
+	if err := h.timeslotRepo.Create(context.Background(), timeslot); err != nil {
+		h.handleTimeslotError(c, err, "Failed to create timeslot")
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.TimeslotToResponse(timeslot))
+}
+
+// UpdateTimeslot changes the fields provided
+// PUT /api/v1/timeslots/:id
+func (h *TimeslotHandler) UpdateTimeslot(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid timeslot ID")
+		return
+	}
+
+	var req types.UpdateTimeslotRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	timeslot, err := h.timeslotRepo.Get(context.Background(), id)
+	if err != nil {
+		h.handleTimeslotError(c, err, "Failed to retrieve timeslot")
+		return
+	}
+
+	if req.Day != nil && !setTimeslotDay(c, timeslot, *req.Day) {
+		return
+	}
+	if req.Kickoff != nil {
+		timeslot.Kickoff = *req.Kickoff
+	}
+	if req.Channel != nil {
+		timeslot.Channel = *req.Channel
+	}
+	if req.PrimeTime != nil {
+		timeslot.IsPrimeTime = *req.PrimeTime
+	}
+	if req.CompetitionID != nil {
+		if !competitionExists(c, h.competitionRepo, req.CompetitionID) {
+			return
+		}
+		timeslot.CompetitionID = req.CompetitionID
+	}
+	if err := timeslot.Validate(); err != nil {
+		middleware.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.timeslotRepo.Update(context.Background(), timeslot); err != nil {
+		h.handleTimeslotError(c, err, "Failed to update timeslot")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.TimeslotToResponse(timeslot))
+}
+
+// DeleteTimeslot removes a slot from the catalogue. Matches already
+// scheduled in it keep their kickoffs.
+// DELETE /api/v1/timeslots/:id
+func (h *TimeslotHandler) DeleteTimeslot(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid timeslot ID")
+		return
+	}
+
+	if err := h.timeslotRepo.Delete(context.Background(), id); err != nil {
+		h.handleTimeslotError(c, err, "Failed to delete timeslot")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SuccessResponse{
+		Success: true,
+		Message: "Timeslot deleted successfully",
+	})
+}
+
+// handleTimeslotError maps timeslot storage errors to responses
+func (h *TimeslotHandler) handleTimeslotError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		middleware.NotFound(c, "Timeslot not found")
+	case errors.Is(err, storage.ErrConflict):
+		middleware.Conflict(c, "The competition already has a timeslot at that day and kickoff")
+	default:
+		log.Printf("%s: %v", message, err)
+		middleware.InternalError(c, message)
+	}
+}
+
+// setTimeslotDay sets a timeslot's day from its name, responding with 400
+// when the name isn't a day
+func setTimeslotDay(c *gin.Context, timeslot *models.Timeslot, day string) bool {
+	weekday, err := constraints.ParseWeekday(day)
+	if err != nil {
+		middleware.BadRequest(c, err.Error())
+		return false
+	}
+	timeslot.DayOfWeek = weekday
+	return true
+}
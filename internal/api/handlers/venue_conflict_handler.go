@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/crossdraw"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// VenueConflictHandler reports clashes between draws that share venues
+type VenueConflictHandler struct {
+	crossDrawService *crossdraw.Service
+}
+
+// NewVenueConflictHandler creates a new venue conflict handler
+func NewVenueConflictHandler(crossDrawService *crossdraw.Service) *VenueConflictHandler {
+	return &VenueConflictHandler{
+		crossDrawService: crossDrawService,
+	}
+}
+
+// GetVenueConflicts lists a draw's matches that kick off too close to other
+// competitions' completed draws at the same venue
+// GET /api/v1/draws/:id/venue-conflicts
+func (h *VenueConflictHandler) GetVenueConflicts(c *gin.Context) {
+	drawID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid draw ID")
+		return
+	}
+
+	var query types.VenueConflictsQuery
+	if err := middleware.BindQueryAndValidate(c, &query); err != nil {
+		c.Error(err)
+		return
+	}
+	minGap := constraints.DefaultSharedVenueGap
+	if query.MinGapMinutes != nil {
+		minGap = time.Duration(*query.MinGapMinutes) * time.Minute
+	}
+
+	report, err := h.crossDrawService.VenueConflicts(context.Background(), drawID, minGap)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			middleware.NotFound(c, "Draw not found")
+			return
+		}
+		log.Printf("Error checking venue conflicts for draw %d: %v", drawID, err)
+		middleware.InternalError(c, "Failed to check venue conflicts")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -30,15 +31,13 @@ func (h *VenueHandler) GetVenues(c *gin.Context) {
 		return
 	}
 
-	// Set defaults
-	if params.Page == 0 {
-		params.Page = 1
-	}
-	if params.PerPage == 0 {
-		params.PerPage = 20
+	opts := listOptions(&params)
+	venues, err := h.venueRepo.List(context.Background(), opts)
+	if err != nil {
+		middleware.InternalError(c, "Failed to retrieve venues")
+		return
 	}
-
-	venues, err := h.venueRepo.List(context.Background())
+	total, err := h.venueRepo.Count(context.Background(), opts)
 	if err != nil {
 		middleware.InternalError(c, "Failed to retrieve venues")
 		return
@@ -50,30 +49,7 @@ func (h *VenueHandler) GetVenues(c *gin.Context) {
 		venueResponses[i] = types.VenueToResponse(venue)
 	}
 
-	// Simple pagination (in production, you'd do this in the database)
-	total := len(venueResponses)
-	start := (params.Page - 1) * params.PerPage
-	end := start + params.PerPage
-	
-	if start >= total {
-		venueResponses = []types.VenueResponse{}
-	} else if end > total {
-		venueResponses = venueResponses[start:]
-	} else {
-		venueResponses = venueResponses[start:end]
-	}
-
-	totalPages := (total + params.PerPage - 1) / params.PerPage
-
-	response := types.PaginatedResponse{
-		Data:       venueResponses,
-		Total:      total,
-		Page:       params.Page,
-		PerPage:    params.PerPage,
-		TotalPages: totalPages,
-	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, paginatedResponse(venueResponses, total, params))
 }
 
 func (h *VenueHandler) GetVenue(c *gin.Context) {
@@ -86,7 +62,7 @@ func (h *VenueHandler) GetVenue(c *gin.Context) {
 
 	venue, err := h.venueRepo.Get(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Venue not found")
 			return
 		}
@@ -138,7 +114,7 @@ func (h *VenueHandler) UpdateVenue(c *gin.Context) {
 
 	venue, err := h.venueRepo.Get(context.Background(), id)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Venue not found")
 			return
 		}
@@ -181,7 +157,7 @@ func (h *VenueHandler) DeleteVenue(c *gin.Context) {
 	}
 
 	if err := h.venueRepo.Delete(context.Background(), id); err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			middleware.NotFound(c, "Venue not found")
 			return
 		}
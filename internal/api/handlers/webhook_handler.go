@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/webhook"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// WebhookHandler registers the URLs notified of job and draw lifecycle events
+type WebhookHandler struct {
+	webhookService *webhook.Service
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *webhook.Service) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// GetWebhooks lists every registered webhook
+// GET /api/v1/webhooks
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.List(context.Background())
+	if err != nil {
+		log.Printf("Error listing webhooks: %v", err)
+		middleware.InternalError(c, "Failed to retrieve webhooks")
+		return
+	}
+
+	responses := make([]types.WebhookResponse, len(webhooks))
+	for i, hook := range webhooks {
+		responses[i] = types.WebhookToResponse(hook)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetWebhook returns one webhook and the outcome of its latest delivery
+// GET /api/v1/webhooks/:id
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	hook, err := h.webhookService.Get(context.Background(), id)
+	if err != nil {
+		h.handleWebhookError(c, err, "Failed to retrieve webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.WebhookToResponse(hook))
+}
+
+// CreateWebhook registers a URL for the given events. The response includes
+// the signing secret, which isn't shown again.
+// POST /api/v1/webhooks
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req types.CreateWebhookRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	hook := &models.Webhook{
+		URL:         req.URL,
+		Events:      req.Events,
+		Secret:      req.Secret,
+		Description: req.Description,
+		IsActive:    true,
+	}
+	if req.IsActive != nil {
+		hook.IsActive = *req.IsActive
+	}
+
+	if err := h.webhookService.Register(context.Background(), hook); err != nil {
+		h.handleWebhookError(c, err, "Failed to create webhook")
+		return
+	}
+
+	response := types.WebhookToResponse(hook)
+	response.Secret = hook.Secret
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateWebhook changes a webhook's URL, events, secret or whether it is active
+// PUT /api/v1/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	var req types.UpdateWebhookRequest
+	if err := middleware.BindAndValidate(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	hook, err := h.webhookService.Get(context.Background(), id)
+	if err != nil {
+		h.handleWebhookError(c, err, "Failed to retrieve webhook")
+		return
+	}
+
+	if req.URL != nil {
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		hook.Events = req.Events
+	}
+	if req.Secret != nil {
+		hook.Secret = *req.Secret
+	}
+	if req.Description != nil {
+		hook.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		hook.IsActive = *req.IsActive
+	}
+
+	if err := h.webhookService.Update(context.Background(), hook); err != nil {
+		h.handleWebhookError(c, err, "Failed to update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.WebhookToResponse(hook))
+}
+
+// DeleteWebhook stops notifying a webhook
+// DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.BadRequest(c, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.webhookService.Delete(context.Background(), id); err != nil {
+		h.handleWebhookError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, types.SuccessResponse{
+		Success: true,
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// handleWebhookError maps webhook service errors to responses
+func (h *WebhookHandler) handleWebhookError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		middleware.NotFound(c, "Webhook not found")
+	case errors.Is(err, webhook.ErrUnknownEvent), strings.HasPrefix(err.Error(), "webhook "):
+		middleware.BadRequest(c, err.Error())
+	default:
+		log.Printf("%s: %v", message, err)
+		middleware.InternalError(c, message)
+	}
+}
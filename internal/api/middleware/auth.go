@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// Role is what an API key is allowed to do. Each role can do everything the
+// roles before it can.
+type Role string
+
+const (
+	// RolePublic marks routes that need no API key
+	RolePublic Role = ""
+	// RoleViewer can read teams, venues, draws and results
+	RoleViewer Role = "viewer"
+	// RoleScheduler can also create, generate and optimize draws
+	RoleScheduler Role = "scheduler"
+	// RoleAdmin can also manage teams, venues and server configuration
+	RoleAdmin Role = "admin"
+)
+
+// roleRanks orders the roles from least to most access
+var roleRanks = map[Role]int{
+	RolePublic:    0,
+	RoleViewer:    1,
+	RoleScheduler: 2,
+	RoleAdmin:     3,
+}
+
+// Allows reports whether the role grants the access a route requires
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+// ParseRole parses a role name
+func ParseRole(name string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(name)))
+	if role == RolePublic || roleRanks[role] == 0 {
+		return "", fmt.Errorf("unknown role %q, expected viewer, scheduler or admin", name)
+	}
+	return role, nil
+}
+
+// AccessTokenParam is the query parameter carrying the API key on WebSocket
+// connections, since browsers can't set headers when opening one
+const AccessTokenParam = "access_token"
+
+// roleContextKey is where the authenticated role is kept on the request
+const roleContextKey = "auth_role"
+
+// APIKeys holds the API keys the server accepts and the role of each. Keys
+// are kept as hashes. With no keys set, authentication is disabled and every
+// request is treated as an admin, so a local server works out of the box.
+type APIKeys struct {
+	mutex sync.RWMutex
+	keys  map[[sha256.Size]byte]Role
+}
+
+// NewAPIKeys creates an empty key set, leaving authentication disabled
+func NewAPIKeys() *APIKeys {
+	return &APIKeys{keys: make(map[[sha256.Size]byte]Role)}
+}
+
+// ParseAPIKeys parses a comma-separated list of role:key pairs, e.g.
+// "admin:s3cret,viewer:readonly"
+func ParseAPIKeys(spec string) (map[string]Role, error) {
+	keys := make(map[string]Role)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, expected role:key", entry)
+		}
+		role, err := ParseRole(name)
+		if err != nil {
+			return nil, err
+		}
+		keys[strings.TrimSpace(key)] = role
+	}
+	return keys, nil
+}
+
+// Set replaces the accepted keys
+func (k *APIKeys) Set(keys map[string]Role) {
+	hashed := make(map[[sha256.Size]byte]Role, len(keys))
+	for key, role := range keys {
+		hashed[sha256.Sum256([]byte(key))] = role
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.keys = hashed
+}
+
+// Enabled reports whether any keys are set
+func (k *APIKeys) Enabled() bool {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	return len(k.keys) > 0
+}
+
+// Lookup returns the role of a key
+func (k *APIKeys) Lookup(key string) (Role, bool) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	role, ok := k.keys[sha256.Sum256([]byte(key))]
+	return role, ok
+}
+
+// Authenticate checks each request's API key against the role its route
+// requires, as given by policy. Keys are read from an "Authorization: Bearer"
+// header, an X-API-Key header, or the access_token query parameter.
+func Authenticate(keys *APIKeys, policy func(method, path string) Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !keys.Enabled() {
+			c.Set(roleContextKey, RoleAdmin)
+			c.Next()
+			return
+		}
+
+		required := policy(c.Request.Method, c.FullPath())
+
+		role := RolePublic
+		if key := requestAPIKey(c); key != "" {
+			var ok bool
+			role, ok = keys.Lookup(key)
+			if !ok {
+				Unauthorized(c, "Invalid API key")
+				return
+			}
+		}
+		c.Set(roleContextKey, role)
+
+		if role.Allows(required) {
+			c.Next()
+			return
+		}
+		if role == RolePublic {
+			Unauthorized(c, "An API key is required")
+			return
+		}
+		Forbidden(c, fmt.Sprintf("This action requires the %s role", required))
+	}
+}
+
+// CurrentRole returns the role the request was authenticated with
+func CurrentRole(c *gin.Context) Role {
+	if role, ok := c.Get(roleContextKey); ok {
+		return role.(Role)
+	}
+	return RolePublic
+}
+
+// requestAPIKey returns the API key a request presents, if any
+func requestAPIKey(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	return c.Query(AccessTokenParam)
+}
+
+func Unauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="nrl-scheduler"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, types.ErrorResponse{
+		Error: message,
+		Code:  "UNAUTHORIZED",
+	})
+}
+
+func Forbidden(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusForbidden, types.ErrorResponse{
+		Error: message,
+		Code:  "FORBIDDEN",
+	})
+}
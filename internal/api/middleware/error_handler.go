@@ -100,4 +100,11 @@ func Conflict(c *gin.Context, message string) {
 		Error: message,
 		Code:  "CONFLICT",
 	})
+}
+
+func Gone(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusGone, types.ErrorResponse{
+		Error: message,
+		Code:  "GONE",
+	})
 }
\ No newline at end of file
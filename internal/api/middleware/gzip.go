@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GzipETagSuffix marks a strong ETag as belonging to the gzipped
+// representation, so it never matches the identity-encoded one
+const GzipETagSuffix = "-gzip"
+
+// Gzip compresses response bodies for clients that send Accept-Encoding: gzip.
+// WebSocket upgrades and empty responses are passed through untouched.
+// Strong ETags on compressed responses, and on 304s to clients that would
+// have got one, get GzipETagSuffix.
+func Gzip(level int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !acceptsGzip(c.Request) || isWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer, level: level}
+		c.Writer = writer
+		c.Header("Vary", "Accept-Encoding")
+
+		defer writer.close()
+		c.Next()
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name := strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0])
+		if strings.EqualFold(name, "gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// StripGzipETag returns the identity ETag of one Gzip suffixed, so handlers
+// can compare an If-None-Match value against the ETag they computed
+func StripGzipETag(etag string) string {
+	if strings.HasSuffix(etag, GzipETagSuffix+`"`) {
+		return strings.TrimSuffix(etag, GzipETagSuffix+`"`) + `"`
+	}
+	return etag
+}
+
+// suffixETag marks a strong ETag as the gzipped representation's. Weak
+// ETags already allow for differences in encoding.
+func suffixETag(header http.Header) {
+	etag := header.Get("ETag")
+	if etag == "" || strings.HasPrefix(etag, "W/") || !strings.HasSuffix(etag, `"`) || strings.HasSuffix(etag, GzipETagSuffix+`"`) {
+		return
+	}
+	header.Set("ETag", strings.TrimSuffix(etag, `"`)+GzipETagSuffix+`"`)
+}
+
+// gzipResponseWriter starts compressing on the first body write so responses
+// without a body (204s, aborted requests) keep their original headers
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	level int
+	gz    *gzip.Writer
+}
+
+func (w *gzipResponseWriter) start() error {
+	if w.gz != nil {
+		return nil
+	}
+
+	header := w.ResponseWriter.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	suffixETag(header)
+
+	gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.level)
+	if err != nil {
+		return err
+	}
+	w.gz = gz
+	return nil
+}
+
+// WriteHeader gives a 304 the ETag the compressed 200 would have had
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if code == http.StatusNotModified {
+		suffixETag(w.ResponseWriter.Header())
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	if err := w.start(); err != nil {
+		return 0, err
+	}
+	return w.gz.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush pushes buffered compressed data to the client so streamed responses
+// arrive incrementally
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NDJSONContentType is the media type for newline-delimited JSON responses
+const NDJSONContentType = "application/x-ndjson"
+
+// ndjsonFlushEvery controls how many records are written between flushes
+const ndjsonFlushEvery = 50
+
+// WantsNDJSON reports whether the client asked for a newline-delimited stream,
+// either with ?format=ndjson or an Accept header naming application/x-ndjson
+func WantsNDJSON(c *gin.Context) bool {
+	if strings.EqualFold(c.Query("format"), "ndjson") {
+		return true
+	}
+	return strings.Contains(c.GetHeader("Accept"), NDJSONContentType)
+}
+
+// StreamNDJSON writes each item produced by next as one JSON line. next returns
+// false once there are no more items. The response is flushed periodically so
+// consumers can process records before the stream is complete.
+func StreamNDJSON(c *gin.Context, next func() (interface{}, bool)) {
+	c.Header("Content-Type", NDJSONContentType)
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	written := 0
+	for {
+		item, ok := next()
+		if !ok {
+			break
+		}
+		if err := encoder.Encode(item); err != nil {
+			// Headers are already sent, so the error can't be reported to the client
+			log.Printf("Error streaming NDJSON response for %s: %v", c.Request.URL.Path, err)
+			return
+		}
+		written++
+		if written%ndjsonFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+	c.Writer.Flush()
+}
+
+// StreamNDJSONSlice streams every element of items as one JSON line
+func StreamNDJSONSlice[T any](c *gin.Context, items []T) {
+	i := 0
+	StreamNDJSON(c, func() (interface{}, bool) {
+		if i >= len(items) {
+			return nil, false
+		}
+		item := items[i]
+		i++
+		return item, true
+	})
+}
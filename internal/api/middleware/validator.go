@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"io"
 	"reflect"
 	"strings"
 
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -16,9 +19,14 @@ func RequestValidator(validate *validator.Validate) gin.HandlerFunc {
 	}
 }
 
-// BindAndValidate binds request JSON and validates it
+// BindAndValidate binds the request body and validates it. Bodies sent as
+// YAML are converted to JSON first, so they bind through the same json tags.
 func BindAndValidate(c *gin.Context, obj interface{}) error {
-	if err := c.ShouldBindJSON(obj); err != nil {
+	if isYAML(c.ContentType()) {
+		if err := bindYAML(c, obj); err != nil {
+			return err
+		}
+	} else if err := c.ShouldBindJSON(obj); err != nil {
 		return err
 	}
 	
@@ -41,6 +49,31 @@ func BindAndValidate(c *gin.Context, obj interface{}) error {
 	return v.Struct(obj)
 }
 
+// isYAML reports whether a content type is one of the YAML media types
+func isYAML(contentType string) bool {
+	switch contentType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	default:
+		return false
+	}
+}
+
+// bindYAML binds a YAML request body
+func bindYAML(c *gin.Context, obj interface{}) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	
+	data, err := constraints.YAMLToJSON(body)
+	if err != nil {
+		return err
+	}
+	
+	return binding.JSON.BindBody(data, obj)
+}
+
 // BindQueryAndValidate binds query parameters and validates them
 func BindQueryAndValidate(c *gin.Context, obj interface{}) error {
 	if err := c.ShouldBindQuery(obj); err != nil {
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/approval"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/compare"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/crossdraw"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/feed"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/optimizer"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/seed"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/share"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/simulate"
+	"github.com/adampetrovic/nrl-scheduler/internal/importer"
+	"github.com/adampetrovic/nrl-scheduler/pkg/types"
+)
+
+// apiEndpoints documents every route the server registers. A test checks
+// the two agree, so a new route must be added here too.
+var apiEndpoints = []types.APIEndpoint{
+	// Bulk imports
+	{Method: "POST", Path: "/api/v1/teams/import", Tag: "Imports", Summary: "Import teams from CSV or a JSON array", Request: []importer.TeamRow{}, RequestTypes: []string{"text/csv"}, Status: http.StatusCreated, Response: importer.Report{}},
+	{Method: "POST", Path: "/api/v1/venues/import", Tag: "Imports", Summary: "Import venues from CSV or a JSON array", Request: []importer.VenueRow{}, RequestTypes: []string{"text/csv"}, Status: http.StatusCreated, Response: importer.Report{}},
+
+	// Competitions
+	{Method: "GET", Path: "/api/v1/competitions", Tag: "Competitions", Summary: "List competitions", Response: []types.CompetitionResponse{}},
+	{Method: "POST", Path: "/api/v1/competitions", Tag: "Competitions", Summary: "Create a competition", Request: types.CreateCompetitionRequest{}, Status: http.StatusCreated, Response: types.CompetitionResponse{}},
+	{Method: "GET", Path: "/api/v1/competitions/:id", Tag: "Competitions", Summary: "Get a competition", Response: types.CompetitionResponse{}},
+	{Method: "PUT", Path: "/api/v1/competitions/:id", Tag: "Competitions", Summary: "Update a competition", Request: types.UpdateCompetitionRequest{}, Response: types.CompetitionResponse{}},
+	{Method: "DELETE", Path: "/api/v1/competitions/:id", Tag: "Competitions", Summary: "Delete a competition", Response: types.SuccessResponse{}},
+
+	// Timeslots
+	{Method: "GET", Path: "/api/v1/timeslots", Tag: "Timeslots", Summary: "List the timeslot catalogue", Response: []types.TimeslotResponse{}},
+	{Method: "POST", Path: "/api/v1/timeslots", Tag: "Timeslots", Summary: "Create a timeslot", Request: types.CreateTimeslotRequest{}, Status: http.StatusCreated, Response: types.TimeslotResponse{}},
+	{Method: "GET", Path: "/api/v1/timeslots/:id", Tag: "Timeslots", Summary: "Get a timeslot", Response: types.TimeslotResponse{}},
+	{Method: "PUT", Path: "/api/v1/timeslots/:id", Tag: "Timeslots", Summary: "Update a timeslot", Request: types.UpdateTimeslotRequest{}, Response: types.TimeslotResponse{}},
+	{Method: "DELETE", Path: "/api/v1/timeslots/:id", Tag: "Timeslots", Summary: "Delete a timeslot", Response: types.SuccessResponse{}},
+
+	// Season calendars
+	{Method: "GET", Path: "/api/v1/constraint-templates", Tag: "Constraint templates", Summary: "List constraint templates", Response: []types.ConstraintTemplateResponse{}},
+	{Method: "POST", Path: "/api/v1/constraint-templates", Tag: "Constraint templates", Summary: "Create a constraint template", Request: types.CreateConstraintTemplateRequest{}, Status: http.StatusCreated, Response: types.ConstraintTemplateResponse{}},
+	{Method: "GET", Path: "/api/v1/constraint-templates/:id", Tag: "Constraint templates", Summary: "Get a constraint template", Response: types.ConstraintTemplateResponse{}},
+	{Method: "PUT", Path: "/api/v1/constraint-templates/:id", Tag: "Constraint templates", Summary: "Update a constraint template, versioning a changed configuration", Request: types.UpdateConstraintTemplateRequest{}, Response: types.ConstraintTemplateResponse{}},
+	{Method: "DELETE", Path: "/api/v1/constraint-templates/:id", Tag: "Constraint templates", Summary: "Delete a constraint template", Response: types.SuccessResponse{}},
+	{Method: "GET", Path: "/api/v1/constraint-templates/:id/versions", Tag: "Constraint templates", Summary: "List a constraint template's versions", Response: []types.ConstraintTemplateVersionResponse{}},
+	{Method: "GET", Path: "/api/v1/constraint-templates/:id/versions/:version", Tag: "Constraint templates", Summary: "Get a constraint template version", Response: types.ConstraintTemplateVersionResponse{}},
+	{Method: "POST", Path: "/api/v1/constraint-templates/:id/draws", Tag: "Constraint templates", Summary: "Create a draft draw from a constraint template", Request: types.CreateDrawFromTemplateRequest{}, Status: http.StatusCreated, Response: types.DrawResponse{}},
+	{Method: "GET", Path: "/api/v1/calendars", Tag: "Calendars", Summary: "List season calendars", Response: []types.SeasonCalendarResponse{}},
+	{Method: "POST", Path: "/api/v1/calendars", Tag: "Calendars", Summary: "Create a season calendar", Request: types.CreateSeasonCalendarRequest{}, Status: http.StatusCreated, Response: types.SeasonCalendarResponse{}},
+	{Method: "GET", Path: "/api/v1/calendars/:id", Tag: "Calendars", Summary: "Get a season calendar", Response: types.SeasonCalendarResponse{}},
+	{Method: "PUT", Path: "/api/v1/calendars/:id", Tag: "Calendars", Summary: "Update a season calendar", Request: types.UpdateSeasonCalendarRequest{}, Response: types.SeasonCalendarResponse{}},
+	{Method: "DELETE", Path: "/api/v1/calendars/:id", Tag: "Calendars", Summary: "Delete a season calendar", Response: types.SuccessResponse{}},
+
+	// Teams
+	{Method: "GET", Path: "/api/v1/teams", Tag: "Teams", Summary: "List teams", Query: types.ListQueryParams{}, Response: types.PaginatedResponse{}},
+	{Method: "POST", Path: "/api/v1/teams", Tag: "Teams", Summary: "Create a team", Request: types.CreateTeamRequest{}, Status: http.StatusCreated, Response: types.TeamResponse{}},
+	{Method: "GET", Path: "/api/v1/teams/:id", Tag: "Teams", Summary: "Get a team", Response: types.TeamResponse{}},
+	{Method: "PUT", Path: "/api/v1/teams/:id", Tag: "Teams", Summary: "Update a team", Request: types.UpdateTeamRequest{}, Response: types.TeamResponse{}},
+	{Method: "DELETE", Path: "/api/v1/teams/:id", Tag: "Teams", Summary: "Delete a team", Response: types.SuccessResponse{}},
+	{Method: "GET", Path: "/api/v1/teams/:id/unavailability", Tag: "Teams", Summary: "List a team's unavailable dates", Response: types.TeamUnavailabilityListResponse{}},
+	{Method: "POST", Path: "/api/v1/teams/:id/unavailability", Tag: "Teams", Summary: "Add an unavailable date range", Request: types.CreateTeamUnavailabilityRequest{}, Status: http.StatusCreated, Response: types.TeamUnavailabilityResponse{}},
+	{Method: "DELETE", Path: "/api/v1/teams/:id/unavailability/:unavailabilityId", Tag: "Teams", Summary: "Remove an unavailable date range", Response: types.SuccessResponse{}},
+
+	// Venues
+	{Method: "GET", Path: "/api/v1/venues", Tag: "Venues", Summary: "List venues", Query: types.ListQueryParams{}, Response: types.PaginatedResponse{}},
+	{Method: "POST", Path: "/api/v1/venues", Tag: "Venues", Summary: "Create a venue", Request: types.CreateVenueRequest{}, Status: http.StatusCreated, Response: types.VenueResponse{}},
+	{Method: "GET", Path: "/api/v1/venues/:id", Tag: "Venues", Summary: "Get a venue", Response: types.VenueResponse{}},
+	{Method: "PUT", Path: "/api/v1/venues/:id", Tag: "Venues", Summary: "Update a venue", Request: types.UpdateVenueRequest{}, Response: types.VenueResponse{}},
+	{Method: "DELETE", Path: "/api/v1/venues/:id", Tag: "Venues", Summary: "Delete a venue", Response: types.SuccessResponse{}},
+
+	// Draws
+	{Method: "GET", Path: "/api/v1/draws", Tag: "Draws", Summary: "List draws", Query: types.ListQueryParams{}, Response: types.PaginatedResponse{}},
+	{Method: "POST", Path: "/api/v1/draws", Tag: "Draws", Summary: "Create a draw", Request: types.CreateDrawRequest{}, Status: http.StatusCreated, Response: types.DrawResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id", Tag: "Draws", Summary: "Get a draw", Params: []types.OpenAPIParameter{
+		{Name: "with_matches", Description: "Include the draw's matches", Schema: &types.OpenAPISchema{Type: "boolean"}},
+	}, Response: types.DrawResponse{}},
+	{Method: "PUT", Path: "/api/v1/draws/:id", Tag: "Draws", Summary: "Update a draw", Request: types.UpdateDrawRequest{}, Response: types.DrawResponse{}},
+	{Method: "DELETE", Path: "/api/v1/draws/:id", Tag: "Draws", Summary: "Delete a draw", Response: types.SuccessResponse{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/clone", Tag: "Draws", Summary: "Clone a draw and its matches", Request: types.CloneDrawRequest{}, Status: http.StatusCreated, Response: types.DrawResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/matches", Tag: "Draws", Summary: "List a draw's matches", Description: "Streams one match per line with format=ndjson or Accept: application/x-ndjson.", Params: []types.OpenAPIParameter{
+		{Name: "format", Schema: &types.OpenAPISchema{Type: "string", Enum: []interface{}{"ndjson"}}},
+	}, Response: []types.MatchResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/rounds/:round", Tag: "Draws", Summary: "Get one round's fixtures", Response: types.RoundResponse{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/teams", Tag: "Draws", Summary: "Add a team to a generated draw", Request: types.AddDrawTeamRequest{}, Response: types.DrawTeamChangeResponse{}},
+	{Method: "DELETE", Path: "/api/v1/draws/:id/teams/:teamId", Tag: "Draws", Summary: "Remove a team from a generated draw", Response: types.DrawTeamChangeResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/teams/:teamId/schedule", Tag: "Draws", Summary: "Get a team's season schedule", Response: types.TeamScheduleResponse{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/generate", Tag: "Draws", Summary: "Generate a draw's matches", Request: types.GenerateDrawRequest{}, Response: types.GenerateDrawResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/validate-constraints", Tag: "Constraints", Summary: "Validate a draw against its constraints", Response: types.ValidateConstraintsResponse{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/validate-constraints", Tag: "Constraints", Summary: "Validate a draw against the given constraints", Request: types.ValidateConstraintsRequest{}, Response: types.ValidateConstraintsResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/statistics", Tag: "Constraints", Summary: "Get every analysis the draw's constraints offer", Response: types.DrawStatisticsResponse{}},
+
+	// Approvals
+	{Method: "GET", Path: "/api/v1/draws/:id/approvals", Tag: "Approvals", Summary: "Get a draw's approval status", Response: approval.Summary{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/approvals", Tag: "Approvals", Summary: "Request sign-off on a draw", Request: types.RequestApprovalsRequest{}, Status: http.StatusCreated, Response: approval.Summary{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/approvals/:role", Tag: "Approvals", Summary: "Record a role's decision", Request: types.RecordApprovalRequest{}, Response: approval.Summary{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/publish", Tag: "Approvals", Summary: "Publish an approved draw", Response: types.DrawResponse{}},
+
+	// Kickoff slots
+	{Method: "POST", Path: "/api/v1/draws/:id/assign-slots", Tag: "Slots", Summary: "Assign kickoff slots to a draw's matches", Request: types.AssignSlotsRequest{}, Response: types.AssignSlotsResponse{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/schedule-timeslots", Tag: "Slots", Summary: "Schedule a draw's matches from timeslot templates", Request: types.ScheduleTimeslotsRequest{}, Response: types.AssignSlotsResponse{}},
+
+	// Share links
+	{Method: "POST", Path: "/api/v1/draws/:id/share-links", Tag: "Sharing", Summary: "Create a read-only share link", Request: types.CreateShareLinkRequest{}, Status: http.StatusCreated, Response: types.ShareLinkResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/share-links", Tag: "Sharing", Summary: "List a draw's share links", Response: []types.ShareLinkResponse{}},
+	{Method: "DELETE", Path: "/api/v1/draws/:id/share-links/:linkId", Tag: "Sharing", Summary: "Revoke a share link", Status: http.StatusNoContent},
+	{Method: "GET", Path: "/share/:token", Tag: "Sharing", Summary: "View a shared draw", Description: "Returns an HTML page, or JSON with format=json or Accept: application/json.", Params: []types.OpenAPIParameter{
+		{Name: "format", Schema: &types.OpenAPISchema{Type: "string", Enum: []interface{}{"json"}}},
+	}, Response: share.SharedDraw{}, Public: true},
+	{Method: "GET", Path: "/api/v1/public/draws/:id/fixtures", Tag: "Sharing", Summary: "Get a published draw's fixture feed", Description: "Cacheable by ETag and Last-Modified; unpublished draws return 404.", Response: feed.Fixture{}, Public: true},
+
+	// Comparison and cross-draw checks
+	{Method: "POST", Path: "/api/v1/draws/compare", Tag: "Draws", Summary: "Compare draws side by side", Request: types.CompareDrawsRequest{}, Response: compare.Comparison{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/venue-conflicts", Tag: "Draws", Summary: "Find venue clashes with other competitions' draws", Query: types.VenueConflictsQuery{}, Response: crossdraw.VenueConflictReport{}},
+
+	// Season ladders
+	{Method: "GET", Path: "/api/v1/ladders/:season", Tag: "Ladders", Summary: "Get a season's final ladder", Response: types.LadderResponse{}},
+	{Method: "PUT", Path: "/api/v1/ladders/:season", Tag: "Ladders", Summary: "Import a season's final ladder", Request: types.ImportLadderRequest{}, Response: types.LadderResponse{}},
+	{Method: "POST", Path: "/api/v1/ladders/:season/repeat-matchups", Tag: "Ladders", Summary: "Derive repeat matchups from a ladder", Request: types.RepeatMatchupsRequest{}, Response: types.RepeatMatchupsResponse{}},
+
+	// Export
+	{Method: "GET", Path: "/api/v1/draws/:id/export", Tag: "Export", Summary: "Export a draw as CSV or iCalendar", Query: types.ExportQueryParams{}, ResponseType: "text/csv"},
+	{Method: "GET", Path: "/api/v1/draws/:id/report", Tag: "Export", Summary: "Render a printable draw report", Query: types.ReportQueryParams{}, ResponseType: "text/html"},
+	{Method: "POST", Path: "/api/v1/draws/:id/export", Tag: "Export", Summary: "Export a draw with locale overrides, such as Indigenous Round team names", Query: types.ExportQueryParams{}, Request: types.ExportLocaleRequest{}, ResponseType: "text/csv"},
+	{Method: "POST", Path: "/api/v1/draws/:id/report", Tag: "Export", Summary: "Render a draw report with locale overrides", Query: types.ReportQueryParams{}, Request: types.ExportLocaleRequest{}, ResponseType: "text/html"},
+
+	// Matches
+	{Method: "PATCH", Path: "/api/v1/matches/:id", Tag: "Matches", Summary: "Edit a match", Description: "Reports the constraint violations and score change the edit causes. Edits that break hard constraints return 409 with the same report unless forced.", Params: []types.OpenAPIParameter{
+		{Name: "dry_run", Description: "Report without saving", Schema: &types.OpenAPISchema{Type: "boolean"}},
+	}, Request: types.UpdateMatchRequest{}, Response: types.MatchChangeResponse{}},
+	{Method: "GET", Path: "/api/v1/matches/:id/venue-substitutes", Tag: "Matches", Summary: "Suggest substitute venues for a match", Params: []types.OpenAPIParameter{
+		{Name: "limit", Description: "Most substitutes to return", Schema: &types.OpenAPISchema{Type: "integer"}},
+	}, Response: types.VenueSubstitutesResponse{}},
+	{Method: "POST", Path: "/api/v1/matches/:id/venue-substitutes", Tag: "Matches", Summary: "Move a match to a substitute venue", Request: types.ApplyVenueSubstitutionRequest{}, Response: types.MatchResponse{}},
+	{Method: "PATCH", Path: "/api/v1/matches/:id/lock", Tag: "Matches", Summary: "Lock or unlock a match", Request: types.LockMatchRequest{}, Response: types.MatchResponse{}},
+	{Method: "POST", Path: "/api/v1/matches/:id/postpone", Tag: "Matches", Summary: "Postpone a match", Request: types.PostponeMatchRequest{}, Response: types.PostponeMatchResponse{}},
+
+	// Constraints
+	{Method: "GET", Path: "/api/v1/constraints/types", Tag: "Constraints", Summary: "List constraint types", Response: types.ConstraintTypesResponse{}},
+	{Method: "GET", Path: "/api/v1/constraints/schema", Tag: "Constraints", Summary: "Get the JSON Schema of constraint configurations", Response: types.ConstraintSchemaResponse{}},
+	{Method: "POST", Path: "/api/v1/constraints/validate", Tag: "Constraints", Summary: "Validate a constraint configuration", Request: types.ValidateConstraintConfigRequest{}, Response: types.ValidateConstraintConfigResponse{}},
+	{Method: "POST", Path: "/api/v1/constraints/simulate", Tag: "Constraints", Summary: "Score a constraint configuration on random draws", Request: types.SimulateConstraintsRequest{}, Response: simulate.Result{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/constraints/timeline", Tag: "Constraints", Summary: "Get a draw's constraint violations by round", Response: types.ConstraintTimelineResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/constraints/broadcaster-quotas", Tag: "Constraints", Summary: "Report broadcaster quotas", Response: types.BroadcasterQuotaReportResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/constraints/derbies", Tag: "Constraints", Summary: "Report derby placement", Response: types.DerbyReportResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/constraints/region-spread", Tag: "Constraints", Summary: "Report home matches per region per round", Response: types.RegionSpreadReportResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/constraints/travel-fatigue", Tag: "Constraints", Summary: "Report each team's itinerary and travel fatigue", Response: types.TravelFatigueReportResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/constraints/travel-cost", Tag: "Constraints", Summary: "Estimate each team's and the league's travel cost", Response: types.TravelCostReportResponse{}},
+	{Method: "GET", Path: "/api/v1/draws/:id/constraints/travel-cost/teams/:teamId", Tag: "Constraints", Summary: "Estimate one team's travel cost", Response: types.TeamTravelCostResponse{}},
+
+	// Admin
+	{Method: "POST", Path: "/api/v1/admin/geocode", Tag: "Admin", Summary: "Fill in missing coordinates", Params: []types.OpenAPIParameter{
+		{Name: "dry_run", Description: "Report without saving", Schema: &types.OpenAPISchema{Type: "boolean"}},
+	}, Response: geo.BackfillReport{}},
+	{Method: "POST", Path: "/api/v1/admin/seed/nrl", Tag: "Admin", Summary: "Seed the NRL's teams and venues", Response: seed.Report{}},
+
+	// Webhooks
+	{Method: "GET", Path: "/api/v1/webhooks", Tag: "Webhooks", Summary: "List webhooks", Response: []types.WebhookResponse{}},
+	{Method: "POST", Path: "/api/v1/webhooks", Tag: "Webhooks", Summary: "Register a webhook", Description: "Deliveries are signed with the returned secret, which isn't shown again.", Request: types.CreateWebhookRequest{}, Status: http.StatusCreated, Response: types.WebhookResponse{}},
+	{Method: "GET", Path: "/api/v1/webhooks/:id", Tag: "Webhooks", Summary: "Get a webhook and its latest delivery", Response: types.WebhookResponse{}},
+	{Method: "PUT", Path: "/api/v1/webhooks/:id", Tag: "Webhooks", Summary: "Update a webhook", Request: types.UpdateWebhookRequest{}, Response: types.WebhookResponse{}},
+	{Method: "DELETE", Path: "/api/v1/webhooks/:id", Tag: "Webhooks", Summary: "Delete a webhook", Response: types.SuccessResponse{}},
+
+	// Optimization
+	{Method: "POST", Path: "/api/v1/optimize/draws/:drawId/start", Tag: "Optimization", Summary: "Start optimizing a draw", Request: types.StartOptimizationRequest{}, Status: http.StatusAccepted, Response: types.StartOptimizationResponse{}},
+	{Method: "GET", Path: "/api/v1/optimize/jobs/:jobId/status", Tag: "Optimization", Summary: "Get a job's progress", Response: types.OptimizationStatusResponse{}},
+	{Method: "GET", Path: "/api/v1/optimize/jobs/:jobId/history", Tag: "Optimization", Summary: "Get a job's score history", Response: optimizer.JobHistory{}},
+	{Method: "POST", Path: "/api/v1/optimize/jobs/:jobId/cancel", Tag: "Optimization", Summary: "Cancel a job", Response: map[string]string{}},
+	{Method: "GET", Path: "/api/v1/optimize/jobs/:jobId/result", Tag: "Optimization", Summary: "Get a finished job's result", Response: optimizer.OptimizationResult{}},
+	{Method: "GET", Path: "/api/v1/optimize/jobs/:jobId/diff", Tag: "Optimization", Summary: "Compare a job's result with its draw", Response: optimizer.OptimizationDiff{}},
+	{Method: "POST", Path: "/api/v1/optimize/jobs/:jobId/apply", Tag: "Optimization", Summary: "Apply a job's result to its draw", Response: map[string]string{}},
+	{Method: "POST", Path: "/api/v1/optimize/jobs/:jobId/restore", Tag: "Optimization", Summary: "Restore an archived job", Response: types.OptimizationStatusResponse{}},
+	{Method: "POST", Path: "/api/v1/optimize/jobs/:jobId/resume", Tag: "Optimization", Summary: "Resume an interrupted job from its checkpoint", Status: http.StatusAccepted, Response: types.StartOptimizationResponse{}},
+	{Method: "GET", Path: "/api/v1/optimize/draws/:drawId/validate-constraints", Tag: "Optimization", Summary: "Validate a draw with the optimizer's constraints", Response: types.ConstraintValidationResponse{}},
+	{Method: "GET", Path: "/api/v1/optimize/draws/:drawId/score", Tag: "Optimization", Summary: "Score a draw", Response: types.DrawScoreResponse{}},
+	{Method: "GET", Path: "/api/v1/optimize/jobs", Tag: "Optimization", Summary: "List jobs", Params: []types.OpenAPIParameter{
+		{Name: "draw_id", Description: "List one draw's jobs", Schema: &types.OpenAPISchema{Type: "integer"}},
+	}, Response: types.OptimizationJobsResponse{}},
+	{Method: "GET", Path: "/api/v1/optimize/statistics", Tag: "Optimization", Summary: "Get job statistics", Response: optimizer.JobStatistics{}},
+	{Method: "GET", Path: "/api/v1/optimize/config", Tag: "Optimization", Summary: "Get the optimizer configuration", Response: optimizer.OptimizationConfig{}},
+	{Method: "PUT", Path: "/api/v1/optimize/config", Tag: "Optimization", Summary: "Set the optimizer configuration", Request: optimizer.OptimizationConfig{}, Response: map[string]interface{}{}},
+	{Method: "GET", Path: "/api/v1/optimize/retention", Tag: "Optimization", Summary: "Get the job retention policy", Response: types.RetentionPolicyResponse{}},
+	{Method: "PUT", Path: "/api/v1/optimize/retention", Tag: "Optimization", Summary: "Set the job retention policy", Request: types.RetentionPolicyRequest{}, Response: types.RetentionPolicyResponse{}},
+	{Method: "POST", Path: "/api/v1/optimize/retention/apply", Tag: "Optimization", Summary: "Archive and prune jobs now", Response: optimizer.RetentionReport{}},
+	{Method: "GET", Path: "/api/v1/optimize/archives", Tag: "Optimization", Summary: "List archived jobs", Params: []types.OpenAPIParameter{
+		{Name: "draw_id", Description: "List one draw's archived jobs", Schema: &types.OpenAPISchema{Type: "integer"}},
+	}, Response: types.JobArchivesResponse{}},
+	{Method: "GET", Path: "/api/v1/optimize/solutions", Tag: "Optimization", Summary: "List the solution library", Response: types.SolutionsResponse{}},
+	{Method: "DELETE", Path: "/api/v1/optimize/solutions/:id", Tag: "Optimization", Summary: "Delete a library solution", Response: types.SuccessResponse{}},
+	{Method: "POST", Path: "/api/v1/draws/:id/tune-weights", Tag: "Optimization", Summary: "Tune soft constraint weights", Request: types.TuneWeightsRequest{}, Response: optimizer.WeightTuningResult{}},
+
+	// WebSockets
+	{Method: "GET", Path: "/ws", Tag: "WebSockets", Summary: "Subscribe to draw and job events", Status: http.StatusSwitchingProtocols},
+	{Method: "GET", Path: "/ws/optimize/:jobId", Tag: "WebSockets", Summary: "Stream one job's progress", Params: []types.OpenAPIParameter{
+		{Name: "interval_ms", Description: "Least time between progress messages", Schema: &types.OpenAPISchema{Type: "integer"}},
+	}, Status: http.StatusSwitchingProtocols},
+
+	// Operations
+	{Method: "GET", Path: "/health", Tag: "Operations", Summary: "Check the server is up", Response: map[string]string{}, Public: true},
+	{Method: "POST", Path: "/test/websocket", Tag: "Operations", Summary: "Broadcast a test WebSocket message", Response: map[string]interface{}{}},
+	{Method: "GET", Path: "/openapi.json", Tag: "Operations", Summary: "Get this OpenAPI document", Response: map[string]interface{}{}, Public: true},
+	{Method: "GET", Path: "/docs", Tag: "Operations", Summary: "Browse this document in Swagger UI", ResponseType: "text/html", Public: true},
+}
+
+// buildOpenAPIDocument generates the OpenAPI document for apiEndpoints
+func buildOpenAPIDocument() *types.OpenAPIDocument {
+	builder := types.NewOpenAPIBuilder(types.OpenAPIInfo{
+		Title:       "NRL Scheduler API",
+		Version:     "1.0",
+		Description: "Generate, constrain, optimize and publish rugby league draws.",
+	})
+	for _, endpoint := range apiEndpoints {
+		builder.Add(endpoint)
+	}
+	return builder.Document()
+}
+
+// swaggerUIPage loads Swagger UI from a CDN and points it at /openapi.json
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>NRL Scheduler API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// serveOpenAPI serves the OpenAPI document and Swagger UI. The document is
+// built once, since the routes can't change while the server runs.
+func (s *Server) serveOpenAPI() {
+	document := buildOpenAPIDocument()
+	s.router.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, document)
+	})
+	s.router.GET("/docs", func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.String(http.StatusOK, swaggerUIPage)
+	})
+}
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"compress/gzip"
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -11,10 +16,31 @@ import (
 	"github.com/adampetrovic/nrl-scheduler/internal/api/handlers"
 	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
 	"github.com/adampetrovic/nrl-scheduler/internal/api/websocket"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/approval"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/compare"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/crossdraw"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/feed"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/ladder"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/optimizer"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/reschedule"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/share"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/slots"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/webhook"
 	"github.com/adampetrovic/nrl-scheduler/internal/storage/sqlite"
 )
 
+// webhookEvents maps the WebSocket messages forwarded to webhooks to their
+// webhook event names
+var webhookEvents = map[string]string{
+	websocket.OptimizationCompleted: webhook.EventOptimizationCompleted,
+	websocket.DrawGenerated:         webhook.EventDrawGenerated,
+	websocket.ConstraintsValidated:  webhook.EventConstraintsValidated,
+	websocket.ApprovalRequested:     webhook.EventApprovalRequested,
+	websocket.ApprovalRecorded:      webhook.EventApprovalRecorded,
+	websocket.DrawPublished:         webhook.EventDrawPublished,
+}
+
 type Server struct {
 	router          *gin.Engine
 	db              *sql.DB
@@ -22,6 +48,13 @@ type Server struct {
 	validate        *validator.Validate
 	optimizerService *optimizer.Service
 	wsHub           *websocket.Hub
+	webhookService  *webhook.Service
+	apiKeys         *middleware.APIKeys
+
+	// httpServer is set by Run; stopBackground stops the retention loop
+	httpServer      *http.Server
+	httpMutex       sync.Mutex
+	stopBackground  context.CancelFunc
 }
 
 func NewServer(db *sql.DB) *Server {
@@ -34,6 +67,14 @@ func NewServer(db *sql.DB) *Server {
 	// Create optimizer service
 	optimizerService := optimizer.NewService(repos)
 
+	// Forward lifecycle events broadcast over WebSockets to webhooks
+	webhookService := webhook.NewService(repos)
+	wsHub.AddListener(func(messageType string, data interface{}) {
+		if event, ok := webhookEvents[messageType]; ok {
+			webhookService.Publish(event, data)
+		}
+	})
+
 	server := &Server{
 		router:          gin.New(),
 		db:              db,
@@ -41,6 +82,17 @@ func NewServer(db *sql.DB) *Server {
 		validate:        validate,
 		optimizerService: optimizerService,
 		wsHub:           wsHub,
+		webhookService:  webhookService,
+		apiKeys:         middleware.NewAPIKeys(),
+	}
+	background, stopBackground := context.WithCancel(context.Background())
+	server.stopBackground = stopBackground
+
+	// Reload jobs from before a restart, failing any that were interrupted
+	if report, err := optimizerService.RecoverJobs(context.Background()); err != nil {
+		log.Printf("Error recovering optimization jobs: %v", err)
+	} else if report.Restored+report.Orphaned > 0 {
+		log.Printf("Recovered %d optimization jobs, %d interrupted by the restart", report.Restored+report.Orphaned, report.Orphaned)
 	}
 
 	// Set up WebSocket broadcasting for the optimizer service
@@ -49,6 +101,12 @@ func NewServer(db *sql.DB) *Server {
 	// Start WebSocket hub
 	go wsHub.Run()
 
+	// Start delivering webhooks
+	go webhookService.Run()
+
+	// Archive finished optimization jobs and prune old payloads
+	go optimizerService.RunRetention(background, optimizer.DefaultRetentionInterval)
+
 	server.setupMiddleware()
 	server.setupRoutes()
 
@@ -58,14 +116,15 @@ func NewServer(db *sql.DB) *Server {
 func (s *Server) setupMiddleware() {
 	s.router.Use(gin.Logger())
 	s.router.Use(gin.Recovery())
+	s.router.Use(middleware.Gzip(gzip.DefaultCompression))
 	s.router.Use(func(c *gin.Context) {
 		c.Header("Content-Type", "application/json")
 		c.Next()
 	})
 	s.router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -74,6 +133,7 @@ func (s *Server) setupMiddleware() {
 
 		c.Next()
 	})
+	s.router.Use(middleware.Authenticate(s.apiKeys, routeRole))
 	s.router.Use(middleware.ErrorHandler())
 	s.router.Use(middleware.RequestValidator(s.validate))
 }
@@ -81,13 +141,56 @@ func (s *Server) setupMiddleware() {
 func (s *Server) setupRoutes() {
 	api := s.router.Group("/api/v1")
 
+	// Bulk import endpoints
+	importHandler := handlers.NewImportHandler(s.repos)
+	api.POST("/teams/import", importHandler.ImportTeams)
+	api.POST("/venues/import", importHandler.ImportVenues)
+
+	// Competitions endpoints
+	competitionHandler := handlers.NewCompetitionHandler(s.repos.Competitions())
+	api.GET("/competitions", competitionHandler.GetCompetitions)
+	api.POST("/competitions", competitionHandler.CreateCompetition)
+	api.GET("/competitions/:id", competitionHandler.GetCompetition)
+	api.PUT("/competitions/:id", competitionHandler.UpdateCompetition)
+	api.DELETE("/competitions/:id", competitionHandler.DeleteCompetition)
+
+	// Timeslot catalogue endpoints
+	timeslotHandler := handlers.NewTimeslotHandler(s.repos.Timeslots(), s.repos.Competitions())
+	api.GET("/timeslots", timeslotHandler.GetTimeslots)
+	api.POST("/timeslots", timeslotHandler.CreateTimeslot)
+	api.GET("/timeslots/:id", timeslotHandler.GetTimeslot)
+	api.PUT("/timeslots/:id", timeslotHandler.UpdateTimeslot)
+	api.DELETE("/timeslots/:id", timeslotHandler.DeleteTimeslot)
+
+	// Season calendar endpoints
+	calendarHandler := handlers.NewSeasonCalendarHandler(s.repos.SeasonCalendars(), s.repos.Competitions())
+	api.GET("/calendars", calendarHandler.GetCalendars)
+	api.POST("/calendars", calendarHandler.CreateCalendar)
+	api.GET("/calendars/:id", calendarHandler.GetCalendar)
+	api.PUT("/calendars/:id", calendarHandler.UpdateCalendar)
+	api.DELETE("/calendars/:id", calendarHandler.DeleteCalendar)
+
+	// Constraint template endpoints
+	templateHandler := handlers.NewConstraintTemplateHandler(s.repos.ConstraintTemplates(), s.repos.Draws(), s.repos.Competitions(), s.wsHub)
+	api.GET("/constraint-templates", templateHandler.GetTemplates)
+	api.POST("/constraint-templates", templateHandler.CreateTemplate)
+	api.GET("/constraint-templates/:id", templateHandler.GetTemplate)
+	api.PUT("/constraint-templates/:id", templateHandler.UpdateTemplate)
+	api.DELETE("/constraint-templates/:id", templateHandler.DeleteTemplate)
+	api.GET("/constraint-templates/:id/versions", templateHandler.GetTemplateVersions)
+	api.GET("/constraint-templates/:id/versions/:version", templateHandler.GetTemplateVersion)
+	api.POST("/constraint-templates/:id/draws", templateHandler.CreateDrawFromTemplate)
+
 	// Teams endpoints
-	teamHandler := handlers.NewTeamHandler(s.repos.Teams())
+	teamHandler := handlers.NewTeamHandler(s.repos.Teams(), s.repos.Competitions())
 	api.GET("/teams", teamHandler.GetTeams)
 	api.POST("/teams", teamHandler.CreateTeam)
 	api.GET("/teams/:id", teamHandler.GetTeam)
 	api.PUT("/teams/:id", teamHandler.UpdateTeam)
 	api.DELETE("/teams/:id", teamHandler.DeleteTeam)
+	api.GET("/teams/:id/unavailability", teamHandler.GetTeamUnavailability)
+	api.POST("/teams/:id/unavailability", teamHandler.CreateTeamUnavailability)
+	api.DELETE("/teams/:id/unavailability/:unavailabilityId", teamHandler.DeleteTeamUnavailability)
 
 	// Venues endpoints
 	venueHandler := handlers.NewVenueHandler(s.repos.Venues())
@@ -98,32 +201,126 @@ func (s *Server) setupRoutes() {
 	api.DELETE("/venues/:id", venueHandler.DeleteVenue)
 
 	// Draws endpoints
-	drawHandler := handlers.NewDrawHandler(s.repos.Draws(), s.repos.Teams(), s.repos.Matches(), s.wsHub)
+	drawHandler := handlers.NewDrawHandler(s.repos, s.wsHub)
+	// Nothing may change a draw while an optimization job holds its lock
+	drawLock := handlers.RequireDrawUnlocked(s.optimizerService, handlers.DrawParam)
+	matchLock := handlers.RequireDrawUnlocked(s.optimizerService, handlers.MatchDraw(s.repos.Matches()))
 	api.GET("/draws", drawHandler.GetDraws)
 	api.POST("/draws", drawHandler.CreateDraw)
 	api.GET("/draws/:id", drawHandler.GetDraw)
-	api.PUT("/draws/:id", drawHandler.UpdateDraw)
-	api.DELETE("/draws/:id", drawHandler.DeleteDraw)
+	api.PUT("/draws/:id", drawLock, drawHandler.UpdateDraw)
+	api.DELETE("/draws/:id", drawLock, drawHandler.DeleteDraw)
+	api.POST("/draws/:id/clone", drawHandler.CloneDraw)
 	api.GET("/draws/:id/matches", drawHandler.GetDrawMatches)
+	api.GET("/draws/:id/rounds/:round", drawHandler.GetRound)
+	api.POST("/draws/:id/teams", drawLock, drawHandler.AddDrawTeam)
+	api.DELETE("/draws/:id/teams/:teamId", drawLock, drawHandler.RemoveDrawTeam)
+	api.GET("/draws/:id/teams/:teamId/schedule", drawHandler.GetTeamSchedule)
 
 	// Draw generation endpoints
-	api.POST("/draws/:id/generate", drawHandler.GenerateDraw)
+	api.POST("/draws/:id/generate", drawLock, drawHandler.GenerateDraw)
+	api.GET("/draws/:id/validate-constraints", drawHandler.ValidateConstraints)
 	api.POST("/draws/:id/validate-constraints", drawHandler.ValidateConstraints)
+	api.GET("/draws/:id/statistics", drawHandler.GetDrawStatistics)
+
+	// Approval endpoints
+	approvalHandler := handlers.NewApprovalHandler(approval.NewService(s.repos), s.wsHub)
+	api.GET("/draws/:id/approvals", approvalHandler.GetApprovals)
+	api.POST("/draws/:id/approvals", approvalHandler.RequestApprovals)
+	api.POST("/draws/:id/approvals/:role", approvalHandler.RecordApproval)
+	api.POST("/draws/:id/publish", drawLock, approvalHandler.PublishDraw)
+
+	// Kickoff slot endpoints
+	slotHandler := handlers.NewSlotHandler(slots.NewService(s.repos), s.wsHub)
+	api.POST("/draws/:id/assign-slots", drawLock, slotHandler.AssignSlots)
+	api.POST("/draws/:id/schedule-timeslots", drawLock, slotHandler.ScheduleTimeslots)
+
+	// Share link endpoints
+	shareHandler := handlers.NewShareHandler(share.NewService(s.repos))
+	api.POST("/draws/:id/share-links", shareHandler.CreateShareLink)
+	api.GET("/draws/:id/share-links", shareHandler.GetShareLinks)
+	api.DELETE("/draws/:id/share-links/:linkId", shareHandler.RevokeShareLink)
+	s.router.GET("/share/:token", shareHandler.ViewSharedDraw)
+
+	// Public fixture feed endpoints
+	feedHandler := handlers.NewFeedHandler(feed.NewService(s.repos))
+	api.GET("/public/draws/:id/fixtures", feedHandler.GetPublishedFixtures)
+
+	// Comparison endpoints
+	compareHandler := handlers.NewCompareHandler(compare.NewService(s.repos))
+	api.POST("/draws/compare", compareHandler.CompareDraws)
+
+	// Cross-draw endpoints
+	venueConflictHandler := handlers.NewVenueConflictHandler(crossdraw.NewService(s.repos))
+	api.GET("/draws/:id/venue-conflicts", venueConflictHandler.GetVenueConflicts)
+
+	// Season ladder endpoints
+	ladderHandler := handlers.NewLadderHandler(ladder.NewService(s.repos))
+	api.GET("/ladders/:season", ladderHandler.GetLadder)
+	api.PUT("/ladders/:season", ladderHandler.ImportLadder)
+	api.POST("/ladders/:season/repeat-matchups", ladderHandler.DeriveRepeatMatchups)
+
+	// Export endpoints
+	exportHandler := handlers.NewExportHandler(s.repos.Draws(), s.repos.Teams(), s.repos.Venues(), s.repos.Matches())
+	api.GET("/draws/:id/export", exportHandler.ExportDraw)
+	api.GET("/draws/:id/report", exportHandler.ReportDraw)
+	api.POST("/draws/:id/export", exportHandler.ExportDraw)
+	api.POST("/draws/:id/report", exportHandler.ReportDraw)
+
+	// Match endpoints
+	matchHandler := handlers.NewMatchHandler(reschedule.NewService(s.repos), s.wsHub)
+	api.PATCH("/matches/:id", matchLock, matchHandler.UpdateMatch)
+	api.GET("/matches/:id/venue-substitutes", matchHandler.GetVenueSubstitutes)
+	api.POST("/matches/:id/venue-substitutes", matchLock, matchHandler.ApplyVenueSubstitution)
+	api.PATCH("/matches/:id/lock", matchHandler.LockMatch)
+	api.POST("/matches/:id/postpone", matchLock, matchHandler.PostponeMatch)
+
+	// Constraint endpoints
+	constraintHandler := handlers.NewConstraintHandler(s.repos.Draws(), s.repos.Teams(), s.repos.Venues())
+	api.GET("/constraints/types", constraintHandler.GetConstraintTypes)
+	api.GET("/constraints/schema", constraintHandler.GetConstraintSchema)
+	api.POST("/constraints/validate", constraintHandler.ValidateConstraintConfig)
+	api.POST("/constraints/simulate", constraintHandler.SimulateConstraints)
+	api.GET("/draws/:id/constraints/timeline", constraintHandler.GetConstraintTimeline)
+	api.GET("/draws/:id/constraints/broadcaster-quotas", constraintHandler.GetBroadcasterQuotas)
+	api.GET("/draws/:id/constraints/derbies", constraintHandler.GetDerbies)
+	api.GET("/draws/:id/constraints/region-spread", constraintHandler.GetRegionSpread)
+	api.GET("/draws/:id/constraints/travel-fatigue", constraintHandler.GetTravelFatigue)
+	api.GET("/draws/:id/constraints/travel-cost", constraintHandler.GetTravelCost)
+	api.GET("/draws/:id/constraints/travel-cost/teams/:teamId", constraintHandler.GetTeamTravelCost)
+
+	// Admin endpoints
+	adminHandler := handlers.NewAdminHandler(s.repos, geo.NewOfflineGeocoder())
+	api.POST("/admin/geocode", adminHandler.GeocodeMissingCoordinates)
+	api.POST("/admin/seed/nrl", adminHandler.SeedNRL)
+
+	// Webhook endpoints
+	webhookHandler := handlers.NewWebhookHandler(s.webhookService)
+	api.GET("/webhooks", webhookHandler.GetWebhooks)
+	api.POST("/webhooks", webhookHandler.CreateWebhook)
+	api.GET("/webhooks/:id", webhookHandler.GetWebhook)
+	api.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+	api.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
 
 	// Optimization endpoints
 	optimizationHandler := handlers.NewOptimizationHandler(s.optimizerService, s.wsHub)
 	optimizationHandler.RegisterRoutes(api)
+	api.POST("/draws/:id/tune-weights", optimizationHandler.TuneWeights)
 
 	// WebSocket endpoint
 	s.router.GET("/ws", func(c *gin.Context) {
 		s.wsHub.ServeWS(c.Writer, c.Request)
 	})
+	s.router.GET("/ws/optimize/:jobId", optimizationHandler.StreamOptimization)
 
 	// Health check
 	s.router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	// API documentation
+	s.serveOpenAPI()
+
 	// Test WebSocket endpoint
 	s.router.POST("/test/websocket", func(c *gin.Context) {
 		if s.wsHub != nil {
@@ -143,15 +340,66 @@ func (s *Server) setupRoutes() {
 	})
 }
 
+// Run serves the API on addr until Shutdown is called, returning nil once
+// the server has shut down
 func (s *Server) Run(addr string) error {
 	log.Printf("Starting server on %s", addr)
-	return s.router.Run(addr)
+	s.httpMutex.Lock()
+	s.httpServer = &http.Server{Addr: addr, Handler: s.router}
+	httpServer := s.httpServer
+	s.httpMutex.Unlock()
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops the server gracefully. It stops accepting requests and
+// waits for those in flight, cancels running optimization jobs so they
+// checkpoint, then delivers the webhooks and WebSocket messages still queued
+// and closes the connections. Every step runs even when an earlier one fails or ctx is
+// done, and their errors are joined. The database is left for the caller to
+// close.
+func (s *Server) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	s.httpMutex.Lock()
+	httpServer := s.httpServer
+	s.httpMutex.Unlock()
+	if httpServer != nil {
+		if err := httpServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stopping HTTP server: %w", err))
+		}
+	}
+
+	s.stopBackground()
+
+	if err := s.optimizerService.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("stopping optimization jobs: %w", err))
+	}
+
+	if err := s.webhookService.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("stopping webhook deliveries: %w", err))
+	}
+
+	if err := s.wsHub.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("stopping WebSocket hub: %w", err))
+	}
+
+	return errors.Join(errs...)
 }
 
 func (s *Server) GetRouter() *gin.Engine {
 	return s.router
 }
 
+// SetAPIKeys sets the API keys the server accepts and the role of each,
+// turning on authentication. With no keys, every request is allowed.
+func (s *Server) SetAPIKeys(keys map[string]middleware.Role) {
+	s.apiKeys.Set(keys)
+}
+
 func (s *Server) GetWebSocketHub() *websocket.Hub {
 	return s.wsHub
 }
@@ -31,6 +31,14 @@ type Client struct {
 
 	// Buffered channel of outbound messages.
 	send chan []byte
+
+	// Job the client is subscribed to; empty for the global event stream.
+	jobID string
+
+	// Minimum time between progress messages for a job subscriber, and when
+	// the last one was sent.
+	progressInterval time.Duration
+	lastProgress     time.Time
 }
 
 // readPump pumps messages from the websocket connection to the hub.
@@ -40,7 +48,7 @@ type Client struct {
 // reads from this goroutine.
 func (c *Client) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		c.hub.leave(c)
 		c.conn.Close()
 	}()
 	c.conn.SetReadLimit(maxMessageSize)
@@ -116,7 +124,10 @@ func serveWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	client := &Client{hub: hub, conn: conn, send: make(chan []byte, 256)}
-	client.hub.register <- client
+	if !client.hub.join(client) {
+		conn.Close()
+		return
+	}
 
 	// Allow collection of memory referenced by the caller by doing all work in
 	// new goroutines.
@@ -1,22 +1,46 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// Limits on how often a job subscriber receives progress messages
+const (
+	DefaultProgressInterval = 500 * time.Millisecond
+	MinProgressInterval     = 50 * time.Millisecond
+)
+
+// jobMessage is a message for the subscribers of one optimization job
+type jobMessage struct {
+	jobID     string
+	data      []byte
+	throttled bool // progress updates are dropped for subscribers that had one too recently
+}
+
 // Hub maintains the set of active clients and broadcasts messages to the clients
 type Hub struct {
 	// Registered clients
 	clients map[*Client]bool
 
+	// Clients subscribed to a single optimization job, keyed by job ID
+	jobClients map[string]map[*Client]bool
+
 	// Inbound messages from the clients
 	broadcast chan []byte
 
+	// Messages for the subscribers of one job
+	jobMessages chan jobMessage
+
+	// How often job subscribers receive progress unless they ask otherwise
+	progressInterval time.Duration
+
 	// Register requests from the clients
 	register chan *Client
 
@@ -25,15 +49,28 @@ type Hub struct {
 
 	// Mutex for thread-safe operations
 	mutex sync.RWMutex
+
+	// Listeners are told about every broadcast, such as to forward events to webhooks
+	listeners []Listener
+
+	// quit asks Run to flush and stop; done is closed once it has
+	quit     chan struct{}
+	done     chan struct{}
+	quitOnce sync.Once
 }
 
 // NewHub creates a new WebSocket hub
 func NewHub() *Hub {
 	return &Hub{
-		broadcast:  make(chan []byte),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		clients:    make(map[*Client]bool),
+		broadcast:        make(chan []byte),
+		jobMessages:      make(chan jobMessage, 256),
+		register:         make(chan *Client),
+		unregister:       make(chan *Client),
+		clients:          make(map[*Client]bool),
+		jobClients:       make(map[string]map[*Client]bool),
+		progressInterval: DefaultProgressInterval,
+		quit:             make(chan struct{}),
+		done:             make(chan struct{}),
 	}
 }
 
@@ -43,18 +80,22 @@ func (h *Hub) Run() {
 		select {
 		case client := <-h.register:
 			h.mutex.Lock()
-			h.clients[client] = true
+			if client.jobID != "" {
+				if h.jobClients[client.jobID] == nil {
+					h.jobClients[client.jobID] = make(map[*Client]bool)
+				}
+				h.jobClients[client.jobID][client] = true
+			} else {
+				h.clients[client] = true
+			}
 			h.mutex.Unlock()
-			log.Printf("Client connected. Total clients: %d", len(h.clients))
+			log.Printf("Client connected. Total clients: %d", h.GetClientCount())
 
 		case client := <-h.unregister:
 			h.mutex.Lock()
-			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				close(client.send)
-			}
+			h.removeClient(client)
 			h.mutex.Unlock()
-			log.Printf("Client disconnected. Total clients: %d", len(h.clients))
+			log.Printf("Client disconnected. Total clients: %d", h.GetClientCount())
 
 		case message := <-h.broadcast:
 			h.mutex.RLock()
@@ -67,12 +108,133 @@ func (h *Hub) Run() {
 				}
 			}
 			h.mutex.RUnlock()
+
+		case message := <-h.jobMessages:
+			h.sendToJob(message)
+
+		case <-h.quit:
+			h.flush()
+			close(h.done)
+			return
+		}
+	}
+}
+
+// sendToJob delivers a message to a job's subscribers
+func (h *Hub) sendToJob(message jobMessage) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	now := time.Now()
+	for client := range h.jobClients[message.jobID] {
+		if message.throttled {
+			if now.Sub(client.lastProgress) < client.progressInterval {
+				continue
+			}
+			client.lastProgress = now
+		}
+		select {
+		case client.send <- message.data:
+		default:
+			h.removeClient(client)
+		}
+	}
+}
+
+// flush delivers the job messages still queued, then closes every client so
+// their connections send what they have and close
+func (h *Hub) flush() {
+	for drained := false; !drained; {
+		select {
+		case message := <-h.jobMessages:
+			h.sendToJob(message)
+		default:
+			drained = true
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for client := range h.clients {
+		h.removeClient(client)
+	}
+	for _, subscribers := range h.jobClients {
+		for client := range subscribers {
+			h.removeClient(client)
 		}
 	}
 }
 
+// join registers a client with the running hub, returning false once the hub
+// has stopped
+func (h *Hub) join(client *Client) bool {
+	select {
+	case h.register <- client:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// leave unregisters a client, unless the hub has stopped and closed it already
+func (h *Hub) leave(client *Client) {
+	select {
+	case h.unregister <- client:
+	case <-h.done:
+	}
+}
+
+// Shutdown stops the hub once the queued job messages are delivered, closing
+// every client's connection. It returns when the hub has stopped or ctx is
+// done. New connections are refused from then on.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.quitOnce.Do(func() { close(h.quit) })
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// removeClient drops a client and closes its send channel. The caller must hold the lock.
+func (h *Hub) removeClient(client *Client) {
+	if client.jobID != "" {
+		subscribers := h.jobClients[client.jobID]
+		if _, ok := subscribers[client]; !ok {
+			return
+		}
+		delete(subscribers, client)
+		if len(subscribers) == 0 {
+			delete(h.jobClients, client.jobID)
+		}
+	} else {
+		if _, ok := h.clients[client]; !ok {
+			return
+		}
+		delete(h.clients, client)
+	}
+	close(client.send)
+}
+
+// Listener receives a broadcast message before it is sent to clients
+type Listener func(messageType string, data interface{})
+
+// AddListener registers a listener for every broadcast message
+func (h *Hub) AddListener(listener Listener) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.listeners = append(h.listeners, listener)
+}
+
 // BroadcastMessage sends a message to all connected clients
 func (h *Hub) BroadcastMessage(messageType string, data interface{}) {
+	h.mutex.RLock()
+	listeners := h.listeners
+	h.mutex.RUnlock()
+	for _, listener := range listeners {
+		listener(messageType, data)
+	}
+
 	message := Message{
 		Type: messageType,
 		Data: data,
@@ -91,11 +253,46 @@ func (h *Hub) BroadcastMessage(messageType string, data interface{}) {
 	}
 }
 
+// SendToJob sends a message to the clients subscribed to one optimization
+// job. Progress messages are throttled to each subscriber's interval; other
+// events, such as completion, are always delivered.
+func (h *Hub) SendToJob(jobID string, messageType string, data interface{}) {
+	jsonData, err := json.Marshal(Message{
+		Type: messageType,
+		Data: data,
+	})
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return
+	}
+
+	select {
+	case h.jobMessages <- jobMessage{jobID: jobID, data: jsonData, throttled: messageType == OptimizationProgress}:
+	default:
+		log.Printf("Job channel full, dropping message for job %s", jobID)
+	}
+}
+
+// SetProgressInterval sets how often job subscribers receive progress
+// messages when they don't choose an interval themselves
+func (h *Hub) SetProgressInterval(interval time.Duration) {
+	if interval < MinProgressInterval {
+		interval = MinProgressInterval
+	}
+	h.mutex.Lock()
+	h.progressInterval = interval
+	h.mutex.Unlock()
+}
+
 // GetClientCount returns the number of connected clients
 func (h *Hub) GetClientCount() int {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
-	return len(h.clients)
+	count := len(h.clients)
+	for _, subscribers := range h.jobClients {
+		count += len(subscribers)
+	}
+	return count
 }
 
 // Message represents a WebSocket message
@@ -128,10 +325,58 @@ func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request) {
 		send: make(chan []byte, 256),
 	}
 
-	client.hub.register <- client
+	if !client.hub.join(client) {
+		conn.Close()
+		return
+	}
 
 	// Allow collection of memory referenced by the caller by doing all work in
 	// new goroutines.
 	go client.writePump()
 	go client.readPump()
-}
\ No newline at end of file
+}
+// ServeJobWS subscribes a websocket connection to one optimization job's
+// progress and completion events. An interval of zero uses the hub's default
+// progress interval. Initial messages, such as the job's current state, are
+// sent before any events.
+func (h *Hub) ServeJobWS(w http.ResponseWriter, r *http.Request, jobID string, interval time.Duration, initial ...Message) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+
+	if interval == 0 {
+		h.mutex.RLock()
+		interval = h.progressInterval
+		h.mutex.RUnlock()
+	}
+	if interval < MinProgressInterval {
+		interval = MinProgressInterval
+	}
+
+	client := &Client{
+		hub:              h,
+		conn:             conn,
+		send:             make(chan []byte, 256),
+		jobID:            jobID,
+		progressInterval: interval,
+	}
+
+	for _, message := range initial {
+		jsonData, err := json.Marshal(message)
+		if err != nil {
+			log.Printf("Error marshaling message: %v", err)
+			continue
+		}
+		client.send <- jsonData
+	}
+
+	if !client.hub.join(client) {
+		conn.Close()
+		return
+	}
+
+	go client.writePump()
+	go client.readPump()
+}
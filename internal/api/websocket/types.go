@@ -29,6 +29,11 @@ const (
 	MatchCreated = "match_created"
 	MatchDeleted = "match_deleted"
 
+	// Approval events
+	ApprovalRequested = "approval_requested"
+	ApprovalRecorded  = "approval_recorded"
+	DrawPublished     = "draw_published"
+
 	// Constraint events
 	ConstraintViolation = "constraint_violation"
 	ConstraintsValidated = "constraints_validated"
@@ -55,8 +60,11 @@ type OptimizationProgressData struct {
 	CurrentScore    float64   `json:"current_score"`
 	BestScore       float64   `json:"best_score"`
 	Temperature     float64   `json:"temperature"`
+	AcceptanceRate  float64   `json:"acceptance_rate"`
 	Progress        float64   `json:"progress"`
 	EstimatedTimeRemaining time.Duration `json:"estimated_time_remaining,omitempty"`
+	WorstTeams      []constraints.ConstraintTeamScores `json:"worst_teams,omitempty"`
+	ConstraintScores []constraints.ConstraintScore `json:"constraint_scores,omitempty"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
@@ -69,6 +77,7 @@ type OptimizationCompletedData struct {
 	FinalScore    float64   `json:"final_score"`
 	Iterations    int       `json:"iterations"`
 	Improvements  int       `json:"improvements"`
+	TerminationReason string `json:"termination_reason,omitempty"`
 }
 
 // OptimizationFailedData represents the data for optimization failed events
@@ -102,6 +111,14 @@ type MatchEventData struct {
 	UserID    string        `json:"user_id,omitempty"`
 }
 
+// ApprovalEventData represents the data for approval state changes
+type ApprovalEventData struct {
+	DrawID         int                `json:"draw_id"`
+	Approvals      []*models.Approval `json:"approvals"`
+	ReadyToPublish bool               `json:"ready_to_publish"`
+	Timestamp      time.Time          `json:"timestamp"`
+}
+
 // ConstraintViolationData represents the data for constraint violation events
 type ConstraintViolationData struct {
 	DrawID      int                               `json:"draw_id"`
@@ -0,0 +1,329 @@
+package approval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// Errors returned by the approval workflow
+var (
+	ErrDrawNotReady        = errors.New("draw must be generated before approvals can be requested")
+	ErrInvalidRole         = errors.New("invalid approval role")
+	ErrNoPendingApproval   = errors.New("no approval has been requested for this role")
+	ErrStaleApproval       = errors.New("draw has changed since approvals were requested")
+	ErrApprovalsIncomplete = errors.New("draw has not been approved by every required role")
+)
+
+// Summary describes where a draw is in the approval workflow
+type Summary struct {
+	DrawID           int                   `json:"draw_id"`
+	CurrentVersion   string                `json:"current_version"`
+	RequestedVersion string                `json:"requested_version,omitempty"`
+	Stale            bool                  `json:"stale"`
+	Approvals        []*models.Approval    `json:"approvals"`
+	Pending          []models.ApprovalRole `json:"pending"`
+	Rejected         []models.ApprovalRole `json:"rejected"`
+	ReadyToPublish   bool                  `json:"ready_to_publish"`
+	PublishedAt      *time.Time            `json:"published_at,omitempty"`
+	PublishedVersion string                `json:"published_version,omitempty"`
+}
+
+// Service manages stakeholder sign-off and publication of draws
+type Service struct {
+	repository storage.Repositories
+}
+
+// NewService creates a new approval service
+func NewService(repository storage.Repositories) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// RequestApprovals asks each role to sign off the draw's current version.
+// Any earlier requests are superseded. With no roles the defaults are used.
+func (s *Service) RequestApprovals(ctx context.Context, drawID int, roles []models.ApprovalRole, requestedBy string) (*Summary, error) {
+	if len(roles) == 0 {
+		roles = models.DefaultApprovalRoles()
+	}
+	for _, role := range roles {
+		if !role.IsValid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+		}
+	}
+
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draw, err := tx.Draws().GetWithMatches(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	if draw.Status == models.DrawStatusDraft || draw.Status == models.DrawStatusOptimizing || len(draw.Matches) == 0 {
+		return nil, ErrDrawNotReady
+	}
+
+	existing, err := tx.Approvals().ListByDraw(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	for _, approval := range existing {
+		if !approval.IsCurrent() {
+			continue
+		}
+		approval.Status = models.ApprovalStatusSuperseded
+		if err := tx.Approvals().Update(ctx, approval); err != nil {
+			return nil, err
+		}
+	}
+
+	version := DrawVersion(draw)
+	seen := make(map[models.ApprovalRole]bool)
+	for _, role := range roles {
+		if seen[role] {
+			continue
+		}
+		seen[role] = true
+
+		approval := &models.Approval{
+			DrawID:      drawID,
+			Role:        role,
+			Status:      models.ApprovalStatusPending,
+			DrawVersion: version,
+			RequestedBy: requestedBy,
+		}
+		if err := tx.Approvals().Create(ctx, approval); err != nil {
+			return nil, err
+		}
+	}
+
+	summary, err := s.summarize(ctx, tx, draw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit approval request: %w", err)
+	}
+
+	return summary, nil
+}
+
+// RecordDecision records a role's approval or rejection of the requested draw version
+func (s *Service) RecordDecision(ctx context.Context, drawID int, role models.ApprovalRole, approver string, approved bool, comment string) (*models.Approval, error) {
+	if !role.IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+	}
+
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draw, err := tx.Draws().GetWithMatches(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals, err := tx.Approvals().ListByDraw(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *models.Approval
+	for _, approval := range approvals {
+		if approval.IsCurrent() && approval.Role == role {
+			target = approval
+		}
+	}
+	if target == nil {
+		return nil, ErrNoPendingApproval
+	}
+	if target.DrawVersion != DrawVersion(draw) {
+		return nil, ErrStaleApproval
+	}
+
+	now := time.Now()
+	target.Status = models.ApprovalStatusRejected
+	if approved {
+		target.Status = models.ApprovalStatusApproved
+	}
+	target.Approver = approver
+	target.Comment = comment
+	target.DecidedAt = &now
+
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	if err := tx.Approvals().Update(ctx, target); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit approval decision: %w", err)
+	}
+
+	return target, nil
+}
+
+// GetSummary returns the approval state of a draw
+func (s *Service) GetSummary(ctx context.Context, drawID int) (*Summary, error) {
+	draw, err := s.repository.Draws().GetWithMatches(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(ctx, s.repository, draw)
+}
+
+// Publish marks the draw as published once every requested role has approved its current version
+func (s *Service) Publish(ctx context.Context, drawID int) (*models.Draw, error) {
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draw, err := tx.Draws().GetWithMatches(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.summarize(ctx, tx, draw)
+	if err != nil {
+		return nil, err
+	}
+	if !summary.ReadyToPublish {
+		return nil, describeIncomplete(summary)
+	}
+
+	now := time.Now()
+	draw.PublishedAt = &now
+	draw.PublishedVersion = summary.CurrentVersion
+	if err := tx.Draws().Update(ctx, draw); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit publication: %w", err)
+	}
+
+	return draw, nil
+}
+
+// summarize builds the approval summary for a draw from the current approval set
+func (s *Service) summarize(ctx context.Context, repos storage.Repositories, draw *models.Draw) (*Summary, error) {
+	approvals, err := repos.Approvals().ListByDraw(ctx, draw.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		DrawID:           draw.ID,
+		CurrentVersion:   DrawVersion(draw),
+		Approvals:        []*models.Approval{},
+		Pending:          []models.ApprovalRole{},
+		Rejected:         []models.ApprovalRole{},
+		PublishedAt:      draw.PublishedAt,
+		PublishedVersion: draw.PublishedVersion,
+	}
+
+	for _, approval := range approvals {
+		if !approval.IsCurrent() {
+			continue
+		}
+		summary.Approvals = append(summary.Approvals, approval)
+		summary.RequestedVersion = approval.DrawVersion
+
+		switch approval.Status {
+		case models.ApprovalStatusPending:
+			summary.Pending = append(summary.Pending, approval.Role)
+		case models.ApprovalStatusRejected:
+			summary.Rejected = append(summary.Rejected, approval.Role)
+		}
+	}
+
+	summary.Stale = summary.RequestedVersion != "" && summary.RequestedVersion != summary.CurrentVersion
+	summary.ReadyToPublish = len(summary.Approvals) > 0 && !summary.Stale &&
+		len(summary.Pending) == 0 && len(summary.Rejected) == 0
+
+	return summary, nil
+}
+
+// describeIncomplete explains why a draw can't be published yet
+func describeIncomplete(summary *Summary) error {
+	switch {
+	case len(summary.Approvals) == 0:
+		return fmt.Errorf("%w: no approvals have been requested", ErrApprovalsIncomplete)
+	case summary.Stale:
+		return fmt.Errorf("%w: %v", ErrApprovalsIncomplete, ErrStaleApproval)
+	}
+
+	var reasons []string
+	if len(summary.Pending) > 0 {
+		reasons = append(reasons, "waiting on "+joinRoles(summary.Pending))
+	}
+	if len(summary.Rejected) > 0 {
+		reasons = append(reasons, "rejected by "+joinRoles(summary.Rejected))
+	}
+	return fmt.Errorf("%w: %s", ErrApprovalsIncomplete, strings.Join(reasons, "; "))
+}
+
+func joinRoles(roles []models.ApprovalRole) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return strings.Join(names, ", ")
+}
+
+// DrawVersion fingerprints a draw's fixture so approvals can be tied to exactly what was reviewed
+func DrawVersion(draw *models.Draw) string {
+	matches := make([]*models.Match, len(draw.Matches))
+	copy(matches, draw.Matches)
+
+	// Order by ID so the fingerprint doesn't depend on load order
+	for i := 0; i < len(matches)-1; i++ {
+		for j := 0; j < len(matches)-i-1; j++ {
+			if matches[j].ID > matches[j+1].ID {
+				matches[j], matches[j+1] = matches[j+1], matches[j]
+			}
+		}
+	}
+
+	hash := sha256.New()
+	for _, match := range matches {
+		fmt.Fprintf(hash, "%d|%d|%s|%s|%s|%s|%s|%t\n",
+			match.ID, match.Round,
+			formatIntPtr(match.HomeTeamID), formatIntPtr(match.AwayTeamID), formatIntPtr(match.VenueID),
+			formatTimePtr(match.MatchDate, "2006-01-02"), formatTimePtr(match.MatchTime, "15:04"),
+			match.IsPrimeTime)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func formatIntPtr(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *value)
+}
+
+func formatTimePtr(value *time.Time, layout string) string {
+	if value == nil {
+		return "-"
+	}
+	return value.Format(layout)
+}
@@ -0,0 +1,234 @@
+package compare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// Limits on how many draws can be compared at once
+const (
+	MinDraws = 2
+	MaxDraws = 10
+)
+
+// Errors returned for invalid comparison requests
+var (
+	ErrTooFewDraws   = fmt.Errorf("at least %d draws are required for a comparison", MinDraws)
+	ErrTooManyDraws  = fmt.Errorf("at most %d draws can be compared at once", MaxDraws)
+	ErrDuplicateDraw = errors.New("each draw can only be compared once")
+	ErrInvalidConfig = errors.New("invalid constraint configuration")
+)
+
+// ConstraintScore is how well a draw satisfies one constraint
+type ConstraintScore struct {
+	Name   string  `json:"name"`
+	Type   string  `json:"type"` // "hard" or "soft"
+	Weight float64 `json:"weight,omitempty"`
+	Score  float64 `json:"score"`
+}
+
+// DrawReport is one draw's column in a comparison
+type DrawReport struct {
+	DrawID         int                             `json:"draw_id"`
+	Name           string                          `json:"name"`
+	SeasonYear     int                             `json:"season_year"`
+	Status         models.DrawStatus               `json:"status"`
+	MatchCount     int                             `json:"match_count"`
+	OverallScore   float64                         `json:"overall_score"`
+	HardViolations int                             `json:"hard_violations"`
+	Constraints    []ConstraintScore               `json:"constraints"`
+	Travel         constraints.TravelStatistics    `json:"travel"`
+	HomeAway       constraints.HomeAwayStatistics  `json:"home_away"`
+	PrimeTime      constraints.PrimeTimeStatistics `json:"prime_time"`
+	// ExpectedAttendance is the estimated season crowd, set when the draw is
+	// scored with an expected crowd constraint
+	ExpectedAttendance float64 `json:"expected_attendance,omitempty"`
+}
+
+// Comparison is a side-by-side report of several draws
+type Comparison struct {
+	Draws []DrawReport `json:"draws"`
+	// BestDrawID is the draw with the highest overall score, 0 when none is valid
+	BestDrawID int `json:"best_draw_id"`
+	// SharedConfig is true when every draw was scored with the same configuration
+	SharedConfig bool      `json:"shared_config"`
+	ComparedAt   time.Time `json:"compared_at"`
+}
+
+// Service builds comparison reports across draws
+type Service struct {
+	repository storage.Repositories
+}
+
+// NewService creates a new comparison service
+func NewService(repository storage.Repositories) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// Compare scores each draw and gathers its travel, home/away and prime-time
+// statistics. Draws are scored against config when given, so they are judged
+// by the same rules; otherwise each draw uses its own stored configuration.
+func (s *Service) Compare(ctx context.Context, drawIDs []int, config *constraints.ConstraintConfig) (*Comparison, error) {
+	if len(drawIDs) < MinDraws {
+		return nil, ErrTooFewDraws
+	}
+	if len(drawIDs) > MaxDraws {
+		return nil, ErrTooManyDraws
+	}
+	seen := make(map[int]bool, len(drawIDs))
+	for _, id := range drawIDs {
+		if seen[id] {
+			return nil, ErrDuplicateDraw
+		}
+		seen[id] = true
+	}
+
+	league, err := constraints.LoadLeagueData(ctx, s.repository.Teams(), s.repository.Venues())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load teams and venues: %w", err)
+	}
+
+	var sharedEngine *constraints.ConstraintEngine
+	if config != nil {
+		sharedEngine, err = constraints.NewConstraintFactory().CreateConstraintEngine(*config)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+		sharedEngine.SetLeagueData(league)
+		if err := sharedEngine.LoadOtherDraws(ctx, s.repository.Draws()); err != nil {
+			return nil, err
+		}
+	}
+
+	comparison := &Comparison{
+		Draws:        make([]DrawReport, 0, len(drawIDs)),
+		SharedConfig: config != nil,
+		ComparedAt:   time.Now(),
+	}
+
+	bestScore := 0.0
+	for _, id := range drawIDs {
+		draw, err := s.repository.Draws().GetWithMatches(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("draw %d: %w", id, err)
+		}
+
+		engine := sharedEngine
+		if engine == nil {
+			engine, err = constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+			if err != nil {
+				return nil, fmt.Errorf("draw %d: %w", id, err)
+			}
+			engine.SetLeagueData(league)
+			if err := engine.LoadOtherDraws(ctx, s.repository.Draws()); err != nil {
+				return nil, fmt.Errorf("draw %d: %w", id, err)
+			}
+		}
+
+		report := buildReport(draw, engine, league)
+		if report.HardViolations == 0 && report.OverallScore > bestScore {
+			bestScore = report.OverallScore
+			comparison.BestDrawID = draw.ID
+		}
+		comparison.Draws = append(comparison.Draws, report)
+	}
+
+	return comparison, nil
+}
+
+// buildReport scores a draw against an engine and gathers its statistics
+func buildReport(draw *models.Draw, engine *constraints.ConstraintEngine, league *constraints.LeagueData) DrawReport {
+	report := DrawReport{
+		DrawID:         draw.ID,
+		Name:           draw.Name,
+		SeasonYear:     draw.SeasonYear,
+		Status:         draw.Status,
+		MatchCount:     len(draw.Matches),
+		OverallScore:   engine.ScoreDraw(draw),
+		HardViolations: len(engine.ValidateDraw(draw)),
+		Constraints:    []ConstraintScore{},
+	}
+
+	for _, constraint := range engine.GetHardConstraints() {
+		report.Constraints = append(report.Constraints, ConstraintScore{
+			Name:  constraint.Name(),
+			Type:  "hard",
+			Score: constraint.Score(draw),
+		})
+	}
+	for _, weighted := range engine.GetSoftConstraints() {
+		report.Constraints = append(report.Constraints, ConstraintScore{
+			Name:   weighted.Constraint.Name(),
+			Type:   "soft",
+			Weight: weighted.Weight,
+			Score:  weighted.Constraint.Score(draw),
+		})
+	}
+
+	travel, homeAway, primeTime := statisticsConstraints(engine)
+	travel.SetLeagueData(league)
+	report.Travel = travel.GetDrawTravelStatistics(draw)
+	report.HomeAway = homeAway.GetDrawBalanceStatistics(draw)
+	report.PrimeTime = primeTime.GetDrawPrimeTimeStatistics(draw)
+	
+	for _, weighted := range engine.GetSoftConstraints() {
+		if crowd, ok := weighted.Constraint.(*constraints.ExpectedCrowdConstraint); ok {
+			report.ExpectedAttendance = crowd.TotalAttendance(draw)
+			break
+		}
+	}
+
+	return report
+}
+
+// statisticsConstraints returns the constraints whose analysis feeds the
+// report's statistics. The engine's own are used so limits match the draw's
+// configuration; missing ones fall back to the default NRL settings.
+func statisticsConstraints(engine *constraints.ConstraintEngine) (*constraints.TravelMinimizationConstraint, *constraints.HomeAwayBalanceConstraint, *constraints.PrimeTimeSpreadConstraint) {
+	var travel *constraints.TravelMinimizationConstraint
+	var homeAway *constraints.HomeAwayBalanceConstraint
+	var primeTime *constraints.PrimeTimeSpreadConstraint
+
+	for _, weighted := range engine.GetSoftConstraints() {
+		switch c := weighted.Constraint.(type) {
+		case *constraints.TravelMinimizationConstraint:
+			if travel == nil {
+				travel = c
+			}
+		case *constraints.HomeAwayBalanceConstraint:
+			if homeAway == nil {
+				homeAway = c
+			}
+		case *constraints.PrimeTimeSpreadConstraint:
+			if primeTime == nil {
+				primeTime = c
+			}
+		}
+	}
+
+	if travel == nil || homeAway == nil || primeTime == nil {
+		defaults, err := constraints.NewConstraintEngineFromJSON(nil)
+		if err == nil {
+			defaultTravel, defaultHomeAway, defaultPrimeTime := statisticsConstraints(defaults)
+			if travel == nil {
+				travel = defaultTravel
+			}
+			if homeAway == nil {
+				homeAway = defaultHomeAway
+			}
+			if primeTime == nil {
+				primeTime = defaultPrimeTime
+			}
+		}
+	}
+
+	return travel, homeAway, primeTime
+}
@@ -0,0 +1,381 @@
+package constraints
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// NoQuotaMaximum marks an unbounded maximum for BroadcasterQuotaConstraint
+const NoQuotaMaximum = -1
+
+// TimeslotCategory groups kickoffs a broadcaster holds rights to, such as
+// free-to-air Thursday night. A match is in the category when it is dated on
+// one of the days and kicks off in the window.
+type TimeslotCategory struct {
+	Broadcaster string
+	Days        []time.Weekday
+	// From and To bound the kickoff time of day, From inclusive and To
+	// exclusive. Either may be nil to leave that side open.
+	From *time.Time
+	To   *time.Time
+	// PrimeTimeOnly limits the category to matches marked prime time
+	PrimeTimeOnly bool
+}
+
+// Contains reports whether a match falls in the category. Undated matches and
+// matches with a kickoff window but no kickoff time are never in it.
+func (tc TimeslotCategory) Contains(match *models.Match) bool {
+	if match.IsBye() || match.MatchDate == nil {
+		return false
+	}
+	if tc.PrimeTimeOnly && !match.IsPrimeTime {
+		return false
+	}
+
+	if len(tc.Days) > 0 {
+		onDay := false
+		for _, day := range tc.Days {
+			if match.MatchDate.Weekday() == day {
+				onDay = true
+				break
+			}
+		}
+		if !onDay {
+			return false
+		}
+	}
+
+	if tc.From == nil && tc.To == nil {
+		return true
+	}
+	if match.MatchTime == nil {
+		return false
+	}
+	kickoff := minuteOfDay(*match.MatchTime)
+	if tc.From != nil && kickoff < minuteOfDay(*tc.From) {
+		return false
+	}
+	if tc.To != nil && kickoff >= minuteOfDay(*tc.To) {
+		return false
+	}
+	return true
+}
+
+// String describes the category, e.g. "Nine Thursday 19:00-21:00"
+func (tc TimeslotCategory) String() string {
+	var parts []string
+	if tc.Broadcaster != "" {
+		parts = append(parts, tc.Broadcaster)
+	}
+	for _, day := range tc.Days {
+		parts = append(parts, day.String())
+	}
+	if tc.From != nil || tc.To != nil {
+		window := ""
+		if tc.From != nil {
+			window = tc.From.Format("15:04")
+		}
+		window += "-"
+		if tc.To != nil {
+			window += tc.To.Format("15:04")
+		}
+		parts = append(parts, window)
+	}
+	if tc.PrimeTimeOnly {
+		parts = append(parts, "prime time")
+	}
+	if len(parts) == 0 {
+		return "any timeslot"
+	}
+	return strings.Join(parts, " ")
+}
+
+// BroadcasterQuotaConstraint bounds how often each team appears in a timeslot
+// category across the season. As a hard constraint every team must be within
+// the bounds; as a soft constraint the draw scores better the closer each team
+// is to them.
+//
+// The constraint reads match dates and kickoff times, so it only bites once a
+// draw has been dated. The slot assigner scores through the constraint engine,
+// so quotas configured here steer which slots teams are given.
+type BroadcasterQuotaConstraint struct {
+	BaseConstraint
+	category       TimeslotCategory
+	minAppearances int // Minimum appearances in the category per team
+	maxAppearances int // Maximum appearances in the category per team (NoQuotaMaximum for unbounded)
+}
+
+// NewBroadcasterQuotaConstraint creates a new broadcaster quota constraint
+func NewBroadcasterQuotaConstraint(category TimeslotCategory, minAppearances, maxAppearances int, isHard bool) *BroadcasterQuotaConstraint {
+	description := fmt.Sprintf("Each team should appear in %s between a minimum and maximum number of times", category)
+	if isHard {
+		description = fmt.Sprintf("Each team must appear in %s between a minimum and maximum number of times", category)
+	}
+
+	return &BroadcasterQuotaConstraint{
+		BaseConstraint: NewBaseConstraint("BroadcasterQuota", description, isHard),
+		category:       category,
+		minAppearances: minAppearances,
+		maxAppearances: maxAppearances,
+	}
+}
+
+// Validate checks if a match in the category pushes either team over its maximum
+func (bqc *BroadcasterQuotaConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	return bqc.ValidateIndexed(match, NewDrawIndex(draw))
+}
+
+// ValidateIndexed checks a match in the category against the maximum using a
+// shared index
+func (bqc *BroadcasterQuotaConstraint) ValidateIndexed(match *models.Match, index *DrawIndex) error {
+	if !bqc.IsHard() || bqc.maxAppearances == NoQuotaMaximum || !bqc.category.Contains(match) {
+		return nil
+	}
+
+	for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+		if teamID == nil {
+			continue
+		}
+
+		// Only the appearances beyond the maximum are flagged, earliest rounds are kept
+		if bqc.position(index, *teamID, match) >= bqc.maxAppearances {
+			return fmt.Errorf("team %d exceeds maximum of %d appearances in %s in round %d",
+				*teamID, bqc.maxAppearances, bqc.category, match.Round)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDraw reports teams below the minimum, which no single match shows.
+// Teams with no dated matches haven't been given timeslots yet and are skipped.
+func (bqc *BroadcasterQuotaConstraint) ValidateDraw(draw *models.Draw) []error {
+	return bqc.ValidateDrawIndexed(NewDrawIndex(draw))
+}
+
+// ValidateDrawIndexed reports teams below the minimum using a shared index
+func (bqc *BroadcasterQuotaConstraint) ValidateDrawIndexed(index *DrawIndex) []error {
+	if !bqc.IsHard() {
+		return nil
+	}
+
+	var errors []error
+	for _, analysis := range bqc.analyzeTeams(index) {
+		if analysis.Status == "UNDER" {
+			errors = append(errors, newDrawViolation([]int{analysis.TeamID}, nil,
+				"team %d has %d appearances in %s, minimum is %d",
+				analysis.TeamID, analysis.Appearances, bqc.category, bqc.minAppearances))
+		}
+	}
+
+	return errors
+}
+
+// Score returns the average per-team quota score
+func (bqc *BroadcasterQuotaConstraint) Score(draw *models.Draw) float64 {
+	return bqc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed returns the average per-team quota score using a shared index
+func (bqc *BroadcasterQuotaConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	scores := bqc.TeamScoresIndexed(index)
+	if len(scores) == 0 {
+		return 1.0
+	}
+
+	total := 0.0
+	for _, score := range scores {
+		total += score
+	}
+	return total / float64(len(scores))
+}
+
+// TeamScores returns each team's quota score
+func (bqc *BroadcasterQuotaConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	return bqc.TeamScoresIndexed(NewDrawIndex(draw))
+}
+
+// TeamScoresIndexed returns each team's quota score using a shared index
+func (bqc *BroadcasterQuotaConstraint) TeamScoresIndexed(index *DrawIndex) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, teamID := range index.Teams() {
+		scores[teamID] = bqc.ScoreTeamIndexed(index, teamID)
+	}
+	return scores
+}
+
+// ScoreTeam returns 1.0 for a team within the quota, falling towards 0.0 the
+// further outside it the team is
+func (bqc *BroadcasterQuotaConstraint) ScoreTeam(draw *models.Draw, teamID int) float64 {
+	return bqc.ScoreTeamIndexed(NewDrawIndex(draw), teamID)
+}
+
+// ScoreTeamIndexed returns one team's quota score using a shared index
+func (bqc *BroadcasterQuotaConstraint) ScoreTeamIndexed(index *DrawIndex, teamID int) float64 {
+	appearances, dated := bqc.countTeam(index, teamID)
+	if !dated {
+		return 1.0
+	}
+
+	var miss, bound int
+	switch {
+	case appearances < bqc.minAppearances:
+		miss, bound = bqc.minAppearances-appearances, bqc.minAppearances
+	case bqc.maxAppearances != NoQuotaMaximum && appearances > bqc.maxAppearances:
+		miss, bound = appearances-bqc.maxAppearances, bqc.maxAppearances
+	default:
+		return 1.0
+	}
+
+	if bound < 1 {
+		bound = 1
+	}
+	if miss >= bound {
+		return 0.0
+	}
+	return 1.0 - float64(miss)/float64(bound)
+}
+
+// position returns the zero-based position of a match among a team's matches in the category
+func (bqc *BroadcasterQuotaConstraint) position(index *DrawIndex, teamID int, target *models.Match) int {
+	position := 0
+	for _, match := range index.TeamMatches(teamID) {
+		if match == target || !bqc.category.Contains(match) {
+			continue
+		}
+		if match.Round < target.Round || (match.Round == target.Round && match.ID < target.ID) {
+			position++
+		}
+	}
+	return position
+}
+
+// countTeam returns a team's appearances in the category and whether any of
+// its matches have been dated
+func (bqc *BroadcasterQuotaConstraint) countTeam(index *DrawIndex, teamID int) (int, bool) {
+	appearances := 0
+	dated := false
+	for _, match := range index.TeamMatches(teamID) {
+		if match.IsBye() || match.MatchDate == nil {
+			continue
+		}
+		dated = true
+		if bqc.category.Contains(match) {
+			appearances++
+		}
+	}
+	return appearances, dated
+}
+
+// GetCategory returns the timeslot category the quota applies to
+func (bqc *BroadcasterQuotaConstraint) GetCategory() TimeslotCategory {
+	return bqc.category
+}
+
+// GetMinAppearances returns the minimum appearances per team
+func (bqc *BroadcasterQuotaConstraint) GetMinAppearances() int {
+	return bqc.minAppearances
+}
+
+// GetMaxAppearances returns the maximum appearances per team
+func (bqc *BroadcasterQuotaConstraint) GetMaxAppearances() int {
+	return bqc.maxAppearances
+}
+
+// AnalyzeTeams returns every team's appearances in the category against the
+// quota, sorted by team ID
+func (bqc *BroadcasterQuotaConstraint) AnalyzeTeams(draw *models.Draw) []BroadcasterQuotaAnalysis {
+	return bqc.analyzeTeams(NewDrawIndex(draw))
+}
+
+// analyzeTeams analyzes every team's appearances using a shared index. The
+// index lists teams in order, so the analyses are sorted by team ID.
+func (bqc *BroadcasterQuotaConstraint) analyzeTeams(index *DrawIndex) []BroadcasterQuotaAnalysis {
+	var analyses []BroadcasterQuotaAnalysis
+
+	for _, teamID := range index.Teams() {
+		appearances, dated := bqc.countTeam(index, teamID)
+
+		status := "WITHIN"
+		switch {
+		case !dated:
+			status = "UNSCHEDULED"
+		case appearances < bqc.minAppearances:
+			status = "UNDER"
+		case bqc.maxAppearances != NoQuotaMaximum && appearances > bqc.maxAppearances:
+			status = "OVER"
+		}
+
+		analyses = append(analyses, BroadcasterQuotaAnalysis{
+			TeamID:         teamID,
+			Category:       bqc.category.String(),
+			Appearances:    appearances,
+			MinAppearances: bqc.minAppearances,
+			MaxAppearances: bqc.maxAppearances,
+			Status:         status,
+		})
+	}
+
+	return analyses
+}
+
+// BroadcasterQuotaAnalysis describes one team's appearances in a timeslot category
+type BroadcasterQuotaAnalysis struct {
+	TeamID         int    `json:"team_id"`
+	Category       string `json:"category"`
+	Appearances    int    `json:"appearances"`
+	MinAppearances int    `json:"min_appearances"`
+	MaxAppearances int    `json:"max_appearances"`
+	Status         string `json:"status"` // "WITHIN", "UNDER", "OVER" or "UNSCHEDULED"
+}
+
+// BroadcasterQuotaReport lists every team's standing against one broadcaster quota
+type BroadcasterQuotaReport struct {
+	Category string                     `json:"category"`
+	Hard     bool                       `json:"hard"`
+	Teams    []BroadcasterQuotaAnalysis `json:"teams"`
+}
+
+// AnalyzeBroadcasterQuotas reports each team against every broadcaster quota
+// in the engine, hard quotas first
+func (ce *ConstraintEngine) AnalyzeBroadcasterQuotas(draw *models.Draw) []BroadcasterQuotaReport {
+	reports := []BroadcasterQuotaReport{}
+
+	quotas := append([]Constraint{}, ce.hardConstraints...)
+	for _, weighted := range ce.softConstraints {
+		quotas = append(quotas, weighted.Constraint)
+	}
+	for _, constraint := range quotas {
+		quota, ok := constraint.(*BroadcasterQuotaConstraint)
+		if !ok {
+			continue
+		}
+		reports = append(reports, BroadcasterQuotaReport{
+			Category: quota.category.String(),
+			Hard:     quota.IsHard(),
+			Teams:    quota.AnalyzeTeams(draw),
+		})
+	}
+
+	return reports
+}
+
+// ParseWeekday reads a day name such as "friday" or "Fri"
+func ParseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || (len(name) == 3 && name == full[:3]) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown day %q", name)
+}
+
+// minuteOfDay returns the minutes past midnight of a kickoff time
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
@@ -6,7 +6,8 @@ import (
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 )
 
-// ByeConstraint ensures each team gets exactly one bye per full round-robin
+// ByeConstraint ensures each team gets exactly one bye per full round-robin.
+// Byes are worked out from the fixtures; a draw's stored byes must agree.
 type ByeConstraint struct {
 	BaseConstraint
 }
@@ -31,193 +32,156 @@ func (bc *ByeConstraint) Validate(match *models.Match, draw *models.Draw) error
 
 // Score calculates how well the draw satisfies the bye constraint
 func (bc *ByeConstraint) Score(draw *models.Draw) float64 {
-	// Get unique teams in the draw
-	teamIDs := bc.getUniqueTeams(draw)
+	return bc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed scores the bye distribution using a shared index
+func (bc *ByeConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teamIDs := index.Teams()
 	if len(teamIDs) == 0 {
 		return 1.0
 	}
-	
+
 	// Calculate expected byes per team based on total rounds and team count
 	totalTeams := len(teamIDs)
-	
+
 	// If even number of teams, no byes needed
 	if totalTeams%2 == 0 {
 		// Check that no team has any byes
 		for _, teamID := range teamIDs {
-			if bc.countByesForTeam(draw, teamID) > 0 {
+			if len(index.ByeRounds(teamID)) > 0 {
 				return 0.0
 			}
 		}
 		return 1.0
 	}
-	
+
 	// For odd number of teams, each team should have equal byes
 	// In a single round-robin, each team should have exactly 1 bye
-	expectedByesPerTeam := 1
-	if draw.Rounds > totalTeams-1 {
-		// For multiple round-robins, calculate expected byes
-		fullRoundRobins := draw.Rounds / (totalTeams - 1)
-		expectedByesPerTeam = fullRoundRobins
-	}
-	
+	expectedByesPerTeam := bc.expectedByesPerTeam(index.Draw(), totalTeams)
+
 	correctByeCount := 0
 	for _, teamID := range teamIDs {
-		actualByes := bc.countByesForTeam(draw, teamID)
-		if actualByes == expectedByesPerTeam {
+		if len(index.ByeRounds(teamID)) == expectedByesPerTeam {
 			correctByeCount++
 		}
 	}
-	
+
 	return float64(correctByeCount) / float64(totalTeams)
 }
 
 // ValidateDrawByes performs comprehensive bye validation for the entire draw
 func (bc *ByeConstraint) ValidateDrawByes(draw *models.Draw) error {
-	teamIDs := bc.getUniqueTeams(draw)
+	index := NewDrawIndex(draw)
+	teamIDs := index.Teams()
 	if len(teamIDs) == 0 {
 		return fmt.Errorf("no teams found in draw")
 	}
-	
+
+	if err := bc.validateStoredByes(draw, index); err != nil {
+		return err
+	}
+
 	totalTeams := len(teamIDs)
-	
+
 	// If even number of teams, no byes should exist
 	if totalTeams%2 == 0 {
 		for _, teamID := range teamIDs {
-			byeCount := bc.countByesForTeam(draw, teamID)
+			byeCount := len(index.ByeRounds(teamID))
 			if byeCount > 0 {
-				return fmt.Errorf("team %d has %d byes but none expected with %d teams", 
+				return fmt.Errorf("team %d has %d byes but none expected with %d teams",
 					teamID, byeCount, totalTeams)
 			}
 		}
 		return nil
 	}
-	
+
 	// For odd number of teams, validate bye distribution
-	expectedByesPerTeam := 1
-	if draw.Rounds > totalTeams-1 {
-		fullRoundRobins := draw.Rounds / (totalTeams - 1)
-		expectedByesPerTeam = fullRoundRobins
-	}
-	
+	expectedByesPerTeam := bc.expectedByesPerTeam(draw, totalTeams)
+
 	for _, teamID := range teamIDs {
-		actualByes := bc.countByesForTeam(draw, teamID)
+		actualByes := len(index.ByeRounds(teamID))
 		if actualByes != expectedByesPerTeam {
-			return fmt.Errorf("team %d has %d byes but expected %d", 
+			return fmt.Errorf("team %d has %d byes but expected %d",
 				teamID, actualByes, expectedByesPerTeam)
 		}
 	}
-	
+
 	// Validate bye distribution across rounds
-	return bc.validateByeDistribution(draw, teamIDs)
+	return bc.validateByeDistribution(index)
 }
 
-// getUniqueTeams extracts all unique team IDs from the draw
-func (bc *ByeConstraint) getUniqueTeams(draw *models.Draw) []int {
-	teamSet := make(map[int]bool)
-	
-	for _, match := range draw.Matches {
-		if match.HomeTeamID != nil {
-			teamSet[*match.HomeTeamID] = true
-		}
-		if match.AwayTeamID != nil {
-			teamSet[*match.AwayTeamID] = true
-		}
-	}
-	
-	var teams []int
-	for teamID := range teamSet {
-		teams = append(teams, teamID)
+// expectedByesPerTeam returns the byes each team should get with an odd
+// number of teams: one per full round-robin, and at least one
+func (bc *ByeConstraint) expectedByesPerTeam(draw *models.Draw, totalTeams int) int {
+	if draw.Rounds > totalTeams-1 {
+		return draw.Rounds / (totalTeams - 1)
 	}
-	
-	return teams
+	return 1
 }
 
-// countByesForTeam counts how many byes a specific team has
-func (bc *ByeConstraint) countByesForTeam(draw *models.Draw, teamID int) int {
-	byeCount := 0
-	
-	// Count rounds where team has no matches
-	roundsWithMatches := make(map[int]bool)
-	
-	for _, match := range draw.Matches {
-		if (match.HomeTeamID != nil && *match.HomeTeamID == teamID) ||
-			(match.AwayTeamID != nil && *match.AwayTeamID == teamID) {
-			roundsWithMatches[match.Round] = true
+// validateStoredByes checks the draw's stored byes name exactly the teams
+// left out of each round's fixtures. Draws without stored byes pass.
+func (bc *ByeConstraint) validateStoredByes(draw *models.Draw, index *DrawIndex) error {
+	if len(draw.Byes) == 0 {
+		return nil
+	}
+
+	recorded := make(map[int]map[int]bool)
+	for _, bye := range draw.Byes {
+		if bye.HomeTeamID == nil {
+			continue
+		}
+		teamID := *bye.HomeTeamID
+		if index.TeamMatchesByRound(teamID)[bye.Round] != nil {
+			return fmt.Errorf("team %d has a bye recorded in round %d but plays that round", teamID, bye.Round)
 		}
+		if recorded[teamID] == nil {
+			recorded[teamID] = make(map[int]bool)
+		}
+		recorded[teamID][bye.Round] = true
 	}
-	
-	// Count total rounds vs rounds with matches
-	for round := 1; round <= draw.Rounds; round++ {
-		if !roundsWithMatches[round] {
-			byeCount++
+
+	for _, teamID := range index.Teams() {
+		for _, round := range index.ByeRounds(teamID) {
+			if !recorded[teamID][round] {
+				return fmt.Errorf("team %d has no bye recorded in round %d", teamID, round)
+			}
 		}
 	}
-	
-	return byeCount
+	return nil
 }
 
 // validateByeDistribution ensures byes are properly distributed across rounds
-func (bc *ByeConstraint) validateByeDistribution(draw *models.Draw, teamIDs []int) error {
-	// Count byes per round
+func (bc *ByeConstraint) validateByeDistribution(index *DrawIndex) error {
+	// For odd number of teams, each round should have exactly 1 bye
+	if len(index.Teams())%2 == 0 {
+		return nil
+	}
+
 	byesPerRound := make(map[int]int)
-	
-	for round := 1; round <= draw.Rounds; round++ {
-		teamsInRound := make(map[int]bool)
-		
-		roundMatches := draw.GetMatchesByRound(round)
-		for _, match := range roundMatches {
-			if match.HomeTeamID != nil {
-				teamsInRound[*match.HomeTeamID] = true
-			}
-			if match.AwayTeamID != nil {
-				teamsInRound[*match.AwayTeamID] = true
-			}
+	for _, rounds := range index.Byes() {
+		for _, round := range rounds {
+			byesPerRound[round]++
 		}
-		
-		byesPerRound[round] = len(teamIDs) - len(teamsInRound)
 	}
-	
-	// For odd number of teams, each round should have exactly 1 bye
-	if len(teamIDs)%2 == 1 {
-		expectedByesPerRound := 1
-		for round, byeCount := range byesPerRound {
-			if byeCount != expectedByesPerRound {
-				return fmt.Errorf("round %d has %d byes but expected %d", 
-					round, byeCount, expectedByesPerRound)
-			}
+
+	expectedByesPerRound := 1
+	for round := 1; round <= index.Draw().Rounds; round++ {
+		if byesPerRound[round] != expectedByesPerRound {
+			return fmt.Errorf("round %d has %d byes but expected %d",
+				round, byesPerRound[round], expectedByesPerRound)
 		}
 	}
-	
+
 	return nil
 }
 
 // GetTeamByes returns bye information for all teams
 func (bc *ByeConstraint) GetTeamByes(draw *models.Draw) map[int][]int {
 	teamByes := make(map[int][]int)
-	teamIDs := bc.getUniqueTeams(draw)
-	
-	for _, teamID := range teamIDs {
-		var byeRounds []int
-		
-		for round := 1; round <= draw.Rounds; round++ {
-			hasMatchInRound := false
-			
-			for _, match := range draw.Matches {
-				if match.Round == round && 
-					((match.HomeTeamID != nil && *match.HomeTeamID == teamID) ||
-					 (match.AwayTeamID != nil && *match.AwayTeamID == teamID)) {
-					hasMatchInRound = true
-					break
-				}
-			}
-			
-			if !hasMatchInRound {
-				byeRounds = append(byeRounds, round)
-			}
-		}
-		
-		teamByes[teamID] = byeRounds
+	for teamID, rounds := range NewDrawIndex(draw).Byes() {
+		teamByes[teamID] = rounds
 	}
-	
 	return teamByes
-}
\ No newline at end of file
+}
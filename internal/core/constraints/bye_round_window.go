@@ -0,0 +1,161 @@
+package constraints
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// ByeRoundWindowConstraint restricts byes to a set of allowed rounds, such as
+// the rounds around State of Origin
+type ByeRoundWindowConstraint struct {
+	BaseConstraint
+	allowedRounds map[int]bool
+}
+
+// NewByeRoundWindowConstraint creates a new bye round window constraint
+func NewByeRoundWindowConstraint(allowedRounds []int) *ByeRoundWindowConstraint {
+	allowed := make(map[int]bool, len(allowedRounds))
+	for _, round := range allowedRounds {
+		allowed[round] = true
+	}
+
+	return &ByeRoundWindowConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"ByeRoundWindow",
+			fmt.Sprintf("Byes may only fall in rounds %v", sortedRounds(allowed)),
+			true, // This is a hard constraint
+		),
+		allowedRounds: allowed,
+	}
+}
+
+// Validate rejects explicit bye matches outside the window. Team byes are
+// rounds a team doesn't play in, so they are checked by ValidateDraw.
+func (brw *ByeRoundWindowConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	if match.IsBye() && !brw.allowedRounds[match.Round] {
+		return fmt.Errorf("bye in round %d is outside the allowed bye rounds %v", match.Round, brw.GetAllowedRounds())
+	}
+	return nil
+}
+
+// ValidateDraw reports every team bye that falls outside the window
+func (brw *ByeRoundWindowConstraint) ValidateDraw(draw *models.Draw) []error {
+	return brw.ValidateDrawIndexed(NewDrawIndex(draw))
+}
+
+// ValidateDrawIndexed reports every out-of-window bye using a shared index
+func (brw *ByeRoundWindowConstraint) ValidateDrawIndexed(index *DrawIndex) []error {
+	var errors []error
+	for _, bye := range brw.outOfWindowByes(index) {
+		errors = append(errors, newDrawViolation([]int{bye.TeamID}, []int{bye.Round},
+			"team %d has a bye in round %d, outside the allowed bye rounds %v",
+			bye.TeamID, bye.Round, brw.GetAllowedRounds()))
+	}
+	return errors
+}
+
+// Score returns the fraction of team byes that fall inside the window
+func (brw *ByeRoundWindowConstraint) Score(draw *models.Draw) float64 {
+	return brw.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed scores the draw's byes using a shared index
+func (brw *ByeRoundWindowConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	totalByes := 0
+	outside := 0
+	for _, rounds := range index.Byes() {
+		for _, round := range rounds {
+			totalByes++
+			if !brw.allowedRounds[round] {
+				outside++
+			}
+		}
+	}
+
+	if totalByes == 0 {
+		return 1.0
+	}
+
+	return float64(totalByes-outside) / float64(totalByes)
+}
+
+// GetAllowedRounds returns the rounds byes may fall in, in ascending order
+func (brw *ByeRoundWindowConstraint) GetAllowedRounds() []int {
+	return sortedRounds(brw.allowedRounds)
+}
+
+// IsAllowedRound reports whether byes may fall in a round
+func (brw *ByeRoundWindowConstraint) IsAllowedRound(round int) bool {
+	return brw.allowedRounds[round]
+}
+
+// GetByesByRound returns the teams with a bye in each round that has any
+func (brw *ByeRoundWindowConstraint) GetByesByRound(draw *models.Draw) map[int][]int {
+	byRound := make(map[int][]int)
+	for teamID, rounds := range NewDrawIndex(draw).Byes() {
+		for _, round := range rounds {
+			byRound[round] = append(byRound[round], teamID)
+		}
+	}
+	for round := range byRound {
+		sortInts(byRound[round])
+	}
+	return byRound
+}
+
+// GetOutOfWindowByes lists the team byes that fall outside the window, by round then team
+func (brw *ByeRoundWindowConstraint) GetOutOfWindowByes(draw *models.Draw) []ByeWindowViolation {
+	return brw.outOfWindowByes(NewDrawIndex(draw))
+}
+
+// outOfWindowByes lists the out-of-window byes using a shared index
+func (brw *ByeRoundWindowConstraint) outOfWindowByes(index *DrawIndex) []ByeWindowViolation {
+	var violations []ByeWindowViolation
+	for teamID, rounds := range index.Byes() {
+		for _, round := range rounds {
+			if !brw.allowedRounds[round] {
+				violations = append(violations, ByeWindowViolation{TeamID: teamID, Round: round})
+			}
+		}
+	}
+
+	// Earliest round first, ties by team ID so the order is stable between reports
+	for i := 0; i < len(violations)-1; i++ {
+		for j := 0; j < len(violations)-i-1; j++ {
+			if violations[j].Round > violations[j+1].Round ||
+				(violations[j].Round == violations[j+1].Round && violations[j].TeamID > violations[j+1].TeamID) {
+				violations[j], violations[j+1] = violations[j+1], violations[j]
+			}
+		}
+	}
+
+	return violations
+}
+
+// sortedRounds returns the rounds in a set in ascending order
+func sortedRounds(set map[int]bool) []int {
+	rounds := make([]int, 0, len(set))
+	for round := range set {
+		rounds = append(rounds, round)
+	}
+	sortInts(rounds)
+	return rounds
+}
+
+// sortInts sorts a slice of ints in ascending order
+func sortInts(values []int) {
+	for i := 0; i < len(values)-1; i++ {
+		for j := 0; j < len(values)-i-1; j++ {
+			if values[j] > values[j+1] {
+				values[j], values[j+1] = values[j+1], values[j]
+			}
+		}
+	}
+}
+
+// ByeWindowViolation is a team bye that falls outside the allowed bye rounds
+type ByeWindowViolation struct {
+	TeamID int `json:"team_id"`
+	Round  int `json:"round"`
+}
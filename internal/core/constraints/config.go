@@ -3,26 +3,28 @@ package constraints
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// ConstraintConfig represents the JSON configuration for all constraints
+// ConstraintConfig represents the JSON or YAML configuration for all constraints
 type ConstraintConfig struct {
-	Hard []HardConstraintConfig `json:"hard"`
-	Soft []SoftConstraintConfig `json:"soft"`
+	Hard []HardConstraintConfig `json:"hard" yaml:"hard"`
+	Soft []SoftConstraintConfig `json:"soft" yaml:"soft"`
 }
 
 // HardConstraintConfig represents configuration for hard constraints
 type HardConstraintConfig struct {
-	Type   string                 `json:"type"`
-	Params map[string]interface{} `json:"params"`
+	Type   string                 `json:"type" yaml:"type"`
+	Params map[string]interface{} `json:"params" yaml:"params"`
 }
 
 // SoftConstraintConfig represents configuration for soft constraints
 type SoftConstraintConfig struct {
-	Type   string                 `json:"type"`
-	Weight float64               `json:"weight"`
-	Params map[string]interface{} `json:"params"`
+	Type   string                 `json:"type" yaml:"type"`
+	Weight float64                `json:"weight" yaml:"weight"`
+	Params map[string]interface{} `json:"params" yaml:"params"`
 }
 
 // ConstraintFactory creates constraints from configuration
@@ -73,7 +75,46 @@ func (cf *ConstraintFactory) createHardConstraint(config HardConstraintConfig) (
 	case "double_up":
 		return cf.createDoubleUpConstraint(config.Params)
 		
+	case "prime_time_cap":
+		return cf.createPrimeTimeCapConstraint(config.Params)
+		
+	case "venue_recovery":
+		return cf.createVenueRecoveryConstraint(config.Params)
+		
+	case "bye_round_window":
+		return cf.createByeRoundWindowConstraint(config.Params)
+		
+	case "rivalry_round":
+		return cf.createRivalryRoundConstraint(config.Params, true)
+		
+	case "broadcaster_quota":
+		return cf.createBroadcasterQuotaConstraint(config.Params, true)
+		
+	case "region_spread":
+		return cf.createRegionSpreadConstraint(config.Params, true)
+		
+	case "magic_round":
+		return cf.createMagicRoundConstraint(config.Params)
+		
+	case "rest_period":
+		return cf.createRestPeriodConstraint(config.Params, true)
+		
+	case "max_short_turnarounds":
+		return cf.createShortTurnaroundCapConstraint(config.Params)
+		
+	case "custom_expression":
+		return cf.createCustomExpressionConstraint(config.Params, true)
+		
+	case "shared_venue":
+		return cf.createSharedVenueConstraint(config.Params)
+		
+	case "venue_quarantine":
+		return cf.createVenueQuarantineConstraint(config.Params)
+		
 	default:
+		if constraint, registered, err := buildRegistered(config.Type, config.Params, true); registered {
+			return constraint, err
+		}
 		return nil, fmt.Errorf("unknown hard constraint type: %s", config.Type)
 	}
 }
@@ -84,8 +125,17 @@ func (cf *ConstraintFactory) createSoftConstraint(config SoftConstraintConfig) (
 	case "travel_minimization":
 		return cf.createTravelMinimizationConstraint(config.Params)
 		
+	case "travel_fatigue":
+		return cf.createTravelFatigueConstraint(config.Params)
+		
+	case "travel_cost":
+		return cf.createTravelCostConstraint(config.Params)
+		
+	case "half_season_travel":
+		return cf.createHalfSeasonTravelConstraint(config.Params)
+		
 	case "rest_period":
-		return cf.createRestPeriodConstraint(config.Params)
+		return cf.createRestPeriodConstraint(config.Params, false)
 		
 	case "prime_time_spread":
 		return cf.createPrimeTimeSpreadConstraint(config.Params)
@@ -93,7 +143,43 @@ func (cf *ConstraintFactory) createSoftConstraint(config SoftConstraintConfig) (
 	case "home_away_balance":
 		return cf.createHomeAwayBalanceConstraint(config.Params)
 		
+	case "prime_time_attractiveness":
+		return cf.createPrimeTimeAttractivenessConstraint(config.Params)
+		
+	case "rivalry_round":
+		return cf.createRivalryRoundConstraint(config.Params, false)
+		
+	case "expected_crowd":
+		return cf.createExpectedCrowdConstraint(config.Params)
+		
+	case "broadcaster_quota":
+		return cf.createBroadcasterQuotaConstraint(config.Params, false)
+		
+	case "region_spread":
+		return cf.createRegionSpreadConstraint(config.Params, false)
+		
+	case "home_venue_share":
+		return NewHomeVenueShareConstraint(), nil
+		
+	case "derby":
+		return cf.createDerbyConstraint(config.Params)
+		
+	case "strength_spread":
+		return cf.createStrengthSpreadConstraint(config.Params)
+		
+	case "double_header":
+		return cf.createDoubleHeaderConstraint(config.Params)
+		
+	case "fixture_variation":
+		return cf.createFixtureVariationConstraint(config.Params)
+		
+	case "custom_expression":
+		return cf.createCustomExpressionConstraint(config.Params, false)
+		
 	default:
+		if constraint, registered, err := buildRegistered(config.Type, config.Params, false); registered {
+			return constraint, err
+		}
 		return nil, fmt.Errorf("unknown soft constraint type: %s", config.Type)
 	}
 }
@@ -132,6 +218,38 @@ func (cf *ConstraintFactory) createVenueAvailabilityConstraint(params map[string
 	return NewVenueAvailabilityConstraint(int(venueID), dates), nil
 }
 
+// createVenueQuarantineConstraint creates a venue quarantine constraint
+func (cf *ConstraintFactory) createVenueQuarantineConstraint(params map[string]interface{}) (Constraint, error) {
+	venueID, ok := params["venue_id"].(float64)
+	if !ok || venueID < 1 {
+		return nil, fmt.Errorf("venue_id parameter required and must be a positive number")
+	}
+	
+	roundList, ok := params["rounds"].([]interface{})
+	if !ok || len(roundList) == 0 {
+		return nil, fmt.Errorf("rounds must be a non-empty array")
+	}
+	
+	var rounds []int
+	for _, roundInterface := range roundList {
+		round, ok := roundInterface.(float64)
+		if !ok || round < 1 {
+			return nil, fmt.Errorf("each quarantined round must be a positive number")
+		}
+		rounds = append(rounds, int(round))
+	}
+	
+	reason := ""
+	if reasonInterface, exists := params["reason"]; exists {
+		reason, ok = reasonInterface.(string)
+		if !ok {
+			return nil, fmt.Errorf("reason must be a string")
+		}
+	}
+	
+	return NewVenueQuarantineConstraint(int(venueID), rounds, reason), nil
+}
+
 // createByeConstraint creates a bye constraint
 func (cf *ConstraintFactory) createByeConstraint(params map[string]interface{}) (Constraint, error) {
 	// Bye constraint doesn't need parameters
@@ -182,24 +300,597 @@ func (cf *ConstraintFactory) createDoubleUpConstraint(params map[string]interfac
 	return NewDoubleUpConstraint(int(minRounds)), nil
 }
 
-// createTravelMinimizationConstraint creates a travel minimization constraint
-func (cf *ConstraintFactory) createTravelMinimizationConstraint(params map[string]interface{}) (Constraint, error) {
-	maxConsecutive, ok := params["max_consecutive_away"].(float64)
+// createPrimeTimeCapConstraint creates a prime-time appearance cap constraint
+func (cf *ConstraintFactory) createPrimeTimeCapConstraint(params map[string]interface{}) (Constraint, error) {
+	minAppearances := 0
+	maxAppearances := NoPrimeTimeCap
+	
+	minValue, hasMin := params["min_appearances"]
+	if hasMin {
+		value, ok := minValue.(float64)
+		if !ok || value < 0 {
+			return nil, fmt.Errorf("min_appearances must be a non-negative number")
+		}
+		minAppearances = int(value)
+	}
+	
+	maxValue, hasMax := params["max_appearances"]
+	if hasMax {
+		value, ok := maxValue.(float64)
+		if !ok || value < 0 {
+			return nil, fmt.Errorf("max_appearances must be a non-negative number")
+		}
+		maxAppearances = int(value)
+	}
+	
+	if !hasMin && !hasMax {
+		return nil, fmt.Errorf("min_appearances or max_appearances parameter required")
+	}
+	
+	if maxAppearances != NoPrimeTimeCap && minAppearances > maxAppearances {
+		return nil, fmt.Errorf("min_appearances cannot exceed max_appearances")
+	}
+	
+	return NewPrimeTimeCapConstraint(minAppearances, maxAppearances), nil
+}
+
+// createVenueRecoveryConstraint creates a venue turf recovery constraint
+func (cf *ConstraintFactory) createVenueRecoveryConstraint(params map[string]interface{}) (Constraint, error) {
+	minDays, ok := params["min_recovery_days"].(float64)
+	if !ok || minDays < 0 {
+		return nil, fmt.Errorf("min_recovery_days parameter required and must be a non-negative number")
+	}
+	
+	overrides := make(map[int]int)
+	if overridesInterface, exists := params["venue_recovery_days"]; exists {
+		overridesMap, ok := overridesInterface.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("venue_recovery_days must be an object keyed by venue ID")
+		}
+		
+		for venueKey, daysInterface := range overridesMap {
+			venueID, err := strconv.Atoi(venueKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid venue ID %s in venue_recovery_days", venueKey)
+			}
+			days, ok := daysInterface.(float64)
+			if !ok || days < 0 {
+				return nil, fmt.Errorf("recovery days for venue %d must be a non-negative number", venueID)
+			}
+			overrides[venueID] = int(days)
+		}
+	}
+	
+	var events []VenueEvent
+	if eventsInterface, exists := params["external_events"]; exists {
+		eventList, ok := eventsInterface.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("external_events must be an array")
+		}
+		
+		for i, eventInterface := range eventList {
+			eventMap, ok := eventInterface.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("external event %d must be an object", i)
+			}
+			
+			venueID, ok := eventMap["venue_id"].(float64)
+			if !ok {
+				return nil, fmt.Errorf("external event %d: venue_id required and must be a number", i)
+			}
+			
+			dateStr, ok := eventMap["date"].(string)
+			if !ok {
+				return nil, fmt.Errorf("external event %d: date required and must be a string", i)
+			}
+			date, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				return nil, fmt.Errorf("external event %d: invalid date format %s (use YYYY-MM-DD): %w", i, dateStr, err)
+			}
+			
+			name, _ := eventMap["name"].(string)
+			events = append(events, VenueEvent{VenueID: int(venueID), Date: date, Name: name})
+		}
+	}
+	
+	return NewVenueRecoveryConstraint(int(minDays), overrides, events), nil
+}
+
+// createByeRoundWindowConstraint creates a bye round window constraint
+func (cf *ConstraintFactory) createByeRoundWindowConstraint(params map[string]interface{}) (Constraint, error) {
+	roundsInterface, ok := params["bye_rounds"]
+	if !ok {
+		return nil, fmt.Errorf("bye_rounds parameter required")
+	}
+	
+	roundList, ok := roundsInterface.([]interface{})
+	if !ok || len(roundList) == 0 {
+		return nil, fmt.Errorf("bye_rounds must be a non-empty array")
+	}
+	
+	var rounds []int
+	for _, roundInterface := range roundList {
+		round, ok := roundInterface.(float64)
+		if !ok || round < 1 {
+			return nil, fmt.Errorf("each bye round must be a positive number")
+		}
+		rounds = append(rounds, int(round))
+	}
+	
+	return NewByeRoundWindowConstraint(rounds), nil
+}
+
+// createMagicRoundConstraint creates a magic round constraint
+func (cf *ConstraintFactory) createMagicRoundConstraint(params map[string]interface{}) (Constraint, error) {
+	round, ok := params["round"].(float64)
+	if !ok || round < 1 {
+		return nil, fmt.Errorf("round parameter required and must be a positive number")
+	}
+	
+	venueID, ok := params["venue_id"].(float64)
+	if !ok || venueID < 1 {
+		return nil, fmt.Errorf("venue_id parameter required and must be a positive number")
+	}
+	
+	weekendDays := float64(DefaultMagicRoundDays)
+	if daysInterface, exists := params["weekend_days"]; exists {
+		weekendDays, ok = daysInterface.(float64)
+		if !ok || weekendDays < 1 {
+			return nil, fmt.Errorf("weekend_days must be a positive number")
+		}
+	}
+	
+	return NewMagicRoundConstraint(int(round), int(venueID), int(weekendDays)), nil
+}
+
+// createSharedVenueConstraint creates a shared-venue constraint
+func (cf *ConstraintFactory) createSharedVenueConstraint(params map[string]interface{}) (Constraint, error) {
+	minGap := DefaultSharedVenueGap
+	if gapInterface, exists := params["min_gap_minutes"]; exists {
+		minutes, ok := gapInterface.(float64)
+		if !ok || minutes < 0 {
+			return nil, fmt.Errorf("min_gap_minutes must be a non-negative number")
+		}
+		minGap = time.Duration(minutes) * time.Minute
+	}
+	
+	return NewSharedVenueConstraint(minGap), nil
+}
+
+// createCustomExpressionConstraint creates a custom expression constraint, hard or soft
+func (cf *ConstraintFactory) createCustomExpressionConstraint(params map[string]interface{}, isHard bool) (Constraint, error) {
+	expression, ok := params["expression"].(string)
+	if !ok || expression == "" {
+		return nil, fmt.Errorf("expression parameter required and must be a string")
+	}
+	
+	description := ""
+	if value, exists := params["description"]; exists {
+		description, ok = value.(string)
+		if !ok {
+			return nil, fmt.Errorf("description must be a string")
+		}
+	}
+	
+	return NewCustomExpressionConstraint(expression, description, isHard)
+}
+
+// createRivalryRoundConstraint creates a rivalry round constraint, hard or soft
+func (cf *ConstraintFactory) createRivalryRoundConstraint(params map[string]interface{}, isHard bool) (Constraint, error) {
+	fixturesInterface, ok := params["fixtures"]
+	if !ok {
+		return nil, fmt.Errorf("fixtures parameter required")
+	}
+	
+	fixtureList, ok := fixturesInterface.([]interface{})
+	if !ok || len(fixtureList) == 0 {
+		return nil, fmt.Errorf("fixtures must be a non-empty array")
+	}
+	
+	var fixtures []RivalryFixture
+	for i, fixtureInterface := range fixtureList {
+		fixtureMap, ok := fixtureInterface.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("fixture %d must be an object", i)
+		}
+		
+		values := make(map[string]int)
+		for _, field := range []string{"team_a", "team_b", "round"} {
+			value, ok := fixtureMap[field].(float64)
+			if !ok || value < 1 {
+				return nil, fmt.Errorf("fixture %d: %s required and must be a positive number", i, field)
+			}
+			values[field] = int(value)
+		}
+		if values["team_a"] == values["team_b"] {
+			return nil, fmt.Errorf("fixture %d: team_a and team_b must be different teams", i)
+		}
+		
+		fixtures = append(fixtures, RivalryFixture{
+			TeamA: values["team_a"],
+			TeamB: values["team_b"],
+			Round: values["round"],
+		})
+	}
+	
+	return NewRivalryRoundConstraint(fixtures, isHard), nil
+}
+
+// createBroadcasterQuotaConstraint creates a broadcaster quota constraint, hard or soft
+func (cf *ConstraintFactory) createBroadcasterQuotaConstraint(params map[string]interface{}, isHard bool) (Constraint, error) {
+	var category TimeslotCategory
+	
+	if value, exists := params["broadcaster"]; exists {
+		broadcaster, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("broadcaster must be a string")
+		}
+		category.Broadcaster = broadcaster
+	}
+	
+	daysInterface, ok := params["days"]
 	if !ok {
-		return nil, fmt.Errorf("max_consecutive_away parameter required and must be a number")
+		return nil, fmt.Errorf("days parameter required")
+	}
+	dayList, ok := daysInterface.([]interface{})
+	if !ok || len(dayList) == 0 {
+		return nil, fmt.Errorf("days must be a non-empty array")
+	}
+	for _, dayInterface := range dayList {
+		name, ok := dayInterface.(string)
+		if !ok {
+			return nil, fmt.Errorf("each day must be a string")
+		}
+		day, err := ParseWeekday(name)
+		if err != nil {
+			return nil, err
+		}
+		category.Days = append(category.Days, day)
 	}
 	
-	return NewTravelMinimizationConstraint(int(maxConsecutive)), nil
+	for field, bound := range map[string]**time.Time{"from": &category.From, "to": &category.To} {
+		value, exists := params[field]
+		if !exists {
+			continue
+		}
+		clock, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a time string", field)
+		}
+		kickoff, err := time.Parse("15:04", clock)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s time %s (use HH:MM): %w", field, clock, err)
+		}
+		*bound = &kickoff
+	}
+	if category.From != nil && category.To != nil && !category.From.Before(*category.To) {
+		return nil, fmt.Errorf("from must be earlier than to")
+	}
+	
+	if value, exists := params["prime_time_only"]; exists {
+		primeTimeOnly, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("prime_time_only must be a boolean")
+		}
+		category.PrimeTimeOnly = primeTimeOnly
+	}
+	
+	minAppearances := 0
+	maxAppearances := NoQuotaMaximum
+	
+	minValue, hasMin := params["min_appearances"]
+	if hasMin {
+		value, ok := minValue.(float64)
+		if !ok || value < 0 {
+			return nil, fmt.Errorf("min_appearances must be a non-negative number")
+		}
+		minAppearances = int(value)
+	}
+	
+	maxValue, hasMax := params["max_appearances"]
+	if hasMax {
+		value, ok := maxValue.(float64)
+		if !ok || value < 0 {
+			return nil, fmt.Errorf("max_appearances must be a non-negative number")
+		}
+		maxAppearances = int(value)
+	}
+	
+	if !hasMin && !hasMax {
+		return nil, fmt.Errorf("min_appearances or max_appearances parameter required")
+	}
+	
+	if maxAppearances != NoQuotaMaximum && minAppearances > maxAppearances {
+		return nil, fmt.Errorf("min_appearances cannot exceed max_appearances")
+	}
+	
+	return NewBroadcasterQuotaConstraint(category, minAppearances, maxAppearances, isHard), nil
 }
 
-// createRestPeriodConstraint creates a rest period constraint
-func (cf *ConstraintFactory) createRestPeriodConstraint(params map[string]interface{}) (Constraint, error) {
-	minDays, ok := params["min_rest_days"].(float64)
+// createRegionSpreadConstraint creates a region spread constraint, hard or soft
+func (cf *ConstraintFactory) createRegionSpreadConstraint(params map[string]interface{}, isHard bool) (Constraint, error) {
+	regionsInterface, ok := params["team_regions"]
 	if !ok {
-		return nil, fmt.Errorf("min_rest_days parameter required and must be a number")
+		return nil, fmt.Errorf("team_regions parameter required")
+	}
+	regionsMap, ok := regionsInterface.(map[string]interface{})
+	if !ok || len(regionsMap) == 0 {
+		return nil, fmt.Errorf("team_regions must be a non-empty object keyed by team ID")
+	}
+	
+	teamRegions := make(map[int]string, len(regionsMap))
+	for teamKey, regionInterface := range regionsMap {
+		teamID, err := strconv.Atoi(teamKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid team ID %s in team_regions", teamKey)
+		}
+		region, ok := regionInterface.(string)
+		if !ok || strings.TrimSpace(region) == "" {
+			return nil, fmt.Errorf("region for team %d must be a non-empty string", teamID)
+		}
+		teamRegions[teamID] = strings.TrimSpace(region)
+	}
+	
+	minHomeMatches := 0
+	maxHomeMatches := NoRegionMaximum
+	
+	minValue, hasMin := params["min_home_matches"]
+	if hasMin {
+		value, ok := minValue.(float64)
+		if !ok || value < 0 {
+			return nil, fmt.Errorf("min_home_matches must be a non-negative number")
+		}
+		minHomeMatches = int(value)
+	}
+	
+	maxValue, hasMax := params["max_home_matches"]
+	if hasMax {
+		value, ok := maxValue.(float64)
+		if !ok || value < 0 {
+			return nil, fmt.Errorf("max_home_matches must be a non-negative number")
+		}
+		maxHomeMatches = int(value)
+	}
+	
+	if !hasMin && !hasMax {
+		return nil, fmt.Errorf("min_home_matches or max_home_matches parameter required")
+	}
+	
+	if maxHomeMatches != NoRegionMaximum && minHomeMatches > maxHomeMatches {
+		return nil, fmt.Errorf("min_home_matches cannot exceed max_home_matches")
+	}
+	
+	return NewRegionSpreadConstraint(teamRegions, minHomeMatches, maxHomeMatches, isHard), nil
+}
+
+// createTravelMinimizationConstraint creates a travel minimization constraint
+func (cf *ConstraintFactory) createTravelMinimizationConstraint(params map[string]interface{}) (Constraint, error) {
+	maxConsecutive := NoConsecutiveAwayLimit
+	if value, exists := params["max_consecutive_away"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 0 {
+			return nil, fmt.Errorf("max_consecutive_away must be a non-negative number")
+		}
+		maxConsecutive = int(number)
+	}
+	
+	maxTravelKm := 0.0
+	if value, exists := params["max_total_travel_km"]; exists {
+		number, ok := value.(float64)
+		if !ok || number <= 0 {
+			return nil, fmt.Errorf("max_total_travel_km must be a positive number")
+		}
+		maxTravelKm = number
+	}
+	
+	if maxConsecutive == NoConsecutiveAwayLimit && maxTravelKm == 0 {
+		return nil, fmt.Errorf("max_consecutive_away or max_total_travel_km parameter required")
+	}
+	
+	teamWeights, err := parseTeamWeights(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	constraint := NewTravelMinimizationConstraint(maxConsecutive)
+	constraint.SetMaxTotalTravelKm(maxTravelKm)
+	constraint.SetTeamWeights(teamWeights)
+	return constraint, nil
+}
+
+// createShortTurnaroundCapConstraint creates a short turnaround cap constraint
+func (cf *ConstraintFactory) createShortTurnaroundCapConstraint(params map[string]interface{}) (Constraint, error) {
+	maxTurnarounds := DefaultMaxShortTurnarounds
+	if value, exists := params["max_turnarounds"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 0 {
+			return nil, fmt.Errorf("max_turnarounds must be a non-negative number")
+		}
+		maxTurnarounds = int(number)
+	}
+	
+	constraint := NewShortTurnaroundCapConstraint(maxTurnarounds)
+	if value, exists := params["short_turnaround_days"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 1 {
+			return nil, fmt.Errorf("short_turnaround_days must be a positive number")
+		}
+		constraint.SetShortTurnaroundDays(int(number))
+	}
+	if value, exists := params["long_haul_km"]; exists {
+		number, ok := value.(float64)
+		if !ok || number <= 0 {
+			return nil, fmt.Errorf("long_haul_km must be a positive number")
+		}
+		constraint.SetLongHaulKm(number)
+	}
+	return constraint, nil
+}
+
+// createTravelFatigueConstraint creates a travel fatigue constraint
+func (cf *ConstraintFactory) createTravelFatigueConstraint(params map[string]interface{}) (Constraint, error) {
+	maxConsecutive := DefaultMaxConsecutiveLongHaul
+	if value, exists := params["max_consecutive_long_haul"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 0 {
+			return nil, fmt.Errorf("max_consecutive_long_haul must be a non-negative number")
+		}
+		maxConsecutive = int(number)
+	}
+	
+	zoneShiftPenalty := DefaultZoneShiftPenalty
+	if value, exists := params["zone_shift_penalty"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 0 {
+			return nil, fmt.Errorf("zone_shift_penalty must be a non-negative number")
+		}
+		zoneShiftPenalty = number
+	}
+	
+	teamWeights, err := parseTeamWeights(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	constraint := NewTravelFatigueConstraint(maxConsecutive)
+	constraint.SetZoneShiftPenalty(zoneShiftPenalty)
+	constraint.SetTeamWeights(teamWeights)
+	return constraint, nil
+}
+
+// createTravelCostConstraint creates a travel cost constraint
+func (cf *ConstraintFactory) createTravelCostConstraint(params map[string]interface{}) (Constraint, error) {
+	budget, ok := params["budget"].(float64)
+	if !ok || budget <= 0 {
+		return nil, fmt.Errorf("budget parameter required and must be a positive number")
+	}
+	
+	model, err := parseTravelCostModel(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	constraint := NewTravelCostConstraint(budget)
+	constraint.SetModel(model)
+	return constraint, nil
+}
+
+// createHalfSeasonTravelConstraint creates a half-season travel constraint
+func (cf *ConstraintFactory) createHalfSeasonTravelConstraint(params map[string]interface{}) (Constraint, error) {
+	splitRound := 0
+	if value, exists := params["split_round"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 1 {
+			return nil, fmt.Errorf("split_round must be a positive number")
+		}
+		splitRound = int(number)
+	}
+	
+	awayTolerance := DefaultHalfSeasonAwayTolerance
+	if value, exists := params["away_tolerance"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 0 {
+			return nil, fmt.Errorf("away_tolerance must be a non-negative number")
+		}
+		awayTolerance = int(number)
+	}
+	
+	distanceTolerance := DefaultHalfSeasonDistanceTolerance
+	if value, exists := params["distance_tolerance"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 0 || number > 1 {
+			return nil, fmt.Errorf("distance_tolerance must be between 0 and 1")
+		}
+		distanceTolerance = number
+	}
+	
+	teamWeights, err := parseTeamWeights(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	constraint := NewHalfSeasonTravelConstraint(splitRound, awayTolerance, distanceTolerance)
+	constraint.SetTeamWeights(teamWeights)
+	return constraint, nil
+}
+
+// parseTravelCostModel reads the travel cost rates, keeping the defaults for
+// any not given
+func parseTravelCostModel(params map[string]interface{}) (TravelCostModel, error) {
+	model := DefaultTravelCostModel()
+	rates := map[string]*float64{
+		"drive_cost_per_km":        &model.DriveCostPerKm,
+		"short_flight_cost_per_km": &model.ShortFlightCostPerKm,
+		"long_flight_cost_per_km":  &model.LongFlightCostPerKm,
+		"accommodation_per_night":  &model.AccommodationPerNight,
+	}
+	for name, rate := range rates {
+		value, exists := params[name]
+		if !exists {
+			continue
+		}
+		number, ok := value.(float64)
+		if !ok || number < 0 {
+			return model, fmt.Errorf("%s must be a non-negative number", name)
+		}
+		*rate = number
+	}
+	
+	if value, exists := params["stay_away_rest_days"]; exists {
+		number, ok := value.(float64)
+		if !ok || number < 0 {
+			return model, fmt.Errorf("stay_away_rest_days must be a non-negative number")
+		}
+		model.StayAwayRestDays = int(number)
+	}
+	return model, nil
+}
+
+// createRestPeriodConstraint creates a rest period constraint, hard or soft
+func (cf *ConstraintFactory) createRestPeriodConstraint(params map[string]interface{}, isHard bool) (Constraint, error) {
+	minRestDays := 0
+	maxShortTurnarounds := NoTurnaroundLimit
+	turnaroundDays := DefaultShortTurnaroundDays
+	
+	minValue, hasMin := params["min_rest_days"]
+	if hasMin {
+		value, ok := minValue.(float64)
+		if !ok {
+			return nil, fmt.Errorf("min_rest_days must be a number")
+		}
+		minRestDays = int(value)
+	}
+	
+	maxValue, hasMax := params["max_short_turnarounds"]
+	if hasMax {
+		value, ok := maxValue.(float64)
+		if !ok || value < 0 {
+			return nil, fmt.Errorf("max_short_turnarounds must be a non-negative number")
+		}
+		maxShortTurnarounds = int(value)
+	}
+	
+	if value, exists := params["short_turnaround_days"]; exists {
+		days, ok := value.(float64)
+		if !ok || days < 1 {
+			return nil, fmt.Errorf("short_turnaround_days must be a positive number")
+		}
+		turnaroundDays = int(days)
+	}
+	
+	if !hasMin && !hasMax {
+		return nil, fmt.Errorf("min_rest_days or max_short_turnarounds parameter required")
+	}
+	
+	teamWeights, err := parseTeamWeights(params)
+	if err != nil {
+		return nil, err
 	}
 	
-	return NewRestPeriodConstraint(int(minDays)), nil
+	constraint := newRestPeriodConstraint(minRestDays, isHard)
+	constraint.SetMaxShortTurnarounds(maxShortTurnarounds, turnaroundDays)
+	constraint.SetTeamWeights(teamWeights)
+	return constraint, nil
 }
 
 // createPrimeTimeSpreadConstraint creates a prime time spread constraint
@@ -214,7 +905,14 @@ func (cf *ConstraintFactory) createPrimeTimeSpreadConstraint(params map[string]i
 		return nil, fmt.Errorf("max_deviation parameter required and must be a number")
 	}
 	
-	return NewPrimeTimeSpreadConstraint(targetRatio, maxDeviation), nil
+	teamWeights, err := parseTeamWeights(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	constraint := NewPrimeTimeSpreadConstraint(targetRatio, maxDeviation)
+	constraint.SetTeamWeights(teamWeights)
+	return constraint, nil
 }
 
 // createHomeAwayBalanceConstraint creates a home/away balance constraint
@@ -224,7 +922,319 @@ func (cf *ConstraintFactory) createHomeAwayBalanceConstraint(params map[string]i
 		return nil, fmt.Errorf("max_deviation parameter required and must be a number")
 	}
 	
-	return NewHomeAwayBalanceConstraint(maxDeviation), nil
+	teamWeights, err := parseTeamWeights(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	constraint := NewHomeAwayBalanceConstraint(maxDeviation)
+	constraint.SetTeamWeights(teamWeights)
+	return constraint, nil
+}
+
+// createPrimeTimeAttractivenessConstraint creates a prime-time attractiveness constraint
+func (cf *ConstraintFactory) createPrimeTimeAttractivenessConstraint(params map[string]interface{}) (Constraint, error) {
+	freeze, _ := params["freeze_weights"].(bool)
+	
+	// Frozen weights are used verbatim so the draw scores reproducibly
+	if frozenInterface, exists := params["frozen_weights"]; exists && freeze {
+		frozen, err := parseMatchupWeights(frozenInterface, "frozen_weights")
+		if err != nil {
+			return nil, err
+		}
+		return NewPrimeTimeAttractivenessConstraint(frozen, true), nil
+	}
+	
+	weights, err := computeRivalryWeightsFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	return NewPrimeTimeAttractivenessConstraint(weights, false), nil
+}
+
+// createExpectedCrowdConstraint creates an expected crowd constraint
+func (cf *ConstraintFactory) createExpectedCrowdConstraint(params map[string]interface{}) (Constraint, error) {
+	popularity := make(map[int]float64)
+	if popularityInterface, exists := params["team_popularity"]; exists {
+		popularityMap, ok := popularityInterface.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("team_popularity must be an object keyed by team ID")
+		}
+		
+		for teamKey, weightInterface := range popularityMap {
+			teamID, err := strconv.Atoi(teamKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid team ID %s in team_popularity", teamKey)
+			}
+			weight, ok := weightInterface.(float64)
+			if !ok || weight < 0 {
+				return nil, fmt.Errorf("popularity for team %d must be a non-negative number", teamID)
+			}
+			popularity[teamID] = weight
+		}
+	}
+	
+	referenceCrowd := DefaultReferenceCrowd
+	if value, exists := params["reference_crowd"]; exists {
+		crowd, ok := value.(float64)
+		if !ok || crowd <= 0 {
+			return nil, fmt.Errorf("reference_crowd must be a positive number")
+		}
+		referenceCrowd = crowd
+	}
+	
+	primeTimeBoost := DefaultPrimeTimeBoost
+	if value, exists := params["prime_time_boost"]; exists {
+		boost, ok := value.(float64)
+		if !ok || boost < 0 {
+			return nil, fmt.Errorf("prime_time_boost must be a non-negative number")
+		}
+		primeTimeBoost = boost
+	}
+	
+	return NewExpectedCrowdConstraint(popularity, referenceCrowd, primeTimeBoost), nil
+}
+
+// createStrengthSpreadConstraint creates a strength spread constraint
+func (cf *ConstraintFactory) createStrengthSpreadConstraint(params map[string]interface{}) (Constraint, error) {
+	strengthsMap, ok := params["team_strengths"].(map[string]interface{})
+	if !ok || len(strengthsMap) == 0 {
+		return nil, fmt.Errorf("team_strengths must be a non-empty object keyed by team ID")
+	}
+	
+	strengths := make(map[int]float64, len(strengthsMap))
+	for teamKey, strengthInterface := range strengthsMap {
+		teamID, err := strconv.Atoi(teamKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid team ID %s in team_strengths", teamKey)
+		}
+		strength, ok := strengthInterface.(float64)
+		if !ok {
+			return nil, fmt.Errorf("strength for team %d must be a number", teamID)
+		}
+		strengths[teamID] = strength
+	}
+	
+	counts := map[string]int{
+		"top_teams":           DefaultStrengthTopTeams,
+		"window_rounds":       DefaultStrengthWindowRounds,
+		"max_tough_in_window": DefaultMaxToughInWindow,
+	}
+	minimums := map[string]float64{"top_teams": 1, "window_rounds": 1, "max_tough_in_window": 0}
+	for name := range counts {
+		value, exists := params[name]
+		if !exists {
+			continue
+		}
+		number, ok := value.(float64)
+		if !ok || number < minimums[name] {
+			return nil, fmt.Errorf("%s must be a number of at least %g", name, minimums[name])
+		}
+		counts[name] = int(number)
+	}
+	
+	teamWeights, err := parseTeamWeights(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	constraint := NewStrengthSpreadConstraint(strengths, counts["top_teams"], counts["window_rounds"], counts["max_tough_in_window"])
+	constraint.SetTeamWeights(teamWeights)
+	return constraint, nil
+}
+
+// createDerbyConstraint creates a derby constraint
+func (cf *ConstraintFactory) createDerbyConstraint(params map[string]interface{}) (Constraint, error) {
+	maxDistanceKm, ok := params["max_distance_km"].(float64)
+	if !ok || maxDistanceKm <= 0 {
+		return nil, fmt.Errorf("max_distance_km parameter required and must be a positive number")
+	}
+	
+	var rounds []int
+	if roundsInterface, exists := params["marquee_rounds"]; exists {
+		roundList, ok := roundsInterface.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("marquee_rounds must be an array")
+		}
+		
+		for _, roundInterface := range roundList {
+			round, ok := roundInterface.(float64)
+			if !ok || round < 1 {
+				return nil, fmt.Errorf("each marquee round must be a positive number")
+			}
+			rounds = append(rounds, int(round))
+		}
+	}
+	
+	return NewDerbyConstraint(maxDistanceKm, rounds), nil
+}
+
+// createDoubleHeaderConstraint creates a double-header constraint
+func (cf *ConstraintFactory) createDoubleHeaderConstraint(params map[string]interface{}) (Constraint, error) {
+	partnerDrawID, ok := params["partner_draw_id"].(float64)
+	if !ok || partnerDrawID < 1 || partnerDrawID != float64(int(partnerDrawID)) {
+		return nil, fmt.Errorf("partner_draw_id parameter required and must be a positive integer")
+	}
+	
+	return NewDoubleHeaderConstraint(int(partnerDrawID)), nil
+}
+
+// createFixtureVariationConstraint creates a fixture variation constraint
+func (cf *ConstraintFactory) createFixtureVariationConstraint(params map[string]interface{}) (Constraint, error) {
+	previousDrawID, ok := params["previous_draw_id"].(float64)
+	if !ok || previousDrawID < 1 || previousDrawID != float64(int(previousDrawID)) {
+		return nil, fmt.Errorf("previous_draw_id parameter required and must be a positive integer")
+	}
+	
+	return NewFixtureVariationConstraint(int(previousDrawID)), nil
+}
+
+// computeRivalryWeightsFromParams builds matchup weights from base weights, results and ladder
+func computeRivalryWeightsFromParams(params map[string]interface{}) (map[string]float64, error) {
+	base := make(map[string]float64)
+	if baseInterface, exists := params["rivalry_weights"]; exists {
+		var err error
+		base, err = parseMatchupWeights(baseInterface, "rivalry_weights")
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	options := DefaultRivalryWeightOptions()
+	if value, exists := params["close_margin"]; exists {
+		margin, ok := value.(float64)
+		if !ok || margin < 0 {
+			return nil, fmt.Errorf("close_margin must be a non-negative number")
+		}
+		options.CloseMargin = int(margin)
+	}
+	if value, exists := params["close_finish_boost"]; exists {
+		boost, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("close_finish_boost must be a number")
+		}
+		options.CloseFinishBoost = boost
+	}
+	if value, exists := params["ladder_boost"]; exists {
+		boost, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("ladder_boost must be a number")
+		}
+		options.LadderBoost = boost
+	}
+	
+	var results []HistoricalResult
+	if resultsInterface, exists := params["results"]; exists {
+		resultList, ok := resultsInterface.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("results must be an array")
+		}
+		
+		for i, resultInterface := range resultList {
+			resultMap, ok := resultInterface.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("result %d must be an object", i)
+			}
+			
+			values := make(map[string]int)
+			for _, field := range []string{"season", "home_team_id", "away_team_id", "home_score", "away_score"} {
+				value, ok := resultMap[field].(float64)
+				if !ok {
+					return nil, fmt.Errorf("result %d: %s required and must be a number", i, field)
+				}
+				values[field] = int(value)
+			}
+			round, _ := resultMap["round"].(float64)
+			
+			results = append(results, HistoricalResult{
+				Season:     values["season"],
+				Round:      int(round),
+				HomeTeamID: values["home_team_id"],
+				AwayTeamID: values["away_team_id"],
+				HomeScore:  values["home_score"],
+				AwayScore:  values["away_score"],
+			})
+		}
+	}
+	
+	ladder := make(map[int]int)
+	if ladderInterface, exists := params["ladder"]; exists {
+		ladderMap, ok := ladderInterface.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("ladder must be an object of team ID to ladder position")
+		}
+		
+		for teamKey, positionInterface := range ladderMap {
+			teamID, err := strconv.Atoi(teamKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid team ID %s in ladder", teamKey)
+			}
+			position, ok := positionInterface.(float64)
+			if !ok || position < 1 {
+				return nil, fmt.Errorf("ladder position for team %d must be a positive number", teamID)
+			}
+			ladder[teamID] = int(position)
+		}
+	}
+	
+	return ComputeRivalryWeights(base, results, ladder, options), nil
+}
+
+// parseMatchupWeights parses an object of matchup key to weight
+func parseMatchupWeights(value interface{}, field string) (map[string]float64, error) {
+	weightsMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object keyed by matchup (e.g. \"1-2\")", field)
+	}
+	
+	weights := make(map[string]float64)
+	for key, weightInterface := range weightsMap {
+		var teamA, teamB int
+		if _, err := fmt.Sscanf(key, "%d-%d", &teamA, &teamB); err != nil {
+			return nil, fmt.Errorf("invalid matchup key %s in %s (use \"teamA-teamB\")", key, field)
+		}
+		weight, ok := weightInterface.(float64)
+		if !ok || weight < 0 {
+			return nil, fmt.Errorf("weight for matchup %s must be a non-negative number", key)
+		}
+		weights[MatchupKey(teamA, teamB)] = weight
+	}
+	
+	return weights, nil
+}
+
+// FreezeRivalryWeights computes rivalry weights once for every prime-time
+// attractiveness constraint that asks for freeze_weights, storing them as
+// frozen_weights so later result imports don't change how the draw scores
+func FreezeRivalryWeights(config *ConstraintConfig) error {
+	for i := range config.Soft {
+		softConfig := &config.Soft[i]
+		if softConfig.Type != "prime_time_attractiveness" {
+			continue
+		}
+		
+		freeze, _ := softConfig.Params["freeze_weights"].(bool)
+		if !freeze {
+			continue
+		}
+		if _, alreadyFrozen := softConfig.Params["frozen_weights"]; alreadyFrozen {
+			continue
+		}
+		
+		weights, err := computeRivalryWeightsFromParams(softConfig.Params)
+		if err != nil {
+			return fmt.Errorf("soft constraint %d (%s): %w", i, softConfig.Type, err)
+		}
+		
+		frozen := make(map[string]interface{})
+		for key, weight := range weights {
+			frozen[key] = weight
+		}
+		softConfig.Params["frozen_weights"] = frozen
+	}
+	
+	return nil
 }
 
 // LoadConstraintConfigFromJSON loads constraint configuration from JSON bytes
@@ -238,6 +1248,27 @@ func LoadConstraintConfigFromJSON(data []byte) (ConstraintConfig, error) {
 	return config, nil
 }
 
+// NewConstraintEngineFromJSON builds a constraint engine from a draw's stored
+// constraint configuration, falling back to the default NRL configuration when empty
+func NewConstraintEngineFromJSON(data []byte) (*ConstraintEngine, error) {
+	config := GetDefaultNRLConstraintConfig()
+	if len(data) > 0 {
+		var err error
+		config, err = LoadConstraintConfigFromJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse constraint config: %w", err)
+		}
+	}
+	
+	factory := NewConstraintFactory()
+	engine, err := factory.CreateConstraintEngine(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create constraint engine: %w", err)
+	}
+	
+	return engine, nil
+}
+
 // SaveConstraintConfigToJSON saves constraint configuration to JSON bytes
 func SaveConstraintConfigToJSON(config ConstraintConfig) ([]byte, error) {
 	data, err := json.MarshalIndent(config, "", "  ")
@@ -297,43 +1328,89 @@ func GetDefaultNRLConstraintConfig() ConstraintConfig {
 	}
 }
 
-// ValidateConstraintConfig validates a constraint configuration
-func ValidateConstraintConfig(config ConstraintConfig) error {
+// ConstraintDefaults holds the default parameters and weight for a constraint type
+type ConstraintDefaults struct {
+	Params map[string]interface{} `json:"params"`
+	Weight *float64               `json:"weight,omitempty"` // Only set for soft constraints
+}
+
+// GetDefaultConstraintParams returns the defaults used by GetDefaultNRLConstraintConfig keyed by type
+func GetDefaultConstraintParams() map[string]ConstraintDefaults {
+	config := GetDefaultNRLConstraintConfig()
+	defaults := make(map[string]ConstraintDefaults)
+	
+	for _, hardConfig := range config.Hard {
+		defaults[hardConfig.Type] = ConstraintDefaults{Params: hardConfig.Params}
+	}
+	
+	for _, softConfig := range config.Soft {
+		weight := softConfig.Weight
+		defaults[softConfig.Type] = ConstraintDefaults{Params: softConfig.Params, Weight: &weight}
+	}
+	
+	return defaults
+}
+
+// ValidateConstraintConfig validates a constraint configuration for a draw.
+// Every constraint must build, and its params are checked against each other
+// and the draw's team count and rounds where those are known. The params'
+// problems are returned as issues, errors and warnings both; the error is
+// set when a constraint doesn't build or any issue is an error.
+func ValidateConstraintConfig(config ConstraintConfig, meta ConflictContext) ([]ConfigConflict, error) {
 	factory := NewConstraintFactory()
+	issues := []ConfigConflict{}
 	
 	// Validate hard constraints
 	for i, hardConfig := range config.Hard {
 		if hardConfig.Type == "" {
-			return fmt.Errorf("hard constraint %d: type cannot be empty", i)
+			return nil, fmt.Errorf("hard constraint %d: type cannot be empty", i)
 		}
 		
 		_, err := factory.createHardConstraint(hardConfig)
 		if err != nil {
-			return fmt.Errorf("hard constraint %d (%s): %w", i, hardConfig.Type, err)
+			return nil, fmt.Errorf("hard constraint %d (%s): %w", i, hardConfig.Type, err)
 		}
+		issues = append(issues, checkParams("hard", i, hardConfig.Type, hardConfig.Params, meta)...)
 	}
 	
 	// Validate soft constraints
 	for i, softConfig := range config.Soft {
 		if softConfig.Type == "" {
-			return fmt.Errorf("soft constraint %d: type cannot be empty", i)
+			return nil, fmt.Errorf("soft constraint %d: type cannot be empty", i)
 		}
 		
 		if softConfig.Weight < 0 || softConfig.Weight > 1 {
-			return fmt.Errorf("soft constraint %d (%s): weight must be between 0 and 1", i, softConfig.Type)
+			return nil, fmt.Errorf("soft constraint %d (%s): weight must be between 0 and 1", i, softConfig.Type)
 		}
 		
 		_, err := factory.createSoftConstraint(softConfig)
 		if err != nil {
-			return fmt.Errorf("soft constraint %d (%s): %w", i, softConfig.Type, err)
+			return nil, fmt.Errorf("soft constraint %d (%s): %w", i, softConfig.Type, err)
 		}
+		issues = append(issues, checkParams("soft", i, softConfig.Type, softConfig.Params, meta)...)
 	}
 	
-	return nil
+	for _, issue := range issues {
+		if issue.Severity == ConflictError {
+			return issues, fmt.Errorf("%s: %s", issue.Constraints[0], issue.Message)
+		}
+	}
+	return issues, nil
 }
 
-// GetConstraintTypeInfo returns information about available constraint types
+// GetConstraintTypeInfo returns information about available constraint types,
+// including those added through Register
 func GetConstraintTypeInfo() map[string]ConstraintTypeInfo {
+	info := builtinConstraintTypeInfo()
+	for name, registered := range registeredTypeInfo() {
+		info[name] = registered
+	}
+	return info
+}
+
+// builtinConstraintTypeInfo returns information about the constraint types
+// this package provides
+func builtinConstraintTypeInfo() map[string]ConstraintTypeInfo {
 	return map[string]ConstraintTypeInfo{
 		"venue_availability": {
 			Type:        "hard",
@@ -343,6 +1420,15 @@ func GetConstraintTypeInfo() map[string]ConstraintTypeInfo {
 				"unavailable_dates": "[]string - Array of dates in YYYY-MM-DD format",
 			},
 		},
+		"venue_quarantine": {
+			Type:        "hard",
+			Description: "Keeps a venue free in given rounds, such as for finals ground preparation or a concert. Checked by round, so it applies before kickoff dates are assigned",
+			Parameters: map[string]string{
+				"venue_id": "int - ID of the venue",
+				"rounds":   "[]int - Rounds the venue can't host a match",
+				"reason":   "string - Why the venue is unavailable, shown in violations (optional)",
+			},
+		},
 		"bye_constraint": {
 			Type:        "hard",
 			Description: "Ensures each team gets exactly one bye per full round-robin",
@@ -363,18 +1449,140 @@ func GetConstraintTypeInfo() map[string]ConstraintTypeInfo {
 				"min_rounds_separation": "int - Minimum rounds between same matchups",
 			},
 		},
+		"prime_time_cap": {
+			Type:        "hard",
+			Description: "Each team must have between a minimum and maximum number of prime-time appearances",
+			Parameters: map[string]string{
+				"min_appearances": "int - Minimum prime-time appearances per team (optional, default 0)",
+				"max_appearances": "int - Maximum prime-time appearances per team (optional, default unlimited)",
+			},
+		},
+		"venue_recovery": {
+			Type:        "hard",
+			Description: "Venues must have a minimum number of days between events for turf recovery",
+			Parameters: map[string]string{
+				"min_recovery_days":   "int - Minimum days between events at any venue",
+				"venue_recovery_days": "map[string]int - Venue-specific recovery days keyed by venue ID (optional)",
+				"external_events":     "[]object - Imported non-fixture events with venue_id, date (YYYY-MM-DD) and name (optional)",
+			},
+		},
+		"bye_round_window": {
+			Type:        "hard",
+			Description: "Byes may only fall in the listed rounds, such as those around State of Origin",
+			Parameters: map[string]string{
+				"bye_rounds": "[]int - Rounds in which teams may have a bye",
+			},
+		},
+		"rivalry_round": {
+			Type:        "hard",
+			Description: "Rivalry fixtures must be played in their target rounds, e.g. a season opener or Anzac Day. Configure as a soft constraint to prefer the rounds instead",
+			Parameters: map[string]string{
+				"fixtures": "[]object - Fixtures with team_a, team_b and the target round",
+			},
+		},
+		"broadcaster_quota": {
+			Type:        "hard",
+			Description: "Each team must appear in a broadcaster's timeslot category, e.g. free-to-air Thursday night, between a minimum and maximum number of times. Configure as a soft constraint to prefer the range instead",
+			Parameters: map[string]string{
+				"broadcaster":     "string - Broadcaster holding the timeslots, used in reports (optional)",
+				"days":            "[]string - Days in the category, e.g. [\"thursday\"]",
+				"from":            "string - Earliest kickoff in HH:MM format (optional)",
+				"to":              "string - Kickoffs must be before this HH:MM time (optional)",
+				"prime_time_only": "bool - Only count matches marked prime time (optional)",
+				"min_appearances": "int - Minimum appearances per team (optional, default 0)",
+				"max_appearances": "int - Maximum appearances per team (optional, default unlimited)",
+			},
+		},
+		"region_spread": {
+			Type:        "hard",
+			Description: "Each region, e.g. Sydney, must host between a minimum and maximum number of home matches per round so broadcasters aren't left with clashing games in one city. A match counts towards its home team's region. Configure as a soft constraint to prefer the range instead",
+			Parameters: map[string]string{
+				"team_regions":     "map[string]string - Region of each team's home games keyed by team ID; teams not listed are ignored",
+				"min_home_matches": "int - Minimum home matches per region per round (optional, default 0)",
+				"max_home_matches": "int - Maximum home matches per region per round (optional, default unlimited)",
+			},
+		},
+		"magic_round": {
+			Type:        "hard",
+			Description: "Every match in a round is played at one venue over a single weekend, with kickoffs stacked so they don't overlap. Venue recovery doesn't apply between the round's matches",
+			Parameters: map[string]string{
+				"round":        "int - The magic round",
+				"venue_id":     "int - ID of the venue hosting every match in the round",
+				"weekend_days": "int - Consecutive days the round may span (optional, default 3 for Friday to Sunday)",
+			},
+		},
+		"shared_venue": {
+			Type:        "hard",
+			Description: "Keep kickoffs clear of other competitions' completed draws at the same venue, such as the NRL's when scheduling the NRLW. Matches without a kickoff aren't checked",
+			Parameters: map[string]string{
+				"min_gap_minutes": "int - Minutes between kickoffs at a shared venue on the same day (optional, default 120)",
+			},
+		},
+		"max_short_turnarounds": {
+			Type:        "hard",
+			Description: "The CBA's short turnaround rules: no team may have more than the cap of short turnarounds across the season, or one straight after a long-haul trip. Matches without a kickoff date aren't checked",
+			Parameters: map[string]string{
+				"max_turnarounds":       "int - Short turnarounds allowed per team across the season (optional, default 2)",
+				"short_turnaround_days": "int - Most days between kickoffs that count as a short turnaround (optional, default 5)",
+				"long_haul_km":          "float - One-way trip, in kilometres, after which no short turnaround may follow (optional, default 1500)",
+			},
+		},
+		"custom_expression": {
+			Type:        "hard",
+			Description: "Every match must satisfy a rule expression, e.g. \"NOT team(1).plays OR team(1).consecutive_away <= 2\", for one-off league rules. Configure as a soft constraint to prefer the rule instead",
+			Parameters: map[string]string{
+				"expression":  "string - Condition over round, rounds, home, away, venue, weekday, hour, prime_time, scheduled and team(id) fields plays, is_home, is_away, consecutive_home, consecutive_away, home_games and away_games, joined with AND, OR and NOT",
+				"description": "string - Description of the rule used in reports (optional)",
+			},
+		},
 		"travel_minimization": {
 			Type:        "soft",
-			Description: "Minimize consecutive away games to reduce travel burden",
+			Description: "Minimize consecutive away games and total kilometres travelled to reduce travel burden",
 			Parameters: map[string]string{
-				"max_consecutive_away": "int - Maximum consecutive away games allowed",
+				"max_consecutive_away": "int - Maximum consecutive away games allowed (optional when max_total_travel_km is set)",
+				"max_total_travel_km":  "float - Season travel per team, in return-trip kilometres, before the score is penalized (optional)",
+				"team_weights":         "map[string]float - Score weight keyed by team ID, so fairness for those teams counts more (optional, default 1.0)",
+			},
+		},
+		"travel_fatigue": {
+			Type:        "soft",
+			Description: "Avoid back-to-back long-haul trips, such as Perth then Auckland. Trips are classed as drives (up to 300km), short flights (up to 1500km) or long flights, and long flights in a row beyond the limit are penalized, more so for each hour they shift the team's clocks, e.g. into New Zealand or, over daylight saving, out of Queensland",
+			Parameters: map[string]string{
+				"max_consecutive_long_haul": "int - Long-haul trips allowed in a row before they're penalized (optional, default 1)",
+				"zone_shift_penalty":        "float - Extra penalty per hour of time zone shift on a penalized trip (optional, default 0.5)",
+				"team_weights":              "map[string]float - Score weight keyed by team ID, so fairness for those teams counts more (optional, default 1.0)",
+			},
+		},
+		"travel_cost": {
+			Type:        "soft",
+			Description: "Keep the league's estimated travel cost for the season within budget. Each leg is priced per kilometre by how it's travelled, and teams flying between matches with too few rest days to go home stay on the road, paying for accommodation",
+			Parameters: map[string]string{
+				"budget":                   "float - League travel budget for the season, in dollars",
+				"drive_cost_per_km":        "float - Cost per kilometre of a drive (optional, default 4)",
+				"short_flight_cost_per_km": "float - Cost per kilometre of a short flight (optional, default 10)",
+				"long_flight_cost_per_km":  "float - Cost per kilometre of a long flight (optional, default 6)",
+				"accommodation_per_night":  "float - Cost of a night's accommodation for a team staying on the road (optional, default 9000)",
+				"stay_away_rest_days":      "int - Teams with fewer rest days than this between flights stay on the road (optional, default 6)",
+			},
+		},
+		"half_season_travel": {
+			Type:        "soft",
+			Description: "Balance each team's away games and travel between the halves of the season, so no team is left on the road for the run home. Travel is measured in return-trip kilometres",
+			Parameters: map[string]string{
+				"split_round":        "int - Last round of the first half (optional, default half the season rounded down, e.g. 13 of 27)",
+				"away_tolerance":     "int - Away games one half may have over the other before it's penalized (optional, default 1)",
+				"distance_tolerance": "float - Gap between the halves' travel, as a share of the team's season travel, before it's penalized (optional, default 0.2)",
+				"team_weights":       "map[string]float - Score weight keyed by team ID, so fairness for those teams counts more (optional, default 1.0)",
 			},
 		},
 		"rest_period": {
 			Type:        "soft",
-			Description: "Ensure minimum rest days between matches for player welfare",
+			Description: "Prefer a number of rest days between matches for player welfare, optionally capping each team's short turnarounds. Configure as a hard constraint to enforce a minimum and the cap instead",
 			Parameters: map[string]string{
-				"min_rest_days": "int - Minimum rest days between matches",
+				"min_rest_days":         "int - Minimum rest days between matches (optional when max_short_turnarounds is set)",
+				"max_short_turnarounds": "int - Maximum short turnarounds per team across the season (optional)",
+				"short_turnaround_days": "int - Most days between kickoffs that count as a short turnaround (optional, default 5)",
+				"team_weights":          "map[string]float - Score weight keyed by team ID, so fairness for those teams counts more (optional, default 1.0)",
 			},
 		},
 		"prime_time_spread": {
@@ -383,6 +1591,7 @@ func GetConstraintTypeInfo() map[string]ConstraintTypeInfo {
 			Parameters: map[string]string{
 				"target_ratio":   "float - Target ratio of prime time games (0.0-1.0)",
 				"max_deviation": "float - Maximum allowed deviation from target",
+				"team_weights":  "map[string]float - Score weight keyed by team ID, so fairness for those teams counts more (optional, default 1.0)",
 			},
 		},
 		"home_away_balance": {
@@ -390,6 +1599,67 @@ func GetConstraintTypeInfo() map[string]ConstraintTypeInfo {
 			Description: "Balance home and away games fairly for all teams",
 			Parameters: map[string]string{
 				"max_deviation": "float - Maximum deviation from 50/50 balance",
+				"team_weights":  "map[string]float - Score weight keyed by team ID, so fairness for those teams counts more (optional, default 1.0)",
+			},
+		},
+		"prime_time_attractiveness": {
+			Type:        "soft",
+			Description: "Assign the most attractive matchups, boosted by recent close finishes and ladder proximity, to prime time",
+			Parameters: map[string]string{
+				"rivalry_weights":    "map[string]float - Base matchup weights keyed by \"teamA-teamB\" (optional, default 1.0)",
+				"results":            "[]object - Historical results with season, round, home_team_id, away_team_id, home_score, away_score (optional)",
+				"ladder":             "map[string]int - Ladder position keyed by team ID (optional)",
+				"close_margin":       "int - Margin counted as a close finish (optional, default 6)",
+				"close_finish_boost": "float - Weight boost per close finish in the latest season (optional, default 0.2)",
+				"ladder_boost":       "float - Weight boost for teams adjacent on the ladder (optional, default 0.3)",
+				"freeze_weights":     "bool - Fix computed weights when the draw is created for reproducibility (optional)",
+			},
+		},
+		"expected_crowd": {
+			Type:        "soft",
+			Description: "Place high-drawing matchups at high-capacity venues and in prime time, scoring the draw on estimated season attendance",
+			Parameters: map[string]string{
+				"team_popularity":  "map[string]float - Crowd-drawing weight keyed by team ID (optional, default 1.0)",
+				"reference_crowd":  "float - Expected crowd when two teams of popularity 1.0 meet outside prime time (optional, default 20000)",
+				"prime_time_boost": "float - Fractional lift in demand for prime-time matches (optional, default 0.25)",
+			},
+		},
+		"home_venue_share": {
+			Type:        "soft",
+			Description: "Split home games of teams with several home venues in their target proportions, set on each team's home_venues",
+			Parameters:  map[string]string{},
+		},
+		"derby": {
+			Type:        "soft",
+			Description: "Schedule derbies between teams based near each other in marquee rounds, or spread them evenly across the season when no marquee rounds are set. Teams are located by their coordinates or home venue",
+			Parameters: map[string]string{
+				"max_distance_km": "float - Greatest distance between two teams' home locations for their matches to be derbies",
+				"marquee_rounds":  "[]int - Rounds to schedule derbies in (optional, default spreads them across the season)",
+			},
+		},
+		"strength_spread": {
+			Type:        "soft",
+			Description: "Spread each team's fixtures against the strongest teams across its season, so no team meets the whole top four in a few weeks. Teams are rated by a configured strength, such as last season's ladder points",
+			Parameters: map[string]string{
+				"team_strengths":      "map[string]float - Strength rating keyed by team ID, higher is stronger; teams not listed are never tough opponents",
+				"top_teams":           "int - How many of the highest rated teams are tough opponents (optional, default 4)",
+				"window_rounds":       "int - Run of rounds tough fixtures are counted over (optional, default 4)",
+				"max_tough_in_window": "int - Tough fixtures allowed in any window before they're penalized (optional, default 2)",
+				"team_weights":        "map[string]float - Score weight keyed by team ID, so fairness for those teams counts more (optional, default 1.0)",
+			},
+		},
+		"double_header": {
+			Type:        "soft",
+			Description: "Give another competition's matches, such as the NRLW's, curtain raisers by playing at the same venue on the same day",
+			Parameters: map[string]string{
+				"partner_draw_id": "int - Draw whose matches are played as curtain raisers",
+			},
+		},
+		"fixture_variation": {
+			Type:        "soft",
+			Description: "Avoid repeating last season's matchups in the same rounds, and its opening and closing round fixtures, so consecutive seasons don't look copy-pasted",
+			Parameters: map[string]string{
+				"previous_draw_id": "int - Last season's draw to compare against",
 			},
 		},
 	}
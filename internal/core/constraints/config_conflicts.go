@@ -0,0 +1,539 @@
+package constraints
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ConflictSeverity indicates whether a configuration conflict makes generation impossible
+type ConflictSeverity string
+
+const (
+	// ConflictError means no draw can satisfy the configuration
+	ConflictError ConflictSeverity = "error"
+	// ConflictWarning means the configuration is satisfiable but probably not what was intended
+	ConflictWarning ConflictSeverity = "warning"
+)
+
+// ConfigConflict describes two or more constraints that work against each other
+type ConfigConflict struct {
+	Code        string           `json:"code"`
+	Severity    ConflictSeverity `json:"severity"`
+	Message     string           `json:"message"`
+	Constraints []string         `json:"constraints"` // e.g. "hard[0]:double_up"
+}
+
+// ConflictContext carries the draw details that some conflicts and param
+// checks depend on
+type ConflictContext struct {
+	Teams  int // teams in the draw; 0 when unknown
+	Rounds int // season length; 0 when unknown
+}
+
+// conflictRule inspects a configuration for one kind of conflict
+type conflictRule func(config ConstraintConfig, ctx ConflictContext) []ConfigConflict
+
+// configConflictRules are applied in order by DetectConfigConflicts
+var configConflictRules = []conflictRule{
+	detectByeWindowSeasonConflicts,
+	detectDoubleUpSeasonConflicts,
+	detectDuplicateHardConstraints,
+	detectMagicRoundConflicts,
+	detectPrimeTimeCapConflicts,
+	detectRegionSpreadConflicts,
+	detectRivalryRoundConflicts,
+	detectVenueDateConflicts,
+	detectVenueQuarantineConflicts,
+}
+
+// DetectConfigConflicts reports constraints that contradict each other or the season.
+// Constraints that fail to parse are skipped; ValidateConstraintConfig reports those.
+func DetectConfigConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	conflicts := []ConfigConflict{}
+	for _, rule := range configConflictRules {
+		conflicts = append(conflicts, rule(config, ctx)...)
+	}
+	return conflicts
+}
+
+// HasBlockingConflicts reports whether any conflict is an error
+func HasBlockingConflicts(conflicts []ConfigConflict) bool {
+	for _, conflict := range conflicts {
+		if conflict.Severity == ConflictError {
+			return true
+		}
+	}
+	return false
+}
+
+// detectByeWindowSeasonConflicts flags bye rounds beyond the end of the season.
+// A window with no round inside the season leaves nowhere for byes to fall.
+func detectByeWindowSeasonConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	if ctx.Rounds <= 0 {
+		return nil
+	}
+
+	var conflicts []ConfigConflict
+	for i, hard := range config.Hard {
+		if hard.Type != "bye_round_window" {
+			continue
+		}
+		rounds, _ := hard.Params["bye_rounds"].([]interface{})
+
+		var outside []int
+		inside := 0
+		for _, roundInterface := range rounds {
+			round, ok := roundInterface.(float64)
+			if !ok {
+				continue
+			}
+			if int(round) > ctx.Rounds {
+				outside = append(outside, int(round))
+			} else {
+				inside++
+			}
+		}
+		if len(outside) == 0 {
+			continue
+		}
+
+		if inside == 0 {
+			conflicts = append(conflicts, ConfigConflict{
+				Code:        "bye_window_outside_season",
+				Severity:    ConflictError,
+				Message:     fmt.Sprintf("bye_round_window rounds %v are all after the %d round season, so no round can hold a bye", outside, ctx.Rounds),
+				Constraints: []string{constraintRef("hard", i, hard.Type)},
+			})
+			continue
+		}
+		conflicts = append(conflicts, ConfigConflict{
+			Code:        "bye_window_exceeds_season",
+			Severity:    ConflictWarning,
+			Message:     fmt.Sprintf("bye_round_window rounds %v are after the %d round season and will never hold a bye", outside, ctx.Rounds),
+			Constraints: []string{constraintRef("hard", i, hard.Type)},
+		})
+	}
+	return conflicts
+}
+
+// detectDoubleUpSeasonConflicts flags double-up separations the season is too short to honour
+func detectDoubleUpSeasonConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	if ctx.Rounds <= 0 {
+		return nil
+	}
+
+	var conflicts []ConfigConflict
+	for i, hard := range config.Hard {
+		if hard.Type != "double_up" {
+			continue
+		}
+		separation, ok := hard.Params["min_rounds_separation"].(float64)
+		if !ok || int(separation) < ctx.Rounds {
+			continue
+		}
+		conflicts = append(conflicts, ConfigConflict{
+			Code:        "double_up_exceeds_season",
+			Severity:    ConflictWarning,
+			Message:     fmt.Sprintf("double_up separation of %d rounds is not shorter than the %d round season, so no pair of teams can meet twice", int(separation), ctx.Rounds),
+			Constraints: []string{constraintRef("hard", i, hard.Type)},
+		})
+	}
+	return conflicts
+}
+
+// detectDuplicateHardConstraints flags draw-wide hard constraints configured more than once with different settings
+func detectDuplicateHardConstraints(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	var conflicts []ConfigConflict
+
+	first := make(map[string]int)
+	for i, hard := range config.Hard {
+		if hard.Type != "double_up" && hard.Type != "venue_recovery" && hard.Type != "bye_round_window" {
+			continue
+		}
+
+		j, seen := first[hard.Type]
+		if !seen {
+			first[hard.Type] = i
+			continue
+		}
+
+		conflicts = append(conflicts, ConfigConflict{
+			Code:        "duplicate_constraint",
+			Severity:    ConflictWarning,
+			Message:     fmt.Sprintf("%s is configured more than once; the strictest settings will apply", hard.Type),
+			Constraints: []string{constraintRef("hard", j, hard.Type), constraintRef("hard", i, hard.Type)},
+		})
+	}
+
+	return conflicts
+}
+
+// detectMagicRoundConflicts flags magic rounds after the end of the season,
+// and rounds given to more than one magic round venue
+func detectMagicRoundConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	type magicVenue struct {
+		index   int
+		venueID int
+	}
+
+	var conflicts []ConfigConflict
+
+	venues := make(map[int]magicVenue)
+	for i, hard := range config.Hard {
+		if hard.Type != "magic_round" {
+			continue
+		}
+		round, okRound := hard.Params["round"].(float64)
+		venueID, okVenue := hard.Params["venue_id"].(float64)
+		if !okRound || !okVenue {
+			continue
+		}
+
+		if ctx.Rounds > 0 && int(round) > ctx.Rounds {
+			conflicts = append(conflicts, ConfigConflict{
+				Code:        "magic_round_exceeds_season",
+				Severity:    ConflictError,
+				Message:     fmt.Sprintf("magic round %d is after the end of the %d round season", int(round), ctx.Rounds),
+				Constraints: []string{constraintRef("hard", i, hard.Type)},
+			})
+		}
+
+		other, seen := venues[int(round)]
+		if !seen {
+			venues[int(round)] = magicVenue{index: i, venueID: int(venueID)}
+			continue
+		}
+		if other.venueID == int(venueID) {
+			conflicts = append(conflicts, ConfigConflict{
+				Code:        "duplicate_constraint",
+				Severity:    ConflictWarning,
+				Message:     fmt.Sprintf("magic round %d is configured more than once", int(round)),
+				Constraints: []string{constraintRef("hard", other.index, hard.Type), constraintRef("hard", i, hard.Type)},
+			})
+			continue
+		}
+		conflicts = append(conflicts, ConfigConflict{
+			Code:        "magic_round_clash",
+			Severity:    ConflictError,
+			Message:     fmt.Sprintf("round %d can't be a magic round at both venue %d and venue %d", int(round), other.venueID, int(venueID)),
+			Constraints: []string{constraintRef("hard", other.index, hard.Type), constraintRef("hard", i, hard.Type)},
+		})
+	}
+	return conflicts
+}
+
+// detectPrimeTimeCapConflicts flags prime-time caps whose ranges don't overlap
+func detectPrimeTimeCapConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	type capRange struct {
+		index int
+		min   int
+		max   int
+	}
+
+	var caps []capRange
+	for i, hard := range config.Hard {
+		if hard.Type != "prime_time_cap" {
+			continue
+		}
+		minValue, _ := hard.Params["min_appearances"].(float64)
+		maxValue := float64(NoPrimeTimeCap)
+		if value, ok := hard.Params["max_appearances"].(float64); ok {
+			maxValue = value
+		}
+		caps = append(caps, capRange{index: i, min: int(minValue), max: int(maxValue)})
+	}
+
+	var conflicts []ConfigConflict
+	for a := 0; a < len(caps); a++ {
+		for b := a + 1; b < len(caps); b++ {
+			if !capsDisjoint(caps[a].min, caps[a].max, caps[b].min, caps[b].max) {
+				continue
+			}
+			conflicts = append(conflicts, ConfigConflict{
+				Code:     "prime_time_cap_contradiction",
+				Severity: ConflictError,
+				Message:  "prime_time_cap constraints leave no appearance count that satisfies both",
+				Constraints: []string{
+					constraintRef("hard", caps[a].index, "prime_time_cap"),
+					constraintRef("hard", caps[b].index, "prime_time_cap"),
+				},
+			})
+		}
+	}
+	return conflicts
+}
+
+// capsDisjoint reports whether two min/max ranges have no value in common
+func capsDisjoint(minA, maxA, minB, maxB int) bool {
+	if maxA != NoPrimeTimeCap && minB > maxA {
+		return true
+	}
+	if maxB != NoPrimeTimeCap && minA > maxB {
+		return true
+	}
+	return false
+}
+
+// detectRegionSpreadConflicts flags hard region spreads with a minimum some
+// region can't reach. A region hosts at most one home match per team a round.
+func detectRegionSpreadConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	var conflicts []ConfigConflict
+	for i, hard := range config.Hard {
+		if hard.Type != "region_spread" {
+			continue
+		}
+		minValue, _ := hard.Params["min_home_matches"].(float64)
+		regions, _ := hard.Params["team_regions"].(map[string]interface{})
+
+		teams := make(map[string]int)
+		var names []string
+		for _, value := range regions {
+			region, ok := value.(string)
+			if !ok {
+				continue
+			}
+			if teams[region] == 0 {
+				names = append(names, region)
+			}
+			teams[region]++
+		}
+		sort.Strings(names)
+		for _, region := range names {
+			if teams[region] >= int(minValue) {
+				continue
+			}
+			conflicts = append(conflicts, ConfigConflict{
+				Code:     "region_spread_unreachable",
+				Severity: ConflictError,
+				Message: fmt.Sprintf("region_spread requires %d home matches per round in %s, which has only %d teams",
+					int(minValue), region, teams[region]),
+				Constraints: []string{constraintRef("hard", i, "region_spread")},
+			})
+		}
+	}
+	return conflicts
+}
+
+// detectRivalryRoundConflicts flags hard rivalry fixtures that can't all be
+// played: a team pinned to two different opponents in one round, or a target
+// round after the end of the season
+func detectRivalryRoundConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	type teamRound struct {
+		teamID int
+		round  int
+	}
+	type pinned struct {
+		index   int
+		matchup string
+	}
+
+	var conflicts []ConfigConflict
+
+	pins := make(map[teamRound]pinned)
+	for i, hard := range config.Hard {
+		if hard.Type != "rivalry_round" {
+			continue
+		}
+		fixtures, _ := hard.Params["fixtures"].([]interface{})
+		for _, fixtureInterface := range fixtures {
+			fixture, ok := fixtureInterface.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			teamA, okA := fixture["team_a"].(float64)
+			teamB, okB := fixture["team_b"].(float64)
+			round, okRound := fixture["round"].(float64)
+			if !okA || !okB || !okRound {
+				continue
+			}
+			matchup := MatchupKey(int(teamA), int(teamB))
+
+			if ctx.Rounds > 0 && int(round) > ctx.Rounds {
+				conflicts = append(conflicts, ConfigConflict{
+					Code:        "rivalry_round_exceeds_season",
+					Severity:    ConflictError,
+					Message:     fmt.Sprintf("rivalry fixture %s is pinned to round %d of a %d round season", matchup, int(round), ctx.Rounds),
+					Constraints: []string{constraintRef("hard", i, hard.Type)},
+				})
+			}
+
+			for _, teamID := range []int{int(teamA), int(teamB)} {
+				key := teamRound{teamID, int(round)}
+				other, seen := pins[key]
+				if !seen {
+					pins[key] = pinned{index: i, matchup: matchup}
+					continue
+				}
+				if other.matchup == matchup {
+					continue
+				}
+				conflicts = append(conflicts, ConfigConflict{
+					Code:        "rivalry_round_clash",
+					Severity:    ConflictError,
+					Message:     fmt.Sprintf("team %d is pinned to both %s and %s in round %d", teamID, other.matchup, matchup, int(round)),
+					Constraints: []string{constraintRef("hard", other.index, hard.Type), constraintRef("hard", i, hard.Type)},
+				})
+			}
+		}
+	}
+	return conflicts
+}
+
+// detectVenueDateConflicts flags venues whose dates are described by more than one
+// constraint, where the lists can drift out of step
+func detectVenueDateConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	type venueDate struct {
+		venueID int
+		date    string
+	}
+
+	var conflicts []ConfigConflict
+
+	venueConstraint := make(map[int]int)
+	blocked := make(map[venueDate]int)
+	for i, hard := range config.Hard {
+		if hard.Type != "venue_availability" {
+			continue
+		}
+		venueID, ok := hard.Params["venue_id"].(float64)
+		if !ok {
+			continue
+		}
+
+		if j, seen := venueConstraint[int(venueID)]; seen {
+			conflicts = append(conflicts, ConfigConflict{
+				Code:        "duplicate_venue_constraint",
+				Severity:    ConflictWarning,
+				Message:     fmt.Sprintf("venue %d has more than one venue_availability constraint; merge their unavailable dates", int(venueID)),
+				Constraints: []string{constraintRef("hard", j, hard.Type), constraintRef("hard", i, hard.Type)},
+			})
+		} else {
+			venueConstraint[int(venueID)] = i
+		}
+
+		dates, _ := hard.Params["unavailable_dates"].([]interface{})
+		for _, dateInterface := range dates {
+			if date, ok := parseConfigDate(dateInterface); ok {
+				blocked[venueDate{int(venueID), date}] = i
+			}
+		}
+	}
+
+	for i, hard := range config.Hard {
+		if hard.Type != "venue_recovery" {
+			continue
+		}
+		events, _ := hard.Params["external_events"].([]interface{})
+		for _, eventInterface := range events {
+			event, ok := eventInterface.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			venueID, ok := event["venue_id"].(float64)
+			if !ok {
+				continue
+			}
+			date, ok := parseConfigDate(event["date"])
+			if !ok {
+				continue
+			}
+
+			j, isBlocked := blocked[venueDate{int(venueID), date}]
+			if !isBlocked {
+				continue
+			}
+			conflicts = append(conflicts, ConfigConflict{
+				Code:        "venue_date_overlap",
+				Severity:    ConflictWarning,
+				Message:     fmt.Sprintf("venue %d on %s is blocked by venue_availability and also booked as an external event; keep one so they can't disagree", int(venueID), date),
+				Constraints: []string{constraintRef("hard", j, "venue_availability"), constraintRef("hard", i, hard.Type)},
+			})
+		}
+	}
+	return conflicts
+}
+
+// detectVenueQuarantineConflicts flags quarantined rounds after the end of the
+// season, and magic rounds held at a venue quarantined in that round
+func detectVenueQuarantineConflicts(config ConstraintConfig, ctx ConflictContext) []ConfigConflict {
+	type venueRound struct {
+		venueID int
+		round   int
+	}
+
+	var conflicts []ConfigConflict
+
+	quarantined := make(map[venueRound]int)
+	for i, hard := range config.Hard {
+		if hard.Type != "venue_quarantine" {
+			continue
+		}
+		venueID, ok := hard.Params["venue_id"].(float64)
+		if !ok {
+			continue
+		}
+		rounds, _ := hard.Params["rounds"].([]interface{})
+
+		var outside []int
+		for _, roundInterface := range rounds {
+			round, ok := roundInterface.(float64)
+			if !ok {
+				continue
+			}
+			if ctx.Rounds > 0 && int(round) > ctx.Rounds {
+				outside = append(outside, int(round))
+			}
+			quarantined[venueRound{int(venueID), int(round)}] = i
+		}
+		if len(outside) > 0 {
+			conflicts = append(conflicts, ConfigConflict{
+				Code:        "venue_quarantine_exceeds_season",
+				Severity:    ConflictWarning,
+				Message:     fmt.Sprintf("venue_quarantine rounds %v for venue %d are after the %d round season and have no effect", outside, int(venueID), ctx.Rounds),
+				Constraints: []string{constraintRef("hard", i, hard.Type)},
+			})
+		}
+	}
+
+	for i, hard := range config.Hard {
+		if hard.Type != "magic_round" {
+			continue
+		}
+		round, okRound := hard.Params["round"].(float64)
+		venueID, okVenue := hard.Params["venue_id"].(float64)
+		if !okRound || !okVenue {
+			continue
+		}
+
+		j, isQuarantined := quarantined[venueRound{int(venueID), int(round)}]
+		if !isQuarantined {
+			continue
+		}
+		conflicts = append(conflicts, ConfigConflict{
+			Code:        "magic_round_quarantined_venue",
+			Severity:    ConflictError,
+			Message:     fmt.Sprintf("magic round %d is at venue %d, which is quarantined in that round", int(round), int(venueID)),
+			Constraints: []string{constraintRef("hard", j, "venue_quarantine"), constraintRef("hard", i, hard.Type)},
+		})
+	}
+	return conflicts
+}
+
+// parseConfigDate normalises a YYYY-MM-DD date parameter
+func parseConfigDate(value interface{}) (string, bool) {
+	dateStr, ok := value.(string)
+	if !ok {
+		return "", false
+	}
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return "", false
+	}
+	return date.Format("2006-01-02"), true
+}
+
+// constraintRef identifies a constraint within a configuration
+func constraintRef(kind string, index int, constraintType string) string {
+	return fmt.Sprintf("%s[%d]:%s", kind, index, constraintType)
+}
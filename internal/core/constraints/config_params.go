@@ -0,0 +1,172 @@
+package constraints
+
+import "fmt"
+
+// paramRule checks one constraint's params make sense together and for the
+// season. It returns problems without Constraints set; checkParams fills
+// that in.
+type paramRule func(params map[string]interface{}, ctx ConflictContext) []ConfigConflict
+
+// paramRules are keyed by constraint type and apply to hard and soft
+// constraints alike
+var paramRules = map[string]paramRule{
+	"double_up":           checkDoubleUpParams,
+	"half_season_travel":  checkHalfSeasonTravelParams,
+	"home_away_balance":   checkHomeAwayBalanceParams,
+	"prime_time_cap":      checkPrimeTimeCapParams,
+	"prime_time_spread":   checkPrimeTimeSpreadParams,
+	"travel_minimization": checkTravelMinimizationParams,
+}
+
+// checkParams applies the constraint type's param rule, if it has one
+func checkParams(kind string, index int, constraintType string, params map[string]interface{}, ctx ConflictContext) []ConfigConflict {
+	rule, ok := paramRules[constraintType]
+	if !ok {
+		return nil
+	}
+	issues := rule(params, ctx)
+	for i := range issues {
+		issues[i].Constraints = []string{constraintRef(kind, index, constraintType)}
+	}
+	return issues
+}
+
+// singleRoundRobinRounds returns the rounds needed for every team to play
+// each other once, a bye round included for an odd number of teams
+func singleRoundRobinRounds(teams int) int {
+	if teams%2 == 1 {
+		return teams
+	}
+	return teams - 1
+}
+
+// checkDoubleUpParams rejects separations that can't be kept when the season
+// is long enough that some teams must meet twice
+func checkDoubleUpParams(params map[string]interface{}, ctx ConflictContext) []ConfigConflict {
+	separation, _ := params["min_rounds_separation"].(float64)
+	if separation < 0 {
+		return []ConfigConflict{{
+			Code:     "double_up_negative_separation",
+			Severity: ConflictError,
+			Message:  "double_up min_rounds_separation can't be negative",
+		}}
+	}
+
+	if ctx.Teams < 2 || ctx.Rounds <= singleRoundRobinRounds(ctx.Teams) || int(separation) < ctx.Rounds {
+		return nil
+	}
+	return []ConfigConflict{{
+		Code:     "double_up_impossible",
+		Severity: ConflictError,
+		Message: fmt.Sprintf("double_up separation of %d rounds can't be kept: %d teams over %d rounds must meet some opponents twice",
+			int(separation), ctx.Teams, ctx.Rounds),
+	}}
+}
+
+// checkHalfSeasonTravelParams flags split rounds that leave no second half
+func checkHalfSeasonTravelParams(params map[string]interface{}, ctx ConflictContext) []ConfigConflict {
+	splitRound, ok := params["split_round"].(float64)
+	if !ok || ctx.Rounds <= 0 || int(splitRound) < ctx.Rounds {
+		return nil
+	}
+	return []ConfigConflict{{
+		Code:     "half_season_split_exceeds_season",
+		Severity: ConflictWarning,
+		Message: fmt.Sprintf("half_season_travel split_round of %d is not before the end of the %d round season, so there's no second half to balance against",
+			int(splitRound), ctx.Rounds),
+	}}
+}
+
+// checkHomeAwayBalanceParams flags deviations that allow any balance
+func checkHomeAwayBalanceParams(params map[string]interface{}, ctx ConflictContext) []ConfigConflict {
+	deviation, _ := params["max_deviation"].(float64)
+	switch {
+	case deviation < 0:
+		return []ConfigConflict{{
+			Code:     "home_away_negative_deviation",
+			Severity: ConflictError,
+			Message:  "home_away_balance max_deviation can't be negative",
+		}}
+	case deviation >= 0.5:
+		return []ConfigConflict{{
+			Code:     "home_away_deviation_too_wide",
+			Severity: ConflictWarning,
+			Message:  fmt.Sprintf("home_away_balance max_deviation of %g allows any home/away split, so the constraint has no effect", deviation),
+		}}
+	}
+	return nil
+}
+
+// checkPrimeTimeCapParams rejects minimum appearances no team can reach in
+// the season
+func checkPrimeTimeCapParams(params map[string]interface{}, ctx ConflictContext) []ConfigConflict {
+	minAppearances, _ := params["min_appearances"].(float64)
+	if ctx.Rounds <= 0 || int(minAppearances) <= ctx.Rounds {
+		return nil
+	}
+	return []ConfigConflict{{
+		Code:     "prime_time_cap_unreachable",
+		Severity: ConflictError,
+		Message: fmt.Sprintf("prime_time_cap min_appearances of %d is more than the %d matches a team plays in the season",
+			int(minAppearances), ctx.Rounds),
+	}}
+}
+
+// checkPrimeTimeSpreadParams rejects ratios outside 0 to 1, and flags
+// tolerances that reach past either end
+func checkPrimeTimeSpreadParams(params map[string]interface{}, ctx ConflictContext) []ConfigConflict {
+	target, _ := params["target_ratio"].(float64)
+	deviation, _ := params["max_deviation"].(float64)
+
+	var issues []ConfigConflict
+	if target < 0 || target > 1 {
+		issues = append(issues, ConfigConflict{
+			Code:     "prime_time_ratio_out_of_range",
+			Severity: ConflictError,
+			Message:  fmt.Sprintf("prime_time_spread target_ratio of %g must be between 0 and 1", target),
+		})
+	}
+	if deviation < 0 {
+		issues = append(issues, ConfigConflict{
+			Code:     "prime_time_negative_deviation",
+			Severity: ConflictError,
+			Message:  "prime_time_spread max_deviation can't be negative",
+		})
+	}
+	if len(issues) > 0 {
+		return issues
+	}
+
+	low, high := target-deviation, target+deviation
+	switch {
+	case low <= 0 && high >= 1:
+		issues = append(issues, ConfigConflict{
+			Code:     "prime_time_deviation_too_wide",
+			Severity: ConflictWarning,
+			Message: fmt.Sprintf("prime_time_spread target_ratio %g ± %g allows any share of prime-time games, so the constraint has no effect",
+				target, deviation),
+		})
+	case low < 0 || high > 1:
+		issues = append(issues, ConfigConflict{
+			Code:     "prime_time_deviation_exceeds_range",
+			Severity: ConflictWarning,
+			Message: fmt.Sprintf("prime_time_spread target_ratio %g ± %g reaches past the 0 to 1 range of possible shares",
+				target, deviation),
+		})
+	}
+	return issues
+}
+
+// checkTravelMinimizationParams flags away streak limits the season can't reach
+func checkTravelMinimizationParams(params map[string]interface{}, ctx ConflictContext) []ConfigConflict {
+	maxConsecutive, ok := params["max_consecutive_away"].(float64)
+	if !ok || ctx.Rounds <= 0 || int(maxConsecutive) < ctx.Rounds {
+		return nil
+	}
+	return []ConfigConflict{{
+		Code:     "travel_away_limit_exceeds_season",
+		Severity: ConflictWarning,
+		Message: fmt.Sprintf("travel_minimization max_consecutive_away of %d is not shorter than the %d round season, so it never applies",
+			int(maxConsecutive), ctx.Rounds),
+	}}
+}
@@ -2,7 +2,13 @@ package constraints
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 )
 
 // TestConstraintFactory tests constraint creation from configuration
@@ -52,6 +58,89 @@ func TestConstraintFactory(t *testing.T) {
 	if softConstraint.IsHard() {
 		t.Error("Travel minimization should be soft constraint")
 	}
+	
+	// Distance-only travel limits don't need an away streak limit
+	distanceConstraint, err := factory.createSoftConstraint(SoftConstraintConfig{
+		Type:   "travel_minimization",
+		Weight: 0.5,
+		Params: map[string]interface{}{"max_total_travel_km": float64(30000)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create distance travel constraint: %v", err)
+	}
+	travel := distanceConstraint.(*TravelMinimizationConstraint)
+	if travel.GetMaxTotalTravelKm() != 30000 || travel.GetMaxConsecutiveAway() != NoConsecutiveAwayLimit {
+		t.Error("Wrong travel limits")
+	}
+	
+	if _, err := factory.createSoftConstraint(SoftConstraintConfig{Type: "travel_minimization", Params: map[string]interface{}{}}); err == nil {
+		t.Error("Should require a travel limit")
+	}
+	
+	// Test creating venue recovery constraint from JSON-shaped params
+	recoveryConfig := HardConstraintConfig{
+		Type: "venue_recovery",
+		Params: map[string]interface{}{
+			"min_recovery_days":   float64(5),
+			"venue_recovery_days": map[string]interface{}{"2": float64(3)},
+			"external_events": []interface{}{
+				map[string]interface{}{"venue_id": float64(2), "date": "2025-06-20", "name": "Concert"},
+			},
+		},
+	}
+	
+	constraint, err = factory.createHardConstraint(recoveryConfig)
+	if err != nil {
+		t.Fatalf("Failed to create venue recovery constraint: %v", err)
+	}
+	
+	recovery, ok := constraint.(*VenueRecoveryConstraint)
+	if !ok {
+		t.Fatal("Expected a venue recovery constraint")
+	}
+	if recovery.GetRecoveryDays(2) != 3 || recovery.GetRecoveryDays(1) != 5 {
+		t.Error("Wrong recovery days")
+	}
+	if len(recovery.GetExternalEvents()) != 1 {
+		t.Error("Expected 1 external event")
+	}
+	
+	// Test creating bye round window constraint
+	constraint, err = factory.createHardConstraint(HardConstraintConfig{
+		Type:   "bye_round_window",
+		Params: map[string]interface{}{"bye_rounds": []interface{}{float64(13), float64(16), float64(19)}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bye round window constraint: %v", err)
+	}
+	window, ok := constraint.(*ByeRoundWindowConstraint)
+	if !ok {
+		t.Fatal("Expected a bye round window constraint")
+	}
+	if !window.IsAllowedRound(16) || window.IsAllowedRound(14) {
+		t.Error("Wrong allowed bye rounds")
+	}
+	
+	// Rivalry rounds can be configured as hard or soft constraints
+	rivalryParams := map[string]interface{}{
+		"fixtures": []interface{}{
+			map[string]interface{}{"team_a": float64(1), "team_b": float64(2), "round": float64(1)},
+		},
+	}
+	constraint, err = factory.createHardConstraint(HardConstraintConfig{Type: "rivalry_round", Params: rivalryParams})
+	if err != nil {
+		t.Fatalf("Failed to create hard rivalry round constraint: %v", err)
+	}
+	if !constraint.IsHard() || len(constraint.(*RivalryRoundConstraint).GetFixtures()) != 1 {
+		t.Error("Expected a hard rivalry round constraint with 1 fixture")
+	}
+	softConstraint, err = factory.createSoftConstraint(SoftConstraintConfig{Type: "rivalry_round", Weight: 0.6, Params: rivalryParams})
+	if err != nil {
+		t.Fatalf("Failed to create soft rivalry round constraint: %v", err)
+	}
+	if softConstraint.IsHard() {
+		t.Error("Rivalry round configured as soft should be a soft constraint")
+	}
 }
 
 // TestConstraintFactoryErrors tests error handling in constraint creation
@@ -96,6 +185,157 @@ func TestConstraintFactoryErrors(t *testing.T) {
 	if err == nil {
 		t.Error("Should return error for invalid date format")
 	}
+	
+	// Test custom expressions that don't parse
+	for _, params := range []map[string]interface{}{
+		{},
+		{"expression": "round =="},
+		{"expression": "round == 1", "description": float64(1)},
+	} {
+		_, err = factory.createSoftConstraint(SoftConstraintConfig{Type: "custom_expression", Weight: 0.5, Params: params})
+		if err == nil {
+			t.Errorf("Should return error for custom expression params %v", params)
+		}
+	}
+	
+	// Test prime-time caps with minimum above maximum
+	primeTimeCapConfig := HardConstraintConfig{
+		Type: "prime_time_cap",
+		Params: map[string]interface{}{
+			"min_appearances": float64(6),
+			"max_appearances": float64(4),
+		},
+	}
+	
+	_, err = factory.createHardConstraint(primeTimeCapConfig)
+	if err == nil {
+		t.Error("Should return error when min_appearances exceeds max_appearances")
+	}
+	
+	// Test prime-time caps with no bounds
+	primeTimeCapConfig.Params = map[string]interface{}{}
+	_, err = factory.createHardConstraint(primeTimeCapConfig)
+	if err == nil {
+		t.Error("Should return error when no prime-time caps are given")
+	}
+	
+	// Test broadcaster quotas with an unknown day and a backwards kickoff window
+	quotaConfig := HardConstraintConfig{
+		Type: "broadcaster_quota",
+		Params: map[string]interface{}{
+			"days":            []interface{}{"thursday", "someday"},
+			"max_appearances": float64(5),
+		},
+	}
+	_, err = factory.createHardConstraint(quotaConfig)
+	if err == nil {
+		t.Error("Should return error for an unknown quota day")
+	}
+	
+	quotaConfig.Params["days"] = []interface{}{"thu"}
+	quotaConfig.Params["from"] = "21:00"
+	quotaConfig.Params["to"] = "19:00"
+	_, err = factory.createHardConstraint(quotaConfig)
+	if err == nil {
+		t.Error("Should return error when the quota window ends before it starts")
+	}
+	
+	delete(quotaConfig.Params, "to")
+	quotaConstraint, err := factory.createHardConstraint(quotaConfig)
+	if err != nil {
+		t.Fatalf("Failed to create broadcaster quota: %v", err)
+	}
+	quota := quotaConstraint.(*BroadcasterQuotaConstraint)
+	if quota.GetMinAppearances() != 0 || quota.GetMaxAppearances() != 5 || quota.GetCategory().Days[0] != time.Thursday {
+		t.Errorf("Unexpected broadcaster quota %+v", quota.GetCategory())
+	}
+	
+	// Test rest periods with neither a minimum nor a turnaround cap
+	restConfig := HardConstraintConfig{
+		Type:   "rest_period",
+		Params: map[string]interface{}{"short_turnaround_days": float64(5)},
+	}
+	_, err = factory.createHardConstraint(restConfig)
+	if err == nil {
+		t.Error("Should return error without min_rest_days or max_short_turnarounds")
+	}
+	
+	restConfig.Params["max_short_turnarounds"] = float64(3)
+	restConstraint, err := factory.createHardConstraint(restConfig)
+	if err != nil {
+		t.Fatalf("Failed to create hard rest period: %v", err)
+	}
+	rest := restConstraint.(*RestPeriodConstraint)
+	if !rest.IsHard() || rest.GetMinRestDays() != 0 || rest.GetMaxShortTurnarounds() != 3 {
+		t.Errorf("Unexpected rest period: min %d, max %d", rest.GetMinRestDays(), rest.GetMaxShortTurnarounds())
+	}
+	
+	// Test expected crowd popularity keyed by something other than a team ID
+	_, err = factory.createSoftConstraint(SoftConstraintConfig{
+		Type:   "expected_crowd",
+		Params: map[string]interface{}{"team_popularity": map[string]interface{}{"broncos": 1.5}},
+	})
+	if err == nil {
+		t.Error("Should return error for a non-numeric team ID in team_popularity")
+	}
+	
+	// Test bye round windows without rounds
+	byeWindowConfig := HardConstraintConfig{
+		Type:   "bye_round_window",
+		Params: map[string]interface{}{"bye_rounds": []interface{}{}},
+	}
+	_, err = factory.createHardConstraint(byeWindowConfig)
+	if err == nil {
+		t.Error("Should return error when no bye rounds are given")
+	}
+	
+	byeWindowConfig.Params = map[string]interface{}{"bye_rounds": []interface{}{float64(0)}}
+	_, err = factory.createHardConstraint(byeWindowConfig)
+	if err == nil {
+		t.Error("Should return error for a non-positive bye round")
+	}
+	
+	// Test a rivalry fixture between a team and itself
+	_, err = factory.createHardConstraint(HardConstraintConfig{
+		Type: "rivalry_round",
+		Params: map[string]interface{}{
+			"fixtures": []interface{}{
+				map[string]interface{}{"team_a": float64(1), "team_b": float64(1), "round": float64(1)},
+			},
+		},
+	})
+	if err == nil {
+		t.Error("Should return error for a rivalry fixture without two teams")
+	}
+	
+	// Test derbies without a distance, or with a round before the season
+	_, err = factory.createSoftConstraint(SoftConstraintConfig{Type: "derby", Params: map[string]interface{}{}})
+	if err == nil {
+		t.Error("Should return error without max_distance_km")
+	}
+	
+	_, err = factory.createSoftConstraint(SoftConstraintConfig{
+		Type:   "derby",
+		Params: map[string]interface{}{"max_distance_km": float64(50), "marquee_rounds": []interface{}{float64(0)}},
+	})
+	if err == nil {
+		t.Error("Should return error for a non-positive marquee round")
+	}
+	
+	// Test double headers without a whole partner draw ID
+	_, err = factory.createSoftConstraint(SoftConstraintConfig{
+		Type:   "double_header",
+		Params: map[string]interface{}{"partner_draw_id": 1.5},
+	})
+	if err == nil {
+		t.Error("Should return error for a fractional partner_draw_id")
+	}
+	
+	// Test fixture variation without last season's draw
+	_, err = factory.createSoftConstraint(SoftConstraintConfig{Type: "fixture_variation", Params: map[string]interface{}{}})
+	if err == nil {
+		t.Error("Should return error without previous_draw_id")
+	}
 }
 
 // TestConstraintEngineFromConfig tests creating constraint engine from configuration
@@ -225,6 +465,62 @@ func TestJSONSerialization(t *testing.T) {
 	}
 }
 
+func TestYAMLSerialization(t *testing.T) {
+	yamlData := []byte(`
+hard:
+  - type: venue_availability
+    params:
+      venue_id: 3
+      unavailable_dates:
+        - 2025-06-15
+        - "2025-07-04"
+soft:
+  - type: rest_period
+    weight: 0.9
+    params:
+      min_rest_days: 5
+`)
+	
+	config, err := LoadConstraintConfigFromYAML(yamlData)
+	if err != nil {
+		t.Fatalf("Failed to load config from YAML: %v", err)
+	}
+	
+	// Unquoted dates and integers must read as they would from JSON
+	params := config.Hard[0].Params
+	if params["venue_id"] != float64(3) {
+		t.Errorf("venue_id = %#v, want float64(3)", params["venue_id"])
+	}
+	dates, ok := params["unavailable_dates"].([]interface{})
+	if !ok || len(dates) != 2 || dates[0] != "2025-06-15" || dates[1] != "2025-07-04" {
+		t.Errorf("unavailable_dates = %#v", params["unavailable_dates"])
+	}
+	if config.Soft[0].Weight != 0.9 {
+		t.Error("Soft constraint weight mismatch")
+	}
+	
+	if _, err := NewConstraintFactory().CreateConstraintEngine(config); err != nil {
+		t.Fatalf("Failed to build engine from YAML config: %v", err)
+	}
+	
+	// Save to YAML and load it back
+	saved, err := SaveConstraintConfigToYAML(config)
+	if err != nil {
+		t.Fatalf("Failed to save config to YAML: %v", err)
+	}
+	reloaded, err := LoadConstraintConfigFromYAML(saved)
+	if err != nil {
+		t.Fatalf("Failed to reload saved YAML: %v", err)
+	}
+	if reloaded.Hard[0].Type != "venue_availability" || reloaded.Soft[0].Params["min_rest_days"] != float64(5) {
+		t.Errorf("YAML round-trip mismatch: %+v", reloaded)
+	}
+	
+	if _, err := LoadConstraintConfigFromYAML([]byte("hard: [")); err == nil {
+		t.Error("expected an error for malformed YAML")
+	}
+}
+
 // TestDefaultNRLConfig tests the default NRL configuration
 func TestDefaultNRLConfig(t *testing.T) {
 	config := GetDefaultNRLConstraintConfig()
@@ -245,6 +541,88 @@ func TestDefaultNRLConfig(t *testing.T) {
 	}
 }
 
+// TestDetectConfigConflicts tests detection of contradictory configurations
+func TestDetectConfigConflicts(t *testing.T) {
+	// The default configuration should be conflict free for a full season
+	if conflicts := DetectConfigConflicts(GetDefaultNRLConstraintConfig(), ConflictContext{Rounds: 27}); len(conflicts) != 0 {
+		t.Errorf("Default NRL config should have no conflicts, got %+v", conflicts)
+	}
+	
+	config := ConstraintConfig{
+		Hard: []HardConstraintConfig{
+			{Type: "double_up", Params: map[string]interface{}{"min_rounds_separation": 30.0}},
+			{Type: "prime_time_cap", Params: map[string]interface{}{"max_appearances": 4.0}},
+			{Type: "prime_time_cap", Params: map[string]interface{}{"min_appearances": 6.0}},
+			{Type: "venue_availability", Params: map[string]interface{}{"venue_id": 1.0, "unavailable_dates": []interface{}{"2025-04-01"}}},
+			{Type: "venue_availability", Params: map[string]interface{}{"venue_id": 1.0, "unavailable_dates": []interface{}{"2025-05-01"}}},
+			{Type: "venue_recovery", Params: map[string]interface{}{
+				"min_recovery_days": 3.0,
+				"external_events":   []interface{}{map[string]interface{}{"venue_id": 1.0, "date": "2025-04-01"}},
+			}},
+			{Type: "bye_round_window", Params: map[string]interface{}{"bye_rounds": []interface{}{13.0, 30.0}}},
+			{Type: "bye_round_window", Params: map[string]interface{}{"bye_rounds": []interface{}{28.0}}},
+			{Type: "rivalry_round", Params: map[string]interface{}{"fixtures": []interface{}{
+				map[string]interface{}{"team_a": 1.0, "team_b": 2.0, "round": 1.0},
+				map[string]interface{}{"team_a": 1.0, "team_b": 3.0, "round": 1.0},
+				map[string]interface{}{"team_a": 4.0, "team_b": 5.0, "round": 29.0},
+			}}},
+			{Type: "magic_round", Params: map[string]interface{}{"round": 10.0, "venue_id": 1.0}},
+			{Type: "magic_round", Params: map[string]interface{}{"round": 10.0, "venue_id": 2.0}},
+			{Type: "magic_round", Params: map[string]interface{}{"round": 31.0, "venue_id": 1.0}},
+			{Type: "venue_quarantine", Params: map[string]interface{}{"venue_id": 1.0, "rounds": []interface{}{10.0, 28.0}}},
+			{Type: "region_spread", Params: map[string]interface{}{
+				"team_regions":     map[string]interface{}{"1": "Sydney", "2": "Sydney", "3": "Brisbane"},
+				"min_home_matches": 2.0,
+			}},
+		},
+	}
+	
+	conflicts := DetectConfigConflicts(config, ConflictContext{Rounds: 27})
+	
+	codes := make(map[string]ConflictSeverity)
+	for _, conflict := range conflicts {
+		codes[conflict.Code] = conflict.Severity
+		if len(conflict.Constraints) == 0 {
+			t.Errorf("Conflict %s should reference the constraints involved", conflict.Code)
+		}
+	}
+	
+	expected := map[string]ConflictSeverity{
+		"double_up_exceeds_season":     ConflictWarning,
+		"prime_time_cap_contradiction": ConflictError,
+		"duplicate_venue_constraint":   ConflictWarning,
+		"venue_date_overlap":           ConflictWarning,
+		"bye_window_exceeds_season":    ConflictWarning,
+		"bye_window_outside_season":    ConflictError,
+		"duplicate_constraint":         ConflictWarning,
+		"rivalry_round_clash":          ConflictError,
+		"rivalry_round_exceeds_season": ConflictError,
+		"magic_round_clash":            ConflictError,
+		"magic_round_exceeds_season":   ConflictError,
+		"magic_round_quarantined_venue": ConflictError,
+		"venue_quarantine_exceeds_season": ConflictWarning,
+		"region_spread_unreachable":    ConflictError,
+	}
+	for code, severity := range expected {
+		if got, ok := codes[code]; !ok {
+			t.Errorf("Expected conflict %s to be detected", code)
+		} else if got != severity {
+			t.Errorf("Expected conflict %s to have severity %s, got %s", code, severity, got)
+		}
+	}
+	
+	if !HasBlockingConflicts(conflicts) {
+		t.Error("Disjoint prime-time caps should block generation")
+	}
+	
+	// Season length is only checked when known
+	for _, conflict := range DetectConfigConflicts(config, ConflictContext{}) {
+		if conflict.Code == "double_up_exceeds_season" {
+			t.Error("Double-up season check should be skipped when rounds are unknown")
+		}
+	}
+}
+
 // TestConstraintConfigValidation tests configuration validation
 func TestConstraintConfigValidation(t *testing.T) {
 	// Test valid configuration
@@ -266,7 +644,7 @@ func TestConstraintConfigValidation(t *testing.T) {
 		},
 	}
 	
-	err := ValidateConstraintConfig(validConfig)
+	_, err := ValidateConstraintConfig(validConfig, ConflictContext{})
 	if err != nil {
 		t.Errorf("Valid config should pass validation: %v", err)
 	}
@@ -284,7 +662,7 @@ func TestConstraintConfigValidation(t *testing.T) {
 		},
 	}
 	
-	err = ValidateConstraintConfig(invalidWeightConfig)
+	_, err = ValidateConstraintConfig(invalidWeightConfig, ConflictContext{})
 	if err == nil {
 		t.Error("Should reject config with weight > 1")
 	}
@@ -299,12 +677,76 @@ func TestConstraintConfigValidation(t *testing.T) {
 		},
 	}
 	
-	err = ValidateConstraintConfig(emptyTypeConfig)
+	_, err = ValidateConstraintConfig(emptyTypeConfig, ConflictContext{})
 	if err == nil {
 		t.Error("Should reject config with empty constraint type")
 	}
 }
 
+// TestValidateConstraintConfigParams tests params checked against each other and the draw
+func TestValidateConstraintConfigParams(t *testing.T) {
+	meta := ConflictContext{Teams: 17, Rounds: 27}
+	
+	// The defaults make sense for an NRL season
+	issues, err := ValidateConstraintConfig(GetDefaultNRLConstraintConfig(), meta)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("Expected no issues with the defaults, got %v (%v)", issues, err)
+	}
+	
+	// Teams must meet twice over 27 rounds, which a 30 round separation rules out
+	doubleUp := ConstraintConfig{Hard: []HardConstraintConfig{
+		{Type: "double_up", Params: map[string]interface{}{"min_rounds_separation": float64(30)}},
+	}}
+	if _, err := ValidateConstraintConfig(doubleUp, meta); err == nil {
+		t.Error("Expected an error for a separation longer than the season")
+	}
+	// A single round-robin has no repeat meetings to separate
+	if _, err := ValidateConstraintConfig(doubleUp, ConflictContext{Teams: 17, Rounds: 17}); err != nil {
+		t.Errorf("Expected no error without repeat meetings, got %v", err)
+	}
+	// Nor can it be judged without the draw
+	if _, err := ValidateConstraintConfig(doubleUp, ConflictContext{}); err != nil {
+		t.Errorf("Expected no error without draw details, got %v", err)
+	}
+	
+	// A tolerance past 100% prime time is suspicious but usable
+	primeTime := ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "prime_time_spread", Weight: 0.5, Params: map[string]interface{}{"target_ratio": 0.9, "max_deviation": 0.5}},
+	}}
+	issues, err = ValidateConstraintConfig(primeTime, meta)
+	if err != nil {
+		t.Fatalf("Expected a warning only, got %v", err)
+	}
+	if len(issues) != 1 || issues[0].Severity != ConflictWarning || issues[0].Code != "prime_time_deviation_exceeds_range" {
+		t.Errorf("Expected a prime time range warning, got %v", issues)
+	}
+	if issues[0].Constraints[0] != "soft[0]:prime_time_spread" {
+		t.Errorf("Expected the warning to name the constraint, got %v", issues[0].Constraints)
+	}
+	
+	primeTime.Soft[0].Params["target_ratio"] = 1.5
+	if _, err := ValidateConstraintConfig(primeTime, meta); err == nil {
+		t.Error("Expected an error for a target ratio above 1")
+	}
+	
+	capped := ConstraintConfig{Hard: []HardConstraintConfig{
+		{Type: "prime_time_cap", Params: map[string]interface{}{"min_appearances": float64(28)}},
+	}}
+	if _, err := ValidateConstraintConfig(capped, meta); err == nil {
+		t.Error("Expected an error for more prime-time appearances than rounds")
+	}
+	
+	balance := ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "home_away_balance", Weight: 0.5, Params: map[string]interface{}{"max_deviation": 0.5}},
+		{Type: "travel_minimization", Weight: 0.5, Params: map[string]interface{}{"max_consecutive_away": float64(27)}},
+		{Type: "half_season_travel", Weight: 0.5, Params: map[string]interface{}{"split_round": float64(27)}},
+	}}
+	issues, err = ValidateConstraintConfig(balance, meta)
+	if err != nil || len(issues) != 3 {
+		t.Errorf("Expected three warnings, got %v (%v)", issues, err)
+	}
+}
+
 // TestConstraintTypeInfo tests constraint type information
 func TestConstraintTypeInfo(t *testing.T) {
 	info := GetConstraintTypeInfo()
@@ -312,10 +754,19 @@ func TestConstraintTypeInfo(t *testing.T) {
 	// Check that all known constraint types are present
 	expectedTypes := []string{
 		"venue_availability",
+		"venue_quarantine",
 		"bye_constraint", 
 		"team_availability",
 		"double_up",
+		"prime_time_cap",
+		"venue_recovery",
+		"bye_round_window",
+		"rivalry_round",
+		"magic_round",
+		"custom_expression",
 		"travel_minimization",
+		"half_season_travel",
+		"strength_spread",
 		"rest_period",
 		"prime_time_spread",
 		"home_away_balance",
@@ -338,6 +789,162 @@ func TestConstraintTypeInfo(t *testing.T) {
 	}
 }
 
+func TestConstraintSchemas(t *testing.T) {
+	info := GetConstraintTypeInfo()
+	schemas := GetConstraintSchemas()
+	factory := NewConstraintFactory()
+	
+	if len(schemas) != len(info) {
+		t.Errorf("got %d schemas for %d constraint types", len(schemas), len(info))
+	}
+	
+	for name, typeInfo := range info {
+		schema, ok := schemas[name]
+		if !ok {
+			t.Errorf("Missing schema for constraint type %s", name)
+			continue
+		}
+		
+		// Every documented parameter is in the schema
+		for param := range typeInfo.Parameters {
+			if _, ok := schema.Params.Properties[param]; !ok {
+				t.Errorf("%s schema is missing parameter %s", name, param)
+			}
+		}
+		for _, required := range schema.Params.Required {
+			if _, ok := schema.Params.Properties[required]; !ok {
+				t.Errorf("%s schema requires undeclared parameter %s", name, required)
+			}
+		}
+		
+		// Hard and soft flags match what the factory builds
+		_, err := factory.createHardConstraint(HardConstraintConfig{Type: name, Params: map[string]interface{}{}})
+		buildsHard := err == nil || !strings.HasPrefix(err.Error(), "unknown hard constraint type")
+		_, err = factory.createSoftConstraint(SoftConstraintConfig{Type: name, Params: map[string]interface{}{}})
+		buildsSoft := err == nil || !strings.HasPrefix(err.Error(), "unknown soft constraint type")
+		if schema.Hard != buildsHard || schema.Soft != buildsSoft {
+			t.Errorf("%s schema has hard=%v soft=%v, factory builds hard=%v soft=%v",
+				name, schema.Hard, schema.Soft, buildsHard, buildsSoft)
+		}
+	}
+	
+	// The default configuration supplies every required parameter
+	for name, defaults := range GetDefaultConstraintParams() {
+		for _, required := range schemas[name].Params.Required {
+			if _, ok := defaults.Params[required]; !ok {
+				t.Errorf("default %s config is missing required parameter %s", name, required)
+			}
+		}
+	}
+	
+	config := ConstraintConfigSchema()
+	if config.Schema != JSONSchemaDraft {
+		t.Errorf("$schema = %q, want %q", config.Schema, JSONSchemaDraft)
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config schema: %v", err)
+	}
+	if !strings.Contains(string(data), `"additionalProperties":false`) {
+		t.Error("config schema should reject unknown fields")
+	}
+}
+
+// blackoutDayConstraint is a downstream constraint type used to test Register
+type blackoutDayConstraint struct {
+	BaseConstraint
+	day time.Weekday
+}
+
+func (c *blackoutDayConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	if match.MatchDate != nil && match.MatchDate.Weekday() == c.day {
+		return fmt.Errorf("match %d is on a %s", match.ID, c.day)
+	}
+	return nil
+}
+
+func (c *blackoutDayConstraint) Score(draw *models.Draw) float64 {
+	return 1.0
+}
+
+func (c *blackoutDayConstraint) Params() map[string]interface{} {
+	return map[string]interface{}{"day": strings.ToLower(c.day.String())}
+}
+
+func TestRegisterConstraintType(t *testing.T) {
+	info := ConstraintTypeInfo{
+		Type:        "hard",
+		Description: "No matches on the blackout day",
+		Parameters:  map[string]string{"day": "string - Day with no matches"},
+	}
+	builder := func(params map[string]interface{}, isHard bool) (Constraint, error) {
+		name, _ := params["day"].(string)
+		day, err := ParseWeekday(name)
+		if err != nil {
+			return nil, err
+		}
+		return &blackoutDayConstraint{
+			BaseConstraint: NewBaseConstraint("BlackoutDay", "No matches on "+day.String(), isHard),
+			day:            day,
+		}, nil
+	}
+	
+	if err := Register("blackout_day", info, builder); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	t.Cleanup(func() { Unregister("blackout_day") })
+	
+	if err := Register("blackout_day", info, builder); !errors.Is(err, ErrConstraintTypeTaken) {
+		t.Errorf("registering twice: error = %v, want ErrConstraintTypeTaken", err)
+	}
+	if err := Register("double_up", info, builder); !errors.Is(err, ErrConstraintTypeTaken) {
+		t.Errorf("replacing a built-in type: error = %v, want ErrConstraintTypeTaken", err)
+	}
+	if err := Register("no_builder", info, nil); !errors.Is(err, ErrInvalidRegistration) {
+		t.Errorf("registering without a builder: error = %v, want ErrInvalidRegistration", err)
+	}
+	
+	if _, ok := GetConstraintTypeInfo()["blackout_day"]; !ok {
+		t.Error("registered type missing from GetConstraintTypeInfo")
+	}
+	if schema, ok := GetConstraintSchemas()["blackout_day"]; !ok || !schema.Hard || schema.Soft {
+		t.Errorf("registered type schema = %+v, want a hard-only schema", schema)
+	}
+	
+	// Validation reflects the registered type and its builder's errors
+	valid := ConstraintConfig{Hard: []HardConstraintConfig{{Type: "blackout_day", Params: map[string]interface{}{"day": "sunday"}}}}
+	if _, err := ValidateConstraintConfig(valid, ConflictContext{}); err != nil {
+		t.Errorf("ValidateConstraintConfig() error = %v", err)
+	}
+	badDay := ConstraintConfig{Hard: []HardConstraintConfig{{Type: "blackout_day", Params: map[string]interface{}{"day": "someday"}}}}
+	if _, err := ValidateConstraintConfig(badDay, ConflictContext{}); err == nil {
+		t.Error("expected the builder's error for an unknown day")
+	}
+	asSoft := ConstraintConfig{Soft: []SoftConstraintConfig{{Type: "blackout_day", Weight: 0.5, Params: map[string]interface{}{"day": "sunday"}}}}
+	if _, err := ValidateConstraintConfig(asSoft, ConflictContext{}); err == nil {
+		t.Error("expected an error configuring a hard-only registered type as soft")
+	}
+	
+	engine, err := NewConstraintFactory().CreateConstraintEngine(valid)
+	if err != nil {
+		t.Fatalf("CreateConstraintEngine() error = %v", err)
+	}
+	constraint := engine.GetHardConstraints()[0]
+	if TypeOf(constraint) != "blackout_day" {
+		t.Errorf("TypeOf() = %s, want blackout_day", TypeOf(constraint))
+	}
+	
+	sunday := time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)
+	if err := constraint.Validate(&models.Match{ID: 1, MatchDate: &sunday}, &models.Draw{}); err == nil {
+		t.Error("expected a Sunday match to violate the registered constraint")
+	}
+	
+	Unregister("blackout_day")
+	if _, err := ValidateConstraintConfig(valid, ConflictContext{}); err == nil {
+		t.Error("expected an unknown type error after Unregister")
+	}
+}
+
 // TestComplexConfiguration tests a complex real-world configuration
 func TestComplexConfiguration(t *testing.T) {
 	factory := NewConstraintFactory()
@@ -408,7 +1015,7 @@ func TestComplexConfiguration(t *testing.T) {
 	}
 	
 	// Validate the configuration
-	err := ValidateConstraintConfig(config)
+	_, err := ValidateConstraintConfig(config, ConflictContext{})
 	if err != nil {
 		t.Fatalf("Complex config should be valid: %v", err)
 	}
@@ -451,4 +1058,17 @@ func TestComplexConfiguration(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create engine from loaded config: %v", err)
 	}
-}
\ No newline at end of file
+}
+// TestParseWeekday tests reading full and short day names
+func TestParseWeekday(t *testing.T) {
+	for name, want := range map[string]time.Weekday{"friday": time.Friday, "Sun": time.Sunday, " THU ": time.Thursday} {
+		got, err := ParseWeekday(name)
+		if err != nil || got != want {
+			t.Errorf("ParseWeekday(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	
+	if _, err := ParseWeekday("fr"); err == nil {
+		t.Error("Expected an error for an unknown day")
+	}
+}
@@ -1,6 +1,7 @@
 package constraints
 
 import (
+	"errors"
 	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
@@ -25,6 +26,12 @@ type Constraint interface {
 	Description() string
 }
 
+// DrawValidator is implemented by hard constraints with rules that can only be
+// judged against the whole draw, such as season totals per team
+type DrawValidator interface {
+	ValidateDraw(draw *models.Draw) []error
+}
+
 // WeightedConstraint wraps a soft constraint with a weight
 type WeightedConstraint struct {
 	Constraint Constraint
@@ -35,6 +42,7 @@ type WeightedConstraint struct {
 type ConstraintEngine struct {
 	hardConstraints []Constraint
 	softConstraints []WeightedConstraint
+	calendar        *models.SeasonCalendar // Dates undated matches, when set
 }
 
 // NewConstraintEngine creates a new constraint engine
@@ -49,6 +57,7 @@ func NewConstraintEngine() *ConstraintEngine {
 func (ce *ConstraintEngine) AddHardConstraint(constraint Constraint) {
 	if constraint.IsHard() {
 		ce.hardConstraints = append(ce.hardConstraints, constraint)
+		ce.shareMagicRounds()
 	}
 }
 
@@ -64,8 +73,13 @@ func (ce *ConstraintEngine) AddSoftConstraint(constraint Constraint, weight floa
 
 // ValidateMatch checks if a match violates any hard constraints
 func (ce *ConstraintEngine) ValidateMatch(match *models.Match, draw *models.Draw) error {
+	return ce.validateMatch(match, NewDrawIndex(draw))
+}
+
+// validateMatch checks a match against the hard constraints using a shared index
+func (ce *ConstraintEngine) validateMatch(match *models.Match, index *DrawIndex) error {
 	for _, constraint := range ce.hardConstraints {
-		if err := constraint.Validate(match, draw); err != nil {
+		if err := validateWith(constraint, match, index); err != nil {
 			return err
 		}
 	}
@@ -74,21 +88,32 @@ func (ce *ConstraintEngine) ValidateMatch(match *models.Match, draw *models.Draw
 
 // ValidateDraw checks if the entire draw violates any hard constraints
 func (ce *ConstraintEngine) ValidateDraw(draw *models.Draw) []error {
+	return ce.validateDraw(NewDrawIndex(draw))
+}
+
+// validateDraw checks the indexed draw against the hard constraints
+func (ce *ConstraintEngine) validateDraw(index *DrawIndex) []error {
 	var errors []error
 
-	for _, match := range draw.Matches {
-		if err := ce.ValidateMatch(match, draw); err != nil {
+	for _, match := range index.draw.Matches {
+		if err := ce.validateMatch(match, index); err != nil {
 			errors = append(errors, err)
 		}
 	}
 
+	for _, constraint := range ce.hardConstraints {
+		errors = append(errors, validateDrawWith(constraint, index)...)
+	}
+
 	return errors
 }
 
 // ScoreDraw calculates the total score for a draw considering all constraints
 func (ce *ConstraintEngine) ScoreDraw(draw *models.Draw) float64 {
+	index := NewDrawIndex(draw)
+
 	// First check hard constraints - if any fail, return 0
-	if violations := ce.ValidateDraw(draw); len(violations) > 0 {
+	if violations := ce.validateDraw(index); len(violations) > 0 {
 		return 0.0
 	}
 
@@ -97,7 +122,7 @@ func (ce *ConstraintEngine) ScoreDraw(draw *models.Draw) float64 {
 	var totalWeight float64
 
 	for _, weighted := range ce.softConstraints {
-		score := weighted.Constraint.Score(draw)
+		score := scoreWith(weighted.Constraint, index)
 		totalScore += score * weighted.Weight
 		totalWeight += weighted.Weight
 	}
@@ -123,10 +148,19 @@ func (ce *ConstraintEngine) GetSoftConstraints() []WeightedConstraint {
 // ConstraintViolation represents a constraint violation
 type ConstraintViolation struct {
 	ConstraintName string
+	ConstraintType string
 	MatchID        int
 	Round          int
 	Description    string
 	Severity       ViolationSeverity
+	// Locked is set when the violating match is locked, so the optimizer
+	// can't fix it and it needs a manual decision
+	Locked bool
+	// MatchIDs, Rounds and TeamIDs list everything the violation affects
+	MatchIDs    []int
+	Rounds      []int
+	TeamIDs     []int
+	Remediation string
 }
 
 // ViolationSeverity indicates how severe a constraint violation is
@@ -138,45 +172,82 @@ const (
 	SeverityWarning ViolationSeverity = "warning"
 )
 
-// AnalyzeDraw performs comprehensive constraint analysis
+// AnalyzeDraw performs comprehensive constraint analysis. Every violation
+// names the matches, rounds and teams it affects and suggests a remediation.
 func (ce *ConstraintEngine) AnalyzeDraw(draw *models.Draw) []ConstraintViolation {
 	var violations []ConstraintViolation
+	index := NewDrawIndex(draw)
 
 	// Check hard constraints
 	for _, constraint := range ce.hardConstraints {
+		constraintType := TypeOf(constraint)
+
 		for _, match := range draw.Matches {
-			if err := constraint.Validate(match, draw); err != nil {
+			if err := validateWith(constraint, match, index); err != nil {
 				violations = append(violations, ConstraintViolation{
 					ConstraintName: constraint.Name(),
+					ConstraintType: constraintType,
 					MatchID:        match.ID,
 					Round:          match.Round,
 					Description:    err.Error(),
 					Severity:       SeverityHard,
+					Locked:         match.Locked,
+					MatchIDs:       []int{match.ID},
+					Rounds:         []int{match.Round},
+					TeamIDs:        matchTeams(match),
+					Remediation:    Remediation(constraintType, SeverityHard, match.Locked),
 				})
 			}
 		}
 
+		// Check draw-level rules
+		for _, err := range validateDrawWith(constraint, index) {
+			violation := ConstraintViolation{
+				ConstraintName: constraint.Name(),
+				ConstraintType: constraintType,
+				MatchID:        0,
+				Round:          0,
+				Description:    err.Error(),
+				Severity:       SeverityHard,
+				Remediation:    Remediation(constraintType, SeverityHard, false),
+			}
+			var drawViolation *DrawViolation
+			if errors.As(err, &drawViolation) {
+				violation.TeamIDs = drawViolation.TeamIDs
+				violation.Rounds = drawViolation.Rounds
+				violation.MatchIDs = affectedMatches(draw, drawViolation.TeamIDs, drawViolation.Rounds)
+			}
+			violations = append(violations, violation)
+		}
+
 		// Check overall draw score for this constraint
-		if score := constraint.Score(draw); score < 0.5 {
+		if score := scoreWith(constraint, index); score < 0.5 {
 			violations = append(violations, ConstraintViolation{
 				ConstraintName: constraint.Name(),
+				ConstraintType: constraintType,
 				MatchID:        0,
 				Round:          0,
 				Description:    "Overall constraint satisfaction below threshold",
 				Severity:       SeverityWarning,
+				TeamIDs:        teamsBelow(constraint, index, 0.5),
+				Remediation:    Remediation(constraintType, SeverityWarning, false),
 			})
 		}
 	}
 
 	// Check soft constraints
 	for _, weighted := range ce.softConstraints {
-		if score := weighted.Constraint.Score(draw); score < 0.3 {
+		if score := scoreWith(weighted.Constraint, index); score < 0.3 {
+			constraintType := TypeOf(weighted.Constraint)
 			violations = append(violations, ConstraintViolation{
 				ConstraintName: weighted.Constraint.Name(),
+				ConstraintType: constraintType,
 				MatchID:        0,
 				Round:          0,
 				Description:    "Soft constraint poorly satisfied",
 				Severity:       SeveritySoft,
+				TeamIDs:        teamsBelow(weighted.Constraint, index, 0.3),
+				Remediation:    Remediation(constraintType, SeveritySoft, false),
 			})
 		}
 	}
@@ -219,6 +290,7 @@ func (bc BaseConstraint) IsHard() bool {
 type DateConstraint struct {
 	BaseConstraint
 	unavailableDates []time.Time
+	calendar         *models.SeasonCalendar
 }
 
 // NewDateConstraint creates a date-based constraint
@@ -244,3 +316,40 @@ func (dc DateConstraint) IsDateUnavailable(date time.Time) bool {
 func (dc DateConstraint) GetUnavailableDates() []time.Time {
 	return dc.unavailableDates
 }
+
+// SetCalendar sets the season calendar undated matches are checked against
+func (dc *DateConstraint) SetCalendar(calendar *models.SeasonCalendar) {
+	dc.calendar = calendar
+}
+
+// conflictDate reports whether a match falls on an unavailable date, and
+// which. A match without a kickoff conflicts only when the season calendar
+// leaves it no available day in its round.
+func (dc DateConstraint) conflictDate(match *models.Match) (time.Time, bool) {
+	if match.MatchDate != nil {
+		return *match.MatchDate, dc.IsDateUnavailable(*match.MatchDate)
+	}
+	if dc.calendar == nil {
+		return time.Time{}, false
+	}
+
+	ranges := dc.calendar.RoundDates(match.Round)
+	if len(ranges) == 0 {
+		return time.Time{}, false
+	}
+	for _, dates := range ranges {
+		for _, day := range dates.Days() {
+			if !dc.IsDateUnavailable(day) {
+				return time.Time{}, false
+			}
+		}
+	}
+	return ranges[0].StartDate, true
+}
+
+// isChecked reports whether a match has a date to check, its own or its
+// round's in the season calendar
+func (dc DateConstraint) isChecked(match *models.Match) bool {
+	_, dated := calendarDate(dc.calendar, match)
+	return dated
+}
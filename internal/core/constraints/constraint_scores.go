@@ -0,0 +1,55 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// ConstraintScore is how well a draw satisfies one constraint. Soft
+// constraints report their score and weighted penalty; hard constraints score
+// 1.0 when unbroken and 0.0 otherwise, with how many violations they found.
+type ConstraintScore struct {
+	Constraint string  `json:"constraint"`
+	Hard       bool    `json:"hard,omitempty"`
+	Weight     float64 `json:"weight,omitempty"`
+	Score      float64 `json:"score"`
+	Penalty    float64 `json:"penalty,omitempty"` // weight * (1 - score)
+	Violations int     `json:"violations,omitempty"`
+}
+
+// ConstraintScores scores the draw against each constraint separately, soft
+// constraints first, in the order they were added
+func (ce *ConstraintEngine) ConstraintScores(draw *models.Draw) []ConstraintScore {
+	scores := make([]ConstraintScore, 0, len(ce.softConstraints)+len(ce.hardConstraints))
+	index := NewDrawIndex(draw)
+
+	for _, weighted := range ce.softConstraints {
+		score := scoreWith(weighted.Constraint, index)
+		scores = append(scores, ConstraintScore{
+			Constraint: weighted.Constraint.Name(),
+			Weight:     weighted.Weight,
+			Score:      score,
+			Penalty:    weighted.Weight * (1.0 - score),
+		})
+	}
+
+	for _, constraint := range ce.hardConstraints {
+		violations := len(validateDrawWith(constraint, index))
+		for _, match := range draw.Matches {
+			if validateWith(constraint, match, index) != nil {
+				violations++
+			}
+		}
+		score := 1.0
+		if violations > 0 {
+			score = 0.0
+		}
+		scores = append(scores, ConstraintScore{
+			Constraint: constraint.Name(),
+			Hard:       true,
+			Score:      score,
+			Violations: violations,
+		})
+	}
+
+	return scores
+}
@@ -120,6 +120,328 @@ func TestConstraintEngineAnalysis(t *testing.T) {
 	}
 }
 
+// TestConstraintEngineAnalysisExplains tests that violations link the matches,
+// rounds and teams they affect
+func TestConstraintEngineAnalysisExplains(t *testing.T) {
+	engine := NewConstraintEngine()
+	draw := createTestDraw()
+	
+	// Repeat the round 1 fixture between teams 1 and 2 in round 2
+	draw.Matches[2].AwayTeamID = draw.Matches[0].AwayTeamID
+	
+	engine.AddHardConstraint(NewDoubleUpConstraint(3))
+	
+	var doubleUp *ConstraintViolation
+	violations := engine.AnalyzeDraw(draw)
+	for i := range violations {
+		if violations[i].Severity == SeverityHard && violations[i].MatchID == 3 {
+			doubleUp = &violations[i]
+		}
+	}
+	if doubleUp == nil {
+		t.Fatal("Expected a double-up violation on match 3")
+	}
+	if doubleUp.ConstraintType != "double_up" {
+		t.Errorf("Expected constraint type double_up, got %q", doubleUp.ConstraintType)
+	}
+	if len(doubleUp.MatchIDs) != 1 || doubleUp.MatchIDs[0] != 3 {
+		t.Errorf("Expected match 3 to be affected, got %v", doubleUp.MatchIDs)
+	}
+	if len(doubleUp.Rounds) != 1 || doubleUp.Rounds[0] != 2 {
+		t.Errorf("Expected round 2 to be affected, got %v", doubleUp.Rounds)
+	}
+	if len(doubleUp.TeamIDs) != 2 || doubleUp.TeamIDs[0] != 1 || doubleUp.TeamIDs[1] != 2 {
+		t.Errorf("Expected teams 1 and 2 to be affected, got %v", doubleUp.TeamIDs)
+	}
+	if doubleUp.Remediation == "" {
+		t.Error("Expected a suggested remediation")
+	}
+	
+	// Draw-level violations name their teams and rounds
+	engine = NewConstraintEngine()
+	engine.AddHardConstraint(NewByeRoundWindowConstraint([]int{1, 2}))
+	
+	var byeWindow *ConstraintViolation
+	violations = engine.AnalyzeDraw(createTestDrawWithByes())
+	for i := range violations {
+		if violations[i].Severity == SeverityHard {
+			byeWindow = &violations[i]
+		}
+	}
+	if byeWindow == nil {
+		t.Fatal("Expected a bye window violation")
+	}
+	if len(byeWindow.TeamIDs) != 1 || byeWindow.TeamIDs[0] != 1 {
+		t.Errorf("Expected team 1 to be affected, got %v", byeWindow.TeamIDs)
+	}
+	if len(byeWindow.Rounds) != 1 || byeWindow.Rounds[0] != 3 {
+		t.Errorf("Expected round 3 to be affected, got %v", byeWindow.Rounds)
+	}
+	
+	if remediation := Remediation("double_up", SeverityHard, true); remediation == Remediation("double_up", SeverityHard, false) {
+		t.Error("Expected locked matches to get different advice")
+	}
+}
+
+// TestConstraintEngineTimeline tests round-by-round violation analysis
+func TestConstraintEngineTimeline(t *testing.T) {
+	engine := NewConstraintEngine()
+	draw := createTestDraw()
+	
+	// Repeat the round 1 fixtures in round 2 to force double-ups
+	draw.Matches[2].AwayTeamID = draw.Matches[0].AwayTeamID
+	draw.Matches[3].HomeTeamID = draw.Matches[1].HomeTeamID
+	
+	engine.AddHardConstraint(NewDoubleUpConstraint(3))
+	engine.AddSoftConstraint(NewHomeAwayBalanceConstraint(0.1), 1.0)
+	
+	timeline := engine.AnalyzeTimeline(draw)
+	
+	if len(timeline.Rounds) != draw.Rounds {
+		t.Fatalf("Expected %d rounds in timeline, got %d", draw.Rounds, len(timeline.Rounds))
+	}
+	
+	hardTotal := 0
+	for _, round := range timeline.Rounds {
+		hardTotal += round.HardViolations
+		if round.HardViolations > 0 && round.Severity != SeverityHard {
+			t.Errorf("Round %d has hard violations but severity %q", round.Round, round.Severity)
+		}
+		if round.SoftPenalty < 0 {
+			t.Errorf("Round %d has negative soft penalty %f", round.Round, round.SoftPenalty)
+		}
+	}
+	if hardTotal == 0 {
+		t.Error("Expected double-up violations to be attributed to rounds")
+	}
+	
+	// Rounds without matches carry nothing
+	empty := timeline.Rounds[5]
+	if empty.MatchCount != 0 || empty.HardViolations != 0 || empty.SoftPenalty != 0 || empty.Severity != "" {
+		t.Errorf("Expected empty round 6, got %+v", empty)
+	}
+	
+	// Total penalty should match the draw's soft score
+	softScore := NewHomeAwayBalanceConstraint(0.1).Score(draw)
+	if diff := timeline.TotalSoftPenalty - (1.0 - softScore); diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected total soft penalty %f, got %f", 1.0-softScore, timeline.TotalSoftPenalty)
+	}
+}
+
+func TestConstraintEngineWorstTeams(t *testing.T) {
+	engine := NewConstraintEngine()
+	draw := createDrawWithUnbalancedHomeAway()
+	
+	engine.AddSoftConstraint(NewHomeAwayBalanceConstraint(0.1), 2.0)
+	// Stability doesn't score teams individually, so it isn't reported
+	engine.AddSoftConstraint(NewScheduleStabilityConstraint(draw), 1.0)
+	
+	results := engine.WorstTeams(draw, 2)
+	if len(results) != 1 {
+		t.Fatalf("Expected one constraint with team scores, got %d", len(results))
+	}
+	
+	worst := results[0]
+	if worst.Constraint != "HomeAwayBalance" || worst.Weight != 2.0 {
+		t.Errorf("Unexpected constraint %q with weight %f", worst.Constraint, worst.Weight)
+	}
+	if len(worst.Teams) != 2 {
+		t.Fatalf("Expected limit of 2 teams, got %d", len(worst.Teams))
+	}
+	if worst.Teams[0].Score > worst.Teams[1].Score {
+		t.Error("Expected teams ordered from lowest score")
+	}
+	for _, team := range worst.Teams {
+		if team.TeamID == 3 {
+			t.Error("Team 3 is balanced and should not be reported")
+		}
+		if expected := 2.0 * (1.0 - team.Score); team.Penalty != expected {
+			t.Errorf("Expected penalty %f for team %d, got %f", expected, team.TeamID, team.Penalty)
+		}
+	}
+}
+
+func TestConstraintEngineConstraintScores(t *testing.T) {
+	engine := NewConstraintEngine()
+	draw := createTestDraw()
+	
+	// Repeat the round 1 fixtures in round 2 to force double-ups
+	draw.Matches[2].AwayTeamID = draw.Matches[0].AwayTeamID
+	draw.Matches[3].HomeTeamID = draw.Matches[1].HomeTeamID
+	
+	engine.AddHardConstraint(NewDoubleUpConstraint(3))
+	engine.AddSoftConstraint(NewHomeAwayBalanceConstraint(0.1), 2.0)
+	
+	scores := engine.ConstraintScores(draw)
+	if len(scores) != 2 {
+		t.Fatalf("Expected a score for each constraint, got %d", len(scores))
+	}
+	
+	soft := scores[0]
+	if soft.Hard || soft.Constraint != "HomeAwayBalance" || soft.Weight != 2.0 {
+		t.Errorf("Expected the soft constraint first, got %+v", soft)
+	}
+	if expected := NewHomeAwayBalanceConstraint(0.1).Score(draw); soft.Score != expected {
+		t.Errorf("Expected score %f, got %f", expected, soft.Score)
+	}
+	if expected := 2.0 * (1.0 - soft.Score); soft.Penalty != expected {
+		t.Errorf("Expected penalty %f, got %f", expected, soft.Penalty)
+	}
+	
+	hard := scores[1]
+	if !hard.Hard || hard.Score != 0 || hard.Violations == 0 {
+		t.Errorf("Expected the broken double-up constraint to score 0 with violations, got %+v", hard)
+	}
+}
+
+func TestConstraintEngineStatistics(t *testing.T) {
+	engine := NewConstraintEngine()
+	draw := createDrawWithShortRestPeriods()
+	
+	engine.AddSoftConstraint(NewHomeAwayBalanceConstraint(0.1), 1.0)
+	engine.AddSoftConstraint(NewRestPeriodConstraint(6), 1.0)
+	// The soft rest period's thresholds are reported over the hard one's
+	engine.AddHardConstraint(NewHardRestPeriodConstraint(3))
+	
+	stats := engine.Statistics(draw)
+	if stats.HomeAway == nil || stats.Rest == nil {
+		t.Fatal("Expected home/away and rest analyses for the configured constraints")
+	}
+	if stats.PrimeTime != nil || stats.Travel != nil {
+		t.Error("Prime time and travel aren't configured and should be left out")
+	}
+	if stats.Rest.MinRestDays != 6 {
+		t.Errorf("Expected the soft rest period's 6 days, got %d", stats.Rest.MinRestDays)
+	}
+	if stats.Rest.Statistics.ShortRestPeriods == 0 {
+		t.Error("Should report the short rest periods")
+	}
+	if len(stats.HomeAway.Teams) != stats.HomeAway.Statistics.TotalTeams {
+		t.Errorf("Expected an analysis for each of the %d teams, got %d", stats.HomeAway.Statistics.TotalTeams, len(stats.HomeAway.Teams))
+	}
+}
+
+func TestScoreCache(t *testing.T) {
+	engine := NewConstraintEngine()
+	draw := createTestDraw()
+	
+	engine.AddHardConstraint(NewByeConstraint())
+	engine.AddSoftConstraint(NewHomeAwayBalanceConstraint(0.1), 1.0)
+	engine.AddSoftConstraint(NewTravelMinimizationConstraint(1), 0.5)
+	// Stability isn't scored per team, so it is rescored in full
+	engine.AddSoftConstraint(NewScheduleStabilityConstraint(copyTestDraw(draw)), 0.5)
+	
+	cache := engine.NewScoreCache(draw)
+	original := engine.ScoreDraw(draw)
+	if cache.Score() != original {
+		t.Fatalf("Expected cached score %f to match the full score %f", cache.Score(), original)
+	}
+	filled := cache.Stats()
+	if filled.Hits != 0 || filled.Misses == 0 {
+		t.Fatalf("Expected filling the cache to only miss, got %+v", filled)
+	}
+	
+	// Flip a fixture in place: only its two teams are rescored
+	flipped := draw.Matches[2]
+	flipped.HomeTeamID, flipped.AwayTeamID = flipped.AwayTeamID, flipped.HomeTeamID
+	cache.Invalidate([]*models.Match{flipped})
+	score := cache.Rescore(draw)
+	if diff := score - engine.ScoreDraw(draw); diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected rescored %f to match the full score %f", score, engine.ScoreDraw(draw))
+	}
+	if cache.Score() != original {
+		t.Error("Rescore should leave the committed score until Commit")
+	}
+	
+	stats := cache.Stats()
+	if misses := stats.Misses - filled.Misses; misses != 4 {
+		t.Errorf("Expected 2 teams rescored for 2 constraints, got %d misses", misses)
+	}
+	if stats.Hits == 0 || stats.Invalidations != 2 || stats.HitRate <= 0 {
+		t.Errorf("Expected the other teams to hit the cache, got %+v", stats)
+	}
+	
+	// Undoing the flip and discarding restores the cached scores
+	flipped.HomeTeamID, flipped.AwayTeamID = flipped.AwayTeamID, flipped.HomeTeamID
+	cache.Discard()
+	if score := cache.Rescore(draw); score != original {
+		t.Errorf("Expected the discarded change to score %f, got %f", original, score)
+	}
+	
+	// A committed change becomes the cache's score
+	flipped.HomeTeamID, flipped.AwayTeamID = flipped.AwayTeamID, flipped.HomeTeamID
+	cache.Invalidate([]*models.Match{flipped})
+	score = cache.Rescore(draw)
+	cache.Commit()
+	if cache.Score() != score {
+		t.Errorf("Expected the committed score %f, got %f", score, cache.Score())
+	}
+	
+	// Moving a fixture to another round rescores its teams
+	moved := draw.Matches[4]
+	moved.Round = 5
+	cache.Invalidate([]*models.Match{moved})
+	score = cache.Rescore(draw)
+	if diff := score - engine.ScoreDraw(draw); diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected the moved fixture to score %f, got %f", engine.ScoreDraw(draw), score)
+	}
+	cache.Commit()
+	
+	// A cache from before a constraint was added is rescored in full
+	engine.AddSoftConstraint(NewPrimeTimeSpreadConstraint(0.3, 0.1), 0.5)
+	if score := cache.Rescore(draw); score != engine.ScoreDraw(draw) {
+		t.Errorf("Expected a stale cache to be rescored in full, got %f want %f", score, engine.ScoreDraw(draw))
+	}
+}
+
+func TestDrawIndex(t *testing.T) {
+	index := NewDrawIndex(createTestDrawWithByes())
+
+	if teams := index.Teams(); len(teams) != 3 || teams[0] != 1 || teams[1] != 2 || teams[2] != 3 {
+		t.Fatalf("Expected teams [1 2 3], got %v", teams)
+	}
+
+	if matches := index.TeamMatches(1); len(matches) != 2 || matches[0].ID != 1 || matches[1].ID != 2 {
+		t.Errorf("Expected team 1 to play matches 1 and 2, got %d matches", len(matches))
+	}
+	if matches := index.RoundMatches(3); len(matches) != 1 || matches[0].ID != 3 {
+		t.Errorf("Expected round 3 to hold match 3, got %d matches", len(matches))
+	}
+	if byRound := index.TeamMatchesByRound(3); len(byRound) != 2 || byRound[2].ID != 2 || byRound[3].ID != 3 {
+		t.Errorf("Expected team 3 to play in rounds 2 and 3, got %v", byRound)
+	}
+
+	// Matchups are found whichever team is at home
+	if matches := index.Matchups(2, 1); len(matches) != 1 || matches[0].ID != 1 {
+		t.Errorf("Expected teams 1 and 2 to meet once in match 1, got %d matches", len(matches))
+	}
+	if matches := index.Matchups(1, 4); len(matches) != 0 {
+		t.Errorf("Expected no matches for a team outside the draw, got %d", len(matches))
+	}
+
+	expectedByes := map[int]int{1: 3, 2: 2, 3: 1}
+	for teamID, round := range expectedByes {
+		if byes := index.ByeRounds(teamID); len(byes) != 1 || byes[0] != round {
+			t.Errorf("Expected team %d to have a bye in round %d, got %v", teamID, round, byes)
+		}
+	}
+
+	if profile := index.RoundProfiles()[1]; profile.Matches != 1 || profile.Byes != 1 {
+		t.Errorf("Expected round 1 to hold 1 match and 1 bye, got %+v", profile)
+	}
+}
+
+// copyTestDraw copies a draw's matches so they can be changed independently
+func copyTestDraw(draw *models.Draw) *models.Draw {
+	copied := *draw
+	copied.Matches = make([]*models.Match, len(draw.Matches))
+	for i, match := range draw.Matches {
+		matchCopy := *match
+		copied.Matches[i] = &matchCopy
+	}
+	return &copied
+}
+
 // TestBaseConstraint tests the base constraint functionality
 func TestBaseConstraint(t *testing.T) {
 	base := NewBaseConstraint("TestConstraint", "Test description", true)
@@ -256,4 +578,21 @@ func BenchmarkConstraintEngineScoring(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		engine.ScoreDraw(draw)
 	}
-}
\ No newline at end of file
+}
+
+func BenchmarkScoreCacheRescore(b *testing.B) {
+	engine := NewConstraintEngine()
+	engine.AddSoftConstraint(NewTravelMinimizationConstraint(3), 0.8)
+	engine.AddSoftConstraint(NewHomeAwayBalanceConstraint(0.1), 0.7)
+	
+	draw := createTestDraw()
+	cache := engine.NewScoreCache(draw)
+	changed := []*models.Match{draw.Matches[0]}
+	
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Invalidate(changed)
+		cache.Rescore(draw)
+		cache.Discard()
+	}
+}
@@ -0,0 +1,84 @@
+package constraints
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// CustomExpressionConstraint requires every match to satisfy a rule written
+// as an expression, e.g. "NOT team(1).plays OR team(1).consecutive_away <= 2",
+// so one-off league rules don't need a constraint type of their own. See
+// expression.go for the rule language.
+type CustomExpressionConstraint struct {
+	BaseConstraint
+	expression string
+	rule       *exprNode
+}
+
+// NewCustomExpressionConstraint creates a new custom expression constraint,
+// returning an error if the expression doesn't parse to a condition
+func NewCustomExpressionConstraint(expression, description string, isHard bool) (*CustomExpressionConstraint, error) {
+	rule, err := compileExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	if description == "" {
+		description = fmt.Sprintf("Every match must satisfy %s", expression)
+	}
+
+	return &CustomExpressionConstraint{
+		BaseConstraint: NewBaseConstraint("CustomExpression", description, isHard),
+		expression:     expression,
+		rule:           rule,
+	}, nil
+}
+
+// Validate checks that a match satisfies the expression
+func (cec *CustomExpressionConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	if match.IsBye() {
+		return nil
+	}
+
+	if !cec.satisfies(match, newExprEnv(draw)) {
+		return fmt.Errorf("match %d in round %d does not satisfy %s", match.ID, match.Round, cec.expression)
+	}
+
+	return nil
+}
+
+// Score returns the fraction of matches that satisfy the expression
+func (cec *CustomExpressionConstraint) Score(draw *models.Draw) float64 {
+	env := newExprEnv(draw)
+	totalMatches := 0
+	satisfied := 0
+
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+
+		totalMatches++
+		if cec.satisfies(match, env) {
+			satisfied++
+		}
+	}
+
+	if totalMatches == 0 {
+		return 1.0
+	}
+
+	return float64(satisfied) / float64(totalMatches)
+}
+
+// satisfies evaluates the expression against a match
+func (cec *CustomExpressionConstraint) satisfies(match *models.Match, env *exprEnv) bool {
+	env.match = match
+	return cec.rule.eval(env).(bool)
+}
+
+// GetExpression returns the rule expression
+func (cec *CustomExpressionConstraint) GetExpression() string {
+	return cec.expression
+}
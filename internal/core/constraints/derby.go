@@ -0,0 +1,266 @@
+package constraints
+
+import (
+	"math"
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DerbyConstraint places derbies, matches between teams based within a
+// distance of each other such as the Sydney clubs. With marquee rounds the
+// draw scores better the more derbies are played in them; without, it scores
+// better the more evenly derbies are spread across the season.
+//
+// Teams are located by their coordinates or home venue, so derbies are only
+// found once league data has been supplied.
+type DerbyConstraint struct {
+	BaseConstraint
+	maxDistanceKm float64
+	marqueeRounds map[int]bool
+	pairs         map[string]DerbyPair
+}
+
+// DerbyPair is two teams close enough for their matches to be derbies
+type DerbyPair struct {
+	TeamA      int     `json:"team_a"`
+	TeamB      int     `json:"team_b"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// NewDerbyConstraint creates a new derby constraint. With no marquee rounds
+// derbies are spread across the season.
+func NewDerbyConstraint(maxDistanceKm float64, marqueeRounds []int) *DerbyConstraint {
+	description := "Spread derbies between nearby teams evenly across the season"
+	if len(marqueeRounds) > 0 {
+		description = "Schedule derbies between nearby teams in the marquee rounds"
+	}
+
+	rounds := make(map[int]bool, len(marqueeRounds))
+	for _, round := range marqueeRounds {
+		rounds[round] = true
+	}
+
+	return &DerbyConstraint{
+		BaseConstraint: NewBaseConstraint("Derby", description, false),
+		maxDistanceKm:  maxDistanceKm,
+		marqueeRounds:  rounds,
+		pairs:          make(map[string]DerbyPair),
+	}
+}
+
+// SetLeagueData finds every pair of teams within the distance threshold
+func (dc *DerbyConstraint) SetLeagueData(data *LeagueData) {
+	dc.pairs = make(map[string]DerbyPair)
+	if data == nil {
+		return
+	}
+
+	for teamA := range data.Teams {
+		latA, lonA, ok := data.TeamHomeLocation(teamA)
+		if !ok {
+			continue
+		}
+		for teamB := range data.Teams {
+			if teamB <= teamA {
+				continue
+			}
+			latB, lonB, ok := data.TeamHomeLocation(teamB)
+			if !ok {
+				continue
+			}
+			if distance := geo.HaversineKm(latA, lonA, latB, lonB); distance <= dc.maxDistanceKm {
+				dc.pairs[MatchupKey(teamA, teamB)] = DerbyPair{TeamA: teamA, TeamB: teamB, DistanceKm: distance}
+			}
+		}
+	}
+}
+
+// Validate always returns nil for soft constraints
+func (dc *DerbyConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score rates where the draw's derbies fall. Draws without derbies score 1.0.
+func (dc *DerbyConstraint) Score(draw *models.Draw) float64 {
+	perRound, total := dc.countDerbies(draw)
+	if total == 0 {
+		return 1.0
+	}
+
+	if len(dc.marqueeRounds) > 0 {
+		return dc.scoreMarquee(draw, perRound, total)
+	}
+	return dc.scoreSpread(draw, perRound, total)
+}
+
+// scoreMarquee returns the fraction of the marquee rounds' matches that are
+// derbies, out of as many as there are derbies to fill them
+func (dc *DerbyConstraint) scoreMarquee(draw *models.Draw, perRound map[int]int, total int) float64 {
+	capacity, placed := 0, 0
+	for _, match := range draw.Matches {
+		if dc.marqueeRounds[match.Round] && match.HomeTeamID != nil && match.AwayTeamID != nil {
+			capacity++
+		}
+	}
+	for round := range dc.marqueeRounds {
+		placed += perRound[round]
+	}
+
+	target := total
+	if capacity < target {
+		target = capacity
+	}
+	if target == 0 {
+		return 1.0
+	}
+	return math.Min(1.0, float64(placed)/float64(target))
+}
+
+// scoreSpread compares each round's derbies with an even share, which is the
+// total divided by the rounds rounded down or up. The score falls with the
+// derbies above or below that share, reaching 0.0 when they are all in one
+// round.
+func (dc *DerbyConstraint) scoreSpread(draw *models.Draw, perRound map[int]int, total int) float64 {
+	if draw.Rounds <= 1 {
+		return 1.0
+	}
+
+	fewest := total / draw.Rounds
+	most := fewest
+	if total%draw.Rounds != 0 {
+		most++
+	}
+
+	deviation := 0
+	for round := 1; round <= draw.Rounds; round++ {
+		count := perRound[round]
+		if count > most {
+			deviation += count - most
+		} else if count < fewest {
+			deviation += fewest - count
+		}
+	}
+
+	worst := (total - most) + (draw.Rounds-1)*fewest
+	if worst == 0 {
+		return 1.0
+	}
+	return math.Max(0, 1-float64(deviation)/float64(worst))
+}
+
+// countDerbies counts the derbies in each round
+func (dc *DerbyConstraint) countDerbies(draw *models.Draw) (map[int]int, int) {
+	perRound := make(map[int]int)
+	total := 0
+	for _, match := range draw.Matches {
+		if dc.IsDerby(match) {
+			perRound[match.Round]++
+			total++
+		}
+	}
+	return perRound, total
+}
+
+// IsDerby reports whether a match is between a derby pair
+func (dc *DerbyConstraint) IsDerby(match *models.Match) bool {
+	if match.HomeTeamID == nil || match.AwayTeamID == nil {
+		return false
+	}
+	_, exists := dc.pairs[MatchupKey(*match.HomeTeamID, *match.AwayTeamID)]
+	return exists
+}
+
+// GetMaxDistanceKm returns the distance within which teams play derbies
+func (dc *DerbyConstraint) GetMaxDistanceKm() float64 {
+	return dc.maxDistanceKm
+}
+
+// GetMarqueeRounds returns the rounds derbies are scheduled in, in order
+func (dc *DerbyConstraint) GetMarqueeRounds() []int {
+	rounds := make([]int, 0, len(dc.marqueeRounds))
+	for round := range dc.marqueeRounds {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+	return rounds
+}
+
+// GetPairs returns every derby pair, closest first
+func (dc *DerbyConstraint) GetPairs() []DerbyPair {
+	pairs := make([]DerbyPair, 0, len(dc.pairs))
+	for _, pair := range dc.pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].DistanceKm != pairs[j].DistanceKm {
+			return pairs[i].DistanceKm < pairs[j].DistanceKm
+		}
+		return MatchupKey(pairs[i].TeamA, pairs[i].TeamB) < MatchupKey(pairs[j].TeamA, pairs[j].TeamB)
+	})
+	return pairs
+}
+
+// DerbyRound lists the derbies played in one round
+type DerbyRound struct {
+	Round    int   `json:"round"`
+	Marquee  bool  `json:"marquee"`
+	Derbies  int   `json:"derbies"`
+	MatchIDs []int `json:"match_ids"`
+}
+
+// DerbyAnalysis describes how a draw's derbies are distributed
+type DerbyAnalysis struct {
+	MaxDistanceKm  float64      `json:"max_distance_km"`
+	MarqueeRounds  []int        `json:"marquee_rounds,omitempty"`
+	Pairs          []DerbyPair  `json:"pairs"`
+	TotalDerbies   int          `json:"total_derbies"`
+	MarqueeDerbies int          `json:"marquee_derbies"`
+	Score          float64      `json:"score"`
+	Rounds         []DerbyRound `json:"rounds"`
+}
+
+// AnalyzeDerbies lists the derbies in every round of the draw
+func (dc *DerbyConstraint) AnalyzeDerbies(draw *models.Draw) DerbyAnalysis {
+	analysis := DerbyAnalysis{
+		MaxDistanceKm: dc.maxDistanceKm,
+		MarqueeRounds: dc.GetMarqueeRounds(),
+		Pairs:         dc.GetPairs(),
+		Score:         dc.Score(draw),
+		Rounds:        make([]DerbyRound, draw.Rounds),
+	}
+	for i := range analysis.Rounds {
+		round := i + 1
+		analysis.Rounds[i] = DerbyRound{Round: round, Marquee: dc.marqueeRounds[round], MatchIDs: []int{}}
+	}
+
+	for _, match := range draw.Matches {
+		if !dc.IsDerby(match) || match.Round < 1 || match.Round > draw.Rounds {
+			continue
+		}
+		round := &analysis.Rounds[match.Round-1]
+		round.Derbies++
+		round.MatchIDs = append(round.MatchIDs, match.ID)
+
+		analysis.TotalDerbies++
+		if round.Marquee {
+			analysis.MarqueeDerbies++
+		}
+	}
+
+	return analysis
+}
+
+// AnalyzeDerbies reports the derby distribution for every derby constraint in
+// the engine
+func (ce *ConstraintEngine) AnalyzeDerbies(draw *models.Draw) []DerbyAnalysis {
+	analyses := []DerbyAnalysis{}
+	for _, weighted := range ce.softConstraints {
+		if derby, ok := weighted.Constraint.(*DerbyConstraint); ok {
+			analyses = append(analyses, derby.AnalyzeDerbies(draw))
+		}
+	}
+	return analyses
+}
@@ -0,0 +1,88 @@
+package constraints
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DoubleHeaderConstraint pairs a draw with another competition's draw, such
+// as the NRL's with the NRLW's, so the partner's matches can be played as
+// curtain raisers: at the same venue on the same day as one of this draw's
+// matches. The draw scores better the more of the partner's matches it gives
+// a double header.
+//
+// The partner draw is read from storage, so double headers are only found
+// once partner draws have been supplied.
+type DoubleHeaderConstraint struct {
+	BaseConstraint
+	partnerDrawID int
+	// slots holds the venue and day of each of the partner's dated matches
+	slots []string
+}
+
+// NewDoubleHeaderConstraint creates a new double-header constraint paired
+// with the given draw
+func NewDoubleHeaderConstraint(partnerDrawID int) *DoubleHeaderConstraint {
+	return &DoubleHeaderConstraint{
+		BaseConstraint: NewBaseConstraint("Double Header", "Play the partner competition's matches as curtain raisers at the same venue on the same day", false),
+		partnerDrawID:  partnerDrawID,
+	}
+}
+
+// ReferencedDrawID returns the draw whose matches are the curtain raisers
+func (dh *DoubleHeaderConstraint) ReferencedDrawID() int {
+	return dh.partnerDrawID
+}
+
+// SetReferencedDraw records where and when the partner's matches are played.
+// A nil draw clears them.
+func (dh *DoubleHeaderConstraint) SetReferencedDraw(draw *models.Draw) {
+	dh.slots = nil
+	if draw == nil {
+		return
+	}
+	for _, match := range draw.Matches {
+		if key, ok := venueDayKey(match); ok {
+			dh.slots = append(dh.slots, key)
+		}
+	}
+}
+
+// Validate always returns nil for soft constraints
+func (dh *DoubleHeaderConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score returns the fraction of the partner's dated matches that share a
+// venue and day with one of the draw's matches. Without a partner draw, or
+// when none of its matches are dated, the draw scores 1.0.
+func (dh *DoubleHeaderConstraint) Score(draw *models.Draw) float64 {
+	if len(dh.slots) == 0 {
+		return 1.0
+	}
+
+	hosted := make(map[string]bool)
+	for _, match := range draw.Matches {
+		if key, ok := venueDayKey(match); ok {
+			hosted[key] = true
+		}
+	}
+
+	paired := 0
+	for _, slot := range dh.slots {
+		if hosted[slot] {
+			paired++
+		}
+	}
+	return float64(paired) / float64(len(dh.slots))
+}
+
+// venueDayKey identifies the venue and day a match is played on, if it has both
+func venueDayKey(match *models.Match) (string, bool) {
+	if match.IsBye() || match.VenueID == nil || match.MatchDate == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%d@%s", *match.VenueID, match.MatchDate.Format("2006-01-02")), true
+}
@@ -26,56 +26,59 @@ func NewDoubleUpConstraint(minRoundsSeparation int) *DoubleUpConstraint {
 
 // Validate checks if a match violates the double-up constraint
 func (duc *DoubleUpConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	return duc.ValidateIndexed(match, NewDrawIndex(draw))
+}
+
+// ValidateIndexed checks a match against the other meetings of its teams
+// using a shared index
+func (duc *DoubleUpConstraint) ValidateIndexed(match *models.Match, index *DrawIndex) error {
 	// Skip validation for bye matches
 	if match.IsBye() {
 		return nil
 	}
-	
+
 	// Get the teams involved in this match
 	homeTeam := *match.HomeTeamID
 	awayTeam := *match.AwayTeamID
-	
+
 	// Find all other matches between these teams
-	for _, otherMatch := range draw.Matches {
+	for _, otherMatch := range index.Matchups(homeTeam, awayTeam) {
 		// Skip the same match
 		if otherMatch.ID == match.ID {
 			continue
 		}
-		
-		// Skip bye matches
-		if otherMatch.IsBye() {
-			continue
-		}
-		
-		// Check if this is a match between the same teams
-		if duc.areMatchesBetweenSameTeams(match, otherMatch) {
-			// Check if they're too close together
-			roundDiff := duc.calculateRoundDifference(match.Round, otherMatch.Round)
-			if roundDiff < duc.minRoundsSeparation {
-				return fmt.Errorf("teams %d and %d play each other in rounds %d and %d (only %d rounds apart, minimum %d required)",
-					homeTeam, awayTeam, match.Round, otherMatch.Round, roundDiff, duc.minRoundsSeparation)
-			}
+
+		// Check if they're too close together
+		roundDiff := duc.calculateRoundDifference(match.Round, otherMatch.Round)
+		if roundDiff < duc.minRoundsSeparation {
+			return fmt.Errorf("teams %d and %d play each other in rounds %d and %d (only %d rounds apart, minimum %d required)",
+				homeTeam, awayTeam, match.Round, otherMatch.Round, roundDiff, duc.minRoundsSeparation)
 		}
 	}
-	
+
 	return nil
 }
 
 // Score calculates how well the draw satisfies this constraint
 func (duc *DoubleUpConstraint) Score(draw *models.Draw) float64 {
+	return duc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed scores the draw's repeated matchups using a shared index
+func (duc *DoubleUpConstraint) ScoreIndexed(index *DrawIndex) float64 {
 	totalMatchups := 0
 	violatingMatchups := 0
-	
+
 	// Get all unique team matchups
-	matchups := duc.getAllMatchups(draw)
-	
+	matchups := duc.getAllMatchups(index)
+
 	for _, rounds := range matchups {
 		if len(rounds) < 2 {
 			continue // Single matchup, no violation possible
 		}
-		
+
 		totalMatchups++
-		
+
 		// Check if any pair of rounds is too close
 		for i := 0; i < len(rounds); i++ {
 			for j := i + 1; j < len(rounds); j++ {
@@ -87,29 +90,16 @@ func (duc *DoubleUpConstraint) Score(draw *models.Draw) float64 {
 			}
 		}
 	}
-	
+
 	// If no repeated matchups, constraint is perfectly satisfied
 	if totalMatchups == 0 {
 		return 1.0
 	}
-	
+
 	// Return the percentage of non-violating matchups
 	return float64(totalMatchups-violatingMatchups) / float64(totalMatchups)
 }
 
-// areMatchesBetweenSameTeams checks if two matches are between the same teams
-func (duc *DoubleUpConstraint) areMatchesBetweenSameTeams(match1, match2 *models.Match) bool {
-	if match1.IsBye() || match2.IsBye() {
-		return false
-	}
-	
-	home1, away1 := *match1.HomeTeamID, *match1.AwayTeamID
-	home2, away2 := *match2.HomeTeamID, *match2.AwayTeamID
-	
-	// Check both directions (home/away can be swapped)
-	return (home1 == home2 && away1 == away2) || (home1 == away2 && away1 == home2)
-}
-
 // calculateRoundDifference calculates the absolute difference between two rounds
 func (duc *DoubleUpConstraint) calculateRoundDifference(round1, round2 int) int {
 	diff := round1 - round2
@@ -120,28 +110,16 @@ func (duc *DoubleUpConstraint) calculateRoundDifference(round1, round2 int) int
 }
 
 // getAllMatchups returns a map of team matchups and the rounds they occur in
-func (duc *DoubleUpConstraint) getAllMatchups(draw *models.Draw) map[string][]int {
+func (duc *DoubleUpConstraint) getAllMatchups(index *DrawIndex) map[string][]int {
 	matchups := make(map[string][]int)
-	
-	for _, match := range draw.Matches {
-		if match.IsBye() {
-			continue
+
+	for key, matches := range index.AllMatchups() {
+		for _, match := range matches {
+			matchups[key] = append(matchups[key], match.Round)
 		}
-		
-		key := duc.getMatchupKey(*match.HomeTeamID, *match.AwayTeamID)
-		matchups[key] = append(matchups[key], match.Round)
 	}
-	
-	return matchups
-}
 
-// getMatchupKey creates a consistent key for a team matchup
-func (duc *DoubleUpConstraint) getMatchupKey(team1, team2 int) string {
-	// Always put the smaller team ID first for consistency
-	if team1 > team2 {
-		team1, team2 = team2, team1
-	}
-	return fmt.Sprintf("%d-%d", team1, team2)
+	return matchups
 }
 
 // GetMinRoundsSeparation returns the minimum rounds separation
@@ -152,7 +130,7 @@ func (duc *DoubleUpConstraint) GetMinRoundsSeparation() int {
 // GetViolatingMatchups returns all matchups that violate the constraint
 func (duc *DoubleUpConstraint) GetViolatingMatchups(draw *models.Draw) map[string][]int {
 	violatingMatchups := make(map[string][]int)
-	matchups := duc.getAllMatchups(draw)
+	matchups := duc.getAllMatchups(NewDrawIndex(draw))
 	
 	for matchupKey, rounds := range matchups {
 		if len(rounds) < 2 {
@@ -204,7 +182,7 @@ func (duc *DoubleUpConstraint) ValidateEntireDraw(draw *models.Draw) []error {
 // GetMatchupFrequency returns how many times each team matchup occurs
 func (duc *DoubleUpConstraint) GetMatchupFrequency(draw *models.Draw) map[string]int {
 	frequency := make(map[string]int)
-	matchups := duc.getAllMatchups(draw)
+	matchups := duc.getAllMatchups(NewDrawIndex(draw))
 	
 	for matchupKey, rounds := range matchups {
 		frequency[matchupKey] = len(rounds)
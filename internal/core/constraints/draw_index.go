@@ -0,0 +1,198 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DrawIndex groups a draw's matches by team, round and matchup so constraints
+// can look matches up instead of each scanning the whole draw. The engine
+// builds one index per validation or scoring pass and shares it with every
+// constraint. The index describes the draw as it was when built, so build a
+// new one after changing the draw's matches. Slices and maps it returns are
+// shared and must not be modified.
+type DrawIndex struct {
+	draw       *models.Draw
+	teams      []int
+	byTeam     map[int][]*models.Match
+	byRound    map[int][]*models.Match
+	teamRounds map[int]map[int]*models.Match
+	matchups   map[string][]*models.Match
+	byes       map[int][]int
+	profiles   map[int]models.RoundProfile
+}
+
+// NewDrawIndex indexes a draw's matches
+func NewDrawIndex(draw *models.Draw) *DrawIndex {
+	index := &DrawIndex{
+		draw:       draw,
+		byTeam:     make(map[int][]*models.Match),
+		byRound:    make(map[int][]*models.Match),
+		teamRounds: make(map[int]map[int]*models.Match),
+		matchups:   make(map[string][]*models.Match),
+	}
+
+	for _, match := range draw.Matches {
+		index.byRound[match.Round] = append(index.byRound[match.Round], match)
+		if match.IsBye() {
+			// A bye names the team sitting out, not a team playing
+			continue
+		}
+
+		for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+			if teamID == nil {
+				continue
+			}
+			if index.teamRounds[*teamID] == nil {
+				index.teams = append(index.teams, *teamID)
+				index.teamRounds[*teamID] = make(map[int]*models.Match)
+			}
+			index.byTeam[*teamID] = append(index.byTeam[*teamID], match)
+			index.teamRounds[*teamID][match.Round] = match
+		}
+
+		if match.HomeTeamID != nil && match.AwayTeamID != nil {
+			key := MatchupKey(*match.HomeTeamID, *match.AwayTeamID)
+			index.matchups[key] = append(index.matchups[key], match)
+		}
+	}
+	sortInts(index.teams)
+
+	index.byes = make(map[int][]int, len(index.teams))
+	for _, teamID := range index.teams {
+		var rounds []int
+		for round := 1; round <= draw.Rounds; round++ {
+			if _, plays := index.teamRounds[teamID][round]; !plays {
+				rounds = append(rounds, round)
+			}
+		}
+		index.byes[teamID] = rounds
+	}
+
+	return index
+}
+
+// Draw returns the indexed draw
+func (di *DrawIndex) Draw() *models.Draw {
+	return di.draw
+}
+
+// Teams returns every team playing in the draw, in ascending order
+func (di *DrawIndex) Teams() []int {
+	return di.teams
+}
+
+// TeamMatches returns a team's matches in draw order
+func (di *DrawIndex) TeamMatches(teamID int) []*models.Match {
+	return di.byTeam[teamID]
+}
+
+// RoundMatches returns a round's matches in draw order
+func (di *DrawIndex) RoundMatches(round int) []*models.Match {
+	return di.byRound[round]
+}
+
+// TeamMatchesByRound returns a team's match in each round it plays. A team
+// listed twice in a round keeps the later match.
+func (di *DrawIndex) TeamMatchesByRound(teamID int) map[int]*models.Match {
+	return di.teamRounds[teamID]
+}
+
+// Matchups returns the matches between two teams, whichever is at home
+func (di *DrawIndex) Matchups(teamA, teamB int) []*models.Match {
+	return di.matchups[MatchupKey(teamA, teamB)]
+}
+
+// AllMatchups returns the matches between every pair of teams that meet,
+// keyed by MatchupKey
+func (di *DrawIndex) AllMatchups() map[string][]*models.Match {
+	return di.matchups
+}
+
+// ByeRounds returns the rounds a team doesn't play in, in ascending order
+func (di *DrawIndex) ByeRounds(teamID int) []int {
+	return di.byes[teamID]
+}
+
+// Byes returns the bye rounds of every team in the draw
+func (di *DrawIndex) Byes() map[int][]int {
+	return di.byes
+}
+
+// RoundProfiles returns the draw's round profiles, worked out on first use
+func (di *DrawIndex) RoundProfiles() map[int]models.RoundProfile {
+	if di.profiles == nil {
+		di.profiles = di.draw.RoundProfiles()
+	}
+	return di.profiles
+}
+
+// IndexedValidator is implemented by constraints that can validate a match
+// using a shared index rather than scanning the draw
+type IndexedValidator interface {
+	ValidateIndexed(match *models.Match, index *DrawIndex) error
+}
+
+// IndexedDrawValidator is the DrawValidator counterpart of IndexedValidator
+type IndexedDrawValidator interface {
+	ValidateDrawIndexed(index *DrawIndex) []error
+}
+
+// IndexedScorer is implemented by constraints that can score a draw using a
+// shared index
+type IndexedScorer interface {
+	ScoreIndexed(index *DrawIndex) float64
+}
+
+// IndexedTeamScorer is the TeamDeltaScorer counterpart of IndexedScorer
+type IndexedTeamScorer interface {
+	TeamScoresIndexed(index *DrawIndex) map[int]float64
+	ScoreTeamIndexed(index *DrawIndex, teamID int) float64
+}
+
+// validateWith validates a match against a constraint, using the index when
+// the constraint supports it
+func validateWith(constraint Constraint, match *models.Match, index *DrawIndex) error {
+	if indexed, ok := constraint.(IndexedValidator); ok {
+		return indexed.ValidateIndexed(match, index)
+	}
+	return constraint.Validate(match, index.draw)
+}
+
+// validateDrawWith returns a constraint's draw-level violations, or nil when
+// it has no draw-level rules
+func validateDrawWith(constraint Constraint, index *DrawIndex) []error {
+	if indexed, ok := constraint.(IndexedDrawValidator); ok {
+		return indexed.ValidateDrawIndexed(index)
+	}
+	if drawValidator, ok := constraint.(DrawValidator); ok {
+		return drawValidator.ValidateDraw(index.draw)
+	}
+	return nil
+}
+
+// scoreWith scores the draw against a constraint, using the index when the
+// constraint supports it
+func scoreWith(constraint Constraint, index *DrawIndex) float64 {
+	if indexed, ok := constraint.(IndexedScorer); ok {
+		return indexed.ScoreIndexed(index)
+	}
+	return constraint.Score(index.draw)
+}
+
+// TeamScoresWith returns a team scorer's per-team scores, using the index
+// when the constraint supports it
+func TeamScoresWith(scorer TeamScorer, index *DrawIndex) map[int]float64 {
+	if indexed, ok := scorer.(IndexedTeamScorer); ok {
+		return indexed.TeamScoresIndexed(index)
+	}
+	return scorer.TeamScores(index.draw)
+}
+
+// scoreTeamWith returns one team's score, using the index when the
+// constraint supports it
+func scoreTeamWith(scorer TeamDeltaScorer, index *DrawIndex, teamID int) float64 {
+	if indexed, ok := scorer.(IndexedTeamScorer); ok {
+		return indexed.ScoreTeamIndexed(index, teamID)
+	}
+	return scorer.ScoreTeam(index.draw, teamID)
+}
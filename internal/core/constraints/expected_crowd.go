@@ -0,0 +1,221 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Defaults for ExpectedCrowdConstraint when the configuration leaves them out
+const (
+	DefaultTeamPopularity = 1.0
+	DefaultReferenceCrowd = 20000.0
+	DefaultPrimeTimeBoost = 0.25
+)
+
+// ExpectedCrowdConstraint rewards draws that put high-drawing matchups at
+// high-capacity venues and in prime time. Each match's crowd is estimated from
+// the popularity of both teams and capped at its venue's capacity; the draw
+// scores the estimated season attendance against the best it could achieve.
+type ExpectedCrowdConstraint struct {
+	BaseConstraint
+	popularity     map[int]float64 // Team popularity weights, DefaultTeamPopularity when missing
+	referenceCrowd float64         // Expected crowd when two teams of popularity 1.0 meet outside prime time
+	primeTimeBoost float64         // Fractional lift in demand for prime-time matches
+	league         *LeagueData
+}
+
+// NewExpectedCrowdConstraint creates a new expected crowd constraint
+func NewExpectedCrowdConstraint(popularity map[int]float64, referenceCrowd, primeTimeBoost float64) *ExpectedCrowdConstraint {
+	if popularity == nil {
+		popularity = make(map[int]float64)
+	}
+
+	return &ExpectedCrowdConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"ExpectedCrowd",
+			"Place high-drawing matchups at high-capacity venues and in prime time to maximize attendance",
+			false, // This is a soft constraint
+		),
+		popularity:     popularity,
+		referenceCrowd: referenceCrowd,
+		primeTimeBoost: primeTimeBoost,
+	}
+}
+
+// SetLeagueData supplies the venue capacities crowds are capped at
+func (ecc *ExpectedCrowdConstraint) SetLeagueData(data *LeagueData) {
+	ecc.league = data
+}
+
+// Validate always returns nil for soft constraints
+func (ecc *ExpectedCrowdConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score returns the draw's estimated attendance as a fraction of the best
+// achievable: every match at the largest venue, with the draw's prime-time
+// slots given to the matchups they lift the most
+func (ecc *ExpectedCrowdConstraint) Score(draw *models.Draw) float64 {
+	largest := ecc.largestCapacity()
+
+	total := 0.0
+	best := 0.0
+	primeTimeCount := 0
+	var primeTimeGains []float64
+
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		total += ecc.EstimateCrowd(match)
+
+		demand := ecc.demand(match)
+		regular := capCrowd(demand, largest)
+		best += regular
+		primeTimeGains = append(primeTimeGains, capCrowd(demand*(1+ecc.primeTimeBoost), largest)-regular)
+		if match.IsPrimeTime {
+			primeTimeCount++
+		}
+	}
+
+	// Sort gains (descending) so the best prime-time selection is counted
+	for i := 0; i < len(primeTimeGains)-1; i++ {
+		for j := i + 1; j < len(primeTimeGains); j++ {
+			if primeTimeGains[i] < primeTimeGains[j] {
+				primeTimeGains[i], primeTimeGains[j] = primeTimeGains[j], primeTimeGains[i]
+			}
+		}
+	}
+	for i := 0; i < primeTimeCount; i++ {
+		best += primeTimeGains[i]
+	}
+
+	if best == 0 {
+		return 1.0
+	}
+	if total >= best {
+		return 1.0
+	}
+	return total / best
+}
+
+// EstimateCrowd returns the expected attendance of a match, capped at its
+// venue's capacity when known
+func (ecc *ExpectedCrowdConstraint) EstimateCrowd(match *models.Match) float64 {
+	if match.IsBye() {
+		return 0
+	}
+
+	demand := ecc.demand(match)
+	if match.IsPrimeTime {
+		demand *= 1 + ecc.primeTimeBoost
+	}
+
+	capacity := 0
+	if match.VenueID != nil {
+		capacity, _ = ecc.league.VenueCapacity(*match.VenueID)
+	}
+	return capCrowd(demand, capacity)
+}
+
+// TotalAttendance returns the estimated attendance across every match in the draw
+func (ecc *ExpectedCrowdConstraint) TotalAttendance(draw *models.Draw) float64 {
+	total := 0.0
+	for _, match := range draw.Matches {
+		total += ecc.EstimateCrowd(match)
+	}
+	return total
+}
+
+// TeamPopularity returns a team's popularity weight
+func (ecc *ExpectedCrowdConstraint) TeamPopularity(teamID int) float64 {
+	if popularity, ok := ecc.popularity[teamID]; ok {
+		return popularity
+	}
+	return DefaultTeamPopularity
+}
+
+// demand returns the crowd a match would draw outside prime time with no capacity limit
+func (ecc *ExpectedCrowdConstraint) demand(match *models.Match) float64 {
+	if match.HomeTeamID == nil || match.AwayTeamID == nil {
+		return 0
+	}
+	pull := (ecc.TeamPopularity(*match.HomeTeamID) + ecc.TeamPopularity(*match.AwayTeamID)) / 2
+	return pull * ecc.referenceCrowd
+}
+
+// largestCapacity returns the capacity of the biggest venue in the league, or
+// 0 when no capacities are known
+func (ecc *ExpectedCrowdConstraint) largestCapacity() int {
+	largest := 0
+	if ecc.league == nil {
+		return largest
+	}
+	for _, venue := range ecc.league.Venues {
+		if venue.Capacity > largest {
+			largest = venue.Capacity
+		}
+	}
+	return largest
+}
+
+// capCrowd limits a crowd to a capacity; a capacity of 0 means unlimited
+func capCrowd(crowd float64, capacity int) float64 {
+	if capacity > 0 && crowd > float64(capacity) {
+		return float64(capacity)
+	}
+	return crowd
+}
+
+// GetReferenceCrowd returns the expected crowd for an average matchup
+func (ecc *ExpectedCrowdConstraint) GetReferenceCrowd() float64 {
+	return ecc.referenceCrowd
+}
+
+// GetPrimeTimeBoost returns the lift in demand for prime-time matches
+func (ecc *ExpectedCrowdConstraint) GetPrimeTimeBoost() float64 {
+	return ecc.primeTimeBoost
+}
+
+// EstimateMatchCrowds returns the estimated crowd of every match, largest first
+func (ecc *ExpectedCrowdConstraint) EstimateMatchCrowds(draw *models.Draw) []MatchCrowd {
+	var crowds []MatchCrowd
+
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		crowd := MatchCrowd{
+			MatchID:        match.ID,
+			Round:          match.Round,
+			VenueID:        match.VenueID,
+			IsPrimeTime:    match.IsPrimeTime,
+			EstimatedCrowd: ecc.EstimateCrowd(match),
+		}
+		if match.VenueID != nil {
+			crowd.Capacity, _ = ecc.league.VenueCapacity(*match.VenueID)
+		}
+		crowds = append(crowds, crowd)
+	}
+
+	// Sort by estimated crowd (descending)
+	for i := 0; i < len(crowds)-1; i++ {
+		for j := i + 1; j < len(crowds); j++ {
+			if crowds[i].EstimatedCrowd < crowds[j].EstimatedCrowd {
+				crowds[i], crowds[j] = crowds[j], crowds[i]
+			}
+		}
+	}
+
+	return crowds
+}
+
+// MatchCrowd contains the estimated crowd of a single match
+type MatchCrowd struct {
+	MatchID        int     `json:"match_id"`
+	Round          int     `json:"round"`
+	VenueID        *int    `json:"venue_id"`
+	Capacity       int     `json:"capacity,omitempty"` // 0 when unknown
+	IsPrimeTime    bool    `json:"is_prime_time"`
+	EstimatedCrowd float64 `json:"estimated_crowd"`
+}
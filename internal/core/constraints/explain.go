@@ -0,0 +1,216 @@
+package constraints
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DrawViolation is a draw-level violation that names the teams and rounds it
+// affects. DrawValidator implementations return it so analysis can link the
+// violation to matches.
+type DrawViolation struct {
+	Message string
+	TeamIDs []int
+	Rounds  []int
+}
+
+// Error returns the violation message
+func (v *DrawViolation) Error() string {
+	return v.Message
+}
+
+// newDrawViolation creates a draw-level violation for the given teams and rounds
+func newDrawViolation(teamIDs, rounds []int, format string, args ...interface{}) *DrawViolation {
+	return &DrawViolation{
+		Message: fmt.Sprintf(format, args...),
+		TeamIDs: teamIDs,
+		Rounds:  rounds,
+	}
+}
+
+// TypeOf returns the configuration type a constraint is created from, or its
+// name for constraints the factory doesn't build
+func TypeOf(constraint Constraint) string {
+	switch constraint.(type) {
+	case *ByeConstraint:
+		return "bye_constraint"
+	case *DoubleUpConstraint:
+		return "double_up"
+	case *VenueAvailabilityConstraint:
+		return "venue_availability"
+	case *VenueQuarantineConstraint:
+		return "venue_quarantine"
+	case *TeamAvailabilityConstraint:
+		return "team_availability"
+	case *StoredTeamAvailabilityConstraint:
+		return "team_unavailability"
+	case *PrimeTimeCapConstraint:
+		return "prime_time_cap"
+	case *VenueRecoveryConstraint:
+		return "venue_recovery"
+	case *ByeRoundWindowConstraint:
+		return "bye_round_window"
+	case *RivalryRoundConstraint:
+		return "rivalry_round"
+	case *MagicRoundConstraint:
+		return "magic_round"
+	case *ShortTurnaroundCapConstraint:
+		return "max_short_turnarounds"
+	case *CustomExpressionConstraint:
+		return "custom_expression"
+	case *BroadcasterQuotaConstraint:
+		return "broadcaster_quota"
+	case *RegionSpreadConstraint:
+		return "region_spread"
+	case *TravelMinimizationConstraint:
+		return "travel_minimization"
+	case *TravelFatigueConstraint:
+		return "travel_fatigue"
+	case *TravelCostConstraint:
+		return "travel_cost"
+	case *HalfSeasonTravelConstraint:
+		return "half_season_travel"
+	case *RestPeriodConstraint:
+		return "rest_period"
+	case *PrimeTimeSpreadConstraint:
+		return "prime_time_spread"
+	case *HomeAwayBalanceConstraint:
+		return "home_away_balance"
+	case *PrimeTimeAttractivenessConstraint:
+		return "prime_time_attractiveness"
+	case *ExpectedCrowdConstraint:
+		return "expected_crowd"
+	case *HomeVenueShareConstraint:
+		return "home_venue_share"
+	case *DerbyConstraint:
+		return "derby"
+	case *StrengthSpreadConstraint:
+		return "strength_spread"
+	case *DoubleHeaderConstraint:
+		return "double_header"
+	case *FixtureVariationConstraint:
+		return "fixture_variation"
+	case *SharedVenueConstraint:
+		return "shared_venue"
+	case *RoundCalendarConstraint:
+		return "round_calendar"
+	default:
+		if name, ok := registeredTypeOf(constraint); ok {
+			return name
+		}
+		return constraint.Name()
+	}
+}
+
+// remediations suggests a fix for violations of each constraint type
+var remediations = map[string]string{
+	"bye_constraint":            "Reschedule the match so each team plays or has a bye exactly once in the round",
+	"double_up":                 "Move one of the repeated fixtures so the two meetings are further apart",
+	"venue_availability":        "Move the match to another venue or to a round when the venue is available",
+	"venue_quarantine":          "Move the match to another round, or swap home and away so it is played at the other team's venue",
+	"team_availability":         "Move the match to a round when both teams are available",
+	"team_unavailability":       "Move the match to a round when both teams are available, or remove the date from the team's unavailability",
+	"prime_time_cap":            "Move prime-time slots between teams so each is within its minimum and maximum appearances",
+	"venue_recovery":            "Space the venue's matches further apart or move one to another venue",
+	"bye_round_window":          "Move the team's bye into one of the allowed bye rounds",
+	"rivalry_round":             "Schedule the rivalry fixture in its target round",
+	"magic_round":               "Move the round's matches to the magic round venue and stack their kickoffs across one weekend",
+	"max_short_turnarounds":     "Move kickoffs so the team's turnaround is longer, or put a home game or shorter trip before it",
+	"custom_expression":         "Move or reschedule the affected matches so they satisfy the rule expression",
+	"shared_venue":              "Move the match's kickoff away from the other competition's match at the venue, or move it to another venue or day",
+	"round_calendar":            "Move the kickoff into the round's dates in the season calendar, or change the calendar",
+	"broadcaster_quota":         "Reassign timeslots so the team's appearances in the category are within the quota",
+	"region_spread":             "Swap home and away teams or move matches between rounds so each region hosts within its limits",
+	"travel_minimization":       "Reorder fixtures to cut consecutive away trips for the affected teams",
+	"travel_fatigue":            "Put a home game, a bye or a shorter trip between the affected teams' long-haul trips",
+	"travel_cost":               "Group distant away games into road trips and cut long-haul trips for the most expensive teams",
+	"half_season_travel":        "Swap home and away in a meeting played in each half, or move long trips into the half the team travels less in",
+	"rest_period":               "Move kickoffs so the affected teams get longer turnarounds between matches",
+	"prime_time_spread":         "Spread prime-time slots more evenly across the affected teams",
+	"home_away_balance":         "Swap home and away teams in some fixtures to even out the affected teams' home games",
+	"prime_time_attractiveness": "Give prime-time slots to the most attractive matchups",
+	"expected_crowd":            "Move high-drawing matchups to larger venues or into prime time",
+	"home_venue_share":          "Move the affected teams' home games between their venues to match the target split",
+	"derby":                     "Move derbies into the marquee rounds, or spread them so no round has more than its share",
+	"strength_spread":           "Swap rounds with other fixtures so the team's matches against the top rated teams are further apart",
+	"double_header":             "Move matches to the venues and days of the partner competition's matches so they can be played as curtain raisers",
+	"fixture_variation":         "Move matchups repeated from last season into different rounds, especially in the opening and closing rounds",
+}
+
+// Remediation suggests how to resolve a violation of a constraint type. Locked
+// matches can only be fixed by hand, so they get the same advice regardless of type.
+func Remediation(constraintType string, severity ViolationSeverity, locked bool) string {
+	if locked {
+		return "Change the locked match by hand or unlock it so the optimizer can move it"
+	}
+	if remediation, ok := remediations[constraintType]; ok {
+		return remediation
+	}
+	if severity == SeverityHard {
+		return "Reschedule the affected matches to satisfy the constraint"
+	}
+	return "Re-optimize the draw with a higher weight on this constraint"
+}
+
+// matchTeams returns the teams playing in a match, or the team with the bye
+func matchTeams(match *models.Match) []int {
+	var teams []int
+	if match.HomeTeamID != nil {
+		teams = append(teams, *match.HomeTeamID)
+	}
+	if match.AwayTeamID != nil {
+		teams = append(teams, *match.AwayTeamID)
+	}
+	return teams
+}
+
+// affectedMatches returns the IDs of matches in the given rounds that involve
+// any of the teams. With no teams, every match in the rounds is affected.
+func affectedMatches(draw *models.Draw, teamIDs, rounds []int) []int {
+	inRound := make(map[int]bool)
+	for _, round := range rounds {
+		inRound[round] = true
+	}
+	involved := make(map[int]bool)
+	for _, teamID := range teamIDs {
+		involved[teamID] = true
+	}
+
+	var matchIDs []int
+	for _, match := range draw.Matches {
+		if !inRound[match.Round] {
+			continue
+		}
+		if len(teamIDs) > 0 {
+			matched := false
+			for _, teamID := range matchTeams(match) {
+				matched = matched || involved[teamID]
+			}
+			if !matched {
+				continue
+			}
+		}
+		matchIDs = append(matchIDs, match.ID)
+	}
+
+	return matchIDs
+}
+
+// teamsBelow returns the teams a constraint scores below the threshold, when
+// it scores teams individually
+func teamsBelow(constraint Constraint, index *DrawIndex, threshold float64) []int {
+	scorer, ok := constraint.(TeamScorer)
+	if !ok {
+		return nil
+	}
+
+	var teams []int
+	for teamID, score := range TeamScoresWith(scorer, index) {
+		if score < threshold {
+			teams = append(teams, teamID)
+		}
+	}
+	sortInts(teams)
+	return teams
+}
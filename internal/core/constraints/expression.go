@@ -0,0 +1,563 @@
+package constraints
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Rule expressions are evaluated against one match at a time. The language has
+// numbers, "strings" and true/false; NOT, AND and OR (or !, && and ||);
+// comparisons with ==, !=, <, <=, > and >=; and +, - and * on numbers.
+//
+// Match variables:
+//
+//	round, rounds       the match's round and the number of rounds in the draw
+//	home, away, venue   team and venue IDs, 0 when unset
+//	weekday             lower-case day name, e.g. "friday", or "" when undated
+//	hour                kickoff hour, or -1 when the kickoff time isn't set
+//	prime_time          whether the match is in a prime-time slot
+//	scheduled           whether the match has a date
+//
+// team(id) fields, measured at the match's round:
+//
+//	plays, is_home, is_away             whether the team plays in this match, and where
+//	consecutive_home, consecutive_away  the team's home or away run ending this round; byes end runs
+//	home_games, away_games              the team's season totals
+
+// exprKind is the static type of an expression
+type exprKind int
+
+const (
+	numberExpr exprKind = iota
+	boolExpr
+	stringExpr
+)
+
+// String returns the name of an expression type
+func (k exprKind) String() string {
+	switch k {
+	case numberExpr:
+		return "number"
+	case boolExpr:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// exprNode is a type-checked expression, compiled to a closure
+type exprNode struct {
+	kind exprKind
+	eval func(env *exprEnv) interface{}
+}
+
+// exprEnv is the match and draw an expression is evaluated against
+type exprEnv struct {
+	match *models.Match
+	draw  *models.Draw
+	teams map[int]*teamRecord
+}
+
+// teamRecord is a team's matches by round and its season totals
+type teamRecord struct {
+	byRound   map[int]*models.Match
+	homeGames int
+	awayGames int
+}
+
+// newExprEnv creates an environment for evaluating expressions against a
+// draw's matches; team records are shared between matches
+func newExprEnv(draw *models.Draw) *exprEnv {
+	return &exprEnv{draw: draw, teams: make(map[int]*teamRecord)}
+}
+
+// team returns a team's record, building it on first use
+func (env *exprEnv) team(teamID int) *teamRecord {
+	if record, ok := env.teams[teamID]; ok {
+		return record
+	}
+
+	record := &teamRecord{byRound: make(map[int]*models.Match)}
+	for _, match := range env.draw.Matches {
+		if !match.HasTeam(teamID) {
+			continue
+		}
+		record.byRound[match.Round] = match
+		if isHome, _ := match.IsHomeGame(teamID); isHome {
+			record.homeGames++
+		} else {
+			record.awayGames++
+		}
+	}
+
+	env.teams[teamID] = record
+	return record
+}
+
+// run returns how many consecutive rounds up to and including round the team
+// played at home, or away
+func (record *teamRecord) run(teamID, round int, home bool) int {
+	count := 0
+	for r := round; r >= 1; r-- {
+		match, ok := record.byRound[r]
+		if !ok {
+			break
+		}
+		if isHome, _ := match.IsHomeGame(teamID); isHome != home {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// compileExpression parses and type-checks a rule expression, which must be boolean
+func compileExpression(source string) (*exprNode, error) {
+	tokens, err := lexExpression(source)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &exprParser{tokens: tokens}
+	node, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := parser.peek(); next.kind != endToken {
+		return nil, fmt.Errorf("unexpected %q at position %d", next.text, next.pos)
+	}
+	if node.kind != boolExpr {
+		return nil, fmt.Errorf("expression must be a condition, not a %s", node.kind)
+	}
+
+	return node, nil
+}
+
+// tokenKind classifies a lexed token
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	numberToken
+	stringToken
+	identToken
+	symbolToken
+)
+
+// exprToken is a lexed token and its position in the source
+type exprToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lexExpression splits an expression into tokens
+func lexExpression(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{numberToken, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, exprToken{identToken, string(runes[start:i]), start})
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			tokens = append(tokens, exprToken{stringToken, string(runes[start+1 : i]), start})
+			i++
+		default:
+			if i+1 < len(runes) {
+				if pair := string(runes[i : i+2]); pair == "==" || pair == "!=" || pair == "<=" || pair == ">=" || pair == "&&" || pair == "||" {
+					tokens = append(tokens, exprToken{symbolToken, pair, i})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("()<>!+-*.", r) {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, exprToken{symbolToken, string(r), i})
+			i++
+		}
+	}
+
+	return append(tokens, exprToken{endToken, "end of expression", len(runes)}), nil
+}
+
+// exprParser is a recursive descent parser over lexed tokens
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+// peek returns the next token without consuming it
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it's one of the given symbols or
+// keywords, which match case-insensitively
+func (p *exprParser) accept(options ...string) (string, bool) {
+	next := p.peek()
+	if next.kind != symbolToken && next.kind != identToken {
+		return "", false
+	}
+	for _, option := range options {
+		if strings.EqualFold(next.text, option) {
+			p.pos++
+			return option, true
+		}
+	}
+	return "", false
+}
+
+// expect consumes a required symbol
+func (p *exprParser) expect(symbol string) error {
+	if _, ok := p.accept(symbol); !ok {
+		next := p.peek()
+		return fmt.Errorf("expected %q at position %d, found %q", symbol, next.pos, next.text)
+	}
+	return nil
+}
+
+// parseOr parses conditions joined by OR
+func (p *exprParser) parseOr() (*exprNode, error) {
+	return p.parseLogical(p.parseAnd, "OR", "||")
+}
+
+// parseAnd parses conditions joined by AND
+func (p *exprParser) parseAnd() (*exprNode, error) {
+	return p.parseLogical(p.parseNot, "AND", "&&")
+}
+
+// parseLogical parses operands joined by a short-circuiting logical operator
+func (p *exprParser) parseLogical(operand func() (*exprNode, error), keyword, symbol string) (*exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		pos := p.peek().pos
+		if _, ok := p.accept(keyword, symbol); !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if left.kind != boolExpr || right.kind != boolExpr {
+			return nil, fmt.Errorf("%s at position %d needs conditions on both sides", keyword, pos)
+		}
+
+		l, r := left.eval, right.eval
+		isOr := keyword == "OR"
+		left = &exprNode{kind: boolExpr, eval: func(env *exprEnv) interface{} {
+			if l(env).(bool) == isOr {
+				return isOr
+			}
+			return r(env).(bool)
+		}}
+	}
+}
+
+// parseNot parses a condition with optional negation
+func (p *exprParser) parseNot() (*exprNode, error) {
+	pos := p.peek().pos
+	if _, ok := p.accept("NOT", "!"); !ok {
+		return p.parseComparison()
+	}
+
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	if operand.kind != boolExpr {
+		return nil, fmt.Errorf("NOT at position %d needs a condition", pos)
+	}
+
+	eval := operand.eval
+	return &exprNode{kind: boolExpr, eval: func(env *exprEnv) interface{} {
+		return !eval(env).(bool)
+	}}, nil
+}
+
+// parseComparison parses a value, optionally compared to another
+func (p *exprParser) parseComparison() (*exprNode, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	pos := p.peek().pos
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	if left.kind != right.kind {
+		return nil, fmt.Errorf("cannot compare %s with %s at position %d", left.kind, right.kind, pos)
+	}
+	if left.kind != numberExpr && op != "==" && op != "!=" {
+		return nil, fmt.Errorf("%s values can only be compared with == or != at position %d", left.kind, pos)
+	}
+
+	l, r := left.eval, right.eval
+	return &exprNode{kind: boolExpr, eval: func(env *exprEnv) interface{} {
+		a, b := l(env), r(env)
+		switch op {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		case "<":
+			return a.(float64) < b.(float64)
+		case "<=":
+			return a.(float64) <= b.(float64)
+		case ">":
+			return a.(float64) > b.(float64)
+		default:
+			return a.(float64) >= b.(float64)
+		}
+	}}, nil
+}
+
+// parseSum parses terms joined by + or -
+func (p *exprParser) parseSum() (*exprNode, error) {
+	return p.parseArithmetic(p.parseProduct, "+", "-")
+}
+
+// parseProduct parses factors joined by *
+func (p *exprParser) parseProduct() (*exprNode, error) {
+	return p.parseArithmetic(p.parseUnary, "*")
+}
+
+// parseArithmetic parses numeric operands joined by arithmetic operators
+func (p *exprParser) parseArithmetic(operand func() (*exprNode, error), operators ...string) (*exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		pos := p.peek().pos
+		op, ok := p.accept(operators...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if left.kind != numberExpr || right.kind != numberExpr {
+			return nil, fmt.Errorf("%s at position %d needs numbers on both sides", op, pos)
+		}
+
+		l, r := left.eval, right.eval
+		left = &exprNode{kind: numberExpr, eval: func(env *exprEnv) interface{} {
+			a, b := l(env).(float64), r(env).(float64)
+			switch op {
+			case "+":
+				return a + b
+			case "-":
+				return a - b
+			default:
+				return a * b
+			}
+		}}
+	}
+}
+
+// parseUnary parses a value with optional numeric negation
+func (p *exprParser) parseUnary() (*exprNode, error) {
+	pos := p.peek().pos
+	if _, ok := p.accept("-"); !ok {
+		return p.parsePrimary()
+	}
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if operand.kind != numberExpr {
+		return nil, fmt.Errorf("- at position %d needs a number", pos)
+	}
+
+	eval := operand.eval
+	return &exprNode{kind: numberExpr, eval: func(env *exprEnv) interface{} {
+		return -eval(env).(float64)
+	}}, nil
+}
+
+// parsePrimary parses a literal, variable, team field or parenthesised expression
+func (p *exprParser) parsePrimary() (*exprNode, error) {
+	token := p.peek()
+
+	switch token.kind {
+	case numberToken:
+		p.pos++
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", token.text, token.pos)
+		}
+		return constantExpr(numberExpr, value), nil
+
+	case stringToken:
+		p.pos++
+		return constantExpr(stringExpr, token.text), nil
+
+	case identToken:
+		p.pos++
+		name := strings.ToLower(token.text)
+		switch name {
+		case "true", "false":
+			return constantExpr(boolExpr, name == "true"), nil
+		case "team":
+			return p.parseTeamField(token.pos)
+		}
+		variable, ok := matchVariables[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q at position %d", token.text, token.pos)
+		}
+		return variable, nil
+	}
+
+	if _, ok := p.accept("("); ok {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", token.text, token.pos)
+}
+
+// parseTeamField parses team(id).field once "team" has been consumed
+func (p *exprParser) parseTeamField(pos int) (*exprNode, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	id, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if id.kind != numberExpr {
+		return nil, fmt.Errorf("team at position %d needs a numeric team ID", pos)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("."); err != nil {
+		return nil, err
+	}
+
+	token := p.peek()
+	if token.kind != identToken {
+		return nil, fmt.Errorf("expected a team field at position %d, found %q", token.pos, token.text)
+	}
+	p.pos++
+
+	field, ok := teamFields[strings.ToLower(token.text)]
+	if !ok {
+		return nil, fmt.Errorf("unknown team field %q at position %d", token.text, token.pos)
+	}
+
+	teamID := id.eval
+	return &exprNode{kind: field.kind, eval: func(env *exprEnv) interface{} {
+		return field.eval(env, int(teamID(env).(float64)))
+	}}, nil
+}
+
+// constantExpr returns an expression with a fixed value
+func constantExpr(kind exprKind, value interface{}) *exprNode {
+	return &exprNode{kind: kind, eval: func(env *exprEnv) interface{} { return value }}
+}
+
+// matchVariables are the variables describing the match being evaluated
+var matchVariables = map[string]*exprNode{
+	"round":  {kind: numberExpr, eval: func(env *exprEnv) interface{} { return float64(env.match.Round) }},
+	"rounds": {kind: numberExpr, eval: func(env *exprEnv) interface{} { return float64(env.draw.Rounds) }},
+	"home":   {kind: numberExpr, eval: func(env *exprEnv) interface{} { return optionalID(env.match.HomeTeamID) }},
+	"away":   {kind: numberExpr, eval: func(env *exprEnv) interface{} { return optionalID(env.match.AwayTeamID) }},
+	"venue":  {kind: numberExpr, eval: func(env *exprEnv) interface{} { return optionalID(env.match.VenueID) }},
+	"weekday": {kind: stringExpr, eval: func(env *exprEnv) interface{} {
+		if env.match.MatchDate == nil {
+			return ""
+		}
+		return strings.ToLower(env.match.MatchDate.Weekday().String())
+	}},
+	"hour": {kind: numberExpr, eval: func(env *exprEnv) interface{} {
+		if env.match.MatchTime == nil {
+			return float64(-1)
+		}
+		return float64(env.match.MatchTime.Hour())
+	}},
+	"prime_time": {kind: boolExpr, eval: func(env *exprEnv) interface{} { return env.match.IsPrimeTime }},
+	"scheduled":  {kind: boolExpr, eval: func(env *exprEnv) interface{} { return env.match.IsScheduled() }},
+}
+
+// teamField is a field of team(id)
+type teamField struct {
+	kind exprKind
+	eval func(env *exprEnv, teamID int) interface{}
+}
+
+// teamFields are the fields of team(id), measured at the match's round
+var teamFields = map[string]teamField{
+	"plays": {boolExpr, func(env *exprEnv, teamID int) interface{} { return env.match.HasTeam(teamID) }},
+	"is_home": {boolExpr, func(env *exprEnv, teamID int) interface{} {
+		isHome, err := env.match.IsHomeGame(teamID)
+		return err == nil && isHome
+	}},
+	"is_away": {boolExpr, func(env *exprEnv, teamID int) interface{} {
+		isHome, err := env.match.IsHomeGame(teamID)
+		return err == nil && !isHome
+	}},
+	"consecutive_home": {numberExpr, func(env *exprEnv, teamID int) interface{} {
+		return float64(env.team(teamID).run(teamID, env.match.Round, true))
+	}},
+	"consecutive_away": {numberExpr, func(env *exprEnv, teamID int) interface{} {
+		return float64(env.team(teamID).run(teamID, env.match.Round, false))
+	}},
+	"home_games": {numberExpr, func(env *exprEnv, teamID int) interface{} { return float64(env.team(teamID).homeGames) }},
+	"away_games": {numberExpr, func(env *exprEnv, teamID int) interface{} { return float64(env.team(teamID).awayGames) }},
+}
+
+// optionalID returns an ID as a number, or 0 when it isn't set
+func optionalID(id *int) float64 {
+	if id == nil {
+		return 0
+	}
+	return float64(*id)
+}
@@ -0,0 +1,120 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// FixtureVariationConstraint compares a draw with last season's so
+// consecutive seasons don't look copy-pasted. The draw scores worse for each
+// matchup played in the same round as last season, and for opening and
+// closing rounds that repeat last season's matchups. Home and away are
+// ignored, since a reversed fixture in the same round still looks repeated.
+//
+// The previous draw is read from storage, so repeats are only found once it
+// has been supplied.
+type FixtureVariationConstraint struct {
+	BaseConstraint
+	previousDrawID int
+	// pairings holds last season's matchups by round
+	pairings map[int]map[string]bool
+	// lastRound is last season's final round
+	lastRound int
+}
+
+// NewFixtureVariationConstraint creates a new fixture variation constraint
+// against the given draw
+func NewFixtureVariationConstraint(previousDrawID int) *FixtureVariationConstraint {
+	return &FixtureVariationConstraint{
+		BaseConstraint: NewBaseConstraint("Fixture Variation", "Avoid repeating last season's matchups in the same rounds, especially in the opening and closing rounds", false),
+		previousDrawID: previousDrawID,
+	}
+}
+
+// ReferencedDrawID returns last season's draw
+func (fv *FixtureVariationConstraint) ReferencedDrawID() int {
+	return fv.previousDrawID
+}
+
+// SetReferencedDraw records last season's matchups by round. A nil draw
+// clears them.
+func (fv *FixtureVariationConstraint) SetReferencedDraw(draw *models.Draw) {
+	fv.pairings = nil
+	fv.lastRound = 0
+	if draw == nil {
+		return
+	}
+
+	fv.pairings = make(map[int]map[string]bool)
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		if fv.pairings[match.Round] == nil {
+			fv.pairings[match.Round] = make(map[string]bool)
+		}
+		fv.pairings[match.Round][MatchupKey(*match.HomeTeamID, *match.AwayTeamID)] = true
+		if match.Round > fv.lastRound {
+			fv.lastRound = match.Round
+		}
+	}
+}
+
+// Validate always returns nil for soft constraints
+func (fv *FixtureVariationConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score averages the fraction of matches that aren't repeated in the same
+// round with the fraction of opening and closing matches that don't repeat
+// last season's. Without a previous draw the draw scores 1.0.
+func (fv *FixtureVariationConstraint) Score(draw *models.Draw) float64 {
+	if len(fv.pairings) == 0 {
+		return 1.0
+	}
+
+	lastRound := 0
+	for _, match := range draw.Matches {
+		if match.Round > lastRound {
+			lastRound = match.Round
+		}
+	}
+
+	totalMatches, repeatedMatches := 0, 0
+	totalBookends, repeatedBookends := 0, 0
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		key := MatchupKey(*match.HomeTeamID, *match.AwayTeamID)
+
+		totalMatches++
+		if fv.pairings[match.Round][key] {
+			repeatedMatches++
+		}
+
+		var previousRound int
+		switch match.Round {
+		case 1:
+			previousRound = 1
+		case lastRound:
+			previousRound = fv.lastRound
+		default:
+			continue
+		}
+		totalBookends++
+		if fv.pairings[previousRound][key] {
+			repeatedBookends++
+		}
+	}
+
+	if totalMatches == 0 {
+		return 1.0
+	}
+
+	score := float64(totalMatches-repeatedMatches) / float64(totalMatches)
+	if totalBookends == 0 {
+		return score
+	}
+	return (score + float64(totalBookends-repeatedBookends)/float64(totalBookends)) / 2
+}
@@ -0,0 +1,255 @@
+package constraints
+
+import (
+	"math"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Half-season travel defaults
+const (
+	// DefaultHalfSeasonAwayTolerance allows the halves' away games to differ by one
+	DefaultHalfSeasonAwayTolerance = 1
+	// DefaultHalfSeasonDistanceTolerance allows each half up to 60% of a
+	// team's season travel
+	DefaultHalfSeasonDistanceTolerance = 0.2
+)
+
+// HalfSeasonTravelConstraint balances each team's away games and travel
+// between the two halves of the season, so no team spends the first half at
+// home and the run home on the road. The first half runs to the split round,
+// by default half the season rounded down, e.g. rounds 1–13 of 27.
+//
+// Travel is measured in return-trip kilometres as for travel minimization,
+// from league data, so only away games are balanced until it's supplied.
+type HalfSeasonTravelConstraint struct {
+	BaseConstraint
+	splitRound        int // last round of the first half; 0 for half the season
+	awayTolerance     int
+	distanceTolerance float64
+	league            *LeagueData
+	teamWeights       TeamWeights // Emphasizes particular teams in the score
+}
+
+// SeasonHalfTravel is a team's matches, away games and travel in one half of the season
+type SeasonHalfTravel struct {
+	FirstRound int     `json:"first_round"`
+	LastRound  int     `json:"last_round"`
+	Matches    int     `json:"matches"`
+	AwayGames  int     `json:"away_games"`
+	TravelKm   float64 `json:"travel_km"`
+}
+
+// HalfSeasonTravelAnalysis compares a team's travel in the two halves of the season
+type HalfSeasonTravelAnalysis struct {
+	TeamID     int              `json:"team_id"`
+	FirstHalf  SeasonHalfTravel `json:"first_half"`
+	SecondHalf SeasonHalfTravel `json:"second_half"`
+	// AwayGameDifference is the first half's away games less the second's
+	AwayGameDifference int `json:"away_game_difference"`
+	// DistanceImbalance is the gap between the halves' travel as a share of
+	// the team's season travel, 0 when it travels nowhere
+	DistanceImbalance float64 `json:"distance_imbalance"`
+	Balanced          bool    `json:"balanced"`
+	Score             float64 `json:"score"`
+}
+
+// HalfSeasonTravelReport is every team's half-season travel breakdown
+type HalfSeasonTravelReport struct {
+	SplitRound        int                        `json:"split_round"`
+	AwayTolerance     int                        `json:"away_tolerance"`
+	DistanceTolerance float64                    `json:"distance_tolerance"`
+	UnbalancedTeams   int                        `json:"unbalanced_teams"`
+	Teams             []HalfSeasonTravelAnalysis `json:"teams"`
+}
+
+// NewHalfSeasonTravelConstraint creates a half-season travel constraint.
+// A split round of 0 splits the season in half.
+func NewHalfSeasonTravelConstraint(splitRound, awayTolerance int, distanceTolerance float64) *HalfSeasonTravelConstraint {
+	return &HalfSeasonTravelConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"HalfSeasonTravel",
+			"Balance each team's away games and travel between the halves of the season",
+			false, // This is a soft constraint
+		),
+		splitRound:        splitRound,
+		awayTolerance:     awayTolerance,
+		distanceTolerance: distanceTolerance,
+	}
+}
+
+// Validate always returns nil for soft constraints (no hard violations)
+func (hst *HalfSeasonTravelConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score calculates how evenly teams' travel is split between the halves
+func (hst *HalfSeasonTravelConstraint) Score(draw *models.Draw) float64 {
+	return hst.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed averages the teams' scores, weighted by the team weights,
+// using a shared index
+func (hst *HalfSeasonTravelConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
+	if len(teams) == 0 {
+		return 1.0
+	}
+
+	totalScore := 0.0
+	totalWeight := 0.0
+	for _, team := range teams {
+		weight := hst.teamWeights.Weight(team)
+		totalScore += weight * hst.analyze(index, team).Score
+		totalWeight += weight
+	}
+
+	return totalScore / totalWeight
+}
+
+// TeamScores returns each team's half-season balance score
+func (hst *HalfSeasonTravelConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	return hst.TeamScoresIndexed(NewDrawIndex(draw))
+}
+
+// TeamScoresIndexed returns each team's score using a shared index
+func (hst *HalfSeasonTravelConstraint) TeamScoresIndexed(index *DrawIndex) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, team := range index.Teams() {
+		scores[team] = hst.analyze(index, team).Score
+	}
+	return scores
+}
+
+// ScoreTeam returns one team's half-season balance score
+func (hst *HalfSeasonTravelConstraint) ScoreTeam(draw *models.Draw, teamID int) float64 {
+	return hst.analyze(NewDrawIndex(draw), teamID).Score
+}
+
+// ScoreTeamIndexed returns one team's score using a shared index
+func (hst *HalfSeasonTravelConstraint) ScoreTeamIndexed(index *DrawIndex, teamID int) float64 {
+	return hst.analyze(index, teamID).Score
+}
+
+// GetTeamWeights returns the weights emphasizing particular teams' scores
+func (hst *HalfSeasonTravelConstraint) GetTeamWeights() TeamWeights {
+	return hst.teamWeights
+}
+
+// SetTeamWeights weights particular teams' scores more heavily in the draw's score
+func (hst *HalfSeasonTravelConstraint) SetTeamWeights(weights TeamWeights) {
+	hst.teamWeights = weights
+}
+
+// SetLeagueData supplies the team and venue coordinates trips are measured with
+func (hst *HalfSeasonTravelConstraint) SetLeagueData(data *LeagueData) {
+	hst.league = data
+}
+
+// GetSplitRound returns the configured last round of the first half, 0 for half the season
+func (hst *HalfSeasonTravelConstraint) GetSplitRound() int {
+	return hst.splitRound
+}
+
+// GetAwayTolerance returns how many more away games one half may have
+func (hst *HalfSeasonTravelConstraint) GetAwayTolerance() int {
+	return hst.awayTolerance
+}
+
+// GetDistanceTolerance returns the gap between the halves' travel allowed,
+// as a share of a team's season travel
+func (hst *HalfSeasonTravelConstraint) GetDistanceTolerance() float64 {
+	return hst.distanceTolerance
+}
+
+// Report returns every team's half-season breakdown, in team order
+func (hst *HalfSeasonTravelConstraint) Report(draw *models.Draw) HalfSeasonTravelReport {
+	index := NewDrawIndex(draw)
+	report := HalfSeasonTravelReport{
+		SplitRound:        hst.split(draw),
+		AwayTolerance:     hst.awayTolerance,
+		DistanceTolerance: hst.distanceTolerance,
+		Teams:             make([]HalfSeasonTravelAnalysis, 0, len(index.Teams())),
+	}
+
+	for _, team := range index.Teams() {
+		analysis := hst.analyze(index, team)
+		if !analysis.Balanced {
+			report.UnbalancedTeams++
+		}
+		report.Teams = append(report.Teams, analysis)
+	}
+	return report
+}
+
+// split returns the last round of the first half for the draw
+func (hst *HalfSeasonTravelConstraint) split(draw *models.Draw) int {
+	if hst.splitRound > 0 {
+		return hst.splitRound
+	}
+	return draw.Rounds / 2
+}
+
+// analyze splits a team's season at the split round. The score is 1.0 less
+// the average of the away game and travel imbalances beyond their
+// tolerances, each as a share of the team's season total.
+func (hst *HalfSeasonTravelConstraint) analyze(index *DrawIndex, teamID int) HalfSeasonTravelAnalysis {
+	draw := index.Draw()
+	split := hst.split(draw)
+	analysis := HalfSeasonTravelAnalysis{
+		TeamID:     teamID,
+		FirstHalf:  SeasonHalfTravel{FirstRound: 1, LastRound: split},
+		SecondHalf: SeasonHalfTravel{FirstRound: split + 1, LastRound: draw.Rounds},
+		Balanced:   true,
+		Score:      1.0,
+	}
+
+	for _, match := range index.TeamMatches(teamID) {
+		half := &analysis.SecondHalf
+		if match.Round <= split {
+			half = &analysis.FirstHalf
+		}
+		half.Matches++
+		if isHome, _ := match.IsHomeGame(teamID); !isHome {
+			half.AwayGames++
+		}
+		half.TravelKm += hst.tripDistance(match, teamID)
+	}
+
+	analysis.AwayGameDifference = analysis.FirstHalf.AwayGames - analysis.SecondHalf.AwayGames
+	awayGames := analysis.FirstHalf.AwayGames + analysis.SecondHalf.AwayGames
+	awayPenalty := 0.0
+	if excess := math.Abs(float64(analysis.AwayGameDifference)) - float64(hst.awayTolerance); excess > 0 {
+		analysis.Balanced = false
+		awayPenalty = excess / float64(awayGames)
+	}
+
+	travelKm := analysis.FirstHalf.TravelKm + analysis.SecondHalf.TravelKm
+	distancePenalty := 0.0
+	if travelKm > 0 {
+		analysis.DistanceImbalance = math.Abs(analysis.FirstHalf.TravelKm-analysis.SecondHalf.TravelKm) / travelKm
+		if excess := analysis.DistanceImbalance - hst.distanceTolerance; excess > 0 {
+			analysis.Balanced = false
+			distancePenalty = excess
+		}
+	}
+
+	analysis.Score = 1.0 - (awayPenalty+distancePenalty)/2
+	return analysis
+}
+
+// tripDistance returns the return-trip kilometres for a team to play a match
+func (hst *HalfSeasonTravelConstraint) tripDistance(match *models.Match, teamID int) float64 {
+	homeLat, homeLon, ok := hst.league.TeamHomeLocation(teamID)
+	if !ok {
+		return 0
+	}
+	venueLat, venueLon, ok := hst.league.MatchLocation(match)
+	if !ok {
+		return 0
+	}
+
+	return 2 * geo.HaversineKm(homeLat, homeLon, venueLat, venueLon)
+}
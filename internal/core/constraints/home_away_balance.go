@@ -5,9 +5,11 @@ import (
 )
 
 // HomeAwayBalanceConstraint ensures fair distribution of home and away games
+
 type HomeAwayBalanceConstraint struct {
 	BaseConstraint
-	maxDeviation float64 // Maximum allowed deviation from 50/50 split
+	maxDeviation float64     // Maximum allowed deviation from 50/50 split
+	teamWeights  TeamWeights // Emphasizes particular teams in the score
 }
 
 // NewHomeAwayBalanceConstraint creates a new home/away balance constraint
@@ -30,31 +32,73 @@ func (habc *HomeAwayBalanceConstraint) Validate(match *models.Match, draw *model
 
 // Score calculates how well the draw balances home and away games
 func (habc *HomeAwayBalanceConstraint) Score(draw *models.Draw) float64 {
-	teams := habc.getUniqueTeams(draw)
+	return habc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed averages the teams' balance scores, weighted by the
+// team weights, using a shared index
+func (habc *HomeAwayBalanceConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
 	if len(teams) == 0 {
 		return 1.0
 	}
-	
+
 	totalScore := 0.0
-	
+	totalWeight := 0.0
+
 	for _, team := range teams {
-		teamScore := habc.scoreTeamBalance(draw, team)
-		totalScore += teamScore
+		weight := habc.teamWeights.Weight(team)
+		totalScore += weight * habc.scoreTeamBalance(index, team)
+		totalWeight += weight
 	}
-	
-	return totalScore / float64(len(teams))
+
+	return totalScore / totalWeight
+}
+
+// TeamScores returns each team's home/away balance score
+func (habc *HomeAwayBalanceConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	return habc.TeamScoresIndexed(NewDrawIndex(draw))
+}
+
+// TeamScoresIndexed returns each team's balance score using a shared index
+func (habc *HomeAwayBalanceConstraint) TeamScoresIndexed(index *DrawIndex) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, team := range index.Teams() {
+		scores[team] = habc.scoreTeamBalance(index, team)
+	}
+	return scores
+}
+
+// ScoreTeam returns one team's home/away balance score
+func (habc *HomeAwayBalanceConstraint) ScoreTeam(draw *models.Draw, teamID int) float64 {
+	return habc.scoreTeamBalance(NewDrawIndex(draw), teamID)
+}
+
+// ScoreTeamIndexed returns one team's balance score using a shared index
+func (habc *HomeAwayBalanceConstraint) ScoreTeamIndexed(index *DrawIndex, teamID int) float64 {
+	return habc.scoreTeamBalance(index, teamID)
+}
+
+// GetTeamWeights returns the weights emphasizing particular teams' scores
+func (habc *HomeAwayBalanceConstraint) GetTeamWeights() TeamWeights {
+	return habc.teamWeights
+}
+
+// SetTeamWeights weights particular teams' scores more heavily in the draw's score
+func (habc *HomeAwayBalanceConstraint) SetTeamWeights(weights TeamWeights) {
+	habc.teamWeights = weights
 }
 
 // scoreTeamBalance calculates the home/away balance score for a specific team
-func (habc *HomeAwayBalanceConstraint) scoreTeamBalance(draw *models.Draw, teamID int) float64 {
-	teamMatches := draw.GetMatchesByTeam(teamID)
+func (habc *HomeAwayBalanceConstraint) scoreTeamBalance(index *DrawIndex, teamID int) float64 {
+	teamMatches := index.TeamMatches(teamID)
 	if len(teamMatches) == 0 {
 		return 1.0
 	}
-	
+
 	homeGames := 0
 	awayGames := 0
-	
+
 	for _, match := range teamMatches {
 		if !match.IsBye() {
 			if isHome, _ := match.IsHomeGame(teamID); isHome {
@@ -64,19 +108,19 @@ func (habc *HomeAwayBalanceConstraint) scoreTeamBalance(draw *models.Draw, teamI
 			}
 		}
 	}
-	
+
 	totalGames := homeGames + awayGames
 	if totalGames == 0 {
 		return 1.0
 	}
-	
+
 	// Calculate the deviation from perfect balance (50/50)
 	homeRatio := float64(homeGames) / float64(totalGames)
 	deviation := homeRatio - 0.5
 	if deviation < 0 {
 		deviation = -deviation
 	}
-	
+
 	// Score based on how close to perfect balance
 	if deviation <= habc.maxDeviation {
 		// Within acceptable range - score based on proximity to perfect balance
@@ -87,27 +131,6 @@ func (habc *HomeAwayBalanceConstraint) scoreTeamBalance(draw *models.Draw, teamI
 	}
 }
 
-// getUniqueTeams extracts all unique team IDs from the draw
-func (habc *HomeAwayBalanceConstraint) getUniqueTeams(draw *models.Draw) []int {
-	teamSet := make(map[int]bool)
-	
-	for _, match := range draw.Matches {
-		if match.HomeTeamID != nil {
-			teamSet[*match.HomeTeamID] = true
-		}
-		if match.AwayTeamID != nil {
-			teamSet[*match.AwayTeamID] = true
-		}
-	}
-	
-	var teams []int
-	for teamID := range teamSet {
-		teams = append(teams, teamID)
-	}
-	
-	return teams
-}
-
 // GetMaxDeviation returns the maximum allowed deviation from 50/50 balance
 func (habc *HomeAwayBalanceConstraint) GetMaxDeviation() float64 {
 	return habc.maxDeviation
@@ -120,6 +143,11 @@ func (habc *HomeAwayBalanceConstraint) SetMaxDeviation(deviation float64) {
 
 // AnalyzeTeamHomeAwayBalance provides detailed balance analysis for a team
 func (habc *HomeAwayBalanceConstraint) AnalyzeTeamHomeAwayBalance(draw *models.Draw, teamID int) HomeAwayAnalysis {
+	return habc.analyzeTeamBalance(NewDrawIndex(draw), teamID)
+}
+
+// analyzeTeamBalance analyzes a team's balance using a shared index
+func (habc *HomeAwayBalanceConstraint) analyzeTeamBalance(index *DrawIndex, teamID int) HomeAwayAnalysis {
 	analysis := HomeAwayAnalysis{
 		TeamID:             teamID,
 		TotalGames:         0,
@@ -133,7 +161,7 @@ func (habc *HomeAwayBalanceConstraint) AnalyzeTeamHomeAwayBalance(draw *models.D
 		AwayRounds:         []int{},
 	}
 	
-	teamMatches := draw.GetMatchesByTeam(teamID)
+	teamMatches := index.TeamMatches(teamID)
 	
 	for _, match := range teamMatches {
 		if !match.IsBye() {
@@ -179,11 +207,12 @@ type HomeAwayAnalysis struct {
 
 // GetAllTeamHomeAwayAnalysis returns balance analysis for all teams
 func (habc *HomeAwayBalanceConstraint) GetAllTeamHomeAwayAnalysis(draw *models.Draw) []HomeAwayAnalysis {
-	teams := habc.getUniqueTeams(draw)
+	index := NewDrawIndex(draw)
+	teams := index.Teams()
 	analyses := make([]HomeAwayAnalysis, len(teams))
 	
 	for i, teamID := range teams {
-		analyses[i] = habc.AnalyzeTeamHomeAwayBalance(draw, teamID)
+		analyses[i] = habc.analyzeTeamBalance(index, teamID)
 	}
 	
 	return analyses
@@ -316,7 +345,7 @@ func (habc *HomeAwayBalanceConstraint) AnalyzeHomeAwaySequences(draw *models.Dra
 		AwaySequences:       []Sequence{},
 	}
 	
-	teamMatches := habc.getTeamMatchesByRound(draw, teamID)
+	teamMatches := NewDrawIndex(draw).TeamMatchesByRound(teamID)
 	
 	currentSequence := ""
 	sequenceStart := 0
@@ -384,19 +413,6 @@ func (habc *HomeAwayBalanceConstraint) recordSequence(analysis *SequenceAnalysis
 	}
 }
 
-// getTeamMatchesByRound returns team matches organized by round
-func (habc *HomeAwayBalanceConstraint) getTeamMatchesByRound(draw *models.Draw, teamID int) map[int]*models.Match {
-	matches := make(map[int]*models.Match)
-	
-	for _, match := range draw.Matches {
-		if match.HasTeam(teamID) {
-			matches[match.Round] = match
-		}
-	}
-	
-	return matches
-}
-
 // SequenceAnalysis contains analysis of consecutive home/away sequences
 type SequenceAnalysis struct {
 	TeamID              int        `json:"team_id"`
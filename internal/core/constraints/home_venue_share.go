@@ -0,0 +1,91 @@
+package constraints
+
+import (
+	"math"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// HomeVenueShareConstraint keeps teams that split home games across several
+// venues close to their target split, such as 70% of home games at one ground
+// and 30% at another. A team scores one minus the fraction of its home games
+// that would have to change venue to hit its targets exactly.
+type HomeVenueShareConstraint struct {
+	BaseConstraint
+	league *LeagueData
+}
+
+// NewHomeVenueShareConstraint creates a new home venue share constraint
+func NewHomeVenueShareConstraint() *HomeVenueShareConstraint {
+	return &HomeVenueShareConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"HomeVenueShare",
+			"Split multi-venue teams' home games across their venues in the target proportions",
+			false, // This is a soft constraint
+		),
+	}
+}
+
+// SetLeagueData supplies each team's home venues and target shares
+func (hvsc *HomeVenueShareConstraint) SetLeagueData(data *LeagueData) {
+	hvsc.league = data
+}
+
+// Validate always returns nil for soft constraints
+func (hvsc *HomeVenueShareConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score returns the average adherence of multi-venue teams to their split.
+// Draws without such teams score 1.0.
+func (hvsc *HomeVenueShareConstraint) Score(draw *models.Draw) float64 {
+	return meanTeamScore(hvsc.TeamScores(draw))
+}
+
+// TeamScores returns the score of each team with more than one home venue
+func (hvsc *HomeVenueShareConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	scores := make(map[int]float64)
+	if hvsc.league == nil {
+		return scores
+	}
+
+	for teamID, team := range hvsc.league.Teams {
+		if team.HasVenueRotation() {
+			scores[teamID] = hvsc.scoreTeam(draw, team)
+		}
+	}
+	return scores
+}
+
+// scoreTeam compares where a team's home games are played with its targets
+func (hvsc *HomeVenueShareConstraint) scoreTeam(draw *models.Draw, team *models.Team) float64 {
+	hosted := make(map[int]int)
+	homeGames := 0
+	for _, match := range draw.Matches {
+		if match.IsBye() || *match.HomeTeamID != team.ID || match.VenueID == nil {
+			continue
+		}
+		hosted[*match.VenueID]++
+		homeGames++
+	}
+	if homeGames == 0 {
+		return 1.0
+	}
+
+	// Half the total absolute difference between actual and target shares,
+	// counting games at venues outside the split in full
+	deviation := 0.0
+	targets := make(map[int]bool)
+	for _, venue := range team.HomeVenues {
+		targets[venue.VenueID] = true
+		deviation += math.Abs(float64(hosted[venue.VenueID])/float64(homeGames) - venue.Share)
+	}
+	for venueID, count := range hosted {
+		if !targets[venueID] {
+			deviation += float64(count) / float64(homeGames)
+		}
+	}
+
+	return math.Max(0, 1-deviation/2)
+}
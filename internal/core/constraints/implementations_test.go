@@ -1,10 +1,16 @@
 package constraints
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 )
 
 // TestByeConstraint tests the bye constraint implementation
@@ -42,6 +48,137 @@ func TestByeConstraint(t *testing.T) {
 	if err != nil {
 		t.Errorf("Valid bye distribution should pass validation: %v", err)
 	}
+	
+	// Stored byes must name the teams left out of each round
+	draw.Byes = draw.ByesFromMatches()
+	if err := constraint.ValidateDrawByes(draw); err != nil {
+		t.Errorf("Matching stored byes should pass validation: %v", err)
+	}
+	draw.Byes[0].HomeTeamID = draw.Byes[1].HomeTeamID
+	if err := constraint.ValidateDrawByes(draw); err == nil {
+		t.Error("Stored byes for the wrong teams should fail validation")
+	}
+}
+
+// TestByeRoundWindowConstraint tests restricting byes to allowed rounds
+func TestByeRoundWindowConstraint(t *testing.T) {
+	constraint := NewByeRoundWindowConstraint([]int{1, 2})
+	
+	// Test constraint properties
+	if constraint.Name() != "ByeRoundWindow" {
+		t.Error("Wrong constraint name")
+	}
+	if !constraint.IsHard() {
+		t.Error("Bye round window constraint should be hard")
+	}
+	
+	// Teams 3, 2 and 1 have byes in rounds 1, 2 and 3
+	draw := createTestDrawWithByes()
+	
+	outside := constraint.GetOutOfWindowByes(draw)
+	if len(outside) != 1 {
+		t.Fatalf("Expected 1 bye outside the window, got %d", len(outside))
+	}
+	if outside[0].TeamID != 1 || outside[0].Round != 3 {
+		t.Errorf("Team 1's round 3 bye should be outside the window, got %+v", outside[0])
+	}
+	
+	if errors := constraint.ValidateDraw(draw); len(errors) != 1 {
+		t.Errorf("Expected 1 draw-level violation, got %d", len(errors))
+	}
+	
+	expectedScore := 2.0 / 3.0
+	if score := constraint.Score(draw); score < expectedScore-0.001 || score > expectedScore+0.001 {
+		t.Errorf("Expected score %f, got %f", expectedScore, score)
+	}
+	
+	byRound := constraint.GetByesByRound(draw)
+	if len(byRound[1]) != 1 || byRound[1][0] != 3 {
+		t.Errorf("Team 3 should have the round 1 bye, got %v", byRound[1])
+	}
+	
+	// Explicit bye matches are checked per match
+	byeMatch := &models.Match{ID: 10, DrawID: 1, Round: 3}
+	if err := constraint.Validate(byeMatch, draw); err == nil {
+		t.Error("Bye match outside the window should be rejected")
+	}
+	byeMatch.Round = 2
+	if err := constraint.Validate(byeMatch, draw); err != nil {
+		t.Errorf("Bye match inside the window should be allowed: %v", err)
+	}
+	
+	// Draw-level violations make the engine reject the draw
+	engine := NewConstraintEngine()
+	engine.AddHardConstraint(constraint)
+	if score := engine.ScoreDraw(draw); score != 0.0 {
+		t.Errorf("Draw with byes outside the window should score 0, got %f", score)
+	}
+	
+	// Every bye inside the window passes
+	wide := NewByeRoundWindowConstraint([]int{3, 1, 2})
+	if errors := wide.ValidateDraw(draw); len(errors) != 0 {
+		t.Errorf("All byes are inside the window, got %v", errors)
+	}
+	if rounds := wide.GetAllowedRounds(); len(rounds) != 3 || rounds[0] != 1 || rounds[2] != 3 {
+		t.Errorf("Allowed rounds should be sorted, got %v", rounds)
+	}
+}
+
+// TestRivalryRoundConstraint tests pinning rivalry fixtures to target rounds
+func TestRivalryRoundConstraint(t *testing.T) {
+	// Teams 1 and 2 meet in round 1, teams 1 and 3 in round 2
+	draw := createTestDraw()
+	
+	fixtures := []RivalryFixture{
+		{TeamA: 2, TeamB: 1, Round: 1}, // Placed, listed away team first
+		{TeamA: 1, TeamB: 3, Round: 3}, // Played in round 2 instead
+	}
+	constraint := NewRivalryRoundConstraint(fixtures, true)
+	
+	// Test constraint properties
+	if constraint.Name() != "RivalryRound" {
+		t.Error("Wrong constraint name")
+	}
+	if !constraint.IsHard() {
+		t.Error("Rivalry round constraint should be hard when configured so")
+	}
+	
+	if score := constraint.Score(draw); score != 0.5 {
+		t.Errorf("Expected half the fixtures placed, got %f", score)
+	}
+	
+	// Round 3 pairs team 1 with team 4 and team 3 with team 2
+	if err := constraint.Validate(draw.Matches[4], draw); err == nil {
+		t.Error("Team 1 playing someone else in the rivalry round should be rejected")
+	}
+	if err := constraint.Validate(draw.Matches[0], draw); err != nil {
+		t.Errorf("The placed rivalry fixture should be allowed: %v", err)
+	}
+	
+	misplaced := constraint.GetMisplacedFixtures(draw)
+	if len(misplaced) != 1 {
+		t.Fatalf("Expected 1 misplaced fixture, got %d", len(misplaced))
+	}
+	if misplaced[0].Round != 3 || len(misplaced[0].ScheduledRounds) != 1 || misplaced[0].ScheduledRounds[0] != 2 {
+		t.Errorf("Expected the 1-3 fixture to be found in round 2, got %+v", misplaced[0])
+	}
+	
+	// Fixtures in rounds neither team plays in are reported at the draw level
+	unscheduled := NewRivalryRoundConstraint([]RivalryFixture{{TeamA: 1, TeamB: 2, Round: 5}}, true)
+	if errors := unscheduled.ValidateDraw(draw); len(errors) != 1 {
+		t.Errorf("Expected 1 draw-level violation, got %d", len(errors))
+	}
+	
+	// As a soft constraint it only lowers the score
+	soft := NewRivalryRoundConstraint(fixtures, false)
+	if soft.IsHard() {
+		t.Error("Rivalry round constraint should be soft when configured so")
+	}
+	engine := NewConstraintEngine()
+	engine.AddSoftConstraint(soft, 1.0)
+	if score := engine.ScoreDraw(draw); score <= 0.0 || score >= 1.0 {
+		t.Errorf("Misplaced soft rivalry fixtures should lower but not zero the score, got %f", score)
+	}
 }
 
 // TestDoubleUpConstraint tests the double-up constraint implementation
@@ -138,6 +275,54 @@ func TestVenueAvailabilityConstraint(t *testing.T) {
 	}
 }
 
+// TestVenueQuarantineConstraint tests venue quarantine by round
+func TestVenueQuarantineConstraint(t *testing.T) {
+	factory := NewConstraintFactory()
+	built, err := factory.createHardConstraint(HardConstraintConfig{
+		Type:   "venue_quarantine",
+		Params: map[string]interface{}{"venue_id": 1.0, "rounds": []interface{}{26.0, 25.0}, "reason": "finals ground preparation"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create venue quarantine constraint: %v", err)
+	}
+	constraint := built.(*VenueQuarantineConstraint)
+	
+	if !constraint.IsHard() {
+		t.Error("Venue quarantine constraint should be hard")
+	}
+	if constraint.GetVenueID() != 1 || fmt.Sprint(constraint.GetRounds()) != "[25 26]" {
+		t.Errorf("Wrong venue or rounds: %d %v", constraint.GetVenueID(), constraint.GetRounds())
+	}
+	
+	// No dates are needed; the round decides
+	quarantined := &models.Match{Round: 25, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0], VenueID: &[]int{1}[0]}
+	otherVenue := &models.Match{Round: 25, HomeTeamID: &[]int{3}[0], AwayTeamID: &[]int{4}[0], VenueID: &[]int{2}[0]}
+	otherRound := &models.Match{Round: 24, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{3}[0], VenueID: &[]int{1}[0]}
+	draw := &models.Draw{Matches: []*models.Match{quarantined, otherVenue, otherRound}}
+	
+	err = constraint.Validate(quarantined, draw)
+	if err == nil || !strings.Contains(err.Error(), "finals ground preparation") {
+		t.Errorf("Expected a violation naming the reason, got %v", err)
+	}
+	if err := constraint.Validate(otherVenue, draw); err != nil {
+		t.Errorf("Other venues should not be quarantined: %v", err)
+	}
+	if err := constraint.Validate(otherRound, draw); err != nil {
+		t.Errorf("Other rounds should not be quarantined: %v", err)
+	}
+	
+	if score := constraint.Score(draw); score != 0.5 {
+		t.Errorf("Expected score 0.5 with one of two venue matches quarantined, got %f", score)
+	}
+	
+	if _, err := factory.createHardConstraint(HardConstraintConfig{
+		Type:   "venue_quarantine",
+		Params: map[string]interface{}{"venue_id": 1.0, "rounds": []interface{}{}},
+	}); err == nil {
+		t.Error("Expected an error for an empty rounds list")
+	}
+}
+
 // TestTeamAvailabilityConstraint tests team availability constraint
 func TestTeamAvailabilityConstraint(t *testing.T) {
 	unavailableDates := []time.Time{
@@ -178,6 +363,107 @@ func TestTeamAvailabilityConstraint(t *testing.T) {
 	}
 }
 
+// TestSeasonCalendarDating tests that constraints date undated matches from
+// the season calendar and that kickoffs must fall within their round
+func TestSeasonCalendarDating(t *testing.T) {
+	team := func(id int) *int { return &id }
+	day := func(d int) time.Time { return time.Date(2025, time.June, d, 0, 0, 0, 0, time.UTC) }
+	calendar := &models.SeasonCalendar{
+		Name:       "NRL 2025",
+		SeasonYear: 2025,
+		Rounds: []models.CalendarRound{
+			{Round: 1, StartDate: day(5), EndDate: day(8), Kind: models.RoundKindRegular},
+			{Round: 2, StartDate: day(9), EndDate: day(11), Kind: models.RoundKindOrigin},
+		},
+	}
+	draw := &models.Draw{
+		ID:     1,
+		Rounds: 2,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: team(1), AwayTeamID: team(2)},
+			{ID: 2, Round: 2, HomeTeamID: team(1), AwayTeamID: team(3)},
+		},
+	}
+
+	// Without a calendar undated matches aren't checked
+	rest := NewHardRestPeriodConstraint(5)
+	if err := rest.Validate(draw.Matches[1], draw); err != nil {
+		t.Fatalf("Undated matches shouldn't be checked without a calendar, got %v", err)
+	}
+
+	engine := NewConstraintEngine()
+	engine.AddHardConstraint(rest)
+	availability := NewTeamAvailabilityConstraint(3, []time.Time{day(9), day(10), day(11)})
+	engine.AddHardConstraint(availability)
+	engine.SetCalendar(calendar)
+
+	// Round 1 starts on the 5th and round 2 on the 9th: 3 days' rest
+	if err := rest.Validate(draw.Matches[1], draw); err == nil {
+		t.Error("Should date both rounds from the calendar and reject 3 days' rest")
+	}
+	if err := availability.Validate(draw.Matches[1], draw); err == nil {
+		t.Error("Team 3 is unavailable for every day of round 2")
+	}
+
+	// Kickoffs outside their round's dates break the calendar
+	outside := day(12)
+	draw.Matches[1].MatchDate = &outside
+	var calendarErr bool
+	for _, violation := range engine.AnalyzeDraw(draw) {
+		if violation.ConstraintType == "round_calendar" {
+			calendarErr = true
+		}
+	}
+	if !calendarErr {
+		t.Error("Should reject a round 2 kickoff after the round's dates")
+	}
+
+	engine.SetCalendar(nil)
+	if engine.Calendar() != nil || len(engine.GetHardConstraints()) != 2 {
+		t.Errorf("Clearing the calendar should remove the round calendar constraint, got %d hard constraints", len(engine.GetHardConstraints()))
+	}
+}
+
+// TestStoredTeamAvailability tests that league data brings stored unavailable dates into the engine
+func TestStoredTeamAvailability(t *testing.T) {
+	unavailable := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	match := &models.Match{
+		ID:         1,
+		DrawID:     1,
+		Round:      1,
+		HomeTeamID: &[]int{1}[0],
+		AwayTeamID: &[]int{2}[0],
+		MatchDate:  &unavailable,
+	}
+	draw := &models.Draw{Matches: []*models.Match{match}}
+	
+	teams := []*models.Team{
+		{ID: 1, Unavailability: []models.TeamUnavailability{{TeamID: 1, Date: unavailable}}},
+		{ID: 2},
+	}
+	
+	engine := NewConstraintEngine()
+	engine.SetLeagueData(NewLeagueData(teams, nil))
+	engine.SetLeagueData(NewLeagueData(teams, nil))
+	
+	// Setting league data again replaces the constraint rather than adding another
+	if len(engine.GetHardConstraints()) != 1 {
+		t.Fatalf("Expected one stored availability constraint, got %d", len(engine.GetHardConstraints()))
+	}
+	if errs := engine.ValidateDraw(draw); len(errs) != 1 {
+		t.Errorf("Expected the match on the stored unavailable date to be rejected, got %v", errs)
+	}
+	if violations := engine.AnalyzeDraw(draw); len(violations) == 0 || violations[0].ConstraintType != "team_unavailability" {
+		t.Errorf("Expected a team_unavailability violation, got %+v", violations)
+	}
+	
+	// Without stored dates the constraint is dropped
+	engine.SetLeagueData(NewLeagueData([]*models.Team{{ID: 1}, {ID: 2}}, nil))
+	if len(engine.GetHardConstraints()) != 0 {
+		t.Errorf("Expected no hard constraints once the dates are removed, got %d", len(engine.GetHardConstraints()))
+	}
+}
+
 // TestTravelMinimizationConstraint tests travel minimization constraint
 func TestTravelMinimizationConstraint(t *testing.T) {
 	constraint := NewTravelMinimizationConstraint(2)
@@ -218,6 +504,70 @@ func TestTravelMinimizationConstraint(t *testing.T) {
 	}
 }
 
+// TestTravelMinimizationDistance tests travel distance scoring from team and venue coordinates
+func TestTravelMinimizationDistance(t *testing.T) {
+	constraint := NewTravelMinimizationConstraint(NoConsecutiveAwayLimit)
+	constraint.SetMaxTotalTravelKm(2000)
+	
+	// Brisbane hosts both games against Melbourne, a ~1,370 km trip for the Storm
+	brisbane, melbourne := 1, 2
+	draw := &models.Draw{
+		ID:     1,
+		Rounds: 2,
+		Matches: []*models.Match{
+			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &brisbane, AwayTeamID: &melbourne, VenueID: &[]int{10}[0]},
+			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &brisbane, AwayTeamID: &melbourne, VenueID: &[]int{10}[0]},
+		},
+	}
+	
+	// Without coordinates there's nothing to penalize
+	if score := constraint.Score(draw); score != 1.0 {
+		t.Errorf("Expected perfect score without coordinates, got %.3f", score)
+	}
+	
+	constraint.SetLeagueData(NewLeagueData(
+		[]*models.Team{
+			{ID: brisbane, VenueID: &[]int{10}[0]},
+			{ID: melbourne, Latitude: -37.8136, Longitude: 144.9631},
+		},
+		[]*models.Venue{{ID: 10, Latitude: -27.4648, Longitude: 153.0095}},
+	))
+	
+	analysis := constraint.AnalyzeTeamTravel(draw, melbourne)
+	if analysis.TravelKm < 5300 || analysis.TravelKm > 5700 {
+		t.Errorf("Expected about 5,500 km of return trips, got %.0f", analysis.TravelKm)
+	}
+	if analysis.LongestTripKm*2 != analysis.TravelKm {
+		t.Errorf("Expected two equal trips, got longest %.0f of %.0f", analysis.LongestTripKm, analysis.TravelKm)
+	}
+	if !analysis.ExceedsTravelLimit {
+		t.Error("Should flag travel beyond the limit")
+	}
+	if home := constraint.AnalyzeTeamTravel(draw, brisbane); home.TravelKm != 0 {
+		t.Errorf("Expected no travel for home team, got %.0f", home.TravelKm)
+	}
+	
+	scores := constraint.TeamScores(draw)
+	if scores[brisbane] != 1.0 {
+		t.Errorf("Expected perfect score for home team, got %.3f", scores[brisbane])
+	}
+	expected := 2000 / analysis.TravelKm
+	if diff := scores[melbourne] - expected; diff > 0.001 || diff < -0.001 {
+		t.Errorf("Expected score %.3f for travelling team, got %.3f", expected, scores[melbourne])
+	}
+	
+	stats := constraint.GetDrawTravelStatistics(draw)
+	if stats.TotalTeams != 2 || stats.TeamsOverTravelLimit != 1 {
+		t.Errorf("Expected 2 teams with 1 over the limit, got %d and %d", stats.TotalTeams, stats.TeamsOverTravelLimit)
+	}
+	if stats.TotalTravelKm != analysis.TravelKm || stats.MinTeamTravelKm != 0 || stats.MaxTeamTravelKm != analysis.TravelKm {
+		t.Errorf("Expected draw totals to match the Storm's travel, got %+v", stats)
+	}
+	if stats.AverageTeamTravelKm != analysis.TravelKm/2 {
+		t.Errorf("Expected average of %.0f km, got %.0f", analysis.TravelKm/2, stats.AverageTeamTravelKm)
+	}
+}
+
 // TestRestPeriodConstraint tests rest period constraint
 func TestRestPeriodConstraint(t *testing.T) {
 	constraint := NewRestPeriodConstraint(3)
@@ -249,6 +599,142 @@ func TestRestPeriodConstraint(t *testing.T) {
 	}
 }
 
+// TestPrimeTimeSpreadWithByes tests that targets follow the prime-time share
+// of the rounds each team plays
+func TestPrimeTimeSpreadWithByes(t *testing.T) {
+	team := func(id int) *int { return &id }
+	draw := &models.Draw{
+		ID:     1,
+		Rounds: 2,
+		Matches: []*models.Match{
+			// A full round with one of three matches in prime time
+			{ID: 1, Round: 1, HomeTeamID: team(1), AwayTeamID: team(2), IsPrimeTime: true},
+			{ID: 2, Round: 1, HomeTeamID: team(3), AwayTeamID: team(4)},
+			{ID: 3, Round: 1, HomeTeamID: team(5), AwayTeamID: team(6)},
+			// Teams 1 and 2 have the bye, leaving one of two in prime time
+			{ID: 4, Round: 2, HomeTeamID: team(3), AwayTeamID: team(4), IsPrimeTime: true},
+			{ID: 5, Round: 2, HomeTeamID: team(5), AwayTeamID: team(6)},
+		},
+	}
+	
+	constraint := NewPrimeTimeSpreadConstraint(0.3, 0.1)
+	byeTeam := constraint.AnalyzeTeamPrimeTimeDistribution(draw, 1)
+	fullTeam := constraint.AnalyzeTeamPrimeTimeDistribution(draw, 3)
+	if byeTeam.TargetRatio >= 0.3 || fullTeam.TargetRatio <= 0.3 {
+		t.Errorf("Expected the bye team's target below 0.3 and the other above, got %.3f and %.3f",
+			byeTeam.TargetRatio, fullTeam.TargetRatio)
+	}
+	
+	rounds := constraint.GetRoundPrimeTimeDistribution(draw)
+	if rounds[2].Byes != 2 || rounds[2].TotalMatches != 2 {
+		t.Errorf("Expected round 2 to have 2 byes and 2 matches, got %+v", rounds[2])
+	}
+	
+	// Without byes every team keeps the configured target
+	draw.Matches = append(draw.Matches, &models.Match{ID: 6, Round: 2, HomeTeamID: team(1), AwayTeamID: team(2)})
+	draw.Matches[4].IsPrimeTime = false
+	if target := constraint.AnalyzeTeamPrimeTimeDistribution(draw, 1).TargetRatio; math.Abs(target-0.3) > 1e-9 {
+		t.Errorf("Expected a target of 0.3 without byes, got %.3f", target)
+	}
+}
+
+// TestRestPeriodHardMinimumAndTurnaroundCap tests the hard rest period and
+// short turnaround cap
+func TestRestPeriodHardMinimumAndTurnaroundCap(t *testing.T) {
+	// Team 1 plays every match: 7, 5, 4 and 5 days apart
+	draw := createDrawWithTurnarounds(0, 7, 12, 16, 21)
+	
+	constraint := NewHardRestPeriodConstraint(4)
+	engine := NewConstraintEngine()
+	engine.AddHardConstraint(constraint)
+	if errors := engine.ValidateDraw(draw); len(errors) != 1 {
+		t.Fatalf("Expected only the 4 day turnaround to breach the minimum, got %v", errors)
+	}
+	if err := constraint.Validate(draw.Matches[3], draw); err == nil {
+		t.Error("Should reject 3 rest days before round 4")
+	}
+	
+	constraint = NewHardRestPeriodConstraint(3)
+	constraint.SetMaxShortTurnarounds(2, DefaultShortTurnaroundDays)
+	analysis := constraint.AnalyzeTeamRestPeriods(draw, 1)
+	if analysis.ShortTurnarounds != 3 {
+		t.Errorf("Expected 3 short turnarounds, got %d", analysis.ShortTurnarounds)
+	}
+	for i, match := range draw.Matches {
+		err := constraint.Validate(match, draw)
+		// Only the third short turnaround, into round 5, is over the cap
+		if (err != nil) != (i == 4) {
+			t.Errorf("Round %d: unexpected validation result %v", match.Round, err)
+		}
+	}
+	
+	// As a soft constraint the turnarounds over the cap lower the score
+	soft := NewRestPeriodConstraint(3)
+	uncapped := soft.ScoreTeam(draw, 1)
+	soft.SetMaxShortTurnarounds(2, DefaultShortTurnaroundDays)
+	if soft.Validate(draw.Matches[4], draw) != nil {
+		t.Error("Soft rest periods should never fail validation")
+	}
+	if capped := soft.ScoreTeam(draw, 1); capped >= uncapped {
+		t.Errorf("Expected the cap to lower the score from %.2f, got %.2f", uncapped, capped)
+	}
+}
+
+func TestShortTurnaroundCapConstraint(t *testing.T) {
+	// Team 1 plays every match: 7, 5, 4 and 5 days apart
+	draw := createDrawWithTurnarounds(0, 7, 12, 16, 21)
+	
+	constraint := NewShortTurnaroundCapConstraint(DefaultMaxShortTurnarounds)
+	engine := NewConstraintEngine()
+	engine.AddHardConstraint(constraint)
+	for i, match := range draw.Matches {
+		err := constraint.Validate(match, draw)
+		// Only the third short turnaround, into round 5, is over the cap
+		if (err != nil) != (i == 4) {
+			t.Errorf("Round %d: unexpected validation result %v", match.Round, err)
+		}
+	}
+	
+	// Round 2 is moved to Perth, so the turnaround into round 3 follows a long-haul trip
+	melbourne, perth := 10, 20
+	draw.Matches[1].VenueID = &perth
+	engine.SetLeagueData(NewLeagueData(
+		[]*models.Team{{ID: 1, VenueID: &melbourne}},
+		[]*models.Venue{
+			{ID: melbourne, Latitude: -37.8251, Longitude: 144.9839},
+			{ID: perth, Latitude: -31.9510, Longitude: 115.8890},
+		},
+	))
+	errors := engine.ValidateDraw(draw)
+	if len(errors) != 2 {
+		t.Fatalf("Expected the turnarounds into rounds 3 and 5 rejected, got %v", errors)
+	}
+	if err := constraint.Validate(draw.Matches[2], draw); err == nil || !strings.Contains(err.Error(), "after a") {
+		t.Errorf("Expected the turnaround after the Perth trip rejected, got %v", err)
+	}
+	if score := constraint.Score(draw); math.Abs(score-5.0/6.0) > 1e-9 {
+		t.Errorf("Expected every team but team 1 within the rules, got %.3f", score)
+	}
+	
+	// Undated matches aren't checked until they're given a kickoff
+	draw.Matches[2].MatchDate = nil
+	if err := constraint.Validate(draw.Matches[2], draw); err != nil {
+		t.Errorf("Expected an undated match to pass, got %v", err)
+	}
+	
+	_, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Hard: []HardConstraintConfig{
+		{Type: "max_short_turnarounds", Params: map[string]interface{}{"max_turnarounds": float64(1), "long_haul_km": float64(2000)}},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if _, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Hard: []HardConstraintConfig{
+		{Type: "max_short_turnarounds", Params: map[string]interface{}{"short_turnaround_days": float64(0)}},
+	}}); err == nil {
+		t.Error("Expected an error for a zero-day short turnaround")
+	}
+}
+
 // TestPrimeTimeSpreadConstraint tests prime time spread constraint
 func TestPrimeTimeSpreadConstraint(t *testing.T) {
 	constraint := NewPrimeTimeSpreadConstraint(0.3, 0.1)
@@ -320,66 +806,1334 @@ func TestHomeAwayBalanceConstraint(t *testing.T) {
 	}
 }
 
-// Helper functions for creating test draws with specific patterns
-
-func createTestDrawWithViolations() *models.Draw {
-	// Create a draw where teams play each other too close together
-	draw := &models.Draw{
-		ID:         1,
-		Name:       "Test Draw with Violations",
-		SeasonYear: 2025,
-		Rounds:     4,
-		Status:     models.DrawStatusDraft,
-		Matches: []*models.Match{
-			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0]},
-			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0]}, // Same teams too soon
-		},
+// TestTeamWeights tests weighting particular teams in per-team soft scores
+func TestTeamWeights(t *testing.T) {
+	draw := createDrawWithUnbalancedHomeAway()
+	
+	config := ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "home_away_balance", Weight: 1.0, Params: map[string]interface{}{
+			"max_deviation": 0.1,
+			"team_weights":  map[string]interface{}{"1": 3.0},
+		}},
+		{Type: "travel_minimization", Weight: 0.5, Params: map[string]interface{}{
+			"max_consecutive_away": float64(1),
+			"team_weights":         map[string]interface{}{"2": 2.0},
+		}},
+	}}
+	engine, err := NewConstraintFactory().CreateConstraintEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
 	}
-	return draw
-}
-
-func createDrawWithConsecutiveAwayGames() *models.Draw {
-	// Team 1 plays 4 consecutive away games
-	draw := &models.Draw{
-		ID:         1,
-		Name:       "Draw with Consecutive Away",
-		SeasonYear: 2025,
-		Rounds:     4,
-		Status:     models.DrawStatusDraft,
-		Matches: []*models.Match{
-			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &[]int{2}[0], AwayTeamID: &[]int{1}[0]}, // Away
-			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &[]int{3}[0], AwayTeamID: &[]int{1}[0]}, // Away
-			{ID: 3, DrawID: 1, Round: 3, HomeTeamID: &[]int{4}[0], AwayTeamID: &[]int{1}[0]}, // Away
-			{ID: 4, DrawID: 1, Round: 4, HomeTeamID: &[]int{5}[0], AwayTeamID: &[]int{1}[0]}, // Away
-		},
+	balance := engine.GetSoftConstraints()[0].Constraint.(*HomeAwayBalanceConstraint)
+	if balance.GetTeamWeights().Weight(1) != 3.0 || balance.GetTeamWeights().Weight(2) != 1.0 {
+		t.Fatalf("Expected team 1 weighted 3.0 and others 1.0, got %v", balance.GetTeamWeights())
+	}
+	
+	// Team 1 plays every game at home, so weighting it lowers the score by
+	// exactly the weighted mean of the team scores
+	teamScores := balance.TeamScores(draw)
+	unweighted := NewHomeAwayBalanceConstraint(0.1).Score(draw)
+	weighted := balance.Score(draw)
+	expected := (3*teamScores[1] + teamScores[2] + teamScores[3] + teamScores[4]) / 6
+	if diff := weighted - expected; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected the weighted mean %f, got %f", expected, weighted)
+	}
+	if weighted >= unweighted {
+		t.Errorf("Expected weighting the unbalanced team to lower the score below %f, got %f", unweighted, weighted)
+	}
+	
+	// Incremental scoring applies the same weights
+	cache := engine.NewScoreCache(draw)
+	if diff := cache.Score() - engine.ScoreDraw(draw); diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected the cached score %f to match the full score %f", cache.Score(), engine.ScoreDraw(draw))
+	}
+	
+	// Weights must be positive and keyed by team ID
+	for _, weights := range []interface{}{
+		map[string]interface{}{"1": 0.0},
+		map[string]interface{}{"warriors": 2.0},
+		[]interface{}{2.0},
+	} {
+		_, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Soft: []SoftConstraintConfig{
+			{Type: "prime_time_spread", Weight: 1.0, Params: map[string]interface{}{
+				"target_ratio": 0.3, "max_deviation": 0.1, "team_weights": weights,
+			}},
+		}})
+		if err == nil {
+			t.Errorf("Expected team_weights %v to be rejected", weights)
+		}
 	}
-	return draw
 }
 
-func createDrawWithShortRestPeriods() *models.Draw {
-	// Matches with very short rest periods
-	date1 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
-	date2 := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC) // Only 1 day rest
+// TestPrimeTimeCapConstraint tests per-team prime-time appearance caps
+func TestPrimeTimeCapConstraint(t *testing.T) {
+	constraint := NewPrimeTimeCapConstraint(1, 1)
 	
-	draw := &models.Draw{
-		ID:         1,
-		Name:       "Draw with Short Rest",
-		SeasonYear: 2025,
-		Rounds:     2,
-		Status:     models.DrawStatusDraft,
-		Matches: []*models.Match{
-			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0], MatchDate: &date1},
-			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{3}[0], MatchDate: &date2},
-		},
+	// Test constraint properties
+	if constraint.Name() != "PrimeTimeCap" {
+		t.Error("Wrong constraint name")
+	}
+	if !constraint.IsHard() {
+		t.Error("Prime-time cap constraint should be hard")
+	}
+	
+	// Team 1 has two prime-time games, team 2 has none
+	draw := createDrawWithUnevenPrimeTime()
+	
+	// The first prime-time appearance is within the cap, the second is not
+	if err := constraint.Validate(draw.Matches[0], draw); err != nil {
+		t.Errorf("First prime-time appearance should be allowed: %v", err)
+	}
+	if err := constraint.Validate(draw.Matches[1], draw); err == nil {
+		t.Error("Second prime-time appearance should exceed the maximum")
+	}
+	
+	// Teams below the minimum are reported at the draw level
+	drawErrors := constraint.ValidateDraw(draw)
+	if len(drawErrors) != 1 {
+		t.Errorf("Expected 1 draw-level violation for team 2, got %d", len(drawErrors))
+	}
+	
+	outside := constraint.GetTeamsOutsideCaps(draw)
+	if len(outside) != 2 {
+		t.Fatalf("Expected 2 teams outside caps, got %d", len(outside))
+	}
+	if outside[0].TeamID != 1 || outside[0].Status != "OVER" {
+		t.Errorf("Team 1 should be over the cap, got %+v", outside[0])
+	}
+	if outside[1].TeamID != 2 || outside[1].Status != "UNDER" {
+		t.Errorf("Team 2 should be under the cap, got %+v", outside[1])
+	}
+	
+	// Draw-level violations make the engine reject the draw
+	engine := NewConstraintEngine()
+	engine.AddHardConstraint(constraint)
+	if score := engine.ScoreDraw(draw); score != 0.0 {
+		t.Errorf("Draw breaking prime-time caps should score 0, got %f", score)
+	}
+	
+	// An unbounded maximum only enforces the minimum
+	minOnly := NewPrimeTimeCapConstraint(0, NoPrimeTimeCap)
+	if score := minOnly.Score(draw); score != 1.0 {
+		t.Errorf("Expected perfect score with no caps breached, got %f", score)
 	}
-	return draw
 }
 
-func createDrawWithUnevenPrimeTime() *models.Draw {
-	// Team 1 gets all prime time games, team 2 gets none
-	draw := &models.Draw{
-		ID:         1,
-		Name:       "Draw with Uneven Prime Time",
+// TestBroadcasterQuotaConstraint tests per-team appearance quotas in a timeslot category
+func TestBroadcasterQuotaConstraint(t *testing.T) {
+	from := time.Date(0, 1, 1, 19, 0, 0, 0, time.UTC)
+	category := TimeslotCategory{Broadcaster: "Nine", Days: []time.Weekday{time.Thursday}, From: &from}
+	constraint := NewBroadcasterQuotaConstraint(category, 1, 1, true)
+	
+	if constraint.Name() != "BroadcasterQuota" || !constraint.IsHard() {
+		t.Error("Expected a hard BroadcasterQuota constraint")
+	}
+	
+	// Team 1 plays both Thursday nights, team 2 only plays on the weekend
+	draw := createDrawWithThursdayNights()
+	
+	if !category.Contains(draw.Matches[0]) || category.Contains(draw.Matches[2]) {
+		t.Error("Only the Thursday 8pm kickoffs should be in the category")
+	}
+	
+	// The first Thursday night appearance is within the quota, the second is not
+	if err := constraint.Validate(draw.Matches[0], draw); err != nil {
+		t.Errorf("First Thursday night appearance should be allowed: %v", err)
+	}
+	if err := constraint.Validate(draw.Matches[1], draw); err == nil {
+		t.Error("Second Thursday night appearance should exceed the maximum")
+	}
+	
+	// Team 2 falls short at the draw level
+	if drawErrors := constraint.ValidateDraw(draw); len(drawErrors) != 1 {
+		t.Errorf("Expected 1 draw-level violation for team 2, got %d", len(drawErrors))
+	}
+	
+	statuses := make(map[int]string)
+	for _, analysis := range constraint.AnalyzeTeams(draw) {
+		statuses[analysis.TeamID] = analysis.Status
+	}
+	if statuses[1] != "OVER" || statuses[2] != "UNDER" || statuses[3] != "WITHIN" {
+		t.Errorf("Unexpected quota statuses %v", statuses)
+	}
+	
+	// Undated draws aren't judged until timeslots are assigned
+	for _, match := range draw.Matches {
+		match.MatchDate = nil
+	}
+	if drawErrors := constraint.ValidateDraw(draw); len(drawErrors) != 0 {
+		t.Errorf("Undated draw should have no quota violations, got %v", drawErrors)
+	}
+	
+	// As a soft constraint teams outside the quota lower the score
+	soft := NewBroadcasterQuotaConstraint(category, 1, 1, false)
+	draw = createDrawWithThursdayNights()
+	if err := soft.Validate(draw.Matches[1], draw); err != nil {
+		t.Errorf("Soft quota should not fail validation: %v", err)
+	}
+	scores := soft.TeamScores(draw)
+	if scores[1] != 0.0 || scores[2] != 0.0 || scores[3] != 1.0 {
+		t.Errorf("Unexpected team scores %v", scores)
+	}
+	
+	engine := NewConstraintEngine()
+	engine.AddSoftConstraint(soft, 1.0)
+	reports := engine.AnalyzeBroadcasterQuotas(draw)
+	if len(reports) != 1 || reports[0].Hard || reports[0].Category != "Nine Thursday 19:00-" {
+		t.Errorf("Unexpected quota reports %+v", reports)
+	}
+}
+
+// TestRegionSpreadConstraint tests per-round bounds on each region's home matches
+func TestRegionSpreadConstraint(t *testing.T) {
+	regions := map[int]string{1: "Sydney", 2: "Sydney", 3: "Sydney", 4: "Brisbane", 5: "Brisbane"}
+	constraint := NewRegionSpreadConstraint(regions, 1, 2, true)
+	
+	if constraint.Name() != "RegionSpread" || !constraint.IsHard() {
+		t.Error("Expected a hard RegionSpread constraint")
+	}
+	if got := constraint.Regions(); len(got) != 2 || got[0] != "Brisbane" || got[1] != "Sydney" {
+		t.Errorf("Expected regions in alphabetical order, got %v", got)
+	}
+	
+	// Round 1 has three Sydney home games and none in Brisbane; round 2 is
+	// within the bounds. Team 6 has no region and is ignored.
+	draw := &models.Draw{
+		ID:     1,
+		Rounds: 2,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{4}[0]},
+			{ID: 2, Round: 1, HomeTeamID: &[]int{2}[0], AwayTeamID: &[]int{5}[0]},
+			{ID: 3, Round: 1, HomeTeamID: &[]int{3}[0], AwayTeamID: &[]int{6}[0]},
+			{ID: 4, Round: 2, HomeTeamID: &[]int{4}[0], AwayTeamID: &[]int{1}[0]},
+			{ID: 5, Round: 2, HomeTeamID: &[]int{5}[0], AwayTeamID: &[]int{2}[0]},
+			{ID: 6, Round: 2, HomeTeamID: &[]int{3}[0], AwayTeamID: &[]int{6}[0]},
+		},
+	}
+	
+	// Only the Sydney home game beyond the maximum is flagged
+	if err := constraint.Validate(draw.Matches[1], draw); err != nil {
+		t.Errorf("Second Sydney home game should be allowed: %v", err)
+	}
+	if err := constraint.Validate(draw.Matches[2], draw); err == nil {
+		t.Error("Third Sydney home game should exceed the maximum")
+	}
+	
+	// Brisbane falls short in round 1 at the draw level
+	drawErrors := constraint.ValidateDraw(draw)
+	if len(drawErrors) != 1 {
+		t.Fatalf("Expected 1 draw-level violation for Brisbane, got %v", drawErrors)
+	}
+	if violation, ok := drawErrors[0].(*DrawViolation); !ok || len(violation.Rounds) != 1 || violation.Rounds[0] != 1 {
+		t.Errorf("Expected the violation to point at round 1, got %+v", drawErrors[0])
+	}
+	
+	analyses := constraint.AnalyzeRounds(draw)
+	if len(analyses) != 2 {
+		t.Fatalf("Expected an analysis for each round, got %+v", analyses)
+	}
+	brisbane, sydney := analyses[0].Regions[0], analyses[0].Regions[1]
+	if brisbane.Status != "UNDER" || sydney.Status != "OVER" || sydney.HomeMatches != 3 || len(sydney.MatchIDs) != 3 {
+		t.Errorf("Unexpected round 1 analysis %+v", analyses[0])
+	}
+	for _, region := range analyses[1].Regions {
+		if region.Status != "WITHIN" {
+			t.Errorf("Round 2 should be within the bounds, got %+v", region)
+		}
+	}
+	
+	// As a soft constraint Sydney is one over (0.5) and Brisbane one under
+	// (0.0) in round 1, with round 2 perfect
+	soft := NewRegionSpreadConstraint(regions, 1, 2, false)
+	if err := soft.Validate(draw.Matches[2], draw); err != nil {
+		t.Errorf("Soft spread should not fail validation: %v", err)
+	}
+	if score := soft.Score(draw); math.Abs(score-0.625) > 1e-9 {
+		t.Errorf("Expected score 0.625, got %f", score)
+	}
+	
+	engine := NewConstraintEngine()
+	engine.AddSoftConstraint(soft, 1.0)
+	reports := engine.AnalyzeRegionSpread(draw)
+	if len(reports) != 1 || reports[0].Hard || reports[0].MaxHomeMatches != 2 || len(reports[0].Rounds) != 2 {
+		t.Errorf("Unexpected region spread reports %+v", reports)
+	}
+	
+	// The factory rejects a minimum above the maximum
+	factory := NewConstraintFactory()
+	_, err := factory.createHardConstraint(HardConstraintConfig{Type: "region_spread", Params: map[string]interface{}{
+		"team_regions":     map[string]interface{}{"1": "Sydney"},
+		"min_home_matches": 3.0,
+		"max_home_matches": 2.0,
+	}})
+	if err == nil {
+		t.Error("Expected an error for min_home_matches above max_home_matches")
+	}
+}
+
+// TestVenueRecoveryConstraint tests minimum recovery days between venue events
+func TestVenueRecoveryConstraint(t *testing.T) {
+	externalEvent := VenueEvent{
+		VenueID: 2,
+		Date:    time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC),
+		Name:    "Concert",
+	}
+	constraint := NewVenueRecoveryConstraint(3, map[int]int{3: 1}, []VenueEvent{externalEvent})
+	
+	// Test constraint properties
+	if constraint.Name() != "VenueRecovery" {
+		t.Error("Wrong constraint name")
+	}
+	if !constraint.IsHard() {
+		t.Error("Venue recovery constraint should be hard")
+	}
+	if constraint.GetRecoveryDays(3) != 1 || constraint.GetRecoveryDays(1) != 3 {
+		t.Error("Venue override should replace the default recovery period")
+	}
+	
+	day1 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	day8 := time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)
+	
+	draw := &models.Draw{
+		ID:      1,
+		Rounds:  2,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0], VenueID: &[]int{1}[0], MatchDate: &day1},
+			{ID: 2, Round: 2, HomeTeamID: &[]int{3}[0], AwayTeamID: &[]int{4}[0], VenueID: &[]int{1}[0], MatchDate: &day2}, // Consecutive days
+			{ID: 3, Round: 1, HomeTeamID: &[]int{5}[0], AwayTeamID: &[]int{6}[0], VenueID: &[]int{2}[0], MatchDate: &day8}, // Day before concert
+			{ID: 4, Round: 1, HomeTeamID: &[]int{7}[0], AwayTeamID: &[]int{8}[0], VenueID: &[]int{3}[0], MatchDate: &day1},
+			{ID: 5, Round: 2, HomeTeamID: &[]int{8}[0], AwayTeamID: &[]int{7}[0], VenueID: &[]int{3}[0], MatchDate: &day2}, // Override allows 1 day
+		},
+	}
+	
+	if err := constraint.Validate(draw.Matches[1], draw); err == nil {
+		t.Error("Matches on consecutive days at the same venue should be rejected")
+	}
+	if err := constraint.Validate(draw.Matches[2], draw); err == nil {
+		t.Error("Match the day before an external event should be rejected")
+	}
+	if err := constraint.Validate(draw.Matches[4], draw); err != nil {
+		t.Errorf("Venue override should allow a 1 day gap: %v", err)
+	}
+	
+	conflicts := constraint.GetRecoveryConflicts(draw)
+	if len(conflicts) != 3 {
+		t.Errorf("Expected 3 recovery conflicts, got %d", len(conflicts))
+	}
+	
+	if score := constraint.Score(draw); score != 0.4 {
+		t.Errorf("Expected score 0.4, got %f", score)
+	}
+}
+
+// TestMagicRoundConstraint tests a round stacked at one venue over a weekend
+func TestMagicRoundConstraint(t *testing.T) {
+	constraint := NewMagicRoundConstraint(2, 9, 0)
+	
+	if constraint.Name() != "MagicRound" {
+		t.Error("Wrong constraint name")
+	}
+	if !constraint.IsHard() {
+		t.Error("Magic round constraint should be hard")
+	}
+	if constraint.GetWeekendDays() != DefaultMagicRoundDays {
+		t.Errorf("Expected the default %d day weekend, got %d", DefaultMagicRoundDays, constraint.GetWeekendDays())
+	}
+	
+	friday := time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)
+	threePM := time.Date(0, 1, 1, 15, 0, 0, 0, time.UTC)
+	fourPM := time.Date(0, 1, 1, 16, 0, 0, 0, time.UTC)
+	sixPM := time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC)
+	
+	draw := &models.Draw{
+		ID:     1,
+		Rounds: 2,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0], VenueID: &[]int{1}[0]},
+			{ID: 2, Round: 2, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{3}[0], VenueID: &[]int{9}[0], MatchDate: &friday, MatchTime: &sixPM},
+			{ID: 3, Round: 2, HomeTeamID: &[]int{2}[0], AwayTeamID: &[]int{4}[0], VenueID: &[]int{9}[0], MatchDate: &saturday, MatchTime: &threePM},
+			{ID: 4, Round: 2, HomeTeamID: &[]int{5}[0], AwayTeamID: &[]int{6}[0], VenueID: &[]int{9}[0], MatchDate: &saturday, MatchTime: &fourPM}, // Kicks off an hour after match 3
+			{ID: 5, Round: 2, HomeTeamID: &[]int{7}[0], AwayTeamID: &[]int{8}[0], VenueID: &[]int{5}[0]},                                            // Home venue
+			{ID: 6, Round: 2, HomeTeamID: &[]int{9}[0], AwayTeamID: &[]int{10}[0], VenueID: &[]int{9}[0], MatchDate: &monday},                       // After the weekend
+		},
+	}
+	
+	if err := constraint.Validate(draw.Matches[0], draw); err != nil {
+		t.Errorf("Matches outside the magic round should be ignored: %v", err)
+	}
+	if err := constraint.Validate(draw.Matches[1], draw); err != nil {
+		t.Errorf("Friday match at the magic round venue should be valid: %v", err)
+	}
+	for _, match := range draw.Matches[2:] {
+		if err := constraint.Validate(match, draw); err == nil {
+			t.Errorf("Match %d should violate the magic round", match.ID)
+		}
+	}
+	
+	if score := constraint.Score(draw); score != 0.2 {
+		t.Errorf("Expected score 0.2, got %f", score)
+	}
+	
+	// Stacked matches at the magic round venue don't need turf recovery
+	// between them, but still do against the rest of the season
+	engine := NewConstraintEngine()
+	recovery := NewVenueRecoveryConstraint(3, nil, nil)
+	engine.AddHardConstraint(recovery)
+	engine.AddHardConstraint(constraint)
+	
+	if rounds := engine.MagicRounds(); rounds[2] != 9 {
+		t.Errorf("Expected round 2 at venue 9, got %v", rounds)
+	}
+	if err := recovery.Validate(draw.Matches[2], draw); err != nil {
+		t.Errorf("Magic round matches shouldn't need recovery from each other: %v", err)
+	}
+	
+	thursday := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	draw.Matches[0].VenueID = &[]int{9}[0]
+	draw.Matches[0].MatchDate = &thursday
+	if err := recovery.Validate(draw.Matches[1], draw); err == nil {
+		t.Error("Magic round matches should still need recovery from other rounds at the venue")
+	}
+}
+
+func TestCustomExpressionConstraint(t *testing.T) {
+	constraint, err := NewCustomExpressionConstraint("team(1).consecutive_away <= 2 AND round != 5", "", true)
+	if err != nil {
+		t.Fatalf("NewCustomExpressionConstraint() error = %v", err)
+	}
+	if constraint.Name() != "CustomExpression" || !constraint.IsHard() {
+		t.Error("Expected a hard constraint named CustomExpression")
+	}
+	
+	// Team 1 is away in rounds 1 to 4
+	draw := createDrawWithConsecutiveAwayGames()
+	for i, match := range draw.Matches {
+		err := constraint.Validate(match, draw)
+		if wantErr := i >= 2; (err != nil) != wantErr {
+			t.Errorf("Round %d: error = %v, wantErr %v", match.Round, err, wantErr)
+		}
+	}
+	if score := constraint.Score(draw); score != 0.5 {
+		t.Errorf("Expected score 0.5, got %f", score)
+	}
+	
+	friday := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	sevenFifty := time.Date(0, 1, 1, 19, 50, 0, 0, time.UTC)
+	match := &models.Match{ID: 9, Round: 3, HomeTeamID: &[]int{4}[0], AwayTeamID: &[]int{6}[0], VenueID: &[]int{2}[0],
+		MatchDate: &friday, MatchTime: &sevenFifty, IsPrimeTime: true}
+	
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{`weekday == "friday" AND hour >= 19 && prime_time`, true},
+		{`NOT team(6).plays OR team(6).is_away`, true},
+		{`team(4).is_home AND !team(4).is_away`, true},
+		{`home + away == 10 and venue == 2`, true},
+		{`team(home).home_games == 1 OR scheduled == false`, true},
+		{`round * 2 - 1 > rounds`, true},
+		{`-round < 0 AND (round == 1 OR round == 3)`, true},
+		{`team(1).consecutive_home > 0`, false},
+		{`weekday != 'friday'`, false},
+	}
+	for _, tt := range tests {
+		expression, err := NewCustomExpressionConstraint(tt.expression, "", false)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.expression, err)
+			continue
+		}
+		if got := expression.satisfies(match, newExprEnv(draw)); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.expression, got, tt.want)
+		}
+	}
+	
+	for _, invalid := range []string{
+		"round",                   // not a condition
+		"round == \"5\"",          // mismatched types
+		"weekday < \"monday\"",    // strings only compare for equality
+		"team(1).travel > 2",      // unknown field
+		"stadium == 1",            // unknown variable
+		"round == 1 AND",          // incomplete
+		"(round == 1",             // unbalanced
+		"round == 1 round == 2",   // trailing tokens
+		"team(\"a\").plays",       // non-numeric team
+		"weekday == \"friday",     // unterminated string
+		"round == 1 ; round == 2", // unknown character
+	} {
+		if _, err := NewCustomExpressionConstraint(invalid, "", true); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+// TestScheduleStabilityConstraint tests the penalty for deviating from a published draw
+func TestScheduleStabilityConstraint(t *testing.T) {
+	published := createTestDraw()
+	constraint := NewScheduleStabilityConstraint(published)
+	
+	// Test constraint properties
+	if constraint.IsHard() {
+		t.Error("Schedule stability should be a soft constraint")
+	}
+	if constraint.GetBaselineSize() != len(published.Matches) {
+		t.Error("Baseline should snapshot every published match")
+	}
+	
+	// An unchanged draw is perfectly stable
+	if score := constraint.Score(published); score != 1.0 {
+		t.Errorf("Expected perfect score for unchanged draw, got %f", score)
+	}
+	
+	// Move one match and change another's venue; the baseline must not follow the edits
+	revised := createTestDraw()
+	revised.Matches[0].Round = 4
+	revised.Matches[1].VenueID = &[]int{9}[0]
+	
+	analysis := constraint.AnalyzeChanges(revised)
+	if analysis.MovedMatches != 1 || analysis.SlotChanges != 1 || analysis.UnchangedMatches != 4 {
+		t.Errorf("Unexpected change analysis: %+v", analysis)
+	}
+	
+	expected := 1.0 - 1.5/6.0
+	if score := constraint.Score(revised); score < expected-0.0001 || score > expected+0.0001 {
+		t.Errorf("Expected score %f, got %f", expected, score)
+	}
+}
+
+// TestComputeRivalryWeights tests boosts from close finishes and ladder proximity
+func TestComputeRivalryWeights(t *testing.T) {
+	results := []HistoricalResult{
+		{Season: 2024, HomeTeamID: 1, AwayTeamID: 2, HomeScore: 18, AwayScore: 16}, // Close, latest season
+		{Season: 2023, HomeTeamID: 2, AwayTeamID: 1, HomeScore: 12, AwayScore: 10}, // Close, one season ago
+		{Season: 2024, HomeTeamID: 3, AwayTeamID: 4, HomeScore: 40, AwayScore: 0},  // Blowout
+	}
+	options := RivalryWeightOptions{CloseMargin: 6, CloseFinishBoost: 0.2, LadderBoost: 0}
+	
+	weights := ComputeRivalryWeights(map[string]float64{"3-4": 1.5}, results, nil, options)
+	
+	expected := DefaultMatchupWeight + 0.2 + 0.1
+	if weight := weights[MatchupKey(2, 1)]; weight < expected-0.0001 || weight > expected+0.0001 {
+		t.Errorf("Expected close finishes to boost weight to %f, got %f", expected, weight)
+	}
+	if weights["3-4"] != 1.5 {
+		t.Errorf("Blowouts should not change the base weight, got %f", weights["3-4"])
+	}
+	
+	// Teams next to each other on the ladder get the full boost
+	ladder := map[int]int{1: 1, 2: 2, 3: 3}
+	weights = ComputeRivalryWeights(nil, nil, ladder, RivalryWeightOptions{LadderBoost: 0.3})
+	if weights["1-2"] <= weights["1-3"] {
+		t.Errorf("Adjacent ladder teams should weigh more: 1-2=%f 1-3=%f", weights["1-2"], weights["1-3"])
+	}
+}
+
+// TestPrimeTimeAttractivenessConstraint tests scoring of prime-time matchup selection
+func TestPrimeTimeAttractivenessConstraint(t *testing.T) {
+	constraint := NewPrimeTimeAttractivenessConstraint(map[string]float64{"1-3": 2.0}, false)
+	
+	if constraint.IsHard() {
+		t.Error("Prime-time attractiveness should be a soft constraint")
+	}
+	
+	// Round 1 (1 v 3) is the most attractive matchup and is in prime time
+	draw := createDrawWithUnevenPrimeTime()
+	if score := constraint.Score(draw); score != 1.0 {
+		t.Errorf("Expected perfect score with best matchups in prime time, got %f", score)
+	}
+	
+	// Moving prime time away from the rivalry lowers the score
+	draw.Matches[0].IsPrimeTime = false
+	draw.Matches[2].IsPrimeTime = true
+	if score := constraint.Score(draw); score >= 1.0 {
+		t.Errorf("Expected lower score when rivalry is not in prime time, got %f", score)
+	}
+	
+	ranked := constraint.RankMatchesByAttractiveness(draw)
+	if len(ranked) != 4 || ranked[0].MatchID != 1 {
+		t.Errorf("Rivalry match should rank first, got %+v", ranked)
+	}
+}
+
+
+// TestExpectedCrowdConstraint tests crowd estimates against venue capacity and prime time
+func TestExpectedCrowdConstraint(t *testing.T) {
+	// Team 1 draws twice the crowd of anyone else
+	constraint := NewExpectedCrowdConstraint(map[int]float64{1: 2.0}, 10000, 0.5)
+	constraint.SetLeagueData(NewLeagueData(nil, []*models.Venue{
+		{ID: 1, Name: "Stadium Australia", Capacity: 80000},
+		{ID: 2, Name: "Leichhardt Oval", Capacity: 12000},
+	}))
+	
+	if constraint.IsHard() || constraint.Name() != "ExpectedCrowd" {
+		t.Error("Expected a soft ExpectedCrowd constraint")
+	}
+	
+	big, small := 1, 2
+	draw := &models.Draw{ID: 1, Rounds: 2, Matches: []*models.Match{
+		{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{3}[0], VenueID: &small, IsPrimeTime: true},
+		{ID: 2, DrawID: 1, Round: 1, HomeTeamID: &[]int{2}[0], AwayTeamID: &[]int{4}[0], VenueID: &big},
+	}}
+	
+	// 15,000 prime-time demand is capped by the small venue
+	if crowd := constraint.EstimateCrowd(draw.Matches[0]); crowd != 12000 {
+		t.Errorf("Expected crowd capped at 12000, got %f", crowd)
+	}
+	poor := constraint.Score(draw)
+	if poor >= 1.0 {
+		t.Errorf("Popular matchup at a small venue should score below 1, got %f", poor)
+	}
+	
+	// Swapping venues seats the whole crowd
+	draw.Matches[0].VenueID, draw.Matches[1].VenueID = &big, &small
+	if score := constraint.Score(draw); score != 1.0 {
+		t.Errorf("Popular prime-time matchup at the big venue should score 1, got %f", score)
+	}
+	if total := constraint.TotalAttendance(draw); total != 32500 {
+		t.Errorf("Expected 22500 + 10000 total attendance, got %f", total)
+	}
+	
+	// Giving prime time to the less popular matchup loses crowd
+	draw.Matches[0].IsPrimeTime, draw.Matches[1].IsPrimeTime = false, true
+	if score := constraint.Score(draw); score >= 1.0 || score <= poor {
+		t.Errorf("Prime time on the weaker matchup should score between %f and 1, got %f", poor, score)
+	}
+	
+	crowds := constraint.EstimateMatchCrowds(draw)
+	if len(crowds) != 2 || crowds[0].MatchID != 1 || crowds[0].Capacity != 80000 {
+		t.Errorf("Expected match 1 at the big venue to top the crowds, got %+v", crowds)
+	}
+}
+// TestHomeVenueShareConstraint tests scoring a multi-venue team against its target split
+func TestHomeVenueShareConstraint(t *testing.T) {
+	bluebet, carrington, other := 1, 2, 3
+	constraint := NewHomeVenueShareConstraint()
+	
+	// Without league data there are no multi-venue teams to score
+	draw := &models.Draw{ID: 1, Rounds: 4}
+	if score := constraint.Score(draw); score != 1.0 {
+		t.Errorf("Expected 1.0 without league data, got %f", score)
+	}
+	
+	constraint.SetLeagueData(NewLeagueData([]*models.Team{
+		{ID: 1, VenueID: &bluebet, HomeVenues: []models.HomeVenue{{VenueID: bluebet, Share: 0.75}, {VenueID: carrington, Share: 0.25}}},
+		{ID: 2, VenueID: &other},
+	}, nil))
+	
+	home := func(round, venueID int) *models.Match {
+		venue := venueID
+		return &models.Match{ID: round, DrawID: 1, Round: round, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0], VenueID: &venue}
+	}
+	
+	draw.Matches = []*models.Match{home(1, bluebet), home(2, carrington), home(3, bluebet), home(4, bluebet)}
+	if score := constraint.Score(draw); math.Abs(score-1.0) > 1e-9 {
+		t.Errorf("A 3/1 split should match the 75/25 target, got %f", score)
+	}
+	
+	// Every game at the main ground is a quarter of the games out of place
+	draw.Matches[1].VenueID = &bluebet
+	if score := constraint.Score(draw); math.Abs(score-0.75) > 1e-9 {
+		t.Errorf("Expected 0.75 with no games at the second venue, got %f", score)
+	}
+	
+	// Games at venues outside the split count against the team
+	draw.Matches[1].VenueID = &other
+	if score := constraint.Score(draw); math.Abs(score-0.75) > 1e-9 {
+		t.Errorf("Expected 0.75 with a game at another venue, got %f", score)
+	}
+	
+	scores := constraint.TeamScores(draw)
+	if _, ok := scores[2]; ok || len(scores) != 1 {
+		t.Errorf("Only multi-venue teams should be scored, got %v", scores)
+	}
+}
+
+func TestTravelFatigueConstraint(t *testing.T) {
+	constraint := NewTravelFatigueConstraint(DefaultMaxConsecutiveLongHaul)
+	
+	// The Storm fly to Auckland then to Perth before coming home
+	storm, warriors, broncos := 1, 2, 3
+	melbourne, auckland, perth := 10, 20, 30
+	draw := &models.Draw{
+		ID:         1,
+		SeasonYear: 2025,
+		Rounds:     3,
+		Matches: []*models.Match{
+			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &warriors, AwayTeamID: &storm, VenueID: &auckland},
+			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &broncos, AwayTeamID: &storm, VenueID: &perth},
+			{ID: 3, DrawID: 1, Round: 3, HomeTeamID: &storm, AwayTeamID: &warriors, VenueID: &melbourne},
+		},
+	}
+	
+	// Without coordinates there's nothing to penalize
+	if score := constraint.Score(draw); score != 1.0 {
+		t.Errorf("Expected perfect score without coordinates, got %.3f", score)
+	}
+	
+	constraint.SetLeagueData(NewLeagueData(
+		[]*models.Team{
+			{ID: storm, VenueID: &melbourne},
+			{ID: warriors, VenueID: &auckland},
+			{ID: broncos, Latitude: -27.4648, Longitude: 153.0095},
+		},
+		[]*models.Venue{
+			{ID: melbourne, Latitude: -37.8251, Longitude: 144.9839},
+			{ID: auckland, Latitude: -36.9036, Longitude: 174.7441},
+			{ID: perth, Latitude: -31.9510, Longitude: 115.8890},
+		},
+	))
+	
+	itinerary := constraint.TeamItinerary(draw, storm)
+	if itinerary.LongFlights != 2 || itinerary.Drives != 1 || itinerary.LongestLongHaulStreak != 2 || itinerary.PenalizedLegs != 1 {
+		t.Fatalf("Expected two long flights in a row then a home game, got %+v", itinerary)
+	}
+	auckLeg, perthLeg := itinerary.Legs[0], itinerary.Legs[1]
+	if auckLeg.Kind != geo.LegLongFlight || auckLeg.ZoneShiftHours != 2 || auckLeg.Penalized || auckLeg.OpponentID != warriors {
+		t.Errorf("Expected an unpenalized long flight two hours ahead, got %+v", auckLeg)
+	}
+	if perthLeg.ZoneShiftHours != -2 || !perthLeg.Penalized || perthLeg.ConsecutiveLongHaul != 2 {
+		t.Errorf("Expected the second long flight in a row penalized, got %+v", perthLeg)
+	}
+	if itinerary.Legs[2].Kind != geo.LegDrive || !itinerary.Legs[2].Home {
+		t.Errorf("Expected the home game as a drive, got %+v", itinerary.Legs[2])
+	}
+	// Both trips shift two hours, so the penalized one is half the burden
+	if itinerary.Score != 0.5 {
+		t.Errorf("Expected a score of 0.5, got %.3f", itinerary.Score)
+	}
+	
+	// The Warriors' bye breaks up their trips
+	scores := constraint.TeamScores(draw)
+	if scores[warriors] != 1.0 || scores[broncos] != 1.0 {
+		t.Errorf("Expected perfect scores for the other teams, got %v", scores)
+	}
+	
+	engine, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "travel_fatigue", Weight: 1, Params: map[string]interface{}{"max_consecutive_long_haul": float64(2)}},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if engine.TravelFatigue().GetMaxConsecutiveLongHaul() != 2 {
+		t.Error("Expected the engine's configured travel fatigue constraint")
+	}
+}
+// TestStrengthSpreadConstraint tests spreading fixtures against the top rated teams
+func TestStrengthSpreadConstraint(t *testing.T) {
+	strengths := map[int]float64{1: 20, 2: 40, 3: 38, 4: 36, 5: 34, 6: 10}
+	constraint := NewStrengthSpreadConstraint(strengths, DefaultStrengthTopTeams, DefaultStrengthWindowRounds, DefaultMaxToughInWindow)
+	
+	if fmt.Sprint(constraint.GetToughOpponents()) != "[2 3 4 5]" {
+		t.Fatalf("Expected the top four rated teams as tough opponents, got %v", constraint.GetToughOpponents())
+	}
+	
+	// Team 1 meets the whole top four in the first four rounds
+	team := func(id int) *int { return &id }
+	draw := &models.Draw{
+		Rounds: 5,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: team(1), AwayTeamID: team(2)},
+			{ID: 2, Round: 2, HomeTeamID: team(3), AwayTeamID: team(1)},
+			{ID: 3, Round: 3, HomeTeamID: team(1), AwayTeamID: team(4)},
+			{ID: 4, Round: 4, HomeTeamID: team(5), AwayTeamID: team(1)},
+			{ID: 5, Round: 5, HomeTeamID: team(1), AwayTeamID: team(6)},
+		},
+	}
+	
+	report := constraint.Report(draw)
+	analysis := report.Teams[0]
+	if analysis.TeamID != 1 || len(analysis.ToughFixtures) != 4 || analysis.ClusteredFixtures != 2 || analysis.BusiestWindow != 4 {
+		t.Fatalf("Expected the third and fourth tough fixtures clustered, got %+v", analysis)
+	}
+	if analysis.ToughFixtures[0].OpponentStrength != 40 || analysis.ToughFixtures[1].Clustered || !analysis.ToughFixtures[2].Clustered {
+		t.Errorf("Unexpected tough fixtures %+v", analysis.ToughFixtures)
+	}
+	if analysis.Score != 0.5 {
+		t.Errorf("Expected a score of 0.5, got %.3f", analysis.Score)
+	}
+	// The top teams only meet team 1, which isn't tough
+	if scores := constraint.TeamScores(draw); scores[2] != 1.0 || scores[6] != 1.0 {
+		t.Errorf("Expected perfect scores for the other teams, got %v", scores)
+	}
+	
+	// Two tough fixtures, a break, then two more stay within the limit
+	spread := &models.Draw{
+		Rounds: 6,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: team(1), AwayTeamID: team(2)},
+			{ID: 2, Round: 2, HomeTeamID: team(3), AwayTeamID: team(1)},
+			{ID: 3, Round: 3, HomeTeamID: team(1), AwayTeamID: team(6)},
+			{ID: 4, Round: 5, HomeTeamID: team(1), AwayTeamID: team(4)},
+			{ID: 5, Round: 6, HomeTeamID: team(5), AwayTeamID: team(1)},
+		},
+	}
+	if score := constraint.ScoreTeam(spread, 1); score != 1.0 {
+		t.Errorf("Expected a perfect score for spread tough fixtures, got %.3f", score)
+	}
+	
+	engine, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "strength_spread", Weight: 0.5, Params: map[string]interface{}{
+			"team_strengths": map[string]interface{}{"2": 40.0, "3": 38.0, "4": 36.0, "5": 34.0},
+			"top_teams":      2.0,
+		}},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	stats := engine.Statistics(draw)
+	if stats.StrengthSpread == nil || fmt.Sprint(stats.StrengthSpread.ToughOpponents) != "[2 3]" || stats.StrengthSpread.Teams[0].ClusteredFixtures != 0 {
+		t.Errorf("Expected only the top two as tough opponents in the statistics, got %+v", stats.StrengthSpread)
+	}
+	
+	if _, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "strength_spread", Weight: 0.5, Params: map[string]interface{}{"team_strengths": map[string]interface{}{}}},
+	}}); err == nil {
+		t.Error("Expected an error without team strengths")
+	}
+}
+
+// TestHalfSeasonTravelConstraint tests away game and travel balance between season halves
+func TestHalfSeasonTravelConstraint(t *testing.T) {
+	constraint := NewHalfSeasonTravelConstraint(0, DefaultHalfSeasonAwayTolerance, DefaultHalfSeasonDistanceTolerance)
+	
+	// The Storm host the Warriors twice, then both return games are in Auckland
+	storm, warriors := 1, 2
+	melbourne, auckland := 10, 20
+	draw := &models.Draw{
+		ID:         1,
+		SeasonYear: 2025,
+		Rounds:     4,
+		Matches: []*models.Match{
+			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &storm, AwayTeamID: &warriors, VenueID: &melbourne},
+			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &storm, AwayTeamID: &warriors, VenueID: &melbourne},
+			{ID: 3, DrawID: 1, Round: 3, HomeTeamID: &warriors, AwayTeamID: &storm, VenueID: &auckland},
+			{ID: 4, DrawID: 1, Round: 4, HomeTeamID: &warriors, AwayTeamID: &storm, VenueID: &auckland},
+		},
+	}
+	
+	// Without coordinates only away games are balanced: one over the tolerance of two
+	if score := constraint.ScoreTeam(draw, storm); score != 0.75 {
+		t.Errorf("Expected 0.75 for away games alone, got %.3f", score)
+	}
+	
+	constraint.SetLeagueData(NewLeagueData(
+		[]*models.Team{
+			{ID: storm, VenueID: &melbourne},
+			{ID: warriors, VenueID: &auckland},
+		},
+		[]*models.Venue{
+			{ID: melbourne, Latitude: -37.8251, Longitude: 144.9839},
+			{ID: auckland, Latitude: -36.9036, Longitude: 174.7441},
+		},
+	))
+	
+	report := constraint.Report(draw)
+	if report.SplitRound != 2 || report.UnbalancedTeams != 2 || len(report.Teams) != 2 {
+		t.Fatalf("Expected both teams unbalanced around round 2, got %+v", report)
+	}
+	analysis := report.Teams[0]
+	if analysis.TeamID != storm || analysis.FirstHalf.LastRound != 2 || analysis.SecondHalf.FirstRound != 3 || analysis.SecondHalf.LastRound != 4 {
+		t.Errorf("Expected the Storm's halves to be rounds 1-2 and 3-4, got %+v", analysis)
+	}
+	if analysis.FirstHalf.AwayGames != 0 || analysis.SecondHalf.AwayGames != 2 || analysis.AwayGameDifference != -2 {
+		t.Errorf("Expected both away games in the second half, got %+v", analysis)
+	}
+	if analysis.FirstHalf.TravelKm != 0 || analysis.SecondHalf.TravelKm < 5000 || analysis.DistanceImbalance != 1.0 {
+		t.Errorf("Expected all travel in the second half, got %+v", analysis)
+	}
+	// Away games are 0.5 over, travel 0.8 over its tolerance
+	if math.Abs(analysis.Score-0.35) > 1e-9 {
+		t.Errorf("Expected a score of 0.35, got %.3f", analysis.Score)
+	}
+	
+	// Splitting after round 3 puts one of the Storm's trips in each half
+	engine, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "half_season_travel", Weight: 1.0, Params: map[string]interface{}{"split_round": 3.0}},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.SetLeagueData(NewLeagueData(
+		[]*models.Team{{ID: storm, VenueID: &melbourne}, {ID: warriors, VenueID: &auckland}},
+		[]*models.Venue{{ID: melbourne, Latitude: -37.8251, Longitude: 144.9839}, {ID: auckland, Latitude: -36.9036, Longitude: 174.7441}},
+	))
+	stats := engine.Statistics(draw)
+	if stats.HalfSeasonTravel == nil || stats.HalfSeasonTravel.SplitRound != 3 || !stats.HalfSeasonTravel.Teams[0].Balanced {
+		t.Errorf("Expected the Storm balanced around round 3 in the statistics, got %+v", stats.HalfSeasonTravel)
+	}
+	
+	if _, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "half_season_travel", Weight: 1.0, Params: map[string]interface{}{"distance_tolerance": 1.5}},
+	}}); err == nil {
+		t.Error("Expected an error for a distance tolerance over 1")
+	}
+}
+
+
+func TestTravelCostConstraint(t *testing.T) {
+	storm, warriors, broncos := 1, 2, 3
+	melbourne, auckland, perth := 10, 20, 30
+	date := func(day int) *time.Time {
+		d := time.Date(2025, time.March, day, 19, 0, 0, 0, time.UTC)
+		return &d
+	}
+	
+	// The Storm fly to Auckland, then to Perth four rest days later
+	draw := &models.Draw{
+		ID:     1,
+		Rounds: 3,
+		Matches: []*models.Match{
+			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &warriors, AwayTeamID: &storm, VenueID: &auckland, MatchDate: date(7)},
+			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &broncos, AwayTeamID: &storm, VenueID: &perth, MatchDate: date(12)},
+			{ID: 3, DrawID: 1, Round: 3, HomeTeamID: &storm, AwayTeamID: &warriors, VenueID: &melbourne, MatchDate: date(22)},
+		},
+	}
+	
+	constraint := NewTravelCostConstraint(1000)
+	constraint.SetModel(TravelCostModel{ShortFlightCostPerKm: 1, LongFlightCostPerKm: 2, AccommodationPerNight: 100, StayAwayRestDays: 6})
+	
+	// Without coordinates nothing costs anything
+	if score := constraint.Score(draw); score != 1.0 {
+		t.Errorf("Expected perfect score without coordinates, got %.3f", score)
+	}
+	
+	constraint.SetLeagueData(NewLeagueData(
+		[]*models.Team{
+			{ID: storm, VenueID: &melbourne},
+			{ID: warriors, VenueID: &auckland},
+			{ID: broncos, Latitude: -27.4648, Longitude: 153.0095},
+		},
+		[]*models.Venue{
+			{ID: melbourne, Latitude: -37.8251, Longitude: 144.9839},
+			{ID: auckland, Latitude: -36.9036, Longitude: 174.7441},
+			{ID: perth, Latitude: -31.9510, Longitude: 115.8890},
+		},
+	))
+	melbourneToAuckland := geo.HaversineKm(-37.8251, 144.9839, -36.9036, 174.7441)
+	aucklandToPerth := geo.HaversineKm(-36.9036, 174.7441, -31.9510, 115.8890)
+	perthToMelbourne := geo.HaversineKm(-31.9510, 115.8890, -37.8251, 144.9839)
+	
+	// Too few rest days to go home, so they stay away and fly on to Perth
+	cost, ok := constraint.TeamCost(draw, storm)
+	if !ok {
+		t.Fatal("Expected the Storm in the draw")
+	}
+	wantTravel := 2 * (melbourneToAuckland + aucklandToPerth + perthToMelbourne)
+	if cost.AccommodationNights != 5 || cost.AccommodationCost != 500 || math.Abs(cost.TravelCost-wantTravel) > 1e-6 {
+		t.Errorf("Expected a road trip costing %.0f plus 5 nights, got %+v", wantTravel, cost)
+	}
+	if cost.DriveKm != 0 || cost.ShortFlightKm != 0 || math.Abs(cost.TotalCost-wantTravel-500) > 1e-6 {
+		t.Errorf("Expected only long flights, got %+v", cost)
+	}
+	
+	// With a week and a half between matches they go home in between
+	draw.Matches[1].MatchDate = date(17)
+	cost, _ = constraint.TeamCost(draw, storm)
+	wantTravel = 2 * 2 * (melbourneToAuckland + perthToMelbourne)
+	if cost.AccommodationNights != 0 || math.Abs(cost.TravelCost-wantTravel) > 1e-6 {
+		t.Errorf("Expected two return trips costing %.0f, got %+v", wantTravel, cost)
+	}
+	
+	report := constraint.Report(draw)
+	if len(report.Teams) != 3 || report.Budget != 1000 {
+		t.Fatalf("Expected every team in the report, got %+v", report)
+	}
+	total := 0.0
+	for _, team := range report.Teams {
+		total += team.TotalCost
+	}
+	if math.Abs(report.TotalCost-total) > 1e-6 || math.Abs(report.AverageTeamCost-total/3) > 1e-6 {
+		t.Errorf("Expected league totals of %.0f, got %+v", total, report)
+	}
+	if score := constraint.Score(draw); math.Abs(score-1000/total) > 1e-9 {
+		t.Errorf("Expected an over-budget score of %.4f, got %.4f", 1000/total, score)
+	}
+	if _, ok := constraint.TeamCost(draw, 99); ok {
+		t.Error("Expected a team outside the draw to be reported missing")
+	}
+	
+	engine, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "travel_cost", Weight: 1, Params: map[string]interface{}{"budget": float64(5000000), "accommodation_per_night": float64(12000)}},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if model := engine.TravelCost().GetModel(); model.AccommodationPerNight != 12000 || model.LongFlightCostPerKm != DefaultLongFlightCostPerKm {
+		t.Errorf("Expected the configured model with default rates, got %+v", model)
+	}
+	if _, err := NewConstraintFactory().CreateConstraintEngine(ConstraintConfig{Soft: []SoftConstraintConfig{
+		{Type: "travel_cost", Weight: 1, Params: map[string]interface{}{}},
+	}}); err == nil {
+		t.Error("Expected an error without a budget")
+	}
+}
+
+func TestDerbyConstraint(t *testing.T) {
+	// Four Sydney clubs within 50 km of each other, and Melbourne
+	league := NewLeagueData([]*models.Team{
+		{ID: 1, Latitude: -33.8150, Longitude: 151.0011},
+		{ID: 2, Latitude: -33.7507, Longitude: 150.6877},
+		{ID: 3, Latitude: -33.9105, Longitude: 151.1033},
+		{ID: 4, Latitude: -33.8830, Longitude: 151.1570},
+		{ID: 5, Latitude: -37.8136, Longitude: 144.9631},
+	}, nil)
+	
+	match := func(id, round, home, away int) *models.Match {
+		return &models.Match{ID: id, DrawID: 1, Round: round, HomeTeamID: &home, AwayTeamID: &away}
+	}
+	clustered := &models.Draw{ID: 1, Rounds: 2, Matches: []*models.Match{
+		match(1, 1, 1, 2), match(2, 1, 3, 4),
+		match(3, 2, 5, 1),
+	}}
+	spread := &models.Draw{ID: 1, Rounds: 2, Matches: []*models.Match{
+		match(1, 1, 1, 2), match(2, 1, 5, 3),
+		match(3, 2, 3, 4), match(4, 2, 5, 1),
+	}}
+	
+	constraint := NewDerbyConstraint(50, nil)
+	if score := constraint.Score(clustered); score != 1.0 {
+		t.Errorf("Expected 1.0 without league data, got %f", score)
+	}
+	
+	constraint.SetLeagueData(league)
+	pairs := constraint.GetPairs()
+	if len(pairs) != 6 {
+		t.Fatalf("Expected 6 Sydney derby pairs, got %d", len(pairs))
+	}
+	if pairs[0].DistanceKm > pairs[5].DistanceKm {
+		t.Errorf("Expected pairs closest first, got %+v", pairs)
+	}
+	
+	if score := constraint.Score(spread); score != 1.0 {
+		t.Errorf("Expected 1.0 with one derby per round, got %f", score)
+	}
+	if score := constraint.Score(clustered); score != 0.0 {
+		t.Errorf("Expected 0.0 with every derby in one round, got %f", score)
+	}
+	
+	marquee := NewDerbyConstraint(50, []int{1})
+	marquee.SetLeagueData(league)
+	if score := marquee.Score(clustered); score != 1.0 {
+		t.Errorf("Expected 1.0 with every derby in the marquee round, got %f", score)
+	}
+	if score := marquee.Score(spread); score != 0.5 {
+		t.Errorf("Expected 0.5 with half the derbies in the marquee round, got %f", score)
+	}
+	
+	analysis := marquee.AnalyzeDerbies(clustered)
+	if analysis.TotalDerbies != 2 || analysis.MarqueeDerbies != 2 {
+		t.Errorf("Expected 2 derbies, both in marquee rounds, got %d and %d", analysis.TotalDerbies, analysis.MarqueeDerbies)
+	}
+	if len(analysis.Rounds) != 2 || !analysis.Rounds[0].Marquee || len(analysis.Rounds[0].MatchIDs) != 2 || analysis.Rounds[1].Derbies != 0 {
+		t.Errorf("Expected both derbies in marquee round 1, got %+v", analysis.Rounds)
+	}
+	
+	engine := NewConstraintEngine()
+	engine.AddSoftConstraint(marquee, 1.0)
+	if analyses := engine.AnalyzeDerbies(clustered); len(analyses) != 1 {
+		t.Errorf("Expected one derby analysis from the engine, got %d", len(analyses))
+	}
+}
+
+// storedDraws serves draws for LoadOtherDraws
+type storedDraws map[int]*models.Draw
+
+func (s storedDraws) GetWithMatches(ctx context.Context, id int) (*models.Draw, error) {
+	if draw, ok := s[id]; ok {
+		return draw, nil
+	}
+	return nil, fmt.Errorf("draw %d: %w", id, storage.ErrNotFound)
+}
+
+func (s storedDraws) List(ctx context.Context, opts storage.ListOptions) ([]*models.Draw, error) {
+	draws := make([]*models.Draw, 0, len(s))
+	for _, draw := range s {
+		draws = append(draws, draw)
+	}
+	return draws, nil
+}
+
+func TestDoubleHeaderConstraint(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	match := func(id, venue int, date *time.Time) *models.Match {
+		home, away := 1, 2
+		return &models.Match{ID: id, Round: 1, HomeTeamID: &home, AwayTeamID: &away, VenueID: &venue, MatchDate: date}
+	}
+	
+	// The NRLW plays at venue 1 on the 8th and venue 2 on the 9th; its undated
+	// match can't be a curtain raiser yet
+	nrlw := &models.Draw{ID: 2, Matches: []*models.Match{
+		match(1, 1, day(8)), match(2, 2, day(9)), match(3, 3, nil),
+	}}
+	nrl := &models.Draw{ID: 1, Matches: []*models.Match{
+		match(4, 1, day(8)), match(5, 2, day(10)),
+	}}
+	
+	constraint := NewDoubleHeaderConstraint(2)
+	if score := constraint.Score(nrl); score != 1.0 {
+		t.Errorf("Expected 1.0 without the partner draw, got %f", score)
+	}
+	
+	engine := NewConstraintEngine()
+	engine.AddSoftConstraint(constraint, 1.0)
+	if err := engine.LoadOtherDraws(context.Background(), storedDraws{2: nrlw}); err != nil {
+		t.Fatalf("LoadOtherDraws() error = %v", err)
+	}
+	if score := constraint.Score(nrl); score != 0.5 {
+		t.Errorf("Expected 0.5 with one of two curtain raisers paired, got %f", score)
+	}
+	
+	nrl.Matches[1].MatchDate = day(9)
+	if score := constraint.Score(nrl); score != 1.0 {
+		t.Errorf("Expected 1.0 with every curtain raiser paired, got %f", score)
+	}
+	
+	// A deleted partner draw no longer counts
+	if err := engine.LoadOtherDraws(context.Background(), storedDraws{}); err != nil {
+		t.Fatalf("LoadOtherDraws() error = %v", err)
+	}
+	nrl.Matches[1].MatchDate = day(10)
+	if score := constraint.Score(nrl); score != 1.0 {
+		t.Errorf("Expected 1.0 once the partner draw is gone, got %f", score)
+	}
+}
+
+func TestSharedVenueConstraint(t *testing.T) {
+	nrl, nrlw := 1, 2
+	kickoff := func(hour, minute int) *time.Time {
+		at := time.Date(2025, 3, 8, hour, minute, 0, 0, time.UTC)
+		return &at
+	}
+	match := func(id, venue int, at *time.Time) *models.Match {
+		home, away := 1, 2
+		return &models.Match{ID: id, Round: 1, HomeTeamID: &home, AwayTeamID: &away, VenueID: &venue, MatchDate: at, MatchTime: at}
+	}
+	
+	// The NRL plays at venue 1 at 7:35pm and venue 2 at 3pm, and an earlier
+	// NRLW draw is completed too
+	active := storedDraws{
+		1: {ID: 1, Status: models.DrawStatusCompleted, CompetitionID: &nrl, Matches: []*models.Match{
+			match(1, 1, kickoff(19, 35)), match(2, 2, kickoff(15, 0)),
+		}},
+		2: {ID: 2, Status: models.DrawStatusCompleted, CompetitionID: &nrlw, Matches: []*models.Match{
+			match(3, 3, kickoff(13, 0)),
+		}},
+		3: {ID: 3, Status: models.DrawStatusDraft, CompetitionID: &nrl, Matches: []*models.Match{
+			match(4, 4, kickoff(16, 0)),
+		}},
+	}
+	
+	// A curtain raiser two hours before the NRL is fine; an hour after it at
+	// venue 2 isn't. Clashes with the other NRLW draw and the NRL draft don't
+	// count, and the undated match can't clash.
+	draw := &models.Draw{ID: 4, CompetitionID: &nrlw, Matches: []*models.Match{
+		match(5, 1, kickoff(17, 35)), match(6, 2, kickoff(16, 0)),
+		match(7, 3, kickoff(13, 0)), match(8, 4, kickoff(16, 0)), match(9, 1, nil),
+	}}
+	
+	constraint := NewSharedVenueConstraint(DefaultSharedVenueGap)
+	engine := NewConstraintEngine()
+	engine.AddHardConstraint(constraint)
+	if err := engine.LoadOtherDraws(context.Background(), active); err != nil {
+		t.Fatalf("LoadOtherDraws() error = %v", err)
+	}
+	
+	conflicts := constraint.GetConflicts(draw)
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d: %+v", len(conflicts), conflicts)
+	}
+	if conflicts[0].MatchID != 6 || conflicts[0].OtherDrawID != 1 || conflicts[0].OtherMatchID != 2 || conflicts[0].GapMinutes != 60 {
+		t.Errorf("Unexpected conflict %+v", conflicts[0])
+	}
+	if err := constraint.Validate(draw.Matches[1], draw); err == nil {
+		t.Error("Expected the clashing match to fail validation")
+	}
+	if err := constraint.Validate(draw.Matches[0], draw); err != nil {
+		t.Errorf("Expected the curtain raiser to pass, got %v", err)
+	}
+	if score := constraint.Score(draw); score != 0.75 {
+		t.Errorf("Expected 0.75 with one of four timed matches clashing, got %f", score)
+	}
+	
+	// Draws without a competition are checked against those with one
+	draw.CompetitionID = nil
+	if conflicts := constraint.GetConflicts(draw); len(conflicts) != 2 {
+		t.Errorf("Expected 2 conflicts for a draw without a competition, got %d", len(conflicts))
+	}
+}
+
+func TestFixtureVariationConstraint(t *testing.T) {
+	match := func(round, home, away int) *models.Match {
+		return &models.Match{Round: round, HomeTeamID: &home, AwayTeamID: &away}
+	}
+	
+	// Last season opened with 1v2 and 3v4 and closed with 1v3 and 2v4
+	previous := &models.Draw{ID: 1, Matches: []*models.Match{
+		match(1, 1, 2), match(1, 3, 4),
+		match(2, 1, 4), match(2, 2, 3),
+		match(3, 1, 3), match(3, 2, 4),
+	}}
+	
+	// This season repeats the reversed 2v1 opener and last season's closer
+	// 1v3 in round 3, and otherwise mixes up the rounds
+	draw := &models.Draw{ID: 2, Matches: []*models.Match{
+		match(1, 2, 1), match(1, 3, 4),
+		match(2, 1, 3), match(2, 2, 4),
+		match(3, 1, 4), match(3, 2, 3),
+	}}
+	
+	constraint := NewFixtureVariationConstraint(1)
+	if score := constraint.Score(draw); score != 1.0 {
+		t.Errorf("Expected 1.0 without last season's draw, got %f", score)
+	}
+	
+	engine := NewConstraintEngine()
+	engine.AddSoftConstraint(constraint, 1.0)
+	if err := engine.LoadOtherDraws(context.Background(), storedDraws{1: previous}); err != nil {
+		t.Fatalf("LoadOtherDraws() error = %v", err)
+	}
+	
+	// Both openers repeat, and neither closer repeats last season's closers
+	expected := (4.0/6 + 0.5) / 2
+	if score := constraint.Score(draw); math.Abs(score-expected) > 1e-9 {
+		t.Errorf("Expected %f with the opening round repeated, got %f", expected, score)
+	}
+	
+	// A copy of last season repeats everything
+	if score := constraint.Score(previous); score != 0.0 {
+		t.Errorf("Expected 0.0 for a copy of last season, got %f", score)
+	}
+	
+	if err := constraint.Validate(draw.Matches[0], draw); err != nil {
+		t.Errorf("Soft constraint should not fail validation, got %v", err)
+	}
+}
+
+// TestFreezeRivalryWeights tests that frozen weights ignore later result changes
+func TestFreezeRivalryWeights(t *testing.T) {
+	config := ConstraintConfig{
+		Soft: []SoftConstraintConfig{
+			{
+				Type:   "prime_time_attractiveness",
+				Weight: 0.5,
+				Params: map[string]interface{}{
+					"freeze_weights": true,
+					"ladder":         map[string]interface{}{"1": float64(1), "2": float64(2)},
+				},
+			},
+		},
+	}
+	
+	if err := FreezeRivalryWeights(&config); err != nil {
+		t.Fatalf("FreezeRivalryWeights() error = %v", err)
+	}
+	if _, ok := config.Soft[0].Params["frozen_weights"]; !ok {
+		t.Fatal("Expected frozen_weights to be stored")
+	}
+	
+	// Changing the ladder after freezing must not change the weights
+	config.Soft[0].Params["ladder"] = map[string]interface{}{"1": float64(1), "2": float64(16)}
+	
+	factory := NewConstraintFactory()
+	constraint, err := factory.createSoftConstraint(config.Soft[0])
+	if err != nil {
+		t.Fatalf("Failed to create constraint: %v", err)
+	}
+	
+	attractiveness := constraint.(*PrimeTimeAttractivenessConstraint)
+	if !attractiveness.IsFrozen() {
+		t.Error("Constraint should use frozen weights")
+	}
+	if weight := attractiveness.GetWeights()["1-2"]; weight <= DefaultMatchupWeight {
+		t.Errorf("Frozen weight should keep the original ladder boost, got %f", weight)
+	}
+}
+
+// Helper functions for creating test draws with specific patterns
+
+func createTestDrawWithViolations() *models.Draw {
+	// Create a draw where teams play each other too close together
+	draw := &models.Draw{
+		ID:         1,
+		Name:       "Test Draw with Violations",
+		SeasonYear: 2025,
+		Rounds:     4,
+		Status:     models.DrawStatusDraft,
+		Matches: []*models.Match{
+			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0]},
+			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0]}, // Same teams too soon
+		},
+	}
+	return draw
+}
+
+func createDrawWithConsecutiveAwayGames() *models.Draw {
+	// Team 1 plays 4 consecutive away games
+	draw := &models.Draw{
+		ID:         1,
+		Name:       "Draw with Consecutive Away",
+		SeasonYear: 2025,
+		Rounds:     4,
+		Status:     models.DrawStatusDraft,
+		Matches: []*models.Match{
+			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &[]int{2}[0], AwayTeamID: &[]int{1}[0]}, // Away
+			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &[]int{3}[0], AwayTeamID: &[]int{1}[0]}, // Away
+			{ID: 3, DrawID: 1, Round: 3, HomeTeamID: &[]int{4}[0], AwayTeamID: &[]int{1}[0]}, // Away
+			{ID: 4, DrawID: 1, Round: 4, HomeTeamID: &[]int{5}[0], AwayTeamID: &[]int{1}[0]}, // Away
+		},
+	}
+	return draw
+}
+
+func createDrawWithShortRestPeriods() *models.Draw {
+	// Matches with very short rest periods
+	date1 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	date2 := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC) // Only 1 day rest
+	
+	draw := &models.Draw{
+		ID:         1,
+		Name:       "Draw with Short Rest",
+		SeasonYear: 2025,
+		Rounds:     2,
+		Status:     models.DrawStatusDraft,
+		Matches: []*models.Match{
+			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{2}[0], MatchDate: &date1},
+			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{3}[0], MatchDate: &date2},
+		},
+	}
+	return draw
+}
+
+// createDrawWithTurnarounds creates a draw where team 1 plays a different
+// opponent on each of the given days after 1 June
+func createDrawWithTurnarounds(days ...int) *models.Draw {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	draw := &models.Draw{
+		ID:         1,
+		Name:       "Draw with Turnarounds",
+		SeasonYear: 2025,
+		Rounds:     len(days),
+		Status:     models.DrawStatusDraft,
+	}
+	for i, day := range days {
+		date := start.AddDate(0, 0, day)
+		draw.Matches = append(draw.Matches, &models.Match{
+			ID: i + 1, DrawID: 1, Round: i + 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{i + 2}[0], MatchDate: &date,
+		})
+	}
+	return draw
+}
+
+func createDrawWithUnevenPrimeTime() *models.Draw {
+	// Team 1 gets all prime time games, team 2 gets none
+	draw := &models.Draw{
+		ID:         1,
+		Name:       "Draw with Uneven Prime Time",
 		SeasonYear: 2025,
 		Rounds:     4,
 		Status:     models.DrawStatusDraft,
@@ -409,4 +2163,26 @@ func createDrawWithUnbalancedHomeAway() *models.Draw {
 		},
 	}
 	return draw
-}
\ No newline at end of file
+}
+
+func createDrawWithThursdayNights() *models.Draw {
+	// Team 1 plays two Thursday 8pm games, team 2 plays on Saturday afternoons
+	thursday := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)
+	night := time.Date(0, 1, 1, 20, 0, 0, 0, time.UTC)
+	afternoon := time.Date(0, 1, 1, 15, 0, 0, 0, time.UTC)
+	
+	draw := &models.Draw{
+		ID:         1,
+		Name:       "Draw with Thursday Nights",
+		SeasonYear: 2025,
+		Rounds:     3,
+		Status:     models.DrawStatusDraft,
+		Matches: []*models.Match{
+			{ID: 1, DrawID: 1, Round: 1, HomeTeamID: &[]int{1}[0], AwayTeamID: &[]int{3}[0], MatchDate: &thursday, MatchTime: &night},
+			{ID: 2, DrawID: 1, Round: 2, HomeTeamID: &[]int{4}[0], AwayTeamID: &[]int{1}[0], MatchDate: &thursday, MatchTime: &night},
+			{ID: 3, DrawID: 1, Round: 3, HomeTeamID: &[]int{2}[0], AwayTeamID: &[]int{3}[0], MatchDate: &saturday, MatchTime: &afternoon},
+		},
+	}
+	return draw
+}
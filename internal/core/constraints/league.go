@@ -0,0 +1,259 @@
+package constraints
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// LeagueData carries the team and venue details some constraints need beyond
+// the draw itself, such as coordinates for travel distances
+type LeagueData struct {
+	Teams  map[int]*models.Team
+	Venues map[int]*models.Venue
+}
+
+// LeagueAware is implemented by constraints that use team or venue details
+type LeagueAware interface {
+	SetLeagueData(data *LeagueData)
+}
+
+// TeamLister lists every team in the league
+type TeamLister interface {
+	List(ctx context.Context, opts storage.ListOptions) ([]*models.Team, error)
+}
+
+// VenueLister lists every venue in the league
+type VenueLister interface {
+	List(ctx context.Context, opts storage.ListOptions) ([]*models.Venue, error)
+}
+
+// ReferencedDrawAware is implemented by constraints scored against another
+// stored draw named in their params, such as another competition's draw or
+// last season's
+type ReferencedDrawAware interface {
+	ReferencedDrawID() int
+	SetReferencedDraw(draw *models.Draw)
+}
+
+// ActiveDrawsAware is implemented by constraints checked against the matches
+// of other draws already scheduled
+type ActiveDrawsAware interface {
+	SetActiveDraws(draws []*models.Draw)
+}
+
+// DrawReader lists draws and reads one with its matches
+type DrawReader interface {
+	GetWithMatches(ctx context.Context, id int) (*models.Draw, error)
+	List(ctx context.Context, opts storage.ListOptions) ([]*models.Draw, error)
+}
+
+// NewLeagueData indexes teams and venues by ID
+func NewLeagueData(teams []*models.Team, venues []*models.Venue) *LeagueData {
+	data := &LeagueData{
+		Teams:  make(map[int]*models.Team, len(teams)),
+		Venues: make(map[int]*models.Venue, len(venues)),
+	}
+	for _, team := range teams {
+		data.Teams[team.ID] = team
+	}
+	for _, venue := range venues {
+		data.Venues[venue.ID] = venue
+	}
+	return data
+}
+
+// LoadLeagueData reads every team and venue from storage
+func LoadLeagueData(ctx context.Context, teams TeamLister, venues VenueLister) (*LeagueData, error) {
+	teamList, err := teams.List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	venueList, err := venues.List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return NewLeagueData(teamList, venueList), nil
+}
+
+// SetLeagueData hands team and venue details to every constraint that uses
+// them, and enforces the unavailable dates stored against each team
+func (ce *ConstraintEngine) SetLeagueData(data *LeagueData) {
+	ce.setStoredAvailability(data)
+
+	for _, constraint := range ce.hardConstraints {
+		if aware, ok := constraint.(LeagueAware); ok {
+			aware.SetLeagueData(data)
+		}
+	}
+	for _, weighted := range ce.softConstraints {
+		if aware, ok := weighted.Constraint.(LeagueAware); ok {
+			aware.SetLeagueData(data)
+		}
+	}
+}
+
+// LoadOtherDraws reads the other draws constraints are checked against: the
+// draw each constraint references, such as a double header's partner or last
+// season's draw, and the completed draws a shared-venue constraint must not
+// clash with. A referenced draw that has since been deleted leaves its
+// constraint without one, so it no longer affects the score.
+func (ce *ConstraintEngine) LoadOtherDraws(ctx context.Context, draws DrawReader) error {
+	var referencing []ReferencedDrawAware
+	var shared []ActiveDrawsAware
+	collect := func(constraint Constraint) {
+		if aware, ok := constraint.(ReferencedDrawAware); ok {
+			referencing = append(referencing, aware)
+		}
+		if aware, ok := constraint.(ActiveDrawsAware); ok {
+			shared = append(shared, aware)
+		}
+	}
+	for _, constraint := range ce.hardConstraints {
+		collect(constraint)
+	}
+	for _, weighted := range ce.softConstraints {
+		collect(weighted.Constraint)
+	}
+
+	for _, aware := range referencing {
+		draw, err := draws.GetWithMatches(ctx, aware.ReferencedDrawID())
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				aware.SetReferencedDraw(nil)
+				continue
+			}
+			return fmt.Errorf("loading referenced draw %d: %w", aware.ReferencedDrawID(), err)
+		}
+		aware.SetReferencedDraw(draw)
+	}
+
+	if len(shared) == 0 {
+		return nil
+	}
+	active, err := LoadActiveDraws(ctx, draws)
+	if err != nil {
+		return err
+	}
+	for _, aware := range shared {
+		aware.SetActiveDraws(active)
+	}
+	return nil
+}
+
+// LoadActiveDraws reads every completed draw with its matches, in ID order.
+// Drafts and draws still being optimized are works in progress, so they
+// aren't booked against.
+func LoadActiveDraws(ctx context.Context, draws DrawReader) ([]*models.Draw, error) {
+	list, err := draws.List(ctx, storage.ListOptions{SortBy: "id"})
+	if err != nil {
+		return nil, fmt.Errorf("listing draws: %w", err)
+	}
+
+	var active []*models.Draw
+	for _, draw := range list {
+		if draw.Status != models.DrawStatusCompleted {
+			continue
+		}
+		withMatches, err := draws.GetWithMatches(ctx, draw.ID)
+		if err != nil {
+			return nil, fmt.Errorf("loading active draw %d: %w", draw.ID, err)
+		}
+		active = append(active, withMatches)
+	}
+	return active, nil
+}
+
+// TeamHomeLocation returns where a team is based: its own coordinates, or
+// its home venue's when the team has none recorded
+func (ld *LeagueData) TeamHomeLocation(teamID int) (lat, lon float64, ok bool) {
+	if ld == nil {
+		return 0, 0, false
+	}
+	team, exists := ld.Teams[teamID]
+	if !exists {
+		return 0, 0, false
+	}
+	if geo.HasCoordinates(team.Latitude, team.Longitude) {
+		return team.Latitude, team.Longitude, true
+	}
+	if team.VenueID != nil {
+		return ld.VenueLocation(*team.VenueID)
+	}
+	return 0, 0, false
+}
+
+// VenueLocation returns a venue's coordinates
+func (ld *LeagueData) VenueLocation(venueID int) (lat, lon float64, ok bool) {
+	if ld == nil {
+		return 0, 0, false
+	}
+	venue, exists := ld.Venues[venueID]
+	if !exists || !geo.HasCoordinates(venue.Latitude, venue.Longitude) {
+		return 0, 0, false
+	}
+	return venue.Latitude, venue.Longitude, true
+}
+
+// VenueCapacity returns a venue's capacity, reporting false when the venue is
+// unknown or has no capacity recorded
+func (ld *LeagueData) VenueCapacity(venueID int) (int, bool) {
+	if ld == nil {
+		return 0, false
+	}
+	venue, exists := ld.Venues[venueID]
+	if !exists || venue.Capacity <= 0 {
+		return 0, false
+	}
+	return venue.Capacity, true
+}
+
+// MatchLocation returns where a match is played: its venue, or the home
+// team's base when no venue has been set
+func (ld *LeagueData) MatchLocation(match *models.Match) (lat, lon float64, ok bool) {
+	if match.VenueID != nil {
+		if lat, lon, ok := ld.VenueLocation(*match.VenueID); ok {
+			return lat, lon, true
+		}
+	}
+	if match.HomeTeamID != nil {
+		return ld.TeamHomeLocation(*match.HomeTeamID)
+	}
+	return 0, 0, false
+}
+
+// UnavailableDates returns the stored unavailable dates of each team that has any
+func (ld *LeagueData) UnavailableDates() map[int][]time.Time {
+	dates := make(map[int][]time.Time)
+	if ld == nil {
+		return dates
+	}
+	for teamID, team := range ld.Teams {
+		for _, unavailability := range team.Unavailability {
+			dates[teamID] = append(dates[teamID], unavailability.Date)
+		}
+	}
+	return dates
+}
+
+// setStoredAvailability replaces the engine's stored team availability
+// constraint with one for the league's current unavailable dates
+func (ce *ConstraintEngine) setStoredAvailability(data *LeagueData) {
+	hard := make([]Constraint, 0, len(ce.hardConstraints)+1)
+	for _, constraint := range ce.hardConstraints {
+		if _, stored := constraint.(*StoredTeamAvailabilityConstraint); !stored {
+			hard = append(hard, constraint)
+		}
+	}
+	if dates := data.UnavailableDates(); len(dates) > 0 {
+		stored := NewStoredTeamAvailabilityConstraint(dates)
+		stored.SetCalendar(ce.calendar)
+		hard = append(hard, stored)
+	}
+	ce.hardConstraints = hard
+}
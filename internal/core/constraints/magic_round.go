@@ -0,0 +1,182 @@
+package constraints
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DefaultMagicRoundDays is how many consecutive days a magic round weekend
+// spans, Friday to Sunday
+const DefaultMagicRoundDays = 3
+
+// MinStackedKickoffGap is the least time between kickoffs when matches share a
+// venue on the same day
+const MinStackedKickoffGap = 2 * time.Hour
+
+// MagicRoundConstraint requires every match in a round to be played at one
+// venue over a single weekend, as in the NRL's Magic Round. The matches are
+// stacked one after another, so venue recovery doesn't apply between them.
+type MagicRoundConstraint struct {
+	BaseConstraint
+	round       int
+	venueID     int
+	weekendDays int
+}
+
+// NewMagicRoundConstraint creates a new magic round constraint. The round's
+// matches must fall within weekendDays consecutive days.
+func NewMagicRoundConstraint(round, venueID, weekendDays int) *MagicRoundConstraint {
+	if weekendDays < 1 {
+		weekendDays = DefaultMagicRoundDays
+	}
+
+	return &MagicRoundConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"MagicRound",
+			fmt.Sprintf("Every match in round %d must be played at venue %d within %d days", round, venueID, weekendDays),
+			true, // This is a hard constraint
+		),
+		round:       round,
+		venueID:     venueID,
+		weekendDays: weekendDays,
+	}
+}
+
+// Validate checks that a magic round match is at the magic round venue, on
+// the weekend, and doesn't kick off on top of another match there
+func (mrc *MagicRoundConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	if match.Round != mrc.round || match.IsBye() {
+		return nil
+	}
+
+	if match.VenueID == nil {
+		return fmt.Errorf("match %d in magic round %d has no venue, want venue %d", match.ID, mrc.round, mrc.venueID)
+	}
+	if *match.VenueID != mrc.venueID {
+		return fmt.Errorf("match %d in magic round %d is at venue %d, want venue %d",
+			match.ID, mrc.round, *match.VenueID, mrc.venueID)
+	}
+
+	if match.MatchDate == nil {
+		return nil
+	}
+
+	if first := mrc.firstDate(draw); first != nil && daysBetween(*first, *match.MatchDate) >= mrc.weekendDays {
+		return fmt.Errorf("match %d on %s is outside the %d-day magic round weekend starting %s",
+			match.ID, match.MatchDate.Format("2006-01-02"), mrc.weekendDays, first.Format("2006-01-02"))
+	}
+
+	if clash := mrc.findClash(match, draw); clash != nil {
+		return fmt.Errorf("match %d kicks off within %s of match %d at the magic round venue",
+			match.ID, MinStackedKickoffGap, clash.ID)
+	}
+
+	return nil
+}
+
+// Score returns the fraction of matches in the magic round that satisfy it
+func (mrc *MagicRoundConstraint) Score(draw *models.Draw) float64 {
+	totalMatches := 0
+	violatingMatches := 0
+
+	for _, match := range draw.Matches {
+		if match.Round != mrc.round || match.IsBye() {
+			continue
+		}
+
+		totalMatches++
+		if mrc.Validate(match, draw) != nil {
+			violatingMatches++
+		}
+	}
+
+	if totalMatches == 0 {
+		return 1.0
+	}
+
+	return float64(totalMatches-violatingMatches) / float64(totalMatches)
+}
+
+// firstDate returns the earliest dated match in the magic round
+func (mrc *MagicRoundConstraint) firstDate(draw *models.Draw) *time.Time {
+	var first *time.Time
+	for _, match := range draw.Matches {
+		if match.Round != mrc.round || match.IsBye() || match.MatchDate == nil {
+			continue
+		}
+		if first == nil || match.MatchDate.Before(*first) {
+			first = match.MatchDate
+		}
+	}
+	return first
+}
+
+// findClash returns another magic round match on the same day that kicks off
+// too close to the match
+func (mrc *MagicRoundConstraint) findClash(match *models.Match, draw *models.Draw) *models.Match {
+	if match.MatchTime == nil {
+		return nil
+	}
+
+	for _, other := range draw.Matches {
+		if other == match || other.Round != mrc.round || other.IsBye() {
+			continue
+		}
+		if other.MatchDate == nil || other.MatchTime == nil || daysBetween(*match.MatchDate, *other.MatchDate) != 0 {
+			continue
+		}
+
+		gap := kickoffClock(*match.MatchTime) - kickoffClock(*other.MatchTime)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap < MinStackedKickoffGap {
+			return other
+		}
+	}
+	return nil
+}
+
+// kickoffClock returns the time of day of a kickoff
+func kickoffClock(kickoff time.Time) time.Duration {
+	return time.Duration(kickoff.Hour())*time.Hour + time.Duration(kickoff.Minute())*time.Minute
+}
+
+// GetRound returns the magic round
+func (mrc *MagicRoundConstraint) GetRound() int {
+	return mrc.round
+}
+
+// GetVenueID returns the venue hosting the magic round
+func (mrc *MagicRoundConstraint) GetVenueID() int {
+	return mrc.venueID
+}
+
+// GetWeekendDays returns how many days the magic round may span
+func (mrc *MagicRoundConstraint) GetWeekendDays() int {
+	return mrc.weekendDays
+}
+
+// MagicRounds returns the venue of each magic round, keyed by round
+func (ce *ConstraintEngine) MagicRounds() map[int]int {
+	rounds := make(map[int]int)
+	for _, constraint := range ce.hardConstraints {
+		if magic, ok := constraint.(*MagicRoundConstraint); ok {
+			rounds[magic.round] = magic.venueID
+		}
+	}
+	return rounds
+}
+
+// shareMagicRounds tells venue recovery constraints which rounds are stacked
+// at a single venue, so the matches sharing that weekend don't clash
+func (ce *ConstraintEngine) shareMagicRounds() {
+	rounds := ce.MagicRounds()
+	for _, constraint := range ce.hardConstraints {
+		if recovery, ok := constraint.(*VenueRecoveryConstraint); ok {
+			recovery.SetMagicRounds(rounds)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package constraints
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Objective groups soft constraints into one goal a multi-objective
+// optimization trades off against the others
+type Objective string
+
+const (
+	// ObjectiveTravel covers how far, how often and how tiringly teams travel
+	ObjectiveTravel Objective = "travel"
+	// ObjectiveFairness covers how evenly home games, prime time and regions
+	// are shared between teams
+	ObjectiveFairness Objective = "fairness"
+	// ObjectiveBroadcast covers how attractive the draw is to broadcasters
+	// and crowds
+	ObjectiveBroadcast Objective = "broadcast"
+)
+
+// ErrInvalidObjectives is returned when objectives are unknown or have no
+// soft constraint in the configuration to score them
+var ErrInvalidObjectives = errors.New("invalid objectives")
+
+// objectiveTypes assigns soft constraint types to the objective they score.
+// Types not listed count towards the weighted score but no objective.
+var objectiveTypes = map[string]Objective{
+	"travel_minimization":       ObjectiveTravel,
+	"travel_fatigue":            ObjectiveTravel,
+	"travel_cost":               ObjectiveTravel,
+	"half_season_travel":        ObjectiveTravel,
+	"rest_period":               ObjectiveTravel,
+	"home_away_balance":         ObjectiveFairness,
+	"prime_time_spread":         ObjectiveFairness,
+	"region_spread":             ObjectiveFairness,
+	"home_venue_share":          ObjectiveFairness,
+	"strength_spread":           ObjectiveFairness,
+	"prime_time_attractiveness": ObjectiveBroadcast,
+	"broadcaster_quota":         ObjectiveBroadcast,
+	"expected_crowd":            ObjectiveBroadcast,
+	"derby":                     ObjectiveBroadcast,
+	"rivalry_round":             ObjectiveBroadcast,
+}
+
+// AllObjectives returns every objective, in a fixed order
+func AllObjectives() []Objective {
+	return []Objective{ObjectiveTravel, ObjectiveFairness, ObjectiveBroadcast}
+}
+
+// ObjectiveOf returns the objective a constraint scores, if any
+func ObjectiveOf(constraint Constraint) (Objective, bool) {
+	objective, ok := objectiveTypes[TypeOf(constraint)]
+	return objective, ok
+}
+
+// CheckObjectives reports an error wrapping ErrInvalidObjectives when an
+// objective is unknown, repeated or has no soft constraint to score it
+func (ce *ConstraintEngine) CheckObjectives(objectives []Objective) error {
+	scored := make(map[Objective]bool)
+	for _, weighted := range ce.softConstraints {
+		if objective, ok := ObjectiveOf(weighted.Constraint); ok {
+			scored[objective] = true
+		}
+	}
+
+	known := make(map[Objective]bool)
+	for _, objective := range AllObjectives() {
+		known[objective] = true
+	}
+
+	seen := make(map[Objective]bool)
+	for _, objective := range objectives {
+		switch {
+		case !known[objective]:
+			return fmt.Errorf("%w: unknown objective %q", ErrInvalidObjectives, objective)
+		case seen[objective]:
+			return fmt.Errorf("%w: objective %q is repeated", ErrInvalidObjectives, objective)
+		case !scored[objective]:
+			return fmt.Errorf("%w: no soft constraint scores the %s objective", ErrInvalidObjectives, objective)
+		}
+		seen[objective] = true
+	}
+	return nil
+}
+
+// ObjectiveScores scores a draw on each objective, in the order given
+func (ce *ConstraintEngine) ObjectiveScores(draw *models.Draw, objectives []Objective) []float64 {
+	index := NewDrawIndex(draw)
+	scores := make([]float64, len(ce.softConstraints))
+	for i, weighted := range ce.softConstraints {
+		scores[i] = scoreWith(weighted.Constraint, index)
+	}
+	return ce.objectiveScores(scores, objectives)
+}
+
+// objectiveScores combines per-soft-constraint scores into each objective's
+// score, the weighted average of the constraints it covers. An objective
+// nothing covers scores 1.0.
+func (ce *ConstraintEngine) objectiveScores(scores []float64, objectives []Objective) []float64 {
+	combined := make([]float64, len(objectives))
+	for i, objective := range objectives {
+		var total, weight float64
+		for j, weighted := range ce.softConstraints {
+			if covers, ok := ObjectiveOf(weighted.Constraint); !ok || covers != objective {
+				continue
+			}
+			total += scores[j] * weighted.Weight
+			weight += weighted.Weight
+		}
+
+		combined[i] = 1.0
+		if weight > 0 {
+			combined[i] = total / weight
+		}
+	}
+	return combined
+}
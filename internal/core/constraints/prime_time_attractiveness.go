@@ -0,0 +1,135 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// PrimeTimeAttractivenessConstraint rewards placing the most attractive matchups,
+// weighted by rivalry, recent close finishes and ladder proximity, in prime time
+type PrimeTimeAttractivenessConstraint struct {
+	BaseConstraint
+	weights map[string]float64 // Matchup weights keyed by MatchupKey
+	frozen  bool               // Weights were fixed when the draw was constructed
+}
+
+// NewPrimeTimeAttractivenessConstraint creates a new prime-time attractiveness constraint
+func NewPrimeTimeAttractivenessConstraint(weights map[string]float64, frozen bool) *PrimeTimeAttractivenessConstraint {
+	if weights == nil {
+		weights = make(map[string]float64)
+	}
+
+	return &PrimeTimeAttractivenessConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"PrimeTimeAttractiveness",
+			"Assign the most attractive matchups to prime-time slots",
+			false, // This is a soft constraint
+		),
+		weights: weights,
+		frozen:  frozen,
+	}
+}
+
+// Validate always returns nil for soft constraints
+func (ptac *PrimeTimeAttractivenessConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score compares the attractiveness of prime-time matches with the best possible selection
+func (ptac *PrimeTimeAttractivenessConstraint) Score(draw *models.Draw) float64 {
+	var allWeights []float64
+	primeTimeTotal := 0.0
+	primeTimeCount := 0
+
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		weight := ptac.MatchAttractiveness(match)
+		allWeights = append(allWeights, weight)
+		if match.IsPrimeTime {
+			primeTimeTotal += weight
+			primeTimeCount++
+		}
+	}
+
+	if primeTimeCount == 0 {
+		return 1.0
+	}
+
+	// Best achievable total is the sum of the most attractive matchups
+	for i := 0; i < len(allWeights)-1; i++ {
+		for j := i + 1; j < len(allWeights); j++ {
+			if allWeights[i] < allWeights[j] {
+				allWeights[i], allWeights[j] = allWeights[j], allWeights[i]
+			}
+		}
+	}
+
+	bestTotal := 0.0
+	for i := 0; i < primeTimeCount; i++ {
+		bestTotal += allWeights[i]
+	}
+	if bestTotal == 0 {
+		return 1.0
+	}
+
+	return primeTimeTotal / bestTotal
+}
+
+// MatchAttractiveness returns the attractiveness weight of a match
+func (ptac *PrimeTimeAttractivenessConstraint) MatchAttractiveness(match *models.Match) float64 {
+	if match.IsBye() || match.HomeTeamID == nil || match.AwayTeamID == nil {
+		return 0
+	}
+	if weight, ok := ptac.weights[MatchupKey(*match.HomeTeamID, *match.AwayTeamID)]; ok {
+		return weight
+	}
+	return DefaultMatchupWeight
+}
+
+// GetWeights returns the matchup weights keyed by MatchupKey
+func (ptac *PrimeTimeAttractivenessConstraint) GetWeights() map[string]float64 {
+	return ptac.weights
+}
+
+// IsFrozen returns whether the weights were fixed at draw construction time
+func (ptac *PrimeTimeAttractivenessConstraint) IsFrozen() bool {
+	return ptac.frozen
+}
+
+// RankMatchesByAttractiveness returns the draw's matches ordered from most to least attractive
+func (ptac *PrimeTimeAttractivenessConstraint) RankMatchesByAttractiveness(draw *models.Draw) []MatchAttractiveness {
+	var ranked []MatchAttractiveness
+
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		ranked = append(ranked, MatchAttractiveness{
+			MatchID:     match.ID,
+			Round:       match.Round,
+			Weight:      ptac.MatchAttractiveness(match),
+			IsPrimeTime: match.IsPrimeTime,
+		})
+	}
+
+	// Sort by weight (descending)
+	for i := 0; i < len(ranked)-1; i++ {
+		for j := i + 1; j < len(ranked); j++ {
+			if ranked[i].Weight < ranked[j].Weight {
+				ranked[i], ranked[j] = ranked[j], ranked[i]
+			}
+		}
+	}
+
+	return ranked
+}
+
+// MatchAttractiveness contains the attractiveness of a single match
+type MatchAttractiveness struct {
+	MatchID     int     `json:"match_id"`
+	Round       int     `json:"round"`
+	Weight      float64 `json:"weight"`
+	IsPrimeTime bool    `json:"is_prime_time"`
+}
@@ -0,0 +1,199 @@
+package constraints
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// NoPrimeTimeCap marks an unbounded maximum for PrimeTimeCapConstraint
+const NoPrimeTimeCap = -1
+
+// PrimeTimeCapConstraint enforces per-team minimum and maximum prime-time appearances
+type PrimeTimeCapConstraint struct {
+	BaseConstraint
+	minAppearances int // Minimum prime-time appearances per team
+	maxAppearances int // Maximum prime-time appearances per team (NoPrimeTimeCap for unbounded)
+}
+
+// NewPrimeTimeCapConstraint creates a new prime-time cap constraint
+func NewPrimeTimeCapConstraint(minAppearances, maxAppearances int) *PrimeTimeCapConstraint {
+	return &PrimeTimeCapConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"PrimeTimeCap",
+			"Each team must have between a minimum and maximum number of prime-time appearances",
+			true, // This is a hard constraint
+		),
+		minAppearances: minAppearances,
+		maxAppearances: maxAppearances,
+	}
+}
+
+// Validate checks if a prime-time match pushes either team over its maximum
+func (ptcc *PrimeTimeCapConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	return ptcc.ValidateIndexed(match, NewDrawIndex(draw))
+}
+
+// ValidateIndexed checks a prime-time match against the maximum using a
+// shared index
+func (ptcc *PrimeTimeCapConstraint) ValidateIndexed(match *models.Match, index *DrawIndex) error {
+	if match.IsBye() || !match.IsPrimeTime || ptcc.maxAppearances == NoPrimeTimeCap {
+		return nil
+	}
+
+	for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+		if teamID == nil {
+			continue
+		}
+
+		// Only the appearances beyond the cap are flagged, earliest rounds are kept
+		position := ptcc.primeTimePosition(index, *teamID, match)
+		if position >= ptcc.maxAppearances {
+			return fmt.Errorf("team %d exceeds maximum of %d prime-time appearances in round %d",
+				*teamID, ptcc.maxAppearances, match.Round)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDraw checks season totals that cannot be judged from a single match
+func (ptcc *PrimeTimeCapConstraint) ValidateDraw(draw *models.Draw) []error {
+	return ptcc.ValidateDrawIndexed(NewDrawIndex(draw))
+}
+
+// ValidateDrawIndexed checks season totals using a shared index
+func (ptcc *PrimeTimeCapConstraint) ValidateDrawIndexed(index *DrawIndex) []error {
+	var errors []error
+
+	counts := ptcc.GetTeamPrimeTimeCounts(index.Draw())
+	for _, teamID := range index.Teams() {
+		if counts[teamID] < ptcc.minAppearances {
+			errors = append(errors, newDrawViolation([]int{teamID}, nil,
+				"team %d has %d prime-time appearances, minimum is %d",
+				teamID, counts[teamID], ptcc.minAppearances))
+		}
+	}
+
+	return errors
+}
+
+// Score returns the fraction of teams whose prime-time appearances are within the caps
+func (ptcc *PrimeTimeCapConstraint) Score(draw *models.Draw) float64 {
+	return ptcc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed scores the teams' prime-time appearances using a shared index
+func (ptcc *PrimeTimeCapConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
+	if len(teams) == 0 {
+		return 1.0
+	}
+
+	counts := ptcc.GetTeamPrimeTimeCounts(index.Draw())
+	withinCaps := 0
+	for _, teamID := range teams {
+		if ptcc.isWithinCaps(counts[teamID]) {
+			withinCaps++
+		}
+	}
+
+	return float64(withinCaps) / float64(len(teams))
+}
+
+// primeTimePosition returns the zero-based position of a match among a team's prime-time matches
+func (ptcc *PrimeTimeCapConstraint) primeTimePosition(index *DrawIndex, teamID int, target *models.Match) int {
+	position := 0
+	for _, match := range index.TeamMatches(teamID) {
+		if match == target || match.IsBye() || !match.IsPrimeTime {
+			continue
+		}
+		if match.Round < target.Round || (match.Round == target.Round && match.ID < target.ID) {
+			position++
+		}
+	}
+	return position
+}
+
+// isWithinCaps reports whether an appearance count satisfies both caps
+func (ptcc *PrimeTimeCapConstraint) isWithinCaps(appearances int) bool {
+	if appearances < ptcc.minAppearances {
+		return false
+	}
+	return ptcc.maxAppearances == NoPrimeTimeCap || appearances <= ptcc.maxAppearances
+}
+
+// GetMinAppearances returns the minimum prime-time appearances per team
+func (ptcc *PrimeTimeCapConstraint) GetMinAppearances() int {
+	return ptcc.minAppearances
+}
+
+// GetMaxAppearances returns the maximum prime-time appearances per team
+func (ptcc *PrimeTimeCapConstraint) GetMaxAppearances() int {
+	return ptcc.maxAppearances
+}
+
+// GetTeamPrimeTimeCounts returns the number of prime-time appearances for each team
+func (ptcc *PrimeTimeCapConstraint) GetTeamPrimeTimeCounts(draw *models.Draw) map[int]int {
+	counts := make(map[int]int)
+
+	for _, match := range draw.Matches {
+		if match.IsBye() || !match.IsPrimeTime {
+			continue
+		}
+		if match.HomeTeamID != nil {
+			counts[*match.HomeTeamID]++
+		}
+		if match.AwayTeamID != nil {
+			counts[*match.AwayTeamID]++
+		}
+	}
+
+	return counts
+}
+
+// GetTeamsOutsideCaps returns the teams whose prime-time appearances break the caps
+func (ptcc *PrimeTimeCapConstraint) GetTeamsOutsideCaps(draw *models.Draw) []PrimeTimeCapAnalysis {
+	var outside []PrimeTimeCapAnalysis
+
+	counts := ptcc.GetTeamPrimeTimeCounts(draw)
+	for _, teamID := range NewDrawIndex(draw).Teams() {
+		appearances := counts[teamID]
+		if ptcc.isWithinCaps(appearances) {
+			continue
+		}
+
+		status := "OVER"
+		if appearances < ptcc.minAppearances {
+			status = "UNDER"
+		}
+
+		outside = append(outside, PrimeTimeCapAnalysis{
+			TeamID:         teamID,
+			Appearances:    appearances,
+			MinAppearances: ptcc.minAppearances,
+			MaxAppearances: ptcc.maxAppearances,
+			Status:         status,
+		})
+	}
+
+	// Sort by team ID for stable output
+	for i := 0; i < len(outside)-1; i++ {
+		for j := i + 1; j < len(outside); j++ {
+			if outside[i].TeamID > outside[j].TeamID {
+				outside[i], outside[j] = outside[j], outside[i]
+			}
+		}
+	}
+
+	return outside
+}
+
+// PrimeTimeCapAnalysis describes a team breaking its prime-time caps
+type PrimeTimeCapAnalysis struct {
+	TeamID         int    `json:"team_id"`
+	Appearances    int    `json:"appearances"`
+	MinAppearances int    `json:"min_appearances"`
+	MaxAppearances int    `json:"max_appearances"`
+	Status         string `json:"status"` // "UNDER" or "OVER"
+}
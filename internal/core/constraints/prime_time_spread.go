@@ -4,11 +4,15 @@ import (
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 )
 
-// PrimeTimeSpreadConstraint ensures fair distribution of prime-time games
+// PrimeTimeSpreadConstraint ensures fair distribution of prime-time games.
+// Rounds with byes have fewer matches, so each team's target is adjusted for
+// the share of prime-time slots in the rounds it actually plays.
+
 type PrimeTimeSpreadConstraint struct {
 	BaseConstraint
-	targetPrimeTimeRatio float64 // Target ratio of prime time games per team
-	maxDeviation         float64 // Maximum allowed deviation from target
+	targetPrimeTimeRatio float64     // Target ratio of prime time games per team
+	maxDeviation         float64     // Maximum allowed deviation from target
+	teamWeights          TeamWeights // Emphasizes particular teams in the score
 }
 
 // NewPrimeTimeSpreadConstraint creates a new prime time spread constraint
@@ -32,24 +36,66 @@ func (ptsc *PrimeTimeSpreadConstraint) Validate(match *models.Match, draw *model
 
 // Score calculates how well the draw distributes prime-time games
 func (ptsc *PrimeTimeSpreadConstraint) Score(draw *models.Draw) float64 {
-	teams := ptsc.getUniqueTeams(draw)
+	return ptsc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed averages the teams' prime time scores, weighted by the
+// team weights, using a shared index
+func (ptsc *PrimeTimeSpreadConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
 	if len(teams) == 0 {
 		return 1.0
 	}
-	
+
 	totalScore := 0.0
-	
+	totalWeight := 0.0
+
 	for _, team := range teams {
-		teamScore := ptsc.scoreTeamPrimeTimeDistribution(draw, team)
-		totalScore += teamScore
+		weight := ptsc.teamWeights.Weight(team)
+		totalScore += weight * ptsc.scoreTeamPrimeTimeDistribution(index, team)
+		totalWeight += weight
 	}
-	
-	return totalScore / float64(len(teams))
+
+	return totalScore / totalWeight
+}
+
+// TeamScores returns each team's prime time distribution score
+func (ptsc *PrimeTimeSpreadConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	return ptsc.TeamScoresIndexed(NewDrawIndex(draw))
+}
+
+// TeamScoresIndexed returns each team's prime time score using a shared index
+func (ptsc *PrimeTimeSpreadConstraint) TeamScoresIndexed(index *DrawIndex) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, team := range index.Teams() {
+		scores[team] = ptsc.scoreTeamPrimeTimeDistribution(index, team)
+	}
+	return scores
+}
+
+// ScoreTeam returns one team's prime time distribution score
+func (ptsc *PrimeTimeSpreadConstraint) ScoreTeam(draw *models.Draw, teamID int) float64 {
+	return ptsc.scoreTeamPrimeTimeDistribution(NewDrawIndex(draw), teamID)
+}
+
+// ScoreTeamIndexed returns one team's prime time score using a shared index
+func (ptsc *PrimeTimeSpreadConstraint) ScoreTeamIndexed(index *DrawIndex, teamID int) float64 {
+	return ptsc.scoreTeamPrimeTimeDistribution(index, teamID)
+}
+
+// GetTeamWeights returns the weights emphasizing particular teams' scores
+func (ptsc *PrimeTimeSpreadConstraint) GetTeamWeights() TeamWeights {
+	return ptsc.teamWeights
+}
+
+// SetTeamWeights weights particular teams' scores more heavily in the draw's score
+func (ptsc *PrimeTimeSpreadConstraint) SetTeamWeights(weights TeamWeights) {
+	ptsc.teamWeights = weights
 }
 
 // scoreTeamPrimeTimeDistribution calculates prime time distribution score for a team
-func (ptsc *PrimeTimeSpreadConstraint) scoreTeamPrimeTimeDistribution(draw *models.Draw, teamID int) float64 {
-	teamMatches := draw.GetMatchesByTeam(teamID)
+func (ptsc *PrimeTimeSpreadConstraint) scoreTeamPrimeTimeDistribution(index *DrawIndex, teamID int) float64 {
+	teamMatches := index.TeamMatches(teamID)
 	if len(teamMatches) == 0 {
 		return 1.0
 	}
@@ -74,7 +120,7 @@ func (ptsc *PrimeTimeSpreadConstraint) scoreTeamPrimeTimeDistribution(draw *mode
 	actualRatio := float64(primeTimeMatches) / float64(totalMatches)
 	
 	// Calculate deviation from target
-	deviation := actualRatio - ptsc.targetPrimeTimeRatio
+	deviation := actualRatio - ptsc.teamTargetRatio(index, teamID)
 	if deviation < 0 {
 		deviation = -deviation
 	}
@@ -89,25 +135,41 @@ func (ptsc *PrimeTimeSpreadConstraint) scoreTeamPrimeTimeDistribution(draw *mode
 	}
 }
 
-// getUniqueTeams extracts all unique team IDs from the draw
-func (ptsc *PrimeTimeSpreadConstraint) getUniqueTeams(draw *models.Draw) []int {
-	teamSet := make(map[int]bool)
+// teamTargetRatio returns the prime-time ratio a team should get: the
+// configured target, scaled by how the prime-time share of the rounds the team
+// plays compares with the share across the whole draw. When every round has
+// the same share, as in a draw without byes, this is the configured target.
+func (ptsc *PrimeTimeSpreadConstraint) teamTargetRatio(index *DrawIndex, teamID int) float64 {
+	profiles := index.RoundProfiles()
+	drawMatches, drawPrimeTime := 0, 0
+	for _, profile := range profiles {
+		drawMatches += profile.Matches
+		drawPrimeTime += profile.PrimeTimeSlots
+	}
+	if drawPrimeTime == 0 {
+		return ptsc.targetPrimeTimeRatio // Nothing in prime time yet to compare against
+	}
+	drawShare := float64(drawPrimeTime) / float64(drawMatches)
 	
-	for _, match := range draw.Matches {
-		if match.HomeTeamID != nil {
-			teamSet[*match.HomeTeamID] = true
-		}
-		if match.AwayTeamID != nil {
-			teamSet[*match.AwayTeamID] = true
+	teamShare := 0.0
+	played := 0
+	for _, match := range index.TeamMatches(teamID) {
+		if match.HomeTeamID == nil || match.AwayTeamID == nil {
+			continue
 		}
+		teamShare += profiles[match.Round].PrimeTimeShare()
+		played++
 	}
-	
-	var teams []int
-	for teamID := range teamSet {
-		teams = append(teams, teamID)
+	if played == 0 {
+		return ptsc.targetPrimeTimeRatio
 	}
+	teamShare /= float64(played)
 	
-	return teams
+	target := ptsc.targetPrimeTimeRatio * teamShare / drawShare
+	if target > 1.0 {
+		target = 1.0
+	}
+	return target
 }
 
 // GetTargetPrimeTimeRatio returns the target prime time ratio
@@ -132,18 +194,24 @@ func (ptsc *PrimeTimeSpreadConstraint) SetMaxDeviation(deviation float64) {
 
 // AnalyzeTeamPrimeTimeDistribution provides detailed analysis for a team
 func (ptsc *PrimeTimeSpreadConstraint) AnalyzeTeamPrimeTimeDistribution(draw *models.Draw, teamID int) PrimeTimeAnalysis {
+	return ptsc.analyzeTeamPrimeTime(NewDrawIndex(draw), teamID)
+}
+
+// analyzeTeamPrimeTime analyzes a team's prime time games using a shared index
+func (ptsc *PrimeTimeSpreadConstraint) analyzeTeamPrimeTime(index *DrawIndex, teamID int) PrimeTimeAnalysis {
 	analysis := PrimeTimeAnalysis{
 		TeamID:              teamID,
 		TotalMatches:        0,
 		PrimeTimeMatches:    0,
 		RegularMatches:      0,
 		PrimeTimeRatio:      0.0,
+		TargetRatio:         ptsc.teamTargetRatio(index, teamID),
 		DeviationFromTarget: 0.0,
 		WithinAcceptableRange: false,
 		PrimeTimeRounds:     []int{},
 	}
 	
-	teamMatches := draw.GetMatchesByTeam(teamID)
+	teamMatches := index.TeamMatches(teamID)
 	
 	for _, match := range teamMatches {
 		if !match.IsBye() {
@@ -159,7 +227,7 @@ func (ptsc *PrimeTimeSpreadConstraint) AnalyzeTeamPrimeTimeDistribution(draw *mo
 	
 	if analysis.TotalMatches > 0 {
 		analysis.PrimeTimeRatio = float64(analysis.PrimeTimeMatches) / float64(analysis.TotalMatches)
-		analysis.DeviationFromTarget = analysis.PrimeTimeRatio - ptsc.targetPrimeTimeRatio
+		analysis.DeviationFromTarget = analysis.PrimeTimeRatio - analysis.TargetRatio
 		
 		if analysis.DeviationFromTarget < 0 {
 			analysis.DeviationFromTarget = -analysis.DeviationFromTarget
@@ -178,6 +246,7 @@ type PrimeTimeAnalysis struct {
 	PrimeTimeMatches      int     `json:"prime_time_matches"`
 	RegularMatches        int     `json:"regular_matches"`
 	PrimeTimeRatio        float64 `json:"prime_time_ratio"`
+	TargetRatio           float64 `json:"target_ratio"`
 	DeviationFromTarget   float64 `json:"deviation_from_target"`
 	WithinAcceptableRange bool    `json:"within_acceptable_range"`
 	PrimeTimeRounds       []int   `json:"prime_time_rounds"`
@@ -185,11 +254,12 @@ type PrimeTimeAnalysis struct {
 
 // GetAllTeamPrimeTimeAnalysis returns prime time analysis for all teams
 func (ptsc *PrimeTimeSpreadConstraint) GetAllTeamPrimeTimeAnalysis(draw *models.Draw) []PrimeTimeAnalysis {
-	teams := ptsc.getUniqueTeams(draw)
+	index := NewDrawIndex(draw)
+	teams := index.Teams()
 	analyses := make([]PrimeTimeAnalysis, len(teams))
 	
 	for i, teamID := range teams {
-		analyses[i] = ptsc.AnalyzeTeamPrimeTimeDistribution(draw, teamID)
+		analyses[i] = ptsc.analyzeTeamPrimeTime(index, teamID)
 	}
 	
 	return analyses
@@ -307,12 +377,15 @@ type PrimeTimeStatistics struct {
 // GetRoundPrimeTimeDistribution returns prime time distribution by round
 func (ptsc *PrimeTimeSpreadConstraint) GetRoundPrimeTimeDistribution(draw *models.Draw) map[int]RoundPrimeTimeInfo {
 	roundInfo := make(map[int]RoundPrimeTimeInfo)
+	index := NewDrawIndex(draw)
+	profiles := index.RoundProfiles()
 	
 	for round := 1; round <= draw.Rounds; round++ {
-		roundMatches := draw.GetMatchesByRound(round)
+		roundMatches := index.RoundMatches(round)
 		
 		info := RoundPrimeTimeInfo{
 			Round:                round,
+			Byes:                 profiles[round].Byes,
 			TotalMatches:         0,
 			PrimeTimeMatches:     0,
 			RegularMatches:       0,
@@ -343,6 +416,7 @@ func (ptsc *PrimeTimeSpreadConstraint) GetRoundPrimeTimeDistribution(draw *model
 // RoundPrimeTimeInfo contains prime time information for a specific round
 type RoundPrimeTimeInfo struct {
 	Round            int     `json:"round"`
+	Byes             int     `json:"byes"`
 	TotalMatches     int     `json:"total_matches"`
 	PrimeTimeMatches int     `json:"prime_time_matches"`
 	RegularMatches   int     `json:"regular_matches"`
@@ -357,22 +431,22 @@ func (ptsc *PrimeTimeSpreadConstraint) SuggestPrimeTimeAdjustments(draw *models.
 	poorDistribution := ptsc.GetTeamsWithPoorDistribution(draw)
 	
 	for _, analysis := range poorDistribution {
-		if analysis.PrimeTimeRatio > ptsc.targetPrimeTimeRatio + ptsc.maxDeviation {
+		if analysis.PrimeTimeRatio > analysis.TargetRatio + ptsc.maxDeviation {
 			// Team has too many prime time games
 			adjustments = append(adjustments, PrimeTimeAdjustment{
 				TeamID:     analysis.TeamID,
 				Action:     "REDUCE",
 				CurrentRatio: analysis.PrimeTimeRatio,
-				TargetRatio:  ptsc.targetPrimeTimeRatio,
+				TargetRatio:  analysis.TargetRatio,
 				Suggestion:   "Move some prime time games to regular time slots",
 			})
-		} else if analysis.PrimeTimeRatio < ptsc.targetPrimeTimeRatio - ptsc.maxDeviation {
+		} else if analysis.PrimeTimeRatio < analysis.TargetRatio - ptsc.maxDeviation {
 			// Team has too few prime time games
 			adjustments = append(adjustments, PrimeTimeAdjustment{
 				TeamID:     analysis.TeamID,
 				Action:     "INCREASE",
 				CurrentRatio: analysis.PrimeTimeRatio,
-				TargetRatio:  ptsc.targetPrimeTimeRatio,
+				TargetRatio:  analysis.TargetRatio,
 				Suggestion:   "Move some regular games to prime time slots",
 			})
 		}
@@ -0,0 +1,289 @@
+package constraints
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// NoRegionMaximum marks an unbounded maximum for RegionSpreadConstraint
+const NoRegionMaximum = -1
+
+// RegionSpreadConstraint bounds how many home matches each region hosts per
+// round, so broadcasters aren't left with half a round in Sydney. A match
+// counts towards its home team's region; teams without a region are ignored.
+// As a hard constraint every region must be within the bounds in every
+// round; as a soft constraint the draw scores better the closer each region
+// is to them.
+type RegionSpreadConstraint struct {
+	BaseConstraint
+	teamRegions    map[int]string // Region of each team's home games
+	minHomeMatches int            // Minimum home matches per region per round
+	maxHomeMatches int            // Maximum home matches per region per round (NoRegionMaximum for unbounded)
+}
+
+// NewRegionSpreadConstraint creates a new region spread constraint
+func NewRegionSpreadConstraint(teamRegions map[int]string, minHomeMatches, maxHomeMatches int, isHard bool) *RegionSpreadConstraint {
+	description := "Each region should host between a minimum and maximum number of home matches per round"
+	if isHard {
+		description = "Each region must host between a minimum and maximum number of home matches per round"
+	}
+
+	return &RegionSpreadConstraint{
+		BaseConstraint: NewBaseConstraint("RegionSpread", description, isHard),
+		teamRegions:    teamRegions,
+		minHomeMatches: minHomeMatches,
+		maxHomeMatches: maxHomeMatches,
+	}
+}
+
+// Validate checks if a match pushes its region over the maximum in its round
+func (rsc *RegionSpreadConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	return rsc.ValidateIndexed(match, NewDrawIndex(draw))
+}
+
+// ValidateIndexed checks a match against its region's maximum using a shared index
+func (rsc *RegionSpreadConstraint) ValidateIndexed(match *models.Match, index *DrawIndex) error {
+	if !rsc.IsHard() || rsc.maxHomeMatches == NoRegionMaximum || match.IsBye() {
+		return nil
+	}
+	region, ok := rsc.teamRegions[*match.HomeTeamID]
+	if !ok {
+		return nil
+	}
+
+	// Only the matches beyond the maximum are flagged, lowest match IDs are kept
+	position := 0
+	for _, other := range index.RoundMatches(match.Round) {
+		if other == match || rsc.regionOf(other) != region {
+			continue
+		}
+		if other.ID < match.ID {
+			position++
+		}
+	}
+	if position >= rsc.maxHomeMatches {
+		return fmt.Errorf("%s exceeds maximum of %d home matches in round %d",
+			region, rsc.maxHomeMatches, match.Round)
+	}
+
+	return nil
+}
+
+// ValidateDraw reports regions below the minimum in a round, which no single
+// match shows. Rounds with no matches haven't been drawn yet and are skipped.
+func (rsc *RegionSpreadConstraint) ValidateDraw(draw *models.Draw) []error {
+	return rsc.ValidateDrawIndexed(NewDrawIndex(draw))
+}
+
+// ValidateDrawIndexed reports regions below the minimum using a shared index
+func (rsc *RegionSpreadConstraint) ValidateDrawIndexed(index *DrawIndex) []error {
+	if !rsc.IsHard() || rsc.minHomeMatches == 0 {
+		return nil
+	}
+
+	var errors []error
+	for _, round := range rsc.analyzeRounds(index) {
+		for _, region := range round.Regions {
+			if region.Status == "UNDER" {
+				errors = append(errors, newDrawViolation(rsc.regionTeams(region.Region), []int{round.Round},
+					"%s has %d home matches in round %d, minimum is %d",
+					region.Region, region.HomeMatches, round.Round, rsc.minHomeMatches))
+			}
+		}
+	}
+
+	return errors
+}
+
+// Score returns the average score of every region in every drawn round
+func (rsc *RegionSpreadConstraint) Score(draw *models.Draw) float64 {
+	return rsc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed returns the average region score using a shared index. A region
+// within the bounds scores 1.0, falling towards 0.0 the further outside them
+// it is.
+func (rsc *RegionSpreadConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	total := 0.0
+	count := 0
+	for _, round := range rsc.analyzeRounds(index) {
+		for _, region := range round.Regions {
+			total += rsc.scoreCount(region.HomeMatches)
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 1.0
+	}
+	return total / float64(count)
+}
+
+// scoreCount scores a region's home matches in one round against the bounds
+func (rsc *RegionSpreadConstraint) scoreCount(homeMatches int) float64 {
+	var miss, bound int
+	switch {
+	case homeMatches < rsc.minHomeMatches:
+		miss, bound = rsc.minHomeMatches-homeMatches, rsc.minHomeMatches
+	case rsc.maxHomeMatches != NoRegionMaximum && homeMatches > rsc.maxHomeMatches:
+		miss, bound = homeMatches-rsc.maxHomeMatches, rsc.maxHomeMatches
+	default:
+		return 1.0
+	}
+
+	if bound < 1 {
+		bound = 1
+	}
+	if miss >= bound {
+		return 0.0
+	}
+	return 1.0 - float64(miss)/float64(bound)
+}
+
+// regionOf returns the region of a match's home team, or "" for byes and
+// teams without a region
+func (rsc *RegionSpreadConstraint) regionOf(match *models.Match) string {
+	if match.IsBye() {
+		return ""
+	}
+	return rsc.teamRegions[*match.HomeTeamID]
+}
+
+// regionTeams returns the teams in a region, sorted by ID
+func (rsc *RegionSpreadConstraint) regionTeams(region string) []int {
+	var teamIDs []int
+	for teamID, teamRegion := range rsc.teamRegions {
+		if teamRegion == region {
+			teamIDs = append(teamIDs, teamID)
+		}
+	}
+	sort.Ints(teamIDs)
+	return teamIDs
+}
+
+// Regions returns the configured regions in alphabetical order
+func (rsc *RegionSpreadConstraint) Regions() []string {
+	seen := make(map[string]bool)
+	var regions []string
+	for _, region := range rsc.teamRegions {
+		if !seen[region] {
+			seen[region] = true
+			regions = append(regions, region)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// GetTeamRegions returns the region of each team
+func (rsc *RegionSpreadConstraint) GetTeamRegions() map[int]string {
+	return rsc.teamRegions
+}
+
+// GetMinHomeMatches returns the minimum home matches per region per round
+func (rsc *RegionSpreadConstraint) GetMinHomeMatches() int {
+	return rsc.minHomeMatches
+}
+
+// GetMaxHomeMatches returns the maximum home matches per region per round
+func (rsc *RegionSpreadConstraint) GetMaxHomeMatches() int {
+	return rsc.maxHomeMatches
+}
+
+// AnalyzeRounds returns every region's home matches in each drawn round
+// against the bounds, in round order
+func (rsc *RegionSpreadConstraint) AnalyzeRounds(draw *models.Draw) []RegionRoundAnalysis {
+	return rsc.analyzeRounds(NewDrawIndex(draw))
+}
+
+// analyzeRounds analyzes every drawn round using a shared index
+func (rsc *RegionSpreadConstraint) analyzeRounds(index *DrawIndex) []RegionRoundAnalysis {
+	regions := rsc.Regions()
+	var analyses []RegionRoundAnalysis
+
+	for round := 1; round <= index.Draw().Rounds; round++ {
+		matches := index.RoundMatches(round)
+		if len(matches) == 0 {
+			continue
+		}
+
+		matchIDs := make(map[string][]int)
+		for _, match := range matches {
+			if region := rsc.regionOf(match); region != "" {
+				matchIDs[region] = append(matchIDs[region], match.ID)
+			}
+		}
+
+		analysis := RegionRoundAnalysis{Round: round}
+		for _, region := range regions {
+			homeMatches := len(matchIDs[region])
+
+			status := "WITHIN"
+			switch {
+			case homeMatches < rsc.minHomeMatches:
+				status = "UNDER"
+			case rsc.maxHomeMatches != NoRegionMaximum && homeMatches > rsc.maxHomeMatches:
+				status = "OVER"
+			}
+
+			analysis.Regions = append(analysis.Regions, RegionHomeMatches{
+				Region:      region,
+				HomeMatches: homeMatches,
+				MatchIDs:    matchIDs[region],
+				Status:      status,
+			})
+		}
+		analyses = append(analyses, analysis)
+	}
+
+	return analyses
+}
+
+// RegionHomeMatches describes one region's home matches in a round
+type RegionHomeMatches struct {
+	Region      string `json:"region"`
+	HomeMatches int    `json:"home_matches"`
+	MatchIDs    []int  `json:"match_ids,omitempty"`
+	Status      string `json:"status"` // "WITHIN", "UNDER" or "OVER"
+}
+
+// RegionRoundAnalysis lists every region's home matches in one round
+type RegionRoundAnalysis struct {
+	Round   int                 `json:"round"`
+	Regions []RegionHomeMatches `json:"regions"`
+}
+
+// RegionSpreadReport lists every round's standing against one region spread
+type RegionSpreadReport struct {
+	Hard           bool                  `json:"hard"`
+	MinHomeMatches int                   `json:"min_home_matches"`
+	MaxHomeMatches int                   `json:"max_home_matches"`
+	Rounds         []RegionRoundAnalysis `json:"rounds"`
+}
+
+// AnalyzeRegionSpread reports each round against every region spread in the
+// engine, hard constraints first
+func (ce *ConstraintEngine) AnalyzeRegionSpread(draw *models.Draw) []RegionSpreadReport {
+	reports := []RegionSpreadReport{}
+
+	spreads := append([]Constraint{}, ce.hardConstraints...)
+	for _, weighted := range ce.softConstraints {
+		spreads = append(spreads, weighted.Constraint)
+	}
+	for _, constraint := range spreads {
+		spread, ok := constraint.(*RegionSpreadConstraint)
+		if !ok {
+			continue
+		}
+		reports = append(reports, RegionSpreadReport{
+			Hard:           spread.IsHard(),
+			MinHomeMatches: spread.minHomeMatches,
+			MaxHomeMatches: spread.maxHomeMatches,
+			Rounds:         spread.AnalyzeRounds(draw),
+		})
+	}
+
+	return reports
+}
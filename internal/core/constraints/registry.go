@@ -0,0 +1,127 @@
+package constraints
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	ErrInvalidRegistration = errors.New("invalid constraint registration")
+	ErrConstraintTypeTaken = errors.New("constraint type already exists")
+)
+
+// ConstraintBuilder builds a constraint from its configuration params, which
+// arrive decoded from JSON, so numbers are float64. isHard reports whether the
+// constraint is configured under hard or soft.
+type ConstraintBuilder func(params map[string]interface{}, isHard bool) (Constraint, error)
+
+// ParamsExporter is implemented by registered constraints that can write their
+// params back out, so exported configurations rebuild them unchanged
+type ParamsExporter interface {
+	Params() map[string]interface{}
+}
+
+// registeredType is a constraint type added through Register
+type registeredType struct {
+	info    ConstraintTypeInfo
+	builder ConstraintBuilder
+}
+
+// registry holds constraint types added outside this package
+var registry = struct {
+	sync.RWMutex
+	types map[string]registeredType
+	kinds map[reflect.Type]string // Go type of built constraints -> configuration type
+}{
+	types: make(map[string]registeredType),
+	kinds: make(map[reflect.Type]string),
+}
+
+// Register adds a constraint type the factory can build. info.Type decides
+// whether it's configured under hard or soft, and info.Parameters documents
+// its params in the type catalogue and schema. Built-in types can't be replaced.
+func Register(name string, info ConstraintTypeInfo, builder ConstraintBuilder) error {
+	if name == "" || builder == nil {
+		return fmt.Errorf("%w: name and builder are required", ErrInvalidRegistration)
+	}
+	if info.Type != "hard" && info.Type != "soft" {
+		return fmt.Errorf("%w: type of %s must be \"hard\" or \"soft\"", ErrInvalidRegistration, name)
+	}
+	if _, builtin := builtinConstraintTypeInfo()[name]; builtin {
+		return fmt.Errorf("%w: %s", ErrConstraintTypeTaken, name)
+	}
+	if info.Parameters == nil {
+		info.Parameters = map[string]string{}
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, exists := registry.types[name]; exists {
+		return fmt.Errorf("%w: %s", ErrConstraintTypeTaken, name)
+	}
+	registry.types[name] = registeredType{info: info, builder: builder}
+	return nil
+}
+
+// Unregister removes a constraint type added through Register
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	delete(registry.types, name)
+	for kind, registered := range registry.kinds {
+		if registered == name {
+			delete(registry.kinds, kind)
+		}
+	}
+}
+
+// registeredTypeInfo returns the catalogue entry of every registered type
+func registeredTypeInfo() map[string]ConstraintTypeInfo {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	info := make(map[string]ConstraintTypeInfo, len(registry.types))
+	for name, registered := range registry.types {
+		info[name] = registered.info
+	}
+	return info
+}
+
+// buildRegistered builds a registered constraint type, reporting false when
+// no type of that name is registered for hard or soft use
+func buildRegistered(name string, params map[string]interface{}, isHard bool) (Constraint, bool, error) {
+	registry.RLock()
+	registered, exists := registry.types[name]
+	registry.RUnlock()
+
+	if !exists || (registered.info.Type == "hard") != isHard {
+		return nil, false, nil
+	}
+
+	constraint, err := registered.builder(params, isHard)
+	if err != nil {
+		return nil, true, err
+	}
+	if constraint == nil {
+		return nil, true, fmt.Errorf("builder for %s returned no constraint", name)
+	}
+
+	registry.Lock()
+	registry.kinds[reflect.TypeOf(constraint)] = name
+	registry.Unlock()
+
+	return constraint, true, nil
+}
+
+// registeredTypeOf returns the configuration type a registered constraint was built from
+func registeredTypeOf(constraint Constraint) (string, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	name, ok := registry.kinds[reflect.TypeOf(constraint)]
+	return name, ok
+}
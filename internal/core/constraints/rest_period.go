@@ -1,63 +1,179 @@
 package constraints
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 )
 
-// RestPeriodConstraint ensures minimum rest days between matches
+// NoTurnaroundLimit disables the cap on short turnarounds
+const NoTurnaroundLimit = -1
+
+// DefaultShortTurnaroundDays is the NRL's short turnaround: five days or fewer
+// between kickoffs, e.g. Sunday to Friday
+const DefaultShortTurnaroundDays = 5
+
+// RestPeriodConstraint ensures minimum rest days between matches. As a soft
+// constraint the draw scores better the more turnarounds give the preferred
+// rest; as a hard constraint no team may get less than the minimum. Either can
+// also cap how many short turnarounds each team gets across the season: as a
+// hard constraint the turnarounds beyond the cap are violations, as a soft
+// constraint they lower the team's score.
+//
+// Configure both to separate the hard minimum from the preference, e.g. a hard
+// 4 rest days with at most 3 short turnarounds and a soft 6 rest days.
+//
+// Matches without a kickoff date are taken to be played on the first day of
+// their round in the season calendar, when there is one.
+
 type RestPeriodConstraint struct {
 	BaseConstraint
-	minRestDays   int
-	penaltyWeight float64
+	minRestDays         int
+	shortTurnaroundDays int // Turnarounds of this many days or fewer between kickoffs are short
+	maxShortTurnarounds int // Maximum short turnarounds per team (NoTurnaroundLimit for unbounded)
+	penaltyWeight       float64
+	teamWeights         TeamWeights // Emphasizes particular teams in the score
+	calendar            *models.SeasonCalendar
 }
 
-// NewRestPeriodConstraint creates a new rest period constraint
+// NewRestPeriodConstraint creates a new soft rest period constraint
 func NewRestPeriodConstraint(minRestDays int) *RestPeriodConstraint {
+	return newRestPeriodConstraint(minRestDays, false)
+}
+
+// NewHardRestPeriodConstraint creates a rest period constraint no team may
+// breach
+func NewHardRestPeriodConstraint(minRestDays int) *RestPeriodConstraint {
+	return newRestPeriodConstraint(minRestDays, true)
+}
+
+func newRestPeriodConstraint(minRestDays int, isHard bool) *RestPeriodConstraint {
+	description := "Ensure minimum rest days between matches for player welfare"
+	if isHard {
+		description = "Every team must get a minimum number of rest days between matches"
+	}
+
 	return &RestPeriodConstraint{
-		BaseConstraint: NewBaseConstraint(
-			"RestPeriod",
-			"Ensure minimum rest days between matches for player welfare",
-			false, // This is a soft constraint
-		),
-		minRestDays:   minRestDays,
-		penaltyWeight: 1.0,
+		BaseConstraint:      NewBaseConstraint("RestPeriod", description, isHard),
+		minRestDays:         minRestDays,
+		shortTurnaroundDays: DefaultShortTurnaroundDays,
+		maxShortTurnarounds: NoTurnaroundLimit,
+		penaltyWeight:       1.0,
 	}
 }
 
-// Validate always returns nil for soft constraints
+// Validate checks that neither team comes into the match with less than the
+// minimum rest, or with one short turnaround more than the cap allows. Only
+// the turnarounds beyond the cap are flagged, the earliest are kept.
 func (rpc *RestPeriodConstraint) Validate(match *models.Match, draw *models.Draw) error {
-	// Soft constraints don't have hard validation failures
+	return rpc.ValidateIndexed(match, NewDrawIndex(draw))
+}
+
+// ValidateIndexed checks the rest before a match using a shared index
+func (rpc *RestPeriodConstraint) ValidateIndexed(match *models.Match, index *DrawIndex) error {
+	if _, dated := calendarDate(rpc.calendar, match); !rpc.IsHard() || !dated {
+		return nil
+	}
+
+	for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+		if teamID == nil {
+			continue
+		}
+
+		shortTurnarounds := 0
+		for _, period := range rpc.analyzeTeamRest(index, *teamID).RestPeriods {
+			if period.IsShortTurnaround {
+				shortTurnarounds++
+			}
+			if period.ToMatchID != match.ID {
+				continue
+			}
+
+			if period.RestDays < rpc.minRestDays {
+				return fmt.Errorf("team %d has %d rest days before round %d, minimum is %d",
+					*teamID, period.RestDays, match.Round, rpc.minRestDays)
+			}
+			if period.IsShortTurnaround && rpc.maxShortTurnarounds != NoTurnaroundLimit && shortTurnarounds > rpc.maxShortTurnarounds {
+				return fmt.Errorf("team %d exceeds maximum of %d short turnarounds in round %d",
+					*teamID, rpc.maxShortTurnarounds, match.Round)
+			}
+			break
+		}
+	}
+
 	return nil
 }
 
 // Score calculates how well the draw satisfies rest period requirements
 func (rpc *RestPeriodConstraint) Score(draw *models.Draw) float64 {
-	teams := rpc.getUniqueTeams(draw)
+	return rpc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed averages the teams' rest period scores, weighted by the
+// team weights, using a shared index
+func (rpc *RestPeriodConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
 	if len(teams) == 0 {
 		return 1.0
 	}
-	
+
 	totalScore := 0.0
-	
+	totalWeight := 0.0
+
 	for _, team := range teams {
-		teamScore := rpc.scoreTeamRestPeriods(draw, team)
-		totalScore += teamScore
+		weight := rpc.teamWeights.Weight(team)
+		totalScore += weight * rpc.scoreTeamRestPeriods(index, team)
+		totalWeight += weight
 	}
-	
-	return totalScore / float64(len(teams))
+
+	return totalScore / totalWeight
+}
+
+// TeamScores returns each team's rest period score
+func (rpc *RestPeriodConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	return rpc.TeamScoresIndexed(NewDrawIndex(draw))
+}
+
+// TeamScoresIndexed returns each team's rest period score using a shared index
+func (rpc *RestPeriodConstraint) TeamScoresIndexed(index *DrawIndex) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, team := range index.Teams() {
+		scores[team] = rpc.scoreTeamRestPeriods(index, team)
+	}
+	return scores
+}
+
+// ScoreTeam returns one team's rest period score
+func (rpc *RestPeriodConstraint) ScoreTeam(draw *models.Draw, teamID int) float64 {
+	return rpc.scoreTeamRestPeriods(NewDrawIndex(draw), teamID)
+}
+
+// ScoreTeamIndexed returns one team's rest period score using a shared index
+func (rpc *RestPeriodConstraint) ScoreTeamIndexed(index *DrawIndex, teamID int) float64 {
+	return rpc.scoreTeamRestPeriods(index, teamID)
+}
+
+// GetTeamWeights returns the weights emphasizing particular teams' scores
+func (rpc *RestPeriodConstraint) GetTeamWeights() TeamWeights {
+	return rpc.teamWeights
+}
+
+// SetTeamWeights weights particular teams' scores more heavily in the draw's score
+func (rpc *RestPeriodConstraint) SetTeamWeights(weights TeamWeights) {
+	rpc.teamWeights = weights
 }
 
 // scoreTeamRestPeriods calculates the rest period score for a specific team
-func (rpc *RestPeriodConstraint) scoreTeamRestPeriods(draw *models.Draw, teamID int) float64 {
-	teamMatches := rpc.getTeamMatchesWithDates(draw, teamID)
+func (rpc *RestPeriodConstraint) scoreTeamRestPeriods(index *DrawIndex, teamID int) float64 {
+	teamMatches := rpc.getTeamMatchesWithDates(index, teamID)
 	if len(teamMatches) <= 1 {
 		return 1.0 // Can't violate rest periods with 0 or 1 matches
 	}
 	
 	violations := 0
 	totalGaps := 0
+	shortTurnarounds := 0
 	
 	// Sort matches by date
 	sortedMatches := rpc.sortMatchesByDate(teamMatches)
@@ -74,6 +190,9 @@ func (rpc *RestPeriodConstraint) scoreTeamRestPeriods(draw *models.Draw, teamID
 			if restDays < rpc.minRestDays {
 				violations++
 			}
+			if rpc.isShortTurnaround(*prevMatch.MatchDate, *currentMatch.MatchDate) {
+				shortTurnarounds++
+			}
 		}
 	}
 	
@@ -81,44 +200,40 @@ func (rpc *RestPeriodConstraint) scoreTeamRestPeriods(draw *models.Draw, teamID
 		return 1.0 // No gaps to evaluate
 	}
 	
-	// Return percentage of adequate rest periods
-	return float64(totalGaps-violations) / float64(totalGaps)
-}
-
-// getUniqueTeams extracts all unique team IDs from the draw
-func (rpc *RestPeriodConstraint) getUniqueTeams(draw *models.Draw) []int {
-	teamSet := make(map[int]bool)
-	
-	for _, match := range draw.Matches {
-		if match.HomeTeamID != nil {
-			teamSet[*match.HomeTeamID] = true
-		}
-		if match.AwayTeamID != nil {
-			teamSet[*match.AwayTeamID] = true
-		}
+	// Percentage of adequate rest periods, halved for each short turnaround over the cap
+	score := float64(totalGaps-violations) / float64(totalGaps)
+	if rpc.maxShortTurnarounds != NoTurnaroundLimit && shortTurnarounds > rpc.maxShortTurnarounds {
+		score /= float64(1 + shortTurnarounds - rpc.maxShortTurnarounds)
 	}
-	
-	var teams []int
-	for teamID := range teamSet {
-		teams = append(teams, teamID)
-	}
-	
-	return teams
+	return score
 }
 
-// getTeamMatchesWithDates returns team matches that have scheduled dates
-func (rpc *RestPeriodConstraint) getTeamMatchesWithDates(draw *models.Draw, teamID int) []*models.Match {
+// getTeamMatchesWithDates returns team matches that have scheduled dates.
+// Undated matches the season calendar dates are returned as copies dated to
+// their round's first day.
+func (rpc *RestPeriodConstraint) getTeamMatchesWithDates(index *DrawIndex, teamID int) []*models.Match {
 	var matches []*models.Match
 	
-	for _, match := range draw.Matches {
-		if match.HasTeam(teamID) && match.MatchDate != nil {
+	for _, match := range index.TeamMatches(teamID) {
+		if match.MatchDate != nil {
 			matches = append(matches, match)
+			continue
+		}
+		if date, dated := calendarDate(rpc.calendar, match); dated {
+			scheduled := *match
+			scheduled.MatchDate = &date
+			matches = append(matches, &scheduled)
 		}
 	}
 	
 	return matches
 }
 
+// SetCalendar sets the season calendar undated matches are dated from
+func (rpc *RestPeriodConstraint) SetCalendar(calendar *models.SeasonCalendar) {
+	rpc.calendar = calendar
+}
+
 // sortMatchesByDate sorts matches by their scheduled date
 func (rpc *RestPeriodConstraint) sortMatchesByDate(matches []*models.Match) []*models.Match {
 	// Create a copy to avoid modifying the original slice
@@ -154,11 +269,36 @@ func (rpc *RestPeriodConstraint) calculateRestDays(date1, date2 time.Time) int {
 	return days - 1
 }
 
+// isShortTurnaround reports whether two match dates are close enough together
+// to count towards the short turnaround cap
+func (rpc *RestPeriodConstraint) isShortTurnaround(date1, date2 time.Time) bool {
+	return rpc.calculateRestDays(date1, date2)+1 <= rpc.shortTurnaroundDays
+}
+
 // GetMinRestDays returns the minimum required rest days
 func (rpc *RestPeriodConstraint) GetMinRestDays() int {
 	return rpc.minRestDays
 }
 
+// SetMaxShortTurnarounds caps each team's short turnarounds across the
+// season, where a turnaround is short with turnaroundDays or fewer between
+// kickoffs
+func (rpc *RestPeriodConstraint) SetMaxShortTurnarounds(maxShortTurnarounds, turnaroundDays int) {
+	rpc.maxShortTurnarounds = maxShortTurnarounds
+	rpc.shortTurnaroundDays = turnaroundDays
+}
+
+// GetMaxShortTurnarounds returns the cap on short turnarounds per team
+func (rpc *RestPeriodConstraint) GetMaxShortTurnarounds() int {
+	return rpc.maxShortTurnarounds
+}
+
+// GetShortTurnaroundDays returns the most days between kickoffs that count as
+// a short turnaround
+func (rpc *RestPeriodConstraint) GetShortTurnaroundDays() int {
+	return rpc.shortTurnaroundDays
+}
+
 // SetPenaltyWeight sets the penalty weight for inadequate rest periods
 func (rpc *RestPeriodConstraint) SetPenaltyWeight(weight float64) {
 	rpc.penaltyWeight = weight
@@ -166,6 +306,11 @@ func (rpc *RestPeriodConstraint) SetPenaltyWeight(weight float64) {
 
 // AnalyzeTeamRestPeriods provides detailed rest period analysis for a team
 func (rpc *RestPeriodConstraint) AnalyzeTeamRestPeriods(draw *models.Draw, teamID int) RestPeriodAnalysis {
+	return rpc.analyzeTeamRest(NewDrawIndex(draw), teamID)
+}
+
+// analyzeTeamRest analyzes a team's rest periods using a shared index
+func (rpc *RestPeriodConstraint) analyzeTeamRest(index *DrawIndex, teamID int) RestPeriodAnalysis {
 	analysis := RestPeriodAnalysis{
 		TeamID:              teamID,
 		TotalMatches:        0,
@@ -175,10 +320,10 @@ func (rpc *RestPeriodConstraint) AnalyzeTeamRestPeriods(draw *models.Draw, teamI
 		RestPeriods:         []RestPeriod{},
 	}
 	
-	teamMatches := draw.GetMatchesByTeam(teamID)
+	teamMatches := index.TeamMatches(teamID)
 	analysis.TotalMatches = len(teamMatches)
 	
-	scheduledMatches := rpc.getTeamMatchesWithDates(draw, teamID)
+	scheduledMatches := rpc.getTeamMatchesWithDates(index, teamID)
 	analysis.ScheduledMatches = len(scheduledMatches)
 	
 	if len(scheduledMatches) <= 1 {
@@ -195,12 +340,13 @@ func (rpc *RestPeriodConstraint) AnalyzeTeamRestPeriods(draw *models.Draw, teamI
 		restDays := rpc.calculateRestDays(*prevMatch.MatchDate, *currentMatch.MatchDate)
 		
 		restPeriod := RestPeriod{
-			FromMatchID:  prevMatch.ID,
-			ToMatchID:    currentMatch.ID,
-			FromDate:     *prevMatch.MatchDate,
-			ToDate:       *currentMatch.MatchDate,
-			RestDays:     restDays,
-			IsAdequate:   restDays >= rpc.minRestDays,
+			FromMatchID:       prevMatch.ID,
+			ToMatchID:         currentMatch.ID,
+			FromDate:          *prevMatch.MatchDate,
+			ToDate:            *currentMatch.MatchDate,
+			RestDays:          restDays,
+			IsAdequate:        restDays >= rpc.minRestDays,
+			IsShortTurnaround: rpc.isShortTurnaround(*prevMatch.MatchDate, *currentMatch.MatchDate),
 		}
 		
 		analysis.RestPeriods = append(analysis.RestPeriods, restPeriod)
@@ -210,6 +356,9 @@ func (rpc *RestPeriodConstraint) AnalyzeTeamRestPeriods(draw *models.Draw, teamI
 		} else {
 			analysis.ShortRestPeriods++
 		}
+		if restPeriod.IsShortTurnaround {
+			analysis.ShortTurnarounds++
+		}
 	}
 	
 	return analysis
@@ -222,26 +371,29 @@ type RestPeriodAnalysis struct {
 	ScheduledMatches    int          `json:"scheduled_matches"`
 	AdequateRestPeriods int          `json:"adequate_rest_periods"`
 	ShortRestPeriods    int          `json:"short_rest_periods"`
+	ShortTurnarounds    int          `json:"short_turnarounds"`
 	RestPeriods         []RestPeriod `json:"rest_periods"`
 }
 
 // RestPeriod represents the rest period between two matches
 type RestPeriod struct {
-	FromMatchID int       `json:"from_match_id"`
-	ToMatchID   int       `json:"to_match_id"`
-	FromDate    time.Time `json:"from_date"`
-	ToDate      time.Time `json:"to_date"`
-	RestDays    int       `json:"rest_days"`
-	IsAdequate  bool      `json:"is_adequate"`
+	FromMatchID       int       `json:"from_match_id"`
+	ToMatchID         int       `json:"to_match_id"`
+	FromDate          time.Time `json:"from_date"`
+	ToDate            time.Time `json:"to_date"`
+	RestDays          int       `json:"rest_days"`
+	IsAdequate        bool      `json:"is_adequate"`
+	IsShortTurnaround bool      `json:"is_short_turnaround"`
 }
 
 // GetAllTeamRestAnalysis returns rest period analysis for all teams
 func (rpc *RestPeriodConstraint) GetAllTeamRestAnalysis(draw *models.Draw) []RestPeriodAnalysis {
-	teams := rpc.getUniqueTeams(draw)
+	index := NewDrawIndex(draw)
+	teams := index.Teams()
 	analyses := make([]RestPeriodAnalysis, len(teams))
 	
 	for i, teamID := range teams {
-		analyses[i] = rpc.AnalyzeTeamRestPeriods(draw, teamID)
+		analyses[i] = rpc.analyzeTeamRest(index, teamID)
 	}
 	
 	return analyses
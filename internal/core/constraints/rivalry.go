@@ -0,0 +1,108 @@
+package constraints
+
+import (
+	"fmt"
+	"math"
+)
+
+// HistoricalResult is the final score of a past match between two teams
+type HistoricalResult struct {
+	Season     int `json:"season"`
+	Round      int `json:"round"`
+	HomeTeamID int `json:"home_team_id"`
+	AwayTeamID int `json:"away_team_id"`
+	HomeScore  int `json:"home_score"`
+	AwayScore  int `json:"away_score"`
+}
+
+// Margin returns the absolute winning margin of the result
+func (hr HistoricalResult) Margin() int {
+	margin := hr.HomeScore - hr.AwayScore
+	if margin < 0 {
+		margin = -margin
+	}
+	return margin
+}
+
+// RivalryWeightOptions control how results and ladder positions boost matchup weights
+type RivalryWeightOptions struct {
+	CloseMargin      int     `json:"close_margin"`       // Margin at or under which a finish counts as close
+	CloseFinishBoost float64 `json:"close_finish_boost"` // Boost per close finish in the latest season
+	LadderBoost      float64 `json:"ladder_boost"`       // Boost for teams adjacent on the ladder
+}
+
+// DefaultRivalryWeightOptions returns the default boosting options
+func DefaultRivalryWeightOptions() RivalryWeightOptions {
+	return RivalryWeightOptions{
+		CloseMargin:      6,
+		CloseFinishBoost: 0.2,
+		LadderBoost:      0.3,
+	}
+}
+
+// DefaultMatchupWeight is the attractiveness of a matchup with no rivalry data
+const DefaultMatchupWeight = 1.0
+
+// MatchupKey returns the order-independent key for a pair of teams
+func MatchupKey(teamA, teamB int) string {
+	if teamA > teamB {
+		teamA, teamB = teamB, teamA
+	}
+	return fmt.Sprintf("%d-%d", teamA, teamB)
+}
+
+// ComputeRivalryWeights boosts base matchup weights using recent close finishes
+// and ladder proximity. Older seasons count for less than the latest one.
+func ComputeRivalryWeights(base map[string]float64, results []HistoricalResult, ladder map[int]int, options RivalryWeightOptions) map[string]float64 {
+	weights := make(map[string]float64)
+	for key, weight := range base {
+		weights[key] = weight
+	}
+
+	weightFor := func(key string) float64 {
+		if weight, ok := weights[key]; ok {
+			return weight
+		}
+		return DefaultMatchupWeight
+	}
+
+	// Close finishes, decayed by how many seasons ago they happened
+	latestSeason := 0
+	for _, result := range results {
+		if result.Season > latestSeason {
+			latestSeason = result.Season
+		}
+	}
+
+	for _, result := range results {
+		if result.Margin() > options.CloseMargin {
+			continue
+		}
+		key := MatchupKey(result.HomeTeamID, result.AwayTeamID)
+		recency := 1.0 / float64(1+latestSeason-result.Season)
+		weights[key] = weightFor(key) + options.CloseFinishBoost*recency
+	}
+
+	// Ladder proximity, strongest for teams finishing next to each other
+	teamCount := len(ladder)
+	if teamCount > 1 {
+		teamIDs := make([]int, 0, teamCount)
+		for teamID := range ladder {
+			teamIDs = append(teamIDs, teamID)
+		}
+
+		for i := 0; i < len(teamIDs)-1; i++ {
+			for j := i + 1; j < len(teamIDs); j++ {
+				gap := math.Abs(float64(ladder[teamIDs[i]] - ladder[teamIDs[j]]))
+				proximity := 1.0 - (gap-1)/float64(teamCount-1)
+				if proximity <= 0 {
+					continue
+				}
+				key := MatchupKey(teamIDs[i], teamIDs[j])
+				weights[key] = weightFor(key) + options.LadderBoost*proximity
+			}
+		}
+	}
+
+	return weights
+}
@@ -0,0 +1,163 @@
+package constraints
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// RivalryFixture pins a blockbuster matchup to a round, such as the season
+// opener or the Anzac Day round
+type RivalryFixture struct {
+	TeamA int `json:"team_a"`
+	TeamB int `json:"team_b"`
+	Round int `json:"round"`
+}
+
+// Involves reports whether a team is one side of the fixture
+func (rf RivalryFixture) Involves(teamID int) bool {
+	return rf.TeamA == teamID || rf.TeamB == teamID
+}
+
+// RivalryRoundConstraint places rivalry fixtures in their target rounds. As a
+// hard constraint the fixtures must be in those rounds; as a soft constraint
+// the draw scores better the more of them are.
+type RivalryRoundConstraint struct {
+	BaseConstraint
+	fixtures []RivalryFixture
+}
+
+// NewRivalryRoundConstraint creates a new rivalry round constraint
+func NewRivalryRoundConstraint(fixtures []RivalryFixture, isHard bool) *RivalryRoundConstraint {
+	description := "Rivalry fixtures should be played in their target rounds"
+	if isHard {
+		description = "Rivalry fixtures must be played in their target rounds"
+	}
+
+	return &RivalryRoundConstraint{
+		BaseConstraint: NewBaseConstraint("RivalryRound", description, isHard),
+		fixtures:       fixtures,
+	}
+}
+
+// Validate checks that a match in a target round doesn't pull either rival
+// into a different matchup
+func (rrc *RivalryRoundConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	if match.IsBye() {
+		return nil
+	}
+
+	for _, fixture := range rrc.fixtures {
+		if match.Round != fixture.Round || rrc.isFixture(match, fixture) {
+			continue
+		}
+		for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+			if teamID != nil && fixture.Involves(*teamID) {
+				return fmt.Errorf("team %d must play the %s rivalry fixture in round %d, not match %d",
+					*teamID, MatchupKey(fixture.TeamA, fixture.TeamB), fixture.Round, match.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateDraw reports rivalry fixtures missing from a target round in which
+// neither rival has been scheduled, which Validate can't see from any match
+func (rrc *RivalryRoundConstraint) ValidateDraw(draw *models.Draw) []error {
+	var errors []error
+
+	for _, fixture := range rrc.fixtures {
+		if rrc.isPlaced(draw, fixture) || rrc.eitherRivalPlays(draw, fixture) {
+			continue
+		}
+		errors = append(errors, newDrawViolation([]int{fixture.TeamA, fixture.TeamB}, []int{fixture.Round},
+			"rivalry fixture %s is not scheduled in round %d",
+			MatchupKey(fixture.TeamA, fixture.TeamB), fixture.Round))
+	}
+
+	return errors
+}
+
+// Score returns the fraction of rivalry fixtures played in their target round
+func (rrc *RivalryRoundConstraint) Score(draw *models.Draw) float64 {
+	if len(rrc.fixtures) == 0 {
+		return 1.0
+	}
+
+	placed := 0
+	for _, fixture := range rrc.fixtures {
+		if rrc.isPlaced(draw, fixture) {
+			placed++
+		}
+	}
+
+	return float64(placed) / float64(len(rrc.fixtures))
+}
+
+// GetFixtures returns the rivalry fixtures and their target rounds
+func (rrc *RivalryRoundConstraint) GetFixtures() []RivalryFixture {
+	return rrc.fixtures
+}
+
+// GetMisplacedFixtures returns every rivalry fixture not played in its target
+// round, with the rounds the two teams meet in instead
+func (rrc *RivalryRoundConstraint) GetMisplacedFixtures(draw *models.Draw) []RivalryPlacement {
+	var misplaced []RivalryPlacement
+
+	for _, fixture := range rrc.fixtures {
+		if rrc.isPlaced(draw, fixture) {
+			continue
+		}
+
+		placement := RivalryPlacement{RivalryFixture: fixture}
+		for _, match := range draw.Matches {
+			if !match.IsBye() && rrc.isPairing(match, fixture) {
+				placement.ScheduledRounds = append(placement.ScheduledRounds, match.Round)
+			}
+		}
+		sortInts(placement.ScheduledRounds)
+		misplaced = append(misplaced, placement)
+	}
+
+	return misplaced
+}
+
+// isPlaced reports whether the fixture's teams meet in its target round
+func (rrc *RivalryRoundConstraint) isPlaced(draw *models.Draw, fixture RivalryFixture) bool {
+	for _, match := range draw.GetMatchesByRound(fixture.Round) {
+		if rrc.isFixture(match, fixture) {
+			return true
+		}
+	}
+	return false
+}
+
+// eitherRivalPlays reports whether either team has a match in the fixture's target round
+func (rrc *RivalryRoundConstraint) eitherRivalPlays(draw *models.Draw, fixture RivalryFixture) bool {
+	for _, match := range draw.GetMatchesByRound(fixture.Round) {
+		if match.HasTeam(fixture.TeamA) || match.HasTeam(fixture.TeamB) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFixture reports whether a match is the fixture's pairing in its target round
+func (rrc *RivalryRoundConstraint) isFixture(match *models.Match, fixture RivalryFixture) bool {
+	return match.Round == fixture.Round && rrc.isPairing(match, fixture)
+}
+
+// isPairing reports whether a match is between the fixture's two teams, either way round
+func (rrc *RivalryRoundConstraint) isPairing(match *models.Match, fixture RivalryFixture) bool {
+	if match.HomeTeamID == nil || match.AwayTeamID == nil {
+		return false
+	}
+	return MatchupKey(*match.HomeTeamID, *match.AwayTeamID) == MatchupKey(fixture.TeamA, fixture.TeamB)
+}
+
+// RivalryPlacement describes a rivalry fixture that missed its target round
+type RivalryPlacement struct {
+	RivalryFixture
+	ScheduledRounds []int `json:"scheduled_rounds"` // Rounds the teams meet in instead, empty if they don't
+}
@@ -0,0 +1,186 @@
+package constraints
+
+import (
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+const (
+	// stabilityMovePenalty is charged when a match moves to a different round
+	stabilityMovePenalty = 1.0
+	// stabilitySlotPenalty is charged when a match keeps its round but changes slot
+	stabilitySlotPenalty = 0.5
+)
+
+// matchSnapshot records the published state of a single match
+type matchSnapshot struct {
+	round       int
+	homeTeamID  *int
+	awayTeamID  *int
+	venueID     *int
+	matchDate   *time.Time
+	matchTime   *time.Time
+	isPrimeTime bool
+}
+
+// ScheduleStabilityConstraint penalizes deviation from a previously published draw
+// so re-optimization prefers minimal-disruption fixes
+type ScheduleStabilityConstraint struct {
+	BaseConstraint
+	baseline map[int]matchSnapshot
+}
+
+// NewScheduleStabilityConstraint creates a stability constraint against the given baseline draw
+func NewScheduleStabilityConstraint(baseline *models.Draw) *ScheduleStabilityConstraint {
+	snapshots := make(map[int]matchSnapshot)
+	if baseline != nil {
+		for _, match := range baseline.Matches {
+			snapshots[match.ID] = matchSnapshot{
+				round:       match.Round,
+				homeTeamID:  copyInt(match.HomeTeamID),
+				awayTeamID:  copyInt(match.AwayTeamID),
+				venueID:     copyInt(match.VenueID),
+				matchDate:   copyTime(match.MatchDate),
+				matchTime:   copyTime(match.MatchTime),
+				isPrimeTime: match.IsPrimeTime,
+			}
+		}
+	}
+
+	return &ScheduleStabilityConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"ScheduleStability",
+			"Minimize changes to the published draw when re-optimizing",
+			false, // This is a soft constraint
+		),
+		baseline: snapshots,
+	}
+}
+
+// Validate always returns nil for soft constraints
+func (ssc *ScheduleStabilityConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score returns 1.0 for an unchanged draw, decreasing with each moved match or changed slot
+func (ssc *ScheduleStabilityConstraint) Score(draw *models.Draw) float64 {
+	compared := 0
+	penalty := 0.0
+
+	for _, match := range draw.Matches {
+		snapshot, exists := ssc.baseline[match.ID]
+		if !exists {
+			continue
+		}
+
+		compared++
+		switch ssc.classifyChange(match, snapshot) {
+		case "MOVED":
+			penalty += stabilityMovePenalty
+		case "SLOT_CHANGED":
+			penalty += stabilitySlotPenalty
+		}
+	}
+
+	if compared == 0 {
+		return 1.0
+	}
+
+	return 1.0 - penalty/float64(compared)
+}
+
+// classifyChange describes how a match differs from its published state
+func (ssc *ScheduleStabilityConstraint) classifyChange(match *models.Match, snapshot matchSnapshot) string {
+	if match.Round != snapshot.round {
+		return "MOVED"
+	}
+
+	if !sameInt(match.HomeTeamID, snapshot.homeTeamID) ||
+		!sameInt(match.AwayTeamID, snapshot.awayTeamID) ||
+		!sameInt(match.VenueID, snapshot.venueID) ||
+		!sameTime(match.MatchDate, snapshot.matchDate) ||
+		!sameTime(match.MatchTime, snapshot.matchTime) ||
+		match.IsPrimeTime != snapshot.isPrimeTime {
+		return "SLOT_CHANGED"
+	}
+
+	return "UNCHANGED"
+}
+
+// GetBaselineSize returns the number of published matches being compared against
+func (ssc *ScheduleStabilityConstraint) GetBaselineSize() int {
+	return len(ssc.baseline)
+}
+
+// AnalyzeChanges summarizes how a draw differs from the published baseline
+func (ssc *ScheduleStabilityConstraint) AnalyzeChanges(draw *models.Draw) StabilityAnalysis {
+	analysis := StabilityAnalysis{
+		MovedMatchIDs:       []int{},
+		SlotChangedMatchIDs: []int{},
+	}
+
+	for _, match := range draw.Matches {
+		snapshot, exists := ssc.baseline[match.ID]
+		if !exists {
+			continue
+		}
+
+		switch ssc.classifyChange(match, snapshot) {
+		case "MOVED":
+			analysis.MovedMatches++
+			analysis.MovedMatchIDs = append(analysis.MovedMatchIDs, match.ID)
+		case "SLOT_CHANGED":
+			analysis.SlotChanges++
+			analysis.SlotChangedMatchIDs = append(analysis.SlotChangedMatchIDs, match.ID)
+		default:
+			analysis.UnchangedMatches++
+		}
+	}
+
+	return analysis
+}
+
+// StabilityAnalysis contains a summary of changes against the published draw
+type StabilityAnalysis struct {
+	MovedMatches        int   `json:"moved_matches"`
+	SlotChanges         int   `json:"slot_changes"`
+	UnchangedMatches    int   `json:"unchanged_matches"`
+	MovedMatchIDs       []int `json:"moved_match_ids"`
+	SlotChangedMatchIDs []int `json:"slot_changed_match_ids"`
+}
+
+// copyInt creates a copy of an int pointer
+func copyInt(ptr *int) *int {
+	if ptr == nil {
+		return nil
+	}
+	value := *ptr
+	return &value
+}
+
+// copyTime creates a copy of a time pointer
+func copyTime(ptr *time.Time) *time.Time {
+	if ptr == nil {
+		return nil
+	}
+	value := *ptr
+	return &value
+}
+
+// sameInt reports whether two int pointers hold the same value
+func sameInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// sameTime reports whether two time pointers hold the same instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
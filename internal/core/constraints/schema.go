@@ -0,0 +1,418 @@
+package constraints
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect the constraint schemas are written in
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema used to describe constraint
+// configuration, so front-ends can build forms and validate configs
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Const                interface{}            `json:"const,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"` // false or a *JSONSchema
+	PropertyNames        *JSONSchema            `json:"propertyNames,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+}
+
+// ConstraintTypeSchema is the schema for one constraint type's params, along
+// with whether it can be configured as a hard or soft constraint
+type ConstraintTypeSchema struct {
+	Hard   bool        `json:"hard"`
+	Soft   bool        `json:"soft"`
+	Params *JSONSchema `json:"params"`
+}
+
+// GetConstraintSchemas returns the params schema of every constraint type,
+// including those added through Register
+func GetConstraintSchemas() map[string]ConstraintTypeSchema {
+	typeInfo := GetConstraintTypeInfo()
+	schemas := make(map[string]ConstraintTypeSchema)
+
+	for name, params := range constraintParamSchemas() {
+		params.Title = name
+		params.Description = typeInfo[name].Description
+		schemas[name] = ConstraintTypeSchema{
+			Hard:   hardOnlyTypes[name] || bothTypes[name],
+			Soft:   !hardOnlyTypes[name],
+			Params: params,
+		}
+	}
+
+	// Registered types only document their params, so describe them loosely
+	for name, info := range registeredTypeInfo() {
+		properties := make(map[string]*JSONSchema, len(info.Parameters))
+		for param, description := range info.Parameters {
+			properties[param] = &JSONSchema{Description: description}
+		}
+		schemas[name] = ConstraintTypeSchema{
+			Hard:   info.Type == "hard",
+			Soft:   info.Type == "soft",
+			Params: &JSONSchema{Title: name, Description: info.Description, Type: "object", Properties: properties},
+		}
+	}
+
+	return schemas
+}
+
+// ConstraintConfigSchema returns a schema for a whole constraint
+// configuration, with the params of each entry checked against its type
+func ConstraintConfigSchema() *JSONSchema {
+	schemas := GetConstraintSchemas()
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var hardEntries, softEntries []*JSONSchema
+	for _, name := range names {
+		schema := schemas[name]
+		if schema.Hard {
+			hardEntries = append(hardEntries, configEntrySchema(name, schema.Params, false))
+		}
+		if schema.Soft {
+			softEntries = append(softEntries, configEntrySchema(name, schema.Params, true))
+		}
+	}
+
+	return &JSONSchema{
+		Schema:      JSONSchemaDraft,
+		Title:       "Constraint configuration",
+		Description: "Hard constraints must hold for a draw to be valid; soft constraints are weighted into its score",
+		Type:        "object",
+		Properties: map[string]*JSONSchema{
+			"hard": {Type: "array", Items: &JSONSchema{OneOf: hardEntries}},
+			"soft": {Type: "array", Items: &JSONSchema{OneOf: softEntries}},
+		},
+		AdditionalProperties: false,
+	}
+}
+
+// configEntrySchema describes one hard or soft entry of a given type
+func configEntrySchema(name string, params *JSONSchema, soft bool) *JSONSchema {
+	entry := &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"type":   {Const: name},
+			"params": params,
+		},
+		Required:             []string{"type"},
+		AdditionalProperties: false,
+	}
+	if soft {
+		entry.Properties["weight"] = numberSchema("Weight of the constraint in the draw score", 0, 1)
+		entry.Required = append(entry.Required, "weight")
+	}
+	return entry
+}
+
+// hardOnlyTypes can only be configured as hard constraints; bothTypes can be
+// either. Every other type is soft.
+var (
+	hardOnlyTypes = map[string]bool{
+		"venue_availability":    true,
+		"venue_quarantine":      true,
+		"bye_constraint":        true,
+		"team_availability":     true,
+		"double_up":             true,
+		"prime_time_cap":        true,
+		"venue_recovery":        true,
+		"bye_round_window":      true,
+		"magic_round":           true,
+		"shared_venue":          true,
+		"max_short_turnarounds": true,
+	}
+	bothTypes = map[string]bool{
+		"rivalry_round":     true,
+		"broadcaster_quota": true,
+		"region_spread":     true,
+		"rest_period":       true,
+		"custom_expression": true,
+	}
+)
+
+// constraintParamSchemas returns the params schema of each constraint type,
+// matching what the factory accepts
+func constraintParamSchemas() map[string]*JSONSchema {
+	appearances := map[string]*JSONSchema{
+		"min_appearances": integerSchema("Minimum appearances per team", 0),
+		"max_appearances": integerSchema("Maximum appearances per team", 0),
+	}
+	appearancesRequired := []*JSONSchema{{Required: []string{"min_appearances"}}, {Required: []string{"max_appearances"}}}
+	teamWeightsSchema := idMapSchema("Score weight keyed by team ID, so fairness for those teams counts more", positiveNumberSchema(""))
+
+	weekdays := make([]interface{}, 0, 14)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		weekdays = append(weekdays, name, name[:3])
+	}
+
+	return map[string]*JSONSchema{
+		"venue_availability": objectSchema(map[string]*JSONSchema{
+			"venue_id":          integerSchema("ID of the venue", 1),
+			"unavailable_dates": arraySchema("Dates the venue is unavailable", dateSchema(), 0),
+		}, "venue_id", "unavailable_dates"),
+		"venue_quarantine": objectSchema(map[string]*JSONSchema{
+			"venue_id": integerSchema("ID of the venue", 1),
+			"rounds":   arraySchema("Rounds the venue can't host a match", integerSchema("", 1), 1),
+			"reason":   stringSchema("Why the venue is unavailable"),
+		}, "venue_id", "rounds"),
+		"bye_constraint": objectSchema(nil),
+		"team_availability": objectSchema(map[string]*JSONSchema{
+			"team_id":           integerSchema("ID of the team", 1),
+			"unavailable_dates": arraySchema("Dates the team is unavailable", dateSchema(), 0),
+		}, "team_id", "unavailable_dates"),
+		"double_up": objectSchema(map[string]*JSONSchema{
+			"min_rounds_separation": integerSchema("Minimum rounds between same matchups", 0),
+		}, "min_rounds_separation"),
+		"prime_time_cap": withAnyOf(objectSchema(map[string]*JSONSchema{
+			"min_appearances": appearances["min_appearances"],
+			"max_appearances": appearances["max_appearances"],
+		}), appearancesRequired),
+		"venue_recovery": objectSchema(map[string]*JSONSchema{
+			"min_recovery_days":   integerSchema("Minimum days between events at any venue", 0),
+			"venue_recovery_days": idMapSchema("Venue-specific recovery days keyed by venue ID", integerSchema("", 0)),
+			"external_events": arraySchema("Imported non-fixture events", objectSchema(map[string]*JSONSchema{
+				"venue_id": integerSchema("ID of the venue", 1),
+				"date":     dateSchema(),
+				"name":     stringSchema("Name of the event"),
+			}, "venue_id", "date"), 0),
+		}, "min_recovery_days"),
+		"bye_round_window": objectSchema(map[string]*JSONSchema{
+			"bye_rounds": arraySchema("Rounds in which teams may have a bye", integerSchema("", 1), 1),
+		}, "bye_rounds"),
+		"rivalry_round": objectSchema(map[string]*JSONSchema{
+			"fixtures": arraySchema("Rivalry fixtures and their target rounds", objectSchema(map[string]*JSONSchema{
+				"team_a": integerSchema("ID of one team", 1),
+				"team_b": integerSchema("ID of the other team", 1),
+				"round":  integerSchema("Target round", 1),
+			}, "team_a", "team_b", "round"), 1),
+		}, "fixtures"),
+		"broadcaster_quota": withAnyOf(objectSchema(map[string]*JSONSchema{
+			"broadcaster":     stringSchema("Broadcaster holding the timeslots, used in reports"),
+			"days":            arraySchema("Days in the category", &JSONSchema{Type: "string", Enum: weekdays}, 1),
+			"from":            clockSchema("Earliest kickoff"),
+			"to":              clockSchema("Kickoffs must be before this time"),
+			"prime_time_only": booleanSchema("Only count matches marked prime time"),
+			"min_appearances": appearances["min_appearances"],
+			"max_appearances": appearances["max_appearances"],
+		}, "days"), appearancesRequired),
+		"region_spread": withAnyOf(objectSchema(map[string]*JSONSchema{
+			"team_regions":     idMapSchema("Region of each team's home games keyed by team ID", stringSchema("")),
+			"min_home_matches": integerSchema("Minimum home matches per region per round", 0),
+			"max_home_matches": integerSchema("Maximum home matches per region per round", 0),
+		}, "team_regions"), []*JSONSchema{{Required: []string{"min_home_matches"}}, {Required: []string{"max_home_matches"}}}),
+		"magic_round": objectSchema(map[string]*JSONSchema{
+			"round":        integerSchema("The magic round", 1),
+			"venue_id":     integerSchema("ID of the venue hosting every match in the round", 1),
+			"weekend_days": withDefault(integerSchema("Consecutive days the round may span", 1), DefaultMagicRoundDays),
+		}, "round", "venue_id"),
+		"max_short_turnarounds": objectSchema(map[string]*JSONSchema{
+			"max_turnarounds":       withDefault(integerSchema("Short turnarounds allowed per team across the season", 0), DefaultMaxShortTurnarounds),
+			"short_turnaround_days": withDefault(integerSchema("Most days between kickoffs that count as a short turnaround", 1), DefaultShortTurnaroundDays),
+			"long_haul_km":          withDefault(positiveNumberSchema("One-way trip, in kilometres, after which no short turnaround may follow"), geo.MaxShortFlightKm),
+		}),
+		"custom_expression": objectSchema(map[string]*JSONSchema{
+			"expression":  stringSchema("Condition every match must satisfy"),
+			"description": stringSchema("Description of the rule used in reports"),
+		}, "expression"),
+		"travel_minimization": withAnyOf(objectSchema(map[string]*JSONSchema{
+			"max_consecutive_away": integerSchema("Maximum consecutive away games allowed", 0),
+			"max_total_travel_km":  positiveNumberSchema("Season travel per team, in return-trip kilometres, before the score is penalized"),
+			"team_weights":         teamWeightsSchema,
+		}), []*JSONSchema{{Required: []string{"max_consecutive_away"}}, {Required: []string{"max_total_travel_km"}}}),
+		"travel_fatigue": objectSchema(map[string]*JSONSchema{
+			"max_consecutive_long_haul": withDefault(integerSchema("Long-haul trips allowed in a row before they're penalized", 0), DefaultMaxConsecutiveLongHaul),
+			"zone_shift_penalty":        withDefault(numberSchema("Extra penalty per hour of time zone shift on a penalized trip", 0, -1), DefaultZoneShiftPenalty),
+			"team_weights":              teamWeightsSchema,
+		}),
+		"travel_cost": objectSchema(map[string]*JSONSchema{
+			"budget":                   positiveNumberSchema("League travel budget for the season, in dollars"),
+			"drive_cost_per_km":        withDefault(numberSchema("Cost per kilometre of a drive", 0, -1), DefaultDriveCostPerKm),
+			"short_flight_cost_per_km": withDefault(numberSchema("Cost per kilometre of a short flight", 0, -1), DefaultShortFlightCostPerKm),
+			"long_flight_cost_per_km":  withDefault(numberSchema("Cost per kilometre of a long flight", 0, -1), DefaultLongFlightCostPerKm),
+			"accommodation_per_night":  withDefault(numberSchema("Cost of a night's accommodation for a team staying on the road", 0, -1), DefaultAccommodationPerNight),
+			"stay_away_rest_days":      withDefault(integerSchema("Teams with fewer rest days than this between flights stay on the road", 0), DefaultStayAwayRestDays),
+		}, "budget"),
+		"half_season_travel": objectSchema(map[string]*JSONSchema{
+			"split_round":        integerSchema("Last round of the first half, half the season rounded down when omitted", 1),
+			"away_tolerance":     withDefault(integerSchema("Away games one half may have over the other before it's penalized", 0), DefaultHalfSeasonAwayTolerance),
+			"distance_tolerance": withDefault(numberSchema("Gap between the halves' travel, as a share of the team's season travel, before it's penalized", 0, 1), DefaultHalfSeasonDistanceTolerance),
+			"team_weights":       teamWeightsSchema,
+		}),
+		"rest_period": withAnyOf(objectSchema(map[string]*JSONSchema{
+			"min_rest_days":         integerSchema("Minimum rest days between matches", 0),
+			"max_short_turnarounds": integerSchema("Maximum short turnarounds per team across the season", 0),
+			"short_turnaround_days": integerSchema("Most days between kickoffs that count as a short turnaround", 1),
+			"team_weights":          teamWeightsSchema,
+		}), []*JSONSchema{{Required: []string{"min_rest_days"}}, {Required: []string{"max_short_turnarounds"}}}),
+		"prime_time_spread": objectSchema(map[string]*JSONSchema{
+			"target_ratio":  numberSchema("Target ratio of prime time games", 0, 1),
+			"max_deviation": numberSchema("Maximum allowed deviation from target", 0, -1),
+			"team_weights":  teamWeightsSchema,
+		}, "target_ratio", "max_deviation"),
+		"home_away_balance": objectSchema(map[string]*JSONSchema{
+			"max_deviation": numberSchema("Maximum deviation from 50/50 balance", 0, -1),
+			"team_weights":  teamWeightsSchema,
+		}, "max_deviation"),
+		"prime_time_attractiveness": objectSchema(map[string]*JSONSchema{
+			"rivalry_weights": matchupWeightsSchema("Base matchup weights keyed by \"teamA-teamB\""),
+			"results": arraySchema("Historical results", objectSchema(map[string]*JSONSchema{
+				"season":       integerSchema("Season year", 0),
+				"round":        integerSchema("Round of the result", 0),
+				"home_team_id": integerSchema("ID of the home team", 1),
+				"away_team_id": integerSchema("ID of the away team", 1),
+				"home_score":   integerSchema("Home team score", 0),
+				"away_score":   integerSchema("Away team score", 0),
+			}, "season", "home_team_id", "away_team_id", "home_score", "away_score"), 0),
+			"ladder":             idMapSchema("Ladder position keyed by team ID", integerSchema("", 1)),
+			"close_margin":       withDefault(integerSchema("Margin counted as a close finish", 0), DefaultRivalryWeightOptions().CloseMargin),
+			"close_finish_boost": withDefault(&JSONSchema{Type: "number", Description: "Weight boost per close finish in the latest season"}, DefaultRivalryWeightOptions().CloseFinishBoost),
+			"ladder_boost":       withDefault(&JSONSchema{Type: "number", Description: "Weight boost for teams adjacent on the ladder"}, DefaultRivalryWeightOptions().LadderBoost),
+			"freeze_weights":     booleanSchema("Fix computed weights when the draw is created for reproducibility"),
+			"frozen_weights":     matchupWeightsSchema("Weights fixed when the draw was created, set by the server"),
+		}),
+		"expected_crowd": objectSchema(map[string]*JSONSchema{
+			"team_popularity":  idMapSchema("Crowd-drawing weight keyed by team ID", numberSchema("", 0, -1)),
+			"reference_crowd":  withDefault(positiveNumberSchema("Expected crowd when two teams of popularity 1.0 meet outside prime time"), DefaultReferenceCrowd),
+			"prime_time_boost": withDefault(numberSchema("Fractional lift in demand for prime-time matches", 0, -1), DefaultPrimeTimeBoost),
+		}),
+		"home_venue_share": objectSchema(nil),
+		"derby": objectSchema(map[string]*JSONSchema{
+			"max_distance_km": positiveNumberSchema("Greatest distance between two teams' home locations for their matches to be derbies"),
+			"marquee_rounds":  arraySchema("Rounds to schedule derbies in, spreading them across the season when empty", integerSchema("", 1), 0),
+		}, "max_distance_km"),
+		"shared_venue": objectSchema(map[string]*JSONSchema{
+			"min_gap_minutes": withDefault(integerSchema("Minutes between kickoffs at a shared venue on the same day", 0), int(DefaultSharedVenueGap.Minutes())),
+		}),
+		"strength_spread": objectSchema(map[string]*JSONSchema{
+			"team_strengths":      idMapSchema("Strength rating keyed by team ID, higher is stronger", &JSONSchema{Type: "number"}),
+			"top_teams":           withDefault(integerSchema("How many of the highest rated teams are tough opponents", 1), DefaultStrengthTopTeams),
+			"window_rounds":       withDefault(integerSchema("Run of rounds tough fixtures are counted over", 1), DefaultStrengthWindowRounds),
+			"max_tough_in_window": withDefault(integerSchema("Tough fixtures allowed in any window before they're penalized", 0), DefaultMaxToughInWindow),
+			"team_weights":        teamWeightsSchema,
+		}, "team_strengths"),
+		"double_header": objectSchema(map[string]*JSONSchema{
+			"partner_draw_id": integerSchema("Draw whose matches are played as curtain raisers", 1),
+		}, "partner_draw_id"),
+		"fixture_variation": objectSchema(map[string]*JSONSchema{
+			"previous_draw_id": integerSchema("Last season's draw to compare against", 1),
+		}, "previous_draw_id"),
+	}
+}
+
+// objectSchema describes a closed object with the given properties
+func objectSchema(properties map[string]*JSONSchema, required ...string) *JSONSchema {
+	return &JSONSchema{
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: false,
+	}
+}
+
+// integerSchema describes an integer of at least minimum
+func integerSchema(description string, minimum float64) *JSONSchema {
+	return &JSONSchema{Type: "integer", Description: description, Minimum: &minimum}
+}
+
+// numberSchema describes a number of at least minimum, and at most maximum
+// unless maximum is negative
+func numberSchema(description string, minimum, maximum float64) *JSONSchema {
+	schema := &JSONSchema{Type: "number", Description: description, Minimum: &minimum}
+	if maximum >= 0 {
+		schema.Maximum = &maximum
+	}
+	return schema
+}
+
+// positiveNumberSchema describes a number greater than zero
+func positiveNumberSchema(description string) *JSONSchema {
+	zero := 0.0
+	return &JSONSchema{Type: "number", Description: description, ExclusiveMinimum: &zero}
+}
+
+// stringSchema describes a free-text string
+func stringSchema(description string) *JSONSchema {
+	return &JSONSchema{Type: "string", Description: description}
+}
+
+// booleanSchema describes a flag
+func booleanSchema(description string) *JSONSchema {
+	return &JSONSchema{Type: "boolean", Description: description}
+}
+
+// dateSchema describes a YYYY-MM-DD date
+func dateSchema() *JSONSchema {
+	return &JSONSchema{Type: "string", Format: "date", Description: "Date in YYYY-MM-DD format"}
+}
+
+// clockSchema describes an HH:MM kickoff time
+func clockSchema(description string) *JSONSchema {
+	return &JSONSchema{Type: "string", Pattern: `^([01][0-9]|2[0-3]):[0-5][0-9]$`, Description: description + " in HH:MM format"}
+}
+
+// arraySchema describes an array of at least minItems items
+func arraySchema(description string, items *JSONSchema, minItems int) *JSONSchema {
+	schema := &JSONSchema{Type: "array", Description: description, Items: items}
+	if minItems > 0 {
+		schema.MinItems = &minItems
+	}
+	return schema
+}
+
+// idMapSchema describes an object keyed by team or venue ID
+func idMapSchema(description string, values *JSONSchema) *JSONSchema {
+	return &JSONSchema{
+		Type:                 "object",
+		Description:          description,
+		PropertyNames:        &JSONSchema{Pattern: `^[0-9]+$`},
+		AdditionalProperties: values,
+	}
+}
+
+// matchupWeightsSchema describes non-negative weights keyed by "teamA-teamB"
+func matchupWeightsSchema(description string) *JSONSchema {
+	return &JSONSchema{
+		Type:                 "object",
+		Description:          description,
+		PropertyNames:        &JSONSchema{Pattern: `^[0-9]+-[0-9]+$`},
+		AdditionalProperties: numberSchema("", 0, -1),
+	}
+}
+
+// withDefault sets the value the factory uses when a param is omitted
+func withDefault(schema *JSONSchema, value interface{}) *JSONSchema {
+	schema.Default = value
+	return schema
+}
+
+// withAnyOf requires the object to match at least one of the alternatives,
+// for params where one of several fields must be set
+func withAnyOf(schema *JSONSchema, alternatives []*JSONSchema) *JSONSchema {
+	schema.AnyOf = alternatives
+	return schema
+}
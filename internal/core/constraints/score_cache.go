@@ -0,0 +1,221 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// TeamDeltaScorer is implemented by soft constraints whose score is the
+// average of per-team scores, weighted when it is TeamWeighted, where a
+// team's score depends only on its own matches. After a change only the teams
+// it touches need rescoring.
+type TeamDeltaScorer interface {
+	TeamScorer
+	ScoreTeam(draw *models.Draw, teamID int) float64
+}
+
+// ScoreCache memoizes the per-team sub-scores of soft constraints
+// implementing TeamDeltaScorer, keyed by constraint and team, for an
+// optimizer changing one draw in place. When a match changes, Invalidate
+// marks the teams playing in it dirty, and Rescore recomputes only their
+// sub-scores; every other team's is served from the cache. Other soft
+// constraints and all hard constraints are evaluated in full.
+//
+// Rescored entries stay pending until Commit keeps them or Discard restores
+// the previous ones, so a rejected move is undone without rescoring.
+type ScoreCache struct {
+	engine     *ConstraintEngine
+	score      float64
+	scores     []float64         // per soft constraint, as committed
+	feasible   bool              // whether the committed draw breaks no hard constraint
+	teamScores []map[int]float64 // per soft constraint, nil unless it is a TeamDeltaScorer
+	dirty      map[int]bool
+	// previous holds the committed scores of entries rescored since the last
+	// Commit or Discard, keyed by constraint and team
+	previous        map[teamScoreKey]cachedScore
+	pending         float64
+	pendingScores   []float64
+	pendingFeasible bool
+	stats           CacheStats
+}
+
+// teamScoreKey identifies one team's sub-score for one soft constraint
+type teamScoreKey struct {
+	constraint int
+	team       int
+}
+
+// cachedScore is a cache entry, which may not have existed
+type cachedScore struct {
+	score   float64
+	present bool
+}
+
+// CacheStats counts how a ScoreCache served team sub-scores
+type CacheStats struct {
+	// Hits is how many sub-scores were reused from the cache
+	Hits int `json:"hits"`
+	// Misses is how many sub-scores were computed, including filling the cache
+	Misses int `json:"misses"`
+	// Invalidations is how many times a team was marked dirty
+	Invalidations int     `json:"invalidations"`
+	HitRate       float64 `json:"hit_rate"`
+}
+
+// Add returns the combined counts of two sets of stats
+func (cs CacheStats) Add(other CacheStats) CacheStats {
+	total := CacheStats{
+		Hits:          cs.Hits + other.Hits,
+		Misses:        cs.Misses + other.Misses,
+		Invalidations: cs.Invalidations + other.Invalidations,
+	}
+	if lookups := total.Hits + total.Misses; lookups > 0 {
+		total.HitRate = float64(total.Hits) / float64(lookups)
+	}
+	return total
+}
+
+// NewScoreCache scores a draw in full and caches every team's sub-scores
+func (ce *ConstraintEngine) NewScoreCache(draw *models.Draw) *ScoreCache {
+	cache := &ScoreCache{engine: ce}
+	cache.fill(draw)
+	return cache
+}
+
+// fill scores the draw in full, replacing the cache
+func (sc *ScoreCache) fill(draw *models.Draw) {
+	sc.teamScores = make([]map[int]float64, len(sc.engine.softConstraints))
+	sc.dirty = make(map[int]bool)
+	sc.previous = make(map[teamScoreKey]cachedScore)
+
+	index := NewDrawIndex(draw)
+	scores := make([]float64, len(sc.engine.softConstraints))
+	for i, weighted := range sc.engine.softConstraints {
+		if scorer, ok := weighted.Constraint.(TeamDeltaScorer); ok {
+			sc.teamScores[i] = TeamScoresWith(scorer, index)
+			sc.stats.Misses += len(sc.teamScores[i])
+			scores[i] = averageTeamScores(weighted.Constraint, sc.teamScores[i])
+		} else {
+			scores[i] = scoreWith(weighted.Constraint, index)
+		}
+	}
+
+	sc.score, sc.feasible = sc.engine.combineFeasibleScores(index, scores)
+	sc.scores = scores
+	sc.pending, sc.pendingScores, sc.pendingFeasible = sc.score, sc.scores, sc.feasible
+}
+
+// Score returns the committed draw score
+func (sc *ScoreCache) Score() float64 {
+	return sc.score
+}
+
+// Invalidate marks the teams playing in the changed matches dirty. Every
+// team a change affects must appear in one of them; moving, swapping or
+// flipping matches satisfies this.
+func (sc *ScoreCache) Invalidate(changed []*models.Match) {
+	for _, match := range changed {
+		for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+			if teamID == nil {
+				continue
+			}
+			sc.dirty[*teamID] = true
+			sc.stats.Invalidations++
+		}
+	}
+}
+
+// Rescore scores the draw after the changes passed to Invalidate, rescoring
+// only dirty teams. The new score is pending until Commit or Discard.
+func (sc *ScoreCache) Rescore(draw *models.Draw) float64 {
+	// A cache from before constraints were added can't be reused
+	if len(sc.teamScores) != len(sc.engine.softConstraints) {
+		sc.fill(draw)
+		return sc.score
+	}
+
+	index := NewDrawIndex(draw)
+	scores := make([]float64, len(sc.engine.softConstraints))
+	for i, weighted := range sc.engine.softConstraints {
+		scorer, ok := weighted.Constraint.(TeamDeltaScorer)
+		if !ok || sc.teamScores[i] == nil {
+			scores[i] = scoreWith(weighted.Constraint, index)
+			continue
+		}
+
+		cached, rescored := len(sc.teamScores[i]), 0
+		for teamID := range sc.dirty {
+			key := teamScoreKey{constraint: i, team: teamID}
+			if _, saved := sc.previous[key]; !saved {
+				score, present := sc.teamScores[i][teamID]
+				sc.previous[key] = cachedScore{score: score, present: present}
+			}
+			if _, present := sc.teamScores[i][teamID]; present {
+				rescored++
+			}
+			sc.teamScores[i][teamID] = scoreTeamWith(scorer, index, teamID)
+			sc.stats.Misses++
+		}
+		sc.stats.Hits += cached - rescored
+		scores[i] = averageTeamScores(weighted.Constraint, sc.teamScores[i])
+	}
+
+	clear(sc.dirty)
+	sc.pending, sc.pendingFeasible = sc.engine.combineFeasibleScores(index, scores)
+	sc.pendingScores = scores
+	return sc.pending
+}
+
+// Commit keeps the entries and score of the last Rescore
+func (sc *ScoreCache) Commit() {
+	sc.score, sc.scores, sc.feasible = sc.pending, sc.pendingScores, sc.pendingFeasible
+	clear(sc.previous)
+}
+
+// Discard restores the entries and score from before the last Commit, for
+// when the changes have been undone
+func (sc *ScoreCache) Discard() {
+	for key, entry := range sc.previous {
+		if entry.present {
+			sc.teamScores[key.constraint][key.team] = entry.score
+		} else {
+			delete(sc.teamScores[key.constraint], key.team)
+		}
+	}
+	sc.pending, sc.pendingScores, sc.pendingFeasible = sc.score, sc.scores, sc.feasible
+	clear(sc.dirty)
+	clear(sc.previous)
+}
+
+// ObjectiveScores scores the draw of the last Rescore on each objective, in
+// the order given, and reports whether it breaks no hard constraint
+func (sc *ScoreCache) ObjectiveScores(objectives []Objective) ([]float64, bool) {
+	return sc.engine.objectiveScores(sc.pendingScores, objectives), sc.pendingFeasible
+}
+
+// Stats returns the cache's hit and miss counts so far
+func (sc *ScoreCache) Stats() CacheStats {
+	return sc.stats.Add(CacheStats{})
+}
+
+// combineFeasibleScores applies the same rules as ScoreDraw to precomputed
+// soft constraint scores: any hard violation scores 0, otherwise the weighted
+// mean. It also reports whether the draw breaks no hard constraint.
+func (ce *ConstraintEngine) combineFeasibleScores(index *DrawIndex, scores []float64) (float64, bool) {
+	if violations := ce.validateDraw(index); len(violations) > 0 {
+		return 0.0, false
+	}
+
+	var totalScore float64
+	var totalWeight float64
+
+	for i, weighted := range ce.softConstraints {
+		totalScore += scores[i] * weighted.Weight
+		totalWeight += weighted.Weight
+	}
+
+	if totalWeight == 0 {
+		return 1.0, true
+	}
+
+	return totalScore / totalWeight, true
+}
@@ -0,0 +1,146 @@
+package constraints
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// CalendarAware is implemented by constraints that date matches from the
+// season calendar when they have no kickoff of their own
+type CalendarAware interface {
+	SetCalendar(calendar *models.SeasonCalendar)
+}
+
+// CalendarReader finds the season calendar a draw is played to
+type CalendarReader interface {
+	GetForSeason(ctx context.Context, seasonYear int, competitionID *int) (*models.SeasonCalendar, error)
+}
+
+// RoundCalendarConstraint keeps every kickoff within the dates the season
+// calendar gives its round. The engine adds it whenever a calendar is set.
+// Rounds the calendar doesn't date, and undated matches, aren't checked.
+type RoundCalendarConstraint struct {
+	BaseConstraint
+	calendar *models.SeasonCalendar
+}
+
+// NewRoundCalendarConstraint creates a constraint for the calendar's rounds
+func NewRoundCalendarConstraint(calendar *models.SeasonCalendar) *RoundCalendarConstraint {
+	return &RoundCalendarConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"RoundCalendar",
+			"Matches must be played within their round's dates in the season calendar",
+			true, // This is a hard constraint
+		),
+		calendar: calendar,
+	}
+}
+
+// Validate checks the match is dated within its round
+func (rcc *RoundCalendarConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	if match.IsBye() || match.MatchDate == nil {
+		return nil
+	}
+	if _, dated := rcc.calendar.RoundStart(match.Round); !dated {
+		return nil
+	}
+	if !rcc.calendar.InRound(match.Round, *match.MatchDate) {
+		return fmt.Errorf("round %d match is dated %s, outside the round's calendar dates",
+			match.Round, match.MatchDate.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// Score returns the fraction of checked matches dated within their round
+func (rcc *RoundCalendarConstraint) Score(draw *models.Draw) float64 {
+	checked, within := 0, 0
+	for _, match := range draw.Matches {
+		if match.IsBye() || match.MatchDate == nil {
+			continue
+		}
+		if _, dated := rcc.calendar.RoundStart(match.Round); !dated {
+			continue
+		}
+		checked++
+		if rcc.calendar.InRound(match.Round, *match.MatchDate) {
+			within++
+		}
+	}
+	if checked == 0 {
+		return 1.0
+	}
+	return float64(within) / float64(checked)
+}
+
+// GetCalendar returns the calendar kickoffs are checked against
+func (rcc *RoundCalendarConstraint) GetCalendar() *models.SeasonCalendar {
+	return rcc.calendar
+}
+
+// SetCalendar hands the season calendar to every constraint that dates
+// matches from it, and keeps kickoffs within their round's dates. A nil
+// calendar clears it.
+func (ce *ConstraintEngine) SetCalendar(calendar *models.SeasonCalendar) {
+	ce.calendar = calendar
+
+	hard := make([]Constraint, 0, len(ce.hardConstraints)+1)
+	for _, constraint := range ce.hardConstraints {
+		if _, rounds := constraint.(*RoundCalendarConstraint); !rounds {
+			hard = append(hard, constraint)
+		}
+	}
+	if calendar != nil {
+		hard = append(hard, NewRoundCalendarConstraint(calendar))
+	}
+	ce.hardConstraints = hard
+
+	for _, constraint := range ce.hardConstraints {
+		if aware, ok := constraint.(CalendarAware); ok {
+			aware.SetCalendar(calendar)
+		}
+	}
+	for _, weighted := range ce.softConstraints {
+		if aware, ok := weighted.Constraint.(CalendarAware); ok {
+			aware.SetCalendar(calendar)
+		}
+	}
+}
+
+// Calendar returns the season calendar the engine dates matches from, or nil
+func (ce *ConstraintEngine) Calendar() *models.SeasonCalendar {
+	return ce.calendar
+}
+
+// LoadCalendar reads the season calendar for the draw's season and
+// competition and sets it on the engine. A draw without a calendar leaves
+// the engine without one.
+func (ce *ConstraintEngine) LoadCalendar(ctx context.Context, calendars CalendarReader, draw *models.Draw) error {
+	calendar, err := calendars.GetForSeason(ctx, draw.SeasonYear, draw.CompetitionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			ce.SetCalendar(nil)
+			return nil
+		}
+		return fmt.Errorf("failed to load season calendar: %w", err)
+	}
+	ce.SetCalendar(calendar)
+	return nil
+}
+
+// calendarDate returns when a match is played: its kickoff date, or the first
+// day of its round in the calendar when it has none. Reports false when
+// neither is known.
+func calendarDate(calendar *models.SeasonCalendar, match *models.Match) (time.Time, bool) {
+	if match.MatchDate != nil {
+		return *match.MatchDate, true
+	}
+	if calendar == nil {
+		return time.Time{}, false
+	}
+	return calendar.RoundStart(match.Round)
+}
@@ -0,0 +1,175 @@
+package constraints
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DefaultSharedVenueGap is how far apart kickoffs at a venue shared with
+// another competition must be by default, enough for a curtain raiser
+const DefaultSharedVenueGap = MinStackedKickoffGap
+
+// SharedVenueConstraint stops a draw booking a venue at the same time as
+// another competition's completed draw, such as the NRLW's matches clashing
+// with the NRL's. Kickoffs at the same venue on the same day must be at least
+// the minimum gap apart; matches without a kickoff can't clash yet.
+//
+// Draws in the same competition are alternatives for one season rather than
+// fixtures played alongside each other, so they are never checked against
+// each other. Active draws are read from storage, so clashes are only found
+// once they have been supplied.
+type SharedVenueConstraint struct {
+	BaseConstraint
+	minGap time.Duration
+	// bookings holds the other draws' timed matches, keyed by venue and day
+	bookings map[string][]sharedBooking
+}
+
+// sharedBooking is another draw's match at a venue
+type sharedBooking struct {
+	draw    *models.Draw
+	match   *models.Match
+	kickoff time.Duration
+}
+
+// SharedVenueConflict describes a match kicking off too close to another
+// draw's match at the same venue
+type SharedVenueConflict struct {
+	VenueID      int       `json:"venue_id"`
+	MatchID      int       `json:"match_id"`
+	OtherDrawID  int       `json:"other_draw_id"`
+	OtherMatchID int       `json:"other_match_id"`
+	Date         time.Time `json:"date"`
+	GapMinutes   int       `json:"gap_minutes"`
+}
+
+// NewSharedVenueConstraint creates a new shared-venue constraint requiring
+// kickoffs at least minGap apart
+func NewSharedVenueConstraint(minGap time.Duration) *SharedVenueConstraint {
+	return &SharedVenueConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"SharedVenue",
+			fmt.Sprintf("Matches must kick off at least %d minutes from other competitions' matches at the same venue", int(minGap.Minutes())),
+			true, // This is a hard constraint
+		),
+		minGap:   minGap,
+		bookings: make(map[string][]sharedBooking),
+	}
+}
+
+// SetActiveDraws records the timed matches of the draws to check against
+func (svc *SharedVenueConstraint) SetActiveDraws(draws []*models.Draw) {
+	svc.bookings = make(map[string][]sharedBooking)
+	for _, draw := range draws {
+		for _, match := range draw.Matches {
+			key, ok := venueDayKey(match)
+			if !ok || match.MatchTime == nil {
+				continue
+			}
+			svc.bookings[key] = append(svc.bookings[key], sharedBooking{
+				draw:    draw,
+				match:   match,
+				kickoff: kickoffClock(*match.MatchTime),
+			})
+		}
+	}
+}
+
+// Validate checks if a match clashes with another competition's match
+func (svc *SharedVenueConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	conflict := svc.findConflict(match, draw)
+	if conflict == nil {
+		return nil
+	}
+
+	return fmt.Errorf("venue %d is booked by match %d of draw %d %d minutes from match %d, needing %d",
+		conflict.VenueID, conflict.OtherMatchID, conflict.OtherDrawID, conflict.GapMinutes, match.ID, int(svc.minGap.Minutes()))
+}
+
+// Score returns the fraction of timed venue bookings that don't clash with
+// another competition's
+func (svc *SharedVenueConstraint) Score(draw *models.Draw) float64 {
+	totalMatches := 0
+	violatingMatches := 0
+
+	for _, match := range draw.Matches {
+		if _, ok := venueDayKey(match); !ok || match.MatchTime == nil {
+			continue
+		}
+
+		totalMatches++
+		if svc.findConflict(match, draw) != nil {
+			violatingMatches++
+		}
+	}
+
+	if totalMatches == 0 {
+		return 1.0
+	}
+
+	return float64(totalMatches-violatingMatches) / float64(totalMatches)
+}
+
+// GetConflicts returns every match that clashes with another competition's
+func (svc *SharedVenueConstraint) GetConflicts(draw *models.Draw) []SharedVenueConflict {
+	var conflicts []SharedVenueConflict
+
+	for _, match := range draw.Matches {
+		if conflict := svc.findConflict(match, draw); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+
+	return conflicts
+}
+
+// findConflict returns the closest clashing booking for a match
+func (svc *SharedVenueConstraint) findConflict(match *models.Match, draw *models.Draw) *SharedVenueConflict {
+	key, ok := venueDayKey(match)
+	if !ok || match.MatchTime == nil {
+		return nil
+	}
+
+	kickoff := kickoffClock(*match.MatchTime)
+	var closest *SharedVenueConflict
+	for _, booking := range svc.bookings[key] {
+		if booking.draw.ID == draw.ID || SameCompetition(booking.draw, draw) {
+			continue
+		}
+
+		gap := kickoff - booking.kickoff
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap >= svc.minGap {
+			continue
+		}
+		if closest == nil || int(gap.Minutes()) < closest.GapMinutes {
+			closest = &SharedVenueConflict{
+				VenueID:      *match.VenueID,
+				MatchID:      match.ID,
+				OtherDrawID:  booking.draw.ID,
+				OtherMatchID: booking.match.ID,
+				Date:         *match.MatchDate,
+				GapMinutes:   int(gap.Minutes()),
+			}
+		}
+	}
+	return closest
+}
+
+// SameCompetition reports whether two draws are for the same competition.
+// Draws without one are treated as one competition.
+func SameCompetition(a, b *models.Draw) bool {
+	if a.CompetitionID == nil || b.CompetitionID == nil {
+		return a.CompetitionID == nil && b.CompetitionID == nil
+	}
+	return *a.CompetitionID == *b.CompetitionID
+}
+
+// GetMinGap returns how far apart kickoffs at a shared venue must be
+func (svc *SharedVenueConstraint) GetMinGap() time.Duration {
+	return svc.minGap
+}
@@ -0,0 +1,169 @@
+package constraints
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DefaultMaxShortTurnarounds is the CBA's cap of two short turnarounds per
+// team per season
+const DefaultMaxShortTurnarounds = 2
+
+// ShortTurnaroundCapConstraint enforces the CBA's short turnaround rules: no
+// team may have more than the cap of short turnarounds across the season, and
+// none may come straight after a long-haul trip. Trips are measured one way
+// from the team's base, so a home game moved far away counts too. Only the
+// turnarounds beyond the cap are flagged, the earliest are kept.
+//
+// Matches without a kickoff date aren't checked, so a draw being dated round
+// by round is judged on the rounds dated so far. Trips are located from league
+// data, so the long-haul rule doesn't apply until it's supplied.
+type ShortTurnaroundCapConstraint struct {
+	BaseConstraint
+	maxTurnarounds      int
+	shortTurnaroundDays int // Turnarounds of this many days or fewer between kickoffs are short
+	longHaulKm          float64
+	league              *LeagueData
+}
+
+// NewShortTurnaroundCapConstraint creates a short turnaround cap allowing
+// maxTurnarounds five-day turnarounds per team, none after a trip beyond
+// the longest short flight
+func NewShortTurnaroundCapConstraint(maxTurnarounds int) *ShortTurnaroundCapConstraint {
+	return &ShortTurnaroundCapConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"ShortTurnaroundCap",
+			"No team may exceed its short turnarounds or have one after a long-haul trip",
+			true, // This is a hard constraint
+		),
+		maxTurnarounds:      maxTurnarounds,
+		shortTurnaroundDays: DefaultShortTurnaroundDays,
+		longHaulKm:          geo.MaxShortFlightKm,
+	}
+}
+
+// Validate checks the turnaround into a match for both teams
+func (stc *ShortTurnaroundCapConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	return stc.ValidateIndexed(match, NewDrawIndex(draw))
+}
+
+// ValidateIndexed checks the turnaround into a match using a shared index
+func (stc *ShortTurnaroundCapConstraint) ValidateIndexed(match *models.Match, index *DrawIndex) error {
+	if match.IsBye() || match.MatchDate == nil {
+		return nil
+	}
+
+	for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+		if teamID == nil {
+			continue
+		}
+		if err, breached := stc.breaches(index, *teamID)[match.ID]; breached {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Score returns the fraction of teams within the short turnaround rules
+func (stc *ShortTurnaroundCapConstraint) Score(draw *models.Draw) float64 {
+	return stc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed scores the teams' turnarounds using a shared index
+func (stc *ShortTurnaroundCapConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
+	if len(teams) == 0 {
+		return 1.0
+	}
+
+	withinRules := 0
+	for _, teamID := range teams {
+		if len(stc.breaches(index, teamID)) == 0 {
+			withinRules++
+		}
+	}
+	return float64(withinRules) / float64(len(teams))
+}
+
+// breaches returns the team's short turnarounds that break the rules, keyed
+// by the ID of the match the team turns around into
+func (stc *ShortTurnaroundCapConstraint) breaches(index *DrawIndex, teamID int) map[int]error {
+	breaches := make(map[int]error)
+	shortTurnarounds := 0
+	matches := datedTeamMatches(index, teamID)
+	for i := 1; i < len(matches); i++ {
+		previous, current := matches[i-1], matches[i]
+		if !stc.isShortTurnaround(*previous.MatchDate, *current.MatchDate) {
+			continue
+		}
+		shortTurnarounds++
+
+		if km, ok := stc.tripKm(previous, teamID); ok && km > stc.longHaulKm {
+			breaches[current.ID] = fmt.Errorf("team %d has a short turnaround into round %d after a %.0fkm trip in round %d",
+				teamID, current.Round, km, previous.Round)
+		} else if shortTurnarounds > stc.maxTurnarounds {
+			breaches[current.ID] = fmt.Errorf("team %d exceeds maximum of %d short turnarounds in round %d",
+				teamID, stc.maxTurnarounds, current.Round)
+		}
+	}
+	return breaches
+}
+
+// SetLeagueData supplies the team and venue coordinates trips are measured with
+func (stc *ShortTurnaroundCapConstraint) SetLeagueData(data *LeagueData) {
+	stc.league = data
+}
+
+// GetMaxTurnarounds returns the short turnarounds allowed per team
+func (stc *ShortTurnaroundCapConstraint) GetMaxTurnarounds() int {
+	return stc.maxTurnarounds
+}
+
+// SetShortTurnaroundDays sets the most days between kickoffs that count as
+// a short turnaround
+func (stc *ShortTurnaroundCapConstraint) SetShortTurnaroundDays(days int) {
+	stc.shortTurnaroundDays = days
+}
+
+// SetLongHaulKm sets the one-way trip beyond which no short turnaround may follow
+func (stc *ShortTurnaroundCapConstraint) SetLongHaulKm(km float64) {
+	stc.longHaulKm = km
+}
+
+// isShortTurnaround reports whether two kickoffs are close enough together
+// to count as a short turnaround
+func (stc *ShortTurnaroundCapConstraint) isShortTurnaround(from, to time.Time) bool {
+	return int(to.Sub(from).Hours()/24) <= stc.shortTurnaroundDays
+}
+
+// tripKm returns how far a team travels one way to play a match
+func (stc *ShortTurnaroundCapConstraint) tripKm(match *models.Match, teamID int) (float64, bool) {
+	baseLat, baseLon, ok := stc.league.TeamHomeLocation(teamID)
+	if !ok {
+		return 0, false
+	}
+	venueLat, venueLon, ok := stc.league.MatchLocation(match)
+	if !ok {
+		return 0, false
+	}
+	return geo.HaversineKm(baseLat, baseLon, venueLat, venueLon), true
+}
+
+// datedTeamMatches returns a team's matches with kickoff dates, earliest first
+func datedTeamMatches(index *DrawIndex, teamID int) []*models.Match {
+	var matches []*models.Match
+	for _, match := range index.TeamMatches(teamID) {
+		if match.MatchDate != nil {
+			matches = append(matches, match)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].MatchDate.Before(*matches[j].MatchDate)
+	})
+	return matches
+}
@@ -0,0 +1,109 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DrawStatistics bundles the analyses of the constraints an engine is
+// configured with. An analysis is left out when the engine has no constraint
+// to run it.
+type DrawStatistics struct {
+	HomeAway          *HomeAwayReport          `json:"home_away,omitempty"`
+	Rest              *RestReport              `json:"rest,omitempty"`
+	PrimeTime         *PrimeTimeReport         `json:"prime_time,omitempty"`
+	Travel            *TravelReport            `json:"travel,omitempty"`
+	HalfSeasonTravel  *HalfSeasonTravelReport  `json:"half_season_travel,omitempty"`
+	StrengthSpread    *StrengthSpreadReport    `json:"strength_spread,omitempty"`
+	BroadcasterQuotas []BroadcasterQuotaReport `json:"broadcaster_quotas,omitempty"`
+	RegionSpreads     []RegionSpreadReport     `json:"region_spreads,omitempty"`
+	Derbies           []DerbyAnalysis          `json:"derbies,omitempty"`
+}
+
+// HomeAwayReport is the draw's home/away balance, overall and by team
+type HomeAwayReport struct {
+	Statistics HomeAwayStatistics `json:"statistics"`
+	Teams      []HomeAwayAnalysis `json:"teams"`
+}
+
+// RestReport is the rest between matches, overall and by team
+type RestReport struct {
+	MinRestDays int                  `json:"min_rest_days"`
+	Statistics  RestStatistics       `json:"statistics"`
+	Teams       []RestPeriodAnalysis `json:"teams"`
+}
+
+// PrimeTimeReport is how prime time is shared, overall and by team
+type PrimeTimeReport struct {
+	Statistics PrimeTimeStatistics `json:"statistics"`
+	Teams      []PrimeTimeAnalysis `json:"teams"`
+}
+
+// TravelReport is the draw's travel, overall and by team
+type TravelReport struct {
+	Statistics TravelStatistics `json:"statistics"`
+	Teams      []TravelAnalysis `json:"teams"`
+}
+
+// Statistics runs every analysis the engine's constraints offer over the
+// draw. Where a constraint is configured both soft and hard, the soft one's
+// thresholds are used. Travel and rest use the league data and season
+// calendar the engine was given.
+func (ce *ConstraintEngine) Statistics(draw *models.Draw) DrawStatistics {
+	stats := DrawStatistics{
+		BroadcasterQuotas: ce.AnalyzeBroadcasterQuotas(draw),
+		RegionSpreads:     ce.AnalyzeRegionSpread(draw),
+		Derbies:           ce.AnalyzeDerbies(draw),
+	}
+
+	configured := make([]Constraint, 0, len(ce.softConstraints)+len(ce.hardConstraints))
+	for _, weighted := range ce.softConstraints {
+		configured = append(configured, weighted.Constraint)
+	}
+	configured = append(configured, ce.hardConstraints...)
+
+	for _, constraint := range configured {
+		switch c := constraint.(type) {
+		case *HomeAwayBalanceConstraint:
+			if stats.HomeAway == nil {
+				stats.HomeAway = &HomeAwayReport{
+					Statistics: c.GetDrawBalanceStatistics(draw),
+					Teams:      c.GetAllTeamHomeAwayAnalysis(draw),
+				}
+			}
+		case *RestPeriodConstraint:
+			if stats.Rest == nil {
+				stats.Rest = &RestReport{
+					MinRestDays: c.GetMinRestDays(),
+					Statistics:  c.GetDrawRestStatistics(draw),
+					Teams:       c.GetAllTeamRestAnalysis(draw),
+				}
+			}
+		case *PrimeTimeSpreadConstraint:
+			if stats.PrimeTime == nil {
+				stats.PrimeTime = &PrimeTimeReport{
+					Statistics: c.GetDrawPrimeTimeStatistics(draw),
+					Teams:      c.GetAllTeamPrimeTimeAnalysis(draw),
+				}
+			}
+		case *HalfSeasonTravelConstraint:
+			if stats.HalfSeasonTravel == nil {
+				report := c.Report(draw)
+				stats.HalfSeasonTravel = &report
+			}
+		case *StrengthSpreadConstraint:
+			if stats.StrengthSpread == nil {
+				report := c.Report(draw)
+				stats.StrengthSpread = &report
+			}
+		case *TravelMinimizationConstraint:
+			if stats.Travel == nil {
+				stats.Travel = &TravelReport{
+					Statistics: c.GetDrawTravelStatistics(draw),
+					Teams:      c.GetAllTeamTravelAnalysis(draw),
+				}
+			}
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,251 @@
+package constraints
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Strength spread defaults
+const (
+	// DefaultStrengthTopTeams treats the top four rated teams as tough opponents
+	DefaultStrengthTopTeams = 4
+	// DefaultStrengthWindowRounds is the run of rounds tough fixtures are counted over
+	DefaultStrengthWindowRounds = 4
+	// DefaultMaxToughInWindow allows two tough fixtures in any window
+	DefaultMaxToughInWindow = 2
+)
+
+// StrengthSpreadConstraint spreads each team's fixtures against the
+// strongest opponents across its season, so no team meets the whole top
+// four in a few weeks. Teams are rated by a configured strength, such as
+// last season's ladder points, and the top rated teams are tough opponents.
+// A tough fixture is clustered when it takes the team past the limit of
+// tough fixtures within the window of rounds ending with it.
+type StrengthSpreadConstraint struct {
+	BaseConstraint
+	strengths        map[int]float64
+	toughOpponents   map[int]bool
+	topTeams         int
+	windowRounds     int
+	maxToughInWindow int
+	teamWeights      TeamWeights // Emphasizes particular teams in the score
+}
+
+// ToughFixture is a match against one of the top rated teams
+type ToughFixture struct {
+	Round            int     `json:"round"`
+	MatchID          int     `json:"match_id"`
+	OpponentID       int     `json:"opponent_id"`
+	OpponentStrength float64 `json:"opponent_strength"`
+	// InWindow counts the team's tough fixtures in the window ending this round
+	InWindow  int  `json:"in_window"`
+	Clustered bool `json:"clustered"`
+}
+
+// StrengthSpreadAnalysis is how a team's tough fixtures fall across its season
+type StrengthSpreadAnalysis struct {
+	TeamID            int            `json:"team_id"`
+	ToughFixtures     []ToughFixture `json:"tough_fixtures"`
+	ClusteredFixtures int            `json:"clustered_fixtures"`
+	// BusiestWindow is the most tough fixtures in any window of rounds
+	BusiestWindow int     `json:"busiest_window"`
+	Score         float64 `json:"score"`
+}
+
+// StrengthSpreadReport is every team's tough fixture spread
+type StrengthSpreadReport struct {
+	ToughOpponents   []int                    `json:"tough_opponents"`
+	WindowRounds     int                      `json:"window_rounds"`
+	MaxToughInWindow int                      `json:"max_tough_in_window"`
+	Teams            []StrengthSpreadAnalysis `json:"teams"`
+}
+
+// NewStrengthSpreadConstraint creates a strength spread constraint. The
+// topTeams highest rated teams are tough opponents, ties going to the lower
+// team ID; more than maxToughInWindow of them within windowRounds rounds is
+// penalized.
+func NewStrengthSpreadConstraint(strengths map[int]float64, topTeams, windowRounds, maxToughInWindow int) *StrengthSpreadConstraint {
+	ranked := make([]int, 0, len(strengths))
+	for teamID := range strengths {
+		ranked = append(ranked, teamID)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if strengths[ranked[i]] != strengths[ranked[j]] {
+			return strengths[ranked[i]] > strengths[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if len(ranked) > topTeams {
+		ranked = ranked[:topTeams]
+	}
+
+	tough := make(map[int]bool, len(ranked))
+	for _, teamID := range ranked {
+		tough[teamID] = true
+	}
+
+	return &StrengthSpreadConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"StrengthSpread",
+			fmt.Sprintf("No more than %d fixtures against the top %d teams in any %d rounds", maxToughInWindow, topTeams, windowRounds),
+			false, // This is a soft constraint
+		),
+		strengths:        strengths,
+		toughOpponents:   tough,
+		topTeams:         topTeams,
+		windowRounds:     windowRounds,
+		maxToughInWindow: maxToughInWindow,
+	}
+}
+
+// Validate always returns nil for soft constraints (no hard violations)
+func (ssc *StrengthSpreadConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score calculates how evenly tough fixtures are spread across teams' seasons
+func (ssc *StrengthSpreadConstraint) Score(draw *models.Draw) float64 {
+	return ssc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed averages the teams' scores, weighted by the team weights,
+// using a shared index
+func (ssc *StrengthSpreadConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
+	if len(teams) == 0 {
+		return 1.0
+	}
+
+	totalScore := 0.0
+	totalWeight := 0.0
+	for _, team := range teams {
+		weight := ssc.teamWeights.Weight(team)
+		totalScore += weight * ssc.analyze(index, team).Score
+		totalWeight += weight
+	}
+
+	return totalScore / totalWeight
+}
+
+// TeamScores returns each team's strength spread score
+func (ssc *StrengthSpreadConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	return ssc.TeamScoresIndexed(NewDrawIndex(draw))
+}
+
+// TeamScoresIndexed returns each team's score using a shared index
+func (ssc *StrengthSpreadConstraint) TeamScoresIndexed(index *DrawIndex) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, team := range index.Teams() {
+		scores[team] = ssc.analyze(index, team).Score
+	}
+	return scores
+}
+
+// ScoreTeam returns one team's strength spread score
+func (ssc *StrengthSpreadConstraint) ScoreTeam(draw *models.Draw, teamID int) float64 {
+	return ssc.analyze(NewDrawIndex(draw), teamID).Score
+}
+
+// ScoreTeamIndexed returns one team's score using a shared index
+func (ssc *StrengthSpreadConstraint) ScoreTeamIndexed(index *DrawIndex, teamID int) float64 {
+	return ssc.analyze(index, teamID).Score
+}
+
+// GetTeamWeights returns the weights emphasizing particular teams' scores
+func (ssc *StrengthSpreadConstraint) GetTeamWeights() TeamWeights {
+	return ssc.teamWeights
+}
+
+// SetTeamWeights weights particular teams' scores more heavily in the draw's score
+func (ssc *StrengthSpreadConstraint) SetTeamWeights(weights TeamWeights) {
+	ssc.teamWeights = weights
+}
+
+// GetTeamStrengths returns the strength rating of each rated team
+func (ssc *StrengthSpreadConstraint) GetTeamStrengths() map[int]float64 {
+	return ssc.strengths
+}
+
+// GetToughOpponents returns the top rated teams, in team ID order
+func (ssc *StrengthSpreadConstraint) GetToughOpponents() []int {
+	return sortedRounds(ssc.toughOpponents)
+}
+
+// GetTopTeams returns how many of the top rated teams are tough opponents
+func (ssc *StrengthSpreadConstraint) GetTopTeams() int {
+	return ssc.topTeams
+}
+
+// GetWindowRounds returns the run of rounds tough fixtures are counted over
+func (ssc *StrengthSpreadConstraint) GetWindowRounds() int {
+	return ssc.windowRounds
+}
+
+// GetMaxToughInWindow returns the tough fixtures allowed in any window
+func (ssc *StrengthSpreadConstraint) GetMaxToughInWindow() int {
+	return ssc.maxToughInWindow
+}
+
+// Report returns every team's tough fixture spread, in team order
+func (ssc *StrengthSpreadConstraint) Report(draw *models.Draw) StrengthSpreadReport {
+	index := NewDrawIndex(draw)
+	report := StrengthSpreadReport{
+		ToughOpponents:   ssc.GetToughOpponents(),
+		WindowRounds:     ssc.windowRounds,
+		MaxToughInWindow: ssc.maxToughInWindow,
+		Teams:            make([]StrengthSpreadAnalysis, 0, len(index.Teams())),
+	}
+	for _, team := range index.Teams() {
+		report.Teams = append(report.Teams, ssc.analyze(index, team))
+	}
+	return report
+}
+
+// analyze walks a team's tough fixtures in round order. The score is 1.0
+// less the share of them that are clustered.
+func (ssc *StrengthSpreadConstraint) analyze(index *DrawIndex, teamID int) StrengthSpreadAnalysis {
+	analysis := StrengthSpreadAnalysis{TeamID: teamID, ToughFixtures: []ToughFixture{}, Score: 1.0}
+
+	teamMatches := index.TeamMatchesByRound(teamID)
+	for round := 1; round <= index.Draw().Rounds; round++ {
+		match, exists := teamMatches[round]
+		if !exists {
+			continue
+		}
+		opponent, err := match.GetOpponent(teamID)
+		if err != nil || opponent == nil || !ssc.toughOpponents[*opponent] {
+			continue
+		}
+		analysis.ToughFixtures = append(analysis.ToughFixtures, ToughFixture{
+			Round:            round,
+			MatchID:          match.ID,
+			OpponentID:       *opponent,
+			OpponentStrength: ssc.strengths[*opponent],
+		})
+	}
+
+	// Count each fixture's window from the earliest tough fixture still in it
+	start := 0
+	for i := range analysis.ToughFixtures {
+		fixture := &analysis.ToughFixtures[i]
+		for fixture.Round-analysis.ToughFixtures[start].Round >= ssc.windowRounds {
+			start++
+		}
+		fixture.InWindow = i - start + 1
+		if fixture.InWindow > analysis.BusiestWindow {
+			analysis.BusiestWindow = fixture.InWindow
+		}
+		if fixture.InWindow > ssc.maxToughInWindow {
+			fixture.Clustered = true
+			analysis.ClusteredFixtures++
+		}
+	}
+
+	if len(analysis.ToughFixtures) > 0 {
+		analysis.Score = 1.0 - float64(analysis.ClusteredFixtures)/float64(len(analysis.ToughFixtures))
+	}
+	return analysis
+}
@@ -38,18 +38,17 @@ func (tac *TeamAvailabilityConstraint) Validate(match *models.Match, draw *model
 		return nil
 	}
 	
-	// Skip if match doesn't have a date assigned yet
-	if match.MatchDate == nil {
+	// Check the match date, or its round's dates in the season calendar
+	date, conflict := tac.conflictDate(match)
+	if !conflict {
 		return nil
 	}
-	
-	// Check if the match date conflicts with unavailable dates
-	if tac.IsDateUnavailable(*match.MatchDate) {
-		return fmt.Errorf("team %d is not available on %s", 
-			tac.teamID, match.MatchDate.Format("2006-01-02"))
+	if match.MatchDate == nil {
+		return fmt.Errorf("team %d is not available on any day of round %d",
+			tac.teamID, match.Round)
 	}
-	
-	return nil
+	return fmt.Errorf("team %d is not available on %s", 
+		tac.teamID, date.Format("2006-01-02"))
 }
 
 // Score calculates how well the draw satisfies this constraint
@@ -59,9 +58,9 @@ func (tac *TeamAvailabilityConstraint) Score(draw *models.Draw) float64 {
 	
 	for _, match := range draw.Matches {
 		// Only consider matches involving this team
-		if match.HasTeam(tac.teamID) && match.MatchDate != nil {
+		if match.HasTeam(tac.teamID) && tac.isChecked(match) {
 			totalMatches++
-			if tac.IsDateUnavailable(*match.MatchDate) {
+			if _, conflict := tac.conflictDate(match); conflict {
 				violatingMatches++
 			}
 		}
@@ -106,8 +105,8 @@ func (tac *TeamAvailabilityConstraint) GetConflictingMatches(draw *models.Draw)
 	var conflictingMatches []*models.Match
 	
 	for _, match := range draw.Matches {
-		if match.HasTeam(tac.teamID) && match.MatchDate != nil {
-			if tac.IsDateUnavailable(*match.MatchDate) {
+		if match.HasTeam(tac.teamID) {
+			if _, conflict := tac.conflictDate(match); conflict {
 				conflictingMatches = append(conflictingMatches, match)
 			}
 		}
@@ -135,6 +134,7 @@ func (tac *TeamAvailabilityConstraint) GetAvailableAlternatives(conflictingMatch
 type MultiTeamAvailabilityConstraint struct {
 	BaseConstraint
 	teamConstraints map[int]*TeamAvailabilityConstraint
+	calendar        *models.SeasonCalendar
 }
 
 // NewMultiTeamAvailabilityConstraint creates a constraint for multiple teams
@@ -186,7 +186,18 @@ func (mtac *MultiTeamAvailabilityConstraint) GetTeamConstraints() map[int]*TeamA
 
 // AddTeamConstraint adds a new team availability constraint
 func (mtac *MultiTeamAvailabilityConstraint) AddTeamConstraint(teamID int, unavailableDates []time.Time) {
-	mtac.teamConstraints[teamID] = NewTeamAvailabilityConstraint(teamID, unavailableDates)
+	constraint := NewTeamAvailabilityConstraint(teamID, unavailableDates)
+	constraint.SetCalendar(mtac.calendar)
+	mtac.teamConstraints[teamID] = constraint
+}
+
+// SetCalendar sets the season calendar every team's undated matches are
+// checked against
+func (mtac *MultiTeamAvailabilityConstraint) SetCalendar(calendar *models.SeasonCalendar) {
+	mtac.calendar = calendar
+	for _, constraint := range mtac.teamConstraints {
+		constraint.SetCalendar(calendar)
+	}
 }
 
 // RemoveTeamConstraint removes a team availability constraint
@@ -206,4 +217,22 @@ func (mtac *MultiTeamAvailabilityConstraint) GetAllConflictingMatches(draw *mode
 	}
 	
 	return conflicts
-}
\ No newline at end of file
+}
+// StoredTeamAvailabilityConstraint keeps teams off the unavailable dates
+// recorded against them in storage. The engine adds it whenever league data is
+// set, so draws don't have to repeat those dates in their own configuration.
+type StoredTeamAvailabilityConstraint struct {
+	*MultiTeamAvailabilityConstraint
+}
+
+// NewStoredTeamAvailabilityConstraint creates a constraint for the stored
+// unavailable dates of each team
+func NewStoredTeamAvailabilityConstraint(teamAvailability map[int][]time.Time) *StoredTeamAvailabilityConstraint {
+	multi := NewMultiTeamAvailabilityConstraint(teamAvailability)
+	multi.BaseConstraint = NewBaseConstraint(
+		"StoredTeamAvailability",
+		"Teams must not be scheduled on the unavailable dates recorded for them",
+		true,
+	)
+	return &StoredTeamAvailabilityConstraint{MultiTeamAvailabilityConstraint: multi}
+}
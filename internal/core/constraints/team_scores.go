@@ -0,0 +1,79 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DefaultWorstTeamsLimit is how many teams are reported per constraint when no limit is given
+const DefaultWorstTeamsLimit = 3
+
+// TeamScorer is implemented by soft constraints whose score is the average of
+// per-team scores. Each team's score is between 0.0 and 1.0, higher is better.
+type TeamScorer interface {
+	TeamScores(draw *models.Draw) map[int]float64
+}
+
+// TeamScore is one team's contribution to a soft constraint
+type TeamScore struct {
+	TeamID  int     `json:"team_id"`
+	Score   float64 `json:"score"`
+	Penalty float64 `json:"penalty"` // weight * (1 - score)
+}
+
+// ConstraintTeamScores lists the teams a soft constraint is least satisfied for
+type ConstraintTeamScores struct {
+	Constraint string      `json:"constraint"`
+	Weight     float64     `json:"weight"`
+	Teams      []TeamScore `json:"teams"`
+}
+
+// WorstTeams returns, for every soft constraint that scores teams individually,
+// the limit teams with the lowest scores. Teams that fully satisfy a constraint
+// are left out.
+func (ce *ConstraintEngine) WorstTeams(draw *models.Draw, limit int) []ConstraintTeamScores {
+	if limit <= 0 {
+		limit = DefaultWorstTeamsLimit
+	}
+
+	var results []ConstraintTeamScores
+	index := NewDrawIndex(draw)
+	for _, weighted := range ce.softConstraints {
+		scorer, ok := weighted.Constraint.(TeamScorer)
+		if !ok {
+			continue
+		}
+
+		var teams []TeamScore
+		for teamID, score := range TeamScoresWith(scorer, index) {
+			if score >= 1.0 {
+				continue
+			}
+			teams = append(teams, TeamScore{
+				TeamID:  teamID,
+				Score:   score,
+				Penalty: weighted.Weight * (1.0 - score),
+			})
+		}
+
+		// Lowest score first, ties by team ID so the order is stable between reports
+		for i := 0; i < len(teams)-1; i++ {
+			for j := 0; j < len(teams)-i-1; j++ {
+				if teams[j].Score > teams[j+1].Score ||
+					(teams[j].Score == teams[j+1].Score && teams[j].TeamID > teams[j+1].TeamID) {
+					teams[j], teams[j+1] = teams[j+1], teams[j]
+				}
+			}
+		}
+		if len(teams) > limit {
+			teams = teams[:limit]
+		}
+
+		results = append(results, ConstraintTeamScores{
+			Constraint: weighted.Constraint.Name(),
+			Weight:     weighted.Weight,
+			Teams:      teams,
+		})
+	}
+
+	return results
+}
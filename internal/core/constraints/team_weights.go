@@ -0,0 +1,103 @@
+package constraints
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TeamWeights emphasizes particular teams in a soft constraint's score, for
+// clubs with negotiated guarantees such as the Warriors' travel. A team
+// weighted 2.0 counts twice as much as the others when per-team scores are
+// averaged. Teams not listed weigh 1.0.
+type TeamWeights map[int]float64
+
+// Weight returns a team's weight
+func (tw TeamWeights) Weight(teamID int) float64 {
+	if weight, ok := tw[teamID]; ok {
+		return weight
+	}
+	return 1.0
+}
+
+// Average combines per-team scores into a weighted mean, scoring 1.0 when
+// there are no teams
+func (tw TeamWeights) Average(teamScores map[int]float64) float64 {
+	totalScore := 0.0
+	totalWeight := 0.0
+	for teamID, score := range teamScores {
+		weight := tw.Weight(teamID)
+		totalScore += weight * score
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return 1.0
+	}
+	return totalScore / totalWeight
+}
+
+// Params returns the weights keyed by team ID, as configured
+func (tw TeamWeights) Params() map[string]float64 {
+	params := make(map[string]float64, len(tw))
+	for teamID, weight := range tw {
+		params[strconv.Itoa(teamID)] = weight
+	}
+	return params
+}
+
+// TeamWeighted is implemented by soft constraints that average per-team
+// scores and can weight some teams more heavily
+type TeamWeighted interface {
+	GetTeamWeights() TeamWeights
+	SetTeamWeights(weights TeamWeights)
+}
+
+// averageTeamScores combines a constraint's per-team scores into its score,
+// applying its team weights when it has them
+func averageTeamScores(constraint Constraint, teamScores map[int]float64) float64 {
+	if weighted, ok := constraint.(TeamWeighted); ok {
+		return weighted.GetTeamWeights().Average(teamScores)
+	}
+	return meanTeamScore(teamScores)
+}
+
+// meanTeamScore averages per-team scores, scoring 1.0 when there are no teams
+func meanTeamScore(teamScores map[int]float64) float64 {
+	if len(teamScores) == 0 {
+		return 1.0
+	}
+
+	total := 0.0
+	for _, score := range teamScores {
+		total += score
+	}
+	return total / float64(len(teamScores))
+}
+
+// parseTeamWeights reads the optional team_weights param, an object of
+// positive weights keyed by team ID
+func parseTeamWeights(params map[string]interface{}) (TeamWeights, error) {
+	value, exists := params["team_weights"]
+	if !exists {
+		return nil, nil
+	}
+
+	weightsMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("team_weights must be an object keyed by team ID")
+	}
+
+	weights := make(TeamWeights, len(weightsMap))
+	for teamKey, weightInterface := range weightsMap {
+		teamID, err := strconv.Atoi(teamKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid team ID %s in team_weights", teamKey)
+		}
+		weight, ok := weightInterface.(float64)
+		if !ok || weight <= 0 {
+			return nil, fmt.Errorf("weight for team %d must be a positive number", teamID)
+		}
+		weights[teamID] = weight
+	}
+	return weights, nil
+}
@@ -0,0 +1,140 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// RoundTimeline summarises constraint problems within a single round
+type RoundTimeline struct {
+	Round             int                `json:"round"`
+	MatchCount        int                `json:"match_count"`
+	HardViolations    int                `json:"hard_violations"`
+	SoftViolations    int                `json:"soft_violations"`
+	Severity          ViolationSeverity  `json:"severity,omitempty"`
+	SoftPenalty       float64            `json:"soft_penalty"`
+	ConstraintPenalty map[string]float64 `json:"constraint_penalty,omitempty"`
+	ConstraintCounts  map[string]int     `json:"constraint_counts,omitempty"`
+}
+
+// ConstraintTimeline is the round-by-round breakdown of a draw's constraint violations
+type ConstraintTimeline struct {
+	Rounds              []RoundTimeline `json:"rounds"`
+	DrawLevelViolations int             `json:"draw_level_violations"`
+	TotalSoftPenalty    float64         `json:"total_soft_penalty"`
+}
+
+// AnalyzeTimeline breaks a draw's violations down by round.
+// Hard violations are attributed to the round of the offending match. Soft
+// penalties are scored globally, so each round is credited with the penalty that
+// disappears when its matches are left out of the draw.
+func (ce *ConstraintEngine) AnalyzeTimeline(draw *models.Draw) ConstraintTimeline {
+	rounds := draw.Rounds
+	for _, match := range draw.Matches {
+		if match.Round > rounds {
+			rounds = match.Round
+		}
+	}
+
+	timeline := ConstraintTimeline{
+		Rounds: make([]RoundTimeline, rounds),
+	}
+	for i := range timeline.Rounds {
+		timeline.Rounds[i] = RoundTimeline{
+			Round:             i + 1,
+			ConstraintPenalty: make(map[string]float64),
+			ConstraintCounts:  make(map[string]int),
+		}
+	}
+
+	for _, match := range draw.Matches {
+		if match.Round >= 1 {
+			timeline.Rounds[match.Round-1].MatchCount++
+		}
+	}
+
+	// Hard violations belong to the round of the match that breaks them
+	index := NewDrawIndex(draw)
+	for _, constraint := range ce.hardConstraints {
+		for _, match := range draw.Matches {
+			if match.Round < 1 {
+				continue
+			}
+			if err := validateWith(constraint, match, index); err != nil {
+				round := &timeline.Rounds[match.Round-1]
+				round.HardViolations++
+				round.ConstraintCounts[constraint.Name()]++
+			}
+		}
+
+		timeline.DrawLevelViolations += len(validateDrawWith(constraint, index))
+	}
+
+	// Soft penalties by leave-one-round-out
+	fullPenalties := ce.softPenalties(draw)
+	for _, penalty := range fullPenalties {
+		timeline.TotalSoftPenalty += penalty
+	}
+
+	for i := range timeline.Rounds {
+		round := &timeline.Rounds[i]
+		if round.MatchCount == 0 {
+			continue
+		}
+
+		withoutRound := ce.softPenalties(drawWithoutRound(draw, round.Round))
+		for name, penalty := range fullPenalties {
+			marginal := penalty - withoutRound[name]
+			if marginal <= 0 {
+				continue
+			}
+			round.ConstraintPenalty[name] = marginal
+			round.SoftPenalty += marginal
+			round.SoftViolations++
+		}
+	}
+
+	for i := range timeline.Rounds {
+		round := &timeline.Rounds[i]
+		if round.HardViolations > 0 {
+			round.Severity = SeverityHard
+		} else if round.SoftViolations > 0 {
+			round.Severity = SeveritySoft
+		}
+	}
+
+	return timeline
+}
+
+// softPenalties returns each soft constraint's weighted penalty, normalised so the
+// penalties sum to 1 - the draw's soft score
+func (ce *ConstraintEngine) softPenalties(draw *models.Draw) map[string]float64 {
+	penalties := make(map[string]float64)
+
+	var totalWeight float64
+	for _, weighted := range ce.softConstraints {
+		totalWeight += weighted.Weight
+	}
+	if totalWeight == 0 {
+		return penalties
+	}
+
+	index := NewDrawIndex(draw)
+	for _, weighted := range ce.softConstraints {
+		penalty := (1.0 - scoreWith(weighted.Constraint, index)) * weighted.Weight / totalWeight
+		penalties[weighted.Constraint.Name()] += penalty
+	}
+
+	return penalties
+}
+
+// drawWithoutRound returns a shallow copy of the draw with one round's matches removed
+func drawWithoutRound(draw *models.Draw, round int) *models.Draw {
+	trial := *draw
+	trial.Matches = make([]*models.Match, 0, len(draw.Matches))
+	for _, match := range draw.Matches {
+		if match.Round != round {
+			trial.Matches = append(trial.Matches, match)
+		}
+	}
+	return &trial
+}
@@ -0,0 +1,274 @@
+package constraints
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Travel cost defaults, in dollars for a travelling squad
+const (
+	DefaultDriveCostPerKm        = 4.0
+	DefaultShortFlightCostPerKm  = 10.0
+	DefaultLongFlightCostPerKm   = 6.0
+	DefaultAccommodationPerNight = 9000.0
+	// DefaultStayAwayRestDays keeps a team on the road between two trips
+	// with fewer than six rest days between them
+	DefaultStayAwayRestDays = 6
+)
+
+// TravelCostModel prices a team's travel. Each leg costs its kilometres at
+// the rate for how it's travelled, classed as for travel fatigue. A team
+// flying to consecutive matches with fewer than StayAwayRestDays rest days
+// between them stays away, paying for a night's accommodation for each day
+// between the matches, and travels on to the next venue instead of going
+// home.
+type TravelCostModel struct {
+	DriveCostPerKm        float64 `json:"drive_cost_per_km"`
+	ShortFlightCostPerKm  float64 `json:"short_flight_cost_per_km"`
+	LongFlightCostPerKm   float64 `json:"long_flight_cost_per_km"`
+	AccommodationPerNight float64 `json:"accommodation_per_night"`
+	StayAwayRestDays      int     `json:"stay_away_rest_days"`
+}
+
+// DefaultTravelCostModel returns the model with the default rates
+func DefaultTravelCostModel() TravelCostModel {
+	return TravelCostModel{
+		DriveCostPerKm:        DefaultDriveCostPerKm,
+		ShortFlightCostPerKm:  DefaultShortFlightCostPerKm,
+		LongFlightCostPerKm:   DefaultLongFlightCostPerKm,
+		AccommodationPerNight: DefaultAccommodationPerNight,
+		StayAwayRestDays:      DefaultStayAwayRestDays,
+	}
+}
+
+// legCost returns the cost of travelling a leg
+func (m TravelCostModel) legCost(km float64) float64 {
+	switch geo.ClassifyLeg(km) {
+	case geo.LegDrive:
+		return km * m.DriveCostPerKm
+	case geo.LegShortFlight:
+		return km * m.ShortFlightCostPerKm
+	default:
+		return km * m.LongFlightCostPerKm
+	}
+}
+
+// TeamTravelCost is a team's estimated travel cost for the season.
+// Matches that can't be located cost nothing.
+type TeamTravelCost struct {
+	TeamID              int     `json:"team_id"`
+	DriveKm             float64 `json:"drive_km"`
+	ShortFlightKm       float64 `json:"short_flight_km"`
+	LongFlightKm        float64 `json:"long_flight_km"`
+	TravelCost          float64 `json:"travel_cost"`
+	AccommodationNights int     `json:"accommodation_nights"`
+	AccommodationCost   float64 `json:"accommodation_cost"`
+	TotalCost           float64 `json:"total_cost"`
+}
+
+// TravelCostReport is the league's estimated travel cost for a draw
+type TravelCostReport struct {
+	Model                  TravelCostModel  `json:"model"`
+	Budget                 float64          `json:"budget,omitempty"`
+	TotalTravelCost        float64          `json:"total_travel_cost"`
+	TotalAccommodationCost float64          `json:"total_accommodation_cost"`
+	TotalCost              float64          `json:"total_cost"`
+	AverageTeamCost        float64          `json:"average_team_cost"`
+	Teams                  []TeamTravelCost `json:"teams"`
+}
+
+// TravelCostConstraint keeps the league's estimated travel cost for the
+// season within a budget. Over budget the score falls as budget/cost.
+//
+// Teams and venues are located from league data, so nothing costs anything
+// until it's supplied.
+type TravelCostConstraint struct {
+	BaseConstraint
+	budget float64
+	model  TravelCostModel
+	league *LeagueData
+}
+
+// NewTravelCostConstraint creates a travel cost constraint with the default
+// model and the given league budget
+func NewTravelCostConstraint(budget float64) *TravelCostConstraint {
+	return &TravelCostConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"TravelCost",
+			"Keep the league's estimated travel cost within budget",
+			false, // This is a soft constraint
+		),
+		budget: budget,
+		model:  DefaultTravelCostModel(),
+	}
+}
+
+// Validate always returns nil for soft constraints (no hard violations)
+func (tcc *TravelCostConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score calculates how well the draw keeps travel costs within budget
+func (tcc *TravelCostConstraint) Score(draw *models.Draw) float64 {
+	return tcc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed scores the league's travel cost using a shared index
+func (tcc *TravelCostConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	if tcc.budget <= 0 {
+		return 1.0
+	}
+
+	total := 0.0
+	for _, team := range index.Teams() {
+		total += tcc.teamCost(index, team).TotalCost
+	}
+	if total <= tcc.budget {
+		return 1.0
+	}
+	return tcc.budget / total
+}
+
+// SetLeagueData supplies the team and venue coordinates trips are measured with
+func (tcc *TravelCostConstraint) SetLeagueData(data *LeagueData) {
+	tcc.league = data
+}
+
+// GetBudget returns the league's season travel budget, 0 when there's none
+func (tcc *TravelCostConstraint) GetBudget() float64 {
+	return tcc.budget
+}
+
+// GetModel returns the rates travel is priced at
+func (tcc *TravelCostConstraint) GetModel() TravelCostModel {
+	return tcc.model
+}
+
+// SetModel sets the rates travel is priced at
+func (tcc *TravelCostConstraint) SetModel(model TravelCostModel) {
+	tcc.model = model
+}
+
+// Report returns every team's travel cost, in team order, and the league totals
+func (tcc *TravelCostConstraint) Report(draw *models.Draw) TravelCostReport {
+	index := NewDrawIndex(draw)
+	report := TravelCostReport{
+		Model:  tcc.model,
+		Budget: tcc.budget,
+		Teams:  make([]TeamTravelCost, 0, len(index.Teams())),
+	}
+
+	for _, team := range index.Teams() {
+		cost := tcc.teamCost(index, team)
+		report.TotalTravelCost += cost.TravelCost
+		report.TotalAccommodationCost += cost.AccommodationCost
+		report.TotalCost += cost.TotalCost
+		report.Teams = append(report.Teams, cost)
+	}
+
+	if len(report.Teams) > 0 {
+		report.AverageTeamCost = report.TotalCost / float64(len(report.Teams))
+	}
+	return report
+}
+
+// TeamCost returns one team's travel cost, and false when the team isn't
+// in the draw
+func (tcc *TravelCostConstraint) TeamCost(draw *models.Draw, teamID int) (TeamTravelCost, bool) {
+	index := NewDrawIndex(draw)
+	if len(index.TeamMatches(teamID)) == 0 {
+		return TeamTravelCost{}, false
+	}
+	return tcc.teamCost(index, teamID), true
+}
+
+// teamCost walks a team's season in round order, following it from its base
+// to each venue and either home again or on to the next venue
+func (tcc *TravelCostConstraint) teamCost(index *DrawIndex, teamID int) TeamTravelCost {
+	cost := TeamTravelCost{TeamID: teamID}
+	baseLat, baseLon, ok := tcc.league.TeamHomeLocation(teamID)
+	if !ok {
+		return cost
+	}
+
+	var matches []*models.Match
+	teamMatches := index.TeamMatchesByRound(teamID)
+	for round := 1; round <= index.Draw().Rounds; round++ {
+		if match, exists := teamMatches[round]; exists {
+			matches = append(matches, match)
+		}
+	}
+
+	travel := func(fromLat, fromLon, toLat, toLon float64) {
+		km := geo.HaversineKm(fromLat, fromLon, toLat, toLon)
+		switch geo.ClassifyLeg(km) {
+		case geo.LegDrive:
+			cost.DriveKm += km
+		case geo.LegShortFlight:
+			cost.ShortFlightKm += km
+		default:
+			cost.LongFlightKm += km
+		}
+		cost.TravelCost += tcc.model.legCost(km)
+	}
+
+	fromLat, fromLon := baseLat, baseLon
+	for i, match := range matches {
+		venueLat, venueLon, ok := tcc.league.MatchLocation(match)
+		if !ok {
+			fromLat, fromLon = baseLat, baseLon
+			continue
+		}
+		travel(fromLat, fromLon, venueLat, venueLon)
+
+		if i+1 < len(matches) {
+			if nights, stays := tcc.staysAway(match, matches[i+1], baseLat, baseLon, venueLat, venueLon); stays {
+				cost.AccommodationNights += nights
+				fromLat, fromLon = venueLat, venueLon
+				continue
+			}
+		}
+		travel(venueLat, venueLon, baseLat, baseLon)
+		fromLat, fromLon = baseLat, baseLon
+	}
+
+	cost.AccommodationCost = float64(cost.AccommodationNights) * tcc.model.AccommodationPerNight
+	cost.TotalCost = cost.TravelCost + cost.AccommodationCost
+	return cost
+}
+
+// staysAway reports whether a team flies from a match straight on to the
+// next rather than going home, and how many nights it spends away between
+// them
+func (tcc *TravelCostConstraint) staysAway(match, next *models.Match, baseLat, baseLon, venueLat, venueLon float64) (int, bool) {
+	if match.MatchDate == nil || next.MatchDate == nil {
+		return 0, false
+	}
+	nextLat, nextLon, ok := tcc.league.MatchLocation(next)
+	if !ok {
+		return 0, false
+	}
+	if geo.ClassifyLeg(geo.HaversineKm(baseLat, baseLon, venueLat, venueLon)) == geo.LegDrive ||
+		geo.ClassifyLeg(geo.HaversineKm(baseLat, baseLon, nextLat, nextLon)) == geo.LegDrive {
+		return 0, false
+	}
+
+	days := int(next.MatchDate.Sub(*match.MatchDate).Hours() / 24)
+	if days-1 >= tcc.model.StayAwayRestDays {
+		return 0, false
+	}
+	return days, true
+}
+
+// TravelCost returns the engine's travel cost constraint, or one with the
+// default model and no budget when the draw's configuration doesn't score
+// travel costs
+func (ce *ConstraintEngine) TravelCost() *TravelCostConstraint {
+	for _, weighted := range ce.softConstraints {
+		if cost, ok := weighted.Constraint.(*TravelCostConstraint); ok {
+			return cost
+		}
+	}
+	return NewTravelCostConstraint(0)
+}
@@ -0,0 +1,274 @@
+package constraints
+
+import (
+	"math"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Travel fatigue defaults
+const (
+	// DefaultMaxConsecutiveLongHaul allows one long-haul trip before the
+	// team must get a break from them
+	DefaultMaxConsecutiveLongHaul = 1
+	// DefaultZoneShiftPenalty is the extra penalty per hour of time zone
+	// shift on a long-haul trip over the limit
+	DefaultZoneShiftPenalty = 0.5
+)
+
+// TravelFatigueConstraint penalizes back-to-back long-haul trips, such as
+// Perth followed by Auckland. Each trip from a team's base is classified as
+// a drive, short flight or long flight by distance; long flights in
+// consecutive rounds beyond the limit are penalized, more so the further
+// they shift the team's clocks. A home game, a bye or a shorter trip breaks
+// the run. Home games moved far from the team's base count as trips too.
+//
+// Teams and venues are located from league data, so nothing is penalized
+// until it's supplied.
+type TravelFatigueConstraint struct {
+	BaseConstraint
+	maxConsecutiveLongHaul int
+	zoneShiftPenalty       float64
+	league                 *LeagueData
+	teamWeights            TeamWeights // Emphasizes particular teams in the score
+}
+
+// TravelLeg is one match in a team's itinerary
+type TravelLeg struct {
+	Round      int  `json:"round"`
+	MatchID    int  `json:"match_id"`
+	OpponentID int  `json:"opponent_id"`
+	Home       bool `json:"home"`
+	VenueID    *int `json:"venue_id,omitempty"`
+	// DistanceKm is one way from the team's base. Kind is empty when the
+	// team or match can't be located.
+	DistanceKm float64     `json:"distance_km"`
+	Kind       geo.LegKind `json:"kind,omitempty"`
+	// ZoneShiftHours is how far the team's clocks move, positive going east
+	ZoneShiftHours float64 `json:"zone_shift_hours"`
+	// ConsecutiveLongHaul counts the long-haul trips in a row up to and
+	// including this one, 0 when this one isn't long-haul
+	ConsecutiveLongHaul int  `json:"consecutive_long_haul"`
+	Penalized           bool `json:"penalized"`
+}
+
+// TeamItinerary is a team's season of trips with its fatigue score
+type TeamItinerary struct {
+	TeamID                int         `json:"team_id"`
+	Drives                int         `json:"drives"`
+	ShortFlights          int         `json:"short_flights"`
+	LongFlights           int         `json:"long_flights"`
+	LongestLongHaulStreak int         `json:"longest_long_haul_streak"`
+	PenalizedLegs         int         `json:"penalized_legs"`
+	Score                 float64     `json:"score"`
+	Legs                  []TravelLeg `json:"legs"`
+}
+
+// NewTravelFatigueConstraint creates a travel fatigue constraint allowing
+// maxConsecutiveLongHaul long-haul trips in a row
+func NewTravelFatigueConstraint(maxConsecutiveLongHaul int) *TravelFatigueConstraint {
+	return &TravelFatigueConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"TravelFatigue",
+			"Avoid back-to-back long-haul trips, especially across time zones",
+			false, // This is a soft constraint
+		),
+		maxConsecutiveLongHaul: maxConsecutiveLongHaul,
+		zoneShiftPenalty:       DefaultZoneShiftPenalty,
+	}
+}
+
+// Validate always returns nil for soft constraints (no hard violations)
+func (tfc *TravelFatigueConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	// Soft constraints don't have hard validation failures
+	return nil
+}
+
+// Score calculates how well the draw avoids back-to-back long-haul trips
+func (tfc *TravelFatigueConstraint) Score(draw *models.Draw) float64 {
+	return tfc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed averages the teams' fatigue scores, weighted by the team
+// weights, using a shared index
+func (tfc *TravelFatigueConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
+	if len(teams) == 0 {
+		return 1.0
+	}
+
+	totalScore := 0.0
+	totalWeight := 0.0
+	for _, team := range teams {
+		weight := tfc.teamWeights.Weight(team)
+		totalScore += weight * tfc.itinerary(index, team).Score
+		totalWeight += weight
+	}
+
+	return totalScore / totalWeight
+}
+
+// TeamScores returns each team's fatigue score
+func (tfc *TravelFatigueConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	return tfc.TeamScoresIndexed(NewDrawIndex(draw))
+}
+
+// TeamScoresIndexed returns each team's fatigue score using a shared index
+func (tfc *TravelFatigueConstraint) TeamScoresIndexed(index *DrawIndex) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, team := range index.Teams() {
+		scores[team] = tfc.itinerary(index, team).Score
+	}
+	return scores
+}
+
+// ScoreTeam returns one team's fatigue score
+func (tfc *TravelFatigueConstraint) ScoreTeam(draw *models.Draw, teamID int) float64 {
+	return tfc.itinerary(NewDrawIndex(draw), teamID).Score
+}
+
+// ScoreTeamIndexed returns one team's fatigue score using a shared index
+func (tfc *TravelFatigueConstraint) ScoreTeamIndexed(index *DrawIndex, teamID int) float64 {
+	return tfc.itinerary(index, teamID).Score
+}
+
+// GetTeamWeights returns the weights emphasizing particular teams' scores
+func (tfc *TravelFatigueConstraint) GetTeamWeights() TeamWeights {
+	return tfc.teamWeights
+}
+
+// SetTeamWeights weights particular teams' scores more heavily in the draw's score
+func (tfc *TravelFatigueConstraint) SetTeamWeights(weights TeamWeights) {
+	tfc.teamWeights = weights
+}
+
+// SetLeagueData supplies the team and venue coordinates trips are measured with
+func (tfc *TravelFatigueConstraint) SetLeagueData(data *LeagueData) {
+	tfc.league = data
+}
+
+// GetMaxConsecutiveLongHaul returns how many long-haul trips in a row are allowed
+func (tfc *TravelFatigueConstraint) GetMaxConsecutiveLongHaul() int {
+	return tfc.maxConsecutiveLongHaul
+}
+
+// SetZoneShiftPenalty sets the extra penalty per hour of time zone shift
+func (tfc *TravelFatigueConstraint) SetZoneShiftPenalty(penalty float64) {
+	tfc.zoneShiftPenalty = penalty
+}
+
+// Itineraries returns every team's itinerary, in team order
+func (tfc *TravelFatigueConstraint) Itineraries(draw *models.Draw) []TeamItinerary {
+	index := NewDrawIndex(draw)
+	itineraries := make([]TeamItinerary, 0, len(index.Teams()))
+	for _, team := range index.Teams() {
+		itineraries = append(itineraries, tfc.itinerary(index, team))
+	}
+	return itineraries
+}
+
+// TeamItinerary returns one team's itinerary
+func (tfc *TravelFatigueConstraint) TeamItinerary(draw *models.Draw, teamID int) TeamItinerary {
+	return tfc.itinerary(NewDrawIndex(draw), teamID)
+}
+
+// itinerary walks a team's season in round order. The score is 1.0 less the
+// share of the team's long-haul burden that falls on trips over the limit,
+// each trip weighing 1 plus the zone shift penalty per hour it shifts.
+func (tfc *TravelFatigueConstraint) itinerary(index *DrawIndex, teamID int) TeamItinerary {
+	draw := index.Draw()
+	itinerary := TeamItinerary{TeamID: teamID, Score: 1.0, Legs: []TravelLeg{}}
+	teamMatches := index.TeamMatchesByRound(teamID)
+
+	streak := 0
+	burden, penalty := 0.0, 0.0
+	for round := 1; round <= draw.Rounds; round++ {
+		match, exists := teamMatches[round]
+		if !exists {
+			// A bye is a break from travel
+			streak = 0
+			continue
+		}
+
+		leg := tfc.leg(draw, match, teamID)
+		switch leg.Kind {
+		case geo.LegDrive:
+			itinerary.Drives++
+		case geo.LegShortFlight:
+			itinerary.ShortFlights++
+		case geo.LegLongFlight:
+			itinerary.LongFlights++
+		}
+
+		if leg.Kind != geo.LegLongFlight {
+			streak = 0
+			itinerary.Legs = append(itinerary.Legs, leg)
+			continue
+		}
+
+		streak++
+		leg.ConsecutiveLongHaul = streak
+		if streak > itinerary.LongestLongHaulStreak {
+			itinerary.LongestLongHaulStreak = streak
+		}
+
+		weight := 1 + tfc.zoneShiftPenalty*math.Abs(leg.ZoneShiftHours)
+		burden += weight
+		if streak > tfc.maxConsecutiveLongHaul {
+			leg.Penalized = true
+			itinerary.PenalizedLegs++
+			penalty += weight
+		}
+		itinerary.Legs = append(itinerary.Legs, leg)
+	}
+
+	if burden > 0 {
+		itinerary.Score = 1.0 - penalty/burden
+	}
+	return itinerary
+}
+
+// leg describes a team's trip from its base to a match
+func (tfc *TravelFatigueConstraint) leg(draw *models.Draw, match *models.Match, teamID int) TravelLeg {
+	leg := TravelLeg{Round: match.Round, MatchID: match.ID, VenueID: match.VenueID}
+	leg.Home, _ = match.IsHomeGame(teamID)
+	if opponent, err := match.GetOpponent(teamID); err == nil && opponent != nil {
+		leg.OpponentID = *opponent
+	}
+
+	baseLat, baseLon, ok := tfc.league.TeamHomeLocation(teamID)
+	if !ok {
+		return leg
+	}
+	venueLat, venueLon, ok := tfc.league.MatchLocation(match)
+	if !ok {
+		return leg
+	}
+
+	leg.DistanceKm = geo.HaversineKm(baseLat, baseLon, venueLat, venueLon)
+	leg.Kind = geo.ClassifyLeg(leg.DistanceKm)
+	leg.ZoneShiftHours = geo.ZoneShiftHours(baseLat, baseLon, venueLat, venueLon, legTime(draw, match))
+	return leg
+}
+
+// legTime is when a match is played for working out time zone offsets.
+// Undated matches use the middle of the season, outside daylight saving.
+func legTime(draw *models.Draw, match *models.Match) time.Time {
+	if match.MatchDate != nil {
+		return *match.MatchDate
+	}
+	return time.Date(draw.SeasonYear, time.June, 1, 12, 0, 0, 0, time.UTC)
+}
+
+// TravelFatigue returns the engine's travel fatigue constraint, or one with
+// the default limit when the draw's configuration doesn't score fatigue
+func (ce *ConstraintEngine) TravelFatigue() *TravelFatigueConstraint {
+	for _, weighted := range ce.softConstraints {
+		if fatigue, ok := weighted.Constraint.(*TravelFatigueConstraint); ok {
+			return fatigue
+		}
+	}
+	return NewTravelFatigueConstraint(DefaultMaxConsecutiveLongHaul)
+}
@@ -1,16 +1,23 @@
 package constraints
 
 import (
-	"math"
-
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 )
 
-// TravelMinimizationConstraint minimizes consecutive away games for teams
+// NoConsecutiveAwayLimit disables the consecutive away game limit, leaving
+// only the travel distance limit
+const NoConsecutiveAwayLimit = -1
+
+// TravelMinimizationConstraint minimizes consecutive away games for teams and,
+// when a distance limit is set, the kilometres each team travels
 type TravelMinimizationConstraint struct {
 	BaseConstraint
 	maxConsecutiveAway int
+	maxTotalTravelKm   float64
 	penaltyWeight      float64
+	league             *LeagueData
+	teamWeights        TeamWeights // Emphasizes particular teams in the score
 }
 
 // NewTravelMinimizationConstraint creates a new travel minimization constraint
@@ -34,24 +41,102 @@ func (tmc *TravelMinimizationConstraint) Validate(match *models.Match, draw *mod
 
 // Score calculates how well the draw minimizes travel
 func (tmc *TravelMinimizationConstraint) Score(draw *models.Draw) float64 {
-	teams := tmc.getUniqueTeams(draw)
+	return tmc.ScoreIndexed(NewDrawIndex(draw))
+}
+
+// ScoreIndexed averages the teams' travel scores, weighted by the
+// team weights, using a shared index
+func (tmc *TravelMinimizationConstraint) ScoreIndexed(index *DrawIndex) float64 {
+	teams := index.Teams()
 	if len(teams) == 0 {
 		return 1.0
 	}
 
 	totalScore := 0.0
+	totalWeight := 0.0
 
 	for _, team := range teams {
-		teamScore := tmc.scoreTeamTravel(draw, team)
-		totalScore += teamScore
+		weight := tmc.teamWeights.Weight(team)
+		totalScore += weight * tmc.scoreTeamTravel(index, team)
+		totalWeight += weight
 	}
 
-	return totalScore / float64(len(teams))
+	return totalScore / totalWeight
+}
+
+// TeamScores returns each team's travel score
+func (tmc *TravelMinimizationConstraint) TeamScores(draw *models.Draw) map[int]float64 {
+	return tmc.TeamScoresIndexed(NewDrawIndex(draw))
+}
+
+// TeamScoresIndexed returns each team's travel score using a shared index
+func (tmc *TravelMinimizationConstraint) TeamScoresIndexed(index *DrawIndex) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, team := range index.Teams() {
+		scores[team] = tmc.scoreTeamTravel(index, team)
+	}
+	return scores
+}
+
+// ScoreTeam returns one team's travel score
+func (tmc *TravelMinimizationConstraint) ScoreTeam(draw *models.Draw, teamID int) float64 {
+	return tmc.scoreTeamTravel(NewDrawIndex(draw), teamID)
 }
 
-// scoreTeamTravel calculates the travel score for a specific team
-func (tmc *TravelMinimizationConstraint) scoreTeamTravel(draw *models.Draw, teamID int) float64 {
-	teamMatches := tmc.getTeamMatchesByRound(draw, teamID)
+// ScoreTeamIndexed returns one team's travel score using a shared index
+func (tmc *TravelMinimizationConstraint) ScoreTeamIndexed(index *DrawIndex, teamID int) float64 {
+	return tmc.scoreTeamTravel(index, teamID)
+}
+
+// GetTeamWeights returns the weights emphasizing particular teams' scores
+func (tmc *TravelMinimizationConstraint) GetTeamWeights() TeamWeights {
+	return tmc.teamWeights
+}
+
+// SetTeamWeights weights particular teams' scores more heavily in the draw's score
+func (tmc *TravelMinimizationConstraint) SetTeamWeights(weights TeamWeights) {
+	tmc.teamWeights = weights
+}
+
+// SetLeagueData supplies the team and venue coordinates used for travel distances
+func (tmc *TravelMinimizationConstraint) SetLeagueData(data *LeagueData) {
+	tmc.league = data
+}
+
+// scoreTeamTravel calculates the travel score for a specific team, averaging
+// the away streak and travel distance scores for whichever limits are set
+func (tmc *TravelMinimizationConstraint) scoreTeamTravel(index *DrawIndex, teamID int) float64 {
+	total := 0.0
+	components := 0
+
+	if tmc.maxConsecutiveAway != NoConsecutiveAwayLimit {
+		total += tmc.scoreTeamAwayStreaks(index, teamID)
+		components++
+	}
+	if tmc.maxTotalTravelKm > 0 {
+		total += tmc.scoreTeamDistance(index, teamID)
+		components++
+	}
+
+	if components == 0 {
+		return 1.0
+	}
+	return total / float64(components)
+}
+
+// scoreTeamDistance scores a team's season travel against the distance limit.
+// Teams within the limit score 1.0; beyond it the score falls as limit/travelled.
+func (tmc *TravelMinimizationConstraint) scoreTeamDistance(index *DrawIndex, teamID int) float64 {
+	travelled := tmc.CalculateTravelDistanceIndexed(index, teamID)
+	if travelled <= tmc.maxTotalTravelKm {
+		return 1.0
+	}
+	return tmc.maxTotalTravelKm / travelled
+}
+
+// scoreTeamAwayStreaks scores a team's consecutive away games against the limit
+func (tmc *TravelMinimizationConstraint) scoreTeamAwayStreaks(index *DrawIndex, teamID int) float64 {
+	teamMatches := index.TeamMatchesByRound(teamID)
 	if len(teamMatches) == 0 {
 		return 1.0
 	}
@@ -61,7 +146,7 @@ func (tmc *TravelMinimizationConstraint) scoreTeamTravel(draw *models.Draw, team
 	totalPenalty := 0.0
 
 	// Analyze consecutive away games
-	for round := 1; round <= draw.Rounds; round++ {
+	for round := 1; round <= index.Draw().Rounds; round++ {
 		match, exists := teamMatches[round]
 		if !exists {
 			// Bye round - reset streak
@@ -107,43 +192,19 @@ func (tmc *TravelMinimizationConstraint) scoreTeamTravel(draw *models.Draw, team
 	return score
 }
 
-// getUniqueTeams extracts all unique team IDs from the draw
-func (tmc *TravelMinimizationConstraint) getUniqueTeams(draw *models.Draw) []int {
-	teamSet := make(map[int]bool)
-
-	for _, match := range draw.Matches {
-		if match.HomeTeamID != nil {
-			teamSet[*match.HomeTeamID] = true
-		}
-		if match.AwayTeamID != nil {
-			teamSet[*match.AwayTeamID] = true
-		}
-	}
-
-	var teams []int
-	for teamID := range teamSet {
-		teams = append(teams, teamID)
-	}
-
-	return teams
+// GetMaxConsecutiveAway returns the maximum allowed consecutive away games
+func (tmc *TravelMinimizationConstraint) GetMaxConsecutiveAway() int {
+	return tmc.maxConsecutiveAway
 }
 
-// getTeamMatchesByRound returns team matches organized by round
-func (tmc *TravelMinimizationConstraint) getTeamMatchesByRound(draw *models.Draw, teamID int) map[int]*models.Match {
-	matches := make(map[int]*models.Match)
-
-	for _, match := range draw.Matches {
-		if match.HasTeam(teamID) {
-			matches[match.Round] = match
-		}
-	}
-
-	return matches
+// GetMaxTotalTravelKm returns the season travel limit per team, 0 when distance isn't scored
+func (tmc *TravelMinimizationConstraint) GetMaxTotalTravelKm() float64 {
+	return tmc.maxTotalTravelKm
 }
 
-// GetMaxConsecutiveAway returns the maximum allowed consecutive away games
-func (tmc *TravelMinimizationConstraint) GetMaxConsecutiveAway() int {
-	return tmc.maxConsecutiveAway
+// SetMaxTotalTravelKm sets the season travel limit per team. Zero stops distance being scored.
+func (tmc *TravelMinimizationConstraint) SetMaxTotalTravelKm(km float64) {
+	tmc.maxTotalTravelKm = km
 }
 
 // SetPenaltyWeight sets the penalty weight for excessive consecutive away games
@@ -153,6 +214,12 @@ func (tmc *TravelMinimizationConstraint) SetPenaltyWeight(weight float64) {
 
 // AnalyzeTeamTravel provides detailed travel analysis for a team
 func (tmc *TravelMinimizationConstraint) AnalyzeTeamTravel(draw *models.Draw, teamID int) TravelAnalysis {
+	return tmc.analyzeTeamTravel(NewDrawIndex(draw), teamID)
+}
+
+// analyzeTeamTravel analyzes a team's travel using a shared index
+func (tmc *TravelMinimizationConstraint) analyzeTeamTravel(index *DrawIndex, teamID int) TravelAnalysis {
+	draw := index.Draw()
 	analysis := TravelAnalysis{
 		TeamID:     teamID,
 		TotalGames: 0,
@@ -161,8 +228,15 @@ func (tmc *TravelMinimizationConstraint) AnalyzeTeamTravel(draw *models.Draw, te
 		Streaks:    []ConsecutiveAwayStreak{},
 	}
 
-	teamMatches := tmc.getTeamMatchesByRound(draw, teamID)
+	teamMatches := index.TeamMatchesByRound(teamID)
 	analysis.TotalGames = len(teamMatches)
+	for _, match := range teamMatches {
+		trip := tmc.tripDistance(match, teamID)
+		analysis.TravelKm += trip
+		if trip > analysis.LongestTripKm {
+			analysis.LongestTripKm = trip
+		}
+	}
 
 	consecutiveAwayCount := 0
 	streakStart := 0
@@ -176,7 +250,7 @@ func (tmc *TravelMinimizationConstraint) AnalyzeTeamTravel(draw *models.Draw, te
 					StartRound:   streakStart,
 					EndRound:     round - 1,
 					Length:       consecutiveAwayCount,
-					ExceedsLimit: consecutiveAwayCount > tmc.maxConsecutiveAway,
+					ExceedsLimit: tmc.exceedsStreakLimit(consecutiveAwayCount),
 				})
 				consecutiveAwayCount = 0
 			}
@@ -193,7 +267,7 @@ func (tmc *TravelMinimizationConstraint) AnalyzeTeamTravel(draw *models.Draw, te
 					StartRound:   streakStart,
 					EndRound:     round - 1,
 					Length:       consecutiveAwayCount,
-					ExceedsLimit: consecutiveAwayCount > tmc.maxConsecutiveAway,
+					ExceedsLimit: tmc.exceedsStreakLimit(consecutiveAwayCount),
 				})
 				consecutiveAwayCount = 0
 			}
@@ -213,7 +287,7 @@ func (tmc *TravelMinimizationConstraint) AnalyzeTeamTravel(draw *models.Draw, te
 			StartRound:   streakStart,
 			EndRound:     draw.Rounds,
 			Length:       consecutiveAwayCount,
-			ExceedsLimit: consecutiveAwayCount > tmc.maxConsecutiveAway,
+			ExceedsLimit: tmc.exceedsStreakLimit(consecutiveAwayCount),
 		})
 	}
 
@@ -229,18 +303,29 @@ func (tmc *TravelMinimizationConstraint) AnalyzeTeamTravel(draw *models.Draw, te
 		}
 	}
 
+	analysis.ExceedsTravelLimit = tmc.maxTotalTravelKm > 0 && analysis.TravelKm > tmc.maxTotalTravelKm
+
 	return analysis
 }
 
-// TravelAnalysis contains detailed travel analysis for a team
+// exceedsStreakLimit reports whether an away streak is longer than allowed
+func (tmc *TravelMinimizationConstraint) exceedsStreakLimit(length int) bool {
+	return tmc.maxConsecutiveAway != NoConsecutiveAwayLimit && length > tmc.maxConsecutiveAway
+}
+
+// TravelAnalysis contains detailed travel analysis for a team. Distances are
+// 0 when team and venue coordinates aren't known.
 type TravelAnalysis struct {
-	TeamID            int                     `json:"team_id"`
-	TotalGames        int                     `json:"total_games"`
-	HomeGames         int                     `json:"home_games"`
-	AwayGames         int                     `json:"away_games"`
-	LongestAwayStreak int                     `json:"longest_away_streak"`
-	ViolatingStreaks  int                     `json:"violating_streaks"`
-	Streaks           []ConsecutiveAwayStreak `json:"streaks"`
+	TeamID             int                     `json:"team_id"`
+	TotalGames         int                     `json:"total_games"`
+	HomeGames          int                     `json:"home_games"`
+	AwayGames          int                     `json:"away_games"`
+	LongestAwayStreak  int                     `json:"longest_away_streak"`
+	ViolatingStreaks   int                     `json:"violating_streaks"`
+	TravelKm           float64                 `json:"travel_km"`
+	LongestTripKm      float64                 `json:"longest_trip_km"`
+	ExceedsTravelLimit bool                    `json:"exceeds_travel_limit"`
+	Streaks            []ConsecutiveAwayStreak `json:"streaks"`
 }
 
 // ConsecutiveAwayStreak represents a streak of consecutive away games
@@ -253,11 +338,12 @@ type ConsecutiveAwayStreak struct {
 
 // GetAllTeamTravelAnalysis returns travel analysis for all teams
 func (tmc *TravelMinimizationConstraint) GetAllTeamTravelAnalysis(draw *models.Draw) []TravelAnalysis {
-	teams := tmc.getUniqueTeams(draw)
+	index := NewDrawIndex(draw)
+	teams := index.Teams()
 	analyses := make([]TravelAnalysis, len(teams))
 
 	for i, teamID := range teams {
-		analyses[i] = tmc.AnalyzeTeamTravel(draw, teamID)
+		analyses[i] = tmc.analyzeTeamTravel(index, teamID)
 	}
 
 	return analyses
@@ -285,43 +371,84 @@ func (tmc *TravelMinimizationConstraint) GetWorstTravelTeams(draw *models.Draw,
 	return analyses[:limit]
 }
 
-// CalculateTotalTravelDistance calculates total travel distance (requires venue coordinates)
-func (tmc *TravelMinimizationConstraint) CalculateTravelDistance(draw *models.Draw, teamID int) float64 {
-	teamMatches := tmc.getTeamMatchesByRound(draw, teamID)
-	totalDistance := 0.0
+// GetDrawTravelStatistics summarises travel across every team in the draw
+func (tmc *TravelMinimizationConstraint) GetDrawTravelStatistics(draw *models.Draw) TravelStatistics {
+	analyses := tmc.GetAllTeamTravelAnalysis(draw)
 
-	var previousVenueID *int
+	stats := TravelStatistics{
+		TotalTeams: len(analyses),
+	}
 
-	for round := 1; round <= draw.Rounds; round++ {
-		match, exists := teamMatches[round]
-		if !exists {
-			continue // Bye round
+	for i, analysis := range analyses {
+		stats.TotalTravelKm += analysis.TravelKm
+		if i == 0 || analysis.TravelKm < stats.MinTeamTravelKm {
+			stats.MinTeamTravelKm = analysis.TravelKm
 		}
-
-		// For away games, calculate travel distance
-		if isHome, _ := match.IsHomeGame(teamID); !isHome {
-			if previousVenueID != nil && match.VenueID != nil {
-				// Calculate distance between venues (placeholder - would need actual coordinates)
-				distance := tmc.calculateVenueDistance(*previousVenueID, *match.VenueID)
-				totalDistance += distance
-			}
-			if match.VenueID != nil {
-				previousVenueID = match.VenueID
-			}
-		} else {
-			// Home game - reset to home venue
-			// This would need the team's home venue ID
-			previousVenueID = nil
+		if analysis.TravelKm > stats.MaxTeamTravelKm {
+			stats.MaxTeamTravelKm = analysis.TravelKm
+		}
+		if analysis.LongestTripKm > stats.LongestTripKm {
+			stats.LongestTripKm = analysis.LongestTripKm
+		}
+		if analysis.LongestAwayStreak > stats.LongestAwayStreak {
+			stats.LongestAwayStreak = analysis.LongestAwayStreak
+		}
+		stats.ViolatingStreaks += analysis.ViolatingStreaks
+		if analysis.ExceedsTravelLimit {
+			stats.TeamsOverTravelLimit++
 		}
 	}
 
+	if len(analyses) > 0 {
+		stats.AverageTeamTravelKm = stats.TotalTravelKm / float64(len(analyses))
+	}
+
+	return stats
+}
+
+// TravelStatistics contains overall travel statistics for a draw
+type TravelStatistics struct {
+	TotalTeams           int     `json:"total_teams"`
+	TotalTravelKm        float64 `json:"total_travel_km"`
+	AverageTeamTravelKm  float64 `json:"average_team_travel_km"`
+	MinTeamTravelKm      float64 `json:"min_team_travel_km"`
+	MaxTeamTravelKm      float64 `json:"max_team_travel_km"`
+	LongestTripKm        float64 `json:"longest_trip_km"`
+	LongestAwayStreak    int     `json:"longest_away_streak"`
+	ViolatingStreaks     int     `json:"violating_streaks"`
+	TeamsOverTravelLimit int     `json:"teams_over_travel_limit"`
+}
+
+// CalculateTravelDistance returns the kilometres a team travels over the
+// season. Each match away from the team's base counts as a return trip, so
+// home games moved to another venue count too. Matches without known
+// coordinates add nothing.
+func (tmc *TravelMinimizationConstraint) CalculateTravelDistance(draw *models.Draw, teamID int) float64 {
+	return tmc.CalculateTravelDistanceIndexed(NewDrawIndex(draw), teamID)
+}
+
+// CalculateTravelDistanceIndexed returns a team's season travel using a
+// shared index
+func (tmc *TravelMinimizationConstraint) CalculateTravelDistanceIndexed(index *DrawIndex, teamID int) float64 {
+	totalDistance := 0.0
+
+	for _, match := range index.TeamMatches(teamID) {
+		totalDistance += tmc.tripDistance(match, teamID)
+	}
+
 	return totalDistance
 }
 
-// calculateVenueDistance is a placeholder for actual distance calculation
-func (tmc *TravelMinimizationConstraint) calculateVenueDistance(venue1ID, venue2ID int) float64 {
-	// This would use actual venue coordinates from the database
-	// For now, return a simple placeholder based on venue ID difference
-	// TODO: implement this properly
-	return math.Abs(float64(venue1ID-venue2ID)) * 100 // Placeholder
+// tripDistance returns the return-trip kilometres for a team to play a match
+func (tmc *TravelMinimizationConstraint) tripDistance(match *models.Match, teamID int) float64 {
+	homeLat, homeLon, ok := tmc.league.TeamHomeLocation(teamID)
+	if !ok {
+		return 0
+	}
+	venueLat, venueLon, ok := tmc.league.MatchLocation(match)
+	if !ok {
+		return 0
+	}
+
+	return 2 * geo.HaversineKm(homeLat, homeLon, venueLat, venueLon)
 }
@@ -38,18 +38,17 @@ func (vac *VenueAvailabilityConstraint) Validate(match *models.Match, draw *mode
 		return nil
 	}
 
-	// Skip if match doesn't have a date assigned yet
-	if match.MatchDate == nil {
+	// Check the match date, or its round's dates in the season calendar
+	date, conflict := vac.conflictDate(match)
+	if !conflict {
 		return nil
 	}
-
-	// Check if the match date conflicts with unavailable dates
-	if vac.IsDateUnavailable(*match.MatchDate) {
-		return fmt.Errorf("venue %d is not available on %s",
-			vac.venueID, match.MatchDate.Format("2006-01-02"))
+	if match.MatchDate == nil {
+		return fmt.Errorf("venue %d is not available on any day of round %d",
+			vac.venueID, match.Round)
 	}
-
-	return nil
+	return fmt.Errorf("venue %d is not available on %s",
+		vac.venueID, date.Format("2006-01-02"))
 }
 
 // Score calculates how well the draw satisfies this constraint
@@ -59,9 +58,9 @@ func (vac *VenueAvailabilityConstraint) Score(draw *models.Draw) float64 {
 
 	for _, match := range draw.Matches {
 		// Only consider matches at this venue
-		if match.VenueID != nil && *match.VenueID == vac.venueID && match.MatchDate != nil {
+		if match.VenueID != nil && *match.VenueID == vac.venueID && vac.isChecked(match) {
 			totalMatches++
-			if vac.IsDateUnavailable(*match.MatchDate) {
+			if _, conflict := vac.conflictDate(match); conflict {
 				violatingMatches++
 			}
 		}
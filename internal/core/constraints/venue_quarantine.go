@@ -0,0 +1,92 @@
+package constraints
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// VenueQuarantineConstraint keeps a venue free in a set of rounds, such as
+// while the ground is prepared for finals or hosts a concert. It is checked
+// by round rather than date, so it applies before kickoffs are scheduled.
+type VenueQuarantineConstraint struct {
+	BaseConstraint
+	venueID int
+	rounds  map[int]bool
+	reason  string
+}
+
+// NewVenueQuarantineConstraint creates a new venue quarantine constraint. The
+// reason is optional and only used in violation messages.
+func NewVenueQuarantineConstraint(venueID int, rounds []int, reason string) *VenueQuarantineConstraint {
+	quarantined := make(map[int]bool, len(rounds))
+	for _, round := range rounds {
+		quarantined[round] = true
+	}
+
+	return &VenueQuarantineConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"VenueQuarantine",
+			fmt.Sprintf("Venue %d must not be used in rounds %v", venueID, sortedRounds(quarantined)),
+			true, // This is a hard constraint
+		),
+		venueID: venueID,
+		rounds:  quarantined,
+		reason:  reason,
+	}
+}
+
+// Validate rejects a match at the venue in one of its quarantined rounds
+func (vqc *VenueQuarantineConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	if !vqc.isQuarantined(match) {
+		return nil
+	}
+	if vqc.reason != "" {
+		return fmt.Errorf("venue %d is quarantined in round %d (%s)", vqc.venueID, match.Round, vqc.reason)
+	}
+	return fmt.Errorf("venue %d is quarantined in round %d", vqc.venueID, match.Round)
+}
+
+// Score returns the fraction of the venue's matches played outside its
+// quarantined rounds
+func (vqc *VenueQuarantineConstraint) Score(draw *models.Draw) float64 {
+	totalMatches := 0
+	violatingMatches := 0
+
+	for _, match := range draw.Matches {
+		if match.IsBye() || match.VenueID == nil || *match.VenueID != vqc.venueID {
+			continue
+		}
+		totalMatches++
+		if vqc.rounds[match.Round] {
+			violatingMatches++
+		}
+	}
+
+	// If no matches at this venue, constraint is perfectly satisfied
+	if totalMatches == 0 {
+		return 1.0
+	}
+
+	return float64(totalMatches-violatingMatches) / float64(totalMatches)
+}
+
+// isQuarantined reports whether the match is at the venue in a quarantined round
+func (vqc *VenueQuarantineConstraint) isQuarantined(match *models.Match) bool {
+	return !match.IsBye() && match.VenueID != nil && *match.VenueID == vqc.venueID && vqc.rounds[match.Round]
+}
+
+// GetVenueID returns the venue ID this constraint applies to
+func (vqc *VenueQuarantineConstraint) GetVenueID() int {
+	return vqc.venueID
+}
+
+// GetRounds returns the quarantined rounds in ascending order
+func (vqc *VenueQuarantineConstraint) GetRounds() []int {
+	return sortedRounds(vqc.rounds)
+}
+
+// GetReason returns why the venue is quarantined, if given
+func (vqc *VenueQuarantineConstraint) GetReason() string {
+	return vqc.reason
+}
@@ -0,0 +1,213 @@
+package constraints
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// VenueEvent is a non-fixture event booked at a venue, such as a concert or
+// another code's match, imported from the venue's calendar
+type VenueEvent struct {
+	VenueID int       `json:"venue_id"`
+	Date    time.Time `json:"date"`
+	Name    string    `json:"name"`
+}
+
+// VenueRecoveryConstraint enforces a minimum number of days between consecutive
+// events at the same venue so the turf can recover
+type VenueRecoveryConstraint struct {
+	BaseConstraint
+	minRecoveryDays int         // Default recovery period for every venue
+	venueOverrides  map[int]int // Venue-specific recovery periods
+	externalEvents  []VenueEvent
+	magicRounds     map[int]int // Rounds stacked at one venue, keyed by round
+}
+
+// NewVenueRecoveryConstraint creates a new venue recovery constraint
+func NewVenueRecoveryConstraint(minRecoveryDays int, venueOverrides map[int]int, externalEvents []VenueEvent) *VenueRecoveryConstraint {
+	if venueOverrides == nil {
+		venueOverrides = make(map[int]int)
+	}
+
+	return &VenueRecoveryConstraint{
+		BaseConstraint: NewBaseConstraint(
+			"VenueRecovery",
+			fmt.Sprintf("Venues must have at least %d days between events for turf recovery", minRecoveryDays),
+			true, // This is a hard constraint
+		),
+		minRecoveryDays: minRecoveryDays,
+		venueOverrides:  venueOverrides,
+		externalEvents:  externalEvents,
+	}
+}
+
+// Validate checks if a match is too close to another event at the same venue
+func (vrc *VenueRecoveryConstraint) Validate(match *models.Match, draw *models.Draw) error {
+	conflict := vrc.findConflict(match, draw)
+	if conflict == nil {
+		return nil
+	}
+
+	if conflict.OtherMatchID != 0 {
+		return fmt.Errorf("venue %d needs %d recovery days but match %d is %d days from match %d",
+			conflict.VenueID, conflict.RequiredDays, match.ID, conflict.GapDays, conflict.OtherMatchID)
+	}
+
+	return fmt.Errorf("venue %d needs %d recovery days but match %d is %d days from external event %q",
+		conflict.VenueID, conflict.RequiredDays, match.ID, conflict.GapDays, conflict.ExternalEvent)
+}
+
+// Score returns the fraction of dated venue bookings that respect the recovery period
+func (vrc *VenueRecoveryConstraint) Score(draw *models.Draw) float64 {
+	totalMatches := 0
+	violatingMatches := 0
+
+	for _, match := range draw.Matches {
+		if match.IsBye() || match.VenueID == nil || match.MatchDate == nil {
+			continue
+		}
+
+		totalMatches++
+		if vrc.findConflict(match, draw) != nil {
+			violatingMatches++
+		}
+	}
+
+	if totalMatches == 0 {
+		return 1.0
+	}
+
+	return float64(totalMatches-violatingMatches) / float64(totalMatches)
+}
+
+// findConflict returns the closest event that breaches the recovery period for a match
+func (vrc *VenueRecoveryConstraint) findConflict(match *models.Match, draw *models.Draw) *VenueRecoveryConflict {
+	if match.IsBye() || match.VenueID == nil || match.MatchDate == nil {
+		return nil
+	}
+
+	venueID := *match.VenueID
+	required := vrc.GetRecoveryDays(venueID)
+	var closest *VenueRecoveryConflict
+
+	consider := func(otherDate time.Time, otherMatchID int, eventName string) {
+		gap := daysBetween(*match.MatchDate, otherDate)
+		if gap >= required {
+			return
+		}
+		if closest == nil || gap < closest.GapDays {
+			closest = &VenueRecoveryConflict{
+				VenueID:       venueID,
+				MatchID:       match.ID,
+				OtherMatchID:  otherMatchID,
+				ExternalEvent: eventName,
+				Date:          *match.MatchDate,
+				OtherDate:     otherDate,
+				GapDays:       gap,
+				RequiredDays:  required,
+			}
+		}
+	}
+
+	for _, other := range draw.Matches {
+		if other == match || other.IsBye() || other.VenueID == nil || other.MatchDate == nil {
+			continue
+		}
+		if *other.VenueID != venueID || vrc.isStacked(match, other) {
+			continue
+		}
+		consider(*other.MatchDate, other.ID, "")
+	}
+
+	for _, event := range vrc.externalEvents {
+		if event.VenueID != venueID {
+			continue
+		}
+		consider(event.Date, 0, event.Name)
+	}
+
+	return closest
+}
+
+// isStacked reports whether two matches share a magic round at its venue,
+// where they are played back to back without recovery
+func (vrc *VenueRecoveryConstraint) isStacked(match, other *models.Match) bool {
+	if match.Round != other.Round {
+		return false
+	}
+	venueID, magic := vrc.magicRounds[match.Round]
+	return magic && *match.VenueID == venueID
+}
+
+// daysBetween returns the absolute number of calendar days between two dates
+func daysBetween(a, b time.Time) int {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+
+	days := int(dayB.Sub(dayA).Hours() / 24)
+	if days < 0 {
+		days = -days
+	}
+	return days
+}
+
+// GetMinRecoveryDays returns the default recovery period
+func (vrc *VenueRecoveryConstraint) GetMinRecoveryDays() int {
+	return vrc.minRecoveryDays
+}
+
+// GetVenueOverrides returns the venue-specific recovery periods
+func (vrc *VenueRecoveryConstraint) GetVenueOverrides() map[int]int {
+	return vrc.venueOverrides
+}
+
+// GetExternalEvents returns the imported non-fixture venue events
+func (vrc *VenueRecoveryConstraint) GetExternalEvents() []VenueEvent {
+	return vrc.externalEvents
+}
+
+// GetRecoveryDays returns the recovery period that applies to a venue
+func (vrc *VenueRecoveryConstraint) GetRecoveryDays(venueID int) int {
+	if days, ok := vrc.venueOverrides[venueID]; ok {
+		return days
+	}
+	return vrc.minRecoveryDays
+}
+
+// SetMagicRounds exempts matches sharing a magic round at its venue from
+// recovery between each other
+func (vrc *VenueRecoveryConstraint) SetMagicRounds(rounds map[int]int) {
+	vrc.magicRounds = rounds
+}
+
+// AddExternalEvent registers an imported event at a venue
+func (vrc *VenueRecoveryConstraint) AddExternalEvent(event VenueEvent) {
+	vrc.externalEvents = append(vrc.externalEvents, event)
+}
+
+// GetRecoveryConflicts returns every match that breaches its venue's recovery period
+func (vrc *VenueRecoveryConstraint) GetRecoveryConflicts(draw *models.Draw) []VenueRecoveryConflict {
+	var conflicts []VenueRecoveryConflict
+
+	for _, match := range draw.Matches {
+		if conflict := vrc.findConflict(match, draw); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+
+	return conflicts
+}
+
+// VenueRecoveryConflict describes a booking that is too close to another event
+type VenueRecoveryConflict struct {
+	VenueID       int       `json:"venue_id"`
+	MatchID       int       `json:"match_id"`
+	OtherMatchID  int       `json:"other_match_id,omitempty"`
+	ExternalEvent string    `json:"external_event,omitempty"`
+	Date          time.Time `json:"date"`
+	OtherDate     time.Time `json:"other_date"`
+	GapDays       int       `json:"gap_days"`
+	RequiredDays  int       `json:"required_days"`
+}
@@ -0,0 +1,75 @@
+package constraints
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConstraintConfigFromYAML loads constraint configuration from YAML bytes.
+// Params are read exactly as the same document written in JSON would be.
+func LoadConstraintConfigFromYAML(data []byte) (ConstraintConfig, error) {
+	jsonData, err := YAMLToJSON(data)
+	if err != nil {
+		return ConstraintConfig{}, err
+	}
+	return LoadConstraintConfigFromJSON(jsonData)
+}
+
+// SaveConstraintConfigToYAML saves constraint configuration to YAML bytes
+func SaveConstraintConfigToYAML(config ConstraintConfig) ([]byte, error) {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	
+	return data, nil
+}
+
+// YAMLToJSON converts a YAML document to JSON, so it decodes into the same
+// numbers and strings the JSON loaders and factory expect. Timestamps become
+// YYYY-MM-DD dates when they fall on midnight UTC, and RFC 3339 otherwise.
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	
+	jsonData, err := json.Marshal(jsonCompatible(document))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML to JSON: %w", err)
+	}
+	
+	return jsonData, nil
+}
+
+// jsonCompatible rewrites decoded YAML values that JSON can't represent the same way
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = jsonCompatible(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	case time.Time:
+		if v.Equal(time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC)) {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,70 @@
+package crossdraw
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// VenueConflictReport lists a draw's matches that clash with other
+// competitions' completed draws at shared venues
+type VenueConflictReport struct {
+	DrawID int `json:"draw_id"`
+	// CheckedDrawIDs are the other competitions' completed draws the draw was
+	// checked against
+	CheckedDrawIDs []int                             `json:"checked_draw_ids"`
+	MinGapMinutes  int                               `json:"min_gap_minutes"`
+	Conflicts      []constraints.SharedVenueConflict `json:"conflicts"`
+	CheckedAt      time.Time                         `json:"checked_at"`
+}
+
+// Service detects clashes between draws that share venues
+type Service struct {
+	repository storage.Repositories
+}
+
+// NewService creates a new cross-draw service
+func NewService(repository storage.Repositories) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// VenueConflicts checks a draw against every other competition's completed
+// draw, reporting matches at the same venue and day whose kickoffs are less
+// than minGap apart. The draw itself may be in any status.
+func (s *Service) VenueConflicts(ctx context.Context, drawID int, minGap time.Duration) (*VenueConflictReport, error) {
+	draw, err := s.repository.Draws().GetWithMatches(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := constraints.LoadActiveDraws(ctx, s.repository.Draws())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active draws: %w", err)
+	}
+
+	constraint := constraints.NewSharedVenueConstraint(minGap)
+	constraint.SetActiveDraws(active)
+
+	report := &VenueConflictReport{
+		DrawID:         draw.ID,
+		CheckedDrawIDs: []int{},
+		MinGapMinutes:  int(minGap.Minutes()),
+		Conflicts:      constraint.GetConflicts(draw),
+		CheckedAt:      time.Now(),
+	}
+	if report.Conflicts == nil {
+		report.Conflicts = []constraints.SharedVenueConflict{}
+	}
+	for _, other := range active {
+		if other.ID != draw.ID && !constraints.SameCompetition(other, draw) {
+			report.CheckedDrawIDs = append(report.CheckedDrawIDs, other.ID)
+		}
+	}
+
+	return report, nil
+}
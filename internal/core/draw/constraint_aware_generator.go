@@ -3,6 +3,7 @@ package draw
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
@@ -29,6 +30,12 @@ func NewConstraintAwareGenerator(teams []*models.Team, rounds int, constraintCon
 		return nil, fmt.Errorf("failed to create constraint engine: %w", err)
 	}
 	
+	for round, venueID := range engine.MagicRounds() {
+		if err := baseGenerator.SetMagicRound(round, venueID); err != nil {
+			return nil, err
+		}
+	}
+	
 	return &ConstraintAwareGenerator{
 		Generator:        baseGenerator,
 		constraintEngine: engine,
@@ -48,8 +55,8 @@ func NewConstraintAwareGeneratorFromJSON(teams []*models.Team, rounds int, confi
 
 // GenerateWithConstraints creates a draw and validates it against constraints
 func (cag *ConstraintAwareGenerator) GenerateWithConstraints() (*models.Draw, []error, error) {
-	// Generate the base draw
-	draw, err := cag.GenerateRoundRobin()
+	// Generate the base draw from the fixture template, or a round-robin
+	draw, err := cag.Generate()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate base draw: %w", err)
 	}
@@ -106,8 +113,12 @@ func (cag *ConstraintAwareGenerator) getConstraintConfig() constraints.Constrain
 		Soft: []constraints.SoftConstraintConfig{},
 	}
 	
-	// Add hard constraints
+	// Add hard constraints. Stored team unavailability is loaded with the
+	// league, so it stays out of the draw's own configuration.
 	for _, constraint := range cag.constraintEngine.GetHardConstraints() {
+		if _, stored := constraint.(*constraints.StoredTeamAvailabilityConstraint); stored {
+			continue
+		}
 		hardConfig := constraints.HardConstraintConfig{
 			Type:   cag.getConstraintType(constraint),
 			Params: cag.getConstraintParams(constraint),
@@ -130,26 +141,7 @@ func (cag *ConstraintAwareGenerator) getConstraintConfig() constraints.Constrain
 
 // getConstraintType maps constraint names to configuration types
 func (cag *ConstraintAwareGenerator) getConstraintType(constraint constraints.Constraint) string {
-	switch constraint.(type) {
-	case *constraints.ByeConstraint:
-		return "bye_constraint"
-	case *constraints.DoubleUpConstraint:
-		return "double_up"
-	case *constraints.VenueAvailabilityConstraint:
-		return "venue_availability"
-	case *constraints.TeamAvailabilityConstraint:
-		return "team_availability"
-	case *constraints.TravelMinimizationConstraint:
-		return "travel_minimization"
-	case *constraints.RestPeriodConstraint:
-		return "rest_period"
-	case *constraints.PrimeTimeSpreadConstraint:
-		return "prime_time_spread"
-	case *constraints.HomeAwayBalanceConstraint:
-		return "home_away_balance"
-	default:
-		return constraint.Name()
-	}
+	return constraints.TypeOf(constraint)
 }
 
 // getConstraintParams extracts parameters from a constraint (basic implementation)
@@ -161,21 +153,126 @@ func (cag *ConstraintAwareGenerator) getConstraintParams(constraint constraints.
 	switch c := constraint.(type) {
 	case *constraints.DoubleUpConstraint:
 		params["min_rounds_separation"] = c.GetMinRoundsSeparation()
+	case *constraints.PrimeTimeCapConstraint:
+		params["min_appearances"] = c.GetMinAppearances()
+		if c.GetMaxAppearances() != constraints.NoPrimeTimeCap {
+			params["max_appearances"] = c.GetMaxAppearances()
+		}
+	case *constraints.VenueRecoveryConstraint:
+		params["min_recovery_days"] = c.GetMinRecoveryDays()
+		if len(c.GetVenueOverrides()) > 0 {
+			overrides := make(map[string]int)
+			for venueID, days := range c.GetVenueOverrides() {
+				overrides[strconv.Itoa(venueID)] = days
+			}
+			params["venue_recovery_days"] = overrides
+		}
+		if len(c.GetExternalEvents()) > 0 {
+			events := make([]map[string]interface{}, len(c.GetExternalEvents()))
+			for i, event := range c.GetExternalEvents() {
+				events[i] = map[string]interface{}{
+					"venue_id": event.VenueID,
+					"date":     event.Date.Format("2006-01-02"),
+					"name":     event.Name,
+				}
+			}
+			params["external_events"] = events
+		}
+	case *constraints.ByeRoundWindowConstraint:
+		params["bye_rounds"] = c.GetAllowedRounds()
+	case *constraints.MagicRoundConstraint:
+		params["round"] = c.GetRound()
+		params["venue_id"] = c.GetVenueID()
+		params["weekend_days"] = c.GetWeekendDays()
+	case *constraints.CustomExpressionConstraint:
+		params["expression"] = c.GetExpression()
+		params["description"] = c.Description()
+	case *constraints.RivalryRoundConstraint:
+		fixtures := make([]map[string]interface{}, len(c.GetFixtures()))
+		for i, fixture := range c.GetFixtures() {
+			fixtures[i] = map[string]interface{}{
+				"team_a": fixture.TeamA,
+				"team_b": fixture.TeamB,
+				"round":  fixture.Round,
+			}
+		}
+		params["fixtures"] = fixtures
+	case *constraints.RegionSpreadConstraint:
+		regions := make(map[string]string)
+		for teamID, region := range c.GetTeamRegions() {
+			regions[strconv.Itoa(teamID)] = region
+		}
+		params["team_regions"] = regions
+		params["min_home_matches"] = c.GetMinHomeMatches()
+		if c.GetMaxHomeMatches() != constraints.NoRegionMaximum {
+			params["max_home_matches"] = c.GetMaxHomeMatches()
+		}
 	case *constraints.TravelMinimizationConstraint:
-		params["max_consecutive_away"] = c.GetMaxConsecutiveAway()
+		if c.GetMaxConsecutiveAway() != constraints.NoConsecutiveAwayLimit {
+			params["max_consecutive_away"] = c.GetMaxConsecutiveAway()
+		}
+		if c.GetMaxTotalTravelKm() > 0 {
+			params["max_total_travel_km"] = c.GetMaxTotalTravelKm()
+		}
+	case *constraints.HalfSeasonTravelConstraint:
+		if c.GetSplitRound() > 0 {
+			params["split_round"] = c.GetSplitRound()
+		}
+		params["away_tolerance"] = c.GetAwayTolerance()
+		params["distance_tolerance"] = c.GetDistanceTolerance()
 	case *constraints.RestPeriodConstraint:
 		params["min_rest_days"] = c.GetMinRestDays()
+		if c.GetMaxShortTurnarounds() != constraints.NoTurnaroundLimit {
+			params["max_short_turnarounds"] = c.GetMaxShortTurnarounds()
+			params["short_turnaround_days"] = c.GetShortTurnaroundDays()
+		}
 	case *constraints.PrimeTimeSpreadConstraint:
 		params["target_ratio"] = c.GetTargetPrimeTimeRatio()
 		params["max_deviation"] = c.GetMaxDeviation()
 	case *constraints.HomeAwayBalanceConstraint:
 		params["max_deviation"] = c.GetMaxDeviation()
+	case *constraints.PrimeTimeAttractivenessConstraint:
+		// Exported weights are already computed, so freeze them
+		params["freeze_weights"] = true
+		params["frozen_weights"] = c.GetWeights()
+	case *constraints.DerbyConstraint:
+		params["max_distance_km"] = c.GetMaxDistanceKm()
+		if len(c.GetMarqueeRounds()) > 0 {
+			params["marquee_rounds"] = c.GetMarqueeRounds()
+		}
+	case *constraints.StrengthSpreadConstraint:
+		strengths := make(map[string]float64)
+		for teamID, strength := range c.GetTeamStrengths() {
+			strengths[strconv.Itoa(teamID)] = strength
+		}
+		params["team_strengths"] = strengths
+		params["top_teams"] = c.GetTopTeams()
+		params["window_rounds"] = c.GetWindowRounds()
+		params["max_tough_in_window"] = c.GetMaxToughInWindow()
+	case *constraints.DoubleHeaderConstraint:
+		params["partner_draw_id"] = c.ReferencedDrawID()
+	case *constraints.FixtureVariationConstraint:
+		params["previous_draw_id"] = c.ReferencedDrawID()
+	case *constraints.SharedVenueConstraint:
+		params["min_gap_minutes"] = int(c.GetMinGap().Minutes())
 	case *constraints.VenueAvailabilityConstraint:
 		params["venue_id"] = c.GetVenueID()
 		params["unavailable_dates"] = cag.formatDates(c.GetUnavailableDatesForVenue())
+	case *constraints.VenueQuarantineConstraint:
+		params["venue_id"] = c.GetVenueID()
+		params["rounds"] = c.GetRounds()
+		if c.GetReason() != "" {
+			params["reason"] = c.GetReason()
+		}
 	case *constraints.TeamAvailabilityConstraint:
 		params["team_id"] = c.GetTeamID()
 		params["unavailable_dates"] = cag.formatDates(c.GetUnavailableDatesForTeam())
+	case constraints.ParamsExporter:
+		params = c.Params()
+	}
+	
+	if weighted, ok := constraint.(constraints.TeamWeighted); ok && len(weighted.GetTeamWeights()) > 0 {
+		params["team_weights"] = weighted.GetTeamWeights().Params()
 	}
 	
 	return params
@@ -284,6 +381,13 @@ func (cag *ConstraintAwareGenerator) UpdateConstraints(config constraints.Constr
 		return fmt.Errorf("failed to create new constraint engine: %w", err)
 	}
 	
+	cag.magicRounds = nil
+	for round, venueID := range engine.MagicRounds() {
+		if err := cag.SetMagicRound(round, venueID); err != nil {
+			return err
+		}
+	}
+	
 	cag.constraintEngine = engine
 	return nil
 }
@@ -293,9 +397,11 @@ func (cag *ConstraintAwareGenerator) GetConstraintTypeInfo() map[string]constrai
 	return constraints.GetConstraintTypeInfo()
 }
 
-// ValidateConstraintConfig validates a constraint configuration without applying it
+// ValidateConstraintConfig validates a constraint configuration for the
+// generator's teams and rounds without applying it
 func (cag *ConstraintAwareGenerator) ValidateConstraintConfig(config constraints.ConstraintConfig) error {
-	return constraints.ValidateConstraintConfig(config)
+	_, err := constraints.ValidateConstraintConfig(config, constraints.ConflictContext{Teams: len(cag.teams), Rounds: cag.rounds})
+	return err
 }
 
 // ExportConstraintConfig exports the current constraint configuration as JSON
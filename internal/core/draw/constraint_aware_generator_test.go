@@ -404,6 +404,58 @@ func TestValidateConstraintConfig(t *testing.T) {
 	}
 }
 
+// TestMagicRoundGeneration tests that a magic_round constraint moves its round to one venue
+func TestMagicRoundGeneration(t *testing.T) {
+	teams := createConstraintTestTeams()
+	config := constraints.ConstraintConfig{
+		Hard: []constraints.HardConstraintConfig{
+			{Type: "magic_round", Params: map[string]interface{}{"round": 3.0, "venue_id": 2.0}},
+		},
+	}
+	
+	generator, err := NewConstraintAwareGenerator(teams, 5, config)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	
+	draw, violations, err := generator.GenerateWithConstraints()
+	if err != nil {
+		t.Fatalf("Failed to generate draw: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Magic round draw should have no violations, got %v", violations)
+	}
+	
+	for _, match := range draw.Matches {
+		if match.Round == 3 {
+			if *match.VenueID != 2 {
+				t.Errorf("Round 3 match at venue %d, want the magic round venue 2", *match.VenueID)
+			}
+		} else if *match.VenueID != *teams[*match.HomeTeamID-1].VenueID {
+			t.Errorf("Round %d match should stay at the home team's venue", match.Round)
+		}
+	}
+	
+	// The magic round survives exporting the configuration
+	exported, err := generator.ExportConstraintConfig()
+	if err != nil {
+		t.Fatalf("Failed to export config: %v", err)
+	}
+	reloaded, err := NewConstraintAwareGeneratorFromJSON(teams, 5, exported)
+	if err != nil {
+		t.Fatalf("Failed to reload exported config: %v", err)
+	}
+	if rounds := reloaded.GetConstraintEngine().MagicRounds(); rounds[3] != 2 {
+		t.Errorf("Expected exported magic round 3 at venue 2, got %v", rounds)
+	}
+	
+	// A magic round after the season can't be generated
+	config.Hard[0].Params["round"] = 8.0
+	if _, err := NewConstraintAwareGenerator(teams, 5, config); err == nil {
+		t.Error("Magic round outside the season should be rejected")
+	}
+}
+
 // createConstraintTestTeams creates a set of teams for testing
 func createConstraintTestTeams() []*models.Team {
 	return []*models.Team{
@@ -0,0 +1,465 @@
+package draw
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// maxColoringAttempts is how many edge orders are tried when fitting the
+// repeat matchups into the rounds left after the single round-robin
+const maxColoringAttempts = 50
+
+// FixtureTemplate describes a season where teams don't play everyone twice:
+// every team plays each opponent once, plus RepeatOpponents opponents a second
+// time. Repeat opponents are drawn from the team's own ladder pool first, so a
+// 17-team NRL season of 24 games is a template with 8 repeat opponents.
+type FixtureTemplate struct {
+	// Pools groups team IDs by the previous season's ladder, top pool first.
+	// Teams left out of every pool are treated as one extra pool at the bottom.
+	Pools [][]int `json:"pools,omitempty"`
+	// RepeatOpponents is how many opponents each team plays a second time
+	RepeatOpponents int `json:"repeat_opponents"`
+	// RepeatMatchups fixes exactly who plays whom twice, overriding the pools.
+	// Every team must appear in RepeatOpponents of them.
+	RepeatMatchups []Matchup `json:"repeat_matchups,omitempty"`
+}
+
+// Matchup is a pair of teams, in no particular home and away order
+type Matchup struct {
+	TeamA int `json:"team_a"`
+	TeamB int `json:"team_b"`
+}
+
+// Validate checks the template against the teams it will be used with
+func (ft FixtureTemplate) Validate(teams []*models.Team) error {
+	numTeams := len(teams)
+	if ft.RepeatOpponents < 0 {
+		return errors.New("repeat_opponents cannot be negative")
+	}
+	if ft.RepeatOpponents > numTeams-1 {
+		return fmt.Errorf("repeat_opponents cannot exceed %d with %d teams", numTeams-1, numTeams)
+	}
+	if (numTeams*ft.RepeatOpponents)%2 != 0 {
+		return fmt.Errorf("%d teams can't each have %d repeat opponents; one of them must be even", numTeams, ft.RepeatOpponents)
+	}
+
+	known := make(map[int]bool)
+	for _, team := range teams {
+		known[team.ID] = true
+	}
+	seen := make(map[int]bool)
+	for _, pool := range ft.Pools {
+		for _, teamID := range pool {
+			if !known[teamID] {
+				return fmt.Errorf("pool team %d is not in the competition", teamID)
+			}
+			if seen[teamID] {
+				return fmt.Errorf("team %d is in more than one pool", teamID)
+			}
+			seen[teamID] = true
+		}
+	}
+
+	if len(ft.RepeatMatchups) == 0 {
+		return nil
+	}
+	counts := make(map[int]int)
+	paired := make(map[string]bool)
+	for _, matchup := range ft.RepeatMatchups {
+		if !known[matchup.TeamA] || !known[matchup.TeamB] {
+			return fmt.Errorf("repeat matchup %d v %d has a team not in the competition", matchup.TeamA, matchup.TeamB)
+		}
+		if matchup.TeamA == matchup.TeamB {
+			return fmt.Errorf("team %d can't repeat against itself", matchup.TeamA)
+		}
+		key := pairKey(matchup.TeamA, matchup.TeamB)
+		if paired[key] {
+			return fmt.Errorf("repeat matchup %d v %d is listed twice", matchup.TeamA, matchup.TeamB)
+		}
+		paired[key] = true
+		counts[matchup.TeamA]++
+		counts[matchup.TeamB]++
+	}
+	for _, team := range teams {
+		if counts[team.ID] != ft.RepeatOpponents {
+			return fmt.Errorf("team %d is in %d repeat matchups, want %d", team.ID, counts[team.ID], ft.RepeatOpponents)
+		}
+	}
+
+	return nil
+}
+
+// SingleRounds returns the rounds a single round-robin of the teams takes
+func SingleRounds(numTeams int) int {
+	if numTeams%2 == 1 {
+		return numTeams
+	}
+	return numTeams - 1
+}
+
+// RepeatRounds returns the fewest rounds the template's repeat matchups can
+// fit in. With an odd number of teams a team sits out every round, so the
+// repeats can need more rounds than each team has repeat opponents.
+func (ft FixtureTemplate) RepeatRounds(numTeams int) int {
+	rounds := ft.RepeatOpponents
+	if numTeams%2 == 1 && rounds > 0 {
+		matchups := numTeams * ft.RepeatOpponents / 2
+		perRound := numTeams / 2
+		if needed := (matchups + perRound - 1) / perRound; needed > rounds {
+			rounds = needed
+		}
+	}
+	return rounds
+}
+
+// SetTemplate makes Generate build the draw from a fixture template instead of
+// a plain round-robin
+func (g *Generator) SetTemplate(template FixtureTemplate) error {
+	if err := g.checkTemplate(template); err != nil {
+		return err
+	}
+	g.template = &template
+	return nil
+}
+
+// checkTemplate validates a template and that the season has room for it
+func (g *Generator) checkTemplate(template FixtureTemplate) error {
+	if err := template.Validate(g.teams); err != nil {
+		return err
+	}
+	if minRounds := SingleRounds(len(g.teams)) + template.RepeatRounds(len(g.teams)); g.rounds < minRounds {
+		return fmt.Errorf("need at least %d rounds for %d teams with %d repeat opponents, have %d",
+			minRounds, len(g.teams), template.RepeatOpponents, g.rounds)
+	}
+	return nil
+}
+
+// Generate creates the draw from the fixture template when one is set, or a
+// round-robin otherwise, built around any pinned fixtures. It then rotates
+// home games across each team's venues and moves magic rounds to their venue.
+func (g *Generator) Generate() (*models.Draw, error) {
+	var draw *models.Draw
+	var err error
+	if len(g.pins) > 0 {
+		draw, err = g.generatePinned()
+	} else {
+		draw, err = g.generateFixture()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	AssignHomeVenues(draw, g.teams)
+	AssignMagicRounds(draw, g.magicRounds)
+	return draw, nil
+}
+
+// generateFixture creates the fixture from the template when one is set, or a
+// round-robin otherwise
+func (g *Generator) generateFixture() (*models.Draw, error) {
+	if g.template != nil {
+		return g.GenerateFromTemplate(*g.template)
+	}
+	return g.GenerateRoundRobin()
+}
+
+// GenerateFromTemplate creates a draw where every team plays each opponent
+// once, followed by rounds of repeat matchups with home and away reversed.
+// The draw needs enough rounds for both; some repeat pairings only fit with
+// one round more than RepeatRounds.
+func (g *Generator) GenerateFromTemplate(template FixtureTemplate) (*models.Draw, error) {
+	if err := g.checkTemplate(template); err != nil {
+		return nil, err
+	}
+
+	singleRounds := SingleRounds(len(g.teams))
+	singleGen, err := NewGenerator(g.teams, singleRounds)
+	if err != nil {
+		return nil, err
+	}
+	singleGen.seed = g.seed
+
+	draw, err := singleGen.GenerateRoundRobin()
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeat meetings reverse the home team of the first meeting
+	firstHome := make(map[string]int)
+	for _, match := range draw.Matches {
+		firstHome[pairKey(*match.HomeTeamID, *match.AwayTeamID)] = *match.HomeTeamID
+	}
+
+	order := ladderOrder(g.teams, template.Pools)
+	var repeats [][2]int
+	if len(template.RepeatMatchups) > 0 {
+		repeats = fixedMatchups(order, template.RepeatMatchups)
+	} else {
+		repeats = repeatMatchups(order, template.RepeatOpponents)
+	}
+
+	var seed int64
+	if g.seed != nil {
+		seed = *g.seed
+	}
+	rounds, err := assignRounds(repeats, len(order), g.rounds-singleRounds, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	venues := make(map[int]*int)
+	for _, team := range g.teams {
+		venues[team.ID] = team.VenueID
+	}
+	for i, pair := range repeats {
+		a, b := order[pair[0]].ID, order[pair[1]].ID
+		home, away := b, a
+		if firstHome[pairKey(a, b)] == b {
+			home, away = a, b
+		}
+		homeID, awayID := home, away
+		draw.Matches = append(draw.Matches, &models.Match{
+			Round:      singleRounds + rounds[i] + 1,
+			HomeTeamID: &homeID,
+			AwayTeamID: &awayID,
+			VenueID:    venues[home],
+		})
+	}
+
+	draw.Name = fmt.Sprintf("Template Draw - %d teams, %d repeat opponents", len(g.teams), template.RepeatOpponents)
+	draw.Rounds = g.rounds
+	draw.Byes = draw.ByesFromMatches()
+	return draw, nil
+}
+
+// ladderOrder lists the teams pool by pool, with unpooled teams last
+func ladderOrder(teams []*models.Team, pools [][]int) []*models.Team {
+	byID := make(map[int]*models.Team)
+	for _, team := range teams {
+		byID[team.ID] = team
+	}
+
+	var order []*models.Team
+	placed := make(map[int]bool)
+	for _, pool := range pools {
+		for _, teamID := range pool {
+			order = append(order, byID[teamID])
+			placed[teamID] = true
+		}
+	}
+	for _, team := range teams {
+		if !placed[team.ID] {
+			order = append(order, team)
+		}
+	}
+
+	return order
+}
+
+// repeatMatchups picks repeat opponents so every team has exactly repeats of
+// them, using the Havel-Hakimi construction: the team with the most repeats
+// still to place is paired with the teams needing the most, preferring the
+// closest on the ladder. Teams are given as indexes into the ladder order.
+func repeatMatchups(order []*models.Team, repeats int) [][2]int {
+	numTeams := len(order)
+	need := make([]int, numTeams)
+	for i := range need {
+		need[i] = repeats
+	}
+	done := make([]bool, numTeams)
+
+	var pairs [][2]int
+	for {
+		// The next team is the one needing the most, highest on the ladder first
+		team := -1
+		for i := 0; i < numTeams; i++ {
+			if !done[i] && need[i] > 0 && (team == -1 || need[i] > need[team]) {
+				team = i
+			}
+		}
+		if team == -1 {
+			break
+		}
+		done[team] = true
+
+		var candidates []int
+		for i := 0; i < numTeams; i++ {
+			if !done[i] && need[i] > 0 {
+				candidates = append(candidates, i)
+			}
+		}
+		// Most needed first, closest on the ladder between equals
+		for i := 0; i < len(candidates)-1; i++ {
+			for j := i + 1; j < len(candidates); j++ {
+				a, b := candidates[i], candidates[j]
+				if need[b] > need[a] || (need[b] == need[a] && ladderDistance(team, b) < ladderDistance(team, a)) {
+					candidates[i], candidates[j] = b, a
+				}
+			}
+		}
+
+		for _, opponent := range candidates[:need[team]] {
+			pairs = append(pairs, [2]int{team, opponent})
+			need[opponent]--
+		}
+		need[team] = 0
+	}
+
+	return pairs
+}
+
+// fixedMatchups converts explicit matchups into indexes into the ladder order
+func fixedMatchups(order []*models.Team, matchups []Matchup) [][2]int {
+	index := make(map[int]int)
+	for i, team := range order {
+		index[team.ID] = i
+	}
+
+	pairs := make([][2]int, len(matchups))
+	for i, matchup := range matchups {
+		pairs[i] = [2]int{index[matchup.TeamA], index[matchup.TeamB]}
+	}
+	return pairs
+}
+
+// ladderDistance is how far apart two ladder positions are
+func ladderDistance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// assignRounds places each matchup in one of numRounds rounds so no team plays
+// twice in a round. It colours the matchups greedily, swapping two rounds
+// along an alternating chain when a matchup has no round free for both teams,
+// and retries with shuffled orders before giving up.
+func assignRounds(pairs [][2]int, numTeams, numRounds int, seed int64) ([]int, error) {
+	rng := rand.New(rand.NewSource(seed))
+	order := make([]int, len(pairs))
+	for i := range order {
+		order[i] = i
+	}
+
+	for attempt := 0; attempt < maxColoringAttempts; attempt++ {
+		if attempt > 0 {
+			rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		}
+		if rounds, ok := colorMatchups(pairs, order, numTeams, numRounds); ok {
+			return rounds, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to fit %d repeat matchups into %d rounds, add a round to the season", len(pairs), numRounds)
+}
+
+// colorMatchups attempts one greedy colouring of the matchups in the given order
+func colorMatchups(pairs [][2]int, order []int, numTeams, numRounds int) ([]int, bool) {
+	// playing[team][round] is the matchup the team plays that round, or -1
+	playing := make([][]int, numTeams)
+	for team := range playing {
+		playing[team] = make([]int, numRounds)
+		for round := range playing[team] {
+			playing[team][round] = -1
+		}
+	}
+	rounds := make([]int, len(pairs))
+
+	place := func(index, round int) {
+		rounds[index] = round
+		playing[pairs[index][0]][round] = index
+		playing[pairs[index][1]][round] = index
+	}
+
+	for _, index := range order {
+		u, v := pairs[index][0], pairs[index][1]
+
+		placed := false
+		for round := 0; round < numRounds && !placed; round++ {
+			if playing[u][round] == -1 && playing[v][round] == -1 {
+				place(index, round)
+				placed = true
+			}
+		}
+
+		// Free a round at v by swapping rounds a and b along the chain from v
+		for a := 0; a < numRounds && !placed; a++ {
+			if playing[u][a] != -1 {
+				continue
+			}
+			for b := 0; b < numRounds && !placed; b++ {
+				if b == a || playing[v][b] != -1 {
+					continue
+				}
+				chain := alternatingChain(pairs, playing, rounds, v, a, b)
+				if chainTouches(pairs, chain, u) {
+					continue
+				}
+				for _, edge := range chain {
+					x, y := pairs[edge][0], pairs[edge][1]
+					playing[x][rounds[edge]], playing[y][rounds[edge]] = -1, -1
+				}
+				for _, edge := range chain {
+					if rounds[edge] == a {
+						place(edge, b)
+					} else {
+						place(edge, a)
+					}
+				}
+				place(index, a)
+				placed = true
+			}
+		}
+
+		if !placed {
+			return nil, false
+		}
+	}
+
+	return rounds, true
+}
+
+// alternatingChain follows matchups from team in rounds a, b, a, ... until
+// the chain ends
+func alternatingChain(pairs [][2]int, playing [][]int, rounds []int, team, a, b int) []int {
+	var chain []int
+	round := a
+	for {
+		edge := playing[team][round]
+		if edge == -1 {
+			return chain
+		}
+		chain = append(chain, edge)
+		if pairs[edge][0] == team {
+			team = pairs[edge][1]
+		} else {
+			team = pairs[edge][0]
+		}
+		if round == a {
+			round = b
+		} else {
+			round = a
+		}
+	}
+}
+
+// chainTouches reports whether any matchup in the chain involves the team
+func chainTouches(pairs [][2]int, chain []int, team int) bool {
+	for _, edge := range chain {
+		if pairs[edge][0] == team || pairs[edge][1] == team {
+			return true
+		}
+	}
+	return false
+}
+
+// pairKey identifies a matchup regardless of which team is at home
+func pairKey(a, b int) string {
+	if a > b {
+		a, b = b, a
+	}
+	return fmt.Sprintf("%d-%d", a, b)
+}
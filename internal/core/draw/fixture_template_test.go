@@ -0,0 +1,170 @@
+package draw
+
+import (
+	"testing"
+)
+
+func TestGenerateFromTemplate_NRLSeason(t *testing.T) {
+	teams := createTestTeams(17)
+	template := FixtureTemplate{
+		Pools: [][]int{
+			{1, 2, 3, 4, 5, 6},
+			{7, 8, 9, 10, 11, 12},
+			{13, 14, 15, 16, 17},
+		},
+		RepeatOpponents: 8,
+	}
+
+	gen, err := NewGenerator(teams, 27)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	gen.SetSeed(42)
+	if err := gen.SetTemplate(template); err != nil {
+		t.Fatalf("SetTemplate() error = %v", err)
+	}
+
+	draw, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// 136 single round-robin matches plus 17 * 8 / 2 repeats
+	if len(draw.Matches) != 204 {
+		t.Errorf("total matches = %d, want 204", len(draw.Matches))
+	}
+	if draw.Rounds != 27 {
+		t.Errorf("rounds = %d, want 27", draw.Rounds)
+	}
+
+	games := make(map[int]int)
+	meetings := make(map[string][]int) // pair -> home team of each meeting
+	playing := make(map[int]map[int]bool)
+	for _, match := range draw.Matches {
+		home, away := *match.HomeTeamID, *match.AwayTeamID
+		if match.Round < 1 || match.Round > 27 {
+			t.Fatalf("match in round %d, outside the season", match.Round)
+		}
+		if playing[match.Round] == nil {
+			playing[match.Round] = make(map[int]bool)
+		}
+		if playing[match.Round][home] || playing[match.Round][away] {
+			t.Errorf("a team plays twice in round %d", match.Round)
+		}
+		playing[match.Round][home] = true
+		playing[match.Round][away] = true
+
+		games[home]++
+		games[away]++
+		key := pairKey(home, away)
+		meetings[key] = append(meetings[key], home)
+	}
+
+	for _, team := range teams {
+		if games[team.ID] != 24 {
+			t.Errorf("team %d plays %d games, want 24", team.ID, games[team.ID])
+		}
+	}
+
+	repeats := make(map[int][]int)
+	for i, a := range teams {
+		for _, b := range teams[i+1:] {
+			homes := meetings[pairKey(a.ID, b.ID)]
+			switch len(homes) {
+			case 1:
+			case 2:
+				if homes[0] == homes[1] {
+					t.Errorf("teams %d and %d should host one meeting each", a.ID, b.ID)
+				}
+				repeats[a.ID] = append(repeats[a.ID], b.ID)
+				repeats[b.ID] = append(repeats[b.ID], a.ID)
+			default:
+				t.Errorf("teams %d and %d meet %d times", a.ID, b.ID, len(homes))
+			}
+		}
+	}
+	for _, team := range teams {
+		if len(repeats[team.ID]) != 8 {
+			t.Errorf("team %d has %d repeat opponents, want 8", team.ID, len(repeats[team.ID]))
+		}
+	}
+
+	// The top team repeats against its whole pool
+	pool := map[int]bool{2: true, 3: true, 4: true, 5: true, 6: true}
+	inPool := 0
+	for _, opponent := range repeats[1] {
+		if pool[opponent] {
+			inPool++
+		}
+	}
+	if inPool != len(pool) {
+		t.Errorf("team 1 repeats against %d of its pool, want %d", inPool, len(pool))
+	}
+}
+
+func TestGenerateFromTemplate_RepeatMatchups(t *testing.T) {
+	teams := createTestTeams(6)
+	// 1-2, 3-4 and 5-6 meet twice, overriding the pools
+	template := FixtureTemplate{
+		Pools:           [][]int{{1, 3, 5}, {2, 4, 6}},
+		RepeatOpponents: 1,
+		RepeatMatchups:  []Matchup{{1, 2}, {3, 4}, {5, 6}},
+	}
+
+	gen, err := NewGenerator(teams, 6)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	draw, err := gen.GenerateFromTemplate(template)
+	if err != nil {
+		t.Fatalf("GenerateFromTemplate() error = %v", err)
+	}
+
+	repeated := make(map[string]bool)
+	for _, match := range draw.Matches {
+		if match.Round == 6 {
+			repeated[pairKey(*match.HomeTeamID, *match.AwayTeamID)] = true
+		}
+	}
+	for _, matchup := range template.RepeatMatchups {
+		if !repeated[pairKey(matchup.TeamA, matchup.TeamB)] {
+			t.Errorf("teams %d and %d should meet again in round 6", matchup.TeamA, matchup.TeamB)
+		}
+	}
+}
+
+func TestFixtureTemplate_Validate(t *testing.T) {
+	teams := createTestTeams(17)
+
+	tests := []struct {
+		name     string
+		template FixtureTemplate
+		wantErr  bool
+	}{
+		{"plain template", FixtureTemplate{RepeatOpponents: 8}, false},
+		{"no repeats", FixtureTemplate{}, false},
+		{"odd teams and odd repeats", FixtureTemplate{RepeatOpponents: 7}, true},
+		{"too many repeats", FixtureTemplate{RepeatOpponents: 17}, true},
+		{"negative repeats", FixtureTemplate{RepeatOpponents: -2}, true},
+		{"unknown pool team", FixtureTemplate{Pools: [][]int{{1, 99}}, RepeatOpponents: 8}, true},
+		{"team in two pools", FixtureTemplate{Pools: [][]int{{1, 2}, {2, 3}}, RepeatOpponents: 8}, true},
+		{"repeat matchups short of repeats", FixtureTemplate{RepeatMatchups: []Matchup{{1, 2}}, RepeatOpponents: 2}, true},
+		{"repeat matchup against itself", FixtureTemplate{RepeatMatchups: []Matchup{{1, 1}}, RepeatOpponents: 2}, true},
+		{"repeat matchup listed twice", FixtureTemplate{RepeatMatchups: []Matchup{{1, 2}, {2, 1}}, RepeatOpponents: 2}, true},
+		{"unknown repeat matchup team", FixtureTemplate{RepeatMatchups: []Matchup{{1, 99}}, RepeatOpponents: 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.Validate(teams)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	gen, _ := NewGenerator(teams, 20)
+	if _, err := gen.GenerateFromTemplate(FixtureTemplate{RepeatOpponents: 8}); err == nil {
+		t.Error("expected an error when the season is too short for the repeats")
+	}
+}
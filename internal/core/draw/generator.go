@@ -3,6 +3,7 @@ package draw
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 )
@@ -11,6 +12,13 @@ import (
 type Generator struct {
 	teams  []*models.Team
 	rounds int
+	seed   *int64 // shuffles the team order when set
+	// template replaces the plain round-robin in Generate when set
+	template *FixtureTemplate
+	// magicRounds maps rounds played entirely at one venue to that venue
+	magicRounds map[int]int
+	// pins are matchups Generate must play in given rounds
+	pins []PinnedFixture
 }
 
 // NewGenerator creates a new draw generator
@@ -27,6 +35,13 @@ func NewGenerator(teams []*models.Team, rounds int) (*Generator, error) {
 	}, nil
 }
 
+// SetSeed seeds the generator. A seeded generator shuffles the teams before
+// scheduling, so different seeds give different fixtures and the same seed
+// always gives the same one. Unseeded generators keep the teams in order.
+func (g *Generator) SetSeed(seed int64) {
+	g.seed = &seed
+}
+
 // GenerateRoundRobin creates a round-robin draw where each team plays each other team
 func (g *Generator) GenerateRoundRobin() (*models.Draw, error) {
 	numTeams := len(g.teams)
@@ -35,6 +50,11 @@ func (g *Generator) GenerateRoundRobin() (*models.Draw, error) {
 	// For odd number of teams, add a virtual "bye" team
 	workingTeams := make([]*models.Team, len(g.teams))
 	copy(workingTeams, g.teams)
+	if g.seed != nil {
+		rand.New(rand.NewSource(*g.seed)).Shuffle(len(workingTeams), func(i, j int) {
+			workingTeams[i], workingTeams[j] = workingTeams[j], workingTeams[i]
+		})
+	}
 	
 	if isOdd {
 		workingTeams = append(workingTeams, nil) // nil represents bye
@@ -68,8 +88,13 @@ func (g *Generator) GenerateRoundRobin() (*models.Draw, error) {
 			homeTeam := workingTeams[homeIdx]
 			awayTeam := workingTeams[awayIdx]
 
-			// Skip if either team is bye (nil)
+			// The team paired with the virtual bye team sits out the round
 			if homeTeam == nil || awayTeam == nil {
+				byeTeam := homeTeam
+				if byeTeam == nil {
+					byeTeam = awayTeam
+				}
+				draw.Byes = append(draw.Byes, models.NewBye(0, round, byeTeam.ID))
 				continue
 			}
 
@@ -142,6 +167,7 @@ func (g *Generator) GenerateDoubleRoundRobin() (*models.Draw, error) {
 	if err != nil {
 		return nil, err
 	}
+	singleGen.seed = g.seed
 
 	// Generate first half
 	draw, err := singleGen.GenerateRoundRobin()
@@ -178,5 +204,6 @@ func (g *Generator) GenerateDoubleRoundRobin() (*models.Draw, error) {
 
 	draw.Name = fmt.Sprintf("Double Round Robin Draw - %d teams", len(g.teams))
 	draw.Rounds = singleRounds * 2
+	draw.Byes = draw.ByesFromMatches()
 	return draw, nil
 }
\ No newline at end of file
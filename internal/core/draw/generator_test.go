@@ -300,6 +300,21 @@ func TestGenerateRoundRobin_OddTeams(t *testing.T) {
 				if byeTeam == -1 {
 					t.Errorf("no team has bye in round %d", round)
 				}
+
+				// The bye is recorded against the team sitting out
+				recorded := 0
+				for _, bye := range draw.Byes {
+					if bye.Round != round {
+						continue
+					}
+					recorded++
+					if !bye.IsByeFor(byeTeam) {
+						t.Errorf("round %d bye recorded for team %d, want %d", round, *bye.HomeTeamID, byeTeam)
+					}
+				}
+				if recorded != 1 {
+					t.Errorf("round %d has %d recorded byes, want 1", round, recorded)
+				}
 			}
 
 			// Verify bye distribution is fair
@@ -415,6 +430,48 @@ func TestGenerateDoubleRoundRobin(t *testing.T) {
 	}
 }
 
+func TestGenerateRoundRobin_Seeded(t *testing.T) {
+	teams := createTestTeams(16)
+	
+	generate := func(seed *int64) []string {
+		gen, err := NewGenerator(teams, 15)
+		if err != nil {
+			t.Fatalf("NewGenerator() error = %v", err)
+		}
+		if seed != nil {
+			gen.SetSeed(*seed)
+		}
+		draw, err := gen.GenerateRoundRobin()
+		if err != nil {
+			t.Fatalf("GenerateRoundRobin() error = %v", err)
+		}
+		fixture := make([]string, len(draw.Matches))
+		for i, match := range draw.Matches {
+			fixture[i] = matchKey(*match.HomeTeamID, *match.AwayTeamID)
+		}
+		return fixture
+	}
+	equal := func(a, b []string) bool {
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return len(a) == len(b)
+	}
+	
+	seed, otherSeed := int64(42), int64(7)
+	if !equal(generate(&seed), generate(&seed)) {
+		t.Error("same seed should generate the same fixture")
+	}
+	if equal(generate(&seed), generate(&otherSeed)) {
+		t.Error("different seeds should generate different fixtures")
+	}
+	if equal(generate(&seed), generate(nil)) {
+		t.Error("a seeded fixture should differ from the unseeded team order")
+	}
+}
+
 // Helper functions
 
 type drawStats struct {
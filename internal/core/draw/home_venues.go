@@ -0,0 +1,54 @@
+package draw
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// AssignHomeVenues spreads the home games of teams with several home venues
+// across them in proportion to their shares. Each home game, in round order,
+// goes to the venue furthest behind its target so far, so a 70/30 split
+// alternates through the season rather than clustering. Locked matches keep
+// their venue but count towards the split.
+func AssignHomeVenues(draw *models.Draw, teams []*models.Team) {
+	for _, team := range teams {
+		if !team.HasVenueRotation() {
+			continue
+		}
+
+		var homeMatches []*models.Match
+		for _, match := range draw.Matches {
+			if !match.IsBye() && *match.HomeTeamID == team.ID {
+				homeMatches = append(homeMatches, match)
+			}
+		}
+		// Round order
+		for i := 0; i < len(homeMatches)-1; i++ {
+			for j := i + 1; j < len(homeMatches); j++ {
+				if homeMatches[j].Round < homeMatches[i].Round {
+					homeMatches[i], homeMatches[j] = homeMatches[j], homeMatches[i]
+				}
+			}
+		}
+
+		hosted := make(map[int]int)
+		for played, match := range homeMatches {
+			if match.Locked && match.VenueID != nil {
+				hosted[*match.VenueID]++
+				continue
+			}
+
+			best := 0
+			bestDeficit := 0.0
+			for i, venue := range team.HomeVenues {
+				deficit := venue.Share*float64(played+1) - float64(hosted[venue.VenueID])
+				if i == 0 || deficit > bestDeficit {
+					best, bestDeficit = i, deficit
+				}
+			}
+
+			venueID := team.HomeVenues[best].VenueID
+			match.VenueID = &venueID
+			hosted[venueID]++
+		}
+	}
+}
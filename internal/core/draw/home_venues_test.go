@@ -0,0 +1,56 @@
+package draw
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestAssignHomeVenues(t *testing.T) {
+	teams := createTestTeams(10)
+	// Team 1 plays 70% of home games at its own ground and 30% at venue 99
+	teams[0].HomeVenues = []models.HomeVenue{{VenueID: 1, Share: 0.7}, {VenueID: 99, Share: 0.3}}
+
+	gen, err := NewGenerator(teams, 18)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	draw, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	hosted := make(map[int]int)
+	var lastSecondary int
+	homeGames := 0
+	for round := 1; round <= draw.Rounds; round++ {
+		for _, match := range draw.Matches {
+			if match.Round != round || *match.HomeTeamID != 1 {
+				continue
+			}
+			homeGames++
+			hosted[*match.VenueID]++
+			if *match.VenueID == 99 {
+				if lastSecondary != 0 && round-lastSecondary < 2 {
+					t.Errorf("second venue used in rounds %d and %d, expected the split to be spread out", lastSecondary, round)
+				}
+				lastSecondary = round
+			}
+		}
+	}
+
+	if homeGames != 9 {
+		t.Fatalf("team 1 has %d home games, want 9", homeGames)
+	}
+	// 70% of 9 home games rounds to 6 at the main ground
+	if hosted[1] != 6 || hosted[99] != 3 {
+		t.Errorf("home games split %d/%d, want 6/3", hosted[1], hosted[99])
+	}
+
+	// Other teams keep their single home venue
+	for _, match := range draw.Matches {
+		if *match.HomeTeamID != 1 && *match.VenueID != *match.HomeTeamID {
+			t.Errorf("team %d home game at venue %d", *match.HomeTeamID, *match.VenueID)
+		}
+	}
+}
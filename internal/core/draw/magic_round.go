@@ -0,0 +1,36 @@
+package draw
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// SetMagicRound makes Generate play every match in a round at one venue, as
+// in the NRL's Magic Round. Setting a round again replaces its venue.
+func (g *Generator) SetMagicRound(round, venueID int) error {
+	if round < 1 || round > g.rounds {
+		return fmt.Errorf("magic round %d is outside the %d round season", round, g.rounds)
+	}
+	if venueID < 1 {
+		return fmt.Errorf("magic round %d needs a venue", round)
+	}
+
+	if g.magicRounds == nil {
+		g.magicRounds = make(map[int]int)
+	}
+	g.magicRounds[round] = venueID
+	return nil
+}
+
+// AssignMagicRounds moves every match in each magic round to the round's
+// venue, keyed by round. Locked matches keep their venue.
+func AssignMagicRounds(draw *models.Draw, magicRounds map[int]int) {
+	for _, match := range draw.Matches {
+		venueID, magic := magicRounds[match.Round]
+		if !magic || match.IsBye() || match.Locked {
+			continue
+		}
+		match.VenueID = &venueID
+	}
+}
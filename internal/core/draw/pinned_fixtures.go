@@ -0,0 +1,484 @@
+package draw
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Pinned fixture search limits. A search that runs out of placements starts
+// again with another round order and twice the placements, so only a search
+// that finishes can prove the pins impossible.
+const (
+	pinSearchPlacements = 10000
+	pinSearchRestarts   = 5
+	// maxPinnedRounds is the longest season the search's round sets can hold
+	maxPinnedRounds = 64
+)
+
+// ErrPinnedFixturesUnsatisfiable is returned when no draw can be built
+// around the pinned fixtures
+var ErrPinnedFixturesUnsatisfiable = errors.New("pinned fixtures can't all be satisfied")
+
+// ErrPinSearchLimit is returned when the search for a draw around the pinned
+// fixtures gives up before finding one or proving there is none
+var ErrPinSearchLimit = errors.New("no draw around the pinned fixtures found within the search limit")
+
+// PinnedFixture fixes a matchup to a round, such as the Anzac Day match or a
+// grand final rematch in round 1. The home team is kept as given.
+type PinnedFixture struct {
+	Round      int `json:"round"`
+	HomeTeamID int `json:"home_team_id"`
+	AwayTeamID int `json:"away_team_id"`
+}
+
+// SetPinnedFixtures makes Generate build the rest of the draw around the
+// pinned fixtures. Pins are checked against each other here; whether the
+// round-robin can be fitted around them is only known when generating.
+func (g *Generator) SetPinnedFixtures(pins []PinnedFixture) error {
+	known := make(map[int]bool)
+	for _, team := range g.teams {
+		known[team.ID] = true
+	}
+
+	if len(pins) > 0 && g.rounds > maxPinnedRounds {
+		return fmt.Errorf("fixtures can only be pinned in seasons of up to %d rounds", maxPinnedRounds)
+	}
+
+	playing := make(map[int]map[int]bool)
+	for _, pin := range pins {
+		if pin.Round < 1 || pin.Round > g.rounds {
+			return fmt.Errorf("pinned round %d is outside the %d round season", pin.Round, g.rounds)
+		}
+		if !known[pin.HomeTeamID] || !known[pin.AwayTeamID] {
+			return fmt.Errorf("pinned fixture %d v %d has a team not in the draw", pin.HomeTeamID, pin.AwayTeamID)
+		}
+		if pin.HomeTeamID == pin.AwayTeamID {
+			return fmt.Errorf("team %d can't be pinned against itself", pin.HomeTeamID)
+		}
+		if playing[pin.Round] == nil {
+			playing[pin.Round] = make(map[int]bool)
+		}
+		for _, teamID := range []int{pin.HomeTeamID, pin.AwayTeamID} {
+			if playing[pin.Round][teamID] {
+				return fmt.Errorf("team %d is pinned twice in round %d", teamID, pin.Round)
+			}
+			playing[pin.Round][teamID] = true
+		}
+	}
+
+	g.pins = pins
+	return nil
+}
+
+// generatePinned builds the draw from the fixture template or round-robin and
+// fits the pinned fixtures into it. Renumbering whole rounds keeps the
+// fixture as generated; when no renumbering fits, the meetings are scheduled
+// around the pins instead.
+func (g *Generator) generatePinned() (*models.Draw, error) {
+	draw, err := g.generateFixture()
+	if err != nil {
+		return nil, err
+	}
+	if g.fitPins(draw) {
+		return draw, nil
+	}
+	if err := g.schedulePins(draw); err != nil {
+		return nil, err
+	}
+	g.orientPins(draw)
+	return draw, nil
+}
+
+// fitPins renumbers the draw's rounds so every pinned matchup is played in its
+// round, then gives the pinned home teams their home games. Moving whole
+// rounds keeps each team playing once a round. It reports false when no
+// renumbering puts every pin in place.
+func (g *Generator) fitPins(draw *models.Draw) bool {
+	// The rounds each matchup is played in
+	meetings := make(map[string][]int)
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		key := pairKey(*match.HomeTeamID, *match.AwayTeamID)
+		meetings[key] = append(meetings[key], match.Round)
+	}
+
+	// renumber maps generated rounds to pinned rounds, and pinned the reverse
+	renumber := make(map[int]int)
+	pinned := make(map[int]int)
+	if !g.placePins(0, meetings, renumber, pinned) {
+		return false
+	}
+
+	// The remaining rounds keep their order in the rounds left over
+	next := 1
+	for round := 1; round <= draw.Rounds; round++ {
+		if _, placed := renumber[round]; placed {
+			continue
+		}
+		for {
+			if _, taken := pinned[next]; !taken {
+				break
+			}
+			next++
+		}
+		renumber[round] = next
+		next++
+	}
+
+	for _, match := range append(draw.Matches, draw.Byes...) {
+		match.Round = renumber[match.Round]
+	}
+	sort.SliceStable(draw.Matches, func(i, j int) bool { return draw.Matches[i].Round < draw.Matches[j].Round })
+	sort.SliceStable(draw.Byes, func(i, j int) bool { return draw.Byes[i].Round < draw.Byes[j].Round })
+
+	g.orientPins(draw)
+	return true
+}
+
+// placePins chooses, pin by pin, which generated meeting of the matchup is
+// moved to the pinned round, backtracking when two pins need the same round
+// moved to different places
+func (g *Generator) placePins(next int, meetings map[string][]int, renumber, pinned map[int]int) bool {
+	if next == len(g.pins) {
+		return true
+	}
+
+	pin := g.pins[next]
+	for _, round := range meetings[pairKey(pin.HomeTeamID, pin.AwayTeamID)] {
+		target, moved := renumber[round]
+		if moved && target != pin.Round {
+			continue
+		}
+		if source, taken := pinned[pin.Round]; taken && source != round {
+			continue
+		}
+
+		if !moved {
+			renumber[round] = pin.Round
+			pinned[pin.Round] = round
+		}
+		if g.placePins(next+1, meetings, renumber, pinned) {
+			return true
+		}
+		if !moved {
+			delete(renumber, round)
+			delete(pinned, pin.Round)
+		}
+	}
+
+	return false
+}
+
+// orientPins gives each pinned home team its home game. To keep the matchup's
+// home games shared, an unpinned meeting the other way round is flipped too.
+func (g *Generator) orientPins(draw *models.Draw) {
+	venues := make(map[int]*int)
+	for _, team := range g.teams {
+		venues[team.ID] = team.VenueID
+	}
+	flip := func(match *models.Match) {
+		match.HomeTeamID, match.AwayTeamID = match.AwayTeamID, match.HomeTeamID
+		match.VenueID = venues[*match.HomeTeamID]
+	}
+
+	isPinned := make(map[*models.Match]bool)
+	var wrongWay []*models.Match
+	for _, pin := range g.pins {
+		for _, match := range draw.Matches {
+			if match.Round != pin.Round || match.IsBye() || !match.HasTeam(pin.HomeTeamID) || !match.HasTeam(pin.AwayTeamID) {
+				continue
+			}
+			isPinned[match] = true
+			if *match.HomeTeamID != pin.HomeTeamID {
+				wrongWay = append(wrongWay, match)
+			}
+		}
+	}
+
+	for _, match := range wrongWay {
+		flip(match)
+		for _, other := range draw.Matches {
+			if isPinned[other] || other.IsBye() || *other.HomeTeamID != *match.HomeTeamID || *other.AwayTeamID != *match.AwayTeamID {
+				continue
+			}
+			flip(other)
+			break
+		}
+	}
+}
+
+// schedulePins moves the draw's meetings between rounds so every pinned
+// matchup is played in its round. Each pin takes one meeting of its matchup
+// and the rest are fitted around them by pinSearch, keeping the draw's
+// meetings, each team to one game a round and each round within the
+// generated fixture's range of games.
+func (g *Generator) schedulePins(draw *models.Draw) error {
+	index := make(map[int]int, len(g.teams))
+	for i, team := range g.teams {
+		index[team.ID] = i
+	}
+
+	// With one bye a round, each bye is a meeting with a stand-in team, so
+	// every round is a full one and a team's bye is placed like a game
+	byes := make(map[int]int)
+	for _, bye := range draw.Byes {
+		if bye.HomeTeamID != nil {
+			byes[bye.Round]++
+		}
+	}
+	standIn := len(byes) == draw.Rounds && len(draw.Byes) == draw.Rounds
+
+	numTeams := len(g.teams)
+	if standIn {
+		numTeams++
+	}
+	search := newPinSearch(numTeams, draw.Rounds)
+	var matches []*models.Match
+	unpinned := make(map[string][]int)
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		meeting := search.addMeeting(index[*match.HomeTeamID], index[*match.AwayTeamID], match.Round)
+		matches = append(matches, match)
+		key := pairKey(*match.HomeTeamID, *match.AwayTeamID)
+		unpinned[key] = append(unpinned[key], meeting)
+	}
+	if standIn {
+		for _, bye := range draw.Byes {
+			search.addMeeting(index[*bye.HomeTeamID], len(g.teams), bye.Round)
+		}
+	}
+	search.limitRounds()
+
+	// Which meeting a pin takes doesn't matter, orientPins sets its home team
+	for _, pin := range g.pins {
+		key := pairKey(pin.HomeTeamID, pin.AwayTeamID)
+		if len(unpinned[key]) == 0 {
+			return fmt.Errorf("%w: %d v %d is pinned more often than they meet",
+				ErrPinnedFixturesUnsatisfiable, pin.HomeTeamID, pin.AwayTeamID)
+		}
+		meeting := unpinned[key][0]
+		unpinned[key] = unpinned[key][1:]
+		if !search.fits(meeting, pin.Round) {
+			return fmt.Errorf("%w: round %d can't hold all of its pinned fixtures",
+				ErrPinnedFixturesUnsatisfiable, pin.Round)
+		}
+		search.place(meeting, pin.Round)
+	}
+
+	var seed int64
+	if g.seed != nil {
+		seed = *g.seed
+	}
+	result := searchAborted
+	for restart := 0; restart < pinSearchRestarts && result == searchAborted; restart++ {
+		search.rng = rand.New(rand.NewSource(seed + int64(restart)))
+		search.placements = pinSearchPlacements << restart
+		result = search.solve()
+	}
+	switch result {
+	case searchFailed:
+		return fmt.Errorf("%w: no %d round draw plays all %d of them in their rounds",
+			ErrPinnedFixturesUnsatisfiable, draw.Rounds, len(g.pins))
+	case searchAborted:
+		return fmt.Errorf("%w of %d restarts", ErrPinSearchLimit, pinSearchRestarts)
+	}
+
+	for i, match := range matches {
+		match.Round = search.rounds[i]
+	}
+	sort.SliceStable(draw.Matches, func(i, j int) bool { return draw.Matches[i].Round < draw.Matches[j].Round })
+	draw.Byes = draw.ByesFromMatches()
+	return nil
+}
+
+// searchResult is how a pinSearch ended
+type searchResult int
+
+const (
+	searchFound   searchResult = iota // every meeting has a round
+	searchFailed                      // no placement of the meetings exists
+	searchAborted                     // the placements ran out first
+)
+
+// roundSet is a set of rounds, round r being bit r-1
+type roundSet uint64
+
+// pinSearch places meetings in rounds by backtracking, most constrained
+// meeting first, so each team plays at most once a round and each round holds
+// between minGames and maxGames meetings. A meeting tries its generated round
+// before the others, keeping the draw close to the generated fixture.
+type pinSearch struct {
+	numRounds          int
+	teams              [][2]int // the two teams of each meeting
+	preferred          []int    // each meeting's generated round
+	rounds             []int    // each meeting's round, 0 until placed
+	playing            []roundSet
+	games              []int // meetings placed in each round, by round
+	minGames, maxGames int
+	full               roundSet // rounds holding maxGames meetings
+	rng                *rand.Rand
+	placements         int // placements left before the search is aborted
+}
+
+func newPinSearch(numTeams, numRounds int) *pinSearch {
+	return &pinSearch{
+		numRounds: numRounds,
+		playing:   make([]roundSet, numTeams),
+		games:     make([]int, numRounds+1),
+	}
+}
+
+// addMeeting adds a meeting between two teams, by index, generated in round
+func (s *pinSearch) addMeeting(a, b, round int) int {
+	s.teams = append(s.teams, [2]int{a, b})
+	s.preferred = append(s.preferred, round)
+	s.rounds = append(s.rounds, 0)
+	return len(s.teams) - 1
+}
+
+// limitRounds keeps each round within the busiest and quietest rounds of the
+// generated fixture
+func (s *pinSearch) limitRounds() {
+	games := make([]int, s.numRounds+1)
+	for _, round := range s.preferred {
+		games[round]++
+	}
+	s.minGames, s.maxGames = games[1], games[1]
+	for _, count := range games[1:] {
+		s.minGames = min(s.minGames, count)
+		s.maxGames = max(s.maxGames, count)
+	}
+}
+
+// open returns the rounds a meeting can still be placed in
+func (s *pinSearch) open(meeting int) roundSet {
+	all := roundSet(1)<<s.numRounds - 1
+	return all &^ s.full &^ s.playing[s.teams[meeting][0]] &^ s.playing[s.teams[meeting][1]]
+}
+
+func (s *pinSearch) fits(meeting, round int) bool {
+	return s.open(meeting)&(1<<(round-1)) != 0
+}
+
+func (s *pinSearch) place(meeting, round int) {
+	bit := roundSet(1) << (round - 1)
+	s.rounds[meeting] = round
+	s.playing[s.teams[meeting][0]] |= bit
+	s.playing[s.teams[meeting][1]] |= bit
+	s.games[round]++
+	if s.games[round] == s.maxGames {
+		s.full |= bit
+	}
+}
+
+func (s *pinSearch) unplace(meeting int) {
+	round := s.rounds[meeting]
+	bit := roundSet(1) << (round - 1)
+	s.rounds[meeting] = 0
+	s.playing[s.teams[meeting][0]] &^= bit
+	s.playing[s.teams[meeting][1]] &^= bit
+	s.games[round]--
+	s.full &^= bit
+}
+
+// solve places the remaining meetings, undoing its placements unless every
+// meeting is placed
+func (s *pinSearch) solve() searchResult {
+	meeting, options, ok := s.choose()
+	if !ok {
+		return searchFailed
+	}
+	if meeting < 0 {
+		return searchFound
+	}
+
+	for _, round := range s.order(meeting, options) {
+		if s.placements == 0 {
+			return searchAborted
+		}
+		s.placements--
+
+		s.place(meeting, round)
+		result := s.solve()
+		if result == searchFound {
+			return result
+		}
+		s.unplace(meeting)
+		if result == searchAborted {
+			return result
+		}
+	}
+	return searchFailed
+}
+
+// choose returns the unplaced meeting with the fewest open rounds, or -1 when
+// all are placed. It reports false when the placements so far can't be
+// completed: a meeting has no open round, a team has fewer rounds open to its
+// meetings than meetings left, or a round can't reach minGames.
+func (s *pinSearch) choose() (int, roundSet, bool) {
+	best, bestOptions, bestCount := -1, roundSet(0), s.numRounds+1
+	reachable := make([]roundSet, len(s.playing))
+	remaining := make([]int, len(s.playing))
+	candidates := make([]int, s.numRounds+1)
+
+	for meeting, round := range s.rounds {
+		if round != 0 {
+			continue
+		}
+		options := s.open(meeting)
+		count := bits.OnesCount64(uint64(options))
+		if count == 0 {
+			return -1, 0, false
+		}
+		if count < bestCount {
+			best, bestOptions, bestCount = meeting, options, count
+		}
+		for _, team := range s.teams[meeting] {
+			reachable[team] |= options
+			remaining[team]++
+		}
+		if s.minGames > 0 {
+			for rest := uint64(options); rest != 0; rest &= rest - 1 {
+				candidates[bits.TrailingZeros64(rest)+1]++
+			}
+		}
+	}
+
+	for team := range remaining {
+		if bits.OnesCount64(uint64(reachable[team])) < remaining[team] {
+			return -1, 0, false
+		}
+	}
+	for round := 1; round <= s.numRounds; round++ {
+		if s.games[round]+candidates[round] < s.minGames {
+			return -1, 0, false
+		}
+	}
+	return best, bestOptions, true
+}
+
+// order lists a meeting's open rounds to try, its generated round first and
+// the rest shuffled
+func (s *pinSearch) order(meeting int, options roundSet) []int {
+	var rounds []int
+	preferred := s.preferred[meeting]
+	for rest := uint64(options); rest != 0; rest &= rest - 1 {
+		if round := bits.TrailingZeros64(rest) + 1; round != preferred {
+			rounds = append(rounds, round)
+		}
+	}
+	s.rng.Shuffle(len(rounds), func(i, j int) { rounds[i], rounds[j] = rounds[j], rounds[i] })
+	if options&(1<<(preferred-1)) != 0 {
+		rounds = append([]int{preferred}, rounds...)
+	}
+	return rounds
+}
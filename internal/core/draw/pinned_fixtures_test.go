@@ -0,0 +1,206 @@
+package draw
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// checkPins fails the test for each pin not played in its round the right way round
+func checkPins(t *testing.T, draw *models.Draw, pins []PinnedFixture) {
+	t.Helper()
+	for _, pin := range pins {
+		found := false
+		for _, match := range draw.Matches {
+			if match.Round == pin.Round && *match.HomeTeamID == pin.HomeTeamID && *match.AwayTeamID == pin.AwayTeamID {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %d v %d in round %d", pin.HomeTeamID, pin.AwayTeamID, pin.Round)
+		}
+	}
+}
+
+func TestGenerate_PinnedFixtures(t *testing.T) {
+	teams := createTestTeams(17)
+	pins := []PinnedFixture{
+		{Round: 1, HomeTeamID: 3, AwayTeamID: 4},
+		{Round: 1, HomeTeamID: 5, AwayTeamID: 6},
+		{Round: 8, HomeTeamID: 2, AwayTeamID: 1},
+		{Round: 25, HomeTeamID: 7, AwayTeamID: 3},
+	}
+
+	gen, err := NewGenerator(teams, 27)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	gen.SetSeed(7)
+	if err := gen.SetTemplate(FixtureTemplate{RepeatOpponents: 8}); err != nil {
+		t.Fatalf("SetTemplate() error = %v", err)
+	}
+	if err := gen.SetPinnedFixtures(pins); err != nil {
+		t.Fatalf("SetPinnedFixtures() error = %v", err)
+	}
+
+	draw, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	checkRounds(t, draw)
+	checkPins(t, draw, pins)
+
+	// The rest of the template is intact: 24 games each, every opponent met
+	games := make(map[int]int)
+	opponents := make(map[int]map[int]bool)
+	for _, match := range draw.Matches {
+		home, away := *match.HomeTeamID, *match.AwayTeamID
+		games[home]++
+		games[away]++
+		for _, pair := range [][2]int{{home, away}, {away, home}} {
+			if opponents[pair[0]] == nil {
+				opponents[pair[0]] = make(map[int]bool)
+			}
+			opponents[pair[0]][pair[1]] = true
+		}
+	}
+	for _, team := range teams {
+		if games[team.ID] != 24 || len(opponents[team.ID]) != 16 {
+			t.Errorf("team %d plays %d games against %d opponents, want 24 against 16", team.ID, games[team.ID], len(opponents[team.ID]))
+		}
+	}
+	for _, bye := range draw.Byes {
+		if bye.Round < 1 || bye.Round > 27 {
+			t.Errorf("bye in round %d outside the season", bye.Round)
+		}
+	}
+}
+
+func TestGenerate_DenselyPinnedRounds(t *testing.T) {
+	tests := []struct {
+		name string
+		pins []PinnedFixture
+	}{
+		{
+			name: "five pins in round 1",
+			pins: []PinnedFixture{
+				{Round: 1, HomeTeamID: 1, AwayTeamID: 2},
+				{Round: 1, HomeTeamID: 3, AwayTeamID: 4},
+				{Round: 1, HomeTeamID: 5, AwayTeamID: 6},
+				{Round: 1, HomeTeamID: 7, AwayTeamID: 8},
+				{Round: 1, HomeTeamID: 9, AwayTeamID: 10},
+			},
+		},
+		{
+			name: "whole round pinned",
+			pins: []PinnedFixture{
+				{Round: 8, HomeTeamID: 1, AwayTeamID: 16},
+				{Round: 8, HomeTeamID: 2, AwayTeamID: 15},
+				{Round: 8, HomeTeamID: 3, AwayTeamID: 14},
+				{Round: 8, HomeTeamID: 4, AwayTeamID: 13},
+				{Round: 8, HomeTeamID: 5, AwayTeamID: 12},
+				{Round: 8, HomeTeamID: 6, AwayTeamID: 11},
+				{Round: 8, HomeTeamID: 7, AwayTeamID: 10},
+				{Round: 8, HomeTeamID: 8, AwayTeamID: 9},
+			},
+		},
+		{
+			name: "several dense rounds",
+			pins: []PinnedFixture{
+				{Round: 1, HomeTeamID: 1, AwayTeamID: 2},
+				{Round: 1, HomeTeamID: 3, AwayTeamID: 4},
+				{Round: 1, HomeTeamID: 5, AwayTeamID: 6},
+				{Round: 1, HomeTeamID: 7, AwayTeamID: 8},
+				{Round: 2, HomeTeamID: 1, AwayTeamID: 3},
+				{Round: 2, HomeTeamID: 2, AwayTeamID: 4},
+				{Round: 2, HomeTeamID: 5, AwayTeamID: 7},
+				{Round: 2, HomeTeamID: 6, AwayTeamID: 8},
+				{Round: 15, HomeTeamID: 9, AwayTeamID: 10},
+				{Round: 15, HomeTeamID: 11, AwayTeamID: 12},
+				{Round: 15, HomeTeamID: 13, AwayTeamID: 14},
+				{Round: 15, HomeTeamID: 15, AwayTeamID: 16},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, err := NewGenerator(createTestTeams(16), 15)
+			if err != nil {
+				t.Fatalf("NewGenerator() error = %v", err)
+			}
+			gen.SetSeed(3)
+			if err := gen.SetPinnedFixtures(tt.pins); err != nil {
+				t.Fatalf("SetPinnedFixtures() error = %v", err)
+			}
+
+			draw, err := gen.Generate()
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			checkPins(t, draw, tt.pins)
+
+			// Still a single round-robin: every team plays every round and
+			// meets every opponent once
+			for round, playing := range checkRounds(t, draw) {
+				if playing != 16 {
+					t.Errorf("round %d has %d teams playing, want 16", round, playing)
+				}
+			}
+			meetings := make(map[string]int)
+			for _, match := range draw.Matches {
+				meetings[pairKey(*match.HomeTeamID, *match.AwayTeamID)]++
+			}
+			if len(meetings) != 120 || len(draw.Matches) != 120 {
+				t.Errorf("got %d matches over %d matchups, want 120 over 120", len(draw.Matches), len(meetings))
+			}
+			if len(draw.Byes) != 0 {
+				t.Errorf("got %d byes, want none", len(draw.Byes))
+			}
+		})
+	}
+}
+
+func TestGenerate_PinnedFixturesUnsatisfiable(t *testing.T) {
+	// With four teams, 1 v 2 is always in the same round as 3 v 4
+	gen, err := NewGenerator(createTestTeams(4), 3)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	err = gen.SetPinnedFixtures([]PinnedFixture{
+		{Round: 1, HomeTeamID: 1, AwayTeamID: 2},
+		{Round: 2, HomeTeamID: 3, AwayTeamID: 4},
+	})
+	if err != nil {
+		t.Fatalf("SetPinnedFixtures() error = %v", err)
+	}
+
+	if _, err := gen.Generate(); !errors.Is(err, ErrPinnedFixturesUnsatisfiable) {
+		t.Errorf("Generate() error = %v, want ErrPinnedFixturesUnsatisfiable", err)
+	}
+}
+
+func TestSetPinnedFixtures_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pins    []PinnedFixture
+		wantErr bool
+	}{
+		{"valid pins", []PinnedFixture{{Round: 1, HomeTeamID: 1, AwayTeamID: 2}, {Round: 1, HomeTeamID: 3, AwayTeamID: 4}}, false},
+		{"round outside the season", []PinnedFixture{{Round: 10, HomeTeamID: 1, AwayTeamID: 2}}, true},
+		{"unknown team", []PinnedFixture{{Round: 1, HomeTeamID: 1, AwayTeamID: 99}}, true},
+		{"against itself", []PinnedFixture{{Round: 1, HomeTeamID: 1, AwayTeamID: 1}}, true},
+		{"team twice in a round", []PinnedFixture{{Round: 2, HomeTeamID: 1, AwayTeamID: 2}, {Round: 2, HomeTeamID: 3, AwayTeamID: 1}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, _ := NewGenerator(createTestTeams(6), 5)
+			err := gen.SetPinnedFixtures(tt.pins)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetPinnedFixtures() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
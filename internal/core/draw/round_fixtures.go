@@ -0,0 +1,81 @@
+package draw
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// ErrRoundNotInDraw is returned for rounds outside a draw's season
+var ErrRoundNotInDraw = errors.New("round is not in the draw")
+
+// RoundFixtures is one round of a draw: its matches and the teams and venues
+// involved
+type RoundFixtures struct {
+	Round int
+	// Matches are in kickoff order, undated matches last
+	Matches []*models.Match
+	// ByeTeams are the draw's teams without a match in the round, by ID
+	ByeTeams         []*models.Team
+	PrimeTimeMatches int
+	// Venues are the venues used in the round, by ID
+	Venues []*models.Venue
+}
+
+// Round returns one round of a draw. Matches need their teams and venues
+// loaded, as returned by ListByDrawWithRelations, for the bye teams and
+// venues to be filled in.
+func Round(draw *models.Draw, round int) (*RoundFixtures, error) {
+	if round < 1 || round > draw.Rounds {
+		return nil, ErrRoundNotInDraw
+	}
+
+	fixtures := &RoundFixtures{Round: round}
+	teams := make(map[int]*models.Team)
+	playing := make(map[int]bool)
+	venues := make(map[int]*models.Venue)
+	for _, match := range draw.Matches {
+		for _, team := range []*models.Team{match.HomeTeam, match.AwayTeam} {
+			if team != nil {
+				teams[team.ID] = team
+			}
+		}
+		if match.Round != round {
+			continue
+		}
+
+		fixtures.Matches = append(fixtures.Matches, match)
+		for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+			if teamID != nil {
+				playing[*teamID] = true
+			}
+		}
+		if match.IsPrimeTime {
+			fixtures.PrimeTimeMatches++
+		}
+		if match.Venue != nil {
+			venues[match.Venue.ID] = match.Venue
+		}
+	}
+
+	sort.SliceStable(fixtures.Matches, func(i, j int) bool {
+		return matchBefore(fixtures.Matches[i], fixtures.Matches[j])
+	})
+	for teamID, team := range teams {
+		if !playing[teamID] {
+			fixtures.ByeTeams = append(fixtures.ByeTeams, team)
+		}
+	}
+	sort.Slice(fixtures.ByeTeams, func(i, j int) bool {
+		return fixtures.ByeTeams[i].ID < fixtures.ByeTeams[j].ID
+	})
+	for _, venue := range venues {
+		fixtures.Venues = append(fixtures.Venues, venue)
+	}
+	sort.Slice(fixtures.Venues, func(i, j int) bool {
+		return fixtures.Venues[i].ID < fixtures.Venues[j].ID
+	})
+
+	return fixtures, nil
+}
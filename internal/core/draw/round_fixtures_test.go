@@ -0,0 +1,73 @@
+package draw
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestRound(t *testing.T) {
+	teams := make([]*models.Team, 5)
+	for i := range teams {
+		teams[i] = &models.Team{ID: i + 1}
+	}
+	suncorp := &models.Venue{ID: 2, Name: "Suncorp Stadium"}
+	aami := &models.Venue{ID: 1, Name: "AAMI Park"}
+	match := func(id, round int, home, away *models.Team, venue *models.Venue, day int, primeTime bool) *models.Match {
+		m := &models.Match{ID: id, Round: round, HomeTeamID: &home.ID, AwayTeamID: &away.ID, HomeTeam: home, AwayTeam: away, Venue: venue, IsPrimeTime: primeTime}
+		if venue != nil {
+			m.VenueID = &venue.ID
+		}
+		if day > 0 {
+			date := time.Date(2025, time.March, day, 19, 0, 0, 0, time.UTC)
+			m.MatchDate = &date
+		}
+		return m
+	}
+
+	draw := &models.Draw{
+		Rounds: 2,
+		Matches: []*models.Match{
+			match(1, 1, teams[0], teams[1], suncorp, 0, false),
+			match(2, 1, teams[2], teams[3], aami, 8, true),
+			match(3, 1, teams[4], teams[0], suncorp, 7, true),
+			match(4, 2, teams[1], teams[4], nil, 0, false),
+		},
+	}
+
+	fixtures, err := Round(draw, 1)
+	if err != nil {
+		t.Fatalf("Round() error = %v", err)
+	}
+	if len(fixtures.Matches) != 3 || fixtures.Matches[0].ID != 3 || fixtures.Matches[2].ID != 1 {
+		t.Errorf("expected matches in kickoff order with the undated match last, got %v", fixtures.Matches)
+	}
+	if fixtures.PrimeTimeMatches != 2 {
+		t.Errorf("expected 2 prime-time matches, got %d", fixtures.PrimeTimeMatches)
+	}
+	if len(fixtures.Venues) != 2 || fixtures.Venues[0] != aami || fixtures.Venues[1] != suncorp {
+		t.Errorf("expected both venues by ID, got %v", fixtures.Venues)
+	}
+	if len(fixtures.ByeTeams) != 0 {
+		t.Errorf("expected no byes in round 1, got %v", fixtures.ByeTeams)
+	}
+
+	fixtures, err = Round(draw, 2)
+	if err != nil {
+		t.Fatalf("Round() error = %v", err)
+	}
+	if len(fixtures.ByeTeams) != 3 || fixtures.ByeTeams[0].ID != 1 || fixtures.ByeTeams[2].ID != 4 {
+		t.Errorf("expected teams 1, 3 and 4 on byes in round 2, got %v", fixtures.ByeTeams)
+	}
+	if len(fixtures.Venues) != 0 {
+		t.Errorf("expected no venues in round 2, got %v", fixtures.Venues)
+	}
+
+	for _, round := range []int{0, 3} {
+		if _, err := Round(draw, round); !errors.Is(err, ErrRoundNotInDraw) {
+			t.Errorf("expected ErrRoundNotInDraw for round %d, got %v", round, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package draw
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Errors returned when a team can't be added to or removed from a draw
+var (
+	ErrTeamInDraw           = errors.New("team is already in the draw")
+	ErrTeamNotInDraw        = errors.New("team is not in the draw")
+	ErrTeamHasLockedMatches = errors.New("team has locked matches; unlock them before removing the team")
+)
+
+// TeamChange lists the matches changed by adding or removing a team, so only
+// those need saving
+type TeamChange struct {
+	Created []*models.Match
+	Updated []*models.Match
+	Deleted []*models.Match
+}
+
+// AddTeam adds a team to an existing draw, changing only the matches it has
+// to. In each round the team plays a team that would otherwise have a bye.
+// When every team is playing it takes a place in an unlocked match and the
+// team it displaces gets the bye, unless the new team has had fewer byes, in
+// which case it sits the round out itself. Opponents it has met least are
+// preferred, so it meets everyone once before meeting anyone twice. Teams are
+// the league's teams, for their home venues.
+func AddTeam(draw *models.Draw, teamID int, teams []*models.Team) (*TeamChange, error) {
+	tally := newDrawTally(draw)
+	if tally.teams[teamID] {
+		return nil, ErrTeamInDraw
+	}
+	tally.teams[teamID] = true
+	venues := homeVenues(teams)
+
+	change := &TeamChange{}
+	for round := 1; round <= draw.Rounds; round++ {
+		if idle := tally.idle(round, teamID); len(idle) > 0 {
+			opponent := tally.leastMet(teamID, idle)
+			home, away := tally.homeSide(teamID, opponent)
+			homeID, awayID := home, away
+			match := &models.Match{
+				DrawID:     draw.ID,
+				Round:      round,
+				HomeTeamID: &homeID,
+				AwayTeamID: &awayID,
+				VenueID:    venues[home],
+			}
+			draw.Matches = append(draw.Matches, match)
+			tally.add(match)
+			tally.byes[opponent]--
+			change.Created = append(change.Created, match)
+			continue
+		}
+
+		match, displaced := tally.displaceable(round, teamID)
+		if match == nil || tally.byes[teamID] < tally.byes[displaced] {
+			tally.byes[teamID]++
+			continue
+		}
+
+		tally.remove(match)
+		if *match.HomeTeamID == displaced {
+			match.HomeTeamID = intPtr(teamID)
+			match.VenueID = venues[teamID]
+		} else {
+			match.AwayTeamID = intPtr(teamID)
+		}
+		tally.add(match)
+		tally.byes[displaced]++
+		change.Updated = append(change.Updated, match)
+	}
+
+	return change, nil
+}
+
+// RemoveTeam removes a team from an existing draw. Each of its matches is
+// handed to a team with a bye that round, which plays the removed team's
+// opponent instead; when no team has a bye the match is deleted and the
+// opponent gets one. The team's locked matches must be unlocked first.
+func RemoveTeam(draw *models.Draw, teamID int, teams []*models.Team) (*TeamChange, error) {
+	tally := newDrawTally(draw)
+	if !tally.teams[teamID] {
+		return nil, ErrTeamNotInDraw
+	}
+
+	var matches []*models.Match
+	for _, match := range draw.Matches {
+		if match.IsBye() || !match.HasTeam(teamID) {
+			continue
+		}
+		if match.Locked {
+			return nil, ErrTeamHasLockedMatches
+		}
+		matches = append(matches, match)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Round < matches[j].Round
+	})
+
+	delete(tally.teams, teamID)
+	venues := homeVenues(teams)
+
+	change := &TeamChange{}
+	deleted := make(map[*models.Match]bool)
+	for _, match := range matches {
+		opponent, _ := match.GetOpponent(teamID)
+		tally.remove(match)
+
+		idle := tally.idle(match.Round, *opponent)
+		if len(idle) == 0 {
+			deleted[match] = true
+			change.Deleted = append(change.Deleted, match)
+			continue
+		}
+
+		replacement := tally.leastMet(*opponent, idle)
+		if *match.HomeTeamID == teamID {
+			match.HomeTeamID = intPtr(replacement)
+			match.VenueID = venues[replacement]
+		} else {
+			match.AwayTeamID = intPtr(replacement)
+		}
+		tally.add(match)
+		change.Updated = append(change.Updated, match)
+	}
+
+	if len(deleted) > 0 {
+		kept := make([]*models.Match, 0, len(draw.Matches)-len(deleted))
+		for _, match := range draw.Matches {
+			if !deleted[match] {
+				kept = append(kept, match)
+			}
+		}
+		draw.Matches = kept
+	}
+
+	return change, nil
+}
+
+// drawTally tracks who plays whom and when while a draw's teams change
+type drawTally struct {
+	draw     *models.Draw
+	teams    map[int]bool
+	playing  map[int]map[int]bool // round -> teams playing
+	meetings map[string]int
+	home     map[int]int
+	away     map[int]int
+	byes     map[int]int
+}
+
+// newDrawTally tallies a draw's matches and each team's byes
+func newDrawTally(draw *models.Draw) *drawTally {
+	tally := &drawTally{
+		draw:     draw,
+		teams:    make(map[int]bool),
+		playing:  make(map[int]map[int]bool),
+		meetings: make(map[string]int),
+		home:     make(map[int]int),
+		away:     make(map[int]int),
+		byes:     make(map[int]int),
+	}
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		tally.teams[*match.HomeTeamID] = true
+		tally.teams[*match.AwayTeamID] = true
+		tally.add(match)
+	}
+	for round := 1; round <= draw.Rounds; round++ {
+		for teamID := range tally.teams {
+			if !tally.playing[round][teamID] {
+				tally.byes[teamID]++
+			}
+		}
+	}
+	return tally
+}
+
+// add counts a match
+func (t *drawTally) add(match *models.Match) {
+	home, away := *match.HomeTeamID, *match.AwayTeamID
+	if t.playing[match.Round] == nil {
+		t.playing[match.Round] = make(map[int]bool)
+	}
+	t.playing[match.Round][home] = true
+	t.playing[match.Round][away] = true
+	t.meetings[pairKey(home, away)]++
+	t.home[home]++
+	t.away[away]++
+}
+
+// remove stops counting a match
+func (t *drawTally) remove(match *models.Match) {
+	home, away := *match.HomeTeamID, *match.AwayTeamID
+	delete(t.playing[match.Round], home)
+	delete(t.playing[match.Round], away)
+	t.meetings[pairKey(home, away)]--
+	t.home[home]--
+	t.away[away]--
+}
+
+// idle returns the teams without a match in a round, other than the given
+// team, in ascending order
+func (t *drawTally) idle(round, except int) []int {
+	var idle []int
+	for teamID := range t.teams {
+		if teamID != except && !t.playing[round][teamID] {
+			idle = append(idle, teamID)
+		}
+	}
+	sort.Ints(idle)
+	return idle
+}
+
+// leastMet returns the candidate a team has met fewest times, the lowest ID
+// breaking ties. Candidates must be in ascending order.
+func (t *drawTally) leastMet(teamID int, candidates []int) int {
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if t.meetings[pairKey(teamID, candidate)] < t.meetings[pairKey(teamID, best)] {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// homeSide orders two teams as home and away, giving the home game to the
+// one furthest behind on home games
+func (t *drawTally) homeSide(a, b int) (home, away int) {
+	if t.away[b]-t.home[b] > t.away[a]-t.home[a] {
+		return b, a
+	}
+	return a, b
+}
+
+// displaceable picks the unlocked match in a round that a team should take a
+// place in, and the team it displaces: the one with the fewest byes, then
+// whose opponent the team has met least. It returns nil when every match in
+// the round is locked.
+func (t *drawTally) displaceable(round, teamID int) (*models.Match, int) {
+	var best *models.Match
+	bestDisplaced := 0
+	for _, match := range t.draw.Matches {
+		if match.Round != round || match.IsBye() || match.Locked {
+			continue
+		}
+		for _, displaced := range []int{*match.HomeTeamID, *match.AwayTeamID} {
+			keeper, _ := match.GetOpponent(displaced)
+			if best != nil {
+				bestKeeper, _ := best.GetOpponent(bestDisplaced)
+				if t.byes[displaced] > t.byes[bestDisplaced] {
+					continue
+				}
+				if t.byes[displaced] == t.byes[bestDisplaced] &&
+					t.meetings[pairKey(teamID, *keeper)] >= t.meetings[pairKey(teamID, *bestKeeper)] {
+					continue
+				}
+			}
+			best, bestDisplaced = match, displaced
+		}
+	}
+	return best, bestDisplaced
+}
+
+// homeVenues maps each team to its primary home venue
+func homeVenues(teams []*models.Team) map[int]*int {
+	venues := make(map[int]*int, len(teams))
+	for _, team := range teams {
+		venues[team.ID] = team.VenueID
+	}
+	return venues
+}
+
+// intPtr returns a pointer to a copy of i
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,188 @@
+package draw
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// roundRobin generates a single round-robin of the first n test teams
+func roundRobin(t *testing.T, teams []*models.Team, n int) *models.Draw {
+	t.Helper()
+	gen, err := NewGenerator(teams[:n], SingleRounds(n))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	draw, err := gen.GenerateRoundRobin()
+	if err != nil {
+		t.Fatalf("GenerateRoundRobin() error = %v", err)
+	}
+	return draw
+}
+
+// checkRounds fails when a team plays twice in a round, and returns how many
+// teams play in each round
+func checkRounds(t *testing.T, draw *models.Draw) map[int]int {
+	t.Helper()
+	playing := make(map[int]map[int]bool)
+	for _, match := range draw.Matches {
+		if playing[match.Round] == nil {
+			playing[match.Round] = make(map[int]bool)
+		}
+		for _, teamID := range []int{*match.HomeTeamID, *match.AwayTeamID} {
+			if playing[match.Round][teamID] {
+				t.Errorf("team %d plays twice in round %d", teamID, match.Round)
+			}
+			playing[match.Round][teamID] = true
+		}
+	}
+	counts := make(map[int]int)
+	for round, teams := range playing {
+		counts[round] = len(teams)
+	}
+	return counts
+}
+
+func TestAddTeam_FillsByes(t *testing.T) {
+	teams := createTestTeams(6)
+	draw := roundRobin(t, teams, 5)
+	before := len(draw.Matches)
+
+	change, err := AddTeam(draw, 6, teams)
+	if err != nil {
+		t.Fatalf("AddTeam() error = %v", err)
+	}
+
+	// Each round's bye team plays the new team, so nothing else changes
+	if len(change.Created) != 5 || len(change.Updated) != 0 || len(change.Deleted) != 0 {
+		t.Fatalf("expected 5 new matches only, got %d created, %d updated, %d deleted",
+			len(change.Created), len(change.Updated), len(change.Deleted))
+	}
+	if len(draw.Matches) != before+5 {
+		t.Errorf("expected %d matches, got %d", before+5, len(draw.Matches))
+	}
+
+	opponents := make(map[int]bool)
+	for _, match := range change.Created {
+		opponent, _ := match.GetOpponent(6)
+		opponents[*opponent] = true
+		if match.VenueID == nil || *match.VenueID != *match.HomeTeamID {
+			t.Errorf("match in round %d should be at the home team's venue", match.Round)
+		}
+	}
+	if len(opponents) != 5 {
+		t.Errorf("new team should meet every team once, met %v", opponents)
+	}
+
+	for round, count := range checkRounds(t, draw) {
+		if count != 6 {
+			t.Errorf("round %d should have every team playing, got %d", round, count)
+		}
+	}
+
+	if _, err := AddTeam(draw, 6, teams); !errors.Is(err, ErrTeamInDraw) {
+		t.Errorf("adding a team twice should fail, got %v", err)
+	}
+}
+
+func TestAddTeam_DisplacesTeams(t *testing.T) {
+	teams := createTestTeams(5)
+	draw := roundRobin(t, teams, 4)
+	locked := draw.Matches[0]
+	locked.Locked = true
+
+	change, err := AddTeam(draw, 5, teams)
+	if err != nil {
+		t.Fatalf("AddTeam() error = %v", err)
+	}
+
+	// Every team is playing, so the new team takes a place in one match a round
+	if len(change.Created) != 0 || len(change.Updated) != 3 {
+		t.Fatalf("expected 3 updated matches, got %d created, %d updated", len(change.Created), len(change.Updated))
+	}
+
+	displaced := make(map[int]bool)
+	for round, count := range checkRounds(t, draw) {
+		if count != 4 {
+			t.Errorf("round %d should have one team on a bye, got %d playing", round, count)
+		}
+	}
+	for _, match := range change.Updated {
+		if match == locked {
+			t.Error("locked match should keep its teams")
+		}
+		if !match.HasTeam(5) {
+			t.Errorf("updated match in round %d should include the new team", match.Round)
+		}
+	}
+	for _, team := range teams[:4] {
+		playing := 0
+		for _, match := range draw.Matches {
+			if match.HasTeam(team.ID) {
+				playing++
+			}
+		}
+		if playing == 2 {
+			displaced[team.ID] = true
+		}
+	}
+	if len(displaced) != 3 {
+		t.Errorf("byes should go to different teams, got %v", displaced)
+	}
+}
+
+func TestRemoveTeam(t *testing.T) {
+	teams := createTestTeams(5)
+	draw := roundRobin(t, teams, 5)
+
+	change, err := RemoveTeam(draw, 3, teams)
+	if err != nil {
+		t.Fatalf("RemoveTeam() error = %v", err)
+	}
+
+	// The round's bye team takes each of the removed team's matches
+	if len(change.Updated) != 4 || len(change.Deleted) != 0 {
+		t.Fatalf("expected 4 updated matches, got %d updated, %d deleted", len(change.Updated), len(change.Deleted))
+	}
+	for _, match := range draw.Matches {
+		if match.HasTeam(3) {
+			t.Errorf("removed team still plays in round %d", match.Round)
+		}
+	}
+	for round, count := range checkRounds(t, draw) {
+		if count != 4 {
+			t.Errorf("round %d should have every remaining team playing, got %d", round, count)
+		}
+	}
+
+	if _, err := RemoveTeam(draw, 3, teams); !errors.Is(err, ErrTeamNotInDraw) {
+		t.Errorf("removing a team twice should fail, got %v", err)
+	}
+}
+
+func TestRemoveTeam_DeletesMatches(t *testing.T) {
+	teams := createTestTeams(4)
+	draw := roundRobin(t, teams, 4)
+
+	change, err := RemoveTeam(draw, 1, teams)
+	if err != nil {
+		t.Fatalf("RemoveTeam() error = %v", err)
+	}
+
+	// Nobody has a bye to take over, so the opponents get one instead
+	if len(change.Deleted) != 3 || len(draw.Matches) != 3 {
+		t.Errorf("expected 3 deleted matches leaving 3, got %d deleted leaving %d", len(change.Deleted), len(draw.Matches))
+	}
+
+	draw = roundRobin(t, teams, 4)
+	for _, match := range draw.Matches {
+		if match.HasTeam(2) {
+			match.Locked = true
+			break
+		}
+	}
+	if _, err := RemoveTeam(draw, 2, teams); !errors.Is(err, ErrTeamHasLockedMatches) {
+		t.Errorf("removing a team with locked matches should fail, got %v", err)
+	}
+}
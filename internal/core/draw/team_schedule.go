@@ -0,0 +1,92 @@
+package draw
+
+import (
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// TeamRound is one round of a team's season, seen from that team's side
+type TeamRound struct {
+	Round int
+	// Match is nil when the team has a bye
+	Match    *models.Match
+	Opponent *models.Team
+	Home     bool
+	// RestDays counts the days between the team's previous match and this
+	// one, not counting either match day. It's nil until both are dated.
+	RestDays *int
+	// ConsecutiveAway counts the team's away games in a row up to and
+	// including this one; home games and byes reset it
+	ConsecutiveAway int
+}
+
+// TeamSchedule returns a team's season in round order, one entry per round
+// with byes included. Matches need their teams loaded, as returned by
+// ListByDrawWithRelations, for the opponents to be filled in.
+func TeamSchedule(draw *models.Draw, teamID int) ([]TeamRound, error) {
+	matchesByRound := make(map[int][]*models.Match)
+	for _, match := range draw.Matches {
+		if match.HasTeam(teamID) {
+			matchesByRound[match.Round] = append(matchesByRound[match.Round], match)
+		}
+	}
+	if len(matchesByRound) == 0 {
+		return nil, ErrTeamNotInDraw
+	}
+
+	var schedule []TeamRound
+	var previous *models.Match
+	awayStreak := 0
+	for round := 1; round <= draw.Rounds; round++ {
+		matches := matchesByRound[round]
+		if len(matches) == 0 {
+			awayStreak = 0
+			schedule = append(schedule, TeamRound{Round: round})
+			continue
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matchBefore(matches[i], matches[j])
+		})
+		for _, match := range matches {
+			entry := TeamRound{Round: round, Match: match}
+			entry.Home, _ = match.IsHomeGame(teamID)
+			if entry.Home {
+				entry.Opponent = match.AwayTeam
+				awayStreak = 0
+			} else {
+				entry.Opponent = match.HomeTeam
+				awayStreak++
+			}
+			entry.ConsecutiveAway = awayStreak
+
+			if previous != nil && previous.MatchDate != nil && match.MatchDate != nil {
+				days := restDays(*previous.MatchDate, *match.MatchDate)
+				entry.RestDays = &days
+			}
+			previous = match
+
+			schedule = append(schedule, entry)
+		}
+	}
+
+	return schedule, nil
+}
+
+// matchBefore orders matches by date, undated matches last
+func matchBefore(a, b *models.Match) bool {
+	if a.MatchDate == nil || b.MatchDate == nil {
+		return a.MatchDate != nil
+	}
+	return a.MatchDate.Before(*b.MatchDate)
+}
+
+// restDays returns the calendar days between two match dates, not counting
+// either match day
+func restDays(previous, next time.Time) int {
+	dayA := time.Date(previous.Year(), previous.Month(), previous.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dayB.Sub(dayA).Hours()/24) - 1
+}
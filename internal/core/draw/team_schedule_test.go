@@ -0,0 +1,60 @@
+package draw
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestTeamSchedule(t *testing.T) {
+	team := func(id int) *int { return &id }
+	date := func(day int) *time.Time {
+		d := time.Date(2025, time.March, day, 19, 30, 0, 0, time.UTC)
+		return &d
+	}
+	opponent := &models.Team{ID: 2, Name: "Storm"}
+
+	draw := &models.Draw{
+		ID:     1,
+		Rounds: 5,
+		Matches: []*models.Match{
+			{ID: 4, Round: 4, HomeTeamID: team(1), AwayTeamID: team(2), MatchDate: date(27)},
+			{ID: 1, Round: 1, HomeTeamID: team(2), AwayTeamID: team(1), AwayTeam: &models.Team{ID: 1}, HomeTeam: opponent, MatchDate: date(6)},
+			{ID: 2, Round: 2, HomeTeamID: team(3), AwayTeamID: team(1), MatchDate: date(14), IsPrimeTime: true},
+			{ID: 5, Round: 5, HomeTeamID: team(4), AwayTeamID: team(1)},
+			{ID: 6, Round: 3, HomeTeamID: team(2), AwayTeamID: team(3)},
+		},
+	}
+
+	schedule, err := TeamSchedule(draw, 1)
+	if err != nil {
+		t.Fatalf("TeamSchedule() error = %v", err)
+	}
+	if len(schedule) != 5 {
+		t.Fatalf("expected 5 rounds, got %d", len(schedule))
+	}
+
+	first := schedule[0]
+	if first.Match.ID != 1 || first.Home || first.Opponent != opponent || first.RestDays != nil || first.ConsecutiveAway != 1 {
+		t.Errorf("unexpected round 1: %+v", first)
+	}
+	second := schedule[1]
+	if second.RestDays == nil || *second.RestDays != 7 || second.ConsecutiveAway != 2 || !second.Match.IsPrimeTime {
+		t.Errorf("expected 7 rest days and a second away game in round 2, got %+v", second)
+	}
+	if schedule[2].Match != nil || schedule[2].ConsecutiveAway != 0 {
+		t.Errorf("expected a bye in round 3, got %+v", schedule[2])
+	}
+	if !schedule[3].Home || schedule[3].ConsecutiveAway != 0 || *schedule[3].RestDays != 12 {
+		t.Errorf("expected a home game 12 days after round 2 in round 4, got %+v", schedule[3])
+	}
+	if schedule[4].RestDays != nil || schedule[4].ConsecutiveAway != 1 {
+		t.Errorf("expected an undated away game in round 5, got %+v", schedule[4])
+	}
+
+	if _, err := TeamSchedule(draw, 9); !errors.Is(err, ErrTeamNotInDraw) {
+		t.Errorf("expected ErrTeamNotInDraw, got %v", err)
+	}
+}
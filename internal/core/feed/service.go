@@ -0,0 +1,168 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/approval"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// ErrDrawNotPublished is returned for draws that haven't been published
+var ErrDrawNotPublished = errors.New("draw has not been published")
+
+// Fixture is the public fixture of a published draw. Its encoding only
+// changes when the fixture does, so it can be cached on its content.
+type Fixture struct {
+	DrawID        int            `json:"draw_id"`
+	Name          string         `json:"name"`
+	SeasonYear    int            `json:"season_year"`
+	Rounds        int            `json:"rounds"`
+	CompetitionID *int           `json:"competition_id,omitempty"`
+	Version       string         `json:"version"`
+	PublishedAt   time.Time      `json:"published_at"`
+	Matches       []FixtureMatch `json:"matches"`
+
+	// LastModified is when the draw or any of its matches last changed
+	LastModified time.Time `json:"-"`
+}
+
+// FixtureMatch is a single match in the feed. IDs are kept so apps can follow
+// a match as it is rescheduled.
+type FixtureMatch struct {
+	ID          int    `json:"id"`
+	Round       int    `json:"round"`
+	Date        string `json:"date,omitempty"`
+	Time        string `json:"time,omitempty"`
+	HomeTeamID  *int   `json:"home_team_id,omitempty"`
+	HomeTeam    string `json:"home_team,omitempty"`
+	AwayTeamID  *int   `json:"away_team_id,omitempty"`
+	AwayTeam    string `json:"away_team,omitempty"`
+	VenueID     *int   `json:"venue_id,omitempty"`
+	Venue       string `json:"venue,omitempty"`
+	IsPrimeTime bool   `json:"is_prime_time"`
+}
+
+// Service builds the public fixture feed for published draws
+type Service struct {
+	repository storage.Repositories
+}
+
+// NewService creates a new feed service
+func NewService(repository storage.Repositories) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// PublishedFixture returns the current fixture of a published draw, in round
+// then kickoff order. Changes made since publication, such as reschedules,
+// are included.
+func (s *Service) PublishedFixture(ctx context.Context, drawID int) (*Fixture, error) {
+	draw, err := s.repository.Draws().Get(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	if !draw.IsPublished() {
+		return nil, ErrDrawNotPublished
+	}
+
+	matches, err := s.repository.Matches().ListByDrawWithRelations(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	draw.Matches = matches
+
+	return buildFixture(draw), nil
+}
+
+// buildFixture captures a draw's fixture with display names
+func buildFixture(draw *models.Draw) *Fixture {
+	fixture := &Fixture{
+		DrawID:        draw.ID,
+		Name:          draw.Name,
+		SeasonYear:    draw.SeasonYear,
+		Rounds:        draw.Rounds,
+		CompetitionID: draw.CompetitionID,
+		Version:       approval.DrawVersion(draw),
+		PublishedAt:   *draw.PublishedAt,
+		Matches:       make([]FixtureMatch, 0, len(draw.Matches)),
+		LastModified:  latest(draw.UpdatedAt, *draw.PublishedAt),
+	}
+
+	matches := make([]*models.Match, len(draw.Matches))
+	copy(matches, draw.Matches)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return kickoffBefore(matches[i], matches[j])
+	})
+
+	for _, match := range matches {
+		fixtureMatch := FixtureMatch{
+			ID:          match.ID,
+			Round:       match.Round,
+			HomeTeamID:  match.HomeTeamID,
+			AwayTeamID:  match.AwayTeamID,
+			VenueID:     match.VenueID,
+			IsPrimeTime: match.IsPrimeTime,
+		}
+		if match.MatchDate != nil {
+			fixtureMatch.Date = match.MatchDate.Format("2006-01-02")
+		}
+		if match.MatchTime != nil {
+			fixtureMatch.Time = match.MatchTime.Format("15:04")
+		}
+		if match.HomeTeam != nil {
+			fixtureMatch.HomeTeam = match.HomeTeam.Name
+		}
+		if match.AwayTeam != nil {
+			fixtureMatch.AwayTeam = match.AwayTeam.Name
+		}
+		if match.Venue != nil {
+			fixtureMatch.Venue = match.Venue.Name
+		}
+		fixture.Matches = append(fixture.Matches, fixtureMatch)
+		fixture.LastModified = latest(fixture.LastModified, match.UpdatedAt)
+	}
+
+	return fixture
+}
+
+// kickoffBefore orders matches by round, then kickoff, with undated matches
+// last in their round, then by ID
+func kickoffBefore(a, b *models.Match) bool {
+	if a.Round != b.Round {
+		return a.Round < b.Round
+	}
+	aKickoff, aDated := kickoff(a)
+	bKickoff, bDated := kickoff(b)
+	if aDated != bDated {
+		return aDated
+	}
+	if aDated && !aKickoff.Equal(bKickoff) {
+		return aKickoff.Before(bKickoff)
+	}
+	return a.ID < b.ID
+}
+
+// kickoff combines a match's date and time, reporting false when it is undated
+func kickoff(match *models.Match) (time.Time, bool) {
+	if match.MatchDate == nil {
+		return time.Time{}, false
+	}
+	date := *match.MatchDate
+	if match.MatchTime == nil {
+		return date, true
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(),
+		match.MatchTime.Hour(), match.MatchTime.Minute(), 0, 0, date.Location()), true
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,129 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// Backfill result statuses
+const (
+	BackfillUpdated  = "updated"
+	BackfillNotFound = "not_found"
+	BackfillFailed   = "failed"
+)
+
+// BackfillResult records the outcome for a single team or venue
+type BackfillResult struct {
+	Kind      string  `json:"kind"` // "team" or "venue"
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+	Source    string  `json:"source,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// BackfillReport summarises a coordinate backfill run
+type BackfillReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Updated  int              `json:"updated"`
+	NotFound int              `json:"not_found"`
+	Failed   int              `json:"failed"`
+	Results  []BackfillResult `json:"results"`
+}
+
+// BackfillCoordinates geocodes every venue and team that has no latitude/longitude.
+// Venues are resolved first so teams can inherit their home venue's coordinates.
+// With dryRun set the report is produced but nothing is written.
+func BackfillCoordinates(ctx context.Context, repos storage.Repositories, geocoder Geocoder, dryRun bool) (*BackfillReport, error) {
+	report := &BackfillReport{DryRun: dryRun, Results: []BackfillResult{}}
+
+	venues, err := repos.Venues().List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list venues: %w", err)
+	}
+
+	venueLocations := make(map[int]Location)
+	for _, venue := range venues {
+		if HasCoordinates(venue.Latitude, venue.Longitude) {
+			venueLocations[venue.ID] = Location{Latitude: venue.Latitude, Longitude: venue.Longitude, Source: "venue"}
+			continue
+		}
+
+		result := BackfillResult{Kind: "venue", ID: venue.ID, Name: venue.Name}
+		location, err := geocoder.Geocode(ctx, Query{Name: venue.Name, City: venue.City})
+		if err == nil {
+			venueLocations[venue.ID] = location
+			if !dryRun {
+				venue.Latitude = location.Latitude
+				venue.Longitude = location.Longitude
+				err = repos.Venues().Update(ctx, venue)
+			}
+		}
+		report.add(result, location, err)
+	}
+
+	teams, err := repos.Teams().List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	for _, team := range teams {
+		if HasCoordinates(team.Latitude, team.Longitude) {
+			continue
+		}
+
+		result := BackfillResult{Kind: "team", ID: team.ID, Name: team.Name}
+
+		var location Location
+		var err error
+		if home, ok := homeVenueLocation(team.VenueID, venueLocations); ok {
+			location = home
+			location.Source = "venue"
+		} else {
+			location, err = geocoder.Geocode(ctx, Query{Name: team.Name, City: team.City})
+		}
+
+		if err == nil && !dryRun {
+			team.Latitude = location.Latitude
+			team.Longitude = location.Longitude
+			err = repos.Teams().Update(ctx, team)
+		}
+		report.add(result, location, err)
+	}
+
+	return report, nil
+}
+
+// homeVenueLocation returns the resolved coordinates of a team's home venue
+func homeVenueLocation(venueID *int, locations map[int]Location) (Location, bool) {
+	if venueID == nil {
+		return Location{}, false
+	}
+	location, ok := locations[*venueID]
+	return location, ok
+}
+
+// add records a result against the report's totals
+func (r *BackfillReport) add(result BackfillResult, location Location, err error) {
+	switch {
+	case err == nil:
+		result.Status = BackfillUpdated
+		result.Latitude = location.Latitude
+		result.Longitude = location.Longitude
+		result.Source = location.Source
+		r.Updated++
+	case errors.Is(err, ErrLocationNotFound):
+		result.Status = BackfillNotFound
+		r.NotFound++
+	default:
+		result.Status = BackfillFailed
+		result.Error = err.Error()
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
@@ -0,0 +1,28 @@
+package geo
+
+import "math"
+
+// EarthRadiusKm is the mean radius of the Earth in kilometres
+const EarthRadiusKm = 6371.0
+
+// HaversineKm returns the great-circle distance in kilometres between two coordinates
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return EarthRadiusKm * c
+}
+
+// HasCoordinates reports whether a latitude/longitude pair has been set.
+// Teams and venues default to 0,0 when no location has been recorded.
+func HasCoordinates(lat, lon float64) bool {
+	return lat != 0 || lon != 0
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,40 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversineKm(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat1     float64
+		lon1     float64
+		lat2     float64
+		lon2     float64
+		expected float64
+	}{
+		{"same point", -33.8470, 151.0634, -33.8470, 151.0634, 0},
+		{"Sydney to Brisbane", -33.8688, 151.2093, -27.4698, 153.0251, 732},
+		{"Sydney to Melbourne", -33.8688, 151.2093, -37.8136, 144.9631, 714},
+		{"Sydney to Auckland", -33.8688, 151.2093, -36.8485, 174.7633, 2156},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HaversineKm(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.expected) > 5 {
+				t.Errorf("HaversineKm() = %.1f, want ~%.0f", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasCoordinates(t *testing.T) {
+	if HasCoordinates(0, 0) {
+		t.Error("Expected 0,0 to be treated as unset")
+	}
+	if !HasCoordinates(-33.8688, 151.2093) {
+		t.Error("Expected Sydney coordinates to be set")
+	}
+}
@@ -0,0 +1,170 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrLocationNotFound is returned when a geocoder has no match for a query
+var ErrLocationNotFound = errors.New("location not found")
+
+// Query describes a place to geocode
+type Query struct {
+	Name string
+	City string
+}
+
+// Location is a geocoded coordinate pair with a note about how it was resolved
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Source    string  `json:"source"`
+}
+
+// Geocoder resolves a place to coordinates
+type Geocoder interface {
+	Geocode(ctx context.Context, query Query) (Location, error)
+}
+
+// StaticGeocoder resolves places from an in-memory table of named locations.
+// Names are matched first, falling back to the city.
+type StaticGeocoder struct {
+	places map[string]Location
+	cities map[string]Location
+}
+
+// NewStaticGeocoder creates an empty static geocoder
+func NewStaticGeocoder() *StaticGeocoder {
+	return &StaticGeocoder{
+		places: make(map[string]Location),
+		cities: make(map[string]Location),
+	}
+}
+
+// NewOfflineGeocoder creates a static geocoder loaded with Australian and New Zealand
+// rugby league stadiums and the cities they are in
+func NewOfflineGeocoder() *StaticGeocoder {
+	g := NewStaticGeocoder()
+	for _, stadium := range offlineStadiums {
+		for _, name := range stadium.names {
+			g.AddPlace(name, stadium.lat, stadium.lon)
+		}
+	}
+	for _, city := range offlineCities {
+		g.AddCity(city.name, city.lat, city.lon)
+	}
+	return g
+}
+
+// AddPlace registers a named location such as a stadium
+func (g *StaticGeocoder) AddPlace(name string, lat, lon float64) {
+	g.places[normalizeName(name)] = Location{Latitude: lat, Longitude: lon, Source: "place"}
+}
+
+// AddCity registers a city centre used when no named place matches
+func (g *StaticGeocoder) AddCity(name string, lat, lon float64) {
+	g.cities[normalizeName(name)] = Location{Latitude: lat, Longitude: lon, Source: "city"}
+}
+
+// Geocode looks up the query's name, then its city
+func (g *StaticGeocoder) Geocode(ctx context.Context, query Query) (Location, error) {
+	if location, ok := g.places[normalizeName(query.Name)]; ok {
+		return location, nil
+	}
+	if location, ok := g.cities[normalizeName(query.City)]; ok {
+		return location, nil
+	}
+	return Location{}, ErrLocationNotFound
+}
+
+// normalizeName lowercases a name and strips punctuation and spacing
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+type offlineStadium struct {
+	names []string
+	lat   float64
+	lon   float64
+}
+
+type offlineCity struct {
+	name string
+	lat  float64
+	lon  float64
+}
+
+// offlineStadiums lists current and former names for each ground
+var offlineStadiums = []offlineStadium{
+	{[]string{"Accor Stadium", "Stadium Australia", "ANZ Stadium"}, -33.8470, 151.0634},
+	{[]string{"Allianz Stadium", "Sydney Football Stadium"}, -33.8893, 151.2247},
+	{[]string{"CommBank Stadium", "Western Sydney Stadium", "Bankwest Stadium"}, -33.8076, 151.0037},
+	{[]string{"Sydney Cricket Ground", "SCG"}, -33.8917, 151.2247},
+	{[]string{"4 Pines Park", "Brookvale Oval", "Lottoland"}, -33.7668, 151.2673},
+	{[]string{"Leichhardt Oval"}, -33.8742, 151.1607},
+	{[]string{"Campbelltown Sports Stadium", "Campbelltown Stadium"}, -34.0569, 150.8276},
+	{[]string{"PointsBet Stadium", "Ocean Protect Stadium", "Shark Park"}, -34.0430, 151.1259},
+	{[]string{"BlueBet Stadium", "Penrith Stadium", "Panthers Stadium"}, -33.7546, 150.6869},
+	{[]string{"Belmore Sports Ground", "Belmore Oval"}, -33.9170, 151.0900},
+	{[]string{"Netstrata Jubilee Stadium", "Jubilee Oval", "Kogarah Oval"}, -33.9667, 151.1340},
+	{[]string{"WIN Stadium"}, -34.4290, 150.9005},
+	{[]string{"Central Coast Stadium", "Industree Group Stadium"}, -33.4283, 151.3419},
+	{[]string{"McDonald Jones Stadium", "Newcastle International Sports Centre", "Hunter Stadium"}, -32.9184, 151.7267},
+	{[]string{"Carrington Park"}, -33.4233, 149.5780},
+	{[]string{"Scully Park"}, -31.0934, 150.9216},
+	{[]string{"Suncorp Stadium", "Lang Park"}, -27.4648, 153.0095},
+	{[]string{"Kayo Stadium", "Moreton Daily Stadium", "Dolphin Stadium"}, -27.2362, 153.1068},
+	{[]string{"Cbus Super Stadium", "Robina Stadium"}, -28.0734, 153.3826},
+	{[]string{"Sunshine Coast Stadium"}, -26.7059, 153.1192},
+	{[]string{"Queensland Country Bank Stadium", "North Queensland Stadium"}, -19.2585, 146.8185},
+	{[]string{"Barlow Park"}, -16.9307, 145.7581},
+	{[]string{"BB Print Stadium", "Mackay Stadium"}, -21.1596, 149.1590},
+	{[]string{"GIO Stadium", "Canberra Stadium"}, -35.2503, 149.1024},
+	{[]string{"AAMI Park", "Melbourne Rectangular Stadium"}, -37.8251, 144.9837},
+	{[]string{"Marvel Stadium", "Docklands Stadium"}, -37.8165, 144.9475},
+	{[]string{"Adelaide Oval"}, -34.9156, 138.5961},
+	{[]string{"Optus Stadium", "Perth Stadium"}, -31.9512, 115.8890},
+	{[]string{"TIO Stadium", "Marrara Oval"}, -12.3990, 130.8870},
+	{[]string{"Go Media Stadium", "Mount Smart Stadium", "Mt Smart Stadium"}, -36.9185, 174.8126},
+	{[]string{"Eden Park"}, -36.8750, 174.7446},
+	{[]string{"Sky Stadium", "Wellington Regional Stadium"}, -41.2729, 174.7859},
+	{[]string{"Apollo Projects Stadium", "Orangetheory Stadium"}, -43.5415, 172.5940},
+}
+
+var offlineCities = []offlineCity{
+	{"Sydney", -33.8688, 151.2093},
+	{"Parramatta", -33.8150, 151.0011},
+	{"Penrith", -33.7507, 150.6877},
+	{"Cronulla", -34.0587, 151.1522},
+	{"Manly", -33.7969, 151.2840},
+	{"Campbelltown", -34.0650, 150.8142},
+	{"Wollongong", -34.4278, 150.8931},
+	{"Gosford", -33.4267, 151.3417},
+	{"Newcastle", -32.9283, 151.7817},
+	{"Bathurst", -33.4193, 149.5775},
+	{"Tamworth", -31.0927, 150.9320},
+	{"Canberra", -35.2809, 149.1300},
+	{"Brisbane", -27.4698, 153.0251},
+	{"Redcliffe", -27.2307, 153.1150},
+	{"Gold Coast", -28.0167, 153.4000},
+	{"Sunshine Coast", -26.6500, 153.0667},
+	{"Townsville", -19.2590, 146.8169},
+	{"Cairns", -16.9186, 145.7781},
+	{"Mackay", -21.1411, 149.1860},
+	{"Melbourne", -37.8136, 144.9631},
+	{"Adelaide", -34.9285, 138.6007},
+	{"Perth", -31.9505, 115.8605},
+	{"Darwin", -12.4634, 130.8456},
+	{"Hobart", -42.8821, 147.3272},
+	{"Auckland", -36.8485, 174.7633},
+	{"Wellington", -41.2865, 174.7762},
+	{"Christchurch", -43.5321, 172.6362},
+}
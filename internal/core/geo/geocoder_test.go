@@ -0,0 +1,38 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOfflineGeocoder(t *testing.T) {
+	geocoder := NewOfflineGeocoder()
+	ctx := context.Background()
+
+	// Stadium names match regardless of case and punctuation, including former names
+	location, err := geocoder.Geocode(ctx, Query{Name: "suncorp stadium"})
+	if err != nil {
+		t.Fatalf("Expected Suncorp Stadium to geocode, got %v", err)
+	}
+	if location.Source != "place" || location.Latitude != -27.4648 {
+		t.Errorf("Unexpected location for Suncorp Stadium: %+v", location)
+	}
+
+	if _, err := geocoder.Geocode(ctx, Query{Name: "Mt. Smart Stadium"}); err != nil {
+		t.Errorf("Expected former stadium name to geocode, got %v", err)
+	}
+
+	// Unknown names fall back to the city
+	location, err = geocoder.Geocode(ctx, Query{Name: "Brisbane Broncos", City: "Brisbane"})
+	if err != nil {
+		t.Fatalf("Expected city fallback, got %v", err)
+	}
+	if location.Source != "city" {
+		t.Errorf("Expected city source, got %s", location.Source)
+	}
+
+	if _, err := geocoder.Geocode(ctx, Query{Name: "Nowhere Oval", City: "Atlantis"}); !errors.Is(err, ErrLocationNotFound) {
+		t.Errorf("Expected ErrLocationNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package geo
+
+import (
+	"time"
+	_ "time/tzdata" // zones don't depend on the host's zoneinfo
+)
+
+// LegKind classifies how a team gets to a match
+type LegKind string
+
+const (
+	// LegDrive is close enough for the team to travel by road
+	LegDrive LegKind = "drive"
+	// LegShortFlight is a domestic flight such as Sydney to Brisbane
+	LegShortFlight LegKind = "short_flight"
+	// LegLongFlight is long-haul, such as to Perth or across the Tasman
+	LegLongFlight LegKind = "long_flight"
+)
+
+// Distances, one way, at which teams stop driving and start flying long-haul
+const (
+	MaxDriveKm       = 300.0
+	MaxShortFlightKm = 1500.0
+)
+
+// ClassifyLeg returns how a team travels a one-way distance
+func ClassifyLeg(km float64) LegKind {
+	switch {
+	case km <= MaxDriveKm:
+		return LegDrive
+	case km <= MaxShortFlightKm:
+		return LegShortFlight
+	default:
+		return LegLongFlight
+	}
+}
+
+var (
+	auckland = mustLoadLocation("Pacific/Auckland")
+	perth    = mustLoadLocation("Australia/Perth")
+	darwin   = mustLoadLocation("Australia/Darwin")
+	adelaide = mustLoadLocation("Australia/Adelaide")
+	brisbane = mustLoadLocation("Australia/Brisbane")
+	sydney   = mustLoadLocation("Australia/Sydney")
+)
+
+// TimeZone returns the time zone of a location in Australia or New Zealand.
+// State borders are approximated, which is close enough for the cities
+// matches are played in. Queensland, like Papua New Guinea, keeps standard
+// time all year, so it's an hour behind New South Wales and Victoria over
+// daylight saving.
+func TimeZone(lat, lon float64) *time.Location {
+	switch {
+	case lon >= 165 || lon <= -175:
+		return auckland
+	case lon < 129:
+		return perth
+	case lon < 138 && lat > -26:
+		return darwin
+	case lon < 141:
+		return adelaide
+	case lat > -28.17:
+		return brisbane
+	default:
+		return sydney
+	}
+}
+
+// ZoneShiftHours returns how many hours clocks move travelling from one
+// location to another at the given time, positive when they go forward
+func ZoneShiftHours(fromLat, fromLon, toLat, toLon float64, at time.Time) float64 {
+	_, from := at.In(TimeZone(fromLat, fromLon)).Zone()
+	_, to := at.In(TimeZone(toLat, toLon)).Zone()
+	return float64(to-from) / 3600
+}
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
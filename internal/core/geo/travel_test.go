@@ -0,0 +1,58 @@
+package geo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyLeg(t *testing.T) {
+	tests := []struct {
+		km   float64
+		want LegKind
+	}{
+		{0, LegDrive},
+		{150, LegDrive},        // Sydney to Newcastle
+		{730, LegShortFlight},  // Sydney to Brisbane
+		{1370, LegShortFlight}, // Brisbane to Melbourne
+		{2150, LegLongFlight},  // Sydney to Auckland
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyLeg(tt.km); got != tt.want {
+			t.Errorf("ClassifyLeg(%v) = %v, want %v", tt.km, got, tt.want)
+		}
+	}
+}
+
+func TestZoneShiftHours(t *testing.T) {
+	const (
+		sydneyLat, sydneyLon     = -33.8470, 151.0634
+		brisbaneLat, brisbaneLon = -27.4648, 153.0095
+		aucklandLat, aucklandLon = -36.9036, 174.7441
+		perthLat, perthLon       = -31.9510, 115.8890
+	)
+	daylightSaving := time.Date(2025, time.March, 15, 9, 0, 0, 0, time.UTC)
+	winter := time.Date(2025, time.July, 15, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		fromLat, fromLon float64
+		toLat, toLon     float64
+		at               time.Time
+		want             float64
+	}{
+		{"Brisbane to Sydney over daylight saving", brisbaneLat, brisbaneLon, sydneyLat, sydneyLon, daylightSaving, 1},
+		{"Brisbane to Sydney in winter", brisbaneLat, brisbaneLon, sydneyLat, sydneyLon, winter, 0},
+		{"Sydney to Auckland", sydneyLat, sydneyLon, aucklandLat, aucklandLon, winter, 2},
+		{"Brisbane to Auckland over daylight saving", brisbaneLat, brisbaneLon, aucklandLat, aucklandLon, daylightSaving, 3},
+		{"Sydney to Perth", sydneyLat, sydneyLon, perthLat, perthLon, winter, -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ZoneShiftHours(tt.fromLat, tt.fromLon, tt.toLat, tt.toLon, tt.at); got != tt.want {
+				t.Errorf("ZoneShiftHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
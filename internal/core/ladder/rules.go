@@ -0,0 +1,292 @@
+package ladder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/draw"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Errors returned when deriving repeat matchups
+var (
+	ErrInvalidRule   = errors.New("invalid repeat rule")
+	ErrUnsatisfiable = errors.New("repeat rules can't be satisfied")
+)
+
+// PositionRange is an inclusive span of ladder positions
+type PositionRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Contains returns true if the position is within the range
+func (r PositionRange) Contains(position int) bool {
+	return position >= r.From && position <= r.To
+}
+
+// RepeatRule makes teams finishing in Positions play a second time. Without
+// Against they play each other, so the top 6 playing each other twice is
+// {Positions: {1, 6}}; with Against they play every team in that range.
+type RepeatRule struct {
+	Positions PositionRange  `json:"positions"`
+	Against   *PositionRange `json:"against,omitempty"`
+}
+
+// OpponentMatrix lists who plays whom twice in the coming season
+type OpponentMatrix struct {
+	Season          int            `json:"season"`
+	RepeatOpponents int            `json:"repeat_opponents"`
+	Matchups        []draw.Matchup `json:"matchups"`
+	// Opponents lists each team's repeat opponents in ladder order
+	Opponents map[int][]int `json:"opponents"`
+	// FromRules is how many matchups the rules fixed; the rest were filled by
+	// pairing teams close on the ladder
+	FromRules int `json:"from_rules"`
+}
+
+// FixtureTemplate returns the template that makes the generator use the matrix
+func (m *OpponentMatrix) FixtureTemplate() draw.FixtureTemplate {
+	return draw.FixtureTemplate{
+		RepeatOpponents: m.RepeatOpponents,
+		RepeatMatchups:  m.Matchups,
+	}
+}
+
+// DeriveRepeats applies the rules to a final ladder in order, then gives every
+// team its remaining repeat opponents by pairing the teams still needing the
+// most, closest on the ladder first. Entries must be sorted by position.
+func DeriveRepeats(season int, entries []*models.LadderEntry, rules []RepeatRule, repeatOpponents int) (*OpponentMatrix, error) {
+	numTeams := len(entries)
+	if repeatOpponents < 0 || repeatOpponents > numTeams-1 {
+		return nil, fmt.Errorf("%w: repeat_opponents must be between 0 and %d", ErrInvalidRule, numTeams-1)
+	}
+	if (numTeams*repeatOpponents)%2 != 0 {
+		return nil, fmt.Errorf("%w: %d teams can't each have %d repeat opponents", ErrUnsatisfiable, numTeams, repeatOpponents)
+	}
+
+	// Teams are handled as indexes into the ladder
+	need := make([]int, numTeams)
+	for i := range need {
+		need[i] = repeatOpponents
+	}
+	paired := make(map[[2]int]bool)
+	addPair := func(a, b int) {
+		if a == b || paired[orderedPair(a, b)] {
+			return
+		}
+		paired[orderedPair(a, b)] = true
+		need[a]--
+		need[b]--
+	}
+
+	var ruled [][2]int
+	for i, rule := range rules {
+		against := rule.Positions
+		if rule.Against != nil {
+			against = *rule.Against
+		}
+		for _, span := range []PositionRange{rule.Positions, against} {
+			if span.From < 1 || span.To > numTeams || span.From > span.To {
+				return nil, fmt.Errorf("%w: rule %d covers positions %d-%d, the ladder has %d", ErrInvalidRule, i+1, span.From, span.To, numTeams)
+			}
+		}
+
+		for a, entry := range entries {
+			if !rule.Positions.Contains(entry.Position) {
+				continue
+			}
+			for b, opponent := range entries {
+				if against.Contains(opponent.Position) && a != b && !paired[orderedPair(a, b)] {
+					addPair(a, b)
+					ruled = append(ruled, orderedPair(a, b))
+				}
+			}
+		}
+
+		for team, remaining := range need {
+			if remaining < 0 {
+				return nil, fmt.Errorf("%w: rule %d gives team %d %d repeat opponents, more than %d",
+					ErrUnsatisfiable, i+1, entries[team].TeamID, repeatOpponents-remaining, repeatOpponents)
+			}
+		}
+	}
+	fixed := make(map[[2]int]bool)
+	for _, pair := range ruled {
+		fixed[pair] = true
+	}
+
+	done := make([]bool, numTeams)
+	for {
+		team := -1
+		for i := 0; i < numTeams; i++ {
+			if !done[i] && need[i] > 0 && (team == -1 || need[i] > need[team]) {
+				team = i
+			}
+		}
+		if team == -1 {
+			break
+		}
+		done[team] = true
+
+		var candidates []int
+		for i := 0; i < numTeams; i++ {
+			if !done[i] && need[i] > 0 && !paired[orderedPair(team, i)] {
+				candidates = append(candidates, i)
+			}
+		}
+		// Most needed first, closest on the ladder between equals
+		for i := 0; i < len(candidates)-1; i++ {
+			for j := i + 1; j < len(candidates); j++ {
+				a, b := candidates[i], candidates[j]
+				if need[b] > need[a] || (need[b] == need[a] && distance(team, b) < distance(team, a)) {
+					candidates[i], candidates[j] = b, a
+				}
+			}
+		}
+		if len(candidates) > need[team] {
+			candidates = candidates[:need[team]]
+		}
+
+		for _, opponent := range candidates {
+			addPair(team, opponent)
+		}
+	}
+
+	// The greedy pass can strand teams whose closest candidates were already
+	// taken; pair stranded teams up, or reroute filled matchups to them
+	for {
+		team := -1
+		for i := 0; i < numTeams && team == -1; i++ {
+			if need[i] > 0 {
+				team = i
+			}
+		}
+		if team == -1 {
+			break
+		}
+
+		other := -1
+		for i := team + 1; i < numTeams && other == -1; i++ {
+			if need[i] > 0 && !paired[orderedPair(team, i)] {
+				other = i
+			}
+		}
+		if other != -1 {
+			addPair(team, other)
+			continue
+		}
+
+		if !reroute(paired, fixed, need, team) {
+			return nil, fmt.Errorf("%w: team %d can't be given %d more repeat opponents; loosen the rules",
+				ErrUnsatisfiable, entries[team].TeamID, need[team])
+		}
+	}
+
+	// Matchups from the rules come first, in rule order, then the rest in
+	// ladder order
+	pairs := ruled
+	for a := 0; a < numTeams; a++ {
+		for b := a + 1; b < numTeams; b++ {
+			if paired[[2]int{a, b}] && !fixed[[2]int{a, b}] {
+				pairs = append(pairs, [2]int{a, b})
+			}
+		}
+	}
+
+	matrix := &OpponentMatrix{
+		Season:          season,
+		RepeatOpponents: repeatOpponents,
+		Matchups:        make([]draw.Matchup, len(pairs)),
+		Opponents:       make(map[int][]int),
+		FromRules:       len(ruled),
+	}
+	for i, pair := range pairs {
+		matrix.Matchups[i] = draw.Matchup{TeamA: entries[pair[0]].TeamID, TeamB: entries[pair[1]].TeamID}
+	}
+	for a, entry := range entries {
+		matrix.Opponents[entry.TeamID] = []int{}
+		for b, opponent := range entries {
+			if paired[orderedPair(a, b)] {
+				matrix.Opponents[entry.TeamID] = append(matrix.Opponents[entry.TeamID], opponent.TeamID)
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+// reroute gives a team short of repeats an extra matchup by breaking up a
+// filled matchup x-y, pairing x with the team and y with another short team,
+// or with the team again when it needs two more. Matchups fixed by rules are
+// never broken up. The closest teams on the ladder are preferred; it returns
+// false when no matchup can be broken up.
+func reroute(paired, fixed map[[2]int]bool, need []int, team int) bool {
+	var partners []int
+	for i := range need {
+		if need[i] > 0 && (i != team || need[i] >= 2) {
+			partners = append(partners, i)
+		}
+	}
+
+	best, bestCost := [3]int{-1}, 0
+	for edge := range paired {
+		if fixed[edge] {
+			continue
+		}
+		for _, partner := range partners {
+			for _, ends := range [][2]int{{edge[0], edge[1]}, {edge[1], edge[0]}} {
+				x, y := ends[0], ends[1]
+				if x == team || y == team || x == partner || y == partner {
+					continue
+				}
+				if paired[orderedPair(team, x)] || paired[orderedPair(partner, y)] {
+					continue
+				}
+				cost := distance(team, x) + distance(partner, y)
+				// Ties are broken by ladder order so the result doesn't
+				// depend on map iteration
+				if best[0] == -1 || cost < bestCost || (cost == bestCost && lessTriple([3]int{x, y, partner}, best)) {
+					best, bestCost = [3]int{x, y, partner}, cost
+				}
+			}
+		}
+	}
+	if best[0] == -1 {
+		return false
+	}
+
+	x, y, partner := best[0], best[1], best[2]
+	delete(paired, orderedPair(x, y))
+	paired[orderedPair(team, x)] = true
+	paired[orderedPair(partner, y)] = true
+	need[team]--
+	need[partner]--
+	return true
+}
+
+// lessTriple orders triples lexicographically
+func lessTriple(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// orderedPair keys a pair of ladder indexes with the lower index first
+func orderedPair(a, b int) [2]int {
+	if a > b {
+		return [2]int{b, a}
+	}
+	return [2]int{a, b}
+}
+
+// distance is how far apart two ladder indexes are
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,116 @@
+package ladder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// createLadder returns a ladder where team i finished in position i
+func createLadder(numTeams int) []*models.LadderEntry {
+	entries := make([]*models.LadderEntry, numTeams)
+	for i := range entries {
+		entries[i] = &models.LadderEntry{Season: 2024, TeamID: i + 1, Position: i + 1}
+	}
+	return entries
+}
+
+func TestDeriveRepeats_NRLSeason(t *testing.T) {
+	entries := createLadder(17)
+	rules := []RepeatRule{
+		{Positions: PositionRange{From: 1, To: 6}},
+		{Positions: PositionRange{From: 7, To: 12}},
+		{Positions: PositionRange{From: 13, To: 17}},
+	}
+
+	matrix, err := DeriveRepeats(2024, entries, rules, 8)
+	if err != nil {
+		t.Fatalf("DeriveRepeats() error = %v", err)
+	}
+
+	// 15 + 15 + 10 pool matchups, then 17 * 8 / 2 in total
+	if matrix.FromRules != 40 {
+		t.Errorf("matchups from rules = %d, want 40", matrix.FromRules)
+	}
+	if len(matrix.Matchups) != 68 {
+		t.Errorf("matchups = %d, want 68", len(matrix.Matchups))
+	}
+
+	seen := make(map[[2]int]bool)
+	for _, matchup := range matrix.Matchups {
+		key := orderedPair(matchup.TeamA, matchup.TeamB)
+		if seen[key] {
+			t.Errorf("matchup %d v %d appears twice", matchup.TeamA, matchup.TeamB)
+		}
+		seen[key] = true
+	}
+	for _, entry := range entries {
+		if len(matrix.Opponents[entry.TeamID]) != 8 {
+			t.Errorf("team %d has %d repeat opponents, want 8", entry.TeamID, len(matrix.Opponents[entry.TeamID]))
+		}
+	}
+	for team := 2; team <= 6; team++ {
+		if !seen[orderedPair(1, team)] {
+			t.Errorf("minor premiers should repeat against team %d", team)
+		}
+	}
+
+	template := matrix.FixtureTemplate()
+	if template.RepeatOpponents != 8 || len(template.RepeatMatchups) != 68 {
+		t.Errorf("template = %d repeats, %d matchups", template.RepeatOpponents, len(template.RepeatMatchups))
+	}
+}
+
+func TestDeriveRepeats_Against(t *testing.T) {
+	// The minor premiers play the bottom 2 twice
+	rules := []RepeatRule{{
+		Positions: PositionRange{From: 1, To: 1},
+		Against:   &PositionRange{From: 5, To: 6},
+	}}
+
+	matrix, err := DeriveRepeats(2024, createLadder(6), rules, 2)
+	if err != nil {
+		t.Fatalf("DeriveRepeats() error = %v", err)
+	}
+
+	want := map[int][]int{1: {5, 6}, 2: {3, 4}, 3: {2, 4}, 4: {2, 3}, 5: {1, 6}, 6: {1, 5}}
+	for team, opponents := range want {
+		got := matrix.Opponents[team]
+		if len(got) != len(opponents) || got[0] != opponents[0] || got[1] != opponents[1] {
+			t.Errorf("team %d repeat opponents = %v, want %v", team, got, opponents)
+		}
+	}
+	if matrix.FromRules != 2 {
+		t.Errorf("matchups from rules = %d, want 2", matrix.FromRules)
+	}
+}
+
+func TestDeriveRepeats_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []RepeatRule
+		repeats int
+		wantErr error
+	}{
+		{"rule off the ladder", []RepeatRule{{Positions: PositionRange{From: 5, To: 9}}}, 2, ErrInvalidRule},
+		{"backwards range", []RepeatRule{{Positions: PositionRange{From: 4, To: 2}}}, 2, ErrInvalidRule},
+		{"too many repeats", nil, 6, ErrInvalidRule},
+		{"rule exceeds repeats", []RepeatRule{{Positions: PositionRange{From: 1, To: 4}}}, 2, ErrUnsatisfiable},
+		{"rules strand teams", []RepeatRule{{Positions: PositionRange{From: 1, To: 2}, Against: &PositionRange{From: 5, To: 6}}}, 2, ErrUnsatisfiable},
+		{"odd total", nil, 1, ErrUnsatisfiable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := createLadder(6)
+			if tt.name == "odd total" {
+				entries = createLadder(5)
+			}
+			_, err := DeriveRepeats(2024, entries, tt.rules, tt.repeats)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeriveRepeats() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package ladder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// Errors returned by the ladder service
+var (
+	ErrInvalidLadder  = errors.New("invalid ladder")
+	ErrLadderNotFound = errors.New("ladder not found")
+)
+
+// Service stores final season ladders and derives repeat matchups from them
+type Service struct {
+	repository storage.Repositories
+}
+
+// NewService creates a new ladder service
+func NewService(repository storage.Repositories) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// ImportLadder replaces a season's final ladder. Positions must run from 1 to
+// the number of teams, each team appearing once.
+func (s *Service) ImportLadder(ctx context.Context, season int, entries []*models.LadderEntry) ([]*models.LadderEntry, error) {
+	if len(entries) < 2 {
+		return nil, fmt.Errorf("%w: a ladder needs at least 2 teams", ErrInvalidLadder)
+	}
+
+	teams := make(map[int]bool)
+	positions := make(map[int]bool)
+	for _, entry := range entries {
+		entry.Season = season
+		if err := entry.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidLadder, err)
+		}
+		if entry.Position > len(entries) {
+			return nil, fmt.Errorf("%w: position %d is below the last of %d teams", ErrInvalidLadder, entry.Position, len(entries))
+		}
+		if teams[entry.TeamID] {
+			return nil, fmt.Errorf("%w: team %d is on the ladder twice", ErrInvalidLadder, entry.TeamID)
+		}
+		if positions[entry.Position] {
+			return nil, fmt.Errorf("%w: position %d is taken twice", ErrInvalidLadder, entry.Position)
+		}
+		teams[entry.TeamID] = true
+		positions[entry.Position] = true
+	}
+
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, entry := range entries {
+		if _, err := tx.Teams().Get(ctx, entry.TeamID); err != nil {
+			return nil, fmt.Errorf("%w: team %d not found", ErrInvalidLadder, entry.TeamID)
+		}
+	}
+
+	if err := tx.Ladders().DeleteSeason(ctx, season); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if err := tx.Ladders().Create(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetLadder(ctx, season)
+}
+
+// GetLadder returns a season's final ladder, top of the ladder first
+func (s *Service) GetLadder(ctx context.Context, season int) ([]*models.LadderEntry, error) {
+	entries, err := s.repository.Ladders().ListBySeason(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w for season %d", ErrLadderNotFound, season)
+	}
+	return entries, nil
+}
+
+// DeriveRepeats builds the coming season's opponent matrix from a season's
+// final ladder
+func (s *Service) DeriveRepeats(ctx context.Context, season int, rules []RepeatRule, repeatOpponents int) (*OpponentMatrix, error) {
+	entries, err := s.GetLadder(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+	return DeriveRepeats(season, entries, rules, repeatOpponents)
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ApprovalRole identifies the stakeholder group signing off a draw
+type ApprovalRole string
+
+const (
+	ApprovalRoleBroadcast  ApprovalRole = "broadcast"
+	ApprovalRoleClubs      ApprovalRole = "clubs"
+	ApprovalRoleOperations ApprovalRole = "operations"
+)
+
+// DefaultApprovalRoles returns the stakeholders required to approve a draw by default
+func DefaultApprovalRoles() []ApprovalRole {
+	return []ApprovalRole{ApprovalRoleBroadcast, ApprovalRoleClubs, ApprovalRoleOperations}
+}
+
+// IsValid returns true if the role is a known stakeholder group
+func (r ApprovalRole) IsValid() bool {
+	switch r {
+	case ApprovalRoleBroadcast, ApprovalRoleClubs, ApprovalRoleOperations:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApprovalStatus represents the state of a single stakeholder sign-off
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending    ApprovalStatus = "pending"
+	ApprovalStatusApproved   ApprovalStatus = "approved"
+	ApprovalStatusRejected   ApprovalStatus = "rejected"
+	ApprovalStatusSuperseded ApprovalStatus = "superseded"
+)
+
+// Approval records one stakeholder's sign-off of a specific draw version
+type Approval struct {
+	ID          int            `json:"id"`
+	DrawID      int            `json:"draw_id"`
+	Role        ApprovalRole   `json:"role"`
+	Status      ApprovalStatus `json:"status"`
+	DrawVersion string         `json:"draw_version"`
+	RequestedBy string         `json:"requested_by,omitempty"`
+	Approver    string         `json:"approver,omitempty"`
+	Comment     string         `json:"comment,omitempty"`
+	DecidedAt   *time.Time     `json:"decided_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// Validate ensures the approval has valid data
+func (a *Approval) Validate() error {
+	if a.DrawID <= 0 {
+		return errors.New("approval must belong to a draw")
+	}
+	if !a.Role.IsValid() {
+		return errors.New("invalid approval role")
+	}
+	if a.DrawVersion == "" {
+		return errors.New("approval must reference a draw version")
+	}
+	switch a.Status {
+	case ApprovalStatusPending, ApprovalStatusApproved, ApprovalStatusRejected, ApprovalStatusSuperseded:
+	default:
+		return errors.New("invalid approval status")
+	}
+	if (a.Status == ApprovalStatusApproved || a.Status == ApprovalStatusRejected) && a.Approver == "" {
+		return errors.New("decided approvals must record the approver")
+	}
+	return nil
+}
+
+// IsCurrent returns true if the approval has not been superseded by a newer request
+func (a *Approval) IsCurrent() bool {
+	return a.Status != ApprovalStatusSuperseded
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestApproval_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		approval Approval
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name: "valid pending approval",
+			approval: Approval{
+				DrawID:      1,
+				Role:        ApprovalRoleBroadcast,
+				Status:      ApprovalStatusPending,
+				DrawVersion: "abc123",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid approved approval",
+			approval: Approval{
+				DrawID:      1,
+				Role:        ApprovalRoleClubs,
+				Status:      ApprovalStatusApproved,
+				DrawVersion: "abc123",
+				Approver:    "club-liaison",
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing draw",
+			approval: Approval{
+				Role:        ApprovalRoleBroadcast,
+				Status:      ApprovalStatusPending,
+				DrawVersion: "abc123",
+			},
+			wantErr: true,
+			errMsg:  "approval must belong to a draw",
+		},
+		{
+			name: "unknown role",
+			approval: Approval{
+				DrawID:      1,
+				Role:        "sponsors",
+				Status:      ApprovalStatusPending,
+				DrawVersion: "abc123",
+			},
+			wantErr: true,
+			errMsg:  "invalid approval role",
+		},
+		{
+			name: "missing version",
+			approval: Approval{
+				DrawID: 1,
+				Role:   ApprovalRoleOperations,
+				Status: ApprovalStatusPending,
+			},
+			wantErr: true,
+			errMsg:  "approval must reference a draw version",
+		},
+		{
+			name: "unknown status",
+			approval: Approval{
+				DrawID:      1,
+				Role:        ApprovalRoleOperations,
+				Status:      "maybe",
+				DrawVersion: "abc123",
+			},
+			wantErr: true,
+			errMsg:  "invalid approval status",
+		},
+		{
+			name: "decision without approver",
+			approval: Approval{
+				DrawID:      1,
+				Role:        ApprovalRoleOperations,
+				Status:      ApprovalStatusRejected,
+				DrawVersion: "abc123",
+			},
+			wantErr: true,
+			errMsg:  "decided approvals must record the approver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.approval.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && err.Error() != tt.errMsg {
+				t.Errorf("Validate() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// competitionCode matches short uppercase codes such as NRL, NRLW and RG
+var competitionCode = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+
+// Competition is a grade played in the same season, such as the NRL, the
+// NRLW or reserve grade. Teams and draws belong to one, or to none when
+// created before competitions existed.
+type Competition struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+	// ConstraintConfig is used to generate the competition's draws when they
+	// have no configuration of their own
+	ConstraintConfig json.RawMessage `json:"constraint_config,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// Validate ensures the competition has valid data
+func (c *Competition) Validate() error {
+	if c.Name == "" {
+		return errors.New("competition name cannot be empty")
+	}
+	if !competitionCode.MatchString(c.Code) {
+		return errors.New("competition code must be 1 to 10 uppercase letters or digits")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestCompetition_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		competition Competition
+		wantErr     bool
+	}{
+		{"valid competition", Competition{Name: "NRL Women's Premiership", Code: "NRLW"}, false},
+		{"empty name", Competition{Code: "NRLW"}, true},
+		{"empty code", Competition{Name: "Reserve Grade"}, true},
+		{"lowercase code", Competition{Name: "Reserve Grade", Code: "rg"}, true},
+		{"long code", Competition{Name: "Reserve Grade", Code: "RESERVEGRADE"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.competition.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTeam_PlaysIn(t *testing.T) {
+	nrl, nrlw := 1, 2
+	unscoped := &Team{ID: 1}
+	team := &Team{ID: 2, CompetitionID: &nrlw}
+
+	if !unscoped.PlaysIn(nil) || !team.PlaysIn(nil) {
+		t.Error("draws without a competition should take any team")
+	}
+	if !team.PlaysIn(&nrlw) {
+		t.Error("team should play in its own competition")
+	}
+	if team.PlaysIn(&nrl) || unscoped.PlaysIn(&nrl) {
+		t.Error("teams should only play in their own competition")
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ConstraintTemplate is a named constraint configuration draws can be
+// created from, such as "NRL default" or "pre-season trial". Changing its
+// configuration bumps its version, and every version is kept so draws can
+// still be created from an earlier one.
+type ConstraintTemplate struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Version     int             `json:"version"`
+	Config      json.RawMessage `json:"config"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// ConstraintTemplateVersion is a template's configuration as it was at one
+// version
+type ConstraintTemplateVersion struct {
+	TemplateID int             `json:"template_id"`
+	Version    int             `json:"version"`
+	Config     json.RawMessage `json:"config"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Validate ensures the template has valid data
+func (ct *ConstraintTemplate) Validate() error {
+	if ct.Name == "" {
+		return errors.New("template name cannot be empty")
+	}
+	if len(ct.Name) > 100 {
+		return errors.New("template name must be at most 100 characters")
+	}
+	if len(ct.Config) == 0 {
+		return errors.New("template must have a constraint configuration")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestConstraintTemplate_Validate(t *testing.T) {
+	config := json.RawMessage(`{"hard":[{"type":"bye_constraint","params":{}}]}`)
+
+	tests := []struct {
+		name     string
+		template ConstraintTemplate
+		wantErr  bool
+	}{
+		{"valid template", ConstraintTemplate{Name: "NRL default", Config: config}, false},
+		{"empty name", ConstraintTemplate{Config: config}, true},
+		{"name too long", ConstraintTemplate{Name: strings.Repeat("a", 101), Config: config}, true},
+		{"no configuration", ConstraintTemplate{Name: "NRLW"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
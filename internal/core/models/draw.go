@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"sort"
 	"time"
 )
 
@@ -23,11 +24,23 @@ type Draw struct {
 	Rounds           int             `json:"rounds"`
 	Status           DrawStatus      `json:"status"`
 	ConstraintConfig json.RawMessage `json:"constraint_config,omitempty"`
+	PublishedAt      *time.Time      `json:"published_at,omitempty"`
+	PublishedVersion string          `json:"published_version,omitempty"`
+	// Version counts the draw's updates. An update must be made against the
+	// current version, so it can't overwrite a change it hasn't seen.
+	Version          int             `json:"version"`
 	CreatedAt        time.Time       `json:"created_at"`
 	UpdatedAt        time.Time       `json:"updated_at"`
 
+	// CompetitionID is the competition the draw is for. Only its teams are
+	// drawn; draws without one draw every team.
+	CompetitionID *int `json:"competition_id,omitempty"`
+
 	// Relations
 	Matches []*Match `json:"matches,omitempty"`
+	// Byes are the stored byes, one per team sitting out a round. They're
+	// kept apart from Matches, which only holds fixtures.
+	Byes []*Match `json:"byes,omitempty"`
 }
 
 // Validate ensures the draw has valid data
@@ -56,6 +69,11 @@ func (d *Draw) isValidStatus() bool {
 	}
 }
 
+// IsPublished returns true once the draw has been signed off and published
+func (d *Draw) IsPublished() bool {
+	return d.PublishedAt != nil
+}
+
 // GetMatchesByRound returns all matches for a specific round
 func (d *Draw) GetMatchesByRound(round int) []*Match {
 	var matches []*Match
@@ -71,14 +89,48 @@ func (d *Draw) GetMatchesByRound(round int) []*Match {
 func (d *Draw) GetMatchesByTeam(teamID int) []*Match {
 	var matches []*Match
 	for _, m := range d.Matches {
-		if (m.HomeTeamID != nil && *m.HomeTeamID == teamID) ||
-			(m.AwayTeamID != nil && *m.AwayTeamID == teamID) {
+		if m.HasTeam(teamID) {
 			matches = append(matches, m)
 		}
 	}
 	return matches
 }
 
+// ByesFromMatches returns a bye for every team in each round it has no
+// match, in round then team order. The draw's teams are those with matches.
+func (d *Draw) ByesFromMatches() []*Match {
+	teams := make(map[int]bool)
+	playing := make(map[int]map[int]bool)
+	for _, m := range d.Matches {
+		if m.IsBye() {
+			continue
+		}
+		if playing[m.Round] == nil {
+			playing[m.Round] = make(map[int]bool)
+		}
+		for _, teamID := range []int{*m.HomeTeamID, *m.AwayTeamID} {
+			teams[teamID] = true
+			playing[m.Round][teamID] = true
+		}
+	}
+
+	teamIDs := make([]int, 0, len(teams))
+	for teamID := range teams {
+		teamIDs = append(teamIDs, teamID)
+	}
+	sort.Ints(teamIDs)
+
+	var byes []*Match
+	for round := 1; round <= d.Rounds; round++ {
+		for _, teamID := range teamIDs {
+			if !playing[round][teamID] {
+				byes = append(byes, NewBye(d.ID, round, teamID))
+			}
+		}
+	}
+	return byes
+}
+
 // IsComplete returns true if all matches have been scheduled
 func (d *Draw) IsComplete() bool {
 	if len(d.Matches) == 0 {
@@ -211,6 +211,7 @@ func TestDraw_GetMatchesByTeam(t *testing.T) {
 			{ID: 4, Round: 2, HomeTeamID: intPtr(2), AwayTeamID: intPtr(4)},
 			{ID: 5, Round: 3, HomeTeamID: intPtr(4), AwayTeamID: intPtr(1)},
 			{ID: 6, Round: 4}, // bye
+			{ID: 7, Round: 4, HomeTeamID: intPtr(1)}, // team 1's bye
 		},
 	}
 
@@ -267,6 +268,66 @@ func TestDraw_GetMatchesByTeam(t *testing.T) {
 	}
 }
 
+func TestDraw_ByesFromMatches(t *testing.T) {
+	draw := Draw{
+		ID:     1,
+		Rounds: 3,
+		Matches: []*Match{
+			{Round: 1, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2)},
+			{Round: 2, HomeTeamID: intPtr(3), AwayTeamID: intPtr(1)},
+			{Round: 3, HomeTeamID: intPtr(2), AwayTeamID: intPtr(3)},
+			NewBye(1, 3, 1),
+		},
+	}
+
+	byes := draw.ByesFromMatches()
+	want := [][2]int{{1, 3}, {2, 2}, {3, 1}}
+	if len(byes) != len(want) {
+		t.Fatalf("ByesFromMatches() returned %d byes, want %d", len(byes), len(want))
+	}
+	for i, bye := range byes {
+		if bye.DrawID != 1 || bye.Round != want[i][0] || !bye.IsByeFor(want[i][1]) {
+			t.Errorf("bye[%d] = round %d team %v, want round %d team %d", i, bye.Round, bye.HomeTeamID, want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestDraw_RoundProfiles(t *testing.T) {
+	team := func(id int) *int { return &id }
+	draw := &Draw{
+		Rounds: 3,
+		Matches: []*Match{
+			{Round: 1, HomeTeamID: team(1), AwayTeamID: team(2), IsPrimeTime: true},
+			{Round: 1, HomeTeamID: team(3), AwayTeamID: team(4)},
+			{Round: 2, HomeTeamID: team(1), AwayTeamID: team(3), IsPrimeTime: true},
+			{Round: 2, HomeTeamID: team(2)},
+		},
+	}
+
+	profiles := draw.RoundProfiles()
+	if len(profiles) != 3 {
+		t.Fatalf("RoundProfiles() returned %d rounds, want 3", len(profiles))
+	}
+
+	want := map[int]RoundProfile{
+		1: {Round: 1, Matches: 2, Byes: 0, Timeslots: 2, PrimeTimeSlots: 1},
+		2: {Round: 2, Matches: 1, Byes: 2, Timeslots: 1, PrimeTimeSlots: 1},
+		3: {Round: 3, Matches: 0, Byes: 4},
+	}
+	for round, profile := range want {
+		if profiles[round] != profile {
+			t.Errorf("RoundProfiles()[%d] = %+v, want %+v", round, profiles[round], profile)
+		}
+	}
+
+	if share := profiles[2].PrimeTimeShare(); share != 1.0 {
+		t.Errorf("PrimeTimeShare() = %v, want 1.0", share)
+	}
+	if share := profiles[3].PrimeTimeShare(); share != 0 {
+		t.Errorf("PrimeTimeShare() of an empty round = %v, want 0", share)
+	}
+}
+
 func TestDraw_IsComplete(t *testing.T) {
 	now := time.Now()
 	tomorrow := now.Add(24 * time.Hour)
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// JobArchive is a finished optimization job moved out of the in-memory job
+// store. Payload holds the gzip-compressed JSON of the job and is dropped
+// once the retention policy no longer needs it.
+type JobArchive struct {
+	ID                 int        `json:"id"`
+	JobID              string     `json:"job_id"`
+	DrawID             int        `json:"draw_id"`
+	Status             string     `json:"status"`
+	InitialScore       float64    `json:"initial_score"`
+	FinalScore         float64    `json:"final_score"`
+	Payload            []byte     `json:"-"`
+	PayloadSize        int        `json:"payload_size"` // uncompressed bytes
+	StoredSize         int        `json:"stored_size"`  // compressed bytes, 0 once discarded
+	IsBest             bool       `json:"is_best"`
+	StartedAt          time.Time  `json:"started_at"`
+	CompletedAt        time.Time  `json:"completed_at"`
+	PayloadDiscardedAt *time.Time `json:"payload_discarded_at,omitempty"`
+	ArchivedAt         time.Time  `json:"archived_at"`
+}
+
+// Validate ensures the archive has valid data
+func (a *JobArchive) Validate() error {
+	if a.JobID == "" {
+		return errors.New("job archive must reference a job")
+	}
+	if a.DrawID <= 0 {
+		return errors.New("job archive must belong to a draw")
+	}
+	if a.Status == "" {
+		return errors.New("job archive must record the job status")
+	}
+	if len(a.Payload) == 0 {
+		return errors.New("job archive must include a payload")
+	}
+	if a.CompletedAt.IsZero() {
+		return errors.New("job archive must record when the job finished")
+	}
+	return nil
+}
+
+// HasPayload returns true if the archived job can still be restored
+func (a *JobArchive) HasPayload() bool {
+	return a.PayloadDiscardedAt == nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// LadderEntry is a team's final position on a season's ladder
+type LadderEntry struct {
+	ID        int       `json:"id"`
+	Season    int       `json:"season"`
+	TeamID    int       `json:"team_id"`
+	Position  int       `json:"position"`
+	Points    int       `json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate ensures the ladder entry has valid data
+func (e *LadderEntry) Validate() error {
+	if e.Season <= 0 {
+		return errors.New("ladder entry must belong to a season")
+	}
+	if e.TeamID <= 0 {
+		return errors.New("ladder entry must reference a team")
+	}
+	if e.Position <= 0 {
+		return errors.New("ladder position must be positive")
+	}
+	return nil
+}
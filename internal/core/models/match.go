@@ -16,6 +16,7 @@ type Match struct {
 	MatchDate   *time.Time `json:"match_date"`
 	MatchTime   *time.Time `json:"match_time"`
 	IsPrimeTime bool       `json:"is_prime_time"`
+	Locked      bool       `json:"locked"` // pinned, e.g. already announced to broadcasters
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 
@@ -34,9 +35,11 @@ func (m *Match) Validate() error {
 		return errors.New("match round must be positive")
 	}
 
-	// Check if it's a bye (both teams nil) or a regular match
-	if m.HomeTeamID == nil && m.AwayTeamID == nil {
-		// This is a bye - valid
+	// Byes only name the team sitting the round out
+	if m.IsBye() {
+		if m.VenueID != nil {
+			return errors.New("bye cannot have a venue")
+		}
 		return nil
 	}
 
@@ -58,9 +61,25 @@ func (m *Match) Validate() error {
 	return nil
 }
 
-// IsBye returns true if this match represents a bye
+// NewBye creates the bye of a team sitting a round out
+func NewBye(drawID, round, teamID int) *Match {
+	return &Match{
+		DrawID:     drawID,
+		Round:      round,
+		HomeTeamID: &teamID,
+	}
+}
+
+// IsBye returns true if this match represents a bye. A bye records the team
+// on the bye as its home team and has no away team; byes stored before teams
+// were recorded have neither.
 func (m *Match) IsBye() bool {
-	return m.HomeTeamID == nil && m.AwayTeamID == nil
+	return m.AwayTeamID == nil
+}
+
+// IsByeFor returns true if this match is the given team's bye
+func (m *Match) IsByeFor(teamID int) bool {
+	return m.IsBye() && m.HomeTeamID != nil && *m.HomeTeamID == teamID
 }
 
 // HasTeam returns true if the match involves the specified team
@@ -72,6 +91,11 @@ func (m *Match) HasTeam(teamID int) bool {
 		(m.AwayTeamID != nil && *m.AwayTeamID == teamID)
 }
 
+// IsMovable returns true if optimization may change the match's round, venue or home team
+func (m *Match) IsMovable() bool {
+	return !m.IsBye() && !m.Locked
+}
+
 // IsScheduled returns true if the match has a date assigned
 func (m *Match) IsScheduled() bool {
 	return m.MatchDate != nil
@@ -99,4 +123,4 @@ func (m *Match) IsHomeGame(teamID int) (bool, error) {
 		return false, errors.New("team not in this match")
 	}
 	return m.HomeTeamID != nil && *m.HomeTeamID == teamID, nil
-}
\ No newline at end of file
+}
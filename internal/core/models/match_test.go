@@ -59,7 +59,18 @@ func TestMatch_Validate(t *testing.T) {
 			errMsg:  "match round must be positive",
 		},
 		{
-			name: "only home team",
+			name: "valid bye with its team",
+			match: Match{
+				DrawID:     1,
+				Round:      1,
+				HomeTeamID: intPtr(1),
+				AwayTeamID: nil,
+				VenueID:    nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "bye with venue",
 			match: Match{
 				DrawID:     1,
 				Round:      1,
@@ -68,7 +79,7 @@ func TestMatch_Validate(t *testing.T) {
 				VenueID:    intPtr(1),
 			},
 			wantErr: true,
-			errMsg:  "match must have both home and away teams or be a bye",
+			errMsg:  "bye cannot have a venue",
 		},
 		{
 			name: "only away team",
@@ -152,9 +163,9 @@ func TestMatch_IsBye(t *testing.T) {
 			want:  false,
 		},
 		{
-			name:  "invalid match with only home team",
+			name:  "bye with its team",
 			match: Match{HomeTeamID: intPtr(1), AwayTeamID: nil},
-			want:  false,
+			want:  true,
 		},
 		{
 			name:  "invalid match with only away team",
@@ -203,6 +214,12 @@ func TestMatch_HasTeam(t *testing.T) {
 			teamID: 1,
 			want:   false,
 		},
+		{
+			name:   "team's own bye",
+			match:  *NewBye(1, 1, 1),
+			teamID: 1,
+			want:   false,
+		},
 	}
 
 	for _, tt := range tests {
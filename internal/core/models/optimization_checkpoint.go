@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// OptimizationCheckpointRecord is the persisted state of a running
+// optimization. Config and State hold JSON snapshots so the job can be resumed
+// from the checkpoint after a crash or restart.
+type OptimizationCheckpointRecord struct {
+	ID        int       `json:"id"`
+	JobID     string    `json:"job_id"`
+	DrawID    int       `json:"draw_id"`
+	Iteration int       `json:"iteration"`
+	Config    []byte    `json:"-"`
+	State     []byte    `json:"-"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate ensures the checkpoint record has valid data
+func (r *OptimizationCheckpointRecord) Validate() error {
+	if r.JobID == "" {
+		return errors.New("checkpoint must have a job ID")
+	}
+	if r.DrawID <= 0 {
+		return errors.New("checkpoint must belong to a draw")
+	}
+	if r.Iteration < 0 {
+		return errors.New("checkpoint iteration cannot be negative")
+	}
+	if len(r.Config) == 0 || len(r.State) == 0 {
+		return errors.New("checkpoint must record the optimizer config and state")
+	}
+	return nil
+}
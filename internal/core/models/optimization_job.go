@@ -0,0 +1,38 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// OptimizationJobRecord is the persisted state of an optimization job. Progress
+// and Result hold JSON snapshots so jobs can be rehydrated after a restart.
+type OptimizationJobRecord struct {
+	ID          int        `json:"id"`
+	JobID       string     `json:"job_id"`
+	DrawID      int        `json:"draw_id"`
+	Status      string     `json:"status"`
+	Progress    []byte     `json:"-"`
+	Result      []byte     `json:"-"` // nil until the job completes
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Validate ensures the job record has valid data
+func (r *OptimizationJobRecord) Validate() error {
+	if r.JobID == "" {
+		return errors.New("job record must have a job ID")
+	}
+	if r.DrawID <= 0 {
+		return errors.New("job record must belong to a draw")
+	}
+	if r.Status == "" {
+		return errors.New("job record must record the job status")
+	}
+	if r.StartedAt.IsZero() {
+		return errors.New("job record must record when the job started")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package models
+
+// RoundProfile describes the shape of one round. Rounds with byes hold fewer
+// matches than a full round, so they need fewer timeslots and a larger share
+// of those timeslots can be prime time.
+type RoundProfile struct {
+	Round          int `json:"round"`
+	Matches        int `json:"matches"`
+	Byes           int `json:"byes"`
+	Timeslots      int `json:"timeslots"`
+	PrimeTimeSlots int `json:"prime_time_slots"`
+}
+
+// PrimeTimeShare returns the fraction of the round's matches that are played
+// in prime time
+func (rp RoundProfile) PrimeTimeShare() float64 {
+	if rp.Matches == 0 {
+		return 0
+	}
+	return float64(rp.PrimeTimeSlots) / float64(rp.Matches)
+}
+
+// RoundProfiles describes every round of the draw from its matches. Each
+// match takes one timeslot, so the prime-time slots are the matches marked
+// prime time, and a team is on a bye in any round it doesn't play, whether or
+// not the draw lists the bye.
+func (d *Draw) RoundProfiles() map[int]RoundProfile {
+	teams := make(map[int]bool)
+	playing := make(map[int]map[int]bool)
+	profiles := make(map[int]RoundProfile, d.Rounds)
+	for round := 1; round <= d.Rounds; round++ {
+		profiles[round] = RoundProfile{Round: round}
+		playing[round] = make(map[int]bool)
+	}
+
+	for _, match := range d.Matches {
+		for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+			if teamID != nil {
+				teams[*teamID] = true
+			}
+		}
+
+		profile := profiles[match.Round]
+		profile.Round = match.Round
+		if match.HomeTeamID != nil && match.AwayTeamID != nil {
+			if playing[match.Round] == nil {
+				playing[match.Round] = make(map[int]bool)
+			}
+			playing[match.Round][*match.HomeTeamID] = true
+			playing[match.Round][*match.AwayTeamID] = true
+
+			profile.Matches++
+			profile.Timeslots++
+			if match.IsPrimeTime {
+				profile.PrimeTimeSlots++
+			}
+		}
+		profiles[match.Round] = profile
+	}
+
+	for round, profile := range profiles {
+		profile.Byes = len(teams) - len(playing[round])
+		profiles[round] = profile
+	}
+	return profiles
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RoundKind describes what sort of weekend a calendar round is
+type RoundKind string
+
+const (
+	// RoundKindRegular is an ordinary premiership weekend
+	RoundKindRegular RoundKind = "regular"
+	// RoundKindSplit is one weekend of a round played over two or more
+	// weekends; the round has an entry for each
+	RoundKindSplit RoundKind = "split"
+	// RoundKindOrigin is a weekend in the State of Origin period
+	RoundKindOrigin RoundKind = "origin"
+	// RoundKindFinals is a finals weekend, after the premiership rounds
+	RoundKindFinals RoundKind = "finals"
+)
+
+// CalendarRound is the date range a round is played over, from the first to
+// the last day inclusive
+type CalendarRound struct {
+	Round     int       `json:"round"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Kind      RoundKind `json:"kind"`
+	Label     string    `json:"label,omitempty"`
+}
+
+// Contains reports whether a date falls within the round's range
+func (cr CalendarRound) Contains(date time.Time) bool {
+	day := calendarDay(date)
+	return !day.Before(calendarDay(cr.StartDate)) && !day.After(calendarDay(cr.EndDate))
+}
+
+// Days returns each day of the round's range, in order
+func (cr CalendarRound) Days() []time.Time {
+	var days []time.Time
+	end := calendarDay(cr.EndDate)
+	for day := calendarDay(cr.StartDate); !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// SeasonCalendar maps a season's rounds to the weekends they're played on.
+// Scheduling dates rounds from it, and constraints check kickoffs against
+// it. Each competition has at most one calendar a season, and a calendar
+// without a competition applies to draws whose competition has none.
+type SeasonCalendar struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	SeasonYear int    `json:"season_year"`
+	// CompetitionID is the competition the calendar is for, or nil when it
+	// is shared
+	CompetitionID *int            `json:"competition_id,omitempty"`
+	Rounds        []CalendarRound `json:"rounds"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Validate ensures the calendar has valid data: every range is in order and
+// inside the season, no two ranges overlap, rounds run in date order, only a
+// split round has more than one range, and finals come after every other round
+func (sc *SeasonCalendar) Validate() error {
+	if sc.Name == "" {
+		return errors.New("calendar name cannot be empty")
+	}
+	if sc.SeasonYear < 2000 || sc.SeasonYear > 2100 {
+		return errors.New("calendar season year must be between 2000 and 2100")
+	}
+	if len(sc.Rounds) == 0 {
+		return errors.New("calendar must have at least one round")
+	}
+
+	ranges := make(map[int]int)
+	for _, round := range sc.Rounds {
+		if round.Round < 1 {
+			return errors.New("calendar round numbers must be at least 1")
+		}
+		switch round.Kind {
+		case RoundKindRegular, RoundKindSplit, RoundKindOrigin, RoundKindFinals:
+		default:
+			return fmt.Errorf("round %d has unknown kind %q", round.Round, round.Kind)
+		}
+		if round.StartDate.IsZero() || round.EndDate.IsZero() {
+			return fmt.Errorf("round %d needs a start and end date", round.Round)
+		}
+		if calendarDay(round.EndDate).Before(calendarDay(round.StartDate)) {
+			return fmt.Errorf("round %d ends before it starts", round.Round)
+		}
+		if round.StartDate.Year() < sc.SeasonYear || round.StartDate.Year() > sc.SeasonYear+1 {
+			return fmt.Errorf("round %d is outside the %d season", round.Round, sc.SeasonYear)
+		}
+		ranges[round.Round]++
+	}
+
+	ordered := sc.orderedRounds()
+	for i, round := range ordered {
+		if ranges[round.Round] > 1 && round.Kind != RoundKindSplit {
+			return fmt.Errorf("round %d has more than one date range but isn't a split round", round.Round)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := ordered[i-1]
+		if !calendarDay(round.StartDate).After(calendarDay(previous.EndDate)) {
+			return fmt.Errorf("round %d overlaps round %d", round.Round, previous.Round)
+		}
+		// A split round's later weekends may fall after the rounds that follow it
+		if round.Round < previous.Round && round.Kind != RoundKindSplit {
+			return fmt.Errorf("round %d is dated before round %d", round.Round, previous.Round)
+		}
+		if previous.Kind == RoundKindFinals && round.Kind != RoundKindFinals {
+			return fmt.Errorf("round %d comes after the finals", round.Round)
+		}
+	}
+
+	return nil
+}
+
+// RoundDates returns the date ranges a round is played over, earliest first
+func (sc *SeasonCalendar) RoundDates(round int) []CalendarRound {
+	var ranges []CalendarRound
+	for _, entry := range sc.orderedRounds() {
+		if entry.Round == round {
+			ranges = append(ranges, entry)
+		}
+	}
+	return ranges
+}
+
+// RoundStart returns the first day a round is played on, and false when the
+// calendar doesn't date the round
+func (sc *SeasonCalendar) RoundStart(round int) (time.Time, bool) {
+	ranges := sc.RoundDates(round)
+	if len(ranges) == 0 {
+		return time.Time{}, false
+	}
+	return calendarDay(ranges[0].StartDate), true
+}
+
+// InRound reports whether a date falls within one of the round's ranges
+func (sc *SeasonCalendar) InRound(round int, date time.Time) bool {
+	for _, entry := range sc.RoundDates(round) {
+		if entry.Contains(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// orderedRounds returns the calendar's ranges in date order
+func (sc *SeasonCalendar) orderedRounds() []CalendarRound {
+	ordered := make([]CalendarRound, len(sc.Rounds))
+	copy(ordered, sc.Rounds)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].StartDate.Before(ordered[j].StartDate)
+	})
+	return ordered
+}
+
+// calendarDay truncates a time to midnight on its day, keeping the location
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeasonCalendar_Validate(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC) }
+	weekend := func(round int, kind RoundKind, month time.Month, d int) CalendarRound {
+		return CalendarRound{Round: round, StartDate: day(month, d), EndDate: day(month, d+3), Kind: kind}
+	}
+
+	tests := []struct {
+		name    string
+		rounds  []CalendarRound
+		wantErr bool
+	}{
+		{"valid calendar", []CalendarRound{weekend(1, RoundKindRegular, 3, 6), weekend(2, RoundKindOrigin, 3, 13), weekend(3, RoundKindFinals, 9, 11)}, false},
+		{"split round around another", []CalendarRound{weekend(1, RoundKindSplit, 3, 6), weekend(2, RoundKindRegular, 3, 13), weekend(1, RoundKindSplit, 3, 20)}, false},
+		{"no rounds", nil, true},
+		{"unknown kind", []CalendarRound{weekend(1, "bye", 3, 6)}, true},
+		{"ends before it starts", []CalendarRound{{Round: 1, StartDate: day(3, 9), EndDate: day(3, 6), Kind: RoundKindRegular}}, true},
+		{"overlapping rounds", []CalendarRound{weekend(1, RoundKindRegular, 3, 6), weekend(2, RoundKindRegular, 3, 9)}, true},
+		{"rounds out of order", []CalendarRound{weekend(2, RoundKindRegular, 3, 6), weekend(1, RoundKindRegular, 3, 13)}, true},
+		{"repeated regular round", []CalendarRound{weekend(1, RoundKindRegular, 3, 6), weekend(1, RoundKindRegular, 3, 13)}, true},
+		{"round after the finals", []CalendarRound{weekend(1, RoundKindFinals, 3, 6), weekend(2, RoundKindRegular, 3, 13)}, true},
+		{"outside the season", []CalendarRound{{Round: 1, StartDate: day(3, 6).AddDate(-1, 0, 0), EndDate: day(3, 9).AddDate(-1, 0, 0), Kind: RoundKindRegular}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := SeasonCalendar{Name: "NRL 2025", SeasonYear: 2025, Rounds: tt.rounds}
+			err := calendar.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSeasonCalendar_RoundDates(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.June, d, 0, 0, 0, 0, time.UTC) }
+	calendar := &SeasonCalendar{
+		Name:       "NRL 2025",
+		SeasonYear: 2025,
+		Rounds: []CalendarRound{
+			{Round: 14, StartDate: day(19), EndDate: day(22), Kind: RoundKindSplit},
+			{Round: 13, StartDate: day(5), EndDate: day(8), Kind: RoundKindSplit},
+			{Round: 13, StartDate: day(12), EndDate: day(15), Kind: RoundKindSplit},
+		},
+	}
+
+	ranges := calendar.RoundDates(13)
+	if len(ranges) != 2 || !ranges[0].StartDate.Equal(day(5)) {
+		t.Fatalf("Expected round 13's two weekends earliest first, got %v", ranges)
+	}
+	if start, dated := calendar.RoundStart(13); !dated || !start.Equal(day(5)) {
+		t.Errorf("Expected round 13 to start on %s, got %s", day(5).Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+	if _, dated := calendar.RoundStart(15); dated {
+		t.Error("Round 15 isn't in the calendar")
+	}
+
+	kickoff := time.Date(2025, time.June, 14, 19, 35, 0, 0, time.UTC)
+	if !calendar.InRound(13, kickoff) {
+		t.Error("A kickoff on the second weekend should be in round 13")
+	}
+	if calendar.InRound(13, day(10)) || calendar.InRound(14, kickoff) {
+		t.Error("Days between and outside a round's weekends aren't in the round")
+	}
+	if days := ranges[1].Days(); len(days) != 4 || !days[3].Equal(day(15)) {
+		t.Errorf("Expected the second weekend to run Thursday to Sunday, got %v", days)
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ShareLink is an expiring, read-only link to a snapshot of a draw version
+type ShareLink struct {
+	ID          int             `json:"id"`
+	DrawID      int             `json:"draw_id"`
+	Token       string          `json:"token"`
+	DrawVersion string          `json:"draw_version"`
+	Snapshot    json.RawMessage `json:"-"`
+	Label       string          `json:"label,omitempty"`
+	CreatedBy   string          `json:"created_by,omitempty"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+	RevokedAt   *time.Time      `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Validate ensures the share link has valid data
+func (l *ShareLink) Validate() error {
+	if l.DrawID <= 0 {
+		return errors.New("share link must belong to a draw")
+	}
+	if l.Token == "" {
+		return errors.New("share link must have a token")
+	}
+	if l.DrawVersion == "" {
+		return errors.New("share link must reference a draw version")
+	}
+	if len(l.Snapshot) == 0 {
+		return errors.New("share link must include a snapshot")
+	}
+	if l.ExpiresAt.IsZero() {
+		return errors.New("share link must expire")
+	}
+	return nil
+}
+
+// IsRevoked returns true if the link has been withdrawn
+func (l *ShareLink) IsRevoked() bool {
+	return l.RevokedAt != nil
+}
+
+// IsExpired returns true if the link is past its expiry time
+func (l *ShareLink) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// IsActive returns true if the link can still be used
+func (l *ShareLink) IsActive(now time.Time) bool {
+	return !l.IsRevoked() && !l.IsExpired(now)
+}
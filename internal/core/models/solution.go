@@ -0,0 +1,41 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Solution is the best-scoring draw found for a combination of teams, rounds
+// and constraint configuration. New optimizations with the same key can
+// start from its fixtures instead of from scratch.
+type Solution struct {
+	ID  int    `json:"id"`
+	Key string `json:"key"`
+	// DrawID is the draw the solution was found for, nil once it's deleted
+	DrawID     *int    `json:"draw_id,omitempty"`
+	JobID      string  `json:"job_id"`
+	Score      float64 `json:"score"`
+	Rounds     int     `json:"rounds"`
+	MatchCount int     `json:"match_count"`
+	// Matches holds the fixtures; lists leave them out
+	Matches   []*Match  `json:"matches,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate ensures the solution has valid data
+func (s *Solution) Validate() error {
+	if s.Key == "" {
+		return errors.New("solution must have a key")
+	}
+	if s.JobID == "" {
+		return errors.New("solution must reference the job that found it")
+	}
+	if s.Rounds <= 0 {
+		return errors.New("solution must have at least one round")
+	}
+	if len(s.Matches) == 0 || len(s.Matches) != s.MatchCount {
+		return errors.New("solution must include all of its matches")
+	}
+	return nil
+}
@@ -2,9 +2,42 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"time"
 )
 
+// homeVenueShareTolerance is how far home venue shares may sum from 1
+const homeVenueShareTolerance = 0.001
+
+// HomeVenue is one of the grounds a team splits its home games across, with
+// the fraction of home games it should host
+type HomeVenue struct {
+	VenueID int     `json:"venue_id"`
+	Share   float64 `json:"share"`
+}
+
+// TeamUnavailability is a date a team can't play, such as when its ground is
+// booked for a concert or a club event clashes
+type TeamUnavailability struct {
+	ID        int       `json:"id"`
+	TeamID    int       `json:"team_id"`
+	Date      time.Time `json:"date"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate ensures the unavailability has valid data
+func (u *TeamUnavailability) Validate() error {
+	if u.TeamID <= 0 {
+		return errors.New("unavailability must belong to a team")
+	}
+	if u.Date.IsZero() {
+		return errors.New("unavailability must have a date")
+	}
+	return nil
+}
+
 // Team represents an NRL team
 type Team struct {
 	ID        int       `json:"id"`
@@ -17,6 +50,18 @@ type Team struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
+	// HomeVenues splits home games across several grounds. Teams with at most
+	// one play every home game at VenueID.
+	HomeVenues []HomeVenue `json:"home_venues,omitempty"`
+
+	// Unavailability lists the dates the team can't play. Every draw's
+	// constraints include them.
+	Unavailability []TeamUnavailability `json:"unavailability,omitempty"`
+
+	// CompetitionID is the competition the team plays in. Teams without one
+	// are only drawn in draws without a competition.
+	CompetitionID *int `json:"competition_id,omitempty"`
+
 	// Relations
 	Venue *Venue `json:"venue,omitempty"`
 }
@@ -41,9 +86,51 @@ func (t *Team) Validate() error {
 	if t.Longitude < -180 || t.Longitude > 180 {
 		return errors.New("team longitude must be between -180 and 180")
 	}
+	return ValidateHomeVenues(t.HomeVenues)
+}
+
+// ValidateHomeVenues checks that each venue is listed once with a positive
+// share and that the shares add up to 1
+func ValidateHomeVenues(venues []HomeVenue) error {
+	if len(venues) == 0 {
+		return nil
+	}
+
+	seen := make(map[int]bool)
+	total := 0.0
+	for _, venue := range venues {
+		if venue.VenueID <= 0 {
+			return errors.New("home venue must reference a venue")
+		}
+		if seen[venue.VenueID] {
+			return fmt.Errorf("venue %d is listed as a home venue twice", venue.VenueID)
+		}
+		if venue.Share <= 0 || venue.Share > 1 {
+			return fmt.Errorf("home venue %d share must be between 0 and 1", venue.VenueID)
+		}
+		seen[venue.VenueID] = true
+		total += venue.Share
+	}
+	if math.Abs(total-1) > homeVenueShareTolerance {
+		return fmt.Errorf("home venue shares must add up to 1, got %.3f", total)
+	}
 	return nil
 }
 
+// HasVenueRotation returns true if the team splits home games across venues
+func (t *Team) HasVenueRotation() bool {
+	return len(t.HomeVenues) > 1
+}
+
+// PlaysIn reports whether the team can be drawn in a competition. Draws
+// without a competition take any team; others only the competition's own.
+func (t *Team) PlaysIn(competitionID *int) bool {
+	if competitionID == nil {
+		return true
+	}
+	return t.CompetitionID != nil && *t.CompetitionID == *competitionID
+}
+
 // HasBye returns true if this team ID represents a bye
 func (t *Team) HasBye() bool {
 	return t == nil || t.ID == 0
@@ -106,6 +106,39 @@ func TestTeam_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "with home venue split",
+			team: Team{
+				Name:       "Penrith Panthers",
+				ShortName:  "PEN",
+				City:       "Penrith",
+				VenueID:    intPtr(1),
+				HomeVenues: []HomeVenue{{VenueID: 1, Share: 0.7}, {VenueID: 2, Share: 0.3}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "home venue shares not adding up",
+			team: Team{
+				Name:       "Penrith Panthers",
+				ShortName:  "PEN",
+				City:       "Penrith",
+				HomeVenues: []HomeVenue{{VenueID: 1, Share: 0.7}, {VenueID: 2, Share: 0.2}},
+			},
+			wantErr: true,
+			errMsg:  "home venue shares must add up to 1, got 0.900",
+		},
+		{
+			name: "home venue listed twice",
+			team: Team{
+				Name:       "Penrith Panthers",
+				ShortName:  "PEN",
+				City:       "Penrith",
+				HomeVenues: []HomeVenue{{VenueID: 1, Share: 0.5}, {VenueID: 1, Share: 0.5}},
+			},
+			wantErr: true,
+			errMsg:  "venue 1 is listed as a home venue twice",
+		},
 	}
 
 	for _, tt := range tests {
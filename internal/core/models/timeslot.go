@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Timeslot is a weekly kickoff window in the league's catalogue, such as
+// Friday 8pm on the free-to-air channel. Scheduling dates rounds from the
+// catalogue, and a match played in a slot is prime time when the slot is.
+type Timeslot struct {
+	ID        int          `json:"id"`
+	DayOfWeek time.Weekday `json:"day_of_week"`
+	// Kickoff is the time of day in HH:MM form
+	Kickoff     string `json:"kickoff"`
+	Channel     string `json:"channel,omitempty"`
+	IsPrimeTime bool   `json:"is_prime_time"`
+	// CompetitionID is the competition the slot is for, or nil when every
+	// competition uses it
+	CompetitionID *int      `json:"competition_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Validate ensures the timeslot has valid data
+func (t *Timeslot) Validate() error {
+	if t.DayOfWeek < time.Sunday || t.DayOfWeek > time.Saturday {
+		return errors.New("timeslot day of week must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	if _, err := t.KickoffTime(); err != nil {
+		return errors.New("timeslot kickoff must be a time in HH:MM format")
+	}
+	if len(t.Channel) > 50 {
+		return errors.New("timeslot channel cannot be longer than 50 characters")
+	}
+	return nil
+}
+
+// KickoffTime returns the kickoff as a time of day, in the same form as
+// other parsed HH:MM times
+func (t *Timeslot) KickoffTime() (time.Time, error) {
+	return time.Parse("15:04", t.Kickoff)
+}
+
+// AppliesTo reports whether a draw in the given competition is scheduled in
+// the slot. Slots without a competition apply to every draw.
+func (t *Timeslot) AppliesTo(competitionID *int) bool {
+	if t.CompetitionID == nil {
+		return true
+	}
+	return competitionID != nil && *t.CompetitionID == *competitionID
+}
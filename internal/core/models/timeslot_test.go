@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeslot_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeslot Timeslot
+		wantErr  bool
+	}{
+		{"valid timeslot", Timeslot{DayOfWeek: time.Friday, Kickoff: "20:00", Channel: "Nine", IsPrimeTime: true}, false},
+		{"sunday", Timeslot{DayOfWeek: time.Sunday, Kickoff: "14:00"}, false},
+		{"day out of range", Timeslot{DayOfWeek: 7, Kickoff: "14:00"}, true},
+		{"missing kickoff", Timeslot{DayOfWeek: time.Friday}, true},
+		{"invalid kickoff", Timeslot{DayOfWeek: time.Friday, Kickoff: "8pm"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.timeslot.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTimeslot_AppliesTo(t *testing.T) {
+	nrl, nrlw := 1, 2
+	shared := &Timeslot{DayOfWeek: time.Saturday, Kickoff: "19:35"}
+	womens := &Timeslot{DayOfWeek: time.Sunday, Kickoff: "12:00", CompetitionID: &nrlw}
+
+	if !shared.AppliesTo(nil) || !shared.AppliesTo(&nrl) {
+		t.Error("slots without a competition should apply to every draw")
+	}
+	if !womens.AppliesTo(&nrlw) {
+		t.Error("slot should apply to its own competition")
+	}
+	if womens.AppliesTo(&nrl) || womens.AppliesTo(nil) {
+		t.Error("slot should only apply to its own competition")
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// Webhook is a URL notified with signed JSON payloads when the events it
+// subscribes to happen, for systems that can't hold a WebSocket open
+type Webhook struct {
+	ID     int      `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs each payload so the receiver can verify it came from us
+	Secret      string `json:"-"`
+	Description string `json:"description,omitempty"`
+	IsActive    bool   `json:"is_active"`
+	// The outcome of the latest delivery; LastStatus is 0 when no response
+	// was received
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastStatus     int        `json:"last_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Validate ensures the webhook has valid data
+func (w *Webhook) Validate() error {
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+	if len(w.Events) == 0 {
+		return errors.New("webhook must subscribe to at least one event")
+	}
+	if w.Secret == "" {
+		return errors.New("webhook must have a signing secret")
+	}
+	if len(w.Description) > 200 {
+		return errors.New("webhook description cannot be longer than 200 characters")
+	}
+	return nil
+}
+
+// Subscribes reports whether the webhook is active and subscribed to an event
+func (w *Webhook) Subscribes(event string) bool {
+	if !w.IsActive {
+		return false
+	}
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestWebhook_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook Webhook
+		wantErr bool
+	}{
+		{"valid webhook", Webhook{URL: "https://fixtures.example.com/hooks", Events: []string{"draw.generated"}, Secret: "s3cret"}, false},
+		{"http URL", Webhook{URL: "http://localhost:8081/hooks", Events: []string{"draw.generated"}, Secret: "s3cret"}, false},
+		{"relative URL", Webhook{URL: "/hooks", Events: []string{"draw.generated"}, Secret: "s3cret"}, true},
+		{"other scheme", Webhook{URL: "ftp://fixtures.example.com/hooks", Events: []string{"draw.generated"}, Secret: "s3cret"}, true},
+		{"no events", Webhook{URL: "https://fixtures.example.com/hooks", Secret: "s3cret"}, true},
+		{"no secret", Webhook{URL: "https://fixtures.example.com/hooks", Events: []string{"draw.generated"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.webhook.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhook_Subscribes(t *testing.T) {
+	webhook := &Webhook{Events: []string{"draw.generated", "optimization.completed"}, IsActive: true}
+
+	if !webhook.Subscribes("optimization.completed") {
+		t.Error("webhook should subscribe to its events")
+	}
+	if webhook.Subscribes("constraints.validated") {
+		t.Error("webhook should not subscribe to other events")
+	}
+
+	webhook.IsActive = false
+	if webhook.Subscribes("draw.generated") {
+		t.Error("inactive webhooks should not subscribe to anything")
+	}
+}
@@ -91,6 +91,27 @@ func BenchmarkOptimizeLargeDraw(b *testing.B) {
 	}
 }
 
+// BenchmarkOptimizeDoubleRoundRobin benchmarks optimization on a 400+ match
+// draw, reporting how often team sub-scores come from the score cache
+func BenchmarkOptimizeDoubleRoundRobin(b *testing.B) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 0.8)
+	engine.AddSoftConstraint(constraints.NewTravelMinimizationConstraint(3), 0.6)
+	engine.AddSoftConstraint(constraints.NewRestPeriodConstraint(5), 0.5)
+
+	sa := NewSimulatedAnnealing(50.0, 0.98, 200, engine)
+	draw := createLargeDraw(17, 3) // 408 matches
+
+	b.ResetTimer()
+	var result *OptimizationResult
+	for i := 0; i < b.N; i++ {
+		result, _ = sa.Optimize(draw, nil)
+	}
+	if result != nil {
+		b.ReportMetric(result.ScoreCache.HitRate, "hit-rate")
+	}
+}
+
 // BenchmarkConstraintEvaluation benchmarks constraint evaluation
 func BenchmarkConstraintEvaluation(b *testing.B) {
 	engine := constraints.NewConstraintEngine()
@@ -129,7 +150,10 @@ func BenchmarkNeighborGeneration(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		sa.generateNeighbor(draw)
+		// Neighbors are made in place, so revert each one as a rejected move would be
+		if _, _, err := sa.generateNeighbor(draw); err == nil {
+			sa.journal.undo()
+		}
 	}
 }
 
@@ -0,0 +1,164 @@
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// DefaultCheckpointInterval is how many iterations run between checkpoints
+// when the optimizer doesn't set its own interval
+const DefaultCheckpointInterval = 5000
+
+var (
+	// ErrNoCheckpoint is returned when resuming a job that never saved a checkpoint
+	ErrNoCheckpoint = errors.New("optimization job has no checkpoint to resume from")
+	// ErrJobNotResumable is returned when resuming a job that is still active,
+	// has completed, or whose optimizer can't resume
+	ErrJobNotResumable = errors.New("optimization job cannot be resumed")
+)
+
+// Checkpoint is the state of a simulated annealing run between iterations.
+// Restoring it with the same optimizer settings continues the run exactly
+// where it stopped, following the same trajectory as an uninterrupted run.
+type Checkpoint struct {
+	// Iteration is the next iteration to run
+	Iteration    int          `json:"iteration"`
+	Temperature  float64      `json:"temperature"`
+	CurrentDraw  *models.Draw `json:"current_draw"`
+	BestDraw     *models.Draw `json:"best_draw"`
+	InitialScore float64      `json:"initial_score"`
+	BestScore    float64      `json:"best_score"`
+	Improvements int          `json:"improvements"`
+	Acceptances  int          `json:"acceptances"`
+	// Seed and RandomDraws restore the random source: a source seeded with
+	// Seed, advanced by RandomDraws values
+	Seed        int64  `json:"seed"`
+	RandomDraws uint64 `json:"random_draws"`
+	// Elapsed is the run time so far, so time budgets carry over
+	Elapsed time.Duration `json:"elapsed"`
+	// OperatorStats carries the per-operator counts so far
+	OperatorStats map[string]OperatorStats `json:"operator_stats,omitempty"`
+	// ScoreCache carries the score cache's counts so far
+	ScoreCache constraints.CacheStats `json:"score_cache"`
+	// Archive carries a multi-objective run's Pareto archive so far
+	Archive []ParetoSolution `json:"archive,omitempty"`
+}
+
+// CheckpointFunc receives each checkpoint as a run saves it
+type CheckpointFunc func(checkpoint *Checkpoint)
+
+// Checkpointer is implemented by optimizers that can save and resume runs
+type Checkpointer interface {
+	// OptimizeFrom optimizes the draw like Optimize, continuing from resume
+	// when it is set. Checkpoints are passed to save as the run goes, and once
+	// more if ctx is cancelled, in which case the run returns ctx's error.
+	OptimizeFrom(ctx context.Context, draw *models.Draw, resume *Checkpoint, callback ProgressCallback, save CheckpointFunc) (*OptimizationResult, error)
+}
+
+// countingSource is a seeded random source that counts the values drawn from
+// it, so its position can be saved and restored
+type countingSource struct {
+	source rand.Source64
+	draws  uint64
+}
+
+// newCountingSource creates a counting source seeded with seed
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{source: rand.NewSource(seed).(rand.Source64)}
+}
+
+// Int63 returns the next value as a non-negative int64
+func (cs *countingSource) Int63() int64 {
+	cs.draws++
+	return cs.source.Int63()
+}
+
+// Uint64 returns the next value as a uint64
+func (cs *countingSource) Uint64() uint64 {
+	cs.draws++
+	return cs.source.Uint64()
+}
+
+// Seed reseeds the source and resets its count
+func (cs *countingSource) Seed(seed int64) {
+	cs.source.Seed(seed)
+	cs.draws = 0
+}
+
+// skip advances the source by n values
+func (cs *countingSource) skip(n uint64) {
+	for i := uint64(0); i < n; i++ {
+		cs.Uint64()
+	}
+}
+
+// SetCheckpointStore saves checkpoints of running jobs to the given
+// repository so they can be resumed
+func (jm *JobManager) SetCheckpointStore(store storage.OptimizationCheckpointRepository) {
+	jm.checkpoints = store
+}
+
+// saveCheckpoint stores a job's checkpoint along with the config it runs with
+func (jm *JobManager) saveCheckpoint(job *OptimizationJob, config OptimizationConfig, checkpoint *Checkpoint) {
+	record, err := checkpointRecord(job, config, checkpoint)
+	if err == nil {
+		err = jm.checkpoints.Save(context.Background(), record)
+	}
+	if err != nil {
+		log.Printf("Error saving checkpoint of optimization job %s: %v", job.ID, err)
+	}
+}
+
+// deleteCheckpoint drops a job's checkpoint once it's no longer needed
+func (jm *JobManager) deleteCheckpoint(jobID string) {
+	if jm.checkpoints == nil {
+		return
+	}
+	if err := jm.checkpoints.Delete(context.Background(), jobID); err != nil {
+		log.Printf("Error deleting checkpoint of optimization job %s: %v", jobID, err)
+	}
+}
+
+// checkpointRecord converts a checkpoint into its persisted form
+func checkpointRecord(job *OptimizationJob, config OptimizationConfig, checkpoint *Checkpoint) (*models.OptimizationCheckpointRecord, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	state, err := json.Marshal(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.OptimizationCheckpointRecord{
+		JobID:     job.ID,
+		DrawID:    job.DrawID,
+		Iteration: checkpoint.Iteration,
+		Config:    configJSON,
+		State:     state,
+	}
+	return record, record.Validate()
+}
+
+// checkpointFromRecord rebuilds a checkpoint and its config from the persisted form
+func checkpointFromRecord(record *models.OptimizationCheckpointRecord) (OptimizationConfig, *Checkpoint, error) {
+	var config OptimizationConfig
+	if err := json.Unmarshal(record.Config, &config); err != nil {
+		return config, nil, err
+	}
+
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(record.State, checkpoint); err != nil {
+		return config, nil, err
+	}
+
+	return config, checkpoint, nil
+}
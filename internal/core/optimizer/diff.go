@@ -0,0 +1,308 @@
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Kinds of change to a match between the stored and optimized draws
+const (
+	ChangeRound    = "round"
+	ChangeSchedule = "schedule" // date, time or prime-time slot
+	ChangeVenue    = "venue"
+	ChangeHomeAway = "home_away"
+	ChangeTeams    = "teams"
+)
+
+// MatchSlot is where and when a match is played
+type MatchSlot struct {
+	Round       int        `json:"round"`
+	HomeTeamID  *int       `json:"home_team_id"`
+	AwayTeamID  *int       `json:"away_team_id"`
+	VenueID     *int       `json:"venue_id"`
+	MatchDate   *time.Time `json:"match_date"`
+	MatchTime   *time.Time `json:"match_time"`
+	IsPrimeTime bool       `json:"is_prime_time"`
+}
+
+// MatchChange is a match that applying the result would change
+type MatchChange struct {
+	MatchID int       `json:"match_id"`
+	Changes []string  `json:"changes"`
+	Before  MatchSlot `json:"before"`
+	After   MatchSlot `json:"after"`
+}
+
+// ConstraintChange is a constraint's score before and after applying
+type ConstraintChange struct {
+	Name   string  `json:"name"`
+	Type   string  `json:"type"` // "hard" or "soft"
+	Weight float64 `json:"weight,omitempty"`
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+}
+
+// TeamMetrics are one team's figures in a draw
+type TeamMetrics struct {
+	HomeGames      int     `json:"home_games"`
+	AwayGames      int     `json:"away_games"`
+	PrimeTimeGames int     `json:"prime_time_games"`
+	TravelKm       float64 `json:"travel_km,omitempty"`
+	// Scores are the team's scores for each soft constraint that scores
+	// teams individually, keyed by constraint name
+	Scores map[string]float64 `json:"scores"`
+}
+
+// TeamChange is a team's metrics before and after applying
+type TeamChange struct {
+	TeamID int         `json:"team_id"`
+	Before TeamMetrics `json:"before"`
+	After  TeamMetrics `json:"after"`
+}
+
+// OptimizationDiff previews what applying a job's result would change in
+// the stored draw. Matches that apply skips, because they were locked after
+// the job started or sit outside the optimized rounds, are counted in
+// SkippedMatches and left out of the rest of the diff.
+type OptimizationDiff struct {
+	JobID                string             `json:"job_id"`
+	DrawID               int                `json:"draw_id"`
+	ScoreBefore          float64            `json:"score_before"`
+	ScoreAfter           float64            `json:"score_after"`
+	HardViolationsBefore int                `json:"hard_violations_before"`
+	HardViolationsAfter  int                `json:"hard_violations_after"`
+	MovedMatches         int                `json:"moved_matches"`
+	VenueChanges         int                `json:"venue_changes"`
+	HomeAwayFlips        int                `json:"home_away_flips"`
+	SkippedMatches       int                `json:"skipped_matches"`
+	Matches              []MatchChange      `json:"matches"`
+	Constraints          []ConstraintChange `json:"constraints"`
+	Teams                []TeamChange       `json:"teams"`
+}
+
+// DiffOptimizationResult compares the stored draw with the draw applying
+// the job's result would produce, scoring both with the draw's constraints
+func (s *Service) DiffOptimizationResult(jobID string) (*OptimizationDiff, error) {
+	result, err := s.GetOptimizationResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if result.BestDraw == nil {
+		return nil, fmt.Errorf("optimization result not available")
+	}
+
+	stored, err := s.repository.Draws().GetWithMatches(context.Background(), result.BestDraw.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch draw: %w", err)
+	}
+	if err := s.loadConstraintConfig(stored); err != nil {
+		return nil, fmt.Errorf("failed to load constraint config: %w", err)
+	}
+
+	diff := diffDraws(stored, result.BestDraw, result.Window, s.constraintEngine)
+	diff.JobID = jobID
+	return diff, nil
+}
+
+// diffDraws compares the stored draw with the optimized one, as apply would
+// write it
+func diffDraws(stored, optimized *models.Draw, window RoundWindow, engine *constraints.ConstraintEngine) *OptimizationDiff {
+	diff := &OptimizationDiff{
+		DrawID:      stored.ID,
+		Matches:     []MatchChange{},
+		Constraints: []ConstraintChange{},
+		Teams:       []TeamChange{},
+	}
+
+	optimizedByID := make(map[int]*models.Match, len(optimized.Matches))
+	for _, match := range optimized.Matches {
+		optimizedByID[match.ID] = match
+	}
+
+	applied := *stored
+	applied.Matches = make([]*models.Match, len(stored.Matches))
+	for i, match := range stored.Matches {
+		applied.Matches[i] = match
+
+		after, ok := optimizedByID[match.ID]
+		if !ok {
+			continue
+		}
+		changes := matchChanges(match, after)
+		if len(changes) == 0 {
+			continue
+		}
+		if match.Locked || !window.Contains(after.Round) {
+			diff.SkippedMatches++
+			continue
+		}
+
+		applied.Matches[i] = after
+		diff.Matches = append(diff.Matches, MatchChange{
+			MatchID: match.ID,
+			Changes: changes,
+			Before:  slotOf(match),
+			After:   slotOf(after),
+		})
+		for _, change := range changes {
+			switch change {
+			case ChangeRound:
+				diff.MovedMatches++
+			case ChangeVenue:
+				diff.VenueChanges++
+			case ChangeHomeAway:
+				diff.HomeAwayFlips++
+			}
+		}
+	}
+
+	diff.ScoreBefore = engine.ScoreDraw(stored)
+	diff.ScoreAfter = engine.ScoreDraw(&applied)
+	diff.HardViolationsBefore = len(engine.ValidateDraw(stored))
+	diff.HardViolationsAfter = len(engine.ValidateDraw(&applied))
+
+	for _, constraint := range engine.GetHardConstraints() {
+		diff.Constraints = append(diff.Constraints, ConstraintChange{
+			Name:   constraint.Name(),
+			Type:   "hard",
+			Before: constraint.Score(stored),
+			After:  constraint.Score(&applied),
+		})
+	}
+	for _, weighted := range engine.GetSoftConstraints() {
+		diff.Constraints = append(diff.Constraints, ConstraintChange{
+			Name:   weighted.Constraint.Name(),
+			Type:   "soft",
+			Weight: weighted.Weight,
+			Before: weighted.Constraint.Score(stored),
+			After:  weighted.Constraint.Score(&applied),
+		})
+	}
+
+	before := teamMetrics(stored, engine)
+	after := teamMetrics(&applied, engine)
+	teamIDs := make([]int, 0, len(before))
+	for teamID := range before {
+		teamIDs = append(teamIDs, teamID)
+	}
+	for teamID := range after {
+		if before[teamID] == nil {
+			teamIDs = append(teamIDs, teamID)
+		}
+	}
+	sort.Ints(teamIDs)
+	for _, teamID := range teamIDs {
+		diff.Teams = append(diff.Teams, TeamChange{
+			TeamID: teamID,
+			Before: before[teamID].orEmpty(),
+			After:  after[teamID].orEmpty(),
+		})
+	}
+
+	return diff
+}
+
+// matchChanges lists how a match differs between two versions
+func matchChanges(before, after *models.Match) []string {
+	changes := []string{}
+	if before.Round != after.Round {
+		changes = append(changes, ChangeRound)
+	}
+	if !sameTime(before.MatchDate, after.MatchDate) || !sameTime(before.MatchTime, after.MatchTime) || before.IsPrimeTime != after.IsPrimeTime {
+		changes = append(changes, ChangeSchedule)
+	}
+	if !sameID(before.VenueID, after.VenueID) {
+		changes = append(changes, ChangeVenue)
+	}
+	if !sameID(before.HomeTeamID, after.HomeTeamID) || !sameID(before.AwayTeamID, after.AwayTeamID) {
+		if sameID(before.HomeTeamID, after.AwayTeamID) && sameID(before.AwayTeamID, after.HomeTeamID) {
+			changes = append(changes, ChangeHomeAway)
+		} else {
+			changes = append(changes, ChangeTeams)
+		}
+	}
+	return changes
+}
+
+// teamMetrics gathers every team's figures in a draw
+func teamMetrics(draw *models.Draw, engine *constraints.ConstraintEngine) map[int]*TeamMetrics {
+	metrics := make(map[int]*TeamMetrics)
+	team := func(teamID int) *TeamMetrics {
+		if metrics[teamID] == nil {
+			metrics[teamID] = &TeamMetrics{Scores: make(map[string]float64)}
+		}
+		return metrics[teamID]
+	}
+
+	for _, match := range draw.Matches {
+		if match.IsBye() {
+			continue
+		}
+		home := team(*match.HomeTeamID)
+		away := team(*match.AwayTeamID)
+		home.HomeGames++
+		away.AwayGames++
+		if match.IsPrimeTime {
+			home.PrimeTimeGames++
+			away.PrimeTimeGames++
+		}
+	}
+
+	index := constraints.NewDrawIndex(draw)
+	for _, weighted := range engine.GetSoftConstraints() {
+		if travel, ok := weighted.Constraint.(*constraints.TravelMinimizationConstraint); ok {
+			for teamID, m := range metrics {
+				m.TravelKm = travel.CalculateTravelDistanceIndexed(index, teamID)
+			}
+		}
+		if scorer, ok := weighted.Constraint.(constraints.TeamScorer); ok {
+			for teamID, score := range constraints.TeamScoresWith(scorer, index) {
+				team(teamID).Scores[weighted.Constraint.Name()] = score
+			}
+		}
+	}
+
+	return metrics
+}
+
+// orEmpty returns the metrics, or empty ones for a team missing from a draw
+func (m *TeamMetrics) orEmpty() TeamMetrics {
+	if m == nil {
+		return TeamMetrics{Scores: map[string]float64{}}
+	}
+	return *m
+}
+
+// slotOf returns where and when a match is played
+func slotOf(match *models.Match) MatchSlot {
+	return MatchSlot{
+		Round:       match.Round,
+		HomeTeamID:  match.HomeTeamID,
+		AwayTeamID:  match.AwayTeamID,
+		VenueID:     match.VenueID,
+		MatchDate:   match.MatchDate,
+		MatchTime:   match.MatchTime,
+		IsPrimeTime: match.IsPrimeTime,
+	}
+}
+
+// sameID reports whether two optional IDs are equal
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameTime reports whether two optional times are equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
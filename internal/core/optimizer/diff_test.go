@@ -0,0 +1,68 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+)
+
+func TestDiffDraws(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+
+	stored := createTestDraw()
+	stored.Matches[0].Locked = true
+
+	optimized := createTestDraw()
+	otherVenue := 3
+	optimized.Matches[0].Round = 4 // locked, so skipped
+	optimized.Matches[1].HomeTeamID, optimized.Matches[1].AwayTeamID = optimized.Matches[1].AwayTeamID, optimized.Matches[1].HomeTeamID
+	optimized.Matches[2].Round = 3
+	optimized.Matches[3].VenueID = &otherVenue
+
+	diff := diffDraws(stored, optimized, RoundWindow{}, engine)
+
+	if diff.SkippedMatches != 1 {
+		t.Errorf("Expected 1 skipped match, got %d", diff.SkippedMatches)
+	}
+	if diff.MovedMatches != 1 || diff.HomeAwayFlips != 1 || diff.VenueChanges != 1 {
+		t.Errorf("Expected one move, flip and venue change, got %d, %d and %d",
+			diff.MovedMatches, diff.HomeAwayFlips, diff.VenueChanges)
+	}
+	if len(diff.Matches) != 3 {
+		t.Fatalf("Expected 3 changed matches, got %d", len(diff.Matches))
+	}
+	if diff.Matches[0].MatchID != 2 || diff.Matches[0].Changes[0] != ChangeHomeAway {
+		t.Errorf("Expected match 2 to flip home and away, got %+v", diff.Matches[0])
+	}
+	if diff.Matches[1].Before.Round != 2 || diff.Matches[1].After.Round != 3 {
+		t.Errorf("Expected match 3 to move from round 2 to 3, got %+v", diff.Matches[1])
+	}
+
+	if len(diff.Constraints) != 1 || diff.Constraints[0].Type != "soft" {
+		t.Errorf("Expected the home/away balance constraint, got %+v", diff.Constraints)
+	}
+
+	if len(diff.Teams) != 4 {
+		t.Fatalf("Expected 4 teams, got %d", len(diff.Teams))
+	}
+	// Team 3 loses its home game to the flip
+	team3 := diff.Teams[2]
+	if team3.TeamID != 3 || team3.Before.HomeGames != 1 || team3.After.HomeGames != 0 {
+		t.Errorf("Expected team 3 to go from 1 home game to 0, got %+v", team3)
+	}
+	if _, ok := team3.After.Scores[constraints.NewHomeAwayBalanceConstraint(0.1).Name()]; !ok {
+		t.Errorf("Expected team 3 to have a home/away balance score, got %+v", team3.After.Scores)
+	}
+
+	// Only the optimized rounds are applied
+	diff = diffDraws(stored, optimized, RoundWindow{From: 1, To: 1}, engine)
+	if len(diff.Matches) != 1 || diff.SkippedMatches != 3 {
+		t.Errorf("Expected 1 change and 3 skipped in round 1, got %d and %d", len(diff.Matches), diff.SkippedMatches)
+	}
+
+	unchanged := diffDraws(stored, createTestDraw(), RoundWindow{}, engine)
+	if len(unchanged.Matches) != 0 || unchanged.ScoreBefore != unchanged.ScoreAfter {
+		t.Errorf("Expected no changes against an identical draw, got %+v", unchanged.Matches)
+	}
+}
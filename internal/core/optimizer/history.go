@@ -0,0 +1,102 @@
+package optimizer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+)
+
+// DefaultHistoryInterval is how many iterations pass between the history
+// samples of a job when its config doesn't set an interval
+const DefaultHistoryInterval = 500
+
+// MaxHistoryPoints caps the samples kept per job. When a long run fills it,
+// every other sample is dropped and the interval doubles, so the history
+// always spans the whole run.
+const MaxHistoryPoints = 1000
+
+// HistoryPoint is one sample of an optimization's trajectory
+type HistoryPoint struct {
+	Iteration      int     `json:"iteration"`
+	Temperature    float64 `json:"temperature"`
+	CurrentScore   float64 `json:"current_score"`
+	BestScore      float64 `json:"best_score"`
+	AcceptanceRate float64 `json:"acceptance_rate"`
+	// ConstraintScores is how the draw scored on each constraint at the sample
+	ConstraintScores []constraints.ConstraintScore `json:"constraint_scores,omitempty"`
+	RecordedAt       time.Time                     `json:"recorded_at"`
+}
+
+// JobHistory is the sampled trajectory of a job, for charting convergence
+type JobHistory struct {
+	JobID  string    `json:"job_id"`
+	Status JobStatus `json:"status"`
+	// Interval is the number of iterations between samples
+	Interval int            `json:"interval"`
+	Points   []HistoryPoint `json:"points"`
+}
+
+// jobHistory samples a job's progress reports
+type jobHistory struct {
+	interval int
+	points   []HistoryPoint
+}
+
+// newJobHistory creates a history sampling every interval iterations
+func newJobHistory(interval int) *jobHistory {
+	if interval <= 0 {
+		interval = DefaultHistoryInterval
+	}
+	return &jobHistory{interval: interval}
+}
+
+// record samples the progress report if the interval has passed since the
+// last sample
+func (h *jobHistory) record(progress OptimizationProgress) {
+	if n := len(h.points); n > 0 && progress.Iteration-h.points[n-1].Iteration < h.interval {
+		return
+	}
+
+	h.points = append(h.points, HistoryPoint{
+		Iteration:        progress.Iteration,
+		Temperature:      progress.Temperature,
+		CurrentScore:     progress.CurrentScore,
+		BestScore:        progress.BestScore,
+		AcceptanceRate:   progress.AcceptanceRate,
+		ConstraintScores: progress.ConstraintScores,
+		RecordedAt:       time.Now(),
+	})
+
+	if len(h.points) > MaxHistoryPoints {
+		thinned := h.points[:0]
+		for i := 0; i < len(h.points); i += 2 {
+			thinned = append(thinned, h.points[i])
+		}
+		h.points = thinned
+		h.interval *= 2
+	}
+}
+
+// GetJobHistory returns a copy of the sampled trajectory of a job
+func (jm *JobManager) GetJobHistory(jobID string) (*JobHistory, error) {
+	jm.mutex.RLock()
+	defer jm.mutex.RUnlock()
+
+	job, exists := jm.jobs[jobID]
+	if !exists {
+		return nil, fmt.Errorf("job %s: %w", jobID, ErrJobNotFound)
+	}
+
+	history := &JobHistory{
+		JobID:  job.ID,
+		Status: job.Status,
+		Points: []HistoryPoint{},
+	}
+	if job.history != nil {
+		history.Interval = job.history.interval
+		history.Points = append(history.Points, job.history.points...)
+	}
+
+	return history, nil
+}
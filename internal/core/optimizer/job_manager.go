@@ -2,11 +2,14 @@ package optimizer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 )
 
 // JobStatus represents the status of an optimization job
@@ -20,6 +23,17 @@ const (
 	JobStatusFailed    JobStatus = "failed"
 )
 
+// ErrShuttingDown is returned when starting or resuming a job after Shutdown
+var ErrShuttingDown = errors.New("optimizer is shutting down")
+
+// ErrDrawLocked is returned when starting or resuming a job on a draw another
+// job is optimizing
+var ErrDrawLocked = errors.New("draw is locked by an optimization job")
+
+// ErrJobNotFound is returned for a job the job manager doesn't know. It wraps
+// storage.ErrNotFound.
+var ErrJobNotFound = fmt.Errorf("optimization job %w", storage.ErrNotFound)
+
 // OptimizationJob represents a running optimization job
 type OptimizationJob struct {
 	ID          string                `json:"id"`
@@ -30,22 +44,49 @@ type OptimizationJob struct {
 	Error       string                `json:"error,omitempty"`
 	StartedAt   time.Time             `json:"started_at"`
 	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+	RestoredAt  *time.Time            `json:"restored_at,omitempty"`
 	CancelFunc  context.CancelFunc    `json:"-"`
+	
+	// done is closed when the job's run returns
+	done chan struct{}
+	// history samples the job's progress reports
+	history *jobHistory
 }
 
 // JobManager manages optimization jobs
 type JobManager struct {
 	jobs        map[string]*OptimizationJob
 	mutex       sync.RWMutex
-	optimizer   *SimulatedAnnealing
+	optimizer   Optimizer
+	// config is the configuration optimizer was built from, saved with
+	// checkpoints so resumed jobs rebuild the same optimizer
+	config      OptimizationConfig
 	broadcaster *OptimizationBroadcaster
+
+	// store persists jobs so they survive restarts; persistMutex orders the
+	// writes so a stale snapshot never overwrites a newer one
+	store                   storage.OptimizationJobRepository
+	persistMutex            sync.Mutex
+	persistedAt             map[string]time.Time
+	progressPersistInterval time.Duration
+	checkpoints             storage.OptimizationCheckpointRepository
+	solutions               storage.SolutionRepository
+
+	// closed is set by Shutdown, after which no jobs start
+	closed bool
+	// drawLocks maps each draw being optimized to the job optimizing it.
+	// A job holds its draw's lock until its run returns.
+	drawLocks map[int]string
 }
 
 // NewJobManager creates a new job manager
-func NewJobManager(optimizer *SimulatedAnnealing) *JobManager {
+func NewJobManager(optimizer Optimizer) *JobManager {
 	return &JobManager{
-		jobs:      make(map[string]*OptimizationJob),
-		optimizer: optimizer,
+		jobs:                    make(map[string]*OptimizationJob),
+		drawLocks:               make(map[int]string),
+		optimizer:               optimizer,
+		persistedAt:             make(map[string]time.Time),
+		progressPersistInterval: DefaultProgressPersistInterval,
 	}
 }
 
@@ -66,30 +107,107 @@ func (jm *JobManager) StartOptimization(drawID int, draw *models.Draw) (string,
 		Status:     JobStatusPending,
 		StartedAt:  time.Now(),
 		CancelFunc: cancel,
+		done:       make(chan struct{}),
+		history:    newJobHistory(jm.config.HistoryInterval),
 	}
 	
 	jm.mutex.Lock()
+	if jm.closed {
+		jm.mutex.Unlock()
+		cancel()
+		return "", ErrShuttingDown
+	}
+	if holder, locked := jm.drawLocks[drawID]; locked {
+		jm.mutex.Unlock()
+		cancel()
+		return "", fmt.Errorf("%w: job %s is optimizing draw %d", ErrDrawLocked, holder, drawID)
+	}
 	jm.jobs[jobID] = job
+	jm.drawLocks[drawID] = jobID
 	jm.mutex.Unlock()
+	jm.persistJob(jobID, true)
 	
-	// Start optimization in a goroutine
-	go jm.runOptimization(ctx, job, draw)
+	// Start optimization in a goroutine. The optimizer is captured now so a
+	// later job's configuration can't change this one mid-run.
+	go jm.runOptimization(ctx, job, draw, jm.optimizer, jm.config, nil)
 	
 	return jobID, nil
 }
 
-// runOptimization executes the optimization algorithm
-func (jm *JobManager) runOptimization(ctx context.Context, job *OptimizationJob, draw *models.Draw) {
+// ResumeOptimization restarts a failed or cancelled job from its checkpoint,
+// keeping its ID. The optimizer must be built from the checkpointed config.
+func (jm *JobManager) ResumeOptimization(jobID string, draw *models.Draw, optimizer Optimizer, config OptimizationConfig, checkpoint *Checkpoint) error {
+	jm.mutex.Lock()
+	if jm.closed {
+		jm.mutex.Unlock()
+		return ErrShuttingDown
+	}
+	job, exists := jm.jobs[jobID]
+	if !exists {
+		jm.mutex.Unlock()
+		return fmt.Errorf("job %s: %w", jobID, ErrJobNotFound)
+	}
+	if job.Status != JobStatusFailed && job.Status != JobStatusCancelled {
+		jm.mutex.Unlock()
+		return fmt.Errorf("%w: job is %s", ErrJobNotResumable, job.Status)
+	}
+	done := job.done
+	jm.mutex.Unlock()
+	
+	// A cancelled run stops at its next check; let it save its last
+	// checkpoint before starting again
+	if done != nil {
+		<-done
+	}
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	
+	jm.mutex.Lock()
+	if jm.closed {
+		jm.mutex.Unlock()
+		cancel()
+		return ErrShuttingDown
+	}
+	if holder, locked := jm.drawLocks[job.DrawID]; locked {
+		jm.mutex.Unlock()
+		cancel()
+		return fmt.Errorf("%w: job %s is optimizing draw %d", ErrDrawLocked, holder, job.DrawID)
+	}
+	jm.drawLocks[job.DrawID] = jobID
+	job.Status = JobStatusPending
+	job.Error = ""
+	job.Result = nil
+	job.CompletedAt = nil
+	job.CancelFunc = cancel
+	job.done = make(chan struct{})
+	if job.history == nil {
+		job.history = newJobHistory(config.HistoryInterval)
+	}
+	jm.mutex.Unlock()
+	jm.persistJob(jobID, true)
+	
+	go jm.runOptimization(ctx, job, draw, optimizer, config, checkpoint)
+	
+	return nil
+}
+
+// runOptimization executes the optimization algorithm, continuing from resume
+// when it is set
+func (jm *JobManager) runOptimization(ctx context.Context, job *OptimizationJob, draw *models.Draw, optimizer Optimizer, config OptimizationConfig, resume *Checkpoint) {
+	defer close(job.done)
+	defer jm.releaseDrawLock(job.DrawID, job.ID)
+	
 	jm.updateJobStatus(job.ID, JobStatusRunning)
 	startTime := time.Now()
 	
 	// Create progress callback
 	progressCallback := func(progress OptimizationProgress) {
 		jm.updateJobProgress(job.ID, progress)
+		jm.persistJob(job.ID, false)
 		
 		// Broadcast progress update
 		if jm.broadcaster != nil {
-			jm.broadcaster.BroadcastOptimizationProgress(job.ID, job.DrawID, progress, jm.optimizer.MaxIterations)
+			jm.broadcaster.BroadcastOptimizationProgress(job.ID, job.DrawID, progress, optimizer.IterationBudget())
 		}
 		
 		// Check for cancellation
@@ -100,8 +218,21 @@ func (jm *JobManager) runOptimization(ctx context.Context, job *OptimizationJob,
 		}
 	}
 	
-	// Run the optimization
-	result, err := jm.optimizer.Optimize(draw, progressCallback)
+	// Run the optimization, stopping it when the job is cancelled and saving
+	// checkpoints when the optimizer supports them
+	var result *OptimizationResult
+	var err error
+	if checkpointer, ok := optimizer.(Checkpointer); ok {
+		var save CheckpointFunc
+		if jm.checkpoints != nil {
+			save = func(checkpoint *Checkpoint) {
+				jm.saveCheckpoint(job, config, checkpoint)
+			}
+		}
+		result, err = checkpointer.OptimizeFrom(ctx, draw, resume, progressCallback, save)
+	} else {
+		result, err = optimizer.Optimize(draw, progressCallback)
+	}
 	
 	// Check if job was cancelled
 	select {
@@ -125,6 +256,12 @@ func (jm *JobManager) runOptimization(ctx context.Context, job *OptimizationJob,
 		}
 	} else {
 		job.Status = JobStatusCompleted
+		result.WarmStartSolutionID = config.WarmStartSolutionID
+		// The result replaces the draw as the job found it, so applying it
+		// fails once the draw has been changed since
+		if result.BestDraw != nil {
+			result.BestDraw.Version = draw.Version
+		}
 		job.Result = result
 		// Broadcast completion
 		if jm.broadcaster != nil {
@@ -133,6 +270,32 @@ func (jm *JobManager) runOptimization(ctx context.Context, job *OptimizationJob,
 	}
 	job.CompletedAt = &completedAt
 	jm.mutex.Unlock()
+	jm.persistJob(job.ID, true)
+	
+	// Failed runs keep their checkpoint so they can be resumed
+	if err == nil {
+		jm.deleteCheckpoint(job.ID)
+		jm.recordSolution(job, config, result)
+	}
+}
+
+// DrawLock returns the job holding a draw's lock, and whether one does
+func (jm *JobManager) DrawLock(drawID int) (string, bool) {
+	jm.mutex.RLock()
+	defer jm.mutex.RUnlock()
+	
+	jobID, locked := jm.drawLocks[drawID]
+	return jobID, locked
+}
+
+// releaseDrawLock releases a draw's lock if the job still holds it
+func (jm *JobManager) releaseDrawLock(drawID int, jobID string) {
+	jm.mutex.Lock()
+	defer jm.mutex.Unlock()
+	
+	if jm.drawLocks[drawID] == jobID {
+		delete(jm.drawLocks, drawID)
+	}
 }
 
 // GetJob returns information about a specific job
@@ -142,32 +305,91 @@ func (jm *JobManager) GetJob(jobID string) (*OptimizationJob, error) {
 	
 	job, exists := jm.jobs[jobID]
 	if !exists {
-		return nil, fmt.Errorf("job %s not found", jobID)
+		return nil, fmt.Errorf("job %s: %w", jobID, ErrJobNotFound)
 	}
 	
 	return job, nil
 }
 
+// GetJobSnapshot returns a copy of a job as it is now, safe to read while the
+// job keeps running
+func (jm *JobManager) GetJobSnapshot(jobID string) (OptimizationJob, error) {
+	jm.mutex.RLock()
+	defer jm.mutex.RUnlock()
+	
+	job, exists := jm.jobs[jobID]
+	if !exists {
+		return OptimizationJob{}, fmt.Errorf("job %s: %w", jobID, ErrJobNotFound)
+	}
+	
+	return *job, nil
+}
+
 // CancelJob cancels a running optimization job
 func (jm *JobManager) CancelJob(jobID string) error {
 	jm.mutex.Lock()
-	defer jm.mutex.Unlock()
-	
 	job, exists := jm.jobs[jobID]
 	if !exists {
-		return fmt.Errorf("job %s not found", jobID)
+		jm.mutex.Unlock()
+		return fmt.Errorf("job %s: %w", jobID, ErrJobNotFound)
 	}
 	
-	if job.Status == JobStatusRunning {
+	cancelled := job.Status == JobStatusRunning
+	if cancelled {
 		job.CancelFunc()
 		job.Status = JobStatusCancelled
 		completedAt := time.Now()
 		job.CompletedAt = &completedAt
 	}
+	jm.mutex.Unlock()
 	
+	if cancelled {
+		jm.persistJob(jobID, true)
+	}
 	return nil
 }
 
+// Shutdown stops new jobs from starting and cancels the pending and running
+// ones, which save a checkpoint as they stop when their optimizer supports
+// it. It waits for their runs to return until ctx is done, and returns the
+// jobs it interrupted.
+func (jm *JobManager) Shutdown(ctx context.Context) ([]*OptimizationJob, error) {
+	jm.mutex.Lock()
+	jm.closed = true
+	var interrupted []*OptimizationJob
+	for _, job := range jm.jobs {
+		if job.Status != JobStatusPending && job.Status != JobStatusRunning {
+			continue
+		}
+		if job.CancelFunc != nil {
+			job.CancelFunc()
+		}
+		job.Status = JobStatusCancelled
+		job.Error = ShutdownJobError
+		completedAt := time.Now()
+		job.CompletedAt = &completedAt
+		interrupted = append(interrupted, job)
+	}
+	jm.mutex.Unlock()
+	
+	for _, job := range interrupted {
+		jm.persistJob(job.ID, true)
+	}
+	
+	for _, job := range interrupted {
+		if job.done == nil {
+			continue
+		}
+		select {
+		case <-job.done:
+		case <-ctx.Done():
+			return interrupted, fmt.Errorf("waiting for optimization job %s to stop: %w", job.ID, ctx.Err())
+		}
+	}
+	
+	return interrupted, nil
+}
+
 // ListJobs returns all jobs, optionally filtered by status
 func (jm *JobManager) ListJobs(status JobStatus) ([]*OptimizationJob, error) {
 	jm.mutex.RLock()
@@ -202,26 +424,89 @@ func (jm *JobManager) GetJobsByDrawID(drawID int) ([]*OptimizationJob, error) {
 
 // CleanupCompletedJobs removes completed jobs older than the specified duration
 func (jm *JobManager) CleanupCompletedJobs(maxAge time.Duration) {
-	jm.mutex.Lock()
-	defer jm.mutex.Unlock()
+	jm.persistMutex.Lock()
+	defer jm.persistMutex.Unlock()
 	
 	cutoff := time.Now().Add(-maxAge)
 	
+	var removed []string
+	jm.mutex.Lock()
 	for jobID, job := range jm.jobs {
 		if job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
 			delete(jm.jobs, jobID)
+			removed = append(removed, jobID)
 		}
 	}
+	jm.mutex.Unlock()
+	
+	for _, jobID := range removed {
+		jm.unpersistJob(jobID)
+	}
 }
 
-// updateJobStatus updates the status of a job
-func (jm *JobManager) updateJobStatus(jobID string, status JobStatus) {
+// IsFinished returns true once the job can no longer change
+func (job *OptimizationJob) IsFinished() bool {
+	switch job.Status {
+	case JobStatusCompleted, JobStatusCancelled, JobStatusFailed:
+		return job.CompletedAt != nil
+	}
+	return false
+}
+
+// finishedBefore returns finished jobs that completed, or were restored from
+// the archive, before the cutoff
+func (jm *JobManager) finishedBefore(cutoff time.Time) []*OptimizationJob {
+	jm.mutex.RLock()
+	defer jm.mutex.RUnlock()
+	
+	var jobs []*OptimizationJob
+	for _, job := range jm.jobs {
+		if !job.IsFinished() || !job.CompletedAt.Before(cutoff) {
+			continue
+		}
+		if job.RestoredAt != nil && !job.RestoredAt.Before(cutoff) {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	
+	return jobs
+}
+
+// removeJob drops a job from memory and from the store
+func (jm *JobManager) removeJob(jobID string) {
+	jm.persistMutex.Lock()
+	defer jm.persistMutex.Unlock()
+	
+	jm.mutex.Lock()
+	delete(jm.jobs, jobID)
+	jm.mutex.Unlock()
+	
+	jm.unpersistJob(jobID)
+}
+
+// restoreJob puts an archived job back into memory. An existing job with the
+// same ID is returned instead of being replaced.
+func (jm *JobManager) restoreJob(job *OptimizationJob) *OptimizationJob {
 	jm.mutex.Lock()
 	defer jm.mutex.Unlock()
 	
+	if existing, exists := jm.jobs[job.ID]; exists {
+		return existing
+	}
+	jm.jobs[job.ID] = job
+	return job
+}
+
+// updateJobStatus updates the status of a job
+func (jm *JobManager) updateJobStatus(jobID string, status JobStatus) {
+	jm.mutex.Lock()
 	if job, exists := jm.jobs[jobID]; exists {
 		job.Status = status
 	}
+	jm.mutex.Unlock()
+	
+	jm.persistJob(jobID, true)
 }
 
 // updateJobProgress updates the progress of a job
@@ -231,6 +516,9 @@ func (jm *JobManager) updateJobProgress(jobID string, progress OptimizationProgr
 	
 	if job, exists := jm.jobs[jobID]; exists {
 		job.Progress = progress
+		if job.history != nil {
+			job.history.record(progress)
+		}
 	}
 }
 
@@ -273,15 +561,62 @@ type JobStatistics struct {
 
 // OptimizationConfig contains configuration for optimization jobs
 type OptimizationConfig struct {
+	// Algorithm selects the optimizer, defaulting to simulated annealing
+	Algorithm       string                    `json:"algorithm,omitempty"`
 	Temperature     float64                   `json:"temperature"`
 	CoolingRate     float64                   `json:"cooling_rate"`
 	MaxIterations   int                       `json:"max_iterations"`
 	CoolingSchedule TemperatureScheduleConfig `json:"cooling_schedule"`
+	// StabilityWeight weights the penalty for deviating from the stored draw.
+	// When nil, completed (published) draws use DefaultStabilityWeight.
+	StabilityWeight *float64 `json:"stability_weight,omitempty"`
+	// TimeBudgetSeconds runs the optimizer for a wall-clock duration instead of
+	// MaxIterations; MaxIterations then only sets the cooling schedule's length.
+	TimeBudgetSeconds int `json:"time_budget_seconds,omitempty"`
+	// EarlyStopping ends a run before its iteration or time budget is spent
+	EarlyStopping EarlyStopping `json:"early_stopping,omitempty"`
+	// WarmStart starts the run from the solution library's best draw for the
+	// same teams, rounds and constraints, when it has one
+	WarmStart bool `json:"warm_start,omitempty"`
+	// SolutionKey and WarmStartSolutionID are set when the job starts: the
+	// library key its result is recorded under, and the solution it was warm
+	// started from
+	SolutionKey         string `json:"solution_key,omitempty"`
+	WarmStartSolutionID int    `json:"warm_start_solution_id,omitempty"`
+	// Seed makes the run reproducible; when nil a seed is picked and reported
+	// in the result
+	Seed *int64 `json:"seed,omitempty"`
+	// MultiStart runs several optimizations concurrently and keeps the best
+	MultiStart MultiStartConfig `json:"multi_start,omitempty"`
+	// Tabu configures tabu search; it is ignored by simulated annealing
+	Tabu TabuConfig `json:"tabu,omitempty"`
+	// Window re-optimizes only a range of rounds, keeping the others fixed
+	Window RoundWindow `json:"window,omitempty"`
+	// CheckpointInterval is how many iterations run between saved
+	// checkpoints, defaulting to DefaultCheckpointInterval
+	CheckpointInterval int `json:"checkpoint_interval,omitempty"`
+	// HistoryInterval is how many iterations pass between samples of the
+	// job's score trajectory, defaulting to DefaultHistoryInterval
+	HistoryInterval int `json:"history_interval,omitempty"`
+	// OperatorWeights is the probability of simulated annealing picking each
+	// neighbourhood operator; they are picked equally when empty
+	OperatorWeights OperatorWeights `json:"operator_weights,omitempty"`
+	// Objectives runs simulated annealing in multi-objective mode, returning
+	// a Pareto archive of up to ArchiveSize draws trading them off
+	Objectives  []constraints.Objective `json:"objectives,omitempty"`
+	ArchiveSize int                     `json:"archive_size,omitempty"`
 }
 
+// MaxTimeBudgetSeconds caps how long a single time-budgeted optimization may run
+const MaxTimeBudgetSeconds = 24 * 60 * 60
+
+// DefaultStabilityWeight is applied when re-optimizing a published draw
+const DefaultStabilityWeight = 0.5
+
 // DefaultOptimizationConfig returns a default configuration
 func DefaultOptimizationConfig() OptimizationConfig {
 	return OptimizationConfig{
+		Algorithm:     AlgorithmSimulatedAnnealing,
 		Temperature:   100.0,
 		CoolingRate:   0.99,
 		MaxIterations: 10000,
@@ -1,10 +1,16 @@
 package optimizer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 )
 
 func TestNewJobManager(t *testing.T) {
@@ -115,6 +121,69 @@ func TestCancelJob_NotFound(t *testing.T) {
 	}
 }
 
+func TestShutdown(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	optimizer := NewSimulatedAnnealing(100.0, 0.99, 100000000, engine)
+	jm := NewJobManager(optimizer)
+
+	jobID, err := jm.StartOptimization(1, createTestDraw())
+	if err != nil {
+		t.Fatalf("Failed to start optimization: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	interrupted, err := jm.Shutdown(ctx)
+	if err != nil {
+		t.Fatalf("Expected the job to stop before the timeout, got %v", err)
+	}
+	if len(interrupted) != 1 || interrupted[0].ID != jobID {
+		t.Fatalf("Expected job %s to be interrupted, got %v", jobID, interrupted)
+	}
+
+	job, _ := jm.GetJobSnapshot(jobID)
+	if job.Status != JobStatusCancelled || job.Error != ShutdownJobError || job.CompletedAt == nil {
+		t.Errorf("Expected the job to be cancelled by the shutdown, got %s (%q)", job.Status, job.Error)
+	}
+
+	if _, err := jm.StartOptimization(1, createTestDraw()); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown after shutdown, got %v", err)
+	}
+}
+
+func TestDrawLock(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	optimizer := NewSimulatedAnnealing(100.0, 0.99, 100000000, engine)
+	jm := NewJobManager(optimizer)
+
+	jobID, err := jm.StartOptimization(1, createTestDraw())
+	if err != nil {
+		t.Fatalf("Failed to start optimization: %v", err)
+	}
+	if holder, locked := jm.DrawLock(1); !locked || holder != jobID {
+		t.Errorf("Expected job %s to hold draw 1's lock, got %q", jobID, holder)
+	}
+	if _, locked := jm.DrawLock(2); locked {
+		t.Error("Expected draw 2 to be unlocked")
+	}
+
+	// A second job can't optimize the same draw
+	if _, err := jm.StartOptimization(1, createTestDraw()); !errors.Is(err, ErrDrawLocked) {
+		t.Errorf("Expected ErrDrawLocked, got %v", err)
+	}
+
+	// The lock is released once the run stops
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := jm.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected the job to stop before the timeout, got %v", err)
+	}
+	if _, locked := jm.DrawLock(1); locked {
+		t.Error("Expected the lock to be released after the job stopped")
+	}
+}
+
 func TestListJobs(t *testing.T) {
 	engine := constraints.NewConstraintEngine()
 	optimizer := NewSimulatedAnnealing(100.0, 0.99, 100, engine)
@@ -202,6 +271,129 @@ func TestCleanupCompletedJobs(t *testing.T) {
 	}
 }
 
+func TestArchiveJobRoundTrip(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	optimizer := NewSimulatedAnnealing(100.0, 0.99, 10, engine)
+	jm := NewJobManager(optimizer)
+
+	jobID, _ := jm.StartOptimization(1, createTestDraw())
+	time.Sleep(100 * time.Millisecond)
+
+	job, _ := jm.GetJob(jobID)
+	if job.Status != JobStatusCompleted {
+		t.Fatalf("Expected job to complete, got %s", job.Status)
+	}
+
+	archive, err := archiveJob(job)
+	if err != nil {
+		t.Fatalf("Unexpected error archiving job: %v", err)
+	}
+	if len(archive.Payload) >= archive.PayloadSize {
+		t.Errorf("Expected payload to be compressed, stored %d of %d bytes", len(archive.Payload), archive.PayloadSize)
+	}
+
+	restored, err := unarchiveJob(archive)
+	if err != nil {
+		t.Fatalf("Unexpected error restoring job: %v", err)
+	}
+	if restored.ID != jobID || restored.Status != JobStatusCompleted {
+		t.Errorf("Expected completed job %s, got %s (%s)", jobID, restored.ID, restored.Status)
+	}
+	if restored.Result == nil || restored.Result.BestDraw == nil {
+		t.Fatal("Expected restored job to keep its result")
+	}
+	if len(restored.Result.BestDraw.Matches) != len(job.Result.BestDraw.Matches) {
+		t.Errorf("Expected %d matches, got %d", len(job.Result.BestDraw.Matches), len(restored.Result.BestDraw.Matches))
+	}
+
+	// A freshly restored job isn't due for archiving again
+	now := time.Now()
+	jm.removeJob(jobID)
+	restored.RestoredAt = &now
+	jm.restoreJob(restored)
+	if due := jm.finishedBefore(now.Add(-time.Minute)); len(due) != 0 {
+		t.Errorf("Expected restored job to stay in memory, got %d due", len(due))
+	}
+}
+
+// memoryJobStore is an in-memory storage.OptimizationJobRepository
+type memoryJobStore struct {
+	mutex   sync.Mutex
+	records map[string]*models.OptimizationJobRecord
+}
+
+func (m *memoryJobStore) Save(ctx context.Context, record *models.OptimizationJobRecord) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	saved := *record
+	m.records[record.JobID] = &saved
+	return nil
+}
+
+func (m *memoryJobStore) GetByJobID(ctx context.Context, jobID string) (*models.OptimizationJobRecord, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	record, exists := m.records[jobID]
+	if !exists {
+		return nil, fmt.Errorf("optimization job %s: %w", jobID, storage.ErrNotFound)
+	}
+	return record, nil
+}
+
+func (m *memoryJobStore) List(ctx context.Context) ([]*models.OptimizationJobRecord, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var records []*models.OptimizationJobRecord
+	for _, record := range m.records {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (m *memoryJobStore) Delete(ctx context.Context, jobID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.records, jobID)
+	return nil
+}
+
+func TestPersistJobRoundTrip(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	optimizer := NewSimulatedAnnealing(100.0, 0.99, 10, engine)
+	jm := NewJobManager(optimizer)
+	store := &memoryJobStore{records: make(map[string]*models.OptimizationJobRecord)}
+	jm.SetStore(store)
+
+	jobID, _ := jm.StartOptimization(1, createTestDraw())
+	time.Sleep(100 * time.Millisecond)
+
+	record, err := store.GetByJobID(context.Background(), jobID)
+	if err != nil {
+		t.Fatalf("Expected job to be persisted: %v", err)
+	}
+	if record.Status != string(JobStatusCompleted) || record.CompletedAt == nil {
+		t.Fatalf("Expected persisted job to be completed, got %s", record.Status)
+	}
+
+	restored, err := jobFromRecord(record)
+	if err != nil {
+		t.Fatalf("Unexpected error restoring job: %v", err)
+	}
+	if restored.Result == nil || restored.Result.BestDraw == nil {
+		t.Fatal("Expected restored job to keep its result")
+	}
+	job, _ := jm.GetJob(jobID)
+	if restored.Result.FinalScore != job.Result.FinalScore {
+		t.Errorf("Expected final score %f, got %f", job.Result.FinalScore, restored.Result.FinalScore)
+	}
+
+	// Jobs leaving memory leave the store too
+	jm.removeJob(jobID)
+	if _, err := store.GetByJobID(context.Background(), jobID); err == nil {
+		t.Error("Expected removed job to be deleted from the store")
+	}
+}
+
 func TestGetJobStatistics(t *testing.T) {
 	engine := constraints.NewConstraintEngine()
 	optimizer := NewSimulatedAnnealing(100.0, 0.99, 100, engine)
@@ -248,6 +440,59 @@ func TestOptimizationProgress(t *testing.T) {
 	}
 }
 
+func TestJobHistory(t *testing.T) {
+	history := newJobHistory(10)
+	for i := 0; i <= 100; i += 5 {
+		history.record(OptimizationProgress{Iteration: i, BestScore: float64(i)})
+	}
+	if len(history.points) != 11 || history.points[1].Iteration != 10 {
+		t.Fatalf("Expected a sample every 10 iterations, got %d samples", len(history.points))
+	}
+
+	// Long runs are thinned so the history spans the whole run
+	for i := 110; len(history.points) < MaxHistoryPoints; i += 10 {
+		history.record(OptimizationProgress{Iteration: i})
+	}
+	last := history.points[len(history.points)-1].Iteration
+	history.record(OptimizationProgress{Iteration: last + 10})
+	if len(history.points) > MaxHistoryPoints/2+1 || history.interval != 20 {
+		t.Errorf("Expected the history to be thinned to every 20 iterations, got %d samples every %d",
+			len(history.points), history.interval)
+	}
+	if history.points[0].Iteration != 0 {
+		t.Errorf("Expected thinning to keep the first sample, got iteration %d", history.points[0].Iteration)
+	}
+
+	engine := constraints.NewConstraintEngine()
+	jm := NewJobManager(NewSimulatedAnnealing(100.0, 0.99, 1000, engine))
+	jm.config.HistoryInterval = 200
+	jobID, _ := jm.StartOptimization(1, createTestDraw())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, _ := jm.GetJobSnapshot(jobID)
+		if job.IsFinished() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Job did not complete within timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	jobHistory, err := jm.GetJobHistory(jobID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if jobHistory.Interval != 200 || len(jobHistory.Points) != 5 {
+		t.Errorf("Expected 5 samples every 200 iterations, got %d every %d", len(jobHistory.Points), jobHistory.Interval)
+	}
+
+	if _, err := jm.GetJobHistory("missing"); err == nil {
+		t.Error("Expected error for missing job")
+	}
+}
+
 func TestJobTimeout(t *testing.T) {
 	engine := constraints.NewConstraintEngine()
 	optimizer := NewSimulatedAnnealing(100.0, 0.99, 1, engine) // Very quick
@@ -0,0 +1,168 @@
+package optimizer
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// matchState is the part of a match a neighbourhood move can change
+type matchState struct {
+	round      int
+	homeTeamID *int
+	awayTeamID *int
+	venueID    *int
+}
+
+// stateOf captures a match's movable fields
+func stateOf(match *models.Match) matchState {
+	return matchState{
+		round:      match.Round,
+		homeTeamID: match.HomeTeamID,
+		awayTeamID: match.AwayTeamID,
+		venueID:    match.VenueID,
+	}
+}
+
+// applyTo sets a match's movable fields
+func (s matchState) applyTo(match *models.Match) {
+	match.Round = s.round
+	match.HomeTeamID = s.homeTeamID
+	match.AwayTeamID = s.awayTeamID
+	match.VenueID = s.venueID
+}
+
+// equal reports whether two states place a match the same way
+func (s matchState) equal(other matchState) bool {
+	return s.round == other.round &&
+		sameIntPtr(s.homeTeamID, other.homeTeamID) &&
+		sameIntPtr(s.awayTeamID, other.awayTeamID) &&
+		sameIntPtr(s.venueID, other.venueID)
+}
+
+// matchEdit is one match's state before and after a move
+type matchEdit struct {
+	index  int
+	match  *models.Match
+	before matchState
+	after  matchState
+}
+
+// move is one neighbourhood move applied to a draw in place
+type move struct {
+	operator string
+	edits    []matchEdit
+}
+
+// indexes returns the positions in the draw of the matches the move changed
+func (m *move) indexes() []int {
+	indexes := make([]int, len(m.edits))
+	for i, edit := range m.edits {
+		indexes[i] = edit.index
+	}
+	return indexes
+}
+
+// matches returns the matches the move changed
+func (m *move) matches() []*models.Match {
+	matches := make([]*models.Match, len(m.edits))
+	for i, edit := range m.edits {
+		matches[i] = edit.match
+	}
+	return matches
+}
+
+// moveJournal records the moves applied to a draw in place, so a rejected
+// neighbour is reverted by undoing its move rather than by working on a copy
+// of the whole draw. Undone moves can be redone, which lets tabu search try
+// each candidate in turn and then replay the one it picks.
+type moveJournal struct {
+	draw    *models.Draw
+	pending *move
+	applied []*move
+}
+
+// newMoveJournal creates a journal for moves made to draw
+func newMoveJournal(draw *models.Draw) *moveJournal {
+	return &moveJournal{draw: draw}
+}
+
+// begin starts recording a move. Operators call touch before changing a match.
+func (j *moveJournal) begin(operator string) {
+	j.pending = &move{operator: operator}
+}
+
+// touch records a match's state before the pending move changes it. Matches
+// touched outside a move, or on a journal-less optimizer, aren't recorded.
+func (j *moveJournal) touch(index int) {
+	if j == nil || j.pending == nil {
+		return
+	}
+	match := j.draw.Matches[index]
+	for _, edit := range j.pending.edits {
+		if edit.match == match {
+			return
+		}
+	}
+	j.pending.edits = append(j.pending.edits, matchEdit{index: index, match: match, before: stateOf(match)})
+}
+
+// end finishes the pending move and records it as applied. Matches the move
+// touched but left as they were are dropped from it.
+func (j *moveJournal) end() *move {
+	m := j.pending
+	j.pending = nil
+
+	edits := m.edits[:0]
+	for _, edit := range m.edits {
+		edit.after = stateOf(edit.match)
+		if !edit.after.equal(edit.before) {
+			edits = append(edits, edit)
+		}
+	}
+	m.edits = edits
+
+	j.applied = append(j.applied, m)
+	return m
+}
+
+// cancel abandons the pending move, restoring any match it changed
+func (j *moveJournal) cancel() {
+	if j.pending == nil {
+		return
+	}
+	for i := len(j.pending.edits) - 1; i >= 0; i-- {
+		edit := j.pending.edits[i]
+		edit.before.applyTo(edit.match)
+	}
+	j.pending = nil
+}
+
+// undo reverts the most recently applied move and returns it, or nil when
+// there is nothing to undo
+func (j *moveJournal) undo() *move {
+	if len(j.applied) == 0 {
+		return nil
+	}
+	m := j.applied[len(j.applied)-1]
+	j.applied = j.applied[:len(j.applied)-1]
+
+	for i := len(m.edits) - 1; i >= 0; i-- {
+		edit := m.edits[i]
+		edit.before.applyTo(edit.match)
+	}
+	return m
+}
+
+// redo reapplies a move that was undone. The draw must be as it was when the
+// move was first applied.
+func (j *moveJournal) redo(m *move) {
+	for _, edit := range m.edits {
+		edit.after.applyTo(edit.match)
+	}
+	j.applied = append(j.applied, m)
+}
+
+// clear forgets the applied moves once they are kept for good, so the journal
+// doesn't grow over a run
+func (j *moveJournal) clear() {
+	j.applied = j.applied[:0]
+}
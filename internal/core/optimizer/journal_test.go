@@ -0,0 +1,101 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+)
+
+func TestMoveJournal_UndoRedo(t *testing.T) {
+	draw := createTestDraw()
+	journal := newMoveJournal(draw)
+	match := draw.Matches[0]
+	round, home, away := match.Round, match.HomeTeamID, match.AwayTeamID
+
+	journal.begin(OperatorSwapHomeAway)
+	journal.touch(0)
+	journal.touch(0) // Touching twice keeps the first state
+	match.Round = 2
+	match.HomeTeamID, match.AwayTeamID = match.AwayTeamID, match.HomeTeamID
+	moved := journal.end()
+
+	if len(moved.edits) != 1 || moved.operator != OperatorSwapHomeAway {
+		t.Fatalf("Expected one edit by %s, got %d by %s", OperatorSwapHomeAway, len(moved.edits), moved.operator)
+	}
+	if changed := moved.matches(); len(changed) != 1 || changed[0] != match {
+		t.Errorf("Expected the move to list the changed match")
+	}
+
+	if journal.undo() != moved {
+		t.Fatal("Expected to undo the move")
+	}
+	if match.Round != round || match.HomeTeamID != home || match.AwayTeamID != away {
+		t.Errorf("Expected undo to restore round %d, got round %d", round, match.Round)
+	}
+	if journal.undo() != nil {
+		t.Error("Expected nothing left to undo")
+	}
+
+	journal.redo(moved)
+	if match.Round != 2 || match.HomeTeamID != away || match.AwayTeamID != home {
+		t.Errorf("Expected redo to reapply the move, got round %d", match.Round)
+	}
+
+	// Cleared moves are kept for good
+	journal.clear()
+	if journal.undo() != nil {
+		t.Error("Expected a cleared journal to have nothing to undo")
+	}
+	if match.Round != 2 {
+		t.Errorf("Expected the cleared move to stay applied, got round %d", match.Round)
+	}
+}
+
+func TestMoveJournal_Cancel(t *testing.T) {
+	draw := createTestDraw()
+	journal := newMoveJournal(draw)
+	round := draw.Matches[1].Round
+
+	journal.begin(OperatorRescheduleMatch)
+	journal.touch(1)
+	draw.Matches[1].Round = round + 1
+	journal.cancel()
+
+	if draw.Matches[1].Round != round {
+		t.Errorf("Expected cancel to restore round %d, got %d", round, draw.Matches[1].Round)
+	}
+	if journal.undo() != nil {
+		t.Error("Expected a cancelled move not to be recorded")
+	}
+
+	// Matches touched outside a move aren't recorded
+	journal.touch(1)
+	var none *moveJournal
+	none.touch(1)
+}
+
+func TestOptimize_LeavesInputDraw(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(1.0), 1.0)
+	sa := NewSimulatedAnnealing(100.0, 0.95, 200, engine)
+	seed := int64(11)
+	sa.Seed = &seed
+
+	draw := createTestDraw()
+	original := sa.copyDraw(draw)
+
+	result, err := sa.Optimize(draw, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Moves are made in place on the optimizer's own copy
+	for i, match := range draw.Matches {
+		if !stateOf(match).equal(stateOf(original.Matches[i])) {
+			t.Errorf("Expected match %d of the input draw to be untouched", i)
+		}
+	}
+	if score := engine.ScoreDraw(result.BestDraw); score != result.FinalScore {
+		t.Errorf("Expected the best draw to score %f, got %f", result.FinalScore, score)
+	}
+}
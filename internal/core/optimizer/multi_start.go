@@ -0,0 +1,247 @@
+package optimizer
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// MultiStartConfig runs several annealing runs concurrently from different
+// random starts and keeps the best result. It is enabled when Starts > 1.
+type MultiStartConfig struct {
+	Starts int `json:"starts,omitempty"`
+	// Workers is how many runs execute at once, defaulting to the CPU count.
+	// A time budget applies to each run, not to the job as a whole.
+	Workers int `json:"workers,omitempty"`
+}
+
+// Enabled reports whether the config asks for more than one run
+func (mc MultiStartConfig) Enabled() bool {
+	return mc.Starts > 1
+}
+
+// MultiStartOptimizer runs several copies of a simulated annealing optimizer
+// concurrently. The first run starts from the draw as given; the others start
+// from randomly perturbed copies so they explore different local optima.
+type MultiStartOptimizer struct {
+	Base    *SimulatedAnnealing
+	Starts  int
+	Workers int
+}
+
+// NewMultiStartOptimizer creates a new multi-start optimizer. A non-positive
+// worker count uses one worker per CPU.
+func NewMultiStartOptimizer(base *SimulatedAnnealing, starts, workers int) *MultiStartOptimizer {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > starts {
+		workers = starts
+	}
+	return &MultiStartOptimizer{
+		Base:    base,
+		Starts:  starts,
+		Workers: workers,
+	}
+}
+
+// IterationBudget returns the iterations all runs are expected to take together
+func (mso *MultiStartOptimizer) IterationBudget() int {
+	return mso.Starts * mso.Base.IterationBudget()
+}
+
+// Optimize runs every start and returns the best-scoring result. Iterations
+// and improvements are totalled across runs. Progress reports aggregate the
+// runs, with the best score being the best any run has found. A multi-objective
+// run merges every start's Pareto archive into one.
+func (mso *MultiStartOptimizer) Optimize(draw *models.Draw, callback ProgressCallback) (*OptimizationResult, error) {
+	if draw == nil {
+		return nil, fmt.Errorf("draw cannot be nil")
+	}
+	if len(draw.Matches) == 0 {
+		return nil, fmt.Errorf("draw has no matches to optimize")
+	}
+	if mso.Starts < 1 {
+		return nil, fmt.Errorf("multi-start needs at least one start")
+	}
+
+	startTime := time.Now()
+	baseSeed := mso.Base.runSeed()
+	aggregator := newProgressAggregator(mso.Starts, startTime, callback)
+
+	results := make([]*OptimizationResult, mso.Starts)
+	errs := make([]error, mso.Starts)
+
+	starts := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < mso.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				results[start], errs[start] = mso.runStart(draw, start, baseSeed+int64(start), aggregator)
+				aggregator.finish(start)
+			}
+		}()
+	}
+	for start := 0; start < mso.Starts; start++ {
+		starts <- start
+	}
+	close(starts)
+	wg.Wait()
+
+	result := &OptimizationResult{
+		InitialScore: mso.Base.ConstraintEngine.ScoreDraw(draw),
+		Seed:         baseSeed,
+		BestStart:    -1,
+		Window:       mso.Base.Window,
+		StartScores:  make([]float64, mso.Starts),
+	}
+	tally := make(operatorTally)
+	var archive *ParetoArchive
+	if len(mso.Base.Objectives) > 0 {
+		archive = NewParetoArchive(mso.Base.Objectives, mso.Base.ArchiveSize)
+	}
+	for start, run := range results {
+		if run == nil {
+			continue
+		}
+		result.Iterations += run.Iterations
+		result.Improvements += run.Improvements
+		tally.add(run.OperatorStats)
+		result.ScoreCache = result.ScoreCache.Add(run.ScoreCache)
+		result.StartScores[start] = run.FinalScore
+		for _, solution := range run.ParetoFront {
+			archive.AddSolution(solution)
+		}
+		// Ties go to the earliest start so seeded runs pick the same winner
+		if result.BestStart < 0 || run.FinalScore > result.FinalScore {
+			result.FinalScore = run.FinalScore
+			result.BestDraw = run.BestDraw
+			result.BestStart = start
+			result.TerminationReason = run.TerminationReason
+		}
+	}
+	if result.BestStart < 0 {
+		return nil, fmt.Errorf("all %d starts failed: %w", mso.Starts, errs[0])
+	}
+	result.Duration = time.Since(startTime)
+	result.OperatorStats = tally.stats()
+	if archive != nil {
+		result.Objectives = mso.Base.Objectives
+		result.ParetoFront = archive.Solutions()
+	}
+
+	return result, nil
+}
+
+// runStart runs one annealing run from the start's own starting draw
+func (mso *MultiStartOptimizer) runStart(draw *models.Draw, start int, seed int64, aggregator *progressAggregator) (*OptimizationResult, error) {
+	run := mso.Base.withSeed(seed)
+	run.Seed = &seed
+
+	startDraw := draw
+	if start > 0 {
+		startDraw = run.copyDraw(draw)
+		run.applyMultipleOperations(startDraw, startPerturbations(draw))
+	}
+
+	return run.Optimize(startDraw, func(progress OptimizationProgress) {
+		aggregator.report(start, progress)
+	})
+}
+
+// startPerturbations is how many random moves shake up a draw before a
+// non-initial start, enough to leave the original draw's local optimum
+func startPerturbations(draw *models.Draw) int {
+	moves := len(draw.Matches) / 4
+	if moves < 1 {
+		moves = 1
+	}
+	return moves
+}
+
+// progressAggregator combines progress reports from concurrent runs into one
+type progressAggregator struct {
+	mutex     sync.Mutex
+	latest    []OptimizationProgress
+	finished  []bool
+	startTime time.Time
+	callback  ProgressCallback
+}
+
+func newProgressAggregator(starts int, startTime time.Time, callback ProgressCallback) *progressAggregator {
+	return &progressAggregator{
+		latest:    make([]OptimizationProgress, starts),
+		finished:  make([]bool, starts),
+		startTime: startTime,
+		callback:  callback,
+	}
+}
+
+// report records a run's progress and passes the combined progress on
+func (pa *progressAggregator) report(start int, progress OptimizationProgress) {
+	if pa.callback == nil {
+		return
+	}
+
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+
+	pa.latest[start] = progress
+	pa.callback(pa.combined(start))
+}
+
+// finish marks a run as done, counting it as complete in later reports
+func (pa *progressAggregator) finish(start int) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+
+	pa.finished[start] = true
+	pa.latest[start].FractionComplete = 1
+}
+
+// combined sums iterations, averages rates and keeps the best scores across
+// runs. Worst teams and constraint scores come from the run that reported
+// last.
+func (pa *progressAggregator) combined(reporting int) OptimizationProgress {
+	combined := OptimizationProgress{
+		WorstTeams:       pa.latest[reporting].WorstTeams,
+		ConstraintScores: pa.latest[reporting].ConstraintScores,
+		Starts:           len(pa.latest),
+	}
+
+	var temperature, acceptance, fraction float64
+	for start, progress := range pa.latest {
+		combined.Iteration += progress.Iteration
+		if progress.CurrentScore > combined.CurrentScore {
+			combined.CurrentScore = progress.CurrentScore
+		}
+		if progress.BestScore > combined.BestScore {
+			combined.BestScore = progress.BestScore
+		}
+		if pa.finished[start] {
+			combined.StartsCompleted++
+		}
+		temperature += progress.Temperature
+		acceptance += progress.AcceptanceRate
+		fraction += progress.FractionComplete
+	}
+
+	starts := float64(len(pa.latest))
+	combined.Temperature = temperature / starts
+	combined.AcceptanceRate = acceptance / starts
+	combined.FractionComplete = fraction / starts
+
+	remaining := time.Duration(0)
+	if combined.FractionComplete > 0 {
+		elapsed := time.Since(pa.startTime)
+		remaining = time.Duration(float64(elapsed) * (1 - combined.FractionComplete) / combined.FractionComplete)
+	}
+	combined.EstimatedTime = remaining.String()
+
+	return combined
+}
@@ -0,0 +1,109 @@
+package optimizer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+)
+
+func TestMultiStartOptimize(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	base := NewSimulatedAnnealing(100.0, 0.99, 200, engine)
+	mso := NewMultiStartOptimizer(base, 4, 2)
+
+	if mso.Workers != 2 {
+		t.Errorf("Expected 2 workers, got %d", mso.Workers)
+	}
+	if mso.IterationBudget() != 800 {
+		t.Errorf("Expected an iteration budget of 800, got %d", mso.IterationBudget())
+	}
+
+	var mutex sync.Mutex
+	reports := 0
+	maxBest := 0.0
+	result, err := mso.Optimize(createTestDraw(), func(progress OptimizationProgress) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		reports++
+		if progress.Starts != 4 {
+			t.Errorf("Expected progress across 4 starts, got %d", progress.Starts)
+		}
+		if progress.BestScore < maxBest {
+			t.Errorf("Aggregated best score went backwards: %.4f after %.4f", progress.BestScore, maxBest)
+		}
+		maxBest = progress.BestScore
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if reports == 0 {
+		t.Error("Expected aggregated progress reports")
+	}
+	if result.Iterations != 800 {
+		t.Errorf("Expected iterations totalled across starts, got %d", result.Iterations)
+	}
+	if len(result.StartScores) != 4 {
+		t.Fatalf("Expected a score per start, got %d", len(result.StartScores))
+	}
+	for start, score := range result.StartScores {
+		if score > result.FinalScore {
+			t.Errorf("Start %d scored %.4f, better than the kept %.4f", start, score, result.FinalScore)
+		}
+	}
+	if result.StartScores[result.BestStart] != result.FinalScore {
+		t.Errorf("Best start %d doesn't match the final score", result.BestStart)
+	}
+	if result.BestDraw == nil {
+		t.Error("Expected best draw in result")
+	}
+}
+
+func TestMultiStartOptimize_Seeded(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	seed := int64(17)
+
+	run := func() *OptimizationResult {
+		base := NewSimulatedAnnealing(100.0, 0.99, 200, engine)
+		base.Seed = &seed
+		result, err := NewMultiStartOptimizer(base, 3, 3).Optimize(createTestDraw(), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result
+	}
+
+	first, second := run(), run()
+	if first.Seed != seed {
+		t.Errorf("Expected result to report seed %d, got %d", seed, first.Seed)
+	}
+	if first.BestStart != second.BestStart {
+		t.Errorf("Expected the same winning start, got %d and %d", first.BestStart, second.BestStart)
+	}
+	for i := range first.StartScores {
+		if first.StartScores[i] != second.StartScores[i] {
+			t.Errorf("Start %d scored %.4f then %.4f", i, first.StartScores[i], second.StartScores[i])
+		}
+	}
+}
+
+func TestNewOptimizerFromConfig_MultiStart(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+
+	config := DefaultOptimizationConfig()
+	if _, ok := newOptimizerFromConfig(config, engine).(*SimulatedAnnealing); !ok {
+		t.Error("Expected a single annealing run by default")
+	}
+
+	config.MultiStart = MultiStartConfig{Starts: 8, Workers: 16}
+	mso, ok := newOptimizerFromConfig(config, engine).(*MultiStartOptimizer)
+	if !ok {
+		t.Fatal("Expected a multi-start optimizer")
+	}
+	if mso.Starts != 8 || mso.Workers != 8 {
+		t.Errorf("Expected 8 starts on 8 workers, got %d on %d", mso.Starts, mso.Workers)
+	}
+}
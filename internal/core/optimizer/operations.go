@@ -2,7 +2,6 @@ package optimizer
 
 import (
 	"errors"
-	"math/rand"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 )
@@ -15,11 +14,12 @@ func (sa *SimulatedAnnealing) swapMatches(draw *models.Draw) error {
 	
 	// Find two different matches from different rounds
 	var match1, match2 *models.Match
+	var idx1, idx2 int
 	maxAttempts := 50
 	
 	for attempts := 0; attempts < maxAttempts; attempts++ {
-		idx1 := rand.Intn(len(draw.Matches))
-		idx2 := rand.Intn(len(draw.Matches))
+		idx1 = sa.random().Intn(len(draw.Matches))
+		idx2 = sa.random().Intn(len(draw.Matches))
 		
 		if idx1 == idx2 {
 			continue
@@ -28,8 +28,8 @@ func (sa *SimulatedAnnealing) swapMatches(draw *models.Draw) error {
 		match1 = draw.Matches[idx1]
 		match2 = draw.Matches[idx2]
 		
-		// Only swap if they're in different rounds and both are regular matches (not byes)
-		if match1.Round != match2.Round && !match1.IsBye() && !match2.IsBye() {
+		// Only swap if they're in different rounds and both are regular, unlocked matches
+		if match1.Round != match2.Round && sa.canMove(match1) && sa.canMove(match2) {
 			break
 		}
 		
@@ -41,6 +41,8 @@ func (sa *SimulatedAnnealing) swapMatches(draw *models.Draw) error {
 	}
 	
 	// Swap the rounds
+	sa.journal.touch(idx1)
+	sa.journal.touch(idx2)
 	match1.Round, match2.Round = match2.Round, match1.Round
 	
 	return nil
@@ -52,16 +54,18 @@ func (sa *SimulatedAnnealing) rescheduleMatch(draw *models.Draw) error {
 		return errors.New("no matches to reschedule")
 	}
 	
-	// Find a regular match (not a bye)
+	// Find a regular match (not a bye) that isn't locked
 	var targetMatch *models.Match
+	var targetIdx int
 	maxAttempts := 50
 	
 	for attempts := 0; attempts < maxAttempts; attempts++ {
-		idx := rand.Intn(len(draw.Matches))
+		idx := sa.random().Intn(len(draw.Matches))
 		match := draw.Matches[idx]
 		
-		if !match.IsBye() {
+		if sa.canMove(match) {
 			targetMatch = match
+			targetIdx = idx
 			break
 		}
 	}
@@ -70,15 +74,20 @@ func (sa *SimulatedAnnealing) rescheduleMatch(draw *models.Draw) error {
 		return errors.New("could not find a regular match to reschedule")
 	}
 	
-	// Choose a new round (different from current)
+	// Choose a new round (different from current) inside the window
+	from, to := sa.Window.Bounds(draw.Rounds)
+	if from >= to {
+		return errors.New("round window has no other round to move to")
+	}
 	originalRound := targetMatch.Round
-	newRound := rand.Intn(draw.Rounds) + 1
+	newRound := from + sa.random().Intn(to-from+1)
 	
 	// Ensure it's different from the current round
 	for newRound == originalRound {
-		newRound = rand.Intn(draw.Rounds) + 1
+		newRound = from + sa.random().Intn(to-from+1)
 	}
 	
+	sa.journal.touch(targetIdx)
 	targetMatch.Round = newRound
 	
 	return nil
@@ -88,11 +97,12 @@ func (sa *SimulatedAnnealing) rescheduleMatch(draw *models.Draw) error {
 func (sa *SimulatedAnnealing) swapVenues(draw *models.Draw) error {
 	// Find two matches with venues that can be swapped
 	var match1, match2 *models.Match
+	var idx1, idx2 int
 	maxAttempts := 50
 	
 	for attempts := 0; attempts < maxAttempts; attempts++ {
-		idx1 := rand.Intn(len(draw.Matches))
-		idx2 := rand.Intn(len(draw.Matches))
+		idx1 = sa.random().Intn(len(draw.Matches))
+		idx2 = sa.random().Intn(len(draw.Matches))
 		
 		if idx1 == idx2 {
 			continue
@@ -101,8 +111,8 @@ func (sa *SimulatedAnnealing) swapVenues(draw *models.Draw) error {
 		m1 := draw.Matches[idx1]
 		m2 := draw.Matches[idx2]
 		
-		// Both matches must have venues and not be byes
-		if m1.VenueID != nil && m2.VenueID != nil && !m1.IsBye() && !m2.IsBye() {
+		// Both matches must have venues and be regular, unlocked matches
+		if m1.VenueID != nil && m2.VenueID != nil && sa.canMove(m1) && sa.canMove(m2) {
 			match1 = m1
 			match2 = m2
 			break
@@ -114,6 +124,8 @@ func (sa *SimulatedAnnealing) swapVenues(draw *models.Draw) error {
 	}
 	
 	// Swap the venues
+	sa.journal.touch(idx1)
+	sa.journal.touch(idx2)
 	match1.VenueID, match2.VenueID = match2.VenueID, match1.VenueID
 	
 	return nil
@@ -125,16 +137,18 @@ func (sa *SimulatedAnnealing) swapHomeAway(draw *models.Draw) error {
 		return errors.New("no matches to modify")
 	}
 	
-	// Find a regular match (not a bye)
+	// Find a regular match (not a bye) that isn't locked
 	var targetMatch *models.Match
+	var targetIdx int
 	maxAttempts := 50
 	
 	for attempts := 0; attempts < maxAttempts; attempts++ {
-		idx := rand.Intn(len(draw.Matches))
+		idx := sa.random().Intn(len(draw.Matches))
 		match := draw.Matches[idx]
 		
-		if !match.IsBye() && match.HomeTeamID != nil && match.AwayTeamID != nil {
+		if sa.canMove(match) && match.HomeTeamID != nil && match.AwayTeamID != nil {
 			targetMatch = match
+			targetIdx = idx
 			break
 		}
 	}
@@ -144,11 +158,18 @@ func (sa *SimulatedAnnealing) swapHomeAway(draw *models.Draw) error {
 	}
 	
 	// Swap home and away teams
+	sa.journal.touch(targetIdx)
 	targetMatch.HomeTeamID, targetMatch.AwayTeamID = targetMatch.AwayTeamID, targetMatch.HomeTeamID
 	
 	return nil
 }
 
+// canMove reports whether operations may change a match: it must be a regular,
+// unlocked match inside the optimizer's round window
+func (sa *SimulatedAnnealing) canMove(match *models.Match) bool {
+	return match.IsMovable() && sa.Window.Contains(match.Round)
+}
+
 // validateOperation checks if an operation maintains draw consistency
 func (sa *SimulatedAnnealing) validateOperation(draw *models.Draw) error {
 	// Check that all matches are still valid
@@ -177,7 +198,7 @@ func (sa *SimulatedAnnealing) applyMultipleOperations(draw *models.Draw, count i
 	}
 	
 	for i := 0; i < count; i++ {
-		operation := operations[rand.Intn(len(operations))]
+		operation := operations[sa.random().Intn(len(operations))]
 		if err := operation(draw); err != nil {
 			// If operation fails, continue with next one
 			continue
@@ -187,21 +208,13 @@ func (sa *SimulatedAnnealing) applyMultipleOperations(draw *models.Draw, count i
 	return nil
 }
 
-// revertLastOperation provides a mechanism to undo the last operation
-// This is useful for more sophisticated optimization strategies
-func (sa *SimulatedAnnealing) revertLastOperation(originalDraw, modifiedDraw *models.Draw) {
-	// This is a simple implementation that just copies the original back
-	// In a more sophisticated implementation, we might track specific operations
-	*modifiedDraw = *sa.copyDraw(originalDraw)
-}
-
 // getRandomMatch returns a random match from the draw
 func (sa *SimulatedAnnealing) getRandomMatch(draw *models.Draw) (*models.Match, error) {
 	if len(draw.Matches) == 0 {
 		return nil, errors.New("no matches available")
 	}
 	
-	idx := rand.Intn(len(draw.Matches))
+	idx := sa.random().Intn(len(draw.Matches))
 	return draw.Matches[idx], nil
 }
 
@@ -219,7 +232,7 @@ func (sa *SimulatedAnnealing) getRandomRegularMatch(draw *models.Draw) (*models.
 		return nil, errors.New("no regular matches available")
 	}
 	
-	idx := rand.Intn(len(regularMatches))
+	idx := sa.random().Intn(len(regularMatches))
 	return regularMatches[idx], nil
 }
 
@@ -165,6 +165,25 @@ func TestSwapHomeAway(t *testing.T) {
 	}
 }
 
+func TestOperations_SkipLockedMatches(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	sa := NewSimulatedAnnealing(100.0, 0.99, 100, engine)
+
+	draw := createTestDraw()
+	locked := draw.Matches[0]
+	locked.Locked = true
+	round, venue, home := locked.Round, *locked.VenueID, *locked.HomeTeamID
+
+	for i := 0; i < 200; i++ {
+		sa.applyMultipleOperations(draw, 4)
+	}
+
+	if locked.Round != round || *locked.VenueID != venue || *locked.HomeTeamID != home {
+		t.Errorf("Expected locked match to stay in round %d at venue %d with home team %d, got round %d, venue %d, home team %d",
+			round, venue, home, locked.Round, *locked.VenueID, *locked.HomeTeamID)
+	}
+}
+
 func TestGetRandomMatch(t *testing.T) {
 	engine := constraints.NewConstraintEngine()
 	sa := NewSimulatedAnnealing(100.0, 0.99, 100, engine)
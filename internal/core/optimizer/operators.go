@@ -0,0 +1,169 @@
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Neighbourhood operators the annealer picks from to change a draw
+const (
+	OperatorSwapMatches     = "swap_matches"
+	OperatorRescheduleMatch = "reschedule_match"
+	OperatorSwapVenues      = "swap_venues"
+	OperatorSwapHomeAway    = "swap_home_away"
+)
+
+// operatorNames lists the operators in the order they are picked from
+var operatorNames = []string{
+	OperatorSwapMatches,
+	OperatorRescheduleMatch,
+	OperatorSwapVenues,
+	OperatorSwapHomeAway,
+}
+
+// operatorWeightTolerance is how far operator weights may sum from 1
+const operatorWeightTolerance = 1e-6
+
+// ErrInvalidOperatorWeights is returned when operator weights are unknown,
+// negative or don't sum to 1
+var ErrInvalidOperatorWeights = errors.New("invalid operator weights")
+
+// OperatorWeights is the probability of each neighbourhood operator being
+// picked, keyed by operator name, e.g. {"swap_matches": 0.4, ...}. Operators
+// left out are never picked. Empty weights pick every operator equally.
+type OperatorWeights map[string]float64
+
+// Validate checks every weight names an operator, none is negative, and the
+// weights sum to 1
+func (w OperatorWeights) Validate() error {
+	if len(w) == 0 {
+		return nil
+	}
+
+	total := 0.0
+	for name, weight := range w {
+		if !isOperator(name) {
+			return fmt.Errorf("%w: unknown operator %q, expected one of %s",
+				ErrInvalidOperatorWeights, name, strings.Join(operatorNames, ", "))
+		}
+		if weight < 0 || math.IsNaN(weight) {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidOperatorWeights, name)
+		}
+		total += weight
+	}
+	if math.Abs(total-1) > operatorWeightTolerance {
+		return fmt.Errorf("%w: weights sum to %g, not 1", ErrInvalidOperatorWeights, total)
+	}
+	return nil
+}
+
+// isOperator reports whether name is a neighbourhood operator
+func isOperator(name string) bool {
+	for _, operator := range operatorNames {
+		if operator == name {
+			return true
+		}
+	}
+	return false
+}
+
+// OperatorStats counts how one neighbourhood operator fared over a run
+type OperatorStats struct {
+	// Attempts is how many times the operator was picked
+	Attempts int `json:"attempts"`
+	// Failures is how many attempts couldn't produce a neighbour, e.g. when
+	// every match the operator could change is locked
+	Failures     int `json:"failures"`
+	Accepted     int `json:"accepted"`
+	Improvements int `json:"improvements"`
+	// AcceptanceRate is the share of attempts that were accepted
+	AcceptanceRate float64 `json:"acceptance_rate"`
+}
+
+// operatorTally accumulates per-operator stats during a run
+type operatorTally map[string]OperatorStats
+
+// record counts one attempt of an operator and its outcome
+func (t operatorTally) record(operator string, failed, accepted, improved bool) {
+	stats := t[operator]
+	stats.Attempts++
+	if failed {
+		stats.Failures++
+	}
+	if accepted {
+		stats.Accepted++
+	}
+	if improved {
+		stats.Improvements++
+	}
+	t[operator] = stats
+}
+
+// add folds another run's stats into the tally
+func (t operatorTally) add(other map[string]OperatorStats) {
+	for operator, stats := range other {
+		total := t[operator]
+		total.Attempts += stats.Attempts
+		total.Failures += stats.Failures
+		total.Accepted += stats.Accepted
+		total.Improvements += stats.Improvements
+		t[operator] = total
+	}
+}
+
+// stats returns a copy of the tally with acceptance rates filled in
+func (t operatorTally) stats() map[string]OperatorStats {
+	stats := make(map[string]OperatorStats, len(t))
+	for operator, tally := range t {
+		if tally.Attempts > 0 {
+			tally.AcceptanceRate = float64(tally.Accepted) / float64(tally.Attempts)
+		}
+		stats[operator] = tally
+	}
+	return stats
+}
+
+// applyOperator changes the draw with the named operator
+func (sa *SimulatedAnnealing) applyOperator(operator string, draw *models.Draw) error {
+	switch operator {
+	case OperatorSwapMatches:
+		return sa.swapMatches(draw)
+	case OperatorRescheduleMatch:
+		return sa.rescheduleMatch(draw)
+	case OperatorSwapVenues:
+		return sa.swapVenues(draw)
+	case OperatorSwapHomeAway:
+		return sa.swapHomeAway(draw)
+	default:
+		return fmt.Errorf("unknown operator %q", operator)
+	}
+}
+
+// chooseOperator picks an operator by the configured weights, or uniformly
+// when none are set
+func (sa *SimulatedAnnealing) chooseOperator() string {
+	if len(sa.OperatorWeights) == 0 {
+		return operatorNames[sa.random().Intn(len(operatorNames))]
+	}
+
+	target := sa.random().Float64()
+	cumulative := 0.0
+	last := ""
+	for _, name := range operatorNames {
+		weight := sa.OperatorWeights[name]
+		if weight <= 0 {
+			continue
+		}
+		cumulative += weight
+		last = name
+		if target < cumulative {
+			return name
+		}
+	}
+	// Rounding can leave the weights just short of 1
+	return last
+}
@@ -0,0 +1,187 @@
+package optimizer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DefaultArchiveSize is how many trade-offs a multi-objective run keeps when
+// it doesn't set its own archive size
+const DefaultArchiveSize = 20
+
+// MaxArchiveSize caps how many trade-offs a multi-objective run may keep
+const MaxArchiveSize = 200
+
+// ParetoSolution is a draw no other archived draw beats on every objective
+type ParetoSolution struct {
+	// Objectives scores the draw on each objective, from 0 to 1
+	Objectives map[constraints.Objective]float64 `json:"objectives"`
+	// Score is the draw's weighted score across all soft constraints
+	Score     float64      `json:"score"`
+	Iteration int          `json:"iteration"`
+	Draw      *models.Draw `json:"draw"`
+}
+
+// ParetoArchive keeps the feasible draws found so far that no other draw
+// dominates, that is scores at least as well on every objective and better on
+// one. Once full, the draw in the most crowded part of the front is dropped,
+// so the archive stays spread across the trade-offs.
+type ParetoArchive struct {
+	objectives []constraints.Objective
+	capacity   int
+	entries    []archiveEntry
+}
+
+// archiveEntry is an archived solution with its scores in objective order
+type archiveEntry struct {
+	scores   []float64
+	solution ParetoSolution
+}
+
+// NewParetoArchive creates an archive over the objectives holding at most
+// capacity solutions. A non-positive capacity uses DefaultArchiveSize.
+func NewParetoArchive(objectives []constraints.Objective, capacity int) *ParetoArchive {
+	if capacity <= 0 {
+		capacity = DefaultArchiveSize
+	}
+	return &ParetoArchive{
+		objectives: objectives,
+		capacity:   capacity,
+	}
+}
+
+// Admits reports whether a draw with these objective scores would join the
+// archive: no archived draw dominates or matches it
+func (pa *ParetoArchive) Admits(scores []float64) bool {
+	for _, entry := range pa.entries {
+		if scoresDominate(entry.scores, scores) || equalScores(entry.scores, scores) {
+			return false
+		}
+	}
+	return true
+}
+
+// Add archives a draw with these objective scores if the archive admits it,
+// dropping the draws it dominates. The draw is kept as given, so callers
+// pass a copy. Reports whether the draw is in the archive afterwards.
+func (pa *ParetoArchive) Add(scores []float64, score float64, iteration int, draw *models.Draw) bool {
+	if !pa.Admits(scores) {
+		return false
+	}
+
+	kept := pa.entries[:0]
+	for _, entry := range pa.entries {
+		if !scoresDominate(scores, entry.scores) {
+			kept = append(kept, entry)
+		}
+	}
+
+	solution := ParetoSolution{
+		Objectives: make(map[constraints.Objective]float64, len(pa.objectives)),
+		Score:      score,
+		Iteration:  iteration,
+		Draw:       draw,
+	}
+	for i, objective := range pa.objectives {
+		solution.Objectives[objective] = scores[i]
+	}
+	pa.entries = append(kept, archiveEntry{scores: scores, solution: solution})
+
+	if len(pa.entries) > pa.capacity {
+		evicted := pa.mostCrowded()
+		pa.entries = append(pa.entries[:evicted], pa.entries[evicted+1:]...)
+		return evicted != len(pa.entries)
+	}
+	return true
+}
+
+// AddSolution archives a solution from another archive over the same objectives
+func (pa *ParetoArchive) AddSolution(solution ParetoSolution) bool {
+	scores := make([]float64, len(pa.objectives))
+	for i, objective := range pa.objectives {
+		scores[i] = solution.Objectives[objective]
+	}
+	return pa.Add(scores, solution.Score, solution.Iteration, solution.Draw)
+}
+
+// Len returns how many solutions the archive holds
+func (pa *ParetoArchive) Len() int {
+	return len(pa.entries)
+}
+
+// Solutions returns the archived solutions, best weighted score first
+func (pa *ParetoArchive) Solutions() []ParetoSolution {
+	solutions := make([]ParetoSolution, len(pa.entries))
+	for i, entry := range pa.entries {
+		solutions[i] = entry.solution
+	}
+	sort.SliceStable(solutions, func(i, j int) bool {
+		return solutions[i].Score > solutions[j].Score
+	})
+	return solutions
+}
+
+// mostCrowded returns the index of the entry with the smallest crowding
+// distance: the sum, over objectives, of the gap between its neighbours on
+// either side. The best and worst on any objective are never the most
+// crowded. Ties go to the latest entry, so newcomers don't displace incumbents.
+func (pa *ParetoArchive) mostCrowded() int {
+	distances := make([]float64, len(pa.entries))
+	order := make([]int, len(pa.entries))
+	for objective := range pa.objectives {
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return pa.entries[order[i]].scores[objective] < pa.entries[order[j]].scores[objective]
+		})
+
+		low := pa.entries[order[0]].scores[objective]
+		high := pa.entries[order[len(order)-1]].scores[objective]
+		distances[order[0]] = math.Inf(1)
+		distances[order[len(order)-1]] = math.Inf(1)
+		if high == low {
+			continue
+		}
+		for i := 1; i < len(order)-1; i++ {
+			gap := pa.entries[order[i+1]].scores[objective] - pa.entries[order[i-1]].scores[objective]
+			distances[order[i]] += gap / (high - low)
+		}
+	}
+
+	crowded := len(distances) - 1
+	for i := len(distances) - 1; i >= 0; i-- {
+		if distances[i] < distances[crowded] {
+			crowded = i
+		}
+	}
+	return crowded
+}
+
+// scoresDominate reports whether a scores at least as well as b on every
+// objective and better on at least one
+func scoresDominate(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// equalScores reports whether two draws score the same on every objective
+func equalScores(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
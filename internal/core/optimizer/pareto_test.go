@@ -0,0 +1,58 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+)
+
+func TestParetoArchive(t *testing.T) {
+	objectives := []constraints.Objective{constraints.ObjectiveTravel, constraints.ObjectiveFairness}
+	archive := NewParetoArchive(objectives, 3)
+
+	if !archive.Add([]float64{0.5, 0.5}, 0.5, 1, createTestDraw()) {
+		t.Fatal("Expected the first draw to be archived")
+	}
+	if archive.Add([]float64{0.4, 0.5}, 0.45, 2, createTestDraw()) {
+		t.Error("Expected a dominated draw to be turned away")
+	}
+	if archive.Add([]float64{0.5, 0.5}, 0.5, 3, createTestDraw()) {
+		t.Error("Expected a draw matching an archived one to be turned away")
+	}
+
+	// Trade-offs join the front, and a draw beating one drops it
+	archive.Add([]float64{0.9, 0.2}, 0.55, 4, createTestDraw())
+	archive.Add([]float64{0.2, 0.9}, 0.55, 5, createTestDraw())
+	if !archive.Add([]float64{0.6, 0.6}, 0.6, 6, createTestDraw()) {
+		t.Fatal("Expected a dominating draw to be archived")
+	}
+	if archive.Len() != 3 {
+		t.Fatalf("Expected the dominated draw to be dropped, got %d solutions", archive.Len())
+	}
+
+	solutions := archive.Solutions()
+	if solutions[0].Iteration != 6 || solutions[0].Objectives[constraints.ObjectiveTravel] != 0.6 {
+		t.Errorf("Expected the best weighted score first, got %+v", solutions[0])
+	}
+	for i, a := range solutions {
+		for j, b := range solutions {
+			if i != j && a.Objectives[constraints.ObjectiveTravel] >= b.Objectives[constraints.ObjectiveTravel] &&
+				a.Objectives[constraints.ObjectiveFairness] >= b.Objectives[constraints.ObjectiveFairness] {
+				t.Errorf("Expected no archived draw to dominate another, %+v dominates %+v", a.Objectives, b.Objectives)
+			}
+		}
+	}
+
+	// Once full, the most crowded draw goes and the extremes stay
+	archive.Add([]float64{0.7, 0.4}, 0.55, 7, createTestDraw())
+	if archive.Len() != 3 {
+		t.Fatalf("Expected the archive to stay at capacity, got %d solutions", archive.Len())
+	}
+	kept := make(map[int]bool)
+	for _, solution := range archive.Solutions() {
+		kept[solution.Iteration] = true
+	}
+	if !kept[4] || !kept[5] {
+		t.Errorf("Expected the extremes of the front to be kept, got %v", kept)
+	}
+}
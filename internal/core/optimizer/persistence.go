@@ -0,0 +1,179 @@
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// DefaultProgressPersistInterval is the minimum time between stored progress
+// snapshots of a running job. Status changes are always stored immediately.
+const DefaultProgressPersistInterval = 2 * time.Second
+
+// OrphanedJobError is recorded on jobs that were still pending or running
+// when the server stopped
+const OrphanedJobError = "job was interrupted by a server restart"
+
+// ShutdownJobError is recorded on jobs cancelled by a graceful shutdown. Those
+// that saved a checkpoint can be resumed.
+const ShutdownJobError = "job was interrupted by a server shutdown"
+
+// JobRecoveryReport summarises the jobs loaded back into memory on startup
+type JobRecoveryReport struct {
+	Restored    int       `json:"restored"`
+	Orphaned    int       `json:"orphaned"`
+	RecoveredAt time.Time `json:"recovered_at"`
+}
+
+// SetStore persists jobs to the given repository as they change
+func (jm *JobManager) SetStore(store storage.OptimizationJobRepository) {
+	jm.store = store
+}
+
+// persistJob stores the current state of a job. Unless force is set, the
+// write is skipped when the job was stored within the progress interval.
+func (jm *JobManager) persistJob(jobID string, force bool) {
+	if jm.store == nil {
+		return
+	}
+
+	jm.persistMutex.Lock()
+	defer jm.persistMutex.Unlock()
+
+	if !force && time.Since(jm.persistedAt[jobID]) < jm.progressPersistInterval {
+		return
+	}
+
+	// Jobs removed from memory have already been dropped from the store
+	job, err := jm.GetJobSnapshot(jobID)
+	if err != nil {
+		return
+	}
+
+	record, err := jobRecord(job)
+	if err != nil {
+		log.Printf("Error persisting optimization job %s: %v", jobID, err)
+		return
+	}
+	if err := jm.store.Save(context.Background(), record); err != nil {
+		log.Printf("Error persisting optimization job %s: %v", jobID, err)
+		return
+	}
+	jm.persistedAt[jobID] = time.Now()
+}
+
+// unpersistJob drops a job from the store
+func (jm *JobManager) unpersistJob(jobID string) {
+	if jm.store == nil {
+		return
+	}
+
+	delete(jm.persistedAt, jobID)
+	if err := jm.store.Delete(context.Background(), jobID); err != nil {
+		log.Printf("Error deleting persisted optimization job %s: %v", jobID, err)
+	}
+	jm.deleteCheckpoint(jobID)
+}
+
+// RecoverJobs loads persisted jobs back into memory after a restart. Finished
+// jobs are restored as they were; jobs that were still pending or running are
+// marked failed and their draws are returned to draft.
+func (s *Service) RecoverJobs(ctx context.Context) (*JobRecoveryReport, error) {
+	now := time.Now()
+	report := &JobRecoveryReport{RecoveredAt: now}
+
+	records, err := s.repository.OptimizationJobs().List(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to load optimization jobs: %w", err)
+	}
+
+	for _, record := range records {
+		job, err := jobFromRecord(record)
+		if err != nil {
+			return report, fmt.Errorf("failed to recover job %s: %w", record.JobID, err)
+		}
+
+		orphaned := job.Status == JobStatusPending || job.Status == JobStatusRunning
+		if orphaned {
+			job.Status = JobStatusFailed
+			job.Error = OrphanedJobError
+			completedAt := now
+			job.CompletedAt = &completedAt
+		}
+
+		s.jobManager.restoreJob(job)
+		if !orphaned {
+			report.Restored++
+			continue
+		}
+
+		s.jobManager.persistJob(job.ID, true)
+		draw, err := s.repository.Draws().Get(ctx, job.DrawID)
+		if err == nil && draw.Status == models.DrawStatusOptimizing {
+			draw.Status = models.DrawStatusDraft
+			if err := s.repository.Draws().Update(ctx, draw); err != nil {
+				return report, fmt.Errorf("failed to reset draw %d: %w", draw.ID, err)
+			}
+		}
+		report.Orphaned++
+	}
+
+	return report, nil
+}
+
+// jobRecord converts a job into its persisted form
+func jobRecord(job OptimizationJob) (*models.OptimizationJobRecord, error) {
+	progress, err := json.Marshal(job.Progress)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.OptimizationJobRecord{
+		JobID:       job.ID,
+		DrawID:      job.DrawID,
+		Status:      string(job.Status),
+		Progress:    progress,
+		Error:       job.Error,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+	}
+	if job.Result != nil {
+		if record.Result, err = json.Marshal(job.Result); err != nil {
+			return nil, err
+		}
+	}
+
+	return record, record.Validate()
+}
+
+// jobFromRecord rebuilds a job from its persisted form
+func jobFromRecord(record *models.OptimizationJobRecord) (*OptimizationJob, error) {
+	job := &OptimizationJob{
+		ID:          record.JobID,
+		DrawID:      record.DrawID,
+		Status:      JobStatus(record.Status),
+		Error:       record.Error,
+		StartedAt:   record.StartedAt,
+		CompletedAt: record.CompletedAt,
+		CancelFunc:  func() {},
+	}
+
+	if len(record.Progress) > 0 {
+		if err := json.Unmarshal(record.Progress, &job.Progress); err != nil {
+			return nil, err
+		}
+	}
+	if len(record.Result) > 0 {
+		job.Result = &OptimizationResult{}
+		if err := json.Unmarshal(record.Result, job.Result); err != nil {
+			return nil, err
+		}
+	}
+
+	return job, nil
+}
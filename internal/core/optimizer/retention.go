@@ -0,0 +1,270 @@
+package optimizer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// DefaultRetentionInterval is how often RunRetention applies the retention policy
+const DefaultRetentionInterval = 15 * time.Minute
+
+// ErrArchivePayloadDiscarded is returned when restoring a job whose archived
+// payload has already been dropped by the retention policy
+var ErrArchivePayloadDiscarded = errors.New("archived job payload has been discarded by the retention policy")
+
+// RetentionPolicy controls how long finished optimization jobs are kept.
+// Finished jobs are compressed into the archive store after ArchiveAfter, and
+// archived payloads are discarded after PayloadTTL. The archive row itself,
+// with the job's scores, is kept so job history stays complete.
+type RetentionPolicy struct {
+	// ArchiveAfter is how long a finished job stays in memory
+	ArchiveAfter time.Duration
+	// PayloadTTL is how long archived payloads are kept after the job finished.
+	// Zero keeps payloads forever.
+	PayloadTTL time.Duration
+	// KeepBestPerDraw exempts each draw's highest-scoring completed job from PayloadTTL
+	KeepBestPerDraw bool
+}
+
+// DefaultRetentionPolicy returns the default retention policy: finished jobs
+// are archived after an hour and only each draw's best result outlives 30 days
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		ArchiveAfter:    time.Hour,
+		PayloadTTL:      30 * 24 * time.Hour,
+		KeepBestPerDraw: true,
+	}
+}
+
+// Validate ensures the retention policy has valid durations
+func (p RetentionPolicy) Validate() error {
+	if p.ArchiveAfter < 0 {
+		return errors.New("archive_after cannot be negative")
+	}
+	if p.PayloadTTL < 0 {
+		return errors.New("payload_ttl cannot be negative")
+	}
+	return nil
+}
+
+// RetentionReport summarises one pass of the retention policy
+type RetentionReport struct {
+	Archived          int       `json:"archived"`
+	PayloadsDiscarded int       `json:"payloads_discarded"`
+	BestResults       int       `json:"best_results"`
+	AppliedAt         time.Time `json:"applied_at"`
+}
+
+// GetRetentionPolicy returns the current retention policy
+func (s *Service) GetRetentionPolicy() RetentionPolicy {
+	s.retentionMutex.RLock()
+	defer s.retentionMutex.RUnlock()
+	return s.retention
+}
+
+// SetRetentionPolicy replaces the retention policy
+func (s *Service) SetRetentionPolicy(policy RetentionPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	s.retentionMutex.Lock()
+	defer s.retentionMutex.Unlock()
+	s.retention = policy
+	return nil
+}
+
+// RunRetention applies the retention policy every interval until the context is cancelled
+func (s *Service) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ApplyRetention(ctx); err != nil {
+				log.Printf("Error applying optimization job retention: %v", err)
+			}
+		}
+	}
+}
+
+// ApplyRetention archives finished jobs that have been in memory longer than
+// the policy allows, marks each draw's best result and discards expired payloads
+func (s *Service) ApplyRetention(ctx context.Context) (*RetentionReport, error) {
+	policy := s.GetRetentionPolicy()
+	now := time.Now()
+	report := &RetentionReport{AppliedAt: now}
+
+	archives := s.repository.JobArchives()
+	for _, job := range s.jobManager.finishedBefore(now.Add(-policy.ArchiveAfter)) {
+		// Restored jobs are already archived and only need to leave memory again
+		if _, err := archives.GetByJobID(ctx, job.ID); err == nil {
+			s.jobManager.removeJob(job.ID)
+			continue
+		}
+
+		archive, err := archiveJob(job)
+		if err != nil {
+			return report, fmt.Errorf("failed to archive job %s: %w", job.ID, err)
+		}
+		if err := archives.Create(ctx, archive); err != nil {
+			return report, fmt.Errorf("failed to archive job %s: %w", job.ID, err)
+		}
+		s.jobManager.removeJob(job.ID)
+		report.Archived++
+	}
+
+	best, err := s.markBestResults(ctx, policy.KeepBestPerDraw)
+	if err != nil {
+		return report, err
+	}
+	report.BestResults = best
+
+	if policy.PayloadTTL > 0 {
+		discarded, err := archives.DiscardPayloads(ctx, now.Add(-policy.PayloadTTL), now)
+		if err != nil {
+			return report, err
+		}
+		report.PayloadsDiscarded = discarded
+	}
+
+	return report, nil
+}
+
+// markBestResults flags the highest-scoring restorable completed job of every
+// archived draw, or clears the flags when best results aren't being kept
+func (s *Service) markBestResults(ctx context.Context, keepBest bool) (int, error) {
+	archives, err := s.repository.JobArchives().List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	best := make(map[int]*models.JobArchive)
+	for _, archive := range archives {
+		if _, seen := best[archive.DrawID]; !seen {
+			best[archive.DrawID] = nil
+		}
+		if !keepBest || archive.Status != string(JobStatusCompleted) || !archive.HasPayload() {
+			continue
+		}
+		if current := best[archive.DrawID]; current == nil || archive.FinalScore > current.FinalScore {
+			best[archive.DrawID] = archive
+		}
+	}
+
+	marked := 0
+	for drawID, archive := range best {
+		jobID := ""
+		if archive != nil {
+			jobID = archive.JobID
+			marked++
+		}
+		if err := s.repository.JobArchives().SetBest(ctx, drawID, jobID); err != nil {
+			return 0, err
+		}
+	}
+
+	return marked, nil
+}
+
+// ListArchivedJobs returns archived jobs, optionally filtered by draw ID
+func (s *Service) ListArchivedJobs(ctx context.Context, drawID int) ([]*models.JobArchive, error) {
+	if drawID > 0 {
+		return s.repository.JobArchives().ListByDraw(ctx, drawID)
+	}
+	return s.repository.JobArchives().List(ctx)
+}
+
+// RestoreJob loads an archived job back into memory so its result can be
+// fetched and applied again. Jobs still in memory are returned as they are.
+func (s *Service) RestoreJob(ctx context.Context, jobID string) (*OptimizationJob, error) {
+	if job, err := s.jobManager.GetJob(jobID); err == nil {
+		return job, nil
+	}
+
+	archive, err := s.repository.JobArchives().GetByJobID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !archive.HasPayload() {
+		return nil, ErrArchivePayloadDiscarded
+	}
+
+	job, err := unarchiveJob(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore job %s: %w", jobID, err)
+	}
+
+	restoredAt := time.Now()
+	job.RestoredAt = &restoredAt
+	return s.jobManager.restoreJob(job), nil
+}
+
+// archiveJob serializes and compresses a finished job
+func archiveJob(job *OptimizationJob) (*models.JobArchive, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	archive := &models.JobArchive{
+		JobID:       job.ID,
+		DrawID:      job.DrawID,
+		Status:      string(job.Status),
+		Payload:     buf.Bytes(),
+		PayloadSize: len(data),
+		StartedAt:   job.StartedAt,
+		CompletedAt: *job.CompletedAt,
+	}
+	if job.Result != nil {
+		archive.InitialScore = job.Result.InitialScore
+		archive.FinalScore = job.Result.FinalScore
+	}
+
+	return archive, archive.Validate()
+}
+
+// unarchiveJob decompresses an archived job payload
+func unarchiveJob(archive *models.JobArchive) (*OptimizationJob, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive.Payload))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+
+	var job OptimizationJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
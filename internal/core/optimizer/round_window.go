@@ -0,0 +1,61 @@
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidRoundWindow is returned when a round window doesn't fit the draw
+var ErrInvalidRoundWindow = errors.New("invalid round window")
+
+// RoundWindow restricts optimization to rounds From to To inclusive, leaving
+// every other round as it is. The zero value covers the whole draw, and an
+// unset bound is open on that side.
+type RoundWindow struct {
+	From int `json:"from_round,omitempty"`
+	To   int `json:"to_round,omitempty"`
+}
+
+// IsSet reports whether the window restricts the draw at all
+func (w RoundWindow) IsSet() bool {
+	return w.From > 0 || w.To > 0
+}
+
+// Contains reports whether a round falls inside the window
+func (w RoundWindow) Contains(round int) bool {
+	if w.From > 0 && round < w.From {
+		return false
+	}
+	if w.To > 0 && round > w.To {
+		return false
+	}
+	return true
+}
+
+// Bounds returns the first and last round of the window in a draw with the
+// given number of rounds
+func (w RoundWindow) Bounds(rounds int) (int, int) {
+	from, to := 1, rounds
+	if w.From > 0 {
+		from = w.From
+	}
+	if w.To > 0 {
+		to = w.To
+	}
+	return from, to
+}
+
+// Validate checks the window lies within a draw with the given number of rounds
+func (w RoundWindow) Validate(rounds int) error {
+	if w.From < 0 || w.To < 0 {
+		return fmt.Errorf("%w: rounds must be positive", ErrInvalidRoundWindow)
+	}
+	from, to := w.Bounds(rounds)
+	if from > to {
+		return fmt.Errorf("%w: from round %d is after to round %d", ErrInvalidRoundWindow, from, to)
+	}
+	if to > rounds {
+		return fmt.Errorf("%w: draw only has %d rounds", ErrInvalidRoundWindow, rounds)
+	}
+	return nil
+}
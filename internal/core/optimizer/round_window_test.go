@@ -0,0 +1,48 @@
+package optimizer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoundWindow_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  RoundWindow
+		wantErr bool
+	}{
+		{"whole draw", RoundWindow{}, false},
+		{"inner range", RoundWindow{From: 3, To: 5}, false},
+		{"open ended", RoundWindow{From: 20}, false},
+		{"single round", RoundWindow{From: 4, To: 4}, false},
+		{"reversed", RoundWindow{From: 6, To: 2}, true},
+		{"past the last round", RoundWindow{From: 20, To: 30}, true},
+		{"starts after the last round", RoundWindow{From: 30}, true},
+		{"negative", RoundWindow{From: -1, To: 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate(26)
+			if tt.wantErr && !errors.Is(err, ErrInvalidRoundWindow) {
+				t.Errorf("Expected ErrInvalidRoundWindow, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRoundWindow_Contains(t *testing.T) {
+	window := RoundWindow{From: 3, To: 5}
+	for round, want := range map[int]bool{2: false, 3: true, 5: true, 6: false} {
+		if got := window.Contains(round); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", round, got, want)
+		}
+	}
+
+	if !(RoundWindow{}).Contains(1) {
+		t.Error("Expected the zero window to contain every round")
+	}
+}
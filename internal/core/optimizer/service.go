@@ -2,7 +2,10 @@ package optimizer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
@@ -15,6 +18,11 @@ type Service struct {
 	constraintEngine *constraints.ConstraintEngine
 	jobManager       *JobManager
 	broadcaster      *OptimizationBroadcaster
+	retention        RetentionPolicy
+	retentionMutex   sync.RWMutex
+	// startMutex makes checking a draw's lock, marking the draw optimizing
+	// and taking its lock one step, so of two racing starts one is refused
+	startMutex sync.Mutex
 }
 
 // NewService creates a new optimizer service
@@ -27,11 +35,16 @@ func NewService(repository storage.Repositories) *Service {
 	
 	// Create job manager
 	jobManager := NewJobManager(optimizer)
+	jobManager.config = DefaultOptimizationConfig()
+	jobManager.SetStore(repository.OptimizationJobs())
+	jobManager.SetCheckpointStore(repository.OptimizationCheckpoints())
+	jobManager.SetSolutionStore(repository.Solutions())
 	
 	return &Service{
 		repository:       repository,
 		constraintEngine: constraintEngine,
 		jobManager:       jobManager,
+		retention:        DefaultRetentionPolicy(),
 	}
 }
 
@@ -43,59 +56,177 @@ func (s *Service) SetWebSocketHub(wsHub WebSocketBroadcaster) {
 
 // OptimizeDraw starts optimization for a specific draw
 func (s *Service) OptimizeDraw(drawID int, config OptimizationConfig) (string, error) {
+	s.startMutex.Lock()
+	defer s.startMutex.Unlock()
+	
 	// Fetch the draw from storage
 	draw, err := s.repository.Draws().GetWithMatches(context.Background(), drawID)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch draw: %w", err)
 	}
+	if jobID, locked := s.jobManager.DrawLock(drawID); locked {
+		return "", fmt.Errorf("%w: job %s is optimizing draw %d", ErrDrawLocked, jobID, drawID)
+	}
 	
-	// Load constraint configuration if present
-	if err := s.loadConstraintConfig(draw); err != nil {
-		return "", fmt.Errorf("failed to load constraint config: %w", err)
+	if err := config.Window.Validate(draw.Rounds); err != nil {
+		return "", err
+	}
+	if err := config.OperatorWeights.Validate(); err != nil {
+		return "", err
+	}
+	// A warm start replaces the whole draw
+	if config.WarmStart && config.Window.IsSet() {
+		return "", fmt.Errorf("%w: warm_start can't be combined with a round window", ErrInvalidRoundWindow)
 	}
 	
-	// Create optimizer with the provided config
-	optimizer := NewSimulatedAnnealing(
-		config.Temperature,
-		config.CoolingRate,
-		config.MaxIterations,
-		s.constraintEngine,
-	)
+	// Fix the stability weight now, so a resumed job scores the draw the same
+	// way even though the draw is no longer published
+	weight := stabilityWeight(draw, config)
+	config.StabilityWeight = &weight
+	start := s.prepareWarmStart(draw, &config)
 	
-	// Set cooling schedule if specified
-	if config.CoolingSchedule.Type != "" {
-		optimizer.CoolingSchedule = CreateCoolingSchedule(config.CoolingSchedule)
+	if err := s.prepareConstraintEngine(draw, config); err != nil {
+		return "", err
+	}
+	if err := s.constraintEngine.CheckObjectives(config.Objectives); err != nil {
+		return "", err
 	}
 	
-	// Update job manager with new optimizer
-	s.jobManager.optimizer = optimizer
+	// Update job manager with an optimizer for the provided config
+	s.jobManager.optimizer = newOptimizerFromConfig(config, s.constraintEngine)
+	s.jobManager.config = config
 	
 	// Mark draw as optimizing
 	draw.Status = models.DrawStatusOptimizing
 	if err := s.repository.Draws().Update(context.Background(), draw); err != nil {
 		return "", fmt.Errorf("failed to update draw status: %w", err)
 	}
+	start.Version = draw.Version
 	
 	// Start optimization job
-	jobID, err := s.jobManager.StartOptimization(drawID, draw)
+	jobID, err := s.jobManager.StartOptimization(drawID, start)
 	if err != nil {
-		// Revert draw status on error
-		draw.Status = models.DrawStatusDraft
-		s.repository.Draws().Update(context.Background(), draw)
+		// Revert draw status on error, unless another job is optimizing it
+		if !errors.Is(err, ErrDrawLocked) {
+			draw.Status = models.DrawStatusDraft
+			s.repository.Draws().Update(context.Background(), draw)
+		}
 		return "", fmt.Errorf("failed to start optimization: %w", err)
 	}
 	
 	return jobID, nil
 }
 
+// ResumeOptimization restarts a failed or cancelled job from its last
+// checkpoint, with the configuration it was started with
+func (s *Service) ResumeOptimization(jobID string) error {
+	s.startMutex.Lock()
+	defer s.startMutex.Unlock()
+	
+	job, err := s.jobManager.GetJobSnapshot(jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != JobStatusFailed && job.Status != JobStatusCancelled {
+		return fmt.Errorf("%w: job is %s", ErrJobNotResumable, job.Status)
+	}
+	if holder, locked := s.jobManager.DrawLock(job.DrawID); locked && holder != jobID {
+		return fmt.Errorf("%w: job %s is optimizing draw %d", ErrDrawLocked, holder, job.DrawID)
+	}
+	
+	record, err := s.repository.OptimizationCheckpoints().GetByJobID(context.Background(), jobID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrNoCheckpoint
+		}
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	config, checkpoint, err := checkpointFromRecord(record)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	
+	draw, err := s.repository.Draws().GetWithMatches(context.Background(), job.DrawID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch draw: %w", err)
+	}
+	
+	if err := s.prepareConstraintEngine(draw, config); err != nil {
+		return err
+	}
+	
+	optimizer := newOptimizerFromConfig(config, s.constraintEngine)
+	if _, ok := optimizer.(Checkpointer); !ok {
+		return fmt.Errorf("%w: %s runs can't be resumed", ErrJobNotResumable, config.Algorithm)
+	}
+	
+	draw.Status = models.DrawStatusOptimizing
+	if err := s.repository.Draws().Update(context.Background(), draw); err != nil {
+		return fmt.Errorf("failed to update draw status: %w", err)
+	}
+	
+	if err := s.jobManager.ResumeOptimization(jobID, draw, optimizer, config, checkpoint); err != nil {
+		if !errors.Is(err, ErrDrawLocked) {
+			draw.Status = models.DrawStatusDraft
+			s.repository.Draws().Update(context.Background(), draw)
+		}
+		return err
+	}
+	
+	return nil
+}
+
+// prepareConstraintEngine loads the draw's constraints, adding the schedule
+// stability constraint when the config weights it
+func (s *Service) prepareConstraintEngine(draw *models.Draw, config OptimizationConfig) error {
+	if err := s.loadConstraintConfig(draw); err != nil {
+		return fmt.Errorf("failed to load constraint config: %w", err)
+	}
+	
+	// Prefer minimal-disruption fixes when re-optimizing a published draw
+	if weight := stabilityWeight(draw, config); weight > 0 {
+		s.constraintEngine.AddSoftConstraint(constraints.NewScheduleStabilityConstraint(draw), weight)
+	}
+	
+	return nil
+}
+
+// stabilityWeight returns the schedule stability weight to use for a draw
+func stabilityWeight(draw *models.Draw, config OptimizationConfig) float64 {
+	if config.StabilityWeight != nil {
+		return *config.StabilityWeight
+	}
+	if draw.Status == models.DrawStatusCompleted {
+		return DefaultStabilityWeight
+	}
+	return 0
+}
+
+// DrawLock returns the optimization job holding a draw's lock, and whether
+// one does. Nothing else may change a locked draw.
+func (s *Service) DrawLock(drawID int) (string, bool) {
+	return s.jobManager.DrawLock(drawID)
+}
+
 // GetOptimizationJob returns information about an optimization job
 func (s *Service) GetOptimizationJob(jobID string) (*OptimizationJob, error) {
 	return s.jobManager.GetJob(jobID)
 }
 
+// GetOptimizationJobSnapshot returns a copy of a job that stays consistent
+// while the job keeps running
+func (s *Service) GetOptimizationJobSnapshot(jobID string) (OptimizationJob, error) {
+	return s.jobManager.GetJobSnapshot(jobID)
+}
+
+// GetOptimizationHistory returns the sampled score trajectory of a job
+func (s *Service) GetOptimizationHistory(jobID string) (*JobHistory, error) {
+	return s.jobManager.GetJobHistory(jobID)
+}
+
 // CancelOptimization cancels a running optimization job
 func (s *Service) CancelOptimization(jobID string) error {
-	job, err := s.jobManager.GetJob(jobID)
+	job, err := s.jobManager.GetJobSnapshot(jobID)
 	if err != nil {
 		return err
 	}
@@ -117,9 +248,28 @@ func (s *Service) CancelOptimization(jobID string) error {
 	return nil
 }
 
+// Shutdown cancels running optimization jobs, letting them checkpoint, and
+// returns their draws to draft. It waits for the jobs to stop until ctx is done.
+func (s *Service) Shutdown(ctx context.Context) error {
+	interrupted, err := s.jobManager.Shutdown(ctx)
+	
+	for _, job := range interrupted {
+		draw, getErr := s.repository.Draws().Get(context.Background(), job.DrawID)
+		if getErr != nil || draw.Status != models.DrawStatusOptimizing {
+			continue
+		}
+		draw.Status = models.DrawStatusDraft
+		if updateErr := s.repository.Draws().Update(context.Background(), draw); updateErr != nil && err == nil {
+			err = fmt.Errorf("failed to reset draw %d: %w", draw.ID, updateErr)
+		}
+	}
+	
+	return err
+}
+
 // GetOptimizationResult returns the result of a completed optimization
 func (s *Service) GetOptimizationResult(jobID string) (*OptimizationResult, error) {
-	job, err := s.jobManager.GetJob(jobID)
+	job, err := s.jobManager.GetJobSnapshot(jobID)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +287,7 @@ func (s *Service) GetOptimizationResult(jobID string) (*OptimizationResult, erro
 
 // ApplyOptimizationResult applies the optimized draw to storage
 func (s *Service) ApplyOptimizationResult(jobID string) error {
-	job, err := s.jobManager.GetJob(jobID)
+	job, err := s.jobManager.GetJobSnapshot(jobID)
 	if err != nil {
 		return err
 	}
@@ -146,21 +296,70 @@ func (s *Service) ApplyOptimizationResult(jobID string) error {
 		return fmt.Errorf("optimization job not completed or result not available")
 	}
 	
-	// Update draw with optimized matches
-	optimizedDraw := job.Result.BestDraw
+	// Update draw with optimized matches, all or nothing
+	ctx := context.Background()
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	// The result replaces the draw as the job found it. Any change since,
+	// including edits to its matches, moved the draw on a version. Work on a
+	// copy so a failed apply leaves the job's result as it was.
+	optimizedDraw := *job.Result.BestDraw
+	current, err := tx.Draws().Get(ctx, optimizedDraw.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch draw: %w", err)
+	}
+	if current.Version != optimizedDraw.Version {
+		return fmt.Errorf("%w: draw %d is at version %d, the result was optimized from version %d",
+			storage.ErrVersionConflict, current.ID, current.Version, optimizedDraw.Version)
+	}
 	optimizedDraw.Status = models.DrawStatusCompleted
 	
-	if err := s.repository.Draws().Update(context.Background(), optimizedDraw); err != nil {
+	if err := tx.Draws().Update(ctx, &optimizedDraw); err != nil {
 		return fmt.Errorf("failed to update draw: %w", err)
 	}
 	
-	// Update all matches
+	stored, err := tx.Matches().ListByDraw(ctx, optimizedDraw.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch matches: %w", err)
+	}
+	storedByID := make(map[int]*models.Match, len(stored))
+	for _, match := range stored {
+		storedByID[match.ID] = match
+	}
+	
+	// Matches locked after the job started, and those outside the rounds a
+	// partial run optimized, keep their stored fixture
+	window := job.Result.Window
+	matches := make([]*models.Match, 0, len(stored))
 	for _, match := range optimizedDraw.Matches {
-		if err := s.repository.Matches().Update(context.Background(), match); err != nil {
-			return fmt.Errorf("failed to update match %d: %w", match.ID, err)
+		if kept, ok := storedByID[match.ID]; ok {
+			delete(storedByID, match.ID)
+			if kept.Locked || !window.Contains(match.Round) {
+				match = kept
+			}
+		}
+		matches = append(matches, match)
+	}
+	// Fixtures the job never saw are kept too
+	for _, match := range stored {
+		if _, unseen := storedByID[match.ID]; unseen {
+			matches = append(matches, match)
 		}
 	}
 	
+	// Moving fixtures between rounds moves the byes with them
+	if err := tx.Matches().ReplaceForDraw(ctx, optimizedDraw.ID, matches); err != nil {
+		return fmt.Errorf("failed to update matches: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit optimization result: %w", err)
+	}
+	
 	return nil
 }
 
@@ -213,38 +412,22 @@ func (s *Service) GetJobStatistics() JobStatistics {
 
 // loadConstraintConfig loads and configures constraints from the draw's configuration
 func (s *Service) loadConstraintConfig(draw *models.Draw) error {
-	if draw.ConstraintConfig == nil {
-		// Use default constraints if none specified
-		return s.loadDefaultConstraints()
-	}
-	
-	// Parse constraint configuration from JSON
-	config, err := constraints.LoadConstraintConfigFromJSON(draw.ConstraintConfig)
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
 	if err != nil {
-		return fmt.Errorf("failed to parse constraint config: %w", err)
+		return err
 	}
 	
-	// Create constraint engine from configuration
-	factory := constraints.NewConstraintFactory()
-	engine, err := factory.CreateConstraintEngine(config)
+	// Travel distances need team and venue coordinates
+	league, err := constraints.LoadLeagueData(context.Background(), s.repository.Teams(), s.repository.Venues())
 	if err != nil {
-		return fmt.Errorf("failed to create constraint engine: %w", err)
+		return fmt.Errorf("failed to load teams and venues: %w", err)
 	}
-	
-	s.constraintEngine = engine
-	return nil
-}
-
-// loadDefaultConstraints loads a default set of NRL constraints
-func (s *Service) loadDefaultConstraints() error {
-	// Get default NRL constraint configuration
-	config := constraints.GetDefaultNRLConstraintConfig()
-	
-	// Create constraint engine from configuration
-	factory := constraints.NewConstraintFactory()
-	engine, err := factory.CreateConstraintEngine(config)
-	if err != nil {
-		return fmt.Errorf("failed to create default constraint engine: %w", err)
+	engine.SetLeagueData(league)
+	if err := engine.LoadOtherDraws(context.Background(), s.repository.Draws()); err != nil {
+		return err
+	}
+	if err := engine.LoadCalendar(context.Background(), s.repository.SeasonCalendars(), draw); err != nil {
+		return err
 	}
 	
 	s.constraintEngine = engine
@@ -263,16 +446,54 @@ func (s *Service) GetJobManager() *JobManager {
 
 // SetOptimizationConfig updates the optimizer configuration
 func (s *Service) SetOptimizationConfig(config OptimizationConfig) {
+	s.startMutex.Lock()
+	defer s.startMutex.Unlock()
+	s.jobManager.optimizer = newOptimizerFromConfig(config, s.constraintEngine)
+	s.jobManager.config = config
+}
+
+// newOptimizerFromConfig creates the optimizer for the given configuration
+func newOptimizerFromConfig(config OptimizationConfig, engine *constraints.ConstraintEngine) Optimizer {
+	if config.Algorithm == AlgorithmTabuSearch {
+		tabu := NewTabuSearch(config.MaxIterations, config.Tabu.Tenure, config.Tabu.NeighborhoodSize, engine)
+		if config.TimeBudgetSeconds > 0 {
+			tabu.TimeBudget = time.Duration(config.TimeBudgetSeconds) * time.Second
+		}
+		tabu.Seed = config.Seed
+		tabu.Window = config.Window
+		tabu.EarlyStopping = config.EarlyStopping
+		return tabu
+	}
+	
 	optimizer := NewSimulatedAnnealing(
 		config.Temperature,
 		config.CoolingRate,
 		config.MaxIterations,
-		s.constraintEngine,
+		engine,
 	)
 	
+	// Set cooling schedule if specified
 	if config.CoolingSchedule.Type != "" {
 		optimizer.CoolingSchedule = CreateCoolingSchedule(config.CoolingSchedule)
 	}
 	
-	s.jobManager.optimizer = optimizer
+	if config.TimeBudgetSeconds > 0 {
+		optimizer.TimeBudget = time.Duration(config.TimeBudgetSeconds) * time.Second
+	}
+	
+	optimizer.Seed = config.Seed
+	optimizer.Window = config.Window
+	optimizer.OperatorWeights = config.OperatorWeights
+	optimizer.EarlyStopping = config.EarlyStopping
+	optimizer.Objectives = config.Objectives
+	optimizer.ArchiveSize = config.ArchiveSize
+	if config.CheckpointInterval > 0 {
+		optimizer.CheckpointInterval = config.CheckpointInterval
+	}
+	
+	if config.MultiStart.Enabled() {
+		return NewMultiStartOptimizer(optimizer, config.MultiStart.Starts, config.MultiStart.Workers)
+	}
+	
+	return optimizer
 }
\ No newline at end of file
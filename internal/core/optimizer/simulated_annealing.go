@@ -1,6 +1,7 @@
 package optimizer
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
@@ -17,8 +18,43 @@ type SimulatedAnnealing struct {
 	MaxIterations    int
 	ConstraintEngine *constraints.ConstraintEngine
 	CoolingSchedule  CoolingSchedule
+	// TimeBudget, when set, runs the annealer for a wall-clock duration instead
+	// of MaxIterations. The cooling schedule is stretched to fit the budget.
+	TimeBudget time.Duration
+	// WorstTeamsLimit is how many struggling teams per soft constraint are
+	// included in progress reports
+	WorstTeamsLimit int
+	// Seed, when set, makes runs reproducible: the same seed, draw and
+	// constraints follow the same trajectory. When nil each run picks a seed.
+	Seed *int64
+	// Window, when set, only changes matches in its rounds; the rest of the
+	// draw stays fixed
+	Window RoundWindow
+	// CheckpointInterval is how many iterations run between checkpoints
+	// saved by OptimizeFrom
+	CheckpointInterval int
+	// OperatorWeights, when set, is the probability of picking each
+	// neighbourhood operator; otherwise they are picked equally
+	OperatorWeights OperatorWeights
+	// EarlyStopping ends the run before MaxIterations or TimeBudget once the
+	// search stalls or reaches a target score
+	EarlyStopping EarlyStopping
+	// Objectives, when set, runs in multi-objective mode: alongside the
+	// weighted search, the run keeps a Pareto archive of feasible draws
+	// trading these objectives off, and always accepts a move into it
+	Objectives []constraints.Objective
+	// ArchiveSize is how many trade-offs a multi-objective run keeps
+	ArchiveSize int
+	
+	rng     *rand.Rand
+	source  *countingSource
+	journal *moveJournal
 }
 
+// DefaultBudgetScheduleIterations is the cooling schedule length used for a
+// time-budgeted run when MaxIterations is not set
+const DefaultBudgetScheduleIterations = 10000
+
 // OptimizationResult contains the results of an optimization run
 type OptimizationResult struct {
 	InitialScore    float64       `json:"initial_score"`
@@ -27,21 +63,61 @@ type OptimizationResult struct {
 	Improvements    int           `json:"improvements"`
 	Duration        time.Duration `json:"duration"`
 	BestDraw        *models.Draw  `json:"best_draw,omitempty"`
+	// Seed reproduces this run when set on an optimizer with the same settings
+	Seed            int64         `json:"seed"`
+	// BestStart and StartScores report each run of a multi-start optimization
+	BestStart       int           `json:"best_start,omitempty"`
+	StartScores     []float64     `json:"start_scores,omitempty"`
+	// Window is the round range the run was restricted to, if any
+	Window          RoundWindow   `json:"window,omitempty"`
+	// OperatorStats counts the attempts, acceptances and improvements of each
+	// neighbourhood operator
+	OperatorStats   map[string]OperatorStats `json:"operator_stats,omitempty"`
+	// ScoreCache counts how often team sub-scores were reused rather than
+	// recomputed after a move
+	ScoreCache      constraints.CacheStats   `json:"score_cache"`
+	// TerminationReason is why the run stopped: "max_iterations",
+	// "time_budget", "no_improvement" or "target_score"
+	TerminationReason string                 `json:"termination_reason"`
+	// WarmStartSolutionID is the solution library entry the run started
+	// from, if it was warm started
+	WarmStartSolutionID int                  `json:"warm_start_solution_id,omitempty"`
+	// Objectives and ParetoFront report a multi-objective run: the feasible
+	// draws found that no other beats on every objective, best weighted
+	// score first
+	Objectives      []constraints.Objective  `json:"objectives,omitempty"`
+	ParetoFront     []ParetoSolution         `json:"pareto_front,omitempty"`
 }
 
 // OptimizationProgress tracks the current state of optimization
 type OptimizationProgress struct {
-	Iteration       int     `json:"iteration"`
-	Temperature     float64 `json:"temperature"`
-	CurrentScore    float64 `json:"current_score"`
-	BestScore       float64 `json:"best_score"`
-	AcceptanceRate  float64 `json:"acceptance_rate"`
-	EstimatedTime   string  `json:"estimated_time"`
+	Iteration        int     `json:"iteration"`
+	Temperature      float64 `json:"temperature"`
+	CurrentScore     float64 `json:"current_score"`
+	BestScore        float64 `json:"best_score"`
+	AcceptanceRate   float64 `json:"acceptance_rate"`
+	EstimatedTime    string  `json:"estimated_time"`
+	FractionComplete float64 `json:"fraction_complete"`
+	// WorstTeams lists, per soft constraint, the teams the current draw satisfies least
+	WorstTeams []constraints.ConstraintTeamScores `json:"worst_teams,omitempty"`
+	// ConstraintScores is how the current draw scores on each constraint, so
+	// a run shows which constraints are improving and which are stuck
+	ConstraintScores []constraints.ConstraintScore `json:"constraint_scores,omitempty"`
+	// Starts and StartsCompleted track the runs of a multi-start optimization
+	Starts          int `json:"starts,omitempty"`
+	StartsCompleted int `json:"starts_completed,omitempty"`
 }
 
 // ProgressCallback is called during optimization to report progress
 type ProgressCallback func(progress OptimizationProgress)
 
+// Optimizer improves a draw against its constraint engine
+type Optimizer interface {
+	Optimize(draw *models.Draw, callback ProgressCallback) (*OptimizationResult, error)
+	// IterationBudget is how many iterations a run is expected to take
+	IterationBudget() int
+}
+
 // NewSimulatedAnnealing creates a new simulated annealing optimizer
 func NewSimulatedAnnealing(temperature, coolingRate float64, maxIterations int, constraintEngine *constraints.ConstraintEngine) *SimulatedAnnealing {
 	return &SimulatedAnnealing{
@@ -50,11 +126,20 @@ func NewSimulatedAnnealing(temperature, coolingRate float64, maxIterations int,
 		MaxIterations:    maxIterations,
 		ConstraintEngine: constraintEngine,
 		CoolingSchedule:  NewExponentialCooling(coolingRate),
+		WorstTeamsLimit:  constraints.DefaultWorstTeamsLimit,
+		CheckpointInterval: DefaultCheckpointInterval,
 	}
 }
 
 // Optimize runs the simulated annealing algorithm on the given draw
 func (sa *SimulatedAnnealing) Optimize(draw *models.Draw, callback ProgressCallback) (*OptimizationResult, error) {
+	return sa.OptimizeFrom(context.Background(), draw, nil, callback, nil)
+}
+
+// OptimizeFrom runs the simulated annealing algorithm on the given draw,
+// continuing from resume when it is set. A checkpoint is passed to save every
+// CheckpointInterval iterations, and when ctx is cancelled.
+func (sa *SimulatedAnnealing) OptimizeFrom(ctx context.Context, draw *models.Draw, resume *Checkpoint, callback ProgressCallback, save CheckpointFunc) (*OptimizationResult, error) {
 	if draw == nil {
 		return nil, fmt.Errorf("draw cannot be nil")
 	}
@@ -63,77 +148,208 @@ func (sa *SimulatedAnnealing) Optimize(draw *models.Draw, callback ProgressCallb
 		return nil, fmt.Errorf("draw has no matches to optimize")
 	}
 
+	if len(sa.Objectives) > 0 {
+		if err := sa.ConstraintEngine.CheckObjectives(sa.Objectives); err != nil {
+			return nil, err
+		}
+	}
+
 	startTime := time.Now()
 	
-	// Create a copy of the draw to work with
+	// Each run draws from its own source, so jobs sharing an optimizer don't
+	// disturb each other's sequence
+	seed := sa.runSeed()
+	if resume != nil {
+		seed = resume.Seed
+	}
+	sa = sa.withSeed(seed)
+	
+	// Work on a copy of the draw, changed in place by each move and reverted
+	// when the move is rejected. Only the best draw is copied.
 	currentDraw := sa.copyDraw(draw)
 	bestDraw := sa.copyDraw(draw)
 	
-	currentScore := sa.ConstraintEngine.ScoreDraw(currentDraw)
+	// Neighbours differ by a move or two, so only rescore the teams a move
+	// touches
+	cache := sa.ConstraintEngine.NewScoreCache(currentDraw)
+	currentScore := cache.Score()
 	bestScore := currentScore
 	initialScore := currentScore
 	
 	temperature := sa.Temperature
 	improvements := 0
 	acceptances := 0
+	iterations := 0
+	start := 0
+	tally := make(operatorTally)
+	var cacheStats constraints.CacheStats
+	
+	// A multi-objective run archives the trade-offs it finds, starting
+	// with the draw as given
+	var archive *ParetoArchive
+	if len(sa.Objectives) > 0 {
+		archive = NewParetoArchive(sa.Objectives, sa.ArchiveSize)
+		if scores, feasible := cache.ObjectiveScores(sa.Objectives); feasible && resume == nil {
+			archive.Add(scores, currentScore, 0, sa.copyDraw(currentDraw))
+		}
+	}
+	
+	// Pick up a checkpointed run where it stopped
+	if resume != nil {
+		currentDraw = sa.copyDraw(resume.CurrentDraw)
+		bestDraw = sa.copyDraw(resume.BestDraw)
+		cache = sa.ConstraintEngine.NewScoreCache(currentDraw)
+		currentScore = cache.Score()
+		bestScore = resume.BestScore
+		initialScore = resume.InitialScore
+		temperature = resume.Temperature
+		improvements = resume.Improvements
+		acceptances = resume.Acceptances
+		iterations = resume.Iteration
+		start = resume.Iteration
+		startTime = startTime.Add(-resume.Elapsed)
+		sa.source.skip(resume.RandomDraws)
+		tally.add(resume.OperatorStats)
+		cacheStats = resume.ScoreCache
+		for _, solution := range resume.Archive {
+			if archive != nil {
+				archive.AddSolution(solution)
+			}
+		}
+	}
+	sa.journal = newMoveJournal(currentDraw)
 	
-	rand.Seed(time.Now().UnixNano())
+	// A resumed run counts iterations without improvement from where it
+	// picked up
+	terminationReason := budgetReason(sa.TimeBudget)
+	lastImprovement := start
+	
+	checkpoint := func(next int) *Checkpoint {
+		return &Checkpoint{
+			Iteration:    next,
+			Temperature:  temperature,
+			CurrentDraw:  sa.copyDraw(currentDraw),
+			BestDraw:     sa.copyDraw(bestDraw),
+			InitialScore: initialScore,
+			BestScore:    bestScore,
+			Improvements: improvements,
+			Acceptances:  acceptances,
+			Seed:         seed,
+			RandomDraws:  sa.source.draws,
+			Elapsed:      time.Since(startTime),
+			OperatorStats: tally.stats(),
+			ScoreCache:   cacheStats.Add(cache.Stats()),
+			Archive:      archiveSolutions(archive),
+		}
+	}
 	
-	for i := 0; i < sa.MaxIterations; i++ {
-		// Create a neighbor solution by applying a random modification
-		neighbor, err := sa.generateNeighbor(currentDraw)
+	for i := start; sa.keepRunning(i, startTime); i++ {
+		// Stop between iterations once the run is cancelled
+		if i%100 == 0 && ctx.Err() != nil {
+			if save != nil {
+				save(checkpoint(i))
+			}
+			return nil, ctx.Err()
+		}
+		
+		if reason := sa.EarlyStopping.stopReason(bestScore, i-lastImprovement); reason != "" {
+			terminationReason = reason
+			break
+		}
+		
+		iterations++
+		
+		// Move to a neighbor solution by applying a random modification
+		neighbor, operator, err := sa.generateNeighbor(currentDraw)
 		if err != nil {
+			tally.record(operator, true, false, false)
 			continue // Skip this iteration if neighbor generation fails
 		}
 		
-		neighborScore := sa.ConstraintEngine.ScoreDraw(neighbor)
+		cache.Invalidate(neighbor.matches())
+		neighborScore := cache.Rescore(currentDraw)
+		
+		// A feasible neighbour that joins the Pareto archive is a new
+		// trade-off, however it scores overall
+		var objectiveScores []float64
+		admitted := false
+		if archive != nil {
+			var feasible bool
+			objectiveScores, feasible = cache.ObjectiveScores(sa.Objectives)
+			admitted = feasible && archive.Admits(objectiveScores)
+		}
 		
 		// Calculate acceptance probability
 		accepted := false
-		if neighborScore > currentScore {
+		improved := neighborScore > currentScore
+		if improved {
 			// Better solution - always accept
 			accepted = true
 			improvements++
+		} else if admitted {
+			accepted = true
 		} else if temperature > 0 {
 			// Worse solution - accept with probability based on temperature
 			delta := neighborScore - currentScore
 			probability := math.Exp(delta / temperature)
-			if rand.Float64() < probability {
+			if sa.random().Float64() < probability {
 				accepted = true
 			}
 		}
 		
-		if accepted {
-			currentDraw = neighbor
+		tally.record(operator, false, accepted, improved)
+		
+		if !accepted {
+			sa.journal.undo()
+			cache.Discard()
+		} else {
+			sa.journal.clear()
+			cache.Commit()
 			currentScore = neighborScore
 			acceptances++
 			
+			if admitted {
+				archive.Add(objectiveScores, currentScore, i+1, sa.copyDraw(currentDraw))
+			}
+			
 			// Update best solution if this is the best we've seen
 			if currentScore > bestScore {
 				bestDraw = sa.copyDraw(currentDraw)
 				bestScore = currentScore
+				lastImprovement = i + 1
 			}
 		}
 		
 		// Update temperature
-		temperature = sa.CoolingSchedule.NextTemperature(sa.Temperature, i)
+		temperature = sa.CoolingSchedule.NextTemperature(sa.Temperature, sa.scheduleIteration(i, startTime))
 		
 		// Report progress if callback provided
 		if callback != nil && i%100 == 0 {
 			acceptanceRate := float64(acceptances) / float64(i+1)
 			elapsed := time.Since(startTime)
-			remaining := time.Duration(float64(elapsed) * float64(sa.MaxIterations-i) / float64(i+1))
+			fraction := sa.fractionComplete(i, startTime)
+			remaining := time.Duration(0)
+			if fraction > 0 {
+				remaining = time.Duration(float64(elapsed) * (1 - fraction) / fraction)
+			}
 			
 			progress := OptimizationProgress{
-				Iteration:      i,
-				Temperature:    temperature,
-				CurrentScore:   currentScore,
-				BestScore:      bestScore,
-				AcceptanceRate: acceptanceRate,
-				EstimatedTime:  remaining.String(),
+				Iteration:        i,
+				Temperature:      temperature,
+				CurrentScore:     currentScore,
+				BestScore:        bestScore,
+				AcceptanceRate:   acceptanceRate,
+				EstimatedTime:    remaining.String(),
+				FractionComplete: fraction,
+				WorstTeams:       sa.ConstraintEngine.WorstTeams(currentDraw, sa.WorstTeamsLimit),
+				ConstraintScores: sa.ConstraintEngine.ConstraintScores(currentDraw),
 			}
 			callback(progress)
 		}
+		
+		if save != nil && sa.CheckpointInterval > 0 && (i+1)%sa.CheckpointInterval == 0 {
+			save(checkpoint(i + 1))
+		}
 	}
 	
 	duration := time.Since(startTime)
@@ -141,37 +357,118 @@ func (sa *SimulatedAnnealing) Optimize(draw *models.Draw, callback ProgressCallb
 	result := &OptimizationResult{
 		InitialScore: initialScore,
 		FinalScore:   bestScore,
-		Iterations:   sa.MaxIterations,
+		Iterations:   iterations,
 		Improvements: improvements,
 		Duration:     duration,
 		BestDraw:     bestDraw,
+		Seed:         seed,
+		Window:       sa.Window,
+		OperatorStats: tally.stats(),
+		ScoreCache:   cacheStats.Add(cache.Stats()),
+		TerminationReason: terminationReason,
+	}
+	if archive != nil {
+		result.Objectives = sa.Objectives
+		result.ParetoFront = archive.Solutions()
 	}
 	
 	return result, nil
 }
 
-// generateNeighbor creates a neighbor solution by applying a random modification
-func (sa *SimulatedAnnealing) generateNeighbor(draw *models.Draw) (*models.Draw, error) {
-	neighbor := sa.copyDraw(draw)
-	
-	// Choose a random modification operation
-	operations := []func(*models.Draw) error{
-		sa.swapMatches,
-		sa.rescheduleMatch,
-		sa.swapVenues,
-		sa.swapHomeAway,
+// archiveSolutions returns a multi-objective run's archived solutions, or
+// nil when the run has no archive
+func archiveSolutions(archive *ParetoArchive) []ParetoSolution {
+	if archive == nil {
+		return nil
+	}
+	return archive.Solutions()
+}
+
+// IterationBudget returns the configured number of iterations
+func (sa *SimulatedAnnealing) IterationBudget() int {
+	return sa.MaxIterations
+}
+
+// runSeed returns the configured seed, or a time-based one when none is set
+func (sa *SimulatedAnnealing) runSeed() int64 {
+	if sa.Seed != nil {
+		return *sa.Seed
+	}
+	return time.Now().UnixNano()
+}
+
+// withSeed returns a copy of the optimizer drawing from a source seeded with seed
+func (sa *SimulatedAnnealing) withSeed(seed int64) *SimulatedAnnealing {
+	run := *sa
+	run.source = newCountingSource(seed)
+	run.rng = rand.New(run.source)
+	return &run
+}
+
+// random returns the optimizer's random source, creating a time-seeded one
+// for operations called outside of Optimize
+func (sa *SimulatedAnnealing) random() *rand.Rand {
+	if sa.rng == nil {
+		sa.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return sa.rng
+}
+
+// keepRunning reports whether another iteration should run. With a time budget
+// the annealer runs until the budget is spent, regardless of MaxIterations.
+func (sa *SimulatedAnnealing) keepRunning(iteration int, startTime time.Time) bool {
+	if sa.TimeBudget > 0 {
+		return time.Since(startTime) < sa.TimeBudget
+	}
+	return iteration < sa.MaxIterations
+}
+
+// fractionComplete returns how far through the run the annealer is, from 0 to 1
+func (sa *SimulatedAnnealing) fractionComplete(iteration int, startTime time.Time) float64 {
+	var fraction float64
+	if sa.TimeBudget > 0 {
+		fraction = float64(time.Since(startTime)) / float64(sa.TimeBudget)
+	} else if sa.MaxIterations > 0 {
+		fraction = float64(iteration+1) / float64(sa.MaxIterations)
+	}
+	return math.Min(fraction, 1)
+}
+
+// scheduleIteration maps the current position in the run onto the cooling
+// schedule. Budgeted runs use elapsed time, so the schedule reaches the same
+// final temperature however many iterations fit in the budget.
+func (sa *SimulatedAnnealing) scheduleIteration(iteration int, startTime time.Time) int {
+	if sa.TimeBudget <= 0 {
+		return iteration
+	}
+	length := sa.MaxIterations
+	if length <= 0 {
+		length = DefaultBudgetScheduleIterations
+	}
+	return int(sa.fractionComplete(iteration, startTime) * float64(length))
+}
+
+// generateNeighbor turns the draw into a neighbor solution in place by
+// applying a random modification. The move is recorded in the journal so it
+// can be undone, and is returned with the operator it applied.
+func (sa *SimulatedAnnealing) generateNeighbor(draw *models.Draw) (*move, string, error) {
+	if sa.journal == nil || sa.journal.draw != draw {
+		sa.journal = newMoveJournal(draw)
 	}
 	
-	operation := operations[rand.Intn(len(operations))]
-	err := operation(neighbor)
-	if err != nil {
-		return nil, err
+	// Choose a random modification operation
+	operator := sa.chooseOperator()
+	sa.journal.begin(operator)
+	if err := sa.applyOperator(operator, draw); err != nil {
+		sa.journal.cancel()
+		return nil, operator, err
 	}
 	
-	return neighbor, nil
+	return sa.journal.end(), operator, nil
 }
 
-// copyDraw creates a deep copy of a draw
+// copyDraw creates a deep copy of a draw. The optimizer changes its working
+// draw in place, so copies are only taken to keep the best draw found.
 func (sa *SimulatedAnnealing) copyDraw(original *models.Draw) *models.Draw {
 	copy := &models.Draw{
 		ID:               original.ID,
@@ -179,9 +476,11 @@ func (sa *SimulatedAnnealing) copyDraw(original *models.Draw) *models.Draw {
 		SeasonYear:       original.SeasonYear,
 		Rounds:           original.Rounds,
 		Status:           original.Status,
+		Version:          original.Version,
 		ConstraintConfig: original.ConstraintConfig,
 		CreatedAt:        original.CreatedAt,
 		UpdatedAt:        original.UpdatedAt,
+		CompetitionID:    original.CompetitionID,
 		Matches:          make([]*models.Match, len(original.Matches)),
 	}
 	
@@ -197,6 +496,7 @@ func (sa *SimulatedAnnealing) copyDraw(original *models.Draw) *models.Draw {
 			MatchDate:   copyTimePtr(match.MatchDate),
 			MatchTime:   copyTimePtr(match.MatchTime),
 			IsPrimeTime: match.IsPrimeTime,
+			Locked:      match.Locked,
 			CreatedAt:   match.CreatedAt,
 			UpdatedAt:   match.UpdatedAt,
 		}
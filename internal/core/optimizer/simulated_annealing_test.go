@@ -1,6 +1,11 @@
 package optimizer
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
 	"testing"
 	"time"
 
@@ -92,6 +97,7 @@ func TestOptimize_ValidDraw(t *testing.T) {
 
 func TestOptimize_WithCallback(t *testing.T) {
 	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
 	sa := NewSimulatedAnnealing(100.0, 0.99, 500, engine)
 
 	draw := createTestDraw()
@@ -105,6 +111,12 @@ func TestOptimize_WithCallback(t *testing.T) {
 		if progress.Temperature < 0 {
 			t.Error("Expected non-negative temperature")
 		}
+		if len(progress.WorstTeams) != 1 {
+			t.Errorf("Expected worst teams for the home/away balance constraint, got %d", len(progress.WorstTeams))
+		}
+		if len(progress.ConstraintScores) != 1 {
+			t.Errorf("Expected a score for the home/away balance constraint, got %d", len(progress.ConstraintScores))
+		}
 	}
 
 	result, err := sa.Optimize(draw, callback)
@@ -120,6 +132,381 @@ func TestOptimize_WithCallback(t *testing.T) {
 	}
 }
 
+func TestOptimize_TimeBudget(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	sa := NewSimulatedAnnealing(100.0, 0.99, 10, engine)
+	sa.TimeBudget = 50 * time.Millisecond
+
+	result, err := sa.Optimize(createTestDraw(), nil)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Duration < sa.TimeBudget {
+		t.Errorf("Expected run to use the %v budget, finished after %v", sa.TimeBudget, result.Duration)
+	}
+	if result.Iterations <= sa.MaxIterations {
+		t.Errorf("Expected budget to override max iterations, got %d iterations", result.Iterations)
+	}
+}
+
+func TestOptimize_EarlyStopping(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+
+	// Without early stopping the run uses its whole budget
+	sa := NewSimulatedAnnealing(100.0, 0.99, 200, engine)
+	result, err := sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Iterations != 200 || result.TerminationReason != TerminationMaxIterations {
+		t.Errorf("Expected 200 iterations stopping at max_iterations, got %d stopping at %s",
+			result.Iterations, result.TerminationReason)
+	}
+
+	sa.TimeBudget = 10 * time.Millisecond
+	result, err = sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TerminationReason != TerminationTimeBudget {
+		t.Errorf("Expected a time_budget stop, got %s", result.TerminationReason)
+	}
+
+	// A score the draw already meets stops the run before it starts
+	target := 0.0
+	sa = NewSimulatedAnnealing(100.0, 0.99, 200, engine)
+	sa.EarlyStopping = EarlyStopping{TargetScore: &target}
+	result, err = sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Iterations != 0 || result.TerminationReason != TerminationTargetScore {
+		t.Errorf("Expected no iterations stopping at target_score, got %d stopping at %s",
+			result.Iterations, result.TerminationReason)
+	}
+
+	// A stall stops the run well before the budget is spent
+	sa = NewSimulatedAnnealing(100.0, 0.99, 100000, engine)
+	sa.EarlyStopping = EarlyStopping{NoImprovementIterations: 50}
+	result, err = sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Iterations >= sa.MaxIterations || result.TerminationReason != TerminationNoImprovement {
+		t.Errorf("Expected an early no_improvement stop, got %d iterations stopping at %s",
+			result.Iterations, result.TerminationReason)
+	}
+}
+
+func TestOptimize_Seeded(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	seed := int64(2025)
+	
+	run := func() *OptimizationResult {
+		sa := NewSimulatedAnnealing(100.0, 0.99, 300, engine)
+		sa.Seed = &seed
+		result, err := sa.Optimize(createTestDraw(), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result
+	}
+	
+	first, second := run(), run()
+	if first.Seed != seed {
+		t.Errorf("Expected result to report seed %d, got %d", seed, first.Seed)
+	}
+	if first.FinalScore != second.FinalScore || first.Improvements != second.Improvements {
+		t.Errorf("Expected identical runs, got scores %.4f/%.4f and improvements %d/%d",
+			first.FinalScore, second.FinalScore, first.Improvements, second.Improvements)
+	}
+	for i, match := range first.BestDraw.Matches {
+		other := second.BestDraw.Matches[i]
+		if match.Round != other.Round || *match.HomeTeamID != *other.HomeTeamID {
+			t.Errorf("Best draws differ at match %d", i)
+			break
+		}
+	}
+	
+	// Unseeded runs still report the seed they used
+	sa := NewSimulatedAnnealing(100.0, 0.99, 10, engine)
+	result, err := sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Seed == 0 {
+		t.Error("Expected unseeded run to report the seed it picked")
+	}
+}
+
+func TestOptimizeFrom_Checkpoint(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	seed := int64(7)
+	
+	newOptimizer := func() *SimulatedAnnealing {
+		sa := NewSimulatedAnnealing(100.0, 0.99, 400, engine)
+		sa.Seed = &seed
+		sa.CheckpointInterval = 100
+		return sa
+	}
+	
+	var checkpoints []*Checkpoint
+	uninterrupted, err := newOptimizer().OptimizeFrom(context.Background(), createTestDraw(), nil, nil, func(checkpoint *Checkpoint) {
+		checkpoints = append(checkpoints, checkpoint)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(checkpoints) != 4 || checkpoints[1].Iteration != 200 {
+		t.Fatalf("Expected a checkpoint every 100 iterations, got %d", len(checkpoints))
+	}
+	
+	// Resume from a checkpoint that went through storage
+	data, err := json.Marshal(checkpoints[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	resumed, err := newOptimizer().OptimizeFrom(context.Background(), createTestDraw(), &checkpoint, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resumed.Iterations != uninterrupted.Iterations || resumed.Improvements != uninterrupted.Improvements {
+		t.Errorf("Expected %d iterations and %d improvements, got %d and %d",
+			uninterrupted.Iterations, uninterrupted.Improvements, resumed.Iterations, resumed.Improvements)
+	}
+	if !reflect.DeepEqual(resumed.OperatorStats, uninterrupted.OperatorStats) {
+		t.Errorf("Expected operator stats to carry over, got %+v and %+v", resumed.OperatorStats, uninterrupted.OperatorStats)
+	}
+	if math.Abs(resumed.FinalScore-uninterrupted.FinalScore) > 1e-9 || resumed.InitialScore != uninterrupted.InitialScore {
+		t.Errorf("Expected the resumed run to match the uninterrupted one, got scores %.6f and %.6f",
+			resumed.FinalScore, uninterrupted.FinalScore)
+	}
+	for i, match := range uninterrupted.BestDraw.Matches {
+		other := resumed.BestDraw.Matches[i]
+		if match.Round != other.Round || *match.HomeTeamID != *other.HomeTeamID || *match.VenueID != *other.VenueID {
+			t.Errorf("Best draws differ at match %d", i)
+			break
+		}
+	}
+	
+	// A cancelled run stops and saves where it got to
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var last *Checkpoint
+	_, err = newOptimizer().OptimizeFrom(ctx, createTestDraw(), nil, nil, func(checkpoint *Checkpoint) {
+		last = checkpoint
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancelled run to return context.Canceled, got %v", err)
+	}
+	if last == nil || last.Iteration != 0 || last.Seed != seed {
+		t.Errorf("Expected a checkpoint at iteration 0 when cancelled, got %+v", last)
+	}
+}
+
+func TestOptimize_IncrementalScoring(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	engine.AddSoftConstraint(constraints.NewTravelMinimizationConstraint(1), 0.5)
+	sa := NewSimulatedAnnealing(100.0, 0.99, 500, engine)
+
+	result, err := sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Delta scores along the way must agree with scoring the best draw in full
+	if full := engine.ScoreDraw(result.BestDraw); math.Abs(full-result.FinalScore) > 1e-9 {
+		t.Errorf("Expected final score %.6f to match the full score %.6f", result.FinalScore, full)
+	}
+	
+	// Teams a move doesn't touch are served from the score cache
+	if cache := result.ScoreCache; cache.Hits == 0 || cache.Invalidations == 0 || cache.HitRate <= 0 {
+		t.Errorf("Expected score cache hits, got %+v", cache)
+	}
+}
+
+func TestOptimize_RoundWindow(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	sa := NewSimulatedAnnealing(100.0, 0.99, 500, engine)
+	sa.Window = RoundWindow{From: 2, To: 3}
+
+	draw := createTestDraw()
+	result, err := sa.Optimize(draw, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Window != sa.Window {
+		t.Errorf("Expected the result to record window %+v, got %+v", sa.Window, result.Window)
+	}
+	for i, match := range result.BestDraw.Matches {
+		original := draw.Matches[i]
+		inWindow := sa.Window.Contains(original.Round)
+		if !inWindow && (match.Round != original.Round || *match.HomeTeamID != *original.HomeTeamID || *match.VenueID != *original.VenueID) {
+			t.Errorf("Expected match %d outside the window to stay fixed", match.ID)
+		}
+		if inWindow && !sa.Window.Contains(match.Round) {
+			t.Errorf("Expected match %d to stay inside the window, moved to round %d", match.ID, match.Round)
+		}
+	}
+}
+
+func TestOptimize_OperatorWeights(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+
+	// Every operator is tried when none are weighted
+	sa := NewSimulatedAnnealing(100.0, 0.99, 400, engine)
+	result, err := sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	attempts := 0
+	for _, operator := range operatorNames {
+		stats := result.OperatorStats[operator]
+		if stats.Attempts == 0 {
+			t.Errorf("Expected %s to be tried", operator)
+		}
+		if stats.Accepted > stats.Attempts || stats.Improvements > stats.Accepted {
+			t.Errorf("Expected improvements <= accepted <= attempts for %s, got %+v", operator, stats)
+		}
+		attempts += stats.Attempts
+	}
+	if attempts != result.Iterations {
+		t.Errorf("Expected one attempt per iteration, got %d over %d iterations", attempts, result.Iterations)
+	}
+
+	// Only weighted operators are picked
+	sa = NewSimulatedAnnealing(100.0, 0.99, 200, engine)
+	sa.OperatorWeights = OperatorWeights{OperatorSwapHomeAway: 0.75, OperatorSwapMatches: 0.25}
+	result, err = sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.OperatorStats) != 2 {
+		t.Errorf("Expected stats for the two weighted operators, got %+v", result.OperatorStats)
+	}
+	flips := result.OperatorStats[OperatorSwapHomeAway]
+	if flips.Attempts <= result.OperatorStats[OperatorSwapMatches].Attempts {
+		t.Errorf("Expected home/away swaps to be picked most, got %+v", result.OperatorStats)
+	}
+	if flips.Attempts > 0 && flips.AcceptanceRate != float64(flips.Accepted)/float64(flips.Attempts) {
+		t.Errorf("Expected acceptance rate %d/%d, got %f", flips.Accepted, flips.Attempts, flips.AcceptanceRate)
+	}
+}
+
+func TestOptimize_Objectives(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewTravelMinimizationConstraint(1), 1.0)
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	objectives := []constraints.Objective{constraints.ObjectiveTravel, constraints.ObjectiveFairness}
+
+	seed := int64(11)
+	sa := NewSimulatedAnnealing(100.0, 0.99, 500, engine)
+	sa.Seed = &seed
+	sa.Objectives = objectives
+	sa.ArchiveSize = 5
+
+	result, err := sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Objectives, objectives) {
+		t.Errorf("Expected objectives %v, got %v", objectives, result.Objectives)
+	}
+	if len(result.ParetoFront) == 0 || len(result.ParetoFront) > 5 {
+		t.Fatalf("Expected between 1 and 5 trade-offs, got %d", len(result.ParetoFront))
+	}
+	for i, solution := range result.ParetoFront {
+		if solution.Draw == nil {
+			t.Fatalf("Expected trade-off %d to have a draw", i)
+		}
+		scores := engine.ObjectiveScores(solution.Draw, objectives)
+		for j, objective := range objectives {
+			if math.Abs(scores[j]-solution.Objectives[objective]) > 1e-9 {
+				t.Errorf("Expected trade-off %d to score %f on %s, got %f", i, scores[j], objective, solution.Objectives[objective])
+			}
+		}
+		if i > 0 && solution.Score > result.ParetoFront[i-1].Score {
+			t.Errorf("Expected trade-offs in descending score order")
+		}
+	}
+
+	// A weighted run returns no front
+	sa.Objectives = nil
+	result, err = sa.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.ParetoFront != nil {
+		t.Errorf("Expected no Pareto front from a weighted run, got %d", len(result.ParetoFront))
+	}
+
+	// Objectives no constraint scores are rejected
+	sa.Objectives = []constraints.Objective{constraints.ObjectiveBroadcast}
+	if _, err := sa.Optimize(createTestDraw(), nil); !errors.Is(err, constraints.ErrInvalidObjectives) {
+		t.Errorf("Expected ErrInvalidObjectives, got %v", err)
+	}
+}
+
+func TestOperatorWeightsValidate(t *testing.T) {
+	valid := []OperatorWeights{
+		nil,
+		{OperatorSwapMatches: 0.4, OperatorRescheduleMatch: 0.3, OperatorSwapVenues: 0.2, OperatorSwapHomeAway: 0.1},
+		{OperatorSwapHomeAway: 1},
+	}
+	for _, weights := range valid {
+		if err := weights.Validate(); err != nil {
+			t.Errorf("Expected %v to be valid, got %v", weights, err)
+		}
+	}
+
+	invalid := []OperatorWeights{
+		{OperatorSwapMatches: 0.4, OperatorSwapHomeAway: 0.1},
+		{OperatorSwapMatches: 1.5, OperatorSwapHomeAway: -0.5},
+		{"shuffle_rounds": 1},
+	}
+	for _, weights := range invalid {
+		if err := weights.Validate(); !errors.Is(err, ErrInvalidOperatorWeights) {
+			t.Errorf("Expected %v to be rejected, got %v", weights, err)
+		}
+	}
+}
+
+func TestScheduleIteration_TimeBudget(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	sa := NewSimulatedAnnealing(100.0, 0.99, 1000, engine)
+
+	// Without a budget the schedule follows the iteration count
+	if got := sa.scheduleIteration(250, time.Now()); got != 250 {
+		t.Errorf("Expected iteration 250, got %d", got)
+	}
+
+	// With a budget it follows elapsed time, whatever the iteration count
+	sa.TimeBudget = time.Minute
+	start := time.Now().Add(-30 * time.Second)
+	got := sa.scheduleIteration(5, start)
+	if got < 490 || got > 510 {
+		t.Errorf("Expected half-way through the schedule (~500), got %d", got)
+	}
+
+	start = time.Now().Add(-2 * time.Minute)
+	if got := sa.scheduleIteration(5, start); got != 1000 {
+		t.Errorf("Expected an exhausted budget to end the schedule at 1000, got %d", got)
+	}
+}
+
 func TestCopyDraw(t *testing.T) {
 	engine := constraints.NewConstraintEngine()
 	sa := NewSimulatedAnnealing(100.0, 0.99, 100, engine)
@@ -158,25 +545,36 @@ func TestGenerateNeighbor(t *testing.T) {
 	sa := NewSimulatedAnnealing(100.0, 0.99, 100, engine)
 
 	draw := createTestDraw()
-	
-	neighbor, err := sa.generateNeighbor(draw)
-	
+	original := sa.copyDraw(draw)
+
+	neighbor, operator, err := sa.generateNeighbor(draw)
+
 	if err != nil {
-		t.Errorf("Unexpected error generating neighbor: %v", err)
+		t.Fatalf("Unexpected error generating neighbor: %v", err)
 	}
-	if neighbor == nil {
-		t.Error("Expected neighbor draw")
+	if !isOperator(operator) || neighbor.operator != operator {
+		t.Errorf("Expected a neighbourhood operator, got %q", operator)
 	}
-	
-	// Verify it's a different object
-	if neighbor == draw {
-		t.Error("Neighbor should be a different object")
+
+	// The move changes the draw in place and only lists matches it changed
+	for _, idx := range neighbor.indexes() {
+		if stateOf(draw.Matches[idx]).equal(stateOf(original.Matches[idx])) {
+			t.Errorf("Expected match %d to have changed", idx)
+		}
 	}
-	
-	// Verify basic structure is maintained
-	if len(neighbor.Matches) != len(draw.Matches) {
+	if len(draw.Matches) != len(original.Matches) {
 		t.Error("Neighbor should have same number of matches")
 	}
+
+	// Undoing the move restores the draw
+	if sa.journal.undo() != neighbor {
+		t.Fatal("Expected to undo the neighbor's move")
+	}
+	for i, match := range draw.Matches {
+		if !stateOf(match).equal(stateOf(original.Matches[i])) {
+			t.Errorf("Expected match %d to be restored", i)
+		}
+	}
 }
 
 func createTestDraw() *models.Draw {
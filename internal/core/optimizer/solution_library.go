@@ -0,0 +1,157 @@
+package optimizer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// SolutionKey identifies a draw's teams, rounds and constraint configuration
+// in the solution library. Configurations that differ only in formatting or
+// key order share a key.
+func SolutionKey(draw *models.Draw) (string, error) {
+	seen := make(map[int]bool)
+	var teams []int
+	for _, match := range draw.Matches {
+		for _, teamID := range []*int{match.HomeTeamID, match.AwayTeamID} {
+			if teamID != nil && !seen[*teamID] {
+				seen[*teamID] = true
+				teams = append(teams, *teamID)
+			}
+		}
+	}
+	sort.Ints(teams)
+
+	// Re-encoding sorts object keys, giving a canonical form of the config
+	config := []byte("null")
+	if len(draw.ConstraintConfig) > 0 {
+		var parsed interface{}
+		if err := json.Unmarshal(draw.ConstraintConfig, &parsed); err != nil {
+			return "", fmt.Errorf("invalid constraint config: %w", err)
+		}
+		var err error
+		if config, err = json.Marshal(parsed); err != nil {
+			return "", err
+		}
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("teams=%v;rounds=%d;constraints=%s", teams, draw.Rounds, config)))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// warmStartDraw returns a copy of the draw with the solution's fixtures in
+// place of its own, keeping the draw's match IDs. Matches are paired in ID
+// order. Draws with locked matches aren't warm started, since the solution
+// may move them.
+func warmStartDraw(draw *models.Draw, solution *models.Solution) (*models.Draw, error) {
+	if len(solution.Matches) != len(draw.Matches) {
+		return nil, fmt.Errorf("solution has %d matches, draw has %d", len(solution.Matches), len(draw.Matches))
+	}
+	for _, match := range draw.Matches {
+		if match.Locked {
+			return nil, fmt.Errorf("draw has locked matches")
+		}
+	}
+
+	warm := (&SimulatedAnnealing{}).copyDraw(draw)
+	targets := append([]*models.Match{}, warm.Matches...)
+	sort.Slice(targets, func(i, j int) bool { return targets[i].ID < targets[j].ID })
+	fixtures := append([]*models.Match{}, solution.Matches...)
+	sort.Slice(fixtures, func(i, j int) bool { return fixtures[i].ID < fixtures[j].ID })
+
+	for i, target := range targets {
+		fixture := fixtures[i]
+		target.Round = fixture.Round
+		target.HomeTeamID = copyIntPtr(fixture.HomeTeamID)
+		target.AwayTeamID = copyIntPtr(fixture.AwayTeamID)
+		target.VenueID = copyIntPtr(fixture.VenueID)
+		target.MatchDate = copyTimePtr(fixture.MatchDate)
+		target.MatchTime = copyTimePtr(fixture.MatchTime)
+		target.IsPrimeTime = fixture.IsPrimeTime
+	}
+
+	return warm, nil
+}
+
+// SetSolutionStore records the best draw of each completed job in the
+// solution library
+func (jm *JobManager) SetSolutionStore(store storage.SolutionRepository) {
+	jm.solutions = store
+}
+
+// recordSolution stores a completed job's best draw under its config's
+// solution key, unless the library already holds a draw scoring as well.
+// Draws breaking hard constraints score 0 and are never stored.
+func (jm *JobManager) recordSolution(job *OptimizationJob, config OptimizationConfig, result *OptimizationResult) {
+	if jm.solutions == nil || config.SolutionKey == "" || result.BestDraw == nil || result.FinalScore <= 0 {
+		return
+	}
+
+	ctx := context.Background()
+	if existing, err := jm.solutions.GetByKey(ctx, config.SolutionKey); err == nil && existing.Score >= result.FinalScore {
+		return
+	}
+
+	drawID := job.DrawID
+	solution := &models.Solution{
+		Key:        config.SolutionKey,
+		DrawID:     &drawID,
+		JobID:      job.ID,
+		Score:      result.FinalScore,
+		Rounds:     result.BestDraw.Rounds,
+		MatchCount: len(result.BestDraw.Matches),
+		Matches:    result.BestDraw.Matches,
+	}
+	if err := jm.solutions.Save(ctx, solution); err != nil {
+		log.Printf("Error recording solution of optimization job %s: %v", job.ID, err)
+	}
+}
+
+// ListSolutions returns the solution library without the solutions' matches
+func (s *Service) ListSolutions(ctx context.Context) ([]*models.Solution, error) {
+	return s.repository.Solutions().List(ctx)
+}
+
+// DeleteSolution removes a solution from the library, so later warm starts
+// don't use it
+func (s *Service) DeleteSolution(ctx context.Context, id int) error {
+	return s.repository.Solutions().Delete(ctx, id)
+}
+
+// prepareWarmStart sets the config's solution key and, when a warm start is
+// requested and the library has a usable solution, returns the draw to start
+// from in place of the stored one. Published draws are scored against their
+// stored fixtures, so their scores aren't comparable and they take no part.
+func (s *Service) prepareWarmStart(draw *models.Draw, config *OptimizationConfig) *models.Draw {
+	if config.StabilityWeight != nil && *config.StabilityWeight > 0 {
+		return draw
+	}
+
+	key, err := SolutionKey(draw)
+	if err != nil {
+		return draw
+	}
+	config.SolutionKey = key
+	if !config.WarmStart {
+		return draw
+	}
+
+	solution, err := s.repository.Solutions().GetByKey(context.Background(), key)
+	if err != nil {
+		return draw
+	}
+	warm, err := warmStartDraw(draw, solution)
+	if err != nil {
+		log.Printf("Not warm starting draw %d from solution %d: %v", draw.ID, solution.ID, err)
+		return draw
+	}
+	config.WarmStartSolutionID = solution.ID
+	return warm
+}
@@ -0,0 +1,82 @@
+package optimizer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestSolutionKey(t *testing.T) {
+	draw := createTestDraw()
+	draw.ConstraintConfig = json.RawMessage(`{"hard": [{"type": "bye", "params": {"max_byes": 1}}], "soft": []}`)
+	key, err := SolutionKey(draw)
+	if err != nil {
+		t.Fatalf("Failed to compute solution key: %v", err)
+	}
+
+	// Fixture order and config formatting don't change the key
+	reordered := createTestDraw()
+	reordered.Matches[0], reordered.Matches[3] = reordered.Matches[3], reordered.Matches[0]
+	reordered.ConstraintConfig = json.RawMessage(`{"soft":[],"hard":[{"params":{"max_byes":1},"type":"bye"}]}`)
+	if other, _ := SolutionKey(reordered); other != key {
+		t.Errorf("Expected the same key for a reordered draw, got %s and %s", key, other)
+	}
+
+	moreRounds := createTestDraw()
+	moreRounds.ConstraintConfig = draw.ConstraintConfig
+	moreRounds.Rounds++
+	if other, _ := SolutionKey(moreRounds); other == key {
+		t.Error("Expected a different key for a different number of rounds")
+	}
+
+	otherConfig := createTestDraw()
+	otherConfig.ConstraintConfig = json.RawMessage(`{"hard": [{"type": "bye", "params": {"max_byes": 2}}], "soft": []}`)
+	if other, _ := SolutionKey(otherConfig); other == key {
+		t.Error("Expected a different key for a different constraint config")
+	}
+
+	invalid := createTestDraw()
+	invalid.ConstraintConfig = json.RawMessage(`{`)
+	if _, err := SolutionKey(invalid); err == nil {
+		t.Error("Expected an error for an invalid constraint config")
+	}
+}
+
+func TestWarmStartDraw(t *testing.T) {
+	draw := createTestDraw()
+	solutionDraw := createTestDraw()
+	for _, match := range solutionDraw.Matches {
+		match.ID += 100
+		match.Round = solutionDraw.Rounds + 1 - match.Round
+		match.HomeTeamID, match.AwayTeamID = match.AwayTeamID, match.HomeTeamID
+	}
+	solution := &models.Solution{ID: 1, Matches: solutionDraw.Matches}
+
+	warm, err := warmStartDraw(draw, solution)
+	if err != nil {
+		t.Fatalf("Failed to warm start draw: %v", err)
+	}
+	for i, match := range warm.Matches {
+		fixture := solutionDraw.Matches[i]
+		if match.ID != draw.Matches[i].ID {
+			t.Errorf("Expected match %d to keep its ID, got %d", draw.Matches[i].ID, match.ID)
+		}
+		if match.Round != fixture.Round || *match.HomeTeamID != *fixture.HomeTeamID {
+			t.Errorf("Expected match %d to take the solution's fixture, got %+v", match.ID, match)
+		}
+	}
+	if draw.Matches[0].Round != 1 {
+		t.Error("Expected the stored draw to be left unchanged")
+	}
+
+	short := &models.Solution{Matches: solutionDraw.Matches[:2]}
+	if _, err := warmStartDraw(draw, short); err == nil {
+		t.Error("Expected an error when the match counts differ")
+	}
+
+	draw.Matches[0].Locked = true
+	if _, err := warmStartDraw(draw, solution); err == nil {
+		t.Error("Expected an error for a draw with locked matches")
+	}
+}
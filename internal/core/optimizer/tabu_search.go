@@ -0,0 +1,251 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Optimization algorithms selectable through OptimizationConfig.Algorithm
+const (
+	AlgorithmSimulatedAnnealing = "simulated_annealing"
+	AlgorithmTabuSearch         = "tabu_search"
+)
+
+// Default tabu search settings
+const (
+	DefaultTabuTenure       = 10
+	DefaultNeighborhoodSize = 20
+)
+
+// tabuProgressInterval is how often, in iterations, tabu search reports
+// progress. Each iteration scores a whole neighbourhood, so reports come more
+// often than the annealer's.
+const tabuProgressInterval = 10
+
+// TabuConfig contains the settings specific to tabu search
+type TabuConfig struct {
+	// Tenure is how many iterations a changed match stays tabu
+	Tenure int `json:"tenure,omitempty"`
+	// NeighborhoodSize is how many candidate moves are scored per iteration
+	NeighborhoodSize int `json:"neighborhood_size,omitempty"`
+}
+
+// TabuSearch implements the tabu search optimization algorithm. Each
+// iteration scores a sample of neighbouring draws and moves to the best one,
+// even when it is worse than the current draw. Matches changed by recent
+// moves are tabu, so the search can't immediately undo its way back into the
+// local optimum it just left. A tabu move is still taken when it beats the
+// best draw found so far.
+type TabuSearch struct {
+	MaxIterations    int
+	Tenure           int
+	NeighborhoodSize int
+	ConstraintEngine *constraints.ConstraintEngine
+	// TimeBudget, when set, runs the search for a wall-clock duration instead
+	// of MaxIterations
+	TimeBudget time.Duration
+	// WorstTeamsLimit is how many struggling teams per soft constraint are
+	// included in progress reports
+	WorstTeamsLimit int
+	// Seed, when set, makes runs reproducible. When nil each run picks a seed.
+	Seed *int64
+	// Window, when set, only changes matches in its rounds
+	Window RoundWindow
+	// EarlyStopping ends the search before MaxIterations or TimeBudget once
+	// it stalls or reaches a target score
+	EarlyStopping EarlyStopping
+}
+
+// NewTabuSearch creates a new tabu search optimizer. Non-positive tenure and
+// neighbourhood sizes use the defaults.
+func NewTabuSearch(maxIterations, tenure, neighborhoodSize int, constraintEngine *constraints.ConstraintEngine) *TabuSearch {
+	if tenure <= 0 {
+		tenure = DefaultTabuTenure
+	}
+	if neighborhoodSize <= 0 {
+		neighborhoodSize = DefaultNeighborhoodSize
+	}
+	return &TabuSearch{
+		MaxIterations:    maxIterations,
+		Tenure:           tenure,
+		NeighborhoodSize: neighborhoodSize,
+		ConstraintEngine: constraintEngine,
+		WorstTeamsLimit:  constraints.DefaultWorstTeamsLimit,
+	}
+}
+
+// IterationBudget returns the configured number of iterations
+func (ts *TabuSearch) IterationBudget() int {
+	return ts.MaxIterations
+}
+
+// Optimize runs tabu search on the given draw
+func (ts *TabuSearch) Optimize(draw *models.Draw, callback ProgressCallback) (*OptimizationResult, error) {
+	if draw == nil {
+		return nil, fmt.Errorf("draw cannot be nil")
+	}
+
+	if len(draw.Matches) == 0 {
+		return nil, fmt.Errorf("draw has no matches to optimize")
+	}
+
+	startTime := time.Now()
+
+	// Candidate moves come from the annealer's move operators, drawing from
+	// this run's own seeded source
+	seed := time.Now().UnixNano()
+	if ts.Seed != nil {
+		seed = *ts.Seed
+	}
+	moves := (&SimulatedAnnealing{ConstraintEngine: ts.ConstraintEngine, Window: ts.Window}).withSeed(seed)
+
+	currentDraw := moves.copyDraw(draw)
+	bestDraw := moves.copyDraw(draw)
+
+	cache := ts.ConstraintEngine.NewScoreCache(currentDraw)
+	currentScore := cache.Score()
+	bestScore := currentScore
+	initialScore := currentScore
+
+	// tabuUntil maps a match's index to the last iteration it is tabu for
+	tabuUntil := make(map[int]int)
+	improvements := 0
+	moved := 0
+	iterations := 0
+	terminationReason := budgetReason(ts.TimeBudget)
+	lastImprovement := 0
+
+	for i := 0; ts.keepRunning(i, startTime); i++ {
+		if reason := ts.EarlyStopping.stopReason(bestScore, i-lastImprovement); reason != "" {
+			terminationReason = reason
+			break
+		}
+
+		iterations++
+
+		var chosen *move
+		var chosenChanges []int
+		chosenScore := math.Inf(-1)
+
+		// Each candidate is applied to the current draw, scored and undone;
+		// the chosen one is replayed afterwards
+		for n := 0; n < ts.NeighborhoodSize; n++ {
+			neighbor, _, err := moves.generateNeighbor(currentDraw)
+			if err != nil {
+				continue
+			}
+			changes := neighbor.indexes()
+			if len(changes) == 0 {
+				moves.journal.undo()
+				continue
+			}
+
+			cache.Invalidate(neighbor.matches())
+			score := cache.Rescore(currentDraw)
+			moves.journal.undo()
+			cache.Discard()
+			if isTabu(changes, tabuUntil, i) && score <= bestScore {
+				continue
+			}
+			if score > chosenScore {
+				chosen = neighbor
+				chosenChanges = changes
+				chosenScore = score
+			}
+		}
+
+		if chosen != nil {
+			moves.journal.redo(chosen)
+			moves.journal.clear()
+			cache.Invalidate(chosen.matches())
+			currentScore = cache.Rescore(currentDraw)
+			cache.Commit()
+			moved++
+			for _, idx := range chosenChanges {
+				tabuUntil[idx] = i + ts.Tenure
+			}
+
+			if currentScore > bestScore {
+				bestDraw = moves.copyDraw(currentDraw)
+				bestScore = currentScore
+				improvements++
+				lastImprovement = i + 1
+			}
+		}
+
+		// Report progress if callback provided
+		if callback != nil && i%tabuProgressInterval == 0 {
+			elapsed := time.Since(startTime)
+			fraction := ts.fractionComplete(i, startTime)
+			remaining := time.Duration(0)
+			if fraction > 0 {
+				remaining = time.Duration(float64(elapsed) * (1 - fraction) / fraction)
+			}
+
+			callback(OptimizationProgress{
+				Iteration:        i,
+				CurrentScore:     currentScore,
+				BestScore:        bestScore,
+				AcceptanceRate:   float64(moved) / float64(i+1),
+				EstimatedTime:    remaining.String(),
+				FractionComplete: fraction,
+				WorstTeams:       ts.ConstraintEngine.WorstTeams(currentDraw, ts.WorstTeamsLimit),
+				ConstraintScores: ts.ConstraintEngine.ConstraintScores(currentDraw),
+			})
+		}
+	}
+
+	return &OptimizationResult{
+		InitialScore:      initialScore,
+		FinalScore:        bestScore,
+		Iterations:        iterations,
+		Improvements:      improvements,
+		Duration:          time.Since(startTime),
+		BestDraw:          bestDraw,
+		Seed:              seed,
+		Window:            ts.Window,
+		ScoreCache:        cache.Stats(),
+		TerminationReason: terminationReason,
+	}, nil
+}
+
+// keepRunning reports whether another iteration should run
+func (ts *TabuSearch) keepRunning(iteration int, startTime time.Time) bool {
+	if ts.TimeBudget > 0 {
+		return time.Since(startTime) < ts.TimeBudget
+	}
+	return iteration < ts.MaxIterations
+}
+
+// fractionComplete returns how far through the run the search is, from 0 to 1
+func (ts *TabuSearch) fractionComplete(iteration int, startTime time.Time) float64 {
+	var fraction float64
+	if ts.TimeBudget > 0 {
+		fraction = float64(time.Since(startTime)) / float64(ts.TimeBudget)
+	} else if ts.MaxIterations > 0 {
+		fraction = float64(iteration+1) / float64(ts.MaxIterations)
+	}
+	return math.Min(fraction, 1)
+}
+
+// isTabu reports whether a move changes any match that is still tabu
+func isTabu(changes []int, tabuUntil map[int]int, iteration int) bool {
+	for _, idx := range changes {
+		if until, exists := tabuUntil[idx]; exists && iteration <= until {
+			return true
+		}
+	}
+	return false
+}
+
+// sameIntPtr reports whether two optional IDs hold the same value
+func sameIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,153 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+)
+
+func TestNewTabuSearch(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	ts := NewTabuSearch(100, 0, 0, engine)
+
+	if ts.Tenure != DefaultTabuTenure {
+		t.Errorf("Expected default tenure %d, got %d", DefaultTabuTenure, ts.Tenure)
+	}
+	if ts.NeighborhoodSize != DefaultNeighborhoodSize {
+		t.Errorf("Expected default neighborhood size %d, got %d", DefaultNeighborhoodSize, ts.NeighborhoodSize)
+	}
+	if ts.IterationBudget() != 100 {
+		t.Errorf("Expected an iteration budget of 100, got %d", ts.IterationBudget())
+	}
+}
+
+func TestTabuSearchOptimize(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	ts := NewTabuSearch(50, 5, 10, engine)
+
+	if _, err := ts.Optimize(nil, nil); err == nil {
+		t.Error("Expected error for nil draw")
+	}
+
+	callbackCount := 0
+	result, err := ts.Optimize(createTestDraw(), func(progress OptimizationProgress) {
+		callbackCount++
+		if progress.BestScore < progress.CurrentScore {
+			t.Errorf("Best score %.4f is below the current %.4f", progress.BestScore, progress.CurrentScore)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Iterations != 50 {
+		t.Errorf("Expected 50 iterations, got %d", result.Iterations)
+	}
+	if result.FinalScore < result.InitialScore {
+		t.Errorf("Final score %.4f is worse than the initial %.4f", result.FinalScore, result.InitialScore)
+	}
+	if result.BestDraw == nil {
+		t.Error("Expected best draw in result")
+	}
+	if callbackCount != 5 {
+		t.Errorf("Expected a progress report every %d iterations, got %d reports", tabuProgressInterval, callbackCount)
+	}
+}
+
+func TestTabuSearchOptimize_EarlyStopping(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+
+	ts := NewTabuSearch(50, 5, 10, engine)
+	result, err := ts.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TerminationReason != TerminationMaxIterations {
+		t.Errorf("Expected a max_iterations stop, got %s", result.TerminationReason)
+	}
+
+	ts = NewTabuSearch(100000, 5, 10, engine)
+	ts.EarlyStopping = EarlyStopping{NoImprovementIterations: 20}
+	result, err = ts.Optimize(createTestDraw(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Iterations >= ts.MaxIterations || result.TerminationReason != TerminationNoImprovement {
+		t.Errorf("Expected an early no_improvement stop, got %d iterations stopping at %s",
+			result.Iterations, result.TerminationReason)
+	}
+}
+
+func TestTabuSearchOptimize_Seeded(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	seed := int64(99)
+
+	run := func() *OptimizationResult {
+		ts := NewTabuSearch(40, 5, 10, engine)
+		ts.Seed = &seed
+		result, err := ts.Optimize(createTestDraw(), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result
+	}
+
+	first, second := run(), run()
+	if first.Seed != seed {
+		t.Errorf("Expected result to report seed %d, got %d", seed, first.Seed)
+	}
+	if first.FinalScore != second.FinalScore || first.Improvements != second.Improvements {
+		t.Errorf("Expected identical runs, got scores %.4f/%.4f", first.FinalScore, second.FinalScore)
+	}
+}
+
+func TestTabuMoves(t *testing.T) {
+	draw := createTestDraw()
+	journal := newMoveJournal(draw)
+
+	journal.begin(OperatorSwapMatches)
+	journal.touch(0)
+	if changes := journal.end().indexes(); len(changes) != 0 {
+		t.Errorf("Expected no changes from an untouched match, got %v", changes)
+	}
+
+	journal.begin(OperatorSwapHomeAway)
+	journal.touch(1)
+	journal.touch(2)
+	draw.Matches[1].Round = 3
+	draw.Matches[2].HomeTeamID, draw.Matches[2].AwayTeamID = draw.Matches[2].AwayTeamID, draw.Matches[2].HomeTeamID
+	changes := journal.end().indexes()
+	if len(changes) != 2 || changes[0] != 1 || changes[1] != 2 {
+		t.Fatalf("Expected matches 1 and 2 to change, got %v", changes)
+	}
+
+	tabuUntil := map[int]int{2: 5}
+	if !isTabu(changes, tabuUntil, 5) {
+		t.Error("Expected a move touching match 2 to be tabu through iteration 5")
+	}
+	if isTabu(changes, tabuUntil, 6) {
+		t.Error("Expected match 2 to be free once its tenure expires")
+	}
+}
+
+func TestNewOptimizerFromConfig_TabuSearch(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+
+	config := DefaultOptimizationConfig()
+	config.Algorithm = AlgorithmTabuSearch
+	config.Tabu = TabuConfig{Tenure: 7}
+
+	ts, ok := newOptimizerFromConfig(config, engine).(*TabuSearch)
+	if !ok {
+		t.Fatal("Expected a tabu search optimizer")
+	}
+	if ts.Tenure != 7 || ts.NeighborhoodSize != DefaultNeighborhoodSize {
+		t.Errorf("Expected tenure 7 and the default neighborhood, got %d and %d", ts.Tenure, ts.NeighborhoodSize)
+	}
+	if ts.MaxIterations != config.MaxIterations {
+		t.Errorf("Expected %d iterations, got %d", config.MaxIterations, ts.MaxIterations)
+	}
+}
@@ -0,0 +1,41 @@
+package optimizer
+
+import "time"
+
+// Reasons an optimization run stopped, reported in OptimizationResult
+const (
+	TerminationMaxIterations = "max_iterations"
+	TerminationTimeBudget    = "time_budget"
+	TerminationNoImprovement = "no_improvement"
+	TerminationTargetScore   = "target_score"
+)
+
+// EarlyStopping ends a run before its iteration or time budget is spent.
+// Each rule is off when left at its zero value.
+type EarlyStopping struct {
+	// NoImprovementIterations stops the run once this many iterations pass
+	// without a new best score
+	NoImprovementIterations int `json:"no_improvement_iterations,omitempty"`
+	// TargetScore stops the run once the best score reaches it
+	TargetScore *float64 `json:"target_score,omitempty"`
+}
+
+// stopReason returns why a run should stop early, given its best score and
+// how many iterations have passed since it was found, or "" to carry on
+func (es EarlyStopping) stopReason(bestScore float64, sinceImprovement int) string {
+	if es.TargetScore != nil && bestScore >= *es.TargetScore {
+		return TerminationTargetScore
+	}
+	if es.NoImprovementIterations > 0 && sinceImprovement >= es.NoImprovementIterations {
+		return TerminationNoImprovement
+	}
+	return ""
+}
+
+// budgetReason returns why a run that used its whole budget stopped
+func budgetReason(timeBudget time.Duration) string {
+	if timeBudget > 0 {
+		return TerminationTimeBudget
+	}
+	return TerminationMaxIterations
+}
@@ -9,6 +9,12 @@ type WebSocketBroadcaster interface {
 	BroadcastMessage(messageType string, data interface{})
 }
 
+// JobBroadcaster is implemented by broadcasters that can also reach the
+// clients following a single job
+type JobBroadcaster interface {
+	SendToJob(jobID string, messageType string, data interface{})
+}
+
 // OptimizationBroadcaster handles broadcasting optimization-related events
 type OptimizationBroadcaster struct {
 	wsHub WebSocketBroadcaster
@@ -27,8 +33,8 @@ func (ob *OptimizationBroadcaster) BroadcastOptimizationProgress(jobID string, d
 		return
 	}
 
-	// Calculate percentage progress
-	progressPercent := float64(progress.Iteration) / float64(maxIterations) * 100.0
+	// Time-budgeted runs don't finish at maxIterations, so use the optimizer's own estimate
+	progressPercent := progress.FractionComplete * 100.0
 
 	data := map[string]interface{}{
 		"job_id":           jobID,
@@ -38,11 +44,14 @@ func (ob *OptimizationBroadcaster) BroadcastOptimizationProgress(jobID string, d
 		"current_score":    progress.CurrentScore,
 		"best_score":       progress.BestScore,
 		"temperature":      progress.Temperature,
+		"acceptance_rate":  progress.AcceptanceRate,
 		"progress":         progressPercent,
+		"worst_teams":      progress.WorstTeams,
+		"constraint_scores": progress.ConstraintScores,
 		"updated_at":       time.Now(),
 	}
 
-	ob.wsHub.BroadcastMessage("optimization_progress", data)
+	ob.send(jobID, "optimization_progress", data)
 }
 
 // BroadcastOptimizationCompleted sends optimization completion events
@@ -59,9 +68,10 @@ func (ob *OptimizationBroadcaster) BroadcastOptimizationCompleted(jobID string,
 		"final_score": result.FinalScore,
 		"iterations":  result.Iterations,
 		"improvements": result.Improvements,
+		"termination_reason": result.TerminationReason,
 	}
 
-	ob.wsHub.BroadcastMessage("optimization_completed", data)
+	ob.send(jobID, "optimization_completed", data)
 }
 
 // BroadcastOptimizationFailed sends optimization failure events
@@ -77,5 +87,13 @@ func (ob *OptimizationBroadcaster) BroadcastOptimizationFailed(jobID string, dra
 		"failed_at": time.Now(),
 	}
 
-	ob.wsHub.BroadcastMessage("optimization_failed", data)
-}
\ No newline at end of file
+	ob.send(jobID, "optimization_failed", data)
+}
+
+// send broadcasts a job event to every client and to the job's own subscribers
+func (ob *OptimizationBroadcaster) send(jobID string, messageType string, data interface{}) {
+	ob.wsHub.BroadcastMessage(messageType, data)
+	if jobHub, ok := ob.wsHub.(JobBroadcaster); ok {
+		jobHub.SendToJob(jobID, messageType, data)
+	}
+}
@@ -0,0 +1,349 @@
+package optimizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Weight sampling strategies
+const (
+	// TuningStrategyGrid tries every combination of the weight levels
+	TuningStrategyGrid = "grid"
+	// TuningStrategyRandom draws each weight uniformly from (0, 1]
+	TuningStrategyRandom = "random"
+)
+
+const (
+	// DefaultTuningSamples is how many weight combinations are tried when the
+	// request doesn't say
+	DefaultTuningSamples = 16
+	// MaxTuningSamples caps the combinations one tuning run may try
+	MaxTuningSamples = 100
+	// DefaultTuningIterations is the length of each short optimization
+	DefaultTuningIterations = 500
+	// MaxTuningIterations caps the length of each short optimization
+	MaxTuningIterations = 20000
+)
+
+// DefaultTuningLevels are the weights a grid tries for each soft constraint
+var DefaultTuningLevels = []float64{0.25, 0.5, 0.75, 1.0}
+
+var (
+	// ErrNoSoftConstraints is returned when a draw has no soft weights to tune
+	ErrNoSoftConstraints = errors.New("draw has no soft constraints to tune")
+	// ErrInvalidTuningConfig is returned for tuning settings that can't be run
+	ErrInvalidTuningConfig = errors.New("invalid weight tuning config")
+	// ErrDrawNotGenerated is returned when a draw has no matches to optimize
+	ErrDrawNotGenerated = errors.New("draw has no matches; generate it before tuning weights")
+)
+
+// WeightTuningConfig controls a weight tuning run
+type WeightTuningConfig struct {
+	// Strategy is grid or random. When empty a grid is used if it fits in
+	// Samples, and random sampling otherwise.
+	Strategy string `json:"strategy,omitempty"`
+	// Samples is how many weight combinations to try, besides the current weights
+	Samples int `json:"samples,omitempty"`
+	// Levels are the weights a grid tries for each constraint
+	Levels []float64 `json:"levels,omitempty"`
+	// Iterations is the length of each short optimization
+	Iterations int `json:"iterations,omitempty"`
+	// Seed is shared by every run so samples differ only in their weights
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+// withDefaults fills in unset fields and checks the config against the
+// number of soft constraints being tuned
+func (wc WeightTuningConfig) withDefaults(constraintCount int) (WeightTuningConfig, error) {
+	if wc.Samples == 0 {
+		wc.Samples = DefaultTuningSamples
+	}
+	if wc.Samples < 1 || wc.Samples > MaxTuningSamples {
+		return wc, fmt.Errorf("%w: samples must be between 1 and %d", ErrInvalidTuningConfig, MaxTuningSamples)
+	}
+	if wc.Iterations == 0 {
+		wc.Iterations = DefaultTuningIterations
+	}
+	if wc.Iterations < 1 || wc.Iterations > MaxTuningIterations {
+		return wc, fmt.Errorf("%w: iterations must be between 1 and %d", ErrInvalidTuningConfig, MaxTuningIterations)
+	}
+	if len(wc.Levels) == 0 {
+		wc.Levels = DefaultTuningLevels
+	}
+	for _, level := range wc.Levels {
+		if level <= 0 || level > 1 {
+			return wc, fmt.Errorf("%w: levels must be greater than 0 and at most 1", ErrInvalidTuningConfig)
+		}
+	}
+
+	combinations := gridSize(len(wc.Levels), constraintCount)
+	switch wc.Strategy {
+	case "":
+		wc.Strategy = TuningStrategyRandom
+		if combinations <= wc.Samples {
+			wc.Strategy = TuningStrategyGrid
+		}
+	case TuningStrategyGrid:
+		if combinations > MaxTuningSamples {
+			return wc, fmt.Errorf("%w: a grid of %d levels over %d constraints has %d combinations, more than %d",
+				ErrInvalidTuningConfig, len(wc.Levels), constraintCount, combinations, MaxTuningSamples)
+		}
+	case TuningStrategyRandom:
+	default:
+		return wc, fmt.Errorf("%w: unknown strategy %q", ErrInvalidTuningConfig, wc.Strategy)
+	}
+
+	return wc, nil
+}
+
+// TunedConstraint is a soft constraint whose weight is being tuned
+type TunedConstraint struct {
+	Index         int     `json:"index"` // Position in the configuration's soft constraints
+	Type          string  `json:"type"`
+	CurrentWeight float64 `json:"current_weight"`
+}
+
+// WeightSample is the outcome of a short optimization under one set of weights.
+// Weights and Scores line up with the tuning result's Constraints.
+type WeightSample struct {
+	Weights []float64 `json:"weights"`
+	// Scores are each soft constraint's unweighted score of the optimized draw
+	Scores []float64 `json:"scores"`
+	// OverallScore is the optimized draw's score under the sample's weights
+	OverallScore   float64 `json:"overall_score"`
+	HardViolations int     `json:"hard_violations"`
+	// Current marks the sample run with the draw's configured weights
+	Current bool `json:"current,omitempty"`
+	// Pareto marks samples no other sample beats on every constraint
+	Pareto bool `json:"pareto"`
+}
+
+// WeightTuningResult reports every weight sample tried and the Pareto front
+type WeightTuningResult struct {
+	DrawID      int               `json:"draw_id"`
+	Strategy    string            `json:"strategy"`
+	Iterations  int               `json:"iterations"`
+	Seed        int64             `json:"seed"`
+	Constraints []TunedConstraint `json:"constraints"`
+	Samples     []WeightSample    `json:"samples"`
+	// ParetoFront holds the Pareto samples, best overall score first
+	ParetoFront []WeightSample `json:"pareto_front"`
+	Duration    time.Duration  `json:"duration"`
+}
+
+// TuneWeights runs a short optimization of the draw for each sampled set of
+// soft-constraint weights, plus the draw's current weights, and reports how
+// each constraint scored. The stored draw is not changed.
+func (s *Service) TuneWeights(ctx context.Context, drawID int, config WeightTuningConfig) (*WeightTuningResult, error) {
+	draw, err := s.repository.Draws().GetWithMatches(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	if len(draw.Matches) == 0 {
+		return nil, ErrDrawNotGenerated
+	}
+
+	constraintConfig := constraints.GetDefaultNRLConstraintConfig()
+	if len(draw.ConstraintConfig) > 0 && string(draw.ConstraintConfig) != "null" {
+		if constraintConfig, err = constraints.LoadConstraintConfigFromJSON(draw.ConstraintConfig); err != nil {
+			return nil, fmt.Errorf("failed to load constraint config: %w", err)
+		}
+	}
+	engine, err := constraints.NewConstraintFactory().CreateConstraintEngine(constraintConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load constraint config: %w", err)
+	}
+	league, err := constraints.LoadLeagueData(ctx, s.repository.Teams(), s.repository.Venues())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load teams and venues: %w", err)
+	}
+	engine.SetLeagueData(league)
+	if err := engine.LoadOtherDraws(ctx, s.repository.Draws()); err != nil {
+		return nil, err
+	}
+
+	return TuneWeights(draw, engine, config)
+}
+
+// TuneWeights samples soft-constraint weights for the engine and optimizes a
+// copy of the draw under each. Every run shares one seed, so differences
+// between samples come from the weights alone.
+func TuneWeights(draw *models.Draw, engine *constraints.ConstraintEngine, config WeightTuningConfig) (*WeightTuningResult, error) {
+	startTime := time.Now()
+
+	soft := engine.GetSoftConstraints()
+	if len(soft) == 0 {
+		return nil, ErrNoSoftConstraints
+	}
+	config, err := config.withDefaults(len(soft))
+	if err != nil {
+		return nil, err
+	}
+
+	seed := time.Now().UnixNano()
+	if config.Seed != nil {
+		seed = *config.Seed
+	}
+
+	result := &WeightTuningResult{
+		DrawID:     draw.ID,
+		Strategy:   config.Strategy,
+		Iterations: config.Iterations,
+		Seed:       seed,
+	}
+
+	current := make([]float64, len(soft))
+	for i, weighted := range soft {
+		current[i] = weighted.Weight
+		result.Constraints = append(result.Constraints, TunedConstraint{
+			Index:         i,
+			Type:          constraints.TypeOf(weighted.Constraint),
+			CurrentWeight: weighted.Weight,
+		})
+	}
+
+	var weightSets [][]float64
+	if config.Strategy == TuningStrategyGrid {
+		weightSets = gridWeights(config.Levels, len(soft))
+	} else {
+		weightSets = randomWeights(rand.New(rand.NewSource(seed)), config.Samples, len(soft))
+	}
+	weightSets = append([][]float64{current}, weightSets...)
+
+	for i, weights := range weightSets {
+		sample, err := runWeightSample(draw, engine, weights, config.Iterations, seed)
+		if err != nil {
+			return nil, fmt.Errorf("weight sample %d failed: %w", i, err)
+		}
+		sample.Current = i == 0
+		result.Samples = append(result.Samples, *sample)
+	}
+
+	markParetoFront(result.Samples)
+	for _, sample := range result.Samples {
+		if sample.Pareto {
+			result.ParetoFront = append(result.ParetoFront, sample)
+		}
+	}
+	// Best overall first
+	for i := 0; i < len(result.ParetoFront)-1; i++ {
+		for j := 0; j < len(result.ParetoFront)-i-1; j++ {
+			if result.ParetoFront[j].OverallScore < result.ParetoFront[j+1].OverallScore {
+				result.ParetoFront[j], result.ParetoFront[j+1] = result.ParetoFront[j+1], result.ParetoFront[j]
+			}
+		}
+	}
+
+	result.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// runWeightSample optimizes the draw with the engine's constraints reweighted
+// and scores each soft constraint on the best draw found
+func runWeightSample(draw *models.Draw, engine *constraints.ConstraintEngine, weights []float64, iterations int, seed int64) (*WeightSample, error) {
+	weighted := constraints.NewConstraintEngine()
+	for _, constraint := range engine.GetHardConstraints() {
+		weighted.AddHardConstraint(constraint)
+	}
+	for i, soft := range engine.GetSoftConstraints() {
+		weighted.AddSoftConstraint(soft.Constraint, weights[i])
+	}
+
+	optimizer := NewSimulatedAnnealing(100.0, 0.99, iterations, weighted)
+	optimizer.Seed = &seed
+	run, err := optimizer.Optimize(draw, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := &WeightSample{
+		Weights:        weights,
+		OverallScore:   run.FinalScore,
+		HardViolations: len(weighted.ValidateDraw(run.BestDraw)),
+	}
+	for _, soft := range weighted.GetSoftConstraints() {
+		sample.Scores = append(sample.Scores, soft.Constraint.Score(run.BestDraw))
+	}
+	return sample, nil
+}
+
+// markParetoFront flags the samples that no other sample dominates. A sample
+// dominates another when it has no more hard violations and scores at least as
+// well on every constraint, and is strictly better somewhere.
+func markParetoFront(samples []WeightSample) {
+	for i := range samples {
+		samples[i].Pareto = true
+		for j := range samples {
+			if i != j && dominates(samples[j], samples[i]) {
+				samples[i].Pareto = false
+				break
+			}
+		}
+	}
+}
+
+// dominates reports whether sample a dominates sample b
+func dominates(a, b WeightSample) bool {
+	if a.HardViolations > b.HardViolations {
+		return false
+	}
+	better := a.HardViolations < b.HardViolations
+	for i := range a.Scores {
+		if a.Scores[i] < b.Scores[i] {
+			return false
+		}
+		if a.Scores[i] > b.Scores[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// gridSize is how many combinations a grid of levels has over the constraints,
+// capped just past MaxTuningSamples so large grids don't overflow
+func gridSize(levels, constraintCount int) int {
+	size := 1
+	for i := 0; i < constraintCount; i++ {
+		size *= levels
+		if size > MaxTuningSamples {
+			return MaxTuningSamples + 1
+		}
+	}
+	return size
+}
+
+// gridWeights returns every combination of the levels over the constraints
+func gridWeights(levels []float64, constraintCount int) [][]float64 {
+	combinations := [][]float64{{}}
+	for i := 0; i < constraintCount; i++ {
+		var next [][]float64
+		for _, combination := range combinations {
+			for _, level := range levels {
+				weights := append(append([]float64{}, combination...), level)
+				next = append(next, weights)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// randomWeights draws sets of weights uniformly from (0, 1], rounded to two
+// decimal places so they can be copied into a configuration as reported
+func randomWeights(rng *rand.Rand, samples, constraintCount int) [][]float64 {
+	sets := make([][]float64, samples)
+	for i := range sets {
+		sets[i] = make([]float64, constraintCount)
+		for j := range sets[i] {
+			sets[i][j] = math.Max(0.01, math.Round((1-rng.Float64())*100)/100)
+		}
+	}
+	return sets
+}
@@ -0,0 +1,114 @@
+package optimizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+)
+
+func TestTuneWeightsGrid(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 0.7)
+	engine.AddSoftConstraint(constraints.NewPrimeTimeSpreadConstraint(0.3, 0.1), 0.3)
+
+	seed := int64(7)
+	result, err := TuneWeights(createTestDraw(), engine, WeightTuningConfig{
+		Levels:     []float64{0.5, 1.0},
+		Iterations: 100,
+		Seed:       &seed,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Two levels over two constraints fit the default sample count
+	if result.Strategy != TuningStrategyGrid {
+		t.Errorf("Expected a grid, got %s", result.Strategy)
+	}
+	if len(result.Constraints) != 2 || result.Constraints[0].Type != "home_away_balance" || result.Constraints[0].CurrentWeight != 0.7 {
+		t.Errorf("Unexpected tuned constraints: %+v", result.Constraints)
+	}
+	if len(result.Samples) != 5 {
+		t.Fatalf("Expected the current weights plus 4 grid samples, got %d", len(result.Samples))
+	}
+	if !result.Samples[0].Current || result.Samples[0].Weights[0] != 0.7 {
+		t.Errorf("Expected the first sample to use the current weights, got %+v", result.Samples[0])
+	}
+
+	if len(result.ParetoFront) == 0 {
+		t.Fatal("Expected a non-empty Pareto front")
+	}
+	for i, sample := range result.ParetoFront {
+		if i > 0 && sample.OverallScore > result.ParetoFront[i-1].OverallScore {
+			t.Error("Expected the Pareto front ordered by overall score")
+		}
+		for _, other := range result.Samples {
+			if dominates(other, sample) {
+				t.Errorf("Pareto sample %v is dominated by %v", sample.Weights, other.Weights)
+			}
+		}
+	}
+
+	// The same seed reproduces the same scores
+	again, err := TuneWeights(createTestDraw(), engine, WeightTuningConfig{
+		Levels:     []float64{0.5, 1.0},
+		Iterations: 100,
+		Seed:       &seed,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := range result.Samples {
+		if again.Samples[i].OverallScore != result.Samples[i].OverallScore {
+			t.Errorf("Sample %d scored %.4f then %.4f with the same seed", i, result.Samples[i].OverallScore, again.Samples[i].OverallScore)
+		}
+	}
+}
+
+func TestTuneWeightsRandomAndErrors(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+
+	result, err := TuneWeights(createTestDraw(), engine, WeightTuningConfig{
+		Strategy:   TuningStrategyRandom,
+		Samples:    3,
+		Iterations: 50,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Samples) != 4 {
+		t.Fatalf("Expected the current weights plus 3 random samples, got %d", len(result.Samples))
+	}
+	for _, sample := range result.Samples {
+		if sample.Weights[0] <= 0 || sample.Weights[0] > 1 {
+			t.Errorf("Random weight %.2f outside (0, 1]", sample.Weights[0])
+		}
+	}
+
+	if _, err := TuneWeights(createTestDraw(), constraints.NewConstraintEngine(), WeightTuningConfig{}); !errors.Is(err, ErrNoSoftConstraints) {
+		t.Errorf("Expected ErrNoSoftConstraints, got %v", err)
+	}
+
+	invalid := []WeightTuningConfig{
+		{Strategy: "annealed"},
+		{Samples: MaxTuningSamples + 1},
+		{Levels: []float64{0, 0.5}},
+		{Iterations: -1},
+	}
+	for _, config := range invalid {
+		if _, err := TuneWeights(createTestDraw(), engine, config); !errors.Is(err, ErrInvalidTuningConfig) {
+			t.Errorf("Expected ErrInvalidTuningConfig for %+v, got %v", config, err)
+		}
+	}
+
+	// A grid larger than the cap is rejected rather than truncated
+	wide := constraints.NewConstraintEngine()
+	for i := 0; i < 4; i++ {
+		wide.AddSoftConstraint(constraints.NewHomeAwayBalanceConstraint(0.1), 1.0)
+	}
+	if _, err := TuneWeights(createTestDraw(), wide, WeightTuningConfig{Strategy: TuningStrategyGrid}); !errors.Is(err, ErrInvalidTuningConfig) {
+		t.Errorf("Expected a 256 combination grid to be rejected, got %v", err)
+	}
+}
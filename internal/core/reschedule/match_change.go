@@ -0,0 +1,162 @@
+package reschedule
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// MatchChange is a manual edit to a match. Nil fields are left unchanged.
+type MatchChange struct {
+	Round       *int
+	HomeTeamID  *int
+	AwayTeamID  *int
+	VenueID     *int
+	MatchDate   *time.Time
+	MatchTime   *time.Time
+	IsPrimeTime *bool
+}
+
+// IsEmpty reports whether the change edits nothing
+func (c MatchChange) IsEmpty() bool {
+	return c.Round == nil && c.HomeTeamID == nil && c.AwayTeamID == nil && c.VenueID == nil &&
+		c.MatchDate == nil && c.MatchTime == nil && c.IsPrimeTime == nil
+}
+
+// Apply returns a copy of the match with the change made, leaving the
+// original untouched
+func (c MatchChange) Apply(match *models.Match) *models.Match {
+	changed := *match
+	if c.Round != nil {
+		changed.Round = *c.Round
+	}
+	if c.HomeTeamID != nil {
+		changed.HomeTeamID = c.HomeTeamID
+		changed.HomeTeam = nil
+	}
+	if c.AwayTeamID != nil {
+		changed.AwayTeamID = c.AwayTeamID
+		changed.AwayTeam = nil
+	}
+	if c.VenueID != nil {
+		changed.VenueID = c.VenueID
+		changed.Venue = nil
+	}
+	if c.MatchDate != nil {
+		changed.MatchDate = c.MatchDate
+	}
+	if c.MatchTime != nil {
+		changed.MatchTime = c.MatchTime
+	}
+	if c.IsPrimeTime != nil {
+		changed.IsPrimeTime = *c.IsPrimeTime
+	}
+	return &changed
+}
+
+// MatchChangeImpact is what a manual edit does to its draw's constraints
+type MatchChangeImpact struct {
+	// Match is the match as it is after the edit
+	Match *models.Match
+	// Conflicts are clashes no constraint covers: the venue or a team booked
+	// twice on the same day or in the same round
+	Conflicts          []string
+	NewViolations      []constraints.ConstraintViolation
+	ResolvedViolations []constraints.ConstraintViolation
+	ScoreBefore        float64
+	ScoreAfter         float64
+	ScoreChange        float64
+	Applied            bool
+}
+
+// Breaking reports whether the edit introduces conflicts or hard constraint
+// violations
+func (i *MatchChangeImpact) Breaking() bool {
+	if len(i.Conflicts) > 0 {
+		return true
+	}
+	for _, violation := range i.NewViolations {
+		if violation.Severity == constraints.SeverityHard {
+			return true
+		}
+	}
+	return false
+}
+
+// PreviewMatchChange analyzes and scores the draw as it is and with the
+// edited match in place. Violations in both
+// analyses are left out, so only the ones the edit causes or fixes are
+// reported.
+func PreviewMatchChange(draw *models.Draw, changed *models.Match, engine *constraints.ConstraintEngine) *MatchChangeImpact {
+	trial := withMatch(draw, changed)
+
+	impact := &MatchChangeImpact{
+		Match:       changed,
+		ScoreBefore: engine.ScoreDraw(draw),
+		ScoreAfter:  engine.ScoreDraw(trial),
+	}
+	impact.ScoreChange = impact.ScoreAfter - impact.ScoreBefore
+
+	if changed.VenueID != nil {
+		impact.Conflicts = FindVenueConflicts(draw, changed, *changed.VenueID)
+	}
+	impact.Conflicts = append(impact.Conflicts, findTeamClashes(draw, changed)...)
+
+	before := engine.AnalyzeDraw(draw)
+	after := engine.AnalyzeDraw(trial)
+	impact.NewViolations = violationsNotIn(after, before)
+	impact.ResolvedViolations = violationsNotIn(before, after)
+
+	return impact
+}
+
+// findTeamClashes returns the other matches either team already plays in the
+// match's round
+func findTeamClashes(draw *models.Draw, match *models.Match) []string {
+	if match.IsBye() {
+		return nil
+	}
+
+	var clashes []string
+	for _, other := range draw.Matches {
+		if other.ID == match.ID || other.IsBye() || other.Round != match.Round {
+			continue
+		}
+		for _, teamID := range []int{*match.HomeTeamID, *match.AwayTeamID} {
+			if other.HasTeam(teamID) {
+				clashes = append(clashes, fmt.Sprintf("team %d already plays match %d in round %d",
+					teamID, other.ID, other.Round))
+			}
+		}
+	}
+	return clashes
+}
+
+// violationsNotIn returns the violations in a that aren't in b. Repeats are
+// counted, so a constraint broken twice where it was broken once is reported.
+func violationsNotIn(a, b []constraints.ConstraintViolation) []constraints.ConstraintViolation {
+	remaining := make(map[string]int, len(b))
+	for _, violation := range b {
+		remaining[violationKey(violation)]++
+	}
+
+	var missing []constraints.ConstraintViolation
+	for _, violation := range a {
+		key := violationKey(violation)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		missing = append(missing, violation)
+	}
+	return missing
+}
+
+// violationKey identifies a violation across two analyses of the same draw
+func violationKey(violation constraints.ConstraintViolation) string {
+	return violation.ConstraintName + "|" + string(violation.Severity) + "|" +
+		strconv.Itoa(violation.MatchID) + "|" + violation.Description
+}
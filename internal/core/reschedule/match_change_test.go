@@ -0,0 +1,90 @@
+package reschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestPreviewMatchChange(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	// Teams 1 and 2 meet in rounds 1 and 4, far enough apart for the
+	// double-up rule
+	first := &models.Match{ID: 1, Round: 1, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2), VenueID: intPtr(1), MatchDate: day(6)}
+	draw := &models.Draw{ID: 1, Rounds: 4, Matches: []*models.Match{
+		first,
+		{ID: 2, Round: 1, HomeTeamID: intPtr(3), AwayTeamID: intPtr(4), VenueID: intPtr(2), MatchDate: day(8)},
+		{ID: 3, Round: 2, HomeTeamID: intPtr(1), AwayTeamID: intPtr(3), VenueID: intPtr(1), MatchDate: day(13)},
+		{ID: 4, Round: 4, HomeTeamID: intPtr(2), AwayTeamID: intPtr(1), VenueID: intPtr(2), MatchDate: day(27)},
+	}}
+
+	engine := constraints.NewConstraintEngine()
+	engine.AddHardConstraint(constraints.NewDoubleUpConstraint(3))
+
+	// Moving the rematch to round 3 brings the teams within three rounds
+	rematch := MatchChange{Round: intPtr(3)}.Apply(draw.Matches[3])
+	impact := PreviewMatchChange(draw, rematch, engine)
+
+	if hardViolations(impact.NewViolations) != 2 || impact.NewViolations[0].ConstraintName != "DoubleUpConstraint" {
+		t.Fatalf("Expected the double-up to be broken by both meetings, got %+v", impact.NewViolations)
+	}
+	if len(impact.ResolvedViolations) != 0 {
+		t.Errorf("Expected no violations resolved, got %+v", impact.ResolvedViolations)
+	}
+	if !impact.Breaking() {
+		t.Error("Expected a new hard violation to break the draw")
+	}
+	if impact.ScoreChange != impact.ScoreAfter-impact.ScoreBefore {
+		t.Errorf("Expected the score change to be the difference of the scores, got %+v", impact)
+	}
+
+	// Moving the round 1 match to 8 March at venue 2 double-books the venue
+	moved := MatchChange{VenueID: intPtr(2), MatchDate: day(8)}.Apply(first)
+	impact = PreviewMatchChange(draw, moved, engine)
+	if len(impact.Conflicts) != 1 || !impact.Breaking() {
+		t.Errorf("Expected the venue double-booking as a conflict, got %+v", impact.Conflicts)
+	}
+
+	// Moving it to round 2 clashes with team 1's match against team 3
+	clash := MatchChange{Round: intPtr(2)}.Apply(first)
+	impact = PreviewMatchChange(draw, clash, engine)
+	if len(impact.Conflicts) != 1 {
+		t.Errorf("Expected team 1 playing twice in round 2 as a conflict, got %+v", impact.Conflicts)
+	}
+
+	// Changing only the kick-off date leaves the draw valid
+	impact = PreviewMatchChange(draw, MatchChange{MatchDate: day(7)}.Apply(first), engine)
+	if impact.Breaking() || len(impact.NewViolations) != 0 {
+		t.Errorf("Expected a harmless change, got conflicts %v and violations %+v", impact.Conflicts, impact.NewViolations)
+	}
+
+	// Previewing must not modify the original match or draw
+	if first.Round != 1 || *first.VenueID != 1 || draw.Matches[0] != first {
+		t.Error("Previewing should not modify the original match or draw")
+	}
+
+	// Fixing the draw reports the violation as resolved
+	broken := &models.Draw{ID: 1, Rounds: 4, Matches: []*models.Match{first, rematch}}
+	impact = PreviewMatchChange(broken, MatchChange{Round: intPtr(4)}.Apply(rematch), engine)
+	if hardViolations(impact.ResolvedViolations) != 2 || len(impact.NewViolations) != 0 || impact.Breaking() {
+		t.Errorf("Expected both double-up violations resolved, got resolved %+v and new %+v",
+			impact.ResolvedViolations, impact.NewViolations)
+	}
+}
+
+// hardViolations counts the hard violations in a list
+func hardViolations(violations []constraints.ConstraintViolation) int {
+	count := 0
+	for _, violation := range violations {
+		if violation.Severity == constraints.SeverityHard {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,122 @@
+package reschedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// PostponementOption is a candidate round and date to replay a postponed match
+type PostponementOption struct {
+	Round     int        `json:"round"`
+	MatchDate *time.Time `json:"match_date,omitempty"`
+	Available bool       `json:"available"`
+	Conflicts []string   `json:"conflicts,omitempty"`
+	// DelayDays is how long after the original date the match is replayed,
+	// 0 when the option has no date
+	DelayDays        int     `json:"delay_days"`
+	ScoreBefore      float64 `json:"score_before"`
+	ScoreAfter       float64 `json:"score_after"`
+	ConstraintImpact float64 `json:"constraint_impact"`
+}
+
+// RankPostponementOptions finds where a postponed match could be replayed and
+// returns the options best first. The match is given as it was before being
+// postponed. Options are the dates already used by later matches in rounds
+// where neither team plays, including the match's own round, and rounds after
+// it with no dates yet. Each is checked against the venue's bookings and the
+// engine's hard constraints, such as team availability, and ranked by the
+// draw's soft score. Unavailable options are ranked last.
+func RankPostponementOptions(draw *models.Draw, match *models.Match, engine *constraints.ConstraintEngine) []PostponementOption {
+	freed := *match
+	freed.MatchDate = nil
+	freed.MatchTime = nil
+	freed.IsPrimeTime = false
+	scoreBefore := engine.ScoreDraw(withMatch(draw, &freed))
+
+	busy := make(map[int]bool)
+	dates := make(map[int]map[string]time.Time)
+	for _, other := range draw.Matches {
+		if other.ID == match.ID || other.IsBye() {
+			continue
+		}
+		if other.HasTeam(*match.HomeTeamID) || other.HasTeam(*match.AwayTeamID) {
+			busy[other.Round] = true
+		}
+		if other.MatchDate == nil || (match.MatchDate != nil && !other.MatchDate.After(*match.MatchDate)) {
+			continue
+		}
+		if dates[other.Round] == nil {
+			dates[other.Round] = make(map[string]time.Time)
+		}
+		dates[other.Round][other.MatchDate.Format("2006-01-02")] = *other.MatchDate
+	}
+
+	var options []PostponementOption
+	consider := func(round int, date *time.Time) {
+		moved := *match
+		moved.Round = round
+		moved.MatchDate = date
+		if date == nil {
+			moved.MatchTime = nil
+			moved.IsPrimeTime = false
+		}
+		trial := withMatch(draw, &moved)
+
+		option := PostponementOption{
+			Round:       round,
+			MatchDate:   date,
+			ScoreBefore: scoreBefore,
+		}
+		if match.VenueID != nil {
+			option.Conflicts = FindVenueConflicts(draw, &moved, *match.VenueID)
+		}
+		if err := engine.ValidateMatch(&moved, trial); err != nil {
+			option.Conflicts = append(option.Conflicts, err.Error())
+		}
+		option.Available = len(option.Conflicts) == 0
+		if date != nil && match.MatchDate != nil {
+			option.DelayDays = int(date.Sub(*match.MatchDate).Hours() / 24)
+		}
+
+		option.ScoreAfter = engine.ScoreDraw(trial)
+		option.ConstraintImpact = option.ScoreAfter - scoreBefore
+
+		options = append(options, option)
+	}
+
+	for round := 1; round <= draw.Rounds; round++ {
+		if busy[round] || round < match.Round {
+			continue
+		}
+		if len(dates[round]) == 0 {
+			if round > match.Round {
+				consider(round, nil)
+			}
+			continue
+		}
+		for _, date := range dates[round] {
+			date := date
+			consider(round, &date)
+		}
+	}
+
+	// Best first: available, then highest score, then soonest
+	sort.SliceStable(options, func(i, j int) bool {
+		a, b := options[i], options[j]
+		if a.Available != b.Available {
+			return a.Available
+		}
+		if a.ScoreAfter != b.ScoreAfter {
+			return a.ScoreAfter > b.ScoreAfter
+		}
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.MatchDate != nil && (b.MatchDate == nil || a.MatchDate.Before(*b.MatchDate))
+	})
+
+	return options
+}
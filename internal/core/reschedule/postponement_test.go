@@ -0,0 +1,56 @@
+package reschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestRankPostponementOptions(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	// Teams 1 and 2 wash out in round 1. Team 1 plays in round 2, both are
+	// free in rounds 3 and 4, and round 4 has no dates yet.
+	washout := &models.Match{ID: 1, Round: 1, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2), VenueID: intPtr(1), MatchDate: day(6)}
+	draw := &models.Draw{ID: 1, Rounds: 4, Matches: []*models.Match{
+		washout,
+		{ID: 2, Round: 1, HomeTeamID: intPtr(3), AwayTeamID: intPtr(4), VenueID: intPtr(2), MatchDate: day(8)},
+		{ID: 3, Round: 2, HomeTeamID: intPtr(1), AwayTeamID: intPtr(3), VenueID: intPtr(1), MatchDate: day(13)},
+		{ID: 4, Round: 3, HomeTeamID: intPtr(3), AwayTeamID: intPtr(4), VenueID: intPtr(1), MatchDate: day(20)},
+		{ID: 5, Round: 3, HomeTeamID: intPtr(5), AwayTeamID: intPtr(6), VenueID: intPtr(2), MatchDate: day(22)},
+		{ID: 6, Round: 4, HomeTeamID: intPtr(3), AwayTeamID: intPtr(4)},
+	}}
+
+	options := RankPostponementOptions(draw, washout, constraints.NewConstraintEngine())
+
+	if len(options) != 4 {
+		t.Fatalf("Expected options on 8, 20 and 22 March and in round 4, got %+v", options)
+	}
+
+	// Round 3 on the 20th clashes with match 4 at the same venue
+	last := options[3]
+	if last.Round != 3 || last.Available || len(last.Conflicts) != 1 {
+		t.Errorf("Expected the double-booked 20 March option last, got %+v", last)
+	}
+
+	// Equal scores rank the soonest round first
+	if options[0].Round != 1 || !options[0].MatchDate.Equal(*day(8)) || options[0].DelayDays != 2 {
+		t.Errorf("Expected 8 March in round 1 first, got %+v", options[0])
+	}
+	if options[1].Round != 3 || !options[1].MatchDate.Equal(*day(22)) {
+		t.Errorf("Expected 22 March in round 3 second, got %+v", options[1])
+	}
+	if options[2].Round != 4 || options[2].MatchDate != nil || !options[2].Available {
+		t.Errorf("Expected undated round 4 third, got %+v", options[2])
+	}
+
+	// The original draw must not be modified while ranking
+	if washout.Round != 1 || !washout.MatchDate.Equal(*day(6)) || draw.Matches[0] != washout {
+		t.Error("Ranking should not modify the original match or draw")
+	}
+}
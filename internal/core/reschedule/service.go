@@ -0,0 +1,300 @@
+package reschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// Errors returned when a venue substitution, postponement or manual change
+// can't be made
+var (
+	ErrByeMatch          = errors.New("bye matches have no venue")
+	ErrSameVenue         = errors.New("match is already scheduled at this venue")
+	ErrVenueUnavailable  = errors.New("venue is not available")
+	ErrMatchLocked       = errors.New("match is locked")
+	ErrNotScheduled      = errors.New("match has no date to postpone")
+	ErrEmptyChange       = errors.New("no match changes given")
+	ErrInvalidChange     = errors.New("invalid match change")
+	ErrBreaksConstraints = errors.New("change breaks hard constraints")
+)
+
+// Service handles emergency changes to individual matches in a draw
+type Service struct {
+	repository storage.Repositories
+}
+
+// NewService creates a new reschedule service
+func NewService(repository storage.Repositories) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// FindVenueSubstitutes ranks replacement venues for a match. A limit of zero returns every candidate.
+func (s *Service) FindVenueSubstitutes(ctx context.Context, matchID int, limit int) (*models.Match, []VenueCandidate, error) {
+	match, draw, err := s.loadMatch(ctx, s.repository, matchID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates, err := s.rank(ctx, s.repository, draw, match)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	return match, candidates, nil
+}
+
+// ApplyVenueSubstitution moves a match to a new venue. Availability is re-checked
+// inside a transaction so a concurrent booking can't slip in between ranking and applying.
+func (s *Service) ApplyVenueSubstitution(ctx context.Context, matchID, venueID int) (*models.Match, error) {
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Venues().Get(ctx, venueID); err != nil {
+		return nil, err
+	}
+
+	match, draw, err := s.loadMatch(ctx, tx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if match.Locked {
+		return nil, ErrMatchLocked
+	}
+	if match.VenueID != nil && *match.VenueID == venueID {
+		return nil, ErrSameVenue
+	}
+
+	engine, err := s.loadEngine(ctx, tx, draw)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := FindVenueConflicts(draw, match, venueID)
+	substituted, trial := substituteVenue(draw, match, venueID)
+	if err := engine.ValidateMatch(substituted, trial); err != nil {
+		conflicts = append(conflicts, err.Error())
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrVenueUnavailable, strings.Join(conflicts, "; "))
+	}
+
+	if err := tx.Matches().Update(ctx, substituted); err != nil {
+		return nil, fmt.Errorf("failed to update match: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit venue substitution: %w", err)
+	}
+
+	return substituted, nil
+}
+
+// PostponeMatch frees a washed-out match's date and kick-off slot, then ranks
+// the rounds and dates it could be replayed in. A limit of zero returns every
+// option. The match keeps its round and venue.
+func (s *Service) PostponeMatch(ctx context.Context, matchID int, limit int) (*models.Match, []PostponementOption, error) {
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	match, draw, err := s.loadMatch(ctx, tx, matchID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if match.Locked {
+		return nil, nil, ErrMatchLocked
+	}
+	if !match.IsScheduled() {
+		return nil, nil, ErrNotScheduled
+	}
+
+	engine, err := s.loadEngine(ctx, tx, draw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	postponed := *match
+	postponed.MatchDate = nil
+	postponed.MatchTime = nil
+	postponed.IsPrimeTime = false
+	if err := tx.Matches().Update(ctx, &postponed); err != nil {
+		return nil, nil, fmt.Errorf("failed to update match: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit postponement: %w", err)
+	}
+
+	options := RankPostponementOptions(draw, match, engine)
+	if limit > 0 && len(options) > limit {
+		options = options[:limit]
+	}
+
+	return &postponed, options, nil
+}
+
+// SetMatchLocked pins a match so generation and optimization leave it alone,
+// or releases it again
+func (s *Service) SetMatchLocked(ctx context.Context, matchID int, locked bool) (*models.Match, error) {
+	if err := s.repository.Matches().SetLocked(ctx, matchID, locked); err != nil {
+		return nil, err
+	}
+
+	return s.repository.Matches().GetWithRelations(ctx, matchID)
+}
+
+// ChangeMatch edits a match by hand and reports what the edit does to the
+// draw's constraints. A dry run only reports. Otherwise the edit is saved
+// unless it introduces conflicts or hard violations, in which case the impact
+// is returned with ErrBreaksConstraints; force saves it anyway.
+func (s *Service) ChangeMatch(ctx context.Context, matchID int, change MatchChange, dryRun, force bool) (*MatchChangeImpact, error) {
+	if change.IsEmpty() {
+		return nil, ErrEmptyChange
+	}
+
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	match, draw, err := s.loadMatch(ctx, tx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if match.Locked {
+		return nil, ErrMatchLocked
+	}
+
+	changed := change.Apply(match)
+	if err := changed.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChange, err)
+	}
+	if draw.Rounds > 0 && changed.Round > draw.Rounds {
+		return nil, fmt.Errorf("%w: draw only has %d rounds", ErrInvalidChange, draw.Rounds)
+	}
+
+	// Load the teams and venue, which also checks the ones given exist
+	if changed.HomeTeam, err = tx.Teams().Get(ctx, *changed.HomeTeamID); err != nil {
+		return nil, err
+	}
+	if changed.AwayTeam, err = tx.Teams().Get(ctx, *changed.AwayTeamID); err != nil {
+		return nil, err
+	}
+	if changed.Venue, err = tx.Venues().Get(ctx, *changed.VenueID); err != nil {
+		return nil, err
+	}
+
+	engine, err := s.loadEngine(ctx, tx, draw)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := PreviewMatchChange(draw, changed, engine)
+	if dryRun {
+		return impact, nil
+	}
+	if impact.Breaking() && !force {
+		return impact, ErrBreaksConstraints
+	}
+
+	if err := tx.Matches().Update(ctx, changed); err != nil {
+		return nil, fmt.Errorf("failed to update match: %w", err)
+	}
+	if err := tx.Matches().SyncByes(ctx, changed.DrawID); err != nil {
+		return nil, fmt.Errorf("failed to update byes: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit match change: %w", err)
+	}
+
+	impact.Applied = true
+	return impact, nil
+}
+
+// loadEngine builds the draw's constraint engine with the league's teams and
+// venues and the other draws it is checked against
+func (s *Service) loadEngine(ctx context.Context, repos storage.Repositories, draw *models.Draw) (*constraints.ConstraintEngine, error) {
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		return nil, err
+	}
+	league, err := constraints.LoadLeagueData(ctx, repos.Teams(), repos.Venues())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load teams and venues: %w", err)
+	}
+	engine.SetLeagueData(league)
+	if err := engine.LoadOtherDraws(ctx, repos.Draws()); err != nil {
+		return nil, err
+	}
+	if err := engine.LoadCalendar(ctx, repos.SeasonCalendars(), draw); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// loadMatch fetches a match along with the draw it belongs to
+func (s *Service) loadMatch(ctx context.Context, repos storage.Repositories, matchID int) (*models.Match, *models.Draw, error) {
+	match, err := repos.Matches().Get(ctx, matchID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if match.IsBye() {
+		return nil, nil, ErrByeMatch
+	}
+
+	draw, err := repos.Draws().GetWithMatches(ctx, match.DrawID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return match, draw, nil
+}
+
+// rank loads venues, teams and constraints for the draw and ranks substitutes
+func (s *Service) rank(ctx context.Context, repos storage.Repositories, draw *models.Draw, match *models.Match) ([]VenueCandidate, error) {
+	venues, err := repos.Venues().List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list venues: %w", err)
+	}
+
+	teamList, err := repos.Teams().List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	teams := make(map[int]*models.Team, len(teamList))
+	for _, team := range teamList {
+		teams[team.ID] = team
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		return nil, err
+	}
+	engine.SetLeagueData(constraints.NewLeagueData(teamList, venues))
+	if err := engine.LoadOtherDraws(ctx, repos.Draws()); err != nil {
+		return nil, err
+	}
+
+	return RankVenueSubstitutes(draw, match, venues, teams, engine), nil
+}
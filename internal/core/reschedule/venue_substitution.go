@@ -0,0 +1,203 @@
+package reschedule
+
+import (
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Weights applied to each component of a venue candidate's composite score
+const (
+	CapacityFitWeight      = 0.3
+	DistanceWeight         = 0.3
+	ConstraintImpactWeight = 0.4
+
+	// DistanceScaleKm is the average fan travel distance at which the distance score halves
+	DistanceScaleKm = 500.0
+)
+
+// VenueCandidate is a ranked replacement venue for a match
+type VenueCandidate struct {
+	Venue            *models.Venue `json:"venue"`
+	Available        bool          `json:"available"`
+	Conflicts        []string      `json:"conflicts,omitempty"`
+	CapacityFit      float64       `json:"capacity_fit"`
+	HomeDistanceKm   *float64      `json:"home_distance_km,omitempty"`
+	AwayDistanceKm   *float64      `json:"away_distance_km,omitempty"`
+	DistanceScore    float64       `json:"distance_score"`
+	ScoreBefore      float64       `json:"score_before"`
+	ScoreAfter       float64       `json:"score_after"`
+	ConstraintImpact float64       `json:"constraint_impact"`
+	Score            float64       `json:"score"`
+}
+
+// RankVenueSubstitutes scores every venue other than the match's current one as a
+// replacement and returns them best first. Unavailable venues are ranked last.
+func RankVenueSubstitutes(draw *models.Draw, match *models.Match, venues []*models.Venue, teams map[int]*models.Team, engine *constraints.ConstraintEngine) []VenueCandidate {
+	var original *models.Venue
+	for _, venue := range venues {
+		if match.VenueID != nil && venue.ID == *match.VenueID {
+			original = venue
+			break
+		}
+	}
+
+	scoreBefore := engine.ScoreDraw(draw)
+
+	var candidates []VenueCandidate
+	for _, venue := range venues {
+		if match.VenueID != nil && venue.ID == *match.VenueID {
+			continue
+		}
+
+		candidate := VenueCandidate{
+			Venue:       venue,
+			CapacityFit: capacityFit(original, venue),
+			ScoreBefore: scoreBefore,
+		}
+
+		candidate.HomeDistanceKm = teamDistance(teams, match.HomeTeamID, venue)
+		candidate.AwayDistanceKm = teamDistance(teams, match.AwayTeamID, venue)
+		candidate.DistanceScore = distanceScore(candidate.HomeDistanceKm, candidate.AwayDistanceKm)
+
+		substituted, trial := substituteVenue(draw, match, venue.ID)
+		candidate.Conflicts = FindVenueConflicts(draw, match, venue.ID)
+		if err := engine.ValidateMatch(substituted, trial); err != nil {
+			candidate.Conflicts = append(candidate.Conflicts, err.Error())
+		}
+		candidate.Available = len(candidate.Conflicts) == 0
+
+		candidate.ScoreAfter = engine.ScoreDraw(trial)
+		candidate.ConstraintImpact = candidate.ScoreAfter - scoreBefore
+
+		if candidate.Available {
+			candidate.Score = CapacityFitWeight*candidate.CapacityFit +
+				DistanceWeight*candidate.DistanceScore +
+				ConstraintImpactWeight*candidate.ScoreAfter
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	// Sort best first, keeping unavailable venues at the bottom
+	for i := 0; i < len(candidates)-1; i++ {
+		for j := 0; j < len(candidates)-i-1; j++ {
+			if candidateLess(candidates[j], candidates[j+1]) {
+				candidates[j], candidates[j+1] = candidates[j+1], candidates[j]
+			}
+		}
+	}
+
+	return candidates
+}
+
+// FindVenueConflicts returns the matches already booked at a venue on the same
+// date as the given match, or in the same round when the match has no date
+func FindVenueConflicts(draw *models.Draw, match *models.Match, venueID int) []string {
+	var conflicts []string
+	for _, other := range draw.Matches {
+		if other.ID == match.ID || other.IsBye() || other.VenueID == nil || *other.VenueID != venueID {
+			continue
+		}
+
+		if match.MatchDate != nil && other.MatchDate != nil {
+			if match.MatchDate.Format("2006-01-02") == other.MatchDate.Format("2006-01-02") {
+				conflicts = append(conflicts, fmt.Sprintf("venue already hosts match %d on %s",
+					other.ID, other.MatchDate.Format("2006-01-02")))
+			}
+			continue
+		}
+
+		if other.Round == match.Round {
+			conflicts = append(conflicts, fmt.Sprintf("venue already hosts match %d in round %d",
+				other.ID, other.Round))
+		}
+	}
+	return conflicts
+}
+
+// candidateLess reports whether a should be ranked below b
+func candidateLess(a, b VenueCandidate) bool {
+	if a.Available != b.Available {
+		return !a.Available
+	}
+	return a.Score < b.Score
+}
+
+// substituteVenue returns a copy of the match at the new venue and a shallow copy
+// of the draw containing it, leaving the originals untouched
+func substituteVenue(draw *models.Draw, match *models.Match, venueID int) (*models.Match, *models.Draw) {
+	substituted := *match
+	substituted.VenueID = &venueID
+	substituted.Venue = nil
+
+	return &substituted, withMatch(draw, &substituted)
+}
+
+// withMatch returns a shallow copy of the draw with the match of the same ID
+// replaced, leaving the original untouched
+func withMatch(draw *models.Draw, replacement *models.Match) *models.Draw {
+	trial := *draw
+	trial.Matches = make([]*models.Match, len(draw.Matches))
+	for i, m := range draw.Matches {
+		if m.ID == replacement.ID {
+			trial.Matches[i] = replacement
+		} else {
+			trial.Matches[i] = m
+		}
+	}
+
+	return &trial
+}
+
+// capacityFit compares a candidate's capacity to the original venue's, 1.0 being identical
+func capacityFit(original, candidate *models.Venue) float64 {
+	if original == nil || original.Capacity == 0 {
+		return 1.0
+	}
+	if candidate.Capacity == 0 {
+		return 0.0
+	}
+
+	smaller, larger := original.Capacity, candidate.Capacity
+	if smaller > larger {
+		smaller, larger = larger, smaller
+	}
+	return float64(smaller) / float64(larger)
+}
+
+// teamDistance returns the distance from a team's home to the venue, or nil when unknown
+func teamDistance(teams map[int]*models.Team, teamID *int, venue *models.Venue) *float64 {
+	if teamID == nil || !geo.HasCoordinates(venue.Latitude, venue.Longitude) {
+		return nil
+	}
+	team, ok := teams[*teamID]
+	if !ok || !geo.HasCoordinates(team.Latitude, team.Longitude) {
+		return nil
+	}
+
+	distance := geo.HaversineKm(team.Latitude, team.Longitude, venue.Latitude, venue.Longitude)
+	return &distance
+}
+
+// distanceScore converts the average fan travel distance into a score between 0 and 1
+func distanceScore(home, away *float64) float64 {
+	var total float64
+	count := 0
+	for _, distance := range []*float64{home, away} {
+		if distance != nil {
+			total += *distance
+			count++
+		}
+	}
+
+	// Without coordinates distance can't separate candidates
+	if count == 0 {
+		return 0.5
+	}
+
+	average := total / float64(count)
+	return 1.0 / (1.0 + average/DistanceScaleKm)
+}
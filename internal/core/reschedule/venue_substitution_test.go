@@ -0,0 +1,83 @@
+package reschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestRankVenueSubstitutes(t *testing.T) {
+	// Original venue is in Sydney; both teams are Sydney based
+	venues := []*models.Venue{
+		{ID: 1, Name: "Accor Stadium", City: "Sydney", Capacity: 80000, Latitude: -33.8470, Longitude: 151.0634},
+		{ID: 2, Name: "Allianz Stadium", City: "Sydney", Capacity: 42500, Latitude: -33.8893, Longitude: 151.2247},
+		{ID: 3, Name: "Suncorp Stadium", City: "Brisbane", Capacity: 52500, Latitude: -27.4648, Longitude: 153.0095},
+		{ID: 4, Name: "CommBank Stadium", City: "Parramatta", Capacity: 30000, Latitude: -33.8076, Longitude: 151.0037},
+	}
+	teams := map[int]*models.Team{
+		1: {ID: 1, Name: "Roosters", Latitude: -33.8915, Longitude: 151.2767},
+		2: {ID: 2, Name: "Rabbitohs", Latitude: -33.9000, Longitude: 151.2100},
+	}
+
+	date := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	match := &models.Match{ID: 1, DrawID: 1, Round: 1, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2), VenueID: intPtr(1), MatchDate: &date}
+	// Parramatta is double-booked the same day
+	other := &models.Match{ID: 2, DrawID: 1, Round: 1, HomeTeamID: intPtr(3), AwayTeamID: intPtr(4), VenueID: intPtr(4), MatchDate: &date}
+
+	draw := &models.Draw{ID: 1, Rounds: 1, Matches: []*models.Match{match, other}}
+
+	candidates := RankVenueSubstitutes(draw, match, venues, teams, constraints.NewConstraintEngine())
+
+	if len(candidates) != 3 {
+		t.Fatalf("Expected 3 candidates excluding the original venue, got %d", len(candidates))
+	}
+
+	if candidates[0].Venue.ID != 2 {
+		t.Errorf("Expected Allianz Stadium to rank first, got %s", candidates[0].Venue.Name)
+	}
+
+	if candidates[1].Venue.ID != 3 {
+		t.Errorf("Expected Suncorp Stadium to rank second, got %s", candidates[1].Venue.Name)
+	}
+
+	last := candidates[2]
+	if last.Venue.ID != 4 || last.Available {
+		t.Errorf("Expected double-booked CommBank Stadium to rank last as unavailable, got %s (available=%v)",
+			last.Venue.Name, last.Available)
+	}
+	if len(last.Conflicts) != 1 {
+		t.Errorf("Expected 1 conflict for CommBank Stadium, got %d", len(last.Conflicts))
+	}
+
+	if candidates[0].HomeDistanceKm == nil || candidates[0].AwayDistanceKm == nil {
+		t.Error("Expected distances for both teams")
+	}
+
+	// The original draw must not be modified while ranking
+	if *match.VenueID != 1 || draw.Matches[0] != match {
+		t.Error("Ranking should not modify the original match or draw")
+	}
+}
+
+func TestCapacityFit(t *testing.T) {
+	original := &models.Venue{Capacity: 40000}
+
+	if fit := capacityFit(original, &models.Venue{Capacity: 40000}); fit != 1.0 {
+		t.Errorf("Expected identical capacity to fit 1.0, got %v", fit)
+	}
+	if fit := capacityFit(original, &models.Venue{Capacity: 20000}); fit != 0.5 {
+		t.Errorf("Expected half capacity to fit 0.5, got %v", fit)
+	}
+	if fit := capacityFit(original, &models.Venue{Capacity: 80000}); fit != 0.5 {
+		t.Errorf("Expected double capacity to fit 0.5, got %v", fit)
+	}
+	if fit := capacityFit(nil, &models.Venue{Capacity: 10000}); fit != 1.0 {
+		t.Errorf("Expected unknown original to fit 1.0, got %v", fit)
+	}
+}
@@ -0,0 +1,185 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// Seed result statuses
+const (
+	StatusCreated  = "created"
+	StatusExisting = "existing"
+)
+
+// Result records what happened to a single seeded team or venue
+type Result struct {
+	Kind   string `json:"kind"` // "team" or "venue"
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// Report summarises a seed run
+type Report struct {
+	VenuesCreated int      `json:"venues_created"`
+	TeamsCreated  int      `json:"teams_created"`
+	Existing      int      `json:"existing"`
+	Results       []Result `json:"results"`
+}
+
+type nrlVenue struct {
+	name     string
+	city     string
+	capacity int
+}
+
+type nrlTeam struct {
+	name      string
+	shortName string
+	city      string
+	venue     string
+}
+
+// nrlVenues lists the current home grounds of the NRL clubs
+var nrlVenues = []nrlVenue{
+	{"Suncorp Stadium", "Brisbane", 52500},
+	{"GIO Stadium", "Canberra", 25011},
+	{"Accor Stadium", "Sydney", 83500},
+	{"Ocean Protect Stadium", "Cronulla", 12000},
+	{"Cbus Super Stadium", "Gold Coast", 27400},
+	{"4 Pines Park", "Manly", 17000},
+	{"AAMI Park", "Melbourne", 30050},
+	{"McDonald Jones Stadium", "Newcastle", 33000},
+	{"Go Media Stadium", "Auckland", 25000},
+	{"Queensland Country Bank Stadium", "Townsville", 25000},
+	{"CommBank Stadium", "Parramatta", 30000},
+	{"BlueBet Stadium", "Penrith", 22500},
+	{"Netstrata Jubilee Stadium", "Sydney", 20500},
+	{"Allianz Stadium", "Sydney", 42500},
+	{"Campbelltown Sports Stadium", "Campbelltown", 17500},
+}
+
+// nrlTeams lists the 17 NRL clubs and the venue each calls home
+var nrlTeams = []nrlTeam{
+	{"Brisbane Broncos", "BRI", "Brisbane", "Suncorp Stadium"},
+	{"Canberra Raiders", "CAN", "Canberra", "GIO Stadium"},
+	{"Canterbury-Bankstown Bulldogs", "CBY", "Sydney", "Accor Stadium"},
+	{"Cronulla-Sutherland Sharks", "CRO", "Cronulla", "Ocean Protect Stadium"},
+	{"Dolphins", "DOL", "Redcliffe", "Suncorp Stadium"},
+	{"Gold Coast Titans", "GLD", "Gold Coast", "Cbus Super Stadium"},
+	{"Manly-Warringah Sea Eagles", "MAN", "Manly", "4 Pines Park"},
+	{"Melbourne Storm", "MEL", "Melbourne", "AAMI Park"},
+	{"Newcastle Knights", "NEW", "Newcastle", "McDonald Jones Stadium"},
+	{"New Zealand Warriors", "NZW", "Auckland", "Go Media Stadium"},
+	{"North Queensland Cowboys", "NQL", "Townsville", "Queensland Country Bank Stadium"},
+	{"Parramatta Eels", "PAR", "Parramatta", "CommBank Stadium"},
+	{"Penrith Panthers", "PEN", "Penrith", "BlueBet Stadium"},
+	{"South Sydney Rabbitohs", "SOU", "Sydney", "Accor Stadium"},
+	{"St George Illawarra Dragons", "SGI", "Sydney", "Netstrata Jubilee Stadium"},
+	{"Sydney Roosters", "SYD", "Sydney", "Allianz Stadium"},
+	{"Wests Tigers", "WST", "Sydney", "Campbelltown Sports Stadium"},
+}
+
+// LoadNRL creates the NRL clubs and their home venues, taking coordinates from
+// the offline stadium list. Teams and venues that already exist by name are
+// left as they are, so seeding twice changes nothing.
+func LoadNRL(ctx context.Context, repos storage.Repositories) (*Report, error) {
+	report := &Report{Results: []Result{}}
+	geocoder := geo.NewOfflineGeocoder()
+
+	tx, err := repos.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	venues, err := tx.Venues().List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list venues: %w", err)
+	}
+	venueIDs := make(map[string]int)
+	for _, venue := range venues {
+		venueIDs[strings.ToLower(venue.Name)] = venue.ID
+	}
+
+	locations := make(map[string]geo.Location)
+	for _, seed := range nrlVenues {
+		location, err := geocoder.Geocode(ctx, geo.Query{Name: seed.name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate %s: %w", seed.name, err)
+		}
+		locations[seed.name] = location
+
+		if id, ok := venueIDs[strings.ToLower(seed.name)]; ok {
+			report.add(Result{Kind: "venue", ID: id, Name: seed.name, Status: StatusExisting})
+			continue
+		}
+
+		venue := &models.Venue{
+			Name:      seed.name,
+			City:      seed.city,
+			Capacity:  seed.capacity,
+			Latitude:  location.Latitude,
+			Longitude: location.Longitude,
+		}
+		if err := tx.Venues().Create(ctx, venue); err != nil {
+			return nil, fmt.Errorf("failed to create venue %s: %w", seed.name, err)
+		}
+		venueIDs[strings.ToLower(seed.name)] = venue.ID
+		report.add(Result{Kind: "venue", ID: venue.ID, Name: seed.name, Status: StatusCreated})
+	}
+
+	teams, err := tx.Teams().List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	teamIDs := make(map[string]int)
+	for _, team := range teams {
+		teamIDs[strings.ToLower(team.Name)] = team.ID
+	}
+
+	for _, seed := range nrlTeams {
+		if id, ok := teamIDs[strings.ToLower(seed.name)]; ok {
+			report.add(Result{Kind: "team", ID: id, Name: seed.name, Status: StatusExisting})
+			continue
+		}
+
+		venueID := venueIDs[strings.ToLower(seed.venue)]
+		team := &models.Team{
+			Name:      seed.name,
+			ShortName: seed.shortName,
+			City:      seed.city,
+			VenueID:   &venueID,
+			Latitude:  locations[seed.venue].Latitude,
+			Longitude: locations[seed.venue].Longitude,
+		}
+		if err := tx.Teams().Create(ctx, team); err != nil {
+			return nil, fmt.Errorf("failed to create team %s: %w", seed.name, err)
+		}
+		report.add(Result{Kind: "team", ID: team.ID, Name: seed.name, Status: StatusCreated})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return report, nil
+}
+
+// add records a result against the report's totals
+func (r *Report) add(result Result) {
+	switch {
+	case result.Status == StatusExisting:
+		r.Existing++
+	case result.Kind == "venue":
+		r.VenuesCreated++
+	default:
+		r.TeamsCreated++
+	}
+	r.Results = append(r.Results, result)
+}
@@ -0,0 +1,37 @@
+package seed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+)
+
+func TestNRLSeedData(t *testing.T) {
+	if len(nrlTeams) != 17 {
+		t.Errorf("Expected 17 NRL teams, got %d", len(nrlTeams))
+	}
+
+	geocoder := geo.NewOfflineGeocoder()
+	venues := make(map[string]bool)
+	for _, venue := range nrlVenues {
+		if _, err := geocoder.Geocode(context.Background(), geo.Query{Name: venue.name}); err != nil {
+			t.Errorf("Venue %s has no offline coordinates: %v", venue.name, err)
+		}
+		if venue.capacity <= 0 {
+			t.Errorf("Venue %s has no capacity", venue.name)
+		}
+		venues[venue.name] = true
+	}
+
+	shortNames := make(map[string]bool)
+	for _, team := range nrlTeams {
+		if !venues[team.venue] {
+			t.Errorf("Team %s plays at unknown venue %s", team.name, team.venue)
+		}
+		if len(team.shortName) != 3 || shortNames[team.shortName] {
+			t.Errorf("Team %s has an invalid or duplicate short name %q", team.name, team.shortName)
+		}
+		shortNames[team.shortName] = true
+	}
+}
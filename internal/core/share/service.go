@@ -0,0 +1,247 @@
+package share
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/approval"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// Share link lifetimes
+const (
+	DefaultTTL = 7 * 24 * time.Hour
+	MaxTTL     = 90 * 24 * time.Hour
+)
+
+// tokenBytes is the amount of randomness in a share token
+const tokenBytes = 24
+
+// Errors returned when minting or resolving share links
+var (
+	ErrDrawNotReady = errors.New("draw has no fixture to share")
+	ErrInvalidTTL   = errors.New("share link lifetime must be between 1 hour and 90 days")
+	ErrLinkExpired  = errors.New("share link has expired")
+	ErrLinkRevoked  = errors.New("share link has been revoked")
+)
+
+// Snapshot is the fixture exactly as it was when a share link was minted
+type Snapshot struct {
+	DrawID     int             `json:"draw_id"`
+	Name       string          `json:"name"`
+	SeasonYear int             `json:"season_year"`
+	Rounds     int             `json:"rounds"`
+	Version    string          `json:"version"`
+	SnapshotAt time.Time       `json:"snapshot_at"`
+	Matches    []SnapshotMatch `json:"matches"`
+}
+
+// SnapshotMatch is a single fixture in a snapshot, resolved to display names
+type SnapshotMatch struct {
+	Round       int    `json:"round"`
+	Date        string `json:"date,omitempty"`
+	Time        string `json:"time,omitempty"`
+	HomeTeam    string `json:"home_team,omitempty"`
+	AwayTeam    string `json:"away_team,omitempty"`
+	Venue       string `json:"venue,omitempty"`
+	IsPrimeTime bool   `json:"is_prime_time"`
+	IsBye       bool   `json:"is_bye"`
+}
+
+// SnapshotRound groups a snapshot's matches by round
+type SnapshotRound struct {
+	Round   int             `json:"round"`
+	Matches []SnapshotMatch `json:"matches"`
+}
+
+// SharedDraw is what a share link resolves to
+type SharedDraw struct {
+	Snapshot
+	Label     string    `json:"label,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Outdated  bool      `json:"outdated"` // the draw has changed since the link was minted
+}
+
+// Service mints and resolves read-only share links for draws
+type Service struct {
+	repository storage.Repositories
+}
+
+// NewService creates a new share service
+func NewService(repository storage.Repositories) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// CreateLink snapshots the draw's current fixture and mints a link to it that
+// expires after ttl. A zero ttl uses DefaultTTL.
+func (s *Service) CreateLink(ctx context.Context, drawID int, ttl time.Duration, label, createdBy string) (*models.ShareLink, error) {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	if ttl < time.Hour || ttl > MaxTTL {
+		return nil, ErrInvalidTTL
+	}
+
+	draw, err := s.loadDraw(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	if len(draw.Matches) == 0 {
+		return nil, ErrDrawNotReady
+	}
+
+	snapshot := buildSnapshot(draw, time.Now())
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode draw snapshot: %w", err)
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link := &models.ShareLink{
+		DrawID:      drawID,
+		Token:       token,
+		DrawVersion: snapshot.Version,
+		Snapshot:    data,
+		Label:       label,
+		CreatedBy:   createdBy,
+		ExpiresAt:   snapshot.SnapshotAt.Add(ttl),
+	}
+	if err := link.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repository.ShareLinks().Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// ListLinks returns every share link minted for a draw, newest first
+func (s *Service) ListLinks(ctx context.Context, drawID int) ([]*models.ShareLink, error) {
+	if _, err := s.repository.Draws().Get(ctx, drawID); err != nil {
+		return nil, err
+	}
+	return s.repository.ShareLinks().ListByDraw(ctx, drawID)
+}
+
+// RevokeLink stops a share link from working before it expires
+func (s *Service) RevokeLink(ctx context.Context, drawID, linkID int) error {
+	return s.repository.ShareLinks().Revoke(ctx, drawID, linkID, time.Now())
+}
+
+// Resolve returns the snapshot behind an active share token
+func (s *Service) Resolve(ctx context.Context, token string) (*SharedDraw, error) {
+	link, err := s.repository.ShareLinks().GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if link.IsRevoked() {
+		return nil, ErrLinkRevoked
+	}
+	if link.IsExpired(time.Now()) {
+		return nil, ErrLinkExpired
+	}
+
+	shared := &SharedDraw{
+		Label:     link.Label,
+		ExpiresAt: link.ExpiresAt,
+	}
+	if err := json.Unmarshal(link.Snapshot, &shared.Snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode draw snapshot: %w", err)
+	}
+
+	// Let reviewers know they're looking at an older version
+	if draw, err := s.loadDraw(ctx, link.DrawID); err == nil {
+		shared.Outdated = approval.DrawVersion(draw) != link.DrawVersion
+	}
+
+	return shared, nil
+}
+
+// loadDraw fetches a draw with its matches and their teams and venues
+func (s *Service) loadDraw(ctx context.Context, drawID int) (*models.Draw, error) {
+	draw, err := s.repository.Draws().Get(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.repository.Matches().ListByDrawWithRelations(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	draw.Matches = matches
+	return draw, nil
+}
+
+// ByRound groups the snapshot's matches by round, in round order
+func (s *Snapshot) ByRound() []SnapshotRound {
+	var rounds []SnapshotRound
+	for _, match := range s.Matches {
+		if len(rounds) == 0 || rounds[len(rounds)-1].Round != match.Round {
+			rounds = append(rounds, SnapshotRound{Round: match.Round})
+		}
+		last := &rounds[len(rounds)-1]
+		last.Matches = append(last.Matches, match)
+	}
+	return rounds
+}
+
+// buildSnapshot captures a draw's fixture with display names.
+// Matches are expected in round order, as returned by ListByDrawWithRelations.
+func buildSnapshot(draw *models.Draw, at time.Time) Snapshot {
+	snapshot := Snapshot{
+		DrawID:     draw.ID,
+		Name:       draw.Name,
+		SeasonYear: draw.SeasonYear,
+		Rounds:     draw.Rounds,
+		Version:    approval.DrawVersion(draw),
+		SnapshotAt: at,
+		Matches:    make([]SnapshotMatch, 0, len(draw.Matches)),
+	}
+
+	for _, match := range draw.Matches {
+		shared := SnapshotMatch{
+			Round:       match.Round,
+			IsPrimeTime: match.IsPrimeTime,
+			IsBye:       match.IsBye(),
+		}
+		if match.MatchDate != nil {
+			shared.Date = match.MatchDate.Format("2006-01-02")
+		}
+		if match.MatchTime != nil {
+			shared.Time = match.MatchTime.Format("15:04")
+		}
+		if match.HomeTeam != nil {
+			shared.HomeTeam = match.HomeTeam.Name
+		}
+		if match.AwayTeam != nil {
+			shared.AwayTeam = match.AwayTeam.Name
+		}
+		if match.Venue != nil {
+			shared.Venue = match.Venue.Name
+		}
+		snapshot.Matches = append(snapshot.Matches, shared)
+	}
+
+	return snapshot
+}
+
+// generateToken returns an unguessable URL-safe token
+func generateToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
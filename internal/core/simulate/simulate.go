@@ -0,0 +1,244 @@
+package simulate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/draw"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Limits on the size of a simulation
+const (
+	MaxTeams       = 32
+	MaxRounds      = 52
+	DefaultSamples = 20
+	MaxSamples     = 200
+)
+
+// Errors returned for invalid simulation requests
+var (
+	ErrInvalidConfig = errors.New("invalid constraint configuration")
+	ErrInvalidLeague = errors.New("invalid league parameters")
+)
+
+// Options describes the synthetic league the draws are generated for
+type Options struct {
+	Teams  int
+	Rounds int
+	// Samples is the number of draws generated, DefaultSamples when 0
+	Samples int
+	// Seed makes the draws repeatable; the current time is used when nil
+	Seed *int64
+}
+
+// Distribution summarises scores across the simulated draws
+type Distribution struct {
+	Min    float64 `json:"min"`
+	P10    float64 `json:"p10"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+}
+
+// ConstraintDistribution is how one constraint scored across the draws
+type ConstraintDistribution struct {
+	Name   string       `json:"name"`
+	Type   string       `json:"type"` // "hard" or "soft"
+	Weight float64      `json:"weight,omitempty"`
+	Scores Distribution `json:"scores"`
+	// PerfectRate is the share of draws the constraint scored 1.0 on
+	PerfectRate float64 `json:"perfect_rate"`
+	// ViolationRate is the share of draws breaking a hard constraint
+	ViolationRate float64 `json:"violation_rate,omitempty"`
+}
+
+// Result is the score distribution of a constraint configuration over
+// randomly generated draws
+type Result struct {
+	Teams   int   `json:"teams"`
+	Rounds  int   `json:"rounds"`
+	Samples int   `json:"samples"`
+	Seed    int64 `json:"seed"`
+	// Overall is the engine's score, 0 for draws breaking a hard constraint
+	Overall Distribution `json:"overall"`
+	// ValidRate is the share of draws with no hard violations
+	ValidRate   float64                  `json:"valid_rate"`
+	Constraints []ConstraintDistribution `json:"constraints"`
+}
+
+// Simulate generates random round-robin draws for a league of the given size
+// and scores each against the configuration, so users can see whether their
+// params and weights are attainable before generating a real draw. The
+// synthetic teams each have their own home venue and the draws are undated,
+// so constraints on dates, travel and named teams or venues score as they
+// would for an unscheduled draw.
+func Simulate(config constraints.ConstraintConfig, opts Options) (*Result, error) {
+	if opts.Teams < 2 || opts.Teams > MaxTeams {
+		return nil, fmt.Errorf("%w: teams must be between 2 and %d", ErrInvalidLeague, MaxTeams)
+	}
+	if opts.Rounds < 1 || opts.Rounds > MaxRounds {
+		return nil, fmt.Errorf("%w: rounds must be between 1 and %d", ErrInvalidLeague, MaxRounds)
+	}
+	if opts.Samples == 0 {
+		opts.Samples = DefaultSamples
+	}
+	if opts.Samples < 1 || opts.Samples > MaxSamples {
+		return nil, fmt.Errorf("%w: samples must be between 1 and %d", ErrInvalidLeague, MaxSamples)
+	}
+	seed := time.Now().UnixNano()
+	if opts.Seed != nil {
+		seed = *opts.Seed
+	}
+
+	engine, err := constraints.NewConstraintFactory().CreateConstraintEngine(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	teams := make([]*models.Team, opts.Teams)
+	for i := range teams {
+		id := i + 1
+		teams[i] = &models.Team{ID: id, Name: fmt.Sprintf("Team %d", id), VenueID: &id}
+	}
+
+	hard := engine.GetHardConstraints()
+	soft := engine.GetSoftConstraints()
+	hardScores := make([][]float64, len(hard))
+	hardViolations := make([]int, len(hard))
+	softScores := make([][]float64, len(soft))
+	var overall []float64
+	valid := 0
+
+	for sample := 0; sample < opts.Samples; sample++ {
+		generator, err := draw.NewGenerator(teams, opts.Rounds)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidLeague, err)
+		}
+		generator.SetSeed(seed + int64(sample))
+		simulated, err := generator.Generate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate draw: %w", err)
+		}
+		// Constraints tell matches apart by ID, as they would stored ones
+		for i, match := range simulated.Matches {
+			match.ID = i + 1
+		}
+
+		overall = append(overall, engine.ScoreDraw(simulated))
+		if len(engine.ValidateDraw(simulated)) == 0 {
+			valid++
+		}
+		for i, constraint := range hard {
+			hardScores[i] = append(hardScores[i], constraint.Score(simulated))
+			if violates(constraint, simulated) {
+				hardViolations[i]++
+			}
+		}
+		for i, weighted := range soft {
+			softScores[i] = append(softScores[i], weighted.Constraint.Score(simulated))
+		}
+	}
+
+	samples := float64(opts.Samples)
+	result := &Result{
+		Teams:       opts.Teams,
+		Rounds:      opts.Rounds,
+		Samples:     opts.Samples,
+		Seed:        seed,
+		Overall:     distribution(overall),
+		ValidRate:   float64(valid) / samples,
+		Constraints: []ConstraintDistribution{},
+	}
+	for i, constraint := range hard {
+		result.Constraints = append(result.Constraints, ConstraintDistribution{
+			Name:          constraint.Name(),
+			Type:          "hard",
+			Scores:        distribution(hardScores[i]),
+			PerfectRate:   perfectRate(hardScores[i]),
+			ViolationRate: float64(hardViolations[i]) / samples,
+		})
+	}
+	for i, weighted := range soft {
+		result.Constraints = append(result.Constraints, ConstraintDistribution{
+			Name:        weighted.Constraint.Name(),
+			Type:        "soft",
+			Weight:      weighted.Weight,
+			Scores:      distribution(softScores[i]),
+			PerfectRate: perfectRate(softScores[i]),
+		})
+	}
+
+	return result, nil
+}
+
+// violates reports whether a hard constraint rejects any of the draw's
+// matches or, for draw-level rules, the draw as a whole
+func violates(constraint constraints.Constraint, simulated *models.Draw) bool {
+	for _, match := range simulated.Matches {
+		if constraint.Validate(match, simulated) != nil {
+			return true
+		}
+	}
+	if validator, ok := constraint.(constraints.DrawValidator); ok {
+		return len(validator.ValidateDraw(simulated)) > 0
+	}
+	return false
+}
+
+// distribution summarises a set of scores
+func distribution(scores []float64) Distribution {
+	if len(scores) == 0 {
+		return Distribution{}
+	}
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, score := range sorted {
+		sum += score
+	}
+	mean := sum / float64(len(sorted))
+	var squares float64
+	for _, score := range sorted {
+		squares += (score - mean) * (score - mean)
+	}
+
+	return Distribution{
+		Min:    sorted[0],
+		P10:    percentile(sorted, 0.1),
+		Median: percentile(sorted, 0.5),
+		P90:    percentile(sorted, 0.9),
+		Max:    sorted[len(sorted)-1],
+		Mean:   mean,
+		StdDev: math.Sqrt(squares / float64(len(sorted))),
+	}
+}
+
+// percentile interpolates between the nearest sorted scores
+func percentile(sorted []float64, p float64) float64 {
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// perfectRate returns the share of scores at 1.0
+func perfectRate(scores []float64) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+	perfect := 0
+	for _, score := range scores {
+		if score >= 1 {
+			perfect++
+		}
+	}
+	return float64(perfect) / float64(len(scores))
+}
@@ -0,0 +1,71 @@
+package simulate
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+)
+
+func TestSimulate(t *testing.T) {
+	config := constraints.ConstraintConfig{
+		Hard: []constraints.HardConstraintConfig{
+			{Type: "bye_constraint", Params: map[string]interface{}{}},
+			{Type: "double_up", Params: map[string]interface{}{"min_rounds_separation": float64(20)}},
+		},
+		Soft: []constraints.SoftConstraintConfig{
+			{Type: "home_away_balance", Weight: 0.5, Params: map[string]interface{}{"max_deviation": 0.1}},
+		},
+	}
+	seed := int64(42)
+
+	result, err := Simulate(config, Options{Teams: 6, Rounds: 10, Samples: 5, Seed: &seed})
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if result.Samples != 5 || result.Seed != 42 || len(result.Constraints) != 3 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	// Six teams over ten rounds meet twice, five rounds apart at most
+	doubleUp := result.Constraints[1]
+	if doubleUp.Type != "hard" || doubleUp.ViolationRate != 1 {
+		t.Errorf("expected every draw to break the double-up separation, got %+v", doubleUp)
+	}
+	if result.ValidRate != 0 || result.Overall.Max != 0 {
+		t.Errorf("expected no valid draws, got %+v", result)
+	}
+	balance := result.Constraints[2]
+	if balance.Type != "soft" || balance.Weight != 0.5 || balance.Scores.Min > balance.Scores.Median || balance.Scores.Median > balance.Scores.Max {
+		t.Errorf("unexpected soft constraint distribution: %+v", balance)
+	}
+
+	// The same seed gives the same draws
+	again, _ := Simulate(config, Options{Teams: 6, Rounds: 10, Samples: 5, Seed: &seed})
+	if again.Constraints[2].Scores != balance.Scores {
+		t.Errorf("expected repeatable scores, got %+v and %+v", balance.Scores, again.Constraints[2].Scores)
+	}
+
+	if _, err := Simulate(config, Options{Teams: 1, Rounds: 10}); !errors.Is(err, ErrInvalidLeague) {
+		t.Errorf("expected ErrInvalidLeague, got %v", err)
+	}
+	bad := constraints.ConstraintConfig{Hard: []constraints.HardConstraintConfig{{Type: "double_up"}}}
+	if _, err := Simulate(bad, Options{Teams: 6, Rounds: 10}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	d := distribution([]float64{0.5, 1, 0, 0.25, 0.75})
+	got := []float64{d.Min, d.P10, d.Median, d.P90, d.Max, d.Mean}
+	want := []float64{0, 0.1, 0.5, 0.9, 1, 0.5}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("distribution() = %+v, want min, p10, median, p90, max and mean %v", d, want)
+		}
+	}
+	if math.Abs(d.StdDev-math.Sqrt(0.125)) > 1e-9 {
+		t.Errorf("expected a standard deviation of %f, got %f", math.Sqrt(0.125), d.StdDev)
+	}
+}
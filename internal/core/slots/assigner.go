@@ -0,0 +1,392 @@
+package slots
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// hardViolationPenalty outweighs any achievable soft score so assignments that
+// break a hard constraint are only kept when nothing better exists
+const hardViolationPenalty = 1000.0
+
+// emptyPrimeTimePenalty outweighs any soft score, so a round with byes fills
+// its prime-time slots before its other slots, but not a hard violation
+const emptyPrimeTimePenalty = 10.0
+
+// maxImprovementPasses bounds the swap search after the greedy assignment
+const maxImprovementPasses = 5
+
+// ErrNotEnoughSlots is returned when a round has more matches than kickoff slots
+var ErrNotEnoughSlots = errors.New("round has fewer slots than matches")
+
+// Slot is a kickoff window available in a round
+type Slot struct {
+	Date        time.Time
+	Time        *time.Time
+	PrimeTime   bool
+	Broadcaster string
+	// AutoPrimeTime has PrimeTime derived from the timeslot catalogue when
+	// the slot is assigned, rather than taken as given
+	AutoPrimeTime bool
+}
+
+// BroadcasterQuota bounds how often each team appears in a broadcaster's slots across the season
+type BroadcasterQuota struct {
+	Broadcaster string
+	MinPerTeam  int
+	MaxPerTeam  int // 0 means no upper limit
+}
+
+// Assignment is the slot chosen for one match
+type Assignment struct {
+	Match *models.Match
+	Slot  Slot
+}
+
+// QuotaShortfall reports a team outside a broadcaster quota
+type QuotaShortfall struct {
+	Broadcaster string `json:"broadcaster"`
+	TeamID      int    `json:"team_id"`
+	Appearances int    `json:"appearances"`
+	Min         int    `json:"min"`
+	Max         int    `json:"max,omitempty"`
+}
+
+// Result is the outcome of assigning slots to a draw
+type Result struct {
+	Draw           *models.Draw
+	Assignments    []Assignment
+	ScoreBefore    float64
+	ScoreAfter     float64
+	HardViolations int
+	Shortfalls     []QuotaShortfall
+	// Profiles describe each scheduled round, in round order
+	Profiles []models.RoundProfile
+}
+
+// Assigner gives each match in a draw a kickoff slot from its round's inventory.
+// Matchups, home/away and rounds are never changed.
+type Assigner struct {
+	engine *constraints.ConstraintEngine
+	quotas []BroadcasterQuota
+}
+
+// NewAssigner creates a slot assigner scoring candidates with the given engine
+func NewAssigner(engine *constraints.ConstraintEngine, quotas []BroadcasterQuota) *Assigner {
+	return &Assigner{
+		engine: engine,
+		quotas: quotas,
+	}
+}
+
+// Assign returns a copy of the draw with every non-bye match in the inventory's
+// rounds placed in a slot; locked matches and rounds missing from the inventory
+// keep their kickoffs.
+// Rounds are filled greedily in order, so rest periods are judged against the
+// rounds already placed, then slots are swapped within each round while the
+// overall objective improves. Rounds with byes have fewer matches than slots;
+// their prime-time slots are filled first and the rest are left unused.
+func (a *Assigner) Assign(draw *models.Draw, inventory map[int][]Slot) (*Result, error) {
+	working := copyDraw(draw)
+	result := &Result{
+		Draw:        working,
+		ScoreBefore: a.engine.ScoreDraw(draw),
+	}
+
+	rounds := roundsOf(working, inventory)
+	assigned := make(map[*models.Match]int) // match -> slot index in its round
+
+	// Clear existing kickoffs for the rounds being assigned so stale times don't
+	// influence rest calculations
+	for _, round := range rounds {
+		for _, match := range round.matches {
+			match.MatchDate = nil
+			match.MatchTime = nil
+			match.IsPrimeTime = false
+		}
+	}
+
+	for _, round := range rounds {
+		slots := inventory[round.number]
+		if len(slots) < len(round.matches) {
+			return nil, fmt.Errorf("%w: round %d has %d matches and %d slots",
+				ErrNotEnoughSlots, round.number, len(round.matches), len(slots))
+		}
+
+		used := make([]bool, len(slots))
+		for _, match := range round.matches {
+			best, bestScore := -1, 0.0
+			for i, slot := range slots {
+				if used[i] {
+					continue
+				}
+				applySlot(match, slot)
+				assigned[match] = i
+				if score := a.objective(working, inventory, assigned); best == -1 || score > bestScore {
+					best, bestScore = i, score
+				}
+			}
+			used[best] = true
+			assigned[match] = best
+			applySlot(match, slots[best])
+		}
+	}
+
+	a.improve(working, rounds, inventory, assigned)
+
+	for _, round := range rounds {
+		for _, match := range round.matches {
+			result.Assignments = append(result.Assignments, Assignment{
+				Match: match,
+				Slot:  inventory[round.number][assigned[match]],
+			})
+		}
+	}
+
+	result.ScoreAfter = a.engine.ScoreDraw(working)
+	result.HardViolations = len(a.engine.ValidateDraw(working))
+	result.Shortfalls = a.shortfalls(working, inventory, assigned)
+	result.Profiles = sortedProfiles(ProfileRounds(working, inventory))
+	return result, nil
+}
+
+// improve swaps slots between matches in the same round, or moves a match to an
+// unused slot, while doing so raises the objective
+func (a *Assigner) improve(draw *models.Draw, rounds []round, inventory map[int][]Slot, assigned map[*models.Match]int) {
+	current := a.objective(draw, inventory, assigned)
+
+	for pass := 0; pass < maxImprovementPasses; pass++ {
+		improved := false
+
+		for _, round := range rounds {
+			slots := inventory[round.number]
+			owner := make([]*models.Match, len(slots))
+			for _, match := range round.matches {
+				owner[assigned[match]] = match
+			}
+
+			for _, match := range round.matches {
+				for target := range slots {
+					from := assigned[match]
+					if target == from {
+						continue
+					}
+					other := owner[target]
+
+					// Try the move (or swap with the target's current match)
+					moveTo(match, target, slots, assigned)
+					if other != nil {
+						moveTo(other, from, slots, assigned)
+					}
+
+					if score := a.objective(draw, inventory, assigned); score > current {
+						current = score
+						owner[target], owner[from] = match, other
+						improved = true
+						continue
+					}
+
+					// Undo
+					moveTo(match, from, slots, assigned)
+					if other != nil {
+						moveTo(other, target, slots, assigned)
+					}
+				}
+			}
+		}
+
+		if !improved {
+			return
+		}
+	}
+}
+
+// objective scores a (possibly partial) assignment; higher is better
+func (a *Assigner) objective(draw *models.Draw, inventory map[int][]Slot, assigned map[*models.Match]int) float64 {
+	var softScore, totalWeight float64
+	for _, weighted := range a.engine.GetSoftConstraints() {
+		softScore += weighted.Constraint.Score(draw) * weighted.Weight
+		totalWeight += weighted.Weight
+	}
+	if totalWeight > 0 {
+		softScore /= totalWeight
+	}
+
+	hard := float64(len(a.engine.ValidateDraw(draw)))
+	empty := float64(emptyPrimeTimeSlots(draw, inventory))
+	return softScore - hard*hardViolationPenalty - empty*emptyPrimeTimePenalty - a.quotaPenalty(draw, inventory, assigned)
+}
+
+// quotaPenalty is the average number of appearances each team is outside its broadcaster quotas
+func (a *Assigner) quotaPenalty(draw *models.Draw, inventory map[int][]Slot, assigned map[*models.Match]int) float64 {
+	if len(a.quotas) == 0 {
+		return 0
+	}
+
+	teams := drawTeams(draw)
+	if len(teams) == 0 {
+		return 0
+	}
+
+	var deviation float64
+	for _, quota := range a.quotas {
+		counts := broadcasterAppearances(draw, quota.Broadcaster, inventory, assigned)
+		for teamID := range teams {
+			deviation += float64(quotaDeviation(counts[teamID], quota))
+		}
+	}
+
+	return deviation / float64(len(teams))
+}
+
+// shortfalls lists every team outside a broadcaster quota, ordered by broadcaster then team
+func (a *Assigner) shortfalls(draw *models.Draw, inventory map[int][]Slot, assigned map[*models.Match]int) []QuotaShortfall {
+	teams := drawTeams(draw)
+
+	shortfalls := []QuotaShortfall{}
+	for _, quota := range a.quotas {
+		counts := broadcasterAppearances(draw, quota.Broadcaster, inventory, assigned)
+		var missed []QuotaShortfall
+		for teamID := range teams {
+			if quotaDeviation(counts[teamID], quota) == 0 {
+				continue
+			}
+			missed = append(missed, QuotaShortfall{
+				Broadcaster: quota.Broadcaster,
+				TeamID:      teamID,
+				Appearances: counts[teamID],
+				Min:         quota.MinPerTeam,
+				Max:         quota.MaxPerTeam,
+			})
+		}
+		for i := 0; i < len(missed)-1; i++ {
+			for j := 0; j < len(missed)-i-1; j++ {
+				if missed[j].TeamID > missed[j+1].TeamID {
+					missed[j], missed[j+1] = missed[j+1], missed[j]
+				}
+			}
+		}
+		shortfalls = append(shortfalls, missed...)
+	}
+	return shortfalls
+}
+
+// broadcasterAppearances counts each team's matches in a broadcaster's slots
+func broadcasterAppearances(draw *models.Draw, broadcaster string, inventory map[int][]Slot, assigned map[*models.Match]int) map[int]int {
+	counts := make(map[int]int)
+	for match, index := range assigned {
+		if inventory[match.Round][index].Broadcaster != broadcaster {
+			continue
+		}
+		for _, teamID := range matchTeams(match) {
+			counts[teamID]++
+		}
+	}
+	return counts
+}
+
+// quotaDeviation is how many appearances a count is outside a quota
+func quotaDeviation(count int, quota BroadcasterQuota) int {
+	if count < quota.MinPerTeam {
+		return quota.MinPerTeam - count
+	}
+	if quota.MaxPerTeam > 0 && count > quota.MaxPerTeam {
+		return count - quota.MaxPerTeam
+	}
+	return 0
+}
+
+// drawTeams returns the set of teams playing in a draw
+func drawTeams(draw *models.Draw) map[int]bool {
+	teams := make(map[int]bool)
+	for _, match := range draw.Matches {
+		for _, teamID := range matchTeams(match) {
+			teams[teamID] = true
+		}
+	}
+	return teams
+}
+
+func matchTeams(match *models.Match) []int {
+	var teams []int
+	if match.HomeTeamID != nil {
+		teams = append(teams, *match.HomeTeamID)
+	}
+	if match.AwayTeamID != nil {
+		teams = append(teams, *match.AwayTeamID)
+	}
+	return teams
+}
+
+// moveTo places a match in the slot at index
+func moveTo(match *models.Match, index int, slots []Slot, assigned map[*models.Match]int) {
+	assigned[match] = index
+	applySlot(match, slots[index])
+}
+
+// applySlot sets a match's kickoff from a slot
+func applySlot(match *models.Match, slot Slot) {
+	date := slot.Date
+	match.MatchDate = &date
+	match.MatchTime = nil
+	if slot.Time != nil {
+		kickoff := *slot.Time
+		match.MatchTime = &kickoff
+	}
+	match.IsPrimeTime = slot.PrimeTime
+}
+
+type round struct {
+	number  int
+	matches []*models.Match
+}
+
+// roundsOf groups a draw's unlocked, non-bye matches in the inventory's rounds by round, in round order
+func roundsOf(draw *models.Draw, inventory map[int][]Slot) []round {
+	byRound := make(map[int]*round)
+	var numbers []int
+	for _, match := range draw.Matches {
+		if !match.IsMovable() || match.HomeTeamID == nil || match.AwayTeamID == nil {
+			continue
+		}
+		if _, scheduled := inventory[match.Round]; !scheduled {
+			continue
+		}
+		r, ok := byRound[match.Round]
+		if !ok {
+			r = &round{number: match.Round}
+			byRound[match.Round] = r
+			numbers = append(numbers, match.Round)
+		}
+		r.matches = append(r.matches, match)
+	}
+
+	for i := 0; i < len(numbers)-1; i++ {
+		for j := 0; j < len(numbers)-i-1; j++ {
+			if numbers[j] > numbers[j+1] {
+				numbers[j], numbers[j+1] = numbers[j+1], numbers[j]
+			}
+		}
+	}
+
+	rounds := make([]round, len(numbers))
+	for i, number := range numbers {
+		rounds[i] = *byRound[number]
+	}
+	return rounds
+}
+
+// copyDraw copies a draw and its matches so assignment never mutates the caller's draw
+func copyDraw(original *models.Draw) *models.Draw {
+	draw := *original
+	draw.Matches = make([]*models.Match, len(original.Matches))
+	for i, match := range original.Matches {
+		copied := *match
+		draw.Matches[i] = &copied
+	}
+	return &draw
+}
@@ -0,0 +1,239 @@
+package slots
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/draw"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestAssignPrefersRestedTeams(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddSoftConstraint(constraints.NewRestPeriodConstraint(5), 1.0)
+
+	d := &models.Draw{
+		ID:     1,
+		Rounds: 2,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2)},
+			{ID: 2, Round: 2, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2)},
+		},
+	}
+
+	round1 := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	inventory := map[int][]Slot{
+		1: {{Date: round1}},
+		// Thursday is only six days on, Monday ten
+		2: {{Date: round1.AddDate(0, 0, 3)}, {Date: round1.AddDate(0, 0, 10)}},
+	}
+
+	result, err := NewAssigner(engine, nil).Assign(d, inventory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := result.Assignments[1].Slot.Date; !got.Equal(round1.AddDate(0, 0, 10)) {
+		t.Errorf("Expected the well-rested round 2 slot, got %s", got.Format("2006-01-02"))
+	}
+	if d.Matches[1].MatchDate != nil {
+		t.Error("Expected the original draw to be left untouched")
+	}
+	if result.Draw.Matches[0].Round != 1 || *result.Draw.Matches[0].HomeTeamID != 1 {
+		t.Error("Expected matchups and rounds to be unchanged")
+	}
+}
+
+func TestAssignShortTurnaroundCap(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+	engine.AddHardConstraint(constraints.NewShortTurnaroundCapConstraint(constraints.DefaultMaxShortTurnarounds))
+	engine.SetLeagueData(constraints.NewLeagueData([]*models.Team{
+		{ID: 1, Latitude: -37.8251, Longitude: 144.9839}, // Melbourne
+		{ID: 2, Latitude: -31.9510, Longitude: 115.8890}, // Perth
+	}, nil))
+
+	d := &models.Draw{
+		ID:     1,
+		Rounds: 2,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: intPtr(2), AwayTeamID: intPtr(1)},
+			{ID: 2, Round: 2, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2)},
+		},
+	}
+
+	// The Storm can't come back from Perth on a five-day turnaround
+	round1 := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	inventory := map[int][]Slot{
+		1: {{Date: round1}},
+		2: {{Date: round1.AddDate(0, 0, 5)}, {Date: round1.AddDate(0, 0, 7)}},
+	}
+
+	result, err := NewAssigner(engine, nil).Assign(d, inventory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := result.Assignments[1].Slot.Date; !got.Equal(round1.AddDate(0, 0, 7)) {
+		t.Errorf("Expected the seven-day turnaround, got %s", got.Format("2006-01-02"))
+	}
+	if result.HardViolations != 0 {
+		t.Errorf("Expected no hard violations, got %d", result.HardViolations)
+	}
+}
+
+func TestAssignBroadcasterQuota(t *testing.T) {
+	engine := constraints.NewConstraintEngine()
+
+	d := &models.Draw{
+		ID:     1,
+		Rounds: 2,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2)},
+			{ID: 2, Round: 1, HomeTeamID: intPtr(3), AwayTeamID: intPtr(4)},
+			{ID: 3, Round: 2, HomeTeamID: intPtr(2), AwayTeamID: intPtr(1)},
+			{ID: 4, Round: 2, HomeTeamID: intPtr(4), AwayTeamID: intPtr(3)},
+		},
+	}
+	date := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	inventory := map[int][]Slot{
+		1: {{Date: date, PrimeTime: true, Broadcaster: "free_to_air"}, {Date: date, Broadcaster: "subscription"}},
+		2: {{Date: date.AddDate(0, 0, 7), PrimeTime: true, Broadcaster: "free_to_air"}, {Date: date.AddDate(0, 0, 7), Broadcaster: "subscription"}},
+	}
+
+	// Every team on free-to-air exactly once means the pairs must alternate
+	quotas := []BroadcasterQuota{{Broadcaster: "free_to_air", MinPerTeam: 1, MaxPerTeam: 1}}
+	result, err := NewAssigner(engine, quotas).Assign(d, inventory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Shortfalls) != 0 {
+		t.Errorf("Expected every quota met, got %+v", result.Shortfalls)
+	}
+
+	// Two free-to-air slots can't give four teams two appearances each
+	quotas = []BroadcasterQuota{{Broadcaster: "free_to_air", MinPerTeam: 2}}
+	result, err = NewAssigner(engine, quotas).Assign(d, inventory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Shortfalls) < 2 {
+		t.Errorf("Expected teams short of the minimum to be reported, got %+v", result.Shortfalls)
+	}
+	for _, shortfall := range result.Shortfalls {
+		if shortfall.Appearances >= 2 {
+			t.Errorf("Team %d met the quota but was reported", shortfall.TeamID)
+		}
+	}
+}
+
+func TestAssignNotEnoughSlots(t *testing.T) {
+	d := &models.Draw{
+		ID:     1,
+		Rounds: 1,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2)},
+			{ID: 2, Round: 1, HomeTeamID: intPtr(3), AwayTeamID: intPtr(4)},
+		},
+	}
+	inventory := map[int][]Slot{1: {{Date: time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)}}}
+
+	_, err := NewAssigner(constraints.NewConstraintEngine(), nil).Assign(d, inventory)
+	if !errors.Is(err, ErrNotEnoughSlots) {
+		t.Errorf("Expected ErrNotEnoughSlots, got %v", err)
+	}
+}
+
+func TestAssignFillsPrimeTimeInByeRounds(t *testing.T) {
+	d := &models.Draw{
+		ID:     1,
+		Rounds: 1,
+		Matches: []*models.Match{
+			{ID: 1, Round: 1, HomeTeamID: intPtr(1), AwayTeamID: intPtr(2)},
+			{ID: 2, Round: 1, HomeTeamID: intPtr(3), AwayTeamID: intPtr(4)},
+			{ID: 3, Round: 1, HomeTeamID: intPtr(5)}, // Team 5 has the bye
+		},
+	}
+
+	// A full template, with prime time listed last so it's never the first pick
+	inventory := BuildInventory(time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC), 1, []TemplateSlot{
+		{Weekday: time.Saturday, Kickoff: clock(15, 0)},
+		{Weekday: time.Sunday, Kickoff: clock(14, 0)},
+		{Weekday: time.Sunday, Kickoff: clock(16, 0)},
+		{Weekday: time.Friday, Kickoff: clock(20, 0)},
+	}, nil)
+
+	result, err := NewAssigner(constraints.NewConstraintEngine(), nil).Assign(d, inventory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Assignments) != 2 {
+		t.Fatalf("Expected 2 assignments, got %d", len(result.Assignments))
+	}
+
+	primeTime := 0
+	for _, assignment := range result.Assignments {
+		if assignment.Slot.PrimeTime {
+			primeTime++
+		}
+	}
+	if primeTime != 1 {
+		t.Errorf("Expected the prime-time slot to be filled, got %d prime-time matches", primeTime)
+	}
+
+	want := models.RoundProfile{Round: 1, Matches: 2, Byes: 1, Timeslots: 4, PrimeTimeSlots: 1}
+	if len(result.Profiles) != 1 || result.Profiles[0] != want {
+		t.Errorf("Expected profile %+v, got %+v", want, result.Profiles)
+	}
+}
+
+func TestAssignFullSeason(t *testing.T) {
+	var teams []*models.Team
+	for i := 1; i <= 16; i++ {
+		teams = append(teams, &models.Team{ID: i, Name: "Team", ShortName: "T", City: "City"})
+	}
+	generator, err := draw.NewGenerator(teams, 15)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	season, err := generator.GenerateRoundRobin()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+	kickoff := time.Date(0, 1, 1, 19, 50, 0, 0, time.UTC)
+	inventory := make(map[int][]Slot)
+	for r := 1; r <= 15; r++ {
+		thursday := start.AddDate(0, 0, 7*(r-1))
+		for day := 0; day < 4; day++ {
+			for n := 0; n < 2; n++ {
+				inventory[r] = append(inventory[r], Slot{Date: thursday.AddDate(0, 0, day), Time: &kickoff, PrimeTime: day < 2 && n == 0})
+			}
+		}
+	}
+
+	started := time.Now()
+	result, err := NewAssigner(engine, nil).Assign(season, inventory)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Assignments) != 15*8 {
+		t.Errorf("Expected 120 assignments, got %d", len(result.Assignments))
+	}
+	for _, match := range result.Draw.Matches {
+		if !match.IsBye() && match.MatchDate == nil {
+			t.Fatalf("Match %d in round %d has no kickoff", match.ID, match.Round)
+		}
+	}
+	t.Logf("assigned a 15-round season in %v (score %.3f -> %.3f)", time.Since(started), result.ScoreBefore, result.ScoreAfter)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
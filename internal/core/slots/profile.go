@@ -0,0 +1,64 @@
+package slots
+
+import (
+	"sort"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// ProfileRounds describes each round in the inventory: its matches and byes
+// from the draw, and the slots the inventory offers it. PrimeTimeSlots is how
+// many prime-time slots the round should fill, which is all of them unless
+// byes leave the round with fewer matches than that.
+func ProfileRounds(draw *models.Draw, inventory map[int][]Slot) map[int]models.RoundProfile {
+	return profileRounds(draw.RoundProfiles(), inventory)
+}
+
+// profileRounds fits the inventory to rounds already described by the draw
+func profileRounds(scheduled map[int]models.RoundProfile, inventory map[int][]Slot) map[int]models.RoundProfile {
+	profiles := make(map[int]models.RoundProfile, len(inventory))
+	for round, slots := range inventory {
+		profile := models.RoundProfile{
+			Round:     round,
+			Matches:   scheduled[round].Matches,
+			Byes:      scheduled[round].Byes,
+			Timeslots: len(slots),
+		}
+		for _, slot := range slots {
+			if slot.PrimeTime {
+				profile.PrimeTimeSlots++
+			}
+		}
+		if profile.PrimeTimeSlots > profile.Matches {
+			profile.PrimeTimeSlots = profile.Matches
+		}
+		profiles[round] = profile
+	}
+	return profiles
+}
+
+// emptyPrimeTimeSlots counts the prime-time slots the draw leaves unfilled in
+// rounds with enough matches to fill them
+func emptyPrimeTimeSlots(draw *models.Draw, inventory map[int][]Slot) int {
+	scheduled := draw.RoundProfiles()
+
+	empty := 0
+	for round, profile := range profileRounds(scheduled, inventory) {
+		if filled := scheduled[round].PrimeTimeSlots; filled < profile.PrimeTimeSlots {
+			empty += profile.PrimeTimeSlots - filled
+		}
+	}
+	return empty
+}
+
+// sortedProfiles lists round profiles in round order
+func sortedProfiles(profiles map[int]models.RoundProfile) []models.RoundProfile {
+	sorted := make([]models.RoundProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		sorted = append(sorted, profile)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Round < sorted[j].Round
+	})
+	return sorted
+}
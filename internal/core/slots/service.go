@@ -0,0 +1,154 @@
+package slots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// ErrDrawNotReady is returned when a draw has no matchups to schedule
+var ErrDrawNotReady = errors.New("draw must be generated, and not being optimized, before kickoff slots can be assigned")
+
+// ErrNoSeasonStart is returned when rounds can't be dated: no season start
+// was given and the draw's season has no calendar
+var ErrNoSeasonStart = errors.New("season_start is required when the draw's season has no calendar")
+
+// Service assigns kickoff slots to stored draws
+type Service struct {
+	repository storage.Repositories
+}
+
+// NewService creates a new slot assignment service
+func NewService(repository storage.Repositories) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// AssignSlots schedules the draw's matches into the given per-round slot inventory,
+// scoring against the draw's own constraint configuration. With dryRun set the
+// result is returned without saving.
+func (s *Service) AssignSlots(ctx context.Context, drawID int, inventory map[int][]Slot, quotas []BroadcasterQuota, dryRun bool) (*Result, error) {
+	tx, err := s.repository.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draw, err := tx.Draws().GetWithMatches(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+	if draw.Status == models.DrawStatusOptimizing || len(draw.Matches) == 0 {
+		return nil, ErrDrawNotReady
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load constraint config: %w", err)
+	}
+	catalogue, err := tx.Timeslots().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timeslot catalogue: %w", err)
+	}
+	inventory = derivePrimeTime(inventory, CatalogueTemplate(catalogue, draw.CompetitionID))
+	league, err := constraints.LoadLeagueData(ctx, tx.Teams(), tx.Venues())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load teams and venues: %w", err)
+	}
+	engine.SetLeagueData(league)
+	if err := engine.LoadOtherDraws(ctx, tx.Draws()); err != nil {
+		return nil, err
+	}
+	if err := engine.LoadCalendar(ctx, tx.SeasonCalendars(), draw); err != nil {
+		return nil, err
+	}
+
+	result, err := NewAssigner(engine, quotas).Assign(draw, inventory)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	for _, assignment := range result.Assignments {
+		if err := tx.Matches().Update(ctx, assignment.Match); err != nil {
+			return nil, fmt.Errorf("failed to update match %d: %w", assignment.Match.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit slot assignment: %w", err)
+	}
+
+	return result, nil
+}
+
+// ScheduleTimeslots dates every round of the draw from weekly templates, with
+// round 1 in the week starting seasonStart, then assigns matches to the
+// resulting slots as AssignSlots does. With a zero seasonStart each round is
+// dated within its weekends in the season calendar instead. Without a template the timeslot
+// catalogue's slots for the draw's competition are used, or the standard NRL
+// round when it has none. Magic rounds in the draw's constraint configuration
+// are stacked over one weekend unless given their own template.
+func (s *Service) ScheduleTimeslots(ctx context.Context, drawID int, seasonStart time.Time, template []TemplateSlot, roundTemplates map[int][]TemplateSlot, quotas []BroadcasterQuota, dryRun bool) (*Result, error) {
+	draw, err := s.repository.Draws().Get(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(template) == 0 {
+		catalogue, err := s.repository.Timeslots().List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load timeslot catalogue: %w", err)
+		}
+		template = CatalogueTemplate(catalogue, draw.CompetitionID)
+	}
+	if len(template) == 0 {
+		template = DefaultNRLTemplate()
+	}
+
+	engine, err := constraints.NewConstraintEngineFromJSON(draw.ConstraintConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load constraint config: %w", err)
+	}
+	roundTemplates = StackMagicRounds(roundTemplates, engine.MagicRounds())
+
+	var inventory map[int][]Slot
+	if seasonStart.IsZero() {
+		calendar, err := s.repository.SeasonCalendars().GetForSeason(ctx, draw.SeasonYear, draw.CompetitionID)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNoSeasonStart
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load season calendar: %w", err)
+		}
+		inventory = BuildCalendarInventory(calendar, draw.Rounds, template, roundTemplates)
+	} else {
+		inventory = BuildInventory(seasonStart, draw.Rounds, template, roundTemplates)
+	}
+	return s.AssignSlots(ctx, drawID, inventory, quotas, dryRun)
+}
+
+// derivePrimeTime returns the inventory with prime time set on slots that
+// leave it to the catalogue. The given inventory is left unchanged.
+func derivePrimeTime(inventory map[int][]Slot, catalogue []TemplateSlot) map[int][]Slot {
+	derived := make(map[int][]Slot, len(inventory))
+	for round, roundSlots := range inventory {
+		derived[round] = make([]Slot, len(roundSlots))
+		for i, slot := range roundSlots {
+			if slot.AutoPrimeTime {
+				slot.PrimeTime = slot.Time != nil && CataloguePrimeTime(catalogue, slot.Date, *slot.Time)
+				slot.AutoPrimeTime = false
+			}
+			derived[round][i] = slot
+		}
+	}
+	return derived
+}
@@ -0,0 +1,193 @@
+package slots
+
+import (
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// primeTimeFrom is the earliest kickoff counted as prime time on prime-time nights
+const primeTimeFrom = 19 * time.Hour
+
+// TemplateSlot is a weekly kickoff window repeated in each round, such as
+// Friday 8pm. Only the clock time of Kickoff is used.
+type TemplateSlot struct {
+	Weekday     time.Weekday
+	Kickoff     time.Time
+	Broadcaster string
+	// PrimeTime overrides the automatic prime-time rule when set
+	PrimeTime *bool
+}
+
+// IsPrimeTime reports whether the slot is prime time, applying the automatic
+// rule unless the slot overrides it
+func (ts TemplateSlot) IsPrimeTime() bool {
+	if ts.PrimeTime != nil {
+		return *ts.PrimeTime
+	}
+	return IsPrimeTimeKickoff(ts.Weekday, ts.Kickoff)
+}
+
+// IsPrimeTimeKickoff reports whether a kickoff falls in the evening broadcast
+// windows: Thursday, Friday or Saturday from 7pm
+func IsPrimeTimeKickoff(weekday time.Weekday, kickoff time.Time) bool {
+	switch weekday {
+	case time.Thursday, time.Friday, time.Saturday:
+		clock := time.Duration(kickoff.Hour())*time.Hour + time.Duration(kickoff.Minute())*time.Minute
+		return clock >= primeTimeFrom
+	}
+	return false
+}
+
+// DefaultNRLTemplate returns the standard NRL round: Thursday 8pm, Friday 6pm
+// and 8pm, Saturday 3pm, 5:30pm and 7:35pm, and Sunday 2pm and 4pm
+func DefaultNRLTemplate() []TemplateSlot {
+	return []TemplateSlot{
+		{Weekday: time.Thursday, Kickoff: clock(20, 0)},
+		{Weekday: time.Friday, Kickoff: clock(18, 0)},
+		{Weekday: time.Friday, Kickoff: clock(20, 0)},
+		{Weekday: time.Saturday, Kickoff: clock(15, 0)},
+		{Weekday: time.Saturday, Kickoff: clock(17, 30)},
+		{Weekday: time.Saturday, Kickoff: clock(19, 35)},
+		{Weekday: time.Sunday, Kickoff: clock(14, 0)},
+		{Weekday: time.Sunday, Kickoff: clock(16, 0)},
+	}
+}
+
+// CatalogueTemplate returns the timeslot catalogue's slots for a draw in the
+// given competition as a weekly template, keeping each slot's channel and
+// prime-time flag
+func CatalogueTemplate(catalogue []*models.Timeslot, competitionID *int) []TemplateSlot {
+	var template []TemplateSlot
+	for _, timeslot := range catalogue {
+		if !timeslot.AppliesTo(competitionID) {
+			continue
+		}
+		kickoff, err := timeslot.KickoffTime()
+		if err != nil {
+			continue
+		}
+		primeTime := timeslot.IsPrimeTime
+		template = append(template, TemplateSlot{
+			Weekday:     timeslot.DayOfWeek,
+			Kickoff:     kickoff,
+			Broadcaster: timeslot.Channel,
+			PrimeTime:   &primeTime,
+		})
+	}
+	return template
+}
+
+// CataloguePrimeTime reports whether a kickoff on a date is prime time: as
+// flagged on the catalogue slot at that day and time, or by the automatic
+// rule when the catalogue has no such slot
+func CataloguePrimeTime(template []TemplateSlot, date, kickoff time.Time) bool {
+	for _, slot := range template {
+		if slot.Weekday == date.Weekday() && slot.Kickoff.Hour() == kickoff.Hour() && slot.Kickoff.Minute() == kickoff.Minute() {
+			return slot.IsPrimeTime()
+		}
+	}
+	return IsPrimeTimeKickoff(date.Weekday(), kickoff)
+}
+
+// MagicRoundTemplate returns a round stacked at a single venue from Friday to
+// Sunday: Friday 6pm and 8pm, Saturday 3pm, 5:30pm and 7:45pm, and Sunday
+// 1:50pm, 4:05pm and 6:15pm. Kickoffs on the same day are at least two hours
+// apart so the matches don't overlap.
+func MagicRoundTemplate() []TemplateSlot {
+	return []TemplateSlot{
+		{Weekday: time.Friday, Kickoff: clock(18, 0)},
+		{Weekday: time.Friday, Kickoff: clock(20, 0)},
+		{Weekday: time.Saturday, Kickoff: clock(15, 0)},
+		{Weekday: time.Saturday, Kickoff: clock(17, 30)},
+		{Weekday: time.Saturday, Kickoff: clock(19, 45)},
+		{Weekday: time.Sunday, Kickoff: clock(13, 50)},
+		{Weekday: time.Sunday, Kickoff: clock(16, 5)},
+		{Weekday: time.Sunday, Kickoff: clock(18, 15)},
+	}
+}
+
+// StackMagicRounds returns the round templates with MagicRoundTemplate added
+// for each magic round that doesn't already have its own template. The given
+// map is left unchanged.
+func StackMagicRounds(roundTemplates map[int][]TemplateSlot, magicRounds map[int]int) map[int][]TemplateSlot {
+	stacked := make(map[int][]TemplateSlot, len(roundTemplates)+len(magicRounds))
+	for round, template := range roundTemplates {
+		stacked[round] = template
+	}
+	for round := range magicRounds {
+		if _, exists := stacked[round]; !exists {
+			stacked[round] = MagicRoundTemplate()
+		}
+	}
+	return stacked
+}
+
+// BuildInventory expands weekly templates into dated slots for each round.
+// Round 1's week starts on seasonStart and each later round a week after the
+// one before; a slot falls on the first day of its weekday in the round's
+// week. Rounds listed in roundTemplates use their own template, such as a
+// shortened Easter or Origin round, and the rest use template.
+func BuildInventory(seasonStart time.Time, rounds int, template []TemplateSlot, roundTemplates map[int][]TemplateSlot) map[int][]Slot {
+	start := time.Date(seasonStart.Year(), seasonStart.Month(), seasonStart.Day(), 0, 0, 0, 0, seasonStart.Location())
+
+	inventory := make(map[int][]Slot, rounds)
+	for round := 1; round <= rounds; round++ {
+		weekStart := start.AddDate(0, 0, 7*(round-1))
+
+		roundTemplate := template
+		if override, exists := roundTemplates[round]; exists {
+			roundTemplate = override
+		}
+
+		for _, templateSlot := range roundTemplate {
+			offset := (int(templateSlot.Weekday) - int(weekStart.Weekday()) + 7) % 7
+			kickoff := templateSlot.Kickoff
+			inventory[round] = append(inventory[round], Slot{
+				Date:        weekStart.AddDate(0, 0, offset),
+				Time:        &kickoff,
+				PrimeTime:   templateSlot.IsPrimeTime(),
+				Broadcaster: templateSlot.Broadcaster,
+			})
+		}
+	}
+	return inventory
+}
+
+// BuildCalendarInventory expands weekly templates into dated slots for each
+// round the season calendar dates. A slot falls on every day of its weekday
+// within the round's date ranges, so a split round gets slots on each of its
+// weekends. Rounds the calendar doesn't date get no slots. Rounds listed in
+// roundTemplates use their own template, as for BuildInventory.
+func BuildCalendarInventory(calendar *models.SeasonCalendar, rounds int, template []TemplateSlot, roundTemplates map[int][]TemplateSlot) map[int][]Slot {
+	inventory := make(map[int][]Slot, rounds)
+	for round := 1; round <= rounds; round++ {
+		roundTemplate := template
+		if override, exists := roundTemplates[round]; exists {
+			roundTemplate = override
+		}
+
+		for _, dates := range calendar.RoundDates(round) {
+			for _, day := range dates.Days() {
+				for _, templateSlot := range roundTemplate {
+					if templateSlot.Weekday != day.Weekday() {
+						continue
+					}
+					kickoff := templateSlot.Kickoff
+					inventory[round] = append(inventory[round], Slot{
+						Date:        day,
+						Time:        &kickoff,
+						PrimeTime:   templateSlot.IsPrimeTime(),
+						Broadcaster: templateSlot.Broadcaster,
+					})
+				}
+			}
+		}
+	}
+	return inventory
+}
+
+// clock returns a kickoff time of day in the same form as parsed "15:04" times
+func clock(hour, minute int) time.Time {
+	return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+}
@@ -0,0 +1,154 @@
+package slots
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestBuildInventoryDefaultTemplate(t *testing.T) {
+	// Round 1's week starts on a Thursday
+	seasonStart := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+	inventory := BuildInventory(seasonStart, 2, DefaultNRLTemplate(), nil)
+
+	if len(inventory) != 2 || len(inventory[1]) != 8 || len(inventory[2]) != 8 {
+		t.Fatalf("Expected 8 slots in each of 2 rounds, got %d rounds", len(inventory))
+	}
+
+	first, last := inventory[1][0], inventory[1][7]
+	if !first.Date.Equal(seasonStart) || first.Time.Format("15:04") != "20:00" || !first.PrimeTime {
+		t.Errorf("Expected round 1 to open with Thursday 8pm prime time, got %s %s prime=%v",
+			first.Date.Format("Mon 2006-01-02"), first.Time.Format("15:04"), first.PrimeTime)
+	}
+	if want := seasonStart.AddDate(0, 0, 3); !last.Date.Equal(want) || last.PrimeTime {
+		t.Errorf("Expected round 1 to close on Sunday %s outside prime time, got %s prime=%v",
+			want.Format("2006-01-02"), last.Date.Format("2006-01-02"), last.PrimeTime)
+	}
+	if want := seasonStart.AddDate(0, 0, 7); !inventory[2][0].Date.Equal(want) {
+		t.Errorf("Expected round 2 a week later on %s, got %s", want.Format("2006-01-02"), inventory[2][0].Date.Format("2006-01-02"))
+	}
+
+	primeTime := 0
+	for _, slot := range inventory[1] {
+		if slot.PrimeTime {
+			primeTime++
+		}
+	}
+	if primeTime != 3 {
+		t.Errorf("Expected Thursday 8pm, Friday 8pm and Saturday 7:35pm as prime time, got %d slots", primeTime)
+	}
+}
+
+func TestBuildInventoryRoundTemplates(t *testing.T) {
+	notPrime := false
+	seasonStart := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+	roundTemplates := map[int][]TemplateSlot{
+		2: {{Weekday: time.Friday, Kickoff: clock(20, 0), PrimeTime: &notPrime}},
+	}
+
+	inventory := BuildInventory(seasonStart, 3, DefaultNRLTemplate(), roundTemplates)
+
+	if len(inventory[2]) != 1 || len(inventory[3]) != 8 {
+		t.Fatalf("Expected round 2 to use its own template, got %d and %d slots", len(inventory[2]), len(inventory[3]))
+	}
+	if slot := inventory[2][0]; slot.PrimeTime || !slot.Date.Equal(time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected an overridden non prime-time Friday slot on 2025-03-14, got %s prime=%v", slot.Date.Format("2006-01-02"), slot.PrimeTime)
+	}
+}
+
+func TestStackMagicRounds(t *testing.T) {
+	seasonStart := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+	own := []TemplateSlot{{Weekday: time.Saturday, Kickoff: clock(19, 0)}}
+	roundTemplates := map[int][]TemplateSlot{3: own}
+
+	stacked := StackMagicRounds(roundTemplates, map[int]int{2: 9, 3: 9})
+	if len(roundTemplates) != 1 {
+		t.Error("Expected the given round templates to be left unchanged")
+	}
+	if len(stacked[3]) != 1 {
+		t.Errorf("Expected round 3 to keep its own template, got %d slots", len(stacked[3]))
+	}
+
+	inventory := BuildInventory(seasonStart, 3, DefaultNRLTemplate(), stacked)
+	magic := inventory[2]
+	if len(magic) != 8 {
+		t.Fatalf("Expected 8 stacked slots in the magic round, got %d", len(magic))
+	}
+
+	friday := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	for i, slot := range magic {
+		if slot.Date.Before(friday) || slot.Date.After(friday.AddDate(0, 0, 2)) {
+			t.Errorf("Slot %d on %s is outside the Friday to Sunday weekend", i, slot.Date.Format("Mon 2006-01-02"))
+		}
+		if i == 0 || !slot.Date.Equal(magic[i-1].Date) {
+			continue
+		}
+		if gap := slot.Time.Sub(*magic[i-1].Time); gap < 2*time.Hour {
+			t.Errorf("Slot %d kicks off %s after the one before, want at least two hours", i, gap)
+		}
+	}
+}
+
+func TestCatalogueTemplate(t *testing.T) {
+	nrl, nrlw := 1, 2
+	catalogue := []*models.Timeslot{
+		{DayOfWeek: time.Friday, Kickoff: "20:00", Channel: "Nine", IsPrimeTime: true},
+		{DayOfWeek: time.Saturday, Kickoff: "17:30", Channel: "Fox League", IsPrimeTime: true},
+		{DayOfWeek: time.Sunday, Kickoff: "12:00", IsPrimeTime: false, CompetitionID: &nrlw},
+	}
+
+	template := CatalogueTemplate(catalogue, &nrl)
+	if len(template) != 2 {
+		t.Fatalf("Expected the 2 shared slots for the NRL, got %d", len(template))
+	}
+	if template[1].Broadcaster != "Fox League" || !template[1].IsPrimeTime() {
+		t.Errorf("Expected the Saturday slot to keep its channel and prime-time flag, got %+v", template[1])
+	}
+	if len(CatalogueTemplate(catalogue, &nrlw)) != 3 {
+		t.Error("Expected the NRLW to use the shared slots and its own")
+	}
+
+	// Kickoffs take the flag of their catalogue slot, then the automatic rule
+	saturday := time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)
+	if !CataloguePrimeTime(template, saturday, clock(17, 30)) {
+		t.Error("Expected Saturday 5:30pm to be prime time as flagged in the catalogue")
+	}
+	if !CataloguePrimeTime(template, saturday, clock(19, 35)) {
+		t.Error("Expected Saturday 7:35pm outside the catalogue to be prime time by the automatic rule")
+	}
+	if CataloguePrimeTime(template, saturday.AddDate(0, 0, 1), clock(17, 30)) {
+		t.Error("Expected Sunday 5:30pm not to be prime time")
+	}
+}
+
+func TestBuildCalendarInventory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+	calendar := &models.SeasonCalendar{
+		Name:       "NRL 2025",
+		SeasonYear: 2025,
+		Rounds: []models.CalendarRound{
+			{Round: 1, StartDate: day(6), EndDate: day(9), Kind: models.RoundKindRegular},
+			// Round 2 is split either side of a fortnight off
+			{Round: 2, StartDate: day(13), EndDate: day(16), Kind: models.RoundKindSplit},
+			{Round: 2, StartDate: day(27), EndDate: day(30), Kind: models.RoundKindSplit},
+		},
+	}
+
+	inventory := BuildCalendarInventory(calendar, 3, DefaultNRLTemplate(), nil)
+
+	if len(inventory[1]) != 8 || len(inventory[2]) != 16 {
+		t.Fatalf("Expected 8 slots in round 1 and 16 over round 2's two weekends, got %d and %d", len(inventory[1]), len(inventory[2]))
+	}
+	if len(inventory[3]) != 0 {
+		t.Errorf("Expected no slots for round 3, which the calendar doesn't date, got %d", len(inventory[3]))
+	}
+	if first := inventory[1][0]; !first.Date.Equal(day(6)) || !first.PrimeTime {
+		t.Errorf("Expected round 1 to open with Thursday prime time on 2025-03-06, got %s prime=%v", first.Date.Format("2006-01-02"), first.PrimeTime)
+	}
+	for _, slot := range inventory[2] {
+		if !calendar.InRound(2, slot.Date) {
+			t.Errorf("Round 2 slot on %s is outside its weekends", slot.Date.Format("2006-01-02"))
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Headers sent with each delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// DefaultTimeout is how long a receiver has to respond to one attempt
+const DefaultTimeout = 10 * time.Second
+
+// Payload is the JSON body delivered to a webhook
+type Payload struct {
+	// ID identifies the delivery, and is the same across its retries so
+	// receivers can ignore duplicates
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// RetryPolicy sets how often a failed delivery is attempted. The wait after
+// each failed attempt doubles from Backoff.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// DefaultRetryPolicy tries a delivery five times over about half a minute
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     2 * time.Second,
+	}
+}
+
+// Sign returns the signature of a payload delivered at the given Unix time:
+// "sha256=" and the hex HMAC-SHA256 of the timestamp, a dot and the body,
+// keyed by the webhook's secret. Receivers recompute it to check the
+// payload came from us, and check the timestamp is recent to reject replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether a signature matches a payload
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// deliver sends an event to a webhook, retrying failures, and records the
+// outcome of the last attempt
+func (s *Service) deliver(webhook *models.Webhook, evt event) {
+	id, err := generateDeliveryID()
+	if err != nil {
+		log.Printf("Error delivering %s event to webhook %d: %v", evt.name, webhook.ID, err)
+		return
+	}
+	body, err := json.Marshal(Payload{ID: id, Event: evt.name, CreatedAt: evt.at, Data: evt.data})
+	if err != nil {
+		log.Printf("Error encoding %s event for webhook %d: %v", evt.name, webhook.ID, err)
+		return
+	}
+
+	backoff := s.retry.Backoff
+	var status int
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		status, retry, err = s.attempt(webhook, evt.name, id, body)
+		if err == nil || !retry || attempt >= s.retry.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			err = fmt.Errorf("abandoned at shutdown after %d attempts: %w", attempt, err)
+			s.record(webhook, status, err)
+			return
+		}
+	}
+	s.record(webhook, status, err)
+}
+
+// attempt makes one delivery attempt, returning the response status, any
+// failure, and whether the failure is worth retrying. Network errors, server
+// errors, timeouts and rate limiting are retried; other client errors aren't.
+func (s *Service) attempt(webhook *models.Webhook, name, id string, body []byte) (int, bool, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "nrl-scheduler-webhooks")
+	req.Header.Set(HeaderEvent, name)
+	req.Header.Set(HeaderDelivery, id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return resp.StatusCode, retry, fmt.Errorf("receiver responded %s", resp.Status)
+}
+
+// record saves the outcome of a delivery on the webhook
+func (s *Service) record(webhook *models.Webhook, status int, deliveryErr error) {
+	var message string
+	if deliveryErr != nil {
+		message = deliveryErr.Error()
+		log.Printf("Webhook %d delivery failed: %s", webhook.ID, message)
+	}
+
+	// The service context may already be cancelled at shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.repository.Webhooks().RecordDelivery(ctx, webhook.ID, time.Now(), status, message); err != nil {
+		log.Printf("Error recording webhook %d delivery: %v", webhook.ID, err)
+	}
+}
+
+// generateDeliveryID returns a random delivery ID
+func generateDeliveryID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate delivery ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// Events a webhook can subscribe to
+const (
+	EventOptimizationCompleted = "optimization.completed"
+	EventDrawGenerated         = "draw.generated"
+	EventConstraintsValidated  = "constraints.validated"
+	EventApprovalRequested     = "approval.requested"
+	EventApprovalRecorded      = "approval.recorded"
+	EventDrawPublished         = "draw.published"
+)
+
+// Events lists every event a webhook can subscribe to
+var Events = []string{
+	EventOptimizationCompleted, EventDrawGenerated, EventConstraintsValidated,
+	EventApprovalRequested, EventApprovalRecorded, EventDrawPublished,
+}
+
+// secretBytes is the amount of randomness in a generated signing secret
+const secretBytes = 32
+
+// queueSize is how many events can wait for delivery before new ones are dropped
+const queueSize = 256
+
+// ErrUnknownEvent is returned when registering a webhook for an event that
+// doesn't exist
+var ErrUnknownEvent = errors.New("unknown webhook event")
+
+// event is a published event waiting to be delivered
+type event struct {
+	name string
+	data interface{}
+	at   time.Time
+}
+
+// Service registers webhooks and delivers published events to the webhooks
+// subscribed to them. Deliveries are signed and retried in the background,
+// so publishing never blocks the caller.
+type Service struct {
+	repository storage.Repositories
+	client     *http.Client
+	retry      RetryPolicy
+
+	queue chan event
+	// deliveries tracks deliveries in flight, including their retries
+	deliveries sync.WaitGroup
+	// ctx is cancelled to abandon retries when shutdown runs out of time
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// quit asks Run to deliver what's queued and stop; done is closed once it has
+	quit     chan struct{}
+	done     chan struct{}
+	quitOnce sync.Once
+}
+
+// NewService creates a new webhook service. Call Run to start delivering.
+func NewService(repository storage.Repositories) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		repository: repository,
+		client:     &http.Client{Timeout: DefaultTimeout},
+		retry:      DefaultRetryPolicy(),
+		queue:      make(chan event, queueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Register creates a webhook, generating a signing secret when it has none
+func (s *Service) Register(ctx context.Context, webhook *models.Webhook) error {
+	if err := validateEvents(webhook.Events); err != nil {
+		return err
+	}
+	if webhook.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+	if err := webhook.Validate(); err != nil {
+		return err
+	}
+	return s.repository.Webhooks().Create(ctx, webhook)
+}
+
+// Update saves changes to a webhook
+func (s *Service) Update(ctx context.Context, webhook *models.Webhook) error {
+	if err := validateEvents(webhook.Events); err != nil {
+		return err
+	}
+	if err := webhook.Validate(); err != nil {
+		return err
+	}
+	return s.repository.Webhooks().Update(ctx, webhook)
+}
+
+// Get returns a webhook
+func (s *Service) Get(ctx context.Context, id int) (*models.Webhook, error) {
+	return s.repository.Webhooks().Get(ctx, id)
+}
+
+// List returns every webhook
+func (s *Service) List(ctx context.Context) ([]*models.Webhook, error) {
+	return s.repository.Webhooks().List(ctx)
+}
+
+// Delete removes a webhook. Deliveries already in flight still complete.
+func (s *Service) Delete(ctx context.Context, id int) error {
+	return s.repository.Webhooks().Delete(ctx, id)
+}
+
+// Publish queues an event for delivery to the webhooks subscribed to it.
+// Events published once the queue is full or the service has shut down are
+// dropped.
+func (s *Service) Publish(name string, data interface{}) {
+	select {
+	case <-s.quit:
+		return
+	default:
+	}
+
+	select {
+	case s.queue <- event{name: name, data: data, at: time.Now()}:
+	default:
+		log.Printf("Webhook queue full, dropping %s event", name)
+	}
+}
+
+// Run delivers published events until Shutdown is called
+func (s *Service) Run() {
+	defer close(s.done)
+	for {
+		select {
+		case evt := <-s.queue:
+			s.dispatch(evt)
+		case <-s.quit:
+			// Deliver what was published before shutdown
+			for {
+				select {
+				case evt := <-s.queue:
+					s.dispatch(evt)
+				default:
+					s.deliveries.Wait()
+					return
+				}
+			}
+		}
+	}
+}
+
+// Shutdown stops accepting events and waits for queued events to be
+// delivered. When ctx is done first, retries still pending are abandoned.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.quitOnce.Do(func() { close(s.quit) })
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// dispatch starts delivering an event to each webhook subscribed to it
+func (s *Service) dispatch(evt event) {
+	webhooks, err := s.repository.Webhooks().List(s.ctx)
+	if err != nil {
+		log.Printf("Error loading webhooks for %s event: %v", evt.name, err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(evt.name) {
+			continue
+		}
+		s.deliveries.Add(1)
+		go func(webhook *models.Webhook) {
+			defer s.deliveries.Done()
+			s.deliver(webhook, evt)
+		}(webhook)
+	}
+}
+
+// validateEvents checks every event can be subscribed to
+func validateEvents(events []string) error {
+	for _, name := range events {
+		known := false
+		for _, event := range Events {
+			if name == event {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownEvent, name)
+		}
+	}
+	return nil
+}
+
+// generateSecret returns a random signing secret
+func generateSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage/sqlite"
+	"github.com/adampetrovic/nrl-scheduler/migrations"
+)
+
+// newTestService creates a running service over a migrated database that
+// retries quickly
+func newTestService(t *testing.T) *Service {
+	db, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.MigrateFS(migrations.FS))
+
+	service := NewService(sqlite.NewRepositories(db.Conn()))
+	service.retry = RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	go service.Run()
+	return service
+}
+
+// receiver records the deliveries it receives, responding with the given
+// statuses in turn and 200 once they run out
+type receiver struct {
+	mutex    sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"draw.generated"}`)
+	signature := Sign("s3cret", 1700000000, body)
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, Verify("s3cret", 1700000000, body, signature))
+	assert.False(t, Verify("other", 1700000000, body, signature), "a different secret must not verify")
+	assert.False(t, Verify("s3cret", 1700000001, body, signature), "a different timestamp must not verify")
+	assert.False(t, Verify("s3cret", 1700000000, []byte(`{}`), signature), "a different body must not verify")
+}
+
+func TestRegister(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	// A secret is generated when none is given
+	hook := &models.Webhook{URL: "https://fixtures.example.com/hooks", Events: []string{EventDrawGenerated}, IsActive: true}
+	require.NoError(t, service.Register(ctx, hook))
+	assert.NotZero(t, hook.ID)
+	assert.Len(t, hook.Secret, 2*secretBytes)
+
+	saved, err := service.Get(ctx, hook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{EventDrawGenerated}, saved.Events)
+	assert.Equal(t, hook.Secret, saved.Secret)
+
+	err = service.Register(ctx, &models.Webhook{URL: "https://fixtures.example.com/hooks", Events: []string{"draw.deleted"}})
+	assert.True(t, errors.Is(err, ErrUnknownEvent))
+}
+
+func TestDelivery(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	flaky := &receiver{statuses: []int{http.StatusServiceUnavailable}}
+	flakyServer := httptest.NewServer(flaky)
+	defer flakyServer.Close()
+	rejecting := &receiver{statuses: []int{http.StatusBadRequest}}
+	rejectingServer := httptest.NewServer(rejecting)
+	defer rejectingServer.Close()
+	other := &receiver{}
+	otherServer := httptest.NewServer(other)
+	defer otherServer.Close()
+
+	flakyHook := &models.Webhook{URL: flakyServer.URL, Events: []string{EventOptimizationCompleted}, Secret: "flaky-secret", IsActive: true}
+	rejectingHook := &models.Webhook{URL: rejectingServer.URL, Events: []string{EventOptimizationCompleted}, Secret: "rejecting-secret", IsActive: true}
+	otherHook := &models.Webhook{URL: otherServer.URL, Events: []string{EventDrawGenerated}, Secret: "other-secret", IsActive: true}
+	for _, hook := range []*models.Webhook{flakyHook, rejectingHook, otherHook} {
+		require.NoError(t, service.Register(ctx, hook))
+	}
+
+	service.Publish(EventOptimizationCompleted, map[string]interface{}{"job_id": "job-1", "final_score": 0.9})
+	require.NoError(t, service.Shutdown(ctx))
+
+	// Server errors are retried with the same delivery ID and a valid signature
+	require.Len(t, flaky.requests, 2)
+	assert.Equal(t, flaky.requests[0].Header.Get(HeaderDelivery), flaky.requests[1].Header.Get(HeaderDelivery))
+	request := flaky.requests[1]
+	assert.Equal(t, EventOptimizationCompleted, request.Header.Get(HeaderEvent))
+	timestamp, err := strconv.ParseInt(request.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, Verify("flaky-secret", timestamp, flaky.bodies[1], request.Header.Get(HeaderSignature)))
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(flaky.bodies[1], &payload))
+	assert.Equal(t, EventOptimizationCompleted, payload.Event)
+	assert.Equal(t, "job-1", payload.Data.(map[string]interface{})["job_id"])
+
+	// Client errors aren't retried, and unsubscribed webhooks aren't called
+	assert.Len(t, rejecting.requests, 1)
+	assert.Empty(t, other.requests)
+
+	// The outcome of each delivery is recorded
+	saved, err := service.Get(ctx, flakyHook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, saved.LastStatus)
+	assert.Empty(t, saved.LastError)
+	assert.NotNil(t, saved.LastDeliveryAt)
+
+	saved, err = service.Get(ctx, rejectingHook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, saved.LastStatus)
+	assert.Contains(t, saved.LastError, "400")
+
+	// Nothing is delivered after shutdown
+	service.Publish(EventDrawGenerated, nil)
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, other.requests)
+}
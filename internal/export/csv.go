@@ -0,0 +1,45 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// CSVContentType is the media type of CSV exports
+const CSVContentType = "text/csv; charset=utf-8"
+
+// csvHeader names the columns of a CSV export
+var csvHeader = []string{"Round", "Date", "Time", "Home Team", "Away Team", "Venue", "Prime Time"}
+
+// WriteCSV writes the draw's fixture list as CSV, one match per row
+func WriteCSV(w io.Writer, draw *models.Draw, locale Locale, filter Filter) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, row := range FixtureRows(draw, locale, filter) {
+		primeTime := "No"
+		if row.IsPrimeTime {
+			primeTime = "Yes"
+		}
+		record := []string{
+			strconv.Itoa(row.Round),
+			row.Date,
+			row.Time,
+			row.HomeTeam,
+			row.AwayTeam,
+			row.Venue,
+			primeTime,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
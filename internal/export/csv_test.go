@@ -0,0 +1,96 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// testExportDraw returns a two-round draw with its teams and venues loaded
+func testExportDraw() *models.Draw {
+	broncos := &models.Team{ID: 1, Name: "Brisbane Broncos"}
+	storm := &models.Team{ID: 2, Name: "Melbourne Storm"}
+	roosters := &models.Team{ID: 3, Name: "Sydney Roosters"}
+	rabbitohs := &models.Team{ID: 4, Name: "South Sydney Rabbitohs"}
+	langPark := &models.Venue{ID: 1, Name: "Lang Park"}
+	allianz := &models.Venue{ID: 2, Name: "Allianz Stadium, Moore Park"}
+
+	roundOne := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+	roundTwo := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)
+	kickoff := time.Date(0, 1, 1, 19, 50, 0, 0, time.UTC)
+
+	match := func(id, round int, home, away *models.Team, venue *models.Venue, date, kickoffTime *time.Time) *models.Match {
+		return &models.Match{
+			ID: id, DrawID: 1, Round: round,
+			HomeTeamID: &home.ID, AwayTeamID: &away.ID, VenueID: &venue.ID,
+			HomeTeam: home, AwayTeam: away, Venue: venue,
+			MatchDate: date, MatchTime: kickoffTime,
+			IsPrimeTime: kickoffTime != nil,
+		}
+	}
+
+	return &models.Draw{
+		ID: 1, Name: "NRL Premiership", SeasonYear: 2025, Rounds: 2,
+		Matches: []*models.Match{
+			match(1, 1, broncos, storm, langPark, &roundOne, &kickoff),
+			match(2, 1, roosters, rabbitohs, allianz, &roundOne, nil),
+			match(3, 2, storm, roosters, langPark, &roundTwo, nil),
+			{ID: 4, DrawID: 1, Round: 2}, // bye
+			match(5, 2, rabbitohs, broncos, allianz, nil, nil),
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testExportDraw(), DefaultLocale(), Filter{}); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("export should be valid CSV: %v", err)
+	}
+
+	// Header plus every match except the bye
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, got %d", len(records))
+	}
+	if records[0][0] != "Round" {
+		t.Errorf("first record should be the header, got %v", records[0])
+	}
+
+	first := records[1]
+	expected := []string{"1", "Thu 6 Mar 2025", "7:50pm", "Brisbane Broncos", "Melbourne Storm", "Lang Park", "Yes"}
+	for i := range expected {
+		if first[i] != expected[i] {
+			t.Errorf("column %s = %q, want %q", csvHeader[i], first[i], expected[i])
+		}
+	}
+
+	// Venue names with commas are quoted rather than split
+	if records[2][5] != "Allianz Stadium, Moore Park" {
+		t.Errorf("venue should survive quoting, got %q", records[2][5])
+	}
+	// Unscheduled matches are exported with blank dates
+	if records[4][1] != "" || records[4][2] != "" {
+		t.Errorf("unscheduled match should have no date or time, got %v", records[4])
+	}
+}
+
+func TestFixtureRowsFilter(t *testing.T) {
+	draw := testExportDraw()
+
+	teamRows := FixtureRows(draw, DefaultLocale(), Filter{TeamID: 1})
+	if len(teamRows) != 2 {
+		t.Fatalf("expected 2 Broncos matches, got %d", len(teamRows))
+	}
+
+	venueRows := FixtureRows(draw, DefaultLocale(), Filter{VenueID: 1})
+	if len(venueRows) != 2 || venueRows[1].MatchID != 3 {
+		t.Errorf("expected matches 1 and 3 at Lang Park, got %+v", venueRows)
+	}
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// FixtureRow is one match of an exported fixture list, resolved to display names
+type FixtureRow struct {
+	MatchID     int
+	Round       int
+	Date        string
+	Time        string
+	HomeTeam    string
+	AwayTeam    string
+	Venue       string
+	IsPrimeTime bool
+}
+
+// Filter restricts an export to the matches of one team or at one venue.
+// Zero values match everything.
+type Filter struct {
+	TeamID  int
+	VenueID int
+}
+
+// Includes reports whether a match passes the filter. Byes never do, since
+// they have no teams or venue to export.
+func (f Filter) Includes(match *models.Match) bool {
+	if match.IsBye() {
+		return false
+	}
+	if f.TeamID > 0 && !match.HasTeam(f.TeamID) {
+		return false
+	}
+	if f.VenueID > 0 && (match.VenueID == nil || *match.VenueID != f.VenueID) {
+		return false
+	}
+	return true
+}
+
+// FixtureRows renders the draw's matches for export. Matches need their teams
+// and venues loaded, as returned by ListByDrawWithRelations.
+func FixtureRows(draw *models.Draw, locale Locale, filter Filter) []FixtureRow {
+	rows := make([]FixtureRow, 0, len(draw.Matches))
+	for _, match := range draw.Matches {
+		if !filter.Includes(match) {
+			continue
+		}
+		rows = append(rows, FixtureRow{
+			MatchID:     match.ID,
+			Round:       match.Round,
+			Date:        locale.FormatDate(match.MatchDate),
+			Time:        locale.FormatTime(match.MatchTime),
+			HomeTeam:    locale.TeamName(match.HomeTeam, match.Round),
+			AwayTeam:    locale.TeamName(match.AwayTeam, match.Round),
+			Venue:       locale.VenueName(match.Venue, match.MatchDate),
+			IsPrimeTime: match.IsPrimeTime,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	// Kick-off times are converted from the league's time zone, which
+	// minimal containers may not have zone data for
+	_ "time/tzdata"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// ICSContentType is the media type of iCalendar exports
+const ICSContentType = "text/calendar; charset=utf-8"
+
+// DefaultTimezone is the time zone kick-off times are recorded in
+const DefaultTimezone = "Australia/Sydney"
+
+// DefaultMatchDuration is how long a calendar event blocks out for a match
+const DefaultMatchDuration = 2 * time.Hour
+
+// icsLineLimit is the longest content line RFC 5545 allows, in octets
+const icsLineLimit = 75
+
+// Calendar describes an iCalendar feed of a draw's matches
+type Calendar struct {
+	Name     string         // calendar display name, e.g. "Broncos - NRL 2025"
+	Locale   Locale         // display names for teams and venues
+	Filter   Filter         // restricts the feed to one team or venue
+	Location *time.Location // time zone kick-off times are recorded in
+}
+
+// WriteICS writes the draw's matches as an iCalendar feed. Matches with a
+// kick-off time become timed events; matches with only a date become all-day
+// events. Unscheduled matches are left out since they can't be placed.
+func WriteICS(w io.Writer, draw *models.Draw, calendar Calendar) error {
+	location := calendar.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	ics := &icsWriter{w: w}
+	ics.line("BEGIN:VCALENDAR")
+	ics.line("VERSION:2.0")
+	ics.line("PRODID:-//NRL Scheduler//Draw Export//EN")
+	ics.line("CALSCALE:GREGORIAN")
+	ics.line("METHOD:PUBLISH")
+	ics.property("X-WR-CALNAME", calendar.Name)
+	ics.property("X-WR-TIMEZONE", location.String())
+
+	for _, match := range draw.Matches {
+		if !calendar.Filter.Includes(match) || match.MatchDate == nil {
+			continue
+		}
+
+		homeTeam := calendar.Locale.TeamName(match.HomeTeam, match.Round)
+		awayTeam := calendar.Locale.TeamName(match.AwayTeam, match.Round)
+
+		ics.line("BEGIN:VEVENT")
+		ics.line(fmt.Sprintf("UID:match-%d-draw-%d@nrl-scheduler", match.ID, draw.ID))
+		ics.line("DTSTAMP:" + icsTimestamp(match.UpdatedAt))
+		if match.MatchTime != nil {
+			date := *match.MatchDate
+			kickoff := time.Date(date.Year(), date.Month(), date.Day(),
+				match.MatchTime.Hour(), match.MatchTime.Minute(), 0, 0, location)
+			ics.line("DTSTART:" + icsTimestamp(kickoff))
+			ics.line("DTEND:" + icsTimestamp(kickoff.Add(DefaultMatchDuration)))
+		} else {
+			date := *match.MatchDate
+			ics.line("DTSTART;VALUE=DATE:" + date.Format("20060102"))
+			ics.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"))
+		}
+		ics.property("SUMMARY", fmt.Sprintf("%s v %s", homeTeam, awayTeam))
+		ics.property("LOCATION", calendar.Locale.VenueName(match.Venue, match.MatchDate))
+		ics.property("DESCRIPTION", fmt.Sprintf("%s %d, Round %d", draw.Name, draw.SeasonYear, match.Round))
+		ics.line("END:VEVENT")
+	}
+
+	ics.line("END:VCALENDAR")
+	return ics.err
+}
+
+// icsTimestamp formats a time as a UTC iCalendar date-time
+func icsTimestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsEscape escapes a text value as RFC 5545 requires
+func icsEscape(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+// icsWriter writes folded CRLF content lines, keeping the first error
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// property writes a text property, skipping empty values
+func (iw *icsWriter) property(name, value string) {
+	if value == "" {
+		return
+	}
+	iw.line(name + ":" + icsEscape(value))
+}
+
+// line writes a content line, folding it onto continuation lines when it is
+// longer than RFC 5545 allows. Folds never split a UTF-8 character.
+func (iw *icsWriter) line(content string) {
+	if iw.err != nil {
+		return
+	}
+
+	var folded strings.Builder
+	limit := icsLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		folded.WriteString(content[:cut])
+		folded.WriteString("\r\n ")
+		content = content[cut:]
+		limit = icsLineLimit - 1 // continuation lines start with a space
+	}
+	folded.WriteString(content)
+	folded.WriteString("\r\n")
+
+	_, iw.err = io.WriteString(iw.w, folded.String())
+}
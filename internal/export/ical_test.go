@@ -0,0 +1,78 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteICS(t *testing.T) {
+	location, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteICS(&buf, testExportDraw(), Calendar{
+		Name:     "NRL Premiership 2025",
+		Locale:   DefaultLocale(),
+		Location: location,
+	})
+	if err != nil {
+		t.Fatalf("WriteICS() error = %v", err)
+	}
+	feed := buf.String()
+
+	if !strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(feed, "END:VCALENDAR\r\n") {
+		t.Error("feed should be a CRLF-terminated VCALENDAR")
+	}
+
+	// The unscheduled match and the bye are left out
+	if count := strings.Count(feed, "BEGIN:VEVENT"); count != 3 {
+		t.Errorf("expected 3 events, got %d", count)
+	}
+
+	// 7:50pm AEDT is 8:50am UTC
+	if !strings.Contains(feed, "DTSTART:20250306T085000Z\r\n") {
+		t.Error("timed match should start at its kick-off in UTC")
+	}
+	if !strings.Contains(feed, "DTSTART;VALUE=DATE:20250306\r\n") {
+		t.Error("match without a kick-off time should be an all-day event")
+	}
+	if !strings.Contains(feed, `LOCATION:Allianz Stadium\, Moore Park`) {
+		t.Error("commas in text values should be escaped")
+	}
+	if !strings.Contains(feed, "UID:match-1-draw-1@nrl-scheduler\r\n") {
+		t.Error("events should have stable UIDs")
+	}
+
+	buf.Reset()
+	if err := WriteICS(&buf, testExportDraw(), Calendar{Filter: Filter{TeamID: 4}}); err != nil {
+		t.Fatalf("WriteICS() error = %v", err)
+	}
+	if count := strings.Count(buf.String(), "BEGIN:VEVENT"); count != 1 {
+		t.Errorf("expected 1 scheduled Rabbitohs event, got %d", count)
+	}
+}
+
+func TestICSLineFolding(t *testing.T) {
+	var buf bytes.Buffer
+	ics := &icsWriter{w: &buf}
+	ics.property("DESCRIPTION", strings.Repeat("é", 100))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > icsLineLimit {
+			t.Errorf("line is %d octets, limit is %d", len(line), icsLineLimit)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("fold split a character: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	if unfolded != "DESCRIPTION:"+strings.Repeat("é", 100)+"\r\n" {
+		t.Error("unfolding should restore the original line")
+	}
+}
@@ -0,0 +1,170 @@
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// Locale controls how fixture details are rendered in exports
+type Locale struct {
+	Code       string              `json:"code"`
+	DateFormat string              `json:"date_format"`
+	TimeFormat string              `json:"time_format"`
+	TeamNames  []TeamNameOverride  `json:"team_names,omitempty"`
+	VenueNames []VenueNameOverride `json:"venue_names,omitempty"`
+}
+
+// TeamNameOverride replaces a team's display name for a range of rounds,
+// such as the names clubs use during Indigenous Round
+type TeamNameOverride struct {
+	TeamID    int    `json:"team_id"`
+	Name      string `json:"name"`
+	FromRound int    `json:"from_round,omitempty"` // 0 means from the first round
+	ToRound   int    `json:"to_round,omitempty"`   // 0 means to the last round
+}
+
+// VenueNameOverride replaces a venue's display name from an effective date,
+// such as a sponsor naming-rights deal that starts mid-season
+type VenueNameOverride struct {
+	VenueID       int        `json:"venue_id"`
+	Name          string     `json:"name"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// localePresets contains the built-in date and time formats by locale code
+var localePresets = map[string]Locale{
+	"en-AU": {Code: "en-AU", DateFormat: "Mon 2 Jan 2006", TimeFormat: "3:04pm"},
+	"en-NZ": {Code: "en-NZ", DateFormat: "Mon 2 Jan 2006", TimeFormat: "3:04pm"},
+	"en-GB": {Code: "en-GB", DateFormat: "Mon 2 January 2006", TimeFormat: "15:04"},
+	"en-US": {Code: "en-US", DateFormat: "Mon, Jan 2, 2006", TimeFormat: "3:04 PM"},
+	"iso":   {Code: "iso", DateFormat: "2006-01-02", TimeFormat: "15:04"},
+}
+
+// DefaultLocaleCode is used when an export request does not specify a locale
+const DefaultLocaleCode = "en-AU"
+
+// DefaultLocale returns the default export locale
+func DefaultLocale() Locale {
+	return localePresets[DefaultLocaleCode]
+}
+
+// LocaleFromCode returns the preset for a locale code
+func LocaleFromCode(code string) (Locale, error) {
+	if code == "" {
+		return DefaultLocale(), nil
+	}
+
+	locale, ok := localePresets[code]
+	if !ok {
+		return Locale{}, fmt.Errorf("unsupported locale: %s", code)
+	}
+	return locale, nil
+}
+
+// SupportedLocales returns the codes of the built-in locale presets
+func SupportedLocales() []string {
+	codes := make([]string, 0, len(localePresets))
+	for code := range localePresets {
+		codes = append(codes, code)
+	}
+
+	// Sort for stable output
+	for i := 0; i < len(codes)-1; i++ {
+		for j := i + 1; j < len(codes); j++ {
+			if codes[i] > codes[j] {
+				codes[i], codes[j] = codes[j], codes[i]
+			}
+		}
+	}
+
+	return codes
+}
+
+// Resolve fills any unset formats from the locale's preset, or the default locale
+func (l Locale) Resolve() (Locale, error) {
+	preset, err := LocaleFromCode(l.Code)
+	if err != nil {
+		return Locale{}, err
+	}
+
+	if l.Code == "" {
+		l.Code = preset.Code
+	}
+	if l.DateFormat == "" {
+		l.DateFormat = preset.DateFormat
+	}
+	if l.TimeFormat == "" {
+		l.TimeFormat = preset.TimeFormat
+	}
+
+	return l, nil
+}
+
+// FormatDate renders a match date, returning an empty string when unscheduled
+func (l Locale) FormatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(l.DateFormat)
+}
+
+// FormatTime renders a kick-off time, returning an empty string when unscheduled
+func (l Locale) FormatTime(kickoff *time.Time) string {
+	if kickoff == nil {
+		return ""
+	}
+	return kickoff.Format(l.TimeFormat)
+}
+
+// TeamName returns the display name for a team in a given round
+func (l Locale) TeamName(team *models.Team, round int) string {
+	if team == nil {
+		return ""
+	}
+
+	for _, override := range l.TeamNames {
+		if override.TeamID != team.ID {
+			continue
+		}
+		if override.FromRound > 0 && round < override.FromRound {
+			continue
+		}
+		if override.ToRound > 0 && round > override.ToRound {
+			continue
+		}
+		return override.Name
+	}
+
+	return team.Name
+}
+
+// VenueName returns the display name for a venue on a given date. Overrides
+// with effective dates need a match date to apply.
+func (l Locale) VenueName(venue *models.Venue, date *time.Time) string {
+	if venue == nil {
+		return ""
+	}
+
+	for _, override := range l.VenueNames {
+		if override.VenueID != venue.ID {
+			continue
+		}
+		if override.EffectiveFrom != nil || override.EffectiveTo != nil {
+			if date == nil {
+				continue
+			}
+			if override.EffectiveFrom != nil && date.Before(*override.EffectiveFrom) {
+				continue
+			}
+			if override.EffectiveTo != nil && date.After(*override.EffectiveTo) {
+				continue
+			}
+		}
+		return override.Name
+	}
+
+	return venue.Name
+}
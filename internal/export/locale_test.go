@@ -0,0 +1,90 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+func TestLocaleFromCode(t *testing.T) {
+	locale, err := LocaleFromCode("")
+	if err != nil {
+		t.Fatalf("LocaleFromCode() error = %v", err)
+	}
+	if locale.Code != DefaultLocaleCode {
+		t.Errorf("empty code should use default locale, got %s", locale.Code)
+	}
+
+	if _, err := LocaleFromCode("xx-XX"); err == nil {
+		t.Error("unsupported locale should return an error")
+	}
+
+	if len(SupportedLocales()) != len(localePresets) {
+		t.Error("SupportedLocales() should list every preset")
+	}
+}
+
+func TestLocaleResolve(t *testing.T) {
+	locale, err := Locale{Code: "iso", TimeFormat: "3pm"}.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if locale.DateFormat != "2006-01-02" {
+		t.Errorf("date format should come from preset, got %s", locale.DateFormat)
+	}
+	if locale.TimeFormat != "3pm" {
+		t.Errorf("explicit time format should be kept, got %s", locale.TimeFormat)
+	}
+}
+
+func TestLocaleFormatting(t *testing.T) {
+	date := time.Date(2025, 5, 16, 0, 0, 0, 0, time.UTC)
+	kickoff := time.Date(0, 1, 1, 19, 50, 0, 0, time.UTC)
+
+	au := DefaultLocale()
+	if got := au.FormatDate(&date); got != "Fri 16 May 2025" {
+		t.Errorf("FormatDate() = %s", got)
+	}
+	if got := au.FormatTime(&kickoff); got != "7:50pm" {
+		t.Errorf("FormatTime() = %s", got)
+	}
+	if got := au.FormatDate(nil); got != "" {
+		t.Errorf("unscheduled date should be empty, got %s", got)
+	}
+}
+
+func TestLocaleDisplayNames(t *testing.T) {
+	team := &models.Team{ID: 1, Name: "Brisbane Broncos"}
+	venue := &models.Venue{ID: 2, Name: "Lang Park"}
+	sponsorStart := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	locale := Locale{
+		Code: "en-AU",
+		TeamNames: []TeamNameOverride{
+			{TeamID: 1, Name: "Brisbane Broncos (Indigenous Round)", FromRound: 12, ToRound: 12},
+		},
+		VenueNames: []VenueNameOverride{
+			{VenueID: 2, Name: "Suncorp Stadium", EffectiveFrom: &sponsorStart},
+		},
+	}
+
+	if got := locale.TeamName(team, 11); got != "Brisbane Broncos" {
+		t.Errorf("TeamName() round 11 = %s", got)
+	}
+	if got := locale.TeamName(team, 12); got != "Brisbane Broncos (Indigenous Round)" {
+		t.Errorf("TeamName() round 12 = %s", got)
+	}
+
+	before := time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC)
+	if got := locale.VenueName(venue, &before); got != "Lang Park" {
+		t.Errorf("VenueName() before sponsorship = %s", got)
+	}
+	if got := locale.VenueName(venue, &after); got != "Suncorp Stadium" {
+		t.Errorf("VenueName() after sponsorship = %s", got)
+	}
+	if got := locale.VenueName(venue, nil); got != "Lang Park" {
+		t.Errorf("VenueName() without date = %s", got)
+	}
+}
@@ -0,0 +1,228 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// HTMLContentType is the media type of HTML reports
+const HTMLContentType = "text/html; charset=utf-8"
+
+// Report is a human-readable review of a draw: every team's fixture list and
+// season figures, and the constraint violations found in it
+type Report struct {
+	Title          string
+	Rounds         int
+	Score          float64
+	HardViolations int
+	SoftViolations int
+	Teams          []TeamReport
+	Violations     []ReportViolation
+	GeneratedAt    time.Time
+}
+
+// TeamReport is one team's section of a report
+type TeamReport struct {
+	TeamID            int
+	Name              string
+	Fixtures          []TeamFixture
+	HomeGames         int
+	AwayGames         int
+	PrimeTimeGames    int
+	ByeRounds         []int
+	TravelKm          float64
+	LongestAwayStreak int
+}
+
+// TeamFixture is one of a team's matches, seen from that team's side
+type TeamFixture struct {
+	Round       int
+	Date        string
+	Time        string
+	Opponent    string
+	Venue       string
+	Home        bool
+	IsPrimeTime bool
+}
+
+// ReportViolation is one constraint violation listed in a report
+type ReportViolation struct {
+	Severity    constraints.ViolationSeverity
+	Constraint  string
+	Round       int
+	Description string
+}
+
+// HomePercent returns the share of the team's games played at home, for
+// drawing its home/away bar
+func (tr TeamReport) HomePercent() float64 {
+	games := tr.HomeGames + tr.AwayGames
+	if games == 0 {
+		return 0
+	}
+	return 100 * float64(tr.HomeGames) / float64(games)
+}
+
+// BuildReport gathers a draw's report. Matches need their teams and venues
+// loaded, as returned by ListByDrawWithRelations, and the engine needs the
+// league data travel is measured with. Travel uses the engine's own travel
+// constraint when it has one so limits match the draw's configuration.
+func BuildReport(draw *models.Draw, engine *constraints.ConstraintEngine, league *constraints.LeagueData, locale Locale) *Report {
+	report := &Report{
+		Title:       fmt.Sprintf("%s %d", draw.Name, draw.SeasonYear),
+		Rounds:      draw.Rounds,
+		Score:       engine.ScoreDraw(draw),
+		Teams:       []TeamReport{},
+		Violations:  []ReportViolation{},
+		GeneratedAt: time.Now(),
+	}
+
+	travel := make(map[int]constraints.TravelAnalysis)
+	for _, analysis := range reportTravelConstraint(engine, league).GetAllTeamTravelAnalysis(draw) {
+		travel[analysis.TeamID] = analysis
+	}
+
+	index := constraints.NewDrawIndex(draw)
+	for _, teamID := range index.Teams() {
+		analysis := travel[teamID]
+		team := TeamReport{
+			TeamID:            teamID,
+			Name:              fmt.Sprintf("Team %d", teamID),
+			ByeRounds:         index.ByeRounds(teamID),
+			TravelKm:          analysis.TravelKm,
+			LongestAwayStreak: analysis.LongestAwayStreak,
+		}
+		if details, ok := league.Teams[teamID]; ok {
+			team.Name = details.Name
+		}
+
+		for _, match := range index.TeamMatches(teamID) {
+			fixture := TeamFixture{
+				Round:       match.Round,
+				Date:        locale.FormatDate(match.MatchDate),
+				Time:        locale.FormatTime(match.MatchTime),
+				Venue:       locale.VenueName(match.Venue, match.MatchDate),
+				IsPrimeTime: match.IsPrimeTime,
+			}
+			fixture.Home, _ = match.IsHomeGame(teamID)
+			if fixture.Home {
+				team.HomeGames++
+				fixture.Opponent = locale.TeamName(match.AwayTeam, match.Round)
+			} else {
+				team.AwayGames++
+				fixture.Opponent = locale.TeamName(match.HomeTeam, match.Round)
+			}
+			if match.IsPrimeTime {
+				team.PrimeTimeGames++
+			}
+			team.Fixtures = append(team.Fixtures, fixture)
+		}
+		sort.SliceStable(team.Fixtures, func(i, j int) bool {
+			return team.Fixtures[i].Round < team.Fixtures[j].Round
+		})
+
+		report.Teams = append(report.Teams, team)
+	}
+
+	for _, violation := range engine.AnalyzeDraw(draw) {
+		switch violation.Severity {
+		case constraints.SeverityHard:
+			report.HardViolations++
+		case constraints.SeveritySoft:
+			report.SoftViolations++
+		}
+		report.Violations = append(report.Violations, ReportViolation{
+			Severity:    violation.Severity,
+			Constraint:  violation.ConstraintName,
+			Round:       violation.Round,
+			Description: violation.Description,
+		})
+	}
+
+	return report
+}
+
+// reportTravelConstraint returns the engine's travel constraint, or one with
+// no away streak limit when the draw's configuration doesn't measure travel
+func reportTravelConstraint(engine *constraints.ConstraintEngine, league *constraints.LeagueData) *constraints.TravelMinimizationConstraint {
+	for _, weighted := range engine.GetSoftConstraints() {
+		if travel, ok := weighted.Constraint.(*constraints.TravelMinimizationConstraint); ok {
+			return travel
+		}
+	}
+	travel := constraints.NewTravelMinimizationConstraint(constraints.NoConsecutiveAwayLimit)
+	travel.SetLeagueData(league)
+	return travel
+}
+
+// WriteHTML writes the report as a standalone HTML page. It carries a print
+// stylesheet so clubs can save it as a PDF from their browser.
+func WriteHTML(w io.Writer, report *Report) error {
+	return reportTemplate.Execute(w, report)
+}
+
+// reportTemplate renders a report as HTML. Text is escaped by html/template.
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"km":      func(km float64) string { return fmt.Sprintf("%.0f km", km) },
+	"percent": func(p float64) string { return fmt.Sprintf("%.0f%%", p) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - Draw Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.6em; text-align: left; }
+th { background: #f2f2f2; }
+.bar { width: 12em; height: 0.9em; background: #c96; }
+.bar .home { height: 100%; background: #369; }
+.hard { color: #b00; }
+section.team { page-break-inside: avoid; }
+@media print { body { margin: 0; } section.team { page-break-before: always; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Rounds}} rounds, {{len .Teams}} teams. Overall score {{printf "%.3f" .Score}}, {{.HardViolations}} hard and {{.SoftViolations}} soft violations.
+Generated {{.GeneratedAt.Format "2 Jan 2006 15:04"}}.</p>
+
+<h2>Season summary</h2>
+<table>
+<tr><th>Team</th><th>Home</th><th>Away</th><th>Home/away</th><th>Byes</th><th>Prime time</th><th>Travel</th><th>Longest away streak</th></tr>
+{{range .Teams}}<tr>
+<td>{{.Name}}</td><td>{{.HomeGames}}</td><td>{{.AwayGames}}</td>
+<td><div class="bar" title="{{percent .HomePercent}} at home"><div class="home" style="width: {{printf "%.1f" .HomePercent}}%"></div></div></td>
+<td>{{range $i, $round := .ByeRounds}}{{if $i}}, {{end}}{{$round}}{{else}}none{{end}}</td>
+<td>{{.PrimeTimeGames}}</td><td>{{km .TravelKm}}</td><td>{{.LongestAwayStreak}}</td>
+</tr>
+{{end}}</table>
+
+<h2>Constraint violations</h2>
+{{if .Violations}}<table>
+<tr><th>Severity</th><th>Constraint</th><th>Round</th><th>Description</th></tr>
+{{range .Violations}}<tr{{if eq .Severity "hard"}} class="hard"{{end}}>
+<td>{{.Severity}}</td><td>{{.Constraint}}</td><td>{{if .Round}}{{.Round}}{{end}}</td><td>{{.Description}}</td>
+</tr>
+{{end}}</table>
+{{else}}<p>No violations.</p>
+{{end}}
+{{range .Teams}}<section class="team">
+<h2>{{.Name}}</h2>
+<table>
+<tr><th>Round</th><th>Date</th><th>Time</th><th></th><th>Opponent</th><th>Venue</th><th>Prime time</th></tr>
+{{range .Fixtures}}<tr>
+<td>{{.Round}}</td><td>{{.Date}}</td><td>{{.Time}}</td><td>{{if .Home}}v{{else}}@{{end}}</td>
+<td>{{.Opponent}}</td><td>{{.Venue}}</td><td>{{if .IsPrimeTime}}Yes{{end}}</td>
+</tr>
+{{end}}</table>
+</section>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// testReportLeague returns the league data for testExportDraw
+func testReportLeague(draw *models.Draw) *constraints.LeagueData {
+	teams := map[int]*models.Team{}
+	venues := map[int]*models.Venue{}
+	for _, match := range draw.Matches {
+		for _, team := range []*models.Team{match.HomeTeam, match.AwayTeam} {
+			if team != nil {
+				teams[team.ID] = team
+			}
+		}
+		if match.Venue != nil {
+			venues[match.Venue.ID] = match.Venue
+		}
+	}
+
+	var teamList []*models.Team
+	for _, team := range teams {
+		teamList = append(teamList, team)
+	}
+	var venueList []*models.Venue
+	for _, venue := range venues {
+		venueList = append(venueList, venue)
+	}
+	return constraints.NewLeagueData(teamList, venueList)
+}
+
+func TestBuildReport(t *testing.T) {
+	draw := testExportDraw()
+	engine := constraints.NewConstraintEngine()
+	engine.AddHardConstraint(constraints.NewByeConstraint())
+
+	report := BuildReport(draw, engine, testReportLeague(draw), DefaultLocale())
+
+	if report.Title != "NRL Premiership 2025" {
+		t.Errorf("Title = %q", report.Title)
+	}
+	if len(report.Teams) != 4 {
+		t.Fatalf("expected 4 teams, got %d", len(report.Teams))
+	}
+
+	broncos := report.Teams[0]
+	if broncos.Name != "Brisbane Broncos" || broncos.HomeGames != 1 || broncos.AwayGames != 1 {
+		t.Errorf("Broncos should play one home and one away game, got %+v", broncos)
+	}
+	if broncos.PrimeTimeGames != 1 || broncos.HomePercent() != 50 {
+		t.Errorf("Broncos should have one prime-time game and a 50%% home share, got %+v", broncos)
+	}
+	if len(broncos.Fixtures) != 2 || broncos.Fixtures[1].Opponent != "South Sydney Rabbitohs" || broncos.Fixtures[1].Home {
+		t.Errorf("Broncos' round 2 fixture should be away at the Rabbitohs, got %+v", broncos.Fixtures)
+	}
+
+	// Every team plays both rounds, so no byes and no bye violations
+	for _, team := range report.Teams {
+		if len(team.ByeRounds) != 0 {
+			t.Errorf("%s should have no byes, got %v", team.Name, team.ByeRounds)
+		}
+	}
+	if report.HardViolations != 0 {
+		t.Errorf("expected no hard violations, got %+v", report.Violations)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	draw := testExportDraw()
+	draw.Name = "NRL <Premiership>"
+	report := BuildReport(draw, constraints.NewConstraintEngine(), testReportLeague(draw), DefaultLocale())
+	report.Violations = append(report.Violations, ReportViolation{
+		Severity:    constraints.SeverityHard,
+		Constraint:  "bye_constraint",
+		Round:       2,
+		Description: "Team 4 has too many byes",
+	})
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, report); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	page := buf.String()
+
+	if !strings.HasPrefix(page, "<!DOCTYPE html>") {
+		t.Error("report should be an HTML page")
+	}
+	if !strings.Contains(page, "NRL &lt;Premiership&gt; 2025") || strings.Contains(page, "<Premiership>") {
+		t.Error("draw names should be escaped")
+	}
+	if strings.Count(page, `<section class="team">`) != 4 {
+		t.Error("report should have a section per team")
+	}
+	if !strings.Contains(page, "Thu 6 Mar 2025") || !strings.Contains(page, "Allianz Stadium, Moore Park") {
+		t.Error("fixtures should be rendered with the locale's formats")
+	}
+	if !strings.Contains(page, `class="hard"`) || !strings.Contains(page, "Team 4 has too many byes") {
+		t.Error("violations should be listed")
+	}
+}
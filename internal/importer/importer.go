@@ -0,0 +1,203 @@
+// Package importer creates teams and venues in bulk from CSV or JSON rows.
+// Every row is validated before anything is written, and the rows are
+// created in a single transaction, so an import either succeeds completely
+// or changes nothing.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+var (
+	// ErrNoRows is returned when an import has nothing to create
+	ErrNoRows = errors.New("import has no rows")
+	// ErrInvalidRows is returned with a report listing each invalid row
+	ErrInvalidRows = errors.New("import has invalid rows")
+)
+
+// RowError describes why a row can't be imported. Rows are numbered from 1,
+// not counting a CSV header.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Result records a created team or venue
+type Result struct {
+	Row  int    `json:"row"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Report summarises an import. When any row is invalid, Errors lists them
+// and nothing is created.
+type Report struct {
+	Kind     string     `json:"kind"` // "team" or "venue"
+	Imported int        `json:"imported"`
+	Results  []Result   `json:"results"`
+	Errors   []RowError `json:"errors"`
+}
+
+// newReport creates an empty report, carrying over any parse errors
+func newReport(kind string, parseErrors []RowError) *Report {
+	return &Report{
+		Kind:    kind,
+		Results: []Result{},
+		Errors:  append([]RowError{}, parseErrors...),
+	}
+}
+
+// sortErrors orders the errors by row, keeping each row's errors in the
+// order they were found
+func (r *Report) sortErrors() {
+	sort.SliceStable(r.Errors, func(i, j int) bool {
+		return r.Errors[i].Row < r.Errors[j].Row
+	})
+}
+
+// ImportVenues creates the venues, returning ErrInvalidRows along with the
+// report when any row fails validation
+func ImportVenues(ctx context.Context, repos storage.Repositories, rows []VenueRow, parseErrors []RowError) (*Report, error) {
+	if len(rows) == 0 {
+		return nil, ErrNoRows
+	}
+
+	report := newReport("venue", parseErrors)
+	venues := make([]*models.Venue, len(rows))
+	for i, row := range rows {
+		venues[i] = &models.Venue{
+			Name:      strings.TrimSpace(row.Name),
+			City:      strings.TrimSpace(row.City),
+			Capacity:  row.Capacity,
+			Latitude:  row.Latitude,
+			Longitude: row.Longitude,
+		}
+		if err := venues[i].Validate(); err != nil {
+			report.Errors = append(report.Errors, RowError{Row: i + 1, Message: err.Error()})
+		}
+	}
+	if len(report.Errors) > 0 {
+		report.sortErrors()
+		return report, ErrInvalidRows
+	}
+
+	tx, err := repos.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, venue := range venues {
+		if err := tx.Venues().Create(ctx, venue); err != nil {
+			return nil, fmt.Errorf("failed to create venue %s: %w", venue.Name, err)
+		}
+		report.Results = append(report.Results, Result{Row: i + 1, ID: venue.ID, Name: venue.Name})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	report.Imported = len(venues)
+	return report, nil
+}
+
+// ImportTeams creates the teams, returning ErrInvalidRows along with the
+// report when any row fails validation. Team names must be new, both to
+// storage and within the import, and venues must already exist.
+func ImportTeams(ctx context.Context, repos storage.Repositories, rows []TeamRow, parseErrors []RowError) (*Report, error) {
+	if len(rows) == 0 {
+		return nil, ErrNoRows
+	}
+
+	tx, err := repos.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	venues, err := tx.Venues().List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list venues: %w", err)
+	}
+	venueIDs := make(map[int]bool, len(venues))
+	venuesByName := make(map[string]int, len(venues))
+	for _, venue := range venues {
+		venueIDs[venue.ID] = true
+		venuesByName[strings.ToLower(venue.Name)] = venue.ID
+	}
+
+	existing, err := tx.Teams().List(ctx, storage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	teamNames := make(map[string]bool, len(existing)+len(rows))
+	for _, team := range existing {
+		teamNames[strings.ToLower(team.Name)] = true
+	}
+
+	report := newReport("team", parseErrors)
+	teams := make([]*models.Team, len(rows))
+	for i, row := range rows {
+		rowNumber := i + 1
+		teams[i] = &models.Team{
+			Name:      strings.TrimSpace(row.Name),
+			ShortName: strings.TrimSpace(row.ShortName),
+			City:      strings.TrimSpace(row.City),
+			VenueID:   row.VenueID,
+			Latitude:  row.Latitude,
+			Longitude: row.Longitude,
+		}
+		if err := teams[i].Validate(); err != nil {
+			report.Errors = append(report.Errors, RowError{Row: rowNumber, Message: err.Error()})
+			continue
+		}
+
+		name := strings.ToLower(teams[i].Name)
+		if teamNames[name] {
+			report.Errors = append(report.Errors, RowError{Row: rowNumber, Field: "name", Message: fmt.Sprintf("team %s already exists", teams[i].Name)})
+		}
+		teamNames[name] = true
+
+		venue := strings.TrimSpace(row.Venue)
+		switch {
+		case row.VenueID != nil && venue != "":
+			report.Errors = append(report.Errors, RowError{Row: rowNumber, Field: "venue", Message: "give either venue_id or venue, not both"})
+		case row.VenueID != nil && !venueIDs[*row.VenueID]:
+			report.Errors = append(report.Errors, RowError{Row: rowNumber, Field: "venue_id", Message: fmt.Sprintf("venue %d not found", *row.VenueID)})
+		case venue != "":
+			id, ok := venuesByName[strings.ToLower(venue)]
+			if !ok {
+				report.Errors = append(report.Errors, RowError{Row: rowNumber, Field: "venue", Message: fmt.Sprintf("venue %s not found", venue)})
+				break
+			}
+			teams[i].VenueID = &id
+		}
+	}
+	if len(report.Errors) > 0 {
+		report.sortErrors()
+		return report, ErrInvalidRows
+	}
+
+	for i, team := range teams {
+		if err := tx.Teams().Create(ctx, team); err != nil {
+			return nil, fmt.Errorf("failed to create team %s: %w", team.Name, err)
+		}
+		report.Results = append(report.Results, Result{Row: i + 1, ID: team.ID, Name: team.Name})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	report.Imported = len(teams)
+	return report, nil
+}
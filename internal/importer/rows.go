@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Import formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// VenueRow is one venue to import. CSV imports name the columns like the
+// JSON fields; only name and city are required.
+type VenueRow struct {
+	Name      string  `json:"name"`
+	City      string  `json:"city"`
+	Capacity  int     `json:"capacity"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// TeamRow is one team to import. The home venue is given either by ID or
+// by name, so a league's venues and teams can be imported from two files
+// without looking up the new venue IDs in between.
+type TeamRow struct {
+	Name      string  `json:"name"`
+	ShortName string  `json:"short_name"`
+	City      string  `json:"city"`
+	VenueID   *int    `json:"venue_id,omitempty"`
+	Venue     string  `json:"venue,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ParseVenues reads venue rows in the given format. Values that can't be
+// parsed are reported per row; an unreadable document is an error.
+func ParseVenues(r io.Reader, format string) ([]VenueRow, []RowError, error) {
+	if format == FormatJSON {
+		var rows []VenueRow
+		if err := decodeJSON(r, &rows); err != nil {
+			return nil, nil, err
+		}
+		return rows, nil, nil
+	}
+
+	records, err := readCSV(r, []string{"name", "city"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]VenueRow, len(records))
+	var rowErrors []RowError
+	for i, record := range records {
+		p := fieldParser{row: i + 1, record: record}
+		rows[i] = VenueRow{
+			Name:      record["name"],
+			City:      record["city"],
+			Capacity:  p.int("capacity"),
+			Latitude:  p.float("latitude"),
+			Longitude: p.float("longitude"),
+		}
+		rowErrors = append(rowErrors, p.errors...)
+	}
+	return rows, rowErrors, nil
+}
+
+// ParseTeams reads team rows in the given format. Values that can't be
+// parsed are reported per row; an unreadable document is an error.
+func ParseTeams(r io.Reader, format string) ([]TeamRow, []RowError, error) {
+	if format == FormatJSON {
+		var rows []TeamRow
+		if err := decodeJSON(r, &rows); err != nil {
+			return nil, nil, err
+		}
+		return rows, nil, nil
+	}
+
+	records, err := readCSV(r, []string{"name", "short_name", "city"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]TeamRow, len(records))
+	var rowErrors []RowError
+	for i, record := range records {
+		p := fieldParser{row: i + 1, record: record}
+		rows[i] = TeamRow{
+			Name:      record["name"],
+			ShortName: record["short_name"],
+			City:      record["city"],
+			Venue:     record["venue"],
+			Latitude:  p.float("latitude"),
+			Longitude: p.float("longitude"),
+		}
+		if record["venue_id"] != "" {
+			venueID := p.int("venue_id")
+			rows[i].VenueID = &venueID
+		}
+		rowErrors = append(rowErrors, p.errors...)
+	}
+	return rows, rowErrors, nil
+}
+
+// decodeJSON reads a JSON array of rows
+func decodeJSON(r io.Reader, rows interface{}) error {
+	if err := json.NewDecoder(r).Decode(rows); err != nil {
+		return fmt.Errorf("invalid JSON: expected an array of rows: %w", err)
+	}
+	return nil
+}
+
+// readCSV reads a CSV document with a header row into one map per row,
+// keyed by the lower-cased column name. Blank lines are skipped.
+func readCSV(r io.Reader, required []string) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV has no header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV is missing the %s column", name)
+		}
+	}
+
+	var records []map[string]string
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		record := make(map[string]string, len(columns))
+		for name, i := range columns {
+			record[name] = strings.TrimSpace(fields[i])
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// fieldParser converts a CSV row's optional numeric fields, collecting an
+// error for each value that isn't a number
+type fieldParser struct {
+	row    int
+	record map[string]string
+	errors []RowError
+}
+
+func (p *fieldParser) int(field string) int {
+	value := p.record[field]
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		p.errors = append(p.errors, RowError{Row: p.row, Field: field, Message: fmt.Sprintf("%q is not a whole number", value)})
+	}
+	return n
+}
+
+func (p *fieldParser) float(field string) float64 {
+	value := p.record[field]
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.errors = append(p.errors, RowError{Row: p.row, Field: field, Message: fmt.Sprintf("%q is not a number", value)})
+	}
+	return f
+}
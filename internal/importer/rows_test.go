@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTeamsCSV(t *testing.T) {
+	input := "Name, Short_Name, City, Venue, Latitude, Longitude, Venue_ID\n" +
+		"Penrith Panthers,PEN,Penrith,BlueBet Stadium,-33.75,150.69,\n" +
+		"\n" +
+		"Parramatta Eels,PAR,Parramatta,,west,150.99,abc\n"
+
+	rows, rowErrors, err := ParseTeams(strings.NewReader(input), FormatCSV)
+	if err != nil {
+		t.Fatalf("ParseTeams() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("ParseTeams() returned %d rows, want 2", len(rows))
+	}
+
+	if rows[0].Name != "Penrith Panthers" || rows[0].Venue != "BlueBet Stadium" || rows[0].Latitude != -33.75 || rows[0].VenueID != nil {
+		t.Errorf("First row = %+v", rows[0])
+	}
+
+	if len(rowErrors) != 2 {
+		t.Fatalf("ParseTeams() returned %d row errors, want 2: %+v", len(rowErrors), rowErrors)
+	}
+	for _, rowError := range rowErrors {
+		if rowError.Row != 2 {
+			t.Errorf("Row error %+v should be on row 2", rowError)
+		}
+	}
+	if rowErrors[0].Field != "latitude" || rowErrors[1].Field != "venue_id" {
+		t.Errorf("Row errors = %+v, want latitude then venue_id", rowErrors)
+	}
+}
+
+func TestParseVenuesErrors(t *testing.T) {
+	if _, _, err := ParseVenues(strings.NewReader(""), FormatCSV); err == nil {
+		t.Error("ParseVenues() should reject a CSV without a header")
+	}
+	if _, _, err := ParseVenues(strings.NewReader("name,capacity\nAAMI Park,30050\n"), FormatCSV); err == nil {
+		t.Error("ParseVenues() should reject a CSV missing the city column")
+	}
+	if _, _, err := ParseVenues(strings.NewReader(`{"name": "AAMI Park"}`), FormatJSON); err == nil {
+		t.Error("ParseVenues() should reject JSON that isn't an array")
+	}
+
+	rows, rowErrors, err := ParseVenues(strings.NewReader(`[{"name": "AAMI Park", "city": "Melbourne", "capacity": 30050}]`), FormatJSON)
+	if err != nil {
+		t.Fatalf("ParseVenues() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Capacity != 30050 || len(rowErrors) != 0 {
+		t.Errorf("ParseVenues() = %+v, %+v", rows, rowErrors)
+	}
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 )
@@ -11,26 +12,86 @@ import (
 var (
 	ErrNotFound = errors.New("not found")
 	ErrConflict = errors.New("conflict")
+	// ErrVersionConflict is returned when updating a draw that has been
+	// updated since it was read
+	ErrVersionConflict = errors.New("draw was changed by another update")
 )
 
+// ListOptions pages, filters and sorts a List query. The zero value lists
+// every row in the repository's default order.
+type ListOptions struct {
+	// Page is 1-based and only applies when PerPage is set
+	Page    int
+	PerPage int
+	// Search matches case-insensitively anywhere in the names and cities of
+	// what's listed
+	Search string
+	// SortBy is id, name, created or updated; matches also sort by round and
+	// date. SortDir is asc or desc, defaulting to asc.
+	SortBy  string
+	SortDir string
+	// CompetitionID limits teams and draws to one competition's. 0 lists
+	// them all, including those without a competition.
+	CompetitionID int
+}
+
+// Offset returns how many rows come before the requested page
+func (o ListOptions) Offset() int {
+	if o.Page <= 1 || o.PerPage <= 0 {
+		return 0
+	}
+	return (o.Page - 1) * o.PerPage
+}
+
+// DrawStatusPublished filters draws on having been published rather than on
+// their status column
+const DrawStatusPublished = "published"
+
+// DrawFilter narrows a draw listing to one season and status on top of the
+// list options
+type DrawFilter struct {
+	ListOptions
+	// SeasonYear is 0 for every season
+	SeasonYear int
+	// Status is a draw status, DrawStatusPublished, or empty for any
+	Status string
+}
+
 // VenueRepository defines methods for venue storage
 type VenueRepository interface {
 	Create(ctx context.Context, venue *models.Venue) error
 	Get(ctx context.Context, id int) (*models.Venue, error)
-	List(ctx context.Context) ([]*models.Venue, error)
+	ListByIDs(ctx context.Context, ids []int) ([]*models.Venue, error)
+	List(ctx context.Context, opts ListOptions) ([]*models.Venue, error)
+	Count(ctx context.Context, opts ListOptions) (int, error)
 	Update(ctx context.Context, venue *models.Venue) error
 	Delete(ctx context.Context, id int) error
 }
 
+// CompetitionRepository defines methods for competition storage
+type CompetitionRepository interface {
+	Create(ctx context.Context, competition *models.Competition) error
+	Get(ctx context.Context, id int) (*models.Competition, error)
+	List(ctx context.Context) ([]*models.Competition, error)
+	Update(ctx context.Context, competition *models.Competition) error
+	Delete(ctx context.Context, id int) error
+}
+
 // TeamRepository defines methods for team storage
 type TeamRepository interface {
 	Create(ctx context.Context, team *models.Team) error
 	Get(ctx context.Context, id int) (*models.Team, error)
 	GetWithVenue(ctx context.Context, id int) (*models.Team, error)
-	List(ctx context.Context) ([]*models.Team, error)
+	List(ctx context.Context, opts ListOptions) ([]*models.Team, error)
+	Count(ctx context.Context, opts ListOptions) (int, error)
 	ListWithVenues(ctx context.Context) ([]*models.Team, error)
+	ListByIDs(ctx context.Context, ids []int) ([]*models.Team, error)
 	Update(ctx context.Context, team *models.Team) error
 	Delete(ctx context.Context, id int) error
+	SetHomeVenues(ctx context.Context, teamID int, venues []models.HomeVenue) error
+	AddUnavailability(ctx context.Context, unavailability *models.TeamUnavailability) error
+	ListUnavailability(ctx context.Context, teamID int) ([]models.TeamUnavailability, error)
+	DeleteUnavailability(ctx context.Context, teamID, id int) error
 }
 
 // DrawRepository defines methods for draw storage
@@ -38,33 +99,157 @@ type DrawRepository interface {
 	Create(ctx context.Context, draw *models.Draw) error
 	Get(ctx context.Context, id int) (*models.Draw, error)
 	GetWithMatches(ctx context.Context, id int) (*models.Draw, error)
-	List(ctx context.Context) ([]*models.Draw, error)
+	List(ctx context.Context, opts ListOptions) ([]*models.Draw, error)
+	Count(ctx context.Context, opts ListOptions) (int, error)
+	ListFiltered(ctx context.Context, filter DrawFilter) ([]*models.Draw, int, error)
 	Update(ctx context.Context, draw *models.Draw) error
 	Delete(ctx context.Context, id int) error
 }
 
-// MatchRepository defines methods for match storage
+// MatchRepository defines methods for match storage. Changing or removing
+// a draw's stored matches, other than locking them, moves the draw on to its
+// next version.
 type MatchRepository interface {
 	Create(ctx context.Context, match *models.Match) error
 	CreateBatch(ctx context.Context, matches []*models.Match) error
 	Get(ctx context.Context, id int) (*models.Match, error)
 	GetWithRelations(ctx context.Context, id int) (*models.Match, error)
+	List(ctx context.Context, opts ListOptions) ([]*models.Match, error)
+	Count(ctx context.Context, opts ListOptions) (int, error)
 	ListByDraw(ctx context.Context, drawID int) ([]*models.Match, error)
 	ListByDrawWithRelations(ctx context.Context, drawID int) ([]*models.Match, error)
 	ListByRound(ctx context.Context, drawID, round int) ([]*models.Match, error)
 	ListByTeam(ctx context.Context, drawID, teamID int) ([]*models.Match, error)
+	ListByes(ctx context.Context, drawID int) ([]*models.Match, error)
+	SyncByes(ctx context.Context, drawID int) error
 	Update(ctx context.Context, match *models.Match) error
 	UpdateBatch(ctx context.Context, matches []*models.Match) error
+	ReplaceForDraw(ctx context.Context, drawID int, matches []*models.Match) error
+	SetLocked(ctx context.Context, id int, locked bool) error
 	Delete(ctx context.Context, id int) error
 	DeleteByDraw(ctx context.Context, drawID int) error
 }
 
+// ApprovalRepository defines methods for draw approval storage
+type ApprovalRepository interface {
+	Create(ctx context.Context, approval *models.Approval) error
+	Get(ctx context.Context, id int) (*models.Approval, error)
+	ListByDraw(ctx context.Context, drawID int) ([]*models.Approval, error)
+	Update(ctx context.Context, approval *models.Approval) error
+}
+
+// ShareLinkRepository defines methods for draw share link storage
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *models.ShareLink) error
+	GetByToken(ctx context.Context, token string) (*models.ShareLink, error)
+	ListByDraw(ctx context.Context, drawID int) ([]*models.ShareLink, error)
+	Revoke(ctx context.Context, drawID, id int, revokedAt time.Time) error
+}
+
+// JobArchiveRepository defines methods for archived optimization job storage.
+// List methods return archives without their payloads.
+type JobArchiveRepository interface {
+	Create(ctx context.Context, archive *models.JobArchive) error
+	GetByJobID(ctx context.Context, jobID string) (*models.JobArchive, error)
+	List(ctx context.Context) ([]*models.JobArchive, error)
+	ListByDraw(ctx context.Context, drawID int) ([]*models.JobArchive, error)
+	SetBest(ctx context.Context, drawID int, jobID string) error
+	DiscardPayloads(ctx context.Context, completedBefore, discardedAt time.Time) (int, error)
+}
+
+// OptimizationJobRepository defines methods for persisted optimization job storage
+type OptimizationJobRepository interface {
+	Save(ctx context.Context, record *models.OptimizationJobRecord) error
+	GetByJobID(ctx context.Context, jobID string) (*models.OptimizationJobRecord, error)
+	List(ctx context.Context) ([]*models.OptimizationJobRecord, error)
+	Delete(ctx context.Context, jobID string) error
+}
+
+// OptimizationCheckpointRepository defines methods for optimization checkpoint storage
+type OptimizationCheckpointRepository interface {
+	Save(ctx context.Context, record *models.OptimizationCheckpointRecord) error
+	GetByJobID(ctx context.Context, jobID string) (*models.OptimizationCheckpointRecord, error)
+	Delete(ctx context.Context, jobID string) error
+}
+
+// SolutionRepository defines methods for solution library storage. List
+// returns solutions without their matches.
+type SolutionRepository interface {
+	Save(ctx context.Context, solution *models.Solution) error
+	GetByKey(ctx context.Context, key string) (*models.Solution, error)
+	List(ctx context.Context) ([]*models.Solution, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// TimeslotRepository defines methods for timeslot catalogue storage
+type TimeslotRepository interface {
+	Create(ctx context.Context, timeslot *models.Timeslot) error
+	Get(ctx context.Context, id int) (*models.Timeslot, error)
+	List(ctx context.Context) ([]*models.Timeslot, error)
+	Update(ctx context.Context, timeslot *models.Timeslot) error
+	Delete(ctx context.Context, id int) error
+}
+
+// SeasonCalendarRepository defines methods for season calendar storage.
+// GetForSeason returns the competition's calendar for a season, falling back
+// to the shared calendar when the competition has none.
+type SeasonCalendarRepository interface {
+	Create(ctx context.Context, calendar *models.SeasonCalendar) error
+	Get(ctx context.Context, id int) (*models.SeasonCalendar, error)
+	GetForSeason(ctx context.Context, seasonYear int, competitionID *int) (*models.SeasonCalendar, error)
+	List(ctx context.Context) ([]*models.SeasonCalendar, error)
+	Update(ctx context.Context, calendar *models.SeasonCalendar) error
+	Delete(ctx context.Context, id int) error
+}
+
+// ConstraintTemplateRepository defines methods for constraint template
+// storage. Create stores the template as version 1, and Update stores a new
+// version whenever the configuration changes.
+type ConstraintTemplateRepository interface {
+	Create(ctx context.Context, template *models.ConstraintTemplate) error
+	Get(ctx context.Context, id int) (*models.ConstraintTemplate, error)
+	List(ctx context.Context) ([]*models.ConstraintTemplate, error)
+	Update(ctx context.Context, template *models.ConstraintTemplate) error
+	Delete(ctx context.Context, id int) error
+	ListVersions(ctx context.Context, templateID int) ([]*models.ConstraintTemplateVersion, error)
+	GetVersion(ctx context.Context, templateID, version int) (*models.ConstraintTemplateVersion, error)
+}
+
+// LadderRepository defines methods for season ladder storage
+type LadderRepository interface {
+	Create(ctx context.Context, entry *models.LadderEntry) error
+	ListBySeason(ctx context.Context, season int) ([]*models.LadderEntry, error)
+	DeleteSeason(ctx context.Context, season int) error
+}
+
+// WebhookRepository defines methods for webhook storage
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	Get(ctx context.Context, id int) (*models.Webhook, error)
+	List(ctx context.Context) ([]*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	RecordDelivery(ctx context.Context, id int, deliveredAt time.Time, status int, deliveryErr string) error
+	Delete(ctx context.Context, id int) error
+}
+
 // Repositories aggregates all repository interfaces
 type Repositories interface {
+	Competitions() CompetitionRepository
 	Venues() VenueRepository
 	Teams() TeamRepository
 	Draws() DrawRepository
 	Matches() MatchRepository
+	Approvals() ApprovalRepository
+	ShareLinks() ShareLinkRepository
+	JobArchives() JobArchiveRepository
+	OptimizationJobs() OptimizationJobRepository
+	OptimizationCheckpoints() OptimizationCheckpointRepository
+	Solutions() SolutionRepository
+	Ladders() LadderRepository
+	Timeslots() TimeslotRepository
+	SeasonCalendars() SeasonCalendarRepository
+	ConstraintTemplates() ConstraintTemplateRepository
+	Webhooks() WebhookRepository
 	
 	// Transaction support
 	BeginTx(ctx context.Context) (Repositories, error)
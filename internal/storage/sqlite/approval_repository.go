@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// ApprovalRepository implements storage.ApprovalRepository using SQLite
+type ApprovalRepository struct {
+	db DBExecutor
+}
+
+// NewApprovalRepository creates a new approval repository
+func NewApprovalRepository(db DBExecutor) *ApprovalRepository {
+	return &ApprovalRepository{db: db}
+}
+
+// Create inserts a new approval
+func (r *ApprovalRepository) Create(ctx context.Context, approval *models.Approval) error {
+	query := `
+		INSERT INTO draw_approvals (draw_id, role, status, draw_version, requested_by,
+			approver, comment, decided_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		approval.DrawID, approval.Role, approval.Status, approval.DrawVersion,
+		approval.RequestedBy, approval.Approver, approval.Comment, approval.DecidedAt)
+	if err != nil {
+		return fmt.Errorf("creating approval: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	approval.ID = int(id)
+	return nil
+}
+
+// Get retrieves an approval by ID
+func (r *ApprovalRepository) Get(ctx context.Context, id int) (*models.Approval, error) {
+	query := `
+		SELECT id, draw_id, role, status, draw_version, requested_by, approver,
+			comment, decided_at, created_at, updated_at
+		FROM draw_approvals
+		WHERE id = ?
+	`
+
+	approval, err := scanApproval(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("approval %d: %w", id, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting approval: %w", err)
+	}
+
+	return approval, nil
+}
+
+// ListByDraw retrieves all approvals for a draw, oldest first
+func (r *ApprovalRepository) ListByDraw(ctx context.Context, drawID int) ([]*models.Approval, error) {
+	query := `
+		SELECT id, draw_id, role, status, draw_version, requested_by, approver,
+			comment, decided_at, created_at, updated_at
+		FROM draw_approvals
+		WHERE draw_id = ?
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, drawID)
+	if err != nil {
+		return nil, fmt.Errorf("listing approvals: %w", err)
+	}
+	defer rows.Close()
+
+	var approvals []*models.Approval
+	for rows.Next() {
+		approval, err := scanApproval(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning approval: %w", err)
+		}
+		approvals = append(approvals, approval)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating approvals: %w", err)
+	}
+
+	return approvals, nil
+}
+
+// Update modifies an existing approval
+func (r *ApprovalRepository) Update(ctx context.Context, approval *models.Approval) error {
+	query := `
+		UPDATE draw_approvals
+		SET status = ?, approver = ?, comment = ?, decided_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		approval.Status, approval.Approver, approval.Comment, approval.DecidedAt, approval.ID)
+	if err != nil {
+		return fmt.Errorf("updating approval: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("approval %d: %w", approval.ID, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanApproval(row rowScanner) (*models.Approval, error) {
+	approval := &models.Approval{}
+	var requestedBy, approver, comment sql.NullString
+	var decidedAt sql.NullTime
+
+	err := row.Scan(
+		&approval.ID, &approval.DrawID, &approval.Role, &approval.Status,
+		&approval.DrawVersion, &requestedBy, &approver, &comment,
+		&decidedAt, &approval.CreatedAt, &approval.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	approval.RequestedBy = requestedBy.String
+	approval.Approver = approver.String
+	approval.Comment = comment.String
+	if decidedAt.Valid {
+		approval.DecidedAt = &decidedAt.Time
+	}
+
+	return approval, nil
+}
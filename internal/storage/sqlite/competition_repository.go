@@ -0,0 +1,186 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// CompetitionRepository implements storage.CompetitionRepository using SQLite
+type CompetitionRepository struct {
+	db DBExecutor
+	// cache holds teams, which deleting a competition changes
+	cache *relationCache
+}
+
+// NewCompetitionRepository creates a new competition repository
+func NewCompetitionRepository(db DBExecutor) *CompetitionRepository {
+	return &CompetitionRepository{db: db}
+}
+
+// Create inserts a new competition. Names and codes must be unique.
+func (r *CompetitionRepository) Create(ctx context.Context, competition *models.Competition) error {
+	if err := competition.Validate(); err != nil {
+		return fmt.Errorf("validating competition: %w", err)
+	}
+
+	query := `
+		INSERT INTO competitions (name, code, constraint_config)
+		VALUES (?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		competition.Name, competition.Code, competition.ConstraintConfig)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("creating competition: %w", storage.ErrConflict)
+		}
+		return fmt.Errorf("creating competition: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	competition.ID = int(id)
+	competition.CreatedAt = time.Now()
+	competition.UpdatedAt = competition.CreatedAt
+	return nil
+}
+
+// Get retrieves a competition by ID
+func (r *CompetitionRepository) Get(ctx context.Context, id int) (*models.Competition, error) {
+	query := `
+		SELECT id, name, code, constraint_config, created_at, updated_at
+		FROM competitions
+		WHERE id = ?
+	`
+
+	competition := &models.Competition{}
+	var constraintConfig []byte
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&competition.ID, &competition.Name, &competition.Code, &constraintConfig,
+		&competition.CreatedAt, &competition.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("competition %d: %w", id, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting competition: %w", err)
+	}
+	competition.ConstraintConfig = constraintConfigFromColumn(constraintConfig)
+
+	return competition, nil
+}
+
+// List retrieves every competition in ID order, so the seeded NRL comes first
+func (r *CompetitionRepository) List(ctx context.Context) ([]*models.Competition, error) {
+	query := `
+		SELECT id, name, code, constraint_config, created_at, updated_at
+		FROM competitions
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("listing competitions: %w", err)
+	}
+	defer rows.Close()
+
+	var competitions []*models.Competition
+	for rows.Next() {
+		competition := &models.Competition{}
+		var constraintConfig []byte
+		err := rows.Scan(
+			&competition.ID, &competition.Name, &competition.Code, &constraintConfig,
+			&competition.CreatedAt, &competition.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning competition: %w", err)
+		}
+		competition.ConstraintConfig = constraintConfigFromColumn(constraintConfig)
+		competitions = append(competitions, competition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating competitions: %w", err)
+	}
+
+	return competitions, nil
+}
+
+// Update modifies an existing competition
+func (r *CompetitionRepository) Update(ctx context.Context, competition *models.Competition) error {
+	if err := competition.Validate(); err != nil {
+		return fmt.Errorf("validating competition: %w", err)
+	}
+
+	query := `
+		UPDATE competitions
+		SET name = ?, code = ?, constraint_config = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		competition.Name, competition.Code, competition.ConstraintConfig, competition.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("updating competition: %w", storage.ErrConflict)
+		}
+		return fmt.Errorf("updating competition: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("competition %d: %w", competition.ID, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Delete removes a competition and its own timeslots. Its teams and draws
+// are kept without one.
+func (r *CompetitionRepository) Delete(ctx context.Context, id int) error {
+	defer r.cache.invalidate()
+
+	// Not every pooled connection has foreign keys on to apply ON DELETE
+	for _, table := range []string{"teams", "draws"} {
+		if _, err := r.db.ExecContext(ctx, "UPDATE "+table+" SET competition_id = NULL WHERE competition_id = ?", id); err != nil {
+			return fmt.Errorf("clearing competition from %s: %w", table, err)
+		}
+	}
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM timeslots WHERE competition_id = ?", id); err != nil {
+		return fmt.Errorf("deleting competition timeslots: %w", err)
+	}
+
+	query := `DELETE FROM competitions WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("deleting competition: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("competition %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// isUniqueViolation reports whether an insert or update broke a UNIQUE constraint
+func isUniqueViolation(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
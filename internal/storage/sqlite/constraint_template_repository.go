@@ -0,0 +1,298 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// ConstraintTemplateRepository implements storage.ConstraintTemplateRepository using SQLite
+type ConstraintTemplateRepository struct {
+	db    DBExecutor
+	sqlDB *sql.DB // Keep reference for transaction operations
+}
+
+// NewConstraintTemplateRepository creates a new constraint template repository
+func NewConstraintTemplateRepository(db DBExecutor) *ConstraintTemplateRepository {
+	var sqlDB *sql.DB
+	if sdb, ok := db.(*sql.DB); ok {
+		sqlDB = sdb
+	}
+	return &ConstraintTemplateRepository{
+		db:    db,
+		sqlDB: sqlDB,
+	}
+}
+
+// Create inserts a new template as version 1. Names must be unique.
+func (r *ConstraintTemplateRepository) Create(ctx context.Context, template *models.ConstraintTemplate) error {
+	if err := template.Validate(); err != nil {
+		return fmt.Errorf("validating constraint template: %w", err)
+	}
+
+	return r.inTx(ctx, func(repo *ConstraintTemplateRepository) error {
+		query := `
+			INSERT INTO constraint_templates (name, description, version, config)
+			VALUES (?, ?, 1, ?)
+		`
+
+		result, err := repo.db.ExecContext(ctx, query, template.Name, template.Description, string(template.Config))
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("creating constraint template: %w", storage.ErrConflict)
+			}
+			return fmt.Errorf("creating constraint template: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("getting last insert id: %w", err)
+		}
+
+		template.ID = int(id)
+		template.Version = 1
+		if err := repo.createVersion(ctx, template); err != nil {
+			return err
+		}
+
+		template.CreatedAt = time.Now()
+		template.UpdatedAt = template.CreatedAt
+		return nil
+	})
+}
+
+// Get retrieves a template by ID at its latest version
+func (r *ConstraintTemplateRepository) Get(ctx context.Context, id int) (*models.ConstraintTemplate, error) {
+	query := `
+		SELECT id, name, description, version, config, created_at, updated_at
+		FROM constraint_templates
+		WHERE id = ?
+	`
+
+	template, err := scanConstraintTemplate(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("constraint template %d: %w", id, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting constraint template: %w", err)
+	}
+
+	return template, nil
+}
+
+// List retrieves every template in name order
+func (r *ConstraintTemplateRepository) List(ctx context.Context) ([]*models.ConstraintTemplate, error) {
+	query := `
+		SELECT id, name, description, version, config, created_at, updated_at
+		FROM constraint_templates
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("listing constraint templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*models.ConstraintTemplate
+	for rows.Next() {
+		template, err := scanConstraintTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning constraint template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating constraint templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// Update modifies an existing template. A changed configuration is stored
+// as the next version and the template's Version set to it; renaming or
+// redescribing the template keeps its version.
+func (r *ConstraintTemplateRepository) Update(ctx context.Context, template *models.ConstraintTemplate) error {
+	if err := template.Validate(); err != nil {
+		return fmt.Errorf("validating constraint template: %w", err)
+	}
+
+	return r.inTx(ctx, func(repo *ConstraintTemplateRepository) error {
+		var version int
+		var config []byte
+		err := repo.db.QueryRowContext(ctx, `SELECT version, config FROM constraint_templates WHERE id = ?`, template.ID).
+			Scan(&version, &config)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("constraint template %d: %w", template.ID, storage.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("getting constraint template: %w", err)
+		}
+
+		changed := !bytes.Equal(config, template.Config)
+		if changed {
+			version++
+		}
+
+		query := `
+			UPDATE constraint_templates
+			SET name = ?, description = ?, version = ?, config = ?
+			WHERE id = ?
+		`
+
+		_, err = repo.db.ExecContext(ctx, query, template.Name, template.Description, version, string(template.Config), template.ID)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("updating constraint template: %w", storage.ErrConflict)
+			}
+			return fmt.Errorf("updating constraint template: %w", err)
+		}
+
+		template.Version = version
+		if changed {
+			return repo.createVersion(ctx, template)
+		}
+		return nil
+	})
+}
+
+// Delete removes a template and its versions. Draws created from it keep
+// their configuration.
+func (r *ConstraintTemplateRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM constraint_templates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting constraint template: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("constraint template %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ListVersions retrieves every version of a template, latest first
+func (r *ConstraintTemplateRepository) ListVersions(ctx context.Context, templateID int) ([]*models.ConstraintTemplateVersion, error) {
+	query := `
+		SELECT template_id, version, config, created_at
+		FROM constraint_template_versions
+		WHERE template_id = ?
+		ORDER BY version DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("listing constraint template versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []*models.ConstraintTemplateVersion
+	for rows.Next() {
+		version, err := scanConstraintTemplateVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning constraint template version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating constraint template versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetVersion retrieves one version of a template
+func (r *ConstraintTemplateRepository) GetVersion(ctx context.Context, templateID, version int) (*models.ConstraintTemplateVersion, error) {
+	query := `
+		SELECT template_id, version, config, created_at
+		FROM constraint_template_versions
+		WHERE template_id = ? AND version = ?
+	`
+
+	templateVersion, err := scanConstraintTemplateVersion(r.db.QueryRowContext(ctx, query, templateID, version))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("constraint template %d version %d: %w", templateID, version, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting constraint template version: %w", err)
+	}
+
+	return templateVersion, nil
+}
+
+// createVersion records the template's configuration at its current version
+func (r *ConstraintTemplateRepository) createVersion(ctx context.Context, template *models.ConstraintTemplate) error {
+	query := `
+		INSERT INTO constraint_template_versions (template_id, version, config)
+		VALUES (?, ?, ?)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, template.ID, template.Version, string(template.Config)); err != nil {
+		return fmt.Errorf("creating constraint template version: %w", err)
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction of its own, or in the caller's when the
+// repository already belongs to one
+func (r *ConstraintTemplateRepository) inTx(ctx context.Context, fn func(repo *ConstraintTemplateRepository) error) error {
+	// Already in a transaction, the caller commits
+	if r.sqlDB == nil {
+		return fn(r)
+	}
+
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(NewTxConstraintTemplateRepository(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// scanConstraintTemplate reads a constraint template from a row
+func scanConstraintTemplate(row rowScanner) (*models.ConstraintTemplate, error) {
+	template := &models.ConstraintTemplate{}
+	var description sql.NullString
+	var config []byte
+	err := row.Scan(
+		&template.ID, &template.Name, &description, &template.Version, &config,
+		&template.CreatedAt, &template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	template.Description = description.String
+	template.Config = config
+	return template, nil
+}
+
+// scanConstraintTemplateVersion reads a constraint template version from a row
+func scanConstraintTemplateVersion(row rowScanner) (*models.ConstraintTemplateVersion, error) {
+	version := &models.ConstraintTemplateVersion{}
+	var config []byte
+	if err := row.Scan(&version.TemplateID, &version.Version, &config, &version.CreatedAt); err != nil {
+		return nil, err
+	}
+	version.Config = config
+	return version, nil
+}
@@ -2,31 +2,89 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// SchemaVersionTable records which migration the database schema is at
+const SchemaVersionTable = "schema_version"
+
+// Connection defaults
+const (
+	// DefaultBusyTimeout is how long a connection waits for another to
+	// finish writing before failing with "database is locked"
+	DefaultBusyTimeout = 5 * time.Second
+	// DefaultMaxOpenConns lets reads run alongside a write under WAL
+	DefaultMaxOpenConns = 8
+	// DefaultMaxIdleConns keeps the whole pool open between requests
+	DefaultMaxIdleConns = 8
+	// DefaultConnMaxIdleTime closes connections idle for this long
+	DefaultConnMaxIdleTime = 5 * time.Minute
+)
+
+// Options tunes how a database is opened
+type Options struct {
+	BusyTimeout     time.Duration
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxIdleTime time.Duration
+}
+
+// DefaultOptions returns the default connection settings
+func DefaultOptions() Options {
+	return Options{
+		BusyTimeout:     DefaultBusyTimeout,
+		MaxOpenConns:    DefaultMaxOpenConns,
+		MaxIdleConns:    DefaultMaxIdleConns,
+		ConnMaxIdleTime: DefaultConnMaxIdleTime,
+	}
+}
+
 // DB represents a SQLite database connection
 type DB struct {
 	conn *sql.DB
 	path string
 }
 
-// New creates a new SQLite database connection
+// New creates a new SQLite database connection with the default options
 func New(path string) (*DB, error) {
-	conn, err := sql.Open("sqlite3", path)
+	return NewWithOptions(path, DefaultOptions())
+}
+
+// NewWithOptions creates a new SQLite database connection. Every pooled
+// connection is opened in WAL mode with a busy timeout, foreign keys on and
+// synchronous NORMAL, so readers don't block the writer and writers queue
+// for each other instead of failing.
+func NewWithOptions(path string, opts Options) (*DB, error) {
+	conn, err := sql.Open("sqlite3", dataSourceName(path, opts))
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
 
-	// Enable foreign keys
-	if _, err := conn.Exec("PRAGMA foreign_keys = ON"); err != nil {
+	// Each connection to an in-memory database gets its own empty database
+	if isInMemory(path) {
+		conn.SetMaxOpenConns(1)
+	} else {
+		conn.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	conn.SetMaxIdleConns(opts.MaxIdleConns)
+	conn.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+
+	// Connect now so a bad path or pragma fails here rather than on first use
+	if err := conn.Ping(); err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("enabling foreign keys: %w", err)
+		return nil, fmt.Errorf("connecting to database: %w", err)
 	}
 
 	return &DB{
@@ -35,6 +93,33 @@ func New(path string) (*DB, error) {
 	}, nil
 }
 
+// dataSourceName adds the connection pragmas to the path. The driver applies
+// them to every connection it opens, where a PRAGMA statement would only
+// reach one connection in the pool. Transactions take the write lock as they
+// begin, so one that reads before writing waits its turn instead of failing
+// when another writer got there first.
+func dataSourceName(path string, opts Options) string {
+	params := url.Values{}
+	params.Set("_busy_timeout", strconv.FormatInt(opts.BusyTimeout.Milliseconds(), 10))
+	params.Set("_foreign_keys", "on")
+	params.Set("_synchronous", "NORMAL")
+	params.Set("_txlock", "immediate")
+	if !isInMemory(path) {
+		params.Set("_journal_mode", "WAL")
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + params.Encode()
+}
+
+// isInMemory reports whether a path names an in-memory database
+func isInMemory(path string) bool {
+	return path == ":memory:" || strings.HasPrefix(path, "file::memory:") || strings.Contains(path, "mode=memory")
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	return db.conn.Close()
@@ -47,18 +132,38 @@ func (db *DB) Conn() *sql.DB {
 
 // Migrate runs database migrations
 func (db *DB) Migrate(migrationsPath string) error {
-	driver, err := sqlite3.WithInstance(db.conn, &sqlite3.Config{})
+	m, err := db.fileMigrator(migrationsPath)
 	if err != nil {
-		return fmt.Errorf("creating migration driver: %w", err)
+		return err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migrationsPath,
-		"sqlite3",
-		driver,
-	)
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateDown rolls back the last migration
+func (db *DB) MigrateDown(migrationsPath string) error {
+	m, err := db.fileMigrator(migrationsPath)
 	if err != nil {
-		return fmt.Errorf("creating migrator: %w", err)
+		return err
+	}
+
+	if err := m.Down(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("rolling back migration: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateFS applies every pending migration read from fsys, such as the
+// embedded migrations.FS
+func (db *DB) MigrateFS(fsys fs.FS) error {
+	m, err := db.fsMigrator(fsys)
+	if err != nil {
+		return err
 	}
 
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
@@ -68,11 +173,69 @@ func (db *DB) Migrate(migrationsPath string) error {
 	return nil
 }
 
-// MigrateDown rolls back the last migration
-func (db *DB) MigrateDown(migrationsPath string) error {
-	driver, err := sqlite3.WithInstance(db.conn, &sqlite3.Config{})
+// MigrateDownFS rolls back every migration read from fsys
+func (db *DB) MigrateDownFS(fsys fs.FS) error {
+	m, err := db.fsMigrator(fsys)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Down(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("rolling back migrations: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateFSTo migrates up or down until the schema is at version
+func (db *DB) MigrateFSTo(fsys fs.FS, version uint) error {
+	m, err := db.fsMigrator(fsys)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Migrate(version); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("migrating to version %d: %w", version, err)
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the last applied migration version, and whether that
+// migration failed part way through. Version 0 means no migrations have run.
+func (db *DB) SchemaVersion() (uint, bool, error) {
+	driver, err := db.migrationDriver()
+	if err != nil {
+		return 0, false, err
+	}
+
+	version, dirty, err := driver.Version()
+	if err != nil {
+		return 0, false, fmt.Errorf("reading schema version: %w", err)
+	}
+	if version < 0 {
+		return 0, false, nil
+	}
+
+	return uint(version), dirty, nil
+}
+
+func (db *DB) migrationDriver() (*sqlite3.Sqlite, error) {
+	driver, err := sqlite3.WithInstance(db.conn, &sqlite3.Config{MigrationsTable: SchemaVersionTable})
 	if err != nil {
-		return fmt.Errorf("creating migration driver: %w", err)
+		return nil, fmt.Errorf("creating migration driver: %w", err)
+	}
+	sqliteDriver, ok := driver.(*sqlite3.Sqlite)
+	if !ok {
+		return nil, errors.New("creating migration driver: unexpected driver type")
+	}
+	return sqliteDriver, nil
+}
+
+func (db *DB) fileMigrator(migrationsPath string) (*migrate.Migrate, error) {
+	driver, err := db.migrationDriver()
+	if err != nil {
+		return nil, err
 	}
 
 	m, err := migrate.NewWithDatabaseInstance(
@@ -81,12 +244,27 @@ func (db *DB) MigrateDown(migrationsPath string) error {
 		driver,
 	)
 	if err != nil {
-		return fmt.Errorf("creating migrator: %w", err)
+		return nil, fmt.Errorf("creating migrator: %w", err)
 	}
 
-	if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("rolling back migration: %w", err)
+	return m, nil
+}
+
+func (db *DB) fsMigrator(fsys fs.FS) (*migrate.Migrate, error) {
+	driver, err := db.migrationDriver()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	source, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite3", driver)
+	if err != nil {
+		return nil, fmt.Errorf("creating migrator: %w", err)
+	}
+
+	return m, nil
 }
\ No newline at end of file
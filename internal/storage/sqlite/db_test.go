@@ -1,10 +1,15 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"testing/fstest"
+
+	"github.com/adampetrovic/nrl-scheduler/migrations"
 )
 
 func TestNew(t *testing.T) {
@@ -34,6 +39,153 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_ConnectionPragmas(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	// Hold several connections at once so each comes from its own slot in the pool
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := db.conn.Conn(ctx)
+		if err != nil {
+			t.Fatalf("failed to get connection %d: %v", i, err)
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+
+	for i, conn := range conns {
+		var journalMode string
+		var busyTimeout, foreignKeys, synchronous int
+		if err := conn.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode); err != nil {
+			t.Fatalf("connection %d: failed to read journal mode: %v", i, err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+			t.Fatalf("connection %d: failed to read busy timeout: %v", i, err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+			t.Fatalf("connection %d: failed to read foreign keys: %v", i, err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA synchronous").Scan(&synchronous); err != nil {
+			t.Fatalf("connection %d: failed to read synchronous: %v", i, err)
+		}
+
+		if journalMode != "wal" {
+			t.Errorf("connection %d: expected WAL journal mode, got %s", i, journalMode)
+		}
+		if busyTimeout != int(DefaultBusyTimeout.Milliseconds()) {
+			t.Errorf("connection %d: expected busy timeout %d, got %d", i, DefaultBusyTimeout.Milliseconds(), busyTimeout)
+		}
+		if foreignKeys != 1 {
+			t.Errorf("connection %d: foreign keys should be enabled", i)
+		}
+		if synchronous != 1 {
+			t.Errorf("connection %d: expected synchronous NORMAL (1), got %d", i, synchronous)
+		}
+	}
+
+	if open := db.conn.Stats().MaxOpenConnections; open != DefaultMaxOpenConns {
+		t.Errorf("expected at most %d open connections, got %d", DefaultMaxOpenConns, open)
+	}
+}
+
+func TestNew_ConcurrentWrites(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.conn.Exec(`CREATE TABLE counters (id INTEGER PRIMARY KEY, value INTEGER NOT NULL)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	if _, err := db.conn.Exec(`INSERT INTO counters (id, value) VALUES (1, 0)`); err != nil {
+		t.Fatalf("failed to insert counter: %v", err)
+	}
+
+	// Read-then-write transactions racing with plain reads, as an applied
+	// optimization does while the API serves the draw
+	const writers, increments = 4, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*increments*2)
+	for w := 0; w < writers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < increments; i++ {
+				tx, err := db.conn.Begin()
+				if err != nil {
+					errs <- err
+					return
+				}
+				var value int
+				if err := tx.QueryRow(`SELECT value FROM counters WHERE id = 1`).Scan(&value); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				if _, err := tx.Exec(`UPDATE counters SET value = ? WHERE id = 1`, value+1); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				if err := tx.Commit(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < increments; i++ {
+				var value int
+				if err := db.conn.QueryRow(`SELECT value FROM counters WHERE id = 1`).Scan(&value); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent access failed: %v", err)
+	}
+
+	var value int
+	if err := db.conn.QueryRow(`SELECT value FROM counters WHERE id = 1`).Scan(&value); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	if value != writers*increments {
+		t.Errorf("expected counter at %d, got %d", writers*increments, value)
+	}
+}
+
+func TestNew_InMemory(t *testing.T) {
+	db, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	// Every query must reach the one in-memory database
+	if _, err := db.conn.Exec(`CREATE TABLE test_table (id INTEGER PRIMARY KEY)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	var count int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM test_table`).Scan(&count); err != nil {
+		t.Errorf("table should be visible to later queries: %v", err)
+	}
+	if open := db.conn.Stats().MaxOpenConnections; open != 1 {
+		t.Errorf("expected a single connection, got %d", open)
+	}
+}
+
 func TestNew_InvalidPath(t *testing.T) {
 	// Try to create database in non-existent directory
 	db, err := New("/invalid/path/test.db")
@@ -175,6 +327,85 @@ func TestMigrate_InvalidPath(t *testing.T) {
 	}
 }
 
+func TestMigrateFS_Embedded(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	version, _, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatalf("failed to read schema version: %v", err)
+	}
+	if version != 0 {
+		t.Errorf("fresh database should be at version 0, got %d", version)
+	}
+
+	// Bootstrap a fresh database from the embedded migrations
+	if err := db.MigrateFS(migrations.FS); err != nil {
+		t.Fatalf("failed to run embedded migrations: %v", err)
+	}
+
+	for _, table := range []string{"venues", "teams", "draws", "matches", SchemaVersionTable} {
+		var name string
+		err := db.conn.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", table).Scan(&name)
+		if err != nil {
+			t.Errorf("%s should exist: %v", table, err)
+		}
+	}
+
+	latest, dirty, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatalf("failed to read schema version: %v", err)
+	}
+	if latest < 1 || dirty {
+		t.Errorf("expected a clean schema version, got %d (dirty=%v)", latest, dirty)
+	}
+
+	// Running again should be a no-op
+	if err := db.MigrateFS(migrations.FS); err != nil {
+		t.Errorf("running migrations again should not error: %v", err)
+	}
+
+	// Pin back to the initial schema
+	if err := db.MigrateFSTo(migrations.FS, 1); err != nil {
+		t.Fatalf("failed to migrate to version 1: %v", err)
+	}
+	if version, _, _ := db.SchemaVersion(); version != 1 {
+		t.Errorf("expected version 1, got %d", version)
+	}
+
+	// Roll everything back
+	if err := db.MigrateDownFS(migrations.FS); err != nil {
+		t.Fatalf("failed to roll back migrations: %v", err)
+	}
+	var name string
+	err = db.conn.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='draws'").Scan(&name)
+	if err != sql.ErrNoRows {
+		t.Error("draws should not exist after rollback")
+	}
+}
+
+func TestMigrateFS_InvalidSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	// A filesystem without migrations can't be used as a source
+	if err := db.MigrateFS(fstest.MapFS{}); err == nil {
+		t.Error("expected error for empty migrations filesystem")
+	}
+}
+
 func TestConn(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -194,4 +425,68 @@ func TestConn(t *testing.T) {
 	if err := conn.Ping(); err != nil {
 		t.Errorf("connection should be valid: %v", err)
 	}
-}
\ No newline at end of file
+}
+func TestMigrateFS_ExplicitByes(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.MigrateFSTo(migrations.FS, 14); err != nil {
+		t.Fatalf("failed to migrate to version 14: %v", err)
+	}
+
+	// Three teams over three rounds, with one bye stored without its team
+	_, err = db.conn.Exec(`
+		INSERT INTO teams (id, name, short_name, city) VALUES
+			(1, 'Broncos', 'BRI', 'Brisbane'), (2, 'Storm', 'MEL', 'Melbourne'), (3, 'Panthers', 'PEN', 'Penrith');
+		INSERT INTO draws (id, name, season_year, rounds, status) VALUES (1, 'Draw', 2025, 3, 'completed');
+		INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+			(1, 1, 1, 2), (1, 2, 2, 3), (1, 3, 3, 1), (1, 1, NULL, NULL);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert data: %v", err)
+	}
+
+	if err := db.MigrateFS(migrations.FS); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	checkByes := func() {
+		t.Helper()
+		byes, err := NewMatchRepository(db.conn).ListByes(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("failed to list byes: %v", err)
+		}
+		want := [][2]int{{1, 3}, {2, 1}, {3, 2}}
+		if len(byes) != len(want) {
+			t.Fatalf("expected %d byes, got %d", len(want), len(byes))
+		}
+		for i, bye := range byes {
+			if bye.Round != want[i][0] || !bye.IsByeFor(want[i][1]) {
+				t.Errorf("bye %d: expected team %d in round %d, got %+v", i, want[i][1], want[i][0], bye)
+			}
+		}
+	}
+	checkByes()
+
+	// Rolling back keeps the byes, without their teams
+	if err := db.MigrateFSTo(migrations.FS, 14); err != nil {
+		t.Fatalf("failed to roll back to version 14: %v", err)
+	}
+	var count int
+	err = db.conn.QueryRow(`SELECT COUNT(*) FROM matches WHERE home_team_id IS NULL AND away_team_id IS NULL`).Scan(&count)
+	if err != nil {
+		t.Fatalf("failed to count byes: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 byes after rolling back, got %d", count)
+	}
+
+	// Migrating up again gives the same byes
+	if err := db.MigrateFS(migrations.FS); err != nil {
+		t.Fatalf("failed to run migrations again: %v", err)
+	}
+	checkByes()
+}
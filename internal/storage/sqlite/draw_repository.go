@@ -3,9 +3,11 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 )
 
 // DrawRepository implements storage.DrawRepository using SQLite
@@ -21,12 +23,12 @@ func NewDrawRepository(db DBExecutor) *DrawRepository {
 // Create inserts a new draw
 func (r *DrawRepository) Create(ctx context.Context, draw *models.Draw) error {
 	query := `
-		INSERT INTO draws (name, season_year, rounds, status, constraint_config)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO draws (name, season_year, rounds, status, constraint_config, competition_id)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
 	
 	result, err := r.db.ExecContext(ctx, query,
-		draw.Name, draw.SeasonYear, draw.Rounds, draw.Status, draw.ConstraintConfig)
+		draw.Name, draw.SeasonYear, draw.Rounds, draw.Status, draw.ConstraintConfig, draw.CompetitionID)
 	if err != nil {
 		return fmt.Errorf("creating draw: %w", err)
 	}
@@ -37,28 +39,36 @@ func (r *DrawRepository) Create(ctx context.Context, draw *models.Draw) error {
 	}
 
 	draw.ID = int(id)
+	draw.Version = 1
 	return nil
 }
 
 // Get retrieves a draw by ID
 func (r *DrawRepository) Get(ctx context.Context, id int) (*models.Draw, error) {
 	query := `
-		SELECT id, name, season_year, rounds, status, constraint_config, created_at, updated_at
+		SELECT id, name, season_year, rounds, status, constraint_config,
+			published_at, published_version, competition_id, version, created_at, updated_at
 		FROM draws
 		WHERE id = ?
 	`
 
 	draw := &models.Draw{}
+	var constraintConfig []byte
+	var publishedAt sql.NullTime
+	var publishedVersion sql.NullString
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&draw.ID, &draw.Name, &draw.SeasonYear, &draw.Rounds,
-		&draw.Status, &draw.ConstraintConfig, &draw.CreatedAt, &draw.UpdatedAt,
+		&draw.Status, &constraintConfig, &publishedAt, &publishedVersion,
+		&draw.CompetitionID, &draw.Version, &draw.CreatedAt, &draw.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("draw not found")
+		return nil, fmt.Errorf("draw %d: %w", id, storage.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("getting draw: %w", err)
 	}
+	draw.ConstraintConfig = constraintConfigFromColumn(constraintConfig)
+	setPublication(draw, publishedAt, publishedVersion)
 
 	return draw, nil
 }
@@ -71,11 +81,11 @@ func (r *DrawRepository) GetWithMatches(ctx context.Context, id int) (*models.Dr
 		return nil, err
 	}
 
-	// Then get all matches for this draw
+	// Then get all matches for this draw, splitting out the byes
 	query := `
 		SELECT 
 			m.id, m.draw_id, m.round, m.home_team_id, m.away_team_id, 
-			m.venue_id, m.match_date, m.match_time, m.is_prime_time,
+			m.venue_id, m.match_date, m.match_time, m.is_prime_time, m.locked,
 			m.created_at, m.updated_at
 		FROM matches m
 		WHERE m.draw_id = ?
@@ -91,12 +101,13 @@ func (r *DrawRepository) GetWithMatches(ctx context.Context, id int) (*models.Dr
 	var matches []*models.Match
 	for rows.Next() {
 		match := &models.Match{}
-		var matchDate, matchTime sql.NullTime
+		var matchDate sql.NullTime
+		var matchTime nullKickoffTime
 
 		err := rows.Scan(
 			&match.ID, &match.DrawID, &match.Round,
 			&match.HomeTeamID, &match.AwayTeamID, &match.VenueID,
-			&matchDate, &matchTime, &match.IsPrimeTime,
+			&matchDate, &matchTime, &match.IsPrimeTime, &match.Locked,
 			&match.CreatedAt, &match.UpdatedAt,
 		)
 		if err != nil {
@@ -117,19 +128,72 @@ func (r *DrawRepository) GetWithMatches(ctx context.Context, id int) (*models.Dr
 		return nil, fmt.Errorf("iterating matches: %w", err)
 	}
 
-	draw.Matches = matches
+	for _, match := range matches {
+		if match.IsBye() {
+			draw.Byes = append(draw.Byes, match)
+		} else {
+			draw.Matches = append(draw.Matches, match)
+		}
+	}
 	return draw, nil
 }
 
-// List retrieves all draws
-func (r *DrawRepository) List(ctx context.Context) ([]*models.Draw, error) {
-	query := `
-		SELECT id, name, season_year, rounds, status, constraint_config, created_at, updated_at
-		FROM draws
-		ORDER BY season_year DESC, created_at DESC
-	`
+// List retrieves draws, a page at a time when the options set PerPage
+func (r *DrawRepository) List(ctx context.Context, opts storage.ListOptions) ([]*models.Draw, error) {
+	return r.listDraws(ctx, opts)
+}
+
+// ListFiltered retrieves a page of the draws in a season and status, and
+// how many there are across every page
+func (r *DrawRepository) ListFiltered(ctx context.Context, filter storage.DrawFilter) ([]*models.Draw, int, error) {
+	conditions, err := drawFilterConditions(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	draws, err := r.listDraws(ctx, filter.ListOptions, conditions...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := drawListQuery.count(filter.ListOptions, conditions...)
+	var total int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting draws: %w", err)
+	}
+	return draws, total, nil
+}
+
+// drawFilterConditions turns a draw filter's season and status into WHERE
+// conditions. A published draw keeps its status, so "published" is checked
+// against the publication date instead.
+func drawFilterConditions(filter storage.DrawFilter) ([]condition, error) {
+	var conditions []condition
+	if filter.SeasonYear != 0 {
+		conditions = append(conditions, condition{clause: "season_year = ?", args: []interface{}{filter.SeasonYear}})
+	}
+
+	switch models.DrawStatus(filter.Status) {
+	case "":
+	case storage.DrawStatusPublished:
+		conditions = append(conditions, condition{clause: "published_at IS NOT NULL"})
+	case models.DrawStatusDraft, models.DrawStatusOptimizing, models.DrawStatusCompleted:
+		conditions = append(conditions, condition{clause: "status = ?", args: []interface{}{filter.Status}})
+	default:
+		return nil, fmt.Errorf("unsupported draw status %q", filter.Status)
+	}
+	return conditions, nil
+}
+
+// listDraws runs drawListQuery with any extra conditions
+func (r *DrawRepository) listDraws(ctx context.Context, opts storage.ListOptions, conditions ...condition) ([]*models.Draw, error) {
+	query, args, err := drawListQuery.list(`id, name, season_year, rounds, status, constraint_config,
+			published_at, published_version, competition_id, version, created_at, updated_at`, opts, conditions...)
+	if err != nil {
+		return nil, fmt.Errorf("listing draws: %w", err)
+	}
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("listing draws: %w", err)
 	}
@@ -138,13 +202,19 @@ func (r *DrawRepository) List(ctx context.Context) ([]*models.Draw, error) {
 	var draws []*models.Draw
 	for rows.Next() {
 		draw := &models.Draw{}
+		var constraintConfig []byte
+		var publishedAt sql.NullTime
+		var publishedVersion sql.NullString
 		err := rows.Scan(
 			&draw.ID, &draw.Name, &draw.SeasonYear, &draw.Rounds,
-			&draw.Status, &draw.ConstraintConfig, &draw.CreatedAt, &draw.UpdatedAt,
+			&draw.Status, &constraintConfig, &publishedAt, &publishedVersion,
+			&draw.CompetitionID, &draw.Version, &draw.CreatedAt, &draw.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scanning draw: %w", err)
 		}
+		draw.ConstraintConfig = constraintConfigFromColumn(constraintConfig)
+		setPublication(draw, publishedAt, publishedVersion)
 		draws = append(draws, draw)
 	}
 
@@ -155,16 +225,51 @@ func (r *DrawRepository) List(ctx context.Context) ([]*models.Draw, error) {
 	return draws, nil
 }
 
-// Update modifies an existing draw
+// Count returns how many draws match the options' search
+func (r *DrawRepository) Count(ctx context.Context, opts storage.ListOptions) (int, error) {
+	query, args := drawListQuery.count(opts)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting draws: %w", err)
+	}
+	return count, nil
+}
+
+// drawListQuery searches draws by name
+var drawListQuery = listQuery{
+	from:        "FROM draws",
+	search:      []string{"name"},
+	competition: "competition_id",
+	sortColumns: map[string]string{
+		"id":      "id",
+		"name":    "name",
+		"created": "created_at",
+		"updated": "updated_at",
+	},
+	defaultOrder: "season_year DESC, created_at DESC, id DESC",
+	tieBreak:     "id",
+}
+
+// Update modifies an existing draw, provided it's still at the draw's
+// version, and moves the draw on to the next version. A draw updated since
+// it was read is left alone and storage.ErrVersionConflict returned.
 func (r *DrawRepository) Update(ctx context.Context, draw *models.Draw) error {
 	query := `
 		UPDATE draws
-		SET name = ?, season_year = ?, rounds = ?, status = ?, constraint_config = ?
-		WHERE id = ?
+		SET name = ?, season_year = ?, rounds = ?, status = ?, constraint_config = ?,
+			published_at = ?, published_version = ?, competition_id = ?, version = version + 1
+		WHERE id = ? AND version = ?
 	`
 
+	var publishedVersion sql.NullString
+	if draw.PublishedVersion != "" {
+		publishedVersion = sql.NullString{String: draw.PublishedVersion, Valid: true}
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
-		draw.Name, draw.SeasonYear, draw.Rounds, draw.Status, draw.ConstraintConfig, draw.ID)
+		draw.Name, draw.SeasonYear, draw.Rounds, draw.Status, draw.ConstraintConfig,
+		draw.PublishedAt, publishedVersion, draw.CompetitionID, draw.ID, draw.Version)
 	if err != nil {
 		return fmt.Errorf("updating draw: %w", err)
 	}
@@ -174,9 +279,18 @@ func (r *DrawRepository) Update(ctx context.Context, draw *models.Draw) error {
 		return fmt.Errorf("getting rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("draw not found")
+		var version int
+		err := r.db.QueryRowContext(ctx, `SELECT version FROM draws WHERE id = ?`, draw.ID).Scan(&version)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("draw %d: %w", draw.ID, storage.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("checking draw version: %w", err)
+		}
+		return fmt.Errorf("%w: draw %d is at version %d, not %d", storage.ErrVersionConflict, draw.ID, version, draw.Version)
 	}
 
+	draw.Version++
 	return nil
 }
 
@@ -194,8 +308,24 @@ func (r *DrawRepository) Delete(ctx context.Context, id int) error {
 		return fmt.Errorf("getting rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("draw not found")
+		return fmt.Errorf("draw %d: %w", id, storage.ErrNotFound)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// constraintConfigFromColumn converts a nullable constraint_config column value.
+// NULL and empty values both mean the draw has no stored configuration.
+func constraintConfigFromColumn(value []byte) json.RawMessage {
+	if len(value) == 0 {
+		return nil
+	}
+	return json.RawMessage(value)
+}
+
+// setPublication copies the nullable publication columns onto a draw
+func setPublication(draw *models.Draw, publishedAt sql.NullTime, publishedVersion sql.NullString) {
+	if publishedAt.Valid {
+		draw.PublishedAt = &publishedAt.Time
+	}
+	draw.PublishedVersion = publishedVersion.String
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+func TestDrawRepository_UpdateVersion(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repos := NewRepositories(db.Conn())
+	ctx := context.Background()
+
+	draw := &models.Draw{Name: "2025", SeasonYear: 2025, Rounds: 2, Status: models.DrawStatusDraft}
+	if err := repos.Draws().Create(ctx, draw); err != nil {
+		t.Fatalf("creating draw: %v", err)
+	}
+	if draw.Version != 1 {
+		t.Fatalf("expected a new draw to be at version 1, got %d", draw.Version)
+	}
+
+	// Two editors read the same version; the first update wins
+	first, err := repos.Draws().Get(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("getting draw: %v", err)
+	}
+	second, err := repos.Draws().Get(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("getting draw: %v", err)
+	}
+
+	first.Name = "2025 revised"
+	if err := repos.Draws().Update(ctx, first); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("expected the update to move the draw to version 2, got %d", first.Version)
+	}
+
+	second.Status = models.DrawStatusCompleted
+	if err := repos.Draws().Update(ctx, second); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict for a stale update, got %v", err)
+	}
+
+	stored, err := repos.Draws().Get(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("getting draw: %v", err)
+	}
+	if stored.Name != "2025 revised" || stored.Status != models.DrawStatusDraft || stored.Version != 2 {
+		t.Errorf("expected the stale update to be rejected, got %+v", stored)
+	}
+
+	missing := &models.Draw{ID: draw.ID + 1, Name: "Missing", SeasonYear: 2025, Rounds: 2, Status: models.DrawStatusDraft, Version: 1}
+	if err := repos.Draws().Update(ctx, missing); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected not found for a missing draw, got %v", err)
+	}
+}
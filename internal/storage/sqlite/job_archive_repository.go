@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// JobArchiveRepository implements storage.JobArchiveRepository using SQLite
+type JobArchiveRepository struct {
+	db DBExecutor
+}
+
+// NewJobArchiveRepository creates a new job archive repository
+func NewJobArchiveRepository(db DBExecutor) *JobArchiveRepository {
+	return &JobArchiveRepository{db: db}
+}
+
+// Create inserts a new job archive
+func (r *JobArchiveRepository) Create(ctx context.Context, archive *models.JobArchive) error {
+	query := `
+		INSERT INTO optimization_job_archives (job_id, draw_id, status, initial_score,
+			final_score, payload, payload_size, is_best, started_at, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		archive.JobID, archive.DrawID, archive.Status, archive.InitialScore,
+		archive.FinalScore, archive.Payload, archive.PayloadSize, archive.IsBest,
+		archive.StartedAt, archive.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("creating job archive: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	archive.ID = int(id)
+	archive.StoredSize = len(archive.Payload)
+	archive.ArchivedAt = time.Now()
+	return nil
+}
+
+// GetByJobID retrieves an archived job, including its payload
+func (r *JobArchiveRepository) GetByJobID(ctx context.Context, jobID string) (*models.JobArchive, error) {
+	query := `
+		SELECT id, job_id, draw_id, status, initial_score, final_score, payload,
+			payload_size, is_best, started_at, completed_at, payload_discarded_at, archived_at
+		FROM optimization_job_archives
+		WHERE job_id = ?
+	`
+
+	archive := &models.JobArchive{}
+	var initialScore, finalScore sql.NullFloat64
+	var discardedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, jobID).Scan(
+		&archive.ID, &archive.JobID, &archive.DrawID, &archive.Status,
+		&initialScore, &finalScore, &archive.Payload,
+		&archive.PayloadSize, &archive.IsBest, &archive.StartedAt, &archive.CompletedAt,
+		&discardedAt, &archive.ArchivedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("job archive %s: %w", jobID, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting job archive: %w", err)
+	}
+
+	archive.InitialScore = initialScore.Float64
+	archive.FinalScore = finalScore.Float64
+	archive.StoredSize = len(archive.Payload)
+	if discardedAt.Valid {
+		archive.PayloadDiscardedAt = &discardedAt.Time
+	}
+
+	return archive, nil
+}
+
+// List retrieves every archived job, most recently finished first
+func (r *JobArchiveRepository) List(ctx context.Context) ([]*models.JobArchive, error) {
+	query := `
+		SELECT id, job_id, draw_id, status, initial_score, final_score, LENGTH(payload),
+			payload_size, is_best, started_at, completed_at, payload_discarded_at, archived_at
+		FROM optimization_job_archives
+		ORDER BY completed_at DESC, id DESC
+	`
+	return r.listArchives(ctx, query)
+}
+
+// ListByDraw retrieves the archived jobs for a draw, most recently finished first
+func (r *JobArchiveRepository) ListByDraw(ctx context.Context, drawID int) ([]*models.JobArchive, error) {
+	query := `
+		SELECT id, job_id, draw_id, status, initial_score, final_score, LENGTH(payload),
+			payload_size, is_best, started_at, completed_at, payload_discarded_at, archived_at
+		FROM optimization_job_archives
+		WHERE draw_id = ?
+		ORDER BY completed_at DESC, id DESC
+	`
+	return r.listArchives(ctx, query, drawID)
+}
+
+// SetBest marks a job as the draw's best result and clears the flag from the draw's other archives
+func (r *JobArchiveRepository) SetBest(ctx context.Context, drawID int, jobID string) error {
+	query := `
+		UPDATE optimization_job_archives
+		SET is_best = (job_id = ?)
+		WHERE draw_id = ?
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, jobID, drawID); err != nil {
+		return fmt.Errorf("marking best job archive: %w", err)
+	}
+
+	return nil
+}
+
+// DiscardPayloads drops the payloads of archives that finished before the
+// cutoff, except each draw's best result. It returns how many were dropped.
+func (r *JobArchiveRepository) DiscardPayloads(ctx context.Context, completedBefore, discardedAt time.Time) (int, error) {
+	query := `
+		UPDATE optimization_job_archives
+		SET payload = NULL, payload_discarded_at = ?
+		WHERE completed_at < ? AND is_best = FALSE AND payload IS NOT NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, discardedAt, completedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("discarding job archive payloads: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("getting rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+func (r *JobArchiveRepository) listArchives(ctx context.Context, query string, args ...interface{}) ([]*models.JobArchive, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing job archives: %w", err)
+	}
+	defer rows.Close()
+
+	var archives []*models.JobArchive
+	for rows.Next() {
+		archive := &models.JobArchive{}
+		var initialScore, finalScore sql.NullFloat64
+		var storedSize sql.NullInt64
+		var discardedAt sql.NullTime
+
+		err := rows.Scan(
+			&archive.ID, &archive.JobID, &archive.DrawID, &archive.Status,
+			&initialScore, &finalScore, &storedSize,
+			&archive.PayloadSize, &archive.IsBest, &archive.StartedAt, &archive.CompletedAt,
+			&discardedAt, &archive.ArchivedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning job archive: %w", err)
+		}
+
+		archive.InitialScore = initialScore.Float64
+		archive.FinalScore = finalScore.Float64
+		archive.StoredSize = int(storedSize.Int64)
+		if discardedAt.Valid {
+			archive.PayloadDiscardedAt = &discardedAt.Time
+		}
+
+		archives = append(archives, archive)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating job archives: %w", err)
+	}
+
+	return archives, nil
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// LadderRepository implements storage.LadderRepository using SQLite
+type LadderRepository struct {
+	db DBExecutor
+}
+
+// NewLadderRepository creates a new ladder repository
+func NewLadderRepository(db DBExecutor) *LadderRepository {
+	return &LadderRepository{db: db}
+}
+
+// Create inserts a ladder entry
+func (r *LadderRepository) Create(ctx context.Context, entry *models.LadderEntry) error {
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("validating ladder entry: %w", err)
+	}
+
+	query := `
+		INSERT INTO season_ladders (season, team_id, position, points)
+		VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, entry.Season, entry.TeamID, entry.Position, entry.Points)
+	if err != nil {
+		return fmt.Errorf("creating ladder entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	entry.ID = int(id)
+	entry.CreatedAt = time.Now()
+	return nil
+}
+
+// ListBySeason retrieves a season's ladder, top of the ladder first
+func (r *LadderRepository) ListBySeason(ctx context.Context, season int) ([]*models.LadderEntry, error) {
+	query := `
+		SELECT id, season, team_id, position, points, created_at
+		FROM season_ladders
+		WHERE season = ?
+		ORDER BY position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, season)
+	if err != nil {
+		return nil, fmt.Errorf("listing ladder entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.LadderEntry
+	for rows.Next() {
+		entry := &models.LadderEntry{}
+		if err := rows.Scan(&entry.ID, &entry.Season, &entry.TeamID, &entry.Position, &entry.Points, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning ladder entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating ladder entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// DeleteSeason removes every entry on a season's ladder
+func (r *LadderRepository) DeleteSeason(ctx context.Context, season int) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM season_ladders WHERE season = ?", season); err != nil {
+		return fmt.Errorf("deleting ladder: %w", err)
+	}
+	return nil
+}
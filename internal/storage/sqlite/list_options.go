@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// listQuery describes how a table is searched and sorted for List and Count
+type listQuery struct {
+	// from is everything after SELECT's column list and before any WHERE
+	from string
+	// filter is a condition every listed row meets, if any
+	filter string
+	// search lists the columns Search matches against
+	search []string
+	// competition is the column CompetitionID filters on, if the table has one
+	competition string
+	// sortColumns maps SortBy values to columns
+	sortColumns map[string]string
+	// defaultOrder is the ORDER BY used when SortBy is empty
+	defaultOrder string
+	// tieBreak keeps pages stable when the sort column has duplicates
+	tieBreak string
+}
+
+// condition is an extra WHERE condition a caller filters a listing on
+type condition struct {
+	clause string
+	args   []interface{}
+}
+
+// where returns the WHERE clause and args for the search, competition
+// filter and extra conditions, if any
+func (q listQuery) where(opts storage.ListOptions, extra ...condition) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if q.filter != "" {
+		clauses = append(clauses, q.filter)
+	}
+
+	if search := strings.TrimSpace(opts.Search); search != "" && len(q.search) > 0 {
+		// Treat the search as literal text, not a LIKE pattern
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(search)) + "%"
+
+		conditions := make([]string, len(q.search))
+		for i, column := range q.search {
+			conditions[i] = fmt.Sprintf(`LOWER(%s) LIKE ? ESCAPE '\'`, column)
+			args = append(args, pattern)
+		}
+		clauses = append(clauses, "("+strings.Join(conditions, " OR ")+")")
+	}
+
+	if opts.CompetitionID > 0 && q.competition != "" {
+		clauses = append(clauses, q.competition+" = ?")
+		args = append(args, opts.CompetitionID)
+	}
+
+	for _, c := range extra {
+		clauses = append(clauses, c.clause)
+		args = append(args, c.args...)
+	}
+
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+// list builds the query selecting columns for one page of results
+func (q listQuery) list(columns string, opts storage.ListOptions, extra ...condition) (string, []interface{}, error) {
+	order := q.defaultOrder
+	if opts.SortBy != "" {
+		column, ok := q.sortColumns[opts.SortBy]
+		if !ok {
+			return "", nil, fmt.Errorf("unsupported sort field %q", opts.SortBy)
+		}
+		direction := "ASC"
+		switch strings.ToLower(opts.SortDir) {
+		case "", "asc":
+		case "desc":
+			direction = "DESC"
+		default:
+			return "", nil, fmt.Errorf("unsupported sort direction %q", opts.SortDir)
+		}
+		order = fmt.Sprintf("%s %s, %s", column, direction, q.tieBreak)
+	}
+
+	where, args := q.where(opts, extra...)
+	query := "SELECT " + columns + " " + q.from + where + " ORDER BY " + order
+	if opts.PerPage > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, opts.PerPage, opts.Offset())
+	}
+	return query, args, nil
+}
+
+// count builds the query counting every result the search matches
+func (q listQuery) count(opts storage.ListOptions, extra ...condition) (string, []interface{}) {
+	where, args := q.where(opts, extra...)
+	return "SELECT COUNT(*) " + q.from + where, args
+}
@@ -4,14 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 )
 
 // MatchRepository implements storage.MatchRepository using SQLite
 type MatchRepository struct {
 	db    DBExecutor
 	sqlDB *sql.DB // Keep reference for transaction operations
+	// teams and venues hydrate matches with their relations
+	teams  *TeamRepository
+	venues *VenueRepository
 }
 
 // NewMatchRepository creates a new match repository
@@ -20,20 +28,25 @@ func NewMatchRepository(db DBExecutor) *MatchRepository {
 	if sdb, ok := db.(*sql.DB); ok {
 		sqlDB = sdb
 	}
-	return &MatchRepository{db: db, sqlDB: sqlDB}
+	return &MatchRepository{
+		db:     db,
+		sqlDB:  sqlDB,
+		teams:  NewTeamRepository(db),
+		venues: NewVenueRepository(db),
+	}
 }
 
 // Create inserts a new match
 func (r *MatchRepository) Create(ctx context.Context, match *models.Match) error {
 	query := `
 		INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, 
-			match_date, match_time, is_prime_time)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			match_date, match_time, is_prime_time, locked)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	
 	result, err := r.db.ExecContext(ctx, query,
 		match.DrawID, match.Round, match.HomeTeamID, match.AwayTeamID,
-		match.VenueID, match.MatchDate, match.MatchTime, match.IsPrimeTime)
+		match.VenueID, match.MatchDate, match.MatchTime, match.IsPrimeTime, match.Locked)
 	if err != nil {
 		return fmt.Errorf("creating match: %w", err)
 	}
@@ -71,8 +84,8 @@ func (r *MatchRepository) CreateBatch(ctx context.Context, matches []*models.Mat
 
 	query := `
 		INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, 
-			match_date, match_time, is_prime_time)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			match_date, match_time, is_prime_time, locked)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	stmt, err := tx.PrepareContext(ctx, query)
@@ -84,7 +97,7 @@ func (r *MatchRepository) CreateBatch(ctx context.Context, matches []*models.Mat
 	for _, match := range matches {
 		result, err := stmt.ExecContext(ctx,
 			match.DrawID, match.Round, match.HomeTeamID, match.AwayTeamID,
-			match.VenueID, match.MatchDate, match.MatchTime, match.IsPrimeTime)
+			match.VenueID, match.MatchDate, match.MatchTime, match.IsPrimeTime, match.Locked)
 		if err != nil {
 			return fmt.Errorf("creating match: %w", err)
 		}
@@ -108,22 +121,23 @@ func (r *MatchRepository) CreateBatch(ctx context.Context, matches []*models.Mat
 func (r *MatchRepository) Get(ctx context.Context, id int) (*models.Match, error) {
 	query := `
 		SELECT id, draw_id, round, home_team_id, away_team_id, venue_id,
-			match_date, match_time, is_prime_time, created_at, updated_at
+			match_date, match_time, is_prime_time, locked, created_at, updated_at
 		FROM matches
 		WHERE id = ?
 	`
 
 	match := &models.Match{}
-	var matchDate, matchTime sql.NullTime
+	var matchDate sql.NullTime
+	var matchTime nullKickoffTime
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&match.ID, &match.DrawID, &match.Round,
 		&match.HomeTeamID, &match.AwayTeamID, &match.VenueID,
-		&matchDate, &matchTime, &match.IsPrimeTime,
+		&matchDate, &matchTime, &match.IsPrimeTime, &match.Locked,
 		&match.CreatedAt, &match.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("match not found")
+		return nil, fmt.Errorf("match %d: %w", id, storage.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("getting match: %w", err)
@@ -141,104 +155,94 @@ func (r *MatchRepository) Get(ctx context.Context, id int) (*models.Match, error
 
 // GetWithRelations retrieves a match with teams and venue
 func (r *MatchRepository) GetWithRelations(ctx context.Context, id int) (*models.Match, error) {
-	query := `
-		SELECT 
-			m.id, m.draw_id, m.round, m.home_team_id, m.away_team_id, m.venue_id,
-			m.match_date, m.match_time, m.is_prime_time, m.created_at, m.updated_at,
-			ht.id, ht.name, ht.short_name, ht.city,
-			at.id, at.name, at.short_name, at.city,
-			v.id, v.name, v.city, v.capacity
-		FROM matches m
-		LEFT JOIN teams ht ON m.home_team_id = ht.id
-		LEFT JOIN teams at ON m.away_team_id = at.id
-		LEFT JOIN venues v ON m.venue_id = v.id
-		WHERE m.id = ?
-	`
-
-	match := &models.Match{}
-	var matchDate, matchTime sql.NullTime
-	var homeTeam, awayTeam models.Team
-	var venue models.Venue
-	var homeTeamID, awayTeamID, venueID sql.NullInt64
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&match.ID, &match.DrawID, &match.Round,
-		&homeTeamID, &awayTeamID, &venueID,
-		&matchDate, &matchTime, &match.IsPrimeTime,
-		&match.CreatedAt, &match.UpdatedAt,
-		&homeTeam.ID, &homeTeam.Name, &homeTeam.ShortName, &homeTeam.City,
-		&awayTeam.ID, &awayTeam.Name, &awayTeam.ShortName, &awayTeam.City,
-		&venue.ID, &venue.Name, &venue.City, &venue.Capacity,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("match not found")
-	}
+	match, err := r.Get(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("getting match with relations: %w", err)
+		return nil, err
 	}
 
-	if matchDate.Valid {
-		match.MatchDate = &matchDate.Time
+	if err := r.hydrate(ctx, []*models.Match{match}); err != nil {
+		return nil, err
 	}
-	if matchTime.Valid {
-		match.MatchTime = &matchTime.Time
-	}
-	if homeTeamID.Valid {
-		match.HomeTeamID = &[]int{int(homeTeamID.Int64)}[0]
-		match.HomeTeam = &homeTeam
-	}
-	if awayTeamID.Valid {
-		match.AwayTeamID = &[]int{int(awayTeamID.Int64)}[0]
-		match.AwayTeam = &awayTeam
+	return match, nil
+}
+
+// List retrieves fixtures across every draw, a page at a time when the
+// options set PerPage. Search matches team and venue names and cities.
+func (r *MatchRepository) List(ctx context.Context, opts storage.ListOptions) ([]*models.Match, error) {
+	query, args, err := matchListQuery.list(`m.id, m.draw_id, m.round, m.home_team_id, m.away_team_id, m.venue_id,
+			m.match_date, m.match_time, m.is_prime_time, m.locked, m.created_at, m.updated_at`, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing matches: %w", err)
 	}
-	if venueID.Valid {
-		match.VenueID = &[]int{int(venueID.Int64)}[0]
-		match.Venue = &venue
+
+	return r.listMatches(ctx, query, args...)
+}
+
+// Count returns how many matches the options' search matches
+func (r *MatchRepository) Count(ctx context.Context, opts storage.ListOptions) (int, error) {
+	query, args := matchListQuery.count(opts)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting matches: %w", err)
 	}
+	return count, nil
+}
 
-	return match, nil
+// matchListQuery searches fixtures by the names and cities of their teams and venue
+var matchListQuery = listQuery{
+	from: `FROM matches m
+		LEFT JOIN teams ht ON m.home_team_id = ht.id
+		LEFT JOIN teams at ON m.away_team_id = at.id
+		LEFT JOIN venues v ON m.venue_id = v.id`,
+	filter: "m.away_team_id IS NOT NULL",
+	search: []string{"ht.name", "ht.city", "at.name", "at.city", "v.name", "v.city"},
+	sortColumns: map[string]string{
+		"id":      "m.id",
+		"round":   "m.round",
+		"date":    "m.match_date",
+		"created": "m.created_at",
+		"updated": "m.updated_at",
+	},
+	defaultOrder: "m.draw_id, m.round, m.id",
+	tieBreak:     "m.id",
 }
 
-// ListByDraw retrieves all matches for a draw
+// ListByDraw retrieves all matches for a draw, not counting byes
 func (r *MatchRepository) ListByDraw(ctx context.Context, drawID int) ([]*models.Match, error) {
 	query := `
 		SELECT id, draw_id, round, home_team_id, away_team_id, venue_id,
-			match_date, match_time, is_prime_time, created_at, updated_at
+			match_date, match_time, is_prime_time, locked, created_at, updated_at
 		FROM matches
-		WHERE draw_id = ?
+		WHERE draw_id = ? AND away_team_id IS NOT NULL
 		ORDER BY round, id
 	`
 
 	return r.listMatches(ctx, query, drawID)
 }
 
-// ListByDrawWithRelations retrieves all matches for a draw with relations
+// ListByDrawWithRelations retrieves all matches for a draw with relations,
+// not counting byes. The teams and venues are loaded in a batch each, not
+// per match.
 func (r *MatchRepository) ListByDrawWithRelations(ctx context.Context, drawID int) ([]*models.Match, error) {
-	query := `
-		SELECT 
-			m.id, m.draw_id, m.round, m.home_team_id, m.away_team_id, m.venue_id,
-			m.match_date, m.match_time, m.is_prime_time, m.created_at, m.updated_at,
-			ht.id, ht.name, ht.short_name, ht.city,
-			at.id, at.name, at.short_name, at.city,
-			v.id, v.name, v.city, v.capacity
-		FROM matches m
-		LEFT JOIN teams ht ON m.home_team_id = ht.id
-		LEFT JOIN teams at ON m.away_team_id = at.id
-		LEFT JOIN venues v ON m.venue_id = v.id
-		WHERE m.draw_id = ?
-		ORDER BY m.round, m.id
-	`
+	matches, err := r.ListByDraw(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
 
-	return r.listMatchesWithRelations(ctx, query, drawID)
+	if err := r.hydrate(ctx, matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
 }
 
 // ListByRound retrieves all matches for a specific round
 func (r *MatchRepository) ListByRound(ctx context.Context, drawID, round int) ([]*models.Match, error) {
 	query := `
 		SELECT id, draw_id, round, home_team_id, away_team_id, venue_id,
-			match_date, match_time, is_prime_time, created_at, updated_at
+			match_date, match_time, is_prime_time, locked, created_at, updated_at
 		FROM matches
-		WHERE draw_id = ? AND round = ?
+		WHERE draw_id = ? AND round = ? AND away_team_id IS NOT NULL
 		ORDER BY id
 	`
 
@@ -249,27 +253,100 @@ func (r *MatchRepository) ListByRound(ctx context.Context, drawID, round int) ([
 func (r *MatchRepository) ListByTeam(ctx context.Context, drawID, teamID int) ([]*models.Match, error) {
 	query := `
 		SELECT id, draw_id, round, home_team_id, away_team_id, venue_id,
-			match_date, match_time, is_prime_time, created_at, updated_at
+			match_date, match_time, is_prime_time, locked, created_at, updated_at
 		FROM matches
-		WHERE draw_id = ? AND (home_team_id = ? OR away_team_id = ?)
+		WHERE draw_id = ? AND (home_team_id = ? OR away_team_id = ?) AND away_team_id IS NOT NULL
 		ORDER BY round, id
 	`
 
 	return r.listMatches(ctx, query, drawID, teamID, teamID)
 }
 
+// ListByes retrieves the byes stored for a draw, in round then team order
+func (r *MatchRepository) ListByes(ctx context.Context, drawID int) ([]*models.Match, error) {
+	query := `
+		SELECT id, draw_id, round, home_team_id, away_team_id, venue_id,
+			match_date, match_time, is_prime_time, locked, created_at, updated_at
+		FROM matches
+		WHERE draw_id = ? AND away_team_id IS NULL
+		ORDER BY round, home_team_id, id
+	`
+
+	return r.listMatches(ctx, query, drawID)
+}
+
+// SyncByes brings a draw's stored byes in line with its fixtures, so each
+// team has one bye row for every round it doesn't play. Byes that still
+// apply keep their IDs.
+func (r *MatchRepository) SyncByes(ctx context.Context, drawID int) error {
+	draw := &models.Draw{ID: drawID}
+	err := r.db.QueryRowContext(ctx, `SELECT rounds FROM draws WHERE id = ?`, drawID).Scan(&draw.Rounds)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("draw %d: %w", drawID, storage.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("getting draw rounds: %w", err)
+	}
+	if err := r.touchDraw(ctx, drawID); err != nil {
+		return err
+	}
+
+	if draw.Matches, err = r.ListByDraw(ctx, drawID); err != nil {
+		return err
+	}
+	stored, err := r.ListByes(ctx, drawID)
+	if err != nil {
+		return err
+	}
+
+	type byeKey struct{ round, teamID int }
+	byes := draw.ByesFromMatches()
+	missing := make(map[byeKey]bool)
+	for _, bye := range byes {
+		missing[byeKey{bye.Round, *bye.HomeTeamID}] = true
+	}
+
+	for _, bye := range stored {
+		if bye.HomeTeamID != nil {
+			key := byeKey{bye.Round, *bye.HomeTeamID}
+			if missing[key] {
+				delete(missing, key)
+				continue
+			}
+		}
+		if _, err := r.db.ExecContext(ctx, `DELETE FROM matches WHERE id = ?`, bye.ID); err != nil {
+			return fmt.Errorf("deleting bye: %w", err)
+		}
+	}
+
+	for _, bye := range byes {
+		if !missing[byeKey{bye.Round, *bye.HomeTeamID}] {
+			continue
+		}
+		if err := r.Create(ctx, bye); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Update modifies an existing match
 func (r *MatchRepository) Update(ctx context.Context, match *models.Match) error {
+	if err := r.touchMatchDraw(ctx, r.db, match.ID); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE matches
 		SET round = ?, home_team_id = ?, away_team_id = ?, venue_id = ?,
-			match_date = ?, match_time = ?, is_prime_time = ?
+			match_date = ?, match_time = ?, is_prime_time = ?, locked = ?
 		WHERE id = ?
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
 		match.Round, match.HomeTeamID, match.AwayTeamID, match.VenueID,
-		match.MatchDate, match.MatchTime, match.IsPrimeTime, match.ID)
+		match.MatchDate, match.MatchTime, match.IsPrimeTime, match.Locked, match.ID)
 	if err != nil {
 		return fmt.Errorf("updating match: %w", err)
 	}
@@ -279,7 +356,7 @@ func (r *MatchRepository) Update(ctx context.Context, match *models.Match) error
 		return fmt.Errorf("getting rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("match not found")
+		return fmt.Errorf("match %d: %w", match.ID, storage.ErrNotFound)
 	}
 
 	return nil
@@ -291,14 +368,9 @@ func (r *MatchRepository) UpdateBatch(ctx context.Context, matches []*models.Mat
 		return nil
 	}
 
-	// If we don't have a sql.DB reference, fall back to individual updates
+	// Already in a transaction, the caller commits
 	if r.sqlDB == nil {
-		for _, match := range matches {
-			if err := r.Update(ctx, match); err != nil {
-				return err
-			}
-		}
-		return nil
+		return r.updateBatch(ctx, matches)
 	}
 
 	tx, err := r.sqlDB.BeginTx(ctx, nil)
@@ -307,36 +379,79 @@ func (r *MatchRepository) UpdateBatch(ctx context.Context, matches []*models.Mat
 	}
 	defer tx.Rollback()
 
-	query := `
+	if err := NewTxMatchRepository(tx).updateBatch(ctx, matches); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// updateBatch updates the matches, then moves each draw they belong to on
+// one version, however many of its matches changed
+func (r *MatchRepository) updateBatch(ctx context.Context, matches []*models.Match) error {
+	stmt, err := r.db.PrepareContext(ctx, `
 		UPDATE matches
 		SET round = ?, home_team_id = ?, away_team_id = ?, venue_id = ?,
-			match_date = ?, match_time = ?, is_prime_time = ?
+			match_date = ?, match_time = ?, is_prime_time = ?, locked = ?
 		WHERE id = ?
-	`
-
-	stmt, err := tx.PrepareContext(ctx, query)
+	`)
 	if err != nil {
 		return fmt.Errorf("preparing statement: %w", err)
 	}
 	defer stmt.Close()
 
+	drawIDs := make(map[int]bool)
 	for _, match := range matches {
-		result, err := stmt.ExecContext(ctx,
-			match.Round, match.HomeTeamID, match.AwayTeamID, match.VenueID,
-			match.MatchDate, match.MatchTime, match.IsPrimeTime, match.ID)
+		var drawID int
+		err := r.db.QueryRowContext(ctx, `SELECT draw_id FROM matches WHERE id = ?`, match.ID).Scan(&drawID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("match %d: %w", match.ID, storage.ErrNotFound)
+		}
 		if err != nil {
-			return fmt.Errorf("updating match %d: %w", match.ID, err)
+			return fmt.Errorf("getting match draw: %w", err)
 		}
+		drawIDs[drawID] = true
 
-		rows, err := result.RowsAffected()
-		if err != nil {
-			return fmt.Errorf("getting rows affected: %w", err)
+		if _, err := stmt.ExecContext(ctx,
+			match.Round, match.HomeTeamID, match.AwayTeamID, match.VenueID,
+			match.MatchDate, match.MatchTime, match.IsPrimeTime, match.Locked, match.ID); err != nil {
+			return fmt.Errorf("updating match %d: %w", match.ID, err)
 		}
-		if rows == 0 {
-			return fmt.Errorf("match %d not found", match.ID)
+	}
+
+	for _, drawID := range idList(drawIDs) {
+		if err := r.touchDraw(ctx, drawID); err != nil {
+			return err
 		}
 	}
 
+	return nil
+}
+
+// ReplaceForDraw stores matches as the draw's fixtures in a single
+// transaction. Matches with an ID are updated in place, those without one are
+// inserted, and stored fixtures not among them are deleted. The byes are then
+// synced with the new fixtures. Nothing is changed if any match fails.
+func (r *MatchRepository) ReplaceForDraw(ctx context.Context, drawID int, matches []*models.Match) error {
+	// Already in a transaction, the caller commits
+	if r.sqlDB == nil {
+		return r.replaceForDraw(ctx, drawID, matches)
+	}
+
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := NewTxMatchRepository(tx).replaceForDraw(ctx, drawID, matches); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("committing transaction: %w", err)
 	}
@@ -344,8 +459,109 @@ func (r *MatchRepository) UpdateBatch(ctx context.Context, matches []*models.Mat
 	return nil
 }
 
+func (r *MatchRepository) replaceForDraw(ctx context.Context, drawID int, matches []*models.Match) error {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM matches WHERE draw_id = ? AND away_team_id IS NOT NULL`, drawID)
+	if err != nil {
+		return fmt.Errorf("listing stored matches: %w", err)
+	}
+	stale := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning match id: %w", err)
+		}
+		stale[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating match ids: %w", err)
+	}
+
+	update, err := r.db.PrepareContext(ctx, `
+		UPDATE matches
+		SET round = ?, home_team_id = ?, away_team_id = ?, venue_id = ?,
+			match_date = ?, match_time = ?, is_prime_time = ?, locked = ?
+		WHERE id = ?
+	`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer update.Close()
+
+	insert, err := r.db.PrepareContext(ctx, `
+		INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, 
+			match_date, match_time, is_prime_time, locked)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer insert.Close()
+
+	for _, match := range matches {
+		if match.ID == 0 {
+			result, err := insert.ExecContext(ctx,
+				drawID, match.Round, match.HomeTeamID, match.AwayTeamID,
+				match.VenueID, match.MatchDate, match.MatchTime, match.IsPrimeTime, match.Locked)
+			if err != nil {
+				return fmt.Errorf("creating match: %w", err)
+			}
+			id, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("getting last insert id: %w", err)
+			}
+			match.ID = int(id)
+			match.DrawID = drawID
+			continue
+		}
+
+		if !stale[match.ID] {
+			return fmt.Errorf("match %d is not a fixture in draw %d", match.ID, drawID)
+		}
+		delete(stale, match.ID)
+		if _, err := update.ExecContext(ctx,
+			match.Round, match.HomeTeamID, match.AwayTeamID, match.VenueID,
+			match.MatchDate, match.MatchTime, match.IsPrimeTime, match.Locked, match.ID); err != nil {
+			return fmt.Errorf("updating match %d: %w", match.ID, err)
+		}
+	}
+
+	for _, id := range idList(stale) {
+		if _, err := r.db.ExecContext(ctx, `DELETE FROM matches WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("deleting match %d: %w", id, err)
+		}
+	}
+
+	return r.SyncByes(ctx, drawID)
+}
+
+// SetLocked pins or unpins a match
+func (r *MatchRepository) SetLocked(ctx context.Context, id int, locked bool) error {
+	query := `UPDATE matches SET locked = ? WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, locked, id)
+	if err != nil {
+		return fmt.Errorf("setting match lock: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("match %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
 // Delete removes a match
 func (r *MatchRepository) Delete(ctx context.Context, id int) error {
+	if err := r.touchMatchDraw(ctx, r.db, id); err != nil {
+		return err
+	}
+
 	query := `DELETE FROM matches WHERE id = ?`
 
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -358,7 +574,7 @@ func (r *MatchRepository) Delete(ctx context.Context, id int) error {
 		return fmt.Errorf("getting rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("match not found")
+		return fmt.Errorf("match %d: %w", id, storage.ErrNotFound)
 	}
 
 	return nil
@@ -366,6 +582,10 @@ func (r *MatchRepository) Delete(ctx context.Context, id int) error {
 
 // DeleteByDraw removes all matches for a draw
 func (r *MatchRepository) DeleteByDraw(ctx context.Context, drawID int) error {
+	if err := r.touchDraw(ctx, drawID); err != nil {
+		return err
+	}
+
 	query := `DELETE FROM matches WHERE draw_id = ?`
 
 	_, err := r.db.ExecContext(ctx, query, drawID)
@@ -378,6 +598,27 @@ func (r *MatchRepository) DeleteByDraw(ctx context.Context, drawID int) error {
 
 // Helper methods
 
+// touchDraw moves a draw on to its next version. Every change to a draw's
+// stored fixture does, so an optimization result or edit made against the
+// draw as it was is refused instead of overwriting the change. New draws are
+// filled by Create and CreateBatch without a version change, and locking a
+// match leaves it alone, as applying a result keeps locked matches as stored.
+func (r *MatchRepository) touchDraw(ctx context.Context, drawID int) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE draws SET version = version + 1 WHERE id = ?`, drawID); err != nil {
+		return fmt.Errorf("updating draw version: %w", err)
+	}
+	return nil
+}
+
+// touchMatchDraw moves the draw of a stored match on to its next version
+func (r *MatchRepository) touchMatchDraw(ctx context.Context, db DBExecutor, matchID int) error {
+	query := `UPDATE draws SET version = version + 1 WHERE id = (SELECT draw_id FROM matches WHERE id = ?)`
+	if _, err := db.ExecContext(ctx, query, matchID); err != nil {
+		return fmt.Errorf("updating draw version: %w", err)
+	}
+	return nil
+}
+
 func (r *MatchRepository) listMatches(ctx context.Context, query string, args ...interface{}) ([]*models.Match, error) {
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -388,12 +629,13 @@ func (r *MatchRepository) listMatches(ctx context.Context, query string, args ..
 	var matches []*models.Match
 	for rows.Next() {
 		match := &models.Match{}
-		var matchDate, matchTime sql.NullTime
+		var matchDate sql.NullTime
+		var matchTime nullKickoffTime
 
 		err := rows.Scan(
 			&match.ID, &match.DrawID, &match.Round,
 			&match.HomeTeamID, &match.AwayTeamID, &match.VenueID,
-			&matchDate, &matchTime, &match.IsPrimeTime,
+			&matchDate, &matchTime, &match.IsPrimeTime, &match.Locked,
 			&match.CreatedAt, &match.UpdatedAt,
 		)
 		if err != nil {
@@ -417,59 +659,97 @@ func (r *MatchRepository) listMatches(ctx context.Context, query string, args ..
 	return matches, nil
 }
 
-func (r *MatchRepository) listMatchesWithRelations(ctx context.Context, query string, args ...interface{}) ([]*models.Match, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("listing matches with relations: %w", err)
+// hydrate attaches the matches' teams and venues, loading each once however
+// many matches share it
+func (r *MatchRepository) hydrate(ctx context.Context, matches []*models.Match) error {
+	teamIDs := make(map[int]bool)
+	venueIDs := make(map[int]bool)
+	for _, match := range matches {
+		if match.HomeTeamID != nil {
+			teamIDs[*match.HomeTeamID] = true
+		}
+		if match.AwayTeamID != nil {
+			teamIDs[*match.AwayTeamID] = true
+		}
+		if match.VenueID != nil {
+			venueIDs[*match.VenueID] = true
+		}
 	}
-	defer rows.Close()
 
-	var matches []*models.Match
-	for rows.Next() {
-		match := &models.Match{}
-		var matchDate, matchTime sql.NullTime
-		var homeTeam, awayTeam models.Team
-		var venue models.Venue
-		var homeTeamID, awayTeamID, venueID sql.NullInt64
+	teams, err := r.teams.ListByIDs(ctx, idList(teamIDs))
+	if err != nil {
+		return fmt.Errorf("loading match teams: %w", err)
+	}
+	venues, err := r.venues.ListByIDs(ctx, idList(venueIDs))
+	if err != nil {
+		return fmt.Errorf("loading match venues: %w", err)
+	}
 
-		err := rows.Scan(
-			&match.ID, &match.DrawID, &match.Round,
-			&homeTeamID, &awayTeamID, &venueID,
-			&matchDate, &matchTime, &match.IsPrimeTime,
-			&match.CreatedAt, &match.UpdatedAt,
-			&homeTeam.ID, &homeTeam.Name, &homeTeam.ShortName, &homeTeam.City,
-			&awayTeam.ID, &awayTeam.Name, &awayTeam.ShortName, &awayTeam.City,
-			&venue.ID, &venue.Name, &venue.City, &venue.Capacity,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("scanning match with relations: %w", err)
-		}
+	teamsByID := make(map[int]*models.Team, len(teams))
+	for _, team := range teams {
+		teamsByID[team.ID] = team
+	}
+	venuesByID := make(map[int]*models.Venue, len(venues))
+	for _, venue := range venues {
+		venuesByID[venue.ID] = venue
+	}
 
-		if matchDate.Valid {
-			match.MatchDate = &matchDate.Time
-		}
-		if matchTime.Valid {
-			match.MatchTime = &matchTime.Time
-		}
-		if homeTeamID.Valid {
-			match.HomeTeamID = &[]int{int(homeTeamID.Int64)}[0]
-			match.HomeTeam = &homeTeam
+	for _, match := range matches {
+		if match.HomeTeamID != nil {
+			match.HomeTeam = teamsByID[*match.HomeTeamID]
 		}
-		if awayTeamID.Valid {
-			match.AwayTeamID = &[]int{int(awayTeamID.Int64)}[0]
-			match.AwayTeam = &awayTeam
+		if match.AwayTeamID != nil {
+			match.AwayTeam = teamsByID[*match.AwayTeamID]
 		}
-		if venueID.Valid {
-			match.VenueID = &[]int{int(venueID.Int64)}[0]
-			match.Venue = &venue
+		if match.VenueID != nil {
+			match.Venue = venuesByID[*match.VenueID]
 		}
-
-		matches = append(matches, match)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterating matches: %w", err)
+	return nil
+}
+
+// idList returns the IDs in a set in ascending order
+func idList(set map[int]bool) []int {
+	ids := make([]int, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
 	}
+	sort.Ints(ids)
+	return ids
+}
 
-	return matches, nil
-}
\ No newline at end of file
+// nullKickoffTime scans the match_time column. SQLite only converts DATE,
+// DATETIME and TIMESTAMP columns back into time values, so a TIME column
+// comes back as the text the driver wrote.
+type nullKickoffTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *nullKickoffTime) Scan(value interface{}) error {
+	t.Time, t.Valid = time.Time{}, false
+
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		t.Time, t.Valid = v, true
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("unsupported match time type %T", value)
+	}
+
+	for _, layout := range append(sqlite3.SQLiteTimestampFormats, "15:04:05", "15:04") {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			t.Time, t.Valid = parsed, true
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid match time %q", text)
+}
@@ -0,0 +1,251 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+func TestMatchRepository_ListByDrawWithRelations(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repos := NewRepositories(db.Conn())
+	ctx := context.Background()
+
+	venue := &models.Venue{Name: "Suncorp Stadium", City: "Brisbane", Capacity: 52500, Latitude: -27.4649, Longitude: 153.0095}
+	if err := repos.Venues().Create(ctx, venue); err != nil {
+		t.Fatalf("creating venue: %v", err)
+	}
+	var teams []*models.Team
+	for _, name := range []string{"Broncos", "Dolphins", "Storm"} {
+		team := &models.Team{Name: name, ShortName: name[:3], City: "Brisbane", VenueID: &venue.ID, Latitude: -27.4649, Longitude: 153.0095}
+		if err := repos.Teams().Create(ctx, team); err != nil {
+			t.Fatalf("creating team: %v", err)
+		}
+		teams = append(teams, team)
+	}
+	draw := &models.Draw{Name: "2025", SeasonYear: 2025, Rounds: 2, Status: models.DrawStatusDraft}
+	if err := repos.Draws().Create(ctx, draw); err != nil {
+		t.Fatalf("creating draw: %v", err)
+	}
+	matches := []*models.Match{
+		{DrawID: draw.ID, Round: 1, HomeTeamID: &teams[0].ID, AwayTeamID: &teams[1].ID, VenueID: &venue.ID},
+		{DrawID: draw.ID, Round: 2, HomeTeamID: &teams[1].ID, AwayTeamID: &teams[2].ID},
+		models.NewBye(draw.ID, 1, teams[2].ID),
+	}
+	if err := repos.Matches().CreateBatch(ctx, matches); err != nil {
+		t.Fatalf("creating matches: %v", err)
+	}
+
+	loaded, err := repos.Matches().ListByDrawWithRelations(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("ListByDrawWithRelations() error = %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 matches without the bye, got %d", len(loaded))
+	}
+	first := loaded[0]
+	if first.HomeTeam == nil || first.HomeTeam.Name != "Broncos" || first.HomeTeam.Latitude != -27.4649 {
+		t.Errorf("expected the full home team, got %+v", first.HomeTeam)
+	}
+	if first.Venue == nil || first.Venue.Capacity != 52500 {
+		t.Errorf("expected the full venue, got %+v", first.Venue)
+	}
+	if loaded[1].Venue != nil {
+		t.Errorf("expected no venue for an unscheduled match, got %+v", loaded[1].Venue)
+	}
+
+	// Renaming a team through the repository clears the cached copy
+	teams[1].Name = "Redcliffe Dolphins"
+	if err := repos.Teams().Update(ctx, teams[1]); err != nil {
+		t.Fatalf("updating team: %v", err)
+	}
+	match, err := repos.Matches().GetWithRelations(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetWithRelations() error = %v", err)
+	}
+	if match.AwayTeam == nil || match.AwayTeam.Name != "Redcliffe Dolphins" {
+		t.Errorf("expected the renamed away team, got %+v", match.AwayTeam)
+	}
+
+	// So does committing a transaction that changed a venue
+	tx, err := repos.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("beginning transaction: %v", err)
+	}
+	venue.Name = "Lang Park"
+	if err := tx.Venues().Update(ctx, venue); err != nil {
+		t.Fatalf("updating venue: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("committing: %v", err)
+	}
+	venues, err := repos.Venues().ListByIDs(ctx, []int{venue.ID, 999})
+	if err != nil {
+		t.Fatalf("ListByIDs() error = %v", err)
+	}
+	if len(venues) != 1 || venues[0].Name != "Lang Park" {
+		t.Errorf("expected the renamed venue alone, got %+v", venues)
+	}
+}
+
+func TestMatchRepository_ReplaceForDraw(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repos := NewRepositories(db.Conn())
+	ctx := context.Background()
+
+	var teams []*models.Team
+	for _, name := range []string{"Broncos", "Dolphins", "Storm", "Raiders"} {
+		team := &models.Team{Name: name, ShortName: name[:3], City: name}
+		if err := repos.Teams().Create(ctx, team); err != nil {
+			t.Fatalf("creating team: %v", err)
+		}
+		teams = append(teams, team)
+	}
+	draw := &models.Draw{Name: "2025", SeasonYear: 2025, Rounds: 2, Status: models.DrawStatusDraft}
+	if err := repos.Draws().Create(ctx, draw); err != nil {
+		t.Fatalf("creating draw: %v", err)
+	}
+	matches := []*models.Match{
+		{DrawID: draw.ID, Round: 1, HomeTeamID: &teams[0].ID, AwayTeamID: &teams[1].ID},
+		{DrawID: draw.ID, Round: 1, HomeTeamID: &teams[2].ID, AwayTeamID: &teams[3].ID},
+		{DrawID: draw.ID, Round: 2, HomeTeamID: &teams[0].ID, AwayTeamID: &teams[2].ID},
+	}
+	if err := repos.Matches().CreateBatch(ctx, matches); err != nil {
+		t.Fatalf("creating matches: %v", err)
+	}
+	if err := repos.Matches().SyncByes(ctx, draw.ID); err != nil {
+		t.Fatalf("syncing byes: %v", err)
+	}
+
+	// An unknown match fails the whole replacement
+	stranger := &models.Match{ID: 999, Round: 2, HomeTeamID: &teams[1].ID, AwayTeamID: &teams[3].ID}
+	moved := *matches[0]
+	moved.Round = 2
+	if err := repos.Matches().ReplaceForDraw(ctx, draw.ID, []*models.Match{&moved, stranger}); err == nil {
+		t.Fatal("expected an error for a match outside the draw")
+	}
+	stored, err := repos.Matches().ListByDraw(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("ListByDraw() error = %v", err)
+	}
+	if len(stored) != 3 || stored[0].ID != matches[0].ID || stored[0].Round != 1 {
+		t.Errorf("expected the draw untouched after a failed replacement, got %+v", stored)
+	}
+
+	// Move the first fixture, drop the others and add a new one
+	added := &models.Match{Round: 2, HomeTeamID: &teams[2].ID, AwayTeamID: &teams[3].ID}
+	if err := repos.Matches().ReplaceForDraw(ctx, draw.ID, []*models.Match{&moved, added}); err != nil {
+		t.Fatalf("ReplaceForDraw() error = %v", err)
+	}
+	if added.ID == 0 || added.DrawID != draw.ID {
+		t.Errorf("expected the new fixture to be created in the draw, got %+v", added)
+	}
+
+	stored, err = repos.Matches().ListByDraw(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("ListByDraw() error = %v", err)
+	}
+	rounds := make(map[int]int)
+	for _, match := range stored {
+		rounds[match.ID] = match.Round
+	}
+	want := map[int]int{matches[0].ID: 2, added.ID: 2}
+	if len(rounds) != len(want) {
+		t.Fatalf("expected %d fixtures, got %+v", len(want), rounds)
+	}
+	for id, round := range want {
+		if rounds[id] != round {
+			t.Errorf("match %d: expected round %d, got %d", id, round, rounds[id])
+		}
+	}
+
+	// Every team now has its bye in round 1
+	byes, err := repos.Matches().ListByes(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("ListByes() error = %v", err)
+	}
+	if len(byes) != 4 {
+		t.Fatalf("expected 4 byes, got %d", len(byes))
+	}
+	for _, bye := range byes {
+		if bye.Round != 1 {
+			t.Errorf("expected byes in round 1, got %+v", bye)
+		}
+	}
+}
+
+func TestMatchRepository_UpdateBatchVersion(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repos := NewRepositories(db.Conn())
+	ctx := context.Background()
+
+	var teams []*models.Team
+	for _, name := range []string{"Broncos", "Dolphins", "Storm", "Raiders"} {
+		team := &models.Team{Name: name, ShortName: name[:3], City: name}
+		if err := repos.Teams().Create(ctx, team); err != nil {
+			t.Fatalf("creating team: %v", err)
+		}
+		teams = append(teams, team)
+	}
+	draw := &models.Draw{Name: "2025", SeasonYear: 2025, Rounds: 2, Status: models.DrawStatusDraft}
+	if err := repos.Draws().Create(ctx, draw); err != nil {
+		t.Fatalf("creating draw: %v", err)
+	}
+	matches := []*models.Match{
+		{DrawID: draw.ID, Round: 1, HomeTeamID: &teams[0].ID, AwayTeamID: &teams[1].ID},
+		{DrawID: draw.ID, Round: 1, HomeTeamID: &teams[2].ID, AwayTeamID: &teams[3].ID},
+		{DrawID: draw.ID, Round: 2, HomeTeamID: &teams[0].ID, AwayTeamID: &teams[2].ID},
+	}
+	if err := repos.Matches().CreateBatch(ctx, matches); err != nil {
+		t.Fatalf("creating matches: %v", err)
+	}
+	before, err := repos.Draws().Get(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// One batch edit is one new version of the draw, however many matches it moves
+	for _, match := range matches {
+		match.Round = 3 - match.Round
+	}
+	if err := repos.Matches().UpdateBatch(ctx, matches); err != nil {
+		t.Fatalf("UpdateBatch() error = %v", err)
+	}
+	after, err := repos.Draws().Get(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if after.Version != before.Version+1 {
+		t.Errorf("expected version %d after one batch, got %d", before.Version+1, after.Version)
+	}
+
+	// A batch with an unknown match changes nothing
+	stranger := &models.Match{ID: 999, Round: 1, HomeTeamID: &teams[1].ID, AwayTeamID: &teams[3].ID}
+	matches[0].Round = 1
+	if err := repos.Matches().UpdateBatch(ctx, []*models.Match{matches[0], stranger}); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("UpdateBatch() error = %v, want storage.ErrNotFound", err)
+	}
+	unchanged, err := repos.Draws().Get(ctx, draw.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if unchanged.Version != after.Version {
+		t.Errorf("expected version %d after a failed batch, got %d", after.Version, unchanged.Version)
+	}
+	stored, err := repos.Matches().Get(ctx, matches[0].ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stored.Round != 2 {
+		t.Errorf("expected match %d left in round 2, got %d", stored.ID, stored.Round)
+	}
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// OptimizationCheckpointRepository implements storage.OptimizationCheckpointRepository using SQLite
+type OptimizationCheckpointRepository struct {
+	db DBExecutor
+}
+
+// NewOptimizationCheckpointRepository creates a new optimization checkpoint repository
+func NewOptimizationCheckpointRepository(db DBExecutor) *OptimizationCheckpointRepository {
+	return &OptimizationCheckpointRepository{db: db}
+}
+
+// Save stores a job's checkpoint, replacing any earlier one
+func (r *OptimizationCheckpointRepository) Save(ctx context.Context, record *models.OptimizationCheckpointRecord) error {
+	if err := record.Validate(); err != nil {
+		return fmt.Errorf("validating checkpoint: %w", err)
+	}
+
+	query := `
+		INSERT INTO optimization_checkpoints (job_id, draw_id, iteration, config, state, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(job_id) DO UPDATE SET
+			iteration = excluded.iteration,
+			config = excluded.config,
+			state = excluded.state,
+			updated_at = excluded.updated_at
+	`
+
+	updatedAt := time.Now()
+	_, err := r.db.ExecContext(ctx, query,
+		record.JobID, record.DrawID, record.Iteration, string(record.Config), string(record.State), updatedAt)
+	if err != nil {
+		return fmt.Errorf("saving optimization checkpoint: %w", err)
+	}
+
+	record.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByJobID retrieves a job's latest checkpoint
+func (r *OptimizationCheckpointRepository) GetByJobID(ctx context.Context, jobID string) (*models.OptimizationCheckpointRecord, error) {
+	query := `
+		SELECT id, job_id, draw_id, iteration, config, state, updated_at
+		FROM optimization_checkpoints
+		WHERE job_id = ?
+	`
+
+	record := &models.OptimizationCheckpointRecord{}
+	var config, state string
+	err := r.db.QueryRowContext(ctx, query, jobID).Scan(
+		&record.ID, &record.JobID, &record.DrawID, &record.Iteration, &config, &state, &record.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("optimization checkpoint %s: %w", jobID, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting optimization checkpoint: %w", err)
+	}
+
+	record.Config = []byte(config)
+	record.State = []byte(state)
+	return record, nil
+}
+
+// Delete removes a job's checkpoint. Deleting a checkpoint that isn't stored is not an error.
+func (r *OptimizationCheckpointRepository) Delete(ctx context.Context, jobID string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM optimization_checkpoints WHERE job_id = ?", jobID); err != nil {
+		return fmt.Errorf("deleting optimization checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// OptimizationJobRepository implements storage.OptimizationJobRepository using SQLite
+type OptimizationJobRepository struct {
+	db DBExecutor
+}
+
+// NewOptimizationJobRepository creates a new optimization job repository
+func NewOptimizationJobRepository(db DBExecutor) *OptimizationJobRepository {
+	return &OptimizationJobRepository{db: db}
+}
+
+// Save inserts a job record, or replaces the stored state of an existing job
+func (r *OptimizationJobRepository) Save(ctx context.Context, record *models.OptimizationJobRecord) error {
+	if err := record.Validate(); err != nil {
+		return fmt.Errorf("validating job record: %w", err)
+	}
+
+	query := `
+		INSERT INTO optimization_jobs (job_id, draw_id, status, progress, result, error,
+			started_at, completed_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(job_id) DO UPDATE SET
+			status = excluded.status,
+			progress = excluded.progress,
+			result = excluded.result,
+			error = excluded.error,
+			completed_at = excluded.completed_at,
+			updated_at = excluded.updated_at
+	`
+
+	updatedAt := time.Now()
+	_, err := r.db.ExecContext(ctx, query,
+		record.JobID, record.DrawID, record.Status, nullableJSON(record.Progress),
+		nullableJSON(record.Result), record.Error, record.StartedAt, record.CompletedAt, updatedAt)
+	if err != nil {
+		return fmt.Errorf("saving optimization job: %w", err)
+	}
+
+	record.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByJobID retrieves a persisted job
+func (r *OptimizationJobRepository) GetByJobID(ctx context.Context, jobID string) (*models.OptimizationJobRecord, error) {
+	query := `
+		SELECT id, job_id, draw_id, status, progress, result, error,
+			started_at, completed_at, updated_at
+		FROM optimization_jobs
+		WHERE job_id = ?
+	`
+
+	record, err := scanOptimizationJob(r.db.QueryRowContext(ctx, query, jobID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("optimization job %s: %w", jobID, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting optimization job: %w", err)
+	}
+
+	return record, nil
+}
+
+// List retrieves every persisted job, oldest first
+func (r *OptimizationJobRepository) List(ctx context.Context) ([]*models.OptimizationJobRecord, error) {
+	query := `
+		SELECT id, job_id, draw_id, status, progress, result, error,
+			started_at, completed_at, updated_at
+		FROM optimization_jobs
+		ORDER BY started_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("listing optimization jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*models.OptimizationJobRecord
+	for rows.Next() {
+		record, err := scanOptimizationJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning optimization job: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating optimization jobs: %w", err)
+	}
+
+	return records, nil
+}
+
+// Delete removes a persisted job. Deleting a job that isn't stored is not an error.
+func (r *OptimizationJobRepository) Delete(ctx context.Context, jobID string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM optimization_jobs WHERE job_id = ?", jobID); err != nil {
+		return fmt.Errorf("deleting optimization job: %w", err)
+	}
+	return nil
+}
+
+func scanOptimizationJob(row rowScanner) (*models.OptimizationJobRecord, error) {
+	record := &models.OptimizationJobRecord{}
+	var progress, result, jobError sql.NullString
+	var completedAt sql.NullTime
+
+	err := row.Scan(
+		&record.ID, &record.JobID, &record.DrawID, &record.Status,
+		&progress, &result, &jobError,
+		&record.StartedAt, &completedAt, &record.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if progress.Valid {
+		record.Progress = []byte(progress.String)
+	}
+	if result.Valid {
+		record.Result = []byte(result.String)
+	}
+	record.Error = jobError.String
+	if completedAt.Valid {
+		record.CompletedAt = &completedAt.Time
+	}
+
+	return record, nil
+}
+
+// nullableJSON stores empty JSON snapshots as NULL
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+)
+
+// relationCache is an in-process read-through cache of the teams and venues
+// that match responses are hydrated with. It's shared by the repositories
+// NewRepositories creates, and any write through them, or a committed
+// transaction, clears it. A nil cache is never hit.
+type relationCache struct {
+	mu     sync.RWMutex
+	teams  map[int]models.Team
+	venues map[int]models.Venue
+	// generation counts invalidations, so a read that raced a write
+	// doesn't store what it loaded from before the write
+	generation uint64
+}
+
+// newRelationCache creates an empty cache
+func newRelationCache() *relationCache {
+	return &relationCache{
+		teams:  make(map[int]models.Team),
+		venues: make(map[int]models.Venue),
+	}
+}
+
+// invalidate drops every cached team and venue
+func (c *relationCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.teams = make(map[int]models.Team)
+	c.venues = make(map[int]models.Venue)
+	c.generation++
+}
+
+// currentGeneration is taken before loading what the cache missed, and
+// handed back when storing it
+func (c *relationCache) currentGeneration() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.generation
+}
+
+// getTeams returns copies of the cached teams among ids and the IDs it
+// doesn't hold
+func (c *relationCache) getTeams(ids []int) ([]*models.Team, []int) {
+	if c == nil {
+		return nil, ids
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var found []*models.Team
+	var missing []int
+	for _, id := range ids {
+		team, ok := c.teams[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		found = append(found, copyTeam(&team))
+	}
+	return found, missing
+}
+
+// putTeams caches teams loaded at the given generation
+func (c *relationCache) putTeams(generation uint64, teams []*models.Team) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	for _, team := range teams {
+		c.teams[team.ID] = *copyTeam(team)
+	}
+}
+
+// getVenues returns copies of the cached venues among ids and the IDs it
+// doesn't hold
+func (c *relationCache) getVenues(ids []int) ([]*models.Venue, []int) {
+	if c == nil {
+		return nil, ids
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var found []*models.Venue
+	var missing []int
+	for _, id := range ids {
+		venue, ok := c.venues[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		found = append(found, &venue)
+	}
+	return found, missing
+}
+
+// putVenues caches venues loaded at the given generation
+func (c *relationCache) putVenues(generation uint64, venues []*models.Venue) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	for _, venue := range venues {
+		c.venues[venue.ID] = *venue
+	}
+}
+
+// copyTeam copies a team deep enough that callers changing the copy's home
+// venues or unavailability don't change the cached team
+func copyTeam(team *models.Team) *models.Team {
+	copied := *team
+	copied.HomeVenues = append([]models.HomeVenue(nil), team.HomeVenues...)
+	copied.Unavailability = append([]models.TeamUnavailability(nil), team.Unavailability...)
+	return &copied
+}
+
+// idPlaceholders returns the "?, ?, ?" list and arguments for an IN clause
+func idPlaceholders(ids []int) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
+}
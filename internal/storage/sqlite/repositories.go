@@ -11,23 +11,65 @@ import (
 type Repositories struct {
 	db           *sql.DB
 	tx           *sql.Tx
+	cache        *relationCache
+	competitions *CompetitionRepository
 	venues       *VenueRepository
 	teams        *TeamRepository
 	draws        *DrawRepository
 	matches      *MatchRepository
+	approvals    *ApprovalRepository
+	shareLinks   *ShareLinkRepository
+	jobArchives  *JobArchiveRepository
+	optimizationJobs *OptimizationJobRepository
+	optimizationCheckpoints *OptimizationCheckpointRepository
+	solutions   *SolutionRepository
+	ladders     *LadderRepository
+	timeslots   *TimeslotRepository
+	seasonCalendars *SeasonCalendarRepository
+	constraintTemplates *ConstraintTemplateRepository
+	webhooks    *WebhookRepository
 }
 
-// NewRepositories creates a new repositories instance
+// NewRepositories creates a new repositories instance. Its teams and
+// venues are cached in-process between writes.
 func NewRepositories(db *sql.DB) *Repositories {
+	cache := newRelationCache()
+	competitions := NewCompetitionRepository(db)
+	competitions.cache = cache
+	venues := NewVenueRepository(db)
+	venues.cache = cache
+	teams := NewTeamRepository(db)
+	teams.cache = cache
+	matches := NewMatchRepository(db)
+	matches.teams, matches.venues = teams, venues
+
 	return &Repositories{
-		db:      db,
-		venues:  NewVenueRepository(db),
-		teams:   NewTeamRepository(db),
-		draws:   NewDrawRepository(db),
-		matches: NewMatchRepository(db),
+		db:         db,
+		cache:      cache,
+		competitions: competitions,
+		venues:     venues,
+		teams:      teams,
+		draws:      NewDrawRepository(db),
+		matches:    matches,
+		approvals:  NewApprovalRepository(db),
+		shareLinks: NewShareLinkRepository(db),
+		jobArchives: NewJobArchiveRepository(db),
+		optimizationJobs: NewOptimizationJobRepository(db),
+		optimizationCheckpoints: NewOptimizationCheckpointRepository(db),
+		solutions:   NewSolutionRepository(db),
+		ladders:     NewLadderRepository(db),
+		timeslots:   NewTimeslotRepository(db),
+		seasonCalendars: NewSeasonCalendarRepository(db),
+		constraintTemplates: NewConstraintTemplateRepository(db),
+		webhooks:    NewWebhookRepository(db),
 	}
 }
 
+// Competitions returns the competition repository
+func (r *Repositories) Competitions() storage.CompetitionRepository {
+	return r.competitions
+}
+
 // Venues returns the venue repository
 func (r *Repositories) Venues() storage.VenueRepository {
 	return r.venues
@@ -48,7 +90,63 @@ func (r *Repositories) Matches() storage.MatchRepository {
 	return r.matches
 }
 
-// BeginTx starts a transaction and returns a new repositories instance
+// Approvals returns the approval repository
+func (r *Repositories) Approvals() storage.ApprovalRepository {
+	return r.approvals
+}
+
+// ShareLinks returns the share link repository
+func (r *Repositories) ShareLinks() storage.ShareLinkRepository {
+	return r.shareLinks
+}
+
+// JobArchives returns the optimization job archive repository
+func (r *Repositories) JobArchives() storage.JobArchiveRepository {
+	return r.jobArchives
+}
+
+// OptimizationJobs returns the persisted optimization job repository
+func (r *Repositories) OptimizationJobs() storage.OptimizationJobRepository {
+	return r.optimizationJobs
+}
+
+// OptimizationCheckpoints returns the optimization checkpoint repository
+func (r *Repositories) OptimizationCheckpoints() storage.OptimizationCheckpointRepository {
+	return r.optimizationCheckpoints
+}
+
+// Solutions returns the solution library repository
+func (r *Repositories) Solutions() storage.SolutionRepository {
+	return r.solutions
+}
+
+// Ladders returns the season ladder repository
+func (r *Repositories) Ladders() storage.LadderRepository {
+	return r.ladders
+}
+
+// Timeslots returns the timeslot catalogue repository
+func (r *Repositories) Timeslots() storage.TimeslotRepository {
+	return r.timeslots
+}
+
+// SeasonCalendars returns the season calendar repository
+func (r *Repositories) SeasonCalendars() storage.SeasonCalendarRepository {
+	return r.seasonCalendars
+}
+
+// ConstraintTemplates returns the constraint template repository
+func (r *Repositories) ConstraintTemplates() storage.ConstraintTemplateRepository {
+	return r.constraintTemplates
+}
+
+// Webhooks returns the webhook repository
+func (r *Repositories) Webhooks() storage.WebhookRepository {
+	return r.webhooks
+}
+
+// BeginTx starts a transaction and returns a new repositories instance.
+// Reads in the transaction skip the cache, which committing clears.
 func (r *Repositories) BeginTx(ctx context.Context) (storage.Repositories, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -56,12 +154,25 @@ func (r *Repositories) BeginTx(ctx context.Context) (storage.Repositories, error
 	}
 
 	return &Repositories{
-		db:      r.db,
-		tx:      tx,
-		venues:  NewTxVenueRepository(tx),
-		teams:   NewTxTeamRepository(tx),
-		draws:   NewTxDrawRepository(tx),
-		matches: NewTxMatchRepository(tx),
+		db:         r.db,
+		tx:         tx,
+		cache:      r.cache,
+		competitions: NewTxCompetitionRepository(tx),
+		venues:     NewTxVenueRepository(tx),
+		teams:      NewTxTeamRepository(tx),
+		draws:      NewTxDrawRepository(tx),
+		matches:    NewTxMatchRepository(tx),
+		approvals:  NewTxApprovalRepository(tx),
+		shareLinks: NewTxShareLinkRepository(tx),
+		jobArchives: NewTxJobArchiveRepository(tx),
+		optimizationJobs: NewTxOptimizationJobRepository(tx),
+		optimizationCheckpoints: NewTxOptimizationCheckpointRepository(tx),
+		solutions:   NewTxSolutionRepository(tx),
+		ladders:     NewTxLadderRepository(tx),
+		timeslots:   NewTxTimeslotRepository(tx),
+		seasonCalendars: NewTxSeasonCalendarRepository(tx),
+		constraintTemplates: NewTxConstraintTemplateRepository(tx),
+		webhooks:    NewTxWebhookRepository(tx),
 	}, nil
 }
 
@@ -70,7 +181,11 @@ func (r *Repositories) Commit() error {
 	if r.tx == nil {
 		return nil
 	}
-	return r.tx.Commit()
+	if err := r.tx.Commit(); err != nil {
+		return err
+	}
+	r.cache.invalidate()
+	return nil
 }
 
 // Rollback rolls back the transaction
@@ -83,6 +198,11 @@ func (r *Repositories) Rollback() error {
 
 // Transaction repository implementations using sql.Tx
 
+// NewTxCompetitionRepository creates a competition repository that uses a transaction
+func NewTxCompetitionRepository(tx *sql.Tx) *CompetitionRepository {
+	return NewCompetitionRepository(tx)
+}
+
 // NewTxVenueRepository creates a venue repository that uses a transaction
 func NewTxVenueRepository(tx *sql.Tx) *VenueRepository {
 	return NewVenueRepository(tx)
@@ -101,4 +221,59 @@ func NewTxDrawRepository(tx *sql.Tx) *DrawRepository {
 // NewTxMatchRepository creates a match repository that uses a transaction
 func NewTxMatchRepository(tx *sql.Tx) *MatchRepository {
 	return NewMatchRepository(tx)
-}
\ No newline at end of file
+}
+
+// NewTxApprovalRepository creates an approval repository that uses a transaction
+func NewTxApprovalRepository(tx *sql.Tx) *ApprovalRepository {
+	return NewApprovalRepository(tx)
+}
+
+// NewTxShareLinkRepository creates a share link repository that uses a transaction
+func NewTxShareLinkRepository(tx *sql.Tx) *ShareLinkRepository {
+	return NewShareLinkRepository(tx)
+}
+
+// NewTxJobArchiveRepository creates a job archive repository that uses a transaction
+func NewTxJobArchiveRepository(tx *sql.Tx) *JobArchiveRepository {
+	return NewJobArchiveRepository(tx)
+}
+
+// NewTxOptimizationJobRepository creates an optimization job repository that uses a transaction
+func NewTxOptimizationJobRepository(tx *sql.Tx) *OptimizationJobRepository {
+	return NewOptimizationJobRepository(tx)
+}
+
+// NewTxOptimizationCheckpointRepository creates an optimization checkpoint repository that uses a transaction
+func NewTxOptimizationCheckpointRepository(tx *sql.Tx) *OptimizationCheckpointRepository {
+	return NewOptimizationCheckpointRepository(tx)
+}
+
+// NewTxSolutionRepository creates a solution repository that uses a transaction
+func NewTxSolutionRepository(tx *sql.Tx) *SolutionRepository {
+	return NewSolutionRepository(tx)
+}
+
+// NewTxLadderRepository creates a ladder repository that uses a transaction
+func NewTxLadderRepository(tx *sql.Tx) *LadderRepository {
+	return NewLadderRepository(tx)
+}
+
+// NewTxTimeslotRepository creates a timeslot repository that uses a transaction
+func NewTxTimeslotRepository(tx *sql.Tx) *TimeslotRepository {
+	return NewTimeslotRepository(tx)
+}
+
+// NewTxSeasonCalendarRepository creates a season calendar repository that uses a transaction
+func NewTxSeasonCalendarRepository(tx *sql.Tx) *SeasonCalendarRepository {
+	return NewSeasonCalendarRepository(tx)
+}
+
+// NewTxConstraintTemplateRepository creates a constraint template repository that uses a transaction
+func NewTxConstraintTemplateRepository(tx *sql.Tx) *ConstraintTemplateRepository {
+	return NewConstraintTemplateRepository(tx)
+}
+
+// NewTxWebhookRepository creates a webhook repository that uses a transaction
+func NewTxWebhookRepository(tx *sql.Tx) *WebhookRepository {
+	return NewWebhookRepository(tx)
+}
@@ -0,0 +1,202 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// SeasonCalendarRepository implements storage.SeasonCalendarRepository using SQLite
+type SeasonCalendarRepository struct {
+	db DBExecutor
+}
+
+// NewSeasonCalendarRepository creates a new season calendar repository
+func NewSeasonCalendarRepository(db DBExecutor) *SeasonCalendarRepository {
+	return &SeasonCalendarRepository{db: db}
+}
+
+// Create inserts a new season calendar. A competition can only have one
+// calendar a season.
+func (r *SeasonCalendarRepository) Create(ctx context.Context, calendar *models.SeasonCalendar) error {
+	if err := calendar.Validate(); err != nil {
+		return fmt.Errorf("validating season calendar: %w", err)
+	}
+
+	rounds, err := json.Marshal(calendar.Rounds)
+	if err != nil {
+		return fmt.Errorf("encoding calendar rounds: %w", err)
+	}
+
+	query := `
+		INSERT INTO season_calendars (name, season_year, competition_id, rounds)
+		VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, calendar.Name, calendar.SeasonYear, calendar.CompetitionID, string(rounds))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("creating season calendar: %w", storage.ErrConflict)
+		}
+		return fmt.Errorf("creating season calendar: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	calendar.ID = int(id)
+	calendar.CreatedAt = time.Now()
+	calendar.UpdatedAt = calendar.CreatedAt
+	return nil
+}
+
+// Get retrieves a season calendar by ID
+func (r *SeasonCalendarRepository) Get(ctx context.Context, id int) (*models.SeasonCalendar, error) {
+	query := `
+		SELECT id, name, season_year, competition_id, rounds, created_at, updated_at
+		FROM season_calendars
+		WHERE id = ?
+	`
+
+	calendar, err := scanSeasonCalendar(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("season calendar %d: %w", id, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting season calendar: %w", err)
+	}
+
+	return calendar, nil
+}
+
+// GetForSeason retrieves the competition's calendar for a season, or the
+// shared calendar when the competition has none or competitionID is nil
+func (r *SeasonCalendarRepository) GetForSeason(ctx context.Context, seasonYear int, competitionID *int) (*models.SeasonCalendar, error) {
+	query := `
+		SELECT id, name, season_year, competition_id, rounds, created_at, updated_at
+		FROM season_calendars
+		WHERE season_year = ? AND (competition_id IS NULL OR competition_id = ?)
+		ORDER BY competition_id IS NULL
+		LIMIT 1
+	`
+
+	calendar, err := scanSeasonCalendar(r.db.QueryRowContext(ctx, query, seasonYear, competitionID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("season calendar: %w", storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting season calendar: %w", err)
+	}
+
+	return calendar, nil
+}
+
+// List retrieves every season calendar, latest season first
+func (r *SeasonCalendarRepository) List(ctx context.Context) ([]*models.SeasonCalendar, error) {
+	query := `
+		SELECT id, name, season_year, competition_id, rounds, created_at, updated_at
+		FROM season_calendars
+		ORDER BY season_year DESC, competition_id IS NOT NULL, competition_id, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("listing season calendars: %w", err)
+	}
+	defer rows.Close()
+
+	var calendars []*models.SeasonCalendar
+	for rows.Next() {
+		calendar, err := scanSeasonCalendar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning season calendar: %w", err)
+		}
+		calendars = append(calendars, calendar)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating season calendars: %w", err)
+	}
+
+	return calendars, nil
+}
+
+// Update modifies an existing season calendar
+func (r *SeasonCalendarRepository) Update(ctx context.Context, calendar *models.SeasonCalendar) error {
+	if err := calendar.Validate(); err != nil {
+		return fmt.Errorf("validating season calendar: %w", err)
+	}
+
+	rounds, err := json.Marshal(calendar.Rounds)
+	if err != nil {
+		return fmt.Errorf("encoding calendar rounds: %w", err)
+	}
+
+	query := `
+		UPDATE season_calendars
+		SET name = ?, season_year = ?, competition_id = ?, rounds = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, calendar.Name, calendar.SeasonYear, calendar.CompetitionID, string(rounds), calendar.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("updating season calendar: %w", storage.ErrConflict)
+		}
+		return fmt.Errorf("updating season calendar: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("season calendar %d: %w", calendar.ID, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Delete removes a season calendar
+func (r *SeasonCalendarRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM season_calendars WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("deleting season calendar: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("season calendar %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanSeasonCalendar reads a season calendar from a row
+func scanSeasonCalendar(row rowScanner) (*models.SeasonCalendar, error) {
+	calendar := &models.SeasonCalendar{}
+	var rounds string
+	err := row.Scan(
+		&calendar.ID, &calendar.Name, &calendar.SeasonYear, &calendar.CompetitionID,
+		&rounds, &calendar.CreatedAt, &calendar.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(rounds), &calendar.Rounds); err != nil {
+		return nil, fmt.Errorf("decoding calendar rounds: %w", err)
+	}
+	return calendar, nil
+}
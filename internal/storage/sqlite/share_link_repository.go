@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// ShareLinkRepository implements storage.ShareLinkRepository using SQLite
+type ShareLinkRepository struct {
+	db DBExecutor
+}
+
+// NewShareLinkRepository creates a new share link repository
+func NewShareLinkRepository(db DBExecutor) *ShareLinkRepository {
+	return &ShareLinkRepository{db: db}
+}
+
+// Create inserts a new share link
+func (r *ShareLinkRepository) Create(ctx context.Context, link *models.ShareLink) error {
+	query := `
+		INSERT INTO draw_share_links (draw_id, token, draw_version, snapshot, label,
+			created_by, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		link.DrawID, link.Token, link.DrawVersion, string(link.Snapshot), link.Label,
+		link.CreatedBy, link.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("creating share link: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	link.ID = int(id)
+	link.CreatedAt = time.Now()
+	return nil
+}
+
+// GetByToken retrieves a share link by its token
+func (r *ShareLinkRepository) GetByToken(ctx context.Context, token string) (*models.ShareLink, error) {
+	query := `
+		SELECT id, draw_id, token, draw_version, snapshot, label, created_by,
+			expires_at, revoked_at, created_at
+		FROM draw_share_links
+		WHERE token = ?
+	`
+
+	link, err := scanShareLink(r.db.QueryRowContext(ctx, query, token))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("share link: %w", storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting share link: %w", err)
+	}
+
+	return link, nil
+}
+
+// ListByDraw retrieves all share links for a draw, newest first
+func (r *ShareLinkRepository) ListByDraw(ctx context.Context, drawID int) ([]*models.ShareLink, error) {
+	query := `
+		SELECT id, draw_id, token, draw_version, snapshot, label, created_by,
+			expires_at, revoked_at, created_at
+		FROM draw_share_links
+		WHERE draw_id = ?
+		ORDER BY id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, drawID)
+	if err != nil {
+		return nil, fmt.Errorf("listing share links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*models.ShareLink
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning share link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating share links: %w", err)
+	}
+
+	return links, nil
+}
+
+// Revoke withdraws a draw's share link so its token stops working
+func (r *ShareLinkRepository) Revoke(ctx context.Context, drawID, id int, revokedAt time.Time) error {
+	query := `
+		UPDATE draw_share_links
+		SET revoked_at = ?
+		WHERE id = ? AND draw_id = ? AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, revokedAt, id, drawID)
+	if err != nil {
+		return fmt.Errorf("revoking share link: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("share link %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanShareLink(row rowScanner) (*models.ShareLink, error) {
+	link := &models.ShareLink{}
+	var snapshot string
+	var label, createdBy sql.NullString
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&link.ID, &link.DrawID, &link.Token, &link.DrawVersion, &snapshot,
+		&label, &createdBy, &link.ExpiresAt, &revokedAt, &link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	link.Snapshot = []byte(snapshot)
+	link.Label = label.String
+	link.CreatedBy = createdBy.String
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+
+	return link, nil
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// SolutionRepository implements storage.SolutionRepository using SQLite
+type SolutionRepository struct {
+	db DBExecutor
+}
+
+// NewSolutionRepository creates a new solution repository
+func NewSolutionRepository(db DBExecutor) *SolutionRepository {
+	return &SolutionRepository{db: db}
+}
+
+// Save stores a solution, replacing any stored under the same key
+func (r *SolutionRepository) Save(ctx context.Context, solution *models.Solution) error {
+	if err := solution.Validate(); err != nil {
+		return fmt.Errorf("validating solution: %w", err)
+	}
+
+	matches, err := json.Marshal(solution.Matches)
+	if err != nil {
+		return fmt.Errorf("encoding solution matches: %w", err)
+	}
+
+	query := `
+		INSERT INTO solutions (solution_key, draw_id, job_id, score, rounds, match_count, matches, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(solution_key) DO UPDATE SET
+			draw_id = excluded.draw_id,
+			job_id = excluded.job_id,
+			score = excluded.score,
+			rounds = excluded.rounds,
+			match_count = excluded.match_count,
+			matches = excluded.matches,
+			updated_at = excluded.updated_at
+	`
+
+	updatedAt := time.Now()
+	_, err = r.db.ExecContext(ctx, query,
+		solution.Key, solution.DrawID, solution.JobID, solution.Score,
+		solution.Rounds, solution.MatchCount, string(matches), updatedAt)
+	if err != nil {
+		return fmt.Errorf("saving solution: %w", err)
+	}
+
+	if err := r.db.QueryRowContext(ctx, "SELECT id, created_at FROM solutions WHERE solution_key = ?", solution.Key).
+		Scan(&solution.ID, &solution.CreatedAt); err != nil {
+		return fmt.Errorf("getting saved solution: %w", err)
+	}
+	solution.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByKey retrieves the solution stored under a key, including its matches
+func (r *SolutionRepository) GetByKey(ctx context.Context, key string) (*models.Solution, error) {
+	query := `
+		SELECT id, solution_key, draw_id, job_id, score, rounds, match_count, matches, created_at, updated_at
+		FROM solutions
+		WHERE solution_key = ?
+	`
+
+	solution := &models.Solution{}
+	var drawID sql.NullInt64
+	var matches string
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&solution.ID, &solution.Key, &drawID, &solution.JobID, &solution.Score,
+		&solution.Rounds, &solution.MatchCount, &matches, &solution.CreatedAt, &solution.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("solution %s: %w", key, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting solution: %w", err)
+	}
+
+	if drawID.Valid {
+		id := int(drawID.Int64)
+		solution.DrawID = &id
+	}
+	if err := json.Unmarshal([]byte(matches), &solution.Matches); err != nil {
+		return nil, fmt.Errorf("decoding solution matches: %w", err)
+	}
+	return solution, nil
+}
+
+// List retrieves every solution without its matches, best scoring first
+func (r *SolutionRepository) List(ctx context.Context) ([]*models.Solution, error) {
+	query := `
+		SELECT id, solution_key, draw_id, job_id, score, rounds, match_count, created_at, updated_at
+		FROM solutions
+		ORDER BY score DESC, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("listing solutions: %w", err)
+	}
+	defer rows.Close()
+
+	var solutions []*models.Solution
+	for rows.Next() {
+		solution := &models.Solution{}
+		var drawID sql.NullInt64
+		if err := rows.Scan(
+			&solution.ID, &solution.Key, &drawID, &solution.JobID, &solution.Score,
+			&solution.Rounds, &solution.MatchCount, &solution.CreatedAt, &solution.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning solution: %w", err)
+		}
+		if drawID.Valid {
+			id := int(drawID.Int64)
+			solution.DrawID = &id
+		}
+		solutions = append(solutions, solution)
+	}
+
+	return solutions, rows.Err()
+}
+
+// Delete removes a solution by ID
+func (r *SolutionRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM solutions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("deleting solution: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("solution %d: %w", id, storage.ErrNotFound)
+	}
+	return nil
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 )
 
 // TeamRepository implements storage.TeamRepository using SQLite
 type TeamRepository struct {
-	db DBExecutor
+	db    DBExecutor
+	cache *relationCache
 }
 
 // NewTeamRepository creates a new team repository
@@ -21,12 +24,12 @@ func NewTeamRepository(db DBExecutor) *TeamRepository {
 // Create inserts a new team
 func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 	query := `
-		INSERT INTO teams (name, short_name, city, venue_id, latitude, longitude)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO teams (name, short_name, city, venue_id, latitude, longitude, competition_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 	
 	result, err := r.db.ExecContext(ctx, query,
-		team.Name, team.ShortName, team.City, team.VenueID, team.Latitude, team.Longitude)
+		team.Name, team.ShortName, team.City, team.VenueID, team.Latitude, team.Longitude, team.CompetitionID)
 	if err != nil {
 		return fmt.Errorf("creating team: %w", err)
 	}
@@ -43,7 +46,7 @@ func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 // Get retrieves a team by ID
 func (r *TeamRepository) Get(ctx context.Context, id int) (*models.Team, error) {
 	query := `
-		SELECT id, name, short_name, city, venue_id, latitude, longitude, created_at, updated_at
+		SELECT id, name, short_name, city, venue_id, latitude, longitude, competition_id, created_at, updated_at
 		FROM teams
 		WHERE id = ?
 	`
@@ -51,15 +54,22 @@ func (r *TeamRepository) Get(ctx context.Context, id int) (*models.Team, error)
 	team := &models.Team{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&team.ID, &team.Name, &team.ShortName, &team.City, &team.VenueID,
-		&team.Latitude, &team.Longitude, &team.CreatedAt, &team.UpdatedAt,
+		&team.Latitude, &team.Longitude, &team.CompetitionID, &team.CreatedAt, &team.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("team not found")
+		return nil, fmt.Errorf("team %d: %w", id, storage.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("getting team: %w", err)
 	}
 
+	if err := r.attachHomeVenues(ctx, []*models.Team{team}); err != nil {
+		return nil, err
+	}
+	if err := r.attachUnavailability(ctx, []*models.Team{team}); err != nil {
+		return nil, err
+	}
+
 	return team, nil
 }
 
@@ -68,7 +78,7 @@ func (r *TeamRepository) GetWithVenue(ctx context.Context, id int) (*models.Team
 	query := `
 		SELECT 
 			t.id, t.name, t.short_name, t.city, t.venue_id, t.latitude, t.longitude, 
-			t.created_at, t.updated_at,
+			t.competition_id, t.created_at, t.updated_at,
 			v.id, v.name, v.city, v.capacity, v.latitude, v.longitude
 		FROM teams t
 		LEFT JOIN venues v ON t.venue_id = v.id
@@ -81,12 +91,12 @@ func (r *TeamRepository) GetWithVenue(ctx context.Context, id int) (*models.Team
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&team.ID, &team.Name, &team.ShortName, &team.City, &venueID,
-		&team.Latitude, &team.Longitude, &team.CreatedAt, &team.UpdatedAt,
+		&team.Latitude, &team.Longitude, &team.CompetitionID, &team.CreatedAt, &team.UpdatedAt,
 		&venue.ID, &venue.Name, &venue.City, &venue.Capacity,
 		&venue.Latitude, &venue.Longitude,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("team not found")
+		return nil, fmt.Errorf("team %d: %w", id, storage.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("getting team with venue: %w", err)
@@ -97,18 +107,25 @@ func (r *TeamRepository) GetWithVenue(ctx context.Context, id int) (*models.Team
 		team.Venue = &venue
 	}
 
+	if err := r.attachHomeVenues(ctx, []*models.Team{team}); err != nil {
+		return nil, err
+	}
+	if err := r.attachUnavailability(ctx, []*models.Team{team}); err != nil {
+		return nil, err
+	}
+
 	return team, nil
 }
 
-// List retrieves all teams
-func (r *TeamRepository) List(ctx context.Context) ([]*models.Team, error) {
-	query := `
-		SELECT id, name, short_name, city, venue_id, latitude, longitude, created_at, updated_at
-		FROM teams
-		ORDER BY name
-	`
+// List retrieves teams, a page at a time when the options set PerPage
+func (r *TeamRepository) List(ctx context.Context, opts storage.ListOptions) ([]*models.Team, error) {
+	query, args, err := teamListQuery.list(
+		"id, name, short_name, city, venue_id, latitude, longitude, competition_id, created_at, updated_at", opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing teams: %w", err)
+	}
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("listing teams: %w", err)
 	}
@@ -119,7 +136,7 @@ func (r *TeamRepository) List(ctx context.Context) ([]*models.Team, error) {
 		team := &models.Team{}
 		err := rows.Scan(
 			&team.ID, &team.Name, &team.ShortName, &team.City, &team.VenueID,
-			&team.Latitude, &team.Longitude, &team.CreatedAt, &team.UpdatedAt,
+			&team.Latitude, &team.Longitude, &team.CompetitionID, &team.CreatedAt, &team.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scanning team: %w", err)
@@ -130,16 +147,50 @@ func (r *TeamRepository) List(ctx context.Context) ([]*models.Team, error) {
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterating teams: %w", err)
 	}
+	rows.Close()
+
+	if err := r.attachHomeVenues(ctx, teams); err != nil {
+		return nil, err
+	}
+	if err := r.attachUnavailability(ctx, teams); err != nil {
+		return nil, err
+	}
 
 	return teams, nil
 }
 
+// Count returns how many teams match the options' search
+func (r *TeamRepository) Count(ctx context.Context, opts storage.ListOptions) (int, error) {
+	query, args := teamListQuery.count(opts)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting teams: %w", err)
+	}
+	return count, nil
+}
+
+// teamListQuery searches teams by name, short name and city
+var teamListQuery = listQuery{
+	from:        "FROM teams",
+	search:      []string{"name", "short_name", "city"},
+	competition: "competition_id",
+	sortColumns: map[string]string{
+		"id":      "id",
+		"name":    "name",
+		"created": "created_at",
+		"updated": "updated_at",
+	},
+	defaultOrder: "name, id",
+	tieBreak:     "id",
+}
+
 // ListWithVenues retrieves all teams with their venues
 func (r *TeamRepository) ListWithVenues(ctx context.Context) ([]*models.Team, error) {
 	query := `
 		SELECT 
 			t.id, t.name, t.short_name, t.city, t.venue_id, t.latitude, t.longitude, 
-			t.created_at, t.updated_at,
+			t.competition_id, t.created_at, t.updated_at,
 			v.id, v.name, v.city, v.capacity, v.latitude, v.longitude
 		FROM teams t
 		LEFT JOIN venues v ON t.venue_id = v.id
@@ -160,7 +211,7 @@ func (r *TeamRepository) ListWithVenues(ctx context.Context) ([]*models.Team, er
 
 		err := rows.Scan(
 			&team.ID, &team.Name, &team.ShortName, &team.City, &venueID,
-			&team.Latitude, &team.Longitude, &team.CreatedAt, &team.UpdatedAt,
+			&team.Latitude, &team.Longitude, &team.CompetitionID, &team.CreatedAt, &team.UpdatedAt,
 			&venue.ID, &venue.Name, &venue.City, &venue.Capacity,
 			&venue.Latitude, &venue.Longitude,
 		)
@@ -179,21 +230,82 @@ func (r *TeamRepository) ListWithVenues(ctx context.Context) ([]*models.Team, er
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterating teams: %w", err)
 	}
+	rows.Close()
+
+	if err := r.attachHomeVenues(ctx, teams); err != nil {
+		return nil, err
+	}
+	if err := r.attachUnavailability(ctx, teams); err != nil {
+		return nil, err
+	}
 
 	return teams, nil
 }
 
+// ListByIDs retrieves the teams with the given IDs in one query, in no
+// particular order. IDs with no team are skipped. Teams already in the
+// repository's cache aren't loaded again.
+func (r *TeamRepository) ListByIDs(ctx context.Context, ids []int) ([]*models.Team, error) {
+	teams, missing := r.cache.getTeams(ids)
+	if len(missing) == 0 {
+		return teams, nil
+	}
+	generation := r.cache.currentGeneration()
+
+	placeholders, args := idPlaceholders(missing)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, short_name, city, venue_id, latitude, longitude, competition_id, created_at, updated_at
+		FROM teams
+		WHERE id IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing teams by id: %w", err)
+	}
+	defer rows.Close()
+
+	var loaded []*models.Team
+	for rows.Next() {
+		team := &models.Team{}
+		err := rows.Scan(
+			&team.ID, &team.Name, &team.ShortName, &team.City, &team.VenueID,
+			&team.Latitude, &team.Longitude, &team.CompetitionID, &team.CreatedAt, &team.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning team: %w", err)
+		}
+		loaded = append(loaded, team)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating teams: %w", err)
+	}
+	rows.Close()
+
+	if err := r.attachHomeVenues(ctx, loaded); err != nil {
+		return nil, err
+	}
+	if err := r.attachUnavailability(ctx, loaded); err != nil {
+		return nil, err
+	}
+
+	r.cache.putTeams(generation, loaded)
+	return append(teams, loaded...), nil
+}
+
 // Update modifies an existing team
 func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
+	defer r.cache.invalidate()
+
 	query := `
 		UPDATE teams
-		SET name = ?, short_name = ?, city = ?, venue_id = ?, latitude = ?, longitude = ?
+		SET name = ?, short_name = ?, city = ?, venue_id = ?, latitude = ?, longitude = ?,
+			competition_id = ?
 		WHERE id = ?
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
 		team.Name, team.ShortName, team.City, team.VenueID, 
-		team.Latitude, team.Longitude, team.ID)
+		team.Latitude, team.Longitude, team.CompetitionID, team.ID)
 	if err != nil {
 		return fmt.Errorf("updating team: %w", err)
 	}
@@ -203,7 +315,7 @@ func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
 		return fmt.Errorf("getting rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("team not found")
+		return fmt.Errorf("team %d: %w", team.ID, storage.ErrNotFound)
 	}
 
 	return nil
@@ -211,6 +323,8 @@ func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
 
 // Delete removes a team
 func (r *TeamRepository) Delete(ctx context.Context, id int) error {
+	defer r.cache.invalidate()
+
 	query := `DELETE FROM teams WHERE id = ?`
 
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -223,8 +337,183 @@ func (r *TeamRepository) Delete(ctx context.Context, id int) error {
 		return fmt.Errorf("getting rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("team not found")
+		return fmt.Errorf("team %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// SetHomeVenues replaces the venues a team splits its home games across
+func (r *TeamRepository) SetHomeVenues(ctx context.Context, teamID int, venues []models.HomeVenue) error {
+	defer r.cache.invalidate()
+
+	if err := models.ValidateHomeVenues(venues); err != nil {
+		return fmt.Errorf("validating home venues: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM team_home_venues WHERE team_id = ?", teamID); err != nil {
+		return fmt.Errorf("clearing home venues: %w", err)
+	}
+
+	for _, venue := range venues {
+		_, err := r.db.ExecContext(ctx,
+			"INSERT INTO team_home_venues (team_id, venue_id, share) VALUES (?, ?, ?)",
+			teamID, venue.VenueID, venue.Share)
+		if err != nil {
+			return fmt.Errorf("setting home venue: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// attachHomeVenues loads the home venue split of each team
+func (r *TeamRepository) attachHomeVenues(ctx context.Context, teams []*models.Team) error {
+	if len(teams) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.Team, len(teams))
+	for _, team := range teams {
+		byID[team.ID] = team
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT team_id, venue_id, share
+		FROM team_home_venues
+		ORDER BY team_id, share DESC, venue_id
+	`)
+	if err != nil {
+		return fmt.Errorf("listing home venues: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var teamID int
+		var venue models.HomeVenue
+		if err := rows.Scan(&teamID, &venue.VenueID, &venue.Share); err != nil {
+			return fmt.Errorf("scanning home venue: %w", err)
+		}
+		if team, ok := byID[teamID]; ok {
+			team.HomeVenues = append(team.HomeVenues, venue)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating home venues: %w", err)
+	}
+
+	return nil
+}
+// AddUnavailability records a date a team can't play
+func (r *TeamRepository) AddUnavailability(ctx context.Context, unavailability *models.TeamUnavailability) error {
+	defer r.cache.invalidate()
+
+	if err := unavailability.Validate(); err != nil {
+		return fmt.Errorf("validating unavailability: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		"INSERT INTO team_unavailability (team_id, date, reason) VALUES (?, ?, ?)",
+		unavailability.TeamID, unavailability.Date, unavailability.Reason)
+	if err != nil {
+		return fmt.Errorf("adding unavailability: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	unavailability.ID = int(id)
+	unavailability.CreatedAt = time.Now()
+	return nil
+}
+
+// ListUnavailability retrieves the dates a team can't play, earliest first
+func (r *TeamRepository) ListUnavailability(ctx context.Context, teamID int) ([]models.TeamUnavailability, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, team_id, date, COALESCE(reason, ''), created_at
+		FROM team_unavailability
+		WHERE team_id = ?
+		ORDER BY date
+	`, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("listing unavailability: %w", err)
+	}
+	defer rows.Close()
+
+	unavailability := []models.TeamUnavailability{}
+	for rows.Next() {
+		var entry models.TeamUnavailability
+		if err := rows.Scan(&entry.ID, &entry.TeamID, &entry.Date, &entry.Reason, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning unavailability: %w", err)
+		}
+		unavailability = append(unavailability, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating unavailability: %w", err)
+	}
+
+	return unavailability, nil
+}
+
+// DeleteUnavailability removes one of a team's unavailable dates
+func (r *TeamRepository) DeleteUnavailability(ctx context.Context, teamID, id int) error {
+	defer r.cache.invalidate()
+
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM team_unavailability WHERE id = ? AND team_id = ?", id, teamID)
+	if err != nil {
+		return fmt.Errorf("deleting unavailability: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("unavailability %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// attachUnavailability loads the unavailable dates of each team
+func (r *TeamRepository) attachUnavailability(ctx context.Context, teams []*models.Team) error {
+	if len(teams) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.Team, len(teams))
+	for _, team := range teams {
+		byID[team.ID] = team
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, team_id, date, COALESCE(reason, ''), created_at
+		FROM team_unavailability
+		ORDER BY team_id, date
+	`)
+	if err != nil {
+		return fmt.Errorf("listing unavailability: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.TeamUnavailability
+		if err := rows.Scan(&entry.ID, &entry.TeamID, &entry.Date, &entry.Reason, &entry.CreatedAt); err != nil {
+			return fmt.Errorf("scanning unavailability: %w", err)
+		}
+		if team, ok := byID[entry.TeamID]; ok {
+			team.Unavailability = append(team.Unavailability, entry)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating unavailability: %w", err)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
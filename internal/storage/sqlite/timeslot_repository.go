@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// TimeslotRepository implements storage.TimeslotRepository using SQLite
+type TimeslotRepository struct {
+	db DBExecutor
+}
+
+// NewTimeslotRepository creates a new timeslot repository
+func NewTimeslotRepository(db DBExecutor) *TimeslotRepository {
+	return &TimeslotRepository{db: db}
+}
+
+// Create inserts a new timeslot. A competition can only have one slot at
+// each day and kickoff.
+func (r *TimeslotRepository) Create(ctx context.Context, timeslot *models.Timeslot) error {
+	if err := timeslot.Validate(); err != nil {
+		return fmt.Errorf("validating timeslot: %w", err)
+	}
+
+	query := `
+		INSERT INTO timeslots (day_of_week, kickoff, channel, is_prime_time, competition_id)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		int(timeslot.DayOfWeek), timeslot.Kickoff, timeslot.Channel, timeslot.IsPrimeTime, timeslot.CompetitionID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("creating timeslot: %w", storage.ErrConflict)
+		}
+		return fmt.Errorf("creating timeslot: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	timeslot.ID = int(id)
+	timeslot.CreatedAt = time.Now()
+	timeslot.UpdatedAt = timeslot.CreatedAt
+	return nil
+}
+
+// Get retrieves a timeslot by ID
+func (r *TimeslotRepository) Get(ctx context.Context, id int) (*models.Timeslot, error) {
+	query := `
+		SELECT id, day_of_week, kickoff, channel, is_prime_time, competition_id, created_at, updated_at
+		FROM timeslots
+		WHERE id = ?
+	`
+
+	timeslot, err := scanTimeslot(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("timeslot %d: %w", id, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting timeslot: %w", err)
+	}
+
+	return timeslot, nil
+}
+
+// List retrieves the whole catalogue in week order from Sunday, then by kickoff
+func (r *TimeslotRepository) List(ctx context.Context) ([]*models.Timeslot, error) {
+	query := `
+		SELECT id, day_of_week, kickoff, channel, is_prime_time, competition_id, created_at, updated_at
+		FROM timeslots
+		ORDER BY day_of_week, kickoff, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("listing timeslots: %w", err)
+	}
+	defer rows.Close()
+
+	var timeslots []*models.Timeslot
+	for rows.Next() {
+		timeslot, err := scanTimeslot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning timeslot: %w", err)
+		}
+		timeslots = append(timeslots, timeslot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating timeslots: %w", err)
+	}
+
+	return timeslots, nil
+}
+
+// Update modifies an existing timeslot
+func (r *TimeslotRepository) Update(ctx context.Context, timeslot *models.Timeslot) error {
+	if err := timeslot.Validate(); err != nil {
+		return fmt.Errorf("validating timeslot: %w", err)
+	}
+
+	query := `
+		UPDATE timeslots
+		SET day_of_week = ?, kickoff = ?, channel = ?, is_prime_time = ?, competition_id = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		int(timeslot.DayOfWeek), timeslot.Kickoff, timeslot.Channel, timeslot.IsPrimeTime, timeslot.CompetitionID, timeslot.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("updating timeslot: %w", storage.ErrConflict)
+		}
+		return fmt.Errorf("updating timeslot: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("timeslot %d: %w", timeslot.ID, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Delete removes a timeslot
+func (r *TimeslotRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM timeslots WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("deleting timeslot: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("timeslot %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanTimeslot reads a timeslot from a row
+func scanTimeslot(row rowScanner) (*models.Timeslot, error) {
+	timeslot := &models.Timeslot{}
+	var dayOfWeek int
+	var channel sql.NullString
+	err := row.Scan(
+		&timeslot.ID, &dayOfWeek, &timeslot.Kickoff, &channel, &timeslot.IsPrimeTime,
+		&timeslot.CompetitionID, &timeslot.CreatedAt, &timeslot.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	timeslot.DayOfWeek = time.Weekday(dayOfWeek)
+	timeslot.Channel = channel.String
+	return timeslot, nil
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 )
 
 // VenueRepository implements storage.VenueRepository using SQLite
 type VenueRepository struct {
-	db DBExecutor
+	db    DBExecutor
+	cache *relationCache
 }
 
 // NewVenueRepository creates a new venue repository
@@ -54,7 +56,7 @@ func (r *VenueRepository) Get(ctx context.Context, id int) (*models.Venue, error
 		&venue.Latitude, &venue.Longitude, &venue.CreatedAt, &venue.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("venue not found")
+		return nil, fmt.Errorf("venue %d: %w", id, storage.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("getting venue: %w", err)
@@ -63,15 +65,15 @@ func (r *VenueRepository) Get(ctx context.Context, id int) (*models.Venue, error
 	return venue, nil
 }
 
-// List retrieves all venues
-func (r *VenueRepository) List(ctx context.Context) ([]*models.Venue, error) {
-	query := `
-		SELECT id, name, city, capacity, latitude, longitude, created_at, updated_at
-		FROM venues
-		ORDER BY name
-	`
+// List retrieves venues, a page at a time when the options set PerPage
+func (r *VenueRepository) List(ctx context.Context, opts storage.ListOptions) ([]*models.Venue, error) {
+	query, args, err := venueListQuery.list(
+		"id, name, city, capacity, latitude, longitude, created_at, updated_at", opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing venues: %w", err)
+	}
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("listing venues: %w", err)
 	}
@@ -97,8 +99,77 @@ func (r *VenueRepository) List(ctx context.Context) ([]*models.Venue, error) {
 	return venues, nil
 }
 
+// Count returns how many venues match the options' search
+func (r *VenueRepository) Count(ctx context.Context, opts storage.ListOptions) (int, error) {
+	query, args := venueListQuery.count(opts)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting venues: %w", err)
+	}
+	return count, nil
+}
+
+// venueListQuery searches venues by name and city
+var venueListQuery = listQuery{
+	from:   "FROM venues",
+	search: []string{"name", "city"},
+	sortColumns: map[string]string{
+		"id":      "id",
+		"name":    "name",
+		"created": "created_at",
+		"updated": "updated_at",
+	},
+	defaultOrder: "name, id",
+	tieBreak:     "id",
+}
+
+// ListByIDs retrieves the venues with the given IDs in one query, in no
+// particular order. IDs with no venue are skipped. Venues already in the
+// repository's cache aren't loaded again.
+func (r *VenueRepository) ListByIDs(ctx context.Context, ids []int) ([]*models.Venue, error) {
+	venues, missing := r.cache.getVenues(ids)
+	if len(missing) == 0 {
+		return venues, nil
+	}
+	generation := r.cache.currentGeneration()
+
+	placeholders, args := idPlaceholders(missing)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, city, capacity, latitude, longitude, created_at, updated_at
+		FROM venues
+		WHERE id IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing venues by id: %w", err)
+	}
+	defer rows.Close()
+
+	var loaded []*models.Venue
+	for rows.Next() {
+		venue := &models.Venue{}
+		err := rows.Scan(
+			&venue.ID, &venue.Name, &venue.City, &venue.Capacity,
+			&venue.Latitude, &venue.Longitude, &venue.CreatedAt, &venue.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning venue: %w", err)
+		}
+		loaded = append(loaded, venue)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating venues: %w", err)
+	}
+
+	r.cache.putVenues(generation, loaded)
+	return append(venues, loaded...), nil
+}
+
 // Update modifies an existing venue
 func (r *VenueRepository) Update(ctx context.Context, venue *models.Venue) error {
+	defer r.cache.invalidate()
+
 	query := `
 		UPDATE venues
 		SET name = ?, city = ?, capacity = ?, latitude = ?, longitude = ?
@@ -116,7 +187,7 @@ func (r *VenueRepository) Update(ctx context.Context, venue *models.Venue) error
 		return fmt.Errorf("getting rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("venue not found")
+		return fmt.Errorf("venue %d: %w", venue.ID, storage.ErrNotFound)
 	}
 
 	return nil
@@ -124,6 +195,8 @@ func (r *VenueRepository) Update(ctx context.Context, venue *models.Venue) error
 
 // Delete removes a venue
 func (r *VenueRepository) Delete(ctx context.Context, id int) error {
+	defer r.cache.invalidate()
+
 	query := `DELETE FROM venues WHERE id = ?`
 
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -136,7 +209,7 @@ func (r *VenueRepository) Delete(ctx context.Context, id int) error {
 		return fmt.Errorf("getting rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("venue not found")
+		return fmt.Errorf("venue %d: %w", id, storage.ErrNotFound)
 	}
 
 	return nil
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
 )
 
 func TestVenueRepository_Create(t *testing.T) {
@@ -88,7 +89,7 @@ func TestVenueRepository_List(t *testing.T) {
 	ctx := context.Background()
 
 	// Test empty list
-	venues, err := repo.List(ctx)
+	venues, err := repo.List(ctx, storage.ListOptions{})
 	if err != nil {
 		t.Fatalf("List() error = %v", err)
 	}
@@ -111,7 +112,7 @@ func TestVenueRepository_List(t *testing.T) {
 	}
 
 	// List venues
-	venues, err = repo.List(ctx)
+	venues, err = repo.List(ctx, storage.ListOptions{})
 	if err != nil {
 		t.Fatalf("List() error = %v", err)
 	}
@@ -125,6 +126,73 @@ func TestVenueRepository_List(t *testing.T) {
 	}
 }
 
+func TestVenueRepository_ListOptions(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewVenueRepository(db.Conn())
+	ctx := context.Background()
+
+	for _, venue := range []*models.Venue{
+		{Name: "Suncorp Stadium", City: "Brisbane", Capacity: 52500},
+		{Name: "Accor Stadium", City: "Sydney", Capacity: 83500},
+		{Name: "Allianz Stadium", City: "Sydney", Capacity: 42500},
+		{Name: "AAMI Park", City: "Melbourne", Capacity: 30050},
+		{Name: "100% Stadium", City: "Nowhere", Capacity: 1000},
+	} {
+		if err := repo.Create(ctx, venue); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	// Second page of two, by name
+	venues, err := repo.List(ctx, storage.ListOptions{Page: 2, PerPage: 2})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(venues) != 2 || venues[0].Name != "Accor Stadium" || venues[1].Name != "Allianz Stadium" {
+		t.Errorf("List() page 2 = %v, want Accor Stadium and Allianz Stadium", venueNames(venues))
+	}
+
+	// Search matches city case-insensitively and counts every match
+	opts := storage.ListOptions{Page: 1, PerPage: 1, Search: "sydney", SortBy: "name", SortDir: "desc"}
+	venues, err = repo.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(venues) != 1 || venues[0].Name != "Allianz Stadium" {
+		t.Errorf("List() search = %v, want Allianz Stadium", venueNames(venues))
+	}
+	count, err := repo.Count(ctx, opts)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Count() = %d, want 2", count)
+	}
+
+	// Search text is literal, not a LIKE pattern
+	count, err = repo.Count(ctx, storage.ListOptions{Search: "%"})
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Count() for %% = %d, want 1", count)
+	}
+
+	if _, err := repo.List(ctx, storage.ListOptions{SortBy: "capacity"}); err == nil {
+		t.Error("List() should reject an unsupported sort field")
+	}
+}
+
+func venueNames(venues []*models.Venue) []string {
+	names := make([]string, len(venues))
+	for i, venue := range venues {
+		names[i] = venue.Name
+	}
+	return names
+}
+
 func TestVenueRepository_Update(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -0,0 +1,198 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
+	"github.com/adampetrovic/nrl-scheduler/internal/storage"
+)
+
+// WebhookRepository implements storage.WebhookRepository using SQLite
+type WebhookRepository struct {
+	db DBExecutor
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db DBExecutor) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create inserts a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if err := webhook.Validate(); err != nil {
+		return fmt.Errorf("validating webhook: %w", err)
+	}
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("encoding webhook events: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhooks (url, events, secret, description, is_active)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		webhook.URL, string(events), webhook.Secret, webhook.Description, webhook.IsActive)
+	if err != nil {
+		return fmt.Errorf("creating webhook: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+
+	webhook.ID = int(id)
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+	return nil
+}
+
+// Get retrieves a webhook by ID
+func (r *WebhookRepository) Get(ctx context.Context, id int) (*models.Webhook, error) {
+	query := `
+		SELECT id, url, events, secret, description, is_active, last_delivery_at,
+			last_status, last_error, created_at, updated_at
+		FROM webhooks
+		WHERE id = ?
+	`
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook %d: %w", id, storage.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// List retrieves every webhook in ID order
+func (r *WebhookRepository) List(ctx context.Context) ([]*models.Webhook, error) {
+	query := `
+		SELECT id, url, events, secret, description, is_active, last_delivery_at,
+			last_status, last_error, created_at, updated_at
+		FROM webhooks
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// Update modifies an existing webhook's URL, events, secret, description and
+// whether it is active
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	if err := webhook.Validate(); err != nil {
+		return fmt.Errorf("validating webhook: %w", err)
+	}
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("encoding webhook events: %w", err)
+	}
+
+	query := `
+		UPDATE webhooks
+		SET url = ?, events = ?, secret = ?, description = ?, is_active = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		webhook.URL, string(events), webhook.Secret, webhook.Description, webhook.IsActive, webhook.ID)
+	if err != nil {
+		return fmt.Errorf("updating webhook: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("webhook %d: %w", webhook.ID, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// RecordDelivery saves the outcome of a webhook's latest delivery. A status
+// of 0 means no response was received.
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, id int, deliveredAt time.Time, status int, deliveryErr string) error {
+	query := `
+		UPDATE webhooks
+		SET last_delivery_at = ?, last_status = ?, last_error = ?
+		WHERE id = ?
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, deliveredAt, status, deliveryErr, id); err != nil {
+		return fmt.Errorf("recording webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a webhook
+func (r *WebhookRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM webhooks WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("deleting webhook: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("webhook %d: %w", id, storage.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanWebhook reads a webhook from a row
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+	var events string
+	var description, lastError sql.NullString
+	var lastStatus sql.NullInt64
+	err := row.Scan(
+		&webhook.ID, &webhook.URL, &events, &webhook.Secret, &description, &webhook.IsActive,
+		&webhook.LastDeliveryAt, &lastStatus, &lastError, &webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+		return nil, fmt.Errorf("decoding webhook events: %w", err)
+	}
+	webhook.Description = description.String
+	webhook.LastStatus = int(lastStatus.Int64)
+	webhook.LastError = lastError.String
+	return webhook, nil
+}
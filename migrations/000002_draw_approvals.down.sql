@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_draw_approvals_updated_at;
+DROP INDEX IF EXISTS idx_draw_approvals_draw_id;
+DROP TABLE IF EXISTS draw_approvals;
+
+ALTER TABLE draws DROP COLUMN published_version;
+ALTER TABLE draws DROP COLUMN published_at;
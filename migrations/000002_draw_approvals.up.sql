@@ -0,0 +1,26 @@
+-- Publication state for draws
+ALTER TABLE draws ADD COLUMN published_at DATETIME;
+ALTER TABLE draws ADD COLUMN published_version TEXT;
+
+-- Stakeholder sign-off for a draw version
+CREATE TABLE draw_approvals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    draw_id INTEGER NOT NULL,
+    role TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'approved', 'rejected', 'superseded')),
+    draw_version TEXT NOT NULL,
+    requested_by TEXT,
+    approver TEXT,
+    comment TEXT,
+    decided_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (draw_id) REFERENCES draws(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_draw_approvals_draw_id ON draw_approvals(draw_id);
+
+CREATE TRIGGER update_draw_approvals_updated_at AFTER UPDATE ON draw_approvals
+BEGIN
+    UPDATE draw_approvals SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_draw_share_links_draw_id;
+DROP TABLE IF EXISTS draw_share_links;
@@ -0,0 +1,16 @@
+-- Expiring read-only links to a snapshot of a draw
+CREATE TABLE draw_share_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    draw_id INTEGER NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    draw_version TEXT NOT NULL,
+    snapshot TEXT NOT NULL, -- JSON fixture as it was when the link was minted
+    label TEXT,
+    created_by TEXT,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (draw_id) REFERENCES draws(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_draw_share_links_draw_id ON draw_share_links(draw_id);
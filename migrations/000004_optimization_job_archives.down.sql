@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_optimization_job_archives_completed_at;
+DROP INDEX IF EXISTS idx_optimization_job_archives_draw_id;
+DROP TABLE IF EXISTS optimization_job_archives;
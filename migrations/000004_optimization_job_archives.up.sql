@@ -0,0 +1,20 @@
+-- Finished optimization jobs moved out of memory, with gzip-compressed JSON payloads
+CREATE TABLE optimization_job_archives (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL UNIQUE,
+    draw_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    initial_score REAL,
+    final_score REAL,
+    payload BLOB, -- NULL once the retention policy has discarded it
+    payload_size INTEGER NOT NULL DEFAULT 0, -- uncompressed bytes
+    is_best BOOLEAN DEFAULT FALSE, -- best completed result for the draw, kept forever
+    started_at DATETIME NOT NULL,
+    completed_at DATETIME NOT NULL,
+    payload_discarded_at DATETIME,
+    archived_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (draw_id) REFERENCES draws(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_optimization_job_archives_draw_id ON optimization_job_archives(draw_id);
+CREATE INDEX idx_optimization_job_archives_completed_at ON optimization_job_archives(completed_at);
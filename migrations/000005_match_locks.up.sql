@@ -0,0 +1,2 @@
+-- Locked matches are pinned in place; generation and optimization leave them untouched
+ALTER TABLE matches ADD COLUMN locked BOOLEAN DEFAULT FALSE;
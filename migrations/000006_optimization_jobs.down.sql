@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_optimization_jobs_status;
+DROP INDEX IF EXISTS idx_optimization_jobs_draw_id;
+DROP TABLE IF EXISTS optimization_jobs;
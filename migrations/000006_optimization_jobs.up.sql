@@ -0,0 +1,17 @@
+-- Optimization jobs persisted as they run so job history survives restarts
+CREATE TABLE optimization_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL UNIQUE,
+    draw_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    progress TEXT, -- JSON snapshot of the latest progress report
+    result TEXT, -- JSON result, set once the job completes
+    error TEXT,
+    started_at DATETIME NOT NULL,
+    completed_at DATETIME,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (draw_id) REFERENCES draws(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_optimization_jobs_draw_id ON optimization_jobs(draw_id);
+CREATE INDEX idx_optimization_jobs_status ON optimization_jobs(status);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_season_ladders_season;
+DROP TABLE IF EXISTS season_ladders;
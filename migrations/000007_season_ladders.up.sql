@@ -0,0 +1,14 @@
+-- Final ladder positions for a season, used to pick the next season's repeat opponents
+CREATE TABLE season_ladders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    season INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    points INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    UNIQUE (season, team_id),
+    UNIQUE (season, position)
+);
+
+CREATE INDEX idx_season_ladders_season ON season_ladders(season);
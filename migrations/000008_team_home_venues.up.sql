@@ -0,0 +1,13 @@
+-- Home grounds for teams that split home games across several venues
+CREATE TABLE team_home_venues (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL,
+    venue_id INTEGER NOT NULL,
+    share REAL NOT NULL CHECK(share > 0 AND share <= 1), -- target fraction of the team's home games
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE,
+    UNIQUE (team_id, venue_id)
+);
+
+CREATE INDEX idx_team_home_venues_team_id ON team_home_venues(team_id);
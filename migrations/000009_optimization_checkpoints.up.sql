@@ -0,0 +1,12 @@
+-- Latest checkpoint of each optimization job, so long runs can be resumed
+-- after a crash or deploy
+CREATE TABLE optimization_checkpoints (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL UNIQUE,
+    draw_id INTEGER NOT NULL,
+    iteration INTEGER NOT NULL, -- next iteration the resumed run starts from
+    config TEXT NOT NULL, -- JSON optimization config the job was started with
+    state TEXT NOT NULL, -- JSON optimizer state: draws, temperature, counters and RNG position
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (draw_id) REFERENCES draws(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_team_unavailability_team_id;
+DROP TABLE IF EXISTS team_unavailability;
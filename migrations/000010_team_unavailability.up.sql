@@ -0,0 +1,12 @@
+-- Dates teams can't play, applied to every draw's constraints automatically
+CREATE TABLE team_unavailability (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL,
+    date DATE NOT NULL,
+    reason TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    UNIQUE (team_id, date)
+);
+
+CREATE INDEX idx_team_unavailability_team_id ON team_unavailability(team_id);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_competitions_updated_at;
+DROP INDEX IF EXISTS idx_draws_competition_id;
+DROP INDEX IF EXISTS idx_teams_competition_id;
+ALTER TABLE draws DROP COLUMN competition_id;
+ALTER TABLE teams DROP COLUMN competition_id;
+DROP TABLE IF EXISTS competitions;
@@ -0,0 +1,26 @@
+-- Grades played in the same season, such as the NRL, NRLW and reserve grade.
+-- Teams and draws without a competition predate them and are shared.
+CREATE TABLE competitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    code TEXT NOT NULL UNIQUE,
+    constraint_config TEXT, -- default constraints for the competition's draws
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO competitions (name, code) VALUES
+    ('NRL Telstra Premiership', 'NRL'),
+    ('NRL Women''s Premiership', 'NRLW'),
+    ('Reserve Grade', 'RG');
+
+ALTER TABLE teams ADD COLUMN competition_id INTEGER REFERENCES competitions(id) ON DELETE SET NULL;
+ALTER TABLE draws ADD COLUMN competition_id INTEGER REFERENCES competitions(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_teams_competition_id ON teams(competition_id);
+CREATE INDEX idx_draws_competition_id ON draws(competition_id);
+
+CREATE TRIGGER update_competitions_updated_at AFTER UPDATE ON competitions
+BEGIN
+    UPDATE competitions SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
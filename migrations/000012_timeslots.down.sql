@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_timeslots_updated_at;
+DROP INDEX IF EXISTS idx_timeslots_slot;
+DROP TABLE IF EXISTS timeslots;
@@ -0,0 +1,31 @@
+-- The league's weekly kickoff windows. Rounds are dated from the slots of a
+-- draw's competition and the slots shared by every competition, and a match
+-- takes its prime-time flag from its slot.
+CREATE TABLE timeslots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN 0 AND 6), -- 0 is Sunday
+    kickoff TEXT NOT NULL, -- HH:MM
+    channel TEXT,
+    is_prime_time BOOLEAN NOT NULL DEFAULT 0,
+    competition_id INTEGER REFERENCES competitions(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_timeslots_slot ON timeslots(day_of_week, kickoff, IFNULL(competition_id, 0));
+
+-- The standard NRL round, shared until competitions get their own slots
+INSERT INTO timeslots (day_of_week, kickoff, is_prime_time) VALUES
+    (4, '20:00', 1),
+    (5, '18:00', 0),
+    (5, '20:00', 1),
+    (6, '15:00', 0),
+    (6, '17:30', 0),
+    (6, '19:35', 1),
+    (0, '14:00', 0),
+    (0, '16:00', 0);
+
+CREATE TRIGGER update_timeslots_updated_at AFTER UPDATE ON timeslots
+BEGIN
+    UPDATE timeslots SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
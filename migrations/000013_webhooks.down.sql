@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_webhooks_updated_at;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,20 @@
+-- URLs notified of job and draw lifecycle events, with the outcome of the
+-- latest delivery
+CREATE TABLE webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL, -- JSON array of event names
+    secret TEXT NOT NULL, -- HMAC key payloads are signed with
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT 1,
+    last_delivery_at DATETIME,
+    last_status INTEGER,
+    last_error TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_webhooks_updated_at AFTER UPDATE OF url, events, secret, description, is_active ON webhooks
+BEGIN
+    UPDATE webhooks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
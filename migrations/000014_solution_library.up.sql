@@ -0,0 +1,15 @@
+-- Best-scoring draw found for each combination of teams, rounds and
+-- constraint configuration, offered as a warm start to new optimizations
+CREATE TABLE solutions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    solution_key TEXT NOT NULL UNIQUE, -- hash of the teams, rounds and constraint config
+    draw_id INTEGER, -- draw the solution was found for
+    job_id TEXT NOT NULL, -- optimization job that found it
+    score REAL NOT NULL,
+    rounds INTEGER NOT NULL,
+    match_count INTEGER NOT NULL,
+    matches TEXT NOT NULL, -- JSON array of the draw's fixtures
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (draw_id) REFERENCES draws(id) ON DELETE SET NULL
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_matches_byes;
+-- Byes go back to rows recording neither team, one per team on the bye.
+-- Which team had the bye can't be kept, but migrating up again derives it
+-- from the fixtures.
+UPDATE matches SET home_team_id = NULL WHERE home_team_id IS NOT NULL AND away_team_id IS NULL;
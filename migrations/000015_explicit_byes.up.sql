@@ -0,0 +1,27 @@
+-- Byes are stored as match rows naming the team on the bye as home team,
+-- with no away team or venue. Older bye rows recorded neither team, so they
+-- are replaced with one attributed bye per team per round it doesn't play.
+DELETE FROM matches WHERE home_team_id IS NULL AND away_team_id IS NULL;
+
+WITH RECURSIVE draw_rounds(draw_id, round, rounds) AS (
+    SELECT id, 1, rounds FROM draws WHERE rounds > 0
+    UNION ALL
+    SELECT draw_id, round + 1, rounds FROM draw_rounds WHERE round < rounds
+),
+draw_teams(draw_id, team_id) AS (
+    SELECT draw_id, home_team_id FROM matches WHERE home_team_id IS NOT NULL AND away_team_id IS NOT NULL
+    UNION
+    SELECT draw_id, away_team_id FROM matches WHERE home_team_id IS NOT NULL AND away_team_id IS NOT NULL
+)
+INSERT INTO matches (draw_id, round, home_team_id)
+SELECT r.draw_id, r.round, t.team_id
+FROM draw_rounds r
+JOIN draw_teams t ON t.draw_id = r.draw_id
+WHERE NOT EXISTS (
+    SELECT 1 FROM matches m
+    WHERE m.draw_id = r.draw_id AND m.round = r.round
+      AND (m.home_team_id = t.team_id OR m.away_team_id = t.team_id)
+)
+ORDER BY r.draw_id, r.round, t.team_id;
+
+CREATE INDEX idx_matches_byes ON matches(draw_id, round) WHERE away_team_id IS NULL;
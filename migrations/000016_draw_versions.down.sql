@@ -0,0 +1 @@
+ALTER TABLE draws DROP COLUMN version;
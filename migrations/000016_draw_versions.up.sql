@@ -0,0 +1,3 @@
+-- Every update to a draw bumps its version; updates made against an older
+-- version are rejected so concurrent edits can't overwrite each other
+ALTER TABLE draws ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
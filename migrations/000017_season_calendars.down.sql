@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_season_calendars_updated_at;
+DROP INDEX IF EXISTS idx_season_calendars_season;
+DROP TABLE IF EXISTS season_calendars;
@@ -0,0 +1,20 @@
+-- The weekends each round of a season is played over, including split
+-- rounds, the Origin period and finals. Rounds are stored as JSON, earliest
+-- first. Draws use the calendar for their season and competition, or the
+-- shared one when their competition has none.
+CREATE TABLE season_calendars (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    season_year INTEGER NOT NULL,
+    competition_id INTEGER REFERENCES competitions(id) ON DELETE CASCADE,
+    rounds TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_season_calendars_season ON season_calendars(season_year, IFNULL(competition_id, 0));
+
+CREATE TRIGGER update_season_calendars_updated_at AFTER UPDATE ON season_calendars
+BEGIN
+    UPDATE season_calendars SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_constraint_templates_updated_at;
+DROP TABLE IF EXISTS constraint_template_versions;
+DROP TABLE IF EXISTS constraint_templates;
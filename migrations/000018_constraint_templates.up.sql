@@ -0,0 +1,25 @@
+-- Named constraint configurations draws are created from. Changing a
+-- template's configuration bumps its version; every version's configuration
+-- is kept in constraint_template_versions.
+CREATE TABLE constraint_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    version INTEGER NOT NULL DEFAULT 1,
+    config TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE constraint_template_versions (
+    template_id INTEGER NOT NULL REFERENCES constraint_templates(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    config TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (template_id, version)
+);
+
+CREATE TRIGGER update_constraint_templates_updated_at AFTER UPDATE ON constraint_templates
+BEGIN
+    UPDATE constraint_templates SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
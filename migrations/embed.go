@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations so binaries can
+// bootstrap a database without the migration files on disk
+package migrations
+
+import "embed"
+
+// FS holds every up and down migration in this directory
+//
+//go:embed *.sql
+var FS embed.FS
@@ -2,43 +2,221 @@ package types
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/draw"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/ladder"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/models"
 	"github.com/adampetrovic/nrl-scheduler/internal/core/optimizer"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/slots"
 )
 
 // Team API types
 type CreateTeamRequest struct {
-	Name      string  `json:"name" validate:"required,min=1,max=100"`
-	ShortName string  `json:"short_name" validate:"required,min=1,max=3"`
-	City      string  `json:"city" validate:"required,min=1,max=100"`
-	VenueID   *int    `json:"venue_id,omitempty"`
-	Latitude  float64 `json:"latitude" validate:"min=-90,max=90"`
-	Longitude float64 `json:"longitude" validate:"min=-180,max=180"`
+	Name       string             `json:"name" validate:"required,min=1,max=100"`
+	ShortName  string             `json:"short_name" validate:"required,min=1,max=3"`
+	City       string             `json:"city" validate:"required,min=1,max=100"`
+	VenueID    *int               `json:"venue_id,omitempty"`
+	Latitude   float64            `json:"latitude" validate:"min=-90,max=90"`
+	Longitude  float64            `json:"longitude" validate:"min=-180,max=180"`
+	HomeVenues []HomeVenueRequest `json:"home_venues,omitempty" validate:"omitempty,dive"`
+	// CompetitionID is the competition the team plays in, if any
+	CompetitionID *int `json:"competition_id,omitempty" validate:"omitempty,min=1"`
 }
 
+// UpdateTeamRequest changes the fields provided. A home_venues list replaces
+// the team's venue split; an empty list clears it.
 type UpdateTeamRequest struct {
-	Name      *string  `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
-	ShortName *string  `json:"short_name,omitempty" validate:"omitempty,min=1,max=3"`
-	City      *string  `json:"city,omitempty" validate:"omitempty,min=1,max=100"`
-	VenueID   *int     `json:"venue_id,omitempty"`
-	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
-	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+	Name       *string            `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	ShortName  *string            `json:"short_name,omitempty" validate:"omitempty,min=1,max=3"`
+	City       *string            `json:"city,omitempty" validate:"omitempty,min=1,max=100"`
+	VenueID    *int               `json:"venue_id,omitempty"`
+	Latitude   *float64           `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude  *float64           `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+	HomeVenues []HomeVenueRequest `json:"home_venues,omitempty" validate:"omitempty,dive"`
+	// CompetitionID moves the team to another competition
+	CompetitionID *int `json:"competition_id,omitempty" validate:"omitempty,min=1"`
+}
+
+// HomeVenueRequest gives a venue's target share of a team's home games
+type HomeVenueRequest struct {
+	VenueID int     `json:"venue_id" validate:"required,min=1"`
+	Share   float64 `json:"share" validate:"required,gt=0,lte=1"`
+}
+
+// CreateTeamUnavailabilityRequest records a date a team can't play
+type CreateTeamUnavailabilityRequest struct {
+	Date   string `json:"date" validate:"required"` // YYYY-MM-DD
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=200"`
+}
+
+// TeamUnavailabilityResponse is one date a team can't play
+type TeamUnavailabilityResponse struct {
+	ID        int       `json:"id"`
+	TeamID    int       `json:"team_id"`
+	Date      string    `json:"date"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TeamUnavailabilityListResponse lists the dates a team can't play
+type TeamUnavailabilityListResponse struct {
+	TeamID         int                          `json:"team_id"`
+	Unavailability []TeamUnavailabilityResponse `json:"unavailability"`
 }
 
 type TeamResponse struct {
-	ID        int            `json:"id"`
-	Name      string         `json:"name"`
-	ShortName string         `json:"short_name"`
-	City      string         `json:"city"`
-	VenueID   *int           `json:"venue_id"`
-	Venue     *VenueResponse `json:"venue,omitempty"`
-	Latitude  float64        `json:"latitude"`
-	Longitude float64        `json:"longitude"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID         int                `json:"id"`
+	Name       string             `json:"name"`
+	ShortName  string             `json:"short_name"`
+	City       string             `json:"city"`
+	VenueID    *int               `json:"venue_id"`
+	Venue      *VenueResponse     `json:"venue,omitempty"`
+	Latitude   float64            `json:"latitude"`
+	Longitude  float64            `json:"longitude"`
+	HomeVenues []models.HomeVenue `json:"home_venues,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	// CompetitionID is nil for teams without a competition
+	CompetitionID *int `json:"competition_id,omitempty"`
+}
+
+// Competition API types
+type CreateCompetitionRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	Code string `json:"code" validate:"required,min=1,max=10"`
+	// ConstraintConfig is the default for the competition's draws
+	ConstraintConfig *constraints.ConstraintConfig `json:"constraint_config,omitempty"`
+}
+
+type UpdateCompetitionRequest struct {
+	Name             *string                       `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Code             *string                       `json:"code,omitempty" validate:"omitempty,min=1,max=10"`
+	ConstraintConfig *constraints.ConstraintConfig `json:"constraint_config,omitempty"`
+}
+
+type CompetitionResponse struct {
+	ID               int         `json:"id"`
+	Name             string      `json:"name"`
+	Code             string      `json:"code"`
+	ConstraintConfig interface{} `json:"constraint_config,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// Timeslot catalogue API types
+type CreateTimeslotRequest struct {
+	Day       string `json:"day" validate:"required"`     // e.g. "friday" or "fri"
+	Kickoff   string `json:"kickoff" validate:"required"` // HH:MM
+	Channel   string `json:"channel,omitempty" validate:"omitempty,max=50"`
+	PrimeTime *bool  `json:"is_prime_time,omitempty"` // defaults to Thursday-Saturday from 7pm
+	// CompetitionID limits the slot to one competition's draws
+	CompetitionID *int `json:"competition_id,omitempty" validate:"omitempty,min=1"`
+}
+
+type UpdateTimeslotRequest struct {
+	Day           *string `json:"day,omitempty"`
+	Kickoff       *string `json:"kickoff,omitempty"`
+	Channel       *string `json:"channel,omitempty" validate:"omitempty,max=50"`
+	PrimeTime     *bool   `json:"is_prime_time,omitempty"`
+	CompetitionID *int    `json:"competition_id,omitempty" validate:"omitempty,min=1"`
+}
+
+type TimeslotResponse struct {
+	ID            int       `json:"id"`
+	Day           string    `json:"day"`
+	Kickoff       string    `json:"kickoff"`
+	Channel       string    `json:"channel,omitempty"`
+	IsPrimeTime   bool      `json:"is_prime_time"`
+	CompetitionID *int      `json:"competition_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Season calendar API types
+type CalendarRoundRequest struct {
+	Round     int    `json:"round" validate:"required,min=1"`
+	StartDate string `json:"start_date" validate:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date" validate:"required"`   // YYYY-MM-DD
+	Kind      string `json:"kind,omitempty"`                 // regular, split, origin or finals; defaults to regular
+	Label     string `json:"label,omitempty" validate:"omitempty,max=100"`
+}
+
+type CreateSeasonCalendarRequest struct {
+	Name       string `json:"name" validate:"required,min=1,max=100"`
+	SeasonYear int    `json:"season_year" validate:"required,min=2000,max=2100"`
+	// CompetitionID limits the calendar to one competition's draws
+	CompetitionID *int                   `json:"competition_id,omitempty" validate:"omitempty,min=1"`
+	Rounds        []CalendarRoundRequest `json:"rounds" validate:"required,min=1,dive"`
+}
+
+type UpdateSeasonCalendarRequest struct {
+	Name          *string                `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	SeasonYear    *int                   `json:"season_year,omitempty" validate:"omitempty,min=2000,max=2100"`
+	CompetitionID *int                   `json:"competition_id,omitempty" validate:"omitempty,min=1"`
+	Rounds        []CalendarRoundRequest `json:"rounds,omitempty" validate:"omitempty,min=1,dive"`
+}
+
+type CalendarRoundResponse struct {
+	Round     int    `json:"round"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Kind      string `json:"kind"`
+	Label     string `json:"label,omitempty"`
+}
+
+type SeasonCalendarResponse struct {
+	ID            int                     `json:"id"`
+	Name          string                  `json:"name"`
+	SeasonYear    int                     `json:"season_year"`
+	CompetitionID *int                    `json:"competition_id,omitempty"`
+	Rounds        []CalendarRoundResponse `json:"rounds"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// Constraint template API types
+type CreateConstraintTemplateRequest struct {
+	Name             string                        `json:"name" validate:"required,min=1,max=100"`
+	Description      string                        `json:"description,omitempty" validate:"max=500"`
+	ConstraintConfig *constraints.ConstraintConfig `json:"constraint_config" validate:"required"`
+}
+
+// UpdateConstraintTemplateRequest changes the fields provided. A new
+// constraint_config becomes the template's next version.
+type UpdateConstraintTemplateRequest struct {
+	Name             *string                       `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description      *string                       `json:"description,omitempty" validate:"omitempty,max=500"`
+	ConstraintConfig *constraints.ConstraintConfig `json:"constraint_config,omitempty"`
+}
+
+type ConstraintTemplateResponse struct {
+	ID               int         `json:"id"`
+	Name             string      `json:"name"`
+	Description      string      `json:"description,omitempty"`
+	Version          int         `json:"version"`
+	ConstraintConfig interface{} `json:"constraint_config"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+type ConstraintTemplateVersionResponse struct {
+	TemplateID       int         `json:"template_id"`
+	Version          int         `json:"version"`
+	ConstraintConfig interface{} `json:"constraint_config"`
+	CreatedAt        time.Time   `json:"created_at"`
+}
+
+// CreateDrawFromTemplateRequest creates a draft draw with a template's
+// configuration, at its latest version unless one is given
+type CreateDrawFromTemplateRequest struct {
+	Name          string `json:"name" validate:"required,min=1,max=100"`
+	SeasonYear    int    `json:"season_year" validate:"required,min=2000,max=2100"`
+	Rounds        int    `json:"rounds" validate:"required,min=1,max=52"`
+	CompetitionID *int   `json:"competition_id,omitempty" validate:"omitempty,min=1"`
+	Version       *int   `json:"version,omitempty" validate:"omitempty,min=1"`
 }
 
 // Venue API types
@@ -75,6 +253,8 @@ type CreateDrawRequest struct {
 	SeasonYear       int                          `json:"season_year" validate:"required,min=2000,max=2100"`
 	Rounds           int                          `json:"rounds" validate:"required,min=1,max=52"`
 	ConstraintConfig *constraints.ConstraintConfig `json:"constraint_config,omitempty"`
+	// CompetitionID limits the draw to one competition's teams
+	CompetitionID *int `json:"competition_id,omitempty" validate:"omitempty,min=1"`
 }
 
 type UpdateDrawRequest struct {
@@ -82,6 +262,17 @@ type UpdateDrawRequest struct {
 	SeasonYear       *int                          `json:"season_year,omitempty" validate:"omitempty,min=2000,max=2100"`
 	Rounds           *int                          `json:"rounds,omitempty" validate:"omitempty,min=1,max=52"`
 	ConstraintConfig *constraints.ConstraintConfig `json:"constraint_config,omitempty"`
+	CompetitionID    *int                          `json:"competition_id,omitempty" validate:"omitempty,min=1"`
+	// Version is the draw version the change is made against. When set, the
+	// update is rejected if the draw has been updated since.
+	Version          *int                          `json:"version,omitempty" validate:"omitempty,min=1"`
+}
+
+// CloneDrawRequest optionally renames the copy and gives it a different
+// constraint configuration to experiment with
+type CloneDrawRequest struct {
+	Name             *string                       `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	ConstraintConfig *constraints.ConstraintConfig `json:"constraint_config,omitempty"`
 }
 
 type DrawResponse struct {
@@ -92,6 +283,9 @@ type DrawResponse struct {
 	Status           string            `json:"status"`
 	ConstraintConfig interface{}       `json:"constraint_config,omitempty"`
 	MatchCount       int               `json:"match_count"`
+	PublishedAt      *time.Time        `json:"published_at,omitempty"`
+	CompetitionID    *int              `json:"competition_id,omitempty"`
+	Version          int               `json:"version"`
 	CreatedAt        time.Time         `json:"created_at"`
 	UpdatedAt        time.Time         `json:"updated_at"`
 }
@@ -106,10 +300,60 @@ type MatchResponse struct {
 	Venue       *VenueResponse  `json:"venue,omitempty"`
 	ScheduledAt *time.Time      `json:"scheduled_at,omitempty"`
 	IsBye       bool            `json:"is_bye"`
+	Locked      bool            `json:"locked"`
 	Created     time.Time       `json:"created"`
 	Updated     time.Time       `json:"updated"`
 }
 
+// TeamScheduleResponse is one team's season in round order, byes included
+type TeamScheduleResponse struct {
+	DrawID int                 `json:"draw_id"`
+	TeamID int                 `json:"team_id"`
+	Rounds []TeamRoundResponse `json:"rounds"`
+}
+
+// TeamRoundResponse is one round of a team's season, seen from its side.
+// Bye rounds only carry the round and bye flag.
+type TeamRoundResponse struct {
+	Round           int            `json:"round"`
+	IsBye           bool           `json:"is_bye"`
+	MatchID         int            `json:"match_id,omitempty"`
+	Opponent        *TeamResponse  `json:"opponent,omitempty"`
+	Home            bool           `json:"home"`
+	Venue           *VenueResponse `json:"venue,omitempty"`
+	ScheduledAt     *time.Time     `json:"scheduled_at,omitempty"`
+	IsPrimeTime     bool           `json:"is_prime_time"`
+	RestDays        *int           `json:"rest_days,omitempty"`
+	ConsecutiveAway int            `json:"consecutive_away"`
+}
+
+// RoundResponse is one round of a draw with its matches and round-level
+// figures. RegionSpread holds the round's standing against each of the
+// draw's region spread constraints.
+type RoundResponse struct {
+	DrawID           int                              `json:"draw_id"`
+	Round            int                              `json:"round"`
+	Matches          []MatchResponse                  `json:"matches"`
+	ByeTeams         []TeamResponse                   `json:"bye_teams"`
+	PrimeTimeMatches int                              `json:"prime_time_matches"`
+	Venues           []VenueResponse                  `json:"venues"`
+	RegionSpread     []constraints.RegionSpreadReport `json:"region_spread,omitempty"`
+}
+
+// AddDrawTeamRequest names the team to add to a generated draw
+type AddDrawTeamRequest struct {
+	TeamID int `json:"team_id" validate:"required,min=1"`
+}
+
+// DrawTeamChangeResponse lists the matches changed by adding or removing a team
+type DrawTeamChangeResponse struct {
+	DrawID  int             `json:"draw_id"`
+	TeamID  int             `json:"team_id"`
+	Created []MatchResponse `json:"created"`
+	Updated []MatchResponse `json:"updated"`
+	Deleted []MatchResponse `json:"deleted"`
+}
+
 // Draw generation types
 type GenerateDrawRequest struct {
 	Constraints *constraints.ConstraintConfig `json:"constraints,omitempty"`
@@ -120,15 +364,26 @@ type GenerationOptions struct {
 	Seed           *int64 `json:"seed,omitempty"`
 	MaxAttempts    *int   `json:"max_attempts,omitempty"`
 	ValidateAfter  *bool  `json:"validate_after,omitempty"`
+	// SeasonStart (YYYY-MM-DD) dates the generated matches into the standard
+	// NRL timeslots, with round 1 in the week starting that day
+	SeasonStart    string `json:"season_start,omitempty"`
+	// FixtureTemplate plays every opponent once plus repeat opponents from
+	// ladder pools, instead of a plain round-robin
+	FixtureTemplate *draw.FixtureTemplate `json:"fixture_template,omitempty"`
+	// PinnedFixtures fix matchups to rounds, such as the Anzac Day match;
+	// the rest of the draw is built around them
+	PinnedFixtures []draw.PinnedFixture `json:"pinned_fixtures,omitempty"`
 }
 
 type GenerateDrawResponse struct {
 	Success        bool                       `json:"success"`
 	MatchCount     int                        `json:"match_count"`
 	Violations     []ConstraintViolation      `json:"violations,omitempty"`
+	Warnings       []constraints.ConfigConflict `json:"warnings,omitempty"`
 	Message        string                     `json:"message"`
 	GeneratedAt    time.Time                  `json:"generated_at"`
 	GenerationTime time.Duration              `json:"generation_time"`
+	Seed           int64                      `json:"seed"` // Pass back in options.seed to regenerate the same draw
 }
 
 // Constraint validation types
@@ -143,12 +398,369 @@ type ValidateConstraintsResponse struct {
 }
 
 type ConstraintViolation struct {
-	Type        string            `json:"type"`
-	Severity    string            `json:"severity"` // "hard" or "soft"
-	Description string            `json:"description"`
-	MatchID     *int              `json:"match_id,omitempty"`
-	Round       *int              `json:"round,omitempty"`
-	Details     map[string]interface{} `json:"details,omitempty"`
+	Type           string            `json:"type"`
+	ConstraintType string            `json:"constraint_type,omitempty"` // configuration type, e.g. "double_up"
+	Severity       string            `json:"severity"` // "hard", "soft" or "warning"
+	Description    string            `json:"description"`
+	MatchID        *int              `json:"match_id,omitempty"`
+	Round          *int              `json:"round,omitempty"`
+	Locked         bool              `json:"locked,omitempty"` // the match is locked, so only a manual change can fix it
+	MatchIDs       []int             `json:"match_ids,omitempty"`
+	Rounds         []int             `json:"rounds,omitempty"`
+	TeamIDs        []int             `json:"team_ids,omitempty"`
+	Remediation    string            `json:"remediation,omitempty"`
+	Details        map[string]interface{} `json:"details,omitempty"`
+}
+
+// Constraint type catalogue types
+type ConstraintTypeResponse struct {
+	Name            string                 `json:"name"`
+	Type            string                 `json:"type"` // "hard" or "soft"
+	Description     string                 `json:"description"`
+	Parameters      map[string]string      `json:"parameters"`
+	DefaultParams   map[string]interface{} `json:"default_params"`
+	DefaultWeight   *float64               `json:"default_weight,omitempty"`
+	InDefaultConfig bool                   `json:"in_default_config"`
+}
+
+type ConstraintTypesResponse struct {
+	Types []ConstraintTypeResponse `json:"types"`
+}
+
+// ConstraintSchemaResponse holds JSON Schemas for a whole constraint
+// configuration and for each constraint type's params
+type ConstraintSchemaResponse struct {
+	Config *constraints.JSONSchema                     `json:"config"`
+	Types  map[string]constraints.ConstraintTypeSchema `json:"types"`
+}
+
+// Constraint configuration validation types
+type ValidateConstraintConfigRequest struct {
+	Constraints constraints.ConstraintConfig `json:"constraints"`
+	Teams       int                          `json:"teams" validate:"min=0,max=64"`
+	Rounds      int                          `json:"rounds" validate:"min=0,max=52"`
+}
+
+type ValidateConstraintConfigResponse struct {
+	Valid     bool                         `json:"valid"`
+	Error     string                       `json:"error,omitempty"`
+	Conflicts []constraints.ConfigConflict `json:"conflicts"`
+	// Errors and Warnings hold the conflicts and param problems by severity
+	Errors   []constraints.ConfigConflict `json:"errors"`
+	Warnings []constraints.ConfigConflict `json:"warnings"`
+}
+
+// SimulateConstraintsRequest scores a constraint configuration on random
+// draws for a league of the given size. Samples defaults to 20.
+type SimulateConstraintsRequest struct {
+	Constraints constraints.ConstraintConfig `json:"constraints"`
+	Teams       int                          `json:"teams" validate:"required,min=2,max=32"`
+	Rounds      int                          `json:"rounds" validate:"required,min=1,max=52"`
+	Samples     int                          `json:"samples" validate:"min=0,max=200"`
+	Seed        *int64                       `json:"seed,omitempty"`
+}
+
+// Constraint timeline types
+type ConstraintTimelineResponse struct {
+	DrawID              int                         `json:"draw_id"`
+	Rounds              []constraints.RoundTimeline `json:"rounds"`
+	DrawLevelViolations int                         `json:"draw_level_violations"`
+	TotalSoftPenalty    float64                     `json:"total_soft_penalty"`
+}
+
+// Draw statistics types
+type DrawStatisticsResponse struct {
+	DrawID     int                        `json:"draw_id"`
+	Score      float64                    `json:"score"`
+	Statistics constraints.DrawStatistics `json:"statistics"`
+}
+
+// Broadcaster quota types
+type BroadcasterQuotaReportResponse struct {
+	DrawID int                                  `json:"draw_id"`
+	Quotas []constraints.BroadcasterQuotaReport `json:"quotas"`
+}
+
+// Derby types
+type DerbyReportResponse struct {
+	DrawID  int                         `json:"draw_id"`
+	Derbies []constraints.DerbyAnalysis `json:"derbies"`
+}
+
+// Travel fatigue types
+type TravelFatigueReportResponse struct {
+	DrawID                 int                         `json:"draw_id"`
+	MaxConsecutiveLongHaul int                         `json:"max_consecutive_long_haul"`
+	Teams                  []constraints.TeamItinerary `json:"teams"`
+}
+
+// Travel cost types
+type TravelCostReportResponse struct {
+	DrawID                 int                          `json:"draw_id"`
+	Model                  constraints.TravelCostModel  `json:"model"`
+	Budget                 float64                      `json:"budget,omitempty"`
+	TotalTravelCost        float64                      `json:"total_travel_cost"`
+	TotalAccommodationCost float64                      `json:"total_accommodation_cost"`
+	TotalCost              float64                      `json:"total_cost"`
+	AverageTeamCost        float64                      `json:"average_team_cost"`
+	Teams                  []constraints.TeamTravelCost `json:"teams"`
+}
+
+type TeamTravelCostResponse struct {
+	DrawID int                         `json:"draw_id"`
+	Model  constraints.TravelCostModel `json:"model"`
+	Team   constraints.TeamTravelCost  `json:"team"`
+}
+
+// Region spread types
+type RegionSpreadReportResponse struct {
+	DrawID  int                              `json:"draw_id"`
+	Spreads []constraints.RegionSpreadReport `json:"spreads"`
+}
+
+// Venue substitution types
+type VenueSubstituteResponse struct {
+	Venue            VenueResponse `json:"venue"`
+	Available        bool          `json:"available"`
+	Conflicts        []string      `json:"conflicts,omitempty"`
+	CapacityFit      float64       `json:"capacity_fit"`
+	HomeDistanceKm   *float64      `json:"home_distance_km,omitempty"`
+	AwayDistanceKm   *float64      `json:"away_distance_km,omitempty"`
+	DistanceScore    float64       `json:"distance_score"`
+	ConstraintImpact float64       `json:"constraint_impact"`
+	Score            float64       `json:"score"`
+}
+
+type VenueSubstitutesResponse struct {
+	MatchID         int                       `json:"match_id"`
+	CurrentVenueID  *int                      `json:"current_venue_id"`
+	Candidates      []VenueSubstituteResponse `json:"candidates"`
+}
+
+type ApplyVenueSubstitutionRequest struct {
+	VenueID int `json:"venue_id" validate:"required,min=1"`
+}
+
+// PostponeMatchRequest limits how many replacement options are returned
+type PostponeMatchRequest struct {
+	Limit int `json:"limit,omitempty" validate:"omitempty,min=0"`
+}
+
+// PostponementOptionResponse is a ranked round and date to replay a postponed match
+type PostponementOptionResponse struct {
+	Round            int        `json:"round"`
+	MatchDate        *time.Time `json:"match_date,omitempty"`
+	Available        bool       `json:"available"`
+	Conflicts        []string   `json:"conflicts,omitempty"`
+	DelayDays        int        `json:"delay_days"`
+	ConstraintImpact float64    `json:"constraint_impact"`
+	Score            float64    `json:"score"`
+}
+
+// PostponeMatchResponse is a postponed match and where it could be replayed
+type PostponeMatchResponse struct {
+	Match   MatchResponse                `json:"match"`
+	Options []PostponementOptionResponse `json:"options"`
+}
+
+// LockMatchRequest pins a match in place or releases it
+type LockMatchRequest struct {
+	Locked *bool `json:"locked" validate:"required"`
+}
+
+// UpdateMatchRequest edits a match by hand. Omitted fields are left unchanged.
+type UpdateMatchRequest struct {
+	Round      *int    `json:"round,omitempty" validate:"omitempty,min=1"`
+	HomeTeamID *int    `json:"home_team_id,omitempty" validate:"omitempty,min=1"`
+	AwayTeamID *int    `json:"away_team_id,omitempty" validate:"omitempty,min=1"`
+	VenueID    *int    `json:"venue_id,omitempty" validate:"omitempty,min=1"`
+	Date       *string `json:"date,omitempty"`       // YYYY-MM-DD
+	Time       *string `json:"time,omitempty"`       // HH:MM
+	PrimeTime  *bool   `json:"prime_time,omitempty"`
+	// Force saves the change even when it breaks hard constraints
+	Force bool `json:"force,omitempty"`
+}
+
+// MatchChangeResponse previews or reports a manual match edit: the match as
+// edited and the constraint violations and score change the edit causes
+type MatchChangeResponse struct {
+	Match              MatchResponse         `json:"match"`
+	DryRun             bool                  `json:"dry_run"`
+	Applied            bool                  `json:"applied"`
+	Conflicts          []string              `json:"conflicts,omitempty"`
+	NewViolations      []ConstraintViolation `json:"new_violations"`
+	ResolvedViolations []ConstraintViolation `json:"resolved_violations"`
+	ScoreBefore        float64               `json:"score_before"`
+	ScoreAfter         float64               `json:"score_after"`
+	ScoreChange        float64               `json:"score_change"`
+}
+
+// Kickoff slot assignment types
+type SlotRequest struct {
+	Date        string `json:"date" validate:"required"`           // YYYY-MM-DD
+	Time        string `json:"time,omitempty"`                     // HH:MM
+	PrimeTime   *bool  `json:"prime_time,omitempty"`              // defaults to the timeslot catalogue's flag
+	Broadcaster string `json:"broadcaster,omitempty" validate:"omitempty,max=50"`
+}
+
+type RoundSlotsRequest struct {
+	Round int           `json:"round" validate:"required,min=1"`
+	Slots []SlotRequest `json:"slots" validate:"required,min=1,dive"`
+}
+
+type BroadcasterQuotaRequest struct {
+	Broadcaster string `json:"broadcaster" validate:"required,max=50"`
+	MinPerTeam  int    `json:"min_per_team,omitempty" validate:"min=0"`
+	MaxPerTeam  int    `json:"max_per_team,omitempty" validate:"min=0"`
+}
+
+type AssignSlotsRequest struct {
+	Rounds            []RoundSlotsRequest       `json:"rounds" validate:"required,min=1,dive"`
+	BroadcasterQuotas []BroadcasterQuotaRequest `json:"broadcaster_quotas,omitempty" validate:"omitempty,dive"`
+	DryRun            bool                      `json:"dry_run,omitempty"`
+}
+
+type SlotAssignmentResponse struct {
+	MatchID     int    `json:"match_id"`
+	Round       int    `json:"round"`
+	Date        string `json:"date"`
+	Time        string `json:"time,omitempty"`
+	IsPrimeTime bool   `json:"is_prime_time"`
+	Broadcaster string `json:"broadcaster,omitempty"`
+}
+
+// TimeslotTemplateRequest is a weekly kickoff window repeated in each round
+type TimeslotTemplateRequest struct {
+	Day         string `json:"day" validate:"required"`  // e.g. "friday" or "fri"
+	Time        string `json:"time" validate:"required"` // HH:MM
+	PrimeTime   *bool  `json:"prime_time,omitempty"`     // defaults to Thursday-Saturday from 7pm
+	Broadcaster string `json:"broadcaster,omitempty" validate:"omitempty,max=50"`
+}
+
+type RoundTemplateRequest struct {
+	Round int                       `json:"round" validate:"required,min=1"`
+	Slots []TimeslotTemplateRequest `json:"slots" validate:"required,min=1,dive"`
+}
+
+// ScheduleTimeslotsRequest dates a draw from weekly timeslot templates. Without
+// a template the timeslot catalogue is used, falling back to the standard NRL
+// round when the catalogue is empty. Without a season start, rounds are dated
+// from the season calendar for the draw's season.
+type ScheduleTimeslotsRequest struct {
+	SeasonStart       string                    `json:"season_start,omitempty"` // YYYY-MM-DD, the start of round 1's week
+	Template          []TimeslotTemplateRequest `json:"template,omitempty" validate:"omitempty,dive"`
+	RoundTemplates    []RoundTemplateRequest    `json:"round_templates,omitempty" validate:"omitempty,dive"`
+	BroadcasterQuotas []BroadcasterQuotaRequest `json:"broadcaster_quotas,omitempty" validate:"omitempty,dive"`
+	DryRun            bool                      `json:"dry_run,omitempty"`
+}
+
+type AssignSlotsResponse struct {
+	DrawID          int                      `json:"draw_id"`
+	DryRun          bool                     `json:"dry_run"`
+	ScoreBefore     float64                  `json:"score_before"`
+	ScoreAfter      float64                  `json:"score_after"`
+	HardViolations  int                      `json:"hard_violations"`
+	Assignments     []SlotAssignmentResponse `json:"assignments"`
+	QuotaShortfalls []slots.QuotaShortfall   `json:"quota_shortfalls"`
+	Rounds          []models.RoundProfile    `json:"rounds"`
+}
+
+// Share link types
+type CreateShareLinkRequest struct {
+	TTLHours  int    `json:"ttl_hours,omitempty" validate:"omitempty,min=1,max=2160"`
+	Label     string `json:"label,omitempty" validate:"omitempty,max=100"`
+	CreatedBy string `json:"created_by,omitempty" validate:"omitempty,max=100"`
+}
+
+type ShareLinkResponse struct {
+	ID          int        `json:"id"`
+	DrawID      int        `json:"draw_id"`
+	Token       string     `json:"token"`
+	URL         string     `json:"url"`
+	DrawVersion string     `json:"draw_version"`
+	Label       string     `json:"label,omitempty"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	Active      bool       `json:"active"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Webhook types
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=2000"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=optimization.completed draw.generated constraints.validated approval.requested approval.recorded draw.published"`
+	// Secret signs deliveries; one is generated when it's omitted
+	Secret      string `json:"secret,omitempty" validate:"omitempty,min=16,max=200"`
+	Description string `json:"description,omitempty" validate:"omitempty,max=200"`
+	IsActive    *bool  `json:"is_active,omitempty"` // defaults to true
+}
+
+// UpdateWebhookRequest changes the fields provided. An events list replaces
+// the webhook's subscriptions.
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url,omitempty" validate:"omitempty,url,max=2000"`
+	Events      []string `json:"events,omitempty" validate:"omitempty,min=1,dive,oneof=optimization.completed draw.generated constraints.validated approval.requested approval.recorded draw.published"`
+	Secret      *string  `json:"secret,omitempty" validate:"omitempty,min=16,max=200"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=200"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+type WebhookResponse struct {
+	ID          int      `json:"id"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Description string   `json:"description,omitempty"`
+	IsActive    bool     `json:"is_active"`
+	// Secret is only returned when it is set, so it can be given to the receiver
+	Secret         string     `json:"secret,omitempty"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastStatus     int        `json:"last_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Approval workflow types
+type RequestApprovalsRequest struct {
+	Roles       []string `json:"roles,omitempty" validate:"omitempty,dive,oneof=broadcast clubs operations"`
+	RequestedBy string   `json:"requested_by,omitempty" validate:"omitempty,max=100"`
+}
+
+type RecordApprovalRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=approve reject"`
+	Approver string `json:"approver" validate:"required,min=1,max=100"`
+	Comment  string `json:"comment,omitempty" validate:"omitempty,max=1000"`
+}
+
+// Season ladder types
+type LadderEntryRequest struct {
+	TeamID   int `json:"team_id" validate:"required,min=1"`
+	Position int `json:"position" validate:"required,min=1"`
+	Points   int `json:"points" validate:"min=0"`
+}
+
+type ImportLadderRequest struct {
+	Entries []LadderEntryRequest `json:"entries" validate:"required,min=2,dive"`
+}
+
+type LadderResponse struct {
+	Season  int                   `json:"season"`
+	Entries []*models.LadderEntry `json:"entries"`
+}
+
+// RepeatMatchupsRequest derives who plays whom twice from a season's ladder.
+// Rules are applied in order before the remaining repeats are filled.
+type RepeatMatchupsRequest struct {
+	RepeatOpponents int                 `json:"repeat_opponents" validate:"min=0,max=100"`
+	Rules           []ladder.RepeatRule `json:"rules,omitempty"`
+}
+
+// RepeatMatchupsResponse returns the opponent matrix with the fixture template
+// to pass to draw generation
+type RepeatMatchupsResponse struct {
+	*ladder.OpponentMatrix
+	FixtureTemplate draw.FixtureTemplate `json:"fixture_template"`
 }
 
 // Optimization API types
@@ -164,10 +776,74 @@ type TemperatureScheduleRequest struct {
 }
 
 type StartOptimizationRequest struct {
+	Algorithm       string                      `json:"algorithm,omitempty" validate:"omitempty,oneof=simulated_annealing tabu_search"`
 	Temperature     float64                     `json:"temperature" validate:"required,min=0.1,max=1000"`
 	CoolingRate     float64                     `json:"cooling_rate" validate:"required,min=0.1,max=0.999"`
 	MaxIterations   int                         `json:"max_iterations" validate:"required,min=100,max=1000000"`
 	CoolingSchedule *TemperatureScheduleRequest `json:"cooling_schedule,omitempty"`
+	StabilityWeight *float64                    `json:"stability_weight,omitempty" validate:"omitempty,min=0,max=1"`
+	TimeBudgetSeconds int                       `json:"time_budget_seconds,omitempty" validate:"omitempty,min=1,max=86400"`
+	Seed            *int64                      `json:"seed,omitempty"`
+	MultiStart      *MultiStartRequest          `json:"multi_start,omitempty"`
+	Tabu            *TabuRequest                `json:"tabu,omitempty"`
+	Rounds          *RoundWindowRequest         `json:"rounds,omitempty"`
+	EarlyStopping   *EarlyStoppingRequest       `json:"early_stopping,omitempty"`
+	// WarmStart starts from the solution library's best draw for the same
+	// teams, rounds and constraints, when it has one
+	WarmStart       bool                        `json:"warm_start,omitempty"`
+	// CheckpointInterval is how many iterations run between checkpoints the
+	// job can be resumed from
+	CheckpointInterval int                      `json:"checkpoint_interval,omitempty" validate:"omitempty,min=1"`
+	// HistoryInterval is how many iterations pass between samples of the
+	// score trajectory served by the history endpoint
+	HistoryInterval int                         `json:"history_interval,omitempty" validate:"omitempty,min=1"`
+	// OperatorWeights is the probability of picking each neighbourhood
+	// operator, e.g. {"swap_matches": 0.4, "swap_home_away": 0.1, ...}, and
+	// must sum to 1. Operators are picked equally when omitted.
+	OperatorWeights map[string]float64          `json:"operator_weights,omitempty"`
+	// Objectives runs a multi-objective search over any of "travel",
+	// "fairness" and "broadcast", returning a Pareto front of up to
+	// archive_size draws trading them off
+	Objectives      []string                    `json:"objectives,omitempty" validate:"omitempty,dive,oneof=travel fairness broadcast"`
+	ArchiveSize     int                         `json:"archive_size,omitempty" validate:"omitempty,min=1,max=200"`
+}
+
+// RoundWindowRequest re-optimizes only rounds from_round to to_round,
+// keeping the rest of the draw fixed
+type RoundWindowRequest struct {
+	FromRound int `json:"from_round" validate:"required,min=1"`
+	ToRound   int `json:"to_round" validate:"required,min=1"`
+}
+
+// EarlyStoppingRequest ends a run before its iteration or time budget once
+// it goes no_improvement_iterations without a new best score, or its best
+// score reaches target_score
+type EarlyStoppingRequest struct {
+	NoImprovementIterations int      `json:"no_improvement_iterations,omitempty" validate:"omitempty,min=1"`
+	TargetScore             *float64 `json:"target_score,omitempty" validate:"omitempty,min=0,max=1"`
+}
+
+// TabuRequest tunes tabu search; unset fields use the defaults
+type TabuRequest struct {
+	Tenure           int `json:"tenure,omitempty" validate:"omitempty,min=1,max=1000"`
+	NeighborhoodSize int `json:"neighborhood_size,omitempty" validate:"omitempty,min=1,max=500"`
+}
+
+// MultiStartRequest runs several optimizations concurrently from different
+// random starts, keeping the best
+type MultiStartRequest struct {
+	Starts  int `json:"starts" validate:"required,min=2,max=64"`
+	Workers int `json:"workers,omitempty" validate:"omitempty,min=1,max=64"`
+}
+
+// TuneWeightsRequest runs short optimizations across sampled soft-constraint
+// weights; unset fields use the defaults
+type TuneWeightsRequest struct {
+	Strategy   string    `json:"strategy,omitempty" validate:"omitempty,oneof=grid random"`
+	Samples    int       `json:"samples,omitempty" validate:"omitempty,min=1,max=100"`
+	Levels     []float64 `json:"levels,omitempty" validate:"omitempty,dive,gt=0,lte=1"`
+	Iterations int       `json:"iterations,omitempty" validate:"omitempty,min=1,max=20000"`
+	Seed       *int64    `json:"seed,omitempty"`
 }
 
 type StartOptimizationResponse struct {
@@ -189,6 +865,28 @@ type OptimizationJobsResponse struct {
 	Jobs []*optimizer.OptimizationJob `json:"jobs"`
 }
 
+// RetentionPolicyRequest replaces the optimization job retention policy.
+// A payload_ttl_days of 0 keeps archived payloads forever.
+type RetentionPolicyRequest struct {
+	ArchiveAfterMinutes int   `json:"archive_after_minutes" validate:"min=0,max=10080"`
+	PayloadTTLDays      int   `json:"payload_ttl_days" validate:"min=0,max=3650"`
+	KeepBestPerDraw     *bool `json:"keep_best_per_draw,omitempty"` // defaults to true
+}
+
+type RetentionPolicyResponse struct {
+	ArchiveAfterMinutes int  `json:"archive_after_minutes"`
+	PayloadTTLDays      int  `json:"payload_ttl_days"`
+	KeepBestPerDraw     bool `json:"keep_best_per_draw"`
+}
+
+type JobArchivesResponse struct {
+	Archives []*models.JobArchive `json:"archives"`
+}
+
+type SolutionsResponse struct {
+	Solutions []*models.Solution `json:"solutions"`
+}
+
 type ConstraintValidationResponse struct {
 	DrawID     int                             `json:"draw_id"`
 	IsValid    bool                            `json:"is_valid"`
@@ -229,20 +927,108 @@ type ListQueryParams struct {
 	SortBy   string `form:"sort_by" validate:"omitempty,oneof=id name created updated"`
 	SortDir  string `form:"sort_dir" validate:"omitempty,oneof=asc desc"`
 	IsActive *bool  `form:"is_active"`
+	// CompetitionID lists one competition's teams or draws
+	CompetitionID int `form:"competition_id" validate:"omitempty,min=1"`
+	// SeasonYear and Status list the draws of one season, or in one status;
+	// "published" lists the draws that have been published
+	SeasonYear int    `form:"season_year" validate:"omitempty,min=2000,max=2100"`
+	Status     string `form:"status" validate:"omitempty,oneof=draft optimizing completed published"`
+}
+
+// ExportQueryParams selects the format and scope of a draw export
+type ExportQueryParams struct {
+	Format   string `form:"format" validate:"required,oneof=csv ics"`
+	TeamID   int    `form:"team_id" validate:"omitempty,min=1"`
+	VenueID  int    `form:"venue_id" validate:"omitempty,min=1"`
+	Locale   string `form:"locale" validate:"omitempty,max=10"`
+	Timezone string `form:"tz" validate:"omitempty,max=64"`
+}
+
+// ReportQueryParams selects the format of a draw report. Reports are HTML;
+// PDFs are printed from the page's print stylesheet.
+type ReportQueryParams struct {
+	Format string `form:"format" validate:"omitempty,oneof=html"`
+	Locale string `form:"locale" validate:"omitempty,max=10"`
+}
+
+// ExportLocaleRequest customizes the locale of a posted export or report.
+// Unset formats come from the preset named by the locale query parameter.
+type ExportLocaleRequest struct {
+	DateFormat string                    `json:"date_format,omitempty" validate:"omitempty,max=64"`
+	TimeFormat string                    `json:"time_format,omitempty" validate:"omitempty,max=64"`
+	TeamNames  []TeamNameOverrideRequest  `json:"team_names,omitempty" validate:"omitempty,dive"`
+	VenueNames []VenueNameOverrideRequest `json:"venue_names,omitempty" validate:"omitempty,dive"`
+}
+
+// TeamNameOverrideRequest renames a team for a range of rounds, such as its
+// Indigenous Round name. Zero rounds leave the range open.
+type TeamNameOverrideRequest struct {
+	TeamID    int    `json:"team_id" validate:"required,min=1"`
+	Name      string `json:"name" validate:"required,min=1,max=100"`
+	FromRound int    `json:"from_round,omitempty" validate:"omitempty,min=1"`
+	ToRound   int    `json:"to_round,omitempty" validate:"omitempty,min=1"`
+}
+
+// VenueNameOverrideRequest renames a venue between effective dates, such as
+// a naming-rights deal starting mid-season
+type VenueNameOverrideRequest struct {
+	VenueID       int        `json:"venue_id" validate:"required,min=1"`
+	Name          string     `json:"name" validate:"required,min=1,max=100"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// CompareDrawsRequest selects the draws to compare side by side. When
+// Constraints is set every draw is scored against it instead of its own
+// stored configuration.
+type CompareDrawsRequest struct {
+	DrawIDs     []int                         `json:"draw_ids" validate:"required,min=2,max=10,dive,min=1"`
+	Constraints *constraints.ConstraintConfig `json:"constraints,omitempty"`
+}
+
+// VenueConflictsQuery sets how far apart kickoffs at a venue shared with
+// another competition must be, defaulting to two hours
+type VenueConflictsQuery struct {
+	MinGapMinutes *int `form:"min_gap_minutes" validate:"omitempty,min=0,max=1440"`
 }
 
 // Conversion helpers
+// HomeVenuesFromRequest converts requested venue shares to the model
+func HomeVenuesFromRequest(venues []HomeVenueRequest) []models.HomeVenue {
+	if venues == nil {
+		return nil
+	}
+	homeVenues := make([]models.HomeVenue, len(venues))
+	for i, venue := range venues {
+		homeVenues[i] = models.HomeVenue{VenueID: venue.VenueID, Share: venue.Share}
+	}
+	return homeVenues
+}
+
+// TeamUnavailabilityToResponse converts a stored unavailable date for the API
+func TeamUnavailabilityToResponse(unavailability models.TeamUnavailability) TeamUnavailabilityResponse {
+	return TeamUnavailabilityResponse{
+		ID:        unavailability.ID,
+		TeamID:    unavailability.TeamID,
+		Date:      unavailability.Date.Format("2006-01-02"),
+		Reason:    unavailability.Reason,
+		CreatedAt: unavailability.CreatedAt,
+	}
+}
+
 func TeamToResponse(team *models.Team, venue *models.Venue) TeamResponse {
 	resp := TeamResponse{
-		ID:        team.ID,
-		Name:      team.Name,
-		ShortName: team.ShortName,
-		City:      team.City,
-		VenueID:   team.VenueID,
-		Latitude:  team.Latitude,
-		Longitude: team.Longitude,
-		CreatedAt: team.CreatedAt,
-		UpdatedAt: team.UpdatedAt,
+		ID:            team.ID,
+		Name:          team.Name,
+		ShortName:     team.ShortName,
+		City:          team.City,
+		VenueID:       team.VenueID,
+		Latitude:      team.Latitude,
+		Longitude:     team.Longitude,
+		HomeVenues:    team.HomeVenues,
+		CompetitionID: team.CompetitionID,
+		CreatedAt:     team.CreatedAt,
+		UpdatedAt:     team.UpdatedAt,
 	}
 	
 	if venue != nil {
@@ -261,6 +1047,30 @@ func TeamToResponse(team *models.Team, venue *models.Venue) TeamResponse {
 	return resp
 }
 
+// ConstraintViolationToResponse converts an analyzed violation for the API
+func ConstraintViolationToResponse(violation constraints.ConstraintViolation) ConstraintViolation {
+	resp := ConstraintViolation{
+		Type:           violation.ConstraintName,
+		ConstraintType: violation.ConstraintType,
+		Severity:       string(violation.Severity),
+		Description:    violation.Description,
+		Locked:         violation.Locked,
+		MatchIDs:       violation.MatchIDs,
+		Rounds:         violation.Rounds,
+		TeamIDs:        violation.TeamIDs,
+		Remediation:    violation.Remediation,
+	}
+	if violation.MatchID > 0 {
+		matchID := violation.MatchID
+		resp.MatchID = &matchID
+	}
+	if violation.Round > 0 {
+		round := violation.Round
+		resp.Round = &round
+	}
+	return resp
+}
+
 func VenueToResponse(venue *models.Venue) VenueResponse {
 	return VenueResponse{
 		ID:        venue.ID,
@@ -274,6 +1084,96 @@ func VenueToResponse(venue *models.Venue) VenueResponse {
 	}
 }
 
+func CompetitionToResponse(competition *models.Competition) CompetitionResponse {
+	var constraintConfig interface{}
+	if len(competition.ConstraintConfig) > 0 {
+		var config constraints.ConstraintConfig
+		if err := json.Unmarshal(competition.ConstraintConfig, &config); err == nil {
+			constraintConfig = config
+		} else {
+			constraintConfig = string(competition.ConstraintConfig)
+		}
+	}
+
+	return CompetitionResponse{
+		ID:               competition.ID,
+		Name:             competition.Name,
+		Code:             competition.Code,
+		ConstraintConfig: constraintConfig,
+		CreatedAt:        competition.CreatedAt,
+		UpdatedAt:        competition.UpdatedAt,
+	}
+}
+
+func TimeslotToResponse(timeslot *models.Timeslot) TimeslotResponse {
+	return TimeslotResponse{
+		ID:            timeslot.ID,
+		Day:           strings.ToLower(timeslot.DayOfWeek.String()),
+		Kickoff:       timeslot.Kickoff,
+		Channel:       timeslot.Channel,
+		IsPrimeTime:   timeslot.IsPrimeTime,
+		CompetitionID: timeslot.CompetitionID,
+		CreatedAt:     timeslot.CreatedAt,
+		UpdatedAt:     timeslot.UpdatedAt,
+	}
+}
+
+func SeasonCalendarToResponse(calendar *models.SeasonCalendar) SeasonCalendarResponse {
+	rounds := make([]CalendarRoundResponse, len(calendar.Rounds))
+	for i, round := range calendar.Rounds {
+		rounds[i] = CalendarRoundResponse{
+			Round:     round.Round,
+			StartDate: round.StartDate.Format("2006-01-02"),
+			EndDate:   round.EndDate.Format("2006-01-02"),
+			Kind:      string(round.Kind),
+			Label:     round.Label,
+		}
+	}
+	return SeasonCalendarResponse{
+		ID:            calendar.ID,
+		Name:          calendar.Name,
+		SeasonYear:    calendar.SeasonYear,
+		CompetitionID: calendar.CompetitionID,
+		Rounds:        rounds,
+		CreatedAt:     calendar.CreatedAt,
+		UpdatedAt:     calendar.UpdatedAt,
+	}
+}
+
+func ConstraintTemplateToResponse(template *models.ConstraintTemplate) ConstraintTemplateResponse {
+	return ConstraintTemplateResponse{
+		ID:               template.ID,
+		Name:             template.Name,
+		Description:      template.Description,
+		Version:          template.Version,
+		ConstraintConfig: constraintConfigToResponse(template.Config),
+		CreatedAt:        template.CreatedAt,
+		UpdatedAt:        template.UpdatedAt,
+	}
+}
+
+func ConstraintTemplateVersionToResponse(version *models.ConstraintTemplateVersion) ConstraintTemplateVersionResponse {
+	return ConstraintTemplateVersionResponse{
+		TemplateID:       version.TemplateID,
+		Version:          version.Version,
+		ConstraintConfig: constraintConfigToResponse(version.Config),
+		CreatedAt:        version.CreatedAt,
+	}
+}
+
+// constraintConfigToResponse decodes a stored configuration, falling back to
+// its raw text when it no longer decodes
+func constraintConfigToResponse(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	var config constraints.ConstraintConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return string(raw)
+	}
+	return config
+}
+
 func DrawToResponse(draw *models.Draw) DrawResponse {
 	var constraintConfig interface{}
 	if len(draw.ConstraintConfig) > 0 {
@@ -299,11 +1199,46 @@ func DrawToResponse(draw *models.Draw) DrawResponse {
 		Status:           string(draw.Status),
 		ConstraintConfig: constraintConfig,
 		MatchCount:       matchCount,
+		PublishedAt:      draw.PublishedAt,
+		CompetitionID:    draw.CompetitionID,
+		Version:          draw.Version,
 		CreatedAt:        draw.CreatedAt,
 		UpdatedAt:        draw.UpdatedAt,
 	}
 }
 
+func ShareLinkToResponse(link *models.ShareLink) ShareLinkResponse {
+	return ShareLinkResponse{
+		ID:          link.ID,
+		DrawID:      link.DrawID,
+		Token:       link.Token,
+		URL:         "/share/" + link.Token,
+		DrawVersion: link.DrawVersion,
+		Label:       link.Label,
+		CreatedBy:   link.CreatedBy,
+		Active:      link.IsActive(time.Now()),
+		ExpiresAt:   link.ExpiresAt,
+		RevokedAt:   link.RevokedAt,
+		CreatedAt:   link.CreatedAt,
+	}
+}
+
+// WebhookToResponse converts a webhook to its response, without its secret
+func WebhookToResponse(webhook *models.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:             webhook.ID,
+		URL:            webhook.URL,
+		Events:         webhook.Events,
+		Description:    webhook.Description,
+		IsActive:       webhook.IsActive,
+		LastDeliveryAt: webhook.LastDeliveryAt,
+		LastStatus:     webhook.LastStatus,
+		LastError:      webhook.LastError,
+		CreatedAt:      webhook.CreatedAt,
+		UpdatedAt:      webhook.UpdatedAt,
+	}
+}
+
 func MatchToResponse(match *models.Match, homeTeam, awayTeam *models.Team, venue *models.Venue) MatchResponse {
 	resp := MatchResponse{
 		ID:          match.ID,
@@ -311,6 +1246,7 @@ func MatchToResponse(match *models.Match, homeTeam, awayTeam *models.Team, venue
 		Round:       match.Round,
 		ScheduledAt: match.MatchDate,
 		IsBye:       match.IsBye(),
+		Locked:      match.Locked,
 		Created:     match.CreatedAt,
 		Updated:     match.UpdatedAt,
 	}
@@ -331,4 +1267,96 @@ func MatchToResponse(match *models.Match, homeTeam, awayTeam *models.Team, venue
 	}
 	
 	return resp
-}
\ No newline at end of file
+}
+
+// RoundToResponse converts a draw round into its API response, keeping only
+// the round's entry in each region spread report
+func RoundToResponse(drawID int, fixtures *draw.RoundFixtures, spreads []constraints.RegionSpreadReport) RoundResponse {
+	resp := RoundResponse{
+		DrawID:           drawID,
+		Round:            fixtures.Round,
+		Matches:          make([]MatchResponse, len(fixtures.Matches)),
+		ByeTeams:         make([]TeamResponse, len(fixtures.ByeTeams)),
+		PrimeTimeMatches: fixtures.PrimeTimeMatches,
+		Venues:           make([]VenueResponse, len(fixtures.Venues)),
+	}
+	
+	for i, match := range fixtures.Matches {
+		resp.Matches[i] = MatchToResponse(match, match.HomeTeam, match.AwayTeam, match.Venue)
+	}
+	for i, team := range fixtures.ByeTeams {
+		resp.ByeTeams[i] = TeamToResponse(team, nil)
+	}
+	for i, venue := range fixtures.Venues {
+		resp.Venues[i] = VenueToResponse(venue)
+	}
+	
+	for _, spread := range spreads {
+		rounds := []constraints.RegionRoundAnalysis{}
+		for _, analysis := range spread.Rounds {
+			if analysis.Round == fixtures.Round {
+				rounds = append(rounds, analysis)
+			}
+		}
+		spread.Rounds = rounds
+		resp.RegionSpread = append(resp.RegionSpread, spread)
+	}
+	
+	return resp
+}
+
+// TeamScheduleToResponse converts a team's season into its API response
+func TeamScheduleToResponse(drawID, teamID int, schedule []draw.TeamRound) TeamScheduleResponse {
+	resp := TeamScheduleResponse{
+		DrawID: drawID,
+		TeamID: teamID,
+		Rounds: make([]TeamRoundResponse, len(schedule)),
+	}
+	
+	for i, entry := range schedule {
+		round := TeamRoundResponse{
+			Round:           entry.Round,
+			IsBye:           entry.Match == nil,
+			Home:            entry.Home,
+			RestDays:        entry.RestDays,
+			ConsecutiveAway: entry.ConsecutiveAway,
+		}
+		if entry.Match != nil {
+			round.MatchID = entry.Match.ID
+			round.ScheduledAt = entry.Match.MatchDate
+			round.IsPrimeTime = entry.Match.IsPrimeTime
+			if entry.Match.Venue != nil {
+				venue := VenueToResponse(entry.Match.Venue)
+				round.Venue = &venue
+			}
+		}
+		if entry.Opponent != nil {
+			opponent := TeamToResponse(entry.Opponent, nil)
+			round.Opponent = &opponent
+		}
+		resp.Rounds[i] = round
+	}
+	
+	return resp
+}
+
+// RetentionPolicyFromRequest converts a retention request into an optimizer policy
+func RetentionPolicyFromRequest(req RetentionPolicyRequest) optimizer.RetentionPolicy {
+	policy := optimizer.RetentionPolicy{
+		ArchiveAfter:    time.Duration(req.ArchiveAfterMinutes) * time.Minute,
+		PayloadTTL:      time.Duration(req.PayloadTTLDays) * 24 * time.Hour,
+		KeepBestPerDraw: true,
+	}
+	if req.KeepBestPerDraw != nil {
+		policy.KeepBestPerDraw = *req.KeepBestPerDraw
+	}
+	return policy
+}
+
+func RetentionPolicyToResponse(policy optimizer.RetentionPolicy) RetentionPolicyResponse {
+	return RetentionPolicyResponse{
+		ArchiveAfterMinutes: int(policy.ArchiveAfter / time.Minute),
+		PayloadTTLDays:      int(policy.PayloadTTL / (24 * time.Hour)),
+		KeepBestPerDraw:     policy.KeepBestPerDraw,
+	}
+}
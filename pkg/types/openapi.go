@@ -0,0 +1,505 @@
+package types
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIVersion is the OpenAPI specification version of the generated document
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPIDocument is an OpenAPI 3 description of the API
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+	Security   []map[string][]string                   `json:"security,omitempty"`
+}
+
+// OpenAPIInfo describes the API as a whole
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIComponents holds the schemas operations refer to by name
+type OpenAPIComponents struct {
+	Schemas         map[string]*OpenAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPISecurityScheme is one way of presenting an API key
+type OpenAPISecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIOperation is one method on one path
+type OpenAPIOperation struct {
+	Summary     string                      `json:"summary"`
+	Description string                      `json:"description,omitempty"`
+	OperationID string                      `json:"operationId"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	// Security is empty rather than absent for routes needing no API key
+	Security *[]map[string][]string `json:"security,omitempty"`
+}
+
+// OpenAPIParameter is a path or query parameter
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody is an operation's body, by content type
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is one of an operation's responses, by content type
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType is the schema of a body in one content type
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the subset of the OpenAPI schema object generated from
+// Go types and their validate tags
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Enum                 []interface{}             `json:"enum,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool                      `json:"exclusiveMinimum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// APIEndpoint documents one route: its parameters, body and response are
+// given as zero values of the types the handler binds and responds with
+type APIEndpoint struct {
+	Method string
+	// Path is the route as registered with Gin, with :name parameters
+	Path        string
+	Summary     string
+	Description string
+	Tag         string
+	// Query is a struct whose form tags are the query parameters
+	Query interface{}
+	// Params are query parameters the handler reads without binding
+	Params []OpenAPIParameter
+	// Request is the JSON body; RequestTypes lists other accepted content
+	// types for the same body, such as CSV
+	Request      interface{}
+	RequestTypes []string
+	// Status is the success status, 200 when unset
+	Status int
+	// Response is the JSON response body, nil when there is none.
+	// ResponseType replaces JSON for endpoints returning another format.
+	Response     interface{}
+	ResponseType string
+	// Public routes need no API key
+	Public bool
+}
+
+// OpenAPIBuilder builds an OpenAPI document from API endpoints, generating
+// schemas from the json, form and validate tags the handlers bind with, so
+// the document can't drift from the request and response types
+type OpenAPIBuilder struct {
+	doc   *OpenAPIDocument
+	names map[reflect.Type]string
+}
+
+// NewOpenAPIBuilder creates a builder for a document with the given info.
+// Every operation accepts an API key as a bearer token or X-API-Key header.
+func NewOpenAPIBuilder(info OpenAPIInfo) *OpenAPIBuilder {
+	return &OpenAPIBuilder{
+		doc: &OpenAPIDocument{
+			OpenAPI: OpenAPIVersion,
+			Info:    info,
+			Paths:   make(map[string]map[string]*OpenAPIOperation),
+			Components: OpenAPIComponents{
+				Schemas: make(map[string]*OpenAPISchema),
+				SecuritySchemes: map[string]*OpenAPISecurityScheme{
+					"bearerAuth": {Type: "http", Scheme: "bearer", Description: "API key as a bearer token"},
+					"apiKey":     {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "API key header"},
+				},
+			},
+			Security: []map[string][]string{{"bearerAuth": {}}, {"apiKey": {}}},
+		},
+		names: make(map[reflect.Type]string),
+	}
+}
+
+// ginParam matches a Gin path parameter
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z_]+)`)
+
+// Add documents an endpoint
+func (b *OpenAPIBuilder) Add(endpoint APIEndpoint) {
+	path := ginParam.ReplaceAllString(endpoint.Path, "{$1}")
+	op := &OpenAPIOperation{
+		Summary:     endpoint.Summary,
+		Description: endpoint.Description,
+		OperationID: operationID(endpoint.Method, endpoint.Path),
+		Responses:   make(map[string]*OpenAPIResponse),
+	}
+	if endpoint.Tag != "" {
+		op.Tags = []string{endpoint.Tag}
+	}
+	if endpoint.Public {
+		op.Security = &[]map[string][]string{}
+	}
+
+	for _, match := range ginParam.FindAllStringSubmatch(endpoint.Path, -1) {
+		op.Parameters = append(op.Parameters, OpenAPIParameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   pathParamSchema(match[1]),
+		})
+	}
+	if endpoint.Query != nil {
+		op.Parameters = append(op.Parameters, b.QueryParameters(endpoint.Query)...)
+	}
+	for _, param := range endpoint.Params {
+		param.In = "query"
+		op.Parameters = append(op.Parameters, param)
+	}
+
+	if endpoint.Request != nil {
+		schema := b.Schema(endpoint.Request)
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  map[string]OpenAPIMediaType{"application/json": {Schema: schema}},
+		}
+		for _, contentType := range endpoint.RequestTypes {
+			op.RequestBody.Content[contentType] = OpenAPIMediaType{Schema: &OpenAPISchema{Type: "string"}}
+		}
+	}
+
+	status := endpoint.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := &OpenAPIResponse{Description: http.StatusText(status)}
+	switch {
+	case endpoint.ResponseType != "":
+		response.Content = map[string]OpenAPIMediaType{endpoint.ResponseType: {Schema: &OpenAPISchema{Type: "string"}}}
+	case endpoint.Response != nil:
+		response.Content = map[string]OpenAPIMediaType{"application/json": {Schema: b.Schema(endpoint.Response)}}
+	}
+	op.Responses[strconv.Itoa(status)] = response
+
+	errorContent := map[string]OpenAPIMediaType{"application/json": {Schema: b.Schema(ErrorResponse{})}}
+	op.Responses["default"] = &OpenAPIResponse{Description: "Error", Content: errorContent}
+
+	if b.doc.Paths[path] == nil {
+		b.doc.Paths[path] = make(map[string]*OpenAPIOperation)
+	}
+	b.doc.Paths[path][strings.ToLower(endpoint.Method)] = op
+}
+
+// Document returns the document built so far
+func (b *OpenAPIBuilder) Document() *OpenAPIDocument {
+	return b.doc
+}
+
+// operationID names an operation after its method and path, such as
+// post_draws_id_generate
+func operationID(method, path string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/api/v1"), "/") {
+		segment = strings.Trim(segment, ":*")
+		segment = strings.ReplaceAll(segment, "-", "_")
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// pathParamSchema types a path parameter: IDs and seasons are integers
+func pathParamSchema(name string) *OpenAPISchema {
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, "id") || lower == "season" {
+		return &OpenAPISchema{Type: "integer"}
+	}
+	return &OpenAPISchema{Type: "string"}
+}
+
+// QueryParameters documents the form-tagged fields of a query struct
+func (b *OpenAPIBuilder) QueryParameters(query interface{}) []OpenAPIParameter {
+	t := reflect.TypeOf(query)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var params []OpenAPIParameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		schema := b.schemaFor(field.Type)
+		required := applyValidation(schema, field.Tag.Get("validate"))
+		params = append(params, OpenAPIParameter{
+			Name:     name,
+			In:       "query",
+			Required: required,
+			Schema:   schema,
+		})
+	}
+	return params
+}
+
+// Schema returns the schema of a value's type, registering the named
+// structs it uses as components
+func (b *OpenAPIBuilder) Schema(value interface{}) *OpenAPISchema {
+	return b.schemaFor(reflect.TypeOf(value))
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaFor returns the schema of a type. Named structs are referenced
+// rather than inlined, which also ends recursion.
+func (b *OpenAPIBuilder) schemaFor(t reflect.Type) *OpenAPISchema {
+	if t == nil {
+		return &OpenAPISchema{}
+	}
+	if t.Kind() == reflect.Ptr {
+		schema := b.schemaFor(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	}
+
+	switch t {
+	case timeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &OpenAPISchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &OpenAPISchema{Type: "number"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: b.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return b.ref(t)
+	default:
+		// Interfaces hold any JSON value
+		return &OpenAPISchema{}
+	}
+}
+
+// ref registers a named struct as a component and refers to it
+func (b *OpenAPIBuilder) ref(t reflect.Type) *OpenAPISchema {
+	name, ok := b.names[t]
+	if !ok {
+		name = schemaName(t)
+		b.names[t] = name
+		// Reserve the name before generating, in case the type refers to itself
+		b.doc.Components.Schemas[name] = &OpenAPISchema{}
+		*b.doc.Components.Schemas[name] = *b.structSchema(t)
+	}
+	return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+// schemaName names a type's component. Types from other packages are
+// qualified by package, since several have the same name.
+func schemaName(t reflect.Type) string {
+	if t.PkgPath() == reflect.TypeOf(OpenAPIDocument{}).PkgPath() {
+		return t.Name()
+	}
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	return pkg + "." + t.Name()
+}
+
+// structSchema describes a struct's JSON fields, flattening embedded structs
+func (b *OpenAPIBuilder) structSchema(t reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	b.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (b *OpenAPIBuilder) addFields(schema *OpenAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := b.schemaFor(field.Type)
+		if applyValidation(fieldSchema, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = fieldSchema
+	}
+}
+
+// applyValidation adds a validate tag's rules to a schema, returning
+// whether the field is required. Rules after dive apply to the items.
+func applyValidation(schema *OpenAPISchema, tag string) bool {
+	if tag == "" || tag == "-" {
+		return false
+	}
+
+	required := false
+	target := schema
+	for _, rule := range strings.Split(tag, ",") {
+		name, value, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if target == schema {
+				required = true
+			}
+		case "dive":
+			switch {
+			case target.Items != nil:
+				target = target.Items
+			case target.AdditionalProperties != nil:
+				target = target.AdditionalProperties
+			default:
+				return required
+			}
+		case "min", "gte":
+			setBound(target, value, true, false)
+		case "gt":
+			setBound(target, value, true, true)
+		case "max", "lte":
+			setBound(target, value, false, false)
+		case "oneof":
+			if target.Ref != "" {
+				continue
+			}
+			for _, option := range strings.Fields(value) {
+				target.Enum = append(target.Enum, enumValue(target, option))
+			}
+		}
+	}
+	return required
+}
+
+// setBound applies a min or max rule, which bounds a string's length, an
+// array's items, or a number's value
+func setBound(schema *OpenAPISchema, value string, lower, exclusive bool) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || schema.Ref != "" {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		if schema.Format != "" {
+			return
+		}
+		n := int(number)
+		if lower {
+			schema.MinLength = &n
+		} else {
+			schema.MaxLength = &n
+		}
+	case "array":
+		n := int(number)
+		if lower {
+			schema.MinItems = &n
+		} else {
+			schema.MaxItems = &n
+		}
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &number
+			schema.ExclusiveMinimum = exclusive
+		} else {
+			schema.Maximum = &number
+		}
+	}
+}
+
+// enumValue converts a oneof option to the schema's type
+func enumValue(schema *OpenAPISchema, option string) interface{} {
+	switch schema.Type {
+	case "integer":
+		if n, err := strconv.Atoi(option); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(option, 64); err == nil {
+			return n
+		}
+	}
+	return option
+}
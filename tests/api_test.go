@@ -2,18 +2,38 @@ package tests
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	gorillaws "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/adampetrovic/nrl-scheduler/internal/api"
+	"github.com/adampetrovic/nrl-scheduler/internal/api/middleware"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/geo"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/constraints"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/crossdraw"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/optimizer"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/seed"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/simulate"
+	"github.com/adampetrovic/nrl-scheduler/internal/core/webhook"
+	"github.com/adampetrovic/nrl-scheduler/internal/importer"
 	"github.com/adampetrovic/nrl-scheduler/pkg/types"
 	
 	_ "github.com/mattn/go-sqlite3"
@@ -23,6 +43,9 @@ func setupTestDB(t *testing.T) *sql.DB {
 	// Use in-memory SQLite database for testing
 	db, err := sql.Open("sqlite3", ":memory:")
 	require.NoError(t, err)
+	// Every connection to :memory: is a separate database, and jobs now write
+	// to it in the background
+	db.SetMaxOpenConns(1)
 	
 	// Create basic schema for testing
 	schema := `
@@ -37,6 +60,59 @@ func setupTestDB(t *testing.T) *sql.DB {
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
+	CREATE TABLE IF NOT EXISTS competitions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		code TEXT NOT NULL UNIQUE,
+		constraint_config TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS timeslots (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		day_of_week INTEGER NOT NULL,
+		kickoff TEXT NOT NULL,
+		channel TEXT,
+		is_prime_time BOOLEAN NOT NULL DEFAULT 0,
+		competition_id INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (competition_id) REFERENCES competitions(id)
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_timeslots_slot ON timeslots(day_of_week, kickoff, IFNULL(competition_id, 0));
+
+	CREATE TABLE IF NOT EXISTS season_calendars (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		season_year INTEGER NOT NULL,
+		competition_id INTEGER,
+		rounds TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (competition_id) REFERENCES competitions(id) ON DELETE CASCADE
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_season_calendars_season ON season_calendars(season_year, IFNULL(competition_id, 0));
+
+	CREATE TABLE IF NOT EXISTS constraint_templates (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		description TEXT,
+		version INTEGER NOT NULL DEFAULT 1,
+		config TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS constraint_template_versions (
+		template_id INTEGER NOT NULL,
+		version INTEGER NOT NULL,
+		config TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (template_id, version),
+		FOREIGN KEY (template_id) REFERENCES constraint_templates(id) ON DELETE CASCADE
+	);
+
 	CREATE TABLE IF NOT EXISTS teams (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		name TEXT NOT NULL,
@@ -45,9 +121,11 @@ func setupTestDB(t *testing.T) *sql.DB {
 		venue_id INTEGER,
 		latitude REAL NOT NULL DEFAULT 0,
 		longitude REAL NOT NULL DEFAULT 0,
+		competition_id INTEGER,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (venue_id) REFERENCES venues(id)
+		FOREIGN KEY (venue_id) REFERENCES venues(id),
+		FOREIGN KEY (competition_id) REFERENCES competitions(id)
 	);
 
 	CREATE TABLE IF NOT EXISTS draws (
@@ -57,6 +135,148 @@ func setupTestDB(t *testing.T) *sql.DB {
 		rounds INTEGER NOT NULL,
 		status TEXT NOT NULL DEFAULT 'draft',
 		constraint_config TEXT,
+		published_at DATETIME,
+		published_version TEXT,
+		competition_id INTEGER,
+		version INTEGER NOT NULL DEFAULT 1,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (competition_id) REFERENCES competitions(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS matches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		draw_id INTEGER NOT NULL,
+		round INTEGER NOT NULL,
+		home_team_id INTEGER,
+		away_team_id INTEGER,
+		venue_id INTEGER,
+		match_date DATE,
+		match_time TIME,
+		is_prime_time BOOLEAN DEFAULT FALSE,
+		locked BOOLEAN DEFAULT FALSE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS draw_approvals (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		draw_id INTEGER NOT NULL,
+		role TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		draw_version TEXT NOT NULL,
+		requested_by TEXT,
+		approver TEXT,
+		comment TEXT,
+		decided_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS draw_share_links (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		draw_id INTEGER NOT NULL,
+		token TEXT NOT NULL UNIQUE,
+		draw_version TEXT NOT NULL,
+		snapshot TEXT NOT NULL,
+		label TEXT,
+		created_by TEXT,
+		expires_at DATETIME NOT NULL,
+		revoked_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS optimization_job_archives (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_id TEXT NOT NULL UNIQUE,
+		draw_id INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		initial_score REAL,
+		final_score REAL,
+		payload BLOB,
+		payload_size INTEGER NOT NULL DEFAULT 0,
+		is_best BOOLEAN DEFAULT FALSE,
+		started_at DATETIME NOT NULL,
+		completed_at DATETIME NOT NULL,
+		payload_discarded_at DATETIME,
+		archived_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS optimization_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_id TEXT NOT NULL UNIQUE,
+		draw_id INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		progress TEXT,
+		result TEXT,
+		error TEXT,
+		started_at DATETIME NOT NULL,
+		completed_at DATETIME,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS optimization_checkpoints (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_id TEXT NOT NULL UNIQUE,
+		draw_id INTEGER NOT NULL,
+		iteration INTEGER NOT NULL,
+		config TEXT NOT NULL,
+		state TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS solutions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		solution_key TEXT NOT NULL UNIQUE,
+		draw_id INTEGER,
+		job_id TEXT NOT NULL,
+		score REAL NOT NULL,
+		rounds INTEGER NOT NULL,
+		match_count INTEGER NOT NULL,
+		matches TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS team_home_venues (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		team_id INTEGER NOT NULL,
+		venue_id INTEGER NOT NULL,
+		share REAL NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (team_id, venue_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS team_unavailability (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		team_id INTEGER NOT NULL,
+		date DATE NOT NULL,
+		reason TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (team_id, date)
+	);
+
+	CREATE TABLE IF NOT EXISTS season_ladders (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		season INTEGER NOT NULL,
+		team_id INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		points INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (season, team_id),
+		UNIQUE (season, position)
+	);
+
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT NOT NULL,
+		events TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		description TEXT,
+		is_active BOOLEAN NOT NULL DEFAULT 1,
+		last_delivery_at DATETIME,
+		last_status INTEGER,
+		last_error TEXT,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
@@ -92,6 +312,87 @@ func TestHealthCheck(t *testing.T) {
 	assert.Equal(t, "ok", response["status"])
 }
 
+func TestOpenAPIDocument(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	gin.SetMode(gin.TestMode)
+	apiServer := api.NewServer(db)
+	apiServer.SetAPIKeys(map[string]middleware.Role{"viewer-key": middleware.RoleViewer})
+	router := apiServer.GetRouter()
+	
+	// The document and Swagger UI need no API key
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/openapi.json", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var document types.OpenAPIDocument
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+	assert.Equal(t, types.OpenAPIVersion, document.OpenAPI)
+	
+	// Every route is documented, and every documented route exists
+	documented := make(map[string]bool)
+	for path, operations := range document.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		path := route.Path
+		for _, segment := range strings.Split(route.Path, "/") {
+			if strings.HasPrefix(segment, ":") {
+				path = strings.Replace(path, segment, "{"+segment[1:]+"}", 1)
+			}
+		}
+		registered[route.Method+" "+path] = true
+		assert.True(t, documented[route.Method+" "+path], "%s %s is not documented", route.Method, route.Path)
+	}
+	for route := range documented {
+		assert.True(t, registered[route], "%s is documented but not registered", route)
+	}
+	
+	// Request schemas carry the handlers' validation rules
+	operation := document.Paths["/api/v1/teams"]["post"]
+	require.NotNil(t, operation)
+	assert.Equal(t, "#/components/schemas/CreateTeamRequest", operation.RequestBody.Content["application/json"].Schema.Ref)
+	teamSchema := document.Components.Schemas["CreateTeamRequest"]
+	require.NotNil(t, teamSchema)
+	assert.Contains(t, teamSchema.Required, "short_name")
+	assert.NotContains(t, teamSchema.Required, "venue_id")
+	require.NotNil(t, teamSchema.Properties["short_name"].MaxLength)
+	assert.Equal(t, 3, *teamSchema.Properties["short_name"].MaxLength)
+	assert.Contains(t, operation.Responses, "201")
+	
+	// Path and query parameters are described
+	operation = document.Paths["/api/v1/draws/{id}/export"]["get"]
+	require.NotNil(t, operation)
+	params := make(map[string]types.OpenAPIParameter)
+	for _, param := range operation.Parameters {
+		params[param.In+" "+param.Name] = param
+	}
+	assert.Equal(t, "integer", params["path id"].Schema.Type)
+	assert.True(t, params["query format"].Required)
+	assert.ElementsMatch(t, []interface{}{"csv", "ics"}, params["query format"].Schema.Enum)
+	
+	// Schemas from other packages are qualified, and all references resolve
+	assert.Contains(t, document.Components.Schemas, "optimizer.OptimizationResult")
+	for _, ref := range regexpRefs.FindAllStringSubmatch(w.Body.String(), -1) {
+		assert.Contains(t, document.Components.Schemas, ref[1])
+	}
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/docs", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+}
+
+// regexpRefs matches schema references in an OpenAPI document
+var regexpRefs = regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`)
+
 func TestVenueCRUD(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -203,6 +504,231 @@ func TestTeamCRUD(t *testing.T) {
 	err = json.Unmarshal(w.Body.Bytes(), &listResp)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, listResp.Total)
+	
+	// Search filters before paging and the total counts only matches
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/teams?search=nomatch&per_page=5", nil)
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &listResp)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, listResp.Total)
+	assert.Equal(t, 5, listResp.PerPage)
+	assert.Empty(t, listResp.Data)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/teams?search=tst&sort_by=name&sort_dir=desc", nil)
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &listResp)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, listResp.Total)
+}
+
+func TestBulkImport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	send := func(path, contentType, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	venuesCSV := "name,city,capacity,latitude,longitude\n" +
+		"BlueBet Stadium,Penrith,22500,-33.7506,150.6936\n" +
+		"CommBank Stadium,Parramatta,30000,-33.8080,151.0036\n"
+	w := send("/api/v1/venues/import", "text/csv", venuesCSV)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	
+	var report importer.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, "CommBank Stadium", report.Results[1].Name)
+	
+	// Every invalid row is reported and nothing is created
+	teamsJSON := `[
+		{"name": "Penrith Panthers", "short_name": "PEN", "city": "Penrith", "venue": "bluebet stadium"},
+		{"name": "Parramatta Eels", "short_name": "PARRA", "city": "Parramatta"},
+		{"name": "Penrith Panthers", "short_name": "PEN", "city": "Penrith"},
+		{"name": "Wests Tigers", "short_name": "WST", "city": "Sydney", "venue": "Leichhardt Oval"}
+	]`
+	w = send("/api/v1/teams/import", "application/json", teamsJSON)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	
+	report = importer.Report{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 0, report.Imported)
+	require.Len(t, report.Errors, 3)
+	assert.Equal(t, []int{2, 3, 4}, []int{report.Errors[0].Row, report.Errors[1].Row, report.Errors[2].Row})
+	assert.Equal(t, "venue", report.Errors[2].Field)
+	
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM teams").Scan(&count))
+	assert.Equal(t, 0, count)
+	
+	teamsCSV := "name,short_name,city,venue\n" +
+		"Penrith Panthers,PEN,Penrith,bluebet stadium\n" +
+		"Parramatta Eels,PAR,Parramatta,CommBank Stadium\n"
+	w = send("/api/v1/teams/import", "text/csv; charset=utf-8", teamsCSV)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	
+	var venueID int
+	require.NoError(t, db.QueryRow("SELECT venue_id FROM teams WHERE name = 'Penrith Panthers'").Scan(&venueID))
+	assert.Equal(t, 1, venueID)
+	
+	w = send("/api/v1/teams/import", "text/csv", "name,short_name,city\n")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = send("/api/v1/teams/import", "application/xml", "<teams/>")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTeamHomeVenueRotation(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES
+		('BlueBet Stadium', 'Penrith', 22500), ('Carrington Park', 'Bathurst', 11000),
+		('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES
+		('Brisbane Broncos', 'BRI', 'Brisbane', 3), ('Melbourne Storm', 'MEL', 'Melbourne', 4),
+		('Sydney Roosters', 'SYD', 'Sydney', 3)`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	team := map[string]interface{}{
+		"name": "Penrith Panthers", "short_name": "PEN", "city": "Penrith", "venue_id": 1,
+		"home_venues": []map[string]interface{}{{"venue_id": 1, "share": 0.5}, {"venue_id": 2, "share": 0.3}},
+	}
+	w := send("POST", "/api/v1/teams", team)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "shares must add up to 1")
+	
+	team["home_venues"] = []map[string]interface{}{{"venue_id": 1, "share": 0.7}, {"venue_id": 2, "share": 0.3}}
+	w = send("POST", "/api/v1/teams", team)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	
+	var created types.TeamResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	
+	w = send("GET", fmt.Sprintf("/api/v1/teams/%d", created.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var fetched types.TeamResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	require.Len(t, fetched.HomeVenues, 2)
+	assert.Equal(t, 1, fetched.HomeVenues[0].VenueID)
+	assert.Equal(t, 0.7, fetched.HomeVenues[0].Share)
+	
+	// Generation rotates the home games and the soft constraint scores the split
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Rotation Draw', 2025, 6, 'draft')`)
+	require.NoError(t, err)
+	w = send("POST", "/api/v1/draws/1/generate", map[string]interface{}{
+		"constraints": map[string]interface{}{
+			"hard": []interface{}{map[string]interface{}{"type": "bye_constraint", "params": map[string]interface{}{}}},
+			"soft": []interface{}{map[string]interface{}{"type": "home_venue_share", "weight": 1.0, "params": map[string]interface{}{}}},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var atSecond int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND home_team_id = ? AND venue_id = 2`,
+		created.ID).Scan(&atSecond))
+	assert.Equal(t, 1, atSecond, "one of three home games should be at the second venue")
+	
+	// An empty list clears the split
+	w = send("PUT", fmt.Sprintf("/api/v1/teams/%d", created.ID), map[string]interface{}{"home_venues": []interface{}{}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var remaining int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM team_home_venues`).Scan(&remaining))
+	assert.Equal(t, 0, remaining)
+}
+
+func TestTeamUnavailability(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	// The draw's own configuration has no availability constraints
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES
+		('Dated Draw', 2025, 1, 'completed', '{"hard":[],"soft":[]}')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, match_date) VALUES
+		(1, 1, 1, 2, '2025-03-08'), (1, 1, 3, 4, '2025-03-09')`)
+	require.NoError(t, err)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	validate := func() types.ValidateConstraintsResponse {
+		w := send("GET", "/api/v1/draws/1/validate-constraints", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp types.ValidateConstraintsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+	
+	require.True(t, validate().IsValid)
+	
+	w := send("POST", "/api/v1/teams/1/unavailability", `{"date":"2025-03-08","reason":"Ground booked for a concert"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created types.TeamUnavailabilityResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "2025-03-08", created.Date)
+	assert.Equal(t, 1, created.TeamID)
+	
+	w = send("POST", "/api/v1/teams/1/unavailability", `{"date":"2025-03-08"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = send("POST", "/api/v1/teams/1/unavailability", `{"date":"08/03/2025"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = send("POST", "/api/v1/teams/99/unavailability", `{"date":"2025-03-08"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	w = send("GET", "/api/v1/teams/1/unavailability", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list types.TeamUnavailabilityListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Unavailability, 1)
+	assert.Equal(t, "Ground booked for a concert", list.Unavailability[0].Reason)
+	
+	// Stored dates apply to the draw without touching its configuration
+	resp := validate()
+	assert.False(t, resp.IsValid)
+	require.NotEmpty(t, resp.Violations)
+	assert.Equal(t, "team_unavailability", resp.Violations[0].ConstraintType)
+	assert.Equal(t, "hard", resp.Violations[0].Severity)
+	assert.Contains(t, resp.Violations[0].TeamIDs, 1)
+	
+	w = send("DELETE", fmt.Sprintf("/api/v1/teams/1/unavailability/%d", created.ID), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = send("DELETE", fmt.Sprintf("/api/v1/teams/1/unavailability/%d", created.ID), "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	assert.True(t, validate().IsValid)
 }
 
 func TestDrawCRUD(t *testing.T) {
@@ -247,31 +773,3675 @@ func TestDrawCRUD(t *testing.T) {
 	assert.Equal(t, 1, listResp.Total)
 }
 
-func TestValidationErrors(t *testing.T) {
+func TestListDrawsFiltered(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 	
 	router := setupTestServer(db)
 	
-	// Test invalid venue creation
-	invalidReq := types.CreateVenueRequest{
-		Name:     "", // Empty name should fail validation
-		City:     "Test City",
-		Capacity: 50000,
+	_, err := db.Exec(`INSERT INTO draws (name, season_year, rounds, status, published_at) VALUES
+		('NRL 2025', 2025, 27, 'completed', CURRENT_TIMESTAMP),
+		('NRL 2026 Draft', 2026, 27, 'draft', NULL),
+		('NRL 2026', 2026, 27, 'completed', CURRENT_TIMESTAMP),
+		('NRL 2026 Trial', 2026, 4, 'completed', NULL)`)
+	require.NoError(t, err)
+	
+	list := func(query string) types.PaginatedResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/draws"+query, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp types.PaginatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
 	}
 	
-	body, _ := json.Marshal(invalidReq)
+	assert.Equal(t, 4, list("").Total)
+	assert.Equal(t, 3, list("?season_year=2026").Total)
+	assert.Equal(t, 2, list("?status=published").Total)
+	assert.Equal(t, 2, list("?season_year=2026&status=completed").Total)
+	assert.Equal(t, 1, list("?season_year=2026&status=draft").Total)
+	
+	resp := list("?season_year=2026&status=published&search=nrl")
+	require.Equal(t, 1, resp.Total)
+	draws := resp.Data.([]interface{})
+	assert.Equal(t, "NRL 2026", draws[0].(map[string]interface{})["name"])
+	
+	for _, query := range []string{"?status=archived", "?season_year=1999"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/draws"+query, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestDrawEditConflicts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Shared Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Two editors start from version 1; the second edit is stale
+	w := send("PUT", "/api/v1/draws/1", map[string]interface{}{"name": "Renamed", "version": 1})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated types.DrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, 2, updated.Version)
+	
+	w = send("PUT", "/api/v1/draws/1", map[string]interface{}{"rounds": 4, "version": 1})
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	var conflict types.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conflict))
+	assert.Equal(t, "VERSION_CONFLICT", conflict.Code)
+	assert.Equal(t, "2", conflict.Details["current_version"])
+	
+	// Edits without a version apply to the latest
+	w = send("PUT", "/api/v1/draws/1", map[string]interface{}{"name": "Renamed again"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	// A running optimization locks the draw
+	w = send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 1000000,
+	})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	
+	for _, attempt := range []struct{ method, path string }{
+		{"PUT", "/api/v1/draws/1"},
+		{"PATCH", "/api/v1/matches/1"},
+		{"POST", "/api/v1/optimize/draws/1/start"},
+	} {
+		w = send(attempt.method, attempt.path, map[string]interface{}{
+			"name": "Clobbered", "round": 3,
+			"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 100,
+		})
+		require.Equal(t, http.StatusConflict, w.Code, "%s %s: %s", attempt.method, attempt.path, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conflict))
+		assert.Equal(t, "DRAW_LOCKED", conflict.Code)
+		assert.Equal(t, started.JobID, conflict.Details["job_id"])
+	}
+	
+	// The lock goes with the job
+	require.Eventually(t, func() bool {
+		w := send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", nil)
+		var status types.OptimizationStatusResponse
+		return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Status == "running"
+	}, 5*time.Second, 10*time.Millisecond)
+	w = send("POST", "/api/v1/optimize/jobs/"+started.JobID+"/cancel", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Eventually(t, func() bool {
+		w := send("PUT", "/api/v1/draws/1", map[string]interface{}{"name": "After optimization"})
+		return w.Code == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestConcurrentOptimizationStarts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Raced Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Starts racing on the same draw: one runs, the others are refused
+	responses := make([]*httptest.ResponseRecorder, 8)
+	var wg sync.WaitGroup
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = send("POST", "/api/v1/optimize/draws/1/start", `{"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 1000000}`)
+		}(i)
+	}
+	wg.Wait()
+	
+	var started types.StartOptimizationResponse
+	for _, w := range responses {
+		if w.Code == http.StatusAccepted {
+			require.Empty(t, started.JobID, "more than one start was accepted")
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+			continue
+		}
+		require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		var conflict types.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conflict))
+		assert.Contains(t, []string{"DRAW_LOCKED", "VERSION_CONFLICT"}, conflict.Code)
+	}
+	require.NotEmpty(t, started.JobID, "no start was accepted")
+	
+	require.Eventually(t, func() bool {
+		w := send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", "")
+		var status types.OptimizationStatusResponse
+		return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Status == "running"
+	}, 5*time.Second, 10*time.Millisecond)
+	w := send("POST", "/api/v1/optimize/jobs/"+started.JobID+"/cancel", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Eventually(t, func() bool {
+		w := send("PUT", "/api/v1/draws/1", `{"name": "After optimization"}`)
+		return w.Code == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDrawYAMLConstraintConfig(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	createBody := `
+name: YAML Draw
+season_year: 2025
+rounds: 26
+constraint_config:
+  hard:
+    - type: venue_availability
+      params:
+        venue_id: 1
+        unavailable_dates:
+          - 2025-06-15
+          - 2025-07-04
+  soft:
+    - type: rest_period
+      weight: 0.9
+      params:
+        min_rest_days: 5
+`
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/api/v1/venues", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("POST", "/api/v1/draws", bytes.NewBufferString(createBody))
+	req.Header.Set("Content-Type", "application/yaml")
 	router.ServeHTTP(w, req)
 	
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
 	
-	var errorResp types.ErrorResponse
-	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
-	assert.NoError(t, err)
-	assert.Contains(t, errorResp.Error, "Validation failed")
+	var createResp types.DrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createResp))
+	assert.Equal(t, "YAML Draw", createResp.Name)
+	
+	var stored string
+	require.NoError(t, db.QueryRow(`SELECT constraint_config FROM draws WHERE id = ?`, createResp.ID).Scan(&stored))
+	config, err := constraints.LoadConstraintConfigFromJSON([]byte(stored))
+	require.NoError(t, err)
+	require.Len(t, config.Hard, 1)
+	assert.Equal(t, float64(1), config.Hard[0].Params["venue_id"])
+	assert.Equal(t, []interface{}{"2025-06-15", "2025-07-04"}, config.Hard[0].Params["unavailable_dates"])
+	
+	// Update with YAML too
+	updateBody := `
+name: Renamed YAML Draw
+constraint_config:
+  hard: []
+  soft:
+    - type: rest_period
+      weight: 0.5
+      params:
+        min_rest_days: 6
+`
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", fmt.Sprintf("/api/v1/draws/%d", createResp.ID), bytes.NewBufferString(updateBody))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	router.ServeHTTP(w, req)
+	
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	require.NoError(t, db.QueryRow(`SELECT constraint_config FROM draws WHERE id = ?`, createResp.ID).Scan(&stored))
+	config, err = constraints.LoadConstraintConfigFromJSON([]byte(stored))
+	require.NoError(t, err)
+	assert.Empty(t, config.Hard)
+	require.Len(t, config.Soft, 1)
+	assert.Equal(t, 0.5, config.Soft[0].Weight)
+	assert.Equal(t, float64(6), config.Soft[0].Params["min_rest_days"])
+	
+	// Malformed YAML is rejected
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/draws", bytes.NewBufferString("name: [unclosed"))
+	req.Header.Set("Content-Type", "application/yaml")
+	router.ServeHTTP(w, req)
+	
+	assert.NotEqual(t, http.StatusCreated, w.Code)
+}
+
+func TestConstraintTypes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/constraints/types", nil)
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusOK, w.Code)
+	
+	var response types.ConstraintTypesResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Types)
+	
+	byName := make(map[string]types.ConstraintTypeResponse)
+	for _, item := range response.Types {
+		byName[item.Name] = item
+	}
+	
+	restPeriod, ok := byName["rest_period"]
+	require.True(t, ok)
+	assert.Equal(t, "soft", restPeriod.Type)
+	assert.True(t, restPeriod.InDefaultConfig)
+	assert.Equal(t, float64(5), restPeriod.DefaultParams["min_rest_days"])
+	require.NotNil(t, restPeriod.DefaultWeight)
+	assert.Equal(t, 0.9, *restPeriod.DefaultWeight)
+	
+	venueAvailability, ok := byName["venue_availability"]
+	require.True(t, ok)
+	assert.False(t, venueAvailability.InDefaultConfig)
+	assert.Nil(t, venueAvailability.DefaultWeight)
+}
+
+func TestConstraintSchema(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/constraints/schema", nil)
+	router.ServeHTTP(w, req)
+	
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	
+	config := response["config"].(map[string]interface{})
+	assert.Equal(t, constraints.JSONSchemaDraft, config["$schema"])
+	assert.Equal(t, "object", config["type"])
+	
+	schemaTypes := response["types"].(map[string]interface{})
+	restPeriod := schemaTypes["rest_period"].(map[string]interface{})
+	assert.Equal(t, true, restPeriod["hard"])
+	assert.Equal(t, true, restPeriod["soft"])
+	
+	params := restPeriod["params"].(map[string]interface{})
+	anyOf := params["anyOf"].([]interface{})
+	require.Len(t, anyOf, 2)
+	assert.Equal(t, []interface{}{"min_rest_days"}, anyOf[0].(map[string]interface{})["required"])
+	minRestDays := params["properties"].(map[string]interface{})["min_rest_days"].(map[string]interface{})
+	assert.Equal(t, "integer", minRestDays["type"])
+	assert.Equal(t, float64(0), minRestDays["minimum"])
+	
+	venueAvailability := schemaTypes["venue_availability"].(map[string]interface{})
+	dates := venueAvailability["params"].(map[string]interface{})["properties"].(map[string]interface{})["unavailable_dates"].(map[string]interface{})
+	assert.Equal(t, "date", dates["items"].(map[string]interface{})["format"])
+	
+	rivalry := schemaTypes["rivalry_round"].(map[string]interface{})
+	assert.Equal(t, true, rivalry["hard"])
+	assert.Equal(t, true, rivalry["soft"])
+}
+
+func TestValidateConstraintConfig(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	validate := func(payload map[string]interface{}) types.ValidateConstraintConfigResponse {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/constraints/validate", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		
+		var response types.ValidateConstraintConfigResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	
+	config := map[string]interface{}{
+		"hard": []interface{}{map[string]interface{}{"type": "double_up", "params": map[string]interface{}{"min_rounds_separation": 30}}},
+		"soft": []interface{}{map[string]interface{}{"type": "prime_time_spread", "weight": 0.5, "params": map[string]interface{}{"target_ratio": 0.9, "max_deviation": 0.5}}},
+	}
+	
+	// Without the draw's size only the params' own consistency is checked
+	response := validate(map[string]interface{}{"constraints": config})
+	assert.True(t, response.Valid, response.Error)
+	assert.Empty(t, response.Errors)
+	require.Len(t, response.Warnings, 1)
+	assert.Equal(t, "prime_time_deviation_exceeds_range", response.Warnings[0].Code)
+	
+	// 17 teams over 27 rounds must meet twice, closer than 30 rounds apart
+	response = validate(map[string]interface{}{"constraints": config, "teams": 17, "rounds": 27})
+	assert.False(t, response.Valid)
+	assert.Contains(t, response.Error, "hard[0]:double_up")
+	codes := []string{}
+	for _, issue := range response.Errors {
+		codes = append(codes, issue.Code)
+	}
+	assert.Contains(t, codes, "double_up_impossible")
+	warnings := []string{}
+	for _, issue := range response.Warnings {
+		warnings = append(warnings, issue.Code)
+	}
+	assert.Contains(t, warnings, "prime_time_deviation_exceeds_range")
+	assert.Contains(t, warnings, "double_up_exceeds_season")
+}
+
+func TestSimulateConstraints(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	simulateConfig := func(payload map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/constraints/simulate", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	config := map[string]interface{}{
+		"hard": []interface{}{map[string]interface{}{"type": "double_up", "params": map[string]interface{}{"min_rounds_separation": 3}}},
+		"soft": []interface{}{map[string]interface{}{"type": "home_away_balance", "weight": 0.5, "params": map[string]interface{}{"max_deviation": 0.2}}},
+	}
+	w := simulateConfig(map[string]interface{}{"constraints": config, "teams": 8, "rounds": 14, "samples": 10, "seed": 7})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var result simulate.Result
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 10, result.Samples)
+	assert.Equal(t, int64(7), result.Seed)
+	require.Len(t, result.Constraints, 2)
+	assert.Equal(t, "hard", result.Constraints[0].Type)
+	assert.Equal(t, "soft", result.Constraints[1].Type)
+	assert.LessOrEqual(t, result.Overall.Min, result.Overall.Max)
+	assert.InDelta(t, 1-result.Constraints[0].ViolationRate, result.ValidRate, 1e-9)
+	
+	// Unbuildable configurations and leagues are rejected
+	w = simulateConfig(map[string]interface{}{"constraints": map[string]interface{}{"hard": []interface{}{map[string]interface{}{"type": "double_up"}}}, "teams": 8, "rounds": 14})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = simulateConfig(map[string]interface{}{"constraints": config, "teams": 1, "rounds": 14})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGeocodeMissingCoordinates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	// Venue and team without coordinates
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane', 1)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Nowhere Oval', 'Atlantis', 1000)`)
+	require.NoError(t, err)
+	
+	// Dry run reports without saving
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/admin/geocode?dry_run=true", nil)
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusOK, w.Code)
+	
+	var report map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &report)
+	assert.NoError(t, err)
+	assert.Equal(t, true, report["dry_run"])
+	assert.Equal(t, float64(2), report["updated"])
+	assert.Equal(t, float64(1), report["not_found"])
+	
+	var lat float64
+	require.NoError(t, db.QueryRow(`SELECT latitude FROM venues WHERE id = 1`).Scan(&lat))
+	assert.Equal(t, float64(0), lat)
+	
+	// Real run writes venue and team coordinates
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/admin/geocode", nil)
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusOK, w.Code)
+	
+	require.NoError(t, db.QueryRow(`SELECT latitude FROM venues WHERE id = 1`).Scan(&lat))
+	assert.InDelta(t, -27.4648, lat, 0.0001)
+	require.NoError(t, db.QueryRow(`SELECT latitude FROM teams WHERE id = 1`).Scan(&lat))
+	assert.InDelta(t, -27.4648, lat, 0.0001)
+}
+
+func TestSeedNRL(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	// An existing team is kept rather than duplicated
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES ('Melbourne Storm', 'MEL', 'Melbourne')`)
+	require.NoError(t, err)
+	
+	seedNRL := func() seed.Report {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/admin/seed/nrl", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		
+		var report seed.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return report
+	}
+	
+	report := seedNRL()
+	assert.Equal(t, 15, report.VenuesCreated)
+	assert.Equal(t, 16, report.TeamsCreated)
+	assert.Equal(t, 1, report.Existing)
+	
+	var venue string
+	var capacity int
+	var lat float64
+	require.NoError(t, db.QueryRow(`SELECT v.name, v.capacity, t.latitude FROM teams t JOIN venues v ON v.id = t.venue_id
+		WHERE t.name = 'Brisbane Broncos'`).Scan(&venue, &capacity, &lat))
+	assert.Equal(t, "Suncorp Stadium", venue)
+	assert.Equal(t, 52500, capacity)
+	assert.InDelta(t, -27.46, lat, 0.01)
+	
+	// Seeding again changes nothing
+	report = seedNRL()
+	assert.Equal(t, 0, report.VenuesCreated+report.TeamsCreated)
+	assert.Equal(t, 32, report.Existing)
+	
+	var teams int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM teams`).Scan(&teams))
+	assert.Equal(t, 17, teams)
+}
+
+func TestDrawApprovalWorkflow(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	post := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// A generated draw with one match
+	_, err := db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Approval Draw', 2025, 1, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id) VALUES (1, 1, 1, 2, 1)`)
+	require.NoError(t, err)
+	
+	// Publishing before any sign-off is refused
+	w := post("/api/v1/draws/1/publish", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	
+	w = post("/api/v1/draws/1/approvals", map[string]interface{}{"roles": []string{"broadcast", "operations"}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Len(t, summary["pending"], 2)
+	assert.Equal(t, false, summary["ready_to_publish"])
+	
+	// Unknown roles are rejected by validation
+	w = post("/api/v1/draws/1/approvals", map[string]interface{}{"roles": []string{"sponsors"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	w = post("/api/v1/draws/1/approvals/broadcast", map[string]interface{}{"decision": "approve", "approver": "broadcaster"})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	// Still waiting on operations
+	w = post("/api/v1/draws/1/publish", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	
+	// The clubs role was never requested
+	w = post("/api/v1/draws/1/approvals/clubs", map[string]interface{}{"decision": "approve", "approver": "club"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	
+	w = post("/api/v1/draws/1/approvals/operations", map[string]interface{}{"decision": "approve", "approver": "ops"})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, true, summary["ready_to_publish"])
+	
+	w = post("/api/v1/draws/1/publish", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var drawResp types.DrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &drawResp))
+	assert.NotNil(t, drawResp.PublishedAt)
+	
+	// Changing the fixture makes outstanding approvals stale
+	_, err = db.Exec(`UPDATE matches SET venue_id = 2 WHERE id = 1`)
+	require.NoError(t, err)
+	
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/approvals", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, true, summary["stale"])
+	assert.Equal(t, false, summary["ready_to_publish"])
+}
+
+func TestDrawMatchesCompressionAndStreaming(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane', 1), ('Melbourne Storm', 'MEL', 'Melbourne', NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Streaming Draw', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id) VALUES (1, 1, 1, 2, 1), (1, 2, 2, 1, 1)`)
+	require.NoError(t, err)
+	
+	// Gzip is applied when the client accepts it
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/matches", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	router.ServeHTTP(w, req)
+	
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	
+	var matches []types.MatchResponse
+	require.NoError(t, json.Unmarshal(body, &matches))
+	require.Len(t, matches, 2)
+	require.NotNil(t, matches[0].HomeTeam)
+	assert.Equal(t, "Brisbane Broncos", matches[0].HomeTeam.Name)
+	
+	// Without Accept-Encoding the body is plain JSON
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/1/matches", nil)
+	router.ServeHTTP(w, req)
+	
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &matches))
+	
+	// NDJSON returns one match per line
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/1/matches?format=ndjson", nil)
+	router.ServeHTTP(w, req)
+	
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	var match types.MatchResponse
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &match))
+	assert.Equal(t, 2, match.Round)
+}
+
+func TestDrawShareLinks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane'), ('Melbourne Storm', 'MEL', 'Melbourne')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Shared Draw', 2025, 1, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id) VALUES (1, 1, 1, 2, 1)`)
+	require.NoError(t, err)
+	
+	body, _ := json.Marshal(map[string]interface{}{"ttl_hours": 48, "label": "Club CEO review"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/draws/1/share-links", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	
+	var link types.ShareLinkResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	assert.True(t, link.Active)
+	assert.NotEmpty(t, link.Token)
+	assert.Equal(t, "/share/"+link.Token, link.URL)
+	
+	// The fixture renders read-only for anyone holding the token
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", link.URL, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "Brisbane Broncos v Melbourne Storm")
+	
+	// Later changes don't alter what was shared, but are flagged
+	_, err = db.Exec(`UPDATE matches SET home_team_id = 2, away_team_id = 1 WHERE id = 1`)
+	require.NoError(t, err)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", link.URL+"?format=json", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var shared map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &shared))
+	assert.Equal(t, true, shared["outdated"])
+	matches := shared["matches"].([]interface{})
+	require.Len(t, matches, 1)
+	assert.Equal(t, "Brisbane Broncos", matches[0].(map[string]interface{})["home_team"])
+	
+	// Revoked links stop working
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", fmt.Sprintf("/api/v1/draws/1/share-links/%d", link.ID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", link.URL, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusGone, w.Code)
+	
+	// Unknown tokens are not found
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/share/does-not-exist", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDrawExport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane'), ('Melbourne Storm', 'MEL', 'Melbourne'), ('Sydney Roosters', 'SYD', 'Sydney')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('NRL Premiership', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date, match_time) VALUES
+		(1, 1, 1, 2, 1, '2025-03-06', '19:50:00'),
+		(1, 2, 2, 3, 2, '2025-03-13', NULL)`)
+	require.NoError(t, err)
+	
+	// CSV lists every match with display names
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/export?format=csv", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="nrl-premiership-2025.csv"`)
+	
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "Brisbane Broncos,Melbourne Storm,Suncorp Stadium")
+	
+	// iCal feeds can be limited to one team
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/1/export?format=ics&team_id=3", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/calendar")
+	
+	feed := w.Body.String()
+	assert.Equal(t, 1, strings.Count(feed, "BEGIN:VEVENT"))
+	assert.Contains(t, feed, "SUMMARY:Melbourne Storm v Sydney Roosters")
+	assert.Contains(t, feed, "X-WR-CALNAME:Sydney Roosters - NRL Premiership 2025")
+	
+	// Posted overrides rename teams for rounds and venues from a date
+	overrides := `{
+		"date_format": "2006-01-02",
+		"team_names": [{"team_id": 2, "name": "Narrm Storm", "from_round": 1, "to_round": 1}],
+		"venue_names": [{"venue_id": 2, "name": "Sponsor Park", "effective_from": "2025-03-10T00:00:00Z"}]
+	}`
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/draws/1/export?format=csv", bytes.NewBufferString(overrides))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	lines = strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "2025-03-06")
+	assert.Contains(t, lines[1], "Brisbane Broncos,Narrm Storm,Suncorp Stadium")
+	assert.Contains(t, lines[2], "Melbourne Storm,Sydney Roosters,Sponsor Park")
+	
+	for _, invalid := range []string{
+		`{"team_names": [{"team_id": 2}]}`,
+		`{"team_names": [{"team_id": 2, "name": "Narrm Storm", "from_round": 2, "to_round": 1}]}`,
+	} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/api/v1/draws/1/export?format=csv", bytes.NewBufferString(invalid))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, invalid)
+	}
+	
+	// Unsupported formats and unknown teams are rejected
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/1/export?format=pdf", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/1/export?format=ics&team_id=99", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/99/export?format=csv", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDrawReport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane'), ('Melbourne Storm', 'MEL', 'Melbourne'), ('Sydney Roosters', 'SYD', 'Sydney')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('NRL Premiership', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date, match_time, is_prime_time) VALUES
+		(1, 1, 1, 2, 1, '2025-03-06', '19:50:00', 1),
+		(1, 2, 2, 3, 2, '2025-03-13', NULL, 0)`)
+	require.NoError(t, err)
+	
+	// The report is an HTML page with a section per team
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/report", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="nrl-premiership-2025-report.html"`)
+	
+	page := w.Body.String()
+	assert.Contains(t, page, "<h1>NRL Premiership 2025</h1>")
+	assert.Equal(t, 3, strings.Count(page, `<section class="team">`))
+	assert.Contains(t, page, "<h2>Sydney Roosters</h2>")
+	assert.Contains(t, page, "Thu 6 Mar 2025")
+	
+	// Posted overrides rename opponents in the fixture lists
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/draws/1/report?locale=iso", bytes.NewBufferString(`{"team_names": [{"team_id": 3, "name": "Roosters Indigenous", "from_round": 2}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "Roosters Indigenous")
+	assert.Contains(t, w.Body.String(), "2025-03-06")
+	
+	// Only HTML reports are supported
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/1/report?format=pdf", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/99/report", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCompareDraws(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane'), ('Melbourne Storm', 'MEL', 'Melbourne')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Option A', 2025, 2, 'completed'), ('Option B', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	// Option A alternates home games; option B gives Brisbane both
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 2, 2, 1),
+		(2, 1, 1, 2), (2, 2, 1, 2)`)
+	require.NoError(t, err)
+	
+	body, _ := json.Marshal(map[string]interface{}{"draw_ids": []int{1, 2}})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/draws/compare", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var comparison map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &comparison))
+	
+	reports := comparison["draws"].([]interface{})
+	require.Len(t, reports, 2)
+	first := reports[0].(map[string]interface{})
+	second := reports[1].(map[string]interface{})
+	assert.Equal(t, "Option A", first["name"])
+	assert.NotEmpty(t, first["constraints"])
+	assert.Contains(t, first, "travel")
+	assert.Contains(t, first, "prime_time")
+	
+	firstBalance := first["home_away"].(map[string]interface{})
+	secondBalance := second["home_away"].(map[string]interface{})
+	assert.Equal(t, float64(2), firstBalance["teams_within_range"])
+	assert.Equal(t, float64(0), secondBalance["teams_within_range"])
+	
+	// A single draw, duplicates and unknown draws are rejected
+	for _, tc := range []struct {
+		ids    []int
+		status int
+	}{
+		{[]int{1}, http.StatusBadRequest},
+		{[]int{1, 1}, http.StatusBadRequest},
+		{[]int{1, 99}, http.StatusNotFound},
+	} {
+		body, _ := json.Marshal(map[string]interface{}{"draw_ids": tc.ids})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/draws/compare", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, "draw_ids %v", tc.ids)
+	}
+}
+
+func TestCloneDraw(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES ('Production', 2025, 2, 'completed', '{"hard":[],"soft":[]}')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, match_date, is_prime_time, locked) VALUES
+		(1, 1, 1, 2, '2025-03-07', 1, 1), (1, 2, 2, 1, '2025-03-14', 0, 0)`)
+	require.NoError(t, err)
+	
+	clone := func(query string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/draws/1/clone"+query, &body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Without matches the copy starts as an empty draft
+	w := clone("", nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp types.DrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Production (copy)", resp.Name)
+	assert.Equal(t, "draft", resp.Status)
+	assert.Equal(t, 0, resp.MatchCount)
+	assert.NotNil(t, resp.ConstraintConfig)
+	
+	// With matches, a new name and a different configuration
+	w = clone("?with_matches=true", map[string]interface{}{
+		"name": "Scenario B",
+		"constraint_config": map[string]interface{}{
+			"soft": []map[string]interface{}{{"type": "rest_period", "weight": 1.0, "params": map[string]interface{}{"min_rest_days": 6}}},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Scenario B", resp.Name)
+	assert.Equal(t, "completed", resp.Status)
+	assert.Equal(t, 2, resp.MatchCount)
+	
+	var copied, locked, primeTime int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*), SUM(locked), SUM(is_prime_time) FROM matches WHERE draw_id = ?`, resp.ID).Scan(&copied, &locked, &primeTime))
+	assert.Equal(t, 2, copied)
+	assert.Equal(t, 1, locked)
+	assert.Equal(t, 1, primeTime)
+	
+	// The production draw is untouched
+	var name, config string
+	require.NoError(t, db.QueryRow(`SELECT name, constraint_config FROM draws WHERE id = 1`).Scan(&name, &config))
+	assert.Equal(t, "Production", name)
+	assert.JSONEq(t, `{"hard":[],"soft":[]}`, config)
+	var original int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1`).Scan(&original))
+	assert.Equal(t, 2, original)
+	
+	assert.Equal(t, http.StatusBadRequest, clone("?with_matches=maybe", nil).Code)
+	
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/draws/99/clone", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAssignSlots(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Slot Draw', 2025, 1, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES (1, 1, 1, 2), (1, 1, 3, 4)`)
+	require.NoError(t, err)
+	
+	assign := func(payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/draws/1/assign-slots", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	slots := []map[string]interface{}{
+		{"date": "2025-03-07", "time": "19:50", "prime_time": true, "broadcaster": "free_to_air"},
+		{"date": "2025-03-08", "time": "17:30"},
+	}
+	
+	// Dry runs don't save
+	w := assign(map[string]interface{}{"rounds": []interface{}{map[string]interface{}{"round": 1, "slots": slots}}, "dry_run": true})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp types.AssignSlotsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Assignments, 2)
+	require.Len(t, resp.Rounds, 1)
+	assert.Equal(t, 2, resp.Rounds[0].Matches)
+	assert.Equal(t, 1, resp.Rounds[0].PrimeTimeSlots)
+	
+	var scheduled int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE match_date IS NOT NULL`).Scan(&scheduled))
+	assert.Equal(t, 0, scheduled)
+	
+	w = assign(map[string]interface{}{"rounds": []interface{}{map[string]interface{}{"round": 1, "slots": slots}}})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE match_date IS NOT NULL`).Scan(&scheduled))
+	assert.Equal(t, 2, scheduled)
+	var primeTime int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE is_prime_time`).Scan(&primeTime))
+	assert.Equal(t, 1, primeTime)
+	
+	// A round needs a slot for every match
+	w = assign(map[string]interface{}{"rounds": []interface{}{map[string]interface{}{"round": 1, "slots": slots[:1]}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	w = assign(map[string]interface{}{"rounds": []interface{}{map[string]interface{}{"round": 1, "slots": []map[string]interface{}{{"date": "7 March"}, {"date": "2025-03-08"}}}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	// Weekly templates date the round from the season start
+	schedule := func(payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/draws/1/schedule-timeslots", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w = schedule(map[string]interface{}{
+		"season_start": "2025-03-06",
+		"template":     []map[string]interface{}{{"day": "thursday", "time": "20:00"}, {"day": "sun", "time": "16:05"}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Assignments, 2)
+	dates := []string{resp.Assignments[0].Date, resp.Assignments[1].Date}
+	assert.ElementsMatch(t, []string{"2025-03-06", "2025-03-09"}, dates)
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE is_prime_time`).Scan(&primeTime))
+	assert.Equal(t, 1, primeTime)
+	
+	w = schedule(map[string]interface{}{"season_start": "2025-03-06", "template": []map[string]interface{}{{"day": "someday", "time": "20:00"}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	// Broadcaster quotas report each team's appearances in the dated timeslots
+	_, err = db.Exec(`UPDATE draws SET constraint_config = ? WHERE id = 1`,
+		`{"hard": [{"type": "broadcaster_quota", "params": {"broadcaster": "Nine", "days": ["thursday"], "from": "19:00", "min_appearances": 1}}], "soft": []}`)
+	require.NoError(t, err)
+	
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/constraints/broadcaster-quotas", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var quotas types.BroadcasterQuotaReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quotas))
+	require.Len(t, quotas.Quotas, 1)
+	assert.True(t, quotas.Quotas[0].Hard)
+	statuses := make(map[string]int)
+	for _, team := range quotas.Quotas[0].Teams {
+		statuses[team.Status]++
+	}
+	assert.Equal(t, map[string]int{"WITHIN": 2, "UNDER": 2}, statuses)
+}
+
+func TestTimeslots(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Friday night is prime time automatically; Saturday afternoon is flagged
+	w := send("POST", "/api/v1/timeslots", `{"day": "fri", "kickoff": "20:00", "channel": "Nine"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var friday types.TimeslotResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &friday))
+	assert.Equal(t, "friday", friday.Day)
+	assert.True(t, friday.IsPrimeTime)
+	
+	w = send("POST", "/api/v1/timeslots", `{"day": "saturday", "kickoff": "15:00", "channel": "Fox League", "is_prime_time": true}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	
+	assert.Equal(t, http.StatusConflict, send("POST", "/api/v1/timeslots", `{"day": "friday", "kickoff": "20:00"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/timeslots", `{"day": "someday", "kickoff": "20:00"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/timeslots", `{"day": "sunday", "kickoff": "4pm"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/timeslots", `{"day": "sunday", "kickoff": "16:00", "competition_id": 99}`).Code)
+	
+	w = send("PUT", "/api/v1/timeslots/1", `{"kickoff": "19:55"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = send("GET", "/api/v1/timeslots", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var catalogue []types.TimeslotResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &catalogue))
+	require.Len(t, catalogue, 2)
+	assert.Equal(t, "19:55", catalogue[0].Kickoff)
+	
+	// Rounds are dated from the catalogue, and matches take its channels and
+	// prime-time flags
+	_, err := db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Slot Draw', 2025, 1, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES (1, 1, 1, 2), (1, 1, 3, 4)`)
+	require.NoError(t, err)
+	
+	w = send("POST", "/api/v1/draws/1/schedule-timeslots", `{"season_start": "2025-03-06"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp types.AssignSlotsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Assignments, 2)
+	for _, assignment := range resp.Assignments {
+		assert.True(t, assignment.IsPrimeTime, "%s %s", assignment.Date, assignment.Time)
+	}
+	assert.ElementsMatch(t, []string{"Nine", "Fox League"}, []string{resp.Assignments[0].Broadcaster, resp.Assignments[1].Broadcaster})
+	
+	// Slots given by hand take the catalogue's flag unless they set their own
+	w = send("POST", "/api/v1/draws/1/assign-slots", `{"rounds": [{"round": 1, "slots": [
+		{"date": "2025-03-08", "time": "15:00"},
+		{"date": "2025-03-09", "time": "16:00"}
+	]}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var primeTime int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE is_prime_time`).Scan(&primeTime))
+	assert.Equal(t, 1, primeTime)
+	
+	w = send("POST", "/api/v1/draws/1/assign-slots", `{"rounds": [{"round": 1, "slots": [
+		{"date": "2025-03-08", "time": "15:00", "prime_time": false},
+		{"date": "2025-03-09", "time": "16:00"}
+	]}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE is_prime_time`).Scan(&primeTime))
+	assert.Equal(t, 0, primeTime)
+	
+	require.Equal(t, http.StatusOK, send("DELETE", "/api/v1/timeslots/2", "").Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/timeslots/2", "").Code)
+}
+
+func TestSeasonCalendars(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Without a season start or a calendar, rounds can't be dated
+	_, err := db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Calendar Draw', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES (1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4)`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/draws/1/schedule-timeslots", `{"dry_run": true}`).Code)
+	
+	// Round 2 is split over two weekends either side of an Origin weekend
+	w := send("POST", "/api/v1/calendars", `{"name": "NRL 2025", "season_year": 2025, "rounds": [
+		{"round": 1, "start_date": "2025-03-06", "end_date": "2025-03-09"},
+		{"round": 2, "start_date": "2025-03-13", "end_date": "2025-03-16", "kind": "split"},
+		{"round": 3, "start_date": "2025-03-20", "end_date": "2025-03-23", "kind": "origin", "label": "Origin I"},
+		{"round": 2, "start_date": "2025-03-27", "end_date": "2025-03-30", "kind": "split"}
+	]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var calendar types.SeasonCalendarResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &calendar))
+	assert.Len(t, calendar.Rounds, 4)
+	assert.Equal(t, "regular", calendar.Rounds[0].Kind)
+	assert.Equal(t, "2025-03-06", calendar.Rounds[0].StartDate)
+	
+	// One calendar a season, and ranges must not overlap
+	assert.Equal(t, http.StatusConflict, send("POST", "/api/v1/calendars", `{"name": "Again", "season_year": 2025, "rounds": [
+		{"round": 1, "start_date": "2025-03-06", "end_date": "2025-03-09"}
+	]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/calendars", `{"name": "Overlap", "season_year": 2026, "rounds": [
+		{"round": 1, "start_date": "2026-03-05", "end_date": "2026-03-08"},
+		{"round": 2, "start_date": "2026-03-08", "end_date": "2026-03-15"}
+	]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/calendars", `{"name": "Bad", "season_year": 2026, "rounds": [
+		{"round": 1, "start_date": "5 March", "end_date": "2026-03-08"}
+	]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/calendars", `{"name": "Elsewhere", "season_year": 2026, "competition_id": 99, "rounds": [
+		{"round": 1, "start_date": "2026-03-05", "end_date": "2026-03-08"}
+	]}`).Code)
+	
+	w = send("PUT", "/api/v1/calendars/1", `{"name": "NRL Telstra Premiership 2025"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = send("GET", "/api/v1/calendars", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var calendars []types.SeasonCalendarResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &calendars))
+	require.Len(t, calendars, 1)
+	assert.Equal(t, "NRL Telstra Premiership 2025", calendars[0].Name)
+	
+	// Scheduling without a season start dates each round within its weekends
+	w = send("POST", "/api/v1/draws/1/schedule-timeslots", `{"dry_run": true, "template": [
+		{"day": "friday", "time": "20:00"},
+		{"day": "saturday", "time": "17:30"}
+	]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp types.AssignSlotsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Assignments, 4)
+	for _, assignment := range resp.Assignments {
+		switch assignment.Round {
+		case 1:
+			assert.Contains(t, []string{"2025-03-07", "2025-03-08"}, assignment.Date)
+		case 2:
+			assert.Contains(t, []string{"2025-03-14", "2025-03-15", "2025-03-28", "2025-03-29"}, assignment.Date)
+		}
+	}
+	
+	require.Equal(t, http.StatusOK, send("DELETE", "/api/v1/calendars/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/calendars/1", "").Code)
+}
+
+func TestAuthentication(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	gin.SetMode(gin.TestMode)
+	apiServer := api.NewServer(db)
+	apiServer.SetAPIKeys(map[string]middleware.Role{
+		"viewer-key":    middleware.RoleViewer,
+		"scheduler-key": middleware.RoleScheduler,
+		"admin-key":     middleware.RoleAdmin,
+	})
+	router := apiServer.GetRouter()
+	
+	send := func(method, path, key string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Health checks stay open, everything else needs a valid key
+	assert.Equal(t, http.StatusOK, send("GET", "/health", "", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, send("GET", "/api/v1/teams", "", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, send("GET", "/api/v1/teams", "wrong-key", nil).Code)
+	assert.Equal(t, http.StatusOK, send("GET", "/api/v1/teams", "viewer-key", nil).Code)
+	
+	// Teams and venues are admin only
+	team := map[string]interface{}{"name": "Penrith Panthers", "short_name": "PEN", "city": "Penrith"}
+	assert.Equal(t, http.StatusForbidden, send("POST", "/api/v1/teams", "viewer-key", team).Code)
+	assert.Equal(t, http.StatusForbidden, send("POST", "/api/v1/teams", "scheduler-key", team).Code)
+	assert.Equal(t, http.StatusCreated, send("POST", "/api/v1/teams", "admin-key", team).Code)
+	
+	// Draws need a scheduler
+	draw := map[string]interface{}{"name": "Auth Draw", "season_year": 2025, "rounds": 3}
+	assert.Equal(t, http.StatusForbidden, send("POST", "/api/v1/draws", "viewer-key", draw).Code)
+	assert.Equal(t, http.StatusCreated, send("POST", "/api/v1/draws", "scheduler-key", draw).Code)
+	assert.Equal(t, http.StatusForbidden, send("PUT", "/api/v1/optimize/config", "scheduler-key", map[string]interface{}{}).Code)
+	
+	// Read-only POSTs are open to viewers
+	w := send("POST", "/api/v1/constraints/validate", "viewer-key", map[string]interface{}{"hard": []interface{}{}, "soft": []interface{}{}})
+	assert.NotEqual(t, http.StatusForbidden, w.Code)
+	assert.NotEqual(t, http.StatusUnauthorized, w.Code)
+	
+	// So are exports and reports posted with locale overrides
+	overrides := map[string]interface{}{"date_format": "02/01/2006"}
+	w = send("POST", "/api/v1/draws/1/export?format=csv", "viewer-key", overrides)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = send("POST", "/api/v1/draws/1/report", "viewer-key", overrides)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	// WebSockets take the key as a query parameter
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	_, resp, err := gorillaws.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	
+	conn, _, err := gorillaws.DefaultDialer.Dial(url+"?access_token=viewer-key", nil)
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestOptimizationJobStream(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	
+	_, err := db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Stream Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/ws/optimize/opt_missing", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	body, _ := json.Marshal(map[string]interface{}{"temperature": 10.0, "cooling_rate": 0.99, "max_iterations": 5000})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/optimize/draws/1/start", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/ws/optimize/"+started.JobID+"?interval_ms=1", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/optimize/" + started.JobID + "?interval_ms=50"
+	conn, _, err := gorillaws.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	
+	// The job's state arrives first, then its events until it completes
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var received []string
+	for !slices.Contains(received, "optimization_completed") {
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err, "received %v", received)
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			var message struct {
+				Type string                 `json:"type"`
+				Data map[string]interface{} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(line, &message))
+			assert.Equal(t, started.JobID, message.Data["job_id"])
+			received = append(received, message.Type)
+		}
+	}
+	for _, messageType := range received {
+		assert.Contains(t, []string{"optimization_progress", "optimization_completed"}, messageType)
+	}
+}
+
+
+func TestOptimizationOperatorWeights(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Operator Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Weights must name operators and sum to 1
+	w := send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 500,
+		"operator_weights": map[string]float64{"swap_matches": 0.4, "swap_home_away": 0.1},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	w = send("PUT", "/api/v1/optimize/config", map[string]interface{}{
+		"operator_weights": map[string]float64{"shuffle": 1},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	w = send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 500,
+		"operator_weights": map[string]float64{"swap_matches": 0.6, "swap_home_away": 0.4},
+	})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	
+	require.Eventually(t, func() bool {
+		w := send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", nil)
+		var status types.OptimizationStatusResponse
+		return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Status == "completed"
+	}, 5*time.Second, 10*time.Millisecond)
+	
+	w = send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/result", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result optimizer.OptimizationResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.OperatorStats, 2)
+	attempts := 0
+	for operator, stats := range result.OperatorStats {
+		assert.Contains(t, []string{optimizer.OperatorSwapMatches, optimizer.OperatorSwapHomeAway}, operator)
+		attempts += stats.Attempts
+	}
+	assert.Equal(t, result.Iterations, attempts)
+}
+
+func TestOptimizationObjectives(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES ('Objective Draw', 2025, 3, 'draft',
+		'{"hard":[],"soft":[{"type":"travel_minimization","weight":1,"params":{"max_consecutive_away":1}},{"type":"home_away_balance","weight":1,"params":{"max_deviation":0.1}}]}')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Objectives need a constraint to score them, and simulated annealing
+	w := send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 500,
+		"objectives": []string{"travel", "broadcast"},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	w = send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"algorithm": "tabu_search", "temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 500,
+		"objectives": []string{"travel"},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	w = send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 500, "seed": 5,
+		"objectives": []string{"travel", "fairness"}, "archive_size": 4,
+	})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	
+	require.Eventually(t, func() bool {
+		w := send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", nil)
+		var status types.OptimizationStatusResponse
+		return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Status == "completed"
+	}, 5*time.Second, 10*time.Millisecond)
+	
+	w = send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/result", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result optimizer.OptimizationResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []constraints.Objective{constraints.ObjectiveTravel, constraints.ObjectiveFairness}, result.Objectives)
+	require.NotEmpty(t, result.ParetoFront)
+	assert.LessOrEqual(t, len(result.ParetoFront), 4)
+	for _, solution := range result.ParetoFront {
+		assert.Contains(t, solution.Objectives, constraints.ObjectiveTravel)
+		assert.Contains(t, solution.Objectives, constraints.ObjectiveFairness)
+		require.NotNil(t, solution.Draw)
+		assert.Len(t, solution.Draw.Matches, 6)
+	}
+}
+
+func TestApplyOptimizationAfterMatchEdit(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Edited Draw', 2025, 3, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date) VALUES
+		(1, 1, 1, 2, 1, '2025-03-06'), (1, 1, 3, 4, 2, '2025-03-07'), (1, 2, 1, 3, 1, '2025-03-13'),
+		(1, 2, 2, 4, 2, '2025-03-14'), (1, 3, 4, 1, 2, '2025-03-20'), (1, 3, 3, 2, 1, '2025-03-21')`)
+	require.NoError(t, err)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	optimize := func() string {
+		w := send("POST", "/api/v1/optimize/draws/1/start", `{"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 200}`)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		var started types.StartOptimizationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+		require.Eventually(t, func() bool {
+			w := send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", "")
+			var status types.OptimizationStatusResponse
+			return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Status == "completed"
+		}, 5*time.Second, 10*time.Millisecond)
+		return started.JobID
+	}
+	matchDate := func() string {
+		var date string
+		require.NoError(t, db.QueryRow(`SELECT match_date FROM matches WHERE id = 1`).Scan(&date))
+		return date[:10]
+	}
+	
+	// A match edited once the job has finished isn't overwritten by its result
+	jobID := optimize()
+	w := send("PATCH", "/api/v1/matches/1", `{"date": "2025-03-08", "force": true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = send("POST", "/api/v1/optimize/jobs/"+jobID+"/apply", "")
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Equal(t, "2025-03-08", matchDate())
+	
+	// A job run on the edited draw applies
+	jobID = optimize()
+	w = send("POST", "/api/v1/optimize/jobs/"+jobID+"/apply", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestOptimizationJobRetention(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Retention Draw', 2025, 3, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 1000,
+	})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	
+	require.Eventually(t, func() bool {
+		w := send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", nil)
+		var status types.OptimizationStatusResponse
+		return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Status == "completed"
+	}, 5*time.Second, 10*time.Millisecond)
+	
+	// The trajectory is kept for charting
+	w = send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/history", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var history optimizer.JobHistory
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	assert.Equal(t, optimizer.DefaultHistoryInterval, history.Interval)
+	require.NotEmpty(t, history.Points)
+	assert.Less(t, history.Points[0].Iteration, history.Interval)
+	
+	w = send("GET", "/api/v1/optimize/jobs/opt_missing/history", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	// The result can be previewed against the stored draw before applying
+	w = send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/diff", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var diff optimizer.OptimizationDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(t, started.JobID, diff.JobID)
+	assert.Equal(t, 1, diff.DrawID)
+	assert.Len(t, diff.Teams, 4)
+	assert.NotEmpty(t, diff.Constraints)
+	
+	w = send("GET", "/api/v1/optimize/jobs/opt_missing/diff", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	// Archive everything that has finished
+	w = send("PUT", "/api/v1/optimize/retention", map[string]interface{}{"archive_after_minutes": 0, "payload_ttl_days": 30})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = send("POST", "/api/v1/optimize/retention/apply", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, float64(1), report["archived"])
+	assert.Equal(t, float64(1), report["best_results"])
+	
+	w = send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	w = send("GET", "/api/v1/optimize/archives?draw_id=1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var archives types.JobArchivesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &archives))
+	require.Len(t, archives.Archives, 1)
+	assert.Equal(t, started.JobID, archives.Archives[0].JobID)
+	assert.True(t, archives.Archives[0].IsBest)
+	assert.Greater(t, archives.Archives[0].StoredSize, 0)
+	assert.Less(t, archives.Archives[0].StoredSize, archives.Archives[0].PayloadSize)
+	
+	// Restored jobs serve their result again
+	w = send("POST", "/api/v1/optimize/jobs/"+started.JobID+"/restore", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/result", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.NotNil(t, result["best_draw"])
+	
+	w = send("POST", "/api/v1/optimize/jobs/opt_missing/restore", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOptimizationJobsSurviveRestart(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Persisted Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(router *gin.Engine, method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send(router, "POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 100,
+	})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	
+	require.Eventually(t, func() bool {
+		w := send(router, "GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", nil)
+		var status types.OptimizationStatusResponse
+		return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Status == "completed"
+	}, 5*time.Second, 10*time.Millisecond)
+	
+	// A job that was running when the server stopped
+	_, err = db.Exec(`INSERT INTO optimization_jobs (job_id, draw_id, status, started_at) VALUES ('opt_1_1', 1, 'running', CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE draws SET status = 'optimizing' WHERE id = 1`)
+	require.NoError(t, err)
+	
+	// Restart the API on the same database
+	restarted := setupTestServer(db)
+	
+	w = send(restarted, "GET", "/api/v1/optimize/jobs", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var jobs struct {
+		Jobs []struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"jobs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jobs))
+	require.Len(t, jobs.Jobs, 2)
+	statuses := make(map[string]string)
+	for _, job := range jobs.Jobs {
+		statuses[job.ID] = job.Status
+		if job.ID == "opt_1_1" {
+			assert.NotEmpty(t, job.Error)
+		}
+	}
+	assert.Equal(t, "completed", statuses[started.JobID])
+	assert.Equal(t, "failed", statuses["opt_1_1"])
+	
+	// The completed result can still be fetched and applied
+	w = send(restarted, "GET", "/api/v1/optimize/jobs/"+started.JobID+"/result", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var drawStatus string
+	require.NoError(t, db.QueryRow(`SELECT status FROM draws WHERE id = 1`).Scan(&drawStatus))
+	assert.Equal(t, "draft", drawStatus)
+}
+
+func TestResumeOptimization(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Resumed Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	status := func(jobID string) types.OptimizationStatusResponse {
+		w := send("GET", "/api/v1/optimize/jobs/"+jobID+"/status", nil)
+		var status types.OptimizationStatusResponse
+		json.Unmarshal(w.Body.Bytes(), &status)
+		return status
+	}
+	checkpointIteration := func(jobID string) int {
+		var iteration int
+		if err := db.QueryRow(`SELECT iteration FROM optimization_checkpoints WHERE job_id = ?`, jobID).Scan(&iteration); err != nil {
+			return -1
+		}
+		return iteration
+	}
+	
+	w := send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 1000000, "checkpoint_interval": 100,
+	})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	
+	require.Eventually(t, func() bool {
+		return checkpointIteration(started.JobID) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	
+	// Running jobs can't be resumed
+	w = send("POST", "/api/v1/optimize/jobs/"+started.JobID+"/resume", nil)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	
+	w = send("POST", "/api/v1/optimize/jobs/"+started.JobID+"/cancel", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = send("POST", "/api/v1/optimize/jobs/"+started.JobID+"/resume", nil)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var resumed types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resumed))
+	assert.Equal(t, started.JobID, resumed.JobID)
+	
+	// The cancelled run saved where it stopped, and the resumed run carries on from there
+	stoppedAt := checkpointIteration(started.JobID)
+	require.Greater(t, stoppedAt, 0)
+	require.Eventually(t, func() bool {
+		status := status(started.JobID)
+		return status.Status == "running" && status.Progress.Iteration > stoppedAt
+	}, 5*time.Second, 10*time.Millisecond)
+	
+	var drawStatus string
+	require.NoError(t, db.QueryRow(`SELECT status FROM draws WHERE id = 1`).Scan(&drawStatus))
+	assert.Equal(t, "optimizing", drawStatus)
+	
+	w = send("POST", "/api/v1/optimize/jobs/"+started.JobID+"/cancel", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	// A job that never saved a checkpoint can't be resumed
+	_, err = db.Exec(`INSERT INTO optimization_jobs (job_id, draw_id, status, started_at) VALUES ('opt_1_1', 1, 'running', CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+	restarted := setupTestServer(db)
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/optimize/jobs/opt_1_1/resume", nil)
+	restarted.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	
+	w = send("POST", "/api/v1/optimize/jobs/opt_missing/resume", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSolutionLibrary(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Library Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	run := func(payload map[string]interface{}) optimizer.OptimizationResult {
+		w := send("POST", "/api/v1/optimize/draws/1/start", payload)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		var started types.StartOptimizationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+		
+		require.Eventually(t, func() bool {
+			w := send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", nil)
+			var status types.OptimizationStatusResponse
+			return json.Unmarshal(w.Body.Bytes(), &status) == nil && status.Status == "completed"
+		}, 5*time.Second, 10*time.Millisecond)
+		
+		w = send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/result", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result optimizer.OptimizationResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+	
+	// Without a stored solution, warm starts begin from the draw as usual
+	first := run(map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 500, "warm_start": true,
+	})
+	assert.Zero(t, first.WarmStartSolutionID)
+	
+	w := send("GET", "/api/v1/optimize/solutions", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var library types.SolutionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &library))
+	require.Len(t, library.Solutions, 1)
+	solution := library.Solutions[0]
+	assert.Equal(t, first.FinalScore, solution.Score)
+	assert.Equal(t, 6, solution.MatchCount)
+	assert.Empty(t, solution.Matches)
+	
+	second := run(map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 500, "warm_start": true,
+	})
+	assert.Equal(t, solution.ID, second.WarmStartSolutionID)
+	assert.GreaterOrEqual(t, second.InitialScore, solution.Score)
+	
+	// Warm starts replace the whole draw, so they can't be limited to some rounds
+	w = send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 500, "warm_start": true,
+		"rounds": map[string]int{"from_round": 2, "to_round": 3},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	w = send("DELETE", fmt.Sprintf("/api/v1/optimize/solutions/%d", solution.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = send("DELETE", fmt.Sprintf("/api/v1/optimize/solutions/%d", solution.ID), nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	gin.SetMode(gin.TestMode)
+	server := api.NewServer(db)
+	router := server.GetRouter()
+	httpServer := httptest.NewServer(router)
+	defer httpServer.Close()
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Shutdown Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4), (1, 3, 4, 1), (1, 3, 3, 2)`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{
+		"temperature": 10.0, "cooling_rate": 0.9, "max_iterations": 10000000, "checkpoint_interval": 100,
+	})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started types.StartOptimizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws/optimize/" + started.JobID
+	conn, _, err := gorillaws.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	
+	require.Eventually(t, func() bool {
+		var count int
+		db.QueryRow(`SELECT COUNT(*) FROM optimization_checkpoints WHERE job_id = ?`, started.JobID).Scan(&count)
+		return count > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+	
+	// The running job is cancelled with its checkpoint kept, and its draw is
+	// back to draft
+	w = send("GET", "/api/v1/optimize/jobs/"+started.JobID+"/status", nil)
+	var status types.OptimizationStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, "cancelled", status.Status)
+	require.NotNil(t, status.Error)
+	assert.Equal(t, optimizer.ShutdownJobError, *status.Error)
+	
+	var jobStatus string
+	require.NoError(t, db.QueryRow(`SELECT status FROM optimization_jobs WHERE job_id = ?`, started.JobID).Scan(&jobStatus))
+	assert.Equal(t, "cancelled", jobStatus)
+	var checkpoints int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM optimization_checkpoints WHERE job_id = ?`, started.JobID).Scan(&checkpoints))
+	assert.Equal(t, 1, checkpoints)
+	var drawStatus string
+	require.NoError(t, db.QueryRow(`SELECT status FROM draws WHERE id = 1`).Scan(&drawStatus))
+	assert.Equal(t, "draft", drawStatus)
+	
+	// Subscribers receive what was queued, then the connection closes
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err = conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	assert.True(t, gorillaws.IsCloseError(err, gorillaws.CloseNormalClosure, gorillaws.CloseNoStatusReceived), "got %v", err)
+	
+	// No new jobs start once shut down
+	w = send("POST", "/api/v1/optimize/draws/1/start", map[string]interface{}{"max_iterations": 10})
+	assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+	require.NoError(t, db.QueryRow(`SELECT status FROM draws WHERE id = 1`).Scan(&drawStatus))
+	assert.Equal(t, "draft", drawStatus)
+}
+
+func TestGenerateDraw(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Generated Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	
+	generate := func(options map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"constraints": map[string]interface{}{
+				"hard": []interface{}{map[string]interface{}{"type": "bye_constraint", "params": map[string]interface{}{}}},
+				"soft": []interface{}{map[string]interface{}{"type": "home_away_balance", "weight": 0.5, "params": map[string]interface{}{"max_deviation": 0.2}}},
+			},
+			"options": options,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/draws/1/generate", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := generate(nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp types.GenerateDrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	assert.Equal(t, 6, resp.MatchCount)
+	assert.Greater(t, int64(resp.GenerationTime), int64(0))
+	
+	var stored int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1`).Scan(&stored))
+	assert.Equal(t, 6, stored)
+	
+	var status string
+	var config sql.NullString
+	require.NoError(t, db.QueryRow(`SELECT status, constraint_config FROM draws WHERE id = 1`).Scan(&status, &config))
+	assert.Equal(t, "completed", status)
+	assert.Contains(t, config.String, "home_away_balance")
+	
+	// Regenerating replaces the fixture rather than adding to it
+	w = generate(nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1`).Scan(&stored))
+	assert.Equal(t, 6, stored)
+	
+	// A season start dates every match into the standard timeslots
+	w = generate(map[string]interface{}{"season_start": "2025-03-06"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var undated int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND (match_date IS NULL OR match_time IS NULL)`).Scan(&undated))
+	assert.Equal(t, 0, undated)
+	
+	// The reported seed regenerates the same fixture
+	fixture := func() string {
+		var rows string
+		require.NoError(t, db.QueryRow(`SELECT GROUP_CONCAT(round || ':' || home_team_id || 'v' || away_team_id, ',') FROM
+			(SELECT round, home_team_id, away_team_id FROM matches WHERE draw_id = 1 ORDER BY id)`).Scan(&rows))
+		return rows
+	}
+	w = generate(map[string]interface{}{"seed": 1234})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(1234), resp.Seed)
+	seeded := fixture()
+	
+	w = generate(map[string]interface{}{"seed": resp.Seed})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, seeded, fixture())
+	
+	// Fixture templates add repeat opponents, which need rounds beyond the round-robin
+	template := map[string]interface{}{"pools": [][]int{{1, 2}, {3, 4}}, "repeat_opponents": 1}
+	w = generate(map[string]interface{}{"fixture_template": template})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	_, err = db.Exec(`UPDATE draws SET rounds = 4 WHERE id = 1`)
+	require.NoError(t, err)
+	w = generate(map[string]interface{}{"fixture_template": template})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 8, resp.MatchCount)
+	var repeated int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND round = 4
+		AND ((home_team_id = 1 AND away_team_id = 2) OR (home_team_id = 2 AND away_team_id = 1))`).Scan(&repeated))
+	assert.Equal(t, 1, repeated, "pool mates should meet again in the repeat round")
+	
+	// Pinned fixtures are played in their rounds, with the draw built around them
+	pins := []map[string]int{{"round": 1, "home_team_id": 4, "away_team_id": 3}}
+	w = generate(map[string]interface{}{"fixture_template": template, "pinned_fixtures": pins})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var pinned int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND round = 1 AND home_team_id = 4 AND away_team_id = 3`).Scan(&pinned))
+	assert.Equal(t, 1, pinned)
+	
+	// With four teams 1 v 2 and 3 v 4 always share a round, so only meet in two
+	pins = []map[string]int{{"round": 1, "home_team_id": 1, "away_team_id": 2}, {"round": 2, "home_team_id": 3, "away_team_id": 4},
+		{"round": 3, "home_team_id": 2, "away_team_id": 1}}
+	w = generate(map[string]interface{}{"pinned_fixtures": pins})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	pins = []map[string]int{{"round": 9, "home_team_id": 1, "away_team_id": 2}}
+	w = generate(map[string]interface{}{"pinned_fixtures": pins})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/draws/99/generate", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGenerateDrawByes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'), ('Panthers', 'PEN', 'Penrith'),
+		('Sharks', 'CRO', 'Cronulla'), ('Raiders', 'CAN', 'Canberra')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Odd Draw', 2025, 5, 'draft')`)
+	require.NoError(t, err)
+	
+	send := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send("/api/v1/draws/1/generate", map[string]interface{}{"options": map[string]interface{}{"seed": 7}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp types.GenerateDrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 10, resp.MatchCount, "byes aren't counted as matches")
+	
+	// Every team sits out one round, and the bye row says which team
+	byeCount := func(drawID int) map[int]int {
+		rows, err := db.Query(`SELECT round, home_team_id FROM matches
+			WHERE draw_id = ? AND away_team_id IS NULL AND venue_id IS NULL`, drawID)
+		require.NoError(t, err)
+		defer rows.Close()
+		counts := make(map[int]int)
+		rounds := make(map[int]bool)
+		for rows.Next() {
+			var round, teamID int
+			require.NoError(t, rows.Scan(&round, &teamID))
+			counts[teamID]++
+			assert.False(t, rounds[round], "round %d has more than one bye", round)
+			rounds[round] = true
+		}
+		return counts
+	}
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 1, 4: 1, 5: 1}, byeCount(1))
+	
+	// Byes stay out of the draw's match list
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/matches", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var matches []types.MatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &matches))
+	assert.Len(t, matches, 10)
+	
+	// Copies keep the byes
+	w = send("/api/v1/draws/1/clone?with_matches=true", map[string]interface{}{})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, byeCount(1), byeCount(2))
+}
+
+func TestGenerateMagicRound(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES
+		('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050),
+		('BlueBet Stadium', 'Penrith', 22500), ('PointsBet Stadium', 'Cronulla', 12500)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES
+		('Broncos', 'BRI', 'Brisbane', 1), ('Storm', 'MEL', 'Melbourne', 2),
+		('Panthers', 'PEN', 'Penrith', 3), ('Sharks', 'CRO', 'Cronulla', 4)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Magic Round Draw', 2025, 3, 'draft')`)
+	require.NoError(t, err)
+	
+	generate := func(magicRounds ...map[string]interface{}) *httptest.ResponseRecorder {
+		hard := []interface{}{
+			map[string]interface{}{"type": "venue_recovery", "params": map[string]interface{}{"min_recovery_days": 3}},
+		}
+		for _, magic := range magicRounds {
+			hard = append(hard, map[string]interface{}{"type": "magic_round", "params": magic})
+		}
+		body, _ := json.Marshal(map[string]interface{}{
+			"constraints": map[string]interface{}{"hard": hard, "soft": []interface{}{}},
+			"options":     map[string]interface{}{"season_start": "2025-03-06"},
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/draws/1/generate", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Round 2 is played entirely at Suncorp Stadium
+	w := generate(map[string]interface{}{"round": 2, "venue_id": 1})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp types.GenerateDrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Violations, "stacked magic round matches shouldn't breach venue recovery")
+	
+	rows, err := db.Query(`SELECT venue_id, match_date, match_time FROM matches WHERE draw_id = 1 AND round = 2 ORDER BY match_date, match_time`)
+	require.NoError(t, err)
+	var kickoffs []string
+	for rows.Next() {
+		var venueID int
+		var date time.Time
+		var kickoff string
+		require.NoError(t, rows.Scan(&venueID, &date, &kickoff))
+		assert.Equal(t, 1, venueID)
+		assert.Contains(t, []time.Weekday{time.Friday, time.Saturday, time.Sunday}, date.Weekday())
+		kickoffs = append(kickoffs, date.Format("2006-01-02")+" "+kickoff)
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	require.Len(t, kickoffs, 2)
+	assert.NotEqual(t, kickoffs[0], kickoffs[1], "magic round matches must not kick off together")
+	
+	var elsewhere int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND round != 2 AND venue_id = 1
+		AND home_team_id != 1`).Scan(&elsewhere))
+	assert.Equal(t, 0, elsewhere, "other rounds stay at the home team's venue")
+	
+	// One round can't be a magic round at two venues
+	w = generate(map[string]interface{}{"round": 2, "venue_id": 1}, map[string]interface{}{"round": 2, "venue_id": 2})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "magic_round_clash")
+}
+
+func TestTuneConstraintWeights(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Tuned Draw', 2025, 6, 'draft')`)
+	require.NoError(t, err)
+	
+	send := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	tune := map[string]interface{}{"levels": []float64{0.5, 1.0}, "iterations": 100, "seed": 3}
+	
+	// Nothing to tune before the draw is generated
+	w := send("/api/v1/draws/1/tune-weights", tune)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	w = send("/api/v1/draws/1/generate", map[string]interface{}{
+		"constraints": map[string]interface{}{
+			"hard": []interface{}{},
+			"soft": []interface{}{
+				map[string]interface{}{"type": "home_away_balance", "weight": 0.8, "params": map[string]interface{}{"max_deviation": 0.1}},
+				map[string]interface{}{"type": "prime_time_spread", "weight": 0.4, "params": map[string]interface{}{"target_ratio": 0.3, "max_deviation": 0.1}},
+			},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var before int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1`).Scan(&before))
+	
+	w = send("/api/v1/draws/1/tune-weights", tune)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var result optimizer.WeightTuningResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, optimizer.TuningStrategyGrid, result.Strategy)
+	require.Len(t, result.Constraints, 2)
+	assert.Equal(t, "home_away_balance", result.Constraints[0].Type)
+	assert.Equal(t, 0.8, result.Constraints[0].CurrentWeight)
+	require.Len(t, result.Samples, 5, "current weights plus a 2x2 grid")
+	assert.True(t, result.Samples[0].Current)
+	assert.NotEmpty(t, result.ParetoFront)
+	for _, sample := range result.Samples {
+		assert.Len(t, sample.Scores, 2)
+	}
+	
+	// Tuning never changes the stored draw
+	var after int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1`).Scan(&after))
+	assert.Equal(t, before, after)
+	var status string
+	require.NoError(t, db.QueryRow(`SELECT status FROM draws WHERE id = 1`).Scan(&status))
+	assert.Equal(t, "completed", status)
+	
+	w = send("/api/v1/draws/1/tune-weights", map[string]interface{}{"strategy": "exhaustive"})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	w = send("/api/v1/draws/99/tune-weights", tune)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestValidateDrawConstraints(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES
+		('Validated Draw', 2025, 3, 'completed', '{"hard":[{"type":"double_up","params":{"min_rounds_separation":3}}],"soft":[]}'),
+		('Draft Draw', 2025, 3, 'draft', NULL)`)
+	require.NoError(t, err)
+	// Broncos and Storm meet in rounds 1 and 2
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 2, 1), (1, 2, 3, 4), (1, 3, 1, 3), (1, 3, 2, 4)`)
+	require.NoError(t, err)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send("GET", "/api/v1/draws/1/validate-constraints", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp types.ValidateConstraintsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.IsValid)
+	
+	var doubleUps []types.ConstraintViolation
+	for _, violation := range resp.Violations {
+		if violation.Severity == "hard" {
+			doubleUps = append(doubleUps, violation)
+		}
+	}
+	require.NotEmpty(t, doubleUps)
+	for _, violation := range doubleUps {
+		assert.Equal(t, "double_up", violation.ConstraintType)
+		assert.Len(t, violation.MatchIDs, 1)
+		assert.NotEmpty(t, violation.Rounds)
+		assert.NotEmpty(t, violation.Remediation)
+		assert.Subset(t, []int{1, 2, 3, 4}, violation.TeamIDs)
+	}
+	
+	// Constraints in the request replace the draw's configuration
+	w = send("POST", "/api/v1/draws/1/validate-constraints", `{"constraints":{"hard":[],"soft":[]}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.IsValid)
+	assert.Empty(t, resp.Violations)
+	
+	w = send("GET", "/api/v1/draws/2/validate-constraints", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	w = send("GET", "/api/v1/draws/99/validate-constraints", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDrawStatistics(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES
+		('Stats Draw', 2025, 3, 'completed', '{"hard":[],"soft":[
+			{"type":"home_away_balance","weight":1,"params":{"max_deviation":0.1}},
+			{"type":"rest_period","weight":1,"params":{"min_rest_days":6}}
+		]}'),
+		('Draft Draw', 2025, 3, 'draft', NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, match_date) VALUES
+		(1, 1, 1, 2, '2025-03-07T00:00:00Z'), (1, 1, 3, 4, '2025-03-08T00:00:00Z'),
+		(1, 2, 1, 3, '2025-03-11T00:00:00Z'), (1, 2, 2, 4, '2025-03-15T00:00:00Z'),
+		(1, 3, 1, 4, '2025-03-21T00:00:00Z'), (1, 3, 2, 3, '2025-03-22T00:00:00Z')`)
+	require.NoError(t, err)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := get("/api/v1/draws/1/statistics")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp types.DrawStatisticsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.DrawID)
+	
+	// Only the configured analyses are run
+	require.NotNil(t, resp.Statistics.HomeAway)
+	require.NotNil(t, resp.Statistics.Rest)
+	assert.Nil(t, resp.Statistics.PrimeTime)
+	assert.Nil(t, resp.Statistics.Travel)
+	assert.Len(t, resp.Statistics.HomeAway.Teams, 4)
+	assert.Equal(t, 6, resp.Statistics.HomeAway.Statistics.TotalHomeGames)
+	// The Broncos have 3 days between rounds 1 and 2
+	assert.Equal(t, 6, resp.Statistics.Rest.MinRestDays)
+	assert.Positive(t, resp.Statistics.Rest.Statistics.ShortRestPeriods)
+	
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/draws/2/statistics").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/draws/99/statistics").Code)
+}
+
+func TestDerbyAnalysis(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city, latitude, longitude) VALUES
+		('Eels', 'PAR', 'Parramatta', -33.8150, 151.0011), ('Panthers', 'PEN', 'Penrith', -33.7507, 150.6877),
+		('Broncos', 'BRI', 'Brisbane', -27.4648, 153.0095), ('Storm', 'MEL', 'Melbourne', -37.8136, 144.9631)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES
+		('Derby Draw', 2025, 2, 'completed', '{"hard":[],"soft":[{"type":"derby","weight":1,"params":{"max_distance_km":50,"marquee_rounds":[2]}}]}')`)
+	require.NoError(t, err)
+	// The Eels and Panthers meet in round 1, missing the marquee round
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 2), (1, 1, 3, 4), (1, 2, 1, 3), (1, 2, 2, 4)`)
+	require.NoError(t, err)
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/constraints/derbies", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp types.DerbyReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Derbies, 1)
+	derbies := resp.Derbies[0]
+	require.Len(t, derbies.Pairs, 1)
+	assert.Equal(t, 1, derbies.Pairs[0].TeamA)
+	assert.Equal(t, 2, derbies.Pairs[0].TeamB)
+	assert.Equal(t, []int{2}, derbies.MarqueeRounds)
+	assert.Equal(t, 1, derbies.TotalDerbies)
+	assert.Equal(t, 0, derbies.MarqueeDerbies)
+	require.Len(t, derbies.Rounds, 2)
+	assert.Equal(t, []int{1}, derbies.Rounds[0].MatchIDs)
+	assert.True(t, derbies.Rounds[1].Marquee)
+	assert.Equal(t, 0.0, derbies.Score)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/99/constraints/derbies", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTravelFatigueAnalysis(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city, latitude, longitude) VALUES
+		('Storm', 'MEL', 'Melbourne', -37.8136, 144.9631), ('Warriors', 'NZW', 'Auckland', -36.9036, 174.7441),
+		('Broncos', 'BRI', 'Brisbane', -27.4648, 153.0095), ('Titans', 'GCT', 'Gold Coast', -28.0167, 153.4000)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Fatigue Draw', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	// The Storm go to Auckland in round 1 and Brisbane, over daylight saving, in round 2
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, match_date) VALUES
+		(1, 1, 2, 1, '2025-03-08'), (1, 1, 3, 4, '2025-03-08'), (1, 2, 3, 1, '2025-03-15'), (1, 2, 4, 2, '2025-03-15')`)
+	require.NoError(t, err)
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/constraints/travel-fatigue", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp types.TravelFatigueReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.MaxConsecutiveLongHaul)
+	require.Len(t, resp.Teams, 4)
+	storm := resp.Teams[0]
+	require.Len(t, storm.Legs, 2)
+	assert.Equal(t, geo.LegLongFlight, storm.Legs[0].Kind)
+	assert.Equal(t, 2.0, storm.Legs[0].ZoneShiftHours)
+	assert.Equal(t, geo.LegShortFlight, storm.Legs[1].Kind)
+	assert.Equal(t, -1.0, storm.Legs[1].ZoneShiftHours)
+	assert.Equal(t, 1.0, storm.Score)
+	
+	// The Warriors cross the Tasman after their home game
+	warriors := resp.Teams[1]
+	assert.Equal(t, geo.LegDrive, warriors.Legs[0].Kind)
+	assert.Equal(t, geo.LegLongFlight, warriors.Legs[1].Kind)
+	assert.Equal(t, -3.0, warriors.Legs[1].ZoneShiftHours)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/99/constraints/travel-fatigue", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTravelCostAnalysis(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city, latitude, longitude) VALUES
+		('Storm', 'MEL', 'Melbourne', -37.8136, 144.9631), ('Warriors', 'NZW', 'Auckland', -36.9036, 174.7441),
+		('Broncos', 'BRI', 'Brisbane', -27.4648, 153.0095), ('Titans', 'GCT', 'Gold Coast', -28.0167, 153.4000)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES ('Cost Draw', 2025, 2, 'completed',
+		'{"hard":[],"soft":[{"type":"travel_cost","weight":1,"params":{"budget":80000,"accommodation_per_night":5000}}]}')`)
+	require.NoError(t, err)
+	// The Storm fly from Auckland straight on to Brisbane with four rest days between
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, match_date) VALUES
+		(1, 1, 2, 1, '2025-03-08'), (1, 1, 3, 4, '2025-03-08'), (1, 2, 3, 1, '2025-03-13'), (1, 2, 4, 2, '2025-03-13')`)
+	require.NoError(t, err)
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/constraints/travel-cost", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp types.TravelCostReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 80000.0, resp.Budget)
+	assert.Equal(t, 5000.0, resp.Model.AccommodationPerNight)
+	assert.Equal(t, constraints.DefaultLongFlightCostPerKm, resp.Model.LongFlightCostPerKm)
+	require.Len(t, resp.Teams, 4)
+	storm := resp.Teams[0]
+	assert.Equal(t, 5, storm.AccommodationNights)
+	assert.Equal(t, 25000.0, storm.AccommodationCost)
+	assert.Greater(t, storm.LongFlightKm, 4000.0)
+	assert.Greater(t, storm.ShortFlightKm, 1000.0)
+	assert.Greater(t, resp.TotalCost, resp.Budget)
+	assert.InDelta(t, resp.TotalCost/4, resp.AverageTeamCost, 1e-6)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/1/constraints/travel-cost/teams/1", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var teamResp types.TeamTravelCostResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &teamResp))
+	assert.Equal(t, storm, teamResp.Team)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/1/constraints/travel-cost/teams/99", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/99/constraints/travel-cost", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRegionSpreadAnalysis(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city, latitude, longitude) VALUES
+		('Eels', 'PAR', 'Parramatta', -33.8150, 151.0011), ('Panthers', 'PEN', 'Penrith', -33.7507, 150.6877),
+		('Broncos', 'BRI', 'Brisbane', -27.4648, 153.0095), ('Storm', 'MEL', 'Melbourne', -37.8136, 144.9631)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES
+		('Region Draw', 2025, 2, 'completed', '{"hard":[{"type":"region_spread","params":{"team_regions":{"1":"Sydney","2":"Sydney","3":"Brisbane"},"max_home_matches":1}}],"soft":[]}')`)
+	require.NoError(t, err)
+	// Both Sydney teams are at home in round 1
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES
+		(1, 1, 1, 3), (1, 1, 2, 4), (1, 2, 3, 1), (1, 2, 4, 2)`)
+	require.NoError(t, err)
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/draws/1/constraints/region-spread", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp types.RegionSpreadReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Spreads, 1)
+	spread := resp.Spreads[0]
+	assert.True(t, spread.Hard)
+	assert.Equal(t, 1, spread.MaxHomeMatches)
+	require.Len(t, spread.Rounds, 2)
+	require.Len(t, spread.Rounds[0].Regions, 2)
+	sydney := spread.Rounds[0].Regions[1]
+	assert.Equal(t, "Sydney", sydney.Region)
+	assert.Equal(t, 2, sydney.HomeMatches)
+	assert.Equal(t, "OVER", sydney.Status)
+	for _, region := range spread.Rounds[1].Regions {
+		assert.Equal(t, "WITHIN", region.Status)
+	}
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/draws/99/constraints/region-spread", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSeasonLadderRepeatMatchups(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne'),
+		('Panthers', 'PEN', 'Penrith'), ('Sharks', 'CRO', 'Cronulla'),
+		('Roosters', 'SYD', 'Sydney'), ('Rabbitohs', 'SOU', 'Sydney')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Next Season', 2025, 7, 'draft')`)
+	require.NoError(t, err)
+	
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send("GET", "/api/v1/ladders/2024", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	// Storm finished top, Broncos last
+	ladder := []map[string]int{
+		{"team_id": 2, "position": 1, "points": 44}, {"team_id": 3, "position": 2, "points": 40},
+		{"team_id": 5, "position": 3, "points": 34}, {"team_id": 6, "position": 4, "points": 30},
+		{"team_id": 4, "position": 5, "points": 22}, {"team_id": 1, "position": 6, "points": 18},
+	}
+	w = send("PUT", "/api/v1/ladders/2024", map[string]interface{}{"entries": ladder})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var ladderResp types.LadderResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ladderResp))
+	require.Len(t, ladderResp.Entries, 6)
+	assert.Equal(t, 2, ladderResp.Entries[0].TeamID)
+	
+	// Re-importing replaces the ladder
+	w = send("PUT", "/api/v1/ladders/2024", map[string]interface{}{"entries": ladder})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var stored int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM season_ladders WHERE season = 2024`).Scan(&stored))
+	assert.Equal(t, 6, stored)
+	
+	duplicate := []map[string]int{{"team_id": 1, "position": 1}, {"team_id": 2, "position": 1}}
+	w = send("PUT", "/api/v1/ladders/2023", map[string]interface{}{"entries": duplicate})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	unknown := []map[string]int{{"team_id": 1, "position": 1}, {"team_id": 99, "position": 2}}
+	w = send("PUT", "/api/v1/ladders/2023", map[string]interface{}{"entries": unknown})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	// The top 2 play each other twice; everyone else gets the closest team left
+	w = send("POST", "/api/v1/ladders/2024/repeat-matchups", map[string]interface{}{
+		"repeat_opponents": 1,
+		"rules":            []interface{}{map[string]interface{}{"positions": map[string]int{"from": 1, "to": 2}}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var matrix types.RepeatMatchupsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &matrix))
+	assert.Equal(t, 1, matrix.FromRules)
+	assert.Len(t, matrix.Matchups, 3)
+	assert.Equal(t, []int{3}, matrix.Opponents[2])
+	assert.Equal(t, []int{6}, matrix.Opponents[5])
+	assert.Equal(t, []int{1}, matrix.Opponents[4])
+	
+	// A rule giving teams too many repeats is rejected
+	w = send("POST", "/api/v1/ladders/2024/repeat-matchups", map[string]interface{}{
+		"repeat_opponents": 1,
+		"rules":            []interface{}{map[string]interface{}{"positions": map[string]int{"from": 1, "to": 3}}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	
+	// The fixture template drives draw generation
+	w = send("POST", "/api/v1/draws/1/generate", map[string]interface{}{
+		"options": map[string]interface{}{"fixture_template": matrix.FixtureTemplate},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var repeated int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND round = 6
+		AND ((home_team_id = 2 AND away_team_id = 3) OR (home_team_id = 3 AND away_team_id = 2))`).Scan(&repeated))
+	assert.Equal(t, 1, repeated, "the top 2 should meet again in the repeat round")
+}
+
+func TestMatchLocking(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane', 1), ('Melbourne Storm', 'MEL', 'Melbourne', 2)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Locked Draw', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id) VALUES (1, 1, 1, 2, 1), (1, 2, 2, 1, 2)`)
+	require.NoError(t, err)
+	
+	lock := func(matchID int, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", fmt.Sprintf("/api/v1/matches/%d/lock", matchID), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := lock(1, `{"locked": true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var match types.MatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &match))
+	assert.True(t, match.Locked)
+	require.NotNil(t, match.HomeTeam)
+	assert.Equal(t, "Brisbane Broncos", match.HomeTeam.Name)
+	
+	// Locked matches can't be moved to another venue or regenerated away
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/matches/1/venue-substitutes", bytes.NewBufferString(`{"venue_id": 2}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/draws/1/generate", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	
+	w = lock(1, `{"locked": false}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &match))
+	assert.False(t, match.Locked)
+	
+	assert.Equal(t, http.StatusBadRequest, lock(1, `{}`).Code)
+	assert.Equal(t, http.StatusNotFound, lock(99, `{"locked": true}`).Code)
+}
+
+func TestDrawTeamChanges(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050), ('Optus Stadium', 'Perth', 60000)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane', 1), ('Melbourne Storm', 'MEL', 'Melbourne', 2), ('Sydney Roosters', 'SYD', 'Sydney', NULL), ('Perth Bears', 'PER', 'Perth', 3)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Expansion Draw', 2025, 3, 'completed')`)
+	require.NoError(t, err)
+	// Three teams, one on a bye each round
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id) VALUES (1, 1, 1, 2, 1), (1, 2, 2, 3, 2), (1, 3, 3, 1, NULL)`)
+	require.NoError(t, err)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Perth plays each round's bye team
+	w := send("POST", "/api/v1/draws/1/teams", `{"team_id": 4}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var change types.DrawTeamChangeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &change))
+	assert.Len(t, change.Created, 3)
+	assert.Empty(t, change.Updated)
+	
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND (home_team_id = 4 OR away_team_id = 4)`).Scan(&count))
+	assert.Equal(t, 3, count)
+	
+	assert.Equal(t, http.StatusConflict, send("POST", "/api/v1/draws/1/teams", `{"team_id": 4}`).Code)
+	assert.Equal(t, http.StatusNotFound, send("POST", "/api/v1/draws/1/teams", `{"team_id": 99}`).Code)
+	
+	// With four teams nobody has a bye, so the Roosters' matches are dropped
+	w = send("DELETE", "/api/v1/draws/1/teams/3", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &change))
+	assert.Len(t, change.Deleted, 3)
+	
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND (home_team_id = 3 OR away_team_id = 3)`).Scan(&count))
+	assert.Equal(t, 0, count)
+	
+	// Locked fixtures stay put
+	_, err = db.Exec(`UPDATE matches SET locked = 1 WHERE id = 1`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, send("DELETE", "/api/v1/draws/1/teams/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, send("DELETE", "/api/v1/draws/1/teams/3", "").Code)
+	
+	_, err = db.Exec(`UPDATE draws SET published_at = CURRENT_TIMESTAMP WHERE id = 1`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, send("POST", "/api/v1/draws/1/teams", `{"team_id": 3}`).Code)
+}
+
+func TestPublicFixtureFeed(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	// The feed needs no API key even when keys are required
+	gin.SetMode(gin.TestMode)
+	apiServer := api.NewServer(db)
+	apiServer.SetAPIKeys(map[string]middleware.Role{"admin-key": middleware.RoleAdmin})
+	router := apiServer.GetRouter()
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane'), ('Melbourne Storm', 'MEL', 'Melbourne'), ('Sydney Roosters', 'SYD', 'Sydney'), ('Penrith Panthers', 'PEN', 'Penrith')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Feed Draw', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date) VALUES
+		(1, 2, 1, 3, 1, '2025-03-14T00:00:00Z'), (1, 1, 3, 4, NULL, '2025-03-08T00:00:00Z'), (1, 1, 1, 2, 1, '2025-03-07T00:00:00Z')`)
+	require.NoError(t, err)
+	
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/public/draws/1/fixtures", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Unpublished draws aren't in the feed
+	assert.Equal(t, http.StatusNotFound, get(nil).Code)
+	
+	_, err = db.Exec(`UPDATE draws SET published_at = '2025-02-01T09:00:00Z' WHERE id = 1`)
+	require.NoError(t, err)
+	
+	w := get(nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+	assert.Contains(t, w.Header().Get("Cache-Control"), "public")
+	
+	var fixture struct {
+		DrawID  int `json:"draw_id"`
+		Matches []struct {
+			ID       int    `json:"id"`
+			Round    int    `json:"round"`
+			Date     string `json:"date"`
+			HomeTeam string `json:"home_team"`
+			Venue    string `json:"venue"`
+		} `json:"matches"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fixture))
+	require.Len(t, fixture.Matches, 3)
+	assert.Equal(t, []int{3, 2, 1}, []int{fixture.Matches[0].ID, fixture.Matches[1].ID, fixture.Matches[2].ID}, "matches should be in round then kickoff order")
+	assert.Equal(t, "Brisbane Broncos", fixture.Matches[0].HomeTeam)
+	assert.Equal(t, "Suncorp Stadium", fixture.Matches[0].Venue)
+	
+	// Polling with the ETag or a later date gets a 304 with no body
+	w = get(map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, http.StatusNotModified, get(map[string]string{"If-None-Match": `"stale", W/` + etag}).Code)
+	assert.Equal(t, http.StatusNotModified, get(map[string]string{"If-Modified-Since": time.Now().UTC().Add(time.Hour).Format(http.TimeFormat)}).Code)
+	assert.Equal(t, http.StatusOK, get(map[string]string{"If-Modified-Since": "Mon, 01 Jan 2024 00:00:00 GMT"}).Code)
+	
+	// The gzipped feed has its own strong ETag, and revalidates with either
+	w = get(map[string]string{"Accept-Encoding": "gzip"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	gzipETag := w.Header().Get("ETag")
+	assert.Equal(t, strings.TrimSuffix(etag, `"`)+`-gzip"`, gzipETag)
+	w = get(map[string]string{"Accept-Encoding": "gzip", "If-None-Match": gzipETag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, gzipETag, w.Header().Get("ETag"))
+	w = get(map[string]string{"If-None-Match": gzipETag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	
+	// A reschedule changes the feed and its ETag
+	_, err = db.Exec(`UPDATE matches SET match_date = '2025-03-09T00:00:00Z' WHERE id = 3`)
+	require.NoError(t, err)
+	w = get(map[string]string{"If-None-Match": etag})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	
+	assert.Equal(t, http.StatusNotFound, func() int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/public/draws/99/fixtures", nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}())
+}
+
+func TestTeamSchedule(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane', 1), ('Melbourne Storm', 'MEL', 'Melbourne', 2), ('Sydney Roosters', 'SYD', 'Sydney', NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Club Draw', 2025, 3, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date, is_prime_time) VALUES
+		(1, 1, 2, 1, 2, '2025-03-06T19:50:00Z', 1), (1, 2, 3, 1, NULL, '2025-03-15T17:30:00Z', 0), (1, 3, 2, 3, 2, NULL, 0)`)
+	require.NoError(t, err)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := get("/api/v1/draws/1/teams/1/schedule")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var schedule types.TeamScheduleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schedule))
+	require.Len(t, schedule.Rounds, 3)
+	
+	first := schedule.Rounds[0]
+	assert.False(t, first.Home)
+	assert.True(t, first.IsPrimeTime)
+	require.NotNil(t, first.Opponent)
+	assert.Equal(t, "Melbourne Storm", first.Opponent.Name)
+	require.NotNil(t, first.Venue)
+	assert.Equal(t, "AAMI Park", first.Venue.Name)
+	assert.Nil(t, first.RestDays)
+	assert.Equal(t, 1, first.ConsecutiveAway)
+	
+	second := schedule.Rounds[1]
+	require.NotNil(t, second.RestDays)
+	assert.Equal(t, 8, *second.RestDays)
+	assert.Equal(t, 2, second.ConsecutiveAway)
+	
+	assert.True(t, schedule.Rounds[2].IsBye)
+	assert.Nil(t, schedule.Rounds[2].Opponent)
+	
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/draws/1/teams/99/schedule").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/draws/9/teams/1/schedule").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/draws/1/teams/abc/schedule").Code)
+}
+
+func TestDrawRound(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('CommBank Stadium', 'Parramatta', 30000), ('BlueBet Stadium', 'Penrith', 22500)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city) VALUES
+		('Eels', 'PAR', 'Parramatta'), ('Panthers', 'PEN', 'Penrith'), ('Broncos', 'BRI', 'Brisbane'),
+		('Storm', 'MEL', 'Melbourne'), ('Warriors', 'WAR', 'Auckland')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, constraint_config) VALUES
+		('Round Draw', 2025, 2, 'completed', '{"hard":[],"soft":[{"type":"region_spread","weight":1,"params":{"team_regions":{"1":"Sydney","2":"Sydney","3":"Brisbane"},"max_home_matches":1}}]}')`)
+	require.NoError(t, err)
+	// The Warriors have the bye in round 1
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date, is_prime_time) VALUES
+		(1, 1, 1, 3, 1, '2025-03-07T19:00:00Z', 1), (1, 1, 2, 4, 2, '2025-03-06T19:50:00Z', 1),
+		(1, 2, 3, 5, NULL, NULL, 0), (1, 2, 4, 1, NULL, NULL, 0)`)
+	require.NoError(t, err)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := get("/api/v1/draws/1/rounds/1")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var round types.RoundResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &round))
+	assert.Equal(t, 1, round.Round)
+	require.Len(t, round.Matches, 2)
+	assert.Equal(t, "Panthers", round.Matches[0].HomeTeam.Name)
+	require.Len(t, round.ByeTeams, 1)
+	assert.Equal(t, "Warriors", round.ByeTeams[0].Name)
+	assert.Equal(t, 2, round.PrimeTimeMatches)
+	assert.Len(t, round.Venues, 2)
+	
+	require.Len(t, round.RegionSpread, 1)
+	require.Len(t, round.RegionSpread[0].Rounds, 1)
+	regions := round.RegionSpread[0].Rounds[0].Regions
+	require.Len(t, regions, 2)
+	assert.Equal(t, "Sydney", regions[1].Region)
+	assert.Equal(t, "OVER", regions[1].Status)
+	
+	w = get("/api/v1/draws/1/rounds/2")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &round))
+	assert.Equal(t, "Panthers", round.ByeTeams[0].Name)
+	assert.Empty(t, round.Venues)
+	
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/draws/1/rounds/3").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/draws/9/rounds/1").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/draws/1/rounds/first").Code)
+}
+
+func TestCompetitions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send("POST", "/api/v1/competitions", `{"name": "NRL Telstra Premiership", "code": "NRL"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = send("POST", "/api/v1/competitions", `{"name": "NRL Women's Premiership", "code": "NRLW", "constraint_config": {
+		"hard": [{"type": "bye_constraint", "params": {}}],
+		"soft": [{"type": "home_away_balance", "weight": 1.0, "params": {"max_deviation": 0.2}}]
+	}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	
+	var nrlw types.CompetitionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &nrlw))
+	assert.Equal(t, "NRLW", nrlw.Code)
+	assert.NotNil(t, nrlw.ConstraintConfig)
+	
+	assert.Equal(t, http.StatusConflict, send("POST", "/api/v1/competitions", `{"name": "Women's", "code": "NRLW"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/competitions", `{"name": "Reserve Grade", "code": "rg"}`).Code)
+	
+	w = send("GET", "/api/v1/competitions", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var competitions []types.CompetitionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &competitions))
+	assert.Len(t, competitions, 2)
+	
+	// Two NRL clubs and four NRLW clubs
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city, competition_id) VALUES
+		('Broncos', 'BRI', 'Brisbane', 1), ('Storm', 'MEL', 'Melbourne', 1),
+		('Broncos Women', 'BRW', 'Brisbane', 2), ('Roosters Women', 'SYW', 'Sydney', 2),
+		('Knights Women', 'NEW', 'Newcastle', 2), ('Titans Women', 'GCW', 'Gold Coast', 2)`)
+	require.NoError(t, err)
+	
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/teams", `{"name": "Dolphins", "short_name": "DOL", "city": "Redcliffe", "competition_id": 99}`).Code)
+	
+	w = send("GET", "/api/v1/teams?competition_id=2", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var teams types.PaginatedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &teams))
+	assert.Equal(t, 4, teams.Total)
+	
+	// The NRLW draw only draws NRLW teams, with the competition's constraints
+	w = send("POST", "/api/v1/draws", `{"name": "NRLW 2025", "season_year": 2025, "rounds": 3, "competition_id": 2}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var drawResp types.DrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &drawResp))
+	require.NotNil(t, drawResp.CompetitionID)
+	assert.Equal(t, 2, *drawResp.CompetitionID)
+	
+	w = send("POST", "/api/v1/draws/1/generate", `{}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var generated types.GenerateDrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &generated))
+	assert.Equal(t, 6, generated.MatchCount)
+	
+	var nrlMatches int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM matches WHERE draw_id = 1 AND (home_team_id IN (1, 2) OR away_team_id IN (1, 2))`).Scan(&nrlMatches))
+	assert.Equal(t, 0, nrlMatches)
+	var stored sql.NullString
+	require.NoError(t, db.QueryRow(`SELECT constraint_config FROM draws WHERE id = 1`).Scan(&stored))
+	assert.Contains(t, stored.String, "home_away_balance")
+	
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/draws/1/teams", `{"team_id": 1}`).Code)
+	
+	w = send("GET", "/api/v1/draws?competition_id=1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var draws types.PaginatedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &draws))
+	assert.Equal(t, 0, draws.Total)
+	
+	// Two NRLW matches are dated at Suncorp; the NRL plays there on one of the days
+	_, err = db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE matches SET venue_id = 1, match_date = '2025-03-08' WHERE id = 1`)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE matches SET venue_id = 1, match_date = '2025-03-15' WHERE id = 2`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, competition_id) VALUES ('NRL 2025', 2025, 1, 'completed', 1)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date) VALUES (2, 1, 1, 2, 1, '2025-03-08')`)
+	require.NoError(t, err)
+	
+	w = send("POST", "/api/v1/draws/2/validate-constraints", `{"constraints": {"soft": [{"type": "double_header", "weight": 1.0, "params": {"partner_draw_id": 1}}]}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var validation types.ValidateConstraintsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &validation))
+	assert.InDelta(t, 0.5, validation.Score, 1e-9)
+	
+	// Deleting a competition keeps its teams and draws
+	require.Equal(t, http.StatusOK, send("DELETE", "/api/v1/competitions/2", "").Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/competitions/2", "").Code)
+	var unscoped int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM teams WHERE competition_id IS NULL`).Scan(&unscoped))
+	assert.Equal(t, 4, unscoped)
+}
+
+func TestVenueConflicts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	_, err := db.Exec(`INSERT INTO competitions (name, code) VALUES ('NRL Telstra Premiership', 'NRL'), ('NRL Women''s Premiership', 'NRLW')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane'), ('Melbourne Storm', 'MEL', 'Melbourne')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status, competition_id) VALUES
+		('NRL 2025', 2025, 1, 'completed', 1),
+		('NRLW 2025', 2025, 1, 'draft', 2),
+		('NRL 2025 alternative', 2025, 1, 'completed', 1)`)
+	require.NoError(t, err)
+	
+	// The NRLW's curtain raiser at Suncorp leaves two hours; its match at AAMI
+	// Park kicks off an hour after the NRL's
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date, match_time) VALUES
+		(1, 1, 1, 2, 1, '2025-03-08', '19:35:00'),
+		(1, 1, 2, 1, 2, '2025-03-09', '14:00:00'),
+		(2, 1, 1, 2, 1, '2025-03-08', '17:35:00'),
+		(2, 1, 2, 1, 2, '2025-03-09', '15:00:00'),
+		(3, 1, 1, 2, 2, '2025-03-09', '13:00:00')`)
+	require.NoError(t, err)
+	
+	w := get("/api/v1/draws/2/venue-conflicts")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report crossdraw.VenueConflictReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, []int{1, 3}, report.CheckedDrawIDs)
+	assert.Equal(t, 120, report.MinGapMinutes)
+	require.Len(t, report.Conflicts, 1)
+	assert.Equal(t, 4, report.Conflicts[0].MatchID)
+	assert.Equal(t, 2, report.Conflicts[0].VenueID)
+	assert.Equal(t, 60, report.Conflicts[0].GapMinutes)
+	
+	// A wider gap catches the curtain raiser too
+	w = get("/api/v1/draws/2/venue-conflicts?min_gap_minutes=180")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Len(t, report.Conflicts, 2)
+	
+	// The alternative NRL draw is the same competition, so it doesn't clash
+	w = get("/api/v1/draws/1/venue-conflicts")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Empty(t, report.CheckedDrawIDs)
+	assert.Empty(t, report.Conflicts)
+	
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/draws/99/venue-conflicts").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/draws/2/venue-conflicts?min_gap_minutes=-5").Code)
+	
+	// The hard constraint consults the completed NRL draws when validating
+	_, err = db.Exec(`UPDATE draws SET status = 'completed' WHERE id = 2`)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/draws/2/validate-constraints", bytes.NewBufferString(`{"constraints": {"hard": [{"type": "shared_venue", "params": {}}]}}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var validation types.ValidateConstraintsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &validation))
+	assert.False(t, validation.IsValid)
+	require.Len(t, validation.Violations, 1)
+	assert.Equal(t, "shared_venue", validation.Violations[0].ConstraintType)
+}
+
+func TestPostponeMatch(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane', 1), ('Melbourne Storm', 'MEL', 'Melbourne', 2), ('Sydney Roosters', 'SYD', 'Sydney', NULL), ('Penrith Panthers', 'PEN', 'Penrith', NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Washout Draw', 2025, 3, 'completed')`)
+	require.NoError(t, err)
+	// Both teams have a bye in round 3; the Broncos play in round 2
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date, match_time, is_prime_time) VALUES
+		(1, 1, 1, 2, 1, '2025-03-06', '19:50:00', 1),
+		(1, 2, 1, 3, 1, '2025-03-13', NULL, 0),
+		(1, 3, 3, 4, 2, '2025-03-20', NULL, 0)`)
+	require.NoError(t, err)
+	// The Storm can't play on the round 1 Saturday
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date) VALUES (1, 1, 3, 4, 2, '2025-03-08')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO team_unavailability (team_id, date) VALUES (2, '2025-03-08')`)
+	require.NoError(t, err)
+	
+	postpone := func(matchID int, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/v1/matches/%d/postpone", matchID), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := postpone(1, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var response types.PostponeMatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.Match.ScheduledAt)
+	assert.Equal(t, 1, response.Match.Round)
+	
+	// Round 2 is skipped because the Broncos play in it
+	require.Len(t, response.Options, 2)
+	assert.Equal(t, 3, response.Options[0].Round)
+	assert.True(t, response.Options[0].Available)
+	assert.Equal(t, 14, response.Options[0].DelayDays)
+	assert.Equal(t, 1, response.Options[1].Round)
+	assert.False(t, response.Options[1].Available)
+	assert.NotEmpty(t, response.Options[1].Conflicts)
+	
+	// The freed slot is saved, so the match can't be postponed twice
+	assert.Equal(t, http.StatusBadRequest, postpone(1, "").Code)
+	
+	// The Roosters play every round, so there's nowhere to replay match 2
+	w = postpone(2, `{"limit": 5}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Options)
+	
+	_, err = db.Exec(`UPDATE matches SET locked = 1 WHERE id = 3`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, postpone(3, "").Code)
+	assert.Equal(t, http.StatusNotFound, postpone(99, "").Code)
+}
+
+func TestUpdateMatch(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO venues (name, city, capacity) VALUES ('Suncorp Stadium', 'Brisbane', 52500), ('AAMI Park', 'Melbourne', 30050)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO teams (name, short_name, city, venue_id) VALUES ('Brisbane Broncos', 'BRI', 'Brisbane', 1), ('Melbourne Storm', 'MEL', 'Melbourne', 2), ('Sydney Roosters', 'SYD', 'Sydney', NULL), ('Penrith Panthers', 'PEN', 'Penrith', NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Tweaked Draw', 2025, 2, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id, match_date) VALUES
+		(1, 1, 1, 2, 1, '2025-03-06'),
+		(1, 1, 3, 4, 2, '2025-03-07'),
+		(1, 2, 1, 3, 1, '2025-03-13')`)
+	require.NoError(t, err)
+	// The Storm can't play on the round 1 Saturday
+	_, err = db.Exec(`INSERT INTO team_unavailability (team_id, date) VALUES (2, '2025-03-08')`)
+	require.NoError(t, err)
+	
+	update := func(matchID int, query, body string) (*httptest.ResponseRecorder, types.MatchChangeResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", fmt.Sprintf("/api/v1/matches/%d%s", matchID, query), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var response types.MatchChangeResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+	matchDate := func(matchID int) string {
+		var date string
+		require.NoError(t, db.QueryRow(`SELECT match_date FROM matches WHERE id = ?`, matchID).Scan(&date))
+		return date[:10]
+	}
+	hasHardViolation := func(violations []types.ConstraintViolation) bool {
+		for _, violation := range violations {
+			if violation.Severity == "hard" {
+				return true
+			}
+		}
+		return false
+	}
+	
+	// A dry run reports the broken constraint without saving
+	w, response := update(1, "?dry_run=true", `{"date": "2025-03-08"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, response.DryRun)
+	assert.False(t, response.Applied)
+	assert.True(t, hasHardViolation(response.NewViolations), "expected the Storm's unavailability to be reported")
+	require.NotNil(t, response.Match.ScheduledAt)
+	assert.Equal(t, "2025-03-08", response.Match.ScheduledAt.Format("2006-01-02"))
+	require.NotNil(t, response.Match.AwayTeam)
+	assert.Equal(t, "Melbourne Storm", response.Match.AwayTeam.Name)
+	assert.Equal(t, response.ScoreAfter-response.ScoreBefore, response.ScoreChange)
+	assert.Equal(t, "2025-03-06", matchDate(1))
+	
+	// Committing it is refused with the same report unless forced
+	w, response = update(1, "", `{"date": "2025-03-08"}`)
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.False(t, response.Applied)
+	assert.True(t, hasHardViolation(response.NewViolations))
+	assert.Equal(t, "2025-03-06", matchDate(1))
+	
+	w, response = update(1, "", `{"date": "2025-03-08", "force": true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, response.Applied)
+	assert.Equal(t, "2025-03-08", matchDate(1))
+	
+	// Moving it back resolves the violation
+	w, response = update(1, "", `{"date": "2025-03-06"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, response.Applied)
+	assert.True(t, hasHardViolation(response.ResolvedViolations))
+	assert.False(t, hasHardViolation(response.NewViolations))
+	
+	// The Roosters already play in round 2
+	w, response = update(2, "", `{"round": 2}`)
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.NotEmpty(t, response.Conflicts)
+	
+	w, _ = update(2, "", `{"time": "19:35", "prime_time": true}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w, _ = update(1, "", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = update(1, "", `{"date": "8 March"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = update(1, "", `{"home_team_id": 2}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = update(1, "", `{"round": 3}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = update(1, "?dry_run=maybe", `{"round": 2}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = update(1, "", `{"venue_id": 99}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w, _ = update(99, "", `{"round": 2}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	
+	_, err = db.Exec(`UPDATE matches SET locked = 1 WHERE id = 3`)
+	require.NoError(t, err)
+	w, _ = update(3, "?dry_run=true", `{"round": 1}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestWebhooks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO teams (name, short_name, city) VALUES ('Broncos', 'BRI', 'Brisbane'), ('Storm', 'MEL', 'Melbourne')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Webhook Draw', 2025, 1, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id) VALUES (1, 1, 1, 2)`)
+	require.NoError(t, err)
+	
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	deliveries := make(chan delivery, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- delivery{header: r.Header, body: body}
+	}))
+	defer receiver.Close()
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	// Only known events and absolute URLs can be registered
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/webhooks", `{"url": "`+receiver.URL+`", "events": ["draw.deleted"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/webhooks", `{"url": "/hooks", "events": ["draw.generated"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/webhooks", `{"url": "`+receiver.URL+`", "events": []}`).Code)
+	
+	// The secret is returned once, when the webhook is created
+	w := send("POST", "/api/v1/webhooks", `{"url": "`+receiver.URL+`", "events": ["constraints.validated"], "description": "Fixture publishing"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created types.WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.True(t, created.IsActive)
+	require.NotEmpty(t, created.Secret)
+	
+	w = send("GET", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Secret)
+	
+	// Validating the draw delivers a signed payload
+	require.Equal(t, http.StatusOK, send("GET", "/api/v1/draws/1/validate-constraints", "").Code)
+	var received delivery
+	select {
+	case received = <-deliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	assert.Equal(t, webhook.EventConstraintsValidated, received.header.Get(webhook.HeaderEvent))
+	timestamp, err := strconv.ParseInt(received.header.Get(webhook.HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, webhook.Verify(created.Secret, timestamp, received.body, received.header.Get(webhook.HeaderSignature)))
+	
+	var payload struct {
+		Event string `json:"event"`
+		Data  struct {
+			DrawID int `json:"draw_id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(received.body, &payload))
+	assert.Equal(t, webhook.EventConstraintsValidated, payload.Event)
+	assert.Equal(t, 1, payload.Data.DrawID)
+	
+	assert.Eventually(t, func() bool {
+		var hook types.WebhookResponse
+		json.Unmarshal(send("GET", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), "").Body.Bytes(), &hook)
+		return hook.LastStatus == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+	
+	// Inactive webhooks aren't called
+	w = send("PUT", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), `{"is_active": false}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, http.StatusOK, send("GET", "/api/v1/draws/1/validate-constraints", "").Code)
+	select {
+	case <-deliveries:
+		t.Fatal("inactive webhook was delivered to")
+	case <-time.After(100 * time.Millisecond):
+	}
+	
+	w = send("GET", "/api/v1/webhooks", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed []types.WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed, 1)
+	assert.False(t, listed[0].IsActive)
+	
+	require.Equal(t, http.StatusOK, send("DELETE", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), "").Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), "").Code)
+}
+
+func TestApprovalWebhooks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	_, err := db.Exec(`INSERT INTO draws (name, season_year, rounds, status) VALUES ('Approval Webhook Draw', 2025, 1, 'completed')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO matches (draw_id, round, home_team_id, away_team_id, venue_id) VALUES (1, 1, 1, 2, 1)`)
+	require.NoError(t, err)
+	
+	events := make(chan string, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events <- r.Header.Get(webhook.HeaderEvent)
+	}))
+	defer receiver.Close()
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send("POST", "/api/v1/webhooks", `{"url": "`+receiver.URL+`", "events": ["approval.requested", "approval.recorded", "draw.published"]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	
+	// Each step of the sign-off delivers its own event
+	require.Equal(t, http.StatusCreated, send("POST", "/api/v1/draws/1/approvals", `{"roles": ["broadcast"]}`).Code)
+	require.Equal(t, http.StatusOK, send("POST", "/api/v1/draws/1/approvals/broadcast", `{"decision": "approve", "approver": "broadcaster"}`).Code)
+	require.Equal(t, http.StatusOK, send("POST", "/api/v1/draws/1/publish", "").Code)
+	
+	var received []string
+	for len(received) < 3 {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only received %v", received)
+		}
+	}
+	assert.ElementsMatch(t, []string{webhook.EventApprovalRequested, webhook.EventApprovalRecorded, webhook.EventDrawPublished}, received)
+}
+
+func TestValidationErrors(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	// Test invalid venue creation
+	invalidReq := types.CreateVenueRequest{
+		Name:     "", // Empty name should fail validation
+		City:     "Test City",
+		Capacity: 50000,
+	}
+	
+	body, _ := json.Marshal(invalidReq)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/venues", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	var errorResp types.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+	assert.NoError(t, err)
+	assert.Contains(t, errorResp.Error, "Validation failed")
+}
+
+func TestConstraintTemplates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	
+	router := setupTestServer(db)
+	
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := send("POST", "/api/v1/constraint-templates", `{"name": "NRL default", "description": "Premiership season", "constraint_config": {
+		"hard": [{"type": "bye_constraint", "params": {}}],
+		"soft": [{"type": "home_away_balance", "weight": 1.0, "params": {"max_deviation": 0.2}}]
+	}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var template types.ConstraintTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &template))
+	assert.Equal(t, 1, template.Version)
+	assert.NotNil(t, template.ConstraintConfig)
+	
+	assert.Equal(t, http.StatusConflict, send("POST", "/api/v1/constraint-templates", `{"name": "NRL default", "constraint_config": {"hard": []}}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/constraint-templates", `{"name": "No config"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/constraint-templates", `{"name": "Unknown", "constraint_config": {
+		"hard": [{"type": "no_such_constraint", "params": {}}]
+	}}`).Code)
+	
+	// Renaming keeps the version, a new configuration is the next one
+	w = send("PUT", "/api/v1/constraint-templates/1", `{"description": "Telstra Premiership season"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &template))
+	assert.Equal(t, 1, template.Version)
+	
+	w = send("PUT", "/api/v1/constraint-templates/1", `{"constraint_config": {
+		"soft": [{"type": "home_away_balance", "weight": 0.5, "params": {"max_deviation": 0.1}}]
+	}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &template))
+	assert.Equal(t, 2, template.Version)
+	
+	w = send("GET", "/api/v1/constraint-templates/1/versions", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var versions []types.ConstraintTemplateVersionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &versions))
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.Equal(t, 1, versions[1].Version)
+	
+	assert.Equal(t, http.StatusOK, send("GET", "/api/v1/constraint-templates/1/versions/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/constraint-templates/1/versions/3", "").Code)
+	
+	// Draws take the latest version unless an earlier one is asked for
+	w = send("POST", "/api/v1/constraint-templates/1/draws", `{"name": "NRL 2026", "season_year": 2026, "rounds": 27}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var draw types.DrawResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &draw))
+	assert.Equal(t, "draft", draw.Status)
+	
+	var config string
+	require.NoError(t, db.QueryRow(`SELECT constraint_config FROM draws WHERE id = ?`, draw.ID).Scan(&config))
+	assert.NotContains(t, config, "bye_constraint")
+	
+	w = send("POST", "/api/v1/constraint-templates/1/draws", `{"name": "NRL 2026 original", "season_year": 2026, "rounds": 27, "version": 1}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &draw))
+	require.NoError(t, db.QueryRow(`SELECT constraint_config FROM draws WHERE id = ?`, draw.ID).Scan(&config))
+	assert.Contains(t, config, "bye_constraint")
+	
+	assert.Equal(t, http.StatusNotFound, send("POST", "/api/v1/constraint-templates/1/draws", `{"name": "Missing", "season_year": 2026, "rounds": 27, "version": 9}`).Code)
+	assert.Equal(t, http.StatusNotFound, send("POST", "/api/v1/constraint-templates/9/draws", `{"name": "Missing", "season_year": 2026, "rounds": 27}`).Code)
+	
+	// Deleting the template leaves its draws' configuration alone
+	assert.Equal(t, http.StatusOK, send("DELETE", "/api/v1/constraint-templates/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/constraint-templates/1", "").Code)
+	require.NoError(t, db.QueryRow(`SELECT constraint_config FROM draws WHERE id = ?`, draw.ID).Scan(&config))
+	assert.Contains(t, config, "bye_constraint")
 }
 
 func TestMain(m *testing.M) {